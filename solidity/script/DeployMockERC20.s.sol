@@ -7,6 +7,8 @@ import "../src/MockERC20.sol";
 /**
  * @title DeployMockERC20
  * @dev Deploy MockERC20 contract using Forge with exact compiler settings
+ * The token is described by TOKEN_NAME, TOKEN_SYMBOL, TOKEN_DECIMALS and TOKEN_INITIAL_SUPPLY (minted to the
+ * deployer), as set per token by the deploy-forge-mock-erc20 tool
  * Usage: forge script script/DeployMockERC20.s.sol:DeployMockERC20 --chain <chain_id> --broadcast --verify
  */
 contract DeployMockERC20 is Script {
@@ -23,10 +25,18 @@ contract DeployMockERC20 is Script {
             deployerPrivateKey = vm.parseUint(string(abi.encodePacked("0x", deployerPrivateKeyStr)));
         }
         
+        string memory name = vm.envOr("TOKEN_NAME", string("MockERC20"));
+        string memory symbol = vm.envOr("TOKEN_SYMBOL", string("MOCK"));
+        uint8 tokenDecimals = uint8(vm.envOr("TOKEN_DECIMALS", uint256(18)));
+        uint256 initialSupply = vm.envOr("TOKEN_INITIAL_SUPPLY", uint256(0));
+
         vm.startBroadcast(deployerPrivateKey);
         
         // Deploy MockERC20
-        MockERC20 mockERC20 = new MockERC20();
+        MockERC20 mockERC20 = new MockERC20(name, symbol, tokenDecimals);
+        if (initialSupply > 0) {
+            mockERC20.mint(vm.addr(deployerPrivateKey), initialSupply);
+        }
         
         vm.stopBroadcast();
         
@@ -2,17 +2,19 @@
 pragma solidity ^0.8.19;
 
 import "@openzeppelin/contracts/token/ERC20/ERC20.sol";
+import "@openzeppelin/contracts/token/ERC20/extensions/ERC20Permit.sol";
 
 /**
  * @title MockERC20
  * @dev A simple ERC20 token with minting capability for testing purposes
  * This is used in local forks to fund test accounts with tokens
+ * EIP-2612 permit() lets allowances be set from an off-chain signature
  */
-contract MockERC20 is ERC20 {
+contract MockERC20 is ERC20, ERC20Permit {
     uint8 private _decimals;
 
-    constructor() ERC20("MockERC20", "MOCK") {
-        _decimals = 18;
+    constructor(string memory name_, string memory symbol_, uint8 decimals_) ERC20(name_, symbol_) ERC20Permit(name_) {
+        _decimals = decimals_;
     }
 
     /**
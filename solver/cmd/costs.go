@@ -0,0 +1,66 @@
+package main
+
+// Transaction costs
+// Every tool run gets a txcost ledger in its context; once the tool is done the fees its transactions paid are
+// printed per network and operation. TX_COST_PRICES ("ETH=2500,STRK=0.45") converts them, and --cost-report also
+// saves them as JSON in the deployment state's costs/ directory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+const (
+	costReportFlag = "--cost-report"
+	costPricesEnv  = "TX_COST_PRICES"
+)
+
+// writeCostReport is set by --cost-report
+var writeCostReport bool
+
+// parseCostReportFlag removes --cost-report from os.Args, wherever it appears
+func parseCostReportFlag() {
+	args := make([]string, 0, len(os.Args))
+	for _, arg := range os.Args {
+		if arg == costReportFlag {
+			writeCostReport = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+}
+
+// withCosts returns ctx with a new ledger, and the function reporting it once tool is done. quiet skips the
+// table, for output that must stay machine-readable
+func withCosts(ctx context.Context, tool string, quiet bool) (context.Context, func()) {
+	ledger := &txcost.Ledger{}
+	return txcost.WithLedger(ctx, ledger), func() {
+		if len(ledger.Entries()) == 0 {
+			return
+		}
+		prices, err := txcost.ParsePrices(os.Getenv(costPricesEnv))
+		if err != nil && !quiet {
+			fmt.Printf("⚠️  Ignoring %s: %v\n", costPricesEnv, err)
+		}
+		if !quiet {
+			ledger.WriteTable(os.Stdout, prices)
+		}
+		if !writeCostReport {
+			return
+		}
+		path := deploystate.Path(fmt.Sprintf("costs/%s-%s.json", tool, time.Now().UTC().Format("20060102T150405Z")))
+		if err := deploystate.WriteJSON(path, ledger.Report(tool, prices)); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to save the cost report: %v\n", err)
+			return
+		}
+		if !quiet {
+			fmt.Printf("💾 Cost report saved to %s\n", path)
+		}
+	}
+}
@@ -4,15 +4,40 @@ package main
 // Provides a single binary with CLI routing to different tools
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/NethermindEth/oif-starknet/solver/cmd/solver"
+	declaresnhyperlane7683 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/declare-sn-hyperlane7683"
+	declaresnmockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/declare-sn-mock-erc20"
+	deploysnhyperlane7683 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/deploy-sn-hyperlane7683"
+	deploysnmockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/deploy-sn-mock-erc20"
+	registerevmrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/register-evm-routers"
+	registersnrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/register-sn-routers"
+	setupstarknetcontracts "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/setup-starknet-contracts"
+	verifyrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/verify-routers"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/tools/balances"
+	deployforgemockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/deploy-forge-mock-erc20"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/tools/doctor"
+	fillorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/fill-order"
+	fundaccounts "github.com/NethermindEth/oif-starknet/solver/cmd/tools/fund-accounts"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/tools/identities"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/tools/nonce"
 	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	setupforks "github.com/NethermindEth/oif-starknet/solver/cmd/tools/setup-forks"
+	typeddatahash "github.com/NethermindEth/oif-starknet/solver/cmd/tools/typed-data-hash"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
 func main() {
+	// --state-dir and --config apply to every command
+	solverdir.ParseOSArgs()
+	config.ParseRPCURLFlags()
+	parseCostReportFlag()
+	parseToolTimeoutFlag()
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(1)
@@ -24,6 +49,8 @@ func main() {
 	case "solver":
 		// Run the main solver
 		runSolver()
+	case "reset-checkpoint":
+		solver.ResetCheckpoint(os.Args[2:])
 	case "tools":
 		// Route to development tools
 		runTools()
@@ -43,31 +70,76 @@ func printUsage() {
 	fmt.Println("  solver <command> [options]")
 	fmt.Println()
 	fmt.Println("Commands:")
-	fmt.Println("  solver                    Run the main solver")
+	fmt.Println("  solver [--dry-run] [--from-block <network>=<block>]...  Run the main solver; --from-block")
+	fmt.Println("                            starts that network there instead of where the last run stopped,")
+	fmt.Println("                            --dry-run logs the orders it would fill without filling them")
+	fmt.Println("  reset-checkpoint <network>  Start the network from its configured start block on the next run")
 	fmt.Println("  tools <tool> [options]    Run development tools")
 	fmt.Println("  help                      Show this help message")
 	fmt.Println()
+	fmt.Println("Global Options:")
+	fmt.Println("  --config <file>           .env file to load (default: <solver dir>/.env, or OIF_CONFIG)")
+	fmt.Println("  --state-dir <dir>         State directory (default: <solver dir>/state, or OIF_STATE_DIR)")
+	fmt.Println("  --rpc-url [<network>=]<url>  RPC endpoint to use instead of <NETWORK>_RPC_URL(S)")
+	fmt.Println("  --cost-report             Save what the tool's transactions cost to <state dir>/deployment/costs/")
+	fmt.Println("                            (TX_COST_PRICES=ETH=2500,STRK=0.45 converts the fees)")
+	fmt.Println("  --timeout <duration>      Give up on a tool after this long (default: 10m, none for watch and")
+	fmt.Println("                            open-order generate; 0 for none). Goes before the command: a tool's")
+	fmt.Println("                            own --timeout follows it")
+	fmt.Println()
 	fmt.Println("Development Tools:")
 	fmt.Println("  tools open-order <chain>  Create test orders (starknet|ztarknet|evm)")
-	fmt.Println("  tools setup-forks <cmd>   Setup forked networks")
+	fmt.Println("  tools fill-order <id> <origin>  Fill an opened order as the solver")
+	fmt.Println("  tools settle-order <origin> <id>...  Settle filled orders")
+	fmt.Println("  tools refund-order <id> <origin>  Refund an expired, unfilled order")
+	fmt.Println("  tools order-status <network> <id>  Read an order's status and fields from chain")
+	fmt.Println("  tools watch <id>          Print an order's lifecycle events as they happen")
+	fmt.Println("  tools orders list|show    Inspect orders recorded by open-order")
+	fmt.Println("  tools balances [--json]   Show Alice's and the solver's balances and allowances")
+	fmt.Println("  tools doctor              Check keys, deployments and chains before a run")
+	fmt.Println("  tools identities list|add List or register the users orders are opened for")
+	fmt.Println("  tools nonce status|invalidate Inspect or burn Hyperlane7683 sender nonces")
+	fmt.Println("  tools typed-data-hash <json...> Print the SNIP-12 hashes of typed data, --expect to check them")
+	fmt.Println("  tools verify-mock-erc20 [network] Verify the deployed MockERC20 tokens on the block explorers")
+	fmt.Println("  tools setup-forks <cmd>   Bootstrap the forks (deploy|declare|verify)")
+	fmt.Println("  tools <setup step>        Run one setup-forks step on its own:")
+	fmt.Println("                            declare-sn-hyperlane7683, declare-sn-mock-erc20, deploy-sn-hyperlane7683,")
+	fmt.Println("                            deploy-sn-mock-erc20, deploy-forge-mock-erc20, fund-accounts,")
+	fmt.Println("                            setup-starknet-contracts, register-evm-routers, register-sn-routers, verify-routers")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  solver solver                    # Run main solver")
+	fmt.Println("  solver solver --from-block base=12345678 # Rescan Base from block 12345678")
+	fmt.Println("  solver reset-checkpoint base     # Forget how far the solver got on Base")
 	fmt.Println("  solver tools open-order starknet # Create Starknet order")
 	fmt.Println("  solver tools open-order ztarknet # Create Ztarknet order")
 	fmt.Println("  solver tools open-order evm      # Create EVM order")
-	fmt.Println("  solver tools setup-forks deploy  # Deploy to forks")
+	fmt.Println("  solver tools fill-order 0x... base # Fill an order opened on Base")
+	fmt.Println("  solver tools settle-order base 0x... # Settle it once filled")
+	fmt.Println("  solver tools refund-order order.json # Refund an order saved with open-order --json")
+	fmt.Println("  solver tools order-status base 0x... # Status, fields and fill deadline of an order opened on Base")
+	fmt.Println("  solver tools watch 0x...         # Follow an order until it is settled or refunded")
+	fmt.Println("  solver tools orders list --refresh # List recorded orders with on-chain status")
+	fmt.Println("  solver tools balances --json     # Balance/allowance matrix on every network as JSON")
+	fmt.Println("  solver tools doctor              # One pass/warn/fail line per check, exit 1 on failure")
+	fmt.Println("  solver tools identities add Bob --evm 0x... --starknet 0x... # Register Bob")
+	fmt.Println("  solver tools nonce invalidate base 42 # Cancel Alice's gasless order signed with nonce 42")
+	fmt.Println("  solver tools typed-data-hash --expect ../typedData/expected_hashes.json ../typedData/*.json # Check the Permit2 typed data")
+	fmt.Println("  solver tools setup-forks deploy  # Declare, deploy, fund and register on the forks, skipping what is done")
+	fmt.Println("  solver tools fund-accounts base  # Fund Alice & Solver on Base only")
+	fmt.Println("  solver --state-dir /tmp/oif tools orders list # Use another state directory")
+	fmt.Println("  solver --timeout 30m tools setup-forks deploy # Allow a slow fork more time")
 }
 
 func runSolver() {
 	// Run the main solver
-	solver.RunSolver()
+	solver.RunSolver(os.Args[2:])
 }
 
 func runTools() {
 	if len(os.Args) < 3 {
 		fmt.Println("Usage: solver tools <tool> [options]")
-		fmt.Println("Available tools: open-order, setup-forks")
+		fmt.Println("Available tools: open-order, fill-order, settle-order, refund-order, order-status, watch, orders, balances, doctor, identities, nonce, typed-data-hash, verify-mock-erc20, setup-forks and its steps (see solver help)")
 		os.Exit(1)
 	}
 
@@ -76,23 +148,233 @@ func runTools() {
 	switch tool {
 	case "open-order":
 		runOpenOrder()
+	case "fill-order":
+		runFillOrder()
+	case "settle-order":
+		runSettleOrders()
+	case "refund-order":
+		runRefundOrder()
+	case "order-status":
+		runOrderStatus()
+	case "watch":
+		runWatch()
+	case "orders":
+		runOrders()
+	case "balances":
+		ctx, stop := toolContext()
+		defer stop()
+		balances.RunBalances(ctx, os.Args[3:])
+	case "doctor":
+		runStepTool(tool, doctor.Run)
+	case "identities":
+		identities.RunIdentities(os.Args[3:])
+	case "nonce":
+		runNonce()
+	case "typed-data-hash":
+		typeddatahash.RunTypedDataHash(os.Args[3:])
+	case "verify-mock-erc20":
+		runStepTool(tool, deployforgemockerc20.Verify)
 	case "setup-forks":
-		runSetupForks()
+		runStepTool(tool, setupforks.Run)
 	default:
+		if run, ok := stepTools[tool]; ok {
+			runStepTool(tool, run)
+			return
+		}
 		fmt.Printf("Unknown tool: %s\n", tool)
-		fmt.Println("Available tools: open-order, setup-forks")
+		fmt.Println("Available tools: open-order, fill-order, settle-order, refund-order, order-status, watch, orders, balances, doctor, identities, nonce, typed-data-hash, verify-mock-erc20, setup-forks and its steps (see solver help)")
+		os.Exit(1)
+	}
+}
+
+func runFillOrder() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: solver tools fill-order <order-id> [<origin-chain>]")
+		fmt.Println("       solver tools fill-order <open-order-result.json | ->")
+		fmt.Println("  - The destination chain and settler are read from the order's origin data")
+		fmt.Println("  - Without an origin chain the order is looked up in the local order store")
+		fmt.Println("  - '-' reads an open-order --json result from stdin")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  solver tools fill-order 0xabc... Base")
+		fmt.Println("  solver tools open-order evm starknet --json | solver tools fill-order -")
+		os.Exit(1)
+	}
+	runStepTool("fill-order", fillorder.RunFillOrder)
+}
+
+func runSettleOrders() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: solver tools settle-order [<origin-chain>] <order-id>... [--timeout <duration>]")
+		fmt.Println("  - Orders are settled from their destination chain in one settle() call per destination")
+		fmt.Println("  - Without an origin chain the orders are looked up in the local order store")
+		fmt.Println("  - Orders that are not FILLED on the destination are refused")
+		fmt.Println("  - --timeout bounds the wait for the origin to mark orders SETTLED (default: 5m)")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  solver tools settle-order Base 0xabc... 0xdef...")
+		fmt.Println("  solver tools settle-order Starknet 0xabc... --timeout 10m")
+		os.Exit(1)
+	}
+	runStepTool("settle-order", fillorder.RunSettleOrders)
+}
+
+func runRefundOrder() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: solver tools refund-order <order-id> [<origin-chain>] [--timeout <duration>]")
+		fmt.Println("       solver tools refund-order <open-order-result.json | -> [--timeout <duration>]")
+		fmt.Println("  - The order must be OPENED on the origin, unknown on the destination and past its fill deadline")
+		fmt.Println("  - Without an origin chain the order struct comes from the local order store")
+		fmt.Println("  - Gasless orders need the open-order --json result to be refunded through the gasless overload")
+		fmt.Println("  - --timeout bounds the wait for the origin to mark the order REFUNDED (default: 5m)")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  solver tools open-order evm starknet --json > order.json")
+		fmt.Println("  solver tools refund-order order.json")
+		fmt.Println("  solver tools refund-order 0xabc... Base --timeout 10m")
+		os.Exit(1)
+	}
+	runStepTool("refund-order", fillorder.RunRefundOrder)
+}
+
+func runOrderStatus() {
+	if len(os.Args) != 5 {
+		fmt.Println("Usage: solver tools order-status <network> <order-id>")
+		fmt.Println("  - Prints the status Hyperlane7683 keeps for the order on that network")
+		fmt.Println("  - On the origin network also prints the open order, its fill deadline and the destination status")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  solver tools order-status Base 0xabc...")
+		fmt.Println("  solver tools order-status Starknet 0xabc...")
+		os.Exit(1)
+	}
+	ctx, stop := toolContext()
+	defer stop()
+	fillorder.RunOrderStatus(ctx, os.Args[3:])
+}
+
+func runWatch() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: solver tools watch <order-id> [--confirmations <n>]")
+		fmt.Println("  - Watches every network with a Hyperlane7683 address and prints Open, Filled, Settle/Refund and")
+		fmt.Println("    Settled/Refunded events of the order; stops once the origin reports it Settled or Refunded")
+		fmt.Println("  - Events are reported after <NETWORK>_CONFIRMATION_BLOCKS blocks, or --confirmations for every network")
+		fmt.Println("  - <NETWORK>_WS_URL subscribes to new blocks instead of polling")
+		fmt.Println("  - The last scanned block is saved in state/watch-cursor.json (override with WATCH_CURSOR_FILE)")
+		os.Exit(1)
+	}
+	ctx, stop := untimedToolContext()
+	defer stop()
+	fillorder.RunWatch(ctx, os.Args[3:])
+}
+
+func runNonce() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: solver tools nonce status <network> <address> <nonce>")
+		fmt.Println("       solver tools nonce invalidate <network> <nonce> [--user <name>]")
+		fmt.Println("  - status reads usedNonces/isValidNonce of the address from the network's Hyperlane7683")
+		fmt.Println("  - invalidate calls invalidateNonces from the user's key (default: Alice) and checks the nonce is used after")
+		fmt.Println("  - Nonces are decimal or 0x integers")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  solver tools nonce status Base 0xf39F... 42")
+		fmt.Println("  solver tools nonce invalidate Starknet 0x2a --user Alice")
+		os.Exit(1)
+	}
+	runStepTool("nonce", nonce.Run)
+}
+
+func runOrders() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: solver tools orders list [--refresh]")
+		fmt.Println("       solver tools orders show <order-id> [--refresh]")
+		fmt.Println("  - Orders are recorded by open-order in state/orders (override with ORDER_STORE_DIR)")
+		fmt.Println("  - --refresh reads each status back from chain and updates the store")
 		os.Exit(1)
 	}
+	ctx, stop := toolContext()
+	defer stop()
+	fillorder.RunOrders(ctx, os.Args[3:])
 }
 
 func runOpenOrder() {
+	// generate runs for its own --duration or --count
+	newContext := toolContext
+	if len(os.Args) > 3 && strings.EqualFold(os.Args[3], "generate") {
+		newContext = untimedToolContext
+	}
+	ctx, stop := newContext()
+	defer stop()
+	defer openorder.CloseClients()
+
+	// --json may appear anywhere; strip it before the positional arguments are read
+	args, jsonMode := openorder.StripJSONFlag(os.Args)
+	openorder.SetJSONOutput(jsonMode)
+	ctx, reportCosts := withCosts(ctx, "open-order", jsonMode)
+	defer reportCosts()
+	args, network, err := openorder.ExtractNetworkFlag(args)
+	if err != nil {
+		openorder.ExitWithOrderError("", "", err)
+	}
+	args, tokens, err := openorder.ExtractTokenFlags(args)
+	if err != nil {
+		openorder.ExitWithOrderError("", "", err)
+	}
+	openorder.SetTokenSelection(tokens)
+	args, windows, err := openorder.ExtractDeadlineFlags(args)
+	if err != nil {
+		openorder.ExitWithOrderError("", "", err)
+	}
+	openorder.SetDeadlineWindows(windows)
+	args, amounts, err := openorder.ExtractAmountFlags(args)
+	if err != nil {
+		openorder.ExitWithOrderError("", "", err)
+	}
+	openorder.SetOrderAmounts(amounts)
+	args, force := openorder.StripForceFlag(args)
+	openorder.SetForceFallback(force)
+	args, approve := openorder.StripAutoApproveFlag(args)
+	openorder.SetAutoApprove(approve)
+	args, permit := openorder.StripUsePermitFlag(args)
+	openorder.SetUsePermit(permit)
+	args, dryRun := openorder.StripDryRunFlag(args)
+	openorder.SetDryRun(dryRun)
+	args, seed, seeded, err := openorder.ExtractSeedFlag(args)
+	if err != nil {
+		openorder.ExitWithOrderError("", "", err)
+	}
+	if err := openorder.InitRandomness(seed, seeded); err != nil {
+		openorder.ExitWithOrderError("", "", err)
+	}
+	os.Args = args
+
 	if len(os.Args) < 4 {
-		fmt.Println("Usage: solver tools open-order <origin> [destination]")
+		fmt.Println("Usage: solver tools open-order <origin> [destination] [--network <starknet-network>] [--input-token <token>] [--output-token <token>] [--open-deadline <duration>] [--fill-deadline <duration>] [--amount-in <tokens>] [--amount-out <tokens>] [--allow-negative-spread] [--auto-approve] [--use-permit] [--force] [--dry-run] [--seed N] [--json]")
+		fmt.Println("       solver tools open-order batch <count> [--concurrency N] [--multicall N] [--auto-approve]")
+		fmt.Println("       solver tools open-order generate [--rate R] (--duration D | --count N) [--max-inflight N] [--stats-interval D]")
+		fmt.Println("                                        [--amount MIN-MAX] [--delta MIN-MAX] [--fill-window MIN-MAX] [--pairs IN:OUT,...] [--auto-approve]")
+		fmt.Println("       solver tools open-order gasless <evm-origin> [destination] [--json]")
+		fmt.Println("       solver tools open-order evm-to-starknet [evm-origin] [--json]")
 		fmt.Println("Available origins: evm, starknet (strk), ztarknet (ztrk), or specific chain names")
 		fmt.Println("Available destinations: evm, starknet (strk), ztarknet (ztrk), or specific chain names")
 		fmt.Println("  - If destination is omitted, a random valid destination will be selected")
 		fmt.Println("  - 'evm' as origin/destination means any EVM chain (Ethereum, Optimism, Arbitrum, Base)")
+		fmt.Println("  - 'any' means any configured network; pick among several with 'base,arbitrum' or exclude with '!ethereum'")
+		fmt.Println("  - --seed N replays the random networks and amounts of a run (the seed of an unseeded run is printed)")
+		fmt.Println("  - DESTINATION_WEIGHTS=\"Base=5,Arbitrum=1\" weighs random destinations (unlisted: 1, 0 never picked)")
 		fmt.Println("  - Origin and destination cannot be the same")
+		fmt.Println("  - --network picks the Starknet network for a Starknet origin (default: Starknet)")
+		fmt.Println("  - --input-token/--output-token take a symbol (from .env or state/deployment) or a 0x address (default: DogCoin)")
+		fmt.Println("  - --open-deadline/--fill-deadline set the deadlines past the origin's latest block time (default: 1h/24h)")
+		fmt.Println("  - --amount-in/--amount-out set the amounts in tokens (e.g. 1500.5), exact to each token's decimals")
+		fmt.Println("    Orders paying out more than they take in are refused unless --allow-negative-spread is given")
+		fmt.Println("  - Orders are refused when Alice's balance or allowance is short; --auto-approve sends the missing approve() first")
+		fmt.Println("  - --use-permit sets a missing allowance on EVM origins with an EIP-2612 permit sent together with open()")
+		fmt.Println("  - --force uses the origin's token/settler when the destination's is missing (debugging only: the order cannot be filled)")
+		fmt.Println("  - --dry-run builds the order and simulates open() as Alice without sending anything (no private key needed)")
+		fmt.Println("  - batch --multicall N opens N orders per transaction through Multicall3, as Permit2-signed openFor calls")
+		fmt.Println("    (Alice's input is approved to Permit2); chains without Multicall3 fall back to one transaction per order")
+		fmt.Println("  - --json silences progress output and prints a single JSON result (exit code 1 on failure)")
 		fmt.Println()
 		fmt.Println("Examples:")
 		fmt.Println("  solver tools open-order evm              # EVM → random destination")
@@ -100,67 +382,158 @@ func runOpenOrder() {
 		fmt.Println("  solver tools open-order starknet evm    # Starknet → EVM")
 		fmt.Println("  solver tools open-order ztarknet starknet # Ztarknet → Starknet")
 		fmt.Println("  solver tools open-order ethereum base   # Ethereum → Base")
+		fmt.Println("  solver tools open-order evm '!ethereum' --seed 7 # Reproducible EVM → non-Ethereum pick")
+		fmt.Println("  solver tools open-order batch 20 --concurrency 5 # 20 random EVM orders")
+		fmt.Println("  solver tools open-order batch 100 --multicall 10 --auto-approve # 10 orders per transaction")
+		fmt.Println("  solver tools open-order generate --rate 0.2 --duration 30m --max-inflight 5 --auto-approve # Soak test the solver")
+		fmt.Println("  solver tools open-order gasless ethereum base # Alice signs, Solver submits openFor")
+		fmt.Println("  solver tools open-order evm-to-starknet base # Base → Starknet")
+		fmt.Println("  solver tools open-order starknet evm --network Starknet # Starknet network by name")
+		fmt.Println("  solver tools open-order starknet evm --json # Machine-readable result for CI")
+		fmt.Println("  solver tools open-order base starknet --dry-run # Simulate open() and print the calldata")
+		fmt.Println("  solver tools open-order base starknet --input-token OrcaCoin --output-token DogCoin # Tokens from deploy-tokens.json")
+		fmt.Println("  solver tools open-order ethereum base --amount-in 1500.5 --amount-out 1499 # Exact amounts in tokens")
 		os.Exit(1)
 	}
 
+	// --network only selects a Starknet origin; the special modes below all open on EVM
+	switch strings.ToLower(os.Args[3]) {
+	case "batch", "generate", "evm-to-starknet", "gasless":
+		if network != "" {
+			openorder.ExitWithOrderError("", "", fmt.Errorf("%s only applies to Starknet origins", openorder.NetworkFlag))
+		}
+	}
+	// Batch, generated and gasless orders send through their own paths, which have no simulation step
+	switch strings.ToLower(os.Args[3]) {
+	case "batch", "generate", "gasless":
+		if dryRun {
+			openorder.ExitWithOrderError("", "", fmt.Errorf("%s is not supported in %s mode", openorder.DryRunFlag, strings.ToLower(os.Args[3])))
+		}
+		if permit {
+			openorder.ExitWithOrderError("", "", fmt.Errorf("%s is not supported in %s mode", openorder.UsePermitFlag, strings.ToLower(os.Args[3])))
+		}
+	}
+	// Batch and generated orders draw their own random amounts
+	switch strings.ToLower(os.Args[3]) {
+	case "batch", "generate":
+		if amounts.In != "" || amounts.Out != "" {
+			openorder.ExitWithOrderError("", "", fmt.Errorf("%s/%s are not supported in %s mode", openorder.AmountInFlag, openorder.AmountOutFlag, strings.ToLower(os.Args[3])))
+		}
+	}
+
+	// Batch mode opens many random EVM orders at once
+	if strings.ToLower(os.Args[3]) == "batch" {
+		if jsonMode {
+			fmt.Println("❌ --json is not supported in batch mode")
+			os.Exit(1)
+		}
+		openorder.RunEVMBatch(ctx, os.Args[4:])
+		return
+	}
+
+	// Generate mode keeps opening random EVM orders at a steady rate to soak test the solver
+	if strings.ToLower(os.Args[3]) == "generate" {
+		if jsonMode {
+			fmt.Println("❌ --json is not supported in generate mode")
+			os.Exit(1)
+		}
+		openorder.RunOrderGenerator(ctx, os.Args[4:])
+		return
+	}
+
+	// EVM → Starknet mode; the EVM origin is optional and defaults to a random EVM chain
+	if strings.ToLower(os.Args[3]) == "evm-to-starknet" {
+		args := os.Args
+		if len(args) < 5 {
+			args = append(args, "evm")
+		}
+		originChain, err := openorder.GetOriginFromArgs(args, 4)
+		if err != nil {
+			openorder.ExitWithOrderError("", "", fmt.Errorf("error getting origin: %w", err))
+		}
+		openorder.RunEVMToStarknetOrder(ctx, originChain)
+		return
+	}
+
+	// Gasless mode opens an EVM order via openFor with Alice's Permit2 signature
+	if strings.ToLower(os.Args[3]) == "gasless" {
+		originChain, err := openorder.GetOriginFromArgs(os.Args, 4)
+		if err != nil {
+			openorder.ExitWithOrderError("", "", fmt.Errorf("error getting origin: %w", err))
+		}
+		destinationChain, err := openorder.GetDestinationFromArgs(originChain, os.Args, 5)
+		if err != nil {
+			openorder.ExitWithOrderError(originChain, "", fmt.Errorf("error getting destination: %w", err))
+		}
+		openorder.RunEVMGaslessOrder(ctx, originChain, destinationChain)
+		return
+	}
+
 	// Get origin chain
 	originChain, err := openorder.GetOriginFromArgs(os.Args, 3)
 	if err != nil {
-		fmt.Printf("❌ Error getting origin: %v\n", err)
-		os.Exit(1)
+		openorder.ExitWithOrderError("", "", fmt.Errorf("error getting origin: %w", err))
+	}
+	originChain, err = openorder.SelectStarknetNetwork(originChain, network)
+	if err != nil {
+		openorder.ExitWithOrderError("", "", fmt.Errorf("error getting origin: %w", err))
 	}
 
 	// Get destination chain (optional)
 	destinationChain, err := openorder.GetDestinationFromArgs(originChain, os.Args, 4)
 	if err != nil {
-		fmt.Printf("❌ Error getting destination: %v\n", err)
-		os.Exit(1)
+		openorder.ExitWithOrderError(originChain, "", fmt.Errorf("error getting destination: %w", err))
 	}
 
 	// Determine network type and route to appropriate handler
 	originType := openorder.GetNetworkType(originChain)
+	if permit && originType != openorder.NetworkTypeEVM {
+		openorder.ExitWithOrderError(originChain, destinationChain, fmt.Errorf("%s only applies to EVM origins", openorder.UsePermitFlag))
+	}
 	
 	switch originType {
 	case openorder.NetworkTypeStarknet:
 		// For Starknet, we need to construct a command string
 		command := "custom"
-		openorder.RunStarknetOrderWithDest(command, originChain, destinationChain)
+		openorder.RunStarknetOrderWithDest(ctx, command, originChain, destinationChain)
 	case openorder.NetworkTypeZtarknet:
 		// For Ztarknet, we need to construct a command string
 		command := "custom"
-		openorder.RunZtarknetOrderWithDest(command, originChain, destinationChain)
+		openorder.RunZtarknetOrderWithDest(ctx, command, originChain, destinationChain)
 	case openorder.NetworkTypeEVM:
 		// For EVM, we need to construct a command string
 		command := "custom"
-		openorder.RunEVMOrderWithDest(command, originChain, destinationChain)
+		openorder.RunEVMOrderWithDest(ctx, command, originChain, destinationChain)
 	default:
-		fmt.Printf("❌ Unknown origin network type: %s\n", originChain)
-		os.Exit(1)
+		openorder.ExitWithOrderError(originChain, destinationChain, fmt.Errorf("unknown origin network type: %s", originChain))
 	}
 }
 
-func runSetupForks() {
-	if len(os.Args) < 4 {
-		fmt.Println("Usage: solver tools setup-forks <command>")
-		fmt.Println("Available commands: deploy, declare, verify")
-		os.Exit(1)
-	}
-
-	cmd := os.Args[3]
+// stepTools are the fork setup steps, also run together by setup-forks deploy
+var stepTools = map[string]func(context.Context, []string) error{
+	"declare-sn-hyperlane7683": declaresnhyperlane7683.Run,
+	"declare-sn-mock-erc20":    declaresnmockerc20.Run,
+	"deploy-sn-hyperlane7683":  deploysnhyperlane7683.Run,
+	"deploy-sn-mock-erc20":     deploysnmockerc20.Run,
+	"deploy-forge-mock-erc20":  deployforgemockerc20.Run,
+	"fund-accounts":            fundaccounts.Run,
+	"setup-starknet-contracts": setupstarknetcontracts.Run,
+	"register-evm-routers":     registerevmrouters.Run,
+	"register-sn-routers":      registersnrouters.Run,
+	"verify-routers":           verifyrouters.Run,
+}
 
-	switch strings.ToLower(cmd) {
-	case "deploy":
-		fmt.Println("🔧 Running fork deployment...")
-		// This will call the deployment logic
-	case "declare":
-		fmt.Println("🔧 Running contract declaration...")
-		// This will call the declaration logic
-	case "verify":
-		fmt.Println("🔧 Running deployment verification...")
-		// This will call the verification logic
-	default:
-		fmt.Printf("Unknown setup command: %s\n", cmd)
-		fmt.Println("Available commands: deploy, declare, verify")
+// runStepTool runs a tool returning an error, then reports what its transactions cost; it exits 1 on failure
+func runStepTool(tool string, run func(context.Context, []string) error) {
+	ctx, stop := toolContext()
+	defer stop()
+	ctx, reportCosts := withCosts(ctx, tool, false)
+	err := run(ctx, os.Args[3:])
+	reportCosts()
+	if err != nil {
+		err = toolError(ctx, err)
+		stop()
+		fmt.Printf("❌ %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -5,38 +5,66 @@ package solver
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
 )
 
-// Custom formatter that outputs only the message
-type cleanFormatter struct{}
+const (
+	// fromBlockFlag starts a network's listener at a given block instead of its checkpoint
+	fromBlockFlag = "--from-block"
+	// dryRunFlag evaluates orders against the rules and fill policy without filling them
+	dryRunFlag = "--dry-run"
+)
 
-func (f *cleanFormatter) Format(entry *logrus.Entry) ([]byte, error) {
-	return append([]byte(entry.Message), '\n'), nil
+// solverOptions are the options of the solver command
+type solverOptions struct {
+	fromBlocks map[string]uint64
+	dryRun     bool
 }
 
-// RunSolver runs the main solver application
-func RunSolver() {
+// RunSolver runs the main solver application; args are the options after the solver command
+func RunSolver(args []string) {
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		logrus.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	options, err := parseSolverOptions(args)
+	if err != nil {
+		logrus.Fatalf("%v", err)
+	}
+	cfg.FromBlocks, cfg.DryRun = options.fromBlocks, options.dryRun
+	cfg.FillPolicy, err = config.LoadFillPolicy(cfg.Networks)
+	if err != nil {
+		logrus.Fatalf("Invalid fill policy: %v", err)
+	}
+	cfg.Inventory, err = config.LoadInventoryConfig(cfg.Networks)
+	if err != nil {
+		logrus.Fatalf("Invalid inventory settings: %v", err)
+	}
+	if cfg.DryRun {
+		// A dry run must not move checkpoints past orders it did not fill
+		config.SetSolverStateReadOnly(true)
+	}
+
 	// Initialize networks from centralized config after .env is loaded
 	config.InitializeNetworks()
 
-	// Set up clean logging
-	logrus.SetFormatter(&cleanFormatter{})
-	logrus.SetLevel(logrus.InfoLevel)
+	// Set up clean logging; LOG_LEVEL and LOG_FORMAT=json override the defaults
+	logutil.ConfigureFromConfig(logrus.StandardLogger(), cfg)
 
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -52,11 +80,45 @@ func RunSolver() {
 		cancel()
 	}()
 
+	// Serve /metrics and /healthz when METRICS_ADDR is set
+	if cfg.MetricsAddr != "" {
+		addr, err := metrics.ListenAddr(cfg.MetricsAddr)
+		if err != nil {
+			logrus.Fatalf("Invalid METRICS_ADDR: %v", err)
+		}
+		go func() {
+			if err := metrics.Serve(ctx, addr, metrics.Default); err != nil {
+				logrus.Errorf("❌ %v", err)
+			}
+		}()
+		logrus.Infof("   📈 Metrics on http://%s/metrics, health on /healthz", addr)
+	}
+
 	// Initialize solver manager
 	solverManager := solvercore.NewSolverManager(cfg)
 
+	// Serve the admin API when ADMIN_ADDR is set; it refuses to start without a token rather than run open
+	if cfg.AdminAddr != "" {
+		if cfg.AdminToken == "" {
+			logrus.Fatal("ADMIN_ADDR is set but ADMIN_TOKEN is empty; set a token to enable the admin API")
+		}
+		addr, err := metrics.ListenAddr(cfg.AdminAddr)
+		if err != nil {
+			logrus.Fatalf("Invalid ADMIN_ADDR: %v", err)
+		}
+		go func() {
+			if err := admin.Serve(ctx, addr, admin.Handler(solverManager, cfg, cfg.AdminToken)); err != nil {
+				logrus.Errorf("❌ %v", err)
+			}
+		}()
+		logrus.Infof("   🛠️  Admin API on http://%s (bearer token from ADMIN_TOKEN)", addr)
+	}
+
 	// Start the solver
 	logrus.Info("Starting OIF Solver...")
+	if cfg.DryRun {
+		logrus.Info("   🧪 Dry run: orders are evaluated and logged, nothing is filled and solver state is not saved")
+	}
 	logrus.Info("   Monitoring networks:", strings.Join(config.GetNetworkNames(), ", "))
 	logrus.Info("   ⏰ Poll interval: 1000ms (default)")
 	logrus.Info("   🛑 Press Ctrl+C to stop")
@@ -68,6 +130,65 @@ func RunSolver() {
 	logrus.Info("✅ Solver stopped gracefully")
 }
 
+// parseSolverOptions reads --dry-run and --from-block <network>=<block> (or --from-block=<network>=<block>), once
+// per network
+func parseSolverOptions(args []string) (solverOptions, error) {
+	options := solverOptions{fromBlocks: make(map[string]uint64)}
+	for i := 0; i < len(args); i++ {
+		if args[i] == dryRunFlag {
+			options.dryRun = true
+			continue
+		}
+		value, ok := strings.CutPrefix(args[i], fromBlockFlag+"=")
+		if !ok {
+			if args[i] != fromBlockFlag {
+				return solverOptions{}, fmt.Errorf("unknown solver option %q (expected %s or %s <network>=<block>)", args[i], dryRunFlag, fromBlockFlag)
+			}
+			if i+1 >= len(args) {
+				return solverOptions{}, fmt.Errorf("%s requires <network>=<block>", fromBlockFlag)
+			}
+			i++
+			value = args[i]
+		}
+
+		name, blockText, found := strings.Cut(value, "=")
+		if !found {
+			return solverOptions{}, fmt.Errorf("invalid %s %q: expected <network>=<block>", fromBlockFlag, value)
+		}
+		network, err := config.CanonicalNetworkName(name)
+		if err != nil {
+			return solverOptions{}, fmt.Errorf("invalid %s %q: %w", fromBlockFlag, value, err)
+		}
+		block, err := strconv.ParseUint(blockText, 10, 64)
+		if err != nil {
+			return solverOptions{}, fmt.Errorf("invalid %s %q: block must be a non-negative number", fromBlockFlag, value)
+		}
+		options.fromBlocks[network] = block
+	}
+	return options, nil
+}
+
+// ResetCheckpoint forgets where the solver got to on the network named in args, so its next run starts from the
+// network's configured start block. The solver must not be running, or it will save its own position again
+func ResetCheckpoint(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: solver reset-checkpoint <network>")
+		os.Exit(2)
+	}
+	if _, err := config.LoadConfig(); err != nil {
+		logrus.Fatalf("Failed to load configuration: %v", err)
+	}
+	network, err := config.CanonicalNetworkName(args[0])
+	if err != nil {
+		logrus.Fatalf("%v", err)
+	}
+	block, err := config.ResetLastIndexedBlock(network)
+	if err != nil {
+		logrus.Fatalf("Failed to reset %s checkpoint: %v", network, err)
+	}
+	fmt.Printf("🔄 Reset the %s checkpoint: the next run starts after block %d\n", network, block)
+}
+
 // TestConnection tests the connection to all configured networks
 func TestConnection() {
 	_, err := config.LoadConfig()
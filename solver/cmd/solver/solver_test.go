@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,7 +29,7 @@ func captureLogrusOutput(fn func()) string {
 
 func TestCleanFormatter(t *testing.T) {
 	t.Run("cleanFormatter formats message correctly", func(t *testing.T) {
-		formatter := &cleanFormatter{}
+		formatter := &logutil.CleanFormatter{}
 		entry := &logrus.Entry{
 			Message: "Test message",
 		}
@@ -39,7 +40,7 @@ func TestCleanFormatter(t *testing.T) {
 	})
 
 	t.Run("cleanFormatter handles empty message", func(t *testing.T) {
-		formatter := &cleanFormatter{}
+		formatter := &logutil.CleanFormatter{}
 		entry := &logrus.Entry{
 			Message: "",
 		}
@@ -50,7 +51,7 @@ func TestCleanFormatter(t *testing.T) {
 	})
 
 	t.Run("cleanFormatter handles multiline message", func(t *testing.T) {
-		formatter := &cleanFormatter{}
+		formatter := &logutil.CleanFormatter{}
 		entry := &logrus.Entry{
 			Message: "Line 1\nLine 2",
 		}
@@ -153,11 +154,11 @@ func TestSolverUtilities(t *testing.T) {
 		}()
 
 		// Set up clean logging like in RunSolver
-		logrus.SetFormatter(&cleanFormatter{})
+		logrus.SetFormatter(&logutil.CleanFormatter{})
 		logrus.SetLevel(logrus.InfoLevel)
 
 		// Verify the formatter was set
-		assert.IsType(t, &cleanFormatter{}, logrus.StandardLogger().Formatter)
+		assert.IsType(t, &logutil.CleanFormatter{}, logrus.StandardLogger().Formatter)
 		assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
 	})
 
@@ -350,3 +351,36 @@ func TestContextHandling(t *testing.T) {
 		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
 	})
 }
+
+func TestParseSolverOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantBlocks map[string]uint64
+		wantDryRun bool
+		wantErr    string
+	}{
+		{"none", nil, map[string]uint64{}, false, ""},
+		{"dry run", []string{"--dry-run"}, map[string]uint64{}, true, ""},
+		{"separate value", []string{"--from-block", "base=123"}, map[string]uint64{"Base": 123}, false, ""},
+		{"joined value, several networks", []string{"--from-block=Ethereum=7", "--dry-run", "--from-block", "starknet=0"}, map[string]uint64{"Ethereum": 7, "Starknet": 0}, true, ""},
+		{"missing value", []string{"--from-block"}, nil, false, "requires"},
+		{"no network", []string{"--from-block", "123"}, nil, false, "expected <network>=<block>"},
+		{"unknown network", []string{"--from-block", "mars=1"}, nil, false, "network not found"},
+		{"bad block", []string{"--from-block", "base=-1"}, nil, false, "non-negative"},
+		{"unknown option", []string{"--verbose"}, nil, false, "unknown solver option"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSolverOptions(tt.args)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBlocks, got.fromBlocks)
+			assert.Equal(t, tt.wantDryRun, got.dryRun)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+// Tool deadline and cancellation
+// Every tool runs under one root context: it is cancelled by Ctrl-C / SIGTERM and expires after --timeout, so a
+// hung RPC call or receipt wait ends the tool with an error instead of hanging the make target that ran it.
+// Catching the signal also means the process is never killed halfway through its work: tools see the context
+// cancelled, stop waiting and return before they write any more deployment state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	toolTimeoutFlag = "--timeout"
+	// defaultToolTimeout bounds a tool run without --timeout
+	defaultToolTimeout = 10 * time.Minute
+)
+
+var (
+	// errCancelledByUser is the cause of a tool context cancelled by a signal
+	errCancelledByUser = errors.New("cancelled by user")
+	// toolTimeout is set by --timeout; zero means no deadline
+	toolTimeout = defaultToolTimeout
+	// toolTimeoutSet records an explicit --timeout, the only deadline of tools that run until told to stop
+	toolTimeoutSet bool
+)
+
+// parseToolTimeoutFlag reads --timeout from os.Args, exiting on an invalid value
+func parseToolTimeoutFlag() {
+	args, timeout, set, err := extractToolTimeout(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+	os.Args = append(os.Args[:1], args...)
+	toolTimeout, toolTimeoutSet = timeout, set
+}
+
+// extractToolTimeout removes --timeout <duration> (or --timeout=<duration>) from the options before the command.
+// Later ones are left alone: settle-order, refund-order, balances and doctor have a --timeout of their own
+func extractToolTimeout(args []string) ([]string, time.Duration, bool, error) {
+	timeout, set := defaultToolTimeout, false
+	rest := make([]string, 0, len(args))
+	i := 0
+	for ; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		value, ok := strings.CutPrefix(args[i], toolTimeoutFlag+"=")
+		switch {
+		case ok:
+		case args[i] == toolTimeoutFlag:
+			if i+1 >= len(args) {
+				return nil, 0, false, fmt.Errorf("%s requires a duration", toolTimeoutFlag)
+			}
+			i++
+			value = args[i]
+		default:
+			rest = append(rest, args[i])
+			continue
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return nil, 0, false, fmt.Errorf("invalid %s %q: expected a duration such as 10m, or 0 for none", toolTimeoutFlag, value)
+		}
+		timeout, set = parsed, true
+	}
+	return append(rest, args[i:]...), timeout, set, nil
+}
+
+// toolContext returns the root context of a tool run: cancelled on Ctrl-C / SIGTERM and expiring after
+// toolTimeout
+func toolContext() (context.Context, context.CancelFunc) {
+	return newToolContext(toolTimeout)
+}
+
+// untimedToolContext is toolContext for tools bounded by their own arguments, such as watch or open-order
+// generate: only an explicit --timeout gives them a deadline
+func untimedToolContext() (context.Context, context.CancelFunc) {
+	if toolTimeoutSet {
+		return newToolContext(toolTimeout)
+	}
+	return newToolContext(0)
+}
+
+// newToolContext builds a tool context expiring after timeout, if positive. Signals keep being caught until the
+// returned function is called, so a second Ctrl-C does not kill the tool while it is still unwinding
+func newToolContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			fmt.Fprintln(os.Stderr, "\n⚠️  Cancelled by user, stopping once the current step returns")
+			cancel(errCancelledByUser)
+		case <-ctx.Done():
+		}
+	}()
+
+	stopTimeout := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, stopTimeout = context.WithTimeoutCause(ctx, timeout,
+			fmt.Errorf("timed out after %s (raise it with solver %s <duration> tools ...)", timeout, toolTimeoutFlag))
+	}
+	return ctx, func() {
+		stopTimeout()
+		cancel(context.Canceled)
+		signal.Stop(signals)
+	}
+}
+
+// toolError names why ctx ended when err is the tool giving up because of it
+func toolError(ctx context.Context, err error) error {
+	cause := context.Cause(ctx)
+	if cause == nil || errors.Is(err, cause) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", cause, err)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractToolTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		rest    []string
+		timeout time.Duration
+		set     bool
+		wantErr bool
+	}{
+		{"default", []string{"tools", "doctor"}, []string{"tools", "doctor"}, defaultToolTimeout, false, false},
+		{"separate value", []string{"--timeout", "30m", "tools", "watch", "0x01"}, []string{"tools", "watch", "0x01"}, 30 * time.Minute, true, false},
+		{"equals value", []string{"--timeout=0", "tools", "watch"}, []string{"tools", "watch"}, 0, true, false},
+		{"other global flags kept", []string{"-v", "--timeout", "1m", "tools"}, []string{"-v", "tools"}, time.Minute, true, false},
+		{"tool flag left alone", []string{"tools", "settle-order", "0x01", "--timeout", "1m"}, []string{"tools", "settle-order", "0x01", "--timeout", "1m"}, defaultToolTimeout, false, false},
+		{"missing value", []string{"--timeout"}, nil, 0, false, true},
+		{"negative", []string{"--timeout", "-1m", "tools"}, nil, 0, false, true},
+		{"not a duration", []string{"--timeout", "soon", "tools"}, nil, 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, timeout, set, err := extractToolTimeout(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rest, rest)
+			assert.Equal(t, tt.timeout, timeout)
+			assert.Equal(t, tt.set, set)
+		})
+	}
+}
+
+func TestToolError(t *testing.T) {
+	failed := errors.New("rpc failed")
+	assert.Equal(t, failed, toolError(context.Background(), failed))
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errCancelledByUser)
+	err := toolError(ctx, errors.New("failed waiting for receipt: context canceled"))
+	assert.ErrorIs(t, err, errCancelledByUser)
+	assert.Equal(t, "cancelled by user: failed waiting for receipt: context canceled", err.Error())
+
+	ctx, stop := newToolContext(time.Millisecond)
+	defer stop()
+	<-ctx.Done()
+	assert.ErrorContains(t, toolError(ctx, ctx.Err()), "timed out after 1ms")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"golang.org/x/term"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+)
+
+// Encrypts a Starknet private key into a keystore the tools read with <PREFIX>_KEY_SOURCE=keystore:<out>, so
+// the raw key does not have to live in .env. The key is read from stdin, without echo when typed at a terminal, and
+// its public key is derived from it.
+//
+// Usage: create-sn-keystore <out.json> <password-file> < private-key
+//
+// Then set e.g. STARKNET_ALICE_KEY_SOURCE=keystore:<out.json> and STARKNET_ALICE_KEY_PASSWORD_FILE=<password-file>.
+// EVM keystores are standard go-ethereum ones: create them with `geth account new` or `cast wallet import`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: create-sn-keystore <out.json> <password-file> < private-key")
+	}
+	out, passwordFile := args[0], args[1]
+
+	if _, err := os.Stat(out); err == nil {
+		return fmt.Errorf("%s already exists, not overwriting it", out)
+	}
+	password, err := credentials.ReadPasswordFile(passwordFile)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("password file %s is empty", passwordFile)
+	}
+
+	line, err := readPrivateKey()
+	if err != nil {
+		return err
+	}
+	privateKey, ok := new(big.Int).SetString(strings.TrimSpace(line), 0)
+	if !ok {
+		return fmt.Errorf("the private key is not an integer (0x-prefixed hex or decimal)")
+	}
+	key, err := credentials.NewStarknetKeyPair(privateKey)
+	if err != nil {
+		return err
+	}
+
+	keyJSON, err := credentials.EncryptStarknetKey(key, password, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, keyJSON, 0o600); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+
+	fmt.Printf("✅ Wrote %s\n", out)
+	fmt.Printf("   Public key: %s\n", key.PublicKey.String())
+	return nil
+}
+
+// readPrivateKey reads the private key from stdin, without echoing it when stdin is a terminal
+func readPrivateKey() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "🔑 Paste the Starknet private key and press enter: ")
+		key, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the private key: %w", err)
+		}
+		return string(key), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read the private key: %w", err)
+	}
+	return line, nil
+}
@@ -0,0 +1,176 @@
+package declaresnhyperlane7683
+
+// Declare tool: declares the Hyperlane7683 class on Starknet with the deployer account and records its class
+// hash in the deployment state, where deploy-sn-hyperlane7683 reads it
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	sierraContractFile = "oif_starknet_Hyperlane7683.contract_class.json"
+	casmContractFile   = "oif_starknet_Hyperlane7683.compiled_contract_class.json"
+
+	// DeclarationFile is the deployment state file the class hash is recorded in
+	DeclarationFile = "starknet-hyperlane7683-declaration.json"
+)
+
+// Run declares Hyperlane7683 on Starknet with the deployer account. A class that is already declared is only
+// recorded
+func Run(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected argument: %s", args[0])
+	}
+	if err := solverdir.LoadEnv(); err != nil {
+		fmt.Printf("⚠️  %v, using environment variables\n", err)
+	}
+
+	// Initialize networks from centralized config after .env is loaded
+	config.InitializeNetworks()
+
+	fmt.Println("📋 Declaring Hyperlane7683 contract on Starknet...")
+
+	// Load environment variables
+	networkName := "Starknet"
+
+	// Get network configuration
+	networkConfig, err := config.GetNetworkConfig(networkName)
+	if err != nil {
+		return fmt.Errorf("failed to get network config for %s: %w", networkName, err)
+	}
+
+	// Load Starknet account details from .env
+	accountAddress := os.Getenv("STARKNET_DEPLOYER_ADDRESS")
+	accountPrivateKey := os.Getenv("STARKNET_DEPLOYER_PRIVATE_KEY")
+	accountPublicKey := os.Getenv("STARKNET_DEPLOYER_PUBLIC_KEY")
+
+	if accountAddress == "" || accountPrivateKey == "" || accountPublicKey == "" {
+		fmt.Println("❌ Missing required environment variables:")
+		fmt.Println("   STARKNET_DEPLOYER_ADDRESS: Your Starknet account address")
+		fmt.Println("   STARKNET_DEPLOYER_PRIVATE_KEY: Your private key")
+		fmt.Println("   STARKNET_DEPLOYER_PUBLIC_KEY: Your public key")
+		return errors.New("missing deployer environment variables")
+	}
+
+	fmt.Printf("📋 Network: %s\n", networkName)
+	fmt.Printf("📋 RPC URL: %s\n", networkConfig.RPCURL)
+	fmt.Printf("📋 Chain ID: %s\n", networkConfig.ChainIDLabel())
+	fmt.Printf("📋 Account: %s\n", accountAddress)
+
+	// Initialize connection to RPC provider
+	client, err := rpc.NewProvider(networkConfig.RPCURL)
+	if err != nil {
+		return fmt.Errorf("error connecting to RPC provider: %w", err)
+	}
+	if err := config.VerifyStarknetChainID(ctx, client, networkConfig); err != nil {
+		return err
+	}
+
+	// Initialize the account memkeyStore (public and private keys)
+	ks := account.NewMemKeystore()
+	privKeyBI, ok := new(big.Int).SetString(accountPrivateKey, 0)
+	if !ok {
+		return errors.New("failed to convert private key to big.Int")
+	}
+	ks.Put(accountPublicKey, privKeyBI)
+
+	// Convert account address to felt
+	accountAddressInFelt, err := utils.HexToFelt(accountAddress)
+	if err != nil {
+		return fmt.Errorf("failed to transform the account address, did you give the hex address? %w", err)
+	}
+
+	accnt, err := starknetutil.NewDeployerAccount(ctx, client, networkName, accountAddressInFelt, accountPublicKey, ks)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Connected to Starknet RPC")
+
+	// The compiled classes are read from the Cairo project next to the solver directory, whatever the cwd
+	sierraContractFilePath := solverdir.Path("..", "cairo", "target", "dev", sierraContractFile)
+	casmContractFilePath := solverdir.Path("..", "cairo", "target", "dev", casmContractFile)
+	fmt.Printf("📋 Loading contract files:\n")
+	fmt.Printf("   Sierra: %s\n", sierraContractFilePath)
+	fmt.Printf("   Casm: %s\n", casmContractFilePath)
+
+	// Unmarshalling the casm contract class from a JSON file.
+	casmClass, err := utils.UnmarshalJSONFileToType[contracts.CasmClass](casmContractFilePath, "")
+	if err != nil {
+		return fmt.Errorf("failed to parse casm contract: %w", err)
+	}
+
+	// Unmarshalling the sierra contract class from a JSON file.
+	contractClass, err := utils.UnmarshalJSONFileToType[contracts.ContractClass](sierraContractFilePath, "")
+	if err != nil {
+		return fmt.Errorf("failed to parse sierra contract: %w", err)
+	}
+
+	// The class hash is computed locally so it can be recorded even when the class is already declared
+	classHash := hash.ClassHash(contractClass)
+	fmt.Printf("📋 Class hash: %s\n", classHash)
+
+	// Building and sending the Broadcast Invoke Txn.
+	resp, err := accnt.BuildAndSendDeclareTxn(
+		ctx,
+		casmClass,
+		contractClass,
+		nil,
+	)
+	if err != nil {
+		reported, declared := starknetutil.AlreadyDeclared(err)
+		if !declared {
+			return fmt.Errorf("declaration failed: %w", err)
+		}
+		if reported != nil && !reported.Equal(classHash) {
+			fmt.Printf("⚠️  Node reports class hash %s, computed %s; using the node's\n", reported, classHash)
+			classHash = reported
+		}
+		fmt.Printf("✅ Contract already declared\n")
+		fmt.Printf("   Class Hash: %s\n", classHash)
+		saveDeclarationInfo("", classHash.String(), networkName)
+		return nil
+	}
+
+	// Building and sending the declare transaction
+	fmt.Println("📤 Declaring contract...")
+	waitCtx := txcost.WithOperation(ctx, "declare Hyperlane7683")
+	if _, err := starknetutil.WaitForFinality(waitCtx, accnt.Provider, resp.Hash, starknetutil.FinalityAcceptedOnL2, time.Second, 0); err != nil {
+		return fmt.Errorf("declare txn failed: %w", err)
+	}
+
+	fmt.Printf("✅ Contract declaration completed!\n")
+	fmt.Printf("   Class Hash: %s\n", resp.ClassHash)
+
+	// Save declaration info
+	saveDeclarationInfo(resp.Hash.String(), resp.ClassHash.String(), networkName)
+	return nil
+}
+
+// saveDeclarationInfo saves declaration information to a file; txHash is empty when the class was already declared
+func saveDeclarationInfo(txHash, classHash, networkName string) {
+	filename := deploystate.Path(DeclarationFile)
+	if err := deploystate.WriteDeclaration(filename, networkName, classHash, txHash); err != nil {
+		fmt.Printf("⚠️  Failed to save declaration info: %s\n", err)
+		return
+	}
+
+	fmt.Printf("💾 Declaration info saved to %s\n", filename)
+}
@@ -0,0 +1,287 @@
+package deploysnhyperlane7683
+
+// Deploy tool: deploys Hyperlane7683 on Starknet through the UDC from the class declared by
+// declare-sn-hyperlane7683, and records the address in the deployment state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	declaresnhyperlane7683 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/declare-sn-hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// Run deploys Hyperlane7683 as described by args: [--write-env] [--salt <felt>] [--predict-only]
+func Run(ctx context.Context, args []string) error {
+	if err := solverdir.LoadEnv(); err != nil {
+		fmt.Printf("⚠️  %v, using environment variables\n", err)
+	}
+
+	// Initialize networks from centralized config after .env is loaded
+	config.InitializeNetworks()
+
+	fmt.Println("🚀 Deploying Hyperlane7683 contract to Starknet...")
+
+	opts, err := starknetutil.ParseDeployArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Load environment variables
+	networkName := "Starknet"
+
+	// Get network configuration
+	networkConfig, err := config.GetNetworkConfig(networkName)
+	if err != nil {
+		return fmt.Errorf("failed to get network config for %s: %w", networkName, err)
+	}
+
+	// Load Starknet account details from .env
+	deployerAddress := os.Getenv("STARKNET_DEPLOYER_ADDRESS")
+	deployerPrivateKey := os.Getenv("STARKNET_DEPLOYER_PRIVATE_KEY")
+	deployerPublicKey := os.Getenv("STARKNET_DEPLOYER_PUBLIC_KEY")
+
+	// Load constructor parameters from environment variables
+	permit2Addr := os.Getenv("STARKNET_PERMIT2_ADDRESS")
+	mailboxAddr := os.Getenv("STARKNET_MAILBOX_ADDRESS")
+	hookAddr := os.Getenv("STARKNET_HOOK_ADDRESS")
+	ismAddr := os.Getenv("STARKNET_ISM_ADDRESS")
+
+	if deployerAddress == "" || deployerPrivateKey == "" || deployerPublicKey == "" {
+		fmt.Println("❌ Missing required environment variables:")
+		fmt.Println("   STARKNET_DEPLOYER_ADDRESS: Your Starknet account address")
+		fmt.Println("   STARKNET_DEPLOYER_PRIVATE_KEY: Your private key")
+		fmt.Println("   STARKNET_DEPLOYER_PUBLIC_KEY: Your public key")
+		fmt.Println("")
+		fmt.Println("Optional constructor parameters (will use 0x0 if not provided):")
+		fmt.Println("   STARKNET_PERMIT2_ADDRESS: Permit2 contract address")
+		fmt.Println("   STARKNET_MAILBOX_ADDRESS: Mailbox contract address")
+		fmt.Println("   STARKNET_HOOK_ADDRESS: Hook contract address")
+		fmt.Println("   STARKNET_ISM_ADDRESS: ISM contract address")
+		return errors.New("missing deployer environment variables")
+	}
+
+	if permit2Addr == "" || mailboxAddr == "" || hookAddr == "" || ismAddr == "" {
+		fmt.Println("❌ Missing required environment variables:")
+		fmt.Println("   STARKNET_PERMIT2_ADDRESS: Permit2 contract address")
+		fmt.Println("   STARKNET_MAILBOX_ADDRESS: Mailbox contract address")
+		fmt.Println("   STARKNET_HOOK_ADDRESS: Hook contract address")
+		fmt.Println("   STARKNET_ISM_ADDRESS: ISM contract address")
+		return errors.New("missing constructor environment variables")
+	}
+
+	// Get class hash from declaration file or environment variable
+	classHash, err := getClassHash()
+	if err != nil {
+		return fmt.Errorf("failed to get class hash: %w", err)
+	}
+
+	fmt.Printf("📋 Network: %s\n", networkName)
+	fmt.Printf("📋 RPC URL: %s\n", networkConfig.RPCURL)
+	fmt.Printf("📋 Chain ID: %s\n", networkConfig.ChainIDLabel())
+	fmt.Printf("📋 Deployer: %s\n", deployerAddress)
+	fmt.Printf("📋 Contract Class Hash: %s\n", classHash)
+
+	// Convert account address to felt
+	accountAddressFelt, err := utils.HexToFelt(deployerAddress)
+	if err != nil {
+		return fmt.Errorf("invalid account address: %w", err)
+	}
+
+	// Convert class hash to felt
+	classHashFelt, err := utils.HexToFelt(classHash)
+	if err != nil {
+		return fmt.Errorf("invalid class hash: %w", err)
+	}
+
+	// Build constructor calldata
+	constructorCalldata, err := buildConstructorCalldata(permit2Addr, mailboxAddr, deployerAddress, hookAddr, ismAddr)
+	if err != nil {
+		return err
+	}
+
+	var predictedAddress *felt.Felt
+	if opts.Salt != nil {
+		predictedAddress = starknetutil.UDCAddress(classHashFelt, opts.Salt, constructorCalldata, accountAddressFelt)
+		fmt.Printf("📋 Salt: %s\n", opts.Salt)
+		fmt.Printf("🔮 Predicted address: %s\n", predictedAddress)
+	}
+	if opts.PredictOnly {
+		return nil
+	}
+
+	// Initialize connection to RPC provider
+	client, err := rpc.NewProvider(networkConfig.RPCURL)
+	if err != nil {
+		return fmt.Errorf("error connecting to RPC provider: %w", err)
+	}
+	if err := config.VerifyStarknetChainID(ctx, client, networkConfig); err != nil {
+		return err
+	}
+
+	// Initialize the account memkeyStore
+	ks := account.NewMemKeystore()
+	privKeyBI, ok := new(big.Int).SetString(deployerPrivateKey, 0)
+	if !ok {
+		return errors.New("failed to convert private key to big.Int")
+	}
+	ks.Put(deployerPublicKey, privKeyBI)
+
+	fmt.Println("✅ Connected to Starknet RPC")
+
+	accnt, err := starknetutil.NewDeployerAccount(ctx, client, networkName, accountAddressFelt, deployerPublicKey, ks)
+	if err != nil {
+		return err
+	}
+
+	// With a fixed salt, a Hyperlane7683 already live at the predicted address only needs its state refreshed
+	if predictedAddress != nil {
+		deployed, err := starknetutil.DeployedAt(ctx, accnt.Provider, predictedAddress, classHashFelt, starknetutil.Hyperlane7683ProbeView)
+		if err != nil {
+			return fmt.Errorf("cannot deploy to %s: %w", predictedAddress, err)
+		}
+		if deployed {
+			fmt.Printf("⏭️  Hyperlane7683 is already deployed at %s, skipping the deploy\n", predictedAddress)
+			saveDeploymentInfo(networkName, classHash, predictedAddress.String(), "", opts.Salt.String())
+			return writeEnv(opts.WriteEnv, predictedAddress)
+		}
+	}
+
+	fmt.Println("📤 Sending deployment transaction...")
+
+	// Deploy the contract with UDC using the modern approach
+	resp, salt, err := accnt.DeployContractWithUDC(ctx, classHashFelt, constructorCalldata, nil, starknetutil.UDCOptions(opts.Salt))
+	if err != nil {
+		return fmt.Errorf("failed to deploy contract: %w", err)
+	}
+
+	// Extract transaction hash from response
+	txHash := resp.Hash
+	fmt.Printf("⏳ Transaction sent! Hash: %s\n", txHash.String())
+	fmt.Println("⏳ Waiting for transaction confirmation...")
+
+	// Wait for transaction receipt
+	waitCtx := txcost.WithOperation(ctx, "deploy Hyperlane7683")
+	txReceipt, err := starknetutil.WaitForFinality(waitCtx, accnt.Provider, txHash, starknetutil.FinalityAcceptedOnL2, time.Second, 0)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the deploy transaction: %w", err)
+	}
+
+	fmt.Printf("   Transaction Hash: %s\n", txHash.String())
+	fmt.Printf("   Execution Status: %s\n", txReceipt.ExecutionStatus)
+	fmt.Printf("   Finality Status: %s\n", txReceipt.FinalityStatus)
+
+	// Take the address the UDC reported, falling back to the precomputed one, and check Hyperlane7683 is live there
+	// before reporting it
+	precomputed := utils.PrecomputeAddressForUDC(classHashFelt, salt, constructorCalldata, utils.UDCCairoV0, accnt.Address)
+	deployedAddress, ok := starknetutil.UDCDeployedAddress(&txReceipt.TransactionReceipt, classHashFelt)
+	switch {
+	case !ok:
+		fmt.Printf("⚠️  No UDC deployment event in the receipt, using the precomputed address %s\n", precomputed)
+		deployedAddress = precomputed
+	case !deployedAddress.Equal(precomputed):
+		fmt.Printf("⚠️  The UDC deployed to %s, not the precomputed %s; using the UDC's\n", deployedAddress, precomputed)
+	}
+	if err := starknetutil.VerifyDeployTransaction(ctx, accnt.Provider, txHash, deployedAddress, classHashFelt, starknetutil.Hyperlane7683ProbeView); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Deployment completed!\n")
+	fmt.Printf("🏗️  Contract deployed at: %s\n", deployedAddress)
+
+	// Save deployment info
+	saveDeploymentInfo(networkName, classHash, deployedAddress.String(), txHash.String(), salt.String())
+	return writeEnv(opts.WriteEnv, deployedAddress)
+}
+
+// writeEnv saves the Hyperlane7683 address to .env when asked to, and otherwise says how to
+func writeEnv(write bool, deployedAddress *felt.Felt) error {
+	// Contract addresses are read from .env; only write it back when asked to
+	if write {
+		if err := envutil.UpdateEnvFile(solverdir.EnvFile(), map[string]string{"STARKNET_HYPERLANE_ADDRESS": deployedAddress.String()}); err != nil {
+			return fmt.Errorf("failed to update .env: %w", err)
+		}
+		fmt.Println("📝 STARKNET_HYPERLANE_ADDRESS updated in .env")
+	} else {
+		fmt.Printf("📝 Set STARKNET_HYPERLANE_ADDRESS=%s in .env (or rerun with %s)\n", deployedAddress, envutil.WriteEnvFlag)
+	}
+	return nil
+}
+
+// getClassHash retrieves the class hash from declaration file or environment variable
+func getClassHash() (string, error) {
+	// First try to get from environment variable
+	if envClassHash := os.Getenv("HYPERLANE7683_CLASS_HASH"); envClassHash != "" {
+		fmt.Printf("📋 Using class hash from environment variable: %s\n", envClassHash)
+		return envClassHash, nil
+	}
+
+	// Try to read from declaration file in deployment directory
+	declarationFile := deploystate.Path(declaresnhyperlane7683.DeclarationFile)
+
+	// Read and parse declaration file
+	declaration, err := deploystate.ReadDeclaration(declarationFile)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("📋 Using class hash from declaration file %s: %s\n", declarationFile, declaration.ClassHash)
+	return declaration.ClassHash, nil
+}
+
+// saveDeploymentInfo records the Hyperlane7683 deployment in the deployment state. An empty txHash keeps the
+// transaction recorded for the same address, for a deploy that was skipped
+func saveDeploymentInfo(networkName, classHash, deployedAddress, txHash, salt string) {
+	dir := deploystate.Dir()
+	err := deploystate.UpdateState(dir, func(state *deploystate.State) error {
+		network := state.EnsureNetwork(networkName)
+		if previous := network.HyperlaneDeploy; txHash == "" && previous != nil && network.HyperlaneAddress == deployedAddress {
+			txHash = previous.TransactionHash
+		}
+		network.SetHyperlane(deployedAddress, &deploystate.ContractDeploy{
+			ClassHash:       classHash,
+			TransactionHash: txHash,
+			Salt:            salt,
+			DeploymentTime:  time.Now().Format(time.RFC3339),
+		})
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Failed to save deployment info: %s\n", err)
+		return
+	}
+
+	fmt.Printf("💾 Deployment info saved to %s\n", deploystate.StatePath(dir))
+}
+
+// buildConstructorCalldata builds the constructor calldata for Hyperlane7683
+func buildConstructorCalldata(permit2Addr, mailboxAddr, ownerAddr, hookAddr, ismAddr string) ([]*felt.Felt, error) {
+	// Constructor parameters in order: permit2, mailbox, owner, hook, ism; 0x0 if not provided
+	calldata := make([]*felt.Felt, 0, 5)
+	for _, hexAddr := range []string{permit2Addr, mailboxAddr, ownerAddr, hookAddr, ismAddr} {
+		if hexAddr == "" {
+			zero := felt.Zero
+			calldata = append(calldata, &zero)
+			continue
+		}
+		f, err := utils.HexToFelt(hexAddr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s: %w", hexAddr, err)
+		}
+		calldata = append(calldata, f)
+	}
+	return calldata, nil
+}
@@ -0,0 +1,324 @@
+package deploysnmockerc20
+
+// Deploy tool: deploys the MockERC20 tokens of the token spec on Starknet from the class declared by
+// declare-sn-mock-erc20, mints their initial supply and records them in the deployment state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	declaresnmockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/declare-sn-mock-erc20"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// Run deploys the token spec's MockERC20 tokens as described by args: [--write-env] [--salt <felt>] [--predict-only]
+func Run(ctx context.Context, args []string) error {
+	if err := solverdir.LoadEnv(); err != nil {
+		fmt.Printf("⚠️  %v, using environment variables\n", err)
+	}
+
+	// Initialize networks from centralized config after .env is loaded
+	config.InitializeNetworks()
+
+	fmt.Println("🚀 Deploying MockERC20 tokens to Starknet...")
+
+	opts, err := starknetutil.ParseDeployArgs(args)
+	if err != nil {
+		return err
+	}
+
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return fmt.Errorf("failed to load token specs: %w", err)
+	}
+
+	// Load environment variables
+	networkName := "Starknet"
+
+	// Get network configuration
+	networkConfig, err := config.GetNetworkConfig(networkName)
+	if err != nil {
+		return fmt.Errorf("failed to get network config for %s: %w", networkName, err)
+	}
+
+	// Load Starknet account details from .env
+	deployerAddress := os.Getenv("STARKNET_DEPLOYER_ADDRESS")
+	deployerPrivateKey := os.Getenv("STARKNET_DEPLOYER_PRIVATE_KEY")
+	deployerPublicKey := os.Getenv("STARKNET_DEPLOYER_PUBLIC_KEY")
+
+	if deployerAddress == "" || deployerPrivateKey == "" || deployerPublicKey == "" {
+		fmt.Println("❌ Missing required environment variables:")
+		fmt.Println("   STARKNET_DEPLOYER_ADDRESS: Your Starknet account address")
+		fmt.Println("   STARKNET_DEPLOYER_PRIVATE_KEY: Your private key")
+		fmt.Println("   STARKNET_DEPLOYER_PUBLIC_KEY: Your public key")
+		return errors.New("missing deployer environment variables")
+	}
+
+	fmt.Printf("📋 Network: %s\n", networkName)
+	fmt.Printf("📋 RPC URL: %s\n", networkConfig.RPCURL)
+	fmt.Printf("📋 Chain ID: %s\n", networkConfig.ChainIDLabel())
+	fmt.Printf("📋 Deployer: %s\n", deployerAddress)
+
+	// Convert account address to felt
+	accountAddressFelt, err := utils.HexToFelt(deployerAddress)
+	if err != nil {
+		return fmt.Errorf("invalid account address: %w", err)
+	}
+
+	// Get class hash from declaration file or environment variable
+	classHash, err := getClassHash()
+	if err != nil {
+		return fmt.Errorf("failed to get class hash: %w", err)
+	}
+
+	// Convert class hash to felt
+	classHashFelt, err := utils.HexToFelt(classHash)
+	if err != nil {
+		return fmt.Errorf("invalid class hash: %w", err)
+	}
+
+	if opts.Salt != nil {
+		fmt.Printf("📋 Salt: %s\n", opts.Salt)
+	}
+	if opts.PredictOnly {
+		fmt.Println("\n🔮 Predicted addresses (nothing is sent):")
+		for _, spec := range specs {
+			calldata, err := constructorCalldata(spec.Name, spec.Symbol)
+			if err != nil {
+				return fmt.Errorf("%s: %w", spec.Name, err)
+			}
+			address := starknetutil.UDCAddress(classHashFelt, opts.Salt, calldata, accountAddressFelt)
+			fmt.Printf("   • %s: %s\n", spec.Name, address)
+		}
+		return nil
+	}
+
+	// Initialize connection to RPC provider
+	client, err := rpc.NewProvider(networkConfig.RPCURL)
+	if err != nil {
+		return fmt.Errorf("error connecting to RPC provider: %w", err)
+	}
+	if err := config.VerifyStarknetChainID(ctx, client, networkConfig); err != nil {
+		return err
+	}
+
+	// Initialize the account memkeyStore
+	ks := account.NewMemKeystore()
+	privKeyBI, ok := new(big.Int).SetString(deployerPrivateKey, 0)
+	if !ok {
+		return errors.New("failed to convert private key to big.Int")
+	}
+	ks.Put(deployerPublicKey, privKeyBI)
+
+	fmt.Println("✅ Connected to Starknet RPC")
+
+	accnt, err := starknetutil.NewDeployerAccount(ctx, client, networkName, accountAddressFelt, deployerPublicKey, ks)
+	if err != nil {
+		return err
+	}
+
+	tokens := make([]tokenspec.DeployedToken, 0, len(specs))
+	envUpdates := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		fmt.Printf("\n🪙 Deploying %s...\n", spec.Name)
+		token, err := deployToken(ctx, accnt, classHashFelt, spec, opts.Salt)
+		if err != nil {
+			// Keep what was deployed so far so a rerun only has to redo the rest
+			recordDeployment(networkName, tokens)
+			return fmt.Errorf("failed to deploy %s: %w", spec.Name, err)
+		}
+		token.ClassHash = classHash
+		fmt.Printf("✅ %s at: %s\n", spec.Name, token.Address)
+		tokens = append(tokens, token)
+		envUpdates[tokenspec.EnvName(networkName, spec.Name)] = token.Address
+	}
+	recordDeployment(networkName, tokens)
+
+	// Token addresses are read from .env; only write it back when asked to
+	if opts.WriteEnv {
+		if err := envutil.UpdateEnvFile(solverdir.EnvFile(), envUpdates); err != nil {
+			return fmt.Errorf("failed to update .env: %w", err)
+		}
+		fmt.Println("📝 Token addresses updated in .env")
+	}
+
+	fmt.Printf("\n🎯 MockERC20 tokens deployed successfully!\n")
+	for _, token := range tokens {
+		fmt.Printf("   • %s: %s\n", token.Name, token.Address)
+	}
+	fmt.Printf("   • Ready for funding and approval setup!\n")
+	return nil
+}
+
+// deployToken deploys the token described by spec and mints its initial supply to the deployer. With a fixed
+// salt, a token already live at its predicted address is kept as is
+func deployToken(ctx context.Context, accnt *account.Account, classHashFelt *felt.Felt, spec tokenspec.TokenSpec, salt *felt.Felt) (tokenspec.DeployedToken, error) {
+	calldata, err := constructorCalldata(spec.Name, spec.Symbol)
+	if err != nil {
+		return tokenspec.DeployedToken{}, err
+	}
+
+	deployed := false
+	var address string
+	if salt != nil {
+		predicted := starknetutil.UDCAddress(classHashFelt, salt, calldata, accnt.Address)
+		deployed, err = starknetutil.DeployedAt(ctx, accnt.Provider, predicted, classHashFelt, starknetutil.ERC20ProbeView)
+		if err != nil {
+			return tokenspec.DeployedToken{}, fmt.Errorf("cannot deploy to %s: %w", predicted, err)
+		}
+		if deployed {
+			fmt.Printf("   ⏭️  Already deployed at %s, skipping\n", predicted)
+			address = predicted.String()
+		}
+	}
+	if !deployed {
+		address, err = deployMockERC20(ctx, accnt, classHashFelt, spec.Name, spec.Symbol, calldata, salt)
+		if err != nil {
+			return tokenspec.DeployedToken{}, err
+		}
+	}
+	token := tokenspec.DeployedToken{Name: spec.Name, Symbol: spec.Symbol, Decimals: spec.Decimals, Address: address}
+
+	// The Cairo MockERC20 fixes its decimals, so record what the contract reports rather than the spec
+	decimals, err := starknetutil.ERC20Decimals(ctx, accnt.Provider, address)
+	if err != nil {
+		return tokenspec.DeployedToken{}, fmt.Errorf("failed to read decimals: %w", err)
+	}
+	if decimals != spec.Decimals {
+		fmt.Printf("   ⚠️  %s has %d decimals on Starknet, not the %d in the token spec\n", spec.Name, decimals, spec.Decimals)
+	}
+	token.Decimals = decimals
+
+	// An existing token got its initial supply when it was deployed
+	if supply := spec.Supply(); supply.Sign() > 0 && !deployed {
+		fmt.Printf("   🪙 Minting the initial supply of %s to the deployer...\n", starknetutil.FormatTokenAmount(supply, int(decimals)))
+		txHash, err := starknetutil.Mint(ctx, accnt, address, accnt.Address.String(), supply)
+		if err != nil {
+			return tokenspec.DeployedToken{}, fmt.Errorf("failed to mint the initial supply: %w", err)
+		}
+		waitCtx := txcost.WithOperation(ctx, "mint "+spec.Symbol)
+		if _, err := starknetutil.WaitForFinality(waitCtx, accnt.Provider, txHash, starknetutil.FinalityAcceptedOnL2, time.Second, 0); err != nil {
+			return tokenspec.DeployedToken{}, fmt.Errorf("failed to wait for the mint receipt: %w", err)
+		}
+	}
+	return token, nil
+}
+
+// constructorCalldata builds the MockERC20 constructor calldata: [name_bytes..., symbol_bytes...]
+func constructorCalldata(tokenName, tokenSymbol string) ([]*felt.Felt, error) {
+	// Convert name and symbol to felt arrays (Cairo strings)
+	nameFelt, err := utils.StringToByteArrFelt(tokenName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert name to felt: %w", err)
+	}
+
+	symbolFelt, err := utils.StringToByteArrFelt(tokenSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert symbol to felt: %w", err)
+	}
+
+	calldata := make([]*felt.Felt, 0, len(nameFelt)+len(symbolFelt))
+	calldata = append(calldata, nameFelt...)
+	calldata = append(calldata, symbolFelt...)
+	return calldata, nil
+}
+
+// deployMockERC20 deploys a single mock ERC20 token, with a random salt when salt is nil
+func deployMockERC20(ctx context.Context, accnt *account.Account, classHashFelt *felt.Felt, tokenName, tokenSymbol string, constructorCalldata []*felt.Felt, salt *felt.Felt) (string, error) {
+	fmt.Printf("   📝 Deploying %s (%s)...\n", tokenName, tokenSymbol)
+
+	fmt.Printf("   📋 Constructor calldata: name='%s', symbol='%s'\n", tokenName, tokenSymbol)
+
+	fmt.Printf("   📤 Sending deployment transaction...\n")
+
+	// Deploy the contract with UDC using the modern approach
+	resp, salt, err := accnt.DeployContractWithUDC(ctx, classHashFelt, constructorCalldata, nil, starknetutil.UDCOptions(salt))
+	if err != nil {
+		return "", fmt.Errorf("failed to deploy contract: %w", err)
+	}
+
+	// Extract transaction hash from response
+	txHash := resp.Hash
+	fmt.Printf("   ⏳ Transaction sent! Hash: %s\n", txHash.String())
+	fmt.Printf("   ⏳ Waiting for transaction confirmation...\n")
+
+	// Wait for transaction receipt
+	waitCtx := txcost.WithOperation(ctx, "deploy "+tokenSymbol)
+	txReceipt, err := starknetutil.WaitForFinality(waitCtx, accnt.Provider, txHash, starknetutil.FinalityAcceptedOnL2, time.Second, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for the deploy transaction: %w", err)
+	}
+
+	fmt.Printf("   📋 Transaction Hash: %s\n", txHash.String())
+	fmt.Printf("   📋 Execution Status: %s\n", txReceipt.ExecutionStatus)
+	fmt.Printf("   📋 Finality Status: %s\n", txReceipt.FinalityStatus)
+
+	// Take the address the UDC reported, falling back to the precomputed one, and check the token is live there
+	// before reporting it
+	precomputed := utils.PrecomputeAddressForUDC(classHashFelt, salt, constructorCalldata, utils.UDCCairoV0, accnt.Address)
+	deployedAddress, ok := starknetutil.UDCDeployedAddress(&txReceipt.TransactionReceipt, classHashFelt)
+	switch {
+	case !ok:
+		fmt.Printf("   ⚠️  No UDC deployment event in the receipt, using the precomputed address %s\n", precomputed)
+		deployedAddress = precomputed
+	case !deployedAddress.Equal(precomputed):
+		fmt.Printf("   ⚠️  The UDC deployed to %s, not the precomputed %s; using the UDC's\n", deployedAddress, precomputed)
+	}
+	if err := starknetutil.VerifyDeployTransaction(ctx, accnt.Provider, txHash, deployedAddress, classHashFelt, starknetutil.ERC20ProbeView); err != nil {
+		return "", err
+	}
+	fmt.Printf("   ✅ Deployment completed!\n")
+	fmt.Printf("   🏗️  Contract deployed at: %s\n", deployedAddress.String())
+
+	return deployedAddress.String(), nil
+}
+
+// getClassHash retrieves the class hash from declaration file or environment variable
+func getClassHash() (string, error) {
+	// First try to get from environment variable
+	if envClassHash := os.Getenv("MOCK_ERC20_CLASS_HASH"); envClassHash != "" {
+		fmt.Printf("📋 Using class hash from environment variable: %s\n", envClassHash)
+		return envClassHash, nil
+	}
+
+	// Try to read from declaration file in deployment directory
+	declarationFile := deploystate.Path(declaresnmockerc20.DeclarationFile)
+
+	// Read and parse declaration file
+	declaration, err := deploystate.ReadDeclaration(declarationFile)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("📋 Using class hash from declaration file %s: %s\n", declarationFile, declaration.ClassHash)
+	return declaration.ClassHash, nil
+}
+
+// recordDeployment saves the deployed tokens to the network's deployment state
+func recordDeployment(networkName string, tokens []tokenspec.DeployedToken) {
+	if len(tokens) == 0 {
+		return
+	}
+	filename, err := tokenspec.RecordDeployment(networkName, tokens)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to save deployment info: %s\n", err)
+		return
+	}
+
+	fmt.Printf("💾 Deployment info saved to %s\n", filename)
+}
@@ -0,0 +1,81 @@
+package registerevmrouters
+
+// Send mode
+// On an anvil fork the owner is impersonated and the calls go out through eth_sendTransaction; on a live
+// network they are signed with EVM_HYPERLANE_OWNER_PRIVATE_KEY. --live/--fork pick the mode explicitly,
+// otherwise FORKING=true/false does, otherwise the tool probes anvil_impersonateAccount and signs when the
+// node does not support it
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// sendMode is how the owner's transactions are sent
+type sendMode int
+
+const (
+	modeAuto sendMode = iota
+	modeImpersonate
+	modeSign
+)
+
+func (m sendMode) String() string {
+	switch m {
+	case modeImpersonate:
+		return "impersonate"
+	case modeSign:
+		return "sign"
+	default:
+		return "auto"
+	}
+}
+
+// parseModeFlags reads --live / --fork from args
+func parseModeFlags(args []string) (sendMode, error) {
+	mode := modeAuto
+	for _, arg := range args {
+		var m sendMode
+		switch arg {
+		case "--live":
+			m = modeSign
+		case "--fork":
+			m = modeImpersonate
+		default:
+			return modeAuto, fmt.Errorf("unexpected argument: %s", arg)
+		}
+		if mode != modeAuto && mode != m {
+			return modeAuto, fmt.Errorf("--live and --fork are mutually exclusive")
+		}
+		mode = m
+	}
+	return mode, nil
+}
+
+// forkingMode maps FORKING to a mode; modeAuto when it is unset
+func forkingMode() (sendMode, error) {
+	value := os.Getenv("FORKING")
+	if value == "" {
+		return modeAuto, nil
+	}
+	forking, err := strconv.ParseBool(value)
+	if err != nil {
+		return modeAuto, fmt.Errorf("invalid FORKING=%q: %w", value, err)
+	}
+	if forking {
+		return modeImpersonate, nil
+	}
+	return modeSign, nil
+}
+
+// resolveMode settles modeAuto on one network: impersonate when the probe succeeds, sign otherwise
+func resolveMode(requested sendMode, canImpersonate func() bool) sendMode {
+	if requested != modeAuto {
+		return requested
+	}
+	if canImpersonate() {
+		return modeImpersonate
+	}
+	return modeSign
+}
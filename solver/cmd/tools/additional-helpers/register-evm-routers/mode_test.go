@@ -0,0 +1,87 @@
+package registerevmrouters
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModeFlags(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    sendMode
+		wantErr string
+	}{
+		{args: nil, want: modeAuto},
+		{args: []string{"--live"}, want: modeSign},
+		{args: []string{"--fork"}, want: modeImpersonate},
+		{args: []string{"--fork", "--fork"}, want: modeImpersonate},
+		{args: []string{"--live", "--fork"}, wantErr: "mutually exclusive"},
+		{args: []string{"--dry-run"}, wantErr: "unexpected argument"},
+	}
+	for _, tt := range tests {
+		got, err := parseModeFlags(tt.args)
+		if tt.wantErr != "" {
+			assert.ErrorContains(t, err, tt.wantErr, tt.args)
+			continue
+		}
+		require.NoError(t, err, tt.args)
+		assert.Equal(t, tt.want, got, tt.args)
+	}
+}
+
+func TestForkingMode(t *testing.T) {
+	t.Setenv("FORKING", "")
+	mode, err := forkingMode()
+	require.NoError(t, err)
+	assert.Equal(t, modeAuto, mode)
+
+	t.Setenv("FORKING", "true")
+	mode, err = forkingMode()
+	require.NoError(t, err)
+	assert.Equal(t, modeImpersonate, mode)
+
+	t.Setenv("FORKING", "false")
+	mode, err = forkingMode()
+	require.NoError(t, err)
+	assert.Equal(t, modeSign, mode)
+
+	t.Setenv("FORKING", "maybe")
+	_, err = forkingMode()
+	assert.Error(t, err)
+}
+
+func TestResolveMode(t *testing.T) {
+	probed := false
+	probe := func(ok bool) func() bool {
+		return func() bool { probed = true; return ok }
+	}
+
+	assert.Equal(t, modeImpersonate, resolveMode(modeAuto, probe(true)))
+	assert.Equal(t, modeSign, resolveMode(modeAuto, probe(false)), "a node without anvil_impersonateAccount gets signed transactions")
+
+	probed = false
+	assert.Equal(t, modeSign, resolveMode(modeSign, probe(true)))
+	assert.Equal(t, modeImpersonate, resolveMode(modeImpersonate, probe(false)))
+	assert.False(t, probed, "an explicit mode does not probe")
+}
+
+func TestOwnerKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyHex := "0x" + common.Bytes2Hex(crypto.FromECDSA(key))
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+
+	got, err := ownerKey(keyHex, owner)
+	require.NoError(t, err)
+	assert.Equal(t, owner, crypto.PubkeyToAddress(got.PublicKey))
+
+	_, err = ownerKey("", owner)
+	assert.ErrorContains(t, err, "EVM_HYPERLANE_OWNER_PRIVATE_KEY is required")
+
+	_, err = ownerKey(keyHex, common.HexToAddress("0xd897155e982b96fe713a1546e3c89995a9436f82"))
+	assert.ErrorContains(t, err, "not the owner")
+}
@@ -0,0 +1,205 @@
+package registerevmrouters
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chain"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/rpcpool"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// Wait time for transaction receipt
+	receiptWaitMs = 500
+)
+
+// Destination gas set on every EVM Hyperlane7683, which verify-routers checks for
+// Previous: 0xfa00 = 64,000 wei (too low!)
+const (
+	EVMDestinationGas      = 0x186a0 // 100,000 wei (still conservative but much better)
+	StarknetDestinationGas = 0x3d090 // 250,000 wei for Starknet operations
+)
+
+// Tool to call enrollRemoteRouters and setDestinationGas as the owner on each EVM network: impersonating the
+// owner on anvil forks, signing with EVM_HYPERLANE_OWNER_PRIVATE_KEY on live networks (see mode.go)
+
+// Run registers the routers and destination gas on every EVM network as described by args: [--live | --fork]
+func Run(ctx context.Context, args []string) error {
+	if err := solverdir.LoadEnv(); err != nil {
+		log.Printf("⚠️  %v, using environment variables", err)
+	}
+
+	requested, err := parseModeFlags(args)
+	if err != nil {
+		return fmt.Errorf("%w (usage: register-evm-routers [--live | --fork])", err)
+	}
+	if requested == modeAuto {
+		if requested, err = forkingMode(); err != nil {
+			return err
+		}
+	}
+
+	ownerHex := os.Getenv("EVM_HYPERLANE_OWNER")
+	if ownerHex == "" {
+		return errors.New("EVM_HYPERLANE_OWNER env var (owner/admin of Hyperlane7683) is required")
+	}
+	owner := common.HexToAddress(ownerHex)
+
+	// Initialize networks from config after .env is loaded
+	config.InitializeNetworks()
+
+	pool := rpcpool.New(rpcpool.OptionsFromEnv())
+	defer pool.Close()
+
+	networkNames := config.GetNetworkNames()
+	sort.Strings(networkNames)
+	for _, networkName := range networkNames {
+		if config.IsStarknetNetwork(networkName) {
+			continue
+		}
+
+		netCfg := config.Networks[networkName]
+		fmt.Printf("\n🔧 Registering routers on %s (%s)\n", netCfg.Name, netCfg.RPCURL)
+
+		// Build arrays: destinations (domains) and routers (bytes32)
+		var destDomains []uint32
+		var routerBytes [][32]byte
+		var gasConfigs []contracts.GasRouterGasRouterConfig
+
+		for _, otherName := range networkNames {
+			otherCfg := config.Networks[otherName]
+			if otherName == networkName {
+				continue
+			}
+			if otherCfg.HyperlaneAddress == "" {
+				fmt.Printf("   ⏭️  Skipping %s: no Hyperlane address configured\n", otherName)
+				continue
+			}
+			dom, err := otherCfg.Domain()
+			if err != nil {
+				return err
+			}
+			destDomains = append(destDomains, dom)
+
+			other := chain.New(otherCfg)
+			router, err := other.AddressToBytes32(otherCfg.HyperlaneAddress)
+			if err != nil {
+				return fmt.Errorf("%s Hyperlane address %w", otherName, err)
+			}
+			routerBytes = append(routerBytes, router)
+
+			// Gas configs: much higher gas for cross-chain operations. Starknet-type domains need more
+			// due to complex operations
+			gas := big.NewInt(EVMDestinationGas)
+			if other.Type().IsCairo() {
+				gas = big.NewInt(StarknetDestinationGas)
+			}
+			fmt.Printf("   🔗 %s domain %d -> router %s (0x%s)\n", otherName, dom, otherCfg.HyperlaneAddress, hex.EncodeToString(router[:]))
+			gasConfigs = append(gasConfigs, contracts.GasRouterGasRouterConfig{Domain: dom, Gas: gas})
+			fmt.Printf("   ⚡ Domain %d: gas = %s wei (0x%s)\n", dom, gas.String(), gas.Text(16))
+		}
+
+		fmt.Printf("   📊 Total destinations: %d, Total routers: %d\n", len(destDomains), len(routerBytes))
+
+		if err := registerOnNetwork(txcost.WithNetwork(ctx, networkName), pool, netCfg, owner, requested, destDomains, routerBytes, gasConfigs); err != nil {
+			return fmt.Errorf("%s: %w", networkName, err)
+		}
+		fmt.Printf("   ✅ Routers/gas registered on %s\n", networkName)
+		if err := deploystate.RecordRouterEnrollment(networkName, netCfg.HyperlaneAddress); err != nil {
+			fmt.Printf("   ⚠️  Failed to record the router enrollment: %s\n", err)
+		}
+	}
+
+	fmt.Printf("\n✅ EVM router registration complete\n")
+	return nil
+}
+
+// registerOnNetwork picks the send mode for one network and sends enrollRemoteRouters and setDestinationGas
+func registerOnNetwork(ctx context.Context, pool *rpcpool.Pool, netCfg config.NetworkConfig, owner common.Address, requested sendMode,
+	destDomains []uint32, routerBytes [][32]byte, gasConfigs []contracts.GasRouterGasRouterConfig) error {
+	client, err := pool.EVMFailover(ctx, netCfg.Name, netCfg.RPCURLs)
+	if err != nil {
+		return fmt.Errorf("failed to dial RPC %s: %w", netCfg.RPCURL, err)
+	}
+	rpcClient := client.Client()
+
+	hlAddr := common.HexToAddress(netCfg.HyperlaneAddress)
+	var dummy any
+	mode := resolveMode(requested, func() bool {
+		// Impersonate owner (anvil returns null on success)
+		return rpcClient.Call(&dummy, "anvil_impersonateAccount", owner.Hex()) == nil
+	})
+	fmt.Printf("   🔀 Send mode: %s\n", mode)
+
+	var sender routerSender
+	switch mode {
+	case modeImpersonate:
+		if requested == modeImpersonate {
+			if err := rpcClient.Call(&dummy, "anvil_impersonateAccount", owner.Hex()); err != nil {
+				return fmt.Errorf("failed to impersonate %s: %w", owner.Hex(), err)
+			}
+		}
+		defer func() { _ = rpcClient.Call(&dummy, "anvil_stopImpersonatingAccount", owner.Hex()) }()
+		fmt.Printf("   👤 Impersonating owner %s\n", owner.Hex())
+
+		// Ensure owner has large ETH balance and verify
+		// Set ~1e27 wei (~1e9 ETH)
+		rich := "0x33B2E3C9FD0803CE8000000"
+		if err := rpcClient.Call(&dummy, "anvil_setBalance", owner.Hex(), rich); err != nil {
+			return fmt.Errorf("failed to set balance for %s: %w", owner.Hex(), err)
+		}
+		if !hasBalance(rpcClient, owner) {
+			// Retry once
+			_ = rpcClient.Call(&dummy, "anvil_setBalance", owner.Hex(), rich)
+			if !hasBalance(rpcClient, owner) {
+				return fmt.Errorf("owner %s still unfunded", owner.Hex())
+			}
+		}
+		if sender, err = newImpersonatingSender(ctx, rpcClient, owner, hlAddr); err != nil {
+			return err
+		}
+	default:
+		key, err := ownerKey(os.Getenv("EVM_HYPERLANE_OWNER_PRIVATE_KEY"), owner)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("   ✍️  Signing as owner %s\n", owner.Hex())
+		if sender, err = newSigningSender(ctx, client, key, hlAddr); err != nil {
+			return err
+		}
+	}
+
+	if err := sender.EnrollRemoteRouters(destDomains, routerBytes); err != nil {
+		return fmt.Errorf("enrollRemoteRouters failed: %w", err)
+	}
+	if err := sender.SetDestinationGas(gasConfigs); err != nil {
+		return fmt.Errorf("setDestinationGas failed: %w", err)
+	}
+	return nil
+}
+
+func hasBalance(c *rpc.Client, addr common.Address) bool {
+	var balHex string
+	if err := c.Call(&balHex, "eth_getBalance", addr.Hex(), "latest"); err != nil {
+		return false
+	}
+	if len(balHex) <= 2 {
+		return false
+	}
+	// consider any non-zero balance sufficient
+	return balHex != "0x0" && balHex != "0x"
+}
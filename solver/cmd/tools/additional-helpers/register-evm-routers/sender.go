@@ -0,0 +1,166 @@
+package registerevmrouters
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// routerSender sends the two owner-only calls to one network's Hyperlane7683
+type routerSender interface {
+	EnrollRemoteRouters(domains []uint32, routers [][32]byte) error
+	SetDestinationGas(configs []contracts.GasRouterGasRouterConfig) error
+}
+
+// impersonatingSender sends unsigned transactions from the impersonated owner on an anvil fork
+type impersonatingSender struct {
+	ctx       context.Context
+	client    *rpc.Client
+	abi       *abi.ABI
+	owner     common.Address
+	hyperlane common.Address
+}
+
+func newImpersonatingSender(ctx context.Context, client *rpc.Client, owner, hyperlane common.Address) (*impersonatingSender, error) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Hyperlane7683 ABI: %w", err)
+	}
+	return &impersonatingSender{ctx: ctx, client: client, abi: parsed, owner: owner, hyperlane: hyperlane}, nil
+}
+
+func (s *impersonatingSender) EnrollRemoteRouters(domains []uint32, routers [][32]byte) error {
+	data, err := s.abi.Pack("enrollRemoteRouters", domains, routers)
+	if err != nil {
+		return fmt.Errorf("pack enrollRemoteRouters failed: %w", err)
+	}
+	return sendImpersonatedTx(s.ctx, s.client, s.owner, s.hyperlane, data)
+}
+
+func (s *impersonatingSender) SetDestinationGas(configs []contracts.GasRouterGasRouterConfig) error {
+	// The batch overload of setDestinationGas is registered as setDestinationGas0 in the parsed ABI
+	data, err := s.abi.Pack("setDestinationGas0", configs)
+	if err != nil {
+		return fmt.Errorf("pack setDestinationGas failed: %w", err)
+	}
+	return sendImpersonatedTx(s.ctx, s.client, s.owner, s.hyperlane, data)
+}
+
+// signingSender signs with the owner key through the generated bindings. bind estimates the gas limit of each
+// call, so a call that would revert fails before anything is broadcast
+type signingSender struct {
+	client     *ethclient.Client
+	transactor *contracts.Hyperlane7683Transactor
+	auth       *bind.TransactOpts
+}
+
+func newSigningSender(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, hyperlane common.Address) (*signingSender, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	auth, err := ethutil.NewTransactor(chainID, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transactor: %w", err)
+	}
+	auth.Context = ctx
+	transactor, err := contracts.NewHyperlane7683Transactor(hyperlane, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind Hyperlane7683: %w", err)
+	}
+	return &signingSender{client: client, transactor: transactor, auth: auth}, nil
+}
+
+func (s *signingSender) EnrollRemoteRouters(domains []uint32, routers [][32]byte) error {
+	tx, err := s.transactor.EnrollRemoteRouters(s.auth, domains, routers)
+	return s.wait("enrollRemoteRouters", tx, err)
+}
+
+func (s *signingSender) SetDestinationGas(configs []contracts.GasRouterGasRouterConfig) error {
+	tx, err := s.transactor.SetDestinationGas0(s.auth, configs)
+	return s.wait("setDestinationGas", tx, err)
+}
+
+// wait waits for tx to be mined and checks its status
+func (s *signingSender) wait(name string, tx *gethtypes.Transaction, sendErr error) error {
+	if sendErr != nil {
+		if revert := ethutil.DecodeRevertError(sendErr); revert != nil {
+			return fmt.Errorf("%s would revert: %w", name, revert)
+		}
+		return fmt.Errorf("%s failed: %w", name, sendErr)
+	}
+	fmt.Printf("   ⛽ %s tx sent: %s (gas limit %d)\n", name, tx.Hash().Hex(), tx.Gas())
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(s.auth.Context, name), s.client, tx)
+	if err != nil {
+		return fmt.Errorf("%s: failed waiting for %s: %w", name, tx.Hash().Hex(), err)
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("%s reverted (tx %s)", name, tx.Hash().Hex())
+	}
+	fmt.Printf("   ⛽ Tx mined: %s\n", tx.Hash().Hex())
+	return nil
+}
+
+// ownerKey loads EVM_HYPERLANE_OWNER_PRIVATE_KEY and checks it belongs to the configured owner
+func ownerKey(keyHex string, owner common.Address) (*ecdsa.PrivateKey, error) {
+	if keyHex == "" {
+		return nil, fmt.Errorf("EVM_HYPERLANE_OWNER_PRIVATE_KEY is required to sign on a live network")
+	}
+	key, err := ethutil.ParsePrivateKey(keyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVM_HYPERLANE_OWNER_PRIVATE_KEY: %w", err)
+	}
+	if addr := crypto.PubkeyToAddress(key.PublicKey); addr != owner {
+		return nil, fmt.Errorf("EVM_HYPERLANE_OWNER_PRIVATE_KEY is for %s, not the owner %s", addr.Hex(), owner.Hex())
+	}
+	return key, nil
+}
+
+func sendImpersonatedTx(ctx context.Context, c *rpc.Client, from, to common.Address, data []byte) error {
+	params := map[string]interface{}{
+		"from": from.Hex(),
+		"to":   to.Hex(),
+		"data": "0x" + hex.EncodeToString(data),
+	}
+	var txHash common.Hash
+	if err := c.CallContext(ctx, &txHash, "eth_sendTransaction", params); err != nil {
+		return err
+	}
+	for i := 0; i < 60; i++ {
+		var raw json.RawMessage
+		if err := c.CallContext(ctx, &raw, "eth_getTransactionReceipt", txHash.Hex()); err == nil && len(raw) > 0 && string(raw) != "null" {
+			fmt.Printf("   ⛽ Tx mined: %s\n", txHash.Hex())
+			var rec map[string]any
+			if err := json.Unmarshal(raw, &rec); err == nil {
+				if status, ok := rec["status"].(string); ok {
+					if status == "0x1" || status == "0x01" {
+						return nil
+					}
+					return fmt.Errorf("transaction reverted (status=%s)", status)
+				}
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for the receipt of %s: %w", txHash.Hex(), ctx.Err())
+		case <-time.After(receiptWaitMs * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("timeout waiting receipt for %s", to.Hex())
+}
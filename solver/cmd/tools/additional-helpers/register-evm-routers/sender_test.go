@@ -0,0 +1,28 @@
+package registerevmrouters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+func TestImpersonatingSenderEncoding(t *testing.T) {
+	s, err := newImpersonatingSender(context.Background(), nil, common.Address{}, common.Address{})
+	require.NoError(t, err)
+
+	// The binding's batch overload must encode as setDestinationGas((uint32,uint256)[]), not the single-domain one
+	data, err := s.abi.Pack("setDestinationGas0", []contracts.GasRouterGasRouterConfig{{Domain: 23448591, Gas: big.NewInt(250000)}})
+	require.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256([]byte("setDestinationGas((uint32,uint256)[])"))[:4], data[:4])
+
+	data, err = s.abi.Pack("enrollRemoteRouters", []uint32{8453}, [][32]byte{{31: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256([]byte("enrollRemoteRouters(uint32[],bytes32[])"))[:4], data[:4])
+}
@@ -0,0 +1,314 @@
+package registersnrouters
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chain"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// Registers routers and sets destination gas configs on Starknet Hyperlane using owner account.
+//
+// Usage:
+//
+//	register-sn-routers                                          enroll every configured network
+//	register-sn-routers --domain <id> --router <0x..> [--gas N]  enroll one additional domain only
+//
+// Gas comes from each destination's config (EVM_DESTINATION_GAS / STARKNET_DESTINATION_GAS); a single
+// --domain uses the gas of the configured network with that domain, or EVM_DESTINATION_GAS for an unknown one
+
+// routerEntry is one destination to enroll
+type routerEntry struct {
+	name   string
+	domain uint32
+	router [32]byte
+	gas    *big.Int
+}
+
+// singleEnrollment is the --domain/--router request; nil when enrolling every network
+type singleEnrollment struct {
+	domain uint32
+	router [32]byte
+	gas    *big.Int // nil = from config
+}
+
+// Run enrolls the routers and sets the destination gas on Starknet as described by args:
+// [--domain <id> --router <0x..> [--gas N]]
+func Run(ctx context.Context, args []string) error {
+	if err := solverdir.LoadEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v, using environment variables\n", err)
+	}
+
+	// Initialize networks from centralized config after .env is loaded
+	config.InitializeNetworks()
+
+	single, err := parseArgs(args)
+	if err != nil {
+		return fmt.Errorf("%w (usage: register-sn-routers [--domain <id> --router <0x..> [--gas N]])", err)
+	}
+
+	networkName := "Starknet"
+	netCfg, err := config.GetNetworkConfig(networkName)
+	if err != nil {
+		return err
+	}
+
+	// Owner creds
+	env, err := requireEnv("STARKNET_DEPLOYER_ADDRESS", "STARKNET_DEPLOYER_PUBLIC_KEY", "STARKNET_DEPLOYER_PRIVATE_KEY")
+	if err != nil {
+		return err
+	}
+	ownerAddr, ownerPub, ownerPriv := env[0], env[1], env[2]
+
+	// Starknet provider/account
+	provider, err := rpc.NewProvider(netCfg.RPCURL)
+	if err != nil {
+		return err
+	}
+	ownerAddrF, err := utils.HexToFelt(ownerAddr)
+	if err != nil {
+		return fmt.Errorf("invalid STARKNET_DEPLOYER_ADDRESS: %w", err)
+	}
+	ks := account.NewMemKeystore()
+	privBI, ok := new(big.Int).SetString(ownerPriv, 0)
+	if !ok {
+		return errors.New("invalid STARKNET_DEPLOYER_PRIVATE_KEY")
+	}
+	ks.Put(ownerPub, privBI)
+	acct, err := starknetutil.NewAccount(ctx, provider, starknetutil.AccountVersionEnv(networkName, "Deployer"), ownerAddrF, ownerPub, ks)
+	if err != nil {
+		return err
+	}
+
+	// Load Starknet Hyperlane address from .env
+	if netCfg.HyperlaneAddress == "" {
+		return errors.New("STARKNET_HYPERLANE_ADDRESS not found in .env")
+	}
+	hlAddrF, err := utils.HexToFelt(netCfg.HyperlaneAddress)
+	if err != nil {
+		return fmt.Errorf("invalid STARKNET_HYPERLANE_ADDRESS: %w", err)
+	}
+
+	if single != nil {
+		entry := single.entry(config.Networks)
+		fmt.Printf("   🔗 domain %d -> router 0x%s, gas %s\n", entry.domain, hex.EncodeToString(entry.router[:]), entry.gas)
+		if err := send(ctx, acct, "enroll_remote_router", rpc.InvokeFunctionCall{
+			ContractAddress: hlAddrF,
+			FunctionName:    "enroll_remote_router",
+			CallData:        starknetutil.EnrollRemoteRouterCalldata(entry.domain, entry.router),
+		}); err != nil {
+			return err
+		}
+		if err := send(ctx, acct, "set_destination_gas", rpc.InvokeFunctionCall{
+			ContractAddress: hlAddrF,
+			FunctionName:    "set_destination_gas",
+			CallData:        starknetutil.SetSingleDestinationGasCalldata(entry.domain, entry.gas),
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("   ✅ Domain %d enrolled\n", entry.domain)
+		return nil
+	}
+
+	// Build arrays of ALL destinations and routers (including Starknet itself, which needs to know about
+	// itself as a destination)
+	entries, err := configuredEntries(config.Networks)
+	if err != nil {
+		return err
+	}
+	domains := make([]uint32, 0, len(entries))
+	routers := make([][32]byte, 0, len(entries))
+	gasConfigs := make([]starknetutil.GasRouterConfig, 0, len(entries))
+	for _, e := range entries {
+		fmt.Printf("   %s: domain %d -> router 0x%s, gas %s\n", e.name, e.domain, hex.EncodeToString(e.router[:]), e.gas)
+		domains = append(domains, e.domain)
+		routers = append(routers, e.router)
+		gasConfigs = append(gasConfigs, starknetutil.GasRouterConfig{Destination: e.domain, Gas: e.gas})
+	}
+
+	enrollCalldata, err := starknetutil.EnrollRemoteRoutersCalldata(domains, routers)
+	if err != nil {
+		return err
+	}
+	if err := send(ctx, acct, "enroll_remote_routers", rpc.InvokeFunctionCall{
+		ContractAddress: hlAddrF,
+		FunctionName:    "enroll_remote_routers",
+		CallData:        enrollCalldata,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("   ✅ Router enrollment confirmed\n")
+
+	// Set destination gas for all domains in a single batch call
+	fmt.Printf("   ⚡ Setting destination gas configs (batch mode)...\n")
+	if err := send(ctx, acct, "set_destination_gas", rpc.InvokeFunctionCall{
+		ContractAddress: hlAddrF,
+		FunctionName:    "set_destination_gas",
+		CallData:        starknetutil.SetDestinationGasCalldata(gasConfigs),
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("   ✅ All %d destination gas configs set successfully in single transaction\n", len(entries))
+	if err := deploystate.RecordRouterEnrollment(networkName, netCfg.HyperlaneAddress); err != nil {
+		fmt.Printf("⚠️  Failed to record the router enrollment: %s\n", err)
+	}
+	return nil
+}
+
+// send invokes call from acct and waits for its receipt. Enrolling routers and setting gas are idempotent, so
+// transient RPC failures are retried
+func send(ctx context.Context, acct *account.Account, name string, call rpc.InvokeFunctionCall) error {
+	tx, err := starknetutil.BuildAndSendInvokeTxnWithRetry(ctx, starknetutil.DefaultRetryPolicy, acct, []rpc.InvokeFunctionCall{call}, nil)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	fmt.Printf("   ⛽ %s tx: %s\n", name, tx.Hash.String())
+
+	if _, err := starknetutil.WaitForFinality(txcost.WithOperation(ctx, name), acct.Provider, tx.Hash, starknetutil.FinalityAcceptedOnL2, time.Second, 0); err != nil {
+		return fmt.Errorf("%s wait failed: %w", name, err)
+	}
+	return nil
+}
+
+// configuredEntries lists every network with a Hyperlane address, sorted by name
+func configuredEntries(networks map[string]config.NetworkConfig) ([]routerEntry, error) {
+	names := make([]string, 0, len(networks))
+	for name, cfg := range networks {
+		if cfg.HyperlaneAddress == "" {
+			fmt.Printf("   ⏭️  Skipping %s: no Hyperlane address configured\n", name)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]routerEntry, 0, len(names))
+	for _, name := range names {
+		cfg := networks[name]
+		router, err := routerWord(name, cfg.HyperlaneAddress)
+		if err != nil {
+			return nil, fmt.Errorf("%s Hyperlane address %w", name, err)
+		}
+		domain, err := cfg.Domain()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, routerEntry{
+			name:   name,
+			domain: domain,
+			router: router,
+			gas:    new(big.Int).SetUint64(cfg.DestinationGas),
+		})
+	}
+	return entries, nil
+}
+
+// routerWord encodes a network's Hyperlane address as a router word of that network (see chain.Network)
+func routerWord(networkName, address string) ([32]byte, error) {
+	return chain.Of(networkName).AddressToBytes32(address)
+}
+
+// entry resolves the single enrollment's gas from config when --gas was not given
+func (s *singleEnrollment) entry(networks map[string]config.NetworkConfig) routerEntry {
+	e := routerEntry{domain: s.domain, router: s.router, gas: s.gas}
+	if e.gas != nil {
+		return e
+	}
+	e.gas = new(big.Int).SetUint64(envutil.GetEnvUint64("EVM_DESTINATION_GAS", config.DefaultEVMDestinationGas))
+	for name, cfg := range networks {
+		if uint32(cfg.HyperlaneDomain) == s.domain {
+			e.name = name
+			e.gas.SetUint64(cfg.DestinationGas)
+		}
+	}
+	return e
+}
+
+// parseArgs parses `[--domain <id> --router <0x..> [--gas N]]`; both --domain and --router, or neither
+func parseArgs(args []string) (*singleEnrollment, error) {
+	var domain, router, gas string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		var target *string
+		switch name {
+		case "--domain":
+			target = &domain
+		case "--router":
+			target = &router
+		case "--gas":
+			target = &gas
+		default:
+			return nil, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		*target = value
+	}
+
+	if domain == "" && router == "" {
+		if gas != "" {
+			return nil, fmt.Errorf("--gas only applies with --domain and --router")
+		}
+		return nil, nil
+	}
+	if domain == "" || router == "" {
+		return nil, fmt.Errorf("--domain and --router must be given together")
+	}
+
+	d, err := strconv.ParseUint(domain, 0, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --domain %q: %w", domain, err)
+	}
+	s := &singleEnrollment{domain: uint32(d)}
+
+	digits := strings.TrimPrefix(router, "0x")
+	if digits == "" || len(digits) > 64 {
+		return nil, fmt.Errorf("invalid --router %q: expected up to 32 bytes of hex", router)
+	}
+	if s.router, err = starknetutil.HexToBytes32(router); err != nil {
+		return nil, fmt.Errorf("invalid --router %q: %w", router, err)
+	}
+
+	if gas != "" {
+		g, ok := new(big.Int).SetString(gas, 0)
+		if !ok || g.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid --gas %q: expected a positive integer", gas)
+		}
+		s.gas = g
+	}
+	return s, nil
+}
+
+// requireEnv returns the values of keys, failing on the first one that is not set
+func requireEnv(keys ...string) ([]string, error) {
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		if values[i] = os.Getenv(k); values[i] == "" {
+			return nil, fmt.Errorf("missing env: %s", k)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,81 @@
+package registersnrouters
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestParseArgs(t *testing.T) {
+	single, err := parseArgs(nil)
+	require.NoError(t, err)
+	assert.Nil(t, single, "no flags enrolls every network")
+
+	single, err = parseArgs([]string{"--domain", "8453", "--router=0xabc"})
+	require.NoError(t, err)
+	require.NotNil(t, single)
+	assert.Equal(t, uint32(8453), single.domain)
+	assert.Equal(t, byte(0x0a), single.router[30])
+	assert.Equal(t, byte(0xbc), single.router[31])
+	assert.Nil(t, single.gas)
+
+	single, err = parseArgs([]string{"--domain=0x2105", "--router", "0x01", "--gas", "70000"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(8453), single.domain)
+	assert.Equal(t, big.NewInt(70000), single.gas)
+
+	for _, args := range [][]string{
+		{"--domain", "1"},
+		{"--router", "0x01"},
+		{"--gas", "1"},
+		{"--domain", "x", "--router", "0x01"},
+		{"--domain", "1", "--router", "0xzz"},
+		{"--domain", "1", "--router", "0x01" + strings.Repeat("00", 32)},
+		{"--domain", "1", "--router", "0x01", "--gas", "0"},
+		{"--domain"},
+		{"extra"},
+	} {
+		_, err := parseArgs(args)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestConfiguredEntries(t *testing.T) {
+	networks := map[string]config.NetworkConfig{
+		"Ethereum": {HyperlaneAddress: "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3", HyperlaneDomain: 11155111, DestinationGas: 64000},
+		"Starknet": {HyperlaneAddress: "0x2a3", HyperlaneDomain: 23448591, DestinationGas: 100000},
+		"Ztarknet": {HyperlaneDomain: 10066329, DestinationGas: 100000},
+	}
+
+	entries, err := configuredEntries(networks)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "networks without a Hyperlane address are skipped")
+
+	assert.Equal(t, "Ethereum", entries[0].name)
+	assert.Equal(t, "0x000000000000000000000000f614c6bf94b022e16bef7dbecf7614ffd2b201d3", "0x"+hex.EncodeToString(entries[0].router[:]))
+	assert.Equal(t, big.NewInt(64000), entries[0].gas)
+
+	assert.Equal(t, "Starknet", entries[1].name)
+	assert.Equal(t, uint32(23448591), entries[1].domain)
+	assert.Equal(t, byte(0x02), entries[1].router[30])
+	assert.Equal(t, byte(0xa3), entries[1].router[31])
+	assert.Equal(t, big.NewInt(100000), entries[1].gas)
+
+	t.Run("single_entry_gas", func(t *testing.T) {
+		known := (&singleEnrollment{domain: 23448591}).entry(networks)
+		assert.Equal(t, big.NewInt(100000), known.gas, "a configured domain uses its DestinationGas")
+
+		t.Setenv("EVM_DESTINATION_GAS", "75000")
+		unknown := (&singleEnrollment{domain: 1}).entry(networks)
+		assert.Equal(t, big.NewInt(75000), unknown.gas, "an unknown domain falls back to EVM_DESTINATION_GAS")
+
+		explicit := (&singleEnrollment{domain: 23448591, gas: big.NewInt(5)}).entry(networks)
+		assert.Equal(t, big.NewInt(5), explicit.gas)
+	})
+}
@@ -0,0 +1,96 @@
+package setupstarknetcontracts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// NoBatchFlag sends every mint and approval as its own invoke, to find which one fails
+const NoBatchFlag = "--no-batch"
+
+// signer sends invokes from one account and waits for their receipts; accountSigner implements it
+type signer interface {
+	starknetutil.InvokeSender
+	starknetutil.FinalityWaiter
+}
+
+// accountSigner is a signer sending from the account and reading statuses and receipts through its provider
+type accountSigner struct {
+	*account.Account
+}
+
+func (a accountSigner) TransactionStatus(ctx context.Context, txHash *felt.Felt) (*rpc.TxnStatusResult, error) {
+	return a.Provider.TransactionStatus(ctx, txHash)
+}
+
+func (a accountSigner) TransactionReceipt(ctx context.Context, txHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	return a.Provider.TransactionReceipt(ctx, txHash)
+}
+
+// setupCall is one mint or approval of the setup
+type setupCall struct {
+	desc string
+	call rpc.InvokeFunctionCall
+}
+
+// parseSetupArgs reads the tool's arguments and reports whether calls are batched
+func parseSetupArgs(args []string) (bool, error) {
+	batch := true
+	for _, arg := range args {
+		if arg != NoBatchFlag {
+			return false, fmt.Errorf("unexpected argument: %s (usage: setup-starknet-contracts [%s])", arg, NoBatchFlag)
+		}
+		batch = false
+	}
+	return batch, nil
+}
+
+// sendCalls sends calls from s. Batched, they go out as a single multicall invoke with one receipt wait, which
+// reverts as a whole; otherwise each call is its own invoke so a failure names its call
+func sendCalls(ctx context.Context, policy starknetutil.RetryPolicy, s signer, what string, calls []setupCall, batch bool) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	if !batch {
+		for _, c := range calls {
+			logger.Infof("     🪙 %s...\n", c.desc)
+			if err := sendInvoke(ctx, policy, s, what, []rpc.InvokeFunctionCall{c.call}); err != nil {
+				return fmt.Errorf("%s: %w", c.desc, err)
+			}
+		}
+		return nil
+	}
+
+	invokes := make([]rpc.InvokeFunctionCall, len(calls))
+	logger.Infof("     📦 Sending %d %s call(s) in one transaction:\n", len(calls), what)
+	for i, c := range calls {
+		invokes[i] = c.call
+		logger.Infof("       • %s\n", c.desc)
+	}
+	if err := sendInvoke(ctx, policy, s, what, invokes); err != nil {
+		return fmt.Errorf("%s multicall of %d calls (rerun with %s to find the failing one): %w", what, len(calls), NoBatchFlag, err)
+	}
+	return nil
+}
+
+// sendInvoke sends one invoke of calls from s and waits for its receipt
+func sendInvoke(ctx context.Context, policy starknetutil.RetryPolicy, s signer, what string, calls []rpc.InvokeFunctionCall) error {
+	resp, err := starknetutil.BuildAndSendInvokeTxnWithRetry(ctx, policy, s, calls, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send the %s transaction: %w", what, err)
+	}
+
+	logger.Infof("     ⏳ %s transaction sent: %s\n", what, resp.Hash.String())
+	logger.Infof("     ⏳ Waiting for confirmation...\n")
+	if err := waitForReceipt(ctx, s, resp.Hash, what); err != nil {
+		return err
+	}
+	logger.Infof("     ✅ %s transaction confirmed\n", what)
+	return nil
+}
@@ -0,0 +1,94 @@
+package setupstarknetcontracts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSigner records the invokes it is asked to send; revertAt reverts the invoke with that index
+type fakeSigner struct {
+	invokes  [][]rpc.InvokeFunctionCall
+	receipts int
+	revertAt int
+}
+
+func (f *fakeSigner) Nonce(context.Context) (*felt.Felt, error) {
+	return new(felt.Felt).SetUint64(uint64(len(f.invokes))), nil
+}
+
+func (f *fakeSigner) BuildAndSendInvokeTxn(_ context.Context, calls []rpc.InvokeFunctionCall, _ *account.TxnOptions) (rpc.AddInvokeTransactionResponse, error) {
+	f.invokes = append(f.invokes, calls)
+	return rpc.AddInvokeTransactionResponse{Hash: new(felt.Felt).SetUint64(uint64(len(f.invokes)))}, nil
+}
+
+func (f *fakeSigner) TransactionStatus(_ context.Context, _ *felt.Felt) (*rpc.TxnStatusResult, error) {
+	return &rpc.TxnStatusResult{FinalityStatus: rpc.TxnStatusAcceptedOnL2}, nil
+}
+
+func (f *fakeSigner) TransactionReceipt(_ context.Context, txHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	f.receipts++
+	receipt := &rpc.TransactionReceiptWithBlockInfo{}
+	if int(txHash.Uint64()) == f.revertAt {
+		receipt.ExecutionStatus = rpc.TxnExecutionStatusREVERTED
+		receipt.RevertReason = "ERC20: insufficient balance"
+	}
+	return receipt, nil
+}
+
+func testCalls(functions ...string) []setupCall {
+	calls := make([]setupCall, len(functions))
+	for i, function := range functions {
+		calls[i] = setupCall{desc: function + " for Alice", call: rpc.InvokeFunctionCall{FunctionName: function}}
+	}
+	return calls
+}
+
+func TestSendCalls(t *testing.T) {
+	policy := retryPolicyFromEnv()
+
+	t.Run("batched", func(t *testing.T) {
+		s := &fakeSigner{}
+		require.NoError(t, sendCalls(context.Background(), policy, s, "mint", testCalls("mint", "mint", "mint"), true))
+		require.Len(t, s.invokes, 1, "one multicall invoke")
+		assert.Len(t, s.invokes[0], 3)
+		assert.Equal(t, 1, s.receipts)
+	})
+
+	t.Run("no batch", func(t *testing.T) {
+		s := &fakeSigner{revertAt: 2}
+		err := sendCalls(context.Background(), policy, s, "approve", testCalls("approve", "transfer", "mint"), false)
+		assert.ErrorContains(t, err, "transfer for Alice: approve transaction 0x2 reverted: ERC20: insufficient balance")
+		assert.Len(t, s.invokes, 2, "sending stops at the failing call")
+	})
+
+	t.Run("batched revert", func(t *testing.T) {
+		s := &fakeSigner{revertAt: 1}
+		err := sendCalls(context.Background(), policy, s, "mint", testCalls("mint", "mint"), true)
+		assert.ErrorContains(t, err, "mint multicall of 2 calls (rerun with --no-batch to find the failing one)")
+	})
+
+	t.Run("nothing to send", func(t *testing.T) {
+		s := &fakeSigner{}
+		require.NoError(t, sendCalls(context.Background(), policy, s, "mint", nil, true))
+		assert.Empty(t, s.invokes)
+	})
+}
+
+func TestParseSetupArgs(t *testing.T) {
+	batch, err := parseSetupArgs(nil)
+	require.NoError(t, err)
+	assert.True(t, batch)
+
+	batch, err = parseSetupArgs([]string{"--no-batch"})
+	require.NoError(t, err)
+	assert.False(t, batch)
+
+	_, err = parseSetupArgs([]string{"--batch"})
+	assert.ErrorContains(t, err, "unexpected argument: --batch")
+}
@@ -0,0 +1,33 @@
+package setupstarknetcontracts
+
+import (
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+const (
+	// Defaults when SETUP_RETRY_ATTEMPTS / SETUP_RETRY_BACKOFF_MS are not set
+	defaultRetryAttempts  = 3
+	defaultRetryBackoffMs = 1000
+)
+
+// retryPolicyFromEnv reads SETUP_RETRY_ATTEMPTS and SETUP_RETRY_BACKOFF_MS into the policy used for every
+// Starknet call, send and receipt wait of the setup
+func retryPolicyFromEnv() starknetutil.RetryPolicy {
+	attempts := envutil.GetEnvInt("SETUP_RETRY_ATTEMPTS", defaultRetryAttempts)
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoffMs := envutil.GetEnvInt("SETUP_RETRY_BACKOFF_MS", defaultRetryBackoffMs)
+	if backoffMs < 0 {
+		backoffMs = 0
+	}
+	return starknetutil.RetryPolicy{
+		Attempts:   attempts,
+		Backoff:    time.Duration(backoffMs) * time.Millisecond,
+		MaxBackoff: starknetutil.DefaultRetryPolicy.MaxBackoff,
+		Logger:     logger,
+	}
+}
@@ -0,0 +1,23 @@
+package setupstarknetcontracts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyFromEnv(t *testing.T) {
+	t.Setenv("SETUP_RETRY_ATTEMPTS", "5")
+	t.Setenv("SETUP_RETRY_BACKOFF_MS", "250")
+	policy := retryPolicyFromEnv()
+	assert.Equal(t, 5, policy.Attempts)
+	assert.Equal(t, 250*time.Millisecond, policy.Backoff)
+	assert.Same(t, logger, policy.Logger)
+
+	t.Setenv("SETUP_RETRY_ATTEMPTS", "0")
+	t.Setenv("SETUP_RETRY_BACKOFF_MS", "")
+	policy = retryPolicyFromEnv()
+	assert.Equal(t, 1, policy.Attempts)
+	assert.Equal(t, defaultRetryBackoffMs*time.Millisecond, policy.Backoff)
+}
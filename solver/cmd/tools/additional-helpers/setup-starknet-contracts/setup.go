@@ -0,0 +1,367 @@
+package setupstarknetcontracts
+
+// Setup tool: funds Alice and the solver with every Starknet token and sets their allowances for Hyperlane7683,
+// skipping what is already in place
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/rpcpool"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// Token deployment info structure
+type TokenDeploymentInfo struct {
+	NetworkName    string      `json:"networkName"`
+	DeploymentTime string      `json:"deploymentTime"`
+	Tokens         []TokenInfo `json:"tokens"`
+}
+
+type TokenInfo struct {
+	Name      string `json:"name"`
+	Symbol    string `json:"symbol"`
+	Address   string `json:"address"`
+	ClassHash string `json:"classHash"`
+	Decimals  uint8  `json:"decimals"`
+}
+
+// User funding configuration
+const (
+	// Amount to fund each user, in whole tokens (scaled by the token's decimals)
+	UserFundingTokens = 100000
+)
+
+// receiptWaitTimeout bounds a receipt wait
+const receiptWaitTimeout = 2 * time.Minute
+
+// logger carries the progress output; LOG_LEVEL and LOG_FORMAT are applied once .env is loaded
+var logger = logutil.NewToolLogger()
+
+// loadCentralAddresses loads Hyperlane from .env and every token of the token set from .env or its deployment state
+func loadCentralAddresses(networkName string, specs []tokenspec.TokenSpec) (hyperlane string, tokens []TokenInfo, err error) {
+	hyperlane = os.Getenv("STARKNET_HYPERLANE_ADDRESS")
+	if hyperlane == "" {
+		return "", nil, fmt.Errorf("STARKNET_HYPERLANE_ADDRESS not found in .env")
+	}
+
+	for _, spec := range specs {
+		address, _, err := tokenspec.Address(networkName, spec.Name)
+		if err != nil {
+			return "", nil, err
+		}
+		tokens = append(tokens, TokenInfo{Name: spec.Name, Symbol: spec.Symbol, Address: address})
+	}
+	return hyperlane, tokens, nil
+}
+
+// Run funds the users and sets their allowances as described by args: [--no-batch]
+func Run(ctx context.Context, args []string) error {
+	batch, err := parseSetupArgs(args)
+	if err != nil {
+		return err
+	}
+
+	envErr := solverdir.LoadEnv()
+	logutil.ConfigureFromEnv(logger)
+	if envErr != nil {
+		logger.Warnf("⚠️  %v, using environment variables", envErr)
+	}
+
+	// Initialize networks from centralized config after .env is loaded
+	config.InitializeNetworks()
+
+	logger.Infoln("🚀 Setting up Starknet contracts: funding users and setting allowances...")
+
+	// Load environment variables
+	networkName := "Starknet"
+
+	// Get network configuration
+	networkConfig, err := config.GetNetworkConfig(networkName)
+	if err != nil {
+		return fmt.Errorf("failed to get network config for %s: %w", networkName, err)
+	}
+
+	// Load Starknet account details from .env
+	deployerAddress := os.Getenv("STARKNET_DEPLOYER_ADDRESS")
+	deployerKey, err := credentials.LoadStarknetKey("STARKNET_DEPLOYER")
+	if err != nil && !errors.Is(err, credentials.ErrMissingKey) {
+		return fmt.Errorf("failed to load the deployer key: %w", err)
+	}
+
+	// Load test user addresses from .env
+	aliceAddress := os.Getenv("STARKNET_ALICE_ADDRESS")
+	solverAddress := os.Getenv("STARKNET_SOLVER_ADDRESS")
+
+	if deployerAddress == "" || err != nil {
+		logger.Errorln("❌ Missing required environment variables:")
+		logger.Errorln("   STARKNET_DEPLOYER_ADDRESS: Your Starknet account address")
+		logger.Errorln("   STARKNET_DEPLOYER_PRIVATE_KEY: Your private key")
+		logger.Errorln("   STARKNET_DEPLOYER_PUBLIC_KEY: Your public key")
+		logger.Errorln("   (or STARKNET_DEPLOYER_KEY_SOURCE=keystore:<path> and STARKNET_DEPLOYER_KEY_PASSWORD_FILE)")
+		return fmt.Errorf("missing deployer environment variables")
+	}
+
+	if aliceAddress == "" || solverAddress == "" {
+		logger.Errorln("❌ Missing required environment variables:")
+		logger.Errorln("   STARKNET_ALICE_ADDRESS: Alice's Starknet address")
+		logger.Errorln("   STARKNET_SOLVER_ADDRESS: Solver's Starknet address")
+		return fmt.Errorf("missing test user environment variables")
+	}
+
+	policy := retryPolicyFromEnv()
+
+	logger.Infof("📋 Network: %s\n", networkName)
+	logger.Infof("📋 RPC URL: %s\n", networkConfig.RPCURL)
+	logger.Infof("📋 Chain ID: %s\n", networkConfig.ChainIDLabel())
+	logger.Infof("📋 Deployer: %s\n", deployerAddress)
+	logger.Infof("📋 Test Users: Alice=%s, Solver=%s\n", aliceAddress, solverAddress)
+	logger.Infof("📋 Retry: %d attempts, %v initial backoff\n", policy.Attempts, policy.Backoff)
+	if !batch {
+		logger.Infof("📋 %s: one transaction per mint and approval\n", NoBatchFlag)
+	}
+
+	// Initialize connection to RPC provider
+	pool := rpcpool.New(rpcpool.OptionsFromEnv())
+	defer pool.Close()
+	client, err := pool.StarknetFailover(ctx, networkName, networkConfig.RPCURLs)
+	if err != nil {
+		return fmt.Errorf("error connecting to RPC provider: %w", err)
+	}
+	if err := config.VerifyStarknetChainID(ctx, client, networkConfig); err != nil {
+		return err
+	}
+
+	// Convert account address to felt
+	accountAddressFelt, err := utils.HexToFelt(deployerAddress)
+	if err != nil {
+		return fmt.Errorf("invalid account address: %w", err)
+	}
+
+	logger.Infoln("✅ Connected to Starknet RPC")
+
+	accnt, err := starknetutil.NewDeployerAccount(ctx, client, networkName, accountAddressFelt, deployerKey.PublicKey.String(), deployerKey.Keystore())
+	if err != nil {
+		return err
+	}
+
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return fmt.Errorf("failed to load token specs: %w", err)
+	}
+
+	// Load addresses from centralized deployment-state
+	hyperlaneAddr, tokens, err := loadCentralAddresses(networkName, specs)
+	if err != nil {
+		return fmt.Errorf("failed to load centralized addresses: %w", err)
+	}
+
+	// Read decimals once so amounts and display match the deployed tokens
+	for i := range tokens {
+		tokens[i].Decimals, err = starknetutil.ERC20Decimals(ctx, starknetutil.RetryingCaller(accnt.Provider, policy), tokens[i].Address)
+		if err != nil {
+			return fmt.Errorf("failed to read %s decimals: %w", tokens[i].Name, err)
+		}
+		logger.Infof("📋 %s: %s (%d decimals)\n", tokens[i].Name, tokens[i].Address, tokens[i].Decimals)
+	}
+
+	// Alice approves Hyperlane7683 to open orders, the solver to fill them on this chain as a destination
+	users := []testUser{
+		{name: "Alice", address: aliceAddress, keyPrefix: "STARKNET_ALICE"},
+		{name: "Solver", address: solverAddress, keyPrefix: "STARKNET_SOLVER"},
+	}
+
+	// Fund test users: every mint is sent by the deployer
+	logger.Infof("\n💰 Funding test users...\n")
+	fundings, err := fundUsers(ctx, policy, accnt, tokens, users, batch)
+	if err != nil {
+		return fmt.Errorf("failed to fund users: %w", err)
+	}
+
+	// Set allowances for Hyperlane7683: the approvals of each user are sent together
+	logger.Infof("\n🔐 Setting allowances for Hyperlane7683...\n")
+	logger.Infof("   📋 Found Hyperlane7683 at: %s\n", hyperlaneAddr)
+	approvers, err := setAllowances(ctx, policy, accnt, tokens, hyperlaneAddr, users, batch)
+	if err != nil {
+		return fmt.Errorf("failed to set allowances: %w", err)
+	}
+
+	// Verify balances and allowances after everything is set, reporting every shortfall at once
+	logger.Infof("\n🔍 Verifying balances and allowances...\n")
+	if err := verifySetup(ctx, policy, accnt, hyperlaneAddr, fundings, setupRequirements(tokens, users, approvers)); err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	logger.Infof("✅ All verifications passed!\n")
+
+	// Note: .env file updates removed - addresses should be set manually after live deployment
+
+	logger.Infof("\n🎯 Starknet contract setup completed successfully!\n")
+	logger.Infof("   • Users funded with %d token(s)\n", len(tokens))
+	logger.Infof("   • Allowances set for Hyperlane7683\n")
+	logger.Infof("   • Ready for cross-chain operations!\n")
+	return nil
+}
+
+// testUser is an account the setup funds
+type testUser struct {
+	name    string
+	address string
+	// keyPrefix names the credentials approving Hyperlane7683 for the user
+	keyPrefix string
+}
+
+// funding is a mint sent by fundUsers, checked by verifySetup
+type funding struct {
+	user   testUser
+	token  TokenInfo
+	before *big.Int
+	amount *big.Int
+}
+
+// fundUsers mints UserFundingTokens of every token to every user, all from accnt: in a single multicall when
+// batch is set. Users already holding at least UserFundingTokens are skipped so reruns don't re-mint
+func fundUsers(ctx context.Context, policy starknetutil.RetryPolicy, accnt *account.Account, tokens []TokenInfo, users []testUser, batch bool) ([]funding, error) {
+	var fundings []funding
+	var calls []setupCall
+	for _, token := range tokens {
+		expectedAmount := starknetutil.ScaleTokenAmount(big.NewInt(UserFundingTokens), token.Decimals)
+		for _, user := range users {
+			// Check balance before minting
+			balanceBefore, err := getTokenBalance(ctx, policy, accnt, token.Address, user.address)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s's %s balance before minting: %w", user.name, token.Name, err)
+			}
+
+			logger.Infof("   📊 %s balance before: %s=%s\n", user.name, token.Name, starknetutil.FormatTokenAmount(balanceBefore, int(token.Decimals)))
+
+			if balanceBefore.Cmp(expectedAmount) >= 0 {
+				logger.Infof("   ✅ %s already funded with %s, skipping mint\n", user.name, token.Name)
+				continue
+			}
+
+			mintCall, err := starknetutil.ERC20Mint(token.Address, user.address, expectedAmount)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build the %s mint for %s: %w", token.Name, user.name, err)
+			}
+			calls = append(calls, setupCall{
+				desc: fmt.Sprintf("mint %s %s to %s", starknetutil.FormatTokenAmount(expectedAmount, int(token.Decimals)), token.Name, user.name),
+				call: *mintCall,
+			})
+			fundings = append(fundings, funding{user: user, token: token, before: balanceBefore, amount: expectedAmount})
+		}
+	}
+
+	if err := sendCalls(ctx, policy, accountSigner{accnt}, "mint", calls, batch); err != nil {
+		return nil, err
+	}
+	return fundings, nil
+}
+
+// waitForReceipt waits up to receiptWaitTimeout for a transaction to be accepted on L2 without reverting
+func waitForReceipt(ctx context.Context, waiter starknetutil.FinalityWaiter, txHash *felt.Felt, desc string) error {
+	_, err := starknetutil.WaitForFinality(txcost.WithOperation(ctx, desc), waiter, txHash, starknetutil.FinalityAcceptedOnL2, time.Second, receiptWaitTimeout)
+	if errors.Is(err, starknetutil.ErrTransactionReverted) || errors.Is(err, starknetutil.ErrTransactionRejected) {
+		return fmt.Errorf("%s %w", desc, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to wait for %s confirmation: %w", desc, err)
+	}
+	return nil
+}
+
+// getTokenBalance gets the balance of a token for a specific address
+func getTokenBalance(ctx context.Context, policy starknetutil.RetryPolicy, accnt *account.Account, tokenAddress, userAddress string) (*big.Int, error) {
+	return starknetutil.ERC20Balance(ctx, starknetutil.RetryingCaller(accnt.Provider, policy), tokenAddress, userAddress)
+}
+
+// setAllowances sets unlimited Hyperlane7683 allowances on every token for the users holding credentials. The
+// approvals of a user are signed by that user, so each one sends a single multicall when batch is set. It returns
+// the users whose allowances were requested, which verifySetup then requires
+func setAllowances(ctx context.Context, policy starknetutil.RetryPolicy, accnt *account.Account, tokens []TokenInfo, hyperlaneAddress string, users []testUser, batch bool) (map[string]bool, error) {
+	approvers := make(map[string]bool)
+	if hyperlaneAddress == "" {
+		logger.Warnln("   ⚠️  No Hyperlane address provided, skipping allowance setup")
+		return approvers, nil
+	}
+
+	// Validate Hyperlane address
+	if _, err := utils.HexToFelt(hyperlaneAddress); err != nil {
+		return nil, fmt.Errorf("invalid Hyperlane address: %w", err)
+	}
+
+	for _, user := range users {
+		logger.Infof("     🔓 Setting %s allowances...\n", user.name)
+
+		// Check if user has credentials
+		if user.keyPrefix == "" {
+			continue
+		}
+		userKey, err := credentials.LoadStarknetKey(user.keyPrefix)
+		if errors.Is(err, credentials.ErrMissingKey) {
+			logger.Warnf("       ⚠️  Missing credentials for %s, skipping\n", user.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the key of %s: %w", user.name, err)
+		}
+
+		// Create user account
+		userAddrFelt, err := utils.HexToFelt(user.address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user address for %s: %w", user.name, err)
+		}
+
+		// Create user account with the Cairo version of its contract
+		userAccnt, err := starknetutil.NewAccount(ctx, accnt.Provider, starknetutil.AccountVersionEnv("Starknet", user.name), userAddrFelt, userKey.PublicKey.String(), userKey.Keystore())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create account for %s: %w", user.name, err)
+		}
+
+		var calls []setupCall
+		for _, token := range tokens {
+			// Skip the approval if a previous run already set it
+			allowance, err := getTokenAllowance(ctx, policy, accnt, token.Address, user.address, hyperlaneAddress)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s's %s allowance: %w", user.name, token.Name, err)
+			}
+			if allowance.Cmp(starknetutil.MaxU256) == 0 {
+				logger.Infof("       ✅ %s already has unlimited %s allowance, skipping\n", user.name, token.Name)
+				continue
+			}
+
+			approveCall, err := starknetutil.ERC20Approve(token.Address, hyperlaneAddress, starknetutil.MaxU256)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build the %s approval for %s: %w", token.Name, user.name, err)
+			}
+			calls = append(calls, setupCall{desc: fmt.Sprintf("approve unlimited %s for %s", token.Name, user.name), call: *approveCall})
+		}
+
+		if err := sendCalls(ctx, policy, accountSigner{userAccnt}, "approve", calls, batch); err != nil {
+			return nil, fmt.Errorf("failed to approve for %s: %w", user.name, err)
+		}
+		approvers[user.name] = true
+		logger.Infof("       ✅ %s allowances set successfully\n", user.name)
+	}
+
+	logger.Infoln("   ✅ All allowances set successfully!")
+	return approvers, nil
+}
+
+// getTokenAllowance gets the allowance of a token for a specific spender
+func getTokenAllowance(ctx context.Context, policy starknetutil.RetryPolicy, accnt *account.Account, tokenAddress, ownerAddress, spenderAddress string) (*big.Int, error) {
+	return starknetutil.ERC20Allowance(ctx, starknetutil.RetryingCaller(accnt.Provider, policy), tokenAddress, ownerAddress, spenderAddress)
+}
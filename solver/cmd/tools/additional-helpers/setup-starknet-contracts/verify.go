@@ -0,0 +1,122 @@
+package setupstarknetcontracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/starknet.go/account"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// requirement is the balance and Hyperlane7683 allowance a user must hold of a token once the setup is done
+type requirement struct {
+	user       testUser
+	token      TokenInfo
+	minBalance *big.Int
+	// minAllowance is nil when no approval was requested for the user
+	minAllowance *big.Int
+}
+
+// setupRequirements lists what verifySetup checks: the funding amount of every token for every user, and an
+// unlimited allowance for the approvers setAllowances returned
+func setupRequirements(tokens []TokenInfo, users []testUser, approvers map[string]bool) []requirement {
+	var requirements []requirement
+	for _, token := range tokens {
+		for _, user := range users {
+			r := requirement{
+				user:       user,
+				token:      token,
+				minBalance: starknetutil.ScaleTokenAmount(big.NewInt(UserFundingTokens), token.Decimals),
+			}
+			if approvers[user.name] {
+				r.minAllowance = starknetutil.MaxU256
+			}
+			requirements = append(requirements, r)
+		}
+	}
+	return requirements
+}
+
+// checkFunding describes how a mint fell short, or returns "" when the balance rose by the minted amount
+func checkFunding(f funding, after *big.Int) string {
+	increase := new(big.Int).Sub(after, f.before)
+	if increase.Cmp(f.amount) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %s: mint raised the balance by %s, expected %s", f.user.name, f.token.Name, increase, f.amount)
+}
+
+// checkRequirement describes how a user falls short of r; allowance is ignored when r requires none
+func checkRequirement(r requirement, balance, allowance *big.Int) []string {
+	var problems []string
+	decimals := int(r.token.Decimals)
+	if balance.Cmp(r.minBalance) < 0 {
+		problems = append(problems, fmt.Sprintf("%s %s balance: %s, expected at least %s", r.user.name, r.token.Name,
+			starknetutil.FormatTokenAmount(balance, decimals), starknetutil.FormatTokenAmount(r.minBalance, decimals)))
+	}
+	if r.minAllowance != nil && allowance.Cmp(r.minAllowance) < 0 {
+		expected := "at least " + r.minAllowance.String()
+		if r.minAllowance.Cmp(starknetutil.MaxU256) == 0 {
+			expected = "unlimited"
+		}
+		problems = append(problems, fmt.Sprintf("%s %s allowance for Hyperlane7683: %s, expected %s", r.user.name, r.token.Name, allowance, expected))
+	}
+	return problems
+}
+
+// verifySetup reads back every minted balance and every requirement, logging each user and token, and returns a
+// single error listing all shortfalls
+func verifySetup(ctx context.Context, policy starknetutil.RetryPolicy, accnt *account.Account, hyperlaneAddress string, fundings []funding, requirements []requirement) error {
+	var problems []string
+	report := func(what string, found []string) {
+		if len(found) == 0 {
+			logger.Infof("   ✅ %s\n", what)
+			return
+		}
+		for _, problem := range found {
+			logger.Errorf("   ❌ %s\n", problem)
+		}
+		problems = append(problems, found...)
+	}
+
+	for _, f := range fundings {
+		after, err := getTokenBalance(ctx, policy, accnt, f.token.Address, f.user.address)
+		if err != nil {
+			report("", []string{fmt.Sprintf("%s %s balance: %v", f.user.name, f.token.Name, err)})
+			continue
+		}
+		var found []string
+		if problem := checkFunding(f, after); problem != "" {
+			found = append(found, problem)
+		}
+		report(fmt.Sprintf("%s %s: minted %s", f.user.name, f.token.Name, starknetutil.FormatTokenAmount(f.amount, int(f.token.Decimals))), found)
+	}
+
+	for _, r := range requirements {
+		balance, err := getTokenBalance(ctx, policy, accnt, r.token.Address, r.user.address)
+		if err != nil {
+			report("", []string{fmt.Sprintf("%s %s balance: %v", r.user.name, r.token.Name, err)})
+			continue
+		}
+		allowance := new(big.Int)
+		if r.minAllowance != nil {
+			if allowance, err = getTokenAllowance(ctx, policy, accnt, r.token.Address, r.user.address, hyperlaneAddress); err != nil {
+				report("", []string{fmt.Sprintf("%s %s allowance: %v", r.user.name, r.token.Name, err)})
+				continue
+			}
+		}
+		what := fmt.Sprintf("%s %s: balance %s", r.user.name, r.token.Name, starknetutil.FormatTokenAmount(balance, int(r.token.Decimals)))
+		if r.minAllowance != nil {
+			what += ", unlimited allowance"
+		}
+		report(what, checkRequirement(r, balance, allowance))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%d check(s) failed:\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
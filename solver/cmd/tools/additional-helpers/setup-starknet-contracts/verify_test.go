@@ -0,0 +1,49 @@
+package setupstarknetcontracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+func TestSetupRequirements(t *testing.T) {
+	tokens := []TokenInfo{{Name: "OrcaCoin", Decimals: 18}, {Name: "DogCoin", Decimals: 6}}
+	users := []testUser{{name: "Alice"}, {name: "Solver"}}
+
+	requirements := setupRequirements(tokens, users, map[string]bool{"Solver": true})
+	require.Len(t, requirements, 4)
+	assert.Equal(t, "Alice", requirements[0].user.name)
+	assert.Nil(t, requirements[0].minAllowance, "no approval was requested for Alice")
+	assert.Equal(t, starknetutil.MaxU256, requirements[1].minAllowance)
+	assert.Equal(t, "100000000000", requirements[3].minBalance.String(), "scaled by the token's decimals")
+}
+
+func TestCheckRequirement(t *testing.T) {
+	r := requirement{
+		user:         testUser{name: "Solver"},
+		token:        TokenInfo{Name: "DogCoin", Decimals: 6},
+		minBalance:   big.NewInt(100_000_000_000),
+		minAllowance: starknetutil.MaxU256,
+	}
+
+	assert.Empty(t, checkRequirement(r, big.NewInt(200_000_000_000), starknetutil.MaxU256))
+
+	problems := checkRequirement(r, big.NewInt(5_000_000), big.NewInt(0))
+	assert.Equal(t, []string{
+		"Solver DogCoin balance: 5.00 tokens, expected at least 100000.00 tokens",
+		"Solver DogCoin allowance for Hyperlane7683: 0, expected unlimited",
+	}, problems, "every shortfall is reported")
+
+	r.minAllowance = nil
+	assert.Empty(t, checkRequirement(r, big.NewInt(100_000_000_000), big.NewInt(0)), "the allowance is only checked when requested")
+}
+
+func TestCheckFunding(t *testing.T) {
+	f := funding{user: testUser{name: "Alice"}, token: TokenInfo{Name: "OrcaCoin"}, before: big.NewInt(10), amount: big.NewInt(100)}
+	assert.Empty(t, checkFunding(f, big.NewInt(110)))
+	assert.Equal(t, "Alice OrcaCoin: mint raised the balance by 0, expected 100", checkFunding(f, big.NewInt(10)))
+}
@@ -2,79 +2,227 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+const (
+	// Timeout for reading one contract
+	callTimeout = 15 * time.Second
 )
 
-type NetworkConfig struct {
-	Name       string
-	RPCURL     string
-	PrivateKey string
+// Checks that the contract at every configured Hyperlane7683 address is Hyperlane7683 on the right domain:
+// localDomain() must be the configured domain and, on Starknet, the class hash must be the one in the declaration
+// file when there is one. PACKAGE_VERSION() and owner() are printed. Exits 1 on any mismatch or failed read.
+//
+// Usage: verify-hyperlane7683 [network]   (defaults to every network with a Hyperlane address)
+
+// contractReader reads the Hyperlane7683 state of one network
+type contractReader interface {
+	Read(ctx context.Context) (deployment, error)
+	Close()
 }
 
 func main() {
-	// Get RPC URLs from environment variables with defaults
-	networks := []NetworkConfig{
-		{
-			Name:       "Sepolia",
-			RPCURL:     getEnvWithDefault("SEPOLIA_RPC_URL", "http://localhost:8545"),
-			PrivateKey: os.Getenv("PRIVATE_KEY"),
-		},
-		{
-			Name:       "Optimism Sepolia",
-			RPCURL:     getEnvWithDefault("OPTIMISM_RPC_URL", "http://localhost:8546"),
-			PrivateKey: os.Getenv("PRIVATE_KEY"),
-		},
-		{
-			Name:       "Arbitrum Sepolia",
-			RPCURL:     getEnvWithDefault("ARBITRUM_RPC_URL", "http://localhost:8547"),
-			PrivateKey: os.Getenv("PRIVATE_KEY"),
-		},
-		{
-			Name:       "Base Sepolia",
-			RPCURL:     getEnvWithDefault("BASE_RPC_URL", "http://localhost:8548"),
-			PrivateKey: os.Getenv("PRIVATE_KEY"),
-		},
-	}
-
-	// Get Hyperlane address from environment
-	hyperlaneAddress := getEnvWithDefault("EVM_HYPERLANE_ADDRESS", "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
-
-	for _, network := range networks {
-		fmt.Printf("🔍 Verifying Hyperlane7683 on %s...\n", network.Name)
-		fmt.Printf("   RPC URL: %s\n", network.RPCURL)
-		fmt.Printf("   Contract Address: %s\n", hyperlaneAddress)
-
-		client, err := ethclient.Dial(network.RPCURL)
-		if err != nil {
-			fmt.Printf("   ❌ Failed to connect: %v\n", err)
-			continue
+	solverdir.ParseOSArgs()
+	config.ParseRPCURLFlags()
+	if err := solverdir.LoadEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v, using environment variables\n", err)
+	}
+
+	config.InitializeNetworks()
+
+	networks, skipped, err := selectNetworks(config.Networks, os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range skipped {
+		fmt.Printf("⏭️  Skipping %s: no Hyperlane address configured\n", name)
+	}
+	declaration := readDeclaration(declarationFile())
+
+	// Ctrl-C stops the read in flight instead of killing the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	failures := 0
+	for _, cfg := range networks {
+		if ctx.Err() != nil {
+			fmt.Println("\n❌ Cancelled by user")
+			os.Exit(1)
 		}
+		fmt.Printf("\n🔍 %s (%s)\n", cfg.Name, cfg.HyperlaneAddress)
+		if err := verify(ctx, cfg, declaration); err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			failures++
+		}
+	}
 
-		// Check if contract exists
-		code, err := client.CodeAt(context.Background(), common.HexToAddress(hyperlaneAddress), nil)
+	if failures > 0 {
+		fmt.Printf("\n❌ %d of %d Hyperlane7683 deployments do not match config\n", failures, len(networks))
+		os.Exit(1)
+	}
+	fmt.Printf("\n✅ All %d Hyperlane7683 deployments match config\n", len(networks))
+}
+
+// verify reads one network's contract, prints what it found and returns an error listing any mismatch
+func verify(ctx context.Context, cfg config.NetworkConfig, declaration *deploystate.Declaration) error {
+	exp, err := expectationFor(cfg, declaration)
+	if err != nil {
+		return err
+	}
+	reader, err := newContractReader(cfg)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	d, err := reader.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	if diffs := diffDeployment(exp, d); len(diffs) > 0 {
+		return fmt.Errorf("does not match config:\n      %s", strings.Join(diffs, "\n      "))
+	}
+	fmt.Printf("   ✅ %s\n", describe(d))
+	return nil
+}
+
+// newContractReader connects to the network's RPC with the reader matching its contract
+func newContractReader(cfg config.NetworkConfig) (contractReader, error) {
+	if config.IsStarknetNetwork(cfg.Name) {
+		provider, err := rpc.NewProvider(cfg.RPCURL)
 		if err != nil {
-			fmt.Printf("   ❌ Failed to get contract code: %v\n", err)
-			continue
+			return nil, fmt.Errorf("failed to connect to %s: %w", cfg.RPCURL, err)
 		}
-
-		if len(code) == 0 {
-			fmt.Printf("   ❌ Contract not deployed or no code at address\n")
-		} else {
-			fmt.Printf("   ✅ Contract deployed")
+		address, err := utils.HexToFelt(cfg.HyperlaneAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Hyperlane address %s: %w", cfg.HyperlaneAddress, err)
 		}
+		return &starknetReader{provider: provider, address: address}, nil
+	}
 
+	if !common.IsHexAddress(cfg.HyperlaneAddress) {
+		return nil, fmt.Errorf("invalid Hyperlane address %s", cfg.HyperlaneAddress)
+	}
+	client, err := ethclient.Dial(cfg.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.RPCURL, err)
+	}
+	address := common.HexToAddress(cfg.HyperlaneAddress)
+	caller, err := contracts.NewHyperlane7683Caller(address, client)
+	if err != nil {
 		client.Close()
+		return nil, fmt.Errorf("failed to bind Hyperlane7683: %w", err)
+	}
+	return &evmReader{client: client, caller: caller, address: address}, nil
+}
+
+// evmReader reads the contract through the generated bindings
+type evmReader struct {
+	client  *ethclient.Client
+	caller  *contracts.Hyperlane7683Caller
+	address common.Address
+}
+
+func (r *evmReader) Read(ctx context.Context) (deployment, error) {
+	code, err := r.client.CodeAt(ctx, r.address, nil)
+	if err != nil {
+		return deployment{}, fmt.Errorf("failed to get contract code: %w", err)
+	}
+	if len(code) == 0 {
+		return deployment{}, nil
+	}
+
+	// A contract other than Hyperlane7683 reverts on these views
+	opts := &bind.CallOpts{Context: ctx}
+	d := deployment{HasCode: true}
+	if d.Domain, err = r.caller.LocalDomain(opts); err != nil {
+		return deployment{}, fmt.Errorf("localDomain() failed, is this Hyperlane7683? %w", err)
+	}
+	if d.Version, err = r.caller.PACKAGEVERSION(opts); err != nil {
+		return deployment{}, fmt.Errorf("PACKAGE_VERSION() failed, is this Hyperlane7683? %w", err)
+	}
+	owner, err := r.caller.Owner(opts)
+	if err != nil {
+		return deployment{}, fmt.Errorf("owner() failed, is this Hyperlane7683? %w", err)
+	}
+	d.Owner = owner.Hex()
+	return d, nil
+}
+
+func (r *evmReader) Close() { r.client.Close() }
+
+// starknetProvider is the part of the Starknet RPC provider starknetReader uses
+type starknetProvider interface {
+	Call(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error)
+	ClassHashAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+}
+
+// starknetReader reads the class hash and the get_local_domain and owner views
+type starknetReader struct {
+	provider starknetProvider
+	address  *felt.Felt
+}
+
+func (r *starknetReader) Read(ctx context.Context) (deployment, error) {
+	classHash, err := r.provider.ClassHashAt(ctx, rpc.WithBlockTag("latest"), r.address)
+	if err != nil {
+		var rpcErr *rpc.RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrContractNotFound.Code {
+			return deployment{}, nil
+		}
+		return deployment{}, fmt.Errorf("failed to get class hash: %w", err)
+	}
+
+	d := deployment{HasCode: true, ClassHash: classHash.String()}
+	domain, err := r.call(ctx, "get_local_domain")
+	if err != nil {
+		return deployment{}, err
 	}
+	d.Domain = uint32(domain.Uint64())
+	owner, err := r.call(ctx, "owner")
+	if err != nil {
+		return deployment{}, err
+	}
+	d.Owner = owner.String()
+	return d, nil
 }
 
-// getEnvWithDefault gets an environment variable with a default fallback
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+func (r *starknetReader) Close() {}
+
+// call calls a view without arguments returning a single felt
+func (r *starknetReader) call(ctx context.Context, function string) (*felt.Felt, error) {
+	resp, err := r.provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    r.address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           []*felt.Felt{},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("%s failed, is this Hyperlane7683? %w", function, err)
+	}
+	if len(resp) == 0 {
+		return nil, fmt.Errorf("%s returned no data", function)
 	}
-	return defaultValue
+	return resp[0], nil
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// declarationFile returns where declare-sn-hyperlane7683 records the declared class hash
+func declarationFile() string {
+	return deploystate.Path("starknet-hyperlane7683-declaration.json")
+}
+
+// expectation is what the Hyperlane7683 of one network should look like on-chain
+type expectation struct {
+	Address   string
+	Domain    uint32
+	ClassHash string // Starknet only; empty when no declaration names this network
+}
+
+// deployment is what was read back from the contract; Version is empty on Starknet, which has no PACKAGE_VERSION
+type deployment struct {
+	HasCode   bool
+	Domain    uint32
+	Version   string
+	Owner     string
+	ClassHash string
+}
+
+// selectNetworks returns the networks to verify, sorted by name: the one named in args (case-insensitive), or
+// every network with a Hyperlane address. Networks without an address are returned by name as skipped
+func selectNetworks(networks map[string]config.NetworkConfig, args []string) ([]config.NetworkConfig, []string, error) {
+	if len(args) > 1 {
+		return nil, nil, fmt.Errorf("usage: verify-hyperlane7683 [network]")
+	}
+
+	var selected []config.NetworkConfig
+	var skipped []string
+	for name, cfg := range networks {
+		if len(args) == 1 && !strings.EqualFold(name, args[0]) {
+			continue
+		}
+		if cfg.HyperlaneAddress == "" {
+			skipped = append(skipped, name)
+			continue
+		}
+		selected = append(selected, cfg)
+	}
+	if len(args) == 1 && len(selected) == 0 && len(skipped) == 0 {
+		return nil, nil, fmt.Errorf("unknown network %q", args[0])
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+	sort.Strings(skipped)
+	return selected, skipped, nil
+}
+
+// expectationFor builds the expectation of a network: its configured address and domain, plus the declared class
+// hash for a Starknet network the declaration file names
+func expectationFor(cfg config.NetworkConfig, declaration *deploystate.Declaration) (expectation, error) {
+	domain, err := config.GetHyperlaneDomain(cfg.Name)
+	if err != nil {
+		return expectation{}, err
+	}
+	exp := expectation{Address: cfg.HyperlaneAddress, Domain: uint32(domain)}
+	if declaration != nil && config.IsStarknetNetwork(cfg.Name) && strings.EqualFold(declaration.NetworkName, cfg.Name) {
+		exp.ClassHash = declaration.ClassHash
+	}
+	return exp, nil
+}
+
+// readDeclaration reads the declaration file; nil when it is absent or unreadable, so the class hash check is skipped
+func readDeclaration(path string) *deploystate.Declaration {
+	declaration, err := deploystate.ReadDeclaration(path)
+	if err != nil {
+		return nil
+	}
+	return &declaration
+}
+
+// diffDeployment lists how d differs from exp as expected vs actual; nil means the contract is the expected one
+func diffDeployment(exp expectation, d deployment) []string {
+	if !d.HasCode {
+		return []string{fmt.Sprintf("code: no contract deployed at %s", exp.Address)}
+	}
+	var diffs []string
+	if d.Domain != exp.Domain {
+		diffs = append(diffs, fmt.Sprintf("localDomain: want %d, got %d", exp.Domain, d.Domain))
+	}
+	if exp.ClassHash != "" && !sameFelt(exp.ClassHash, d.ClassHash) {
+		diffs = append(diffs, fmt.Sprintf("class hash: want %s (%s), got %s", exp.ClassHash, declarationFile(), d.ClassHash))
+	}
+	return diffs
+}
+
+// sameFelt compares two hex felts numerically, so leading zeros do not matter
+func sameFelt(a, b string) bool {
+	fa, errA := utils.HexToFelt(a)
+	fb, errB := utils.HexToFelt(b)
+	if errA != nil || errB != nil {
+		return strings.EqualFold(a, b)
+	}
+	return fa.Equal(fb)
+}
+
+// describe summarizes a verified deployment
+func describe(d deployment) string {
+	parts := []string{fmt.Sprintf("localDomain %d", d.Domain)}
+	if d.Version != "" {
+		parts = append(parts, "PACKAGE_VERSION "+d.Version)
+	}
+	parts = append(parts, "owner "+d.Owner)
+	if d.ClassHash != "" {
+		parts = append(parts, "class hash "+d.ClassHash)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestSelectNetworks(t *testing.T) {
+	networks := map[string]config.NetworkConfig{
+		"Base":     {Name: "Base", HyperlaneAddress: "0x7683"},
+		"Ethereum": {Name: "Ethereum", HyperlaneAddress: "0x7683"},
+		"Ztarknet": {Name: "Ztarknet"},
+	}
+
+	selected, skipped, err := selectNetworks(networks, nil)
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "Base", selected[0].Name)
+	assert.Equal(t, []string{"Ztarknet"}, skipped)
+
+	selected, skipped, err = selectNetworks(networks, []string{"ethereum"})
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "Ethereum", selected[0].Name)
+	assert.Empty(t, skipped)
+
+	_, _, err = selectNetworks(networks, []string{"Solana"})
+	assert.ErrorContains(t, err, `unknown network "Solana"`)
+	_, _, err = selectNetworks(networks, []string{"Base", "Ethereum"})
+	assert.ErrorContains(t, err, "usage")
+}
+
+func TestExpectationFor(t *testing.T) {
+	config.InitializeNetworks()
+	declaration := &deploystate.Declaration{ClassHash: "0x123", NetworkName: "Starknet"}
+
+	starknet, err := expectationFor(config.Networks["Starknet"], declaration)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(config.Networks["Starknet"].HyperlaneDomain), starknet.Domain)
+	assert.Equal(t, "0x123", starknet.ClassHash)
+
+	ztarknet, err := expectationFor(config.Networks["Ztarknet"], declaration)
+	require.NoError(t, err)
+	assert.Empty(t, ztarknet.ClassHash, "the declaration only covers the network it names")
+
+	base, err := expectationFor(config.Networks["Base"], nil)
+	require.NoError(t, err)
+	assert.Empty(t, base.ClassHash)
+}
+
+func TestDiffDeployment(t *testing.T) {
+	exp := expectation{Address: "0x7683", Domain: 84532, ClassHash: "0x0123"}
+
+	assert.Nil(t, diffDeployment(exp, deployment{HasCode: true, Domain: 84532, ClassHash: "0x123"}), "leading zeros do not matter")
+	assert.Equal(t, []string{"code: no contract deployed at 0x7683"}, diffDeployment(exp, deployment{}))
+
+	diffs := diffDeployment(exp, deployment{HasCode: true, Domain: 1, ClassHash: "0x456"})
+	require.Len(t, diffs, 2)
+	assert.Equal(t, "localDomain: want 84532, got 1", diffs[0])
+	assert.Contains(t, diffs[1], "class hash: want 0x0123")
+	assert.Contains(t, diffs[1], "got 0x456")
+}
+
+// fakeProvider answers the Starknet views by selector
+type fakeProvider struct {
+	classHash *felt.Felt
+	views     map[string]*felt.Felt
+}
+
+func (f fakeProvider) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	for name, value := range f.views {
+		if call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt(name)) {
+			return []*felt.Felt{value}, nil
+		}
+	}
+	return nil, rpc.ErrEntrypointNotFound
+}
+
+func (f fakeProvider) ClassHashAt(context.Context, rpc.BlockID, *felt.Felt) (*felt.Felt, error) {
+	if f.classHash == nil {
+		return nil, rpc.ErrContractNotFound
+	}
+	return f.classHash, nil
+}
+
+func TestStarknetReader(t *testing.T) {
+	address := new(felt.Felt).SetUint64(0x7683)
+	reader := &starknetReader{provider: fakeProvider{
+		classHash: new(felt.Felt).SetUint64(0x123),
+		views: map[string]*felt.Felt{
+			"get_local_domain": new(felt.Felt).SetUint64(23448591),
+			"owner":            new(felt.Felt).SetUint64(0xa11ce),
+		},
+	}, address: address}
+
+	d, err := reader.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, deployment{HasCode: true, Domain: 23448591, Owner: "0xa11ce", ClassHash: "0x123"}, d)
+	assert.Equal(t, "localDomain 23448591, owner 0xa11ce, class hash 0x123", describe(d))
+
+	d, err = (&starknetReader{provider: fakeProvider{}, address: address}).Read(context.Background())
+	require.NoError(t, err)
+	assert.False(t, d.HasCode, "a missing contract is reported as no code")
+
+	_, err = (&starknetReader{provider: fakeProvider{classHash: new(felt.Felt).SetUint64(1)}, address: address}).Read(context.Background())
+	assert.ErrorContains(t, err, "get_local_domain failed, is this Hyperlane7683?")
+}
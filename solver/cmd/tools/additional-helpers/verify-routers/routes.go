@@ -0,0 +1,128 @@
+package verifyrouters
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	registerevmrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/register-evm-routers"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// route is one (origin, destination) pair and the router and gas the origin should hold for it
+type route struct {
+	Origin      string
+	Destination string
+	Domain      uint32
+	Router      [32]byte
+	Gas         *big.Int
+}
+
+// normalizeRouter parses a router as a 32-byte word. EVM addresses and bytes32 values are left-padded and
+// felts are read as numbers, so "0x00..00abcd" and "0xabcd" compare equal
+func normalizeRouter(s string) ([32]byte, error) {
+	var out [32]byte
+	digits := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "0x"), "0X")
+	if digits == "" {
+		return out, fmt.Errorf("empty router address")
+	}
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return out, fmt.Errorf("invalid router address %q: %w", s, err)
+	}
+	b = []byte(strings.TrimLeft(string(b), "\x00"))
+	if len(b) > len(out) {
+		return out, fmt.Errorf("router address %q is longer than 32 bytes", s)
+	}
+	copy(out[len(out)-len(b):], b)
+	return out, nil
+}
+
+// formatRouter renders a router word as the 0x-prefixed 32-byte hex used in the diff
+func formatRouter(b [32]byte) string {
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+// expectedGas is the destination gas the register tools set on origin for destination: register-evm-routers'
+// constants on EVM origins, and each destination's configured DestinationGas on Starknet-type origins, as
+// register-sn-routers sets
+func expectedGas(origin string, destination config.NetworkConfig) *big.Int {
+	switch {
+	case config.IsStarknetNetwork(origin):
+		return new(big.Int).SetUint64(destination.DestinationGas)
+	case config.IsStarknetNetwork(destination.Name):
+		return big.NewInt(registerevmrouters.StarknetDestinationGas)
+	default:
+		return big.NewInt(registerevmrouters.EVMDestinationGas)
+	}
+}
+
+// expectedRoutes builds the routes every origin in networks should have enrolled, sorted by origin then
+// destination. EVM origins enroll every other network and Starknet-type origins also enroll themselves, as the
+// register tools do. Networks without a Hyperlane address are skipped and returned by name
+func expectedRoutes(networks map[string]config.NetworkConfig, origins []string) ([]route, []string, error) {
+	names := make([]string, 0, len(networks))
+	var skipped []string
+	for name, cfg := range networks {
+		if cfg.HyperlaneAddress == "" {
+			skipped = append(skipped, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.Strings(skipped)
+
+	if len(origins) == 0 {
+		origins = names
+	}
+
+	var routes []route
+	for _, origin := range origins {
+		if _, ok := networks[origin]; !ok {
+			return nil, nil, fmt.Errorf("unknown network %q", origin)
+		}
+		if networks[origin].HyperlaneAddress == "" {
+			continue
+		}
+		for _, destination := range names {
+			if destination == origin && !config.IsStarknetNetwork(origin) {
+				continue
+			}
+			cfg := networks[destination]
+			router, err := normalizeRouter(cfg.HyperlaneAddress)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", destination, err)
+			}
+			domain, err := cfg.Domain()
+			if err != nil {
+				return nil, nil, err
+			}
+			routes = append(routes, route{
+				Origin:      origin,
+				Destination: destination,
+				Domain:      domain,
+				Router:      router,
+				Gas:         expectedGas(origin, cfg),
+			})
+		}
+	}
+	return routes, skipped, nil
+}
+
+// diffRoute lists how the router and gas read back from the origin differ from r; nil means the route matches
+func diffRoute(r route, router [32]byte, gas *big.Int) []string {
+	var diffs []string
+	if router != r.Router {
+		diffs = append(diffs, fmt.Sprintf("router: want %s, got %s", formatRouter(r.Router), formatRouter(router)))
+	}
+	if gas == nil || gas.Cmp(r.Gas) != 0 {
+		diffs = append(diffs, fmt.Sprintf("destinationGas: want %s, got %v", r.Gas, gas))
+	}
+	return diffs
+}
@@ -0,0 +1,147 @@
+package verifyrouters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	evmHyperlane      = "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"
+	starknetHyperlane = "0x2a3bc4b7d6e5f0a1c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b"
+)
+
+func TestNormalizeRouter(t *testing.T) {
+	padded, err := normalizeRouter("0x000000000000000000000000f614c6bf94b022e16bef7dbecf7614ffd2b201d3")
+	require.NoError(t, err)
+	address, err := normalizeRouter(evmHyperlane)
+	require.NoError(t, err)
+	assert.Equal(t, padded, address, "EVM-padded bytes32 and the bare address are the same router")
+
+	short, err := normalizeRouter("0xabc")
+	require.NoError(t, err)
+	long, err := normalizeRouter("0x0000000000000000000000000000000000000000000000000000000000000abc")
+	require.NoError(t, err)
+	assert.Equal(t, long, short, "felts without leading zeros and odd-length hex are left-padded")
+
+	for _, bad := range []string{"", "0x", "0xzz"} {
+		_, err := normalizeRouter(bad)
+		assert.Error(t, err, bad)
+	}
+	_, err = normalizeRouter("0x01" + "0000000000000000000000000000000000000000000000000000000000000000")
+	assert.Error(t, err, "33 significant bytes do not fit a router")
+}
+
+func testNetworks() map[string]config.NetworkConfig {
+	return map[string]config.NetworkConfig{
+		"Ethereum": {Name: "Ethereum", HyperlaneAddress: evmHyperlane, HyperlaneDomain: 11155111, DestinationGas: 64000},
+		"Base":     {Name: "Base", HyperlaneAddress: evmHyperlane, HyperlaneDomain: 84532, DestinationGas: 70000},
+		"Starknet": {Name: "Starknet", HyperlaneAddress: starknetHyperlane, HyperlaneDomain: 23448591, DestinationGas: 100000},
+		"Ztarknet": {Name: "Ztarknet", HyperlaneDomain: 10066329},
+	}
+}
+
+func TestExpectedRoutes(t *testing.T) {
+	routes, skipped, err := expectedRoutes(testNetworks(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ztarknet"}, skipped)
+
+	type pair struct{ origin, destination string }
+	got := make(map[pair]route)
+	var order []pair
+	for _, r := range routes {
+		p := pair{r.Origin, r.Destination}
+		got[p] = r
+		order = append(order, p)
+	}
+	assert.Equal(t, []pair{
+		{"Base", "Ethereum"}, {"Base", "Starknet"},
+		{"Ethereum", "Base"}, {"Ethereum", "Starknet"},
+		{"Starknet", "Base"}, {"Starknet", "Ethereum"}, {"Starknet", "Starknet"},
+	}, order, "EVM origins skip themselves, Starknet enrolls itself")
+
+	starknetRouter, err := normalizeRouter(starknetHyperlane)
+	require.NoError(t, err)
+	assert.Equal(t, starknetRouter, got[pair{"Base", "Starknet"}].Router)
+	assert.Equal(t, uint32(23448591), got[pair{"Base", "Starknet"}].Domain)
+	assert.Equal(t, big.NewInt(250000), got[pair{"Base", "Starknet"}].Gas)
+	assert.Equal(t, big.NewInt(100000), got[pair{"Base", "Ethereum"}].Gas)
+	assert.Equal(t, big.NewInt(70000), got[pair{"Starknet", "Base"}].Gas, "Starknet origins use the destination's DestinationGas")
+	assert.Equal(t, big.NewInt(100000), got[pair{"Starknet", "Starknet"}].Gas)
+
+	t.Run("origin_filter", func(t *testing.T) {
+		routes, _, err := expectedRoutes(testNetworks(), []string{"Ethereum"})
+		require.NoError(t, err)
+		require.Len(t, routes, 2)
+		for _, r := range routes {
+			assert.Equal(t, "Ethereum", r.Origin)
+		}
+	})
+
+	t.Run("unknown_origin", func(t *testing.T) {
+		_, _, err := expectedRoutes(testNetworks(), []string{"Mars"})
+		assert.ErrorContains(t, err, `unknown network "Mars"`)
+	})
+}
+
+func TestDiffRoute(t *testing.T) {
+	router, err := normalizeRouter(evmHyperlane)
+	require.NoError(t, err)
+	r := route{Origin: "Base", Destination: "Ethereum", Domain: 11155111, Router: router, Gas: big.NewInt(100000)}
+
+	assert.Empty(t, diffRoute(r, router, big.NewInt(100000)))
+
+	diffs := diffRoute(r, [32]byte{}, big.NewInt(64000))
+	require.Len(t, diffs, 2)
+	assert.Contains(t, diffs[0], "router: want 0x000000000000000000000000f614c6bf94b022e16bef7dbecf7614ffd2b201d3, got 0x0000")
+	assert.Equal(t, "destinationGas: want 100000, got 64000", diffs[1])
+
+	assert.Len(t, diffRoute(r, router, nil), 1)
+}
+
+// u256Caller answers every view with a fixed u256 per entry point
+type u256Caller struct {
+	values map[string]*big.Int
+}
+
+func (c *u256Caller) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	for name, value := range c.values {
+		if call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt(name)) {
+			low := new(big.Int).And(value, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+			high := new(big.Int).Rsh(value, 128)
+			return []*felt.Felt{utils.BigIntToFelt(low), utils.BigIntToFelt(high)}, nil
+		}
+	}
+	return []*felt.Felt{}, nil
+}
+
+func TestStarknetReader(t *testing.T) {
+	want, err := normalizeRouter(starknetHyperlane)
+	require.NoError(t, err)
+	address, err := utils.HexToFelt(starknetHyperlane)
+	require.NoError(t, err)
+
+	reader := &starknetReader{
+		caller: &u256Caller{values: map[string]*big.Int{
+			"routers":         new(big.Int).SetBytes(want[:]),
+			"destination_gas": big.NewInt(100000),
+		}},
+		address: address,
+	}
+
+	diffs, err := checkRoute(context.Background(), reader, route{Destination: "Starknet", Domain: 23448591, Router: want, Gas: big.NewInt(100000)})
+	require.NoError(t, err)
+	assert.Empty(t, diffs, "a felt router read back as u256 matches the configured address")
+
+	reader.caller = &u256Caller{values: map[string]*big.Int{"routers": big.NewInt(1)}}
+	_, err = checkRoute(context.Background(), reader, route{Domain: 1, Router: want, Gas: big.NewInt(1)})
+	assert.ErrorContains(t, err, "destination_gas returned 0 felts")
+}
@@ -0,0 +1,205 @@
+package verifyrouters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+const (
+	// Timeout for each view call
+	callTimeout = 15 * time.Second
+)
+
+// Reads back the routers and destination gas enrolled on every Hyperlane7683 in config and diffs them against
+// what register-evm-routers and register-sn-routers set. Fails on any mismatch or failed read.
+//
+// Usage: verify-routers [network...]   (defaults to every network with a Hyperlane address)
+
+// routeReader reads the enrolled router and destination gas for a domain from one origin contract
+type routeReader interface {
+	Router(ctx context.Context, domain uint32) ([32]byte, error)
+	DestinationGas(ctx context.Context, domain uint32) (*big.Int, error)
+	Close()
+}
+
+// Run checks the routes of the networks in args, or of every network with a Hyperlane address
+func Run(ctx context.Context, args []string) error {
+	return Check(ctx, args, os.Stdout)
+}
+
+// Check is Run writing the per-route report to w
+func Check(ctx context.Context, args []string, w io.Writer) error {
+	if err := solverdir.LoadEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v, using environment variables\n", err)
+	}
+
+	config.InitializeNetworks()
+
+	routes, skipped, err := expectedRoutes(config.Networks, args)
+	if err != nil {
+		return err
+	}
+	for _, name := range skipped {
+		fmt.Fprintf(w, "⏭️  Skipping %s: no Hyperlane address configured\n", name)
+	}
+
+	readers := make(map[string]routeReader)
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+
+	failures := 0
+	origin := ""
+	for _, r := range routes {
+		if r.Origin != origin {
+			origin = r.Origin
+			fmt.Fprintf(w, "\n🔍 %s (%s)\n", origin, config.Networks[origin].HyperlaneAddress)
+		}
+
+		reader, ok := readers[origin]
+		if !ok {
+			reader, err = newRouteReader(config.Networks[origin])
+			if err != nil {
+				fmt.Fprintf(w, "   ❌ %v\n", err)
+				failures++
+				continue
+			}
+			readers[origin] = reader
+		}
+
+		diffs, err := checkRoute(ctx, reader, r)
+		switch {
+		case err != nil:
+			fmt.Fprintf(w, "   ❌ %s (domain %d): %v\n", r.Destination, r.Domain, err)
+			failures++
+		case len(diffs) > 0:
+			fmt.Fprintf(w, "   ❌ %s (domain %d)\n", r.Destination, r.Domain)
+			for _, d := range diffs {
+				fmt.Fprintf(w, "      %s\n", d)
+			}
+			failures++
+		default:
+			fmt.Fprintf(w, "   ✅ %s (domain %d): router %s, gas %s\n", r.Destination, r.Domain, formatRouter(r.Router), r.Gas)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d routes do not match config", failures, len(routes))
+	}
+	fmt.Fprintf(w, "\n✅ All %d routes match config\n", len(routes))
+	return nil
+}
+
+// checkRoute reads r's router and gas from reader and diffs them
+func checkRoute(ctx context.Context, reader routeReader, r route) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	router, err := reader.Router(ctx, r.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read router: %w", err)
+	}
+	gas, err := reader.DestinationGas(ctx, r.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read destination gas: %w", err)
+	}
+	return diffRoute(r, router, gas), nil
+}
+
+// newRouteReader connects to the network's RPC with the reader matching its contract
+func newRouteReader(cfg config.NetworkConfig) (routeReader, error) {
+	if config.IsStarknetNetwork(cfg.Name) {
+		provider, err := rpc.NewProvider(cfg.RPCURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", cfg.RPCURL, err)
+		}
+		address, err := utils.HexToFelt(cfg.HyperlaneAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Hyperlane address %s: %w", cfg.HyperlaneAddress, err)
+		}
+		return &starknetReader{caller: provider, address: address}, nil
+	}
+
+	client, err := ethclient.Dial(cfg.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.RPCURL, err)
+	}
+	caller, err := contracts.NewHyperlane7683Caller(common.HexToAddress(cfg.HyperlaneAddress), client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to bind Hyperlane7683: %w", err)
+	}
+	return &evmReader{client: client, caller: caller}, nil
+}
+
+// evmReader reads routers(domain) and destinationGas(domain) through the generated bindings
+type evmReader struct {
+	client *ethclient.Client
+	caller *contracts.Hyperlane7683Caller
+}
+
+func (r *evmReader) Router(ctx context.Context, domain uint32) ([32]byte, error) {
+	return r.caller.Routers(&bind.CallOpts{Context: ctx}, domain)
+}
+
+func (r *evmReader) DestinationGas(ctx context.Context, domain uint32) (*big.Int, error) {
+	return r.caller.DestinationGas(&bind.CallOpts{Context: ctx}, domain)
+}
+
+func (r *evmReader) Close() { r.client.Close() }
+
+// starknetReader reads the routers(domain) and destination_gas(domain) views, both returning a u256
+type starknetReader struct {
+	caller  starknetutil.ContractCaller
+	address *felt.Felt
+}
+
+func (r *starknetReader) Router(ctx context.Context, domain uint32) ([32]byte, error) {
+	var out [32]byte
+	value, err := r.callU256(ctx, "routers", domain)
+	if err != nil {
+		return out, err
+	}
+	value.FillBytes(out[:])
+	return out, nil
+}
+
+func (r *starknetReader) DestinationGas(ctx context.Context, domain uint32) (*big.Int, error) {
+	return r.callU256(ctx, "destination_gas", domain)
+}
+
+func (r *starknetReader) Close() {}
+
+// callU256 calls a view taking a domain and returning a u256
+func (r *starknetReader) callU256(ctx context.Context, function string, domain uint32) (*big.Int, error) {
+	resp, err := r.caller.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    r.address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           []*felt.Felt{utils.Uint64ToFelt(uint64(domain))},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("%s(%d) failed: %w", function, domain, err)
+	}
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("%s returned %d felts, expected 2", function, len(resp))
+	}
+	return starknetutil.FromU256(resp[0], resp[1]), nil
+}
@@ -0,0 +1,336 @@
+package balances
+
+// Balances tool: a read-only matrix of Alice's and the solver's token balances on every configured network,
+// with each account's allowance toward that network's Hyperlane7683
+// Only addresses are read from env, so no private keys are needed. The networks are queried in parallel and
+// every RPC call has its own timeout; a call that fails or times out shows as n/a instead of aborting the run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	// JSONFlag prints the matrix as JSON instead of a table
+	JSONFlag = "--json"
+	// TimeoutFlag bounds each RPC call
+	TimeoutFlag = "--timeout"
+
+	// DefaultCallTimeout is the per-call timeout without --timeout
+	DefaultCallTimeout = 5 * time.Second
+
+	// notAvailable marks a cell whose value could not be read
+	notAvailable = "n/a"
+)
+
+// balancesRequest is a parsed balances command
+type balancesRequest struct {
+	JSON        bool
+	CallTimeout time.Duration
+}
+
+// Cell is one value of the matrix: the raw amount and its decimal form, or the error that left it n/a
+type Cell struct {
+	Amount    string `json:"amount,omitempty"`
+	Formatted string `json:"formatted,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Row is one account's balance of one token, and its allowance toward the network's Hyperlane7683
+type Row struct {
+	Network   string `json:"network"`
+	Token     string `json:"token"`
+	TokenAddr string `json:"tokenAddress,omitempty"`
+	Account   string `json:"account"`
+	Address   string `json:"address,omitempty"`
+	Spender   string `json:"spender,omitempty"`
+	Balance   Cell   `json:"balance"`
+	Allowance Cell   `json:"allowance"`
+}
+
+// account is a named holder address on one network; an empty address is not configured
+type account struct {
+	Name    string
+	Address string
+}
+
+// tokenReader reads token state on one network
+type tokenReader interface {
+	Balance(ctx context.Context, token, holder string) (*big.Int, error)
+	Allowance(ctx context.Context, token, holder, spender string) (*big.Int, error)
+	Decimals(ctx context.Context, token string) (uint8, error)
+}
+
+// RunBalances runs `balances [--json] [--timeout <duration>]`
+func RunBalances(ctx context.Context, args []string) {
+	req, err := parseBalancesArgs(args)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := config.LoadConfig(); err != nil {
+		fmt.Printf("❌ failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	rows := collectRows(ctx, config.GetNetworkNames(), networkInputs, req.CallTimeout)
+	if req.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rows); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	renderTable(os.Stdout, rows)
+}
+
+func parseBalancesArgs(args []string) (balancesRequest, error) {
+	req := balancesRequest{CallTimeout: DefaultCallTimeout}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == JSONFlag:
+			req.JSON = true
+		case arg == TimeoutFlag || strings.HasPrefix(arg, TimeoutFlag+"="):
+			value, ok := strings.CutPrefix(arg, TimeoutFlag+"=")
+			if !ok {
+				if i+1 >= len(args) {
+					return balancesRequest{}, fmt.Errorf("%s requires a duration", TimeoutFlag)
+				}
+				i++
+				value = args[i]
+			}
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return balancesRequest{}, fmt.Errorf("invalid %s %q: expected a positive duration such as 5s", TimeoutFlag, value)
+			}
+			req.CallTimeout = timeout
+		default:
+			return balancesRequest{}, fmt.Errorf("unexpected argument: %s (usage: balances [%s] [%s <duration>])", arg, JSONFlag, TimeoutFlag)
+		}
+	}
+	return req, nil
+}
+
+// networkInput is what is read for one network: who holds, which tokens, the spender and how to read them
+type networkInput struct {
+	Accounts []account
+	Tokens   []openorder.KnownToken
+	Spender  string
+	Reader   tokenReader
+	Close    func()
+}
+
+// networkInputs resolves the accounts, tokens and reader of a configured network; replaced in tests
+var networkInputs = func(networkName string) (networkInput, error) {
+	network, ok := config.Networks[networkName]
+	if !ok {
+		return networkInput{}, fmt.Errorf("network %s not found in config", networkName)
+	}
+	input := networkInput{
+		Accounts: accountsFor(networkName),
+		Tokens:   openorder.KnownTokens(networkName),
+		Spender:  network.HyperlaneAddress,
+		Close:    func() {},
+	}
+
+	if openorder.GetNetworkType(networkName) == openorder.NetworkTypeEVM {
+		// Dialing an HTTP endpoint does not connect, so an unreachable RPC surfaces on the first call
+		client, err := ethclient.Dial(network.RPCURL)
+		if err != nil {
+			return networkInput{}, fmt.Errorf("failed to connect to %s: %w", networkName, err)
+		}
+		input.Reader, input.Close = evmReader{client: client}, client.Close
+		return input, nil
+	}
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return networkInput{}, fmt.Errorf("failed to connect to %s: %w", networkName, err)
+	}
+	input.Reader = starknetReader{provider: provider}
+	return input, nil
+}
+
+// accountsFor returns Alice's and the solver's addresses on a network
+func accountsFor(networkName string) []account {
+	switch openorder.GetNetworkType(networkName) {
+	case openorder.NetworkTypeStarknet:
+		return []account{{"Alice", envutil.GetStarknetAliceAddress()}, {"Solver", envutil.GetStarknetSolverAddress()}}
+	case openorder.NetworkTypeZtarknet:
+		return []account{{"Alice", envutil.GetZtarknetAliceAddress()}, {"Solver", envutil.GetZtarknetSolverAddress()}}
+	default:
+		var accounts []account
+		for _, r := range envutil.GetEVMRecipients() {
+			accounts = append(accounts, account{r.Name, r.Address.Hex()})
+		}
+		return accounts
+	}
+}
+
+// collectRows queries every network in parallel and returns the rows sorted by network, token and account
+func collectRows(ctx context.Context, networks []string, inputs func(string) (networkInput, error), callTimeout time.Duration) []Row {
+	var (
+		mu   sync.Mutex
+		rows []Row
+		wg   sync.WaitGroup
+	)
+	for _, networkName := range networks {
+		wg.Add(1)
+		go func(networkName string) {
+			defer wg.Done()
+			networkRows := collectNetwork(ctx, networkName, inputs, callTimeout)
+			mu.Lock()
+			rows = append(rows, networkRows...)
+			mu.Unlock()
+		}(networkName)
+	}
+	wg.Wait()
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Network != rows[j].Network {
+			return rows[i].Network < rows[j].Network
+		}
+		if rows[i].Token != rows[j].Token {
+			return rows[i].Token < rows[j].Token
+		}
+		return rows[i].Account < rows[j].Account
+	})
+	return rows
+}
+
+// collectNetwork reads every account × token cell of one network
+func collectNetwork(ctx context.Context, networkName string, inputs func(string) (networkInput, error), callTimeout time.Duration) []Row {
+	input, err := inputs(networkName)
+	if err != nil {
+		return []Row{{Network: networkName, Token: "-", Account: "-", Balance: unavailable(err), Allowance: unavailable(err)}}
+	}
+	defer input.Close()
+
+	tokens := input.Tokens
+	if len(tokens) == 0 {
+		tokens = []openorder.KnownToken{{Symbol: openorder.DefaultOrderToken}}
+	}
+
+	var rows []Row
+	for _, token := range tokens {
+		decimals := -1
+		if token.Address != "" {
+			if d, err := withTimeout(ctx, callTimeout, func(ctx context.Context) (uint8, error) {
+				return input.Reader.Decimals(ctx, token.Address)
+			}); err == nil {
+				decimals = int(d)
+			}
+		}
+
+		for _, acct := range input.Accounts {
+			row := Row{Network: networkName, Token: token.Symbol, TokenAddr: token.Address, Account: acct.Name, Address: acct.Address, Spender: input.Spender}
+			row.Balance, row.Allowance = readCells(ctx, input, token.Address, acct.Address, decimals, callTimeout)
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// readCells reads one account's balance of token and its allowance toward the spender
+func readCells(ctx context.Context, input networkInput, token, holder string, decimals int, callTimeout time.Duration) (balance, allowance Cell) {
+	switch {
+	case token == "":
+		err := errors.New("token address not configured")
+		return unavailable(err), unavailable(err)
+	case holder == "":
+		err := errors.New("account address not configured")
+		return unavailable(err), unavailable(err)
+	}
+
+	amount, err := withTimeout(ctx, callTimeout, func(ctx context.Context) (*big.Int, error) {
+		return input.Reader.Balance(ctx, token, holder)
+	})
+	balance = toCell(amount, err, decimals)
+	if input.Spender == "" {
+		return balance, unavailable(errors.New("Hyperlane7683 address not configured"))
+	}
+	amount, err = withTimeout(ctx, callTimeout, func(ctx context.Context) (*big.Int, error) {
+		return input.Reader.Allowance(ctx, token, holder, input.Spender)
+	})
+	return balance, toCell(amount, err, decimals)
+}
+
+// withTimeout runs one call under its own timeout
+func withTimeout[T any](ctx context.Context, timeout time.Duration, call func(context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return call(ctx)
+}
+
+// toCell formats an amount with the token decimals (raw when they are unknown, -1)
+func toCell(amount *big.Int, err error, decimals int) Cell {
+	if err != nil {
+		return unavailable(err)
+	}
+	cell := Cell{Amount: amount.String(), Formatted: amount.String()}
+	switch {
+	case amount.Cmp(starknetutil.MaxU256) == 0:
+		cell.Formatted = "unlimited"
+	case decimals >= 0:
+		cell.Formatted = ethutil.FormatTokenAmount(amount, decimals)
+	}
+	return cell
+}
+
+func unavailable(err error) Cell {
+	return Cell{Error: err.Error()}
+}
+
+// evmReader reads ERC20 state through an ethclient
+type evmReader struct {
+	client *ethclient.Client
+}
+
+func (r evmReader) Balance(ctx context.Context, token, holder string) (*big.Int, error) {
+	return ethutil.ERC20BalanceAt(ctx, r.client, common.HexToAddress(token), common.HexToAddress(holder), nil)
+}
+
+func (r evmReader) Allowance(ctx context.Context, token, holder, spender string) (*big.Int, error) {
+	return ethutil.ERC20AllowanceAt(ctx, r.client, common.HexToAddress(token), common.HexToAddress(holder), common.HexToAddress(spender), nil)
+}
+
+func (r evmReader) Decimals(ctx context.Context, token string) (uint8, error) {
+	return ethutil.ERC20Decimals(ctx, r.client, common.HexToAddress(token))
+}
+
+// starknetReader reads ERC20 state on Starknet and Ztarknet
+type starknetReader struct {
+	provider starknetutil.ContractCaller
+}
+
+func (r starknetReader) Balance(ctx context.Context, token, holder string) (*big.Int, error) {
+	return starknetutil.ERC20Balance(ctx, r.provider, token, holder)
+}
+
+func (r starknetReader) Allowance(ctx context.Context, token, holder, spender string) (*big.Int, error) {
+	return starknetutil.ERC20Allowance(ctx, r.provider, token, holder, spender)
+}
+
+func (r starknetReader) Decimals(ctx context.Context, token string) (uint8, error) {
+	return starknetutil.ERC20Decimals(ctx, r.provider, token)
+}
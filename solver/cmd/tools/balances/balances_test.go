@@ -0,0 +1,145 @@
+package balances
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// fakeReader answers from maps keyed by holder; with hang set every call blocks until its context expires
+type fakeReader struct {
+	balances   map[string]*big.Int
+	allowances map[string]*big.Int
+	decimals   uint8
+	hang       bool
+}
+
+func (f fakeReader) wait(ctx context.Context) error {
+	if f.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (f fakeReader) Balance(ctx context.Context, _, holder string) (*big.Int, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.balances[holder], nil
+}
+
+func (f fakeReader) Allowance(ctx context.Context, _, holder, _ string) (*big.Int, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return f.allowances[holder], nil
+}
+
+func (f fakeReader) Decimals(ctx context.Context, _ string) (uint8, error) {
+	if err := f.wait(ctx); err != nil {
+		return 0, err
+	}
+	return f.decimals, nil
+}
+
+func testInputs() func(string) (networkInput, error) {
+	accounts := []account{{"Alice", "0xa11ce"}, {"Solver", "0x5017e7"}}
+	dogCoin := []openorder.KnownToken{{Symbol: "DogCoin", Address: "0xd06"}}
+	return func(networkName string) (networkInput, error) {
+		input := networkInput{Accounts: accounts, Tokens: dogCoin, Spender: "0x4e7", Close: func() {}}
+		switch networkName {
+		case "Base":
+			input.Reader = fakeReader{
+				balances:   map[string]*big.Int{"0xa11ce": big.NewInt(1_500_000), "0x5017e7": big.NewInt(0)},
+				allowances: map[string]*big.Int{"0xa11ce": starknetutil.MaxU256, "0x5017e7": big.NewInt(0)},
+				decimals:   6,
+			}
+		case "Arbitrum":
+			input.Reader = fakeReader{hang: true}
+		case "Ztarknet":
+			input.Tokens = nil
+			input.Reader = fakeReader{}
+		default:
+			return networkInput{}, errors.New("failed to connect to " + networkName)
+		}
+		return input, nil
+	}
+}
+
+func TestCollectRows(t *testing.T) {
+	start := time.Now()
+	rows := collectRows(context.Background(), []string{"Ztarknet", "Base", "Optimism", "Arbitrum"}, testInputs(), 50*time.Millisecond)
+	// The hanging network costs one timeout per call: decimals plus two calls per account
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	byKey := make(map[string]Row)
+	var order []string
+	for _, row := range rows {
+		byKey[row.Network+"/"+row.Account] = row
+		order = append(order, row.Network+"/"+row.Account)
+	}
+	assert.Equal(t, []string{"Arbitrum/Alice", "Arbitrum/Solver", "Base/Alice", "Base/Solver", "Optimism/-", "Ztarknet/Alice", "Ztarknet/Solver"}, order)
+
+	alice := byKey["Base/Alice"]
+	assert.Equal(t, Cell{Amount: "1500000", Formatted: "1.50 tokens"}, alice.Balance)
+	assert.Equal(t, "unlimited", alice.Allowance.Formatted)
+	assert.Equal(t, "0x4e7", alice.Spender)
+
+	assert.Contains(t, byKey["Arbitrum/Solver"].Balance.Error, "deadline exceeded")
+	assert.Equal(t, "failed to connect to Optimism", byKey["Optimism/-"].Allowance.Error)
+	assert.Equal(t, "token address not configured", byKey["Ztarknet/Alice"].Balance.Error)
+}
+
+func TestRenderTable(t *testing.T) {
+	rows := collectRows(context.Background(), []string{"Base", "Optimism"}, testInputs(), time.Second)
+
+	var buf bytes.Buffer
+	renderTable(&buf, rows)
+	out := buf.String()
+
+	assert.Contains(t, out, "NETWORK")
+	assert.Regexp(t, `Base\s+DogCoin\s+Alice\s+1\.50 tokens\s+unlimited`, out)
+	assert.Regexp(t, `Optimism\s+-\s+-\s+n/a\s+n/a`, out)
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("⚠️  Optimism: failed to connect to Optimism")), "each reason is listed once")
+}
+
+func TestParseBalancesArgs(t *testing.T) {
+	req, err := parseBalancesArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, balancesRequest{CallTimeout: DefaultCallTimeout}, req)
+
+	req, err = parseBalancesArgs([]string{"--json", "--timeout", "2s"})
+	require.NoError(t, err)
+	assert.Equal(t, balancesRequest{JSON: true, CallTimeout: 2 * time.Second}, req)
+
+	req, err = parseBalancesArgs([]string{"--timeout=500ms"})
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, req.CallTimeout)
+
+	_, err = parseBalancesArgs([]string{"--timeout", "-1s"})
+	assert.ErrorContains(t, err, "invalid --timeout")
+	_, err = parseBalancesArgs([]string{"--timeout"})
+	assert.ErrorContains(t, err, "requires a duration")
+	_, err = parseBalancesArgs([]string{"base"})
+	assert.ErrorContains(t, err, "unexpected argument: base")
+}
+
+func TestAccountsFor(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("STARKNET_SOLVER_ADDRESS", "0x5")
+	t.Setenv("ZTARKNET_ALICE_ADDRESS", "")
+
+	assert.Equal(t, account{"Solver", "0x5"}, accountsFor("Starknet")[1])
+	assert.Equal(t, account{"Alice", ""}, accountsFor("Ztarknet")[0], "a missing address is kept so its cells show n/a")
+	assert.Len(t, accountsFor("Base"), 2)
+}
@@ -0,0 +1,44 @@
+package balances
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// renderTable prints the rows as an aligned table, one line per network, token and account
+func renderTable(w io.Writer, rows []Row) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "📭 No networks configured")
+		return
+	}
+
+	fmt.Fprintln(w, "💰 Balances and allowances toward Hyperlane7683")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NETWORK\tTOKEN\tACCOUNT\tBALANCE\tALLOWANCE")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", row.Network, row.Token, row.Account, row.Balance.String(), row.Allowance.String())
+	}
+	tw.Flush()
+
+	// The reasons behind n/a cells, once per distinct error
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for _, cell := range []Cell{row.Balance, row.Allowance} {
+			key := row.Network + ": " + cell.Error
+			if cell.Error == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			fmt.Fprintf(w, "   ⚠️  %s\n", key)
+		}
+	}
+}
+
+// String is the cell as shown in the table
+func (c Cell) String() string {
+	if c.Error != "" {
+		return notAvailable
+	}
+	return c.Formatted
+}
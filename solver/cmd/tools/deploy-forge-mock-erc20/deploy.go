@@ -0,0 +1,514 @@
+package deployforgemockerc20
+
+// Deploy tool: deploys the MockERC20 tokens of the token spec on the EVM networks from the Forge artifact, at a
+// nonce-based address or through the CREATE2 proxy with a fixed salt, records them in the deployment state and
+// verifies them on the live networks (see verify.go)
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+const (
+	// concurrencyFlag bounds how many networks are deployed to at once
+	concurrencyFlag = "--concurrency"
+	// failFastFlag cancels the other deployments after the first failure
+	failFastFlag = "--fail-fast"
+	// saltFlag deploys through the CREATE2 proxy with a fixed salt, so a token keeps its address across fork
+	// restarts and networks; deploySaltEnv sets it without the flag
+	saltFlag      = "--salt"
+	deploySaltEnv = "EVM_DEPLOY_SALT"
+	// predictOnlyFlag prints the CREATE2 addresses without deploying
+	predictOnlyFlag = "--predict-only"
+)
+
+// NetworkInfo contains deployment information for each network
+type NetworkInfo struct {
+	Name    string
+	ChainID string
+	// Network is the config network name, which prefixes the token address variables (e.g. BASE_DOG_COIN_ADDRESS)
+	Network string
+	// APIKeyEnv is the explorer API key variable foundry.toml reads for the network, used when ETHERSCAN_API_KEY
+	// is unset
+	APIKeyEnv string
+}
+
+// Networks are the networks the tokens are deployed to
+var Networks = []NetworkInfo{
+	{
+		Name:      "Ethereum Sepolia",
+		ChainID:   "11155111",
+		Network:   "Ethereum",
+		APIKeyEnv: "API_KEY_ETHERSCAN",
+	},
+	{
+		Name:      "Optimism Sepolia",
+		ChainID:   "11155420",
+		Network:   "Optimism",
+		APIKeyEnv: "API_KEY_OPTIMISTIC_ETHERSCAN",
+	},
+	{
+		Name:      "Arbitrum Sepolia",
+		ChainID:   "421614",
+		Network:   "Arbitrum",
+		APIKeyEnv: "API_KEY_ARBISCAN",
+	},
+	{
+		Name:      "Base Sepolia",
+		ChainID:   "84532",
+		Network:   "Base",
+		APIKeyEnv: "API_KEY_BASESCAN",
+	},
+}
+
+// Run deploys the token spec's MockERC20 tokens as described by args: [network] [--write-env] [--concurrency N]
+// [--fail-fast] [--salt N] [--predict-only]. It fails if any network failed
+func Run(ctx context.Context, args []string) error {
+	if err := solverdir.LoadEnv(); err != nil {
+		return err
+	}
+
+	opts, err := parseArgs(args, Networks)
+	if err != nil {
+		return err
+	}
+	targetNetworks := opts.Networks
+
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return err
+	}
+
+	// Compile when Forge is installed so the artifact matches the sources; without it the committed build is used
+	if _, err := exec.LookPath("forge"); err == nil {
+		if err := buildWithForge(ctx); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("ℹ️  forge not found, deploying the existing %s\n", tokenspec.MockERC20Artifact)
+	}
+
+	if opts.PredictOnly {
+		fmt.Printf("🔮 CREATE2 addresses on every network (salt %s, nothing is sent):\n", common.Hash(*opts.Salt).Hex())
+		for _, spec := range specs {
+			address, err := tokenspec.PredictERC20Address(spec, *opts.Salt)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("   %s: %s\n", spec.Name, address.Hex())
+		}
+		return nil
+	}
+
+	bytecode, err := tokenspec.LoadMockERC20Bytecode(solverdir.Path(tokenspec.MockERC20Artifact))
+	if err != nil {
+		return err
+	}
+	deploy := deployWithNonce(bytecode)
+	if opts.Salt != nil {
+		deploy = deployWithCreate2(bytecode, *opts.Salt)
+		fmt.Printf("🚀 Deploying %d MockERC20 token(s) with CREATE2 (salt %s) to %d network(s), %d at a time...\n\n",
+			len(specs), common.Hash(*opts.Salt).Hex(), len(targetNetworks), opts.Concurrency)
+	} else {
+		fmt.Printf("🚀 Deploying %d MockERC20 token(s) to %d network(s), %d at a time...\n", len(specs), len(targetNetworks), opts.Concurrency)
+		fmt.Printf("   These will have matching compiler settings for verification!\n\n")
+	}
+
+	results := deployAll(ctx, targetNetworks, specs, opts.Concurrency, opts.FailFast, deploy)
+
+	successCount := 0
+	var deployedAddresses []string
+	envUpdates := make(map[string]string)
+
+	fmt.Printf("\n🎯 Deployment Summary:\n")
+	for _, result := range results {
+		network := result.Network
+		// Record what was deployed even when a later token failed, so a rerun only has to redo the rest
+		if opts.Salt != nil {
+			for i := range result.Tokens {
+				result.Tokens[i].Salt = common.Hash(*opts.Salt).Hex()
+			}
+		}
+		if len(result.Tokens) > 0 {
+			if _, err := tokenspec.RecordDeployment(network.Network, result.Tokens); err != nil {
+				fmt.Printf("   ⚠️  %s: failed to record deployment: %v\n", network.Name, err)
+			}
+		}
+		for _, token := range result.Tokens {
+			envVar := tokenspec.EnvName(network.Network, token.Name)
+			fmt.Printf("   ✅ %s %s: %s\n", network.Name, token.Symbol, token.Address)
+			fmt.Printf("      Explorer: %s\n", getExplorerURL(network.ChainID, token.Address))
+
+			deployedAddresses = append(deployedAddresses, fmt.Sprintf("%s=%s", envVar, token.Address))
+			envUpdates[envVar] = token.Address
+		}
+		if result.Err != nil {
+			fmt.Printf("   ❌ %s: %v\n", network.Name, result.Err)
+			continue
+		}
+		successCount++
+	}
+	fmt.Printf("   Successful: %d/%d\n", successCount, len(targetNetworks))
+
+	if len(deployedAddresses) > 0 {
+		if opts.WriteEnv {
+			if err := envutil.UpdateEnvFile(solverdir.EnvFile(), envUpdates); err != nil {
+				return fmt.Errorf("failed to update .env: %w", err)
+			}
+			fmt.Printf("\n📝 Updated .env with the new addresses:\n")
+		} else {
+			fmt.Printf("\n📝 Update your .env file with these new addresses (or rerun with %s):\n", envutil.WriteEnvFlag)
+		}
+		for _, addr := range deployedAddresses {
+			fmt.Printf("   %s\n", addr)
+		}
+		verifyDeployed(ctx, results, specs)
+	}
+	if successCount < len(targetNetworks) {
+		return fmt.Errorf("deployed to %d of %d networks", successCount, len(targetNetworks))
+	}
+	return nil
+}
+
+// options is the parsed command line
+type options struct {
+	Networks    []NetworkInfo
+	Concurrency int
+	FailFast    bool
+	WriteEnv    bool
+	Salt        *[32]byte // nil deploys at a nonce-based address
+	PredictOnly bool
+}
+
+// parseArgs parses [network] [--write-env] [--concurrency N] [--fail-fast] [--salt N] [--predict-only]. Without
+// a network every network is deployed to, and the concurrency defaults to the number of networks. The salt
+// defaults to EVM_DEPLOY_SALT, and predicting requires one
+func parseArgs(args []string, networks []NetworkInfo) (options, error) {
+	var opts options
+	var networkArg string
+	saltArg := strings.TrimSpace(os.Getenv(deploySaltEnv))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == predictOnlyFlag:
+			opts.PredictOnly = true
+		case arg == saltFlag || strings.HasPrefix(arg, saltFlag+"="):
+			value, ok := strings.CutPrefix(arg, saltFlag+"=")
+			if !ok {
+				if i+1 >= len(args) {
+					return options{}, fmt.Errorf("%s requires a value", saltFlag)
+				}
+				i++
+				value = args[i]
+			}
+			saltArg = strings.TrimSpace(value)
+		case arg == envutil.WriteEnvFlag:
+			// --write-env persists the deployed addresses to .env instead of only printing them
+			opts.WriteEnv = true
+		case arg == failFastFlag:
+			opts.FailFast = true
+		case arg == concurrencyFlag || strings.HasPrefix(arg, concurrencyFlag+"="):
+			value, ok := strings.CutPrefix(arg, concurrencyFlag+"=")
+			if !ok {
+				if i+1 >= len(args) {
+					return options{}, fmt.Errorf("%s requires a number", concurrencyFlag)
+				}
+				i++
+				value = args[i]
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return options{}, fmt.Errorf("invalid %s %q: expected a positive number", concurrencyFlag, value)
+			}
+			opts.Concurrency = n
+		case strings.HasPrefix(arg, "--") || networkArg != "":
+			return options{}, fmt.Errorf("unexpected argument: %s", arg)
+		default:
+			networkArg = arg
+		}
+	}
+
+	selected, err := selectNetworks(networkArg, networks)
+	if err != nil {
+		return options{}, err
+	}
+	opts.Networks = selected
+	if opts.Concurrency == 0 {
+		opts.Concurrency = len(opts.Networks)
+	}
+	if saltArg != "" {
+		salt, err := ethutil.ParseSalt(saltArg)
+		if err != nil {
+			return options{}, err
+		}
+		opts.Salt = &salt
+	}
+	if opts.PredictOnly && opts.Salt == nil {
+		return options{}, fmt.Errorf("%s needs a fixed salt: pass %s or set %s", predictOnlyFlag, saltFlag, deploySaltEnv)
+	}
+	return opts, nil
+}
+
+// selectNetworks returns the network whose name contains name, or every network when name is empty
+func selectNetworks(name string, networks []NetworkInfo) ([]NetworkInfo, error) {
+	if name == "" {
+		return networks, nil
+	}
+	lower := strings.ToLower(name)
+	for _, network := range networks {
+		if strings.Contains(strings.ToLower(network.Name), lower) {
+			return []NetworkInfo{network}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid network name: %s. Available: ethereum, optimism, arbitrum, base", name)
+}
+
+// deployment is the outcome of deploying to one network: the tokens deployed, and the error that stopped it
+type deployment struct {
+	Network NetworkInfo
+	Tokens  []tokenspec.DeployedToken
+	Err     error
+}
+
+// deployFunc deploys one token to a chain and returns its address
+type deployFunc func(ctx context.Context, chainID string, spec tokenspec.TokenSpec) (string, error)
+
+// deployAll deploys every token to every network, at most concurrency networks at a time, and returns the outcomes
+// in the order of networks. The tokens of a network are deployed one after the other, as they share the
+// deployer's nonce. A failure only fails its own network unless failFast is set, in which case it cancels the
+// deployments still running and skips those not started
+func deployAll(ctx context.Context, networks []NetworkInfo, specs []tokenspec.TokenSpec, concurrency int, failFast bool, deploy deployFunc) []deployment {
+	results := make([]deployment, len(networks))
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	if failFast {
+		ctx = groupCtx
+	}
+
+	for i, network := range networks {
+		g.Go(func() error {
+			results[i].Network = network
+			if err := ctx.Err(); err != nil {
+				results[i].Err = fmt.Errorf("skipped: %w", err)
+				return nil
+			}
+			fmt.Printf("📡 Deploying to %s (Chain ID: %s)...\n", network.Name, network.ChainID)
+			for _, spec := range specs {
+				address, err := deploy(txcost.WithNetwork(ctx, network.Name), network.ChainID, spec)
+				if err != nil {
+					fmt.Printf("   ❌ %s %s failed\n", network.Name, spec.Symbol)
+					results[i].Err = fmt.Errorf("%s: %w", spec.Name, err)
+					break
+				}
+				fmt.Printf("   ✅ %s %s deployed at %s\n", network.Name, spec.Symbol, address)
+				results[i].Tokens = append(results[i].Tokens, tokenspec.DeployedToken{
+					Name:     spec.Name,
+					Symbol:   spec.Symbol,
+					Decimals: spec.Decimals,
+					Address:  address,
+				})
+			}
+			if failFast {
+				return results[i].Err
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+// buildWithForge compiles the Solidity project
+func buildWithForge(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "forge", "build")
+	cmd.Dir = solidityDir()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("forge build failed: %v\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// deployWithNonce returns a deployFunc deploying bytecode from the deployer at its next nonce, then minting the
+// initial supply to it
+func deployWithNonce(bytecode []byte) deployFunc {
+	return func(ctx context.Context, chainID string, spec tokenspec.TokenSpec) (string, error) {
+		client, auth, err := dialDeployer(ctx, chainID)
+		if err != nil {
+			return "", err
+		}
+		defer client.Close()
+
+		initCode, err := tokenspec.ERC20InitCode(bytecode, spec)
+		if err != nil {
+			return "", err
+		}
+		auth.Context = ctx
+		address, tx, _, err := bind.DeployContract(auth, abi.ABI{}, initCode, client)
+		if err != nil {
+			return "", fmt.Errorf("failed to send the deploy transaction: %w", err)
+		}
+		if err := finishDeploy(ctx, client, auth, spec, address, tx); err != nil {
+			return "", err
+		}
+		return address.Hex(), nil
+	}
+}
+
+// deployWithCreate2 returns a deployFunc deploying through the CREATE2 proxy with salt. A token already at its
+// address is kept as is; a new one gets its initial supply minted to the deployer
+func deployWithCreate2(bytecode []byte, salt [32]byte) deployFunc {
+	return func(ctx context.Context, chainID string, spec tokenspec.TokenSpec) (string, error) {
+		client, auth, err := dialDeployer(ctx, chainID)
+		if err != nil {
+			return "", err
+		}
+		defer client.Close()
+
+		initCode, err := tokenspec.ERC20InitCode(bytecode, spec)
+		if err != nil {
+			return "", err
+		}
+		address, tx, err := ethutil.DeployCreate2(ctx, client, auth, salt, initCode)
+		if err != nil {
+			return "", err
+		}
+		if tx == nil {
+			fmt.Printf("   ⏭️  %s is already deployed at %s, skipping\n", spec.Symbol, address.Hex())
+			return address.Hex(), nil
+		}
+		if err := finishDeploy(ctx, client, auth, spec, address, tx); err != nil {
+			return "", err
+		}
+		return address.Hex(), nil
+	}
+}
+
+// dialDeployer connects to chainID's RPC and returns a transactor for DEPLOYER_PRIVATE_KEY
+func dialDeployer(ctx context.Context, chainID string) (*ethclient.Client, *bind.TransactOpts, error) {
+	rpcURL := getRPCURL(chainID)
+	if rpcURL == "" {
+		return nil, nil, fmt.Errorf("no RPC URL configured for chain ID %s", chainID)
+	}
+	key, err := ethutil.ParsePrivateKey(os.Getenv("DEPLOYER_PRIVATE_KEY"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid DEPLOYER_PRIVATE_KEY: %w", err)
+	}
+	id, ok := new(big.Int).SetString(chainID, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid chain ID %s", chainID)
+	}
+	auth, err := ethutil.NewTransactor(id, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", rpcURL, err)
+	}
+	return client, auth, nil
+}
+
+// finishDeploy waits for the deploy transaction tx, checks it left code at address and mints spec's initial supply
+// to the deployer
+func finishDeploy(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, spec tokenspec.TokenSpec, address common.Address, tx *gethtypes.Transaction) error {
+	if err := waitForSuccess(txcost.WithOperation(ctx, "deploy "+spec.Symbol), client, tx, "deploy"); err != nil {
+		return err
+	}
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get code at %s: %w", address.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("deploy transaction %s left no code at %s", tx.Hash().Hex(), address.Hex())
+	}
+
+	if supply := spec.Supply(); supply.Sign() > 0 {
+		data, err := mintABI.Pack("mint", auth.From, supply)
+		if err != nil {
+			return err
+		}
+		tx, err := ethutil.SendTx(ctx, client, auth, address, nil, data)
+		if err != nil {
+			return fmt.Errorf("failed to mint the initial supply: %w", err)
+		}
+		if err := waitForSuccess(txcost.WithOperation(ctx, "mint "+spec.Symbol), client, tx, "mint"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mintABI is MockERC20's mint(address,uint256)
+var mintABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"mint","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"}]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// waitForSuccess waits for tx and fails unless it succeeded
+func waitForSuccess(ctx context.Context, client *ethclient.Client, tx *gethtypes.Transaction, what string) error {
+	receipt, err := ethutil.WaitForTransaction(ctx, client, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the %s transaction %s: %w", what, tx.Hash().Hex(), err)
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("%s transaction %s reverted", what, tx.Hash().Hex())
+	}
+	return nil
+}
+
+// solidityDir returns the Foundry project, next to the solver directory
+func solidityDir() string {
+	return solverdir.Path("..", "solidity")
+}
+
+// getRPCURL returns the RPC URL of the chain, the LOCAL_ one of its fork when IS_DEVNET=true
+func getRPCURL(chainID string) string {
+	switch chainID {
+	case "11155111": // Sepolia
+		return envutil.GetConditionalEnv("ETHEREUM_RPC_URL", "")
+	case "11155420": // OP Sepolia
+		return envutil.GetConditionalEnv("OPTIMISM_RPC_URL", "")
+	case "421614": // Arbitrum Sepolia
+		return envutil.GetConditionalEnv("ARBITRUM_RPC_URL", "")
+	case "84532": // Base Sepolia
+		return envutil.GetConditionalEnv("BASE_RPC_URL", "")
+	default:
+		return ""
+	}
+}
+
+func getExplorerURL(chainID, address string) string {
+	switch chainID {
+	case "11155111": // Sepolia
+		return fmt.Sprintf("https://sepolia.etherscan.io/address/%s", address)
+	case "11155420": // OP Sepolia
+		return fmt.Sprintf("https://sepolia-optimism.etherscan.io/address/%s", address)
+	case "421614": // Arbitrum Sepolia
+		return fmt.Sprintf("https://sepolia.arbiscan.io/address/%s", address)
+	case "84532": // Base Sepolia
+		return fmt.Sprintf("https://sepolia.basescan.org/address/%s", address)
+	default:
+		return fmt.Sprintf("Unknown chain ID: %s", chainID)
+	}
+}
@@ -0,0 +1,131 @@
+package deployforgemockerc20
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+)
+
+var testNetworks = []NetworkInfo{
+	{Name: "Ethereum Sepolia", ChainID: "11155111", Network: "Ethereum"},
+	{Name: "Optimism Sepolia", ChainID: "11155420", Network: "Optimism"},
+	{Name: "Arbitrum Sepolia", ChainID: "421614", Network: "Arbitrum"},
+	{Name: "Base Sepolia", ChainID: "84532", Network: "Base"},
+}
+
+var testSpecs = []tokenspec.TokenSpec{
+	{Name: "DogCoin", Symbol: "DOG", Decimals: 18},
+	{Name: "USDC", Symbol: "USDC", Decimals: 6},
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Setenv(deploySaltEnv, "")
+	opts, err := parseArgs(nil, testNetworks)
+	require.NoError(t, err)
+	assert.Equal(t, options{Networks: testNetworks, Concurrency: len(testNetworks)}, opts)
+
+	opts, err = parseArgs([]string{"base", "--write-env", "--concurrency", "2", "--fail-fast"}, testNetworks)
+	require.NoError(t, err)
+	assert.Equal(t, options{Networks: testNetworks[3:], Concurrency: 2, FailFast: true, WriteEnv: true}, opts)
+
+	opts, err = parseArgs([]string{"--concurrency=1"}, testNetworks)
+	require.NoError(t, err)
+	assert.Equal(t, 1, opts.Concurrency)
+
+	for wantErr, args := range map[string][]string{
+		"invalid network name: solana":      {"solana"},
+		`invalid --concurrency "0"`:         {"--concurrency", "0"},
+		"--concurrency requires a number":   {"--concurrency"},
+		"unexpected argument: --parallel":   {"--parallel"},
+		"unexpected argument: optimism":     {"base", "optimism"},
+		`invalid --concurrency "two"`:       {"--concurrency=two"},
+		"--salt requires a value":           {"--salt"},
+		`invalid salt "pepper"`:             {"--salt", "pepper"},
+		"--predict-only needs a fixed salt": {"--predict-only"},
+	} {
+		_, err := parseArgs(args, testNetworks)
+		assert.ErrorContains(t, err, wantErr)
+	}
+}
+
+func TestParseArgsSalt(t *testing.T) {
+	t.Setenv(deploySaltEnv, "")
+	opts, err := parseArgs([]string{"--salt", "0x2a", "--predict-only"}, testNetworks)
+	require.NoError(t, err)
+	require.NotNil(t, opts.Salt)
+	assert.Equal(t, byte(0x2a), opts.Salt[31])
+	assert.True(t, opts.PredictOnly)
+
+	t.Setenv(deploySaltEnv, "7")
+	opts, err = parseArgs(nil, testNetworks)
+	require.NoError(t, err)
+	require.NotNil(t, opts.Salt)
+	assert.Equal(t, byte(7), opts.Salt[31], "the salt defaults to "+deploySaltEnv)
+
+	opts, err = parseArgs([]string{"--salt=8"}, testNetworks)
+	require.NoError(t, err)
+	assert.Equal(t, byte(8), opts.Salt[31], "the flag wins over the environment")
+}
+
+func TestDeployAllRunsInParallel(t *testing.T) {
+	var running, peak atomic.Int32
+	deploy := func(_ context.Context, chainID string, spec tokenspec.TokenSpec) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		if chainID == "11155420" && spec.Symbol == "USDC" {
+			return "", errors.New("nonce too low")
+		}
+		return "0x" + chainID + spec.Symbol, nil
+	}
+
+	results := deployAll(context.Background(), testNetworks, testSpecs, 2, false, deploy)
+	require.Len(t, results, len(testNetworks))
+	assert.EqualValues(t, 2, peak.Load(), "at most concurrency deployments at once")
+
+	for i, result := range results {
+		assert.Equal(t, testNetworks[i], result.Network, "results keep the network order")
+	}
+	assert.EqualError(t, results[1].Err, "USDC: nonce too low")
+	assert.Equal(t, []tokenspec.DeployedToken{{Name: "DogCoin", Symbol: "DOG", Decimals: 18, Address: "0x11155420DOG"}}, results[1].Tokens,
+		"tokens deployed before the failure are kept")
+	for _, i := range []int{0, 2, 3} {
+		assert.NoError(t, results[i].Err, "a failure does not cancel the other networks")
+		require.Len(t, results[i].Tokens, 2)
+		assert.Equal(t, "0x"+testNetworks[i].ChainID+"DOG", results[i].Tokens[0].Address)
+		assert.Equal(t, tokenspec.DeployedToken{Name: "USDC", Symbol: "USDC", Decimals: 6, Address: "0x" + testNetworks[i].ChainID + "USDC"}, results[i].Tokens[1])
+	}
+}
+
+func TestDeployAllFailFast(t *testing.T) {
+	deploy := func(ctx context.Context, chainID string, _ tokenspec.TokenSpec) (string, error) {
+		if chainID == "11155111" {
+			return "", errors.New("insufficient funds")
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(5 * time.Second):
+			return "0x" + chainID, nil
+		}
+	}
+
+	results := deployAll(context.Background(), testNetworks, testSpecs, 2, true, deploy)
+	assert.EqualError(t, results[0].Err, "DogCoin: insufficient funds")
+	for _, result := range results[1:] {
+		assert.ErrorIs(t, result.Err, context.Canceled, "%s is cancelled or skipped", result.Network.Name)
+	}
+}
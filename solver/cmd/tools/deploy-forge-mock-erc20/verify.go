@@ -0,0 +1,206 @@
+package deployforgemockerc20
+
+// Verify tool: verifies the token spec's MockERC20 tokens on the networks' block explorers. The sources and compiler
+// settings come from the Forge artifact's metadata and are submitted to the Etherscan API directly, so Foundry is
+// not needed; without an API key, forge verify-contract is used when it is installed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/etherscan"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+)
+
+const (
+	// etherscanAPIKeyEnv is the Etherscan v2 key, valid on every network; the networks' own keys are the fallback
+	etherscanAPIKeyEnv = "ETHERSCAN_API_KEY"
+	// etherscanURLEnv overrides the Etherscan API endpoint
+	etherscanURLEnv = "ETHERSCAN_API_URL"
+)
+
+// Verify verifies the token spec's MockERC20 tokens as described by args: [network]. The addresses are read from
+// .env or the deployment state. It fails if any token failed to verify
+func Verify(ctx context.Context, args []string) error {
+	if err := solverdir.LoadEnv(); err != nil {
+		return err
+	}
+	if len(args) > 1 || (len(args) == 1 && strings.HasPrefix(args[0], "--")) {
+		return fmt.Errorf("unexpected argument: %s (usage: verify-mock-erc20 [network])", strings.Join(args, " "))
+	}
+	var networkArg string
+	if len(args) == 1 {
+		networkArg = args[0]
+	}
+	networks, err := selectNetworks(networkArg, Networks)
+	if err != nil {
+		return err
+	}
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return err
+	}
+
+	v := newVerifier()
+	failed := 0
+	for _, network := range networks {
+		fmt.Printf("🔍 Verifying on %s (Chain ID: %s)...\n", network.Name, network.ChainID)
+		for _, spec := range specs {
+			address, _, err := tokenspec.Address(network.Network, spec.Name)
+			if err == nil && !common.IsHexAddress(address) {
+				err = fmt.Errorf("invalid address %q", address)
+			}
+			if err == nil {
+				err = v.verify(ctx, network, spec, common.HexToAddress(address))
+			}
+			if err != nil {
+				fmt.Printf("   ❌ %s %s: %v\n", network.Name, spec.Symbol, err)
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d token(s) failed to verify", failed)
+	}
+	return nil
+}
+
+// verifyDeployed verifies the tokens deployed by Run on the live networks. Verification failures are only reported,
+// as the deployment itself succeeded
+func verifyDeployed(ctx context.Context, results []deployment, specs []tokenspec.TokenSpec) {
+	if envutil.IsDevnet() {
+		return
+	}
+	v := newVerifier()
+	fmt.Printf("\n🔍 Verifying the deployed tokens...\n")
+	for _, result := range results {
+		for _, token := range result.Tokens {
+			spec, ok := tokenspec.Find(specs, token.Name)
+			if !ok {
+				continue
+			}
+			if err := v.verify(ctx, result.Network, spec, common.HexToAddress(token.Address)); err != nil {
+				fmt.Printf("   ⚠️  %s %s: %v\n", result.Network.Name, token.Symbol, err)
+				fmt.Printf("      Retry with: make verify-mock-erc20\n")
+			}
+		}
+	}
+}
+
+// verifier verifies tokens through the Etherscan API, or forge when the network has no API key
+type verifier struct {
+	url string
+	// apiKey returns the API key of a network, empty when there is none
+	apiKey func(NetworkInfo) string
+	// forge verifies a token with forge verify-contract
+	forge func(ctx context.Context, network NetworkInfo, address common.Address, constructorArgs []byte) error
+	// source loads the verification input, once
+	source func() (etherscan.Source, error)
+	client etherscan.Client
+}
+
+func newVerifier() *verifier {
+	v := &verifier{
+		url:    os.Getenv(etherscanURLEnv),
+		apiKey: apiKey,
+		forge:  verifyWithForge,
+	}
+	var source etherscan.Source
+	var sourceErr error
+	loaded := false
+	v.source = func() (etherscan.Source, error) {
+		if !loaded {
+			source, sourceErr = etherscan.SourceFromArtifact(solidityDir(), solverdir.Path(tokenspec.MockERC20Artifact))
+			loaded = true
+		}
+		return source, sourceErr
+	}
+	return v
+}
+
+// verify verifies spec's token at address on network and prints the verification GUID and outcome
+func (v *verifier) verify(ctx context.Context, network NetworkInfo, spec tokenspec.TokenSpec, address common.Address) error {
+	constructorArgs, err := tokenspec.ERC20ConstructorArgs(spec)
+	if err != nil {
+		return err
+	}
+	key := v.apiKey(network)
+	if key == "" {
+		fmt.Printf("   ℹ️  No %s or %s, falling back to forge verify-contract\n", etherscanAPIKeyEnv, network.APIKeyEnv)
+		return v.forge(ctx, network, address, constructorArgs)
+	}
+
+	source, err := v.source()
+	if err != nil {
+		return err
+	}
+	client := v.client
+	client.URL = v.url
+	client.APIKey = key
+	guid, err := client.Submit(ctx, etherscan.VerifyRequest{
+		ChainID:         network.ChainID,
+		Address:         address,
+		ContractName:    source.ContractName,
+		CompilerVersion: source.CompilerVersion,
+		StandardJSON:    source.StandardJSON,
+		ConstructorArgs: constructorArgs,
+	})
+	if errors.Is(err, etherscan.ErrAlreadyVerified) {
+		fmt.Printf("   ⏭️  %s %s is already verified\n", spec.Symbol, address.Hex())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   📨 %s %s submitted (GUID %s)\n", spec.Symbol, address.Hex(), guid)
+
+	status, err := client.Wait(ctx, network.ChainID, guid)
+	if err != nil {
+		return fmt.Errorf("verification %s: %w", guid, err)
+	}
+	if !status.Verified {
+		return fmt.Errorf("verification %s failed: %s", guid, status.Message)
+	}
+	fmt.Printf("   ✅ %s %s verified: %s\n", spec.Symbol, address.Hex(), status.Message)
+	fmt.Printf("      Explorer: %s#code\n", getExplorerURL(network.ChainID, address.Hex()))
+	return nil
+}
+
+// apiKey returns ETHERSCAN_API_KEY, or the network's foundry.toml key
+func apiKey(network NetworkInfo) string {
+	if key := strings.TrimSpace(os.Getenv(etherscanAPIKeyEnv)); key != "" {
+		return key
+	}
+	if network.APIKeyEnv == "" {
+		return ""
+	}
+	return strings.TrimSpace(os.Getenv(network.APIKeyEnv))
+}
+
+// verifyWithForge verifies the token at address with forge verify-contract, which reads the API keys from
+// foundry.toml
+func verifyWithForge(ctx context.Context, network NetworkInfo, address common.Address, constructorArgs []byte) error {
+	if _, err := exec.LookPath("forge"); err != nil {
+		return fmt.Errorf("no API key for %s (set %s) and forge is not installed", network.Name, etherscanAPIKeyEnv)
+	}
+	cmd := exec.CommandContext(ctx, "forge", "verify-contract",
+		"--chain", network.ChainID,
+		"--watch",
+		"--constructor-args", common.Bytes2Hex(constructorArgs),
+		address.Hex(),
+		"src/MockERC20.sol:MockERC20",
+	)
+	cmd.Dir = solidityDir()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("forge verify-contract failed: %v\nOutput: %s", err, output)
+	}
+	return nil
+}
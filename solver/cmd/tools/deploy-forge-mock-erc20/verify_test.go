@@ -0,0 +1,102 @@
+package deployforgemockerc20
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/etherscan"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+)
+
+var testSource = etherscan.Source{
+	ContractName:    "src/MockERC20.sol:MockERC20",
+	CompilerVersion: "v0.8.25+commit.b61c2a91",
+	StandardJSON:    []byte(`{"language":"Solidity"}`),
+}
+
+// testVerifier returns a verifier against url with the key for every network, recording the forge fallbacks
+func testVerifier(url, key string, forged *[]common.Address) *verifier {
+	return &verifier{
+		url:    url,
+		apiKey: func(NetworkInfo) string { return key },
+		forge: func(_ context.Context, _ NetworkInfo, address common.Address, _ []byte) error {
+			*forged = append(*forged, address)
+			return nil
+		},
+		source: func() (etherscan.Source, error) { return testSource, nil },
+		client: etherscan.Client{PollInterval: time.Millisecond},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	spec := testSpecs[1]
+	wantArgs, err := tokenspec.ERC20ConstructorArgs(spec)
+	require.NoError(t, err)
+	address := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		result := map[string]string{"status": "1", "message": "OK"}
+		switch r.Form.Get("action") {
+		case "verifysourcecode":
+			assert.Equal(t, testNetworks[3].ChainID, r.PostForm.Get("chainid"))
+			assert.Equal(t, address.Hex(), r.PostForm.Get("contractaddress"))
+			assert.Equal(t, hex.EncodeToString(wantArgs), r.PostForm.Get("constructorArguements"))
+			result["result"] = "guid-1"
+		case "checkverifystatus":
+			checks++
+			result["result"] = "Pending in queue"
+			if checks > 1 {
+				result["result"] = "Pass - Verified"
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(result))
+	}))
+	defer server.Close()
+
+	var forged []common.Address
+	require.NoError(t, testVerifier(server.URL, "key", &forged).verify(context.Background(), testNetworks[3], spec, address))
+	assert.Equal(t, 2, checks)
+	assert.Empty(t, forged)
+
+	// Without an API key forge verifies instead
+	require.NoError(t, testVerifier(server.URL, "", &forged).verify(context.Background(), testNetworks[3], spec, address))
+	assert.Equal(t, []common.Address{address}, forged)
+}
+
+func TestVerifyFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		result := map[string]string{"status": "1", "message": "OK", "result": "guid-1"}
+		if r.Form.Get("action") == "checkverifystatus" {
+			result["result"] = "Fail - Unable to verify"
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(result))
+	}))
+	defer server.Close()
+
+	var forged []common.Address
+	err := testVerifier(server.URL, "key", &forged).verify(context.Background(), testNetworks[0], testSpecs[0], common.Address{1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "verification guid-1 failed: Fail - Unable to verify")
+}
+
+func TestAPIKey(t *testing.T) {
+	t.Setenv(etherscanAPIKeyEnv, "")
+	t.Setenv("API_KEY_BASESCAN", "base-key")
+	assert.Equal(t, "base-key", apiKey(Networks[3]))
+	assert.Empty(t, apiKey(NetworkInfo{Name: "Unknown"}))
+
+	t.Setenv(etherscanAPIKeyEnv, "v2-key")
+	assert.Equal(t, "v2-key", apiKey(Networks[3]))
+}
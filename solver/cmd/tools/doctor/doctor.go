@@ -0,0 +1,288 @@
+package doctor
+
+// Doctor tool: sanity-checks .env, the deployment state and the chains before a run
+// For every configured network the RPC must answer with the configured chain ID, Hyperlane7683 and the known
+// tokens must have code (on Cairo networks a view must answer too, see starknetutil.VerifyDeployment), and
+// Hyperlane7683's localDomain() must be the configured domain. The account keys are checked against the
+// configured addresses. One line is printed per check; the exit code is 1 if any fails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	// TimeoutFlag bounds each RPC call
+	TimeoutFlag = "--timeout"
+
+	// DefaultCallTimeout is the per-call timeout without --timeout
+	DefaultCallTimeout = 10 * time.Second
+)
+
+// Status is the outcome of one check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result is one printed check
+type Result struct {
+	Network string
+	Check   string
+	Status  Status
+	Detail  string
+}
+
+func pass(network, check, format string, args ...any) Result {
+	return Result{network, check, StatusPass, fmt.Sprintf(format, args...)}
+}
+
+func warn(network, check, format string, args ...any) Result {
+	return Result{network, check, StatusWarn, fmt.Sprintf(format, args...)}
+}
+
+func fail(network, check, format string, args ...any) Result {
+	return Result{network, check, StatusFail, fmt.Sprintf(format, args...)}
+}
+
+// Run runs `doctor [--timeout <duration>]`: every check, printing the results and failing if any check failed
+func Run(ctx context.Context, args []string) error {
+	timeout, err := parseDoctorArgs(args)
+	if err != nil {
+		return err
+	}
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	networks := make([]config.NetworkConfig, 0, len(config.Networks))
+	for _, network := range config.Networks {
+		networks = append(networks, network)
+	}
+	results := checkNetworks(ctx, networks, openProbe, timeout)
+	results = append(results, checkEVMKeys()...)
+	if failed := printResults(os.Stdout, results); failed > 0 {
+		return fmt.Errorf("%d doctor checks failed", failed)
+	}
+	return nil
+}
+
+func parseDoctorArgs(args []string) (time.Duration, error) {
+	timeout := DefaultCallTimeout
+	for i := 0; i < len(args); i++ {
+		value, ok := strings.CutPrefix(args[i], TimeoutFlag+"=")
+		switch {
+		case ok:
+		case args[i] == TimeoutFlag && i+1 < len(args):
+			i++
+			value = args[i]
+		default:
+			return 0, fmt.Errorf("unexpected argument: %s (usage: doctor [%s <duration>])", args[i], TimeoutFlag)
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return 0, fmt.Errorf("invalid %s %q: expected a positive duration such as 10s", TimeoutFlag, value)
+		}
+		timeout = parsed
+	}
+	return timeout, nil
+}
+
+// knownTokens lists the token addresses of a network; replaced in tests
+var knownTokens = openorder.KnownTokens
+
+// openProbe connects to a configured network; replaced in tests
+var openProbe = func(network config.NetworkConfig) (chainProbe, func(), error) {
+	if openorder.GetNetworkType(network.Name) == openorder.NetworkTypeEVM {
+		client, err := ethclient.Dial(network.RPCURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		return evmProbe{client: client}, client.Close, nil
+	}
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return starknetProbe{provider: provider}, func() {}, nil
+}
+
+// checkNetworks checks every network in parallel and returns the results grouped by network, in name order
+func checkNetworks(ctx context.Context, networks []config.NetworkConfig, open func(config.NetworkConfig) (chainProbe, func(), error), timeout time.Duration) []Result {
+	sorted := append([]config.NetworkConfig(nil), networks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	perNetwork := make([][]Result, len(sorted))
+	var wg sync.WaitGroup
+	for i, network := range sorted {
+		wg.Add(1)
+		go func(i int, network config.NetworkConfig) {
+			defer wg.Done()
+			perNetwork[i] = checkNetwork(ctx, network, open, timeout)
+		}(i, network)
+	}
+	wg.Wait()
+
+	var results []Result
+	for _, r := range perNetwork {
+		results = append(results, r...)
+	}
+	return results
+}
+
+// checkNetwork runs the on-chain checks of one network; they stop at the RPC check if the node does not answer
+func checkNetwork(ctx context.Context, network config.NetworkConfig, open func(config.NetworkConfig) (chainProbe, func(), error), timeout time.Duration) []Result {
+	name := network.Name
+	probe, closeProbe, err := open(network)
+	if err != nil {
+		return []Result{fail(name, "rpc", "cannot connect to %s: %v", network.RPCURL, err)}
+	}
+	defer closeProbe()
+
+	callCtx := func() (context.Context, context.CancelFunc) { return context.WithTimeout(ctx, timeout) }
+
+	c, cancel := callCtx()
+	chainID, err := probe.ChainID(c)
+	cancel()
+	if err != nil {
+		return []Result{fail(name, "rpc", "%s is unreachable: %v", network.RPCURL, err)}
+	}
+	results := []Result{pass(name, "rpc", "%s is reachable", network.RPCURL), checkChainID(network, chainID)}
+
+	// Hyperlane7683: deployed, and on the configured domain
+	if network.HyperlaneAddress == "" {
+		results = append(results, fail(name, "hyperlane", "Hyperlane7683 address not configured"))
+	} else {
+		c, cancel = callCtx()
+		results = append(results, checkContract(c, probe, name, "hyperlane", "Hyperlane7683", network.HyperlaneAddress, starknetutil.Hyperlane7683ProbeView))
+		cancel()
+
+		c, cancel = callCtx()
+		domain, err := probe.LocalDomain(c, network.HyperlaneAddress)
+		cancel()
+		switch {
+		case err != nil:
+			results = append(results, fail(name, "domain", "cannot read localDomain(): %v", err))
+		case uint64(domain) != network.HyperlaneDomain:
+			results = append(results, fail(name, "domain", "localDomain() is %d, config has %d", domain, network.HyperlaneDomain))
+		default:
+			results = append(results, pass(name, "domain", "localDomain() is %d", domain))
+		}
+	}
+
+	tokens := knownTokens(name)
+	if len(tokens) == 0 {
+		results = append(results, warn(name, "tokens", "no token addresses in .env or state/deployment"))
+	}
+	for _, token := range tokens {
+		c, cancel = callCtx()
+		results = append(results, checkContract(c, probe, name, "token", token.Symbol+" ("+token.Source+")", token.Address, starknetutil.ERC20ProbeView))
+		cancel()
+	}
+
+	if openorder.GetNetworkType(name) != openorder.NetworkTypeEVM {
+		keys, _ := probe.(accountKeyReader)
+		results = append(results, checkCairoKeys(ctx, name, keys, timeout)...)
+	}
+	return results
+}
+
+// checkChainID compares the node's chain ID with config. Starknet nodes report a short string (SN_SEPOLIA):
+// it is checked against <NETWORK>_CHAIN_ID when that gives the Starknet chain ID, and only shown otherwise since
+// the numeric chain ID is the Hyperlane one, which the domain check covers
+func checkChainID(network config.NetworkConfig, reported string) Result {
+	name := network.Name
+	if openorder.GetNetworkType(name) != openorder.NetworkTypeEVM {
+		if network.StarknetChainID == "" {
+			return pass(name, "chain-id", "node reports %s", reported)
+		}
+		if err := config.CheckStarknetChainID(network, reported); err != nil {
+			return fail(name, "chain-id", "%v", err)
+		}
+		return pass(name, "chain-id", "%s matches config", network.ChainIDLabel())
+	}
+	numeric, err := strconv.ParseUint(reported, 10, 64)
+	switch {
+	case err != nil:
+		return fail(name, "chain-id", "node reports unexpected chain ID %q", reported)
+	case numeric != network.ChainID:
+		return fail(name, "chain-id", "node reports %d, config has %d", numeric, network.ChainID)
+	default:
+		return pass(name, "chain-id", "%d matches config", numeric)
+	}
+}
+
+// checkCode checks a contract is deployed at address
+func checkCode(ctx context.Context, probe chainProbe, network, check, what, address string) Result {
+	deployed, err := probe.HasCode(ctx, address)
+	switch {
+	case err != nil:
+		return fail(network, check, "cannot read code of %s at %s: %v", what, address, err)
+	case !deployed:
+		return fail(network, check, "no code for %s at %s", what, address)
+	default:
+		return pass(network, check, "%s deployed at %s", what, address)
+	}
+}
+
+// checkContract checks a contract is deployed at address and, where the probe can verify deployments, that view
+// answers there, which catches a Cairo deployment whose constructor failed
+func checkContract(ctx context.Context, probe chainProbe, network, check, what, address, view string) Result {
+	verifier, ok := probe.(deploymentVerifier)
+	if !ok {
+		return checkCode(ctx, probe, network, check, what, address)
+	}
+	err := verifier.VerifyDeployment(ctx, address, view)
+	switch {
+	case errors.Is(err, starknetutil.ErrNoContract):
+		return fail(network, check, "no code for %s at %s", what, address)
+	case err != nil:
+		return fail(network, check, "%s at %s is not usable: %v", what, address, err)
+	default:
+		return pass(network, check, "%s deployed at %s, %s() answers", what, address, view)
+	}
+}
+
+// printResults prints one line per result and a summary, and returns the number of failures
+func printResults(w io.Writer, results []Result) int {
+	counts := make(map[Status]int)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range results {
+		counts[r.Status]++
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", statusIcon(r.Status), strings.ToUpper(string(r.Status)), r.Network, r.Check, r.Detail)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n🩺 %d passed, %d warnings, %d failed\n", counts[StatusPass], counts[StatusWarn], counts[StatusFail])
+	return counts[StatusFail]
+}
+
+func statusIcon(status Status) string {
+	switch status {
+	case StatusPass:
+		return "✅"
+	case StatusWarn:
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
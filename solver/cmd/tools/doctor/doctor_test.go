@@ -0,0 +1,244 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// fakeProbe answers from fixed values; addresses in code are deployed
+type fakeProbe struct {
+	chainID    string
+	chainErr   error
+	code       map[string]bool
+	domain     uint32
+	publicKeys map[string]*felt.Felt
+}
+
+func (f fakeProbe) ChainID(context.Context) (string, error) { return f.chainID, f.chainErr }
+
+func (f fakeProbe) HasCode(_ context.Context, address string) (bool, error) {
+	return f.code[address], nil
+}
+
+func (f fakeProbe) LocalDomain(context.Context, string) (uint32, error) { return f.domain, nil }
+
+func (f fakeProbe) AccountPublicKey(_ context.Context, account string) (*felt.Felt, error) {
+	key, ok := f.publicKeys[account]
+	if !ok {
+		return nil, errors.New("contract not found")
+	}
+	return key, nil
+}
+
+func byCheck(results []Result) map[string]Result {
+	m := make(map[string]Result)
+	for _, r := range results {
+		m[r.Network+"/"+r.Check] = r
+	}
+	return m
+}
+
+func TestCheckNetworks(t *testing.T) {
+	knownTokens = func(networkName string) []openorder.KnownToken {
+		if networkName == "Base" {
+			return []openorder.KnownToken{{Symbol: "DogCoin", Address: "0xd06", Source: "BASE_DOG_COIN_ADDRESS"}, {Symbol: "OrcaCoin", Address: "0x0ca"}}
+		}
+		return nil
+	}
+	t.Cleanup(func() { knownTokens = openorder.KnownTokens })
+	t.Setenv("ZTARKNET_ALICE_PRIVATE_KEY", "")
+	t.Setenv("ZTARKNET_ALICE_PUBLIC_KEY", "")
+	t.Setenv("ZTARKNET_SOLVER_PRIVATE_KEY", "")
+	t.Setenv("ZTARKNET_SOLVER_PUBLIC_KEY", "")
+
+	networks := []config.NetworkConfig{
+		{Name: "Optimism", RPCURL: "http://optimism"},
+		{Name: "Base", RPCURL: "http://base", ChainID: 8453, HyperlaneAddress: "0x7683", HyperlaneDomain: 8453},
+		{Name: "Arbitrum", RPCURL: "http://arbitrum", ChainID: 42161, HyperlaneAddress: "0x7683", HyperlaneDomain: 42161},
+		{Name: "Ztarknet", RPCURL: "http://ztarknet", ChainID: 10066329, HyperlaneAddress: "0x7683", HyperlaneDomain: 10066329},
+	}
+	probes := map[string]fakeProbe{
+		"Optimism": {chainErr: errors.New("connection refused")},
+		"Base":     {chainID: "8453", code: map[string]bool{"0x7683": true, "0xd06": true}, domain: 8453},
+		"Arbitrum": {chainID: "1", domain: 1},
+		"Ztarknet": {chainID: "ZTARKNET", code: map[string]bool{"0x7683": true}, domain: 10066329},
+	}
+	open := func(network config.NetworkConfig) (chainProbe, func(), error) {
+		return probes[network.Name], func() {}, nil
+	}
+
+	results := checkNetworks(context.Background(), networks, open, time.Second)
+	assert.Equal(t, "Arbitrum", results[0].Network, "networks are reported in name order")
+	checks := byCheck(results)
+
+	assert.Equal(t, StatusFail, checks["Optimism/rpc"].Status)
+	assert.NotContains(t, checks, "Optimism/chain-id", "the other checks are skipped when the RPC is down")
+
+	assert.Equal(t, StatusPass, checks["Base/chain-id"].Status)
+	assert.Equal(t, StatusPass, checks["Base/hyperlane"].Status)
+	assert.Equal(t, StatusPass, checks["Base/domain"].Status)
+
+	assert.Equal(t, StatusFail, checks["Arbitrum/chain-id"].Status)
+	assert.Equal(t, "node reports 1, config has 42161", checks["Arbitrum/chain-id"].Detail)
+	assert.Equal(t, StatusFail, checks["Arbitrum/hyperlane"].Status)
+	assert.Equal(t, "localDomain() is 1, config has 42161", checks["Arbitrum/domain"].Detail)
+	assert.Equal(t, StatusWarn, checks["Arbitrum/tokens"].Status)
+
+	assert.Equal(t, StatusPass, checks["Ztarknet/chain-id"].Status, "Starknet chain IDs are short strings")
+	assert.Equal(t, StatusWarn, checks["Ztarknet/keys Alice"].Status)
+
+	var tokens []Result
+	for _, r := range results {
+		if r.Network == "Base" && r.Check == "token" {
+			tokens = append(tokens, r)
+		}
+	}
+	require.Len(t, tokens, 2)
+	assert.Equal(t, StatusPass, tokens[0].Status)
+	assert.Equal(t, StatusFail, tokens[1].Status)
+	assert.Contains(t, tokens[1].Detail, "no code for OrcaCoin")
+}
+
+// verifyingProbe is a fakeProbe that also verifies deployments, failing those in errs
+type verifyingProbe struct {
+	fakeProbe
+	errs map[string]error
+}
+
+func (v verifyingProbe) VerifyDeployment(_ context.Context, address, _ string) error {
+	return v.errs[address]
+}
+
+func TestCheckContract(t *testing.T) {
+	probe := verifyingProbe{errs: map[string]error{
+		"0xdead": fmt.Errorf("%w at 0xdead", starknetutil.ErrNoContract),
+		"0xbad":  errors.New("name() at 0xbad failed: Entry point not found"),
+	}}
+
+	result := checkContract(context.Background(), probe, "Starknet", "token", "DogCoin", "0xd06", starknetutil.ERC20ProbeView)
+	assert.Equal(t, pass("Starknet", "token", "DogCoin deployed at 0xd06, name() answers"), result)
+
+	result = checkContract(context.Background(), probe, "Starknet", "token", "DogCoin", "0xdead", starknetutil.ERC20ProbeView)
+	assert.Equal(t, fail("Starknet", "token", "no code for DogCoin at 0xdead"), result)
+
+	result = checkContract(context.Background(), probe, "Starknet", "token", "DogCoin", "0xbad", starknetutil.ERC20ProbeView)
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Contains(t, result.Detail, "DogCoin at 0xbad is not usable: name() at 0xbad failed")
+
+	result = checkContract(context.Background(), probe.fakeProbe, "Base", "token", "DogCoin", "0xd06", starknetutil.ERC20ProbeView)
+	assert.Equal(t, "no code for DogCoin at 0xd06", result.Detail, "probes without verification only check for code")
+}
+
+func TestCheckEVMKeys(t *testing.T) {
+	t.Setenv("IS_DEVNET", "true")
+	// anvil account 3
+	t.Setenv("LOCAL_SOLVER_PRIVATE_KEY", "0x7c852118294e51e653712a81e05800f419141751be58f605c371e15141b007a6")
+	t.Setenv("LOCAL_SOLVER_PUB_KEY", "0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	t.Setenv("LOCAL_ALICE_PRIVATE_KEY", "")
+	t.Setenv("LOCAL_ALICE_PUB_KEY", "")
+
+	results := checkEVMKeys()
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusPass, results[0].Status)
+	assert.Equal(t, StatusWarn, results[1].Status, "Alice is optional")
+
+	t.Setenv("LOCAL_SOLVER_PUB_KEY", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	assert.Contains(t, checkEVMKeys()[0].Detail, "derives 0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	assert.Equal(t, StatusFail, checkEVMKeys()[0].Status)
+
+	t.Setenv("LOCAL_SOLVER_PRIVATE_KEY", "your solver private key")
+	assert.Equal(t, "LOCAL_SOLVER_PRIVATE_KEY not set", checkEVMKeys()[0].Detail, "example.env placeholders count as unset")
+	assert.Equal(t, StatusFail, checkEVMKeys()[0].Status, "the solver key is required")
+}
+
+func TestCheckCairoKeys(t *testing.T) {
+	privateKey := big.NewInt(0x1234)
+	x, _ := curve.PrivateKeyToPoint(privateKey)
+	publicKey := new(felt.Felt).SetBigInt(x)
+
+	t.Setenv("ZTARKNET_ALICE_ADDRESS", "0xa11ce")
+	t.Setenv("ZTARKNET_ALICE_PRIVATE_KEY", fmt.Sprintf("0x%x", privateKey))
+	t.Setenv("ZTARKNET_ALICE_PUBLIC_KEY", publicKey.String())
+	t.Setenv("ZTARKNET_SOLVER_ADDRESS", "0x5017e7")
+	t.Setenv("ZTARKNET_SOLVER_PRIVATE_KEY", fmt.Sprintf("0x%x", privateKey))
+	t.Setenv("ZTARKNET_SOLVER_PUBLIC_KEY", "")
+
+	probe := fakeProbe{publicKeys: map[string]*felt.Felt{"0xa11ce": publicKey}}
+	results := checkCairoKeys(context.Background(), "Ztarknet", probe, time.Second)
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusPass, results[0].Status)
+	assert.Equal(t, StatusFail, results[1].Status, "a partial key pair fails")
+
+	probe.publicKeys["0xa11ce"] = new(felt.Felt).SetUint64(1)
+	assert.Equal(t, StatusFail, checkCairoKeys(context.Background(), "Ztarknet", probe, time.Second)[0].Status)
+
+	t.Setenv("ZTARKNET_ALICE_ADDRESS", "0xdead")
+	assert.Equal(t, StatusWarn, checkCairoKeys(context.Background(), "Ztarknet", probe, time.Second)[0].Status,
+		"an unreadable account only warns")
+
+	t.Setenv("ZTARKNET_ALICE_PUBLIC_KEY", "0x1")
+	assert.Contains(t, checkCairoKeys(context.Background(), "Ztarknet", nil, time.Second)[0].Detail, "derives "+publicKey.String())
+}
+
+func TestPrintResults(t *testing.T) {
+	var buf bytes.Buffer
+	failed := printResults(&buf, []Result{
+		pass("Base", "rpc", "http://base is reachable"),
+		warn("Base", "tokens", "no token addresses"),
+		fail("Base", "domain", "localDomain() is 1, config has 8453"),
+	})
+	assert.Equal(t, 1, failed)
+	assert.Regexp(t, `✅ PASS\s+Base\s+rpc\s+http://base is reachable`, buf.String())
+	assert.Regexp(t, `❌ FAIL\s+Base\s+domain`, buf.String())
+	assert.Contains(t, buf.String(), "1 passed, 1 warnings, 1 failed")
+}
+
+func TestParseDoctorArgs(t *testing.T) {
+	timeout, err := parseDoctorArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCallTimeout, timeout)
+
+	timeout, err = parseDoctorArgs([]string{"--timeout", "2s"})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, timeout)
+
+	timeout, err = parseDoctorArgs([]string{"--timeout=500ms"})
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, timeout)
+
+	_, err = parseDoctorArgs([]string{"--timeout", "0s"})
+	assert.ErrorContains(t, err, "invalid --timeout")
+	_, err = parseDoctorArgs([]string{"base"})
+	assert.ErrorContains(t, err, "unexpected argument: base")
+}
+
+func TestCheckChainIDStarknet(t *testing.T) {
+	network := config.NetworkConfig{Name: "Starknet", ChainID: config.StarknetSepoliaChainID, StarknetChainID: "0x534e5f5345504f4c4941"}
+
+	result := checkChainID(network, "SN_SEPOLIA")
+	assert.Equal(t, StatusPass, result.Status)
+	assert.Equal(t, "SN_SEPOLIA (0x534e5f5345504f4c4941) matches config", result.Detail)
+
+	result = checkChainID(network, "SN_MAIN")
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "chain ID mismatch: Starknet node reports SN_MAIN, STARKNET_CHAIN_ID is SN_SEPOLIA", result.Detail)
+
+	network.StarknetChainID = ""
+	result = checkChainID(network, "SN_MAIN")
+	assert.Equal(t, StatusPass, result.Status, "without STARKNET_CHAIN_ID the node's chain is only shown")
+	assert.Equal(t, "node reports SN_MAIN", result.Detail)
+}
@@ -0,0 +1,167 @@
+package doctor
+
+// Key checks
+// EVM accounts are configured as a private key and an address: the address derived from the key must be the
+// configured one. Cairo accounts are a private key, a public key and an account address: the public key derived
+// from the private key must be the configured one, and the account contract must hold it (get_public_key)
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+)
+
+// evmNetworkLabel is the network column of the EVM key checks, which are shared by all EVM networks
+const evmNetworkLabel = "EVM"
+
+// keyPair names the env vars of one account; the solver's are required, Alice's only warn when missing
+type keyPair struct {
+	Account    string
+	PrivateEnv string
+	AddressEnv string
+	Required   bool
+}
+
+// cairoAccountEnvs names the key env vars of one Starknet or Ztarknet account, and its resolved address
+type cairoAccountEnvs struct {
+	Account    string
+	Address    string
+	PrivateEnv string
+	PublicEnv  string
+}
+
+// envValue reads an env var, treating the example.env placeholders ("your ... key") as unset
+func envValue(name string) string {
+	value := os.Getenv(name)
+	if strings.Contains(value, " ") {
+		return ""
+	}
+	return value
+}
+
+// checkEVMKeys checks the solver's and Alice's EVM keys derive to their configured addresses
+func checkEVMKeys() []Result {
+	pairs := []keyPair{
+		{Account: "Solver", PrivateEnv: envutil.ConditionalKey("SOLVER_PRIVATE_KEY"), AddressEnv: envutil.ConditionalKey("SOLVER_PUB_KEY"), Required: true},
+		{Account: "Alice", PrivateEnv: envutil.ConditionalKey("ALICE_PRIVATE_KEY"), AddressEnv: envutil.ConditionalKey("ALICE_PUB_KEY")},
+	}
+	results := make([]Result, 0, len(pairs))
+	for _, pair := range pairs {
+		results = append(results, checkEVMKey(pair))
+	}
+	return results
+}
+
+func checkEVMKey(pair keyPair) Result {
+	check := "keys " + pair.Account
+	missing := missingEnvs(pair.PrivateEnv, pair.AddressEnv)
+	switch {
+	case len(missing) == 2 && !pair.Required:
+		return warn(evmNetworkLabel, check, "%s not set", strings.Join(missing, ", "))
+	case len(missing) > 0:
+		return fail(evmNetworkLabel, check, "%s not set", strings.Join(missing, ", "))
+	}
+
+	key, err := ethutil.ParsePrivateKey(envValue(pair.PrivateEnv))
+	if err != nil {
+		return fail(evmNetworkLabel, check, "invalid %s: %v", pair.PrivateEnv, err)
+	}
+	configured := envValue(pair.AddressEnv)
+	if !common.IsHexAddress(configured) {
+		return fail(evmNetworkLabel, check, "invalid %s %q", pair.AddressEnv, configured)
+	}
+	derived := crypto.PubkeyToAddress(key.PublicKey)
+	if derived != common.HexToAddress(configured) {
+		return fail(evmNetworkLabel, check, "%s derives %s but %s is %s", pair.PrivateEnv, derived.Hex(), pair.AddressEnv, configured)
+	}
+	return pass(evmNetworkLabel, check, "%s matches %s", pair.PrivateEnv, derived.Hex())
+}
+
+// cairoAccounts names the Alice and solver key env vars of a Starknet (LOCAL_ on devnet) or Ztarknet network
+func cairoAccounts(networkName string) []cairoAccountEnvs {
+	if openorder.GetNetworkType(networkName) == openorder.NetworkTypeStarknet {
+		return []cairoAccountEnvs{
+			{"Alice", envutil.GetStarknetAliceAddress(), envutil.ConditionalKey("STARKNET_ALICE_PRIVATE_KEY"), envutil.ConditionalKey("STARKNET_ALICE_PUBLIC_KEY")},
+			{"Solver", envutil.GetStarknetSolverAddress(), envutil.ConditionalKey("STARKNET_SOLVER_PRIVATE_KEY"), envutil.ConditionalKey("STARKNET_SOLVER_PUBLIC_KEY")},
+		}
+	}
+	return []cairoAccountEnvs{
+		{"Alice", envutil.GetZtarknetAliceAddress(), "ZTARKNET_ALICE_PRIVATE_KEY", "ZTARKNET_ALICE_PUBLIC_KEY"},
+		{"Solver", envutil.GetZtarknetSolverAddress(), "ZTARKNET_SOLVER_PRIVATE_KEY", "ZTARKNET_SOLVER_PUBLIC_KEY"},
+	}
+}
+
+// checkCairoKeys checks the account keys of a Starknet or Ztarknet network; keys may be nil to skip the on-chain read
+func checkCairoKeys(ctx context.Context, networkName string, keys accountKeyReader, timeout time.Duration) []Result {
+	var results []Result
+	for _, account := range cairoAccounts(networkName) {
+		results = append(results, checkCairoKey(ctx, networkName, account, keys, timeout))
+	}
+	return results
+}
+
+func checkCairoKey(ctx context.Context, networkName string, account cairoAccountEnvs, keys accountKeyReader, timeout time.Duration) Result {
+	check := "keys " + account.Account
+	missing := missingEnvs(account.PrivateEnv, account.PublicEnv)
+	switch {
+	case len(missing) == 2:
+		return warn(networkName, check, "%s not set", strings.Join(missing, ", "))
+	case len(missing) > 0:
+		return fail(networkName, check, "%s not set", strings.Join(missing, ", "))
+	}
+
+	privateKey, ok := new(big.Int).SetString(strings.TrimPrefix(envValue(account.PrivateEnv), "0x"), 16)
+	if !ok || privateKey.Sign() == 0 {
+		return fail(networkName, check, "invalid %s", account.PrivateEnv)
+	}
+	publicKey, err := utils.HexToFelt(envValue(account.PublicEnv))
+	if err != nil {
+		return fail(networkName, check, "invalid %s: %v", account.PublicEnv, err)
+	}
+	derived, _ := curve.PrivateKeyToPoint(privateKey)
+	if derived.Cmp(publicKey.BigInt(new(big.Int))) != 0 {
+		return fail(networkName, check, "%s derives 0x%x but %s is %s", account.PrivateEnv, derived, account.PublicEnv, publicKey.String())
+	}
+	if keys == nil {
+		return pass(networkName, check, "%s matches %s", account.PrivateEnv, account.PublicEnv)
+	}
+
+	// A Starknet address does not derive from the key, so the account contract is asked which key it holds
+	address := account.Address
+	if address == "" {
+		return fail(networkName, check, "keys match, but the %s account address is not set", account.Account)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	onChain, err := keys.AccountPublicKey(callCtx, address)
+	cancel()
+	switch {
+	case err != nil:
+		return warn(networkName, check, "keys match, but cannot read the public key of %s: %v", address, err)
+	case !onChain.Equal(publicKey):
+		return fail(networkName, check, "account %s holds public key %s, %s is %s", address, onChain.String(), account.PublicEnv, publicKey.String())
+	default:
+		return pass(networkName, check, "%s matches %s and account %s", account.PrivateEnv, account.PublicEnv, address)
+	}
+}
+
+// missingEnvs returns the names that are not set
+func missingEnvs(names ...string) []string {
+	var missing []string
+	for _, name := range names {
+		if envValue(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,137 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// chainProbe is the read-only access doctor needs to one network
+type chainProbe interface {
+	// ChainID is the node's chain ID: decimal on EVM networks, the decoded short string on Starknet
+	ChainID(ctx context.Context) (string, error)
+	// HasCode reports whether a contract is deployed at address
+	HasCode(ctx context.Context, address string) (bool, error)
+	// LocalDomain reads the Hyperlane domain of the Hyperlane7683 at address
+	LocalDomain(ctx context.Context, hyperlane string) (uint32, error)
+}
+
+// accountKeyReader reads the public key stored in a Starknet account contract
+type accountKeyReader interface {
+	AccountPublicKey(ctx context.Context, account string) (*felt.Felt, error)
+}
+
+// deploymentVerifier checks a contract is live and its constructor ran, beyond having code
+type deploymentVerifier interface {
+	VerifyDeployment(ctx context.Context, address, view string) error
+}
+
+// evmProbe reads an EVM network through an ethclient
+type evmProbe struct {
+	client *ethclient.Client
+}
+
+func (p evmProbe) ChainID(ctx context.Context) (string, error) {
+	chainID, err := p.client.ChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return chainID.String(), nil
+}
+
+func (p evmProbe) HasCode(ctx context.Context, address string) (bool, error) {
+	if !common.IsHexAddress(address) {
+		return false, fmt.Errorf("invalid address %q", address)
+	}
+	code, err := p.client.CodeAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+func (p evmProbe) LocalDomain(ctx context.Context, hyperlane string) (uint32, error) {
+	caller, err := contracts.NewHyperlane7683Caller(common.HexToAddress(hyperlane), p.client)
+	if err != nil {
+		return 0, err
+	}
+	return caller.LocalDomain(&bind.CallOpts{Context: ctx})
+}
+
+// starknetProbe reads a Starknet or Ztarknet network through the RPC provider
+type starknetProbe struct {
+	provider *rpc.Provider
+}
+
+func (p starknetProbe) ChainID(ctx context.Context) (string, error) {
+	return p.provider.ChainID(ctx)
+}
+
+func (p starknetProbe) HasCode(ctx context.Context, address string) (bool, error) {
+	addr, err := utils.HexToFelt(address)
+	if err != nil {
+		return false, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	if _, err := p.provider.ClassHashAt(ctx, rpc.WithBlockTag("latest"), addr); err != nil {
+		var rpcErr *rpc.RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrContractNotFound.Code {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (p starknetProbe) VerifyDeployment(ctx context.Context, address, view string) error {
+	addr, err := utils.HexToFelt(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	return starknetutil.VerifyDeployment(ctx, p.provider, addr, nil, view)
+}
+
+func (p starknetProbe) LocalDomain(ctx context.Context, hyperlane string) (uint32, error) {
+	value, err := p.call(ctx, hyperlane, "get_local_domain")
+	if err != nil {
+		return 0, err
+	}
+	return uint32(value.Uint64()), nil
+}
+
+func (p starknetProbe) AccountPublicKey(ctx context.Context, account string) (*felt.Felt, error) {
+	addr, err := utils.HexToFelt(account)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", account, err)
+	}
+	return starknetutil.AccountPublicKey(ctx, p.provider, addr)
+}
+
+// call invokes a view function without arguments and returns its single felt result
+func (p starknetProbe) call(ctx context.Context, address, function string) (*felt.Felt, error) {
+	addr, err := utils.HexToFelt(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	resp, err := p.provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    addr,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           []*felt.Felt{},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", function, err)
+	}
+	if len(resp) == 0 {
+		return nil, fmt.Errorf("%s returned no data", function)
+	}
+	return resp[0], nil
+}
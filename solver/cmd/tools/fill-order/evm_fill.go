@@ -0,0 +1,349 @@
+package fillorder
+
+// EVM side of the fill tool: reads openOrders on EVM origins and fills, settles and refunds orders on EVM destinations
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// newEVMSolver connects to an EVM network and returns a transactor for the solver account
+func newEVMSolver(ctx context.Context, network config.NetworkConfig) (*ethclient.Client, *bind.TransactOpts, error) {
+	privateKey, err := ethutil.ParsePrivateKey(envutil.GetSolverPrivateKey())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse SOLVER_PRIVATE_KEY: %w", err)
+	}
+	auth, err := ethutil.NewTransactor(new(big.Int).SetUint64(network.ChainID), privateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create auth: %w", err)
+	}
+
+	client, err := ethclient.DialContext(ctx, network.RPCURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	auth.GasPrice = gasPrice
+	return client, auth, nil
+}
+
+// fillEVMOrder approves the output token if needed and calls fill on the EVM destination settler
+func fillEVMOrder(
+	ctx context.Context,
+	destination config.NetworkConfig,
+	orderID common.Hash,
+	originData, fillerData []byte,
+	order *originOrder,
+) (*fillResult, error) {
+	ctx = txcost.WithNetwork(ctx, destination.Name)
+	client, auth, err := newEVMSolver(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	settler, err := starknetutil.Bytes32ToEVMAddress(order.DestinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination settler: %w", err)
+	}
+	contract, err := contracts.NewHyperlane7683(settler, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind Hyperlane7683 at %s: %w", settler.Hex(), err)
+	}
+
+	// Base7683.fill only accepts orders the destination has never seen
+	status, err := evmOrderStatus(ctx, client, contract, destination.Name, settler, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order status: %w", err)
+	}
+	if status != orderStatusUnknown {
+		return nil, fmt.Errorf("order %s is already %s on %s", orderID.Hex(), status, destination.Name)
+	}
+
+	// Native output is paid with the call value, ERC20 output is pulled by the settler
+	outputToken, err := starknetutil.Bytes32ToEVMAddress(order.OutputToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output token: %w", err)
+	}
+	if outputToken == (common.Address{}) {
+		auth.Value = order.AmountOut
+	} else if err := ensureEVMAllowance(ctx, client, auth, outputToken, settler, order.AmountOut); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("   Calling fill on %s...\n", settler.Hex())
+	tx, err := contract.Fill(auth, orderID, originData, fillerData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send fill transaction: %w", ethutil.RevertReason(err))
+	}
+	fmt.Printf("   Transaction sent: %s\n", tx.Hash().Hex())
+
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "fill"), client, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for fill confirmation: %w", err)
+	}
+	if receipt.Status != 1 {
+		return nil, ethutil.RevertedTxError(ctx, client, "fill", tx, auth.From, receipt)
+	}
+
+	status, err = evmOrderStatus(ctx, client, contract, destination.Name, settler, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order status after fill: %w", err)
+	}
+	result := &fillResult{TxHash: tx.Hash().Hex(), GasUsed: receipt.GasUsed, Status: status}
+	if result.Status != orderStatusFilled {
+		return nil, fmt.Errorf("order status is %s after fill, expected %s", result.Status, orderStatusFilled)
+	}
+	return result, nil
+}
+
+// evmOrderStatus reads orderStatus(orderID) from the settler and names it with the settler's own status constants
+func evmOrderStatus(ctx context.Context, client *ethclient.Client, contract *contracts.Hyperlane7683, network string, settler common.Address, orderID common.Hash) (string, error) {
+	word, err := contract.OrderStatus(&bind.CallOpts{Context: ctx}, orderID)
+	if err != nil {
+		return "", err
+	}
+	constants, err := orderquery.LoadConstants(ctx, network, settler, client)
+	if err != nil {
+		return "", err
+	}
+	status, err := constants.Status(word)
+	if err != nil {
+		return "", err
+	}
+	return status.String(), nil
+}
+
+// ensureEVMAllowance approves the settler to pull amount of the solver's output token
+func ensureEVMAllowance(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, token, spender common.Address, amount *big.Int) error {
+	balance, err := ethutil.ERC20BalanceAt(ctx, client, token, auth.From, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read solver output token balance: %w", err)
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("solver has %s output tokens but the order needs %s", balance, amount)
+	}
+
+	allowance, err := ethutil.ERC20AllowanceAt(ctx, client, token, auth.From, spender, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read allowance: %w", err)
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil
+	}
+
+	fmt.Printf("   Approving %s output tokens...\n", amount)
+	approveTx, err := ethutil.ERC20Approve(ctx, client, auth, token, spender, amount)
+	if err != nil {
+		return fmt.Errorf("failed to approve output token: %w", err)
+	}
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "approve"), client, approveTx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for approval transaction: %w", err)
+	}
+	if receipt.Status != 1 {
+		return fmt.Errorf("approval transaction %s failed", approveTx.Hash().Hex())
+	}
+	return nil
+}
+
+// settleEVMOrders calls settle(orderIds) on an EVM destination settler, paying the quoted Hyperlane gas, and returns
+// the fillerData its Settle event carried per order
+func settleEVMOrders(ctx context.Context, destination config.NetworkConfig, settlerWord [32]byte, originDomain uint32, orderIDs []common.Hash) (string, map[common.Hash][]byte, error) {
+	ctx = txcost.WithNetwork(ctx, destination.Name)
+	client, auth, err := newEVMSolver(ctx, destination)
+	if err != nil {
+		return "", nil, err
+	}
+	defer client.Close()
+
+	settler, err := starknetutil.Bytes32ToEVMAddress(settlerWord)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid destination settler: %w", err)
+	}
+	contract, err := contracts.NewHyperlane7683(settler, client)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to bind Hyperlane7683 at %s: %w", settler.Hex(), err)
+	}
+
+	gasPayment, err := contract.QuoteGasPayment(&bind.CallOpts{Context: ctx}, originDomain)
+	if err != nil {
+		return "", nil, fmt.Errorf("quoteGasPayment(%d) failed on %s: %w", originDomain, destination.Name, err)
+	}
+	auth.Value = gasPayment
+	fmt.Printf("   Gas payment for domain %d: %s wei\n", originDomain, gasPayment)
+
+	ids := make([][32]byte, len(orderIDs))
+	for i, orderID := range orderIDs {
+		ids[i] = orderID
+	}
+	tx, err := contract.Settle(auth, ids)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to send settle transaction: %w", ethutil.RevertReason(err))
+	}
+	fmt.Printf("   Transaction sent: %s\n", tx.Hash().Hex())
+
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "settle"), client, tx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to wait for settle confirmation: %w", err)
+	}
+	if receipt.Status != 1 {
+		return "", nil, ethutil.RevertedTxError(ctx, client, "settle", tx, auth.From, receipt)
+	}
+	fmt.Printf("   Settle confirmed (gas used: %d)\n", receipt.GasUsed)
+
+	fillerData, err := evmSettledFillerData(&contract.Hyperlane7683Filterer, settler, receipt.Logs)
+	if err != nil {
+		return "", nil, err
+	}
+	return tx.Hash().Hex(), fillerData, nil
+}
+
+// evmSettledFillerData reads the fillerData of every order in the Settle events settler emitted in logs
+func evmSettledFillerData(filterer *contracts.Hyperlane7683Filterer, settler common.Address, logs []*gethtypes.Log) (map[common.Hash][]byte, error) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	settleID := parsed.Events["Settle"].ID
+	fillerData := make(map[common.Hash][]byte)
+	for _, log := range logs {
+		if log.Address != settler || len(log.Topics) == 0 || log.Topics[0] != settleID {
+			continue
+		}
+		event, err := filterer.ParseSettle(*log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Settle event: %w", err)
+		}
+		if len(event.OrderIds) != len(event.OrdersFillerData) {
+			return nil, fmt.Errorf("settle event has %d orders but %d fillerData", len(event.OrderIds), len(event.OrdersFillerData))
+		}
+		for i, orderID := range event.OrderIds {
+			fillerData[orderID] = event.OrdersFillerData[i]
+		}
+	}
+	return fillerData, nil
+}
+
+// refundEVMOrder calls refund on an EVM destination settler, paying the quoted Hyperlane gas for the refund message.
+// Gasless orders go through the GaslessCrossChainOrder overload, everything else through the OnchainCrossChainOrder one
+func refundEVMOrder(ctx context.Context, destination config.NetworkConfig, r *refundOrder) (string, error) {
+	ctx = txcost.WithNetwork(ctx, destination.Name)
+	client, auth, err := newEVMSolver(ctx, destination)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	settler, err := starknetutil.Bytes32ToEVMAddress(r.Order.DestinationSettler)
+	if err != nil {
+		return "", fmt.Errorf("invalid destination settler: %w", err)
+	}
+	contract, err := contracts.NewHyperlane7683(settler, client)
+	if err != nil {
+		return "", fmt.Errorf("failed to bind Hyperlane7683 at %s: %w", settler.Hex(), err)
+	}
+
+	gasPayment, err := contract.QuoteGasPayment(&bind.CallOpts{Context: ctx}, r.Order.OriginDomain)
+	if err != nil {
+		return "", fmt.Errorf("quoteGasPayment(%d) failed on %s: %w", r.Order.OriginDomain, destination.Name, err)
+	}
+	auth.Value = gasPayment
+	fmt.Printf("   Gas payment for domain %d: %s wei\n", r.Order.OriginDomain, gasPayment)
+
+	var tx *gethtypes.Transaction
+	if r.Gasless {
+		var originSettler, user common.Address
+		if originSettler, err = starknetutil.Bytes32ToEVMAddress(r.OriginSettler); err != nil {
+			return "", fmt.Errorf("invalid origin settler: %w", err)
+		}
+		if user, err = starknetutil.Bytes32ToEVMAddress(r.Order.Sender); err != nil {
+			return "", fmt.Errorf("invalid order sender: %w", err)
+		}
+		tx, err = contract.Refund0(auth, []contracts.GaslessCrossChainOrder{{
+			OriginSettler: originSettler,
+			User:          user,
+			Nonce:         r.Order.SenderNonce,
+			OriginChainId: new(big.Int).SetUint64(uint64(r.Order.OriginDomain)),
+			OpenDeadline:  uint32(r.OpenDeadline),
+			FillDeadline:  uint32(r.FillDeadline),
+			OrderDataType: r.OrderDataType,
+			OrderData:     r.OrderData,
+		}})
+	} else {
+		tx, err = contract.Refund(auth, []contracts.OnchainCrossChainOrder{{
+			FillDeadline:  uint32(r.FillDeadline),
+			OrderDataType: r.OrderDataType,
+			OrderData:     r.OrderData,
+		}})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to send refund transaction: %w", ethutil.RevertReason(err))
+	}
+	fmt.Printf("   Transaction sent: %s\n", tx.Hash().Hex())
+
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "refund"), client, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for refund confirmation: %w", err)
+	}
+	if receipt.Status != 1 {
+		return "", ethutil.RevertedTxError(ctx, client, "refund", tx, auth.From, receipt)
+	}
+	fmt.Printf("   Refund confirmed (gas used: %d)\n", receipt.GasUsed)
+	return tx.Hash().Hex(), nil
+}
+
+// evmBlockTime returns the timestamp of the latest block, which is what refund compares fillDeadline against
+func evmBlockTime(ctx context.Context, network config.NetworkConfig) (uint64, error) {
+	client, err := ethclient.DialContext(ctx, network.RPCURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	defer client.Close()
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest block on %s: %w", network.Name, err)
+	}
+	return header.Time, nil
+}
+
+// evmTokenBalance reads an ERC20 balance and, unless previous is nil, waits for it to move away from previous
+func evmTokenBalance(ctx context.Context, network config.NetworkConfig, tokenWord, ownerWord [32]byte, previous *big.Int) (*big.Int, error) {
+	client, err := ethclient.DialContext(ctx, network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	defer client.Close()
+
+	token, err := starknetutil.Bytes32ToEVMAddress(tokenWord)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	owner, err := starknetutil.Bytes32ToEVMAddress(ownerWord)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner: %w", err)
+	}
+	if previous == nil {
+		return ethutil.ERC20BalanceAt(ctx, client, token, owner, nil)
+	}
+	return ethutil.WaitForERC20BalanceChange(ctx, client, token, owner, previous, ethutil.BalancePollOptions{})
+}
@@ -0,0 +1,305 @@
+package fillorder
+
+// Fill tool: fills an order opened by open-order on its destination chain
+// The origin data is read back from openOrders(orderId) on the origin chain, so only the order ID
+// and the origin chain are needed; the destination chain and settler come from the order data itself
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const wordSize = 32
+
+// Order statuses stored by Base7683 (bytes32 on EVM, short string felt on Starknet)
+const (
+	orderStatusUnknown = "UNKNOWN"
+	orderStatusFilled  = "FILLED"
+)
+
+// fillRequest identifies the order to fill
+type fillRequest struct {
+	OrderID     common.Hash
+	OriginChain string
+}
+
+// originOrder holds the OrderData fields needed to fill, settle or refund an order
+type originOrder = orderquery.OrderData
+
+// fillResult reports a confirmed fill
+type fillResult struct {
+	TxHash  string
+	GasUsed uint64
+	Status  string
+}
+
+// RunFillOrder fills the order described by args: <order-id> <origin-chain>, an order ID recorded in the
+// local order store, or the path to an open-order --json result ("-" reads it from stdin)
+func RunFillOrder(ctx context.Context, args []string) error {
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := localOrderStore()
+	req, err := parseFillArgs(args, os.Stdin, store)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fillOrder(ctx, req); err != nil {
+		return err
+	}
+	recordStatus(store, req.OrderID, orderStatusFilled)
+	return nil
+}
+
+// FillOrder fills orderID, opened on originChain, as the Solver and records it as FILLED in the local order
+// store. It returns the fill transaction hash. The configuration must already be loaded
+func FillOrder(ctx context.Context, originChain string, orderID common.Hash) (string, error) {
+	result, err := fillOrder(ctx, fillRequest{OrderID: orderID, OriginChain: originChain})
+	if err != nil {
+		return "", err
+	}
+	recordStatus(localOrderStore(), orderID, orderStatusFilled)
+	return result.TxHash, nil
+}
+
+// fillOrder reads the order back from its origin chain and fills it on the destination chain
+func fillOrder(ctx context.Context, req fillRequest) (*fillResult, error) {
+	originName, err := networkByName(req.OriginChain)
+	if err != nil {
+		return nil, fmt.Errorf("origin: %w", err)
+	}
+
+	originData, order, err := loadOriginOrder(ctx, originName, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	destinationName, err := networkByDomain(order.DestinationDomain)
+	if err != nil {
+		return nil, fmt.Errorf("destination: %w", err)
+	}
+	destination := config.Networks[destinationName]
+
+	fillerData, err := solverFillerData(originName)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("   %s → %s, amountOut %s, settler %s\n", originName, destinationName, order.AmountOut, common.Hash(order.DestinationSettler).Hex())
+
+	var result *fillResult
+	if openorder.GetNetworkType(destinationName) == openorder.NetworkTypeEVM {
+		result, err = fillEVMOrder(ctx, destination, req.OrderID, originData, fillerData, order)
+	} else {
+		result, err = fillStarknetOrder(ctx, destination, req.OrderID, originData, fillerData, order)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("✅ Order %s filled on %s\n", req.OrderID.Hex(), destinationName)
+	fmt.Printf("   Transaction: %s\n", result.TxHash)
+	fmt.Printf("   Gas used: %d\n", result.GasUsed)
+	fmt.Printf("   Status: %s\n", result.Status)
+	return result, nil
+}
+
+// loadOriginOrder reads an order back from openOrders on its origin chain and returns its orderData
+func loadOriginOrder(ctx context.Context, originName string, orderID common.Hash) ([]byte, *originOrder, error) {
+	origin, err := queryNetwork(originName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fmt.Printf("🔍 Loading order %s from %s...\n", orderID.Hex(), originName)
+	raw, err := orderquery.ReadOpenOrder(ctx, origin, orderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read open order from %s: %w", originName, err)
+	}
+
+	originData, err := orderquery.OpenOrderData(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("order %s on %s: %w", orderID.Hex(), originName, err)
+	}
+	order, err := orderquery.ParseOrderData(originData)
+	if err != nil {
+		return nil, nil, err
+	}
+	return originData, order, nil
+}
+
+// queryNetwork locates the Hyperlane7683 contract of a configured network for orderquery
+func queryNetwork(networkName string) (orderquery.Network, error) {
+	address, err := hyperlaneAddressWord(networkName)
+	if err != nil {
+		return orderquery.Network{}, err
+	}
+	return settlerNetwork(networkName, address)
+}
+
+// settlerNetwork locates the contract at address, a 32-byte word, on a configured network for orderquery
+func settlerNetwork(networkName string, address [32]byte) (orderquery.Network, error) {
+	network := orderquery.Network{
+		Name:   networkName,
+		RPCURL: config.Networks[networkName].RPCURL,
+		Cairo:  openorder.GetNetworkType(networkName) != openorder.NetworkTypeEVM,
+	}
+	if network.Cairo {
+		contract, err := starknetutil.Bytes32ToFelt(address)
+		if err != nil {
+			return orderquery.Network{}, fmt.Errorf("invalid %s Hyperlane address: %w", networkName, err)
+		}
+		network.Contract = contract.String()
+		return network, nil
+	}
+	contract, err := starknetutil.Bytes32ToEVMAddress(address)
+	if err != nil {
+		return orderquery.Network{}, fmt.Errorf("invalid %s Hyperlane address: %w", networkName, err)
+	}
+	network.Contract = contract.Hex()
+	return network, nil
+}
+
+// parseFillArgs reads the order to fill from the command line arguments
+func parseFillArgs(args []string, stdin io.Reader, store *orderstore.Store) (fillRequest, error) {
+	switch len(args) {
+	case 1:
+		if orderID, err := parseOrderID(args[0]); err == nil {
+			stored, err := lookupStoredOrder(store, orderID)
+			if err != nil {
+				return fillRequest{}, err
+			}
+			return fillRequest{OrderID: orderID, OriginChain: stored.OriginChain}, nil
+		}
+		data, err := readOrderResult(args[0], stdin)
+		if err != nil {
+			return fillRequest{}, err
+		}
+		return parseOrderResult(data)
+	case 2:
+		orderID, err := parseOrderID(args[0])
+		if err != nil {
+			return fillRequest{}, err
+		}
+		return fillRequest{OrderID: orderID, OriginChain: args[1]}, nil
+	default:
+		return fillRequest{}, fmt.Errorf("usage: fill-order <order-id> [<origin-chain>] | fill-order <open-order-result.json|->")
+	}
+}
+
+// readOrderResult reads an open-order --json result from a file, or from stdin when path is "-"
+func readOrderResult(path string, stdin io.Reader) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read open-order result: %w", err)
+	}
+	return data, nil
+}
+
+// parseOrderResult extracts the order to fill from an open-order --json result
+func parseOrderResult(data []byte) (fillRequest, error) {
+	result, orderID, err := decodeOrderResult(data)
+	if err != nil {
+		return fillRequest{}, err
+	}
+	return fillRequest{OrderID: orderID, OriginChain: result.OriginChain}, nil
+}
+
+// decodeOrderResult parses an open-order --json result, rejecting orders that were not opened
+func decodeOrderResult(data []byte) (*openorder.OrderResult, common.Hash, error) {
+	var result openorder.OrderResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, common.Hash{}, fmt.Errorf("invalid open-order result: %w", err)
+	}
+	if result.Status == openorder.OrderStatusFailed {
+		return nil, common.Hash{}, fmt.Errorf("order was not opened: %s", result.Error)
+	}
+	if result.OriginChain == "" {
+		return nil, common.Hash{}, fmt.Errorf("open-order result has no originChain")
+	}
+
+	orderID, err := parseOrderID(result.OrderID)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return &result, orderID, nil
+}
+
+// parseOrderID parses a 0x-prefixed bytes32 order ID
+func parseOrderID(s string) (common.Hash, error) {
+	b, err := hexToBytes(s)
+	if err != nil || len(b) == 0 || len(b) > wordSize {
+		return common.Hash{}, fmt.Errorf("invalid order ID %q: expected 0x-prefixed bytes32", s)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// hexToBytes decodes a 0x-prefixed hex string, allowing an odd number of digits
+func hexToBytes(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, fmt.Errorf("missing 0x prefix")
+	}
+	digits := s[2:]
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	return hex.DecodeString(digits)
+}
+
+// decodeOrderStatus turns a status word (bytes32 or short string felt) into its string form
+func decodeOrderStatus(word []byte) string {
+	status, err := orderquery.DecodeStatus(word)
+	if err != nil {
+		return strings.Trim(string(word), "\x00")
+	}
+	return status.String()
+}
+
+// networkByName resolves a configured network name case-insensitively
+func networkByName(name string) (string, error) {
+	for _, networkName := range sortedNetworkNames() {
+		if strings.EqualFold(networkName, name) {
+			return networkName, nil
+		}
+	}
+	return "", fmt.Errorf("unknown network %q (known: %s)", name, strings.Join(sortedNetworkNames(), ", "))
+}
+
+// networkByDomain resolves the configured network with the given Hyperlane domain
+func networkByDomain(domain uint32) (string, error) {
+	for _, networkName := range sortedNetworkNames() {
+		if config.Networks[networkName].HyperlaneDomain == uint64(domain) {
+			return networkName, nil
+		}
+	}
+	return "", fmt.Errorf("no configured network has Hyperlane domain %d", domain)
+}
+
+// sortedNetworkNames returns the configured network names in a stable order
+func sortedNetworkNames() []string {
+	names := config.GetNetworkNames()
+	sort.Strings(names)
+	return names
+}
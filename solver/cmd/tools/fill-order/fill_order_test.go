@@ -0,0 +1,133 @@
+package fillorder
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/fillerdata"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestParseFillArgs(t *testing.T) {
+	orderID := "0x9f4a7c1b2d3e4f5061728394a5b6c7d8e9f00112233445566778899aabbccdd"
+
+	t.Run("order ID and origin", func(t *testing.T) {
+		req, err := parseFillArgs([]string{orderID, "Base"}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash(orderID), req.OrderID)
+		assert.Equal(t, "Base", req.OriginChain)
+	})
+
+	t.Run("open-order JSON from stdin", func(t *testing.T) {
+		body := `{"orderId":"` + orderID + `","originChain":"Starknet","destinationChain":"Base","status":"opened"}`
+		req, err := parseFillArgs([]string{"-"}, strings.NewReader(body), nil)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash(orderID), req.OrderID)
+		assert.Equal(t, "Starknet", req.OriginChain)
+	})
+
+	t.Run("open-order JSON from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "order.json")
+		body := `{"orderId":"` + orderID + `","originChain":"Ethereum","status":"opened"}`
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+
+		req, err := parseFillArgs([]string{path}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Ethereum", req.OriginChain)
+	})
+
+	t.Run("failed orders are rejected", func(t *testing.T) {
+		body := `{"originChain":"Base","status":"failed","error":"insufficient token balance"}`
+		_, err := parseFillArgs([]string{"-"}, strings.NewReader(body), nil)
+		assert.ErrorContains(t, err, "insufficient token balance")
+	})
+
+	t.Run("invalid order IDs are rejected", func(t *testing.T) {
+		for _, id := range []string{"1234", "0xzz", "0x" + strings.Repeat("11", 33)} {
+			_, err := parseFillArgs([]string{id, "Base"}, nil, nil)
+			assert.ErrorContains(t, err, "invalid order ID", id)
+		}
+	})
+
+	t.Run("short order IDs are left padded", func(t *testing.T) {
+		req, err := parseFillArgs([]string{"0xabc", "Base"}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0xabc"), req.OrderID)
+	})
+
+	t.Run("order ID alone resolves the origin from the order store", func(t *testing.T) {
+		store := orderstore.New(t.TempDir())
+		require.NoError(t, store.Save(&orderstore.Order{OrderID: common.HexToHash(orderID).Hex(), OriginChain: "Optimism"}))
+
+		req, err := parseFillArgs([]string{orderID}, nil, store)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash(orderID), req.OrderID)
+		assert.Equal(t, "Optimism", req.OriginChain)
+
+		_, err = parseFillArgs([]string{"0x01"}, nil, store)
+		assert.ErrorContains(t, err, "not recorded")
+	})
+
+	t.Run("wrong argument count", func(t *testing.T) {
+		_, err := parseFillArgs(nil, nil, nil)
+		assert.ErrorContains(t, err, "usage")
+	})
+}
+
+func TestDecodeOrderStatus(t *testing.T) {
+	filled := common.HexToHash("0x46494c4c45440000000000000000000000000000000000000000000000000000")
+	assert.Equal(t, orderStatusFilled, decodeOrderStatus(filled[:]))
+
+	// Starknet stores the short string as a felt, i.e. right aligned
+	feltWord := utils.Uint64ToFelt(0x46494c4c4544).Bytes()
+	assert.Equal(t, orderStatusFilled, decodeOrderStatus(feltWord[:]))
+
+	assert.Equal(t, orderStatusUnknown, decodeOrderStatus(make([]byte, wordSize)))
+}
+
+func TestFillerData(t *testing.T) {
+	addr := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	data, err := evmFillerData(addr)
+	require.NoError(t, err)
+	require.Len(t, data, wordSize)
+	assert.Equal(t, addr, common.BytesToAddress(data))
+
+	_, err = evmFillerData(common.Address{})
+	assert.ErrorIs(t, err, fillerdata.ErrZeroReceiver)
+
+	data, err = starknetFillerData("0x2af9427c5a277474c079a1283c880ee8a6f0f8fbf73ce969c08d88befec1bba", "STARKNET")
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x2af9427c5a277474c079a1283c880ee8a6f0f8fbf73ce969c08d88befec1bba").Bytes(), data)
+
+	_, err = starknetFillerData("", "ZTARKNET")
+	assert.ErrorContains(t, err, "ZTARKNET_SOLVER_ADDRESS")
+	_, err = starknetFillerData("0x800000000000011000000000000000000000000000000000000000000000001", "STARKNET")
+	assert.ErrorContains(t, err, "exceeds the felt field")
+}
+
+func TestNetworkLookup(t *testing.T) {
+	config.InitializeNetworks()
+
+	name, err := networkByName("base")
+	require.NoError(t, err)
+	assert.Equal(t, "Base", name)
+
+	_, err = networkByName("Solana")
+	assert.ErrorContains(t, err, "known: ")
+
+	domain := uint32(config.Networks["Starknet"].HyperlaneDomain)
+	name, err = networkByDomain(domain)
+	require.NoError(t, err)
+	assert.Equal(t, "Starknet", name)
+
+	_, err = networkByDomain(0xffffffff)
+	assert.ErrorContains(t, err, "no configured network")
+}
@@ -0,0 +1,145 @@
+package fillorder
+
+// Order status tool: reads an order back from chain without the local order store
+// Prints the status Base7683 keeps on the given network and, when that network is the origin, the order it
+// stores in openOrders with the time left until its fill deadline and the status on the destination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// orderStatusTimeout bounds the reads of one order-status run
+const orderStatusTimeout = 60 * time.Second
+
+// RunOrderStatus runs `order-status <network> <order-id>`
+func RunOrderStatus(ctx context.Context, args []string) {
+	if _, err := config.LoadConfig(); err != nil {
+		fmt.Printf("❌ failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	networkName, orderID, err := parseOrderStatusArgs(args)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, orderStatusTimeout)
+	defer cancel()
+	if err := printOrderStatus(ctx, networkName, orderID); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseOrderStatusArgs reads the network and order ID of order-status
+func parseOrderStatusArgs(args []string) (string, common.Hash, error) {
+	if len(args) != 2 {
+		return "", common.Hash{}, fmt.Errorf("usage: order-status <network> <order-id>")
+	}
+	networkName, err := networkByName(args[0])
+	if err != nil {
+		return "", common.Hash{}, err
+	}
+	orderID, err := parseOrderID(args[1])
+	if err != nil {
+		return "", common.Hash{}, err
+	}
+	return networkName, orderID, nil
+}
+
+// printOrderStatus prints the status of an order on networkName and, when it is the origin, the open order
+func printOrderStatus(ctx context.Context, networkName string, orderID common.Hash) error {
+	network, err := queryNetwork(networkName)
+	if err != nil {
+		return err
+	}
+	status, err := orderquery.GetOrderStatus(ctx, network, orderID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📋 Order %s\n", orderID.Hex())
+	fmt.Printf("   Status on %s: %s\n", networkName, status)
+
+	order, err := orderquery.GetOpenOrder(ctx, network, orderID)
+	if errors.Is(err, orderquery.ErrOrderNotFound) {
+		fmt.Printf("   No open order on %s: it is not the origin of this order\n", networkName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("   Fill deadline: %d (%s)\n", order.FillDeadline, deadlineNote(order.FillDeadline, uint64(time.Now().Unix())))
+	destinationName, err := networkByDomain(order.DestinationDomain)
+	if err != nil {
+		fmt.Printf("   Destination domain %d: %v\n", order.DestinationDomain, err)
+		destinationName = ""
+	}
+	for _, line := range orderFieldLines(networkName, destinationName, &order) {
+		fmt.Printf("   %s\n", line)
+	}
+	if destinationName == "" {
+		return nil
+	}
+
+	destinationNetwork, err := settlerNetwork(destinationName, order.DestinationSettler)
+	if err == nil {
+		var destinationStatus orderquery.Status
+		if destinationStatus, err = orderquery.GetOrderStatus(ctx, destinationNetwork, orderID); err == nil {
+			fmt.Printf("   Status on %s: %s\n", destinationName, destinationStatus)
+			return nil
+		}
+	}
+	fmt.Printf("   ⚠️  failed to read the status on %s: %v\n", destinationName, err)
+	return nil
+}
+
+// orderFieldLines formats the fields of an open order; origin fields are shown as origin addresses and
+// destination fields as destination addresses, or as raw words when the destination is unknown
+func orderFieldLines(originName, destinationName string, order *orderquery.OrderData) []string {
+	return []string{
+		fmt.Sprintf("Route: %s (domain %d) → %s (domain %d)", originName, order.OriginDomain, destinationLabel(destinationName), order.DestinationDomain),
+		fmt.Sprintf("Sender: %s", formatWord(originName, order.Sender)),
+		fmt.Sprintf("Recipient: %s", formatWord(destinationName, order.Recipient)),
+		fmt.Sprintf("Input: %s of %s", order.AmountIn, formatWord(originName, order.InputToken)),
+		fmt.Sprintf("Output: %s of %s", order.AmountOut, formatWord(destinationName, order.OutputToken)),
+		fmt.Sprintf("Sender nonce: %s", order.SenderNonce),
+		fmt.Sprintf("Destination settler: %s", formatWord(destinationName, order.DestinationSettler)),
+		fmt.Sprintf("Order data type: %s", common.Hash(order.OrderDataType).Hex()),
+		fmt.Sprintf("Data: 0x%x", order.Data),
+	}
+}
+
+func destinationLabel(destinationName string) string {
+	if destinationName == "" {
+		return "unknown"
+	}
+	return destinationName
+}
+
+// formatWord shows a 32-byte word as an address of networkName: an EVM address or a felt. Words that are not a
+// valid address there, or whose network is unknown, are shown in full
+func formatWord(networkName string, word [32]byte) string {
+	if networkName == "" {
+		return common.Hash(word).Hex()
+	}
+	if openorder.GetNetworkType(networkName) == openorder.NetworkTypeEVM {
+		if address, err := starknetutil.Bytes32ToEVMAddress(word); err == nil {
+			return address.Hex()
+		}
+	} else if f, err := starknetutil.Bytes32ToFelt(word); err == nil {
+		return f.String()
+	}
+	return common.Hash(word).Hex()
+}
@@ -0,0 +1,55 @@
+package fillorder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestParseOrderStatusArgs(t *testing.T) {
+	config.InitializeNetworks()
+
+	name, orderID, err := parseOrderStatusArgs([]string{"starknet", "0x01"})
+	require.NoError(t, err)
+	assert.Equal(t, "Starknet", name)
+	assert.Equal(t, common.HexToHash("0x01"), orderID)
+
+	_, _, err = parseOrderStatusArgs([]string{"0x01"})
+	assert.ErrorContains(t, err, "usage: order-status <network> <order-id>")
+
+	_, _, err = parseOrderStatusArgs([]string{"Solana", "0x01"})
+	assert.ErrorContains(t, err, "known: ")
+}
+
+func TestOrderFieldLines(t *testing.T) {
+	var sender, recipient [32]byte
+	copy(sender[12:], common.HexToAddress("0x00000000000000000000000000000000000000aa").Bytes())
+	recipient[0] = 0x07
+	recipient[31] = 0x01
+	order := &orderquery.OrderData{
+		Sender:            sender,
+		Recipient:         recipient,
+		AmountIn:          big.NewInt(1000),
+		AmountOut:         big.NewInt(990),
+		SenderNonce:       big.NewInt(7),
+		OriginDomain:      8453,
+		DestinationDomain: 23448591,
+		Data:              []byte{0xaa},
+	}
+
+	lines := orderFieldLines("Base", "Starknet", order)
+	assert.Contains(t, lines, "Route: Base (domain 8453) → Starknet (domain 23448591)")
+	assert.Contains(t, lines, "Sender: 0x00000000000000000000000000000000000000AA")
+	assert.Contains(t, lines, "Recipient: 0x700000000000000000000000000000000000000000000000000000000000001")
+	assert.Contains(t, lines, "Data: 0xaa")
+
+	lines = orderFieldLines("Base", "", order)
+	assert.Contains(t, lines, "Route: Base (domain 8453) → unknown (domain 23448591)")
+	assert.Contains(t, lines, "Recipient: "+common.Hash(recipient).Hex())
+}
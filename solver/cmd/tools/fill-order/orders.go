@@ -0,0 +1,248 @@
+package fillorder
+
+// Orders tool: lists and shows the orders recorded in the local order store by open-order
+// With --refresh every order's status is read back from chain (origin first, then the destination
+// while the origin still reports OPENED) and the store is updated
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// RefreshFlag makes the orders tool read every status back from chain
+const RefreshFlag = "--refresh"
+
+// ordersRequest is a parsed orders command: list, or show with an order ID
+type ordersRequest struct {
+	Command string
+	OrderID common.Hash
+	Refresh bool
+}
+
+// RunOrders runs `orders list [--refresh]` or `orders show <order-id> [--refresh]`
+func RunOrders(ctx context.Context, args []string) {
+	req, err := parseOrdersArgs(args)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if req.Refresh {
+		if _, err := config.LoadConfig(); err != nil {
+			fmt.Printf("❌ failed to load config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	store := localOrderStore()
+	if req.Command == "show" {
+		err = showOrder(ctx, store, req.OrderID, req.Refresh)
+	} else {
+		err = listOrders(ctx, store, req.Refresh)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// listOrders prints one line per stored order, oldest first
+func listOrders(ctx context.Context, store *orderstore.Store, refresh bool) error {
+	orders, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(orders) == 0 {
+		fmt.Printf("📭 No orders recorded in %s\n", store.Dir())
+		return nil
+	}
+
+	fmt.Printf("📋 %d order(s) in %s:\n", len(orders), store.Dir())
+	now := uint64(time.Now().Unix())
+	for _, o := range orders {
+		note := ""
+		if refresh {
+			note = refreshStoredOrder(ctx, store, o)
+		}
+		fmt.Printf("   %s  %s → %s  %-8s  %s%s\n", o.OrderID, o.OriginChain, o.DestinationChain, o.Status, deadlineNote(o.FillDeadline, now), note)
+	}
+	return nil
+}
+
+// showOrder prints every stored field of one order
+func showOrder(ctx context.Context, store *orderstore.Store, orderID common.Hash, refresh bool) error {
+	o, err := lookupStoredOrder(store, orderID)
+	if err != nil {
+		return err
+	}
+	note := ""
+	if refresh {
+		note = refreshStoredOrder(ctx, store, o)
+	}
+
+	fmt.Printf("📋 Order %s\n", o.OrderID)
+	fmt.Printf("   Route: %s → %s\n", o.OriginChain, o.DestinationChain)
+	fmt.Printf("   Status: %s%s\n", o.Status, note)
+	fmt.Printf("   Open tx: %s\n", o.TxHash)
+	if o.User != "" {
+		fmt.Printf("   User: %s\n", o.User)
+	}
+	if o.Gasless {
+		fmt.Printf("   Gasless: open deadline %d\n", o.OpenDeadline)
+	}
+	fmt.Printf("   Fill deadline: %d (%s)\n", o.FillDeadline, deadlineNote(o.FillDeadline, uint64(time.Now().Unix())))
+	if o.OrderDataType != "" {
+		fmt.Printf("   Order data type: %s\n", o.OrderDataType)
+	}
+	if o.OrderData != "" {
+		fmt.Printf("   Order data: %s\n", o.OrderData)
+	}
+	fmt.Printf("   Recorded: %s (updated %s)\n", o.CreatedAt, o.UpdatedAt)
+	return nil
+}
+
+// refreshStoredOrder reads the order status from chain and records it, returning a note for the printed line
+func refreshStoredOrder(ctx context.Context, store *orderstore.Store, o *orderstore.Order) string {
+	status, err := chainOrderStatus(ctx, o)
+	if err != nil {
+		return fmt.Sprintf(" (refresh failed: %v)", err)
+	}
+	if status == o.Status {
+		return ""
+	}
+	previous := o.Status
+	o.Status = status
+	if err := store.UpdateStatus(o.OrderID, status); err != nil {
+		return fmt.Sprintf(" (was %s, store update failed: %v)", previous, err)
+	}
+	return fmt.Sprintf(" (was %s)", previous)
+}
+
+// chainOrderStatus reads the lifecycle status of a stored order. The origin only learns of a fill once it is
+// settled, so an order still OPENED on the origin is reported FILLED when its destination has filled it
+func chainOrderStatus(ctx context.Context, o *orderstore.Order) (string, error) {
+	orderID, err := parseOrderID(o.OrderID)
+	if err != nil {
+		return "", err
+	}
+	originName, err := networkByName(o.OriginChain)
+	if err != nil {
+		return "", fmt.Errorf("origin: %w", err)
+	}
+	originContract, err := hyperlaneAddressWord(originName)
+	if err != nil {
+		return "", err
+	}
+	status, err := orderStatusAt(ctx, originName, originContract, orderID)
+	if err != nil || status != orderStatusOpened || o.OrderData == "" {
+		return status, err
+	}
+
+	orderData, err := hexutil.Decode(o.OrderData)
+	if err != nil {
+		return "", fmt.Errorf("invalid stored order data: %w", err)
+	}
+	order, err := orderquery.ParseOrderData(orderData)
+	if err != nil {
+		return "", err
+	}
+	destinationName, err := networkByDomain(order.DestinationDomain)
+	if err != nil {
+		return "", fmt.Errorf("destination: %w", err)
+	}
+	destinationStatus, err := orderStatusAt(ctx, destinationName, order.DestinationSettler, orderID)
+	if err != nil {
+		return "", err
+	}
+	if destinationStatus == orderStatusFilled {
+		return orderStatusFilled, nil
+	}
+	return status, nil
+}
+
+// deadlineNote describes a fill deadline relative to now
+func deadlineNote(fillDeadline, now uint64) string {
+	switch {
+	case fillDeadline == 0:
+		return "no fill deadline recorded"
+	case now > fillDeadline:
+		return fmt.Sprintf("expired %s ago", time.Duration(now-fillDeadline)*time.Second)
+	default:
+		return fmt.Sprintf("fill deadline in %s", time.Duration(fillDeadline-now)*time.Second)
+	}
+}
+
+// parseOrdersArgs reads the orders subcommand and its arguments
+func parseOrdersArgs(args []string) (ordersRequest, error) {
+	var req ordersRequest
+	var positional []string
+	for _, arg := range args {
+		if arg == RefreshFlag {
+			req.Refresh = true
+			continue
+		}
+		positional = append(positional, arg)
+	}
+
+	switch {
+	case len(positional) == 1 && positional[0] == "list":
+		req.Command = "list"
+	case len(positional) == 2 && positional[0] == "show":
+		orderID, err := parseOrderID(positional[1])
+		if err != nil {
+			return ordersRequest{}, err
+		}
+		req.Command, req.OrderID = "show", orderID
+	default:
+		return ordersRequest{}, fmt.Errorf("usage: orders list [%s] | orders show <order-id> [%s]", RefreshFlag, RefreshFlag)
+	}
+	return req, nil
+}
+
+// localOrderStore opens the order store written by open-order
+func localOrderStore() *orderstore.Store {
+	return orderstore.New(orderstore.DefaultDir())
+}
+
+// lookupStoredOrder returns the stored record of an order, explaining where it was looked for when missing
+func lookupStoredOrder(store *orderstore.Store, orderID common.Hash) (*orderstore.Order, error) {
+	o, err := store.Get(orderID.Hex())
+	if errors.Is(err, orderstore.ErrNotFound) {
+		return nil, fmt.Errorf("order %s is not recorded in %s (pass the origin chain explicitly)", orderID.Hex(), store.Dir())
+	}
+	return o, err
+}
+
+// storedOrderResult turns a stored order back into the open-order result it was recorded from
+func storedOrderResult(o *orderstore.Order) *openorder.OrderResult {
+	return &openorder.OrderResult{
+		OrderID:          o.OrderID,
+		TxHash:           o.TxHash,
+		OriginChain:      o.OriginChain,
+		DestinationChain: o.DestinationChain,
+		Status:           openorder.OrderStatusOpened,
+		Gasless:          o.Gasless,
+		OpenDeadline:     o.OpenDeadline,
+		FillDeadline:     o.FillDeadline,
+		OrderDataType:    o.OrderDataType,
+		OrderData:        o.OrderData,
+	}
+}
+
+// recordStatus updates the stored status of an order; orders that were never recorded are left alone
+func recordStatus(store *orderstore.Store, orderID common.Hash, status string) {
+	err := store.UpdateStatus(orderID.Hex(), status)
+	if err != nil && !errors.Is(err, orderstore.ErrNotFound) {
+		fmt.Printf("⚠️  failed to record status %s for %s: %v\n", status, orderID.Hex(), err)
+	}
+}
@@ -0,0 +1,74 @@
+package fillorder
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+)
+
+func TestParseOrdersArgs(t *testing.T) {
+	req, err := parseOrdersArgs([]string{"list"})
+	require.NoError(t, err)
+	assert.Equal(t, "list", req.Command)
+	assert.False(t, req.Refresh)
+
+	req, err = parseOrdersArgs([]string{RefreshFlag, "show", "0xabc"})
+	require.NoError(t, err)
+	assert.Equal(t, "show", req.Command)
+	assert.Equal(t, common.HexToHash("0xabc"), req.OrderID)
+	assert.True(t, req.Refresh)
+
+	for name, args := range map[string][]string{
+		"no command":      nil,
+		"unknown command": {"delete"},
+		"show without ID": {"show"},
+		"bad ID":          {"show", "nope"},
+		"list with ID":    {"list", "0xabc"},
+	} {
+		_, err := parseOrdersArgs(args)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDeadlineNote(t *testing.T) {
+	assert.Equal(t, "no fill deadline recorded", deadlineNote(0, 100))
+	assert.Equal(t, "expired 1m0s ago", deadlineNote(1000, 1060))
+	assert.Equal(t, "fill deadline in 2h0m0s", deadlineNote(8200, 1000))
+}
+
+func TestStoredOrderResult(t *testing.T) {
+	stored := &orderstore.Order{
+		OrderID:       "0x01",
+		OriginChain:   "Base",
+		Gasless:       true,
+		OpenDeadline:  5,
+		FillDeadline:  6,
+		OrderDataType: "0x02",
+		OrderData:     "0x03",
+	}
+	result := storedOrderResult(stored)
+	assert.Equal(t, "Base", result.OriginChain)
+	assert.True(t, result.Gasless)
+	assert.Equal(t, uint64(5), result.OpenDeadline)
+	assert.Equal(t, uint64(6), result.FillDeadline)
+	assert.Equal(t, "0x02", result.OrderDataType)
+	assert.Equal(t, "0x03", result.OrderData)
+}
+
+func TestRecordStatus(t *testing.T) {
+	store := orderstore.New(t.TempDir())
+	orderID := common.HexToHash("0x01")
+	require.NoError(t, store.Save(&orderstore.Order{OrderID: orderID.Hex(), Status: orderstore.StatusOpened}))
+
+	recordStatus(store, orderID, orderStatusFilled)
+	stored, err := store.Get(orderID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, orderstore.StatusFilled, stored.Status)
+
+	// Orders opened elsewhere are not in the store and are silently skipped
+	recordStatus(store, common.HexToHash("0x02"), orderStatusFilled)
+}
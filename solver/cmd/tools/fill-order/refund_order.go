@@ -0,0 +1,301 @@
+package fillorder
+
+// Refund tool: refunds an order that expired without being filled
+// refund() on the destination Hyperlane7683 checks the order was never filled and its fillDeadline has passed,
+// then dispatches a Hyperlane message to the origin chain, which returns amountIn of the input token to the
+// sender and marks the order REFUNDED
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	orderStatusOpened   = "OPENED"
+	orderStatusRefunded = "REFUNDED"
+)
+
+// refundRequest identifies the order to refund. Result is set when it came from an open-order --json result
+type refundRequest struct {
+	OrderID     common.Hash
+	OriginChain string
+	Result      *openorder.OrderResult
+	Timeout     time.Duration
+}
+
+// refundOrder is the order struct passed to refund(), rebuilt from an open-order result or from openOrders
+type refundOrder struct {
+	OrderID       common.Hash
+	OrderDataType [32]byte
+	OrderData     []byte
+	FillDeadline  uint64
+	Order         *originOrder
+
+	// Gasless orders are refunded through the GaslessCrossChainOrder overload, which also needs
+	// the origin settler and the open deadline they were signed with
+	Gasless       bool
+	OpenDeadline  uint64
+	OriginSettler [32]byte
+}
+
+// RunRefundOrder refunds the order described by args: <order-id> <origin-chain>, an order ID recorded in the
+// local order store, or the path to an open-order --json result ("-" reads it from stdin),
+// optionally followed by --timeout <duration>
+func RunRefundOrder(ctx context.Context, args []string) error {
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := localOrderStore()
+	req, err := parseRefundArgs(args, os.Stdin, store)
+	if err != nil {
+		return err
+	}
+
+	if err := refundExpiredOrder(ctx, req); err != nil {
+		return err
+	}
+	recordStatus(store, req.OrderID, orderStatusRefunded)
+	return nil
+}
+
+// refundExpiredOrder checks the order can be refunded, refunds it on the destination chain and waits for the
+// origin chain to mark it REFUNDED and return the input tokens to the sender
+func refundExpiredOrder(ctx context.Context, req refundRequest) error {
+	originName, err := networkByName(req.OriginChain)
+	if err != nil {
+		return fmt.Errorf("origin: %w", err)
+	}
+	originContract, err := hyperlaneAddressWord(originName)
+	if err != nil {
+		return err
+	}
+
+	r, err := loadRefundOrder(ctx, originName, req)
+	if err != nil {
+		return err
+	}
+	r.OriginSettler = originContract
+
+	destinationName, err := networkByDomain(r.Order.DestinationDomain)
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+	destination := config.Networks[destinationName]
+
+	originStatus, err := orderStatusAt(ctx, originName, originContract, r.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to read status on %s: %w", originName, err)
+	}
+	destinationStatus, err := orderStatusAt(ctx, destinationName, r.Order.DestinationSettler, r.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to read status on %s: %w", destinationName, err)
+	}
+	var now uint64
+	if openorder.GetNetworkType(destinationName) == openorder.NetworkTypeEVM {
+		now, err = evmBlockTime(ctx, destination)
+	} else {
+		now, err = starknetBlockTime(ctx, destination)
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkRefundable(originStatus, destinationStatus, r.FillDeadline, now); err != nil {
+		return fmt.Errorf("refusing to refund %s: %w", r.OrderID.Hex(), err)
+	}
+
+	nativeInput := r.Order.InputToken == [32]byte{}
+	var before *big.Int
+	if !nativeInput {
+		before, err = tokenBalanceAt(ctx, originName, r.Order.InputToken, r.Order.Sender, nil)
+		if err != nil {
+			return fmt.Errorf("failed to read sender input token balance on %s: %w", originName, err)
+		}
+	}
+
+	fmt.Printf("📤 Refunding order %s on %s (fill deadline %d passed at %d)...\n", r.OrderID.Hex(), destinationName, r.FillDeadline, now)
+	o := &trackedOrder{OrderID: r.OrderID, Order: r.Order}
+	if openorder.GetNetworkType(destinationName) == openorder.NetworkTypeEVM {
+		o.TxHash, err = refundEVMOrder(ctx, destination, r)
+	} else {
+		o.TxHash, err = refundStarknetOrder(ctx, destination, r)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("⏳ Waiting up to %s for %s to mark the order %s...\n", req.Timeout, originName, orderStatusRefunded)
+	readOrigin := func(ctx context.Context, orderID common.Hash) (string, error) {
+		return orderStatusAt(ctx, originName, originContract, orderID)
+	}
+	waitForOriginStatus(ctx, []*trackedOrder{o}, readOrigin, orderStatusRefunded, req.Timeout, originPollInterval)
+	if o.Err != nil {
+		return o.Err
+	}
+
+	fmt.Printf("✅ Order %s refunded (refund tx %s)\n", r.OrderID.Hex(), o.TxHash)
+	if nativeInput {
+		fmt.Printf("   Input token is native, skipping the balance check\n")
+		return nil
+	}
+	after, err := tokenBalanceAt(ctx, originName, r.Order.InputToken, r.Order.Sender, before)
+	if err != nil {
+		return fmt.Errorf("order is %s but the sender balance did not change: %w", orderStatusRefunded, err)
+	}
+	if delta := new(big.Int).Sub(after, before); delta.Cmp(r.Order.AmountIn) != 0 {
+		return fmt.Errorf("sender balance changed by %s, expected the order amountIn %s", delta, r.Order.AmountIn)
+	}
+	fmt.Printf("   Sender balance restored: %s → %s (+%s)\n", before, after, r.Order.AmountIn)
+	return nil
+}
+
+// loadRefundOrder rebuilds the order struct from the open-order result (or order store record) when it carries one,
+// and otherwise from openOrders on the origin chain
+func loadRefundOrder(ctx context.Context, originName string, req refundRequest) (*refundOrder, error) {
+	if req.Result != nil && req.Result.OrderData != "" {
+		return refundOrderFromResult(req.Result, req.OrderID)
+	}
+
+	origin, err := queryNetwork(originName)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("🔍 Loading order %s from %s...\n", req.OrderID.Hex(), originName)
+	raw, err := orderquery.ReadOpenOrder(ctx, origin, req.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read open order from %s: %w", originName, err)
+	}
+	return refundOrderFromOpenOrder(req.OrderID, raw)
+}
+
+// refundOrderFromResult rebuilds the order struct recorded by open-order --json
+func refundOrderFromResult(result *openorder.OrderResult, orderID common.Hash) (*refundOrder, error) {
+	orderDataType, err := hexutil.Decode(result.OrderDataType)
+	if err != nil || len(orderDataType) != wordSize {
+		return nil, fmt.Errorf("invalid orderDataType %q in open-order result", result.OrderDataType)
+	}
+	orderData, err := hexutil.Decode(result.OrderData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid orderData in open-order result: %w", err)
+	}
+
+	r, err := newRefundOrder(orderID, common.BytesToHash(orderDataType), orderData)
+	if err != nil {
+		return nil, err
+	}
+	if result.FillDeadline != 0 {
+		r.FillDeadline = result.FillDeadline
+	}
+	r.Gasless = result.Gasless
+	r.OpenDeadline = result.OpenDeadline
+	return r, nil
+}
+
+// refundOrderFromOpenOrder rebuilds the order struct from abi.encode(orderDataType, orderData) stored in openOrders.
+// openOrders does not record how the order was opened; the order ID only depends on the order data, so the
+// OnchainCrossChainOrder overload refunds gasless orders too
+func refundOrderFromOpenOrder(orderID common.Hash, raw []byte) (*refundOrder, error) {
+	orderData, err := orderquery.OpenOrderData(raw)
+	if err != nil {
+		return nil, fmt.Errorf("order %s: %w", orderID.Hex(), err)
+	}
+	return newRefundOrder(orderID, common.BytesToHash(raw[:wordSize]), orderData)
+}
+
+// newRefundOrder checks the order data hashes to the order ID and reads its fields
+func newRefundOrder(orderID common.Hash, orderDataType [32]byte, orderData []byte) (*refundOrder, error) {
+	if computed := crypto.Keccak256Hash(orderData); computed != orderID {
+		return nil, fmt.Errorf("order data hashes to %s, not order %s", computed.Hex(), orderID.Hex())
+	}
+	order, err := orderquery.ParseOrderData(orderData)
+	if err != nil {
+		return nil, err
+	}
+	return &refundOrder{
+		OrderID:       orderID,
+		OrderDataType: orderDataType,
+		OrderData:     orderData,
+		FillDeadline:  order.FillDeadline,
+		Order:         order,
+	}, nil
+}
+
+// checkRefundable mirrors the contract checks: the origin still holds the deposit, the destination never
+// filled the order, and the destination chain is past the fill deadline
+func checkRefundable(originStatus, destinationStatus string, fillDeadline, now uint64) error {
+	if originStatus != orderStatusOpened {
+		return fmt.Errorf("origin status is %s, expected %s", originStatus, orderStatusOpened)
+	}
+	if destinationStatus != orderStatusUnknown {
+		return fmt.Errorf("destination status is %s, expected %s", destinationStatus, orderStatusUnknown)
+	}
+	if now <= fillDeadline {
+		return fmt.Errorf("fill deadline %d has not passed (destination block time %d, %s to go)",
+			fillDeadline, now, time.Duration(fillDeadline-now+1)*time.Second)
+	}
+	return nil
+}
+
+// tokenBalanceAt reads an ERC20 balance on any configured network; with previous set it waits for the balance to change
+func tokenBalanceAt(ctx context.Context, networkName string, token, owner [32]byte, previous *big.Int) (*big.Int, error) {
+	network := config.Networks[networkName]
+	if openorder.GetNetworkType(networkName) == openorder.NetworkTypeEVM {
+		return evmTokenBalance(ctx, network, token, owner, previous)
+	}
+	return starknetTokenBalance(ctx, network, token, owner, previous)
+}
+
+// parseRefundArgs reads the order to refund and the optional --timeout from the command line
+func parseRefundArgs(args []string, stdin io.Reader, store *orderstore.Store) (refundRequest, error) {
+	positional, timeout, err := stripTimeoutFlag(args)
+	if err != nil {
+		return refundRequest{}, err
+	}
+	req := refundRequest{Timeout: defaultOriginTimeout}
+	if timeout > 0 {
+		req.Timeout = timeout
+	}
+
+	switch len(positional) {
+	case 1:
+		if orderID, err := parseOrderID(positional[0]); err == nil {
+			stored, err := lookupStoredOrder(store, orderID)
+			if err != nil {
+				return refundRequest{}, err
+			}
+			req.OrderID, req.OriginChain, req.Result = orderID, stored.OriginChain, storedOrderResult(stored)
+			return req, nil
+		}
+		data, err := readOrderResult(positional[0], stdin)
+		if err != nil {
+			return refundRequest{}, err
+		}
+		result, orderID, err := decodeOrderResult(data)
+		if err != nil {
+			return refundRequest{}, err
+		}
+		req.OrderID, req.OriginChain, req.Result = orderID, result.OriginChain, result
+	case 2:
+		orderID, err := parseOrderID(positional[0])
+		if err != nil {
+			return refundRequest{}, err
+		}
+		req.OrderID, req.OriginChain = orderID, positional[1]
+	default:
+		return refundRequest{}, fmt.Errorf("usage: refund-order <order-id> [<origin-chain>] | refund-order <open-order-result.json|-> [%s <duration>]", TimeoutFlag)
+	}
+	return req, nil
+}
@@ -0,0 +1,188 @@
+package fillorder
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery/orderquerytest"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+func TestParseRefundArgs(t *testing.T) {
+	orderID := "0x9f4a7c1b2d3e4f5061728394a5b6c7d8e9f00112233445566778899aabbccdd"
+
+	t.Run("order ID and origin", func(t *testing.T) {
+		req, err := parseRefundArgs([]string{orderID, "Base"}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash(orderID), req.OrderID)
+		assert.Equal(t, "Base", req.OriginChain)
+		assert.Nil(t, req.Result)
+		assert.Equal(t, defaultOriginTimeout, req.Timeout)
+	})
+
+	t.Run("open-order JSON keeps the order struct", func(t *testing.T) {
+		body := `{"orderId":"` + orderID + `","originChain":"Ethereum","status":"opened","gasless":true,"orderData":"0x01"}`
+		req, err := parseRefundArgs([]string{"-", TimeoutFlag, "2m"}, strings.NewReader(body), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Ethereum", req.OriginChain)
+		require.NotNil(t, req.Result)
+		assert.True(t, req.Result.Gasless)
+		assert.Equal(t, "0x01", req.Result.OrderData)
+		assert.Equal(t, 2*time.Minute, req.Timeout)
+	})
+
+	t.Run("order ID alone is rebuilt from the order store", func(t *testing.T) {
+		store := orderstore.New(t.TempDir())
+		require.NoError(t, store.Save(&orderstore.Order{
+			OrderID:      common.HexToHash(orderID).Hex(),
+			OriginChain:  "Starknet",
+			FillDeadline: 1700000000,
+			OrderData:    "0x01",
+		}))
+
+		req, err := parseRefundArgs([]string{orderID}, nil, store)
+		require.NoError(t, err)
+		assert.Equal(t, "Starknet", req.OriginChain)
+		require.NotNil(t, req.Result)
+		assert.Equal(t, uint64(1700000000), req.Result.FillDeadline)
+		assert.Equal(t, "0x01", req.Result.OrderData)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for name, args := range map[string][]string{
+			"no arguments":     nil,
+			"too many":         {orderID, "Base", "extra"},
+			"bad order ID":     {"nope", "Base"},
+			"missing duration": {orderID, "Base", TimeoutFlag},
+		} {
+			_, err := parseRefundArgs(args, nil, nil)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestRefundOrderFromResult(t *testing.T) {
+	orderData := orderquerytest.OrderData(t)
+	orderID := crypto.Keccak256Hash(orderData)
+	orderDataType := common.Hash{0x01}
+
+	result := &openorder.OrderResult{
+		Gasless:       true,
+		OpenDeadline:  1690000000,
+		FillDeadline:  1700000001,
+		OrderDataType: hexutil.Encode(orderDataType[:]),
+		OrderData:     hexutil.Encode(orderData),
+	}
+
+	t.Run("rebuilds the gasless order", func(t *testing.T) {
+		r, err := refundOrderFromResult(result, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, orderID, r.OrderID)
+		assert.Equal(t, [32]byte(orderDataType), r.OrderDataType)
+		assert.Equal(t, orderData, r.OrderData)
+		assert.True(t, r.Gasless)
+		assert.Equal(t, uint64(1690000000), r.OpenDeadline)
+		assert.Equal(t, uint64(1700000001), r.FillDeadline, "the struct deadline wins over the order data")
+		assert.Equal(t, int64(1000), r.Order.AmountIn.Int64())
+	})
+
+	t.Run("order data must hash to the order ID", func(t *testing.T) {
+		_, err := refundOrderFromResult(result, common.Hash{0x02})
+		assert.ErrorContains(t, err, "hashes to")
+	})
+
+	t.Run("order data type must be a word", func(t *testing.T) {
+		bad := *result
+		bad.OrderDataType = "0x01"
+		_, err := refundOrderFromResult(&bad, orderID)
+		assert.ErrorContains(t, err, "invalid orderDataType")
+	})
+}
+
+func TestRefundOrderFromOpenOrder(t *testing.T) {
+	orderData := orderquerytest.OrderData(t)
+	orderID := crypto.Keccak256Hash(orderData)
+
+	r, err := refundOrderFromOpenOrder(orderID, orderquerytest.EVMOpenOrder(t, orderData))
+	require.NoError(t, err)
+	assert.Equal(t, [32]byte{0x01}, r.OrderDataType)
+	assert.Equal(t, orderData, r.OrderData)
+	assert.Equal(t, uint64(1700000000), r.FillDeadline)
+	assert.False(t, r.Gasless)
+
+	_, err = refundOrderFromOpenOrder(orderID, nil)
+	assert.ErrorContains(t, err, "order not found")
+}
+
+func TestCheckRefundable(t *testing.T) {
+	const deadline = 1700000000
+
+	require.NoError(t, checkRefundable(orderStatusOpened, orderStatusUnknown, deadline, deadline+1))
+
+	err := checkRefundable(orderStatusOpened, orderStatusUnknown, deadline, deadline)
+	assert.ErrorContains(t, err, "has not passed")
+	err = checkRefundable(orderStatusOpened, orderStatusUnknown, deadline, deadline-59)
+	assert.ErrorContains(t, err, "1m0s to go")
+
+	err = checkRefundable(orderStatusSettled, orderStatusUnknown, deadline, deadline+1)
+	assert.ErrorContains(t, err, "origin status is SETTLED")
+	err = checkRefundable(orderStatusOpened, orderStatusFilled, deadline, deadline+1)
+	assert.ErrorContains(t, err, "destination status is FILLED")
+}
+
+func TestRefundCalldata(t *testing.T) {
+	orderData := orderquerytest.OrderData(t)
+	r, err := newRefundOrder(crypto.Keccak256Hash(orderData), [32]byte{31: 0x07}, orderData)
+	require.NoError(t, err)
+	value := big.NewInt(4242)
+	orderBytes := starknetutil.ToCairoBytes(orderData)
+
+	t.Run("onchain order", func(t *testing.T) {
+		function, calldata, err := refundCalldata(r, value)
+		require.NoError(t, err)
+		assert.Equal(t, "refund_onchain_cross_chain_order", function)
+		require.Len(t, calldata, 1+3+len(orderBytes)+2)
+		assert.Equal(t, uint64(1), calldata[0].Uint64(), "one order")
+		assert.Equal(t, uint64(1700000000), calldata[1].Uint64())
+		assert.Equal(t, int64(7), starknetutil.FromU256(calldata[2], calldata[3]).Int64())
+		assert.Equal(t, orderBytes, calldata[4:4+len(orderBytes)])
+		assert.Equal(t, value, starknetutil.FromU256(calldata[len(calldata)-2], calldata[len(calldata)-1]))
+	})
+
+	t.Run("gasless order", func(t *testing.T) {
+		gasless := *r
+		gasless.Gasless = true
+		gasless.OpenDeadline = 1690000000
+		gasless.OriginSettler = [32]byte{31: 0x99}
+
+		function, calldata, err := refundCalldata(&gasless, value)
+		require.NoError(t, err)
+		assert.Equal(t, "refund_gasless_cross_chain_order", function)
+		require.Len(t, calldata, 1+5+3+len(orderBytes)+2)
+		assert.Equal(t, uint64(0x99), calldata[1].Uint64(), "origin settler")
+		assert.Equal(t, uint64(1), calldata[2].Uint64(), "user is the order sender")
+		assert.Equal(t, uint64(7), calldata[3].Uint64(), "nonce is the sender nonce")
+		assert.Equal(t, uint64(10), calldata[4].Uint64(), "origin chain ID is the origin domain")
+		assert.Equal(t, uint64(1690000000), calldata[5].Uint64())
+		assert.Equal(t, uint64(1700000000), calldata[6].Uint64())
+	})
+
+	t.Run("gasless origin settler outside the field", func(t *testing.T) {
+		gasless := *r
+		gasless.Gasless = true
+		gasless.OriginSettler = [32]byte{0: 0xff}
+
+		_, _, err := refundCalldata(&gasless, value)
+		assert.ErrorContains(t, err, "invalid origin settler: word 0xff")
+	})
+}
@@ -0,0 +1,438 @@
+package fillorder
+
+// Settle tool: settles filled orders from their destination chain
+// settle(orderIds) on the destination Hyperlane7683 dispatches a Hyperlane message to the origin chain,
+// which releases the deposits to the filler; the origin orderStatus turns SETTLED once it is delivered
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// TimeoutFlag bounds how long settle-order and refund-order wait for the origin chain to update the order status
+const TimeoutFlag = "--timeout"
+
+const (
+	orderStatusSettled = "SETTLED"
+
+	// defaultOriginTimeout leaves room for the Hyperlane relayer to deliver the settle or refund message
+	defaultOriginTimeout = 5 * time.Minute
+	originPollInterval   = 5 * time.Second
+)
+
+// settleRequest identifies the orders to settle; all of them were opened on OriginChain
+type settleRequest struct {
+	OriginChain string
+	OrderIDs    []common.Hash
+	Timeout     time.Duration
+}
+
+// trackedOrder follows one order from its settle or refund transaction to its final origin status
+type trackedOrder struct {
+	OrderID common.Hash
+	Order   *originOrder
+	TxHash  string // settle or refund transaction on the destination chain
+	Status  string // last status read on the origin chain
+	// Receiver is the origin address the settle message pays, read back from the Settle event's fillerData
+	Receiver string
+	Err      error
+}
+
+// settleBatch groups the orders that share a destination settler and go out in one settle() call
+type settleBatch struct {
+	Destination string
+	Settler     [32]byte
+	Orders      []*trackedOrder
+}
+
+// statusReader reads an order status; it is swapped out in tests
+type statusReader func(ctx context.Context, orderID common.Hash) (string, error)
+
+// RunSettleOrders settles the orders described by args: [<origin-chain>] <order-id>... [--timeout <duration>].
+// Without an origin chain it is looked up in the local order store
+func RunSettleOrders(ctx context.Context, args []string) error {
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := localOrderStore()
+	req, err := parseSettleArgs(args, store)
+	if err != nil {
+		return err
+	}
+
+	orders, err := settleOrders(ctx, req)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders {
+		if o.Err == nil {
+			recordStatus(store, o.OrderID, orderStatusSettled)
+		}
+	}
+	if !printSettleResults(orders) {
+		return errors.New("not every order was settled")
+	}
+	return nil
+}
+
+// SettleResult is the outcome of settling one order
+type SettleResult struct {
+	OrderID  common.Hash
+	TxHash   string // settle transaction on the destination chain
+	Status   string // last status read on the origin chain, empty when not waited for
+	Receiver string // origin address the Settle event's fillerData pays
+	Err      error
+}
+
+// SettleOrders settles orderIDs, all opened on originChain, and records the settled ones in the local order
+// store. With a positive timeout it waits for the origin to mark them SETTLED, which needs a Hyperlane relayer;
+// a zero timeout returns once the settle transactions are confirmed. The configuration must already be loaded
+func SettleOrders(ctx context.Context, originChain string, orderIDs []common.Hash, timeout time.Duration) ([]SettleResult, error) {
+	orders, err := settleOrders(ctx, settleRequest{OriginChain: originChain, OrderIDs: orderIDs, Timeout: timeout})
+	if err != nil {
+		return nil, err
+	}
+	store := localOrderStore()
+	results := make([]SettleResult, len(orders))
+	for i, o := range orders {
+		if o.Err == nil {
+			recordStatus(store, o.OrderID, orderStatusSettled)
+		}
+		results[i] = SettleResult{OrderID: o.OrderID, TxHash: o.TxHash, Status: o.Status, Receiver: o.Receiver, Err: o.Err}
+	}
+	return results, nil
+}
+
+// settleOrders settles every order whose destination status is FILLED and waits for the origin to mark them SETTLED.
+// A zero timeout skips the wait
+func settleOrders(ctx context.Context, req settleRequest) ([]*trackedOrder, error) {
+	originName, err := networkByName(req.OriginChain)
+	if err != nil {
+		return nil, fmt.Errorf("origin: %w", err)
+	}
+
+	orders := make([]*trackedOrder, 0, len(req.OrderIDs))
+	for _, orderID := range req.OrderIDs {
+		o := &trackedOrder{OrderID: orderID}
+		orders = append(orders, o)
+		_, o.Order, o.Err = loadOriginOrder(ctx, originName, orderID)
+	}
+
+	for _, batch := range groupByDestination(orders) {
+		settleOrderBatch(ctx, originName, batch)
+	}
+
+	if req.Timeout <= 0 {
+		return orders, nil
+	}
+	originContract, err := hyperlaneAddressWord(originName)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("⏳ Waiting up to %s for %s to mark the orders %s...\n", req.Timeout, originName, orderStatusSettled)
+	readOrigin := func(ctx context.Context, orderID common.Hash) (string, error) {
+		return orderStatusAt(ctx, originName, originContract, orderID)
+	}
+	waitForOriginStatus(ctx, orders, readOrigin, orderStatusSettled, req.Timeout, originPollInterval)
+	return orders, nil
+}
+
+// settleOrderBatch checks every order is FILLED on the destination and settles those that are in one call
+func settleOrderBatch(ctx context.Context, originName string, batch *settleBatch) {
+	var ready []*trackedOrder
+	for _, o := range batch.Orders {
+		status, err := orderStatusAt(ctx, batch.Destination, batch.Settler, o.OrderID)
+		switch {
+		case err != nil:
+			o.Err = fmt.Errorf("failed to read status on %s: %w", batch.Destination, err)
+		case status != orderStatusFilled:
+			o.Err = fmt.Errorf("refusing to settle: status on %s is %s, expected %s", batch.Destination, status, orderStatusFilled)
+		default:
+			ready = append(ready, o)
+		}
+	}
+	if len(ready) == 0 {
+		return
+	}
+
+	orderIDs := make([]common.Hash, len(ready))
+	for i, o := range ready {
+		orderIDs[i] = o.OrderID
+	}
+	// The settle message travels back to the origin, so the gas payment is quoted for the origin domain
+	originDomain := ready[0].Order.OriginDomain
+	destination := config.Networks[batch.Destination]
+
+	fmt.Printf("📤 Settling %d order(s) on %s...\n", len(orderIDs), batch.Destination)
+	var txHash string
+	var fillerData map[common.Hash][]byte
+	var err error
+	if openorder.GetNetworkType(batch.Destination) == openorder.NetworkTypeEVM {
+		txHash, fillerData, err = settleEVMOrders(ctx, destination, batch.Settler, originDomain, orderIDs)
+	} else {
+		txHash, fillerData, err = settleStarknetOrders(ctx, destination, batch.Settler, originDomain, orderIDs)
+	}
+	if err != nil {
+		for _, o := range ready {
+			o.Err = err
+		}
+		return
+	}
+
+	// What we encode for this solver is what settlement pays, unless another solver filled the order
+	expected, expectedErr := solverFillerData(originName)
+	for _, o := range ready {
+		o.TxHash = txHash
+		o.Receiver, err = settledReceiver(originName, fillerData, o.OrderID, expected, expectedErr)
+		if err != nil {
+			fmt.Printf("   ⚠️  %s: %v\n", o.OrderID.Hex(), err)
+		}
+	}
+}
+
+// settledReceiver recovers the receiver of orderID from the Settle event's fillerData and checks it is the one
+// expected, the fillerData this solver fills with
+func settledReceiver(originName string, fillerData map[common.Hash][]byte, orderID common.Hash, expected []byte, expectedErr error) (string, error) {
+	data, ok := fillerData[orderID]
+	if !ok {
+		return "", errors.New("the settle transaction has no Settle event for this order")
+	}
+	receiver, err := fillerReceiver(originName, data)
+	if err != nil {
+		return "", fmt.Errorf("settled with fillerData 0x%x that %s cannot pay: %w", data, originName, err)
+	}
+	if expectedErr != nil {
+		return receiver, fmt.Errorf("settled to %s, not checked against this solver: %w", receiver, expectedErr)
+	}
+	if !bytes.Equal(data, expected) {
+		want, _ := fillerReceiver(originName, expected)
+		return receiver, fmt.Errorf("settled to %s, not this solver's %s; was it filled by another solver?", receiver, want)
+	}
+	return receiver, nil
+}
+
+// groupByDestination batches the loaded orders by destination settler, keeping the order they were given in
+func groupByDestination(orders []*trackedOrder) []*settleBatch {
+	var batches []*settleBatch
+	index := make(map[string]*settleBatch)
+	for _, o := range orders {
+		if o.Err != nil {
+			continue
+		}
+		destination, err := networkByDomain(o.Order.DestinationDomain)
+		if err != nil {
+			o.Err = fmt.Errorf("destination: %w", err)
+			continue
+		}
+
+		key := destination + "/" + common.Hash(o.Order.DestinationSettler).Hex()
+		batch, ok := index[key]
+		if !ok {
+			batch = &settleBatch{Destination: destination, Settler: o.Order.DestinationSettler}
+			index[key] = batch
+			batches = append(batches, batch)
+		}
+		batch.Orders = append(batch.Orders, o)
+	}
+	return batches
+}
+
+// waitForOriginStatus polls the origin status of every submitted order until it reaches want or the timeout expires
+func waitForOriginStatus(ctx context.Context, orders []*trackedOrder, read statusReader, want string, timeout, interval time.Duration) {
+	var pending []*trackedOrder
+	for _, o := range orders {
+		if o.Err == nil && o.TxHash != "" {
+			pending = append(pending, o)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for len(pending) > 0 {
+		remaining := pending[:0]
+		for _, o := range pending {
+			// Read errors are retried on the next tick
+			if status, err := read(ctx, o.OrderID); err == nil {
+				o.Status = status
+			}
+			if o.Status != want {
+				remaining = append(remaining, o)
+			}
+		}
+		pending = remaining
+		if len(pending) == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			for _, o := range pending {
+				o.Err = fmt.Errorf("transaction %s sent but origin status is %s after %s, expected %s", o.TxHash, statusOrUnknown(o.Status), timeout, want)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// printSettleResults prints one line per order and reports whether all of them settled
+func printSettleResults(orders []*trackedOrder) bool {
+	ok := true
+	fmt.Println("📋 Settlement results:")
+	for _, o := range orders {
+		if o.Err != nil {
+			ok = false
+			fmt.Printf("   ❌ %s: %v\n", o.OrderID.Hex(), o.Err)
+			continue
+		}
+		fmt.Printf("   ✅ %s: %s (settle tx %s, pays %s)\n", o.OrderID.Hex(), o.Status, o.TxHash, receiverOrUnknown(o.Receiver))
+	}
+	return ok
+}
+
+// receiverOrUnknown names a receiver that could not be read back
+func receiverOrUnknown(receiver string) string {
+	if receiver == "" {
+		return "unknown receiver"
+	}
+	return receiver
+}
+
+// statusOrUnknown names an order status that was never read
+func statusOrUnknown(status string) string {
+	if status == "" {
+		return orderStatusUnknown
+	}
+	return status
+}
+
+// parseSettleArgs reads the origin chain, order IDs and optional --timeout from the command line.
+// When the first argument is already an order ID, the origin comes from the order store
+func parseSettleArgs(args []string, store *orderstore.Store) (settleRequest, error) {
+	req := settleRequest{Timeout: defaultOriginTimeout}
+	positional, timeout, err := stripTimeoutFlag(args)
+	if err != nil {
+		return settleRequest{}, err
+	}
+	if timeout > 0 {
+		req.Timeout = timeout
+	}
+
+	usage := fmt.Errorf("usage: settle-order [<origin-chain>] <order-id>... [%s <duration>]", TimeoutFlag)
+	if len(positional) == 0 {
+		return settleRequest{}, usage
+	}
+	ids := positional
+	if _, err := parseOrderID(positional[0]); err != nil {
+		if len(positional) < 2 {
+			return settleRequest{}, usage
+		}
+		req.OriginChain, ids = positional[0], positional[1:]
+	}
+
+	seen := make(map[common.Hash]bool)
+	for _, arg := range ids {
+		orderID, err := parseOrderID(arg)
+		if err != nil {
+			return settleRequest{}, err
+		}
+		if seen[orderID] {
+			return settleRequest{}, fmt.Errorf("order %s is listed twice", orderID.Hex())
+		}
+		seen[orderID] = true
+		req.OrderIDs = append(req.OrderIDs, orderID)
+	}
+
+	if req.OriginChain == "" {
+		origin, err := storedOrigin(store, req.OrderIDs)
+		if err != nil {
+			return settleRequest{}, err
+		}
+		req.OriginChain = origin
+	}
+	return req, nil
+}
+
+// storedOrigin returns the origin chain the order store records for the orders, which must all share it
+func storedOrigin(store *orderstore.Store, orderIDs []common.Hash) (string, error) {
+	var origin string
+	for _, orderID := range orderIDs {
+		stored, err := lookupStoredOrder(store, orderID)
+		if err != nil {
+			return "", err
+		}
+		if origin != "" && stored.OriginChain != origin {
+			return "", fmt.Errorf("orders were opened on different origins (%s and %s); settle each origin separately", origin, stored.OriginChain)
+		}
+		origin = stored.OriginChain
+	}
+	return origin, nil
+}
+
+// stripTimeoutFlag removes --timeout <duration> from args; the returned timeout is zero when the flag is absent
+func stripTimeoutFlag(args []string) ([]string, time.Duration, error) {
+	var positional []string
+	var timeout time.Duration
+	for i := 0; i < len(args); i++ {
+		if args[i] != TimeoutFlag {
+			positional = append(positional, args[i])
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, 0, fmt.Errorf("%s requires a duration (e.g. 10m)", TimeoutFlag)
+		}
+		d, err := time.ParseDuration(args[i+1])
+		if err != nil || d <= 0 {
+			return nil, 0, fmt.Errorf("invalid %s %q: expected a positive duration (e.g. 10m)", TimeoutFlag, args[i+1])
+		}
+		timeout = d
+		i++
+	}
+	return positional, timeout, nil
+}
+
+// hyperlaneAddressWord returns the configured Hyperlane7683 address of a network as a 32-byte word
+func hyperlaneAddressWord(networkName string) ([32]byte, error) {
+	address := config.Networks[networkName].HyperlaneAddress
+	if address == "" {
+		return [32]byte{}, fmt.Errorf("no Hyperlane address configured for %s (set %s_HYPERLANE_ADDRESS)", networkName, strings.ToUpper(networkName))
+	}
+	if openorder.GetNetworkType(networkName) == openorder.NetworkTypeEVM {
+		return starknetutil.EVMAddressToBytes32(common.HexToAddress(address)), nil
+	}
+	f, err := utils.HexToFelt(address)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("invalid %s Hyperlane address %q: %w", networkName, address, err)
+	}
+	return starknetutil.FeltToBytes32(f), nil
+}
+
+// orderStatusAt reads orderStatus(orderId) from a Hyperlane7683 contract on any configured network
+func orderStatusAt(ctx context.Context, networkName string, contractAddr [32]byte, orderID common.Hash) (string, error) {
+	network, err := settlerNetwork(networkName, contractAddr)
+	if err != nil {
+		return "", err
+	}
+	status, err := orderquery.GetOrderStatus(ctx, network, orderID)
+	if err != nil {
+		return "", err
+	}
+	return status.String(), nil
+}
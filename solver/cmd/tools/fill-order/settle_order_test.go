@@ -0,0 +1,212 @@
+package fillorder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/fillerdata"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestParseSettleArgs(t *testing.T) {
+	id1 := "0x9f4a7c1b2d3e4f5061728394a5b6c7d8e9f00112233445566778899aabbccdd"
+	id2 := "0x01"
+
+	t.Run("origin and several orders", func(t *testing.T) {
+		req, err := parseSettleArgs([]string{"Base", id1, id2}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Base", req.OriginChain)
+		assert.Equal(t, []common.Hash{common.HexToHash(id1), common.HexToHash(id2)}, req.OrderIDs)
+		assert.Equal(t, defaultOriginTimeout, req.Timeout)
+	})
+
+	t.Run("timeout anywhere in the arguments", func(t *testing.T) {
+		req, err := parseSettleArgs([]string{"Base", TimeoutFlag, "90s", id1}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 90*time.Second, req.Timeout)
+		assert.Len(t, req.OrderIDs, 1)
+	})
+
+	t.Run("origin resolved from the order store", func(t *testing.T) {
+		store := orderstore.New(t.TempDir())
+		require.NoError(t, store.Save(&orderstore.Order{OrderID: common.HexToHash(id1).Hex(), OriginChain: "Base"}))
+		require.NoError(t, store.Save(&orderstore.Order{OrderID: common.HexToHash(id2).Hex(), OriginChain: "Base"}))
+
+		req, err := parseSettleArgs([]string{id1, id2}, store)
+		require.NoError(t, err)
+		assert.Equal(t, "Base", req.OriginChain)
+		assert.Len(t, req.OrderIDs, 2)
+
+		require.NoError(t, store.Save(&orderstore.Order{OrderID: common.HexToHash(id2).Hex(), OriginChain: "Starknet"}))
+		_, err = parseSettleArgs([]string{id1, id2}, store)
+		assert.ErrorContains(t, err, "different origins")
+
+		_, err = parseSettleArgs([]string{"0x03"}, store)
+		assert.ErrorContains(t, err, "not recorded")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for name, args := range map[string][]string{
+			"no arguments":      nil,
+			"no orders":         {"Base"},
+			"missing duration":  {"Base", id1, TimeoutFlag},
+			"bad duration":      {"Base", id1, TimeoutFlag, "soon"},
+			"negative duration": {"Base", id1, TimeoutFlag, "-1m"},
+			"bad order ID":      {"Base", "nope"},
+			"duplicate order":   {"Base", id1, id1},
+		} {
+			_, err := parseSettleArgs(args, nil)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestGroupByDestination(t *testing.T) {
+	config.InitializeNetworks()
+	baseDomain := uint32(config.Networks["Base"].HyperlaneDomain)
+	starknetDomain := uint32(config.Networks["Starknet"].HyperlaneDomain)
+
+	order := func(id byte, domain uint32, settler byte) *trackedOrder {
+		return &trackedOrder{
+			OrderID: common.Hash{id},
+			Order:   &originOrder{DestinationDomain: domain, DestinationSettler: [32]byte{settler}},
+		}
+	}
+	failed := &trackedOrder{OrderID: common.Hash{9}, Err: errors.New("order not found")}
+	unknown := order(8, 0xffffffff, 1)
+	orders := []*trackedOrder{order(1, baseDomain, 1), failed, order(2, starknetDomain, 2), order(3, baseDomain, 1), unknown}
+
+	batches := groupByDestination(orders)
+	require.Len(t, batches, 2)
+	assert.Equal(t, "Base", batches[0].Destination)
+	assert.Equal(t, []*trackedOrder{orders[0], orders[3]}, batches[0].Orders)
+	assert.Equal(t, "Starknet", batches[1].Destination)
+	assert.Equal(t, []*trackedOrder{orders[2]}, batches[1].Orders)
+
+	assert.EqualError(t, failed.Err, "order not found")
+	assert.ErrorContains(t, unknown.Err, "no configured network")
+}
+
+func TestWaitForOriginStatus(t *testing.T) {
+	t.Run("polls until every order is settled", func(t *testing.T) {
+		fast := &trackedOrder{OrderID: common.Hash{1}, TxHash: "0xaa"}
+		slow := &trackedOrder{OrderID: common.Hash{2}, TxHash: "0xaa"}
+		refused := &trackedOrder{OrderID: common.Hash{3}, Err: errors.New("refusing to settle")}
+
+		reads := map[common.Hash]int{}
+		read := func(_ context.Context, orderID common.Hash) (string, error) {
+			reads[orderID]++
+			if orderID == slow.OrderID && reads[orderID] < 3 {
+				return orderStatusFilled, nil
+			}
+			return orderStatusSettled, nil
+		}
+
+		waitForOriginStatus(context.Background(), []*trackedOrder{fast, slow, refused}, read, orderStatusSettled, time.Second, time.Millisecond)
+		require.NoError(t, fast.Err)
+		require.NoError(t, slow.Err)
+		assert.Equal(t, orderStatusSettled, fast.Status)
+		assert.Equal(t, orderStatusSettled, slow.Status)
+		assert.Equal(t, 1, reads[fast.OrderID])
+		assert.Equal(t, 3, reads[slow.OrderID])
+		assert.Zero(t, reads[refused.OrderID], "orders that were not settled are not polled")
+	})
+
+	t.Run("times out with the last status", func(t *testing.T) {
+		o := &trackedOrder{OrderID: common.Hash{1}, TxHash: "0xaa"}
+		read := func(context.Context, common.Hash) (string, error) { return orderStatusFilled, nil }
+
+		waitForOriginStatus(context.Background(), []*trackedOrder{o}, read, orderStatusSettled, 20*time.Millisecond, time.Millisecond)
+		require.Error(t, o.Err)
+		assert.Contains(t, o.Err.Error(), "origin status is FILLED")
+	})
+
+	t.Run("read errors are retried", func(t *testing.T) {
+		o := &trackedOrder{OrderID: common.Hash{1}, TxHash: "0xaa"}
+		calls := 0
+		read := func(context.Context, common.Hash) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("connection reset")
+			}
+			return orderStatusSettled, nil
+		}
+
+		waitForOriginStatus(context.Background(), []*trackedOrder{o}, read, orderStatusSettled, time.Second, time.Millisecond)
+		require.NoError(t, o.Err)
+		assert.Equal(t, orderStatusSettled, o.Status)
+	})
+}
+
+func TestSettleCalldata(t *testing.T) {
+	id1 := common.HexToHash("0x00000000000000000000000000000001000000000000000000000000000000ff")
+	id2 := common.HexToHash("0x02")
+	calldata := settleCalldata([]common.Hash{id1, id2}, big.NewInt(12345))
+
+	require.Len(t, calldata, 1+2*2+2)
+	assert.Equal(t, uint64(2), calldata[0].Uint64())
+	assert.Equal(t, uint64(0xff), calldata[1].Uint64(), "low half of the first ID")
+	assert.Equal(t, uint64(1), calldata[2].Uint64(), "high half of the first ID")
+	assert.Equal(t, id2.Big(), starknetutil.FromU256(calldata[3], calldata[4]))
+	assert.Equal(t, int64(12345), starknetutil.FromU256(calldata[5], calldata[6]).Int64())
+}
+
+func TestPrintSettleResults(t *testing.T) {
+	settled := &trackedOrder{OrderID: common.Hash{1}, TxHash: "0xaa", Status: orderStatusSettled}
+	assert.True(t, printSettleResults([]*trackedOrder{settled}))
+
+	refused := &trackedOrder{OrderID: common.Hash{2}, Err: errors.New("refusing to settle")}
+	assert.False(t, printSettleResults([]*trackedOrder{settled, refused}))
+}
+
+func TestDecodeStarknetSettle(t *testing.T) {
+	id1 := common.HexToHash("0x00000000000000000000000000000001000000000000000000000000000000ff")
+	id2 := common.HexToHash("0x02")
+	receiver1 := common.HexToHash("0x02af94").Bytes()
+	receiver2 := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8").Bytes()
+
+	low1, high1 := starknetutil.ToU256(id1.Big())
+	low2, high2 := starknetutil.ToU256(id2.Big())
+	data := []*felt.Felt{utils.Uint64ToFelt(2), low1, high1, low2, high2, utils.Uint64ToFelt(2)}
+	data = append(data, starknetutil.ToCairoBytes(receiver1)...)
+	data = append(data, starknetutil.ToCairoBytes(receiver2)...)
+
+	fillerData, err := decodeStarknetSettle(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[common.Hash][]byte{id1: receiver1, id2: receiver2}, fillerData)
+
+	_, err = decodeStarknetSettle(data[:len(data)-1])
+	assert.ErrorContains(t, err, "truncated")
+	_, err = decodeStarknetSettle(data[:3])
+	assert.ErrorContains(t, err, "declares 2 orders")
+}
+
+func TestSettledReceiver(t *testing.T) {
+	orderID := common.Hash{1}
+	solver, err := fillerdata.BuildFillerDataEVM(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"))
+	require.NoError(t, err)
+	other, err := fillerdata.BuildFillerDataEVM(common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"))
+	require.NoError(t, err)
+
+	receiver, err := settledReceiver("Ethereum", map[common.Hash][]byte{orderID: solver}, orderID, solver, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", receiver)
+
+	receiver, err = settledReceiver("Ethereum", map[common.Hash][]byte{orderID: other}, orderID, solver, nil)
+	assert.ErrorContains(t, err, "another solver")
+	assert.Equal(t, "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC", receiver)
+
+	_, err = settledReceiver("Ethereum", map[common.Hash][]byte{}, orderID, solver, nil)
+	assert.ErrorContains(t, err, "no Settle event")
+}
@@ -0,0 +1,128 @@
+package fillorder
+
+// Solver credentials used to fill orders
+// The fill is sent from the solver's account on the destination chain, while the fillerData carries
+// the solver's address on the origin chain, where settlement releases the input tokens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/fillerdata"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// starknetSolverCredentials holds the solver account for a Starknet network
+type starknetSolverCredentials struct {
+	address    string
+	publicKey  string
+	privateKey string
+}
+
+// starknetSolver returns the solver credentials for a Starknet or Ztarknet network
+func starknetSolver(networkName string) (starknetSolverCredentials, string) {
+	if openorder.GetNetworkType(networkName) == openorder.NetworkTypeZtarknet {
+		return starknetSolverCredentials{
+			address:    envutil.GetZtarknetSolverAddress(),
+			publicKey:  envutil.GetZtarknetSolverPublicKey(),
+			privateKey: envutil.GetZtarknetSolverPrivateKey(),
+		}, "ZTARKNET"
+	}
+	return starknetSolverCredentials{
+		address:    envutil.GetStarknetSolverAddress(),
+		publicKey:  envutil.GetStarknetSolverPublicKey(),
+		privateKey: envutil.GetStarknetSolverPrivateKey(),
+	}, "STARKNET"
+}
+
+// newStarknetSolverAccount builds the solver account that sends the fill on a Starknet network
+func newStarknetSolverAccount(ctx context.Context, provider *rpc.Provider, networkName string) (*account.Account, *felt.Felt, error) {
+	creds, prefix := starknetSolver(networkName)
+	if creds.address == "" || creds.publicKey == "" || creds.privateKey == "" {
+		return nil, nil, fmt.Errorf("missing %s_SOLVER_ADDRESS, %s_SOLVER_PUBLIC_KEY or %s_SOLVER_PRIVATE_KEY", prefix, prefix, prefix)
+	}
+
+	addr, err := utils.HexToFelt(creds.address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s_SOLVER_ADDRESS: %w", prefix, err)
+	}
+	privateKey, ok := new(big.Int).SetString(creds.privateKey, 0)
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to parse %s_SOLVER_PRIVATE_KEY", prefix)
+	}
+
+	ks := account.NewMemKeystore()
+	ks.Put(creds.publicKey, privateKey)
+	accnt, err := starknetutil.NewAccount(ctx, provider, starknetutil.AccountVersionEnv(networkName, "Solver"), addr, creds.publicKey, ks)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s solver account: %w", networkName, err)
+	}
+	return accnt, addr, nil
+}
+
+// solverFillerData returns the fillerData for a fill: the solver's origin chain address as bytes32,
+// which settlement on the origin chain decodes as the receiver of the input tokens (see pkg/fillerdata)
+func solverFillerData(originName string) ([]byte, error) {
+	if openorder.GetNetworkType(originName) != openorder.NetworkTypeEVM {
+		creds, prefix := starknetSolver(originName)
+		return starknetFillerData(creds.address, prefix)
+	}
+
+	privateKey, err := ethutil.ParsePrivateKey(envutil.GetSolverPrivateKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SOLVER_PRIVATE_KEY: %w", err)
+	}
+	return evmFillerData(crypto.PubkeyToAddress(privateKey.PublicKey))
+}
+
+// evmFillerData left-pads an EVM address to bytes32
+func evmFillerData(addr common.Address) ([]byte, error) {
+	data, err := fillerdata.BuildFillerDataEVM(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SOLVER_PRIVATE_KEY address: %w", err)
+	}
+	return data, nil
+}
+
+// starknetFillerData encodes a Starknet address felt as bytes32
+func starknetFillerData(address, prefix string) ([]byte, error) {
+	if address == "" {
+		return nil, fmt.Errorf("missing %s_SOLVER_ADDRESS for fillerData", prefix)
+	}
+	f, err := fillerdata.ParseStarknetAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s_SOLVER_ADDRESS: %w", prefix, err)
+	}
+	data, err := fillerdata.BuildFillerDataStarknet(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s_SOLVER_ADDRESS: %w", prefix, err)
+	}
+	return data, nil
+}
+
+// fillerReceiver reads the receiver settlement on originName pays from fillerData, as an address of the origin
+func fillerReceiver(originName string, data []byte) (string, error) {
+	if openorder.GetNetworkType(originName) != openorder.NetworkTypeEVM {
+		receiver, err := fillerdata.ReceiverStarknet(data)
+		if err != nil {
+			return "", err
+		}
+		return receiver.String(), nil
+	}
+	receiver, err := fillerdata.ReceiverEVM(data)
+	if err != nil {
+		return "", err
+	}
+	return receiver.Hex(), nil
+}
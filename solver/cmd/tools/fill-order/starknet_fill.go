@@ -0,0 +1,442 @@
+package fillorder
+
+// Starknet side of the fill tool: reads open_orders on Starknet origins and fills, settles and refunds orders on
+// Starknet and Ztarknet destinations
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// receiptPollInterval is how often the fill receipt is polled on Starknet
+const receiptPollInterval = 2 * time.Second
+
+// fillStarknetOrder approves the output token if needed and calls fill on the Starknet destination settler.
+// The approval and the fill go out in one multicall
+func fillStarknetOrder(
+	ctx context.Context,
+	destination config.NetworkConfig,
+	orderID common.Hash,
+	originData, fillerData []byte,
+	order *originOrder,
+) (*fillResult, error) {
+	ctx = txcost.WithNetwork(ctx, destination.Name)
+	provider, err := rpc.NewProvider(destination.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", destination.Name, err)
+	}
+	accnt, solverAddr, err := newStarknetSolverAccount(ctx, provider, destination.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	settler, err := starknetutil.Bytes32ToFelt(order.DestinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination settler: %w", err)
+	}
+
+	// Base7683.fill only accepts orders the destination has never seen
+	status, err := starknetOrderStatus(ctx, provider, settler, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if status != orderStatusUnknown {
+		return nil, fmt.Errorf("order %s is already %s on %s", orderID.Hex(), status, destination.Name)
+	}
+
+	var calls []rpc.InvokeFunctionCall
+	outputToken, err := starknetutil.Bytes32ToFelt(order.OutputToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output token: %w", err)
+	}
+	balance, err := starknetutil.ERC20Balance(ctx, provider, outputToken.String(), solverAddr.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read solver output token balance: %w", err)
+	}
+	if balance.Cmp(order.AmountOut) < 0 {
+		return nil, fmt.Errorf("solver has %s output tokens but the order needs %s", balance, order.AmountOut)
+	}
+	allowance, err := starknetutil.ERC20Allowance(ctx, provider, outputToken.String(), solverAddr.String(), settler.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowance: %w", err)
+	}
+	if allowance.Cmp(order.AmountOut) < 0 {
+		fmt.Printf("   Approving %s output tokens...\n", order.AmountOut)
+		approve, err := starknetutil.ERC20Approve(outputToken.String(), settler.String(), order.AmountOut)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build approve call: %w", err)
+		}
+		calls = append(calls, *approve)
+	}
+
+	// fill(order_id: u256, origin_data: Bytes, filler_data: Bytes)
+	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert order ID: %w", err)
+	}
+	calldata := []*felt.Felt{orderIDLow, orderIDHigh}
+	calldata = append(calldata, starknetutil.ToCairoBytes(originData)...)
+	calldata = append(calldata, starknetutil.ToCairoBytes(fillerData)...)
+	calls = append(calls, rpc.InvokeFunctionCall{ContractAddress: settler, FunctionName: "fill", CallData: calldata})
+
+	fmt.Printf("   Calling fill on %s...\n", settler.String())
+	tx, err := accnt.BuildAndSendInvokeTxn(ctx, calls, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send fill transaction: %w", err)
+	}
+	fmt.Printf("   Transaction sent: %s\n", tx.Hash.String())
+
+	receipt, err := starknetutil.WaitForFinality(txcost.WithOperation(ctx, "fill"), accnt.Provider, tx.Hash, starknetutil.FinalityAcceptedOnL2, receiptPollInterval, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for fill confirmation: %w", err)
+	}
+
+	status, err = starknetOrderStatus(ctx, provider, settler, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("after fill: %w", err)
+	}
+	result := &fillResult{TxHash: tx.Hash.String(), GasUsed: uint64(receipt.ExecutionResources.L2Gas), Status: status}
+	if result.Status != orderStatusFilled {
+		return nil, fmt.Errorf("order status is %s after fill, expected %s", result.Status, orderStatusFilled)
+	}
+	return result, nil
+}
+
+// starknetOrderStatus reads order_status(order_id) from a Starknet settler
+func starknetOrderStatus(ctx context.Context, provider *rpc.Provider, settler *felt.Felt, orderID common.Hash) (string, error) {
+	resp, err := callWithOrderID(ctx, provider, settler, "order_status", orderID)
+	if err != nil {
+		return "", err
+	}
+	if len(resp) == 0 {
+		return "", fmt.Errorf("order_status returned no data")
+	}
+	word := resp[0].Bytes()
+	return decodeOrderStatus(word[:]), nil
+}
+
+// callWithOrderID calls a view that takes the order ID as its only (u256) argument
+func callWithOrderID(ctx context.Context, provider *rpc.Provider, contract *felt.Felt, function string, orderID common.Hash) ([]*felt.Felt, error) {
+	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert order ID: %w", err)
+	}
+	call := rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           []*felt.Felt{orderIDLow, orderIDHigh},
+	}
+	resp, err := provider.Call(ctx, call, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", function, err)
+	}
+	return resp, nil
+}
+
+// settleStarknetOrders calls settle(order_ids, value) on a Starknet destination settler and returns the fillerData
+// its Settle event carried per order. The quoted Hyperlane gas is paid in ETH pulled by the settler, so its
+// approval goes out in the same multicall
+func settleStarknetOrders(ctx context.Context, destination config.NetworkConfig, settlerWord [32]byte, originDomain uint32, orderIDs []common.Hash) (string, map[common.Hash][]byte, error) {
+	ctx = txcost.WithNetwork(ctx, destination.Name)
+	provider, err := rpc.NewProvider(destination.RPCURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to connect to %s: %w", destination.Name, err)
+	}
+	accnt, solverAddr, err := newStarknetSolverAccount(ctx, provider, destination.Name)
+	if err != nil {
+		return "", nil, err
+	}
+	settler, err := starknetutil.Bytes32ToFelt(settlerWord)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid destination settler: %w", err)
+	}
+
+	gasPayment, calls, err := starknetGasPayment(ctx, provider, destination.Name, solverAddr, settler, originDomain)
+	if err != nil {
+		return "", nil, err
+	}
+	calls = append(calls, rpc.InvokeFunctionCall{ContractAddress: settler, FunctionName: "settle", CallData: settleCalldata(orderIDs, gasPayment)})
+	txHash, err := sendStarknetCalls(ctx, accnt, "settle", calls)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash, err := utils.HexToFelt(txHash)
+	if err != nil {
+		return "", nil, err
+	}
+	receipt, err := provider.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the settle receipt: %w", err)
+	}
+	fillerData, err := starknetSettledFillerData(settler, receipt.Events)
+	if err != nil {
+		return "", nil, err
+	}
+	return txHash, fillerData, nil
+}
+
+// starknetSettledFillerData reads the fillerData of every order in the Settle events settler emitted in events.
+// A Settle event's data is order_ids: Array<u256> followed by orders_filler_data: Array<Bytes>
+func starknetSettledFillerData(settler *felt.Felt, events []rpc.Event) (map[common.Hash][]byte, error) {
+	settleSelector := utils.GetSelectorFromNameFelt("Settle")
+	fillerData := make(map[common.Hash][]byte)
+	for _, event := range events {
+		if !event.FromAddress.Equal(settler) || len(event.Keys) == 0 || !event.Keys[0].Equal(settleSelector) {
+			continue
+		}
+		orders, err := decodeStarknetSettle(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Settle event: %w", err)
+		}
+		for orderID, data := range orders {
+			fillerData[orderID] = data
+		}
+	}
+	return fillerData, nil
+}
+
+// decodeStarknetSettle decodes the data of a Settle event into the fillerData of each order
+func decodeStarknetSettle(data []*felt.Felt) (map[common.Hash][]byte, error) {
+	next := func(what string) (uint64, error) {
+		if len(data) == 0 {
+			return 0, fmt.Errorf("missing %s", what)
+		}
+		value := data[0].BigInt(new(big.Int))
+		data = data[1:]
+		if !value.IsUint64() {
+			return 0, fmt.Errorf("%s %s is not a valid length", what, value)
+		}
+		return value.Uint64(), nil
+	}
+
+	count, err := next("order_ids length")
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) < 2*count {
+		return nil, fmt.Errorf("declares %d orders but has %d felts", count, len(data))
+	}
+	orderIDs := make([]common.Hash, count)
+	for i := range orderIDs {
+		orderIDs[i] = common.BigToHash(starknetutil.FromU256(data[2*i], data[2*i+1]))
+	}
+	data = data[2*count:]
+
+	fillerCount, err := next("orders_filler_data length")
+	if err != nil {
+		return nil, err
+	}
+	if fillerCount != count {
+		return nil, fmt.Errorf("has %d orders but %d fillerData", count, fillerCount)
+	}
+	fillerData := make(map[common.Hash][]byte, count)
+	for _, orderID := range orderIDs {
+		if len(data) < 2 {
+			return nil, fmt.Errorf("fillerData of order %s is truncated", orderID.Hex())
+		}
+		words := data[1].BigInt(new(big.Int))
+		if !words.IsUint64() || words.Uint64() > uint64(len(data)-2) {
+			return nil, fmt.Errorf("fillerData of order %s is truncated", orderID.Hex())
+		}
+		end := 2 + int(words.Uint64())
+		bytes, err := starknetutil.FromCairoBytes(data[:end])
+		if err != nil {
+			return nil, fmt.Errorf("fillerData of order %s: %w", orderID.Hex(), err)
+		}
+		fillerData[orderID] = bytes
+		data = data[end:]
+	}
+	return fillerData, nil
+}
+
+// starknetGasPayment quotes the Hyperlane gas for a message to originDomain and returns the ETH approval
+// the settler needs to pull it, if the current allowance does not cover it
+func starknetGasPayment(
+	ctx context.Context,
+	provider *rpc.Provider,
+	networkName string,
+	solverAddr, settler *felt.Felt,
+	originDomain uint32,
+) (*big.Int, []rpc.InvokeFunctionCall, error) {
+	// quote_gas_payment(destination_domain: u32) -> u256
+	resp, err := provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    settler,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("quote_gas_payment"),
+		Calldata:           []*felt.Felt{utils.Uint64ToFelt(uint64(originDomain))},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("quote_gas_payment(%d) failed on %s: %w", originDomain, networkName, err)
+	}
+	if len(resp) < 2 {
+		return nil, nil, fmt.Errorf("quote_gas_payment returned %d felts, expected 2", len(resp))
+	}
+	gasPayment := starknetutil.FromU256(resp[0], resp[1])
+	fmt.Printf("   Gas payment for domain %d: %s wei\n", originDomain, gasPayment)
+
+	if gasPayment.Sign() == 0 {
+		return gasPayment, nil, nil
+	}
+	ethAddress := starknetETHAddress(networkName)
+	allowance, err := starknetutil.ERC20Allowance(ctx, provider, ethAddress, solverAddr.String(), settler.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read ETH allowance: %w", err)
+	}
+	if allowance.Cmp(gasPayment) >= 0 {
+		return gasPayment, nil, nil
+	}
+	approve, err := starknetutil.ERC20Approve(ethAddress, settler.String(), gasPayment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build ETH approve call: %w", err)
+	}
+	return gasPayment, []rpc.InvokeFunctionCall{*approve}, nil
+}
+
+// sendStarknetCalls sends calls as one multicall and waits for it to be accepted without reverting
+func sendStarknetCalls(ctx context.Context, accnt *account.Account, label string, calls []rpc.InvokeFunctionCall) (string, error) {
+	tx, err := accnt.BuildAndSendInvokeTxn(ctx, calls, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s transaction: %w", label, err)
+	}
+	fmt.Printf("   Transaction sent: %s\n", tx.Hash.String())
+
+	receipt, err := starknetutil.WaitForFinality(txcost.WithOperation(ctx, label), accnt.Provider, tx.Hash, starknetutil.FinalityAcceptedOnL2, receiptPollInterval, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for %s confirmation: %w", label, err)
+	}
+	fmt.Printf("   %s confirmed (L2 gas used: %d)\n", strings.ToUpper(label[:1])+label[1:], receipt.ExecutionResources.L2Gas)
+	return tx.Hash.String(), nil
+}
+
+// settleCalldata encodes settle(order_ids: Array<u256>, value: u256): the array length, each ID as low/high, then the value
+func settleCalldata(orderIDs []common.Hash, value *big.Int) []*felt.Felt {
+	calldata := make([]*felt.Felt, 0, 1+2*len(orderIDs)+2)
+	calldata = append(calldata, utils.Uint64ToFelt(uint64(len(orderIDs))))
+	for _, orderID := range orderIDs {
+		low, high := starknetutil.ToU256(orderID.Big())
+		calldata = append(calldata, low, high)
+	}
+	valueLow, valueHigh := starknetutil.ToU256(value)
+	return append(calldata, valueLow, valueHigh)
+}
+
+// starknetETHAddress returns the ETH token used to pay Hyperlane gas on a Starknet network
+func starknetETHAddress(networkName string) string {
+	return envutil.GetEnvWithDefault(strings.ToUpper(networkName)+"_ETH_ADDRESS", "0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+}
+
+// refundStarknetOrder calls refund_onchain_cross_chain_order or refund_gasless_cross_chain_order on a Starknet
+// destination settler. Like settle, the Hyperlane gas is paid in ETH approved in the same multicall
+func refundStarknetOrder(ctx context.Context, destination config.NetworkConfig, r *refundOrder) (string, error) {
+	ctx = txcost.WithNetwork(ctx, destination.Name)
+	provider, err := rpc.NewProvider(destination.RPCURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", destination.Name, err)
+	}
+	accnt, solverAddr, err := newStarknetSolverAccount(ctx, provider, destination.Name)
+	if err != nil {
+		return "", err
+	}
+	settler, err := starknetutil.Bytes32ToFelt(r.Order.DestinationSettler)
+	if err != nil {
+		return "", fmt.Errorf("invalid destination settler: %w", err)
+	}
+
+	gasPayment, calls, err := starknetGasPayment(ctx, provider, destination.Name, solverAddr, settler, r.Order.OriginDomain)
+	if err != nil {
+		return "", err
+	}
+	function, calldata, err := refundCalldata(r, gasPayment)
+	if err != nil {
+		return "", err
+	}
+	calls = append(calls, rpc.InvokeFunctionCall{ContractAddress: settler, FunctionName: function, CallData: calldata})
+	return sendStarknetCalls(ctx, accnt, "refund", calls)
+}
+
+// refundCalldata picks the refund entrypoint for the order and encodes a one-order array followed by the value:
+//
+//	refund_onchain_cross_chain_order(orders: Array<OnchainCrossChainOrder>, value: u256)
+//	refund_gasless_cross_chain_order(orders: Array<GaslessCrossChainOrder>, value: u256)
+func refundCalldata(r *refundOrder, value *big.Int) (string, []*felt.Felt, error) {
+	function := "refund_onchain_cross_chain_order"
+	calldata := []*felt.Felt{utils.Uint64ToFelt(1)}
+	if r.Gasless {
+		originSettler, err := starknetutil.Bytes32ToFelt(r.OriginSettler)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid origin settler: %w", err)
+		}
+		user, err := starknetutil.Bytes32ToFelt(r.Order.Sender)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid order sender: %w", err)
+		}
+		function = "refund_gasless_cross_chain_order"
+		calldata = append(calldata,
+			originSettler,
+			user,
+			utils.BigIntToFelt(r.Order.SenderNonce),
+			utils.Uint64ToFelt(uint64(r.Order.OriginDomain)),
+			utils.Uint64ToFelt(r.OpenDeadline),
+		)
+	}
+	typeLow, typeHigh := starknetutil.ToU256(new(big.Int).SetBytes(r.OrderDataType[:]))
+	calldata = append(calldata, utils.Uint64ToFelt(r.FillDeadline), typeLow, typeHigh)
+	calldata = append(calldata, starknetutil.ToCairoBytes(r.OrderData)...)
+
+	valueLow, valueHigh := starknetutil.ToU256(value)
+	return function, append(calldata, valueLow, valueHigh), nil
+}
+
+// starknetBlockTime returns the timestamp of the latest (possibly pre-confirmed) block
+func starknetBlockTime(ctx context.Context, network config.NetworkConfig) (uint64, error) {
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	block, err := provider.BlockWithTxHashes(ctx, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest block on %s: %w", network.Name, err)
+	}
+	switch b := block.(type) {
+	case *rpc.BlockTxHashes:
+		return b.Timestamp, nil
+	case *rpc.PreConfirmedBlockTxHashes:
+		return b.Timestamp, nil
+	default:
+		return 0, fmt.Errorf("unexpected block type %T from %s", block, network.Name)
+	}
+}
+
+// starknetTokenBalance reads an ERC20 balance and, unless previous is nil, waits for it to move away from previous
+func starknetTokenBalance(ctx context.Context, network config.NetworkConfig, tokenWord, ownerWord [32]byte, previous *big.Int) (*big.Int, error) {
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	token, err := starknetutil.Bytes32ToFelt(tokenWord)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	owner, err := starknetutil.Bytes32ToFelt(ownerWord)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner: %w", err)
+	}
+	if previous == nil {
+		return starknetutil.ERC20Balance(ctx, provider, token.String(), owner.String())
+	}
+	return starknetutil.WaitForERC20BalanceChange(ctx, provider, token.String(), owner.String(), previous, starknetutil.BalancePollOptions{})
+}
@@ -0,0 +1,205 @@
+package fillorder
+
+// Watch tool: prints the lifecycle events of one order as they happen on any configured network
+// Every network with a Hyperlane7683 address is watched at once; the run ends when the origin reports the
+// order Settled or Refunded, or on Ctrl-C. Progress is saved to the watch cursor so the next run resumes there
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/watcher"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// ConfirmationsFlag overrides the confirmation blocks of every network
+const ConfirmationsFlag = "--confirmations"
+
+// watchStatusTimeout bounds the initial status read on each network
+const watchStatusTimeout = 10 * time.Second
+
+// watchRequest is a parsed watch command
+type watchRequest struct {
+	OrderID common.Hash
+	// Confirmations overrides each network's CONFIRMATION_BLOCKS when set
+	Confirmations *uint64
+}
+
+// update is an event or the error that ended one network's watch
+type update struct {
+	event   watcher.Event
+	network string
+	err     error
+	done    bool
+}
+
+// RunWatch runs `watch <order-id> [--confirmations <n>]` until the order is settled or refunded, or ctx is done
+func RunWatch(ctx context.Context, args []string) {
+	req, err := parseWatchArgs(args)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := config.LoadConfig(); err != nil {
+		fmt.Printf("❌ failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	networks := watchNetworks(config.Networks, req.Confirmations)
+	if len(networks) == 0 {
+		fmt.Println("❌ no network has a Hyperlane7683 address configured")
+		os.Exit(1)
+	}
+	cursorPath := watcher.DefaultCursorPath()
+	fmt.Printf("👀 Watching order %s on %d network(s), cursor %s\n", req.OrderID.Hex(), len(networks), cursorPath)
+	printStatuses(ctx, networks, req.OrderID)
+
+	if err := watchOrder(ctx, networks, req.OrderID, watcher.NewFileCursor(cursorPath)); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// watchOrder prints the events of orderID from every network until the origin settles or refunds it
+func watchOrder(ctx context.Context, networks []watcher.Network, orderID common.Hash, cursor watcher.Cursor) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan update)
+	for _, network := range networks {
+		go func() {
+			events, errs := watcher.Watch(ctx, network, watcher.Options{Cursor: cursor})
+			for e := range events {
+				select {
+				case updates <- update{event: e, network: network.Name}:
+				case <-ctx.Done():
+				}
+			}
+			select {
+			case updates <- update{network: network.Name, err: <-errs, done: true}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	for running := len(networks); running > 0; {
+		select {
+		case <-ctx.Done():
+			fmt.Println("🛑 Stopped")
+			return nil
+		case u := <-updates:
+			if u.done {
+				running--
+				if u.err != nil {
+					fmt.Printf("⚠️  stopped watching %s: %v\n", u.network, u.err)
+				}
+				continue
+			}
+			if u.event.OrderID != orderID {
+				continue
+			}
+			fmt.Println(formatEvent(u.event))
+			if isFinal(u.event.Kind) {
+				fmt.Printf("✅ Order %s is %s\n", orderID.Hex(), strings.ToUpper(u.event.Kind.String()))
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("every network stopped before order %s was settled or refunded", orderID.Hex())
+}
+
+// printStatuses prints the current status of the order on every network that knows it
+func printStatuses(ctx context.Context, networks []watcher.Network, orderID common.Hash) {
+	for _, network := range networks {
+		callCtx, cancel := context.WithTimeout(ctx, watchStatusTimeout)
+		status, err := orderquery.GetOrderStatus(callCtx, orderquery.Network{
+			Name: network.Name, RPCURL: network.RPCURL, Contract: network.Contract, Cairo: network.Cairo,
+		}, orderID)
+		cancel()
+		switch {
+		case err != nil:
+			fmt.Printf("   %-9s status unavailable: %v\n", network.Name, err)
+		case status != orderquery.StatusUnknown:
+			fmt.Printf("   %-9s %s\n", network.Name, status)
+		}
+	}
+}
+
+// formatEvent is the printed line of an event
+func formatEvent(e watcher.Event) string {
+	return fmt.Sprintf("🔔 %s block %d: %s (tx %s)", e.Network, e.BlockNumber, e.Kind, e.TxHash)
+}
+
+// isFinal reports whether an event ends the order's lifecycle: both are emitted on the origin
+func isFinal(kind watcher.Kind) bool {
+	return kind == watcher.KindSettled || kind == watcher.KindRefunded
+}
+
+// watchNetworks returns the configured networks that have a Hyperlane7683 address, sorted by name
+func watchNetworks(networks map[string]config.NetworkConfig, confirmations *uint64) []watcher.Network {
+	var watched []watcher.Network
+	for name, network := range networks {
+		if network.HyperlaneAddress == "" {
+			continue
+		}
+		w := watcher.Network{
+			Name:          name,
+			RPCURL:        network.RPCURL,
+			WSURL:         network.WSURL,
+			Contract:      network.HyperlaneAddress,
+			Cairo:         config.IsStarknetNetwork(name),
+			Confirmations: network.ConfirmationBlocks,
+			PollInterval:  time.Duration(network.PollInterval) * time.Millisecond,
+			MaxBlockRange: network.MaxBlockRange,
+		}
+		if confirmations != nil {
+			w.Confirmations = *confirmations
+		}
+		watched = append(watched, w)
+	}
+	sort.Slice(watched, func(i, j int) bool { return watched[i].Name < watched[j].Name })
+	return watched
+}
+
+// parseWatchArgs reads the order ID and the optional confirmations override
+func parseWatchArgs(args []string) (watchRequest, error) {
+	var req watchRequest
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		value, ok := strings.CutPrefix(args[i], ConfirmationsFlag+"=")
+		if !ok && args[i] != ConfirmationsFlag {
+			positional = append(positional, args[i])
+			continue
+		}
+		if !ok {
+			if i+1 >= len(args) {
+				return watchRequest{}, fmt.Errorf("%s requires a number of blocks", ConfirmationsFlag)
+			}
+			i++
+			value = args[i]
+		}
+		confirmations, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return watchRequest{}, fmt.Errorf("invalid %s %q: %w", ConfirmationsFlag, value, err)
+		}
+		req.Confirmations = &confirmations
+	}
+
+	if len(positional) != 1 {
+		return watchRequest{}, fmt.Errorf("usage: watch <order-id> [%s <n>]", ConfirmationsFlag)
+	}
+	orderID, err := parseOrderID(positional[0])
+	if err != nil {
+		return watchRequest{}, err
+	}
+	req.OrderID = orderID
+	return req, nil
+}
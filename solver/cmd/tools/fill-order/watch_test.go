@@ -0,0 +1,71 @@
+package fillorder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/watcher"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestParseWatchArgs(t *testing.T) {
+	req, err := parseWatchArgs([]string{"0x01"})
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x01"), req.OrderID)
+	assert.Nil(t, req.Confirmations)
+
+	req, err = parseWatchArgs([]string{"--confirmations", "3", "0x01"})
+	require.NoError(t, err)
+	require.NotNil(t, req.Confirmations)
+	assert.Equal(t, uint64(3), *req.Confirmations)
+
+	req, err = parseWatchArgs([]string{"0x01", "--confirmations=0"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), *req.Confirmations)
+
+	_, err = parseWatchArgs([]string{"0x01", "--confirmations"})
+	assert.ErrorContains(t, err, "--confirmations requires a number of blocks")
+	_, err = parseWatchArgs([]string{"0x01", "--confirmations", "-1"})
+	assert.ErrorContains(t, err, `invalid --confirmations "-1"`)
+	_, err = parseWatchArgs(nil)
+	assert.ErrorContains(t, err, "usage: watch <order-id>")
+	_, err = parseWatchArgs([]string{"order"})
+	assert.ErrorContains(t, err, "invalid order ID")
+}
+
+func TestWatchNetworks(t *testing.T) {
+	networks := map[string]config.NetworkConfig{
+		"Starknet": {RPCURL: "http://localhost:5050", HyperlaneAddress: "0x0123", ConfirmationBlocks: 1, PollInterval: 2000},
+		"Base":     {RPCURL: "http://localhost:8548", WSURL: "ws://localhost:8548", HyperlaneAddress: "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3", MaxBlockRange: 10},
+		"Ztarknet": {RPCURL: "http://localhost:5051"},
+	}
+
+	watched := watchNetworks(networks, nil)
+	require.Len(t, watched, 2, "networks without a Hyperlane7683 address are skipped")
+	assert.Equal(t, watcher.Network{
+		Name: "Base", RPCURL: "http://localhost:8548", WSURL: "ws://localhost:8548",
+		Contract: "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3", MaxBlockRange: 10,
+	}, watched[0])
+	assert.Equal(t, "Starknet", watched[1].Name)
+	assert.True(t, watched[1].Cairo)
+	assert.Equal(t, uint64(1), watched[1].Confirmations)
+	assert.Equal(t, 2*time.Second, watched[1].PollInterval)
+
+	five := uint64(5)
+	for _, w := range watchNetworks(networks, &five) {
+		assert.Equal(t, five, w.Confirmations, w.Name)
+	}
+}
+
+func TestFormatEvent(t *testing.T) {
+	e := watcher.Event{Kind: watcher.KindFilled, Network: "Base", OrderID: common.HexToHash("0x01"), BlockNumber: 12, TxHash: "0xabc"}
+	assert.Equal(t, "🔔 Base block 12: Filled (tx 0xabc)", formatEvent(e))
+
+	assert.True(t, isFinal(watcher.KindSettled))
+	assert.True(t, isFinal(watcher.KindRefunded))
+	assert.False(t, isFinal(watcher.KindSettle), "Settle is only dispatched from the destination")
+}
@@ -0,0 +1,189 @@
+package fundaccounts
+
+// Alice's EVM allowances (--allowances)
+// Alice gives Hyperlane7683 an unlimited allowance on every token, so opening orders needs no approve(). She
+// only signs an EIP-2612 permit off-chain and the deployer submits it, leaving Alice's gas untouched; a token
+// without permit(), or no deployer key, falls back to an approve() sent by Alice. Allowances that are already
+// unlimited are left alone
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/erc2612"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// AllowancesFlag also sets Alice's Hyperlane7683 allowances on the EVM networks after funding
+const AllowancesFlag = "--allowances"
+
+// permitValidity is how long, in seconds of block time, a setup permit stays valid
+const permitValidity = 3600
+
+// allowanceKeys are the keys setting the allowances: Alice signs, the deployer (nil when not configured) submits
+type allowanceKeys struct {
+	alice    *ecdsa.PrivateKey
+	deployer *ecdsa.PrivateKey
+}
+
+// loadAllowanceKeys loads Alice's key, which is required, and the deployer's, which is not
+func loadAllowanceKeys() (allowanceKeys, error) {
+	alice, err := credentials.LoadEVMKey(envutil.ConditionalKey("ALICE"))
+	if err != nil {
+		return allowanceKeys{}, fmt.Errorf("failed to load Alice's key, which signs her allowances: %w", err)
+	}
+	deployer, err := credentials.LoadEVMKey(envutil.ConditionalKey("DEPLOYER"))
+	if errors.Is(err, credentials.ErrMissingKey) {
+		return allowanceKeys{alice: alice}, nil
+	}
+	if err != nil {
+		return allowanceKeys{}, err
+	}
+	return allowanceKeys{alice: alice, deployer: deployer}, nil
+}
+
+// SetEVMAllowances gives Alice an unlimited Hyperlane7683 allowance on every token of an EVM network
+func SetEVMAllowances(ctx context.Context, networkName string) error {
+	logger.Infof("🔓 Setting Alice's allowances on %s...\n", strings.ToTitle(networkName))
+	config.InitializeNetworks()
+	var networkConfig *config.NetworkConfig
+	for name, cfg := range config.Networks {
+		if strings.EqualFold(name, networkName) {
+			networkConfig = &cfg
+			break
+		}
+	}
+	if networkConfig == nil {
+		return fmt.Errorf("network not found: %s", networkName)
+	}
+	if networkConfig.HyperlaneAddress == "" {
+		return fmt.Errorf("no Hyperlane7683 address configured for %s", networkName)
+	}
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return fmt.Errorf("failed to load token specs: %w", err)
+	}
+	keys, err := loadAllowanceKeys()
+	if err != nil {
+		return err
+	}
+
+	client, err := ethclient.DialContext(ctx, networkConfig.RPCURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", networkName, err)
+	}
+	defer client.Close()
+
+	ctx = txcost.WithNetwork(ctx, networkName)
+	chainID := new(big.Int).SetUint64(networkConfig.ChainID)
+	hyperlane := common.HexToAddress(networkConfig.HyperlaneAddress)
+	failed := 0
+	for _, spec := range specs {
+		tokenAddress, _, err := tokenspec.Address(networkName, spec.Name)
+		if err == nil {
+			err = setAllowance(ctx, client, chainID, common.HexToAddress(tokenAddress), hyperlane, spec.Symbol, keys)
+		}
+		if err != nil {
+			logger.Errorf("   ❌ %s: %v\n", spec.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d allowances not set", failed, len(specs))
+	}
+	return nil
+}
+
+// setAllowance sets Alice's unlimited allowance for spender on token, by permit when it can
+func setAllowance(ctx context.Context, client *ethclient.Client, chainID *big.Int, token, spender common.Address, symbol string, keys allowanceKeys) error {
+	owner := crypto.PubkeyToAddress(keys.alice.PublicKey)
+	allowance, err := ethutil.ERC20AllowanceAt(ctx, client, token, owner, spender, nil)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(abi.MaxUint256) == 0 {
+		logger.Infof("   ⏭️  %s: Alice's allowance is already unlimited\n", symbol)
+		return nil
+	}
+
+	if keys.deployer != nil {
+		err := submitPermit(ctx, client, chainID, token, spender, symbol, keys)
+		if !errors.Is(err, erc2612.ErrUnsupported) {
+			return err
+		}
+		logger.Infof("   ↩️  %s: %v, approving from Alice instead\n", symbol, err)
+	}
+
+	auth, err := ethutil.NewTransactor(chainID, keys.alice)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %w", err)
+	}
+	tx, err := ethutil.ERC20Approve(ctx, client, auth, token, spender, abi.MaxUint256)
+	if err != nil {
+		return fmt.Errorf("failed to approve: %w", err)
+	}
+	if err := waitForSuccess(txcost.WithOperation(ctx, "approve "+symbol), client, tx, "approve"); err != nil {
+		return err
+	}
+	logger.Infof("   ✅ %s: approved by Alice (%s)\n", symbol, tx.Hash().Hex())
+	return nil
+}
+
+// submitPermit has Alice sign an unlimited permit and the deployer submit it. It returns
+// erc2612.ErrUnsupported when the token has no permit()
+func submitPermit(ctx context.Context, client *ethclient.Client, chainID *big.Int, token, spender common.Address, symbol string, keys allowanceKeys) error {
+	// Forks can run far from this machine's clock, so the deadline follows the block time
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read the head block: %w", err)
+	}
+	deadline := new(big.Int).SetUint64(head.Time + permitValidity)
+	data, err := erc2612.SignedCall(ctx, client, chainID, token, spender, abi.MaxUint256, deadline, keys.alice)
+	if err != nil {
+		return err
+	}
+
+	auth, err := ethutil.NewTransactor(chainID, keys.deployer)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %w", err)
+	}
+	tx, err := ethutil.SendTx(ctx, client, auth, token, nil, data)
+	if err != nil {
+		if revert := ethutil.DecodeRevertError(err); revert != nil {
+			return fmt.Errorf("permit would revert: %w", revert)
+		}
+		return fmt.Errorf("failed to send permit: %w", err)
+	}
+	if err := waitForSuccess(txcost.WithOperation(ctx, "permit "+symbol), client, tx, "permit"); err != nil {
+		return err
+	}
+	logger.Infof("   ✅ %s: permit signed by Alice, submitted by the deployer (%s)\n", symbol, tx.Hash().Hex())
+	return nil
+}
+
+// waitForSuccess waits for tx and fails unless it succeeded
+func waitForSuccess(ctx context.Context, client *ethclient.Client, tx *gethtypes.Transaction, what string) error {
+	receipt, err := ethutil.WaitForTransaction(ctx, client, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the %s transaction %s: %w", what, tx.Hash().Hex(), err)
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("%s transaction %s reverted", what, tx.Hash().Hex())
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package fundaccounts
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAllowanceKeys(t *testing.T) {
+	t.Run("alice_and_deployer", func(t *testing.T) {
+		clearMinterEnv(t)
+		t.Setenv("ALICE_PRIVATE_KEY", testAliceKey)
+		t.Setenv("DEPLOYER_PRIVATE_KEY", testDeployerKey)
+
+		keys, err := loadAllowanceKeys()
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToAddress(testAliceAddress), crypto.PubkeyToAddress(keys.alice.PublicKey))
+		require.NotNil(t, keys.deployer)
+		assert.Equal(t, common.HexToAddress(testDeployerAddress), crypto.PubkeyToAddress(keys.deployer.PublicKey))
+	})
+
+	t.Run("no_deployer", func(t *testing.T) {
+		clearMinterEnv(t)
+		t.Setenv("ALICE_PRIVATE_KEY", testAliceKey)
+
+		keys, err := loadAllowanceKeys()
+		require.NoError(t, err)
+		assert.Nil(t, keys.deployer, "Alice approves herself")
+	})
+
+	t.Run("no_alice", func(t *testing.T) {
+		clearMinterEnv(t)
+		t.Setenv("DEPLOYER_PRIVATE_KEY", testDeployerKey)
+
+		_, err := loadAllowanceKeys()
+		assert.ErrorContains(t, err, "Alice's key")
+	})
+}
+
+func TestStripFlag(t *testing.T) {
+	args, found := stripFlag([]string{"base", AllowancesFlag, "500"}, AllowancesFlag)
+	assert.True(t, found)
+	assert.Equal(t, []string{"base", "500"}, args)
+
+	args, found = stripFlag([]string{"all"}, AllowancesFlag)
+	assert.False(t, found)
+	assert.Equal(t, []string{"all"}, args)
+}
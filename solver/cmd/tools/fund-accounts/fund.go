@@ -0,0 +1,266 @@
+package fundaccounts
+
+// Fund tool: mints every token of the token spec to Alice and the solver on the EVM networks, Starknet and
+// Ztarknet. With --allowances Alice's EVM allowances for Hyperlane7683 are set too (see allowance.go)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// Default funding amount (420,690,000,000 tokens)
+	defaultFundingAmount = 420_690_000_000
+	// Token decimals (18 for most ERC20 tokens)
+	tokenDecimals = 18
+	// Base 10 for string parsing
+	base10 = 10
+)
+
+// logger carries the progress output; LOG_LEVEL and LOG_FORMAT are applied once the config is loaded
+var logger = logutil.NewToolLogger()
+
+// Run funds the accounts as described by args: <network|all> [amount] [--allowances]. It fails if any network
+// failed
+func Run(ctx context.Context, args []string) error {
+	args, allowances := stripFlag(args, AllowancesFlag)
+	if len(args) < 1 {
+		fmt.Println("🏦 MockERC20 Token Funding Tool")
+		fmt.Println()
+		fmt.Println("Usage:")
+		fmt.Println("  fund-accounts <network|all> [amount] [--allowances]")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  fund-accounts all           # Fund Alice & Solver on all networks with 10000 tokens")
+		fmt.Println("  fund-accounts ethereum      # Fund Alice & Solver on Ethereum with 10000 tokens")
+		fmt.Println("  fund-accounts starknet      # Fund Alice & Solver on Starknet with 10000 tokens")
+		fmt.Println("  fund-accounts ztarknet      # Fund Alice & Solver on Ztarknet with 10000 tokens")
+		fmt.Println("  fund-accounts all 50000     # Fund Alice & Solver on all networks with 50000 tokens")
+		fmt.Println("  fund-accounts base --allowances  # Also give Hyperlane7683 Alice's allowance, by permit when possible")
+		fmt.Println()
+		fmt.Println("Networks: ethereum, optimism, arbitrum, base, starknet, ztarknet, all")
+		return errors.New("missing network")
+	}
+
+	networkArg := strings.ToLower(args[0])
+
+	// Default funding amount in whole tokens (420,690,000,000); each network scales it by its token's decimals
+	tokens := big.NewInt(defaultFundingAmount)
+
+	// Parse custom amount if provided
+	if len(args) >= 2 {
+		customAmount, ok := new(big.Int).SetString(args[1], base10)
+		if !ok || customAmount.Sign() <= 0 {
+			return fmt.Errorf("invalid amount: %s", args[1])
+		}
+		tokens = customAmount
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	logutil.ConfigureFromConfig(logger, cfg)
+
+	// Every token in the deploy token set is funded
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return fmt.Errorf("failed to load token specs: %w", err)
+	}
+
+	logger.Infof("🏦 Funding Alice and Solver accounts with %s of each of %d token(s)\n", ethutil.FormatTokenAmount(tokens, 0), len(specs))
+	logger.Infof("💰 Using conditional environment variables (IS_DEVNET=%s)\n", os.Getenv("IS_DEVNET"))
+	logger.Infoln()
+
+	var legs []fundingLeg
+	evmLeg := func(network string) fundingLeg {
+		return fundingLeg{Name: network, Fund: func() error {
+			if err := fundNetwork(ctx, network, specs, tokens); err != nil || !allowances {
+				return err
+			}
+			return SetEVMAllowances(ctx, network)
+		}}
+	}
+	starknetLeg := fundingLeg{Name: "starknet", Fund: func() error { return fundStarknet(ctx, specs, tokens) }}
+	ztarknetLeg := fundingLeg{Name: "ztarknet", Fund: func() error { return fundZtarknet(ctx, specs, tokens) }}
+	switch networkArg {
+	case "all":
+		for _, network := range EVMNetworks {
+			legs = append(legs, evmLeg(network))
+		}
+		legs = append(legs, starknetLeg, ztarknetLeg)
+	case "starknet":
+		legs = append(legs, starknetLeg)
+	case "ztarknet":
+		legs = append(legs, ztarknetLeg)
+	default:
+		legs = append(legs, evmLeg(networkArg))
+	}
+
+	if failed := runFundingLegs(legs); len(failed) > 0 {
+		return fmt.Errorf("funding failed on %s", strings.Join(failed, ", "))
+	}
+	logger.Infoln("🎉 Funding completed!")
+	return nil
+}
+
+// stripFlag removes every occurrence of flag from args and reports whether it was present
+func stripFlag(args []string, flag string) ([]string, bool) {
+	out := make([]string, 0, len(args))
+	found := false
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out, found
+}
+
+// EVMNetworks are the EVM networks funded by "all"
+var EVMNetworks = []string{"ethereum", "optimism", "arbitrum", "base"}
+
+// fundingLeg funds the accounts on one network
+type fundingLeg struct {
+	Name string
+	Fund func() error
+}
+
+// runFundingLegs runs every leg, so a failure on one network does not stop the others, and returns the names of
+// the legs that failed
+func runFundingLegs(legs []fundingLeg) []string {
+	var failed []string
+	for _, leg := range legs {
+		if err := leg.Fund(); err != nil {
+			logger.Errorf("❌ Funding %s failed: %v\n", leg.Name, err)
+			failed = append(failed, leg.Name)
+		}
+		logger.Infoln()
+	}
+	return failed
+}
+
+// fundNetwork mints tokens (whole tokens, scaled by each token's decimals) of every token to each recipient on an
+// EVM network
+func fundNetwork(ctx context.Context, networkName string, specs []tokenspec.TokenSpec, tokens *big.Int) error {
+	logger.Infof("📡 Funding %s network...\n", strings.ToTitle(networkName))
+
+	// Load network configuration
+	config.InitializeNetworks()
+
+	var networkConfig *config.NetworkConfig
+	for name, cfg := range config.Networks {
+		if strings.EqualFold(name, networkName) {
+			networkConfig = &cfg
+			break
+		}
+	}
+
+	if networkConfig == nil {
+		return fmt.Errorf("network not found: %s", networkName)
+	}
+
+	// Connect to network
+	rpcClient, err := rpc.DialContext(ctx, networkConfig.RPCURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", networkName, err)
+	}
+	client := ethclient.NewClient(rpcClient)
+	defer client.Close()
+
+	logger.Infof("   📍 Network: %s (Chain ID: %d)\n", networkConfig.Name, networkConfig.ChainID)
+
+	failed := 0
+	for _, spec := range specs {
+		if err := fundToken(ctx, rpcClient, client, networkName, networkConfig.ChainID, spec, tokens); err != nil {
+			logger.Errorf("   ❌ %s: %v\n", spec.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tokens not fully funded", failed, len(specs))
+	}
+	return nil
+}
+
+// fundToken mints one token to each recipient
+func fundToken(ctx context.Context, rpcClient *rpc.Client, client *ethclient.Client, networkName string, chainID uint64, spec tokenspec.TokenSpec, tokens *big.Int) error {
+	tokenAddress, source, err := tokenspec.Address(networkName, spec.Name)
+	if err != nil {
+		return err
+	}
+	decimals := int(spec.Decimals)
+	amount := createTokenAmount(tokens, decimals)
+	logger.Infof("   🪙 %s: %s (from %s)\n", spec.Name, tokenAddress, source)
+
+	// Mint as the token owner when mint() is owner-only
+	mint, err := newMinter(ctx, rpcClient, client, networkName, chainID, common.HexToAddress(tokenAddress))
+	if err != nil {
+		return fmt.Errorf("cannot mint: %w", err)
+	}
+
+	// Fund each recipient using direct contract call (since Go bindings don't have Mint yet)
+	recipients := envutil.GetEVMRecipients()
+	failed := 0
+	for _, recipient := range recipients {
+		logger.Infof("   💸 Funding %s (%s)...\n", recipient.Name, recipient.Address.Hex())
+
+		// Check current balance
+		before, err := ethutil.ERC20BalanceAt(ctx, client, common.HexToAddress(tokenAddress), recipient.Address, nil)
+		if err != nil {
+			logger.Errorf("     ❌ Failed to read %s's balance: %v\n", recipient.Name, err)
+			failed++
+			continue
+		}
+		logger.Infof("     📊 Current balance: %s\n", ethutil.FormatTokenAmount(before, decimals))
+
+		// Call mint function directly using raw transaction
+		err = mint.Mint(txcost.WithOperation(txcost.WithNetwork(ctx, networkName), "mint "+spec.Symbol), recipient.Address, amount)
+		if err != nil {
+			logger.Errorf("     ❌ Failed to mint tokens for %s: %v\n", recipient.Name, err)
+			failed++
+			continue
+		}
+
+		logger.Infof("     ✅ Minted %s tokens\n", ethutil.FormatTokenAmount(amount, decimals))
+
+		// Verify the balance rose by the minted amount
+		after, err := ethutil.ERC20BalanceAt(ctx, client, common.HexToAddress(tokenAddress), recipient.Address, nil)
+		if err == nil {
+			err = checkBalanceIncrease(before, after, amount)
+		}
+		if err != nil {
+			logger.Errorf("     ❌ %s's balance not verified: %v\n", recipient.Name, err)
+			failed++
+			continue
+		}
+		logger.Infof("     💰 New balance: %s\n", ethutil.FormatTokenAmount(after, decimals))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d mints failed or were not verified", failed, len(recipients))
+	}
+	return nil
+}
+
+func createTokenAmount(tokens *big.Int, decimals int) *big.Int {
+	amount := new(big.Int).Set(tokens)
+	multiplier := big.NewInt(base10)
+	multiplier.Exp(multiplier, big.NewInt(int64(decimals)), nil)
+	return amount.Mul(amount, multiplier)
+}
@@ -0,0 +1,37 @@
+package fundaccounts
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunFundingLegs(t *testing.T) {
+	var ran []string
+	leg := func(name string, err error) fundingLeg {
+		return fundingLeg{Name: name, Fund: func() error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+
+	failed := runFundingLegs([]fundingLeg{
+		leg("base", nil),
+		leg("starknet", errors.New("DogCoin address not found: set STARKNET_DOG_COIN_ADDRESS")),
+		leg("ztarknet", nil),
+	})
+
+	assert.Equal(t, []string{"base", "starknet", "ztarknet"}, ran, "a failed leg does not stop the next")
+	assert.Equal(t, []string{"starknet"}, failed)
+}
+
+func TestCreateTokenAmount(t *testing.T) {
+	tokens := big.NewInt(defaultFundingAmount)
+	amount := createTokenAmount(tokens, tokenDecimals)
+
+	expected, _ := new(big.Int).SetString("420690000000000000000000000000", 10)
+	assert.Equal(t, expected, amount)
+	assert.Equal(t, big.NewInt(defaultFundingAmount), tokens, "the input is not modified")
+}
@@ -0,0 +1,260 @@
+package fundaccounts
+
+// Minter selection
+// mint() on the dog coins may be owner-only, so the mints are signed with the configured key whose address is the
+// token's owner(): <NETWORK>_MINTER_PRIVATE_KEY, DEPLOYER_PRIVATE_KEY, then Alice's key (each may instead come
+// from the keystore its <NAME>_KEY_SOURCE points at, see pkg/credentials). A token without owner()
+// mints for anyone and uses the first key configured. When no key matches on an anvil fork the owner is
+// impersonated instead; FORKING=false turns that off
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+// tokenABI is the part of MockERC20 the tool calls
+const tokenABI = `[
+	{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"name":"mint","outputs":[],"stateMutability":"nonpayable","type":"function"},
+	{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"}
+]`
+
+var parsedTokenABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(tokenABI))
+	if err != nil {
+		panic(fmt.Sprintf("invalid token ABI: %v", err))
+	}
+	return parsed
+}()
+
+// minterKey is a configured private key and where it came from
+type minterKey struct {
+	Source  string
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+}
+
+// minterKeyPrefixes lists the credential prefixes of the candidate minter keys for networkName, in order of
+// preference; each key is read from <prefix>_PRIVATE_KEY or the source <prefix>_KEY_SOURCE selects
+func minterKeyPrefixes(networkName string) []string {
+	return []string{
+		strings.ToUpper(networkName) + "_MINTER",
+		envutil.ConditionalKey("DEPLOYER"),
+		envutil.ConditionalKey("ALICE"),
+	}
+}
+
+// configured reports whether an env value is set to something other than an example.env placeholder
+func configured(value string) bool {
+	return value != "" && !strings.Contains(value, " ")
+}
+
+// loadMinterKeys loads the candidate keys that are configured
+func loadMinterKeys(networkName string) ([]minterKey, error) {
+	var keys []minterKey
+	for _, prefix := range minterKeyPrefixes(networkName) {
+		provider, err := credentials.For(prefix)
+		if err != nil {
+			return nil, err
+		}
+		key, err := provider.EVMKey()
+		if errors.Is(err, credentials.ErrMissingKey) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, minterKey{Source: provider.String(), Key: key, Address: crypto.PubkeyToAddress(key.PublicKey)})
+	}
+	return keys, nil
+}
+
+// selectMinterKey returns the key for owner, or the first key when owner is nil (the token has no owner()).
+// It returns nil when no key matches
+func selectMinterKey(keys []minterKey, owner *common.Address) *minterKey {
+	for i := range keys {
+		if owner == nil || keys[i].Address == *owner {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+// readOwner calls owner() on token. It returns nil when the call reverts, i.e. the token is not Ownable
+func readOwner(ctx context.Context, caller ethereum.ContractCaller, token common.Address) (*common.Address, error) {
+	data, err := parsedTokenABI.Pack("owner")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack owner call: %w", err)
+	}
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to call owner(): %w", err)
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	values, err := parsedTokenABI.Unpack("owner", out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode owner(): %w", err)
+	}
+	owner, ok := values[0].(common.Address)
+	if !ok {
+		return nil, fmt.Errorf("unexpected owner() result %T", values[0])
+	}
+	return &owner, nil
+}
+
+// impersonationAllowed reports whether FORKING permits impersonating the owner (it does unless FORKING=false)
+func impersonationAllowed() (bool, error) {
+	value := os.Getenv("FORKING")
+	if value == "" {
+		return true, nil
+	}
+	forking, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid FORKING=%q: %w", value, err)
+	}
+	return forking, nil
+}
+
+// minter sends mint(to, amount) on one network's token
+type minter interface {
+	Mint(ctx context.Context, to common.Address, amount *big.Int) error
+}
+
+// newMinter picks how the mints for token are sent: signed with a matching key, or from the impersonated owner
+func newMinter(ctx context.Context, rpcClient *rpc.Client, client *ethclient.Client, networkName string, chainID uint64, token common.Address) (minter, error) {
+	keys, err := loadMinterKeys(networkName)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := readOwner(ctx, client, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if key := selectMinterKey(keys, owner); key != nil {
+		logger.Infof("   🔑 Minter: %s (%s)\n", key.Address.Hex(), key.Source)
+		auth, err := ethutil.NewTransactor(new(big.Int).SetUint64(chainID), key.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transactor: %w", err)
+		}
+		return &signingMinter{client: client, auth: auth, token: token}, nil
+	}
+
+	prefixes := minterKeyPrefixes(networkName)
+	names := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		names[i] = prefix + "_PRIVATE_KEY"
+	}
+	envs := strings.Join(names, ", ")
+	if owner == nil {
+		return nil, fmt.Errorf("no minter key configured: set one of %s", envs)
+	}
+
+	allowed, err := impersonationAllowed()
+	if err != nil {
+		return nil, err
+	}
+	var dummy interface{}
+	if allowed && rpcClient.CallContext(ctx, &dummy, "anvil_impersonateAccount", owner.Hex()) == nil {
+		logger.Infof("   🎭 Minter: impersonating owner %s\n", owner.Hex())
+		return &impersonatingMinter{client: client, rpcClient: rpcClient, owner: *owner, token: token}, nil
+	}
+	return nil, fmt.Errorf("mint() is restricted to the token owner %s and none of %s holds its key", owner.Hex(), envs)
+}
+
+// signingMinter signs the mints with the minter key
+type signingMinter struct {
+	client *ethclient.Client
+	auth   *bind.TransactOpts
+	token  common.Address
+}
+
+func (m *signingMinter) Mint(ctx context.Context, to common.Address, amount *big.Int) error {
+	data, err := parsedTokenABI.Pack("mint", to, amount)
+	if err != nil {
+		return fmt.Errorf("failed to pack mint call: %w", err)
+	}
+
+	// Send with an estimated gas limit, as a dynamic-fee transaction when the chain supports it
+	signedTx, err := ethutil.SendTx(ctx, m.client, m.auth, m.token, nil, data)
+	if err != nil {
+		if revert := ethutil.DecodeRevertError(err); revert != nil {
+			return fmt.Errorf("mint would revert: %w", revert)
+		}
+		return fmt.Errorf("failed to send mint transaction: %w", err)
+	}
+	logger.Infof("     🚀 Mint transaction: %s\n", signedTx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(ctx, m.client, signedTx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for mint confirmation: %w", err)
+	}
+	txcost.RecordEVM(ctx, signedTx, receipt)
+	return checkMintReceipt(receipt)
+}
+
+// impersonatingMinter sends the mints unsigned from the token owner, impersonated on an anvil fork
+type impersonatingMinter struct {
+	client    *ethclient.Client
+	rpcClient *rpc.Client
+	owner     common.Address
+	token     common.Address
+}
+
+func (m *impersonatingMinter) Mint(ctx context.Context, to common.Address, amount *big.Int) error {
+	data, err := parsedTokenABI.Pack("mint", to, amount)
+	if err != nil {
+		return fmt.Errorf("failed to pack mint call: %w", err)
+	}
+
+	params := map[string]interface{}{
+		"from": m.owner.Hex(),
+		"to":   m.token.Hex(),
+		"data": "0x" + hex.EncodeToString(data),
+	}
+	var txHash common.Hash
+	if err := m.rpcClient.CallContext(ctx, &txHash, "eth_sendTransaction", params); err != nil {
+		return fmt.Errorf("failed to send mint transaction: %w", err)
+	}
+	logger.Infof("     🚀 Mint transaction: %s\n", txHash.Hex())
+
+	receipt, err := bind.WaitMinedHash(ctx, m.client, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to wait for mint confirmation: %w", err)
+	}
+	return checkMintReceipt(receipt)
+}
+
+func checkMintReceipt(receipt *gethtypes.Receipt) error {
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("mint transaction %s reverted", receipt.TxHash.Hex())
+	}
+	logger.Infof("     ⛽ Gas used: %d\n", receipt.GasUsed)
+	return nil
+}
@@ -0,0 +1,161 @@
+package fundaccounts
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/evmtest"
+)
+
+// Anvil's default accounts 0 (deployer) and 1 (Alice)
+const (
+	testDeployerKey     = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	testDeployerAddress = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	testAliceKey        = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	testAliceAddress    = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+)
+
+// fakeCaller answers eth_call with a fixed result or error
+type fakeCaller struct {
+	out []byte
+	err error
+}
+
+func (f fakeCaller) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return f.out, f.err
+}
+
+func clearMinterEnv(t *testing.T) {
+	t.Setenv("IS_DEVNET", "")
+	for _, prefix := range []string{"BASE_MINTER", "DEPLOYER", "LOCAL_DEPLOYER", "ALICE", "LOCAL_ALICE"} {
+		t.Setenv(prefix+"_PRIVATE_KEY", "")
+		t.Setenv(prefix+"_KEY_SOURCE", "")
+	}
+}
+
+func TestLoadMinterKeys(t *testing.T) {
+	t.Run("order_and_devnet_names", func(t *testing.T) {
+		clearMinterEnv(t)
+		t.Setenv("IS_DEVNET", "true")
+		t.Setenv("LOCAL_ALICE_PRIVATE_KEY", testAliceKey)
+		t.Setenv("LOCAL_DEPLOYER_PRIVATE_KEY", testDeployerKey)
+		t.Setenv("DEPLOYER_PRIVATE_KEY", "your deployer private key")
+
+		keys, err := loadMinterKeys("base")
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "LOCAL_DEPLOYER_PRIVATE_KEY", keys[0].Source)
+		assert.Equal(t, common.HexToAddress(testDeployerAddress), keys[0].Address)
+		assert.Equal(t, "LOCAL_ALICE_PRIVATE_KEY", keys[1].Source)
+	})
+
+	t.Run("network_override_first", func(t *testing.T) {
+		clearMinterEnv(t)
+		t.Setenv("DEPLOYER_PRIVATE_KEY", testDeployerKey)
+		t.Setenv("BASE_MINTER_PRIVATE_KEY", testAliceKey)
+
+		keys, err := loadMinterKeys("base")
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "BASE_MINTER_PRIVATE_KEY", keys[0].Source)
+	})
+
+	t.Run("invalid_key_names_env", func(t *testing.T) {
+		clearMinterEnv(t)
+		t.Setenv("BASE_MINTER_PRIVATE_KEY", "0x1234")
+
+		_, err := loadMinterKeys("base")
+		assert.ErrorContains(t, err, "BASE_MINTER_PRIVATE_KEY")
+	})
+
+	t.Run("keystore_source", func(t *testing.T) {
+		clearMinterEnv(t)
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(testDeployerKey, "0x"))
+		require.NoError(t, err)
+		keyJSON, err := keystore.EncryptKey(&keystore.Key{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}, "pw", keystore.LightScryptN, keystore.LightScryptP)
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		keyFile, passwordFile := filepath.Join(dir, "deployer.json"), filepath.Join(dir, "password")
+		require.NoError(t, os.WriteFile(keyFile, keyJSON, 0o600))
+		require.NoError(t, os.WriteFile(passwordFile, []byte("pw\n"), 0o600))
+		t.Setenv("DEPLOYER_KEY_SOURCE", "keystore:"+keyFile)
+		t.Setenv("DEPLOYER_KEY_PASSWORD_FILE", passwordFile)
+
+		keys, err := loadMinterKeys("base")
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, "keystore "+keyFile, keys[0].Source)
+		assert.Equal(t, common.HexToAddress(testDeployerAddress), keys[0].Address)
+	})
+}
+
+func TestSelectMinterKey(t *testing.T) {
+	clearMinterEnv(t)
+	t.Setenv("DEPLOYER_PRIVATE_KEY", testDeployerKey)
+	t.Setenv("ALICE_PRIVATE_KEY", testAliceKey)
+	keys, err := loadMinterKeys("base")
+	require.NoError(t, err)
+
+	alice := common.HexToAddress(testAliceAddress)
+	stranger := common.HexToAddress("0x1")
+
+	assert.Equal(t, "ALICE_PRIVATE_KEY", selectMinterKey(keys, &alice).Source, "the owner's key wins over earlier keys")
+	assert.Equal(t, "DEPLOYER_PRIVATE_KEY", selectMinterKey(keys, nil).Source, "open mint uses the first key")
+	assert.Nil(t, selectMinterKey(keys, &stranger))
+	assert.Nil(t, selectMinterKey(nil, nil))
+}
+
+func TestReadOwner(t *testing.T) {
+	owner := common.HexToAddress(testDeployerAddress)
+
+	t.Run("ownable", func(t *testing.T) {
+		got, err := readOwner(context.Background(), fakeCaller{out: common.LeftPadBytes(owner.Bytes(), 32)}, common.Address{})
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, owner, *got)
+	})
+
+	t.Run("not_ownable", func(t *testing.T) {
+		got, err := readOwner(context.Background(), fakeCaller{err: evmtest.RevertError{}}, common.Address{})
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		got, err = readOwner(context.Background(), fakeCaller{}, common.Address{})
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("transport_error", func(t *testing.T) {
+		_, err := readOwner(context.Background(), fakeCaller{err: errors.New("connection refused")}, common.Address{})
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestImpersonationAllowed(t *testing.T) {
+	t.Setenv("FORKING", "")
+	allowed, err := impersonationAllowed()
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	t.Setenv("FORKING", "false")
+	allowed, err = impersonationAllowed()
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	t.Setenv("FORKING", "maybe")
+	_, err = impersonationAllowed()
+	assert.Error(t, err)
+}
@@ -1,159 +1,207 @@
-package main
+package fundaccounts
+
+// Funding on the Cairo networks (Starknet and Ztarknet)
+// The mints are sent from the network's deployer account (falling back to Alice's), and each recipient's
+// balance is re-read after the receipt to check it rose by exactly the minted amount
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
-	"os"
 	"time"
 
-	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
-func fundStarknet(amount *big.Int) {
-	fmt.Printf("📡 Funding Starknet network...\n")
+// receiptPollInterval is how often the mint receipt is polled
+const receiptPollInterval = 2 * time.Second
 
-	// Load network configuration
-	config.InitializeNetworks()
+// cairoAccount is a Starknet-style account and where its key pair is read from
+type cairoAccount struct {
+	Name    string
+	Address string
+	// KeyPrefix names the account's key env vars, e.g. STARKNET_DEPLOYER (see pkg/credentials)
+	KeyPrefix string
+}
 
-	starknetConfig, exists := config.Networks["Starknet"]
-	if !exists {
-		log.Fatalf("Starknet network not found in config")
-	}
+type StarknetRecipient struct {
+	Name    string
+	Address string
+}
+
+// cairoNetwork is what fund-accounts reads from env for one Cairo network
+type cairoNetwork struct {
+	// Name is the config.Networks key, which also carries the RPC URL and chain ID
+	Name       string
+	Minters    []cairoAccount
+	Recipients []StarknetRecipient
+}
 
-	// Get MockERC20 address from environment
-	tokenAddress := os.Getenv("STARKNET_DOG_COIN_ADDRESS")
-	if tokenAddress == "" {
-		log.Fatalf("STARKNET_DOG_COIN_ADDRESS not found in environment")
+func starknetNetwork() cairoNetwork {
+	return cairoNetwork{
+		Name: "Starknet",
+		Minters: []cairoAccount{
+			{
+				Name:      "deployer",
+				Address:   envutil.GetConditionalAccountEnv("STARKNET_DEPLOYER_ADDRESS"),
+				KeyPrefix: envutil.ConditionalKey("STARKNET_DEPLOYER"),
+			},
+			{
+				Name:      "Alice",
+				Address:   envutil.GetStarknetAliceAddress(),
+				KeyPrefix: envutil.ConditionalKey("STARKNET_ALICE"),
+			},
+		},
+		Recipients: []StarknetRecipient{
+			{Name: "Alice", Address: envutil.GetStarknetAliceAddress()},
+			{Name: "Solver", Address: envutil.GetStarknetSolverAddress()},
+		},
 	}
+}
 
-	// Connect to Starknet
-	client, err := rpc.NewProvider(starknetConfig.RPCURL)
-	if err != nil {
-		log.Fatalf("Failed to connect to Starknet: %v", err)
+// minter returns the first account with an address and a key pair, and its key pair
+func (n cairoNetwork) minter() (cairoAccount, credentials.StarknetKeyPair, error) {
+	for _, acct := range n.Minters {
+		if !configured(acct.Address) {
+			continue
+		}
+		key, err := credentials.LoadStarknetKey(acct.KeyPrefix)
+		if errors.Is(err, credentials.ErrMissingKey) {
+			continue
+		}
+		if err != nil {
+			return cairoAccount{}, credentials.StarknetKeyPair{}, fmt.Errorf("%s %s key: %w", n.Name, acct.Name, err)
+		}
+		return acct, key, nil
 	}
+	return cairoAccount{}, credentials.StarknetKeyPair{}, fmt.Errorf("%s minter credentials not found (deployer or Alice address, public and private key)", n.Name)
+}
 
-	fmt.Printf("   📍 Network: Starknet (Chain ID: %d)\n", starknetConfig.ChainID)
-	fmt.Printf("   🪙 MockERC20: %s\n", tokenAddress)
+func fundStarknet(ctx context.Context, specs []tokenspec.TokenSpec, tokens *big.Int) error {
+	return fundCairoNetwork(ctx, starknetNetwork(), specs, tokens)
+}
 
-	// Get minter account (use Alice as minter)
-	minterPrivateKey := envutil.GetStarknetAlicePrivateKey()
-	minterPublicKey := envutil.GetStarknetAlicePublicKey()
-	minterAddress := envutil.GetStarknetAliceAddress()
+// fundCairoNetwork mints tokens (whole tokens, scaled by each token's decimals) of every token to each recipient
+// on network
+func fundCairoNetwork(ctx context.Context, network cairoNetwork, specs []tokenspec.TokenSpec, tokens *big.Int) error {
+	logger.Infof("📡 Funding %s network...\n", network.Name)
 
-	if minterPrivateKey == "" || minterPublicKey == "" {
-		log.Fatalf("Starknet minter credentials not found (Alice's keys)")
+	// Load network configuration
+	config.InitializeNetworks()
+	networkConfig, exists := config.Networks[network.Name]
+	if !exists {
+		return fmt.Errorf("%s network not found in config", network.Name)
 	}
 
-	// Create minter account
-	minterAddrFelt, err := utils.HexToFelt(minterAddress)
+	client, err := rpc.NewProvider(networkConfig.RPCURL)
 	if err != nil {
-		log.Fatalf("Failed to convert minter address to felt: %v", err)
+		return fmt.Errorf("failed to connect to %s: %w", network.Name, err)
 	}
-
-	minterKs := account.NewMemKeystore()
-	minterPrivKeyBI, ok := new(big.Int).SetString(minterPrivateKey, 0)
-	if !ok {
-		log.Fatalf("Failed to parse minter private key")
+	if err := config.VerifyStarknetChainID(ctx, client, networkConfig); err != nil {
+		return err
 	}
-	minterKs.Put(minterPublicKey, minterPrivKeyBI)
 
-	minterAccount, err := account.NewAccount(client, minterAddrFelt, minterPublicKey, minterKs, account.CairoV2)
+	logger.Infof("   📍 Network: %s (Chain ID: %s)\n", network.Name, networkConfig.ChainIDLabel())
+
+	minter, minterKey, err := network.minter()
 	if err != nil {
-		log.Fatalf("Failed to create minter account: %v", err)
+		return err
 	}
+	minterAccount, err := newCairoAccount(ctx, client, network.Name, minter, minterKey)
+	if err != nil {
+		return err
+	}
+	logger.Infof("   🔑 Minter: %s (%s)\n", minter.Address, minter.Name)
 
-	// Get recipient addresses
-	recipients := getStarknetRecipients()
-
-	// Fund each recipient
-	for _, recipient := range recipients {
-		fmt.Printf("   💸 Funding %s (%s)...\n", recipient.Name, recipient.Address)
-
-		// Check current balance
-		currentBalance, err := starknetutil.ERC20Balance(client, tokenAddress, recipient.Address)
-		if err == nil {
-			fmt.Printf("     📊 Current balance: %s\n", starknetutil.FormatTokenAmount(currentBalance, tokenDecimals))
-		}
-
-		// Convert addresses to felts for the mint call
-		tokenFelt, err := utils.HexToFelt(tokenAddress)
+	failed := 0
+	for _, spec := range specs {
+		tokenAddress, source, err := tokenspec.Address(network.Name, spec.Name)
 		if err != nil {
-			log.Printf("     ❌ Failed to convert token address to felt: %v", err)
+			logger.Errorf("   ❌ %v\n", err)
+			failed += len(network.Recipients)
 			continue
 		}
+		logger.Infof("   🪙 %s: %s (from %s)\n", spec.Name, tokenAddress, source)
 
-		recipientFelt, err := utils.HexToFelt(recipient.Address)
+		// The Cairo mock fixes its own decimals, so scale by what the contract reports
+		decimals, err := starknetutil.ERC20Decimals(ctx, client, tokenAddress)
 		if err != nil {
-			log.Printf("     ❌ Failed to convert recipient address to felt: %v", err)
-			continue
+			logger.Warnf("   ⚠️  Could not read decimals, assuming %d: %v\n", spec.Decimals, err)
+			decimals = spec.Decimals
 		}
-
-		// Convert amount to two felts (low, high) for u256
-		amountLow, amountHigh := starknetutil.ConvertBigIntToU256Felts(amount)
-
-		// Build mint calldata: mint(to: ContractAddress, amount: u256)
-		mintCalldata := []*felt.Felt{recipientFelt, amountLow, amountHigh}
-
-		// Create mint transaction
-		mintCall := rpc.InvokeFunctionCall{
-			ContractAddress: tokenFelt,
-			FunctionName:    "mint",
-			CallData:        mintCalldata,
+		amount := starknetutil.ScaleTokenAmount(tokens, decimals)
+
+		for _, recipient := range network.Recipients {
+			logger.Infof("   💸 Funding %s (%s)...\n", recipient.Name, recipient.Address)
+			if err := mintAndVerify(txcost.WithOperation(txcost.WithNetwork(ctx, network.Name), "mint "+spec.Symbol), client, minterAccount, tokenAddress, recipient.Address, amount, int(decimals)); err != nil {
+				logger.Errorf("     ❌ Failed to fund %s: %v\n", recipient.Name, err)
+				failed++
+			}
 		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s mints failed", failed, len(specs)*len(network.Recipients), network.Name)
+	}
+	return nil
+}
 
-		// Send mint transaction
-		mintTx, err := minterAccount.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{mintCall}, nil)
-		if err != nil {
-			log.Printf("     ❌ Failed to send mint transaction for %s: %v", recipient.Name, err)
-			continue
-		}
+func newCairoAccount(ctx context.Context, client *rpc.Provider, networkName string, acct cairoAccount, key credentials.StarknetKeyPair) (*account.Account, error) {
+	addrFelt, err := utils.HexToFelt(acct.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s address: %w", acct.Name, err)
+	}
 
-		fmt.Printf("     🚀 Mint transaction: %s\n", mintTx.Hash.String())
+	accnt, err := starknetutil.NewAccount(ctx, client, starknetutil.AccountVersionEnv(networkName, acct.Name), addrFelt, key.PublicKey.String(), key.Keystore())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s account: %w", acct.Name, err)
+	}
+	return accnt, nil
+}
 
-		// Wait for confirmation
-		_, err = minterAccount.WaitForTransactionReceipt(context.Background(), mintTx.Hash, 2*time.Second)
-		if err != nil {
-			log.Printf("     ❌ Failed to wait for transaction confirmation: %v", err)
-			continue
-		}
+// mintAndVerify mints amount to recipient, waits for the receipt and checks the balance rose by exactly amount
+func mintAndVerify(ctx context.Context, client *rpc.Provider, minter *account.Account, tokenAddress, recipient string, amount *big.Int, decimals int) error {
+	before, err := starknetutil.ERC20Balance(ctx, client, tokenAddress, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to read balance: %w", err)
+	}
+	logger.Infof("     📊 Current balance: %s\n", starknetutil.FormatTokenAmount(before, decimals))
 
-		fmt.Printf("     ✅ Minted %s tokens\n", starknetutil.FormatTokenAmount(amount, tokenDecimals))
+	// Send mint transaction: mint(to: ContractAddress, amount: u256)
+	txHash, err := starknetutil.Mint(ctx, minter, tokenAddress, recipient, amount)
+	if err != nil {
+		return err
+	}
+	logger.Infof("     🚀 Mint transaction: %s\n", txHash.String())
 
-		// Verify new balance
-		newBalance, err := starknetutil.ERC20Balance(client, tokenAddress, recipient.Address)
-		if err == nil {
-			fmt.Printf("     💰 New balance: %s\n", starknetutil.FormatTokenAmount(newBalance, tokenDecimals))
-		}
+	// The balance check below confirms the mint, so waiting for the node to take it is enough
+	if _, err := starknetutil.WaitForFinality(ctx, minter.Provider, txHash, starknetutil.FinalityReceived, receiptPollInterval, 0); err != nil {
+		return fmt.Errorf("failed to wait for mint confirmation: %w", err)
 	}
-}
+	logger.Infof("     ✅ Minted %s tokens\n", starknetutil.FormatTokenAmount(amount, decimals))
 
-type StarknetRecipient struct {
-	Name    string
-	Address string
+	after, err := starknetutil.WaitForERC20BalanceChange(ctx, client, tokenAddress, recipient, before, starknetutil.BalancePollOptions{})
+	if err != nil {
+		return err
+	}
+	logger.Infof("     💰 New balance: %s (was %s)\n",
+		starknetutil.FormatTokenAmount(after, decimals), starknetutil.FormatTokenAmount(before, decimals))
+	return checkBalanceIncrease(before, after, amount)
 }
 
-func getStarknetRecipients() []StarknetRecipient {
-	var recipients []StarknetRecipient
-
-	// Alice and Solver
-	recipients = append(recipients,
-		StarknetRecipient{
-			Name:    "Alice",
-			Address: envutil.GetStarknetAliceAddress(),
-		},
-		StarknetRecipient{
-			Name:    "Solver",
-			Address: envutil.GetStarknetSolverAddress(),
-		},
-	)
-
-	return recipients
+// checkBalanceIncrease checks after is exactly before + amount
+func checkBalanceIncrease(before, after, amount *big.Int) error {
+	increase := new(big.Int).Sub(after, before)
+	if increase.Cmp(amount) != 0 {
+		return fmt.Errorf("balance rose by %s, expected %s", increase, amount)
+	}
+	return nil
 }
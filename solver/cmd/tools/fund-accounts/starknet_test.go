@@ -0,0 +1,66 @@
+package fundaccounts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCairoNetworkMinter(t *testing.T) {
+	for prefix, keys := range map[string][2]string{
+		"TEST_DEPLOYER":    {"0x3", "0x2"},
+		"TEST_ALICE":       {"0x6", "0x5"},
+		"TEST_PLACEHOLDER": {"your ztarknet deployer private key", "0x2"},
+	} {
+		t.Setenv(prefix+"_KEY_SOURCE", "")
+		t.Setenv(prefix+"_PRIVATE_KEY", keys[0])
+		t.Setenv(prefix+"_PUBLIC_KEY", keys[1])
+	}
+	deployer := cairoAccount{Name: "deployer", Address: "0x1", KeyPrefix: "TEST_DEPLOYER"}
+	alice := cairoAccount{Name: "Alice", Address: "0x4", KeyPrefix: "TEST_ALICE"}
+	placeholder := cairoAccount{Name: "deployer", Address: "0x1", KeyPrefix: "TEST_PLACEHOLDER"}
+
+	minter, key, err := cairoNetwork{Minters: []cairoAccount{deployer, alice}}.minter()
+	require.NoError(t, err)
+	assert.Equal(t, "deployer", minter.Name)
+	assert.Equal(t, "0x2", key.PublicKey.String())
+
+	minter, key, err = cairoNetwork{Minters: []cairoAccount{placeholder, alice}}.minter()
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", minter.Name, "placeholder credentials are skipped")
+	assert.Equal(t, int64(6), key.PrivateKey.Int64())
+
+	_, _, err = cairoNetwork{Name: "Ztarknet", Minters: []cairoAccount{placeholder}}.minter()
+	assert.ErrorContains(t, err, "Ztarknet minter credentials not found")
+
+	t.Setenv("TEST_DEPLOYER_KEY_SOURCE", "keystore:")
+	_, _, err = cairoNetwork{Name: "Starknet", Minters: []cairoAccount{deployer, alice}}.minter()
+	assert.ErrorContains(t, err, "Starknet deployer key: invalid TEST_DEPLOYER_KEY_SOURCE", "a broken key source does not fall through")
+}
+
+func TestNetworkEnv(t *testing.T) {
+	t.Setenv("IS_DEVNET", "true")
+	t.Setenv("LOCAL_STARKNET_DEPLOYER_ADDRESS", "0xd0")
+	t.Setenv("ZTARKNET_DEPLOYER_ADDRESS", "0xd1")
+	t.Setenv("ZTARKNET_SOLVER_ADDRESS", "0x50")
+
+	starknet := starknetNetwork()
+	assert.Equal(t, "Starknet", starknet.Name)
+	assert.Equal(t, "0xd0", starknet.Minters[0].Address)
+
+	ztarknet := ztarknetNetwork()
+	assert.Equal(t, "Ztarknet", ztarknet.Name)
+	assert.Equal(t, "0xd1", ztarknet.Minters[0].Address)
+	assert.Equal(t, StarknetRecipient{Name: "Solver", Address: "0x50"}, ztarknet.Recipients[1])
+}
+
+func TestCheckBalanceIncrease(t *testing.T) {
+	// An amount above 2^128 spans both felts of the u256
+	amount := new(big.Int).Lsh(big.NewInt(3), 130)
+	before := big.NewInt(7)
+
+	assert.NoError(t, checkBalanceIncrease(before, new(big.Int).Add(before, amount), amount))
+	assert.ErrorContains(t, checkBalanceIncrease(before, amount, amount), "balance rose by")
+}
@@ -0,0 +1,123 @@
+package identities
+
+// Identities tool: lists the users open-order can act for and registers new ones in state/identities.json
+// (see pkg/identity). Only addresses and public keys are stored; keys stay in .env or keystores
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/identity"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// JSONFlag prints the registry as JSON instead of a table
+const JSONFlag = "--json"
+
+const usage = "usage: identities list [--json] | identities add <name> [--evm <address>] [--starknet <address>] " +
+	"[--starknet-public-key <key>] [--ztarknet <address>] [--ztarknet-public-key <key>]"
+
+// RunIdentities runs `tools identities list|add`
+func RunIdentities(args []string) {
+	if _, err := config.LoadConfig(); err != nil {
+		fmt.Printf("❌ failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := run(os.Stdout, identity.Path(), args); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, path string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", usage)
+	}
+	switch args[0] {
+	case "list":
+		asJSON := false
+		for _, arg := range args[1:] {
+			if arg != JSONFlag {
+				return fmt.Errorf("unexpected argument: %s (%s)", arg, usage)
+			}
+			asJSON = true
+		}
+		registry, err := identity.Load(path)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(registry.Identities())
+		}
+		renderTable(w, registry.Identities())
+		return nil
+	case "add":
+		id, err := parseAddArgs(args[1:])
+		if err != nil {
+			return err
+		}
+		if err := identity.Add(path, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✅ Registered %s in %s\n", id.Name, path)
+		return nil
+	default:
+		return fmt.Errorf("unknown identities command %q (%s)", args[0], usage)
+	}
+}
+
+// parseAddArgs parses `add <name> --<chain> <address> ...`
+func parseAddArgs(args []string) (identity.Identity, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "--") {
+		return identity.Identity{}, fmt.Errorf("add requires a name (%s)", usage)
+	}
+	id := identity.Identity{Name: args[0]}
+	fields := map[string]*string{
+		"--evm":                 &id.EVMAddress,
+		"--starknet":            &id.StarknetAddress,
+		"--starknet-public-key": &id.StarknetPublicKey,
+		"--ztarknet":            &id.ZtarknetAddress,
+		"--ztarknet-public-key": &id.ZtarknetPublicKey,
+	}
+	for i := 1; i < len(args); i++ {
+		flag, value, inline := strings.Cut(args[i], "=")
+		field, ok := fields[flag]
+		if !ok {
+			return identity.Identity{}, fmt.Errorf("unexpected argument: %s (%s)", args[i], usage)
+		}
+		if !inline {
+			if i+1 >= len(args) {
+				return identity.Identity{}, fmt.Errorf("%s requires a value", flag)
+			}
+			i++
+			value = args[i]
+		}
+		*field = value
+	}
+	if id.EVMAddress == "" && id.StarknetAddress == "" && id.ZtarknetAddress == "" {
+		return identity.Identity{}, fmt.Errorf("user %s needs at least one of --evm, --starknet or --ztarknet", id.Name)
+	}
+	return id, nil
+}
+
+func renderTable(w io.Writer, ids []identity.Identity) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tEVM\tSTARKNET\tZTARKNET")
+	for _, id := range ids {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", id.Name, orDash(id.EVMAddress), orDash(id.StarknetAddress), orDash(id.ZtarknetAddress))
+	}
+	tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package identities
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/identity"
+)
+
+func TestParseAddArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    identity.Identity
+		wantErr string
+	}{
+		{
+			name: "flags",
+			args: []string{"Bob", "--evm", "0xb0b", "--starknet=0x1", "--starknet-public-key", "0x2"},
+			want: identity.Identity{Name: "Bob", EVMAddress: "0xb0b", StarknetAddress: "0x1", StarknetPublicKey: "0x2"},
+		},
+		{name: "no_name", args: []string{"--evm", "0xb0b"}, wantErr: "add requires a name"},
+		{name: "no_address", args: []string{"Bob", "--starknet-public-key", "0x2"}, wantErr: "needs at least one of"},
+		{name: "missing_value", args: []string{"Bob", "--evm"}, wantErr: "--evm requires a value"},
+		{name: "unknown_flag", args: []string{"Bob", "--solana", "x"}, wantErr: "unexpected argument: --solana"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAddArgs(tt.args)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunAddThenList(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("ALICE_PUB_KEY", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	path := filepath.Join(t.TempDir(), "identities.json")
+
+	var out bytes.Buffer
+	require.NoError(t, run(&out, path, []string{"add", "Bob", "--evm", "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"}))
+	assert.Contains(t, out.String(), "Registered Bob")
+
+	out.Reset()
+	require.NoError(t, run(&out, path, []string{"list"}))
+	assert.Contains(t, out.String(), "Alice")
+	assert.Contains(t, out.String(), "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+
+	assert.ErrorContains(t, run(&out, path, []string{"remove", "Bob"}), `unknown identities command "remove"`)
+}
@@ -0,0 +1,163 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/identity"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/rpcpool"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// newNonceReader reads the nonces of network's Hyperlane7683 through the pool's client
+func newNonceReader(ctx context.Context, pool *rpcpool.Pool, network config.NetworkConfig) (nonceReader, error) {
+	if openorder.GetNetworkType(network.Name) != openorder.NetworkTypeEVM {
+		return newStarknetNonces(ctx, pool, network)
+	}
+	client, err := pool.EVMFailover(ctx, network.Name, network.RPCURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	return newEVMNonceReader(common.HexToAddress(network.HyperlaneAddress), client)
+}
+
+// newNonceInvalidator signs invalidateNonces on network with user's key from the credentials provider
+func newNonceInvalidator(ctx context.Context, pool *rpcpool.Pool, network config.NetworkConfig, user string) (nonceInvalidator, error) {
+	if openorder.GetNetworkType(network.Name) != openorder.NetworkTypeEVM {
+		return newStarknetInvalidator(ctx, pool, network, user)
+	}
+	client, err := pool.EVMFailover(ctx, network.Name, network.RPCURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	hyperlane := common.HexToAddress(network.HyperlaneAddress)
+	reader, err := newEVMNonceReader(hyperlane, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user's key comes from <USER>_PRIVATE_KEY (LOCAL_ on devnet) or the source <USER>_KEY_SOURCE selects
+	prefix := envutil.ConditionalKey(strings.ToUpper(user))
+	key, err := credentials.LoadEVMKey(prefix)
+	if errors.Is(err, credentials.ErrMissingKey) {
+		return nil, fmt.Errorf("private key not found for user %s (%s)", user, prefix+"_PRIVATE_KEY")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load private key for %s: %w", user, err)
+	}
+	auth, err := ethutil.NewTransactor(new(big.Int).SetUint64(network.ChainID), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth: %w", err)
+	}
+	// The nonce burnt must be the one of the account the user opens orders from
+	registered, err := registeredAddress(user, identity.EVM)
+	if err != nil {
+		return nil, err
+	}
+	if common.HexToAddress(registered) != auth.From {
+		return nil, fmt.Errorf("%s key signs as %s, but %s is registered with %s", prefix, auth.From.Hex(), user, registered)
+	}
+	return &evmInvalidator{evmNonceReader: reader, client: client, hyperlane: hyperlane, auth: auth}, nil
+}
+
+// evmNonceReader reads usedNonces and isValidNonce from a Solidity Hyperlane7683
+type evmNonceReader struct {
+	caller *contracts.Hyperlane7683Caller
+}
+
+func newEVMNonceReader(hyperlane common.Address, backend bind.ContractCaller) (*evmNonceReader, error) {
+	caller, err := contracts.NewHyperlane7683Caller(hyperlane, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind Hyperlane7683 at %s: %w", hyperlane.Hex(), err)
+	}
+	return &evmNonceReader{caller: caller}, nil
+}
+
+func (r *evmNonceReader) UsedNonce(ctx context.Context, owner string, nonce *big.Int) (bool, error) {
+	address, err := evmOwner(owner)
+	if err != nil {
+		return false, err
+	}
+	return r.caller.UsedNonces(&bind.CallOpts{Context: ctx}, address, nonce)
+}
+
+func (r *evmNonceReader) IsValidNonce(ctx context.Context, owner string, nonce *big.Int) (bool, error) {
+	address, err := evmOwner(owner)
+	if err != nil {
+		return false, err
+	}
+	return r.caller.IsValidNonce(&bind.CallOpts{Context: ctx}, address, nonce)
+}
+
+// evmOwner parses an EVM nonce owner
+func evmOwner(owner string) (common.Address, error) {
+	if !common.IsHexAddress(owner) {
+		return common.Address{}, fmt.Errorf("invalid EVM address %q", owner)
+	}
+	return common.HexToAddress(owner), nil
+}
+
+// evmInvalidator sends invalidateNonces with the user's key
+type evmInvalidator struct {
+	*evmNonceReader
+	client    *ethclient.Client
+	hyperlane common.Address
+	auth      *bind.TransactOpts
+}
+
+func (s *evmInvalidator) Owner() string { return s.auth.From.Hex() }
+
+func (s *evmInvalidator) Invalidate(ctx context.Context, nonce *big.Int) (string, error) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	data, err := parsed.Pack("invalidateNonces", nonce)
+	if err != nil {
+		return "", fmt.Errorf("failed to pack invalidateNonces: %w", err)
+	}
+	// SendTx estimates the gas first, so a would-be revert comes back decoded before anything is broadcast
+	tx, err := ethutil.SendTx(ctx, s.client, s.auth, s.hyperlane, nil, data)
+	if err != nil {
+		if revert := ethutil.DecodeRevertError(err); revert != nil {
+			err = revert
+		}
+		return "", fmt.Errorf("failed to send invalidateNonces: %w", err)
+	}
+	fmt.Printf("   Transaction sent: %s\n", tx.Hash().Hex())
+
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "invalidate"), s.client, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for invalidateNonces confirmation: %w", err)
+	}
+	if receipt.Status != 1 {
+		return "", fmt.Errorf("invalidateNonces transaction %s reverted", tx.Hash().Hex())
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// registeredAddress returns the address user is registered with on chain (see pkg/identity)
+func registeredAddress(user string, chain identity.Chain) (string, error) {
+	registry, err := identity.Load(identity.Path())
+	if err != nil {
+		return "", fmt.Errorf("failed to load identities: %w", err)
+	}
+	id, err := registry.Lookup(user)
+	if err != nil {
+		return "", err
+	}
+	return id.AddressOn(chain)
+}
@@ -0,0 +1,192 @@
+package nonce
+
+// Nonce tool: reads and burns Hyperlane7683 sender nonces
+// Every order carries a sender nonce the contract marks used when the order is opened. `status` reads
+// usedNonces/isValidNonce for an owner; `invalidate` calls invalidateNonces from a registered user's key, which
+// cancels a gasless order signed with that nonce but not yet opened, and reads the flag back once it is mined
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/rpcpool"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	// UserFlag picks the registered user whose nonce invalidate burns (default: Alice)
+	UserFlag = "--user"
+
+	defaultUser = "Alice"
+
+	usage = "usage: nonce status <network> <address> <nonce> | nonce invalidate <network> <nonce> [--user <name>]"
+)
+
+// nonceRequest is a parsed nonce command
+type nonceRequest struct {
+	Command string
+	Network string
+	Owner   string // status only
+	Nonce   *big.Int
+	User    string // invalidate only
+}
+
+// nonceReader reads the nonce state of a Hyperlane7683 contract
+type nonceReader interface {
+	UsedNonce(ctx context.Context, owner string, nonce *big.Int) (bool, error)
+	IsValidNonce(ctx context.Context, owner string, nonce *big.Int) (bool, error)
+}
+
+// nonceInvalidator burns nonces of the account it signs with
+type nonceInvalidator interface {
+	nonceReader
+	// Owner is the address of the signing account, whose nonces are invalidated
+	Owner() string
+	// Invalidate sends invalidateNonces(nonce) and waits for it to be mined, returning the transaction hash
+	Invalidate(ctx context.Context, nonce *big.Int) (string, error)
+}
+
+// Run runs `nonce status|invalidate`
+func Run(ctx context.Context, args []string) error {
+	req, err := parseNonceArgs(args)
+	if err != nil {
+		return err
+	}
+	if _, err := config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	network, err := config.GetNetworkConfig(req.Network)
+	if err != nil {
+		return err
+	}
+	if network.HyperlaneAddress == "" {
+		return fmt.Errorf("no Hyperlane address configured for %s (set %s_HYPERLANE_ADDRESS)", network.Name, strings.ToUpper(network.Name))
+	}
+
+	pool := rpcpool.New(rpcpool.OptionsFromEnv())
+	defer pool.Close()
+	ctx = txcost.WithNetwork(ctx, network.Name)
+
+	if req.Command == "status" {
+		reader, err := newNonceReader(ctx, pool, network)
+		if err != nil {
+			return err
+		}
+		return printStatus(ctx, os.Stdout, reader, network.Name, req.Owner, req.Nonce)
+	}
+	invalidator, err := newNonceInvalidator(ctx, pool, network, req.User)
+	if err != nil {
+		return err
+	}
+	return invalidate(ctx, os.Stdout, invalidator, network.Name, req.Nonce)
+}
+
+func parseNonceArgs(args []string) (nonceRequest, error) {
+	req := nonceRequest{User: defaultUser}
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == UserFlag || strings.HasPrefix(arg, UserFlag+"="):
+			value, ok := strings.CutPrefix(arg, UserFlag+"=")
+			if !ok {
+				if i+1 >= len(args) {
+					return nonceRequest{}, fmt.Errorf("%s requires a user name", UserFlag)
+				}
+				i++
+				value = args[i]
+			}
+			req.User = value
+		case strings.HasPrefix(arg, "--"):
+			return nonceRequest{}, fmt.Errorf("unexpected argument: %s (%s)", arg, usage)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 {
+		return nonceRequest{}, fmt.Errorf("%s", usage)
+	}
+
+	req.Command = positional[0]
+	var nonce string
+	switch req.Command {
+	case "status":
+		if len(positional) != 4 {
+			return nonceRequest{}, fmt.Errorf("status takes <network> <address> <nonce> (%s)", usage)
+		}
+		req.Network, req.Owner, nonce = positional[1], positional[2], positional[3]
+		if req.User != defaultUser {
+			return nonceRequest{}, fmt.Errorf("%s only applies to invalidate", UserFlag)
+		}
+	case "invalidate":
+		if len(positional) != 3 {
+			return nonceRequest{}, fmt.Errorf("invalidate takes <network> <nonce> (%s)", usage)
+		}
+		req.Network, nonce = positional[1], positional[2]
+	default:
+		return nonceRequest{}, fmt.Errorf("unknown nonce command %q (%s)", req.Command, usage)
+	}
+
+	n, ok := new(big.Int).SetString(nonce, 0)
+	if !ok || n.Sign() < 0 {
+		return nonceRequest{}, fmt.Errorf("invalid nonce %q: expected a non-negative decimal or 0x integer", nonce)
+	}
+	req.Nonce = n
+	return req, nil
+}
+
+// printStatus prints whether nonce is used by owner and can still open an order
+func printStatus(ctx context.Context, w io.Writer, reader nonceReader, networkName, owner string, nonce *big.Int) error {
+	used, err := reader.UsedNonce(ctx, owner, nonce)
+	if err != nil {
+		return fmt.Errorf("usedNonces failed on %s: %w", networkName, err)
+	}
+	valid, err := reader.IsValidNonce(ctx, owner, nonce)
+	if err != nil {
+		return fmt.Errorf("isValidNonce failed on %s: %w", networkName, err)
+	}
+
+	fmt.Fprintf(w, "Nonce %s of %s on %s\n", nonce, owner, networkName)
+	fmt.Fprintf(w, "   usedNonces:   %t\n", used)
+	fmt.Fprintf(w, "   isValidNonce: %t\n", valid)
+	if valid {
+		fmt.Fprintf(w, "✅ usable: an order can be opened with this nonce\n")
+	} else {
+		fmt.Fprintf(w, "⛔ used: orders with this nonce are rejected with InvalidNonce\n")
+	}
+	return nil
+}
+
+// invalidate burns nonce for the signing account and checks the contract reports it used afterwards
+func invalidate(ctx context.Context, w io.Writer, invalidator nonceInvalidator, networkName string, nonce *big.Int) error {
+	owner := invalidator.Owner()
+	valid, err := invalidator.IsValidNonce(ctx, owner, nonce)
+	if err != nil {
+		return fmt.Errorf("isValidNonce failed on %s: %w", networkName, err)
+	}
+	if !valid {
+		// invalidateNonces reverts with InvalidNonce on a used nonce
+		return fmt.Errorf("nonce %s of %s is already used on %s, nothing to invalidate", nonce, owner, networkName)
+	}
+
+	fmt.Fprintf(w, "📤 Invalidating nonce %s of %s on %s...\n", nonce, owner, networkName)
+	txHash, err := invalidator.Invalidate(ctx, nonce)
+	if err != nil {
+		return err
+	}
+
+	used, err := invalidator.UsedNonce(ctx, owner, nonce)
+	if err != nil {
+		return fmt.Errorf("invalidateNonces mined in %s, but usedNonces failed: %w", txHash, err)
+	}
+	if !used {
+		return fmt.Errorf("invalidateNonces mined in %s, but nonce %s is still unused", txHash, nonce)
+	}
+	fmt.Fprintf(w, "✅ Nonce %s invalidated (tx %s)\n", nonce, txHash)
+	return nil
+}
@@ -0,0 +1,223 @@
+package nonce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+const testOwner = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+// fakeNonces keeps used nonces per owner; invalidate marks them used unless stuck
+type fakeNonces struct {
+	owner string
+	used  map[string]bool
+	stuck bool
+	sent  int
+}
+
+func (f *fakeNonces) UsedNonce(_ context.Context, owner string, nonce *big.Int) (bool, error) {
+	return f.used[owner+"/"+nonce.String()], nil
+}
+
+func (f *fakeNonces) IsValidNonce(ctx context.Context, owner string, nonce *big.Int) (bool, error) {
+	used, err := f.UsedNonce(ctx, owner, nonce)
+	return !used, err
+}
+
+func (f *fakeNonces) Owner() string { return f.owner }
+
+func (f *fakeNonces) Invalidate(_ context.Context, nonce *big.Int) (string, error) {
+	f.sent++
+	if !f.stuck {
+		f.used[f.owner+"/"+nonce.String()] = true
+	}
+	return "0xabc", nil
+}
+
+func TestParseNonceArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want nonceRequest
+		err  string
+	}{
+		{
+			name: "status",
+			args: []string{"status", "Base", testOwner, "42"},
+			want: nonceRequest{Command: "status", Network: "Base", Owner: testOwner, Nonce: big.NewInt(42), User: defaultUser},
+		},
+		{
+			name: "invalidate with a hex nonce and user",
+			args: []string{"invalidate", "Starknet", "0x2a", "--user", "Bob"},
+			want: nonceRequest{Command: "invalidate", Network: "Starknet", Nonce: big.NewInt(42), User: "Bob"},
+		},
+		{
+			name: "inline user",
+			args: []string{"invalidate", "--user=Bob", "Base", "7"},
+			want: nonceRequest{Command: "invalidate", Network: "Base", Nonce: big.NewInt(7), User: "Bob"},
+		},
+		{name: "no command", args: nil, err: "usage: nonce"},
+		{name: "unknown command", args: []string{"burn", "Base", "1"}, err: `unknown nonce command "burn"`},
+		{name: "status without owner", args: []string{"status", "Base", "1"}, err: "status takes <network> <address> <nonce>"},
+		{name: "status with user", args: []string{"status", "Base", testOwner, "1", "--user", "Bob"}, err: "--user only applies to invalidate"},
+		{name: "negative nonce", args: []string{"invalidate", "Base", "-1"}, err: `invalid nonce "-1"`},
+		{name: "user without value", args: []string{"invalidate", "Base", "1", "--user"}, err: "--user requires a user name"},
+		{name: "unknown flag", args: []string{"invalidate", "Base", "1", "--json"}, err: "unexpected argument: --json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNonceArgs(tt.args)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrintStatus(t *testing.T) {
+	nonces := &fakeNonces{used: map[string]bool{testOwner + "/1": true}}
+
+	var buf bytes.Buffer
+	require.NoError(t, printStatus(context.Background(), &buf, nonces, "Base", testOwner, big.NewInt(2)))
+	assert.Contains(t, buf.String(), "usedNonces:   false")
+	assert.Contains(t, buf.String(), "✅ usable")
+
+	buf.Reset()
+	require.NoError(t, printStatus(context.Background(), &buf, nonces, "Base", testOwner, big.NewInt(1)))
+	assert.Contains(t, buf.String(), "usedNonces:   true")
+	assert.Contains(t, buf.String(), "⛔ used")
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flag flips", func(t *testing.T) {
+		nonces := &fakeNonces{owner: testOwner, used: map[string]bool{}}
+		var buf bytes.Buffer
+		require.NoError(t, invalidate(ctx, &buf, nonces, "Base", big.NewInt(42)))
+		assert.True(t, nonces.used[testOwner+"/42"])
+		assert.Contains(t, buf.String(), "Nonce 42 invalidated (tx 0xabc)")
+	})
+
+	t.Run("used nonce is refused before sending", func(t *testing.T) {
+		nonces := &fakeNonces{owner: testOwner, used: map[string]bool{testOwner + "/42": true}}
+		err := invalidate(ctx, &bytes.Buffer{}, nonces, "Base", big.NewInt(42))
+		assert.ErrorContains(t, err, "already used on Base")
+		assert.Zero(t, nonces.sent)
+	})
+
+	t.Run("flag that did not flip fails", func(t *testing.T) {
+		nonces := &fakeNonces{owner: testOwner, used: map[string]bool{}, stuck: true}
+		err := invalidate(ctx, &bytes.Buffer{}, nonces, "Base", big.NewInt(42))
+		assert.ErrorContains(t, err, "nonce 42 is still unused")
+	})
+}
+
+// abiBackend answers Hyperlane7683 nonce views through the generated binding's ABI, standing in for a deployed
+// contract that has used the nonces in used
+type abiBackend struct {
+	abi  *abi.ABI
+	used map[common.Address]map[string]bool
+}
+
+func (b *abiBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x60, 0x80}, nil
+}
+
+func (b *abiBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := b.abi.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	used := b.used[args[0].(common.Address)][args[1].(*big.Int).String()]
+	switch method.RawName {
+	case "usedNonces":
+		return method.Outputs.Pack(used)
+	case "isValidNonce":
+		return method.Outputs.Pack(!used)
+	}
+	return nil, errors.New("unexpected call " + method.RawName)
+}
+
+func TestEVMNonceReader(t *testing.T) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+	owner := common.HexToAddress(testOwner)
+	backend := &abiBackend{abi: parsed, used: map[common.Address]map[string]bool{owner: {"42": true}}}
+	reader, err := newEVMNonceReader(common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"), backend)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	used, err := reader.UsedNonce(ctx, testOwner, big.NewInt(42))
+	require.NoError(t, err)
+	assert.True(t, used)
+	valid, err := reader.IsValidNonce(ctx, testOwner, big.NewInt(43))
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = reader.UsedNonce(ctx, "0x1234", big.NewInt(42))
+	assert.ErrorContains(t, err, `invalid EVM address "0x1234"`)
+}
+
+// fakeStarknetNonces answers used_nonces and is_valid_nonce for one used (owner, nonce) pair
+type fakeStarknetNonces struct {
+	owner, nonce *felt.Felt
+	calls        []rpc.FunctionCall
+}
+
+func (f *fakeStarknetNonces) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	f.calls = append(f.calls, call)
+	used := call.Calldata[0].Equal(f.owner) && call.Calldata[1].Equal(f.nonce)
+	if call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt("is_valid_nonce")) {
+		used = !used
+	}
+	if used {
+		return []*felt.Felt{new(felt.Felt).SetUint64(1)}, nil
+	}
+	return []*felt.Felt{new(felt.Felt)}, nil
+}
+
+func TestStarknetNonces(t *testing.T) {
+	ctx := context.Background()
+	caller := &fakeStarknetNonces{owner: new(felt.Felt).SetUint64(0xa11ce), nonce: new(felt.Felt).SetUint64(42)}
+	hyperlane := new(felt.Felt).SetUint64(0x7683)
+	reader := &starknetNonces{caller: caller, hyperlane: hyperlane}
+
+	used, err := reader.UsedNonce(ctx, "0xa11ce", big.NewInt(42))
+	require.NoError(t, err)
+	assert.True(t, used)
+	assert.True(t, hyperlane.Equal(caller.calls[0].ContractAddress))
+	assert.Equal(t, utils.GetSelectorFromNameFelt("used_nonces"), caller.calls[0].EntryPointSelector)
+
+	valid, err := reader.IsValidNonce(ctx, "0xa11ce", big.NewInt(42))
+	require.NoError(t, err)
+	assert.False(t, valid)
+	valid, err = reader.IsValidNonce(ctx, "0xa11ce", big.NewInt(43))
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 252)
+	_, err = reader.UsedNonce(ctx, "0xa11ce", tooLarge)
+	assert.ErrorContains(t, err, "does not fit in a felt252")
+}
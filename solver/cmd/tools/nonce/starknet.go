@@ -0,0 +1,162 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/identity"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/rpcpool"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// receiptPollInterval is how often a sent invalidate_nonces is polled for its receipt
+const receiptPollInterval = 2 * time.Second
+
+// starknetNonces reads used_nonces and is_valid_nonce from a Cairo Hyperlane7683
+type starknetNonces struct {
+	caller    starknetutil.ContractCaller
+	hyperlane *felt.Felt
+}
+
+func newStarknetNonces(ctx context.Context, pool *rpcpool.Pool, network config.NetworkConfig) (*starknetNonces, error) {
+	provider, err := pool.StarknetFailover(ctx, network.Name, network.RPCURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	hyperlane, err := utils.HexToFelt(network.HyperlaneAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s Hyperlane address %q: %w", network.Name, network.HyperlaneAddress, err)
+	}
+	return &starknetNonces{caller: provider, hyperlane: hyperlane}, nil
+}
+
+func (r *starknetNonces) UsedNonce(ctx context.Context, owner string, nonce *big.Int) (bool, error) {
+	return r.call(ctx, "used_nonces", owner, nonce)
+}
+
+func (r *starknetNonces) IsValidNonce(ctx context.Context, owner string, nonce *big.Int) (bool, error) {
+	return r.call(ctx, "is_valid_nonce", owner, nonce)
+}
+
+// call invokes a (owner: ContractAddress, nonce: felt252) -> bool view
+func (r *starknetNonces) call(ctx context.Context, function, owner string, nonce *big.Int) (bool, error) {
+	ownerFelt, err := utils.HexToFelt(owner)
+	if err != nil {
+		return false, fmt.Errorf("invalid Starknet address %q: %w", owner, err)
+	}
+	nonceFelt, err := nonceFelt(nonce)
+	if err != nil {
+		return false, err
+	}
+	resp, err := r.caller.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    r.hyperlane,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           []*felt.Felt{ownerFelt, nonceFelt},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return false, err
+	}
+	if len(resp) == 0 {
+		return false, fmt.Errorf("%s returned no data", function)
+	}
+	return !resp[0].IsZero(), nil
+}
+
+// nonceFelt converts a nonce to the felt252 the Cairo contract keys nonces by
+func nonceFelt(nonce *big.Int) (*felt.Felt, error) {
+	f := new(felt.Felt).SetBigInt(nonce)
+	if f.BigInt(new(big.Int)).Cmp(nonce) != 0 {
+		return nil, fmt.Errorf("nonce %s does not fit in a felt252", nonce)
+	}
+	return f, nil
+}
+
+// starknetInvalidator sends invalidate_nonces from the user's account
+type starknetInvalidator struct {
+	*starknetNonces
+	account *account.Account
+}
+
+// newStarknetInvalidator opens the user's registered account on network with the key pair of
+// <NETWORK>_<USER> (LOCAL_ on devnet for Starknet)
+func newStarknetInvalidator(ctx context.Context, pool *rpcpool.Pool, network config.NetworkConfig, user string) (*starknetInvalidator, error) {
+	reader, err := newStarknetNonces(ctx, pool, network)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := pool.StarknetFailover(ctx, network.Name, network.RPCURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+
+	chain, prefix, versionNetwork := identity.Starknet, envutil.ConditionalKey("STARKNET_"+strings.ToUpper(user)), "Starknet"
+	if openorder.GetNetworkType(network.Name) == openorder.NetworkTypeZtarknet {
+		chain, prefix, versionNetwork = identity.Ztarknet, "ZTARKNET_"+strings.ToUpper(user), "Ztarknet"
+	}
+	owner, err := registeredAddress(user, chain)
+	if err != nil {
+		return nil, err
+	}
+	address, err := utils.HexToFelt(owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s address of %s: %w", chain, user, err)
+	}
+	key, err := credentials.LoadStarknetKey(prefix)
+	if errors.Is(err, credentials.ErrMissingKey) {
+		return nil, fmt.Errorf("missing %s credentials of %s: %s_PRIVATE_KEY and %s_PUBLIC_KEY are required", chain, user, prefix, prefix)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s key: %w", prefix, err)
+	}
+	if err := key.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid %s key pair: %w", prefix, err)
+	}
+	// A key the account does not hold fails validation on the node with an error that does not say so
+	if err := starknetutil.CheckAccountPublicKey(ctx, provider, address, key.PublicKey); err != nil && !errors.Is(err, starknetutil.ErrPublicKeyUnavailable) {
+		return nil, err
+	}
+
+	accnt, err := starknetutil.NewAccount(ctx, provider, starknetutil.AccountVersionEnv(versionNetwork, user), address, key.PublicKey.String(), key.Keystore())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account for %s: %w", owner, err)
+	}
+	return &starknetInvalidator{starknetNonces: reader, account: accnt}, nil
+}
+
+func (s *starknetInvalidator) Owner() string { return s.account.Address.String() }
+
+func (s *starknetInvalidator) Invalidate(ctx context.Context, nonce *big.Int) (string, error) {
+	nonceFelt, err := nonceFelt(nonce)
+	if err != nil {
+		return "", err
+	}
+	tx, err := s.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{{
+		ContractAddress: s.hyperlane,
+		FunctionName:    "invalidate_nonces",
+		CallData:        []*felt.Felt{nonceFelt},
+	}}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to send invalidate_nonces: %w", err)
+	}
+	fmt.Printf("   Transaction sent: %s\n", tx.Hash.String())
+
+	waitCtx := txcost.WithOperation(ctx, "invalidate")
+	if _, err := starknetutil.WaitForFinality(waitCtx, s.account.Provider, tx.Hash, starknetutil.FinalityAcceptedOnL2, receiptPollInterval, 0); err != nil {
+		return "", fmt.Errorf("failed to wait for invalidate_nonces confirmation: %w", err)
+	}
+	return tx.Hash.String(), nil
+}
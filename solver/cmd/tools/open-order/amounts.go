@@ -0,0 +1,137 @@
+package openorder
+
+// Order amounts
+// --amount-in and --amount-out replace an order's generated amounts with decimal token amounts such as 1500.5.
+// They are scaled exactly by each token's own decimals once those are read from chain: an amount with more
+// fractional digits than its token has is an error, never rounded. An order paying out more than it takes in is
+// refused unless --allow-negative-spread is given, since no rational solver fills it
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	// AmountInFlag sets the amount Alice locks on the origin chain, in tokens
+	AmountInFlag = "--amount-in"
+	// AmountOutFlag sets the amount Alice receives on the destination chain, in tokens
+	AmountOutFlag = "--amount-out"
+	// AllowNegativeSpreadFlag opens orders whose output is worth more tokens than their input, with a warning
+	AllowNegativeSpreadFlag = "--allow-negative-spread"
+)
+
+// OrderAmounts are the amounts requested on the command line as decimal token amounts; empty ones are generated
+type OrderAmounts struct {
+	In                  string
+	Out                 string
+	AllowNegativeSpread bool
+}
+
+// orderAmounts are the amounts used by every order this run opens
+var orderAmounts OrderAmounts
+
+// SetOrderAmounts sets the amounts for the orders of this run
+func SetOrderAmounts(amounts OrderAmounts) {
+	orderAmounts = amounts
+}
+
+// ExtractAmountFlags removes --amount-in/--amount-out (or the =value forms) and --allow-negative-spread from args.
+// Amounts must be positive and have at most 18 decimals; the tokens' own decimals are checked once they are known
+func ExtractAmountFlags(args []string) ([]string, OrderAmounts, error) {
+	out := make([]string, 0, len(args))
+	var amounts OrderAmounts
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var target *string
+		var flag string
+		switch {
+		case arg == AllowNegativeSpreadFlag:
+			amounts.AllowNegativeSpread = true
+			continue
+		case arg == AmountInFlag || strings.HasPrefix(arg, AmountInFlag+"="):
+			target, flag = &amounts.In, AmountInFlag
+		case arg == AmountOutFlag || strings.HasPrefix(arg, AmountOutFlag+"="):
+			target, flag = &amounts.Out, AmountOutFlag
+		default:
+			out = append(out, arg)
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+			*target = value
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			*target = args[i+1]
+			i++
+		}
+		if *target == "" {
+			return nil, OrderAmounts{}, fmt.Errorf("%s requires a token amount", flag)
+		}
+		amount, err := types.ParseTokenAmount(*target, tokenDecimals)
+		if err != nil {
+			return nil, OrderAmounts{}, fmt.Errorf("invalid %s: %w", flag, err)
+		}
+		if amount.Sign() == 0 {
+			return nil, OrderAmounts{}, fmt.Errorf("%s must be greater than zero", flag)
+		}
+	}
+	return out, amounts, nil
+}
+
+// isSet reports whether either amount was given
+func (a OrderAmounts) isSet() bool {
+	return a.In != "" || a.Out != ""
+}
+
+// orderAmount returns value of flag in base units of a token with decimals, or generated (in 18-decimal units)
+// scaled to decimals when the flag was not given
+func orderAmount(flag, value string, generated *big.Int, decimals int) (*big.Int, error) {
+	if value == "" {
+		return scaleTokenAmount(generated, decimals), nil
+	}
+	amount, err := types.ParseTokenAmount(value, uint8(decimals))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", flag, err)
+	}
+	return amount, nil
+}
+
+// checkSpread refuses an order whose output is worth more tokens than its input, or only warns about it when
+// allowed. The amounts are compared in whole tokens, as the input and output tokens may have different decimals
+func checkSpread(in *big.Int, inDecimals int, out *big.Int, outDecimals int, allow bool) error {
+	scaledOut := new(big.Int).Mul(out, pow10(inDecimals))
+	scaledIn := new(big.Int).Mul(in, pow10(outDecimals))
+	if scaledOut.Cmp(scaledIn) <= 0 {
+		return nil
+	}
+	spread := fmt.Sprintf("the order pays out %s tokens for %s tokens in, which no rational solver fills",
+		formatExactAmount(out, outDecimals), formatExactAmount(in, inDecimals))
+	if !allow {
+		return fmt.Errorf("%s; pass %s to open it anyway", spread, AllowNegativeSpreadFlag)
+	}
+	warnf("⚠️  Negative spread: %s\n", spread)
+	return nil
+}
+
+// formatExactAmount renders base units of a token with decimals as a decimal amount, without rounding
+func formatExactAmount(amount *big.Int, decimals int) string {
+	whole, fraction := new(big.Int).QuoRem(amount, pow10(decimals), new(big.Int))
+	if fraction.Sign() == 0 {
+		return whole.String()
+	}
+	digits := fmt.Sprintf("%0*s", decimals, fraction.String())
+	return whole.String() + "." + strings.TrimRight(digits, "0")
+}
+
+// describeAmount renders amount in tokens and in the base units the order carries, e.g. "1.5 USDC (1500000)", so
+// amounts of tokens with different decimals can be told apart
+func (t orderToken) describeAmount(amount *big.Int) string {
+	return fmt.Sprintf("%s %s (%s)", formatExactAmount(amount, t.Decimals), t.Symbol, amount.String())
+}
+
+// pow10 returns 10^n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
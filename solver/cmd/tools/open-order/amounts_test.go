@@ -0,0 +1,98 @@
+package openorder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractAmountFlags(t *testing.T) {
+	args, amounts, err := ExtractAmountFlags([]string{"evm", "--amount-in", "1500.5", "base", "--amount-out=1499", "--allow-negative-spread", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evm", "base", "--json"}, args)
+	assert.Equal(t, OrderAmounts{In: "1500.5", Out: "1499", AllowNegativeSpread: true}, amounts)
+
+	args, amounts, err = ExtractAmountFlags([]string{"evm", "--amount-in", "0.000000000000000001"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evm"}, args)
+	assert.Equal(t, OrderAmounts{In: "0.000000000000000001"}, amounts)
+
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--amount-in"}, "--amount-in requires a token amount"},
+		{[]string{"--amount-out", "--json"}, "--amount-out requires a token amount"},
+		{[]string{"--amount-in", "0"}, "--amount-in must be greater than zero"},
+		{[]string{"--amount-out=0.000"}, "--amount-out must be greater than zero"},
+		{[]string{"--amount-in", "-5"}, "invalid --amount-in"},
+		{[]string{"--amount-in", "1e3"}, "invalid --amount-in"},
+		{[]string{"--amount-out", "0.0000000000000000001"}, "more than the token's 18 decimals"},
+	} {
+		_, _, err := ExtractAmountFlags(tc.args)
+		assert.ErrorContains(t, err, tc.err, tc.args)
+	}
+}
+
+func TestApplyAmountFlags(t *testing.T) {
+	t.Cleanup(func() { SetOrderAmounts(OrderAmounts{}) })
+	tokens := &orderTokens{
+		Input:  orderToken{Network: "Ethereum", Symbol: "DogCoin", Decimals: 18},
+		Output: orderToken{Network: "Base", Symbol: "USDC", Decimals: 6},
+	}
+	generatedIn, generatedOut := CreateTokenAmount(1001, 18), CreateTokenAmount(1000, 18)
+
+	// Without flags the generated amounts are scaled
+	in, out, err := tokens.apply(generatedIn, generatedOut, nil)
+	require.NoError(t, err)
+	assert.Equal(t, generatedIn, in)
+	assert.Equal(t, "1000000000", out.String())
+
+	SetOrderAmounts(OrderAmounts{In: "1500.5", Out: "1499.25"})
+	result := &OrderResult{}
+	in, out, err = tokens.apply(generatedIn, generatedOut, result)
+	require.NoError(t, err)
+	assert.Equal(t, "1500500000000000000000", in.String())
+	assert.Equal(t, "1499250000", out.String())
+	assert.Equal(t, "1499250000", result.OutputAmount)
+
+	// One flag keeps the other amount generated
+	SetOrderAmounts(OrderAmounts{In: "2000"})
+	_, out, err = tokens.apply(generatedIn, generatedOut, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000", out.String())
+
+	// USDC has 6 decimals
+	SetOrderAmounts(OrderAmounts{In: "1500", Out: "1499.0000001"})
+	_, _, err = tokens.apply(generatedIn, generatedOut, nil)
+	assert.ErrorContains(t, err, "invalid --amount-out: token amount \"1499.0000001\" has more than the token's 6 decimals")
+
+	SetOrderAmounts(OrderAmounts{In: "1499", Out: "1500.5"})
+	_, _, err = tokens.apply(generatedIn, generatedOut, nil)
+	assert.ErrorContains(t, err, "the order pays out 1500.5 tokens for 1499 tokens in")
+	assert.ErrorContains(t, err, "pass --allow-negative-spread")
+
+	SetOrderAmounts(OrderAmounts{In: "1499", Out: "1500.5", AllowNegativeSpread: true})
+	_, out, err = tokens.apply(generatedIn, generatedOut, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1500500000", out.String())
+}
+
+func TestCheckSpread(t *testing.T) {
+	// 1 token of 18 decimals against 1 token of 6 decimals is an even spread
+	require.NoError(t, checkSpread(CreateTokenAmount(1, 18), 18, big.NewInt(1_000_000), 6, false))
+	assert.Error(t, checkSpread(CreateTokenAmount(1, 18), 18, big.NewInt(1_000_001), 6, false))
+	require.NoError(t, checkSpread(big.NewInt(1_000_001), 6, CreateTokenAmount(1, 18), 18, false))
+	// A single base unit more is still refused
+	assert.ErrorContains(t, checkSpread(big.NewInt(10), 18, big.NewInt(11), 18, false),
+		"pays out 0.000000000000000011 tokens for 0.00000000000000001 tokens in")
+}
+
+func TestFormatExactAmount(t *testing.T) {
+	assert.Equal(t, "1500.5", formatExactAmount(big.NewInt(1_500_500_000), 6))
+	assert.Equal(t, "1499", formatExactAmount(CreateTokenAmount(1499, 18), 18))
+	assert.Equal(t, "0.000000000000000001", formatExactAmount(big.NewInt(1), 18))
+	assert.Equal(t, "42", formatExactAmount(big.NewInt(42), 0))
+}
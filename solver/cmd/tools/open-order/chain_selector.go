@@ -0,0 +1,239 @@
+package openorder
+
+// Chain selectors
+// Origin and destination arguments name one network or a set to pick from: "any" (every configured network),
+// "evm", aliases (strk, ztrk), comma-separated candidates ("base,arbitrum") and exclusions ("!ethereum",
+// "any,!evm"). Every random draw of a run (networks and amounts) comes from one source seeded by --seed, or by a
+// random seed that is printed so the run can be replayed. DESTINATION_WEIGHTS skews the destination picks
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// SeedFlag makes random network picks reproducible
+const SeedFlag = "--seed"
+
+// anySelector matches every configured network
+const anySelector = "any"
+
+var (
+	pickerMu sync.Mutex
+	// picker is the seeded source set by SetSeed; nil picks with crypto/rand
+	picker *rand.Rand
+	// destinationWeights weigh the destination picks by network; nil weighs every network 1
+	destinationWeights map[string]int
+)
+
+// SetSeed makes every following network pick deterministic for seed
+func SetSeed(seed uint64) {
+	pickerMu.Lock()
+	defer pickerMu.Unlock()
+	picker = rand.New(rand.NewPCG(seed, seed))
+}
+
+// clearSeed goes back to crypto/rand picks
+func clearSeed() {
+	pickerMu.Lock()
+	defer pickerMu.Unlock()
+	picker = nil
+}
+
+// RandomSeed returns a fresh seed for runs without --seed
+func RandomSeed() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano())
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// InitRandomness seeds the run's random draws with seed, or with a fresh seed printed to stderr (keeping --json
+// output clean) when seeded is false, and loads the destination weights from the config
+func InitRandomness(seed uint64, seeded bool) error {
+	if !seeded {
+		seed = RandomSeed()
+		fmt.Fprintf(os.Stderr, "🎲 Random seed %d (replay with %s %d)\n", seed, SeedFlag, seed)
+	}
+	SetSeed(seed)
+	weights, err := config.DestinationWeights()
+	if err != nil {
+		return err
+	}
+	SetDestinationWeights(weights)
+	return nil
+}
+
+// SetDestinationWeights weighs the following destination picks by network (see config.DestinationWeights)
+func SetDestinationWeights(weights map[string]int) {
+	pickerMu.Lock()
+	defer pickerMu.Unlock()
+	destinationWeights = weights
+}
+
+// randomInt returns a random int in [0, n) from the seeded source, or crypto/rand without a seed
+func randomInt(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	pickerMu.Lock()
+	defer pickerMu.Unlock()
+	if picker != nil {
+		return picker.IntN(n)
+	}
+	return secureRandomInt(n)
+}
+
+// pickNetwork picks one of candidates, which must be sorted so a seed always yields the same network
+func pickNetwork(candidates []string) string {
+	return candidates[randomInt(len(candidates))]
+}
+
+// destinationWeight is networkName's weight as a destination
+func destinationWeight(networkName string) int {
+	pickerMu.Lock()
+	defer pickerMu.Unlock()
+	if weight, ok := destinationWeights[networkName]; ok {
+		return weight
+	}
+	return 1
+}
+
+// pickWeighted picks one of candidates with a probability proportional to its destination weight; candidates
+// must be sorted and weigh more than 0
+func pickWeighted(candidates []string) string {
+	total := 0
+	for _, networkName := range candidates {
+		total += destinationWeight(networkName)
+	}
+	n := randomInt(total)
+	for _, networkName := range candidates {
+		if n -= destinationWeight(networkName); n < 0 {
+			return networkName
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// ExtractSeedFlag removes "--seed <n>" (or "--seed=<n>") from args and returns the seed; ok is false when absent
+func ExtractSeedFlag(args []string) (out []string, seed uint64, ok bool, err error) {
+	out = make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var value string
+		switch {
+		case arg == SeedFlag:
+			if i+1 >= len(args) || strings.HasPrefix(args[i+1], "--") {
+				return nil, 0, false, fmt.Errorf("%s requires a number", SeedFlag)
+			}
+			value = args[i+1]
+			i++
+		case strings.HasPrefix(arg, SeedFlag+"="):
+			value = strings.TrimPrefix(arg, SeedFlag+"=")
+		default:
+			out = append(out, arg)
+			continue
+		}
+
+		seed, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("%s requires a number, got %q", SeedFlag, value)
+		}
+		ok = true
+	}
+	return out, seed, ok, nil
+}
+
+// unknownNetworkError reports a selector term that names no configured network
+type unknownNetworkError struct {
+	role string // origin or destination
+	name string
+}
+
+func (e *unknownNetworkError) Error() string {
+	return fmt.Sprintf("%s network not found: %s (known: %s)", e.role, e.name, strings.Join(sortedNetworkNames(), ", "))
+}
+
+// resolveChainTerm maps one selector term to configured network names (sorted)
+func resolveChainTerm(role, term string, all []string) ([]string, error) {
+	switch strings.ToLower(term) {
+	case anySelector, "*":
+		return all, nil
+	case "evm":
+		var evm []string
+		for _, networkName := range all {
+			if GetNetworkType(networkName) == NetworkTypeEVM {
+				evm = append(evm, networkName)
+			}
+		}
+		return evm, nil
+	case "strk":
+		term = starknetNetworkName
+	case "ztrk":
+		term = ztarknetNetworkName
+	}
+
+	for _, networkName := range all {
+		if strings.EqualFold(networkName, term) {
+			return []string{networkName}, nil
+		}
+	}
+	return nil, &unknownNetworkError{role: role, name: term}
+}
+
+// resolveChainSelector expands a selector into the sorted configured networks it allows. A selector made only
+// of exclusions starts from every network
+func resolveChainSelector(role, selector string) ([]string, error) {
+	all := sortedNetworkNames()
+	included := map[string]bool{}
+	excluded := map[string]bool{}
+	hasInclusion := false
+
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		target := included
+		if name, ok := strings.CutPrefix(term, "!"); ok {
+			term, target = strings.TrimSpace(name), excluded
+		} else {
+			hasInclusion = true
+		}
+		if term == "" {
+			return nil, fmt.Errorf("empty %s in %q", role, selector)
+		}
+
+		names, err := resolveChainTerm(role, term, all)
+		if err != nil {
+			return nil, err
+		}
+		for _, networkName := range names {
+			target[networkName] = true
+		}
+	}
+
+	var candidates []string
+	for _, networkName := range all {
+		if (hasInclusion && !included[networkName]) || excluded[networkName] {
+			continue
+		}
+		candidates = append(candidates, networkName)
+	}
+	return candidates, nil
+}
+
+// isValidDestination applies the network type rules of an order: Ztarknet orders cannot stay on Ztarknet, every
+// other pair of distinct networks is allowed
+func isValidDestination(originChain, destChain string) bool {
+	if destChain == originChain {
+		return false
+	}
+	return GetNetworkType(originChain) != NetworkTypeZtarknet || GetNetworkType(destChain) != NetworkTypeZtarknet
+}
@@ -0,0 +1,28 @@
+package openorder
+
+import (
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/rpcpool"
+)
+
+// The RPC clients of an open-order run come from one pool, so the orders of a batch and the token lookups
+// reuse their connections. The pool is created on first use, after .env is loaded
+var (
+	clientPoolOnce sync.Once
+	clientPool     *rpcpool.Pool
+)
+
+func clients() *rpcpool.Pool {
+	clientPoolOnce.Do(func() {
+		opts := rpcpool.OptionsFromEnv()
+		opts.Logger = logger
+		clientPool = rpcpool.New(opts)
+	})
+	return clientPool
+}
+
+// CloseClients closes the RPC clients opened during the run; callers defer it around the open-order commands
+func CloseClients() {
+	clients().Close()
+}
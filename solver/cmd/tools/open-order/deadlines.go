@@ -0,0 +1,190 @@
+package openorder
+
+// Order deadlines
+// Deadlines are relative to the origin chain's latest block time, not this machine's clock: anvil forks often run
+// hours behind (forked at an old block) or ahead (after evm_increaseTime), and an order must not be born expired
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+)
+
+const (
+	// OpenDeadlineFlag sets how long after the origin's block time an order may still be opened
+	OpenDeadlineFlag = "--open-deadline"
+	// FillDeadlineFlag sets how long after the origin's block time an order may still be filled
+	FillDeadlineFlag = "--fill-deadline"
+
+	defaultOpenDeadline = 1 * time.Hour
+	defaultFillDeadline = orderDeadlineHours * time.Hour
+	// clockSkewWarning is how far chain time may drift from this machine's clock before it is reported
+	clockSkewWarning = 5 * time.Minute
+)
+
+// DeadlineWindows are how far past the origin's block time an order's open and fill deadlines are
+type DeadlineWindows struct {
+	Open time.Duration
+	Fill time.Duration
+}
+
+// deadlineWindows are the windows used by every order this run opens
+var deadlineWindows = DeadlineWindows{Open: defaultOpenDeadline, Fill: defaultFillDeadline}
+
+// SetDeadlineWindows sets the deadline windows for the orders of this run; zero fields keep the defaults
+func SetDeadlineWindows(windows DeadlineWindows) {
+	deadlineWindows = windows.withDefaults()
+}
+
+func (w DeadlineWindows) withDefaults() DeadlineWindows {
+	if w.Open == 0 {
+		w.Open = defaultOpenDeadline
+	}
+	if w.Fill == 0 {
+		w.Fill = defaultFillDeadline
+	}
+	return w
+}
+
+// validate checks the fill deadline comes after the open deadline, which comes after the block time
+func (w DeadlineWindows) validate() error {
+	if w.Open <= 0 {
+		return fmt.Errorf("%s must be positive, got %s", OpenDeadlineFlag, w.Open)
+	}
+	if w.Fill <= w.Open {
+		return fmt.Errorf("%s (%s) must be longer than %s (%s)", FillDeadlineFlag, w.Fill, OpenDeadlineFlag, w.Open)
+	}
+	return nil
+}
+
+// ExtractDeadlineFlags removes --open-deadline/--fill-deadline (or the =value forms) from args and returns the
+// windows, defaults applied
+func ExtractDeadlineFlags(args []string) ([]string, DeadlineWindows, error) {
+	out := make([]string, 0, len(args))
+	var windows DeadlineWindows
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var target *time.Duration
+		var flag string
+		switch {
+		case arg == OpenDeadlineFlag || strings.HasPrefix(arg, OpenDeadlineFlag+"="):
+			target, flag = &windows.Open, OpenDeadlineFlag
+		case arg == FillDeadlineFlag || strings.HasPrefix(arg, FillDeadlineFlag+"="):
+			target, flag = &windows.Fill, FillDeadlineFlag
+		default:
+			out = append(out, arg)
+			continue
+		}
+
+		value, ok := strings.CutPrefix(arg, flag+"=")
+		if !ok {
+			if i+1 >= len(args) || strings.HasPrefix(args[i+1], "--") {
+				return nil, DeadlineWindows{}, fmt.Errorf("%s requires a duration", flag)
+			}
+			value = args[i+1]
+			i++
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, DeadlineWindows{}, fmt.Errorf("invalid %s %q: %w", flag, value, err)
+		}
+		*target = d
+	}
+
+	windows = windows.withDefaults()
+	if err := windows.validate(); err != nil {
+		return nil, DeadlineWindows{}, err
+	}
+	return out, windows, nil
+}
+
+// at computes the deadlines of the windows from the chain time now and validates them
+func (w DeadlineWindows) at(now uint64) (orderdeadline.Deadlines, error) {
+	deadlines := orderdeadline.Deadlines{
+		Open: orderdeadline.Deadline(now + uint64(w.Open/time.Second)),
+		Fill: orderdeadline.Deadline(now + uint64(w.Fill/time.Second)),
+	}
+	return deadlines, deadlines.Validate(now)
+}
+
+// chainDeadlines computes the deadlines of an order opened on network at block time chainNow, reporting when
+// the chain's clock is off from this machine's
+func chainDeadlines(network string, chainNow uint64, windows DeadlineWindows) (orderdeadline.Deadlines, error) {
+	warnClockSkew(network, chainNow, time.Now())
+	deadlines, err := windows.at(chainNow)
+	if err != nil {
+		return orderdeadline.Deadlines{}, fmt.Errorf("invalid deadlines on %s: %w", network, err)
+	}
+	return deadlines, nil
+}
+
+// clockSkew returns how far chain time is ahead of wallNow (negative when behind)
+func clockSkew(chainNow uint64, wallNow time.Time) time.Duration {
+	return time.Duration(int64(chainNow)-wallNow.Unix()) * time.Second
+}
+
+func warnClockSkew(network string, chainNow uint64, wallNow time.Time) {
+	skew := clockSkew(chainNow, wallNow)
+	switch {
+	case skew > clockSkewWarning:
+		warnf("   ⚠️  %s block time is %s ahead of this machine's clock; deadlines follow the chain\n", network, skew)
+	case skew < -clockSkewWarning:
+		warnf("   ⚠️  %s block time is %s behind this machine's clock; deadlines follow the chain\n", network, -skew)
+	}
+}
+
+// headerReader reads block headers; *ethclient.Client implements it
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+}
+
+// stampEVMDeadlines sets order's deadlines from the latest block time of its EVM origin
+func stampEVMDeadlines(ctx context.Context, client headerReader, order *OrderConfig, windows DeadlineWindows) error {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read the latest %s block: %w", order.OriginChain, err)
+	}
+	deadlines, err := chainDeadlines(order.OriginChain, header.Time, windows)
+	if err != nil {
+		return err
+	}
+	order.Deadlines = deadlines
+	return nil
+}
+
+// blockReader reads Starknet blocks; *rpc.Provider implements it
+type blockReader interface {
+	BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (interface{}, error)
+}
+
+// stampCairoDeadlines sets order's deadlines from the latest (possibly pre-confirmed) block time of its
+// Starknet or Ztarknet origin
+func stampCairoDeadlines(ctx context.Context, client blockReader, order *StarknetOrderConfig, windows DeadlineWindows) error {
+	block, err := client.BlockWithTxHashes(ctx, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return fmt.Errorf("failed to read the latest %s block: %w", order.OriginChain, err)
+	}
+	var now uint64
+	switch b := block.(type) {
+	case *rpc.BlockTxHashes:
+		now = b.Timestamp
+	case *rpc.PreConfirmedBlockTxHashes:
+		now = b.Timestamp
+	default:
+		return fmt.Errorf("unexpected block type %T from %s", block, order.OriginChain)
+	}
+
+	deadlines, err := chainDeadlines(order.OriginChain, now, windows)
+	if err != nil {
+		return err
+	}
+	order.Deadlines = deadlines
+	return nil
+}
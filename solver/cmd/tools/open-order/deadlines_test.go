@@ -0,0 +1,135 @@
+package openorder
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+)
+
+func TestExtractDeadlineFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantArgs    []string
+		wantWindows DeadlineWindows
+		wantErr     string
+	}{
+		{name: "absent", args: []string{"evm", "base"}, wantArgs: []string{"evm", "base"}, wantWindows: DeadlineWindows{time.Hour, 24 * time.Hour}},
+		{name: "both", args: []string{"evm", "--open-deadline", "10m", "--fill-deadline=2h"}, wantArgs: []string{"evm"}, wantWindows: DeadlineWindows{10 * time.Minute, 2 * time.Hour}},
+		{name: "fill_only", args: []string{"--fill-deadline", "90m", "evm"}, wantArgs: []string{"evm"}, wantWindows: DeadlineWindows{time.Hour, 90 * time.Minute}},
+		{name: "fill_before_open", args: []string{"--fill-deadline", "30m"}, wantErr: "--fill-deadline (30m0s) must be longer than --open-deadline (1h0m0s)"},
+		{name: "negative_open", args: []string{"--open-deadline=-1h"}, wantErr: "--open-deadline must be positive"},
+		{name: "missing_value", args: []string{"evm", "--open-deadline"}, wantErr: "--open-deadline requires a duration"},
+		{name: "flag_as_value", args: []string{"--fill-deadline", "--json"}, wantErr: "--fill-deadline requires a duration"},
+		{name: "not_a_duration", args: []string{"--fill-deadline", "tomorrow"}, wantErr: `invalid --fill-deadline "tomorrow"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, windows, err := ExtractDeadlineFlags(tt.args)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, args)
+			assert.Equal(t, tt.wantWindows, windows)
+		})
+	}
+}
+
+func TestDeadlineWindowsAt(t *testing.T) {
+	const now = 1_700_000_000
+
+	deadlines, err := DeadlineWindows{Open: time.Hour, Fill: 24 * time.Hour}.at(now)
+	require.NoError(t, err)
+	assert.Equal(t, orderdeadline.Deadlines{Open: now + 3600, Fill: now + 86400}, deadlines)
+
+	_, err = DeadlineWindows{Open: 0, Fill: time.Hour}.at(now)
+	assert.EqualError(t, err, "open deadline 1700000000 is not after the current time 1700000000")
+
+	_, err = DeadlineWindows{Open: time.Hour, Fill: time.Hour}.at(now)
+	assert.EqualError(t, err, "fill deadline 1700003600 is not after the open deadline 1700003600")
+
+	_, err = DeadlineWindows{Open: time.Hour, Fill: 24 * time.Hour}.at(math.MaxUint32 - 7200)
+	assert.ErrorContains(t, err, "does not fit in uint32")
+}
+
+func TestClockSkew(t *testing.T) {
+	wall := time.Unix(1_700_000_000, 0)
+	assert.Equal(t, 3*time.Hour, clockSkew(1_700_000_000+3*3600, wall))
+	assert.Equal(t, -90*time.Minute, clockSkew(1_700_000_000-5400, wall))
+	assert.Zero(t, clockSkew(1_700_000_000, wall))
+}
+
+// fakeHeaders returns a latest header at a fixed time
+type fakeHeaders struct {
+	time uint64
+	err  error
+}
+
+func (f fakeHeaders) HeaderByNumber(context.Context, *big.Int) (*gethtypes.Header, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &gethtypes.Header{Time: f.time}, nil
+}
+
+func TestStampEVMDeadlines(t *testing.T) {
+	// A fork frozen at an old block: deadlines follow its clock, not this machine's
+	forkTime := uint64(time.Now().Add(-48 * time.Hour).Unix())
+	order := &OrderConfig{OriginChain: "Ethereum"}
+	require.NoError(t, stampEVMDeadlines(context.Background(), fakeHeaders{time: forkTime}, order, DeadlineWindows{Open: time.Hour, Fill: 24 * time.Hour}))
+	assert.Equal(t, orderdeadline.Deadlines{Open: orderdeadline.Deadline(forkTime + 3600), Fill: orderdeadline.Deadline(forkTime + 86400)}, order.Deadlines)
+
+	err := stampEVMDeadlines(context.Background(), fakeHeaders{err: errors.New("connection refused")}, order, deadlineWindows)
+	assert.EqualError(t, err, "failed to read the latest Ethereum block: connection refused")
+}
+
+// fakeBlocks returns a latest block of either kind
+type fakeBlocks struct {
+	block interface{}
+}
+
+func (f fakeBlocks) BlockWithTxHashes(context.Context, rpc.BlockID) (interface{}, error) {
+	return f.block, nil
+}
+
+func TestStampCairoDeadlines(t *testing.T) {
+	const chainTime = 1_800_000_000
+	windows := DeadlineWindows{Open: time.Hour, Fill: 2 * time.Hour}
+
+	order := &StarknetOrderConfig{OriginChain: "Starknet"}
+	confirmed := &rpc.BlockTxHashes{BlockHeader: rpc.BlockHeader{Timestamp: chainTime}}
+	require.NoError(t, stampCairoDeadlines(context.Background(), fakeBlocks{confirmed}, order, windows))
+	assert.Equal(t, orderdeadline.Deadlines{Open: chainTime + 3600, Fill: chainTime + 7200}, order.Deadlines)
+
+	order = &StarknetOrderConfig{OriginChain: "Ztarknet"}
+	preConfirmed := &rpc.PreConfirmedBlockTxHashes{}
+	preConfirmed.Timestamp = chainTime + 10
+	require.NoError(t, stampCairoDeadlines(context.Background(), fakeBlocks{preConfirmed}, order, windows))
+	assert.Equal(t, orderdeadline.Deadline(chainTime+10+3600), order.Deadlines.Open)
+
+	err := stampCairoDeadlines(context.Background(), fakeBlocks{"block"}, order, windows)
+	assert.EqualError(t, err, "unexpected block type string from Ztarknet")
+}
+
+func TestSetDeadlineWindows(t *testing.T) {
+	t.Cleanup(func() { SetDeadlineWindows(DeadlineWindows{}) })
+
+	SetDeadlineWindows(DeadlineWindows{Fill: 3 * time.Hour})
+	assert.Equal(t, DeadlineWindows{Open: defaultOpenDeadline, Fill: 3 * time.Hour}, deadlineWindows)
+
+	SetDeadlineWindows(DeadlineWindows{})
+	assert.Equal(t, DeadlineWindows{Open: defaultOpenDeadline, Fill: defaultFillDeadline}, deadlineWindows)
+}
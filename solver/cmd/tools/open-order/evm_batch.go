@@ -0,0 +1,553 @@
+package openorder
+
+// EVM batch order creation - opens many random orders concurrently
+// One client and one locally tracked tx nonce per origin network, distinct contract senderNonces per order
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// defaultBatchConcurrency is the number of orders in flight when --concurrency is not given
+const defaultBatchConcurrency = 4
+
+// batchResult is the outcome of a single order in a batch
+type batchResult struct {
+	Order   OrderConfig
+	TxHash  common.Hash
+	OrderID common.Hash
+	GasUsed uint64
+	Err     error
+
+	// The order struct as sent, recorded in the order store once the order is opened
+	OrderData    []byte
+	FillDeadline uint32
+	// Gasless orders were opened through openFor, which also records their open deadline
+	Gasless      bool
+	OpenDeadline uint32
+	// TxOrders are the orders opened by the same transaction, when several were aggregated into it
+	TxOrders []common.Hash
+}
+
+// originSession holds the per-origin state shared by all orders of a batch
+type originSession struct {
+	network     *NetworkConfig
+	client      *ethclient.Client
+	auth        *bind.TransactOpts
+	contract    *contracts.Hyperlane7683
+	localDomain uint32
+
+	mu           sync.Mutex
+	txNonce      uint64
+	senderNonces []*big.Int
+}
+
+// batchOptions are the arguments of `open-order batch`
+type batchOptions struct {
+	count       int
+	concurrency int
+	// multicall is the number of orders aggregated per transaction through Multicall3, 0 to send one each
+	multicall int
+}
+
+// parseBatchArgs parses `<count> [--concurrency N] [--multicall N]`
+func parseBatchArgs(args []string) (batchOptions, error) {
+	opts := batchOptions{concurrency: defaultBatchConcurrency}
+	countSet := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var err error
+		switch {
+		case arg == "--concurrency" || arg == MulticallFlag:
+			if i+1 >= len(args) {
+				return batchOptions{}, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			target := &opts.concurrency
+			if arg == MulticallFlag {
+				target = &opts.multicall
+			}
+			*target, err = strconv.Atoi(args[i])
+		case strings.HasPrefix(arg, "--concurrency="):
+			opts.concurrency, err = strconv.Atoi(strings.TrimPrefix(arg, "--concurrency="))
+		case strings.HasPrefix(arg, MulticallFlag+"="):
+			opts.multicall, err = strconv.Atoi(strings.TrimPrefix(arg, MulticallFlag+"="))
+		case !countSet:
+			opts.count, err = strconv.Atoi(arg)
+			countSet = true
+		default:
+			return batchOptions{}, fmt.Errorf("unexpected argument: %s", arg)
+		}
+		if err != nil {
+			return batchOptions{}, fmt.Errorf("invalid number %q: %w", arg, err)
+		}
+	}
+
+	if !countSet {
+		return batchOptions{}, fmt.Errorf("order count is required")
+	}
+	if opts.count <= 0 {
+		return batchOptions{}, fmt.Errorf("order count must be positive, got %d", opts.count)
+	}
+	if opts.concurrency <= 0 {
+		return batchOptions{}, fmt.Errorf("concurrency must be positive, got %d", opts.concurrency)
+	}
+	if opts.multicall < 0 {
+		return batchOptions{}, fmt.Errorf("%s must be positive, got %d", MulticallFlag, opts.multicall)
+	}
+	return opts, nil
+}
+
+// RunEVMBatch opens `count` random orders from EVM origins, `concurrency` at a time. With --multicall N, each
+// origin's orders are instead opened N per transaction
+func RunEVMBatch(ctx context.Context, args []string) {
+	opts, err := parseBatchArgs(args)
+	if err != nil {
+		fmt.Println("Usage: open-order batch <count> [--concurrency N] [--multicall N]")
+		logger.Fatalf("Invalid batch arguments: %v", err)
+	}
+
+	// Load configuration (this loads .env and initializes networks)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+	logutil.ConfigureFromConfig(logger, cfg)
+	if err := initializeUsers(); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	networks := loadNetworks(cfg)
+
+	count, concurrency := opts.count, opts.concurrency
+	orders, err := randomBatchOrders(count, networks)
+	if err != nil {
+		logger.Fatalf("Failed to generate batch orders: %v", err)
+	}
+
+	logf("Opening %d orders (concurrency %d)\n", count, concurrency)
+
+	results := make([]batchResult, 0, count)
+	var resultsMu sync.Mutex
+	record := func(r batchResult) {
+		resultsMu.Lock()
+		defer resultsMu.Unlock()
+		results = append(results, r)
+	}
+
+	// Group orders by origin so each origin gets one client and one nonce sequence.
+	// Destinations are resolved first, so an order whose token or settler is missing fails on its own
+	// before the origin's batch approval is sent
+	byOrigin := make(map[string][]OrderConfig)
+	for _, order := range orders {
+		if err := resolveBatchDestination(ctx, &order, networks); err != nil {
+			record(batchResult{Order: order, Err: err})
+			continue
+		}
+		byOrigin[order.OriginChain] = append(byOrigin[order.OriginChain], order)
+	}
+
+	type job struct {
+		session *originSession
+		order   OrderConfig
+	}
+	jobs := make(chan job)
+	store := orderstore.New(orderstore.DefaultDir())
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				r := j.session.openOrder(ctx, j.order, deadlineWindows, networks)
+				// Each order is recorded as soon as it is open; the store writes one file per order
+				if r.Err == nil && r.OrderID != (common.Hash{}) {
+					saveOrder(store, r.orderResult())
+				}
+				record(r)
+			}
+		}()
+	}
+
+	for origin, originOrders := range byOrigin {
+		if opts.multicall > 0 {
+			// Orders Multicall3 cannot carry, or every order on a chain without it, are sent one by one below
+			originOrders = openMulticallOrders(ctx, origin, originOrders, opts.multicall, networks, store, record)
+			if len(originOrders) == 0 {
+				continue
+			}
+		}
+
+		session, err := newOriginSession(ctx, origin, originOrders, networks)
+		if err != nil {
+			// A broken origin only fails its own orders
+			errorf("   ❌ %s: %v\n", origin, err)
+			for _, order := range originOrders {
+				record(batchResult{Order: order, Err: err})
+			}
+			continue
+		}
+
+		for _, order := range originOrders {
+			jobs <- job{session: session, order: order}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	printBatchSummary(results)
+}
+
+// randomBatchOrders generates random orders from EVM origins to any valid destination
+func randomBatchOrders(count int, networks []NetworkConfig) ([]OrderConfig, error) {
+	var origins []string
+	for _, n := range networks {
+		if GetNetworkType(n.name) == NetworkTypeEVM {
+			origins = append(origins, n.name)
+		}
+	}
+	if len(origins) == 0 {
+		return nil, fmt.Errorf("no EVM networks configured")
+	}
+
+	orders := make([]OrderConfig, 0, count)
+	for i := 0; i < count; i++ {
+		origin := origins[randomInt(len(origins))]
+		destination, err := GetRandomDestination(origin)
+		if err != nil {
+			return nil, err
+		}
+
+		inputAmount, outputAmount := randomOrderAmounts()
+		orders = append(orders, OrderConfig{
+			OriginChain:      origin,
+			DestinationChain: destination,
+			InputToken:       tokenSelection.Input,
+			OutputToken:      tokenSelection.Output,
+			InputAmount:      inputAmount,
+			OutputAmount:     outputAmount,
+			User:             AliceUserName,
+		})
+	}
+	return orders, nil
+}
+
+// resolveBatchDestination resolves an order's tokens and checks its destination settler
+func resolveBatchDestination(ctx context.Context, order *OrderConfig, networks []NetworkConfig) error {
+	origin := findNetwork(networks, order.OriginChain)
+	destination := findNetwork(networks, order.DestinationChain)
+	if origin == nil || destination == nil {
+		return fmt.Errorf("network not found: %s → %s", order.OriginChain, order.DestinationChain)
+	}
+	if err := checkOriginSettler(origin); err != nil {
+		return err
+	}
+	if _, err := withDestinationSettler(destination, origin); err != nil {
+		return err
+	}
+	return order.resolveTokens(ctx, nil)
+}
+
+// newOriginSession connects to an origin, approves the whole batch amount once and reserves nonces
+func newOriginSession(ctx context.Context, origin string, orders []OrderConfig, networks []NetworkConfig) (*originSession, error) {
+	network := findNetwork(networks, origin)
+	if network == nil {
+		return nil, fmt.Errorf("origin network not found: %s", origin)
+	}
+
+	_, auth, err := aliceTransactor(network)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := clients().EVMFailover(ctx, origin, network.urls)
+	if err != nil {
+		return nil, err
+	}
+	return prepareOriginSession(ctx, client, auth, network, orders)
+}
+
+// aliceTransactor loads Alice's key and returns it with options that sign as her on network
+func aliceTransactor(network *NetworkConfig) (*ecdsa.PrivateKey, *bind.TransactOpts, error) {
+	privateKey, err := credentials.LoadEVMKey(envutil.ConditionalKey("ALICE"))
+	if errors.Is(err, credentials.ErrMissingKey) {
+		return nil, nil, fmt.Errorf("private key not found for user: %s", AliceUserName)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load private key: %w", err)
+	}
+	auth, err := ethutil.NewTransactor(new(big.Int).SetUint64(network.chainID), privateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create auth: %w", err)
+	}
+	return privateKey, auth, nil
+}
+
+func prepareOriginSession(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, network *NetworkConfig, orders []OrderConfig) (*originSession, error) {
+	hyperlane := common.HexToAddress(network.hyperlaneAddress)
+	localDomain, err := getLocalDomain(ctx, client, hyperlane)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read localDomain: %w", err)
+	}
+
+	// Check (and with --auto-approve, approve) the total input of each input token up front so orders never race
+	// on allowance. Input tokens are resolved on this origin
+	for _, input := range batchInputTotals(orders) {
+		if err := approveBatchInput(ctx, client, auth, network, hyperlane, input); err != nil {
+			return nil, err
+		}
+	}
+
+	// Query the account nonce once; it is tracked locally from here on
+	txNonce, err := client.PendingNonceAt(ctx, auth.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account nonce: %w", err)
+	}
+
+	senderNonces, err := pickValidSenderNonces(ctx, client, hyperlane, auth.From, len(orders))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve sender nonces: %w", err)
+	}
+
+	contract, err := contracts.NewHyperlane7683(hyperlane, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind Hyperlane7683: %w", err)
+	}
+
+	return &originSession{
+		network:      network,
+		client:       client,
+		auth:         auth,
+		contract:     contract,
+		localDomain:  localDomain,
+		txNonce:      txNonce,
+		senderNonces: senderNonces,
+	}, nil
+}
+
+// batchInput is the total amount a batch spends of one input token
+type batchInput struct {
+	token  orderToken
+	total  *big.Int
+	orders int
+}
+
+// batchInputTotals sums the input amounts of orders per input token, in first-seen order
+func batchInputTotals(orders []OrderConfig) []batchInput {
+	var inputs []batchInput
+	index := make(map[common.Address]int)
+	for _, order := range orders {
+		token := common.HexToAddress(order.tokens.Input.Address)
+		i, ok := index[token]
+		if !ok {
+			i = len(inputs)
+			index[token] = i
+			inputs = append(inputs, batchInput{token: order.tokens.Input, total: new(big.Int)})
+		}
+		inputs[i].total.Add(inputs[i].total, order.InputAmount)
+		inputs[i].orders++
+	}
+	return inputs
+}
+
+// approveBatchInput checks Alice can spend the input's total and, with --auto-approve, approves it
+func approveBatchInput(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, network *NetworkConfig, hyperlane common.Address, input batchInput) error {
+	inputToken, total := input.token, input.total
+	token := common.HexToAddress(inputToken.Address)
+	decimals := inputToken.Decimals
+
+	balance, _, err := readFunds(ctx, "balance", evmBalance(client, token, auth.From), total)
+	if err != nil {
+		return fmt.Errorf("failed to read balance: %w", err)
+	}
+	allowance, _, err := readFunds(ctx, "allowance", evmAllowance(client, token, auth.From, hyperlane), total)
+	if err != nil {
+		return fmt.Errorf("failed to read allowance: %w", err)
+	}
+	needsApproval, err := preflightFunds(fundsCheck{
+		Token:     inputToken.Address,
+		Owner:     auth.From.Hex(),
+		Spender:   hyperlane.Hex(),
+		Decimals:  decimals,
+		Balance:   balance,
+		Allowance: allowance,
+		Need:      total,
+	})
+	if err != nil || !needsApproval {
+		return err
+	}
+
+	approveTx, err := ethutil.ERC20Approve(ctx, client, auth, token, hyperlane, total)
+	if err != nil {
+		return fmt.Errorf("failed to approve tokens: %w", err)
+	}
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(txcost.WithNetwork(ctx, network.name), "approve"), client, approveTx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for approval: %w", err)
+	}
+	if receipt.Status != 1 {
+		return fmt.Errorf("approval transaction %s failed", approveTx.Hash().Hex())
+	}
+	logf("   %s: approved %s for %d orders\n", network.name, ethutil.FormatTokenAmount(total, decimals), input.orders)
+	return nil
+}
+
+// openOrder sends one open() with deadlines windows past the origin's block time and waits for it, never exiting
+// the process
+func (s *originSession) openOrder(ctx context.Context, order OrderConfig, windows DeadlineWindows, networks []NetworkConfig) batchResult {
+	result := batchResult{Order: order}
+
+	destination := findNetwork(networks, order.DestinationChain)
+	if destination == nil {
+		result.Err = fmt.Errorf("destination network not found: %s", order.DestinationChain)
+		return result
+	}
+	destination, err := withDestinationSettler(destination, s.network)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if err := stampEVMDeadlines(ctx, s.client, &order, windows); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Order = order
+
+	tx, sent, err := s.send(ctx, order, destination)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.TxHash = tx.Hash()
+	result.OrderData = sent.OrderData
+	result.FillDeadline = sent.FillDeadline
+
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(txcost.WithNetwork(ctx, s.network.name), "open order"), s.client, tx)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to wait for %s: %w", result.TxHash.Hex(), err)
+		return result
+	}
+	result.GasUsed = receipt.GasUsed
+	if receipt.Status != 1 {
+		result.Err = ethutil.RevertedTxError(ctx, s.client, "open", tx, s.auth.From, receipt)
+		return result
+	}
+
+	for _, l := range receipt.Logs {
+		if event, err := s.contract.ParseOpen(*l); err == nil {
+			result.OrderID = event.OrderId
+			break
+		}
+	}
+	return result
+}
+
+// orderResult converts a successful batch order into the result recorded in the order store
+func (r batchResult) orderResult() *OrderResult {
+	result := newOrderResult(r.Order.OriginChain, r.Order.DestinationChain, r.Order.InputAmount, r.Order.OutputAmount)
+	result.InputToken = r.Order.tokens.Input.Address
+	result.OutputToken = r.Order.tokens.Output.Address
+	result.OrderID = r.OrderID.Hex()
+	result.TxHash = r.TxHash.Hex()
+	result.GasUsed = r.GasUsed
+	result.recordOrder(uint64(r.FillDeadline), getOrderDataTypeHash(), r.OrderData)
+	result.Gasless = r.Gasless
+	result.OpenDeadline = uint64(r.OpenDeadline)
+	for _, id := range r.TxOrders {
+		result.TxOrders = append(result.TxOrders, id.Hex())
+	}
+	result.complete(nil)
+	return result
+}
+
+// send builds and broadcasts open() under the session lock so tx nonces stay gapless.
+// It returns the order as sent alongside the transaction
+func (s *originSession) send(ctx context.Context, order OrderConfig, destination *NetworkConfig) (*gethtypes.Transaction, contracts.OnchainCrossChainOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.senderNonces) == 0 {
+		return nil, contracts.OnchainCrossChainOrder{}, fmt.Errorf("no reserved sender nonce left")
+	}
+	senderNonce := s.senderNonces[0]
+
+	orderData, err := buildOrderData(&order, order.tokens, destination, s.localDomain, senderNonce)
+	if err != nil {
+		return nil, contracts.OnchainCrossChainOrder{}, fmt.Errorf("failed to build order data: %w", err)
+	}
+
+	opts := withOpenValue(s.auth, nativeInputValue(&orderData))
+	opts.Nonce = new(big.Int).SetUint64(s.txNonce)
+	onchainOrder, err := newOnchainOrder(&orderData, senderNonce)
+	if err != nil {
+		return nil, contracts.OnchainCrossChainOrder{}, fmt.Errorf("failed to encode order data: %w", err)
+	}
+	if err := verifyEVMOrderDataType(ctx, s.contract, s.network.name, common.HexToAddress(s.network.hyperlaneAddress), onchainOrder); err != nil {
+		return nil, contracts.OnchainCrossChainOrder{}, err
+	}
+	tx, err := sendOpenTransaction(ctx, s.client, opts, common.HexToAddress(s.network.hyperlaneAddress), onchainOrder)
+	if err != nil {
+		// Nothing was broadcast, so the tx nonce and sender nonce stay available
+		return nil, contracts.OnchainCrossChainOrder{}, fmt.Errorf("failed to send open transaction: %w", err)
+	}
+
+	s.txNonce++
+	s.senderNonces = s.senderNonces[1:]
+	return tx, onchainOrder, nil
+}
+
+// findNetwork looks up a network by name
+func findNetwork(networks []NetworkConfig, name string) *NetworkConfig {
+	for i := range networks {
+		if networks[i].name == name {
+			return &networks[i]
+		}
+	}
+	return nil
+}
+
+func printBatchSummary(results []batchResult) {
+	var succeeded, failed int
+	var totalGas uint64
+
+	logf("\nBatch Summary:\n")
+	for _, r := range results {
+		totalGas += r.GasUsed
+		if r.Err != nil {
+			failed++
+			errorf("   ❌ %s → %s: %v\n", r.Order.OriginChain, r.Order.DestinationChain, r.Err)
+			continue
+		}
+		succeeded++
+		shared := ""
+		if len(r.TxOrders) > 1 {
+			shared = fmt.Sprintf(", shared by %d orders", len(r.TxOrders))
+		}
+		logf("   ✅ %s → %s: order %s (tx %s%s)\n", r.Order.OriginChain, r.Order.DestinationChain, r.OrderID.Hex(), r.TxHash.Hex(), shared)
+	}
+
+	logf("   Succeeded: %d\n", succeeded)
+	logf("   Failed: %d\n", failed)
+	logf("   Total gas used: %d\n", totalGas)
+}
@@ -0,0 +1,68 @@
+package openorder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestParseBatchArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    batchOptions
+		wantErr bool
+	}{
+		{name: "count only", args: []string{"10"}, want: batchOptions{count: 10, concurrency: defaultBatchConcurrency}},
+		{name: "separate flag", args: []string{"10", "--concurrency", "3"}, want: batchOptions{count: 10, concurrency: 3}},
+		{name: "inline flag", args: []string{"--concurrency=2", "5"}, want: batchOptions{count: 5, concurrency: 2}},
+		{name: "multicall", args: []string{"10", "--multicall", "5"}, want: batchOptions{count: 10, concurrency: defaultBatchConcurrency, multicall: 5}},
+		{name: "inline multicall", args: []string{"--multicall=4", "8"}, want: batchOptions{count: 8, concurrency: defaultBatchConcurrency, multicall: 4}},
+		{name: "missing count", args: []string{"--concurrency", "3"}, wantErr: true},
+		{name: "missing flag value", args: []string{"10", "--concurrency"}, wantErr: true},
+		{name: "missing multicall value", args: []string{"10", "--multicall"}, wantErr: true},
+		{name: "zero count", args: []string{"0"}, wantErr: true},
+		{name: "zero concurrency", args: []string{"3", "--concurrency=0"}, wantErr: true},
+		{name: "negative multicall", args: []string{"3", "--multicall=-1"}, wantErr: true},
+		{name: "not a number", args: []string{"many"}, wantErr: true},
+		{name: "extra argument", args: []string{"3", "4"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseBatchArgs(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, opts)
+		})
+	}
+}
+
+func TestRandomBatchOrders(t *testing.T) {
+	networks := loadNetworks(&config.Config{Networks: config.ReloadNetworks()})
+
+	orders, err := randomBatchOrders(25, networks)
+	require.NoError(t, err)
+	require.Len(t, orders, 25)
+
+	for _, order := range orders {
+		assert.Equal(t, NetworkTypeEVM, GetNetworkType(order.OriginChain), "origin must be EVM")
+		assert.NotEqual(t, order.OriginChain, order.DestinationChain)
+		assert.True(t, order.InputAmount.Cmp(order.OutputAmount) > 0, "input must exceed output for solver profit")
+		assert.Zero(t, order.Deadlines, "deadlines are stamped from the origin's block time when sent")
+	}
+}
+
+func TestFindNetwork(t *testing.T) {
+	networks := []NetworkConfig{{name: "Ethereum"}, {name: "Base"}}
+
+	require.NotNil(t, findNetwork(networks, "Base"))
+	assert.Equal(t, "Base", findNetwork(networks, "Base").name)
+	assert.Nil(t, findNetwork(networks, "Optimism"))
+}
@@ -0,0 +1,353 @@
+package openorder
+
+// EVM gasless order creation - ERC-7683 openFor with a Permit2 witness signature
+// Alice signs off-chain, the Solver account submits openFor and pays the gas
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/permit2"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// RunEVMGaslessOrder opens an EVM order for Alice through openFor, submitted by the Solver
+func RunEVMGaslessOrder(ctx context.Context, originChain, destinationChain string) {
+	// Load configuration (this loads .env and initializes networks)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		failOrder(originChain, destinationChain, fmt.Errorf("failed to load config: %w", err))
+		return
+	}
+	logutil.ConfigureFromConfig(logger, cfg)
+
+	if err := initializeUsers(); err != nil {
+		failOrder(originChain, destinationChain, err)
+		return
+	}
+	networks := loadNetworks(cfg)
+
+	if GetNetworkType(originChain) != NetworkTypeEVM {
+		failOrder(originChain, destinationChain, fmt.Errorf("gasless orders require an EVM origin, got %s", originChain))
+		return
+	}
+
+	// Random amounts - ensure solver profitability
+	outputAmount := CreateTokenAmount(int64(randomInt(maxTokenAmount-minTokenAmount+1)+minTokenAmount), tokenDecimals)
+	delta := CreateTokenAmount(int64(randomInt(maxDeltaAmount-minDeltaAmount+1)+minDeltaAmount), tokenDecimals)
+
+	order := OrderConfig{
+		OriginChain:      originChain,
+		DestinationChain: destinationChain,
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
+		InputAmount:      new(big.Int).Add(outputAmount, delta),
+		OutputAmount:     outputAmount,
+		User:             AliceUserName,
+	}
+
+	result := newOrderResult(order.OriginChain, order.DestinationChain, order.InputAmount, order.OutputAmount)
+	if err := executeGaslessOrder(ctx, &order, networks, result); err != nil {
+		finishOrder(result, fmt.Errorf("gasless order failed: %w", err))
+		return
+	}
+	finishOrder(result, nil)
+}
+
+func executeGaslessOrder(ctx context.Context, order *OrderConfig, networks []NetworkConfig, result *OrderResult) error {
+	logf("\nOpening Gasless Order: %s → %s\n", order.OriginChain, order.DestinationChain)
+
+	originNetwork := findNetwork(networks, order.OriginChain)
+	if originNetwork == nil {
+		return fmt.Errorf("origin network not found: %s", order.OriginChain)
+	}
+	if err := checkOriginSettler(originNetwork); err != nil {
+		return err
+	}
+	ctx = txcost.WithNetwork(ctx, originNetwork.name)
+	destinationNetwork := findNetwork(networks, order.DestinationChain)
+	if destinationNetwork == nil {
+		return fmt.Errorf("destination network not found: %s", order.DestinationChain)
+	}
+	destinationNetwork, err := withDestinationSettler(destinationNetwork, originNetwork)
+	if err != nil {
+		return err
+	}
+
+	aliceKey, err := credentials.LoadEVMKey(envutil.ConditionalKey("ALICE"))
+	if err != nil {
+		return fmt.Errorf("failed to load Alice private key: %w", err)
+	}
+	solverKey, err := credentials.LoadEVMKey(envutil.ConditionalKey("SOLVER"))
+	if err != nil {
+		return fmt.Errorf("failed to load Solver private key: %w", err)
+	}
+
+	chainID := new(big.Int).SetUint64(originNetwork.chainID)
+	aliceAuth, err := ethutil.NewTransactor(chainID, aliceKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Alice auth: %w", err)
+	}
+	solverAuth, err := ethutil.NewTransactor(chainID, solverKey)
+	if err != nil {
+		return fmt.Errorf("failed to create Solver auth: %w", err)
+	}
+	alice := aliceAuth.From
+
+	client, err := clients().EVMFailover(ctx, order.OriginChain, originNetwork.urls)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", order.OriginChain, err)
+	}
+	if err := stampEVMDeadlines(ctx, client, order, deadlineWindows); err != nil {
+		return err
+	}
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get gas price: %w", err)
+	}
+	aliceAuth.GasPrice = gasPrice
+	solverAuth.GasPrice = gasPrice
+
+	hyperlane := common.HexToAddress(originNetwork.hyperlaneAddress)
+	contract, err := contracts.NewHyperlane7683(hyperlane, client)
+	if err != nil {
+		return fmt.Errorf("failed to bind Hyperlane7683: %w", err)
+	}
+
+	localDomain, err := getLocalDomain(ctx, client, hyperlane)
+	if err != nil {
+		return fmt.Errorf("failed to read localDomain: %w", err)
+	}
+
+	callOpts := &bind.CallOpts{Context: ctx}
+	permit2Address, err := contract.PERMIT2(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to read PERMIT2 address: %w", err)
+	}
+
+	if err := order.resolveTokens(ctx, result); err != nil {
+		return err
+	}
+	token := common.HexToAddress(order.tokens.Input.Address)
+	initialBalance, _, err := readFunds(ctx, "balance", evmBalance(client, token, alice), order.InputAmount)
+	if err != nil {
+		return fmt.Errorf("failed to read Alice balance: %w", err)
+	}
+	permit2Allowance, _, err := readFunds(ctx, "Permit2 allowance", evmAllowance(client, token, alice, permit2Address), order.InputAmount)
+	if err != nil {
+		return fmt.Errorf("failed to read Permit2 allowance: %w", err)
+	}
+	// Permit2 is the spender here: it pulls the input when the Solver calls openFor
+	needsApproval, err := preflightFunds(fundsCheck{
+		Token:     order.tokens.Input.Address,
+		Owner:     alice.Hex(),
+		Spender:   "Permit2 " + permit2Address.Hex(),
+		Decimals:  order.tokens.Input.Decimals,
+		Balance:   initialBalance,
+		Allowance: permit2Allowance,
+		Need:      order.InputAmount,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The senderNonce doubles as the Permit2 unordered nonce; both are random and checked for reuse
+	senderNonce, err := pickValidSenderNonce(ctx, client, hyperlane, alice)
+	if err != nil {
+		return fmt.Errorf("failed to pick a valid sender nonce: %w", err)
+	}
+	result.SenderNonce = senderNonce.String()
+
+	orderData, err := buildOrderData(order, order.tokens, destinationNetwork, localDomain, senderNonce)
+	if err != nil {
+		return fmt.Errorf("failed to build order data: %w", err)
+	}
+	openDeadline, err := order.Deadlines.Open.Uint32()
+	if err != nil {
+		return fmt.Errorf("invalid open deadline: %w", err)
+	}
+	onchainOrder, err := newOnchainOrder(&orderData, senderNonce)
+	if err != nil {
+		return fmt.Errorf("failed to encode order data: %w", err)
+	}
+	gaslessOrder := contracts.GaslessCrossChainOrder{
+		OriginSettler: hyperlane,
+		User:          alice,
+		Nonce:         senderNonce,
+		OriginChainId: big.NewInt(int64(localDomain)),
+		OpenDeadline:  openDeadline,
+		FillDeadline:  onchainOrder.FillDeadline,
+		OrderDataType: onchainOrder.OrderDataType,
+		OrderData:     onchainOrder.OrderData,
+	}
+	originFillerData := []byte{}
+	result.recordOrder(uint64(gaslessOrder.FillDeadline), gaslessOrder.OrderDataType, gaslessOrder.OrderData)
+	result.Gasless = true
+	result.OpenDeadline = uint64(gaslessOrder.OpenDeadline)
+
+	// The Permit2 approval is the first transaction, sent only once the order is fully built
+	if needsApproval {
+		if err := approvePermit2(ctx, client, aliceAuth, token, permit2Address); err != nil {
+			return err
+		}
+	}
+
+	if err := verifyEVMOrderDataType(ctx, contract, order.OriginChain, hyperlane, onchainOrder); err != nil {
+		return err
+	}
+	constants, err := orderquery.LoadConstants(ctx, order.OriginChain, hyperlane, client)
+	if err != nil {
+		return err
+	}
+
+	signature, resolved, err := signGaslessOrder(contract, callOpts, client, constants, gaslessOrder, originFillerData, permit2Address, hyperlane, aliceKey)
+	if err != nil {
+		return err
+	}
+	logf("   Alice signed Permit2 witness for order %s\n", common.Hash(resolved.OrderId).Hex())
+
+	tx, err := contract.OpenFor(solverAuth, gaslessOrder, signature, originFillerData)
+	if err != nil {
+		return fmt.Errorf("failed to send openFor transaction: %w", ethutil.RevertReason(err))
+	}
+	result.TxHash = tx.Hash().Hex()
+	logf("   openFor sent by Solver %s: %s\n", solverAuth.From.Hex(), tx.Hash().Hex())
+	logf("   ⏳ Waiting for confirmation...\n")
+
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "openFor"), client, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for openFor transaction: %w", err)
+	}
+	result.GasUsed = receipt.GasUsed
+	if receipt.Status != 1 {
+		return ethutil.RevertedTxError(ctx, client, "openFor", tx, solverAuth.From, receipt)
+	}
+
+	var orderID common.Hash
+	for _, l := range receipt.Logs {
+		if event, err := contract.ParseOpen(*l); err == nil {
+			orderID = event.OrderId
+			break
+		}
+	}
+	if orderID != resolved.OrderId {
+		return fmt.Errorf("order ID mismatch: resolved %s, contract emitted %s", common.Hash(resolved.OrderId).Hex(), orderID.Hex())
+	}
+	word, err := contract.OrderStatus(&bind.CallOpts{Context: ctx, BlockNumber: receipt.BlockNumber}, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to read order status: %w", err)
+	}
+	if status, err := constants.Status(word); err != nil || status != orderquery.StatusOpened {
+		return fmt.Errorf("order %s is not %s after openFor (status %s)", orderID.Hex(), orderquery.StatusOpened, common.Hash(word).Hex())
+	}
+	result.OrderID = orderID.Hex()
+
+	// Read at the openFor block so the comparison does not depend on RPC state lag
+	finalBalance, err := ethutil.WaitForERC20BalanceChange(ctx, client, token, alice, initialBalance,
+		ethutil.BalancePollOptions{BlockNumber: receipt.BlockNumber})
+	if err != nil {
+		return fmt.Errorf("failed to read Alice balance: %w", err)
+	}
+	spent := new(big.Int).Sub(initialBalance, finalBalance)
+	if spent.Cmp(order.InputAmount) != 0 {
+		return fmt.Errorf("alice balance changed by %s, expected %s", spent.String(), order.InputAmount.String())
+	}
+
+	logf("✅ Gasless order opened successfully!\n")
+	logf("📊 Gas used: %d (paid by Solver)\n", receipt.GasUsed)
+	logf("   Order ID: %s\n", orderID.Hex())
+	logf("   Alice spent: %s\n", ethutil.FormatTokenAmount(spent, order.tokens.Input.Decimals))
+	return nil
+}
+
+// approvePermit2 gives Permit2 a one-time unlimited allowance from the user (--auto-approve).
+// Permit2 then moves tokens per signed order, so later gasless orders need no approval
+func approvePermit2(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, token, permit2Address common.Address) error {
+	logf("   Approving Permit2 %s (one-time, paid by Alice)...\n", permit2Address.Hex())
+	approveTx, err := ethutil.ERC20Approve(ctx, client, auth, token, permit2Address, abi.MaxUint256)
+	if err != nil {
+		return fmt.Errorf("failed to approve Permit2: %w", err)
+	}
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "approve Permit2"), client, approveTx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for Permit2 approval: %w", err)
+	}
+	if receipt.Status != 1 {
+		return fmt.Errorf("permit2 approval transaction %s failed", approveTx.Hash().Hex())
+	}
+	return nil
+}
+
+// signGaslessOrder resolves the order on-chain and signs the Permit2 PermitBatchWitnessTransferFrom for it, once the
+// witness type string is checked against the contract's RESOLVED_CROSS_CHAIN_ORDER_TYPEHASH
+func signGaslessOrder(
+	contract *contracts.Hyperlane7683,
+	callOpts *bind.CallOpts,
+	client *ethclient.Client,
+	constants orderquery.ContractConstants,
+	order contracts.GaslessCrossChainOrder,
+	originFillerData []byte,
+	permit2Address, spender common.Address,
+	key *ecdsa.PrivateKey,
+) ([]byte, contracts.ResolvedCrossChainOrder, error) {
+	resolved, err := contract.ResolveFor(callOpts, order, originFillerData)
+	if err != nil {
+		return nil, resolved, fmt.Errorf("failed to resolve gasless order: %w", err)
+	}
+	witness, err := contract.WitnessHash(callOpts, resolved)
+	if err != nil {
+		return nil, resolved, fmt.Errorf("failed to compute witness hash: %w", err)
+	}
+	witnessTypeString, err := contract.WitnessTypeString(callOpts)
+	if err != nil {
+		return nil, resolved, fmt.Errorf("failed to read witness type string: %w", err)
+	}
+	if err := constants.CheckWitnessTypeString(witnessTypeString); err != nil {
+		return nil, resolved, err
+	}
+	domainSeparator, err := permit2.DomainSeparator(callOpts.Context, client, permit2Address)
+	if err != nil {
+		return nil, resolved, err
+	}
+
+	// Permit2 pulls exactly minReceived, with the order nonce and openDeadline
+	permitted := make([]permit2.TokenPermissions, 0, len(resolved.MinReceived))
+	for _, out := range resolved.MinReceived {
+		token, err := starknetutil.Bytes32ToEVMAddress(out.Token)
+		if err != nil {
+			return nil, resolved, fmt.Errorf("invalid minReceived token: %w", err)
+		}
+		permitted = append(permitted, permit2.TokenPermissions{
+			Token:  token,
+			Amount: out.Amount,
+		})
+	}
+	permit := permit2.PermitBatchTransferFrom{
+		Permitted: permitted,
+		Nonce:     order.Nonce,
+		Deadline:  new(big.Int).SetUint64(uint64(resolved.OpenDeadline)),
+	}
+
+	structHash := permit2.HashBatchWitnessTransferFrom(permit, spender, witness, witnessTypeString)
+	signature, err := permit2.Sign(permit2.TypedDataDigest(domainSeparator, structHash), key)
+	if err != nil {
+		return nil, resolved, err
+	}
+	return signature, resolved, nil
+}
@@ -0,0 +1,404 @@
+package openorder
+
+// EVM batch orders aggregated through Multicall3 (batch --multicall N)
+// Multicall3 is msg.sender of every call it makes, so open() through it would open orders for Multicall3 and pull
+// its tokens, not Alice's. Aggregated orders are therefore gasless: Alice signs each one's Permit2 witness and
+// sends a single aggregate3 of openFor calls herself, which opens them all for her. Each call may fail on its own;
+// the aggregation is simulated first so failing orders are reported with their revert reason and left out, and the
+// orders actually opened are read from the Open events of the receipt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/multicall3"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// MulticallFlag aggregates batch orders, N per transaction, through Multicall3
+const MulticallFlag = "--multicall"
+
+// multicallSession holds the per-origin state of a Multicall3 batch. Alice signs every order and sends the
+// aggregated transactions
+type multicallSession struct {
+	network     *NetworkConfig
+	client      *ethclient.Client
+	key         *ecdsa.PrivateKey
+	auth        *bind.TransactOpts
+	contract    *contracts.Hyperlane7683
+	hyperlane   common.Address
+	multicall   common.Address
+	permit2     common.Address
+	constants   orderquery.ContractConstants
+	localDomain uint32
+
+	senderNonces []*big.Int
+}
+
+// multicallCall is an order signed and packed into an aggregate3 call
+type multicallCall struct {
+	result  int // index of the order's result
+	orderID common.Hash
+	call    multicall3.Call3
+}
+
+// openMulticallOrders opens the orders of origin perTx at a time through Multicall3, saving and recording each
+// result. It returns the orders left to send one by one: native-input orders, which openFor cannot carry, or all
+// of them when origin has no Multicall3
+func openMulticallOrders(ctx context.Context, origin string, orders []OrderConfig, perTx int, networks []NetworkConfig, store *orderstore.Store, record func(batchResult)) []OrderConfig {
+	var aggregated, individual []OrderConfig
+	for _, order := range orders {
+		if common.HexToAddress(order.tokens.Input.Address) == (common.Address{}) {
+			individual = append(individual, order)
+			continue
+		}
+		aggregated = append(aggregated, order)
+	}
+	if len(aggregated) == 0 {
+		return individual
+	}
+
+	session, err := newMulticallSession(ctx, origin, aggregated, networks, multicall3.Address)
+	if errors.Is(err, multicall3.ErrNotDeployed) {
+		warnf("   ⚠️  %s: %v, sending its orders one by one\n", origin, err)
+		return orders
+	}
+	if err != nil {
+		errorf("   ❌ %s: %v\n", origin, err)
+		for _, order := range aggregated {
+			record(batchResult{Order: order, Err: err})
+		}
+		return individual
+	}
+
+	for start := 0; start < len(aggregated); start += perTx {
+		end := min(start+perTx, len(aggregated))
+		for _, r := range session.openOrders(ctx, aggregated[start:end], deadlineWindows, networks) {
+			if r.Err == nil && r.OrderID != (common.Hash{}) {
+				saveOrder(store, r.orderResult())
+			}
+			record(r)
+		}
+	}
+	return individual
+}
+
+// newMulticallSession connects to an origin with Multicall3 at multicall, gives Permit2 the batch's inputs and
+// reserves sender nonces
+func newMulticallSession(ctx context.Context, origin string, orders []OrderConfig, networks []NetworkConfig, multicall common.Address) (*multicallSession, error) {
+	network := findNetwork(networks, origin)
+	if network == nil {
+		return nil, fmt.Errorf("origin network not found: %s", origin)
+	}
+	key, auth, err := aliceTransactor(network)
+	if err != nil {
+		return nil, err
+	}
+	client, err := clients().EVMFailover(ctx, origin, network.urls)
+	if err != nil {
+		return nil, err
+	}
+	if err := multicall3.CheckDeployed(ctx, client, multicall); err != nil {
+		return nil, err
+	}
+	ctx = txcost.WithNetwork(ctx, network.name)
+
+	hyperlane := common.HexToAddress(network.hyperlaneAddress)
+	localDomain, err := getLocalDomain(ctx, client, hyperlane)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read localDomain: %w", err)
+	}
+	contract, err := contracts.NewHyperlane7683(hyperlane, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind Hyperlane7683: %w", err)
+	}
+	permit2Address, err := contract.PERMIT2(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PERMIT2 address: %w", err)
+	}
+	constants, err := orderquery.LoadConstants(ctx, network.name, hyperlane, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Permit2 pulls every aggregated order's input, so it is the spender the batch totals are checked against
+	for _, input := range batchInputTotals(orders) {
+		if err := approvePermit2Input(ctx, client, auth, network, permit2Address, input); err != nil {
+			return nil, err
+		}
+	}
+
+	// The senderNonce doubles as the Permit2 unordered nonce of each order
+	senderNonces, err := pickValidSenderNonces(ctx, client, hyperlane, auth.From, len(orders))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve sender nonces: %w", err)
+	}
+
+	logf("   %s: opening %d orders through Multicall3 %s\n", network.name, len(orders), multicall.Hex())
+	return &multicallSession{
+		network:      network,
+		client:       client,
+		key:          key,
+		auth:         auth,
+		contract:     contract,
+		hyperlane:    hyperlane,
+		multicall:    multicall,
+		permit2:      permit2Address,
+		constants:    constants,
+		localDomain:  localDomain,
+		senderNonces: senderNonces,
+	}, nil
+}
+
+// approvePermit2Input checks Alice can spend the input's total through Permit2 and, with --auto-approve, approves it
+func approvePermit2Input(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, network *NetworkConfig, permit2Address common.Address, input batchInput) error {
+	token := common.HexToAddress(input.token.Address)
+	balance, _, err := readFunds(ctx, "balance", evmBalance(client, token, auth.From), input.total)
+	if err != nil {
+		return fmt.Errorf("failed to read balance: %w", err)
+	}
+	allowance, _, err := readFunds(ctx, "Permit2 allowance", evmAllowance(client, token, auth.From, permit2Address), input.total)
+	if err != nil {
+		return fmt.Errorf("failed to read Permit2 allowance: %w", err)
+	}
+	needsApproval, err := preflightFunds(fundsCheck{
+		Token:     input.token.Address,
+		Owner:     auth.From.Hex(),
+		Spender:   "Permit2 " + permit2Address.Hex(),
+		Decimals:  input.token.Decimals,
+		Balance:   balance,
+		Allowance: allowance,
+		Need:      input.total,
+	})
+	if err != nil || !needsApproval {
+		return err
+	}
+	if err := approvePermit2(ctx, client, auth, token, permit2Address); err != nil {
+		return err
+	}
+	logf("   %s: approved Permit2 for %d orders of %s\n", network.name, input.orders, input.token.Address)
+	return nil
+}
+
+// openOrders opens orders in one aggregate3 transaction and returns a result for each, in order
+func (s *multicallSession) openOrders(ctx context.Context, orders []OrderConfig, windows DeadlineWindows, networks []NetworkConfig) []batchResult {
+	ctx = txcost.WithNetwork(ctx, s.network.name)
+	results := make([]batchResult, len(orders))
+	var calls []multicallCall
+	for i, order := range orders {
+		results[i] = batchResult{Order: order, Gasless: true}
+		call, err := s.signOrder(ctx, &results[i], windows, networks)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		call.result = i
+		calls = append(calls, call)
+	}
+
+	// Leave out the orders whose openFor would revert, so one bad order does not cost the others a transaction
+	calls = s.simulate(ctx, calls, results)
+	if len(calls) == 0 {
+		return results
+	}
+	s.send(ctx, calls, results)
+	return results
+}
+
+// signOrder builds result's order with deadlines windows past the origin's block time, has Alice sign it and
+// packs its openFor call
+func (s *multicallSession) signOrder(ctx context.Context, result *batchResult, windows DeadlineWindows, networks []NetworkConfig) (multicallCall, error) {
+	order := result.Order
+	destination := findNetwork(networks, order.DestinationChain)
+	if destination == nil {
+		return multicallCall{}, fmt.Errorf("destination network not found: %s", order.DestinationChain)
+	}
+	destination, err := withDestinationSettler(destination, s.network)
+	if err != nil {
+		return multicallCall{}, err
+	}
+	if err := stampEVMDeadlines(ctx, s.client, &order, windows); err != nil {
+		return multicallCall{}, err
+	}
+	result.Order = order
+
+	if len(s.senderNonces) == 0 {
+		return multicallCall{}, fmt.Errorf("no reserved sender nonce left")
+	}
+	senderNonce := s.senderNonces[0]
+	s.senderNonces = s.senderNonces[1:]
+
+	orderData, err := buildOrderData(&order, order.tokens, destination, s.localDomain, senderNonce)
+	if err != nil {
+		return multicallCall{}, fmt.Errorf("failed to build order data: %w", err)
+	}
+	onchainOrder, err := newOnchainOrder(&orderData, senderNonce)
+	if err != nil {
+		return multicallCall{}, fmt.Errorf("failed to encode order data: %w", err)
+	}
+	openDeadline, err := order.Deadlines.Open.Uint32()
+	if err != nil {
+		return multicallCall{}, fmt.Errorf("invalid open deadline: %w", err)
+	}
+	gaslessOrder := contracts.GaslessCrossChainOrder{
+		OriginSettler: s.hyperlane,
+		User:          s.auth.From,
+		Nonce:         senderNonce,
+		OriginChainId: big.NewInt(int64(s.localDomain)),
+		OpenDeadline:  openDeadline,
+		FillDeadline:  onchainOrder.FillDeadline,
+		OrderDataType: onchainOrder.OrderDataType,
+		OrderData:     onchainOrder.OrderData,
+	}
+	result.OrderData = gaslessOrder.OrderData
+	result.FillDeadline = gaslessOrder.FillDeadline
+	result.OpenDeadline = gaslessOrder.OpenDeadline
+
+	originFillerData := []byte{}
+	callOpts := &bind.CallOpts{Context: ctx}
+	signature, resolved, err := signGaslessOrder(s.contract, callOpts, s.client, s.constants, gaslessOrder, originFillerData, s.permit2, s.hyperlane, s.key)
+	if err != nil {
+		return multicallCall{}, err
+	}
+	data, err := packOpenFor(gaslessOrder, signature, originFillerData)
+	if err != nil {
+		return multicallCall{}, err
+	}
+	return multicallCall{
+		orderID: resolved.OrderId,
+		call:    multicall3.Call3{Target: s.hyperlane, AllowFailure: true, CallData: data},
+	}, nil
+}
+
+// simulate runs the aggregation through eth_call, fails the results of the calls that would revert and returns
+// the calls that would succeed
+func (s *multicallSession) simulate(ctx context.Context, calls []multicallCall, results []batchResult) []multicallCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	simulated, err := multicall3.Simulate(ctx, s.client, s.multicall, s.auth.From, nil, multicallCalls(calls))
+	if err != nil {
+		for _, c := range calls {
+			results[c.result].Err = fmt.Errorf("aggregate3 would revert: %w", ethutil.RevertReason(err))
+		}
+		return nil
+	}
+	var succeeding []multicallCall
+	for i, c := range calls {
+		if !simulated[i].Success {
+			results[c.result].Err = fmt.Errorf("openFor would revert: %w", callRevertReason(simulated[i].ReturnData))
+			continue
+		}
+		succeeding = append(succeeding, c)
+	}
+	return succeeding
+}
+
+// send broadcasts calls as one aggregate3 transaction and fills in the results of their orders from its receipt
+func (s *multicallSession) send(ctx context.Context, calls []multicallCall, results []batchResult) {
+	fail := func(err error) {
+		for _, c := range calls {
+			results[c.result].Err = err
+		}
+	}
+	data, err := multicall3.Pack(multicallCalls(calls))
+	if err != nil {
+		fail(err)
+		return
+	}
+	tx, err := ethutil.SendTx(ctx, s.client, s.auth, s.multicall, nil, data)
+	if err != nil {
+		fail(fmt.Errorf("failed to send aggregate3 transaction: %w", ethutil.RevertReason(err)))
+		return
+	}
+	for _, c := range calls {
+		results[c.result].TxHash = tx.Hash()
+	}
+	logf("   %s: aggregate3 of %d orders sent: %s\n", s.network.name, len(calls), tx.Hash().Hex())
+
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "open orders (multicall)"), s.client, tx)
+	if err != nil {
+		fail(fmt.Errorf("failed to wait for %s: %w", tx.Hash().Hex(), err))
+		return
+	}
+	if receipt.Status != 1 {
+		fail(ethutil.RevertedTxError(ctx, s.client, "aggregate3", tx, s.auth.From, receipt))
+		return
+	}
+	decomposeMulticallReceipt(s.contract, s.hyperlane, receipt, calls, results)
+}
+
+// decomposeMulticallReceipt reports each aggregated order as opened when the receipt has its Open event. The
+// transaction's gas is shared evenly among the orders it opened
+func decomposeMulticallReceipt(contract openEventParser, hyperlane common.Address, receipt *gethtypes.Receipt, calls []multicallCall, results []batchResult) {
+	emitted := make(map[common.Hash]bool)
+	for _, l := range receipt.Logs {
+		if l.Address != hyperlane {
+			continue
+		}
+		if event, err := contract.ParseOpen(*l); err == nil {
+			emitted[event.OrderId] = true
+		}
+	}
+
+	var opened []common.Hash
+	for _, c := range calls {
+		if !emitted[c.orderID] {
+			results[c.result].Err = fmt.Errorf("order %s has no Open event in %s", c.orderID.Hex(), receipt.TxHash.Hex())
+			continue
+		}
+		opened = append(opened, c.orderID)
+	}
+	for _, c := range calls {
+		if results[c.result].Err != nil {
+			continue
+		}
+		r := &results[c.result]
+		r.OrderID = c.orderID
+		r.GasUsed = receipt.GasUsed / uint64(len(opened))
+		r.TxOrders = opened
+	}
+}
+
+// multicallCalls returns the aggregate3 calls of calls
+func multicallCalls(calls []multicallCall) []multicall3.Call3 {
+	out := make([]multicall3.Call3, len(calls))
+	for i, c := range calls {
+		out[i] = c.call
+	}
+	return out
+}
+
+// callRevertReason decodes the revert data of one aggregated call
+func callRevertReason(data []byte) error {
+	revert, err := ethutil.DecodeRevert(data)
+	if err != nil {
+		return fmt.Errorf("reverted with data 0x%x", data)
+	}
+	return revert
+}
+
+// packOpenFor ABI-encodes the openFor(order, signature, originFillerData) call
+func packOpenFor(order contracts.GaslessCrossChainOrder, signature, originFillerData []byte) ([]byte, error) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Hyperlane7683 ABI: %w", err)
+	}
+	data, err := parsed.Pack("openFor", order, signature, originFillerData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack openFor: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,69 @@
+package openorder
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+func TestDecomposeMulticallReceipt(t *testing.T) {
+	contract, err := contracts.NewHyperlane7683(fixtureHyperlane, nil)
+	require.NoError(t, err)
+	receipt := loadOpenReceipt(t)
+
+	// The fixture's receipt opened one order; the other aggregated call left no Open event
+	missing := common.HexToHash("0x0bad")
+	calls := []multicallCall{{result: 0, orderID: fixtureOrderID}, {result: 2, orderID: missing}}
+	results := make([]batchResult, 3)
+	results[1].Err = assert.AnError // failed in simulation, never sent
+	decomposeMulticallReceipt(contract, fixtureHyperlane, receipt, calls, results)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, fixtureOrderID, results[0].OrderID)
+	assert.Equal(t, receipt.GasUsed, results[0].GasUsed, "the only order opened carries the whole gas")
+	assert.Equal(t, []common.Hash{fixtureOrderID}, results[0].TxOrders)
+	assert.Equal(t, assert.AnError, results[1].Err)
+	assert.ErrorContains(t, results[2].Err, "order "+missing.Hex()+" has no Open event in "+receipt.TxHash.Hex())
+
+	// Open events from another contract do not open anything
+	results = make([]batchResult, 3)
+	decomposeMulticallReceipt(contract, common.HexToAddress("0x01"), receipt, calls, results)
+	assert.Error(t, results[0].Err)
+}
+
+func TestMulticallOrderResult(t *testing.T) {
+	other := common.HexToHash("0x02")
+	r := batchResult{
+		Order:        OrderConfig{OriginChain: "Base", DestinationChain: "Optimism", tokens: &orderTokens{}},
+		OrderID:      fixtureOrderID,
+		Gasless:      true,
+		OpenDeadline: 1760533200,
+		FillDeadline: 1760536800,
+		TxOrders:     []common.Hash{fixtureOrderID, other},
+	}
+	order := storedOrder(r.orderResult())
+	assert.True(t, order.Gasless)
+	assert.Equal(t, uint64(1760533200), order.OpenDeadline)
+	assert.Equal(t, []string{fixtureOrderID.Hex(), other.Hex()}, order.TxOrders)
+}
+
+func TestCallRevertReason(t *testing.T) {
+	data, err := abi.NewError("Error", abi.Arguments{{Type: mustType(t, "string")}}).Inputs.Pack("InvalidNonce")
+	require.NoError(t, err)
+	reason := callRevertReason(append([]byte{0x08, 0xc3, 0x79, 0xa0}, data...))
+	assert.ErrorContains(t, reason, "InvalidNonce")
+
+	assert.EqualError(t, callRevertReason(nil), "reverted with data 0x")
+}
+
+func mustType(t *testing.T, name string) abi.Type {
+	t.Helper()
+	typ, err := abi.NewType(name, "", nil)
+	require.NoError(t, err)
+	return typ
+}
@@ -0,0 +1,103 @@
+package openorder
+
+// Open event of an EVM order
+// Once open() lands, the Open event in its receipt is the order as the contract recorded it. It is printed in
+// full, compared with the order the tool built, and its values (not the ones computed before sending) are what
+// the result and the order store keep. A divergence cannot be undone at that point, so it is only warned about
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// openEventParser decodes Open logs (the Hyperlane7683 binding satisfies it)
+type openEventParser interface {
+	ParseOpen(log gethtypes.Log) (*contracts.Hyperlane7683Open, error)
+}
+
+// expectedOpen is the order the tool built, as the Open event should report it
+type expectedOpen struct {
+	intent       starknetorder.Intent
+	user         common.Address
+	fillDeadline uint32
+}
+
+// parseEVMOpenEvent finds and decodes the Open event emitted by hyperlane in logs
+func parseEVMOpenEvent(contract openEventParser, hyperlane common.Address, logs []*gethtypes.Log) (*contracts.Hyperlane7683Open, error) {
+	for _, l := range logs {
+		if l.Address != hyperlane {
+			continue
+		}
+		if event, err := contract.ParseOpen(*l); err == nil {
+			return event, nil
+		}
+	}
+	return nil, fmt.Errorf("no Open event from %s in the open() receipt", hyperlane.Hex())
+}
+
+// diffEVMOpenEvent lists every value of event that differs from the order built
+func diffEVMOpenEvent(event *contracts.Hyperlane7683Open, expected expectedOpen) []starknetorder.ResolutionDiff {
+	ro := event.ResolvedOrder
+	var diffs []starknetorder.ResolutionDiff
+	check := func(field, intended, emitted string) {
+		if intended != emitted {
+			diffs = append(diffs, starknetorder.ResolutionDiff{Field: field, Intended: intended, Resolved: emitted})
+		}
+	}
+
+	check("orderId (topic)", expected.intent.OrderID.Hex(), common.Hash(event.OrderId).Hex())
+	check("user", expected.user.Hex(), ro.User.Hex())
+	check("fillDeadline", fmt.Sprint(expected.fillDeadline), fmt.Sprint(ro.FillDeadline))
+	var mismatch *starknetorder.ResolutionMismatchError
+	if errors.As(starknetorder.CheckResolution("", expected.intent, evmResolution(ro)), &mismatch) {
+		diffs = append(diffs, mismatch.Diffs...)
+	}
+	return diffs
+}
+
+// warnEVMOpenEvent warns about every value of the Open event that differs from the order built
+func warnEVMOpenEvent(contractName string, diffs []starknetorder.ResolutionDiff) {
+	if len(diffs) == 0 {
+		return
+	}
+	warnf("   ⚠️  ⚠️  The Open event emitted by %s does not match the order built; the emitted values are recorded\n", contractName)
+	for _, d := range diffs {
+		warnf("   ⚠️    - intended %s: %s\n   ⚠️    + emitted %s: %s\n", d.Field, d.Intended, d.Field, d.Resolved)
+	}
+}
+
+// recordOpenEvent stores the values the contract emitted in result, replacing the ones computed before sending
+func (r *OrderResult) recordOpenEvent(event *contracts.Hyperlane7683Open) {
+	ro := event.ResolvedOrder
+	r.OrderID = common.Hash(event.OrderId).Hex()
+	r.User = common.BytesToHash(ro.User.Bytes()).Hex()
+	r.OpenDeadline = uint64(ro.OpenDeadline)
+	r.FillDeadline = uint64(ro.FillDeadline)
+}
+
+// printEVMOpenEvent prints the Open event decoded from the open() receipt
+func printEVMOpenEvent(event *contracts.Hyperlane7683Open) {
+	ro := event.ResolvedOrder
+	logf("   Open event:\n")
+	logf("     Order ID: %s\n", common.Hash(event.OrderId).Hex())
+	logf("     User: %s\n", ro.User.Hex())
+	logf("     Origin Chain ID: %s\n", ro.OriginChainId.String())
+	logf("     Open Deadline: %d\n", ro.OpenDeadline)
+	logf("     Fill Deadline: %d\n", ro.FillDeadline)
+	for _, out := range ro.MaxSpent {
+		logf("     Max Spent: %s of %s to %s on domain %s\n", out.Amount.String(), common.Hash(out.Token).Hex(),
+			common.Hash(out.Recipient).Hex(), out.ChainId.String())
+	}
+	for _, out := range ro.MinReceived {
+		logf("     Min Received: %s of %s on domain %s\n", out.Amount.String(), common.Hash(out.Token).Hex(), out.ChainId.String())
+	}
+	for _, fill := range ro.FillInstructions {
+		logf("     Fill Instruction: domain %s, settler %s\n", fill.DestinationChainId.String(), common.Hash(fill.DestinationSettler).Hex())
+	}
+}
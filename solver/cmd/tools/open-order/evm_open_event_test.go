@@ -0,0 +1,124 @@
+package openorder
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// testdata/evm_open_receipt.json is an open() receipt as Base Sepolia returns it: the input token's Transfer, then the Open
+var (
+	fixtureHyperlane = common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
+	fixtureUser      = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	fixtureOrderID   = common.HexToHash("0x9f2b6c8a5d0e4b1f7a3c2e6d8b0f1a4c5e7d9b2a3c4d5e6f708192a3b4c5d6e7")
+)
+
+func loadOpenReceipt(t *testing.T) *gethtypes.Receipt {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/evm_open_receipt.json")
+	require.NoError(t, err)
+	var receipt gethtypes.Receipt
+	require.NoError(t, json.Unmarshal(raw, &receipt))
+	return &receipt
+}
+
+// fixtureExpectation is the order the fixture's Open event was emitted for
+func fixtureExpectation() expectedOpen {
+	return expectedOpen{
+		intent: starknetorder.Intent{
+			OrderID:            fixtureOrderID,
+			InputToken:         common.HexToHash("0x036cbd53842c5426634e7929541ec2318f3dcf7e"),
+			AmountIn:           big.NewInt(1001000),
+			OriginDomain:       84532,
+			OutputToken:        common.HexToHash("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"),
+			AmountOut:          big.NewInt(1000000),
+			DestinationDomain:  11155420,
+			DestinationSettler: common.HexToHash("0xf614c6bf94b022e16bef7dbecf7614ffd2b201d3"),
+		},
+		user:         fixtureUser,
+		fillDeadline: 1760536800,
+	}
+}
+
+func TestParseEVMOpenEvent(t *testing.T) {
+	contract, err := contracts.NewHyperlane7683(fixtureHyperlane, nil)
+	require.NoError(t, err)
+	receipt := loadOpenReceipt(t)
+
+	event, err := parseEVMOpenEvent(contract, fixtureHyperlane, receipt.Logs)
+	require.NoError(t, err)
+	assert.Equal(t, fixtureOrderID, common.Hash(event.OrderId))
+	ro := event.ResolvedOrder
+	assert.Equal(t, fixtureUser, ro.User)
+	assert.Equal(t, big.NewInt(84532), ro.OriginChainId)
+	assert.Equal(t, uint32(1760533200), ro.OpenDeadline)
+	assert.Equal(t, uint32(1760536800), ro.FillDeadline)
+	require.Len(t, ro.MaxSpent, 1)
+	assert.Equal(t, big.NewInt(1000000), ro.MaxSpent[0].Amount)
+	require.Len(t, ro.MinReceived, 1)
+	assert.Equal(t, big.NewInt(1001000), ro.MinReceived[0].Amount)
+	require.Len(t, ro.FillInstructions, 1)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, ro.FillInstructions[0].OriginData)
+
+	// Logs from any other contract are not the order's Open event
+	_, err = parseEVMOpenEvent(contract, common.HexToAddress("0x01"), receipt.Logs)
+	assert.ErrorContains(t, err, "no Open event from 0x0000000000000000000000000000000000000001")
+}
+
+func TestDiffEVMOpenEvent(t *testing.T) {
+	contract, err := contracts.NewHyperlane7683(fixtureHyperlane, nil)
+	require.NoError(t, err)
+	event, err := parseEVMOpenEvent(contract, fixtureHyperlane, loadOpenReceipt(t).Logs)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(*expectedOpen)
+		fields []string
+	}{
+		{"matches the order built", func(*expectedOpen) {}, nil},
+		{"another order ID", func(e *expectedOpen) { e.intent.OrderID = common.HexToHash("0x01") }, []string{"orderId (topic)", "orderId"}},
+		{"another user", func(e *expectedOpen) { e.user = common.HexToAddress("0x02") }, []string{"user"}},
+		{"another fill deadline", func(e *expectedOpen) { e.fillDeadline++ }, []string{"fillDeadline"}},
+		{"another input amount", func(e *expectedOpen) { e.intent.AmountIn = big.NewInt(1) }, []string{"minReceived[0].amount"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := fixtureExpectation()
+			tt.modify(&expected)
+			var fields []string
+			for _, d := range diffEVMOpenEvent(event, expected) {
+				fields = append(fields, d.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestRecordOpenEvent(t *testing.T) {
+	contract, err := contracts.NewHyperlane7683(fixtureHyperlane, nil)
+	require.NoError(t, err)
+	event, err := parseEVMOpenEvent(contract, fixtureHyperlane, loadOpenReceipt(t).Logs)
+	require.NoError(t, err)
+
+	// The values computed before sending are replaced by the emitted ones
+	result := newOrderResult("Base", "Optimism", big.NewInt(1001000), big.NewInt(1000000))
+	result.OrderID = common.HexToHash("0x01").Hex()
+	result.FillDeadline = 1
+	result.recordOpenEvent(event)
+
+	order := storedOrder(result)
+	assert.Equal(t, fixtureOrderID.Hex(), order.OrderID)
+	assert.Equal(t, common.BytesToHash(fixtureUser.Bytes()).Hex(), order.User)
+	assert.Equal(t, uint64(1760533200), order.OpenDeadline)
+	assert.Equal(t, uint64(1760536800), order.FillDeadline)
+}
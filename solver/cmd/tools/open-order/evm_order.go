@@ -6,22 +6,27 @@ package openorder
 import (
 	"context"
 	"crypto/rand"
-	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
-	"os"
 	"strings"
 	"time"
 
-	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chain"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/identity"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/holiman/uint256"
@@ -83,10 +88,14 @@ type OrderData struct {
 	DestinationChainID *big.Int
 	User               string
 	Recipient          string // Added explicit Recipient field
-	OpenDeadline       *big.Int
-	FillDeadline       *big.Int
+	Deadlines          orderdeadline.Deadlines
 	MaxSpent           []TokenAmount
 	MinReceived        []TokenAmount
+
+	// Destination-side bytes32 words, encoded by buildOrderData for the destination network type
+	RecipientWord          [32]byte
+	OutputTokenWord        [32]byte
+	DestinationSettlerWord [32]byte
 }
 
 // ABIOrderData struct for ABI encoding (matches Solidity interface)
@@ -124,14 +133,8 @@ func (od *OrderData) IsValid() bool {
 		return false
 	}
 
-	// Check that deadlines are in the future
-	now := big.NewInt(time.Now().Unix())
-	if od.OpenDeadline.Cmp(now) <= 0 {
-		return false
-	}
-
-	// Check that fill deadline is after open deadline
-	if od.FillDeadline.Cmp(od.OpenDeadline) <= 0 {
+	// Check that the deadlines are in the future, in order and fit the contract's uint32
+	if err := od.Deadlines.Validate(uint64(time.Now().Unix())); err != nil {
 		return false
 	}
 
@@ -158,6 +161,17 @@ func ToBigInt(u *uint256.Int) *big.Int {
 	return u.ToBig()
 }
 
+// randomOrderAmounts draws the amounts of a random order in 18-decimal units: 100-10000 tokens out and 1-10
+// whole tokens more in, so the solver is paid for the fill. Both are whole tokens, which each side's own
+// decimals then represent exactly, so the margin survives scaling to tokens of different decimals
+func randomOrderAmounts() (inputAmount, outputAmount *big.Int) {
+	// Alice provides InputAmount and receives OutputAmount; the solver receives InputAmount (MinReceived) and
+	// provides OutputAmount (MaxSpent)
+	outputAmount = CreateTokenAmount(int64(randomInt(maxTokenAmount-minTokenAmount+1)+minTokenAmount), tokenDecimals)
+	delta := CreateTokenAmount(int64(randomInt(maxDeltaAmount-minDeltaAmount+1)+minDeltaAmount), tokenDecimals)
+	return new(big.Int).Add(outputAmount, delta), outputAmount
+}
+
 // CreateTokenAmount creates a token amount using uint256 for better performance
 func CreateTokenAmount(tokens int64, decimals int) *big.Int {
 	// Use uint256 for the arithmetic operations
@@ -172,10 +186,9 @@ func CreateTokenAmount(tokens int64, decimals int) *big.Int {
 // NetworkConfig represents a single network configuration
 type NetworkConfig struct {
 	name             string
-	url              string
+	urls             []string
 	chainID          uint64
 	hyperlaneAddress string
-	dogCoinAddress   string
 }
 
 // OrderConfig represents order configuration
@@ -187,8 +200,9 @@ type OrderConfig struct {
 	InputAmount      *big.Int
 	OutputAmount     *big.Int
 	User             string
-	OpenDeadline     uint32
-	FillDeadline     uint32
+	Deadlines        orderdeadline.Deadlines
+
+	tokens *orderTokens // resolved InputToken/OutputToken, set by resolveTokens
 }
 
 // OrderParams contains the actual addresses and amounts for order execution
@@ -213,142 +227,159 @@ type OnchainCrossChainOrder struct {
 	OrderData     []byte
 }
 
-// Test user configuration (Alice-only for orders, Solver for fills)
-var testUsers = []struct {
-	name       string
-	privateKey string
-	address    string
-}{
-	{"Alice", "ALICE_PRIVATE_KEY", getEnvWithDefault("ALICE_PUB_KEY", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8")},
+// users resolves order senders and recipients; initializeUsers loads it once .env is loaded
+var users *identity.Registry
+
+// initializeUsers loads the identity registry (.env users extended by state/identities.json)
+func initializeUsers() error {
+	registry, err := identity.Load(identity.Path())
+	if err != nil {
+		return fmt.Errorf("failed to load identities: %w", err)
+	}
+	users = registry
+	return nil
 }
 
-// initializeTestUsers initializes the test user configuration after .env is loaded
-func initializeTestUsers() {
-	// Use conditional environment variable logic based on IS_DEVNET
-	aliceAddr := envutil.GetAlicePublicKey()
-	privateKeyVar := envutil.GetConditionalAccountEnv("ALICE_PRIVATE_KEY")
+// lookupUser returns the registered user. Unknown users are an error
+func lookupUser(user string) (identity.Identity, error) {
+	registry := users
+	if registry == nil {
+		// Not loaded by an entry point (e.g. in tests): read the registry as configured now
+		var err error
+		if registry, err = identity.Load(identity.Path()); err != nil {
+			return identity.Identity{}, fmt.Errorf("failed to load identities: %w", err)
+		}
+	}
+	return registry.Lookup(user)
+}
 
-	testUsers = []struct {
-		name       string
-		privateKey string
-		address    string
-	}{
-		{"Alice", privateKeyVar, aliceAddr},
+// userAddress returns the address of the registered user on chain. Unknown users are an error
+func userAddress(user string, chain identity.Chain) (string, error) {
+	id, err := lookupUser(user)
+	if err != nil {
+		return "", err
 	}
+	return id.AddressOn(chain)
+}
+
+// userAddressOn returns the address of the registered user on networkName
+func userAddressOn(user, networkName string) (string, error) {
+	return userAddress(user, identityChain(networkName))
 }
 
-// loadNetworks loads network configuration from centralized config and environment variables
-func loadNetworks() []NetworkConfig {
-	// Initialize networks from centralized config after .env is loaded
-	config.InitializeNetworks()
+// identityChain maps a network to the chain type its addresses are registered under
+func identityChain(networkName string) identity.Chain {
+	switch GetNetworkType(networkName) {
+	case NetworkTypeStarknet:
+		return identity.Starknet
+	case NetworkTypeZtarknet:
+		return identity.Ztarknet
+	default:
+		return identity.EVM
+	}
+}
 
-	// Build networks from centralized config
-	networkNames := config.GetNetworkNames()
+// loadNetworks lists the networks of cfg, as loaded by config.LoadConfig
+func loadNetworks(cfg *config.Config) []NetworkConfig {
+	networkNames := cfg.NetworkNames()
 	networks := make([]NetworkConfig, 0, len(networkNames))
 
 	for _, networkName := range networkNames {
-		networkConfig := config.Networks[networkName]
-
-		// Determine dog coin address based on network type
-		var dogCoinAddr string
-		if networkName == starknetNetworkName {
-			dogCoinAddr = os.Getenv("STARKNET_DOG_COIN_ADDRESS")
-		} else if networkName == "Ztarknet" {
-			dogCoinAddr = os.Getenv("ZTARKNET_DOG_COIN_ADDRESS")
-		} else {
-			dogCoinAddr = os.Getenv(strings.ToUpper(networkName) + "_DOG_COIN_ADDRESS")
-		}
-
+		networkConfig := cfg.Networks[networkName]
 		networks = append(networks, NetworkConfig{
 			name:             networkConfig.Name,
-			url:              networkConfig.RPCURL,
+			urls:             networkConfig.RPCURLs,
 			chainID:          networkConfig.ChainID,
 			hyperlaneAddress: networkConfig.HyperlaneAddress,
-			dogCoinAddress:   dogCoinAddr,
 		})
 	}
 
 	return networks
 }
 
-// getHyperlaneDomain returns the Hyperlane domain ID for a given network
-func getHyperlaneDomain(networkName string) uint32 {
-	domain, err := config.GetHyperlaneDomain(networkName)
-	if err != nil {
-		return 0
-	}
-	return uint32(domain)
-}
-
 // RunEVMOrder creates an EVM order based on the command
-func RunEVMOrder(command string) {
+func RunEVMOrder(ctx context.Context, command string) {
 	//fmt.Println("🎯 Opening EVM order...")
 
 	// Load configuration (this loads .env and initializes networks)
-	_, err := config.LoadConfig()
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		failOrder("", "", fmt.Errorf("failed to load config: %w", err))
+		return
 	}
+	logutil.ConfigureFromConfig(logger, cfg)
 
-	// Initialize test users after .env is loaded
-	initializeTestUsers()
+	// Load the users orders are opened for after .env is loaded
+	if err := initializeUsers(); err != nil {
+		failOrder("", "", err)
+		return
+	}
 
 	// Load network configuration
-	networks := loadNetworks()
+	networks := loadNetworks(cfg)
 
 	switch command {
 	case "random-to-evm":
-		openRandomToEvm(networks)
+		openRandomToEvm(ctx, networks)
 	case "random-to-sn":
-		openRandomToStarknet(networks)
+		openRandomToStarknet(ctx, networks)
 	case "default-evm-evm":
-		openDefaultEvmToEvm(networks)
+		openDefaultEvmToEvm(ctx, networks)
 	case "default-evm-sn":
-		openDefaultEvmToStarknet(networks)
+		openDefaultEvmToStarknet(ctx, networks)
 	default:
 		// Default to random EVM order
-		openRandomToEvm(networks)
+		openRandomToEvm(ctx, networks)
 	}
 }
 
 // RunEVMOrderWithDest creates an EVM order with specific origin and destination
-func RunEVMOrderWithDest(command, originChain, destinationChain string) {
+func RunEVMOrderWithDest(ctx context.Context, command, originChain, destinationChain string) {
 	//	fmt.Printf("🎯 Running EVM order creation: %s → %s\n", originChain, destinationChain)
 
 	// Load configuration (this loads .env and initializes networks)
-	_, err := config.LoadConfig()
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		failOrder(originChain, destinationChain, fmt.Errorf("failed to load config: %w", err))
+		return
 	}
+	logutil.ConfigureFromConfig(logger, cfg)
 
-	// Initialize test users after .env is loaded
-	initializeTestUsers()
+	// Load the users orders are opened for after .env is loaded
+	if err := initializeUsers(); err != nil {
+		failOrder(originChain, destinationChain, err)
+		return
+	}
 
 	// Load network configuration
-	networks := loadNetworks()
+	networks := loadNetworks(cfg)
 
-	// Random amounts - ensure solver profitability
-	outputAmount := CreateTokenAmount(int64(secureRandomInt(maxTokenAmount-minTokenAmount+1)+minTokenAmount), tokenDecimals)
-	delta := CreateTokenAmount(int64(secureRandomInt(maxDeltaAmount-minDeltaAmount+1)+minDeltaAmount), tokenDecimals)
-	inputAmount := new(big.Int).Add(outputAmount, delta)
+	inputAmount, outputAmount := randomOrderAmounts()
 
 	order := OrderConfig{
 		OriginChain:      originChain,
 		DestinationChain: destinationChain,
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
 		InputAmount:      inputAmount,
 		OutputAmount:     outputAmount,
 		User:             AliceUserName,
-		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
 	}
 
-	executeOrder(&order, networks)
+	executeOrder(ctx, &order, networks)
+}
+
+// RunEVMToStarknetOrder opens an order from an EVM origin that is filled on Starknet
+func RunEVMToStarknetOrder(ctx context.Context, originChain string) {
+	if GetNetworkType(originChain) != NetworkTypeEVM {
+		failOrder(originChain, StarknetNetworkName, fmt.Errorf("evm-to-starknet requires an EVM origin, got %s", originChain))
+		return
+	}
+	RunEVMOrderWithDest(ctx, "custom", originChain, StarknetNetworkName)
 }
 
-func openRandomToEvm(networks []NetworkConfig) {
-	fmt.Println("Opening Random Test Order...")
+func openRandomToEvm(ctx context.Context, networks []NetworkConfig) {
+	logln("Opening Random Test Order...")
 
 	// Random origin and destination chains (exclude Starknet from origins)
 	var evmNetworks []NetworkConfig
@@ -358,43 +389,37 @@ func openRandomToEvm(networks []NetworkConfig) {
 		}
 	}
 	if len(evmNetworks) == 0 {
-		log.Fatalf("no EVM networks configured")
+		failOrder("", "", fmt.Errorf("no EVM networks configured"))
+		return
 	}
 
-	originIdx := secureRandomInt(len(evmNetworks))
-	destIdx := secureRandomInt(len(evmNetworks)) // Only pick from EVM networks for EVM-EVM orders
-	for destIdx == originIdx {
-		destIdx = (originIdx + 1) % len(evmNetworks) // Ensure different EVM chain
+	origin := evmNetworks[randomInt(len(evmNetworks))].name
+	destination, err := selectDestination(origin, "evm") // Only pick from EVM networks for EVM-EVM orders
+	if err != nil {
+		failOrder(origin, "", err)
+		return
 	}
 
 	// Always use Alice for orders
 	user := AliceUserName
 
-	// Random amounts (100-10000 tokens) - ensure solver profitability
-	// Alice provides InputAmount, receives OutputAmount
-	// Solver receives InputAmount (MinReceived), provides OutputAmount (MaxSpent)
-	// For profitability: InputAmount (MinReceived) > OutputAmount (MaxSpent)
-	outputAmount := CreateTokenAmount(int64(secureRandomInt(maxTokenAmount-minTokenAmount+1)+minTokenAmount), tokenDecimals) // 100-10000 tokens (what solver provides)
-	delta := CreateTokenAmount(int64(secureRandomInt(maxDeltaAmount-minDeltaAmount+1)+minDeltaAmount), tokenDecimals)        // 1-10 tokens profit margin
-	inputAmount := new(big.Int).Add(outputAmount, delta)                                                                     // slightly more to ensure solver profit
+	inputAmount, outputAmount := randomOrderAmounts()
 
 	order := OrderConfig{
-		OriginChain:      evmNetworks[originIdx].name,
-		DestinationChain: evmNetworks[destIdx].name,
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
+		OriginChain:      origin,
+		DestinationChain: destination,
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
 		InputAmount:      inputAmount,
 		OutputAmount:     outputAmount,
 		User:             user,
-		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
 	}
 
-	executeOrder(&order, networks)
+	executeOrder(ctx, &order, networks)
 }
 
-func openRandomToStarknet(networks []NetworkConfig) {
-	fmt.Println("Opening Random EVM → Starknet Test Order...")
+func openRandomToStarknet(ctx context.Context, networks []NetworkConfig) {
+	logln("Opening Random EVM → Starknet Test Order...")
 
 	// Pick random EVM origin (exclude Starknet)
 	var evmNetworks []NetworkConfig
@@ -404,71 +429,67 @@ func openRandomToStarknet(networks []NetworkConfig) {
 		}
 	}
 	if len(evmNetworks) == 0 {
-		log.Fatalf("no EVM networks configured")
+		failOrder("", "", fmt.Errorf("no EVM networks configured"))
+		return
 	}
-	origin := evmNetworks[secureRandomInt(len(evmNetworks))]
+	origin := evmNetworks[randomInt(len(evmNetworks))]
 
-	// Random amounts - ensure solver profitability
-	// Alice provides InputAmount, receives OutputAmount
-	// Solver receives InputAmount (MinReceived), provides OutputAmount (MaxSpent)
-	// For profitability: InputAmount (MinReceived) > OutputAmount (MaxSpent)
-	outputAmount := CreateTokenAmount(int64(secureRandomInt(maxTokenAmount-minTokenAmount+1)+minTokenAmount), tokenDecimals) // 100-10000 tokens (what solver provides)
-	delta := big.NewInt(int64(secureRandomInt(maxDeltaAmount-minDeltaAmount+1) + minDeltaAmount))                            // 1-10 tokens profit margin
-	inputAmount := new(big.Int).Add(outputAmount, delta)                                                                     // slightly more to ensure solver profit
+	inputAmount, outputAmount := randomOrderAmounts()
 
 	order := OrderConfig{
 		OriginChain:      origin.name,
 		DestinationChain: starknetNetworkName,
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
 		InputAmount:      inputAmount,
 		OutputAmount:     outputAmount,
 		User:             AliceUserName,
-		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
 	}
 
-	executeOrder(&order, networks)
+	executeOrder(ctx, &order, networks)
 }
 
-func openDefaultEvmToEvm(networks []NetworkConfig) {
-	fmt.Println("Opening Default EVM → EVM Test Order...")
+func openDefaultEvmToEvm(ctx context.Context, networks []NetworkConfig) {
+	logln("Opening Default EVM → EVM Test Order...")
 
 	order := OrderConfig{
 		OriginChain:      "Ethereum",
 		DestinationChain: "Optimism",
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
 		InputAmount:      CreateTokenAmount(testInputAmount, tokenDecimals), // 1001 tokens (what solver receives)
 		OutputAmount:     CreateTokenAmount(1000, 18),                       // 1000 tokens (what solver provides)
 		User:             AliceUserName,
-		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
 	}
 
-	executeOrder(&order, networks)
+	executeOrder(ctx, &order, networks)
 }
 
-func openDefaultEvmToStarknet(networks []NetworkConfig) {
-	fmt.Println("Opening Default EVM → Starknet Test Order...")
+func openDefaultEvmToStarknet(ctx context.Context, networks []NetworkConfig) {
+	logln("Opening Default EVM → Starknet Test Order...")
 
 	order := OrderConfig{
 		OriginChain:      "Ethereum",
 		DestinationChain: starknetNetworkName,
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
 		InputAmount:      CreateTokenAmount(testInputAmount, tokenDecimals), // 1001 tokens (what solver receives)
 		OutputAmount:     CreateTokenAmount(1000, 18),                       // 1000 tokens (what solver provides)
 		User:             AliceUserName,
-		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
 	}
 
-	executeOrder(&order, networks)
+	executeOrder(ctx, &order, networks)
+}
+
+// executeOrder opens a single EVM order and reports the result
+func executeOrder(ctx context.Context, order *OrderConfig, networks []NetworkConfig) {
+	result := newOrderResult(order.OriginChain, order.DestinationChain, order.InputAmount, order.OutputAmount)
+	finishOrder(result, openEVMOrder(ctx, order, networks, result))
 }
 
-func executeOrder(order *OrderConfig, networks []NetworkConfig) {
-	fmt.Printf("\nOpening Order: %s → %s\n", order.OriginChain, order.DestinationChain)
+// openEVMOrder approves (if needed) and opens the order, filling in result as it goes
+func openEVMOrder(ctx context.Context, order *OrderConfig, networks []NetworkConfig, result *OrderResult) error {
+	logf("\nOpening Order: %s → %s\n", order.OriginChain, order.DestinationChain)
 
 	// Find origin network
 	var originNetwork *NetworkConfig
@@ -480,46 +501,29 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	}
 
 	if originNetwork == nil {
-		log.Fatalf("Origin network not found: %s", order.OriginChain)
-	}
-
-	// Get user private key using conditional environment variable logic
-	var userKey string
-	isDevnet := os.Getenv("IS_DEVNET") == "true"
-	if isDevnet {
-		userKey = os.Getenv(fmt.Sprintf("LOCAL_%s_PRIVATE_KEY", strings.ToUpper(order.User)))
-	} else {
-		userKey = os.Getenv(fmt.Sprintf("%s_PRIVATE_KEY", strings.ToUpper(order.User)))
-	}
-	if userKey == "" {
-		log.Fatalf("Private key not found for user: %s (IS_DEVNET=%s)", order.User, os.Getenv("IS_DEVNET"))
+		return fmt.Errorf("origin network not found: %s", order.OriginChain)
 	}
-
-	// Parse private key
-	privateKey, err := ethutil.ParsePrivateKey(userKey)
-	if err != nil {
-		log.Fatalf("Failed to parse private key for %s: %v", order.User, err)
+	if err := checkOriginSettler(originNetwork); err != nil {
+		return err
 	}
+	ctx = txcost.WithNetwork(ctx, originNetwork.name)
 
-	// Create auth
-	auth, err := ethutil.NewTransactor(big.NewInt(int64(originNetwork.chainID)), privateKey)
+	// Only sending needs the user's key; a dry run simulates as Alice's address
+	submitter, err := newEVMSubmitter(order.User, originNetwork.chainID)
 	if err != nil {
-		log.Fatalf("Failed to create auth: %v", err)
+		return err
 	}
 
 	// Connect to origin network
-	client, err := ethclient.Dial(originNetwork.url)
+	client, err := clients().EVMFailover(ctx, order.OriginChain, originNetwork.urls)
 	if err != nil {
-		log.Fatalf("Failed to connect to %s: %v", order.OriginChain, err)
+		return fmt.Errorf("failed to connect to %s: %w", order.OriginChain, err)
 	}
 
-	// Get current gas price
-	gasPrice, err := ethutil.SuggestGas(client)
-	if err != nil {
-		client.Close()
-		log.Fatalf("Failed to get gas price: %v", err)
+	// Deadlines run from the origin's block time, which on a fork can be far from this machine's clock
+	if err := stampEVMDeadlines(ctx, client, order, deadlineWindows); err != nil {
+		return err
 	}
-	auth.GasPrice = gasPrice
 
 	// Find destination network (check all networks, including Starknet)
 	var destinationNetwork *NetworkConfig
@@ -532,296 +536,281 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 		}
 	}
 
-	// If not found in EVM networks, check if it's Starknet or Ztarknet
-	if destinationNetwork == nil && order.DestinationChain == starknetNetworkName {
-		// Create NetworkConfig for Starknet destination
-		starknetConfig := config.Networks[starknetNetworkName]
-		destinationNetwork = &NetworkConfig{
-			name:             starknetNetworkName,
-			url:              starknetConfig.RPCURL,
-			chainID:          starknetConfig.ChainID,
-			hyperlaneAddress: starknetConfig.HyperlaneAddress,
-			dogCoinAddress:   os.Getenv("STARKNET_DOG_COIN_ADDRESS"), // From env
+	// If not found in EVM networks, check the Cairo networks (Starknet and Ztarknet)
+	if destinationNetwork == nil && config.IsStarknetNetwork(order.DestinationChain) {
+		cairoConfig, err := config.GetNetworkConfig(order.DestinationChain)
+		if err != nil {
+			return err
 		}
-	}
-
-	if destinationNetwork == nil && order.DestinationChain == "Ztarknet" {
-		// Create NetworkConfig for Ztarknet destination
-		ztarknetConfig := config.Networks["Ztarknet"]
 		destinationNetwork = &NetworkConfig{
-			name:             "Ztarknet",
-			url:              ztarknetConfig.RPCURL,
-			chainID:          ztarknetConfig.ChainID,
-			hyperlaneAddress: ztarknetConfig.HyperlaneAddress,
-			dogCoinAddress:   os.Getenv("ZTARKNET_DOG_COIN_ADDRESS"), // From env
+			name:             cairoConfig.Name,
+			urls:             cairoConfig.RPCURLs,
+			chainID:          cairoConfig.ChainID,
+			hyperlaneAddress: cairoConfig.HyperlaneAddress,
 		}
 	}
 
 	if destinationNetwork == nil {
-		log.Fatalf("Destination network not found: %s", order.DestinationChain)
+		return fmt.Errorf("destination network not found: %s", order.DestinationChain)
+	}
+	destinationNetwork, err = withDestinationSettler(destinationNetwork, originNetwork)
+	if err != nil {
+		return err
 	}
 
 	// Read localDomain from the origin Hyperlane contract to guarantee it matches on-chain
-	localDomain, err := getLocalDomain(client, common.HexToAddress(originNetwork.hyperlaneAddress))
+	localDomain, err := getLocalDomain(ctx, client, common.HexToAddress(originNetwork.hyperlaneAddress))
 	if err != nil {
-		client.Close()
-		log.Fatalf("Failed to read localDomain from origin contract: %v", err)
+		return fmt.Errorf("failed to read localDomain from origin contract: %w", err)
 	}
 
-	// Preflight: balances and allowances on origin for input token
-	inputTokenStr := originNetwork.dogCoinAddress
-	inputTokenAddr := common.HexToAddress(inputTokenStr)
-	owner := auth.From
-	spender := common.HexToAddress(originNetwork.hyperlaneAddress)
+	// Resolve the token pair on both chains and scale the amounts to their decimals
+	if err := order.resolveTokens(ctx, result); err != nil {
+		return err
+	}
+	inputDecimals := order.tokens.Input.Decimals
 
-	// Get initial balances
-	initialUserBalance, err := ethutil.ERC20Balance(client, inputTokenAddr, owner)
-	if err == nil {
-		fmt.Printf("   Initial InputToken balance(owner): %s\n", initialUserBalance.String())
-	} else {
-		fmt.Printf("   ⚠️  Could not read initial balance: %v\n", err)
+	// Pick a fresh senderNonce recognized by the contract to avoid InvalidNonce
+	senderNonce, err := pickValidSenderNonce(ctx, client, common.HexToAddress(originNetwork.hyperlaneAddress), submitter.from())
+	if err != nil {
+		return fmt.Errorf("failed to pick a valid sender nonce: %w", err)
 	}
+	result.SenderNonce = senderNonce.String()
 
-	initialHyperlaneBalance, err := ethutil.ERC20Balance(client, inputTokenAddr, spender)
-	if err == nil {
-		fmt.Printf("   Initial InputToken balance(hyperlane): %s\n", initialHyperlaneBalance.String())
-	} else {
-		fmt.Printf("   ⚠️  Could not read initial hyperlane balance: %v\n", err)
+	// Build the order data before anything is sent, so an unresolvable destination aborts without an approval
+	orderData, err := buildOrderData(order, order.tokens, destinationNetwork, localDomain, senderNonce)
+	if err != nil {
+		return fmt.Errorf("failed to build order data: %w", err)
 	}
 
-	// Check if Alice has sufficient tokens for the order
-	requiredAmount := order.InputAmount
-	if initialUserBalance == nil || initialUserBalance.Cmp(requiredAmount) < 0 {
-		fmt.Printf("   ⚠️  Insufficient balance! Alice needs %s tokens but has %s\n",
-			ethutil.FormatTokenAmount(requiredAmount, 18),
-			ethutil.FormatTokenAmount(initialUserBalance, 18))
-		fmt.Printf("   ⚠️  Please mint tokens manually using the MockERC20 contract's mint() function\n")
-		fmt.Printf("   ⚠️  Contract address: %s\n", inputTokenStr)
-		fmt.Printf("   ⚠️  Call: mint(\"%s\", \"%s\")\n", owner.Hex(), requiredAmount.String())
-		client.Close()
-		log.Fatalf("Insufficient token balance for order creation")
-	} else {
-		fmt.Printf("   Alice has sufficient tokens (%s)\n", ethutil.FormatTokenAmount(initialUserBalance, 18))
+	// Build the OnchainCrossChainOrder
+	crossChainOrder, err := newOnchainOrder(&orderData, senderNonce)
+	if err != nil {
+		return fmt.Errorf("failed to encode order data: %w", err)
 	}
+	result.recordOrder(uint64(crossChainOrder.FillDeadline), crossChainOrder.OrderDataType, crossChainOrder.OrderData)
+
+	// Preflight: balances and allowances on origin for input token
+	inputTokenStr := order.tokens.Input.Address
+	inputTokenAddr := common.HexToAddress(inputTokenStr)
+	owner := submitter.from()
+	spender := common.HexToAddress(originNetwork.hyperlaneAddress)
 
-	// Check allowance
-	allowance, err := ethutil.ERC20Allowance(client, inputTokenAddr, owner, spender)
+	// Get initial balances
+	requiredAmount := order.InputAmount
+	initialUserBalance, balanceState, err := readFunds(ctx, "balance", evmBalance(client, inputTokenAddr, owner), requiredAmount)
 	if err == nil {
-		fmt.Printf("   Current allowance(owner->hyperlane): %s\n", allowance.String())
+		logf("   Initial InputToken balance(owner): %s (%s)\n", initialUserBalance.String(), balanceState)
 	} else {
-		fmt.Printf("   ⚠️  Could not read allowance: %v\n", err)
+		warnf("   ⚠️  Could not read initial balance: %v\n", err)
 	}
 
-	// If allowance is insufficient, approve the Hyperlane contract
-	requiredAmount = order.InputAmount
-	if allowance.Cmp(requiredAmount) < 0 {
-		fmt.Printf("   Insufficient allowance, approving %s tokens...\n", requiredAmount.String())
-
-		// Approve the Hyperlane contract to spend the required amount
-		approveTx, err := ethutil.ERC20Approve(client, auth, inputTokenAddr, spender, requiredAmount)
-		if err != nil {
-			client.Close()
-			log.Fatalf("Failed to approve tokens: %v", err)
-		}
-
-		fmt.Printf("   Approval transaction sent: %s\n", approveTx.Hash().Hex())
-
-		// Wait for approval transaction to be mined
-		fmt.Printf("   ⏳ Waiting for approval confirmation...\n")
-		receipt, err := ethutil.WaitForTransaction(client, approveTx)
-		if err != nil {
-			client.Close()
-			log.Fatalf("Failed to wait for approval transaction: %v", err)
-		}
-
-		if receipt.Status != 1 {
-			client.Close()
-			log.Fatalf("Approval transaction failed")
-		}
-
-		fmt.Printf("   Approval confirmed!\n")
+	initialHyperlaneBalance, err := ethutil.ERC20BalanceAt(ctx, client, inputTokenAddr, spender, nil)
+	if err == nil {
+		logf("   Initial InputToken balance(hyperlane): %s\n", initialHyperlaneBalance.String())
 	} else {
-		fmt.Printf("   Sufficient allowance already exists\n")
+		warnf("   ⚠️  Could not read initial hyperlane balance: %v\n", err)
 	}
 
-	// Pick a fresh senderNonce recognized by the contract to avoid InvalidNonce
-	senderNonce, err := pickValidSenderNonce(client, common.HexToAddress(originNetwork.hyperlaneAddress), auth.From)
+	// Gate on balance and allowance before sending anything
+	if initialUserBalance == nil {
+		return fmt.Errorf("failed to read %s balance of %s", inputTokenStr, owner.Hex())
+	}
+	allowance, allowanceState, err := readFunds(ctx, "allowance", evmAllowance(client, inputTokenAddr, owner, spender), requiredAmount)
 	if err != nil {
-		client.Close()
-		log.Fatalf("Failed to pick a valid sender nonce: %v", err)
+		return fmt.Errorf("failed to read allowance: %w", err)
+	}
+	logf("   Current allowance(owner->hyperlane): %s (%s)\n", allowance.String(), allowanceState)
+
+	needsApproval, err := preflightFunds(fundsCheck{
+		Token:     inputTokenStr,
+		Owner:     owner.Hex(),
+		Spender:   spender.Hex(),
+		Decimals:  inputDecimals,
+		Balance:   initialUserBalance,
+		Allowance: allowance,
+		Need:      requiredAmount,
+		Permit:    usePermit,
+	})
+	var shortfall *FundsShortfallError
+	if errors.As(err, &shortfall) && shortfall.Check == "balance" {
+		warnf("   ⚠️  Please mint tokens manually using the MockERC20 contract's mint() function\n")
+		warnf("   ⚠️  Contract address: %s\n", inputTokenStr)
+		warnf("   ⚠️  Call: mint(\"%s\", \"%s\")\n", owner.Hex(), shortfall.Shortfall().String())
 	}
-
-	// Build the order data
-	orderData := buildOrderData(order, originNetwork, destinationNetwork, localDomain, senderNonce)
-
-	// Build the OnchainCrossChainOrder
-	crossChainOrder := OnchainCrossChainOrder{
-		FillDeadline:  order.FillDeadline,
-		OrderDataType: getOrderDataTypeHash(),
-		OrderData:     encodeOrderData(&orderData, senderNonce, networks),
+	if err := dryRunPreflight(err); err != nil {
+		return err
 	}
-
-	// Debug: Log the encoded data
-	// fmt.Printf("🔍 Encoded Order Data Debug:\n")
-	// fmt.Printf("   • FillDeadline: %d\n", crossChainOrder.FillDeadline)
-	// fmt.Printf("   • OrderDataType: %x\n", crossChainOrder.OrderDataType)
-	// fmt.Printf("   • OrderData length: %d bytes\n", len(crossChainOrder.OrderData))
+	logf("   Alice has sufficient tokens (%s)\n", ethutil.FormatTokenAmount(initialUserBalance, inputDecimals))
 
 	// Use generated bindings for open()
 	contract, err := contracts.NewHyperlane7683(common.HexToAddress(originNetwork.hyperlaneAddress), client)
 	if err != nil {
-		client.Close()
-		log.Fatalf("Failed to bind Hyperlane7683: %v", err)
+		return fmt.Errorf("failed to bind Hyperlane7683: %w", err)
 	}
 
-	tx, err := contract.Open(auth, contracts.OnchainCrossChainOrder{
-		FillDeadline:  crossChainOrder.FillDeadline,
-		OrderDataType: crossChainOrder.OrderDataType,
-		OrderData:     crossChainOrder.OrderData,
-	})
-	if err != nil {
-		client.Close()
-		log.Fatalf("Failed to send open transaction: %v", err)
+	hyperlane := common.HexToAddress(originNetwork.hyperlaneAddress)
+	if err := verifyEVMOrderDataType(ctx, contract, order.OriginChain, hyperlane, crossChainOrder); err != nil {
+		return err
 	}
 
-	fmt.Printf("   Transaction sent: %s\n", tx.Hash().Hex())
-	fmt.Printf("   ⏳ Waiting for confirmation...\n")
-
-	// Wait for transaction confirmation
-	receipt, err := ethutil.WaitForTransaction(client, tx)
+	// Have the contract resolve the exact order before anything is sent, and stop if it is not the one intended
+	intent := evmIntent(order, &orderData, localDomain, crossChainOrder)
+	orderID, err := checkEVMResolution(ctx, contract, order.OriginChain+" "+hyperlane.Hex(), owner, crossChainOrder, intent)
 	if err != nil {
-		client.Close()
-		log.Fatalf("Failed to wait for transaction confirmation: %v", err)
+		return err
 	}
+	result.OrderID = orderID.Hex()
 
-	defer client.Close()
-
-	if receipt.Status == 1 {
-		fmt.Printf("✅ Order opened successfully!\n")
-		fmt.Printf("📊 Gas used: %d\n", receipt.GasUsed)
-	} else {
-		fmt.Printf("❌ Order opening failed\n")
-		fmt.Printf("🔍 Transaction hash: %s\n", tx.Hash().Hex())
-		fmt.Printf("📊 Gas used: %d\n", receipt.GasUsed)
-
-		// Try to get more details about the failure
-		fmt.Printf("   🔍 Checking transaction details...\n")
-		txDetails, _, err := client.TransactionByHash(context.Background(), tx.Hash())
-		if err != nil {
-			fmt.Printf("❌ Could not retrieve transaction details: %v\n", err)
-		} else {
-			fmt.Printf("📝 Transaction data: 0x%x\n", txDetails.Data())
+	if needsApproval {
+		if err := allowanceSetter(ctx, submitter, client, inputTokenAddr, spender, requiredAmount); err != nil {
+			return err
 		}
+	} else {
+		logf("   Sufficient allowance already exists\n")
 	}
 
-	fmt.Printf("\n🎉 Order execution completed!\n")
-	fmt.Printf("   Order Summary:\n")
-	fmt.Printf("   Input Amount: %s\n", order.InputAmount.String())
-	fmt.Printf("   Output Amount: %s\n", order.OutputAmount.String())
-	fmt.Printf("   Origin Chain: %s\n", order.OriginChain)
-	fmt.Printf("   Destination Chain: %s\n", order.DestinationChain)
+	quoteGasPayment(ctx, contract, uint32(orderData.DestinationChainID.Uint64()), result)
+
+	// Submission is the final step: send open() or, with --dry-run, simulate it
+	if err := submitter.open(ctx, evmOpen{
+		client:    client,
+		contract:  contract,
+		hyperlane: hyperlane,
+		order:     crossChainOrder,
+		value:     nativeInputValue(&orderData),
+		expected:  expectedOpen{intent: intent, user: owner, fillDeadline: crossChainOrder.FillDeadline},
+	}, result); err != nil {
+		return err
+	}
+	if !dryRun {
+		printOrderSummary(order.OriginChain, order.DestinationChain, order.tokens, order.InputAmount, order.OutputAmount)
+	}
+	return nil
 }
 
-func buildOrderData(order *OrderConfig, originNetwork, destinationNetwork *NetworkConfig, originDomain uint32, _ *big.Int) OrderData {
-	// Input token from origin network, output token from destination network
-	// inputTokenAddr := originNetwork.dogCoinAddress
-	// outputTokenAddr := destinationNetwork.dogCoinAddress
-
-	// Convert destination settler depending on destination chain type
-	var destSettlerBytes [32]byte
-	if destinationNetwork.name == starknetNetworkName {
-		// Use Starknet Hyperlane address from config (.env) as raw 32 bytes (felt)
-		if snNetwork, exists := config.Networks[starknetNetworkName]; exists {
-			starknetHyperlaneAddr := os.Getenv("STARKNET_HYPERLANE_ADDRESS")
-			if starknetHyperlaneAddr != "" {
-				destSettlerBytes = hexToBytes32(starknetHyperlaneAddr)
-			} else if snNetwork.HyperlaneAddress != "" {
-				destSettlerBytes = hexToBytes32(snNetwork.HyperlaneAddress)
-			}
-		}
-		if destSettlerBytes == ([32]byte{}) {
-			log.Printf("⚠️  Starknet Hyperlane address not found in config; destinationSettler will be zero")
-		}
-	} else if destinationNetwork.name == "Ztarknet" {
-		// Use Ztarknet Hyperlane address from config (.env) as raw 32 bytes (felt)
-		if ztarknetNetwork, exists := config.Networks["Ztarknet"]; exists {
-			ztarknetHyperlaneAddr := os.Getenv("ZTARKNET_HYPERLANE_ADDRESS")
-			if ztarknetHyperlaneAddr != "" {
-				destSettlerBytes = hexToBytes32(ztarknetHyperlaneAddr)
-			} else if ztarknetNetwork.HyperlaneAddress != "" {
-				destSettlerBytes = hexToBytes32(ztarknetNetwork.HyperlaneAddress)
-			}
-		}
-		if destSettlerBytes == ([32]byte{}) {
-			log.Printf("⚠️  Ztarknet Hyperlane address not found in config; destinationSettler will be zero")
-		}
-	} else {
-		// EVM router is 20-byte address left-padded to 32
-		destSettler := common.HexToAddress(destinationNetwork.hyperlaneAddress)
-		copy(destSettlerBytes[12:], destSettler.Bytes())
-	}
-
-	// Get the destination chain ID (Hyperlane domain)
-	destinationChainID := getHyperlaneDomain(destinationNetwork.name)
-
-	// Build proper OrderData with actual token amounts
-	// Map token names to actual addresses
-	inputTokenAddr := originNetwork.dogCoinAddress
-
-	// Determine Recipient based on destination network
-	var recipient string
-	if destinationNetwork.name == starknetNetworkName {
-		// Use Starknet Alice address
-		recipient = envutil.GetStarknetAliceAddress()
-	} else if destinationNetwork.name == "Ztarknet" {
-		// Use Ztarknet Alice address
-		recipient = envutil.GetZtarknetAliceAddress()
-	} else {
-		// For EVM destinations, use the sender's EVM address (Alice)
-		// Get the actual user address from testUsers array
-		for _, user := range testUsers {
-			if user.name == order.User {
-				recipient = user.address
-				break
-			}
-		}
+// buildOrderData assembles the order for the resolved tokens: the solver spends the output token on the
+// destination and receives the input token on the origin
+func buildOrderData(order *OrderConfig, tokens *orderTokens, destinationNetwork *NetworkConfig, originDomain uint32, _ *big.Int) (OrderData, error) {
+	// An unset or zero token would open an order nobody can fill, so stop before encoding anything
+	if err := tokens.check(); err != nil {
+		return OrderData{}, err
+	}
+
+	// Get the destination chain ID (Hyperlane domain); an unknown, unset or origin domain makes the order unroutable
+	destinationChainID, err := config.DestinationDomain(originDomain, destinationNetwork.name)
+	if err != nil {
+		return OrderData{}, fmt.Errorf("invalid destination %s: %w", destinationNetwork.name, err)
+	}
+
+	words, err := resolveDestinationWords(destinationNetwork, tokens.Output, order.User)
+	if err != nil {
+		return OrderData{}, err
 	}
 
 	// For cross-chain orders:
 	// - MaxSpent: What the solver needs to provide (destination chain tokens)
 	// - MinReceived: What the solver will receive (origin chain tokens)
-	var maxSpent, minReceived []TokenAmount
-
-	// Set up token amounts (same for both EVM→Starknet and EVM→EVM orders)
-	maxSpent = []TokenAmount{
+	maxSpent := []TokenAmount{
 		{
-			Token:   destinationNetwork.dogCoinAddress,       // Destination chain token (string)
+			Token:   tokens.Output.Address,                   // Destination chain token (string)
 			Amount:  uint256.MustFromBig(order.OutputAmount), // Amount solver needs to provide
 			ChainID: big.NewInt(int64(destinationChainID)),   // Destination chain ID
 		},
 	}
-	minReceived = []TokenAmount{
+	minReceived := []TokenAmount{
 		{
-			Token:   inputTokenAddr,                         // Origin chain token (string)
+			Token:   tokens.Input.Address,                   // Origin chain token (string)
 			Amount:  uint256.MustFromBig(order.InputAmount), // Amount solver will receive
 			ChainID: big.NewInt(int64(originDomain)),        // Origin chain ID
 		},
 	}
 
 	return OrderData{
-		OriginChainID:      big.NewInt(int64(originDomain)),
-		DestinationChainID: big.NewInt(int64(destinationChainID)),
-		User:               order.User,
-		Recipient:          recipient,
-		OpenDeadline:       big.NewInt(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:       big.NewInt(int64(order.FillDeadline)),
-		MaxSpent:           maxSpent,
-		MinReceived:        minReceived,
+		OriginChainID:          big.NewInt(int64(originDomain)),
+		DestinationChainID:     big.NewInt(int64(destinationChainID)),
+		User:                   order.User,
+		Recipient:              words.recipientHex,
+		Deadlines:              order.Deadlines,
+		MaxSpent:               maxSpent,
+		MinReceived:            minReceived,
+		RecipientWord:          words.recipient,
+		OutputTokenWord:        words.outputToken,
+		DestinationSettlerWord: words.destinationSettler,
+	}, nil
+}
+
+// destinationWords are the destination-side OrderData fields encoded as bytes32
+type destinationWords struct {
+	recipientHex       string
+	recipient          [32]byte
+	outputToken        [32]byte
+	destinationSettler [32]byte
+}
+
+// resolveDestinationWords encodes recipient, output token and destination settler as words of the destination
+// network. The order pays out to user's own registered address on the destination
+func resolveDestinationWords(destinationNetwork *NetworkConfig, outputToken orderToken, user string) (destinationWords, error) {
+	outputTokenWord, err := outputToken.word()
+	if err != nil {
+		return destinationWords{}, err
 	}
+	recipient, err := userAddressOn(user, destinationNetwork.name)
+	if err != nil {
+		return destinationWords{}, fmt.Errorf("failed to resolve the recipient: %w", err)
+	}
+
+	recipientWord, err := addressWord(destinationNetwork.name, fmt.Sprintf("%s's %s address", user, destinationNetwork.name), recipient)
+	if err != nil {
+		return destinationWords{}, err
+	}
+	settlerWord, err := addressWord(destinationNetwork.name, strings.ToUpper(destinationNetwork.name)+"_HYPERLANE_ADDRESS", destinationNetwork.hyperlaneAddress)
+	if err != nil {
+		return destinationWords{}, err
+	}
+	return destinationWords{
+		recipientHex:       recipient,
+		recipient:          recipientWord,
+		outputToken:        outputTokenWord,
+		destinationSettler: settlerWord,
+	}, nil
+}
+
+// addressWord validates hexStr as a non-zero address of networkName and returns it as its bytes32 OrderData word
+// (see chain.Network). name says where hexStr came from
+func addressWord(networkName, name, hexStr string) ([32]byte, error) {
+	word, err := chain.Of(networkName).AddressToBytes32(hexStr)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("%s %w", name, err)
+	}
+	return word, nil
+}
+
+// sendOpenTransaction sends open(order) through ethutil.SendTx, which estimates the gas limit and prices the
+// transaction as EIP-1559 on chains with a base fee. A would-be revert comes back decoded before anything is broadcast
+func sendOpenTransaction(ctx context.Context, client *ethclient.Client, opts *bind.TransactOpts, hyperlane common.Address, order contracts.OnchainCrossChainOrder) (*gethtypes.Transaction, error) {
+	data, err := packOpen(order)
+	if err != nil {
+		return nil, err
+	}
+	return ethutil.SendTx(ctx, client, opts, hyperlane, opts.Value, data)
+}
+
+// packOpen ABI-encodes the open(order) call
+func packOpen(order contracts.OnchainCrossChainOrder) ([]byte, error) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Hyperlane7683 ABI: %w", err)
+	}
+	data, err := parsed.Pack("open", order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack open: %w", err)
+	}
+	return data, nil
 }
 
 // getLocalDomain reads the `localDomain()` from the Hyperlane7683 contract on the connected chain
-func getLocalDomain(client *ethclient.Client, contractAddress common.Address) (uint32, error) {
+func getLocalDomain(ctx context.Context, client *ethclient.Client, contractAddress common.Address) (uint32, error) {
 	abiStr := `[{"inputs":[],"name":"localDomain","outputs":[{"internalType":"uint32","name":"","type":"uint32"}],"stateMutability":"view","type":"function"}]`
 
 	parsedABI, err := abi.JSON(strings.NewReader(abiStr))
@@ -848,7 +837,7 @@ func getLocalDomain(client *ethclient.Client, contractAddress common.Address) (u
 		BlobHashes:        nil,
 		AuthorizationList: nil,
 	}
-	result, err := client.CallContract(context.Background(), msg, nil)
+	result, err := client.CallContract(ctx, msg, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -862,7 +851,7 @@ func getLocalDomain(client *ethclient.Client, contractAddress common.Address) (u
 }
 
 // isValidNonce calls the contract to check whether a nonce is usable for a given address
-func isValidNonce(client *ethclient.Client, contractAddress, from common.Address, nonce *big.Int) (bool, error) {
+func isValidNonce(ctx context.Context, client *ethclient.Client, contractAddress, from common.Address, nonce *big.Int) (bool, error) {
 	abiStr := `[{"inputs":[{"internalType":"address","name":"_from","type":"address"},{"internalType":"uint256","name":"_nonce","type":"uint256"}],"name":"isValidNonce","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"}]`
 
 	parsedABI, err := abi.JSON(strings.NewReader(abiStr))
@@ -889,7 +878,7 @@ func isValidNonce(client *ethclient.Client, contractAddress, from common.Address
 		BlobHashes:        nil,
 		AuthorizationList: nil,
 	}
-	result, err := client.CallContract(context.Background(), msg, nil)
+	result, err := client.CallContract(ctx, msg, nil)
 	if err != nil {
 		return false, err
 	}
@@ -901,27 +890,6 @@ func isValidNonce(client *ethclient.Client, contractAddress, from common.Address
 	return valid, nil
 }
 
-// pickValidSenderNonce finds a nonce that the contract reports as valid for the provided sender
-func pickValidSenderNonce(client *ethclient.Client, contractAddress, from common.Address) (*big.Int, error) {
-	// Start with a pseudo-random seed and probe upward
-	seed := time.Now().Unix() % 1_000_000
-	if seed < 1 {
-		seed = 1
-	}
-	nonce := big.NewInt(seed)
-	for i := 0; i < 1000; i++ {
-		valid, err := isValidNonce(client, contractAddress, from, nonce)
-		if err != nil {
-			return nil, err
-		}
-		if valid {
-			return new(big.Int).Set(nonce), nil
-		}
-		nonce = new(big.Int).Add(nonce, big.NewInt(1))
-	}
-	return nil, fmt.Errorf("could not find a valid sender nonce after 1000 attempts starting from %d", seed)
-}
-
 func getOrderDataTypeHash() [32]byte {
 	// This should match OrderEncoder.orderDataType() from Solidity EXACTLY
 	// Including field names and spacing
@@ -930,27 +898,29 @@ func getOrderDataTypeHash() [32]byte {
 	return hash
 }
 
-// hexToBytes32 converts a hex string to bytes32, handling both EVM and Starknet addresses
-func hexToBytes32(hexStr string) [32]byte {
-	s := strings.TrimPrefix(hexStr, "0x")
-	if len(s)%2 == 1 {
-		s = "0" + s
-	}
-	b, err := hex.DecodeString(s)
+// newOnchainOrder builds the OnchainCrossChainOrder passed to open(), with the fill deadline checked to fit uint32
+func newOnchainOrder(orderData *OrderData, senderNonce *big.Int) (contracts.OnchainCrossChainOrder, error) {
+	fillDeadline, err := orderData.Deadlines.Fill.Uint32()
 	if err != nil {
-		log.Fatalf("invalid hex for bytes32: %s (%v)", hexStr, err)
+		return contracts.OnchainCrossChainOrder{}, fmt.Errorf("invalid fill deadline: %w", err)
 	}
-	if len(b) > 32 {
-		b = b[len(b)-32:]
+	encoded, err := encodeOrderData(orderData, senderNonce)
+	if err != nil {
+		return contracts.OnchainCrossChainOrder{}, err
 	}
-	var out [32]byte
-	copy(out[32-len(b):], b)
-	return out
+	return contracts.OnchainCrossChainOrder{
+		FillDeadline:  fillDeadline,
+		OrderDataType: getOrderDataTypeHash(),
+		OrderData:     encoded,
+	}, nil
 }
 
-func encodeOrderData(orderData *OrderData, senderNonce *big.Int, networks []NetworkConfig) []byte {
+func encodeOrderData(orderData *OrderData, senderNonce *big.Int) ([]byte, error) {
 	// Convert OrderData to ABIOrderData for encoding
-	abiOrderData := convertToABIOrderData(orderData, senderNonce, networks)
+	abiOrderData, err := convertToABIOrderData(orderData, senderNonce)
+	if err != nil {
+		return nil, err
+	}
 
 	// Pack as a tuple to match Solidity's abi.encode(order)
 	tupleT, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
@@ -968,168 +938,68 @@ func encodeOrderData(orderData *OrderData, senderNonce *big.Int, networks []Netw
 		{Name: "data", Type: "bytes", InternalType: "", Components: nil, Indexed: false},
 	})
 	if err != nil {
-		log.Fatalf("Failed to define OrderData tuple type: %v", err)
+		logger.Fatalf("Failed to define OrderData tuple type: %v", err)
 	}
 
 	args := abi.Arguments{{Type: tupleT, Name: "", Indexed: false}}
 
 	encoded, err := args.Pack(abiOrderData)
 	if err != nil {
-		log.Fatalf("Failed to ABI-pack OrderData: %v", err)
+		logger.Fatalf("Failed to ABI-pack OrderData: %v", err)
 	}
 
-	return encoded
+	return encoded, nil
 }
 
-// convertToABIOrderData converts OrderData to ABIOrderData for ABI encoding
-func convertToABIOrderData(orderData *OrderData, senderNonce *big.Int, networks []NetworkConfig) ABIOrderData {
-	var senderBytes [32]byte
-	var recipientBytes [32]byte
-	var inputTokenBytes [32]byte
-	var outputTokenBytes [32]byte
-	var destinationSettlerBytes [32]byte
-
-	// Convert user address to bytes32 (left-padded)
-	// Get the actual user address from testUsers array
-	var userAddr common.Address
-	for _, user := range testUsers {
-		if user.name == orderData.User {
-			userAddr = common.HexToAddress(user.address)
-			break
-		}
-	}
-	if userAddr != (common.Address{}) {
-		copy(senderBytes[12:], userAddr.Bytes()) // Left-pad to 32 bytes
-
-		// For EVM→Starknet/Ztarknet orders, recipient should be the Starknet/Ztarknet user address
-		// For EVM→EVM orders, recipient can be the same as sender
-		if orderData.DestinationChainID.Uint64() == config.StarknetSepoliaChainID { // Starknet
-			// Get Starknet user address using conditional environment variable logic
-			starknetUserAddr := envutil.GetStarknetAliceAddress()
-			if starknetUserAddr != "" {
-				// Convert Starknet address to bytes32 (it's already 32 bytes)
-				starknetBytes := hexToBytes32(starknetUserAddr)
-				copy(recipientBytes[:], starknetBytes[:])
-			} else {
-				copy(recipientBytes[12:], userAddr.Bytes()) // Fallback to EVM address
-			}
-		} else if orderData.DestinationChainID.Uint64() == 10066329 { // Ztarknet (0x999999 = 10066329 in decimal)
-			// Get Ztarknet user address
-			ztarknetUserAddr := envutil.GetZtarknetAliceAddress()
-			if ztarknetUserAddr != "" {
-				// Convert Ztarknet address to bytes32 (it's already 32 bytes)
-				ztarknetBytes := hexToBytes32(ztarknetUserAddr)
-				copy(recipientBytes[:], ztarknetBytes[:])
-			} else {
-				copy(recipientBytes[12:], userAddr.Bytes()) // Fallback to EVM address
-			}
-		} else {
-			copy(recipientBytes[12:], userAddr.Bytes()) // Self-transfer for EVM→EVM
-		}
+// convertToABIOrderData converts OrderData to ABIOrderData for ABI encoding. A fill deadline that does not fit
+// the uint32 field is an error instead of being truncated
+func convertToABIOrderData(orderData *OrderData, senderNonce *big.Int) (ABIOrderData, error) {
+	fillDeadline, err := orderData.Deadlines.Fill.Uint32()
+	if err != nil {
+		return ABIOrderData{}, fmt.Errorf("invalid fill deadline: %w", err)
 	}
 
-	// Extract amounts from MaxSpent and MinReceived arrays
-	var amountIn, amountOut = big.NewInt(0), big.NewInt(0)
+	var senderBytes [32]byte
+	var inputTokenBytes [32]byte
 
-	// For ABI encoding, we need to use the origin chain tokens
-	// since the order is processed on the origin chain
-	// But the amounts come from the order data
+	// The sender is the user's registered EVM address, left-padded
+	sender, err := userAddress(orderData.User, identity.EVM)
+	if err != nil {
+		return ABIOrderData{}, fmt.Errorf("failed to resolve the sender: %w", err)
+	}
+	senderBytes = starknetutil.EVMAddressToBytes32(common.HexToAddress(sender))
 
 	// Get amount from MinReceived (what Alice provides = AmountIn)
+	// and from MaxSpent (what Alice receives = AmountOut)
+	var amountIn, amountOut = big.NewInt(0), big.NewInt(0)
 	if len(orderData.MinReceived) > 0 {
 		amountIn = orderData.MinReceived[0].Amount.ToBig()
 	}
-
-	// Get amount from MaxSpent (what Alice receives = AmountOut)
 	if len(orderData.MaxSpent) > 0 {
 		amountOut = orderData.MaxSpent[0].Amount.ToBig()
 	}
 
-	// For ABI encoding, we need to set the correct token addresses
-	originChainID := orderData.OriginChainID.Uint64()
-	destinationChainID := orderData.DestinationChainID.Uint64()
-
-	var originTokenAddr, destinationTokenAddr string
-
-	// Find the origin network config to get the input token address
-	for _, network := range networks {
-		if network.chainID == originChainID {
-			originTokenAddr = network.dogCoinAddress
-			break
-		}
-	}
-
-	// Find the destination network config to get the output token address
-	for _, network := range networks {
-		if network.chainID == destinationChainID {
-			destinationTokenAddr = network.dogCoinAddress
-			break
-		}
-	}
-
-	// Special handling for Starknet/Ztarknet destinations - need to get from environment
-	if destinationChainID == config.StarknetSepoliaChainID { // Starknet
-		starknetDogCoin := os.Getenv("STARKNET_DOG_COIN_ADDRESS")
-		if starknetDogCoin != "" {
-			destinationTokenAddr = starknetDogCoin
-		}
-	} else if destinationChainID == config.ZtarknetTestnetChainID { // Ztarknet (0x999999 = 10066329 in decimal)
-		ztarknetDogCoin := os.Getenv("ZTARKNET_DOG_COIN_ADDRESS")
-		if ztarknetDogCoin != "" {
-			destinationTokenAddr = ztarknetDogCoin
-		}
-	}
-
-	// Set InputToken (origin chain token - what Alice locks up)
-	if originTokenAddr != "" {
-		inputTokenBytes = hexToBytes32(originTokenAddr)
-	}
-
-	// Set OutputToken (destination chain token - what solver provides to Alice)
-	if destinationTokenAddr != "" {
-		outputTokenBytes = hexToBytes32(destinationTokenAddr)
-	}
-
-	// Set destination settler address (Hyperlane contract on destination chain)
-	// This should be the Hyperlane contract address for the destination domain
-	if orderData.DestinationChainID.Uint64() == config.StarknetSepoliaChainID { // Starknet
-		// Use Starknet Hyperlane address from environment
-		starknetHyperlaneAddr := os.Getenv("STARKNET_HYPERLANE_ADDRESS")
-		if starknetHyperlaneAddr != "" {
-			starknetBytes := hexToBytes32(starknetHyperlaneAddr)
-			copy(destinationSettlerBytes[:], starknetBytes[:])
-		} else {
-			// Fallback to zero address
-			copy(destinationSettlerBytes[:], make([]byte, 32))
-		}
-	} else if orderData.DestinationChainID.Uint64() == config.ZtarknetTestnetChainID { // Ztarknet (0x999999 = 10066329 in decimal)
-		// Use Ztarknet Hyperlane address from environment
-		ztarknetHyperlaneAddr := os.Getenv("ZTARKNET_HYPERLANE_ADDRESS")
-		if ztarknetHyperlaneAddr != "" {
-			ztarknetBytes := hexToBytes32(ztarknetHyperlaneAddr)
-			copy(destinationSettlerBytes[:], ztarknetBytes[:])
-		} else {
-			// Fallback to zero address
-			copy(destinationSettlerBytes[:], make([]byte, 32))
+	// InputToken is the origin chain token Alice locks up
+	if len(orderData.MinReceived) > 0 {
+		inputTokenBytes, err = starknetutil.HexToBytes32(orderData.MinReceived[0].Token)
+		if err != nil {
+			return ABIOrderData{}, fmt.Errorf("invalid input token: %w", err)
 		}
-	} else {
-		// EVM Hyperlane address
-		destinationSettlerAddr := common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
-		copy(destinationSettlerBytes[12:], destinationSettlerAddr.Bytes()) // Left-pad to 32 bytes
 	}
 
+	// Recipient, OutputToken and DestinationSettler were encoded for the destination type by buildOrderData
 	return ABIOrderData{
 		Sender:             senderBytes,
-		Recipient:          recipientBytes,
+		Recipient:          orderData.RecipientWord,
 		InputToken:         inputTokenBytes,
-		OutputToken:        outputTokenBytes,
+		OutputToken:        orderData.OutputTokenWord,
 		AmountIn:           amountIn,
 		AmountOut:          amountOut,
 		SenderNonce:        senderNonce, // Use actual nonce from parameter
 		OriginDomain:       uint32(orderData.OriginChainID.Uint64()),
 		DestinationDomain:  uint32(orderData.DestinationChainID.Uint64()),
-		DestinationSettler: destinationSettlerBytes,
-		FillDeadline:       uint32(orderData.FillDeadline.Uint64()),
+		DestinationSettler: orderData.DestinationSettlerWord,
+		FillDeadline:       fillDeadline,
 		Data:               []byte{}, // Empty data for now
-	}
+	}, nil
 }
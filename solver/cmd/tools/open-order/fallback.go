@@ -0,0 +1,121 @@
+package openorder
+
+// Destination fallback
+// An order whose output token or destination settler belongs to the origin chain can never be filled and
+// locks Alice's input until it is refunded, so a destination address that cannot be resolved is an error.
+// --force restores the old behaviour of falling back to the origin's address, for debugging only. A missing
+// origin address is always an error, and either one names the setup step that deploys it when there is one
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// ForceFlag opens orders even when a destination address is missing, using the origin's instead
+const ForceFlag = "--force"
+
+// forceOriginFallback is set by --force
+var forceOriginFallback bool
+
+// SetForceFallback enables the origin fallback for missing destination tokens and settlers
+func SetForceFallback(enabled bool) {
+	forceOriginFallback = enabled
+}
+
+// StripForceFlag removes --force from args and reports whether it was present
+func StripForceFlag(args []string) ([]string, bool) {
+	return stripBoolFlag(args, ForceFlag)
+}
+
+// MissingAddressError names an order address that could not be resolved on a network
+type MissingAddressError struct {
+	Key         string // e.g. DogCoinAddress or HyperlaneAddress
+	Network     string
+	Hint        string // how to provide it
+	Setup       string // the solver tools step that deploys it, empty when none does
+	Destination bool   // destination addresses can be forced to the origin's
+}
+
+// Error renders the missing key with the network and how to provide it
+func (e *MissingAddressError) Error() string {
+	msg := fmt.Sprintf("missing %s for network %q (%s)", e.Key, e.Network, e.Hint)
+	if e.Setup != "" {
+		msg += fmt.Sprintf("; run `solver tools %s` to deploy it", e.Setup)
+	}
+	if e.Destination {
+		msg += fmt.Sprintf("; %s falls back to the origin's, producing an order that cannot be filled", ForceFlag)
+	}
+	return msg
+}
+
+// destinationSettlerAddress looks up the Hyperlane7683 address of a destination network: Starknet-type networks
+// read <NAME>_HYPERLANE_ADDRESS first, then the static and centralized config are checked
+func destinationSettlerAddress(destChainName string) string {
+	if config.IsStarknetNetwork(destChainName) {
+		if settler := getEnvWithDefault(strings.ToUpper(destChainName)+"_HYPERLANE_ADDRESS", ""); settler != "" {
+			return settler
+		}
+	}
+	if settler, err := config.GetHyperlaneAddress(destChainName); err == nil && settler != "" {
+		return settler
+	}
+	if network, exists := config.Networks[destChainName]; exists {
+		return network.HyperlaneAddress
+	}
+	return ""
+}
+
+// resolveDestinationSettler returns settler, or with --force the origin's settler when settler is empty
+func resolveDestinationSettler(destChainName, settler, originChain, originSettler string) (string, error) {
+	if settler != "" {
+		return settler, nil
+	}
+	if !forceOriginFallback {
+		missing := missingSettlerError(destChainName)
+		missing.Destination = true
+		return "", missing
+	}
+	warnf("   ⚠️  %s: no Hyperlane address for %s, using %s's settler %s; this order cannot be filled\n",
+		ForceFlag, destChainName, originChain, originSettler)
+	return originSettler, nil
+}
+
+// withDestinationSettler returns a copy of destination whose settler is resolved with resolveDestinationSettler
+func withDestinationSettler(destination, origin *NetworkConfig) (*NetworkConfig, error) {
+	settler, err := resolveDestinationSettler(destination.name, destination.hyperlaneAddress, origin.name, origin.hyperlaneAddress)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *destination
+	resolved.hyperlaneAddress = settler
+	return &resolved, nil
+}
+
+// missingSettlerError reports networkName's Hyperlane7683 address as unset
+func missingSettlerError(networkName string) *MissingAddressError {
+	return &MissingAddressError{
+		Key:     "HyperlaneAddress",
+		Network: networkName,
+		Hint:    "set " + strings.ToUpper(networkName) + "_HYPERLANE_ADDRESS",
+		Setup:   settlerSetupStep(networkName),
+	}
+}
+
+// settlerSetupStep is the step deploying networkName's Hyperlane7683; EVM forks use the mainnet deployments
+// from the network config and Ztarknet has no setup step
+func settlerSetupStep(networkName string) string {
+	if GetNetworkType(networkName) == NetworkTypeStarknet {
+		return "deploy-sn-hyperlane7683"
+	}
+	return ""
+}
+
+// checkOriginSettler rejects an origin without a Hyperlane7683 address before anything is read from or sent to it
+func checkOriginSettler(origin *NetworkConfig) error {
+	if origin.hyperlaneAddress == "" {
+		return missingSettlerError(origin.name)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package openorder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useForceFallback sets --force for the duration of a test
+func useForceFallback(t *testing.T, enabled bool) {
+	saved := forceOriginFallback
+	SetForceFallback(enabled)
+	t.Cleanup(func() { SetForceFallback(saved) })
+}
+
+func TestStripForceFlag(t *testing.T) {
+	args, force := StripForceFlag([]string{"evm", "--force", "starknet"})
+	assert.Equal(t, []string{"evm", "starknet"}, args)
+	assert.True(t, force)
+
+	_, force = StripForceFlag([]string{"evm", "starknet"})
+	assert.False(t, force)
+}
+
+func TestResolveDestinationSettler(t *testing.T) {
+	t.Run("missing settler names the key", func(t *testing.T) {
+		useForceFallback(t, false)
+		_, err := resolveDestinationSettler("Base", "", "Ethereum", testEthereumSettler)
+
+		var missing *MissingAddressError
+		require.ErrorAs(t, err, &missing)
+		assert.Equal(t, "HyperlaneAddress", missing.Key)
+		assert.Equal(t, "Base", missing.Network)
+		assert.ErrorContains(t, err, `missing HyperlaneAddress for network "Base" (set BASE_HYPERLANE_ADDRESS)`)
+		assert.ErrorContains(t, err, ForceFlag)
+	})
+
+	t.Run("force falls back to the origin settler", func(t *testing.T) {
+		useForceFallback(t, true)
+		settler, err := resolveDestinationSettler("Base", "", "Ethereum", testEthereumSettler)
+		require.NoError(t, err)
+		assert.Equal(t, testEthereumSettler, settler)
+	})
+
+	t.Run("a resolved settler is kept", func(t *testing.T) {
+		useForceFallback(t, true)
+		settler, err := resolveDestinationSettler("Starknet", testStarknetSettler, "Ethereum", testEthereumSettler)
+		require.NoError(t, err)
+		assert.Equal(t, testStarknetSettler, settler)
+	})
+}
+
+func TestCheckOriginSettler(t *testing.T) {
+	require.NoError(t, checkOriginSettler(&NetworkConfig{name: "Ethereum", hyperlaneAddress: testEthereumSettler}))
+
+	err := checkOriginSettler(&NetworkConfig{name: "Base"})
+	var missing *MissingAddressError
+	require.ErrorAs(t, err, &missing)
+	assert.False(t, missing.Destination, "a missing origin settler is never forced")
+	assert.NotContains(t, err.Error(), ForceFlag)
+
+	err = checkOriginSettler(&NetworkConfig{name: "Starknet"})
+	assert.ErrorContains(t, err, `missing HyperlaneAddress for network "Starknet" (set STARKNET_HYPERLANE_ADDRESS); run `+"`solver tools deploy-sn-hyperlane7683`")
+}
+
+func TestWithDestinationSettlerKeepsNetwork(t *testing.T) {
+	useForceFallback(t, true)
+	origin := &NetworkConfig{name: "Ethereum", hyperlaneAddress: testEthereumSettler}
+	destination := &NetworkConfig{name: "Base"}
+
+	resolved, err := withDestinationSettler(destination, origin)
+	require.NoError(t, err)
+	assert.Equal(t, testEthereumSettler, resolved.hyperlaneAddress)
+	assert.Empty(t, destination.hyperlaneAddress, "the shared network config is not modified")
+}
+
+func TestResolveOrderTokensMissingDestination(t *testing.T) {
+	t.Setenv("ETHEREUM_DOG_COIN_ADDRESS", testEthereumDogCoin)
+	t.Setenv("BASE_DOG_COIN_ADDRESS", "")
+	saved := tokenDecimalsReader
+	tokenDecimalsReader = func(context.Context, string, string) (int, error) { return tokenDecimals, nil }
+	t.Cleanup(func() { tokenDecimalsReader = saved })
+
+	t.Run("error before anything is sent", func(t *testing.T) {
+		useForceFallback(t, false)
+		_, err := resolveOrderTokens(context.Background(), DefaultOrderToken, DefaultOrderToken, "Ethereum", "Base")
+		assert.ErrorContains(t, err, `missing DogCoinAddress for network "Base" (set BASE_DOG_COIN_ADDRESS`)
+		assert.ErrorContains(t, err, ForceFlag)
+	})
+
+	t.Run("force uses the origin token", func(t *testing.T) {
+		useForceFallback(t, true)
+		tokens, err := resolveOrderTokens(context.Background(), DefaultOrderToken, DefaultOrderToken, "Ethereum", "Base")
+		require.NoError(t, err)
+		assert.Equal(t, testEthereumDogCoin, tokens.Output.Address)
+	})
+
+	t.Run("a missing origin token is never forced", func(t *testing.T) {
+		useForceFallback(t, true)
+		_, err := resolveOrderTokens(context.Background(), DefaultOrderToken, DefaultOrderToken, "Base", "Ethereum")
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), ForceFlag)
+	})
+}
@@ -0,0 +1,77 @@
+package openorder
+
+// Open value
+// open() on Hyperlane7683 is payable but only accepts a msg.value equal to the order's native (zero address)
+// inputs, reverting with InvalidNativeAmount on any other value. The Hyperlane gas quoted by quoteGasPayment
+// pays for the settle and refund messages, which the solver sends from the destination, so it is read and
+// printed for the route but never attached to open(): doing so would make every ERC20 order revert
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// gasPaymentQuoter is the quoteGasPayment view of the generated Hyperlane7683 bindings
+type gasPaymentQuoter interface {
+	QuoteGasPayment(opts *bind.CallOpts, destinationDomain uint32) (*big.Int, error)
+}
+
+// quoteGasPayment reads the Hyperlane gas quote for messages to destinationDomain and records it in result.
+// A failing quote is only logged, since open() does not depend on it
+func quoteGasPayment(ctx context.Context, quoter gasPaymentQuoter, destinationDomain uint32, result *OrderResult) {
+	quote, err := quoter.QuoteGasPayment(&bind.CallOpts{Context: ctx}, destinationDomain)
+	if err != nil {
+		warnf("   ⚠️  quoteGasPayment(%d) failed: %v\n", destinationDomain, ethutil.RevertReason(err))
+		return
+	}
+	recordGasQuote(destinationDomain, quote, result)
+}
+
+// quoteStarknetGasPayment is quoteGasPayment for a Starknet-type origin. The Cairo open() takes no fee, so the
+// quote is only reported
+func quoteStarknetGasPayment(ctx context.Context, caller starknetutil.ContractCaller, hyperlane *felt.Felt, destinationDomain uint32, result *OrderResult) {
+	quote, err := starknetorder.QuoteGasPayment(ctx, caller, hyperlane, destinationDomain)
+	if err != nil {
+		warnf("   ⚠️  %v\n", err)
+		return
+	}
+	recordGasQuote(destinationDomain, quote, result)
+}
+
+// recordGasQuote prints a gas quote and stores it in result
+func recordGasQuote(destinationDomain uint32, quote *big.Int, result *OrderResult) {
+	logf("   Hyperlane gas quote for domain %d: %s wei (paid by the solver on settle/refund, not by open)\n", destinationDomain, quote)
+	if result != nil {
+		result.GasQuote = quote.String()
+	}
+}
+
+// nativeInputValue is the msg.value open() requires: the sum of the order inputs in the native token
+func nativeInputValue(orderData *OrderData) *big.Int {
+	value := new(big.Int)
+	for _, input := range orderData.MinReceived {
+		if common.HexToAddress(input.Token) == (common.Address{}) && input.Amount != nil {
+			value.Add(value, input.Amount.ToBig())
+		}
+	}
+	return value
+}
+
+// withOpenValue returns a copy of auth that sends value with open(), or no value at all when it is zero
+func withOpenValue(auth *bind.TransactOpts, value *big.Int) *bind.TransactOpts {
+	opts := *auth
+	opts.Value = nil
+	if value != nil && value.Sign() > 0 {
+		opts.Value = new(big.Int).Set(value)
+		logf("   Sending %s wei with open() for native inputs\n", value)
+	}
+	return &opts
+}
@@ -0,0 +1,67 @@
+package openorder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQuoter returns a fixed quoteGasPayment answer
+type fakeQuoter struct {
+	quote  *big.Int
+	err    error
+	domain uint32
+}
+
+func (f *fakeQuoter) QuoteGasPayment(_ *bind.CallOpts, destinationDomain uint32) (*big.Int, error) {
+	f.domain = destinationDomain
+	return f.quote, f.err
+}
+
+func TestQuoteGasPayment(t *testing.T) {
+	quoter := &fakeQuoter{quote: big.NewInt(12345)}
+	result := &OrderResult{}
+	quoteGasPayment(context.Background(), quoter, 8453, result)
+	assert.Equal(t, uint32(8453), quoter.domain)
+	assert.Equal(t, "12345", result.GasQuote)
+
+	failing := &OrderResult{}
+	quoteGasPayment(context.Background(), &fakeQuoter{err: errors.New("no router enrolled")}, 8453, failing)
+	assert.Empty(t, failing.GasQuote, "a failed quote does not stop the order")
+}
+
+func TestWithOpenValue(t *testing.T) {
+	auth := &bind.TransactOpts{From: common.HexToAddress(testEthereumSettler), Value: big.NewInt(7)}
+
+	t.Run("non-zero value is forwarded", func(t *testing.T) {
+		opts := withOpenValue(auth, big.NewInt(1000))
+		require.NotNil(t, opts.Value)
+		assert.Equal(t, big.NewInt(1000), opts.Value)
+		assert.Equal(t, auth.From, opts.From)
+	})
+
+	t.Run("zero value is omitted", func(t *testing.T) {
+		assert.Nil(t, withOpenValue(auth, big.NewInt(0)).Value)
+		assert.Nil(t, withOpenValue(auth, nil).Value)
+	})
+
+	assert.Equal(t, big.NewInt(7), auth.Value, "the shared auth is not modified")
+}
+
+func TestNativeInputValue(t *testing.T) {
+	erc20 := &OrderData{MinReceived: []TokenAmount{{Token: testEthereumDogCoin, Amount: uint256.NewInt(1001)}}}
+	assert.Zero(t, nativeInputValue(erc20).Sign(), "ERC20 inputs are pulled with transferFrom, not paid in value")
+
+	native := &OrderData{MinReceived: []TokenAmount{
+		{Token: common.Address{}.Hex(), Amount: uint256.NewInt(1001)},
+		{Token: testEthereumDogCoin, Amount: uint256.NewInt(5)},
+	}}
+	assert.Equal(t, big.NewInt(1001), nativeInputValue(native))
+}
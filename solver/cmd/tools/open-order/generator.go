@@ -0,0 +1,470 @@
+package openorder
+
+// Continuous order generator - opens randomized EVM orders at a steady rate to soak test the solver
+// Orders are planned up front and opened through batch sessions (one client, tx nonce sequence and approval per
+// origin), then released on a ticker with at most --max-inflight open() transactions waiting at a time
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+const (
+	defaultGeneratorRate        = 1.0
+	defaultGeneratorMaxInflight = 4
+	defaultGeneratorStatsEvery  = 30 * time.Second
+	// maxGeneratorOrders bounds a run, since its approvals and sender nonces are reserved up front
+	maxGeneratorOrders = 10000
+	// generatorDrainTimeout bounds how long Ctrl-C waits for inflight orders
+	generatorDrainTimeout = 2 * time.Minute
+)
+
+// generatorUsage is printed when the generate arguments are invalid
+const generatorUsage = "Usage: open-order generate [--rate R] (--duration D | --count N) [--max-inflight N] [--stats-interval D] " +
+	"[--amount MIN-MAX] [--delta MIN-MAX] [--fill-window MIN-MAX] [--pairs IN:OUT[,IN:OUT...]] [--auto-approve]"
+
+// tokenRange is an inclusive range of whole token amounts
+type tokenRange struct {
+	min, max int
+}
+
+// durationRange is an inclusive range of durations
+type durationRange struct {
+	min, max time.Duration
+}
+
+// generatorOptions configures a generator run
+type generatorOptions struct {
+	rate        float64 // orders released per second
+	duration    time.Duration
+	count       int
+	maxInflight int
+	statsEvery  time.Duration
+
+	amount     tokenRange    // the output amount
+	delta      tokenRange    // the solver's margin, added to the output for the input amount
+	fillWindow durationRange // how far past the origin's block time an order's fill deadline is
+	pairs      []TokenSelection
+}
+
+// parseGeneratorArgs parses the generate flags; every flag takes a value, as "--flag value" or "--flag=value"
+func parseGeneratorArgs(args []string) (generatorOptions, error) {
+	opts := generatorOptions{
+		rate:        defaultGeneratorRate,
+		maxInflight: defaultGeneratorMaxInflight,
+		statsEvery:  defaultGeneratorStatsEvery,
+		amount:      tokenRange{minTokenAmount, maxTokenAmount},
+		delta:       tokenRange{minDeltaAmount, maxDeltaAmount},
+		fillWindow:  durationRange{deadlineWindows.Fill, deadlineWindows.Fill},
+		pairs:       []TokenSelection{tokenSelection},
+	}
+
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(name, "--") {
+			return generatorOptions{}, fmt.Errorf("unexpected argument: %s", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) || strings.HasPrefix(args[i+1], "--") {
+				return generatorOptions{}, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		var err error
+		switch name {
+		case "--rate":
+			opts.rate, err = strconv.ParseFloat(value, 64)
+		case "--duration":
+			opts.duration, err = time.ParseDuration(value)
+		case "--count":
+			opts.count, err = strconv.Atoi(value)
+		case "--max-inflight":
+			opts.maxInflight, err = strconv.Atoi(value)
+		case "--stats-interval":
+			opts.statsEvery, err = time.ParseDuration(value)
+		case "--amount":
+			opts.amount, err = parseTokenRange(value)
+		case "--delta":
+			opts.delta, err = parseTokenRange(value)
+		case "--fill-window":
+			opts.fillWindow, err = parseDurationRange(value)
+		case "--pairs":
+			opts.pairs, err = parseTokenPairs(value)
+		default:
+			return generatorOptions{}, fmt.Errorf("unknown flag: %s", name)
+		}
+		if err != nil {
+			return generatorOptions{}, fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+	}
+
+	switch {
+	case opts.duration <= 0 && opts.count <= 0:
+		return generatorOptions{}, fmt.Errorf("a positive --duration or --count is required")
+	case opts.duration < 0 || opts.count < 0:
+		return generatorOptions{}, fmt.Errorf("--duration and --count must be positive")
+	case opts.rate <= 0 || math.IsInf(opts.rate, 0) || math.IsNaN(opts.rate):
+		return generatorOptions{}, fmt.Errorf("--rate must be a positive number of orders per second, got %v", opts.rate)
+	case opts.maxInflight <= 0:
+		return generatorOptions{}, fmt.Errorf("--max-inflight must be positive, got %d", opts.maxInflight)
+	case opts.statsEvery <= 0:
+		return generatorOptions{}, fmt.Errorf("--stats-interval must be positive, got %s", opts.statsEvery)
+	case opts.fillWindow.min <= deadlineWindows.Open:
+		return generatorOptions{}, fmt.Errorf("--fill-window must be longer than %s (%s), got %s", OpenDeadlineFlag, deadlineWindows.Open, opts.fillWindow.min)
+	case opts.amount.min <= 0:
+		return generatorOptions{}, fmt.Errorf("--amount must be positive, got %d", opts.amount.min)
+	}
+	if opts.total() > maxGeneratorOrders {
+		return generatorOptions{}, fmt.Errorf("a run opens at most %d orders; lower --rate, --duration or --count", maxGeneratorOrders)
+	}
+	return opts, nil
+}
+
+// total is how many orders a run opens at most: --count, or what --rate releases within --duration
+func (o generatorOptions) total() int {
+	if o.duration <= 0 {
+		return o.count
+	}
+	total := int(math.Min(math.Ceil(o.rate*o.duration.Seconds()), maxGeneratorOrders+1))
+	if o.count > 0 && o.count < total {
+		return o.count
+	}
+	return total
+}
+
+// parseTokenRange parses "MIN-MAX" or a single amount
+func parseTokenRange(value string) (tokenRange, error) {
+	minValue, maxValue, isRange := strings.Cut(value, "-")
+	if !isRange {
+		maxValue = minValue
+	}
+	lo, err := strconv.Atoi(minValue)
+	if err != nil {
+		return tokenRange{}, err
+	}
+	hi, err := strconv.Atoi(maxValue)
+	if err != nil {
+		return tokenRange{}, err
+	}
+	if lo < 0 || hi < lo {
+		return tokenRange{}, fmt.Errorf("want 0 <= MIN <= MAX")
+	}
+	return tokenRange{lo, hi}, nil
+}
+
+// parseDurationRange parses "MIN-MAX" (e.g. 1h-24h) or a single duration
+func parseDurationRange(value string) (durationRange, error) {
+	minValue, maxValue, isRange := strings.Cut(value, "-")
+	if !isRange {
+		maxValue = minValue
+	}
+	lo, err := time.ParseDuration(minValue)
+	if err != nil {
+		return durationRange{}, err
+	}
+	hi, err := time.ParseDuration(maxValue)
+	if err != nil {
+		return durationRange{}, err
+	}
+	if lo < 0 || hi < lo {
+		return durationRange{}, fmt.Errorf("want 0 <= MIN <= MAX")
+	}
+	return durationRange{lo, hi}, nil
+}
+
+// parseTokenPairs parses "IN:OUT[,IN:OUT...]" token symbols or addresses
+func parseTokenPairs(value string) ([]TokenSelection, error) {
+	var pairs []TokenSelection
+	for _, pair := range strings.Split(value, ",") {
+		input, output, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || input == "" || output == "" {
+			return nil, fmt.Errorf("want IN:OUT, got %q", pair)
+		}
+		pairs = append(pairs, TokenSelection{Input: input, Output: output})
+	}
+	return pairs, nil
+}
+
+// pick returns a random amount of the range in base units
+func (r tokenRange) pick() *big.Int {
+	return CreateTokenAmount(int64(r.min+randomInt(r.max-r.min+1)), tokenDecimals)
+}
+
+// pick returns a random duration of the range, to the second
+func (r durationRange) pick() time.Duration {
+	span := int((r.max - r.min) / time.Second)
+	return r.min + time.Duration(randomInt(span+1))*time.Second
+}
+
+// generatedOrder is a planned order; its deadlines are stamped from the origin's block time when it is sent
+type generatedOrder struct {
+	order   OrderConfig
+	windows DeadlineWindows
+	session *originSession
+}
+
+// planGeneratorOrders plans count orders from EVM origins. Orders alternate origins in turn, each origin cycles
+// through its valid destinations (so both directions of every EVM pair come up) and token pairs rotate
+func planGeneratorOrders(opts generatorOptions, count int) ([]generatedOrder, error) {
+	var origins []string
+	destinations := make(map[string][]string)
+	for _, origin := range sortedNetworkNames() {
+		if GetNetworkType(origin) != NetworkTypeEVM {
+			continue
+		}
+		for _, destination := range sortedNetworkNames() {
+			if isValidDestination(origin, destination) {
+				destinations[origin] = append(destinations[origin], destination)
+			}
+		}
+		if len(destinations[origin]) > 0 {
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) == 0 {
+		return nil, fmt.Errorf("no EVM networks configured")
+	}
+
+	orders := make([]generatedOrder, 0, count)
+	for i := 0; i < count; i++ {
+		origin := origins[i%len(origins)]
+		originDestinations := destinations[origin]
+		pair := opts.pairs[i%len(opts.pairs)]
+
+		outputAmount := opts.amount.pick()
+		orders = append(orders, generatedOrder{
+			order: OrderConfig{
+				OriginChain:      origin,
+				DestinationChain: originDestinations[(i/len(origins))%len(originDestinations)],
+				InputToken:       pair.Input,
+				OutputToken:      pair.Output,
+				InputAmount:      new(big.Int).Add(outputAmount, opts.delta.pick()),
+				OutputAmount:     outputAmount,
+				User:             AliceUserName,
+			},
+			windows: DeadlineWindows{Open: deadlineWindows.Open, Fill: opts.fillWindow.pick()},
+		})
+	}
+	return orders, nil
+}
+
+// generatorStats tracks the outcome of a generator run
+type generatorStats struct {
+	mu       sync.Mutex
+	opened   int
+	failed   int
+	inflight int
+	latency  time.Duration // total open latency of the opened orders
+}
+
+func (s *generatorStats) started() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inflight++
+}
+
+// finished records an order that was sent; latency runs from send to receipt
+func (s *generatorStats) finished(err error, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inflight--
+	if err != nil {
+		s.failed++
+		return
+	}
+	s.opened++
+	s.latency += latency
+}
+
+// failedBeforeSend records an order that failed before it was released
+func (s *generatorStats) failedBeforeSend() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed++
+}
+
+func (s *generatorStats) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	average := time.Duration(0)
+	if s.opened > 0 {
+		average = s.latency / time.Duration(s.opened)
+	}
+	return fmt.Sprintf("opened %d, failed %d, inflight %d, avg open latency %s",
+		s.opened, s.failed, s.inflight, average.Round(time.Millisecond))
+}
+
+// RunOrderGenerator keeps opening random EVM orders at --rate until --duration elapses or --count orders were
+// released. Ctrl-C stops releasing orders and waits for the inflight ones. Opened orders go to the order store
+func RunOrderGenerator(ctx context.Context, args []string) {
+	opts, err := parseGeneratorArgs(args)
+	if err != nil {
+		fmt.Println(generatorUsage)
+		logger.Fatalf("Invalid generate arguments: %v", err)
+	}
+
+	// Load configuration (this loads .env and initializes networks)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+	logutil.ConfigureFromConfig(logger, cfg)
+	if err := initializeUsers(); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	networks := loadNetworks(cfg)
+
+	planned, err := planGeneratorOrders(opts, opts.total())
+	if err != nil {
+		logger.Fatalf("Failed to plan orders: %v", err)
+	}
+	stats := &generatorStats{}
+	queue := prepareGeneratorSessions(ctx, planned, networks, stats)
+
+	logf("Generating up to %d orders at %.2f/s (max inflight %d)\n", len(queue), opts.rate, opts.maxInflight)
+
+	// Inflight orders keep going after Ctrl-C so their outcome is recorded; the drain is bounded
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	jobs := make(chan generatedOrder)
+	store := orderstore.New(orderstore.DefaultDir())
+	var wg sync.WaitGroup
+	for range opts.maxInflight {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range jobs {
+				stats.started()
+				started := time.Now()
+				r := g.session.openOrder(workCtx, g.order, g.windows, networks)
+				stats.finished(r.Err, time.Since(started))
+				if r.Err != nil {
+					errorf("   ❌ %s → %s: %v\n", r.Order.OriginChain, r.Order.DestinationChain, r.Err)
+					continue
+				}
+				if r.OrderID != (common.Hash{}) {
+					saveOrder(store, r.orderResult())
+				}
+				logf("   ✅ %s → %s: order %s (tx %s)\n", r.Order.OriginChain, r.Order.DestinationChain, r.OrderID.Hex(), r.TxHash.Hex())
+			}
+		}()
+	}
+
+	released := releaseGeneratedOrders(ctx, opts, queue, jobs, stats)
+	close(jobs)
+	if ctx.Err() != nil {
+		logf("Interrupted: waiting up to %s for inflight orders (%s)\n", generatorDrainTimeout, stats)
+		time.AfterFunc(generatorDrainTimeout, cancelWork)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	statsTicker := time.NewTicker(opts.statsEvery)
+	defer statsTicker.Stop()
+	for waiting := true; waiting; {
+		select {
+		case <-done:
+			waiting = false
+		case <-statsTicker.C:
+			logf("📊 %s\n", stats)
+		}
+	}
+
+	logf("\nGenerator Summary:\n")
+	logf("   Released: %d of %d planned\n", released, len(planned))
+	logf("   %s\n", stats)
+}
+
+// prepareGeneratorSessions resolves the planned orders and opens one session per origin. Orders that cannot be
+// opened are reported and counted as failed; the rest are returned in plan order
+func prepareGeneratorSessions(ctx context.Context, planned []generatedOrder, networks []NetworkConfig, stats *generatorStats) []generatedOrder {
+	byOrigin := make(map[string][]OrderConfig)
+	resolved := make([]generatedOrder, 0, len(planned))
+	for _, g := range planned {
+		if err := resolveBatchDestination(ctx, &g.order, networks); err != nil {
+			errorf("   ❌ %s → %s: %v\n", g.order.OriginChain, g.order.DestinationChain, err)
+			stats.failedBeforeSend()
+			continue
+		}
+		byOrigin[g.order.OriginChain] = append(byOrigin[g.order.OriginChain], g.order)
+		resolved = append(resolved, g)
+	}
+
+	sessions := make(map[string]*originSession)
+	for origin, orders := range byOrigin {
+		session, err := newOriginSession(ctx, origin, orders, networks)
+		if err != nil {
+			// A broken origin only fails its own orders
+			errorf("   ❌ %s: %v\n", origin, err)
+			continue
+		}
+		sessions[origin] = session
+	}
+
+	queue := make([]generatedOrder, 0, len(resolved))
+	for _, g := range resolved {
+		if g.session = sessions[g.order.OriginChain]; g.session == nil {
+			stats.failedBeforeSend()
+			continue
+		}
+		queue = append(queue, g)
+	}
+	return queue
+}
+
+// releaseGeneratedOrders hands queued orders to the workers at opts.rate until the queue is empty, the duration
+// elapses or ctx is done, printing stats along the way. Ticks that find every worker busy are skipped, so the
+// rate is an upper bound. It returns how many orders were released
+func releaseGeneratedOrders(ctx context.Context, opts generatorOptions, queue []generatedOrder, jobs chan<- generatedOrder, stats *generatorStats) int {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.rate))
+	defer ticker.Stop()
+	statsTicker := time.NewTicker(opts.statsEvery)
+	defer statsTicker.Stop()
+	var deadline <-chan time.Time
+	if opts.duration > 0 {
+		timer := time.NewTimer(opts.duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	// pending is jobs while a released order waits for a free worker, nil otherwise
+	var pending chan<- generatedOrder
+	var next generatedOrder
+	released := 0
+	for released < len(queue) || pending != nil {
+		select {
+		case <-ctx.Done():
+			return released
+		case <-deadline:
+			return released
+		case <-statsTicker.C:
+			logf("📊 %s\n", stats)
+		case <-ticker.C:
+			if pending == nil {
+				next, pending = queue[released], jobs
+			}
+		case pending <- next:
+			pending = nil
+			released++
+		}
+	}
+	return released
+}
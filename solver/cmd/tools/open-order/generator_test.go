@@ -0,0 +1,134 @@
+package openorder
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestParseGeneratorArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		check   func(t *testing.T, opts generatorOptions)
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			args: []string{"--count", "10"},
+			check: func(t *testing.T, opts generatorOptions) {
+				assert.Equal(t, 10, opts.total())
+				assert.Equal(t, defaultGeneratorRate, opts.rate)
+				assert.Equal(t, defaultGeneratorMaxInflight, opts.maxInflight)
+				assert.Equal(t, tokenRange{minTokenAmount, maxTokenAmount}, opts.amount)
+				assert.Equal(t, []TokenSelection{tokenSelection}, opts.pairs)
+			},
+		},
+		{
+			name: "soak_test",
+			args: []string{"--rate", "0.2", "--duration=30m", "--max-inflight", "5"},
+			check: func(t *testing.T, opts generatorOptions) {
+				assert.Equal(t, 360, opts.total())
+				assert.Equal(t, 5, opts.maxInflight)
+			},
+		},
+		{
+			name: "count_caps_duration",
+			args: []string{"--rate", "2", "--duration", "1m", "--count", "7"},
+			check: func(t *testing.T, opts generatorOptions) {
+				assert.Equal(t, 7, opts.total())
+			},
+		},
+		{
+			name: "randomization",
+			args: []string{"--count=1", "--amount", "5-50", "--delta", "0", "--fill-window", "90m-2h", "--pairs", "DogCoin:OrcaCoin, OrcaCoin:DogCoin"},
+			check: func(t *testing.T, opts generatorOptions) {
+				assert.Equal(t, tokenRange{5, 50}, opts.amount)
+				assert.Equal(t, tokenRange{0, 0}, opts.delta)
+				assert.Equal(t, durationRange{90 * time.Minute, 2 * time.Hour}, opts.fillWindow)
+				assert.Equal(t, []TokenSelection{{Input: "DogCoin", Output: "OrcaCoin"}, {Input: "OrcaCoin", Output: "DogCoin"}}, opts.pairs)
+			},
+		},
+		{name: "no_limit", args: []string{"--rate", "1"}, wantErr: "--duration or --count is required"},
+		{name: "zero_rate", args: []string{"--count", "1", "--rate", "0"}, wantErr: "--rate must be a positive"},
+		{name: "zero_inflight", args: []string{"--count", "1", "--max-inflight", "0"}, wantErr: "--max-inflight must be positive"},
+		{name: "too_many", args: []string{"--rate", "100", "--duration", "1h"}, wantErr: "a run opens at most 10000 orders"},
+		{name: "fill_within_open", args: []string{"--count", "1", "--fill-window", "30m-2h"}, wantErr: "--fill-window must be longer than --open-deadline (1h0m0s)"},
+		{name: "inverted_range", args: []string{"--count", "1", "--amount", "50-5"}, wantErr: `invalid --amount "50-5"`},
+		{name: "bad_pair", args: []string{"--count", "1", "--pairs", "DogCoin"}, wantErr: `invalid --pairs "DogCoin"`},
+		{name: "bad_duration", args: []string{"--duration", "soon"}, wantErr: `invalid --duration "soon"`},
+		{name: "missing_value", args: []string{"--count"}, wantErr: "--count requires a value"},
+		{name: "flag_as_value", args: []string{"--count", "--rate", "1"}, wantErr: "--count requires a value"},
+		{name: "unknown_flag", args: []string{"--count", "1", "--concurrency", "3"}, wantErr: "unknown flag: --concurrency"},
+		{name: "positional", args: []string{"10"}, wantErr: "unexpected argument: 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseGeneratorArgs(tt.args)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, opts)
+		})
+	}
+}
+
+func TestPlanGeneratorOrders(t *testing.T) {
+	config.InitializeNetworks()
+	opts, err := parseGeneratorArgs([]string{"--count", "40", "--amount", "5-50", "--delta", "1-2", "--fill-window", "2h-3h", "--pairs", "DogCoin:OrcaCoin,OrcaCoin:DogCoin"})
+	require.NoError(t, err)
+
+	orders, err := planGeneratorOrders(opts, opts.total())
+	require.NoError(t, err)
+	require.Len(t, orders, 40)
+
+	routes := make(map[string]bool)
+	for i, g := range orders {
+		order := g.order
+		assert.Equal(t, NetworkTypeEVM, GetNetworkType(order.OriginChain), "origin must be EVM")
+		assert.True(t, isValidDestination(order.OriginChain, order.DestinationChain))
+		assert.Equal(t, opts.pairs[i%2].Input, order.InputToken)
+		assert.Equal(t, opts.pairs[i%2].Output, order.OutputToken)
+
+		assert.True(t, order.OutputAmount.Cmp(CreateTokenAmount(5, tokenDecimals)) >= 0)
+		assert.True(t, order.OutputAmount.Cmp(CreateTokenAmount(50, tokenDecimals)) <= 0)
+		delta := new(big.Int).Sub(order.InputAmount, order.OutputAmount)
+		assert.True(t, delta.Cmp(CreateTokenAmount(1, tokenDecimals)) >= 0)
+		assert.True(t, delta.Cmp(CreateTokenAmount(2, tokenDecimals)) <= 0)
+		assert.Equal(t, defaultOpenDeadline, g.windows.Open)
+		assert.GreaterOrEqual(t, g.windows.Fill, 2*time.Hour)
+		assert.LessOrEqual(t, g.windows.Fill, 3*time.Hour)
+
+		if i > 0 {
+			assert.NotEqual(t, orders[i-1].order.OriginChain, order.OriginChain, "origins alternate")
+		}
+		routes[order.OriginChain+"→"+order.DestinationChain] = true
+	}
+	// Both directions of an EVM pair come up
+	assert.True(t, routes["Arbitrum→Base"])
+	assert.True(t, routes["Base→Arbitrum"])
+}
+
+func TestGeneratorStats(t *testing.T) {
+	stats := &generatorStats{}
+	stats.started()
+	stats.started()
+	stats.started()
+	stats.finished(nil, 2*time.Second)
+	stats.finished(nil, 4*time.Second)
+	stats.failedBeforeSend()
+	assert.Equal(t, "opened 2, failed 1, inflight 1, avg open latency 3s", stats.String())
+
+	stats.finished(errors.New("reverted"), time.Second)
+	assert.Equal(t, "opened 2, failed 2, inflight 0, avg open latency 3s", stats.String())
+	assert.Equal(t, "opened 0, failed 0, inflight 0, avg open latency 0s", (&generatorStats{}).String())
+}
@@ -0,0 +1,176 @@
+package openorder
+
+// Network profiles
+// Starknet and Ztarknet orders are the same Cairo order opened with different env vars and addresses, so one
+// implementation (starknet_order.go) opens both from a NetworkProfile: where the origin lives, who signs, how
+// recipients on the destination are mapped and which settler the order is sent to
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// NetworkProfile describes a Cairo origin network (Starknet or Ztarknet) orders are opened on
+type NetworkProfile struct {
+	kind NetworkType
+	// envPrefix prefixes the signer's env vars, e.g. STARKNET (LOCAL_STARKNET on devnet) or ZTARKNET
+	envPrefix        string
+	name             string
+	urls             []string
+	chainID          uint64
+	domain           uint32
+	hyperlaneAddress string
+	alice            cairoSigner
+}
+
+// cairoSigner is the account that signs the orders of a profile
+type cairoSigner struct {
+	// user is the registered user the account belongs to (see pkg/identity)
+	user string
+	// keyPrefix names the signer's key env vars, e.g. STARKNET_ALICE (see pkg/credentials)
+	keyPrefix  string
+	versionEnv string
+}
+
+// newNetworkProfile builds the profile of a configured Starknet-type or Ztarknet network. The Hyperlane address
+// is read from <NAME>_HYPERLANE_ADDRESS first and validated when the network is selected as origin
+func newNetworkProfile(network config.NetworkConfig) NetworkProfile {
+	profile := NetworkProfile{
+		kind:             GetNetworkType(network.Name),
+		name:             network.Name,
+		urls:             network.RPCURLs,
+		chainID:          network.ChainID,
+		domain:           uint32(network.HyperlaneDomain),
+		hyperlaneAddress: getEnvWithDefault(strings.ToUpper(network.Name)+"_HYPERLANE_ADDRESS", network.HyperlaneAddress),
+	}
+	if profile.domain == 0 {
+		profile.domain = uint32(network.ChainID)
+	}
+
+	// Ztarknet is testnet-only and has no LOCAL_ variants; every Starknet-type network signs with Starknet Alice
+	if profile.kind == NetworkTypeZtarknet {
+		profile.envPrefix = "ZTARKNET"
+		profile.alice = cairoSigner{
+			user:       AliceUserName,
+			keyPrefix:  profile.envPrefix + "_ALICE",
+			versionEnv: starknetutil.AccountVersionEnv("Ztarknet", AliceUserName),
+		}
+		return profile
+	}
+	profile.envPrefix = envutil.ConditionalKey("STARKNET")
+	profile.alice = cairoSigner{
+		user:       AliceUserName,
+		keyPrefix:  profile.envPrefix + "_ALICE",
+		versionEnv: starknetutil.AccountVersionEnv("Starknet", AliceUserName),
+	}
+	return profile
+}
+
+// loadNetworkProfiles loads the profile of every network of kind (Starknet or Ztarknet) in cfg
+func loadNetworkProfiles(cfg *config.Config, kind NetworkType) ([]NetworkProfile, error) {
+	var profiles []NetworkProfile
+	for _, networkName := range cfg.NetworkNames() {
+		if GetNetworkType(networkName) != kind {
+			continue
+		}
+		profiles = append(profiles, newNetworkProfile(cfg.Networks[networkName]))
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no %s networks configured", kind.Label())
+	}
+	return profiles, nil
+}
+
+// findNetworkProfile looks up a profile by network name (case-insensitive) and checks its Hyperlane address is set
+func findNetworkProfile(profiles []NetworkProfile, name string) (*NetworkProfile, error) {
+	for i := range profiles {
+		if !strings.EqualFold(profiles[i].name, name) {
+			continue
+		}
+		profile := &profiles[i]
+		if profile.hyperlaneAddress == "" {
+			return nil, missingSettlerError(profile.name)
+		}
+		return profile, nil
+	}
+
+	names := make([]string, len(profiles))
+	kind := NetworkTypeStarknet
+	for i, profile := range profiles {
+		names[i] = profile.name
+		kind = profile.kind
+	}
+	return nil, fmt.Errorf("unknown %s network %q (known: %s)", kind.Label(), name, strings.Join(names, ", "))
+}
+
+// missingKeysError is returned when sending without Alice's key pair
+func (p *NetworkProfile) missingKeysError() error {
+	return fmt.Errorf("missing Alice's %s credentials: %s_PRIVATE_KEY and %s_PUBLIC_KEY are required",
+		p.kind.Label(), p.alice.keyPrefix, p.alice.keyPrefix)
+}
+
+// senderAddress returns the signer's registered address on this network
+func (p *NetworkProfile) senderAddress() (string, error) {
+	return userAddressOn(p.alice.user, p.name)
+}
+
+// signerPublicKey returns the public key registered for the signer's account on this network, empty when none is
+func (p *NetworkProfile) signerPublicKey() (string, error) {
+	id, err := lookupUser(p.alice.user)
+	if err != nil {
+		return "", err
+	}
+	return id.PublicKeyOn(identityChain(p.name))
+}
+
+// recipient maps an order recipient to its OrderData word on destChain; an empty address is the signer's
+// registered address there
+func (p *NetworkProfile) recipient(destChain, address string) (*felt.Felt, error) {
+	if address == "" {
+		var err error
+		if address, err = userAddressOn(p.alice.user, destChain); err != nil {
+			return nil, fmt.Errorf("failed to resolve the recipient: %w", err)
+		}
+	}
+	recipient, err := destinationAddressFelt(destChain, "recipient on "+destChain, address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+	return recipient, nil
+}
+
+// destinationSettler resolves the Hyperlane7683 of destChain (with --force, this profile's own settler when it is
+// missing) as an OrderData word
+func (p *NetworkProfile) destinationSettler(destChain string) (*felt.Felt, error) {
+	settler, err := resolveDestinationSettler(destChain, destinationSettlerAddress(destChain), p.name, p.hyperlaneAddress)
+	if err != nil {
+		return nil, err
+	}
+	settlerFelt, err := destinationAddressFelt(destChain, destChain+" Hyperlane7683", settler)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination settler: %w", err)
+	}
+	return settlerFelt, nil
+}
+
+// destinationAddressFelt encodes an address on networkName as a Cairo ContractAddress: Starknet and Ztarknet
+// addresses are felts, EVM addresses are left-padded to 32 bytes. Zero addresses and words outside the felt field
+// are rejected; name says what the address is, for the errors
+func destinationAddressFelt(networkName, name, address string) (*felt.Felt, error) {
+	word, err := addressWord(networkName, name, address)
+	if err != nil {
+		return nil, err
+	}
+	f, err := starknetutil.Bytes32ToFelt(word)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return f, nil
+}
@@ -4,20 +4,72 @@ package openorder
 // This allows the order creation tools to be imported and run from the main CLI
 
 import (
+	"context"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
 )
 
 // RunOpenOrder runs Alice's order creation tool
-func RunOpenOrder(args []string) {
+func RunOpenOrder(ctx context.Context, args []string) {
+	defer CloseClients()
+
+	args, jsonMode := StripJSONFlag(args)
+	SetJSONOutput(jsonMode)
+
+	args, network, err := ExtractNetworkFlag(args)
+	if err != nil {
+		ExitWithOrderError("", "", err)
+	}
+	args, tokens, err := ExtractTokenFlags(args)
+	if err != nil {
+		ExitWithOrderError("", "", err)
+	}
+	SetTokenSelection(tokens)
+	args, windows, err := ExtractDeadlineFlags(args)
+	if err != nil {
+		ExitWithOrderError("", "", err)
+	}
+	SetDeadlineWindows(windows)
+	args, amounts, err := ExtractAmountFlags(args)
+	if err != nil {
+		ExitWithOrderError("", "", err)
+	}
+	SetOrderAmounts(amounts)
+	args, force := StripForceFlag(args)
+	SetForceFallback(force)
+	args, approve := StripAutoApproveFlag(args)
+	SetAutoApprove(approve)
+	args, permit := StripUsePermitFlag(args)
+	SetUsePermit(permit)
+	args, dry := StripDryRunFlag(args)
+	SetDryRun(dry)
+	args, seed, seeded, err := ExtractSeedFlag(args)
+	if err != nil {
+		ExitWithOrderError("", "", err)
+	}
+	if err := InitRandomness(seed, seeded); err != nil {
+		ExitWithOrderError("", "", err)
+	}
+
 	if len(args) == 0 {
-		fmt.Println("Usage: open-order <chain> [command]")
+		fmt.Println("Usage: open-order <chain> [command] [--network <starknet-network>] [--input-token <symbol|0x>] [--output-token <symbol|0x>] [--open-deadline <duration>] [--fill-deadline <duration>] [--amount-in <tokens>] [--amount-out <tokens>] [--allow-negative-spread] [--auto-approve] [--use-permit] [--force] [--dry-run] [--seed N] [--json]")
 		fmt.Println("Available chains: starknet, ztarknet, evm")
 		os.Exit(1)
 	}
 
 	chain := strings.ToLower(args[0])
+	if network != "" && chain != "starknet" {
+		ExitWithOrderError("", "", fmt.Errorf("%s only applies to Starknet origins, got %s", NetworkFlag, args[0]))
+	}
+	if permit && chain != "evm" {
+		ExitWithOrderError("", "", fmt.Errorf("%s only applies to EVM origins, got %s", UsePermitFlag, args[0]))
+	}
 	command := "default"
 	if len(args) > 1 {
 		command = args[1]
@@ -26,16 +78,71 @@ func RunOpenOrder(args []string) {
 	switch chain {
 	case "starknet":
 		//fmt.Println("🎯 Running Alice's Starknet order creation...")
-		RunStarknetOrder(command)
+		RunStarknetOrder(ctx, command, network)
 	case "ztarknet":
 		//fmt.Println("🎯 Running Alice's Ztarknet order creation...")
-		RunZtarknetOrder(command)
+		RunZtarknetOrder(ctx, command)
 	case "evm":
 		//fmt.Println("🎯 Running Alice's EVM order creation...")
-		RunEVMOrder(command)
+		RunEVMOrder(ctx, command)
 	default:
 		fmt.Printf("Unknown chain: %s\n", chain)
 		fmt.Println("Available chains: starknet, ztarknet, evm")
 		os.Exit(1)
 	}
 }
+
+// OpenOrder opens a single order for Alice from originChain to destinationChain with the selected tokens and
+// returns its result instead of printing it and exiting, for callers driving the tools as a library (e.g. the
+// end-to-end test). Amounts are in 18-decimal units and are scaled to the token decimals like the CLI's
+func OpenOrder(ctx context.Context, originChain, destinationChain string, inputAmount, outputAmount *big.Int) (*OrderResult, error) {
+	result := newOrderResult(originChain, destinationChain, inputAmount, outputAmount)
+	err := openOrder(ctx, originChain, destinationChain, inputAmount, outputAmount, result)
+	result.complete(err)
+	if err != nil {
+		return result, err
+	}
+	if !dryRun {
+		saveOrder(orderstore.New(orderstore.DefaultDir()), result)
+	}
+	return result, nil
+}
+
+func openOrder(ctx context.Context, originChain, destinationChain string, inputAmount, outputAmount *big.Int, result *OrderResult) error {
+	if !isValidDestination(originChain, destinationChain) {
+		return fmt.Errorf("%s cannot open an order to %s", originChain, destinationChain)
+	}
+	kind := GetNetworkType(originChain)
+	if kind != NetworkTypeEVM {
+		origin, err := loadOrigin(kind, originChain)
+		if err != nil {
+			return err
+		}
+		return openCairoOrder(ctx, origin, &StarknetOrderConfig{
+			OriginChain:      origin.name,
+			DestinationChain: destinationChain,
+			InputToken:       tokenSelection.Input,
+			OutputToken:      tokenSelection.Output,
+			InputAmount:      inputAmount,
+			OutputAmount:     outputAmount,
+		}, result)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	logutil.ConfigureFromConfig(logger, cfg)
+	if err := initializeUsers(); err != nil {
+		return err
+	}
+	return openEVMOrder(ctx, &OrderConfig{
+		OriginChain:      originChain,
+		DestinationChain: destinationChain,
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
+		InputAmount:      inputAmount,
+		OutputAmount:     outputAmount,
+		User:             AliceUserName,
+	}, loadNetworks(cfg), result)
+}
@@ -4,209 +4,155 @@ package openorder
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
-// NetworkType represents the type of network
-type NetworkType string
+// NetworkType is the configured type of a network (see config.NetworkType)
+type NetworkType = config.NetworkType
 
 const (
-	NetworkTypeEVM     NetworkType = "evm"
-	NetworkTypeStarknet NetworkType = "starknet"
-	NetworkTypeZtarknet NetworkType = "ztarknet"
+	NetworkTypeEVM      = config.NetworkTypeEVM
+	NetworkTypeStarknet = config.NetworkTypeStarknet
+	NetworkTypeZtarknet = config.NetworkTypeZtarknet
 )
 
-// GetNetworkType determines the network type from a network name
+// GetNetworkType returns the configured type of a network; names that are not configured are EVM
 func GetNetworkType(networkName string) NetworkType {
-	lowerName := strings.ToLower(networkName)
-	if strings.Contains(lowerName, "ztarknet") {
-		return NetworkTypeZtarknet
-	}
-	if strings.Contains(lowerName, "starknet") {
-		return NetworkTypeStarknet
-	}
-	return NetworkTypeEVM
+	return config.NetworkTypeOf(networkName)
 }
 
 // GetRandomDestination gets a random destination chain, excluding the origin
 // Supports all network types: evm, starknet, ztarknet
 func GetRandomDestination(originChain string) (string, error) {
-	allNetworks := config.GetNetworkNames()
-	originType := GetNetworkType(originChain)
-
-	var validDestinations []string
-	for _, networkName := range allNetworks {
-		// Skip the origin chain
-		if networkName == originChain {
-			continue
-		}
-
-		destType := GetNetworkType(networkName)
-
-		// For EVM origins: can go to any EVM chain or any Starknet-type chain
-		if originType == NetworkTypeEVM {
-			validDestinations = append(validDestinations, networkName)
-		} else if originType == NetworkTypeStarknet {
-			// For Starknet origins: can go to any EVM chain or Ztarknet
-			if destType == NetworkTypeEVM || destType == NetworkTypeZtarknet {
-				validDestinations = append(validDestinations, networkName)
-			}
-		} else if originType == NetworkTypeZtarknet {
-			// For Ztarknet origins: can go to any EVM chain or Starknet
-			if destType == NetworkTypeEVM || destType == NetworkTypeStarknet {
-				validDestinations = append(validDestinations, networkName)
-			}
-		}
-	}
-
-	if len(validDestinations) == 0 {
-		return "", fmt.Errorf("no valid destinations found for origin %s", originChain)
-	}
-
-	// Select random destination
-	destIdx := secureRandomInt(len(validDestinations))
-	return validDestinations[destIdx], nil
+	return selectDestination(originChain, anySelector)
 }
 
-// GetDestinationFromArgs gets destination from args, or random if not provided
+// GetDestinationFromArgs gets destination from args, or random if not provided. The argument is a chain
+// selector (see chain_selector.go), e.g. "base", "evm", "any", "base,arbitrum" or "!ethereum"
 func GetDestinationFromArgs(originChain string, args []string, argIndex int) (string, error) {
 	if len(args) > argIndex && args[argIndex] != "" {
-		destChain := args[argIndex]
-		destNormalized := normalizeChainName(destChain)
-		
-		// Handle "evm" as destination - pick a random EVM chain (different from origin)
-		if destNormalized == "evm" {
-			allNetworks := config.GetNetworkNames()
-			var evmNetworks []string
-			
-			for _, networkName := range allNetworks {
-				netType := GetNetworkType(networkName)
-				// Skip origin chain (compare actual names, not normalized)
-				if networkName == originChain {
-					continue
-				}
-				if netType == NetworkTypeEVM {
-					evmNetworks = append(evmNetworks, networkName)
-				}
-			}
-			
-			if len(evmNetworks) == 0 {
-				return "", fmt.Errorf("no EVM networks available as destination (all EVM chains are the same as origin)")
-			}
-			
-			idx := secureRandomInt(len(evmNetworks))
-			return evmNetworks[idx], nil
-		}
-		
-		// Map aliases to actual network names first
-		var actualDestChain string
-		if destNormalized == "starknet" || destNormalized == "strk" {
-			actualDestChain = "Starknet"
-		} else if destNormalized == "ztarknet" || destNormalized == "ztrk" {
-			actualDestChain = "Ztarknet"
-		} else {
-			// Validate destination exists in config and get actual name
-			allNetworks := config.GetNetworkNames()
-			found := false
-			for _, networkName := range allNetworks {
-				if strings.EqualFold(networkName, destChain) {
-					actualDestChain = networkName
-					found = true
-					break
-				}
-			}
-			if !found {
-				return "", fmt.Errorf("destination network not found: %s", destChain)
-			}
-		}
-		
-		// Check if origin and destination are the same (compare actual network names)
-		if originChain == actualDestChain {
-			return "", fmt.Errorf("origin and destination cannot be the same: %s", originChain)
-		}
-		
-		return actualDestChain, nil
+		return selectDestination(originChain, args[argIndex])
 	}
-	
+
 	// No destination provided, get random
 	return GetRandomDestination(originChain)
 }
 
-// normalizeChainName normalizes chain names for comparison
-// evm -> any EVM chain name, strk/starknet -> Starknet, ztrk/ztarknet -> Ztarknet
-func normalizeChainName(chainName string) string {
-	lower := strings.ToLower(chainName)
-	
-	// Handle aliases
-	if lower == "strk" || lower == "starknet" {
-		return "starknet"
+// selectDestination picks a destination for originChain among the networks selector allows
+func selectDestination(originChain, selector string) (string, error) {
+	candidates, err := resolveChainSelector("destination", selector)
+	if err != nil {
+		return "", err
+	}
+
+	// Naming the origin itself is a mistake rather than an empty pick
+	if len(candidates) == 1 && candidates[0] == originChain {
+		return "", fmt.Errorf("origin and destination cannot be the same: %s", originChain)
 	}
-	if lower == "ztrk" || lower == "ztarknet" {
-		return "ztarknet"
+
+	var valid, weightless []string
+	for _, networkName := range candidates {
+		switch {
+		case !isValidDestination(originChain, networkName):
+		case destinationWeight(networkName) == 0:
+			weightless = append(weightless, networkName)
+		default:
+			valid = append(valid, networkName)
+		}
 	}
-	if lower == "evm" {
-		return "evm"
+	if len(valid) == 0 && len(weightless) > 0 {
+		return "", fmt.Errorf("every destination for origin %s matching %q weighs 0 in %s (%s)",
+			originChain, selector, config.DestinationWeightsEnv, strings.Join(weightless, ", "))
 	}
-	
-	// Check if it's an EVM chain
-	allNetworks := config.GetNetworkNames()
-	for _, networkName := range allNetworks {
-		if strings.EqualFold(networkName, chainName) {
-			netType := GetNetworkType(networkName)
-			if netType == NetworkTypeEVM {
-				return "evm"
-			}
-			return strings.ToLower(networkName)
+	if len(valid) == 0 {
+		if selector == anySelector {
+			return "", fmt.Errorf("no valid destinations found for origin %s", originChain)
 		}
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("no destination network matches %q (known: %s)", selector, strings.Join(sortedNetworkNames(), ", "))
+		}
+		return "", fmt.Errorf("no valid destination for origin %s matches %q (candidates: %s)",
+			originChain, selector, strings.Join(candidates, ", "))
 	}
-	
-	return strings.ToLower(chainName)
+	return pickWeighted(valid), nil
 }
 
-// GetOriginFromArgs gets origin chain from args
+// GetOriginFromArgs gets origin chain from args. The argument is a chain selector (see chain_selector.go),
+// e.g. "ethereum", "evm", "any", "base,arbitrum" or "evm,!ethereum"
 func GetOriginFromArgs(args []string, argIndex int) (string, error) {
 	if len(args) <= argIndex {
 		return "", fmt.Errorf("origin chain not provided")
 	}
-	
-	originArg := args[argIndex]
-	originNormalized := normalizeChainName(originArg)
-	
-	// Handle special cases
-	if originNormalized == "evm" {
-		// For "evm", pick a random EVM chain
-		allNetworks := config.GetNetworkNames()
-		var evmNetworks []string
-		for _, networkName := range allNetworks {
-			if GetNetworkType(networkName) == NetworkTypeEVM {
-				evmNetworks = append(evmNetworks, networkName)
+
+	selector := args[argIndex]
+	candidates, err := resolveChainSelector("origin", selector)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no origin network matches %q (known: %s)", selector, strings.Join(sortedNetworkNames(), ", "))
+	}
+	return pickNetwork(candidates), nil
+}
+
+// NetworkFlag selects which Starknet network a Starknet-origin order is opened on
+const NetworkFlag = "--network"
+
+// ExtractNetworkFlag removes "--network <name>" (or "--network=<name>") from args and returns the name
+func ExtractNetworkFlag(args []string) ([]string, string, error) {
+	out := make([]string, 0, len(args))
+	network := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == NetworkFlag:
+			if i+1 >= len(args) || strings.HasPrefix(args[i+1], "--") {
+				return nil, "", fmt.Errorf("%s requires a network name", NetworkFlag)
 			}
+			network = args[i+1]
+			i++
+		case strings.HasPrefix(arg, NetworkFlag+"="):
+			network = strings.TrimPrefix(arg, NetworkFlag+"=")
+			if network == "" {
+				return nil, "", fmt.Errorf("%s requires a network name", NetworkFlag)
+			}
+		default:
+			out = append(out, arg)
 		}
-		if len(evmNetworks) == 0 {
-			return "", fmt.Errorf("no EVM networks configured")
-		}
-		idx := secureRandomInt(len(evmNetworks))
-		return evmNetworks[idx], nil
 	}
-	
-	// Map aliases to actual network names
-	if originNormalized == "starknet" || originNormalized == "strk" {
-		return "Starknet", nil
+	return out, network, nil
+}
+
+// SelectStarknetNetwork applies --network to a Starknet origin and returns the configured network name.
+// An empty network keeps originChain
+func SelectStarknetNetwork(originChain, network string) (string, error) {
+	if network == "" {
+		return originChain, nil
 	}
-	if originNormalized == "ztarknet" || originNormalized == "ztrk" {
-		return "Ztarknet", nil
+	if GetNetworkType(originChain) != NetworkTypeStarknet {
+		return "", fmt.Errorf("%s only applies to Starknet origins, got %s", NetworkFlag, originChain)
 	}
-	
-	// Validate origin exists in config
-	allNetworks := config.GetNetworkNames()
-	for _, networkName := range allNetworks {
-		if strings.EqualFold(networkName, originArg) {
+
+	var known []string
+	for _, networkName := range sortedNetworkNames() {
+		if GetNetworkType(networkName) != NetworkTypeStarknet {
+			continue
+		}
+		if strings.EqualFold(networkName, network) {
 			return networkName, nil
 		}
+		known = append(known, networkName)
 	}
-	
-	return "", fmt.Errorf("origin network not found: %s", originArg)
+	return "", fmt.Errorf("unknown Starknet network %q (known: %s)", network, strings.Join(known, ", "))
+}
+
+// sortedNetworkNames returns the configured network names in a stable order for listings
+func sortedNetworkNames() []string {
+	names := config.GetNetworkNames()
+	sort.Strings(names)
+	return names
 }
 
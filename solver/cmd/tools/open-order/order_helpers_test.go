@@ -0,0 +1,267 @@
+package openorder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func TestExtractNetworkFlag(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantArgs    []string
+		wantNetwork string
+		wantErr     bool
+	}{
+		{name: "absent", args: []string{"starknet", "evm"}, wantArgs: []string{"starknet", "evm"}},
+		{name: "separate_value", args: []string{"starknet", "--network", "Starknet", "evm"}, wantArgs: []string{"starknet", "evm"}, wantNetwork: "Starknet"},
+		{name: "equals_value", args: []string{"starknet", "evm", "--network=Starknet"}, wantArgs: []string{"starknet", "evm"}, wantNetwork: "Starknet"},
+		{name: "missing_value", args: []string{"starknet", "--network"}, wantErr: true},
+		{name: "flag_as_value", args: []string{"starknet", "--network", "--json"}, wantErr: true},
+		{name: "empty_equals", args: []string{"starknet", "--network="}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, network, err := ExtractNetworkFlag(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, args)
+			assert.Equal(t, tt.wantNetwork, network)
+		})
+	}
+}
+
+func TestSelectStarknetNetwork(t *testing.T) {
+	t.Run("no_flag_keeps_origin", func(t *testing.T) {
+		origin, err := SelectStarknetNetwork("Ethereum", "")
+		require.NoError(t, err)
+		assert.Equal(t, "Ethereum", origin)
+	})
+
+	t.Run("case_insensitive_match", func(t *testing.T) {
+		origin, err := SelectStarknetNetwork("Starknet", "starknet")
+		require.NoError(t, err)
+		assert.Equal(t, "Starknet", origin)
+	})
+
+	t.Run("unknown_lists_known_networks", func(t *testing.T) {
+		_, err := SelectStarknetNetwork("Starknet", "StarknetMainnet")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown Starknet network "StarknetMainnet"`)
+		assert.Contains(t, err.Error(), "known: Starknet")
+	})
+
+	t.Run("rejects_non_starknet_origin", func(t *testing.T) {
+		_, err := SelectStarknetNetwork("Ethereum", "Starknet")
+		assert.ErrorContains(t, err, "only applies to Starknet origins")
+	})
+}
+
+func TestGetOriginFromArgsUnknownListsNetworks(t *testing.T) {
+	_, err := GetOriginFromArgs([]string{"solver", "tools", "open-order", "mars"}, 3)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "origin network not found: mars")
+	assert.Contains(t, err.Error(), "Ethereum")
+	assert.Contains(t, err.Error(), "Starknet")
+
+	_, err = GetDestinationFromArgs("Ethereum", []string{"solver", "tools", "open-order", "ethereum", "mars"}, 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "destination network not found: mars")
+	assert.Contains(t, err.Error(), "Optimism")
+}
+
+func TestGetOriginFromArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    []string // the possible picks
+		wantErr string
+	}{
+		{name: "exact_name", arg: "ethereum", want: []string{"Ethereum"}},
+		{name: "starknet_alias", arg: "strk", want: []string{"Starknet"}},
+		{name: "ztarknet_alias", arg: "ZTRK", want: []string{"Ztarknet"}},
+		{name: "evm", arg: "evm", want: []string{"Arbitrum", "Base", "Ethereum", "Optimism"}},
+		{name: "any", arg: "any", want: []string{"Arbitrum", "Base", "Ethereum", "Optimism", "Starknet", "Ztarknet"}},
+		{name: "list", arg: "base,arbitrum", want: []string{"Arbitrum", "Base"}},
+		{name: "list_with_spaces", arg: "base, Arbitrum", want: []string{"Arbitrum", "Base"}},
+		{name: "exclusion_only", arg: "!ethereum", want: []string{"Arbitrum", "Base", "Optimism", "Starknet", "Ztarknet"}},
+		{name: "group_minus_name", arg: "evm,!ethereum,!base", want: []string{"Arbitrum", "Optimism"}},
+		{name: "any_minus_group", arg: "any,!evm", want: []string{"Starknet", "Ztarknet"}},
+		{name: "unknown", arg: "mars", wantErr: "origin network not found: mars (known: Arbitrum, Base, Ethereum"},
+		{name: "unknown_exclusion", arg: "!mars", wantErr: "origin network not found: mars"},
+		{name: "empty_term", arg: "base,", wantErr: `empty origin in "base,"`},
+		{name: "everything_excluded", arg: "base,!base", wantErr: `no origin network matches "base,!base" (known: `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				origin, err := GetOriginFromArgs([]string{"solver", "tools", "open-order", tt.arg}, 3)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Contains(t, tt.want, origin)
+			}
+		})
+	}
+}
+
+func TestGetDestinationFromArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		arg     string
+		want    []string // the possible picks
+		wantErr string
+	}{
+		{name: "exact_name", origin: "Ethereum", arg: "base", want: []string{"Base"}},
+		{name: "omitted_from_evm", origin: "Ethereum", want: []string{"Arbitrum", "Base", "Optimism", "Starknet", "Ztarknet"}},
+		{name: "omitted_from_starknet", origin: "Starknet", want: []string{"Arbitrum", "Base", "Ethereum", "Optimism", "Ztarknet"}},
+		{name: "omitted_from_ztarknet", origin: "Ztarknet", want: []string{"Arbitrum", "Base", "Ethereum", "Optimism", "Starknet"}},
+		{name: "evm_skips_origin", origin: "Ethereum", arg: "evm", want: []string{"Arbitrum", "Base", "Optimism"}},
+		{name: "any_skips_origin", origin: "Base", arg: "any", want: []string{"Arbitrum", "Ethereum", "Optimism", "Starknet", "Ztarknet"}},
+		{name: "list", origin: "Starknet", arg: "base,arbitrum", want: []string{"Arbitrum", "Base"}},
+		{name: "list_containing_origin", origin: "Base", arg: "base,arbitrum", want: []string{"Arbitrum"}},
+		{name: "exclusion", origin: "Starknet", arg: "!ethereum,!ztarknet", want: []string{"Arbitrum", "Base", "Optimism"}},
+		{name: "cairo_group", origin: "Ethereum", arg: "any,!evm", want: []string{"Starknet", "Ztarknet"}},
+		{name: "same_as_origin", origin: "Ethereum", arg: "ethereum", wantErr: "origin and destination cannot be the same: Ethereum"},
+		{name: "ztarknet_to_ztarknet", origin: "Ztarknet", arg: "ztrk", wantErr: "origin and destination cannot be the same: Ztarknet"},
+		{name: "only_origin_left", origin: "Ethereum", arg: "evm,!base,!arbitrum,!optimism", wantErr: "origin and destination cannot be the same: Ethereum"},
+		{name: "nothing_left", origin: "Ethereum", arg: "base,!base", wantErr: `no destination network matches "base,!base" (known: Arbitrum`},
+		{name: "unknown", origin: "Ethereum", arg: "mars", wantErr: "destination network not found: mars (known: Arbitrum, Base, Ethereum"},
+		{name: "unknown_in_list", origin: "Ethereum", arg: "base,mars", wantErr: "destination network not found: mars"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"solver", "tools", "open-order", tt.origin}
+			if tt.arg != "" {
+				args = append(args, tt.arg)
+			}
+			for range 20 {
+				destination, err := GetDestinationFromArgs(tt.origin, args, 4)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Contains(t, tt.want, destination)
+			}
+		})
+	}
+}
+
+func TestExtractSeedFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantArgs []string
+		wantSeed uint64
+		wantOK   bool
+		wantErr  bool
+	}{
+		{name: "absent", args: []string{"evm", "any"}, wantArgs: []string{"evm", "any"}},
+		{name: "separate_value", args: []string{"evm", "--seed", "42", "any"}, wantArgs: []string{"evm", "any"}, wantSeed: 42, wantOK: true},
+		{name: "equals_value", args: []string{"evm", "any", "--seed=7"}, wantArgs: []string{"evm", "any"}, wantSeed: 7, wantOK: true},
+		{name: "missing_value", args: []string{"evm", "--seed"}, wantErr: true},
+		{name: "flag_as_value", args: []string{"evm", "--seed", "--json"}, wantErr: true},
+		{name: "not_a_number", args: []string{"evm", "--seed=abc"}, wantErr: true},
+		{name: "negative", args: []string{"evm", "--seed", "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, seed, ok, err := ExtractSeedFlag(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, args)
+			assert.Equal(t, tt.wantSeed, seed)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestSeededPicksAreDeterministic(t *testing.T) {
+	t.Cleanup(clearSeed)
+
+	picks := func(seed uint64) []string {
+		SetSeed(seed)
+		var out []string
+		for range 10 {
+			origin, err := GetOriginFromArgs([]string{"solver", "tools", "open-order", "any"}, 3)
+			require.NoError(t, err)
+			destination, err := GetDestinationFromArgs(origin, nil, 4)
+			require.NoError(t, err)
+			out = append(out, origin, destination)
+		}
+		return out
+	}
+
+	first := picks(42)
+	assert.Equal(t, first, picks(42))
+	assert.NotEqual(t, first, picks(43))
+}
+
+func TestSeededOrdersAreReproducible(t *testing.T) {
+	t.Cleanup(clearSeed)
+	networks := loadNetworks(&config.Config{Networks: config.ReloadNetworks()})
+
+	orders := func(seed uint64) []OrderConfig {
+		SetSeed(seed)
+		out, err := randomBatchOrders(20, networks)
+		require.NoError(t, err)
+		return out
+	}
+
+	first := orders(7)
+	assert.Equal(t, first, orders(7), "a seed replays the networks and amounts")
+	assert.NotEqual(t, first, orders(8))
+}
+
+func TestWeightedDestinations(t *testing.T) {
+	t.Cleanup(clearSeed)
+	t.Cleanup(func() { SetDestinationWeights(nil) })
+	SetSeed(1)
+	SetDestinationWeights(map[string]int{"Base": 6, "Arbitrum": 2, "Optimism": 0})
+
+	const picks = 8000
+	counts := map[string]int{}
+	for range picks {
+		destination, err := selectDestination("Ethereum", "evm")
+		require.NoError(t, err)
+		counts[destination]++
+	}
+
+	// Base:Arbitrum:Optimism weigh 6:2:0, so Base takes 3/4 of the picks and Arbitrum 1/4; 4 standard
+	// deviations (about 0.02 of the picks) keep the test stable while catching a uniform pick
+	assert.Zero(t, counts["Optimism"], "a zero weight is never picked")
+	assert.Zero(t, counts["Ethereum"], "the origin is never picked")
+	assert.InDelta(t, 0.75, float64(counts["Base"])/picks, 0.02)
+	assert.InDelta(t, 0.25, float64(counts["Arbitrum"])/picks, 0.02)
+}
+
+func TestZeroWeightDestinations(t *testing.T) {
+	t.Cleanup(func() { SetDestinationWeights(nil) })
+	SetDestinationWeights(map[string]int{"Optimism": 0})
+
+	_, err := selectDestination("Ethereum", "optimism")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "weighs 0 in DESTINATION_WEIGHTS")
+
+	destination, err := selectDestination("Ethereum", "optimism,base")
+	require.NoError(t, err)
+	assert.Equal(t, "Base", destination)
+}
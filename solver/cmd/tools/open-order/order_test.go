@@ -6,8 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/identity"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
 // TestOrderOpening tests the order opening functionality
@@ -55,8 +62,10 @@ func TestOrderDataValidation(t *testing.T) {
 			OriginChainID:      big.NewInt(1),
 			DestinationChainID: big.NewInt(421614), // Base Sepolia
 			User:               "0x1234567890123456789012345678901234567890",
-			OpenDeadline:       big.NewInt(time.Now().Add(1 * time.Hour).Unix()),
-			FillDeadline:       big.NewInt(time.Now().Add(2 * time.Hour).Unix()),
+			Deadlines: orderdeadline.Deadlines{
+				Open: orderdeadline.Deadline(time.Now().Add(1 * time.Hour).Unix()),
+				Fill: orderdeadline.Deadline(time.Now().Add(2 * time.Hour).Unix()),
+			},
 			MaxSpent: []TokenAmount{
 				{
 					Token:  "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd",
@@ -73,8 +82,12 @@ func TestOrderDataValidation(t *testing.T) {
 
 		// Test order data validation
 		assert.True(t, orderData.IsValid(), "Order data should be valid")
-		assert.True(t, orderData.OpenDeadline.Cmp(big.NewInt(time.Now().Unix())) > 0, "Open deadline should be in the future")
-		assert.True(t, orderData.FillDeadline.Cmp(orderData.OpenDeadline) > 0, "Fill deadline should be after open deadline")
+		assert.Greater(t, uint64(orderData.Deadlines.Open), uint64(time.Now().Unix()), "Open deadline should be in the future")
+		assert.Greater(t, orderData.Deadlines.Fill, orderData.Deadlines.Open, "Fill deadline should be after open deadline")
+
+		// A fill deadline past uint32 would be truncated on chain, so it invalidates the order
+		orderData.Deadlines.Fill = orderdeadline.Max + 1
+		assert.False(t, orderData.IsValid(), "Order data with an overflowing fill deadline should be invalid")
 	})
 
 	t.Run("Invalid order data", func(t *testing.T) {
@@ -82,8 +95,10 @@ func TestOrderDataValidation(t *testing.T) {
 			OriginChainID:      big.NewInt(1),
 			DestinationChainID: big.NewInt(1), // Same as origin - invalid
 			User:               "invalid_address",
-			OpenDeadline:       big.NewInt(time.Now().Add(-1 * time.Hour).Unix()), // Past deadline
-			FillDeadline:       big.NewInt(time.Now().Add(1 * time.Hour).Unix()),
+			Deadlines: orderdeadline.Deadlines{
+				Open: orderdeadline.Deadline(time.Now().Add(-1 * time.Hour).Unix()), // Past deadline
+				Fill: orderdeadline.Deadline(time.Now().Add(1 * time.Hour).Unix()),
+			},
 		}
 
 		assert.False(t, orderData.IsValid(), "Order data should be invalid")
@@ -150,3 +165,217 @@ func TestBalanceVerification(t *testing.T) {
 		assert.False(t, hasSufficientBalance, "User should not have sufficient balance")
 	})
 }
+
+const (
+	testEVMAlice         = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	testStarknetAlice    = "0x13d9ee239f33fea4f8785b9e3870ade909e20a9599ae7cd62c1c292b73af1b7"
+	testStarknetDogCoin  = "0x0747adc6b3a9e0a2a5b9a2d4b4bd47ef9d34a1ae1b3ac0d2b0b10b8d4f0a11aa"
+	testStarknetSettler  = "0x0512a53f27a8b62e6d4c9d5d6fe3c1b5e5b2f7d3a0b1c2d3e4f5a6b7c8d9e0f1"
+	testEthereumDomain   = 11155111
+	testEthereumDogCoin  = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	testEthereumSettler  = "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"
+	testOptimismDogCoin  = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
+	testFeltOutOfRange   = "0x0800000000000011000000000000000000000000000000000000000000000001"
+	testOrderSenderNonce = 7
+)
+
+// useTestAlice pins the addresses buildOrderData and convertToABIOrderData resolve Alice to
+func useTestAlice(t *testing.T) {
+	registry, err := identity.New([]identity.Identity{
+		{Name: AliceUserName, EVMAddress: testEVMAlice, StarknetAddress: testStarknetAlice},
+	})
+	require.NoError(t, err)
+	saved := users
+	users = registry
+	t.Cleanup(func() { users = saved })
+}
+
+// testTokens pairs Ethereum's DogCoin with outputAddress on the destination, as resolved from .env
+func testTokens(destination, outputAddress string) *orderTokens {
+	return &orderTokens{
+		Input:  orderToken{Network: "Ethereum", Symbol: DefaultOrderToken, Address: testEthereumDogCoin, Source: "ETHEREUM_DOG_COIN_ADDRESS", Decimals: tokenDecimals},
+		Output: orderToken{Network: destination, Symbol: DefaultOrderToken, Address: outputAddress, Source: tokenspec.EnvName(destination, DefaultOrderToken), Decimals: tokenDecimals},
+	}
+}
+
+func testOrderConfig() *OrderConfig {
+	return &OrderConfig{
+		User:         AliceUserName,
+		InputAmount:  big.NewInt(1001),
+		OutputAmount: big.NewInt(1000),
+		Deadlines:    orderdeadline.Deadlines{Open: 1_800_000_000, Fill: 1_900_000_000},
+	}
+}
+
+// orderDataWord returns field i of abi.encode(OrderData); the tuple is dynamic, so word 0 is its offset
+func orderDataWord(encoded []byte, i int) []byte {
+	return encoded[32+32*i : 64+32*i]
+}
+
+func paddedWord(hexStr string) []byte {
+	return common.LeftPadBytes(common.FromHex(hexStr), 32)
+}
+
+func TestBuildOrderDataStarknetDestination(t *testing.T) {
+	useTestAlice(t)
+
+	destination := &NetworkConfig{name: "Starknet", hyperlaneAddress: testStarknetSettler}
+
+	orderData, err := buildOrderData(testOrderConfig(), testTokens("Starknet", testStarknetDogCoin), destination, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	assert.Equal(t, testStarknetAlice, orderData.Recipient)
+
+	encoded, err := encodeOrderData(&orderData, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	require.Len(t, encoded, 32+13*32) // offset, 12 head words, empty data length
+
+	assert.Equal(t, paddedWord(testEVMAlice), orderDataWord(encoded, 0), "sender")
+	assert.Equal(t, paddedWord(testStarknetAlice), orderDataWord(encoded, 1), "recipient")
+	assert.Equal(t, paddedWord(testEthereumDogCoin), orderDataWord(encoded, 2), "inputToken")
+	assert.Equal(t, paddedWord(testStarknetDogCoin), orderDataWord(encoded, 3), "outputToken")
+	assert.Equal(t, paddedWord("0x3e9"), orderDataWord(encoded, 4), "amountIn")
+	assert.Equal(t, paddedWord("0x3e8"), orderDataWord(encoded, 5), "amountOut")
+	assert.Equal(t, paddedWord("0x07"), orderDataWord(encoded, 6), "senderNonce")
+	assert.Equal(t, paddedWord(testStarknetSettler), orderDataWord(encoded, 9), "destinationSettler")
+
+	// Felts use the full 32-byte word, not a 20-byte EVM address
+	assert.NotZero(t, orderDataWord(encoded, 3)[0], "outputToken high byte")
+	assert.NotZero(t, orderDataWord(encoded, 9)[0], "destinationSettler high byte")
+}
+
+func TestBuildOrderDataEVMDestination(t *testing.T) {
+	useTestAlice(t)
+
+	destination := &NetworkConfig{name: "Optimism", hyperlaneAddress: testEthereumSettler}
+
+	orderData, err := buildOrderData(testOrderConfig(), testTokens("Optimism", testOptimismDogCoin), destination, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+
+	encoded, err := encodeOrderData(&orderData, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	assert.Equal(t, paddedWord(testEVMAlice), orderDataWord(encoded, 1), "recipient")
+	assert.Equal(t, paddedWord(testOptimismDogCoin), orderDataWord(encoded, 3), "outputToken")
+	assert.Equal(t, paddedWord(testEthereumSettler), orderDataWord(encoded, 9), "destinationSettler")
+}
+
+func TestBuildOrderDataResolvesUsers(t *testing.T) {
+	const bobEVM = "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"
+	registry, err := identity.New([]identity.Identity{
+		{Name: AliceUserName, EVMAddress: testEVMAlice, StarknetAddress: testStarknetAlice},
+		{Name: "Bob", EVMAddress: bobEVM},
+	})
+	require.NoError(t, err)
+	saved := users
+	users = registry
+	t.Cleanup(func() { users = saved })
+
+	optimism := &NetworkConfig{name: "Optimism", hyperlaneAddress: testEthereumSettler}
+	order := testOrderConfig()
+	order.User = "bob"
+	orderData, err := buildOrderData(order, testTokens("Optimism", testOptimismDogCoin), optimism, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	encoded, err := encodeOrderData(&orderData, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	assert.Equal(t, paddedWord(bobEVM), orderDataWord(encoded, 0), "sender")
+	assert.Equal(t, paddedWord(bobEVM), orderDataWord(encoded, 1), "recipient")
+
+	starknet := &NetworkConfig{name: "Starknet", hyperlaneAddress: testStarknetSettler}
+	_, err = buildOrderData(order, testTokens("Starknet", testStarknetDogCoin), starknet, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	assert.ErrorContains(t, err, "user Bob has no Starknet address")
+
+	order.User = "Mallory"
+	_, err = buildOrderData(order, testTokens("Optimism", testOptimismDogCoin), optimism, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	assert.ErrorContains(t, err, `unknown user "Mallory" (known: Alice, Bob)`)
+
+	orderData.User = "Mallory"
+	_, err = encodeOrderData(&orderData, big.NewInt(testOrderSenderNonce))
+	assert.ErrorContains(t, err, "failed to resolve the sender", "an unknown sender is not encoded as zero")
+}
+
+func TestBuildOrderDataRejectsUnsetTokens(t *testing.T) {
+	useTestAlice(t)
+	destination := &NetworkConfig{name: "Optimism", hyperlaneAddress: testEthereumSettler}
+
+	tokens := testTokens("Optimism", testOptimismDogCoin)
+	tokens.Input.Address = ""
+	_, err := buildOrderData(testOrderConfig(), tokens, destination, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	assert.EqualError(t, err, "input token: ETHEREUM_DOG_COIN_ADDRESS not set")
+
+	tokens.Input.Address = "0x0000000000000000000000000000000000000000"
+	_, err = buildOrderData(testOrderConfig(), tokens, destination, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	assert.EqualError(t, err, "input token: ETHEREUM_DOG_COIN_ADDRESS must not be zero")
+}
+
+func TestBuildOrderDataStarknetDestinationErrors(t *testing.T) {
+	useTestAlice(t)
+
+	tests := []struct {
+		name        string
+		destination NetworkConfig
+		outputToken string
+		errContains string
+	}{
+		{
+			name:        "missing_settler",
+			destination: NetworkConfig{name: "Starknet"},
+			outputToken: testStarknetDogCoin,
+			errContains: "STARKNET_HYPERLANE_ADDRESS not set",
+		},
+		{
+			name:        "settler_exceeds_felt",
+			destination: NetworkConfig{name: "Starknet", hyperlaneAddress: testFeltOutOfRange},
+			outputToken: testStarknetDogCoin,
+			errContains: "STARKNET_HYPERLANE_ADDRESS is not a valid felt",
+		},
+		{
+			name:        "zero_token",
+			destination: NetworkConfig{name: "Starknet", hyperlaneAddress: testStarknetSettler},
+			outputToken: "0x0",
+			errContains: "STARKNET_DOG_COIN_ADDRESS must not be zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildOrderData(testOrderConfig(), testTokens("Starknet", tt.outputToken), &tt.destination, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestBuildOrderDataEVMDestinationErrors(t *testing.T) {
+	useTestAlice(t)
+
+	settlers := map[string]string{
+		"": "OPTIMISM_HYPERLANE_ADDRESS not set",
+		"0x0000000000000000000000000000000000000000":           "OPTIMISM_HYPERLANE_ADDRESS must not be zero",
+		"0xffffffffffffffffffffffff" + testEthereumSettler[2:]: "OPTIMISM_HYPERLANE_ADDRESS is not an EVM address",
+	}
+	for settler, errContains := range settlers {
+		destination := &NetworkConfig{name: "Optimism", hyperlaneAddress: settler}
+		_, err := buildOrderData(testOrderConfig(), testTokens("Optimism", testOptimismDogCoin), destination, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+		require.Error(t, err, settler)
+		assert.Contains(t, err.Error(), errContains)
+	}
+}
+
+func TestBuildOrderDataRejectsUnroutableDomains(t *testing.T) {
+	useTestAlice(t)
+	t.Cleanup(config.ResetNetworks)
+	config.ReloadNetworks()
+
+	mars := &NetworkConfig{name: "Mars", hyperlaneAddress: testEthereumSettler}
+	_, err := buildOrderData(testOrderConfig(), testTokens("Optimism", testOptimismDogCoin), mars, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	require.ErrorIs(t, err, config.ErrUnknownNetwork)
+	assert.ErrorContains(t, err, "invalid destination Mars: network not found: Mars (known: Arbitrum (domain 421614), Base (domain 84532)")
+
+	optimism := &NetworkConfig{name: "Optimism", hyperlaneAddress: testEthereumSettler}
+	_, err = buildOrderData(testOrderConfig(), testTokens("Optimism", testOptimismDogCoin), optimism, uint32(config.OptimismSepoliaChainID), big.NewInt(testOrderSenderNonce))
+	assert.ErrorContains(t, err, "origin and destination must differ")
+
+	t.Setenv("CUSTOM_DOMAIN_OPTIMISM", "0")
+	config.ReloadNetworks()
+	_, err = buildOrderData(testOrderConfig(), testTokens("Optimism", testOptimismDogCoin), optimism, testEthereumDomain, big.NewInt(testOrderSenderNonce))
+	assert.ErrorContains(t, err, "Optimism has Hyperlane domain 0")
+}
@@ -0,0 +1,45 @@
+package openorder
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// orderResolver is the resolve() view of the generated Hyperlane7683 binding
+type orderResolver interface {
+	Resolve(opts *bind.CallOpts, order contracts.OnchainCrossChainOrder) (contracts.ResolvedCrossChainOrder, error)
+}
+
+// verifyEVMOrderDataType checks the Solidity Hyperlane7683 on networkName accepts the order data type hash before
+// anything is sent. Results are cached per contract, so batch runs only probe once
+func verifyEVMOrderDataType(ctx context.Context, contract orderResolver, networkName string, address common.Address, order contracts.OnchainCrossChainOrder) error {
+	sent := starknetorder.OrderDataTypeCandidate{
+		Source: "OrderData type string",
+		Hash:   new(big.Int).SetBytes(order.OrderDataType[:]),
+	}
+	candidates := append([]starknetorder.OrderDataTypeCandidate{sent}, starknetorder.KnownOrderDataTypes()...)
+	return starknetorder.CheckOrderDataType(ctx, networkName+" "+address.Hex(), sent, candidates, evmOrderDataTypeProbe(contract, order))
+}
+
+// evmOrderDataTypeProbe calls resolve(order) with the given type hash; only InvalidOrderType counts as a rejection
+func evmOrderDataTypeProbe(contract orderResolver, order contracts.OnchainCrossChainOrder) starknetorder.OrderDataTypeProbe {
+	return func(ctx context.Context, orderDataType *big.Int) (bool, error) {
+		order.OrderDataType = common.BigToHash(orderDataType)
+		_, err := contract.Resolve(&bind.CallOpts{Context: ctx}, order)
+		if err == nil {
+			return true, nil
+		}
+		revert := ethutil.DecodeRevertError(err)
+		if revert == nil {
+			return false, err
+		}
+		return revert.Name != "InvalidOrderType", nil
+	}
+}
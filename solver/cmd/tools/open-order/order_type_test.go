@@ -0,0 +1,78 @@
+package openorder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// revertDataError mimics the JSON-RPC error geth returns for a reverted eth_call
+type revertDataError struct {
+	data string
+}
+
+func (e revertDataError) Error() string          { return "execution reverted" }
+func (e revertDataError) ErrorData() interface{} { return e.data }
+
+// fakeResolver accepts one order data type and reverts with InvalidOrderType for every other one
+type fakeResolver struct {
+	accept common.Hash
+	err    error
+	calls  int
+}
+
+func (f *fakeResolver) Resolve(_ *bind.CallOpts, order contracts.OnchainCrossChainOrder) (contracts.ResolvedCrossChainOrder, error) {
+	f.calls++
+	if f.err != nil {
+		return contracts.ResolvedCrossChainOrder{}, f.err
+	}
+	if order.OrderDataType != f.accept {
+		return contracts.ResolvedCrossChainOrder{}, revertDataError{data: "0x8703f772" + common.Hash(order.OrderDataType).Hex()[2:]}
+	}
+	return contracts.ResolvedCrossChainOrder{}, nil
+}
+
+func TestVerifyEVMOrderDataType(t *testing.T) {
+	t.Setenv("ORDER_DATA_TYPE_HASH", "")
+	order := contracts.OnchainCrossChainOrder{FillDeadline: 1700000000, OrderDataType: getOrderDataTypeHash(), OrderData: []byte{0x01}}
+	address := common.HexToAddress("0x7683")
+
+	t.Run("accepted once per contract", func(t *testing.T) {
+		resolver := &fakeResolver{accept: getOrderDataTypeHash()}
+		require.NoError(t, verifyEVMOrderDataType(context.Background(), resolver, "Accepted", address, order))
+		require.NoError(t, verifyEVMOrderDataType(context.Background(), resolver, "Accepted", address, order))
+		assert.Equal(t, 1, resolver.calls)
+	})
+
+	t.Run("stale hash reports the one the contract accepts", func(t *testing.T) {
+		stale := order
+		stale.OrderDataType = common.Hash{0x01}
+		resolver := &fakeResolver{accept: getOrderDataTypeHash()}
+		err := verifyEVMOrderDataType(context.Background(), resolver, "Stale", address, stale)
+
+		var mismatch *starknetorder.OrderDataTypeMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		require.NotNil(t, mismatch.Accepted)
+		assert.Equal(t, "built-in default", mismatch.Accepted.Source)
+		assert.Contains(t, err.Error(), "Stale "+address.Hex())
+	})
+
+	t.Run("other reverts mean the type hash passed", func(t *testing.T) {
+		// InvalidOriginDomain(23448594)
+		resolver := &fakeResolver{err: revertDataError{data: "0x56632acd000000000000000000000000000000000000000000000000000000000165cc12"}}
+		require.NoError(t, verifyEVMOrderDataType(context.Background(), resolver, "OtherRevert", address, order))
+	})
+
+	t.Run("RPC failures are returned", func(t *testing.T) {
+		resolver := &fakeResolver{err: errors.New("connection refused")}
+		assert.ErrorContains(t, verifyEVMOrderDataType(context.Background(), resolver, "Unreachable", address, order), "connection refused")
+	})
+}
@@ -0,0 +1,220 @@
+package openorder
+
+// Output routing for the open-order tool
+// Progress is logged as human-readable text; with --json the log is silenced and a single
+// OrderResult document is printed once the order has been opened (or has failed)
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// JSONFlag switches open-order to machine-readable output
+const JSONFlag = "--json"
+
+// Order statuses reported in OrderResult
+const (
+	OrderStatusOpened = "opened"
+	OrderStatusFailed = "failed"
+	// OrderStatusSimulated is a --dry-run whose simulated open() succeeded; nothing was sent
+	OrderStatusSimulated = "simulated"
+)
+
+// OrderResult is the JSON document printed for a single order in --json mode
+type OrderResult struct {
+	OrderID          string `json:"orderId"`
+	TxHash           string `json:"txHash"`
+	OriginChain      string `json:"originChain"`
+	DestinationChain string `json:"destinationChain"`
+	InputAmount      string `json:"inputAmount"`
+	OutputAmount     string `json:"outputAmount"`
+	InputToken       string `json:"inputToken,omitempty"`
+	OutputToken      string `json:"outputToken,omitempty"`
+	SenderNonce      string `json:"senderNonce"`
+	GasQuote         string `json:"gasQuote,omitempty"`
+	GasUsed          uint64 `json:"gasUsed"`
+	Status           string `json:"status"`
+	Error            string `json:"error,omitempty"`
+
+	// The order struct as opened, so it can be rebuilt later (e.g. by refund-order) without reading the chain
+	Gasless       bool   `json:"gasless,omitempty"`
+	User          string `json:"user,omitempty"`
+	OpenDeadline  uint64 `json:"openDeadline,omitempty"`
+	FillDeadline  uint64 `json:"fillDeadline,omitempty"`
+	OrderDataType string `json:"orderDataType,omitempty"`
+	OrderData     string `json:"orderData,omitempty"`
+	// TxOrders lists every order opened by TxHash when it opened several (Multicall3 batches)
+	TxOrders []string `json:"txOrders,omitempty"`
+
+	// Calldata of the simulated transaction (--dry-run only): open() calldata on EVM, comma-separated
+	// __execute__ calldata on Starknet
+	Calldata string `json:"calldata,omitempty"`
+}
+
+var (
+	jsonOutput bool
+	logOutput  io.Writer = os.Stdout
+	// logger carries the progress output; LOG_LEVEL and LOG_FORMAT are applied once the config is loaded
+	logger = logutil.NewToolLogger()
+)
+
+// SetJSONOutput silences progress logging and reports orders as a single JSON document
+func SetJSONOutput(enabled bool) {
+	jsonOutput = enabled
+	if enabled {
+		logOutput = io.Discard
+	} else {
+		logOutput = os.Stdout
+	}
+	logger.SetOutput(logOutput)
+}
+
+// StripJSONFlag removes --json from args and reports whether it was present
+func StripJSONFlag(args []string) ([]string, bool) {
+	return stripBoolFlag(args, JSONFlag)
+}
+
+// logf prints progress output at info level unless JSON mode is enabled
+func logf(format string, args ...interface{}) {
+	logger.Infof(format, args...)
+}
+
+// logln prints a progress line at info level unless JSON mode is enabled
+func logln(args ...interface{}) {
+	logger.Infoln(args...)
+}
+
+// debugf prints raw dumps (calldata, transaction data) that are only shown with LOG_LEVEL=debug
+func debugf(format string, args ...interface{}) {
+	logger.Debugf(format, args...)
+}
+
+// warnf prints a warning that does not stop the order
+func warnf(format string, args ...interface{}) {
+	logger.Warnf(format, args...)
+}
+
+// errorf prints a failure
+func errorf(format string, args ...interface{}) {
+	logger.Errorf(format, args...)
+}
+
+// newOrderResult starts a result for an order; the remaining fields are filled in as it progresses
+func newOrderResult(originChain, destinationChain string, inputAmount, outputAmount *big.Int) *OrderResult {
+	result := &OrderResult{
+		OriginChain:      originChain,
+		DestinationChain: destinationChain,
+	}
+	if inputAmount != nil {
+		result.InputAmount = inputAmount.String()
+	}
+	if outputAmount != nil {
+		result.OutputAmount = outputAmount.String()
+	}
+	return result
+}
+
+// recordOrder stores the opened order struct in the result
+func (r *OrderResult) recordOrder(fillDeadline uint64, orderDataType [32]byte, orderData []byte) {
+	r.FillDeadline = fillDeadline
+	r.OrderDataType = hexutil.Encode(orderDataType[:])
+	r.OrderData = hexutil.Encode(orderData)
+}
+
+// complete sets the final status from the order error
+func (r *OrderResult) complete(err error) {
+	if err != nil {
+		r.Status = OrderStatusFailed
+		r.Error = err.Error()
+		return
+	}
+	r.Status = OrderStatusOpened
+	if dryRun {
+		r.Status = OrderStatusSimulated
+	}
+	r.Error = ""
+}
+
+// writeOrderResult writes the result as a single JSON document
+func writeOrderResult(w io.Writer, result *OrderResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// ExitWithOrderError reports an order that could not be started (e.g. bad arguments) and exits with status 1
+func ExitWithOrderError(originChain, destinationChain string, err error) {
+	failOrder(originChain, destinationChain, err)
+}
+
+// failOrder reports an order that failed before it could be opened
+func failOrder(originChain, destinationChain string, err error) {
+	finishOrder(newOrderResult(originChain, destinationChain, nil, nil), err)
+}
+
+// finishOrder reports the outcome of an order and exits non-zero if it failed.
+// In JSON mode the result is printed for failures too, so callers always get a body to parse.
+// Simulated orders were never opened, so they are not recorded in the order store
+func finishOrder(result *OrderResult, err error) {
+	result.complete(err)
+	if err == nil && !dryRun {
+		saveOrder(orderstore.New(orderstore.DefaultDir()), result)
+	}
+	if jsonOutput {
+		if writeErr := writeOrderResult(os.Stdout, result); writeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to write JSON result: %v\n", writeErr)
+		}
+	} else if err != nil {
+		errorf("❌ %v\n", err)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// saveOrder records an opened order in the local order store so later tools can resolve it by ID.
+// A store failure is only reported: the order is already on chain
+func saveOrder(store *orderstore.Store, result *OrderResult) {
+	if result.OrderID == "" {
+		return
+	}
+	if err := store.Save(storedOrder(result)); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  failed to record order %s in %s: %v\n", result.OrderID, store.Dir(), err)
+	}
+}
+
+// storedOrder converts a result into its order store record. The user is the one the Open event named, or else
+// the sender word of the order data
+func storedOrder(result *OrderResult) *orderstore.Order {
+	order := &orderstore.Order{
+		OrderID:          result.OrderID,
+		OriginChain:      result.OriginChain,
+		DestinationChain: result.DestinationChain,
+		TxHash:           result.TxHash,
+		Gasless:          result.Gasless,
+		OpenDeadline:     result.OpenDeadline,
+		FillDeadline:     result.FillDeadline,
+		OrderDataType:    result.OrderDataType,
+		OrderData:        result.OrderData,
+		User:             result.User,
+		TxOrders:         result.TxOrders,
+		Status:           orderstore.StatusOpened,
+	}
+	if order.User != "" {
+		return order
+	}
+	// OrderData starts with its offset word, followed by the sender
+	if data, err := hexutil.Decode(result.OrderData); err == nil && len(data) >= 64 {
+		order.User = common.BytesToHash(data[32:64]).Hex()
+	}
+	return order
+}
@@ -0,0 +1,186 @@
+package openorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderstore"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+)
+
+func TestStripJSONFlag(t *testing.T) {
+	args, found := StripJSONFlag([]string{"solver", "tools", "open-order", "--json", "starknet", "evm"})
+	assert.True(t, found)
+	assert.Equal(t, []string{"solver", "tools", "open-order", "starknet", "evm"}, args)
+
+	args, found = StripJSONFlag([]string{"solver", "tools", "open-order", "evm"})
+	assert.False(t, found)
+	assert.Equal(t, []string{"solver", "tools", "open-order", "evm"}, args)
+}
+
+func TestSetJSONOutput(t *testing.T) {
+	t.Cleanup(func() { SetJSONOutput(false) })
+
+	SetJSONOutput(true)
+	assert.True(t, jsonOutput)
+	assert.Equal(t, io.Discard, logOutput)
+
+	SetJSONOutput(false)
+	assert.False(t, jsonOutput)
+	assert.Equal(t, io.Writer(os.Stdout), logOutput)
+}
+
+func TestLogLevels(t *testing.T) {
+	saved := logger.GetLevel()
+	t.Cleanup(func() {
+		logger.SetLevel(saved)
+		SetJSONOutput(false)
+	})
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	logf("   Transaction sent: %s\n", "0x01")
+	debugf("📝 Transaction data: 0x%x\n", []byte{0x01})
+	errorf("❌ %v\n", "reverted")
+	assert.Equal(t, "   Transaction sent: 0x01\n❌ reverted\n", buf.String(), "debug dumps are hidden at info level")
+
+	buf.Reset()
+	logger.SetLevel(logrus.ErrorLevel)
+	logf("   Transaction sent: %s\n", "0x01")
+	errorf("❌ %v\n", "reverted")
+	assert.Equal(t, "❌ reverted\n", buf.String())
+
+	SetJSONOutput(true)
+	assert.Equal(t, io.Discard, logger.Out)
+}
+
+func TestWriteOrderResult(t *testing.T) {
+	t.Run("opened", func(t *testing.T) {
+		result := newOrderResult("Ethereum", "Starknet", big.NewInt(1001), big.NewInt(1000))
+		result.OrderID = "0x01"
+		result.TxHash = "0x02"
+		result.SenderNonce = "7"
+		result.GasUsed = 21000
+		result.complete(nil)
+
+		var buf bytes.Buffer
+		require.NoError(t, writeOrderResult(&buf, result))
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		assert.Equal(t, map[string]interface{}{
+			"orderId":          "0x01",
+			"txHash":           "0x02",
+			"originChain":      "Ethereum",
+			"destinationChain": "Starknet",
+			"inputAmount":      "1001",
+			"outputAmount":     "1000",
+			"senderNonce":      "7",
+			"gasUsed":          float64(21000),
+			"status":           OrderStatusOpened,
+		}, doc)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		result := newOrderResult("Starknet", "Base", nil, nil)
+		result.complete(errors.New("insufficient token balance for order creation"))
+
+		var buf bytes.Buffer
+		require.NoError(t, writeOrderResult(&buf, result))
+
+		var decoded OrderResult
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, OrderStatusFailed, decoded.Status)
+		assert.Equal(t, "insufficient token balance for order creation", decoded.Error)
+		assert.Empty(t, decoded.TxHash)
+	})
+}
+
+func TestFillStarknetOrderResult(t *testing.T) {
+	result := &OrderResult{}
+	fillStarknetOrderResult(result, starknetorder.OrderResult{})
+	assert.Empty(t, result.TxHash)
+	assert.Empty(t, result.OrderID)
+
+	orderID := common.HexToHash("0xabc")
+	fillStarknetOrderResult(result, starknetorder.OrderResult{
+		TransactionHash: new(felt.Felt).SetUint64(0x1234),
+		OrderID:         orderID,
+		GasUsed:         42,
+	})
+	assert.Equal(t, "0x1234", result.TxHash)
+	assert.Equal(t, orderID.Hex(), result.OrderID)
+	assert.Equal(t, uint64(42), result.GasUsed)
+	assert.Empty(t, result.OrderData, "no order struct without the encoded order")
+
+	fillStarknetOrderResult(result, starknetorder.OrderResult{
+		TransactionHash: new(felt.Felt).SetUint64(0x1234),
+		OrderID:         orderID,
+		EncodedOrder:    []byte{0xde, 0xad},
+		OrderDataType:   big.NewInt(0x08d7),
+		FillDeadline:    1700000000,
+	})
+	assert.Equal(t, "0xdead", result.OrderData)
+	assert.Equal(t, common.BigToHash(big.NewInt(0x08d7)).Hex(), result.OrderDataType)
+	assert.Equal(t, uint64(1700000000), result.FillDeadline)
+	assert.False(t, result.Gasless)
+}
+
+func TestRecordOrderJSON(t *testing.T) {
+	result := newOrderResult("Base", "Starknet", nil, nil)
+	result.recordOrder(1700000000, [32]byte{0x01}, []byte{0x02, 0x03})
+	result.Gasless = true
+	result.OpenDeadline = 1699990000
+	result.complete(nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeOrderResult(&buf, result))
+
+	var decoded OrderResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.True(t, decoded.Gasless)
+	assert.Equal(t, uint64(1699990000), decoded.OpenDeadline)
+	assert.Equal(t, uint64(1700000000), decoded.FillDeadline)
+	assert.Equal(t, common.Hash{0x01}.Hex(), decoded.OrderDataType)
+	assert.Equal(t, "0x0203", decoded.OrderData)
+}
+
+func TestSaveOrder(t *testing.T) {
+	store := orderstore.New(t.TempDir())
+
+	orderData := make([]byte, 64)
+	orderData[63] = 0x42
+	result := newOrderResult("Base", "Starknet", nil, nil)
+	result.OrderID = common.Hash{0x0a}.Hex()
+	result.TxHash = "0xbeef"
+	result.recordOrder(1700000000, [32]byte{0x01}, orderData)
+	result.complete(nil)
+	saveOrder(store, result)
+
+	stored, err := store.Get(result.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, "Base", stored.OriginChain)
+	assert.Equal(t, "Starknet", stored.DestinationChain)
+	assert.Equal(t, "0xbeef", stored.TxHash)
+	assert.Equal(t, uint64(1700000000), stored.FillDeadline)
+	assert.Equal(t, result.OrderData, stored.OrderData)
+	assert.Equal(t, common.BigToHash(big.NewInt(0x42)).Hex(), stored.User, "user is the order data sender")
+	assert.Equal(t, orderstore.StatusOpened, stored.Status)
+
+	// Results without an order ID have nothing to key the record on
+	saveOrder(store, newOrderResult("Base", "Starknet", nil, nil))
+	orders, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, orders, 1)
+}
@@ -0,0 +1,126 @@
+package openorder
+
+// EIP-2612 permits (--use-permit)
+// On an EVM origin a missing allowance is granted by a permit Alice signs off-chain instead of an approve().
+// permit() and open() are sent back to back, open()'s gas estimated against the pending state that already
+// holds the permit, and only then are both waited for. A node that cannot estimate on the pending state gets
+// the permit mined first. A token without permit() falls back to approve()
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/erc2612"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+// UsePermitFlag sets a missing allowance with an EIP-2612 permit submitted together with open()
+const UsePermitFlag = "--use-permit"
+
+// permitValidity is how long, in seconds of the origin's block time, a permit stays valid
+const permitValidity = 600
+
+// usePermit is set by --use-permit
+var usePermit bool
+
+// SetUsePermit makes EVM origins set a missing allowance with a permit
+func SetUsePermit(enabled bool) {
+	usePermit = enabled
+}
+
+// StripUsePermitFlag removes --use-permit from args and reports whether it was present
+func StripUsePermitFlag(args []string) ([]string, bool) {
+	return stripBoolFlag(args, UsePermitFlag)
+}
+
+// allowanceSetter grants spender an allowance: approve() by default, a permit with --use-permit when the token
+// supports it
+func allowanceSetter(ctx context.Context, submitter evmSubmitter, client *ethclient.Client, token, spender common.Address, amount *big.Int) error {
+	if usePermit {
+		err := submitter.permit(ctx, client, token, spender, amount)
+		if !errors.Is(err, erc2612.ErrUnsupported) {
+			return err
+		}
+		warnf("   ⚠️  %v, approving instead\n", err)
+	}
+	return submitter.approve(ctx, client, token, spender, amount)
+}
+
+func (s *evmSender) permit(ctx context.Context, client *ethclient.Client, token, spender common.Address, amount *big.Int) error {
+	logf("   Insufficient allowance, signing a permit for %s tokens (%s)...\n", amount.String(), UsePermitFlag)
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read the head block: %w", err)
+	}
+	deadline := new(big.Int).SetUint64(head.Time + permitValidity)
+	data, err := erc2612.SignedCall(ctx, client, s.chainID, token, spender, amount, deadline, s.key)
+	if err != nil {
+		return err
+	}
+	tx, err := ethutil.SendTx(ctx, client, s.auth, token, nil, data)
+	if err != nil {
+		if revert := ethutil.DecodeRevertError(err); revert != nil {
+			return fmt.Errorf("permit would revert: %w", revert)
+		}
+		return fmt.Errorf("failed to send permit: %w", err)
+	}
+	logf("   Permit transaction sent: %s\n", tx.Hash().Hex())
+	s.pendingPermit = tx
+	return nil
+}
+
+// openOpts returns the options open() is sent with. Behind a pending permit its gas is estimated on the pending
+// state, or the permit is waited for when the node cannot do that
+func (s *evmSender) openOpts(ctx context.Context, open evmOpen) (*bind.TransactOpts, error) {
+	opts := withOpenValue(s.auth, open.value)
+	if s.pendingPermit == nil {
+		return opts, nil
+	}
+	data, err := packOpen(open.order)
+	if err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{From: s.auth.From, To: &open.hyperlane, Value: opts.Value, Data: data}
+	gas, err := open.client.EstimateGasAtBlock(ctx, msg, ethutil.PendingBlock())
+	if err == nil {
+		opts.GasLimit = uint64(float64(gas) * ethutil.GasLimitMultiplier())
+		return opts, nil
+	}
+	warnf("   ⚠️  Could not estimate open() behind the pending permit, waiting for the permit first: %v\n", err)
+	if err := s.waitForPermit(ctx, open.client); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// waitForPermit waits for the pending permit, if any, and fails unless it succeeded
+func (s *evmSender) waitForPermit(ctx context.Context, client *ethclient.Client) error {
+	tx := s.pendingPermit
+	if tx == nil {
+		return nil
+	}
+	s.pendingPermit = nil
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "permit"), client, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the permit transaction: %w", err)
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return ethutil.RevertedTxError(ctx, client, "permit", tx, s.auth.From, receipt)
+	}
+	logf("   Permit confirmed!\n")
+	return nil
+}
+
+func (s evmSimulator) permit(_ context.Context, _ *ethclient.Client, token, spender common.Address, amount *big.Int) error {
+	warnf("   ⚠️  Skipping permit(%s, %s) on %s (%s): open() will revert if the allowance is short\n", spender.Hex(), amount.String(), token.Hex(), DryRunFlag)
+	return nil
+}
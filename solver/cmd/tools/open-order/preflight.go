@@ -0,0 +1,183 @@
+package openorder
+
+// Funds preflight
+// Every path checks Alice's balance and allowance against the order's input amount before the first
+// transaction. A shortfall aborts with the exact amount missing instead of reverting on-chain; a missing
+// allowance is only approved by the tool itself when --auto-approve is given
+//
+// Reads start at the latest block. When that falls short the pending state is consulted too, so an approve sent
+// just before opening counts while it is still in the mempool (EVM) or the pre_confirmed block (Starknet)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// AutoApproveFlag lets the tool send the missing approve() before opening
+const AutoApproveFlag = "--auto-approve"
+
+// autoApprove is set by --auto-approve
+var autoApprove bool
+
+// SetAutoApprove enables sending approve() when the preflight finds a missing allowance
+func SetAutoApprove(enabled bool) {
+	autoApprove = enabled
+}
+
+// StripAutoApproveFlag removes --auto-approve from args and reports whether it was present
+func StripAutoApproveFlag(args []string) ([]string, bool) {
+	return stripBoolFlag(args, AutoApproveFlag)
+}
+
+// stripBoolFlag removes every occurrence of flag from args and reports whether it was present
+func stripBoolFlag(args []string, flag string) ([]string, bool) {
+	out := make([]string, 0, len(args))
+	found := false
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out, found
+}
+
+// FundsShortfallError reports a balance or allowance below the order's input amount
+type FundsShortfallError struct {
+	Check    string // "balance" or "allowance"
+	Token    string
+	Holder   string // owner for balance, spender for allowance
+	Have     *big.Int
+	Need     *big.Int
+	Decimals int
+}
+
+// Shortfall is the amount missing
+func (e *FundsShortfallError) Shortfall() *big.Int {
+	return new(big.Int).Sub(e.Need, e.Have)
+}
+
+// Error renders the shortfall in token units
+func (e *FundsShortfallError) Error() string {
+	msg := fmt.Sprintf("insufficient %s of %s for %s: need %s, have %s (short %s)", e.Check, e.Token, e.Holder,
+		ethutil.FormatTokenAmount(e.Need, e.Decimals),
+		ethutil.FormatTokenAmount(e.Have, e.Decimals),
+		ethutil.FormatTokenAmount(e.Shortfall(), e.Decimals))
+	if e.Check == "allowance" {
+		msg += fmt.Sprintf("; approve it or rerun with %s", AutoApproveFlag)
+	}
+	return msg
+}
+
+// readFunds reads what at the latest block and, when that is below need, at the pending state (see
+// starknetutil.ReadFunds), returning the value and the state that covers need
+func readFunds(ctx context.Context, what string, read starknetutil.FundsReader, need *big.Int) (*big.Int, starknetutil.FundsState, error) {
+	funds, err := starknetutil.ReadFunds(ctx, read, need)
+	if err != nil {
+		return nil, "", err
+	}
+	if funds.PendingErr != nil {
+		warnf("   ⚠️  Could not read the pending %s, using the latest block: %v\n", what, funds.PendingErr)
+	}
+	if funds.State == starknetutil.FundsPending {
+		logf("   The pending %s covers the order (%s at the latest block)\n", what, funds.Latest.String())
+	}
+	return funds.Value, funds.State, nil
+}
+
+// evmBalance reads owner's token balance on an EVM origin
+func evmBalance(client *ethclient.Client, token, owner common.Address) starknetutil.FundsReader {
+	return func(ctx context.Context, pending bool) (*big.Int, error) {
+		return ethutil.ERC20BalanceAt(ctx, client, token, owner, evmBlock(pending))
+	}
+}
+
+// evmAllowance reads the token allowance owner gave spender on an EVM origin
+func evmAllowance(client *ethclient.Client, token, owner, spender common.Address) starknetutil.FundsReader {
+	return func(ctx context.Context, pending bool) (*big.Int, error) {
+		return ethutil.ERC20AllowanceAt(ctx, client, token, owner, spender, evmBlock(pending))
+	}
+}
+
+func evmBlock(pending bool) *big.Int {
+	if pending {
+		return ethutil.PendingBlock()
+	}
+	return nil
+}
+
+// fundsCheck is what the preflight compares for one order
+type fundsCheck struct {
+	Token     string
+	Owner     string
+	Spender   string
+	Decimals  int
+	Balance   *big.Int
+	Allowance *big.Int
+	Need      *big.Int
+	Permit    bool // a missing allowance can be set with a permit (--use-permit)
+}
+
+// preflightFunds returns an error when the balance or allowance is below Need. With --auto-approve, or a check
+// that allows a permit, a missing allowance is not an error and needsApproval tells the caller to set it before
+// opening
+func preflightFunds(c fundsCheck) (needsApproval bool, err error) {
+	if c.Balance.Cmp(c.Need) < 0 {
+		return false, &FundsShortfallError{Check: "balance", Token: c.Token, Holder: c.Owner, Have: c.Balance, Need: c.Need, Decimals: c.Decimals}
+	}
+	if c.Allowance.Cmp(c.Need) >= 0 {
+		return false, nil
+	}
+	if autoApprove || c.Permit {
+		return true, nil
+	}
+	return false, &FundsShortfallError{Check: "allowance", Token: c.Token, Holder: c.Spender, Have: c.Allowance, Need: c.Need, Decimals: c.Decimals}
+}
+
+// preflightStarknetFunds reads balance and allowance on a Starknet-type origin and runs preflightFunds.
+// The approval itself, when allowed, is sent by starknetorder.OpenOrder with AutoApprove
+func preflightStarknetFunds(ctx context.Context, caller starknetutil.ContractCaller, token, owner, spender string, decimals int, need *big.Int) error {
+	balance, balanceState, err := readFunds(ctx, "balance", starknetutil.ERC20BalanceReader(caller, token, owner), need)
+	if err != nil {
+		return fmt.Errorf("failed to read %s balance of %s: %w", token, owner, err)
+	}
+	logf("   Initial InputToken balance(owner): %s (%s)\n", starknetutil.FormatTokenAmount(balance, decimals), balanceState)
+
+	allowance, allowanceState, err := readFunds(ctx, "allowance", starknetutil.ERC20AllowanceReader(caller, token, owner, spender), need)
+	if err != nil {
+		return fmt.Errorf("failed to read allowance: %w", err)
+	}
+	logf("   Current allowance(owner->hyperlane): %s (%s)\n", starknetutil.FormatTokenAmount(allowance, decimals), allowanceState)
+
+	needsApproval, err := preflightFunds(fundsCheck{
+		Token:     token,
+		Owner:     owner,
+		Spender:   spender,
+		Decimals:  decimals,
+		Balance:   balance,
+		Allowance: allowance,
+		Need:      need,
+	})
+	var shortfall *FundsShortfallError
+	if errors.As(err, &shortfall) && shortfall.Check == "balance" {
+		warnf("   ⚠️  Please mint tokens manually using the MockERC20 contract's mint() function\n")
+		warnf("   ⚠️  Contract address: %s\n", token)
+	}
+	if err != nil {
+		return err
+	}
+	logf("   Alice has sufficient tokens (%s)\n", starknetutil.FormatTokenAmount(balance, decimals))
+	if needsApproval {
+		logf("   Insufficient allowance, OpenOrder will approve %s first (%s)\n", need.String(), AutoApproveFlag)
+	}
+	return nil
+}
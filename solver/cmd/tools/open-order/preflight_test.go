@@ -0,0 +1,179 @@
+package openorder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// useAutoApprove sets --auto-approve for the duration of a test
+func useAutoApprove(t *testing.T, enabled bool) {
+	saved := autoApprove
+	SetAutoApprove(enabled)
+	t.Cleanup(func() { SetAutoApprove(saved) })
+}
+
+func testFundsCheck(balance, allowance, need int64) fundsCheck {
+	return fundsCheck{
+		Token:     testEthereumDogCoin,
+		Owner:     testEVMAlice,
+		Spender:   testEthereumSettler,
+		Decimals:  tokenDecimals,
+		Balance:   CreateTokenAmount(balance, tokenDecimals),
+		Allowance: CreateTokenAmount(allowance, tokenDecimals),
+		Need:      CreateTokenAmount(need, tokenDecimals),
+	}
+}
+
+func TestPreflightFunds(t *testing.T) {
+	t.Run("enough balance and allowance", func(t *testing.T) {
+		useAutoApprove(t, false)
+		needsApproval, err := preflightFunds(testFundsCheck(2000, 1001, 1001))
+		require.NoError(t, err)
+		assert.False(t, needsApproval)
+	})
+
+	t.Run("balance shortfall is never approved away", func(t *testing.T) {
+		useAutoApprove(t, true)
+		_, err := preflightFunds(testFundsCheck(1000, 5000, 1001))
+
+		var shortfall *FundsShortfallError
+		require.ErrorAs(t, err, &shortfall)
+		assert.Equal(t, "balance", shortfall.Check)
+		assert.Equal(t, CreateTokenAmount(1, tokenDecimals), shortfall.Shortfall())
+		assert.ErrorContains(t, err, "insufficient balance of "+testEthereumDogCoin+" for "+testEVMAlice)
+		assert.ErrorContains(t, err, "(short 1.00 tokens)")
+	})
+
+	t.Run("allowance shortfall refuses without auto-approve", func(t *testing.T) {
+		useAutoApprove(t, false)
+		_, err := preflightFunds(testFundsCheck(2000, 1000, 1001))
+
+		var shortfall *FundsShortfallError
+		require.ErrorAs(t, err, &shortfall)
+		assert.Equal(t, "allowance", shortfall.Check)
+		assert.Equal(t, testEthereumSettler, shortfall.Holder)
+		assert.ErrorContains(t, err, "need 1001.00 tokens, have 1000.00 tokens (short 1.00 tokens)")
+		assert.ErrorContains(t, err, AutoApproveFlag)
+	})
+
+	t.Run("allowance shortfall approves with auto-approve", func(t *testing.T) {
+		useAutoApprove(t, true)
+		needsApproval, err := preflightFunds(testFundsCheck(2000, 0, 1001))
+		require.NoError(t, err)
+		assert.True(t, needsApproval)
+	})
+
+	t.Run("allowance shortfall is permitted with use-permit", func(t *testing.T) {
+		useAutoApprove(t, false)
+		check := testFundsCheck(2000, 0, 1001)
+		check.Permit = true
+		needsApproval, err := preflightFunds(check)
+		require.NoError(t, err)
+		assert.True(t, needsApproval)
+
+		check.Balance = CreateTokenAmount(1000, tokenDecimals)
+		_, err = preflightFunds(check)
+		assert.ErrorContains(t, err, "insufficient balance")
+	})
+}
+
+func TestFundsShortfallUnits(t *testing.T) {
+	err := &FundsShortfallError{Check: "allowance", Token: "USDC", Holder: "Hyperlane", Have: big.NewInt(500_000), Need: big.NewInt(1_500_000), Decimals: 6}
+	assert.Equal(t, big.NewInt(1_000_000), err.Shortfall())
+	assert.Contains(t, err.Error(), "need 1.50 tokens, have 0.50 tokens (short 1.00 tokens)")
+}
+
+func TestStripAutoApproveFlag(t *testing.T) {
+	args, found := StripAutoApproveFlag([]string{"starknet", "evm", "--auto-approve"})
+	assert.Equal(t, []string{"starknet", "evm"}, args)
+	assert.True(t, found)
+}
+
+func TestStripUsePermitFlag(t *testing.T) {
+	args, found := StripUsePermitFlag([]string{"base", "--use-permit", "starknet"})
+	assert.Equal(t, []string{"base", "starknet"}, args)
+	assert.True(t, found)
+}
+
+// blockCaller answers balanceOf and allowance per block tag, as u256; a tag without values fails like a node
+// that does not know it
+type blockCaller struct {
+	balances, allowances map[rpc.BlockTag]*big.Int
+}
+
+func (c *blockCaller) Call(_ context.Context, call rpc.FunctionCall, block rpc.BlockID) ([]*felt.Felt, error) {
+	values := c.balances
+	if call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt("allowance")) {
+		values = c.allowances
+	}
+	value, ok := values[block.Tag]
+	if !ok {
+		return nil, errors.New("Invalid block id")
+	}
+	low, high := starknetutil.ToU256(value)
+	return []*felt.Felt{low, high}, nil
+}
+
+func TestPreflightStarknetFundsPending(t *testing.T) {
+	need := CreateTokenAmount(1001, tokenDecimals)
+	enough := CreateTokenAmount(2000, tokenDecimals)
+	latest, pending := rpc.BlockTagLatest, starknetutil.PendingBlock.Tag
+
+	tests := []struct {
+		name    string
+		caller  *blockCaller
+		wantErr string
+	}{
+		{
+			name: "pending approve covers the order",
+			caller: &blockCaller{
+				balances:   map[rpc.BlockTag]*big.Int{latest: enough},
+				allowances: map[rpc.BlockTag]*big.Int{latest: big.NewInt(0), pending: need},
+			},
+		},
+		{
+			name: "pending mint covers the order",
+			caller: &blockCaller{
+				balances:   map[rpc.BlockTag]*big.Int{latest: big.NewInt(0), pending: enough},
+				allowances: map[rpc.BlockTag]*big.Int{latest: enough},
+			},
+		},
+		{
+			name: "pending state still short",
+			caller: &blockCaller{
+				balances:   map[rpc.BlockTag]*big.Int{latest: enough},
+				allowances: map[rpc.BlockTag]*big.Int{latest: big.NewInt(0), pending: CreateTokenAmount(1000, tokenDecimals)},
+			},
+			wantErr: "need 1001.00 tokens, have 0.00 tokens",
+		},
+		{
+			name: "node without a pending block falls back to latest",
+			caller: &blockCaller{
+				balances:   map[rpc.BlockTag]*big.Int{latest: enough},
+				allowances: map[rpc.BlockTag]*big.Int{latest: big.NewInt(0)},
+			},
+			wantErr: "insufficient allowance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useAutoApprove(t, false)
+			err := preflightStarknetFunds(context.Background(), tt.caller, testStarknetDogCoin, testStarknetAlice, testStarknetSettler, tokenDecimals, need)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
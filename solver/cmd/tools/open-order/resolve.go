@@ -0,0 +1,115 @@
+package openorder
+
+// Resolved order check
+// Before open() is sent the origin contract resolves the exact order that was built. The breakdown is printed
+// and compared with what the tool meant to open; any difference aborts with a diff before funds are locked
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// evmIntent is what an EVM order built from orderData is meant to resolve to. The order ID is the hash of the
+// encoded order, which already carries the sender and fill deadline the contract sets
+func evmIntent(order *OrderConfig, orderData *OrderData, originDomain uint32, onchain contracts.OnchainCrossChainOrder) starknetorder.Intent {
+	return starknetorder.Intent{
+		OrderID:            crypto.Keccak256Hash(onchain.OrderData),
+		InputToken:         common.Hash(starknetutil.EVMAddressToBytes32(common.HexToAddress(order.tokens.Input.Address))),
+		AmountIn:           order.InputAmount,
+		OriginDomain:       originDomain,
+		OutputToken:        orderData.OutputTokenWord,
+		AmountOut:          order.OutputAmount,
+		DestinationDomain:  uint32(orderData.DestinationChainID.Uint64()),
+		DestinationSettler: orderData.DestinationSettlerWord,
+	}
+}
+
+// checkEVMResolution resolves order as from, which resolve() takes the sender and order ID from, prints the
+// breakdown and checks it against intent
+func checkEVMResolution(ctx context.Context, contract orderResolver, contractName string, from common.Address, order contracts.OnchainCrossChainOrder, intent starknetorder.Intent) (common.Hash, error) {
+	resolved, err := contract.Resolve(&bind.CallOpts{Context: ctx, From: from}, order)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("resolve() would revert: %w", ethutil.RevertReason(err))
+	}
+	r := evmResolution(resolved)
+	printResolution(r)
+	return r.OrderID, starknetorder.CheckResolution(contractName, intent, r)
+}
+
+// evmResolution reduces a Solidity resolved order to the values starknetorder.CheckResolution compares
+func evmResolution(ro contracts.ResolvedCrossChainOrder) starknetorder.Resolution {
+	r := starknetorder.Resolution{OrderID: ro.OrderId}
+	for _, out := range ro.MaxSpent {
+		r.MaxSpent = append(r.MaxSpent, starknetorder.ResolvedOutput{Token: out.Token, Amount: out.Amount, ChainID: out.ChainId.Uint64()})
+	}
+	for _, out := range ro.MinReceived {
+		r.MinReceived = append(r.MinReceived, starknetorder.ResolvedOutput{Token: out.Token, Amount: out.Amount, ChainID: out.ChainId.Uint64()})
+	}
+	for _, fill := range ro.FillInstructions {
+		r.FillInstructions = append(r.FillInstructions, starknetorder.ResolvedFill{
+			DestinationChainID: fill.DestinationChainId.Uint64(),
+			DestinationSettler: fill.DestinationSettler,
+		})
+	}
+	return r
+}
+
+// checkCairoResolution resolves o on a Cairo origin, prints the breakdown and checks it against the order built.
+// The view resolves for a zero sender: the order ID it checks is that of the zero-sender order, and the one
+// printed is computed for o's sender, as open() will emit it
+func checkCairoResolution(ctx context.Context, caller starknetutil.ContractCaller, hyperlane *felt.Felt, o *starknetorder.OrderData) error {
+	orderDataType, err := starknetorder.OrderDataTypeHash()
+	if err != nil {
+		return err
+	}
+	contractName := "Hyperlane7683 " + hyperlane.String()
+	// An unknown type hash is reported by the type preflight, which names the hash the contract expects
+	sent := starknetorder.OrderDataTypeCandidate{Source: starknetorder.KnownOrderDataTypes()[0].Source, Hash: orderDataType}
+	probe := starknetorder.StarknetOrderDataTypeProbe(caller, hyperlane, o)
+	if err := starknetorder.CheckOrderDataType(ctx, contractName, sent, starknetorder.KnownOrderDataTypes(), probe); err != nil {
+		return err
+	}
+
+	resolved, err := starknetorder.Resolve(ctx, caller, hyperlane, orderDataType, o)
+	if err != nil {
+		return err
+	}
+	intent, err := starknetorder.ViewIntent(o)
+	if err != nil {
+		return err
+	}
+	encoded, err := starknetorder.EncodeOrderData(o)
+	if err != nil {
+		return err
+	}
+	r := resolved.Resolution()
+	shown := r
+	shown.OrderID = starknetorder.ComputeOrderID(encoded)
+	printResolution(shown)
+	return starknetorder.CheckResolution(contractName, intent, r)
+}
+
+// printResolution prints what resolve() made of the order
+func printResolution(r starknetorder.Resolution) {
+	logf("   Resolved order:\n")
+	logf("     Order ID: %s\n", r.OrderID.Hex())
+	for _, out := range r.MaxSpent {
+		logf("     Max Spent: %s of %s on domain %d\n", out.Amount.String(), out.Token.Hex(), out.ChainID)
+	}
+	for _, out := range r.MinReceived {
+		logf("     Min Received: %s of %s on domain %d\n", out.Amount.String(), out.Token.Hex(), out.ChainID)
+	}
+	for _, fill := range r.FillInstructions {
+		logf("     Fill Instruction: domain %d, settler %s\n", fill.DestinationChainID, fill.DestinationSettler.Hex())
+	}
+}
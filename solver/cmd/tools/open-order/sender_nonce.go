@@ -0,0 +1,213 @@
+package openorder
+
+// Sender nonce selection
+// Every order carries a senderNonce the contract must report as valid (gasless orders also use it as the
+// Permit2 unordered nonce). The default strategy draws random 128-bit nonces, which practically never collide,
+// so a single isValidNonce call is enough; any rejected candidates are replaced by a batch of fresh ones
+// checked in parallel. The sequential strategy probes upward from a time-derived seed, for deterministic runs
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+const (
+	// NonceStrategyRandom draws random 128-bit sender nonces (default)
+	NonceStrategyRandom = "random"
+	// NonceStrategySequential probes sender nonces one by one from a time-derived seed
+	NonceStrategySequential = "sequential"
+
+	// nonceStrategyEnv selects the sender nonce strategy
+	nonceStrategyEnv = "SENDER_NONCE_STRATEGY"
+
+	senderNonceBits       = 128
+	nonceProbeWorkers     = 8
+	nonceRetryBatch       = 4 // fresh random candidates drawn per nonce still missing
+	maxRandomNonceRounds  = 5 // rounds of random candidates before giving up
+	maxSequentialNonceGap = 1000
+)
+
+// nonceChecker reports whether the contract accepts a sender nonce
+type nonceChecker func(ctx context.Context, nonce *big.Int) (bool, error)
+
+// senderNoncePicker reserves sender nonces with the configured strategy
+type senderNoncePicker struct {
+	strategy string
+	check    nonceChecker
+	random   func() (*big.Int, error) // random candidate source
+	seed     func() int64             // first nonce probed by the sequential strategy
+	workers  int
+}
+
+// newSenderNoncePicker checks nonces against the Hyperlane7683 at contractAddress for from,
+// using the strategy set in SENDER_NONCE_STRATEGY
+func newSenderNoncePicker(client *ethclient.Client, contractAddress, from common.Address) (*senderNoncePicker, error) {
+	strategy := envutil.GetEnvWithDefault(nonceStrategyEnv, NonceStrategyRandom)
+	if strategy != NonceStrategyRandom && strategy != NonceStrategySequential {
+		return nil, fmt.Errorf("invalid %s %q (use %s or %s)", nonceStrategyEnv, strategy, NonceStrategyRandom, NonceStrategySequential)
+	}
+	return &senderNoncePicker{
+		strategy: strategy,
+		check: func(ctx context.Context, nonce *big.Int) (bool, error) {
+			return isValidNonce(ctx, client, contractAddress, from, nonce)
+		},
+		random:  randomSenderNonce,
+		seed:    timeNonceSeed,
+		workers: nonceProbeWorkers,
+	}, nil
+}
+
+// pickValidSenderNonce finds a nonce that the contract reports as valid for the provided sender
+func pickValidSenderNonce(ctx context.Context, client *ethclient.Client, contractAddress, from common.Address) (*big.Int, error) {
+	nonces, err := pickValidSenderNonces(ctx, client, contractAddress, from, 1)
+	if err != nil {
+		return nil, err
+	}
+	return nonces[0], nil
+}
+
+// pickValidSenderNonces reserves count distinct nonces that the contract reports as valid for the sender
+func pickValidSenderNonces(ctx context.Context, client *ethclient.Client, contractAddress, from common.Address, count int) ([]*big.Int, error) {
+	picker, err := newSenderNoncePicker(client, contractAddress, from)
+	if err != nil {
+		return nil, err
+	}
+	nonces, probes, err := picker.pick(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+	logf("   Picked %d %s sender nonce(s) with %d isValidNonce probe(s)\n", len(nonces), picker.strategy, probes)
+	return nonces, nil
+}
+
+// pick reserves count distinct valid nonces and reports how many isValidNonce probes it took
+func (p *senderNoncePicker) pick(ctx context.Context, count int) ([]*big.Int, int, error) {
+	if p.strategy == NonceStrategySequential {
+		return p.pickSequential(ctx, count)
+	}
+	return p.pickRandom(ctx, count)
+}
+
+// pickSequential probes one nonce at a time upward from the seed
+func (p *senderNoncePicker) pickSequential(ctx context.Context, count int) ([]*big.Int, int, error) {
+	seed := p.seed()
+	nonce := big.NewInt(seed)
+	nonces := make([]*big.Int, 0, count)
+	probes := 0
+	for ; probes < maxSequentialNonceGap+count && len(nonces) < count; probes++ {
+		valid, err := p.check(ctx, nonce)
+		if err != nil {
+			return nil, probes + 1, err
+		}
+		if valid {
+			nonces = append(nonces, new(big.Int).Set(nonce))
+		}
+		nonce = new(big.Int).Add(nonce, big.NewInt(1))
+	}
+	if len(nonces) < count {
+		return nil, probes, fmt.Errorf("could only find %d of %d valid sender nonces starting from %d", len(nonces), count, seed)
+	}
+	return nonces, probes, nil
+}
+
+// pickRandom checks one random candidate per nonce, then replaces rejected ones with batches of fresh candidates
+func (p *senderNoncePicker) pickRandom(ctx context.Context, count int) ([]*big.Int, int, error) {
+	nonces := make([]*big.Int, 0, count)
+	seen := make(map[string]bool)
+	probes := 0
+	for round := 0; round < maxRandomNonceRounds && len(nonces) < count; round++ {
+		missing := count - len(nonces)
+		batch := missing
+		if round > 0 {
+			batch = missing * nonceRetryBatch
+		}
+
+		candidates := make([]*big.Int, 0, batch)
+		for len(candidates) < batch {
+			nonce, err := p.random()
+			if err != nil {
+				return nil, probes, fmt.Errorf("failed to draw a random sender nonce: %w", err)
+			}
+			if key := nonce.String(); !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, nonce)
+			}
+		}
+
+		valid, err := p.checkAll(ctx, candidates)
+		probes += len(candidates)
+		if err != nil {
+			return nil, probes, err
+		}
+		for i, nonce := range candidates {
+			if valid[i] && len(nonces) < count {
+				nonces = append(nonces, nonce)
+			}
+		}
+	}
+	if len(nonces) < count {
+		return nil, probes, fmt.Errorf("could only find %d of %d valid sender nonces after %d random probes", len(nonces), count, probes)
+	}
+	return nonces, probes, nil
+}
+
+// checkAll checks candidates in parallel with at most p.workers calls in flight
+func (p *senderNoncePicker) checkAll(ctx context.Context, candidates []*big.Int) ([]bool, error) {
+	valid := make([]bool, len(candidates))
+	errs := make([]error, len(candidates))
+	workers := p.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, nonce := range candidates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, nonce *big.Int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			valid[i], errs[i] = p.check(ctx, nonce)
+		}(i, nonce)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return valid, nil
+}
+
+// randomSenderNonce draws a non-zero nonce uniformly from [1, 2^128)
+func randomSenderNonce() (*big.Int, error) {
+	for {
+		nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), senderNonceBits))
+		if err != nil {
+			return nil, err
+		}
+		if nonce.Sign() > 0 {
+			return nonce, nil
+		}
+	}
+}
+
+// timeNonceSeed derives the first sequential nonce from the clock
+func timeNonceSeed() int64 {
+	seed := time.Now().Unix() % 1_000_000
+	if seed < 1 {
+		seed = 1
+	}
+	return seed
+}
@@ -0,0 +1,131 @@
+package openorder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingChecker accepts every nonce not listed as used and counts the probes
+type countingChecker struct {
+	mu    sync.Mutex
+	used  map[int64]bool
+	err   error
+	calls int
+}
+
+func (c *countingChecker) check(_ context.Context, nonce *big.Int) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.err != nil {
+		return false, c.err
+	}
+	return !c.used[nonce.Int64()], nil
+}
+
+// sequenceSource returns the given nonces in order as "random" candidates
+func sequenceSource(nonces ...int64) func() (*big.Int, error) {
+	i := 0
+	return func() (*big.Int, error) {
+		n := nonces[i%len(nonces)]
+		i++
+		return big.NewInt(n), nil
+	}
+}
+
+func TestPickRandomSenderNonces(t *testing.T) {
+	t.Run("one probe per nonce when nothing collides", func(t *testing.T) {
+		checker := &countingChecker{}
+		p := &senderNoncePicker{strategy: NonceStrategyRandom, check: checker.check, random: sequenceSource(11, 12, 13), workers: 2}
+
+		nonces, probes, err := p.pick(context.Background(), 3)
+		require.NoError(t, err)
+		assert.Equal(t, []*big.Int{big.NewInt(11), big.NewInt(12), big.NewInt(13)}, nonces)
+		assert.Equal(t, 3, probes)
+		assert.Equal(t, 3, checker.calls)
+	})
+
+	t.Run("rejected candidates are replaced by a batch", func(t *testing.T) {
+		checker := &countingChecker{used: map[int64]bool{1: true, 2: true}}
+		p := &senderNoncePicker{strategy: NonceStrategyRandom, check: checker.check, random: sequenceSource(1, 2, 3, 4, 5, 6), workers: 4}
+
+		nonces, probes, err := p.pick(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, []*big.Int{big.NewInt(3)}, nonces)
+		assert.Equal(t, 1+nonceRetryBatch, probes)
+	})
+
+	t.Run("duplicate draws are not probed twice", func(t *testing.T) {
+		checker := &countingChecker{}
+		p := &senderNoncePicker{strategy: NonceStrategyRandom, check: checker.check, random: sequenceSource(7, 7, 8), workers: 1}
+
+		nonces, probes, err := p.pick(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, []*big.Int{big.NewInt(7), big.NewInt(8)}, nonces)
+		assert.Equal(t, 2, probes)
+	})
+
+	t.Run("gives up after the last round", func(t *testing.T) {
+		next := int64(0)
+		checker := &countingChecker{used: map[int64]bool{}}
+		random := func() (*big.Int, error) {
+			next++
+			checker.used[next] = true
+			return big.NewInt(next), nil
+		}
+		p := &senderNoncePicker{strategy: NonceStrategyRandom, check: checker.check, random: random, workers: 1}
+
+		_, _, err := p.pick(context.Background(), 1)
+		assert.ErrorContains(t, err, "could only find 0 of 1 valid sender nonces")
+	})
+
+	t.Run("RPC errors abort", func(t *testing.T) {
+		checker := &countingChecker{err: errors.New("connection refused")}
+		p := &senderNoncePicker{strategy: NonceStrategyRandom, check: checker.check, random: sequenceSource(1, 2), workers: 2}
+
+		_, _, err := p.pick(context.Background(), 2)
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestPickSequentialSenderNonces(t *testing.T) {
+	checker := &countingChecker{used: map[int64]bool{100: true, 102: true}}
+	p := &senderNoncePicker{strategy: NonceStrategySequential, check: checker.check, seed: func() int64 { return 100 }}
+
+	nonces, probes, err := p.pick(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(101), big.NewInt(103)}, nonces)
+	assert.Equal(t, 4, probes)
+}
+
+func TestRandomSenderNonce(t *testing.T) {
+	limit := new(big.Int).Lsh(big.NewInt(1), senderNonceBits)
+	for i := 0; i < 16; i++ {
+		nonce, err := randomSenderNonce()
+		require.NoError(t, err)
+		assert.Positive(t, nonce.Sign())
+		assert.Negative(t, nonce.Cmp(limit))
+	}
+}
+
+func TestNewSenderNoncePickerStrategy(t *testing.T) {
+	t.Setenv(nonceStrategyEnv, "")
+	p, err := newSenderNoncePicker(nil, [20]byte{}, [20]byte{})
+	require.NoError(t, err)
+	assert.Equal(t, NonceStrategyRandom, p.strategy)
+
+	t.Setenv(nonceStrategyEnv, NonceStrategySequential)
+	p, err = newSenderNoncePicker(nil, [20]byte{}, [20]byte{})
+	require.NoError(t, err)
+	assert.Equal(t, NonceStrategySequential, p.strategy)
+
+	t.Setenv(nonceStrategyEnv, "linear")
+	_, err = newSenderNoncePicker(nil, [20]byte{}, [20]byte{})
+	assert.ErrorContains(t, err, "invalid SENDER_NONCE_STRATEGY")
+}
@@ -1,70 +1,27 @@
 package openorder
 
 // Starknet order creation logic - extracted from the original open-order/starknet/main.go
-// This handles creating orders on Starknet chains
+// This handles creating orders on Cairo chains: Starknet and Ztarknet orders share this implementation and only
+// differ by their NetworkProfile (see network_profile.go)
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
-	"log"
 	"math/big"
-	"os"
-	"strings"
 	"time"
 
-	"github.com/NethermindEth/juno/core/felt"
-	"github.com/NethermindEth/starknet.go/account"
-	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
-	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
 )
 
-const (
-	// Data offset for Cairo bytes
-	dataOffset = 384
-)
-
-// getAliceAddressForNetwork gets Alice's address for a specific network using IS_DEVNET logic
-func getAliceAddressForNetwork(networkName string) (string, error) {
-	if strings.Contains(strings.ToLower(networkName), "starknet") {
-		// Use conditional environment variable for Starknet
-		address := envutil.GetStarknetAliceAddress()
-		if address == "" {
-			return "", fmt.Errorf("starknet Alice address not set")
-		}
-		return address, nil
-	} else if strings.Contains(strings.ToLower(networkName), "ztarknet") {
-		// Use conditional environment variable for Starknet
-		address := envutil.GetZtarknetAliceAddress()
-		if address == "" {
-			return "", fmt.Errorf("ztarknet Alice address not set")
-		}
-		return address, nil
-	} else {
-		// Use conditional environment variable for EVM networks
-		address := envutil.GetAlicePublicKey()
-		if address == "" {
-			return "", fmt.Errorf("alice public key not set")
-		}
-		return address, nil
-	}
-}
-
-// NetworkConfig represents a single network configuration for Starknet
-type StarknetNetworkConfig struct {
-	name             string
-	url              string
-	chainID          uint64
-	hyperlaneAddress string
-	dogCoinAddress   string
-}
-
-// OrderConfig represents order configuration for Starknet
+// StarknetOrderConfig represents order configuration for a Starknet or Ztarknet origin
 type StarknetOrderConfig struct {
 	OriginChain      string
 	DestinationChain string
@@ -72,753 +29,277 @@ type StarknetOrderConfig struct {
 	OutputToken      string
 	InputAmount      *big.Int
 	OutputAmount     *big.Int
-	User             string
-	Recipient        string
-	OpenDeadline     uint64
-	FillDeadline     uint64
-}
-
-// OrderData struct matching the Cairo OrderData
-type StarknetOrderData struct {
-	Sender             *felt.Felt
-	Recipient          *felt.Felt
-	InputToken         *felt.Felt
-	OutputToken        *felt.Felt
-	AmountIn           *big.Int
-	AmountOut          *big.Int
-	SenderNonce        *felt.Felt
-	OriginDomain       uint32
-	DestinationDomain  uint32
-	DestinationSettler *felt.Felt
-	OpenDeadline       uint64
-	FillDeadline       uint64
-	Data               []*felt.Felt
-}
-
-// StarknetOnchainCrossChainOrder struct matching the Cairo interface
-type StarknetOnchainCrossChainOrder struct {
-	FillDeadline      uint64
-	OrderDataTypeLow  *felt.Felt
-	OrderDataTypeHigh *felt.Felt
-	OrderData         []*felt.Felt
-}
-
-// Test user configuration for Starknet
-var starknetTestUsers []struct {
-	name       string
-	privateKey string
-	address    string
+	// Recipient is the recipient address on the destination chain; empty is Alice's address there
+	Recipient string
+	Deadlines orderdeadline.Deadlines
 }
 
-// initializeStarknetTestUsers initializes the test user configuration after .env is loaded
-func initializeStarknetTestUsers() {
-	// Use envutil for conditional environment variable access
-	aliceAddr := envutil.GetStarknetAliceAddress()
-	solverAddr := envutil.GetStarknetSolverAddress()
-	alicePrivateKeyVar := envutil.GetConditionalAccountEnv("STARKNET_ALICE_PRIVATE_KEY")
-	solverPrivateKeyVar := envutil.GetConditionalAccountEnv("STARKNET_SOLVER_PRIVATE_KEY")
-
-	starknetTestUsers = []struct {
-		name       string
-		privateKey string
-		address    string
-	}{
-		{"Alice", alicePrivateKeyVar, aliceAddr},
-		{"Solver", solverPrivateKeyVar, solverAddr},
+// loadOrigin loads the configuration (.env and networks) and the profile of the originChain network of kind
+func loadOrigin(kind NetworkType, originChain string) (*NetworkProfile, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
-}
-
-// loadStarknetNetworks loads network configuration from centralized config and environment variables
-func loadStarknetNetworks() []StarknetNetworkConfig {
-	// Initialize networks from centralized config after .env is loaded
-	config.InitializeNetworks()
-
-	// Build networks from centralized config
-	networkNames := config.GetNetworkNames()
-	networks := make([]StarknetNetworkConfig, 0, len(networkNames))
-
-	for _, networkName := range networkNames {
-		// Only include Starknet networks
-		if networkName != "Starknet" {
-			continue
-		}
-
-		networkConfig := config.Networks[networkName]
-
-		// Load addresses from environment variables
-		hyperlaneAddr := getEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", "")
-		dogAddr := getEnvWithDefault("STARKNET_DOG_COIN_ADDRESS", "")
-
-		if hyperlaneAddr == "" || dogAddr == "" {
-			log.Fatalf("missing STARKNET_HYPERLANE_ADDRESS or STARKNET_DOG_COIN_ADDRESS in .env")
-		}
-
-		networks = append(networks, StarknetNetworkConfig{
-			name:             networkConfig.Name,
-			url:              networkConfig.RPCURL,
-			chainID:          networkConfig.ChainID,
-			hyperlaneAddress: hyperlaneAddr,
-			dogCoinAddress:   dogAddr,
-		})
+	logutil.ConfigureFromConfig(logger, cfg)
+	if err := initializeUsers(); err != nil {
+		return nil, err
 	}
 
-	return networks
+	profiles, err := loadNetworkProfiles(cfg, kind)
+	if err != nil {
+		return nil, err
+	}
+	return findNetworkProfile(profiles, originChain)
 }
 
-// RunStarknetOrder creates a Starknet order based on the command
-func RunStarknetOrder(command string) {
-	//fmt.Println("🎯 Opening Starknet order...")
-
-	// Load configuration (this loads .env and initializes networks)
-	_, err := config.LoadConfig()
+// RunStarknetOrder creates a Starknet order based on the command.
+// originChain selects the Starknet network to open on; empty means the default Starknet network
+func RunStarknetOrder(ctx context.Context, command, originChain string) {
+	if originChain == "" {
+		originChain = starknetNetworkName
+	}
+	origin, err := loadOrigin(NetworkTypeStarknet, originChain)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		failOrder(originChain, "", err)
+		return
 	}
 
-	// Initialize test users after .env is loaded
-	initializeStarknetTestUsers()
-
-	// Load network configuration
-	networks := loadStarknetNetworks()
-
 	switch command {
-	case "random":
-		openRandomStarknetOrder(networks)
 	case "default":
-		openDefaultStarknetToEvm(networks)
+		openDefaultCairoOrder(ctx, origin, getEnvWithDefault("DEFAULT_EVM_DESTINATION", "Ethereum"))
 	default:
 		// Default to random Starknet order
-		openRandomStarknetOrder(networks)
+		openRandomCairoOrder(ctx, origin)
 	}
 }
 
 // RunStarknetOrderWithDest creates a Starknet order with specific origin and destination
-func RunStarknetOrderWithDest(command, originChain, destinationChain string) {
-	//fmt.Printf("🎯 Running Starknet order creation: %s → %s\n", originChain, destinationChain)
+func RunStarknetOrderWithDest(ctx context.Context, _, originChain, destinationChain string) {
+	runCairoOrderWithDest(ctx, NetworkTypeStarknet, originChain, destinationChain)
+}
 
-	// Load configuration (this loads .env and initializes networks)
-	_, err := config.LoadConfig()
+// runCairoOrderWithDest opens a random-amount order from the originChain network of kind to destinationChain
+func runCairoOrderWithDest(ctx context.Context, kind NetworkType, originChain, destinationChain string) {
+	origin, err := loadOrigin(kind, originChain)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		failOrder(originChain, destinationChain, err)
+		return
 	}
+	executeCairoOrder(ctx, origin, randomCairoOrder(origin.name, destinationChain))
+}
 
-	// Initialize test users after .env is loaded
-	initializeStarknetTestUsers()
-
-	// Load network configuration
-	networks := loadStarknetNetworks()
+// openRandomCairoOrder opens a random-amount order from origin to a random destination
+func openRandomCairoOrder(ctx context.Context, origin *NetworkProfile) {
+	logf("🎲 Opening Random %s Test Order...\n", origin.name)
 
-	// Get Alice's address for the destination chain
-	user, err := getAliceAddressForNetwork(destinationChain)
+	// Get available destination networks from config
+	destinationChain, err := GetRandomDestination(origin.name)
 	if err != nil {
-		log.Fatalf("Failed to get Alice address for %s: %v", destinationChain, err)
+		failOrder(origin.name, "", fmt.Errorf("failed to get random destination: %w", err))
+		return
 	}
+	executeCairoOrder(ctx, origin, randomCairoOrder(origin.name, destinationChain))
+}
 
-	// Random amounts
-	inputAmount := CreateTokenAmount(int64(secureRandomInt(maxTokenAmount-minTokenAmount)+minTokenAmount), 18)
-	delta := CreateTokenAmount(int64(secureRandomInt(maxDeltaAmount-minDeltaAmount)+minDeltaAmount), 18)
-	outputAmount := new(big.Int).Sub(inputAmount, delta)
+// openDefaultCairoOrder opens the fixed-amount test order from origin to destinationChain
+func openDefaultCairoOrder(ctx context.Context, origin *NetworkProfile, destinationChain string) {
+	logf("🎯 Opening Default %s → %s Test Order...\n", origin.name, destinationChain)
 
-	order := StarknetOrderConfig{
-		OriginChain:      originChain,
+	executeCairoOrder(ctx, origin, &StarknetOrderConfig{
+		OriginChain:      origin.name,
 		DestinationChain: destinationChain,
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
-		InputAmount:      inputAmount,
-		OutputAmount:     outputAmount,
-		User:             "Alice",
-		Recipient:        user,
-		OpenDeadline:     uint64(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint64(time.Now().Add(24 * time.Hour).Unix()),
-	}
-
-	executeStarknetOrder(&order, networks)
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
+		InputAmount:      CreateTokenAmount(1000, 18),                                // 1000 tokens
+		OutputAmount:     CreateTokenAmount(testOutputAmountStarknet, tokenDecimals), // 999 tokens
+	})
 }
 
-func openRandomStarknetOrder(networks []StarknetNetworkConfig) {
-	fmt.Println("🎲 Opening Random Starknet Test Order...")
-
-	// Use configured Starknet network as origin
-	originChain := "Starknet"
+// randomCairoOrder is an order of random amounts to Alice on destinationChain
+func randomCairoOrder(originChain, destinationChain string) *StarknetOrderConfig {
+	inputAmount := CreateTokenAmount(int64(randomInt(maxTokenAmount-minTokenAmount)+minTokenAmount), 18) // 100-10000 tokens
+	delta := CreateTokenAmount(int64(randomInt(maxDeltaAmount-minDeltaAmount)+minDeltaAmount), 18)       // 1-10 tokens
+	outputAmount := new(big.Int).Sub(inputAmount, delta)                                                 // slightly less to ensure it's fillable
 
-	// Get available destination networks from config
-	destinationChain, err := GetRandomDestination(originChain)
-	if err != nil {
-		log.Fatalf("Failed to get random destination: %v", err)
-	}
-
-	// Get Alice's address for the destination chain
-	user, err := getAliceAddressForNetwork(destinationChain)
-	if err != nil {
-		log.Fatalf("Failed to get Alice address for %s: %v", destinationChain, err)
-	}
-
-	// Random amounts
-	inputAmount := CreateTokenAmount(int64(secureRandomInt(maxTokenAmount-minTokenAmount)+minTokenAmount), 18) // 100-10000 tokens
-	delta := CreateTokenAmount(int64(secureRandomInt(maxDeltaAmount-minDeltaAmount)+minDeltaAmount), 18)       // 1-10 tokens
-	outputAmount := new(big.Int).Sub(inputAmount, delta)                                                       // slightly less to ensure it's fillable
-
-	order := StarknetOrderConfig{
+	return &StarknetOrderConfig{
 		OriginChain:      originChain,
 		DestinationChain: destinationChain,
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
+		InputToken:       tokenSelection.Input,
+		OutputToken:      tokenSelection.Output,
 		InputAmount:      inputAmount,
 		OutputAmount:     outputAmount,
-		User:             "Alice", // Sender name
-		Recipient:        user,    // Recipient address on destination chain
-		OpenDeadline:     uint64(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint64(time.Now().Add(24 * time.Hour).Unix()),
 	}
-
-	executeStarknetOrder(&order, networks)
 }
 
-func openDefaultStarknetToEvm(networks []StarknetNetworkConfig) {
-	//fmt.Println("🎯 Opening Default Starknet → EVM Test Order...")
+// executeCairoOrder opens a single order from origin and reports the result
+func executeCairoOrder(ctx context.Context, origin *NetworkProfile, order *StarknetOrderConfig) {
+	result := newOrderResult(order.OriginChain, order.DestinationChain, order.InputAmount, order.OutputAmount)
+	finishOrder(result, openCairoOrder(ctx, origin, order, result))
+}
 
-	// Use configured networks instead of hardcoded names
-	originChain := "Starknet"
-	destinationChain := getEnvWithDefault("DEFAULT_EVM_DESTINATION", "Ethereum")
+// openCairoOrder approves (if needed) and opens the order from origin, filling in result as it goes
+func openCairoOrder(ctx context.Context, origin *NetworkProfile, order *StarknetOrderConfig, result *OrderResult) error {
+	logf("\nOpening Order: %s → %s\n", order.OriginChain, order.DestinationChain)
+	ctx = txcost.WithNetwork(ctx, origin.name)
 
-	// Get Alice's address for the destination chain
-	aliceAddress, err := getAliceAddressForNetwork(destinationChain)
+	client, err := clients().StarknetFailover(ctx, origin.name, origin.urls)
 	if err != nil {
-		log.Fatalf("Failed to get Alice address for %s: %v", destinationChain, err)
+		return fmt.Errorf("failed to connect to %s: %w", origin.name, err)
 	}
-
-	order := StarknetOrderConfig{
-		OriginChain:      originChain,
-		DestinationChain: destinationChain,
-		InputToken:       "DogCoin",
-		OutputToken:      "DogCoin",
-		InputAmount:      CreateTokenAmount(1000, 18),                                // 1000 tokens
-		OutputAmount:     CreateTokenAmount(testOutputAmountStarknet, tokenDecimals), // 999 tokens
-		User:             "Alice",                                                    // Sender
-		Recipient:        aliceAddress,                                               // Recipient address on destination chain
-		OpenDeadline:     uint64(time.Now().Add(1 * time.Hour).Unix()),
-		FillDeadline:     uint64(time.Now().Add(24 * time.Hour).Unix()),
+	if err := config.VerifyStarknetChainID(ctx, client, config.Networks[origin.name]); err != nil {
+		return err
 	}
 
-	executeStarknetOrder(&order, networks)
-}
-
-func executeStarknetOrder(order *StarknetOrderConfig, networks []StarknetNetworkConfig) {
-	fmt.Printf("\nOpening Order: %s → %s\n", order.OriginChain, order.DestinationChain)
-
-	// Find origin network (should be Starknet)
-	var originNetwork *StarknetNetworkConfig
-	for _, network := range networks {
-		if network.name == order.OriginChain {
-			originNetwork = &network
-			break
-		}
+	// Deadlines run from the origin's block time, which on a devnet can be far from this machine's clock
+	if err := stampCairoDeadlines(ctx, client, order, deadlineWindows); err != nil {
+		return err
 	}
 
-	if originNetwork == nil {
-		fmt.Printf("❌ Origin network not found: %s\n", order.OriginChain)
-		os.Exit(1)
-	}
-
-	// Connect to Starknet RPC
-	client, err := rpc.NewProvider(originNetwork.url)
-	if err != nil {
-		fmt.Printf("❌ Failed to connect to %s: %v\n", order.OriginChain, err)
-		os.Exit(1)
-	}
-
-	// Always use Alice's Starknet credentials for signing orders on Starknet
-	// The order.User field contains the recipient address (destination chain), not the signer
-	userKey := envutil.GetStarknetAlicePrivateKey()
-	userPublicKey := envutil.GetStarknetAlicePublicKey()
-
-	if userKey == "" || userPublicKey == "" {
-		fmt.Printf("❌ Missing Alice's Starknet credentials (IS_DEVNET=%v)\n", envutil.IsDevnet())
-		if envutil.IsDevnet() {
-			fmt.Printf("   Required: LOCAL_STARKNET_ALICE_PRIVATE_KEY and LOCAL_STARKNET_ALICE_PUBLIC_KEY\n")
-		} else {
-			fmt.Printf("   Required: STARKNET_ALICE_PRIVATE_KEY and STARKNET_ALICE_PUBLIC_KEY\n")
-		}
-		os.Exit(1)
-	}
-
-	// Always use Alice's Starknet address for signing (order signer)
-	var userAddr string
-	for _, user := range starknetTestUsers {
-		if user.name == "Alice" {
-			userAddr = user.address
-			break
-		}
-	}
-
-	// Get domains from config
-	var originDomain, destinationDomain uint32
-	if originConfig, err := config.GetHyperlaneDomain(order.OriginChain); err == nil {
-		originDomain = uint32(originConfig)
-	} else {
-		fmt.Printf("   ⚠️  Warning: Could not get origin domain from config, using chain ID\n")
-		originDomain = uint32(originNetwork.chainID)
-	}
-
-	if destConfig, err := config.GetHyperlaneDomain(order.DestinationChain); err == nil {
-		destinationDomain = uint32(destConfig)
-	} else {
-		fmt.Printf("   ⚠️  Warning: Could not get destination domain from config\n")
-		os.Exit(1)
-	}
-
-	// Preflight: check balances and allowances
-	inputToken := originNetwork.dogCoinAddress
-	owner := userAddr
-	spender := originNetwork.hyperlaneAddress
-
-	// Get initial balances
-	initialUserBalance, err := starknetutil.ERC20Balance(client, inputToken, owner)
-	if err == nil {
-		fmt.Printf("   Initial InputToken balance(owner): %s\n", starknetutil.FormatTokenAmount(initialUserBalance, 18))
-	} else {
-		fmt.Printf("   ⚠️  Could not read initial balance: %v\n", err)
-	}
-
-	// Check if Alice has sufficient tokens for the order
-	requiredAmount := order.InputAmount
-	if initialUserBalance == nil || initialUserBalance.Cmp(requiredAmount) < 0 {
-		fmt.Printf("   ⚠️  Insufficient balance! Alice needs %s tokens but has %s\n",
-			starknetutil.FormatTokenAmount(requiredAmount, 18),
-			starknetutil.FormatTokenAmount(initialUserBalance, 18))
-		fmt.Printf("   ⚠️  Please mint tokens manually using the MockERC20 contract's mint() function\n")
-		fmt.Printf("   ⚠️  Contract address: %s\n", inputToken)
-		fmt.Printf("❌ Insufficient token balance for order creation\n")
-		os.Exit(1)
-	} else {
-		fmt.Printf("   Alice has sufficient tokens (%s)\n", starknetutil.FormatTokenAmount(initialUserBalance, 18))
-	}
-
-	// Create user account for transaction signing (needed for approval)
-	userAddrFelt, err := utils.HexToFelt(userAddr)
+	// Alice's credentials on the origin sign the order; the recipient is Alice on the destination
+	registeredKey, err := origin.signerPublicKey()
 	if err != nil {
-		fmt.Printf("❌ Failed to convert user address to felt: %v\n", err)
-		os.Exit(1)
+		return err
 	}
-
-	// Initialize user's keystore
-	userKs := account.NewMemKeystore()
-	userPrivKeyBI, ok := new(big.Int).SetString(userKey, 0)
-	if !ok {
-		fmt.Printf("❌ Failed to convert private key for %s: %v\n", order.User, err)
-		os.Exit(1)
-	}
-	userKs.Put(userPublicKey, userPrivKeyBI)
-
-	// Create user account (Cairo v2)
-	userAccnt, err := account.NewAccount(client, userAddrFelt, userPublicKey, userKs, account.CairoV2)
+	submit, err := newStarknetSubmitter(origin.alice, registeredKey, origin.missingKeysError())
 	if err != nil {
-		fmt.Printf("❌ Failed to create account for %s: %v\n", order.User, err)
-		os.Exit(1)
+		return err
 	}
 
-	// Check allowance
-	allowance, err := starknetutil.ERC20Allowance(client, inputToken, owner, spender)
-	if err == nil {
-		fmt.Printf("   Current allowance(owner->hyperlane): %s\n", starknetutil.FormatTokenAmount(allowance, 18))
-	} else {
-		fmt.Printf("   ⚠️  Could not read allowance: %v\n", err)
+	// Resolve the token pair on both chains and scale the amounts to their decimals
+	tokens, err := resolveOrderTokens(ctx, order.InputToken, order.OutputToken, origin.name, order.DestinationChain)
+	if err != nil {
+		return err
 	}
-
-	// Store initial balance for comparison
-	// initialBalance := initialUserBalance
-
-	// If allowance is insufficient, approve the Hyperlane contract
-	requiredAmount = order.InputAmount
-	if allowance == nil || allowance.Cmp(requiredAmount) < 0 {
-		fmt.Printf("   🔄 Insufficient allowance, approving %s tokens...\n", starknetutil.FormatTokenAmount(requiredAmount, 18))
-
-		// Create approval transaction
-		approveCall, err := starknetutil.ERC20Approve(inputToken, spender, requiredAmount)
-		if err != nil {
-			fmt.Printf("❌ Failed to create approve transaction: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Send approval transaction
-		approveTx, err := userAccnt.BuildAndSendInvokeTxn(context.Background(), []rpc.InvokeFunctionCall{*approveCall}, nil)
-		if err != nil {
-			fmt.Printf("❌ Failed to send approval transaction: %v\n", err)
-			os.Exit(1)
-		}
-
-		fmt.Printf("   Approval transaction sent: %s\n", approveTx.Hash.String())
-		fmt.Printf("   ⏳ Waiting for approval confirmation...\n")
-
-		// Wait for approval transaction to be mined
-		_, err = userAccnt.WaitForTransactionReceipt(context.Background(), approveTx.Hash, 2*time.Second)
-		if err != nil {
-			fmt.Printf("❌ Failed to wait for approval transaction: %v\n", err)
-			os.Exit(1)
-		}
-
-		fmt.Printf("   Approval confirmed!\n")
-	} else {
-		fmt.Printf("   Sufficient allowance already exists\n")
+	order.InputAmount, order.OutputAmount, err = tokens.apply(order.InputAmount, order.OutputAmount, result)
+	if err != nil {
+		return err
 	}
 
 	// Generate a random nonce for the order
 	senderNonce := big.NewInt(time.Now().UnixNano())
+	result.SenderNonce = senderNonce.String()
 
-	// Build the order data
-	orderData := buildStarknetOrderData(order, originNetwork, originDomain, destinationDomain, senderNonce, order.DestinationChain)
-
-	// Build the StarknetOnchainCrossChainOrder with u256 order_data_type (low, high)
-	lowHash, highHash := getOrderDataTypeHashU256()
-	crossChainOrder := StarknetOnchainCrossChainOrder{
-		FillDeadline:      order.FillDeadline,
-		OrderDataTypeLow:  lowHash,
-		OrderDataTypeHigh: highHash,
-		OrderData:         encodeStarknetOrderData(&orderData),
+	// Build the order data; this resolves the recipient and destination settler before anything is sent
+	orderData, err := buildCairoOrderData(origin, order, tokens, senderNonce)
+	if err != nil {
+		return fmt.Errorf("failed to build order data: %w", err)
 	}
 
-	// Use generated bindings for open()
-	fmt.Printf("   Calling open() function...\n")
+	// Preflight: gate on balance and allowance before sending anything
+	input := tokens.Input
+	if err := dryRunPreflight(preflightStarknetFunds(ctx, client, input.Address, orderData.Sender.String(), origin.hyperlaneAddress, input.Decimals, order.InputAmount)); err != nil {
+		return err
+	}
 
-	// Get Hyperlane7683 contract address
-	hyperlaneAddrFelt, err := utils.HexToFelt(originNetwork.hyperlaneAddress)
+	hyperlaneAddrFelt, err := utils.HexToFelt(origin.hyperlaneAddress)
 	if err != nil {
-		fmt.Printf("❌ Failed to convert Hyperlane7683 address to felt: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to convert Hyperlane7683 address to felt: %w", err)
 	}
+	quoteStarknetGasPayment(ctx, client, hyperlaneAddrFelt, orderData.DestinationDomain, result)
 
-	fmt.Printf("   Sending open transaction...\n")
+	// Have the contract resolve the order before anything is sent, and stop if it is not the one intended
+	if err := checkCairoResolution(ctx, client, hyperlaneAddrFelt, &orderData); err != nil {
+		return err
+	}
 
-	// Build the transaction calldata for open(fill_deadline: u64, order_data_type: u256, order_data: Bytes)
-	calldata := []*felt.Felt{
-		utils.Uint64ToFelt(crossChainOrder.FillDeadline),
-		crossChainOrder.OrderDataTypeLow,
-		crossChainOrder.OrderDataTypeHigh,
+	// Submission is the final step: approve (if needed) and open, or with --dry-run simulate open()
+	if err := submit(ctx, client, orderData.Sender, starknetorder.OrderParams{
+		HyperlaneAddress: hyperlaneAddrFelt,
+		Order:            orderData,
+		AutoApprove:      autoApprove,
+		Retry:            starknetutil.DefaultRetryPolicy.WithLogger(logger),
+	}, result); err != nil {
+		return err
 	}
-	calldata = append(calldata, crossChainOrder.OrderData...)
+	if !dryRun {
+		printOrderSummary(order.OriginChain, order.DestinationChain, tokens, order.InputAmount, order.OutputAmount)
+	}
+	return nil
+}
 
-	tx, err := userAccnt.BuildAndSendInvokeTxn(
-		context.Background(),
-		[]rpc.InvokeFunctionCall{{
-			ContractAddress: hyperlaneAddrFelt,
-			FunctionName:    "open",
-			CallData:        calldata,
-		}},
-		nil,
-	)
+// buildCairoOrderData encodes order as opened by Alice on origin: the sender and origin domain come from the
+// profile, the recipient and destination settler are mapped to the destination by it
+func buildCairoOrderData(origin *NetworkProfile, order *StarknetOrderConfig, tokens *orderTokens, senderNonce *big.Int) (starknetorder.OrderData, error) {
+	senderAddress, err := origin.senderAddress()
 	if err != nil {
-		fmt.Printf("❌ Failed to send open transaction: %v\n", err)
-		os.Exit(1)
+		return starknetorder.OrderData{}, fmt.Errorf("failed to resolve the sender: %w", err)
+	}
+	sender, err := utils.HexToFelt(senderAddress)
+	if err != nil {
+		return starknetorder.OrderData{}, fmt.Errorf("failed to convert user address to felt: %w", err)
 	}
 
-	fmt.Printf("   Transaction sent: %s\n", tx.Hash.String())
-	fmt.Printf("   ⏳ Waiting for confirmation...\n")
-
-	// Wait for transaction receipt
-	_, err = userAccnt.WaitForTransactionReceipt(context.Background(), tx.Hash, time.Second)
+	destinationDomain, err := config.DestinationDomain(origin.domain, order.DestinationChain)
 	if err != nil {
-		fmt.Printf("❌ Failed to wait for transaction confirmation: %v\n", err)
-		os.Exit(1)
+		return starknetorder.OrderData{}, fmt.Errorf("invalid destination %s: %w", order.DestinationChain, err)
 	}
 
-	fmt.Printf("   Order opened successfully!\n")
+	recipient, err := origin.recipient(order.DestinationChain, order.Recipient)
+	if err != nil {
+		return starknetorder.OrderData{}, err
+	}
+	destSettler, err := origin.destinationSettler(order.DestinationChain)
+	if err != nil {
+		return starknetorder.OrderData{}, err
+	}
 
-	fmt.Printf("\n🎉 Order execution completed!\n")
-	fmt.Printf("   Order Summary:\n")
-	fmt.Printf("   Input Amount: %s\n", order.InputAmount.String())
-	fmt.Printf("   Output Amount: %s\n", order.OutputAmount.String())
-	fmt.Printf("   Origin Chain: %s\n", order.OriginChain)
-	fmt.Printf("   Destination Chain: %s\n", order.DestinationChain)
-}
+	// Output token is resolved on the destination network; EVM addresses are left-padded to 32 bytes
+	inputToken, err := tokens.Input.felt()
+	if err != nil {
+		return starknetorder.OrderData{}, err
+	}
+	outputToken, err := tokens.Output.felt()
+	if err != nil {
+		return starknetorder.OrderData{}, err
+	}
 
-func buildStarknetOrderData(order *StarknetOrderConfig, originNetwork *StarknetNetworkConfig, originDomain, destinationDomain uint32, senderNonce *big.Int, destChainName string) StarknetOrderData {
-	// Get the actual user address for the specified user (Sender)
-	var userAddr string
-	for _, user := range starknetTestUsers {
-		if user.name == order.User {
-			userAddr = user.address
-			break
-		}
-	}
-	// Fallback if User is already an address (shouldn't happen with "Alice")
-	if userAddr == "" {
-		userAddr = order.User
-	}
-
-	// Convert addresses to felt
-	userAddrFelt, _ := utils.HexToFelt(userAddr)
-	inputTokenFelt, _ := utils.HexToFelt(originNetwork.dogCoinAddress)
-
-	// Process Recipient based on destination network type
-	var recipientFelt *felt.Felt
-
-	if isStarknetNetwork(destChainName) {
-		// Starknet/Ztarknet destination: use recipient address directly (32 bytes)
-		if order.Recipient == "" {
-			log.Fatalf("Recipient address not set for Starknet/Ztarknet order")
-		}
-		recipientFelt, _ = utils.HexToFelt(order.Recipient)
-	} else {
-		// EVM destination: use Recipient if set, otherwise default to EVM Alice
-		recipientAddr := order.Recipient
-		if recipientAddr == "" {
-			recipientAddr = envutil.GetAlicePublicKey()
-			if recipientAddr == "" {
-				log.Fatalf("Alice public key not set")
-			}
-		}
-
-		// Pad EVM address to 32 bytes for Cairo ContractAddress
-		evmAddr := common.HexToAddress(recipientAddr)
-		paddedAddr := common.LeftPadBytes(evmAddr.Bytes(), 32)
-		recipientFelt, _ = utils.HexToFelt(hex.EncodeToString(paddedAddr))
-	}
-
-	// Output token should be from the destination network, not origin
-	var outputTokenFelt *felt.Felt
-	if isStarknetNetwork(destChainName) {
-		// If destination is Starknet or Ztarknet, get the destination's DogCoin address
-		if destChainName == "Starknet" {
-			starknetDogCoin := getEnvWithDefault("STARKNET_DOG_COIN_ADDRESS", "")
-			if starknetDogCoin == "" {
-				log.Fatalf("STARKNET_DOG_COIN_ADDRESS not set")
-			}
-			outputTokenFelt, _ = utils.HexToFelt(starknetDogCoin)
-		} else if destChainName == "Ztarknet" {
-			ztarknetDogCoin := getEnvWithDefault("ZTARKNET_DOG_COIN_ADDRESS", "")
-			if ztarknetDogCoin == "" {
-				log.Fatalf("ZTARKNET_DOG_COIN_ADDRESS not set")
-			}
-			outputTokenFelt, _ = utils.HexToFelt(ztarknetDogCoin)
-		} else {
-			// Fallback (shouldn't happen if isStarknetNetwork works correctly)
-			outputTokenFelt, _ = utils.HexToFelt(originNetwork.dogCoinAddress)
-		}
-	} else {
-		// If destination is EVM, get DogCoin address from destination network config (.env)
-		if _, exists := config.Networks[destChainName]; exists {
-			dogCoinAddr := getEnvWithDefault(strings.ToUpper(destChainName)+"_DOG_COIN_ADDRESS", "")
-			if dogCoinAddr != "" {
-				// For EVM addresses, we need to left-pad to 32 bytes for Cairo ContractAddress
-				evmAddr := common.HexToAddress(dogCoinAddr)
-				paddedAddr := common.LeftPadBytes(evmAddr.Bytes(), 32)
-				outputTokenFelt, _ = utils.HexToFelt(hex.EncodeToString(paddedAddr))
-			} else {
-				// Last resort - use origin network (this is wrong but prevents crash)
-				outputTokenFelt, _ = utils.HexToFelt(originNetwork.dogCoinAddress)
-				fmt.Printf("   ⚠️  Warning: No %s_DOG_COIN_ADDRESS in .env, using origin network DogCoin as fallback\n", strings.ToUpper(destChainName))
-			}
-		} else {
-			// Fallback to origin network if destination network not found
-			outputTokenFelt, _ = utils.HexToFelt(originNetwork.dogCoinAddress)
-			fmt.Printf("   ⚠️  Warning: Destination network %s not found in config, using origin network DogCoin as fallback\n", destChainName)
-		}
-	}
-
-	// Destination settler must be the Hyperlane address for the destination network
-	destSettlerHex := ""
-	if isStarknetNetwork(destChainName) {
-		// If destination is Starknet or Ztarknet, get the destination's Hyperlane address
-		if destChainName == "Starknet" {
-			destSettlerHex = getEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", "")
-			if destSettlerHex == "" {
-				log.Fatalf("STARKNET_HYPERLANE_ADDRESS not set")
-			}
-		} else if destChainName == "Ztarknet" {
-			destSettlerHex = getEnvWithDefault("ZTARKNET_HYPERLANE_ADDRESS", "")
-			if destSettlerHex == "" {
-				log.Fatalf("ZTARKNET_HYPERLANE_ADDRESS not set")
-			}
-		}
-	} else {
-		// If destination is EVM, get EVM Hyperlane address
-		if staticAddr, err := config.GetHyperlaneAddress(destChainName); err == nil {
-			destSettlerHex = staticAddr
-		} else if destNetwork, exists := config.Networks[destChainName]; exists {
-			destSettlerHex = destNetwork.HyperlaneAddress
-		}
-		if destSettlerHex == "" {
-			log.Fatalf("Could not get destination settler address for %s", destChainName)
-		}
-	}
-
-	// Ensure destination settler is properly padded to 32 bytes for Cairo ContractAddress
-	var destSettlerFelt *felt.Felt
-	if isStarknetNetwork(destChainName) {
-		// If destination is Starknet, use Starknet address directly
-		destSettlerFelt, _ = utils.HexToFelt(destSettlerHex)
-	} else {
-		// If destination is EVM, pad the EVM address to 32 bytes
-		evmAddr := common.HexToAddress(destSettlerHex)
-		paddedAddr := common.LeftPadBytes(evmAddr.Bytes(), 32)
-		destSettlerFelt, _ = utils.HexToFelt(hex.EncodeToString(paddedAddr))
-	}
-
-	return StarknetOrderData{
-		Sender:             userAddrFelt,
-		Recipient:          recipientFelt,
-		InputToken:         inputTokenFelt,
-		OutputToken:        outputTokenFelt,
+	return starknetorder.OrderData{
+		Sender:             sender,
+		Recipient:          recipient,
+		InputToken:         inputToken,
+		OutputToken:        outputToken,
 		AmountIn:           order.InputAmount,
 		AmountOut:          order.OutputAmount,
 		SenderNonce:        utils.BigIntToFelt(senderNonce),
-		OriginDomain:       originDomain,
+		OriginDomain:       origin.domain,
 		DestinationDomain:  destinationDomain,
-		DestinationSettler: destSettlerFelt,
-		OpenDeadline:       order.OpenDeadline,
-		FillDeadline:       order.FillDeadline,
-		Data:               []*felt.Felt{},
-	}
-}
-
-func getOrderDataTypeHashU256() (low, high *felt.Felt) {
-	// Solidity ORDER_DATA_TYPE_HASH (32 bytes)
-	hashHex := getEnvWithDefault("ORDER_DATA_TYPE_HASH", "0x08d75650babf4de09c9273d48ef647876057ed91d4323f8a2e3ebc2cd8a63b5e")
-	bi, ok := new(big.Int).SetString(hashHex, 0)
-	if !ok {
-		panic("Failed to parse ORDER_DATA_TYPE_HASH")
-	}
-	// Split into low/high 128-bit felts: low = lower 128, high = upper 128
-	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
-	lowBI := new(big.Int).And(bi, mask)
-	highBI := new(big.Int).Rsh(bi, 128)
-	return utils.BigIntToFelt(lowBI), utils.BigIntToFelt(highBI)
-}
-
-func encodeStarknetOrderData(orderData *StarknetOrderData) []*felt.Felt {
-	leftPad := func(src []byte, size int) []byte {
-		if len(src) >= size {
-			return src[len(src)-size:]
-		}
-		out := make([]byte, size)
-		copy(out[size-len(src):], src)
-		return out
-	}
-
-	feltToBytes32 := func(f *felt.Felt) []byte {
-		b := f.Bytes()
-		// Ensure we always return exactly 32 bytes
-		result := make([]byte, 32)
-
-		if len(b) >= 32 {
-			// Take the last 32 bytes if longer
-			start := len(b) - 32
-			for i := 0; i < 32; i++ {
-				result[i] = b[start+i]
-			}
-		} else {
-			// Pad with zeros if shorter
-			start := 32 - len(b)
-			for i := 0; i < len(b); i++ {
-				result[start+i] = b[i]
-			}
-		}
-
-		return result
-	}
-
-	u256ToBytes32 := func(n *big.Int) []byte {
-		if n == nil {
-			return make([]byte, 32)
-		}
-		return leftPad(n.Bytes(), 32)
-	}
-
-	u32Word := func(v uint32) []byte {
-		b := make([]byte, 32)
-		b[28] = byte(v >> 24)
-		b[29] = byte(v >> 16)
-		b[30] = byte(v >> 8)
-		b[31] = byte(v)
-		return b
-	}
-
-	writeWord := func(dst *[]byte, word []byte) {
-		*dst = append(*dst, leftPad(word, 32)...)
-	}
-
-	var raw []byte
-
-	// 0) Dynamic data offset (32 bytes) - this is the offset to where the data field starts
-	writeWord(&raw, u32Word(32))
-
-	// 1) sender, recipient, input_token, output_token (bytes32)
-	writeWord(&raw, feltToBytes32(orderData.Sender))
-	writeWord(&raw, feltToBytes32(orderData.Recipient))
-	writeWord(&raw, feltToBytes32(orderData.InputToken))
-	writeWord(&raw, feltToBytes32(orderData.OutputToken))
-	// 2) amount_in, amount_out (uint256)
-	writeWord(&raw, u256ToBytes32(orderData.AmountIn))
-	writeWord(&raw, u256ToBytes32(orderData.AmountOut))
-	// 3) sender_nonce (uint256 from felt)
-	writeWord(&raw, feltToBytes32(orderData.SenderNonce))
-	// 4) origin_domain (uint32), destination_domain (uint32) as 32-byte words
-	writeWord(&raw, u32Word(orderData.OriginDomain))
-	writeWord(&raw, u32Word(orderData.DestinationDomain))
-	// 5) destination_settler (bytes32)
-	writeWord(&raw, feltToBytes32(orderData.DestinationSettler))
-	// 6) fill_deadline (uint32) in 32-byte word (clamp lower 32 bits)
-	fill32 := uint32(orderData.FillDeadline)
-	writeWord(&raw, u32Word(fill32))
-	// 7) data offset (32 * 12 = 384)
-	writeWord(&raw, u32Word(dataOffset))
-
-	// Tail: data length (32 bytes) then data padded to 32
-	writeWord(&raw, make([]byte, 0)) // length = 0 -> becomes 32 zero bytes
-
-	// Now wrap into Cairo Bytes: size, words_len, then 16-byte words as felts
-	numElements := (len(raw) + 15) / 16
-	words := make([]*felt.Felt, 0, numElements)
-	for i := 0; i < len(raw); i += 16 {
-		end := i + 16
-		if end > len(raw) {
-			chunk := make([]byte, 16)
-			copy(chunk, raw[i:])
-			words = append(words, utils.BigIntToFelt(new(big.Int).SetBytes(chunk)))
-		} else {
-			words = append(words, utils.BigIntToFelt(new(big.Int).SetBytes(raw[i:end])))
-		}
-	}
-
-	bytesStruct := make([]*felt.Felt, 0, 2+len(words))
-	bytesStruct = append(bytesStruct,
-		utils.Uint64ToFelt(uint64(len(raw))),
-		utils.Uint64ToFelt(uint64(len(words))),
-	)
-	bytesStruct = append(bytesStruct, words...)
-
-	return bytesStruct
+		DestinationSettler: destSettler,
+		FillDeadline:       order.Deadlines.Fill,
+		Data:               []byte{},
+	}, nil
 }
 
-// getRandomDestinationChain gets a random destination chain from available networks
-func getRandomDestinationChain(originChain string) string {
-	// Get all available networks from the internal config
-	allNetworks := config.GetNetworkNames()
-
-	// Filter out the origin chain and non-EVM networks
-	var evmDestinations []string
-	for _, networkName := range allNetworks {
-		if networkName != originChain && !isStarknetNetwork(networkName) {
-			evmDestinations = append(evmDestinations, networkName)
-		}
+// fillStarknetOrderResult copies what is known about an opened (or partially opened) Starknet order into result
+func fillStarknetOrderResult(result *OrderResult, opened starknetorder.OrderResult) {
+	if opened.TransactionHash != nil {
+		result.TxHash = opened.TransactionHash.String()
+		result.OrderID = opened.OrderID.Hex()
 	}
-
-	// If no EVM networks found, use fallback
-	if len(evmDestinations) == 0 {
-		fmt.Printf("   ⚠️ No EVM networks found in config, using fallback destination\n")
-		return getEnvWithDefault("DEFAULT_EVM_DESTINATION", "Sepolia")
+	result.GasUsed = opened.GasUsed
+	if len(opened.EncodedOrder) > 0 && opened.OrderDataType != nil {
+		var orderDataType [32]byte
+		opened.OrderDataType.FillBytes(orderDataType[:])
+		result.recordOrder(opened.FillDeadline, orderDataType, opened.EncodedOrder)
 	}
-
-	// Select random destination
-	destIdx := secureRandomInt(len(evmDestinations))
-	return evmDestinations[destIdx]
 }
 
-// isStarknetNetwork checks if a network name represents a Starknet network
-func isStarknetNetwork(networkName string) bool {
-	// Check if network name contains "starknet" or "ztarknet" (case insensitive)
-	lowerName := strings.ToLower(networkName)
-	return strings.Contains(lowerName, "starknet") || strings.Contains(lowerName, "ztarknet")
+// printOpenEvent prints the Open event decoded from the open() receipt
+func printOpenEvent(event *starknetorder.OpenEvent) {
+	ro := event.ResolvedOrder
+	logf("   Open event:\n")
+	logf("     Order ID: %s\n", event.OrderID.Hex())
+	logf("     User: %s\n", ro.User.String())
+	logf("     Origin Domain: %d\n", ro.OriginChainID)
+	logf("     Fill Deadline: %d\n", ro.FillDeadline)
+	for _, out := range ro.MaxSpent {
+		logf("     Max Spent: %s of %s on domain %d\n", out.Amount.String(), out.Token.String(), out.ChainID)
+	}
+	for _, out := range ro.MinReceived {
+		logf("     Min Received: %s of %s on domain %d\n", out.Amount.String(), out.Token.String(), out.ChainID)
+	}
+	for _, fi := range ro.FillInstructions {
+		logf("     Fill Instruction: domain %d, settler %s\n", fi.DestinationChainID, fi.DestinationSettler.String())
+	}
 }
 
 // getEnvWithDefault gets an environment variable with a default fallback
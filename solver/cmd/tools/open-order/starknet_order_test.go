@@ -0,0 +1,269 @@
+package openorder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testZtarknetAlice   = "0x2a1e7a0c0b8d1c3e5f7a9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a1c3e5b7d9f1"
+	testZtarknetDogCoin = "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcde"
+	testZtarknetSettler = "0x04b1a8e1e0c3f2d5a6b7c8d9e0f1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b"
+)
+
+func TestLoadNetworkProfiles(t *testing.T) {
+	t.Setenv("STARKNET_RPC_URL", "http://localhost:5050")
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", testStarknetSettler)
+	t.Setenv("ZTARKNET_HYPERLANE_ADDRESS", testZtarknetSettler)
+	t.Cleanup(config.ResetNetworks)
+	cfg := &config.Config{Networks: config.ReloadNetworks()}
+
+	profiles, err := loadNetworkProfiles(cfg, NetworkTypeStarknet)
+	require.NoError(t, err)
+
+	// Every Starknet-type network in config is listed; EVM chains and Ztarknet are not
+	for _, profile := range profiles {
+		assert.Equal(t, NetworkTypeStarknet, GetNetworkType(profile.name), profile.name)
+	}
+
+	origin, err := findNetworkProfile(profiles, "starknet")
+	require.NoError(t, err)
+	assert.Equal(t, "Starknet", origin.name)
+	assert.Equal(t, testStarknetSettler, origin.hyperlaneAddress)
+	assert.Equal(t, config.Networks["Starknet"].ChainID, origin.chainID)
+	assert.Equal(t, uint32(config.Networks["Starknet"].HyperlaneDomain), origin.domain)
+
+	profiles, err = loadNetworkProfiles(cfg, NetworkTypeZtarknet)
+	require.NoError(t, err)
+	origin, err = findNetworkProfile(profiles, "Ztarknet")
+	require.NoError(t, err)
+	assert.Equal(t, testZtarknetSettler, origin.hyperlaneAddress)
+	assert.Equal(t, "ZTARKNET", origin.envPrefix)
+	assert.Equal(t, uint32(config.ZtarknetTestnetChainID), origin.domain)
+}
+
+func TestFindNetworkProfile(t *testing.T) {
+	profiles := []NetworkProfile{
+		{kind: NetworkTypeStarknet, name: "Starknet", hyperlaneAddress: testStarknetSettler},
+		{kind: NetworkTypeStarknet, name: "StarknetSepolia"},
+	}
+
+	profile, err := findNetworkProfile(profiles, "Starknet")
+	require.NoError(t, err)
+	assert.Equal(t, "Starknet", profile.name)
+
+	_, err = findNetworkProfile(profiles, "StarknetSepolia")
+	assert.ErrorContains(t, err, "STARKNETSEPOLIA_HYPERLANE_ADDRESS")
+
+	_, err = findNetworkProfile(profiles, "Mainnet")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown Starknet network "Mainnet"`)
+	assert.Contains(t, err.Error(), "known: Starknet, StarknetSepolia")
+}
+
+func TestMissingKeysError(t *testing.T) {
+	t.Setenv("IS_DEVNET", "true")
+	starknet := newNetworkProfile(config.NetworkConfig{Name: "Starknet"})
+	assert.EqualError(t, starknet.missingKeysError(),
+		"missing Alice's Starknet credentials: LOCAL_STARKNET_ALICE_PRIVATE_KEY and LOCAL_STARKNET_ALICE_PUBLIC_KEY are required")
+
+	ztarknet := newNetworkProfile(config.NetworkConfig{Name: "Ztarknet"})
+	assert.EqualError(t, ztarknet.missingKeysError(),
+		"missing Alice's Ztarknet credentials: ZTARKNET_ALICE_PRIVATE_KEY and ZTARKNET_ALICE_PUBLIC_KEY are required")
+}
+
+// cairoTestProfiles pins Alice, the settlers and the tokens of Starknet and Ztarknet
+func cairoTestProfiles(t *testing.T) (starknet, ztarknet *NetworkProfile) {
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("STARKNET_ALICE_ADDRESS", testStarknetAlice)
+	t.Setenv("ZTARKNET_ALICE_ADDRESS", testZtarknetAlice)
+	t.Setenv("ALICE_PUB_KEY", testEVMAlice)
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", testStarknetSettler)
+	t.Setenv("ZTARKNET_HYPERLANE_ADDRESS", testZtarknetSettler)
+	t.Setenv("EVM_HYPERLANE_ADDRESS", testEthereumSettler)
+	config.ResetNetworks()
+	t.Cleanup(config.ResetNetworks)
+	config.InitializeNetworks()
+
+	starknetProfile := newNetworkProfile(config.Networks["Starknet"])
+	ztarknetProfile := newNetworkProfile(config.Networks["Ztarknet"])
+	return &starknetProfile, &ztarknetProfile
+}
+
+// cairoTestOrder is the same order to destination from any Cairo origin
+func cairoTestOrder(origin *NetworkProfile, destination, inputToken, outputToken string) (*StarknetOrderConfig, *orderTokens) {
+	order := &StarknetOrderConfig{
+		OriginChain:      origin.name,
+		DestinationChain: destination,
+		InputAmount:      big.NewInt(1001),
+		OutputAmount:     big.NewInt(1000),
+		Deadlines:        orderdeadline.Deadlines{Open: 1_800_000_000, Fill: 1_900_000_000},
+	}
+	tokens := &orderTokens{
+		Input:  orderToken{Network: origin.name, Symbol: DefaultOrderToken, Address: inputToken, Decimals: tokenDecimals},
+		Output: orderToken{Network: destination, Symbol: DefaultOrderToken, Address: outputToken, Decimals: tokenDecimals},
+	}
+	return order, tokens
+}
+
+// TestCairoProfilesEncodeTheSameOrder opens the same order from both profiles: only the origin's sender, input
+// token and domain may differ in the open() calldata
+func TestCairoProfilesEncodeTheSameOrder(t *testing.T) {
+	starknet, ztarknet := cairoTestProfiles(t)
+
+	for _, destination := range []string{"Ethereum", "Base"} {
+		t.Run(destination, func(t *testing.T) {
+			order, tokens := cairoTestOrder(starknet, destination, testStarknetDogCoin, testEthereumDogCoin)
+			fromStarknet, err := buildCairoOrderData(starknet, order, tokens, big.NewInt(testOrderSenderNonce))
+			require.NoError(t, err)
+
+			order, tokens = cairoTestOrder(ztarknet, destination, testZtarknetDogCoin, testEthereumDogCoin)
+			fromZtarknet, err := buildCairoOrderData(ztarknet, order, tokens, big.NewInt(testOrderSenderNonce))
+			require.NoError(t, err)
+
+			assert.Equal(t, feltHex(testStarknetAlice), fromStarknet.Sender.String())
+			assert.Equal(t, feltHex(testZtarknetAlice), fromZtarknet.Sender.String())
+			assert.Equal(t, uint32(config.Networks["Starknet"].HyperlaneDomain), fromStarknet.OriginDomain)
+			assert.Equal(t, uint32(config.ZtarknetTestnetChainID), fromZtarknet.OriginDomain)
+			assert.NotEqual(t, fromStarknet.InputToken, fromZtarknet.InputToken)
+
+			// The EVM recipient and settler are left-padded the same way from both origins
+			assert.Equal(t, paddedWord(testEVMAlice), feltBytes(fromStarknet.Recipient))
+			assert.Equal(t, paddedWord(testEthereumSettler), feltBytes(fromStarknet.DestinationSettler))
+
+			// Past the origin fields, both profiles produce the same calldata
+			fromZtarknet.Sender = fromStarknet.Sender
+			fromZtarknet.OriginDomain = fromStarknet.OriginDomain
+			fromZtarknet.InputToken = fromStarknet.InputToken
+			starknetCalldata, err := starknetorder.EncodeOrderDataCalldata(&fromStarknet)
+			require.NoError(t, err)
+			ztarknetCalldata, err := starknetorder.EncodeOrderDataCalldata(&fromZtarknet)
+			require.NoError(t, err)
+			assert.Equal(t, starknetCalldata, ztarknetCalldata)
+		})
+	}
+}
+
+// TestCairoProfilesMapCairoDestinations checks each profile sends to Alice and the settler of the other Cairo chain
+func TestCairoProfilesMapCairoDestinations(t *testing.T) {
+	starknet, ztarknet := cairoTestProfiles(t)
+
+	order, tokens := cairoTestOrder(starknet, "Ztarknet", testStarknetDogCoin, testZtarknetDogCoin)
+	toZtarknet, err := buildCairoOrderData(starknet, order, tokens, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	assert.Equal(t, feltHex(testZtarknetAlice), toZtarknet.Recipient.String())
+	assert.Equal(t, feltHex(testZtarknetSettler), toZtarknet.DestinationSettler.String())
+	assert.Equal(t, uint32(config.ZtarknetTestnetChainID), toZtarknet.DestinationDomain)
+
+	order, tokens = cairoTestOrder(ztarknet, "Starknet", testZtarknetDogCoin, testStarknetDogCoin)
+	toStarknet, err := buildCairoOrderData(ztarknet, order, tokens, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	assert.Equal(t, feltHex(testStarknetAlice), toStarknet.Recipient.String())
+	assert.Equal(t, feltHex(testStarknetSettler), toStarknet.DestinationSettler.String())
+
+	// An explicit recipient overrides Alice
+	order.Recipient = "0x1234"
+	toStarknet, err = buildCairoOrderData(ztarknet, order, tokens, big.NewInt(testOrderSenderNonce))
+	require.NoError(t, err)
+	assert.Equal(t, "0x1234", toStarknet.Recipient.String())
+}
+
+func TestBuildCairoOrderDataMissingSettler(t *testing.T) {
+	starknet, _ := cairoTestProfiles(t)
+	t.Setenv("ZTARKNET_HYPERLANE_ADDRESS", "")
+	config.ResetNetworks()
+
+	order, tokens := cairoTestOrder(starknet, "Ztarknet", testStarknetDogCoin, testZtarknetDogCoin)
+	_, err := buildCairoOrderData(starknet, order, tokens, big.NewInt(testOrderSenderNonce))
+	var missing *MissingAddressError
+	require.ErrorAs(t, err, &missing)
+	assert.Equal(t, "Ztarknet", missing.Network)
+}
+
+func TestBuildCairoOrderDataRejectsInvalidDestinationAddresses(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		env         map[string]string
+		recipient   string
+		errContains string
+	}{
+		{
+			name:        "zero_evm_recipient",
+			destination: "Base",
+			recipient:   "0x0000000000000000000000000000000000000000",
+			errContains: "invalid recipient: recipient on Base must not be zero",
+		},
+		{
+			name:        "padded_evm_recipient",
+			destination: "Base",
+			recipient:   "0x000000000000000000000000" + testEVMAlice[2:],
+			errContains: "recipient on Base is not an EVM address",
+		},
+		{
+			name:        "zero_cairo_recipient",
+			destination: "Ztarknet",
+			recipient:   "0x0",
+			errContains: "recipient on Ztarknet must not be zero",
+		},
+		{
+			name:        "evm_settler_word",
+			destination: "Base",
+			env:         map[string]string{"EVM_HYPERLANE_ADDRESS": "0xffffffffffffffffffffffff" + testEthereumSettler[2:]},
+			errContains: "Base Hyperlane7683 is not an EVM address",
+		},
+		{
+			name:        "zero_evm_settler",
+			destination: "Base",
+			env:         map[string]string{"EVM_HYPERLANE_ADDRESS": "0x0000000000000000000000000000000000000000"},
+			errContains: "Base Hyperlane7683 must not be zero",
+		},
+		{
+			name:        "cairo_settler_past_the_prime",
+			destination: "Ztarknet",
+			env:         map[string]string{"ZTARKNET_HYPERLANE_ADDRESS": testFeltOutOfRange},
+			errContains: "Ztarknet Hyperlane7683 is not a valid felt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starknet, _ := cairoTestProfiles(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			config.ResetNetworks()
+
+			outputToken := testEthereumDogCoin
+			if tt.destination == "Ztarknet" {
+				outputToken = testZtarknetDogCoin
+			}
+			order, tokens := cairoTestOrder(starknet, tt.destination, testStarknetDogCoin, outputToken)
+			order.Recipient = tt.recipient
+			_, err := buildCairoOrderData(starknet, order, tokens, big.NewInt(testOrderSenderNonce))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+// feltHex normalizes an address to felt.String() form
+func feltHex(address string) string {
+	f, err := new(felt.Felt).SetString(address)
+	if err != nil {
+		panic(err)
+	}
+	return f.String()
+}
+
+func feltBytes(f *felt.Felt) []byte {
+	word := f.Bytes()
+	return word[:]
+}
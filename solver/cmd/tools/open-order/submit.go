@@ -0,0 +1,375 @@
+package openorder
+
+// Order submission
+// Every open path resolves networks, tokens and domains, runs the funds preflight and builds the order before
+// handing it to a submitter, the only step that touches the chain with a transaction. The sending submitters sign
+// with Alice's key; with --dry-run the simulating ones run open() as Alice without a key (eth_call on EVM,
+// starknet_simulateTransactions on Starknet) and print what it would emit or why it would revert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/identity"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// DryRunFlag simulates open() instead of sending any transaction
+const DryRunFlag = "--dry-run"
+
+// dryRun is set by --dry-run
+var dryRun bool
+
+// SetDryRun makes the open paths simulate open() instead of sending it
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// StripDryRunFlag removes --dry-run from args and reports whether it was present
+func StripDryRunFlag(args []string) ([]string, bool) {
+	return stripBoolFlag(args, DryRunFlag)
+}
+
+// dryRunPreflight lets a dry run carry on past a funds shortfall, which the simulation then reports as a revert
+func dryRunPreflight(err error) error {
+	var shortfall *FundsShortfallError
+	if dryRun && errors.As(err, &shortfall) {
+		warnf("   ⚠️  %v (continuing: %s)\n", err, DryRunFlag)
+		return nil
+	}
+	return err
+}
+
+// evmOpen is a fully built EVM order ready to be submitted
+type evmOpen struct {
+	client    *ethclient.Client
+	contract  *contracts.Hyperlane7683
+	hyperlane common.Address
+	order     contracts.OnchainCrossChainOrder
+	value     *big.Int // native input carried by open(), nil for ERC20 inputs
+	// expected is what the Open event should report
+	expected expectedOpen
+}
+
+// evmSubmitter sends (or simulates) the transactions of an EVM order
+type evmSubmitter interface {
+	// from is the account the order is opened from
+	from() common.Address
+	// approve lets spender take amount of token from the account
+	approve(ctx context.Context, client *ethclient.Client, token, spender common.Address, amount *big.Int) error
+	// permit does the same with an EIP-2612 permit; erc2612.ErrUnsupported means the token has none
+	permit(ctx context.Context, client *ethclient.Client, token, spender common.Address, amount *big.Int) error
+	// open opens the order and records its ID in result
+	open(ctx context.Context, open evmOpen, result *OrderResult) error
+}
+
+// newEVMSubmitter returns the submitter for the current mode. Only sending needs user's private key
+func newEVMSubmitter(user string, chainID uint64) (evmSubmitter, error) {
+	if dryRun {
+		sender, err := userAddress(user, identity.EVM)
+		if err != nil {
+			return nil, err
+		}
+		address := common.HexToAddress(sender)
+		logf("   🧪 Dry run: simulating as %s, nothing will be sent\n", address.Hex())
+		return evmSimulator{address: address}, nil
+	}
+
+	// The user's key comes from <USER>_PRIVATE_KEY (LOCAL_ on devnet) or the source <USER>_KEY_SOURCE selects
+	privateKey, err := credentials.LoadEVMKey(envutil.ConditionalKey(strings.ToUpper(user)))
+	if errors.Is(err, credentials.ErrMissingKey) {
+		return nil, fmt.Errorf("private key not found for user: %s (IS_DEVNET=%s)", user, os.Getenv("IS_DEVNET"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load private key for %s: %w", user, err)
+	}
+	id := new(big.Int).SetUint64(chainID)
+	auth, err := ethutil.NewTransactor(id, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth: %w", err)
+	}
+	return &evmSender{auth: auth, key: privateKey, chainID: id}, nil
+}
+
+// evmSender signs and sends with the user's key
+type evmSender struct {
+	auth    *bind.TransactOpts
+	key     *ecdsa.PrivateKey // signs permits
+	chainID *big.Int
+	// pendingPermit is a permit sent ahead of open(), waited for once open() is out
+	pendingPermit *gethtypes.Transaction
+}
+
+func (s *evmSender) from() common.Address { return s.auth.From }
+
+func (s *evmSender) approve(ctx context.Context, client *ethclient.Client, token, spender common.Address, amount *big.Int) error {
+	logf("   Insufficient allowance, approving %s tokens (%s)...\n", amount.String(), AutoApproveFlag)
+
+	approveTx, err := ethutil.ERC20Approve(ctx, client, s.auth, token, spender, amount)
+	if err != nil {
+		return fmt.Errorf("failed to approve tokens: %w", err)
+	}
+	logf("   Approval transaction sent: %s\n", approveTx.Hash().Hex())
+
+	logf("   ⏳ Waiting for approval confirmation...\n")
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "approve"), client, approveTx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for approval transaction: %w", err)
+	}
+	if receipt.Status != 1 {
+		return fmt.Errorf("approval transaction %s failed", approveTx.Hash().Hex())
+	}
+	logf("   Approval confirmed!\n")
+	return nil
+}
+
+func (s *evmSender) open(ctx context.Context, open evmOpen, result *OrderResult) error {
+	opts, err := s.openOpts(ctx, open)
+	if err != nil {
+		return err
+	}
+	tx, err := sendOpenTransaction(ctx, open.client, opts, open.hyperlane, open.order)
+	if err != nil {
+		return fmt.Errorf("failed to send open transaction: %w", err)
+	}
+	result.TxHash = tx.Hash().Hex()
+
+	logf("   Transaction sent: %s\n", tx.Hash().Hex())
+	if err := s.waitForPermit(ctx, open.client); err != nil {
+		return err
+	}
+	logf("   ⏳ Waiting for confirmation...\n")
+
+	// Wait for transaction confirmation; a stuck open() that gets replaced or dropped is reported instead of hanging
+	receipt, err := ethutil.WaitForTransaction(txcost.WithOperation(ctx, "open order"), open.client, tx, ethutil.WithReplacementDetection())
+	if errors.Is(err, ethutil.ErrTxReplaced) || errors.Is(err, ethutil.ErrTxDropped) {
+		return fmt.Errorf("open transaction did not land, retry the order with a higher fee: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to wait for transaction confirmation: %w", err)
+	}
+	result.GasUsed = receipt.GasUsed
+
+	if receipt.Status != 1 {
+		errorf("❌ Order opening failed\n")
+		logf("🔍 Transaction hash: %s\n", tx.Hash().Hex())
+		logf("📊 Gas used: %d\n", receipt.GasUsed)
+
+		// Try to get more details about the failure
+		logf("   🔍 Checking transaction details...\n")
+		txDetails, _, err := open.client.TransactionByHash(ctx, tx.Hash())
+		if err != nil {
+			errorf("❌ Could not retrieve transaction details: %v\n", err)
+		} else {
+			debugf("📝 Transaction data: 0x%x\n", txDetails.Data())
+		}
+		return ethutil.RevertedTxError(ctx, open.client, "open", tx, s.auth.From, receipt)
+	}
+
+	logf("✅ Order opened successfully!\n")
+	logf("📊 Gas used: %d\n", receipt.GasUsed)
+
+	// The order is on chain: record what the contract emitted, and only warn if it is not the order built
+	event, err := parseEVMOpenEvent(open.contract, open.hyperlane, receipt.Logs)
+	if err != nil {
+		warnf("   ⚠️  %v; recording the order as resolved before sending\n", err)
+		return nil
+	}
+	printEVMOpenEvent(event)
+	warnEVMOpenEvent("Hyperlane7683 "+open.hyperlane.Hex(), diffEVMOpenEvent(event, open.expected))
+	result.recordOpenEvent(event)
+	return nil
+}
+
+// evmSimulator runs open() as address through eth_call; approvals are only reported
+type evmSimulator struct {
+	address common.Address
+}
+
+func (s evmSimulator) from() common.Address { return s.address }
+
+func (s evmSimulator) approve(_ context.Context, _ *ethclient.Client, token, spender common.Address, amount *big.Int) error {
+	warnf("   ⚠️  Skipping approve(%s, %s) on %s (%s): open() will revert if the allowance is short\n", spender.Hex(), amount.String(), token.Hex(), DryRunFlag)
+	return nil
+}
+
+func (s evmSimulator) open(ctx context.Context, open evmOpen, result *OrderResult) error {
+	data, err := packOpen(open.order)
+	if err != nil {
+		return err
+	}
+	result.Calldata = hexutil.Encode(data)
+	logf("   open() calldata: %s\n", result.Calldata)
+	logf("   Order data type: %s\n", hexutil.Encode(open.order.OrderDataType[:]))
+	logf("   Encoded order: %s\n", hexutil.Encode(open.order.OrderData))
+
+	msg := ethereum.CallMsg{From: s.address, To: &open.hyperlane, Value: open.value, Data: data}
+	if _, err := open.client.CallContract(ctx, msg, nil); err != nil {
+		return fmt.Errorf("open() would revert: %w", ethutil.RevertReason(err))
+	}
+	if gas, err := open.client.EstimateGas(ctx, msg); err == nil {
+		result.GasUsed = gas
+	}
+
+	logf("✅ open() simulation succeeded (estimated gas: %d)\n", result.GasUsed)
+	return nil
+}
+
+// starknetSubmitter opens (or simulates) a fully built Starknet or Ztarknet order from sender
+type starknetSubmitter func(ctx context.Context, client *rpc.Provider, sender *felt.Felt, params starknetorder.OrderParams, result *OrderResult) error
+
+// newStarknetSubmitter returns the submitter for the current mode, signing with signer's key pair; missingKeys is
+// returned when sending without one. The key pair must be consistent and, when registeredKey is set, be the
+// public key the signer is registered under
+func newStarknetSubmitter(signer cairoSigner, registeredKey string, missingKeys error) (starknetSubmitter, error) {
+	if dryRun {
+		logf("   🧪 Dry run: simulating open(), nothing will be sent\n")
+		return simulateStarknetOrder, nil
+	}
+	key, err := credentials.LoadStarknetKey(signer.keyPrefix)
+	if errors.Is(err, credentials.ErrMissingKey) {
+		return nil, missingKeys
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s key: %w", signer.keyPrefix, err)
+	}
+	if err := checkSignerKey(signer, key, registeredKey); err != nil {
+		return nil, err
+	}
+	return sendStarknetOrder(key, signer.versionEnv), nil
+}
+
+// checkSignerKey checks the loaded key pair derives its public key and, when registeredKey is set, is the one the
+// signer is registered under
+func checkSignerKey(signer cairoSigner, key credentials.StarknetKeyPair, registeredKey string) error {
+	if err := key.Verify(); err != nil {
+		return fmt.Errorf("invalid %s key pair: %w", signer.keyPrefix, err)
+	}
+	if registeredKey == "" {
+		return nil
+	}
+	registered, err := utils.HexToFelt(registeredKey)
+	if err != nil {
+		return fmt.Errorf("invalid registered public key of %s: %w", signer.user, err)
+	}
+	if !registered.Equal(key.PublicKey) {
+		return fmt.Errorf("%s key pair has public key %s, but %s is registered with %s",
+			signer.keyPrefix, key.PublicKey.String(), signer.user, registered.String())
+	}
+	return nil
+}
+
+// sendStarknetOrder approves (if needed) and opens the order with the given key pair
+func sendStarknetOrder(key credentials.StarknetKeyPair, versionEnv string) starknetSubmitter {
+	return func(ctx context.Context, client *rpc.Provider, sender *felt.Felt, params starknetorder.OrderParams, result *OrderResult) error {
+		// A key the account does not hold fails validation on the node with an error that does not say so
+		if err := checkAccountKey(ctx, client, sender, key.PublicKey); err != nil {
+			return err
+		}
+
+		// Create user account with the Cairo version of its contract
+		accnt, err := starknetutil.NewAccount(ctx, client, versionEnv, sender, key.PublicKey.String(), key.Keystore())
+		if err != nil {
+			return fmt.Errorf("failed to create account for %s: %w", sender.String(), err)
+		}
+
+		logf("   Sending open transaction...\n")
+		opened, err := starknetorder.OpenOrder(ctx, client, accnt, params)
+		fillStarknetOrderResult(result, opened)
+		if err != nil {
+			return fmt.Errorf("failed to open order: %w", err)
+		}
+
+		if opened.ApprovalTxHash != nil {
+			logf("   Approval transaction: %s\n", opened.ApprovalTxHash.String())
+		}
+		logf("   Transaction: %s\n", opened.TransactionHash.String())
+		logf("   Order opened successfully!\n")
+		printOpenEvent(opened.Event)
+		return nil
+	}
+}
+
+// checkAccountKey checks the account at sender holds publicKey. Accounts without a public key view (Argent,
+// Braavos) cannot be checked and only warn
+func checkAccountKey(ctx context.Context, caller starknetutil.ContractCaller, sender, publicKey *felt.Felt) error {
+	err := starknetutil.CheckAccountPublicKey(ctx, caller, sender, publicKey)
+	if errors.Is(err, starknetutil.ErrPublicKeyUnavailable) {
+		warnf("   ⚠️  Cannot check the public key of account %s: %v\n", sender.String(), err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("account key check failed: %w", err)
+	}
+	return nil
+}
+
+// simulateStarknetOrder runs open() from sender through starknet_simulateTransactions with validation skipped
+func simulateStarknetOrder(ctx context.Context, client *rpc.Provider, sender *felt.Felt, params starknetorder.OrderParams, result *OrderResult) error {
+	if params.AutoApprove {
+		warnf("   ⚠️  Skipping approve (%s): open() will revert if the allowance is short\n", DryRunFlag)
+	}
+	simulated, err := starknetorder.SimulateOpen(ctx, client, sender, params)
+	if len(simulated.EncodedOrder) > 0 {
+		var orderDataType [32]byte
+		simulated.OrderDataType.FillBytes(orderDataType[:])
+		result.recordOrder(simulated.FillDeadline, orderDataType, simulated.EncodedOrder)
+		result.OrderID = simulated.OrderID.Hex()
+		result.Calldata = feltsHex(simulated.InvokeCalldata)
+
+		logf("   open() calldata: %s\n", feltsHex(simulated.Calldata))
+		logf("   Order data type: %s\n", result.OrderDataType)
+		logf("   Encoded order: %s\n", result.OrderData)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to simulate open: %w", err)
+	}
+	result.GasUsed = simulated.L2Gas
+	if simulated.RevertReason != "" {
+		return fmt.Errorf("open() would revert: %s", simulated.RevertReason)
+	}
+
+	logf("✅ open() simulation succeeded (L2 gas: %d)\n", simulated.L2Gas)
+	printOpenEvent(simulated.Event)
+	return nil
+}
+
+// feltsHex joins felts as a comma-separated hex list
+func feltsHex(felts []*felt.Felt) string {
+	parts := make([]string, len(felts))
+	for i, f := range felts {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// printOrderSummary closes the log of an opened order, with the amounts in tokens and base units of each side
+func printOrderSummary(originChain, destinationChain string, tokens *orderTokens, inputAmount, outputAmount *big.Int) {
+	logf("\n🎉 Order execution completed!\n")
+	logf("   Order Summary:\n")
+	logf("   Input Amount: %s\n", tokens.Input.describeAmount(inputAmount))
+	logf("   Output Amount: %s\n", tokens.Output.describeAmount(outputAmount))
+	logf("   Origin Chain: %s\n", originChain)
+	logf("   Destination Chain: %s\n", destinationChain)
+}
@@ -0,0 +1,133 @@
+package openorder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/credentials"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// useDryRun sets --dry-run for the duration of a test
+func useDryRun(t *testing.T, enabled bool) {
+	saved := dryRun
+	SetDryRun(enabled)
+	t.Cleanup(func() { SetDryRun(saved) })
+}
+
+func TestStripDryRunFlag(t *testing.T) {
+	args, found := StripDryRunFlag([]string{"solver", "tools", "open-order", "base", "--dry-run", "starknet"})
+	assert.True(t, found)
+	assert.Equal(t, []string{"solver", "tools", "open-order", "base", "starknet"}, args)
+}
+
+func TestDryRunPreflight(t *testing.T) {
+	shortfall := &FundsShortfallError{Check: "balance", Token: testEthereumDogCoin, Holder: testEVMAlice, Have: big.NewInt(1), Need: big.NewInt(2)}
+	other := errors.New("failed to read allowance")
+
+	useDryRun(t, false)
+	assert.ErrorIs(t, dryRunPreflight(shortfall), shortfall)
+	assert.NoError(t, dryRunPreflight(nil))
+
+	useDryRun(t, true)
+	assert.NoError(t, dryRunPreflight(shortfall), "a shortfall only warns in a dry run")
+	assert.ErrorIs(t, dryRunPreflight(other), other, "read errors still abort")
+}
+
+func TestNewEVMSubmitterDryRunNeedsNoKey(t *testing.T) {
+	t.Setenv("IS_DEVNET", "")
+	t.Setenv("ALICE_PRIVATE_KEY", "")
+
+	useDryRun(t, false)
+	_, err := newEVMSubmitter(AliceUserName, 1)
+	assert.ErrorContains(t, err, "private key not found")
+
+	useDryRun(t, true)
+	useTestAlice(t)
+	submitter, err := newEVMSubmitter(AliceUserName, 1)
+	require.NoError(t, err)
+	assert.IsType(t, evmSimulator{}, submitter)
+	assert.Equal(t, common.HexToAddress(testEVMAlice), submitter.from())
+
+	_, err = newEVMSubmitter("Mallory", 1)
+	assert.ErrorContains(t, err, `unknown user "Mallory"`)
+}
+
+func TestNewStarknetSubmitterDryRunNeedsNoKey(t *testing.T) {
+	missing := errors.New("missing keys")
+	t.Setenv("TEST_ALICE_KEY_SOURCE", "")
+	t.Setenv("TEST_ALICE_PRIVATE_KEY", "")
+
+	useDryRun(t, false)
+	_, err := newStarknetSubmitter(cairoSigner{user: AliceUserName, keyPrefix: "TEST_ALICE"}, "", missing)
+	assert.ErrorIs(t, err, missing)
+
+	t.Setenv("TEST_ALICE_KEY_SOURCE", "keystore:")
+	_, err = newStarknetSubmitter(cairoSigner{user: AliceUserName, keyPrefix: "TEST_ALICE"}, "", missing)
+	assert.ErrorContains(t, err, "invalid TEST_ALICE_KEY_SOURCE", "a broken key source is not reported as missing keys")
+
+	useDryRun(t, true)
+	submit, err := newStarknetSubmitter(cairoSigner{user: AliceUserName, keyPrefix: "TEST_ALICE"}, "", missing)
+	require.NoError(t, err)
+	assert.NotNil(t, submit)
+}
+
+func TestCompleteDryRun(t *testing.T) {
+	useDryRun(t, true)
+
+	result := newOrderResult("Base", "Starknet", big.NewInt(1), big.NewInt(1))
+	result.complete(nil)
+	assert.Equal(t, OrderStatusSimulated, result.Status)
+
+	result.complete(errors.New("open() would revert: InvalidNonce()"))
+	assert.Equal(t, OrderStatusFailed, result.Status)
+}
+
+// keyedAccount is a Starknet account holding publicKey, read through get_public_key
+type keyedAccount struct {
+	publicKey *felt.Felt
+}
+
+func (a keyedAccount) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	if a.publicKey == nil || !call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt("get_public_key")) {
+		return nil, errors.New("Contract error: ENTRYPOINT_NOT_FOUND")
+	}
+	return []*felt.Felt{a.publicKey}, nil
+}
+
+func TestCheckSignerKey(t *testing.T) {
+	key, err := credentials.NewStarknetKeyPair(big.NewInt(0x1234))
+	require.NoError(t, err)
+	signer := cairoSigner{user: AliceUserName, keyPrefix: "TEST_ALICE"}
+
+	require.NoError(t, checkSignerKey(signer, key, ""))
+	require.NoError(t, checkSignerKey(signer, key, key.PublicKey.String()))
+	assert.ErrorContains(t, checkSignerKey(signer, key, "0x5678"), "but Alice is registered with 0x5678")
+
+	drifted := credentials.StarknetKeyPair{PrivateKey: key.PrivateKey, PublicKey: new(felt.Felt).SetUint64(0x5678)}
+	assert.ErrorContains(t, checkSignerKey(signer, drifted, ""), "invalid TEST_ALICE key pair")
+}
+
+func TestCheckAccountKey(t *testing.T) {
+	ctx := context.Background()
+	sender := new(felt.Felt).SetUint64(0xa11ce)
+	configured := new(felt.Felt).SetUint64(0x1234)
+
+	require.NoError(t, checkAccountKey(ctx, keyedAccount{publicKey: configured}, sender, configured))
+
+	err := checkAccountKey(ctx, keyedAccount{publicKey: new(felt.Felt).SetUint64(0x5678)}, sender, configured)
+	var mismatch *starknetutil.AccountKeyMismatchError
+	require.ErrorAs(t, err, &mismatch, "a mismatched key refuses to send")
+	assert.Equal(t, "0x5678", mismatch.OnChain.String())
+
+	assert.NoError(t, checkAccountKey(ctx, keyedAccount{}, sender, configured), "accounts without a key view only warn")
+}
@@ -0,0 +1,410 @@
+package openorder
+
+// Order tokens
+// --input-token and --output-token take a token symbol or a raw 0x address. A symbol must name a token of the
+// deploy token set (deploy-tokens.json, see pkg/tokenspec), the same list the setup tools deploy and fund, and
+// resolves per network from <NETWORK>_<NAME>_ADDRESS in .env (DogCoin reads <NETWORK>_DOG_COIN_ADDRESS), then
+// from the mock ERC20 deployment state in state/deployment. The input token must exist on the origin and the
+// output token on the destination; there is no fallback to another chain's token, and an unset or zero address
+// is an error. Order amounts are generated in 18-decimal units and scaled to each token's own decimals once
+// those are read from chain, unless --amount-in/--amount-out set them (see amounts.go)
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/chain"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	// InputTokenFlag selects the token Alice locks on the origin chain
+	InputTokenFlag = "--input-token"
+	// OutputTokenFlag selects the token Alice receives on the destination chain
+	OutputTokenFlag = "--output-token"
+	// DefaultOrderToken is used when no token flag is given
+	DefaultOrderToken = "DogCoin"
+)
+
+// TokenSelection is the token pair requested on the command line, as symbols or addresses
+type TokenSelection struct {
+	Input  string
+	Output string
+}
+
+// tokenSelection is the pair used by every order this run opens
+var tokenSelection = TokenSelection{Input: DefaultOrderToken, Output: DefaultOrderToken}
+
+// SetTokenSelection sets the token pair for the orders of this run; empty fields keep DefaultOrderToken
+func SetTokenSelection(selection TokenSelection) {
+	if selection.Input == "" {
+		selection.Input = DefaultOrderToken
+	}
+	if selection.Output == "" {
+		selection.Output = DefaultOrderToken
+	}
+	tokenSelection = selection
+}
+
+// ExtractTokenFlags removes --input-token/--output-token (or the =value forms) from args
+func ExtractTokenFlags(args []string) ([]string, TokenSelection, error) {
+	out := make([]string, 0, len(args))
+	var selection TokenSelection
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var target *string
+		var flag string
+		switch {
+		case arg == InputTokenFlag || strings.HasPrefix(arg, InputTokenFlag+"="):
+			target, flag = &selection.Input, InputTokenFlag
+		case arg == OutputTokenFlag || strings.HasPrefix(arg, OutputTokenFlag+"="):
+			target, flag = &selection.Output, OutputTokenFlag
+		default:
+			out = append(out, arg)
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+			*target = value
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			*target = args[i+1]
+			i++
+		}
+		if *target == "" {
+			return nil, TokenSelection{}, fmt.Errorf("%s requires a token symbol or address", flag)
+		}
+	}
+	return out, selection, nil
+}
+
+// orderToken is a token resolved on one network
+type orderToken struct {
+	Network  string
+	Symbol   string // the symbol or address it was requested as
+	Address  string
+	Source   string // where the address came from, used in errors
+	Decimals int
+}
+
+// orderTokens is the resolved input (origin) and output (destination) token of an order
+type orderTokens struct {
+	Input  orderToken
+	Output orderToken
+}
+
+// word returns the token address as the bytes32 OrderData word of its network (see chain.Network)
+func (t orderToken) word() ([32]byte, error) {
+	word, err := chain.Of(t.Network).AddressToBytes32(t.Address)
+	if errors.Is(err, chain.ErrNotEVMAddress) {
+		return [32]byte{}, fmt.Errorf("invalid %s address %q from %s", t.Network, t.Address, t.Source)
+	}
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("%s %w", t.Source, err)
+	}
+	return word, nil
+}
+
+// felt returns the token address as a Cairo ContractAddress
+func (t orderToken) felt() (*felt.Felt, error) {
+	word, err := t.word()
+	if err != nil {
+		return nil, err
+	}
+	f, err := starknetutil.Bytes32ToFelt(word)
+	if err != nil {
+		return nil, fmt.Errorf("%s token from %s: %w", t.Network, t.Source, err)
+	}
+	return f, nil
+}
+
+// check rejects an input or output token that is unset, zero or not an address of its network
+func (t *orderTokens) check() error {
+	if t == nil {
+		return errors.New("order tokens are not resolved")
+	}
+	if _, err := t.Input.word(); err != nil {
+		return fmt.Errorf("input token: %w", err)
+	}
+	if _, err := t.Output.word(); err != nil {
+		return fmt.Errorf("output token: %w", err)
+	}
+	return nil
+}
+
+// tokenDecimalsReader reads a token's decimals from chain; replaced in tests
+var tokenDecimalsReader = readTokenDecimals
+
+// resolveOrderTokens resolves the input token on the origin and the output token on the destination,
+// and reads their decimals
+func resolveOrderTokens(ctx context.Context, inputToken, outputToken, originChain, destinationChain string) (*orderTokens, error) {
+	input, err := resolveToken(originChain, inputToken, InputTokenFlag)
+	if err != nil {
+		return nil, err
+	}
+	output, err := resolveToken(destinationChain, outputToken, OutputTokenFlag)
+	var missing *MissingAddressError
+	if errors.As(err, &missing) && forceOriginFallback {
+		warnf("   ⚠️  %s: no %s on %s, using %s's %s; this order cannot be filled\n",
+			ForceFlag, missing.Key, destinationChain, originChain, input.Address)
+		output = input
+	} else if err != nil {
+		return nil, err
+	}
+	if input.Decimals, err = tokenDecimalsReader(ctx, input.Network, input.Address); err != nil {
+		return nil, err
+	}
+	if output.Decimals, err = tokenDecimalsReader(ctx, output.Network, output.Address); err != nil {
+		return nil, err
+	}
+	return &orderTokens{Input: input, Output: output}, nil
+}
+
+// resolveToken resolves a symbol or raw address on networkName and validates the address for its network type
+func resolveToken(networkName, token, flag string) (orderToken, error) {
+	if token == "" {
+		token = DefaultOrderToken
+	}
+	resolved := orderToken{Network: networkName, Symbol: token}
+
+	switch {
+	case strings.HasPrefix(token, "0x") || strings.HasPrefix(token, "0X"):
+		resolved.Address, resolved.Source = token, flag
+	default:
+		spec, err := specToken(token, flag)
+		if err != nil {
+			return orderToken{}, err
+		}
+		envName := tokenspec.EnvName(networkName, spec.Name)
+		if address := os.Getenv(envName); address != "" {
+			resolved.Address, resolved.Source = address, envName
+		} else if address, file, ok := deployedTokenAddress(deploystate.Dir(), networkName, spec.Name); ok {
+			resolved.Address, resolved.Source = address, file
+		} else {
+			return orderToken{}, missingTokenError(deploystate.Dir(), networkName, spec.Name, flag)
+		}
+	}
+
+	if _, err := resolved.word(); err != nil {
+		return orderToken{}, err
+	}
+	return resolved, nil
+}
+
+// specToken looks a symbol up in the deploy token set, so an order cannot use a token the setup never deployed
+func specToken(token, flag string) (tokenspec.TokenSpec, error) {
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return tokenspec.TokenSpec{}, err
+	}
+	if spec, ok := tokenspec.Find(specs, token); ok {
+		return spec, nil
+	}
+	return tokenspec.TokenSpec{}, fmt.Errorf("%s %s is not in the deploy token set %s (%s): add and deploy it, or pass a 0x address",
+		flag, token, tokenspec.Path(), strings.Join(tokenspec.Names(specs), ", "))
+}
+
+// missingTokenError reports token as unresolved on networkName, telling a network the deployment state in dir
+// does not know at all from one whose deployment lacks the token
+func missingTokenError(dir, networkName, token, flag string) *MissingAddressError {
+	envName := tokenspec.EnvName(networkName, token)
+	hint := fmt.Sprintf("set %s, add it to %s or pass a 0x address with %s", envName, dir, flag)
+	if tokens, _ := networkTokens(dir, networkName); len(tokens) == 0 {
+		hint = fmt.Sprintf("set %s or pass a 0x address with %s; %s has no deployment state for %s", envName, flag, dir, networkName)
+	}
+	return &MissingAddressError{
+		Key:         token + "Address",
+		Network:     networkName,
+		Hint:        hint,
+		Setup:       tokenSetupStep(networkName),
+		Destination: flag == OutputTokenFlag,
+	}
+}
+
+// tokenSetupStep is the step deploying the token set on networkName; Ztarknet has no setup step
+func tokenSetupStep(networkName string) string {
+	switch GetNetworkType(networkName) {
+	case NetworkTypeEVM:
+		return "deploy-forge-mock-erc20"
+	case NetworkTypeStarknet:
+		return "deploy-sn-mock-erc20"
+	default:
+		return ""
+	}
+}
+
+// networkTokens reads the tokens recorded for networkName in the deployment state in dir and the file they were
+// read from; none when the state is unreadable
+func networkTokens(dir, networkName string) ([]deploystate.TokenDeployment, string) {
+	state, err := deploystate.ReadState(dir)
+	if err != nil {
+		return nil, ""
+	}
+	return state.Network(networkName).SortedTokens(), state.TokenSource(networkName)
+}
+
+// deployedTokenAddress looks a token up by name or symbol in the deployment state in dir
+func deployedTokenAddress(dir, networkName, token string) (address, file string, ok bool) {
+	tokens, file := networkTokens(dir, networkName)
+	for _, t := range tokens {
+		if t.Address != "" && (strings.EqualFold(t.Name, token) || strings.EqualFold(t.Symbol, token)) {
+			return t.Address, file, true
+		}
+	}
+	return "", "", false
+}
+
+// KnownToken is a token address found for a network in .env or the deployment state
+type KnownToken struct {
+	Symbol  string
+	Address string
+	Source  string
+}
+
+// knownTokenSymbols are the deploy token set, looked up in .env on every network
+func knownTokenSymbols() []string {
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		specs = tokenspec.Defaults()
+	}
+	return tokenspec.Names(specs)
+}
+
+// KnownTokens lists the deploy token set from .env followed by every token in the deployment state for
+// networkName, once per address
+func KnownTokens(networkName string) []KnownToken {
+	return knownTokens(deploystate.Dir(), networkName)
+}
+
+func knownTokens(dir, networkName string) []KnownToken {
+	var tokens []KnownToken
+	seen := make(map[string]bool)
+	add := func(t KnownToken) {
+		key := strings.ToLower(t.Address)
+		if t.Address == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		tokens = append(tokens, t)
+	}
+
+	for _, symbol := range knownTokenSymbols() {
+		envName := tokenspec.EnvName(networkName, symbol)
+		add(KnownToken{Symbol: symbol, Address: os.Getenv(envName), Source: envName})
+	}
+	deployed, file := networkTokens(dir, networkName)
+	for _, t := range deployed {
+		symbol := t.Name
+		if symbol == "" {
+			symbol = t.Symbol
+		}
+		add(KnownToken{Symbol: symbol, Address: t.Address, Source: file})
+	}
+	return tokens
+}
+
+// tokenDecimalsCache keeps decimals per network and token so batch runs read them once
+var tokenDecimalsCache sync.Map
+
+// readTokenDecimals reads decimals() of a token on any configured network
+func readTokenDecimals(ctx context.Context, networkName, address string) (int, error) {
+	key := networkName + "/" + strings.ToLower(address)
+	if cached, ok := tokenDecimalsCache.Load(key); ok {
+		return cached.(int), nil
+	}
+	network, ok := config.Networks[networkName]
+	if !ok {
+		return 0, fmt.Errorf("network %s not found in config", networkName)
+	}
+
+	var decimals uint8
+	if GetNetworkType(networkName) == NetworkTypeEVM {
+		client, err := clients().EVMFailover(ctx, networkName, network.RPCURLs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to connect to %s: %w", networkName, err)
+		}
+		decimals, err = ethutil.ERC20Decimals(ctx, client, common.HexToAddress(address))
+		if err != nil {
+			return 0, fmt.Errorf("token %s on %s: %w", address, networkName, err)
+		}
+	} else {
+		provider, err := clients().StarknetFailover(ctx, networkName, network.RPCURLs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to connect to %s: %w", networkName, err)
+		}
+		decimals, err = starknetutil.ERC20Decimals(ctx, provider, address)
+		if err != nil {
+			return 0, fmt.Errorf("token %s on %s: %w", address, networkName, err)
+		}
+	}
+
+	tokenDecimalsCache.Store(key, int(decimals))
+	return int(decimals), nil
+}
+
+// scaleTokenAmount converts an amount written in 18-decimal units to a token with the given decimals
+func scaleTokenAmount(amount *big.Int, decimals int) *big.Int {
+	if amount == nil || decimals == tokenDecimals {
+		return amount
+	}
+	if decimals > tokenDecimals {
+		return new(big.Int).Mul(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-tokenDecimals)), nil))
+	}
+	return new(big.Int).Quo(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tokenDecimals-decimals)), nil))
+}
+
+// apply scales the order amounts to the token decimals, or replaces them with the --amount-in/--amount-out
+// amounts, and records the tokens and final amounts in result
+func (t *orderTokens) apply(inputAmount, outputAmount *big.Int, result *OrderResult) (*big.Int, *big.Int, error) {
+	inputAmount, err := orderAmount(AmountInFlag, orderAmounts.In, inputAmount, t.Input.Decimals)
+	if err != nil {
+		return nil, nil, err
+	}
+	outputAmount, err = orderAmount(AmountOutFlag, orderAmounts.Out, outputAmount, t.Output.Decimals)
+	if err != nil {
+		return nil, nil, err
+	}
+	if orderAmounts.isSet() {
+		if err := checkSpread(inputAmount, t.Input.Decimals, outputAmount, t.Output.Decimals, orderAmounts.AllowNegativeSpread); err != nil {
+			return nil, nil, err
+		}
+	}
+	if result != nil {
+		result.InputToken = t.Input.Address
+		result.OutputToken = t.Output.Address
+		result.InputAmount = inputAmount.String()
+		result.OutputAmount = outputAmount.String()
+	}
+	logf("   Input token: %s %s on %s (%d decimals)\n", t.Input.Symbol, t.Input.Address, t.Input.Network, t.Input.Decimals)
+	logf("   Output token: %s %s on %s (%d decimals)\n", t.Output.Symbol, t.Output.Address, t.Output.Network, t.Output.Decimals)
+	logf("   Amounts: %s in, %s out\n", t.Input.describeAmount(inputAmount), t.Output.describeAmount(outputAmount))
+	return inputAmount, outputAmount, nil
+}
+
+// resolveTokens resolves the order's tokens once and scales its amounts to their decimals
+func (o *OrderConfig) resolveTokens(ctx context.Context, result *OrderResult) error {
+	if o.tokens != nil {
+		return nil
+	}
+	tokens, err := resolveOrderTokens(ctx, o.InputToken, o.OutputToken, o.OriginChain, o.DestinationChain)
+	if err != nil {
+		return err
+	}
+	o.InputAmount, o.OutputAmount, err = tokens.apply(o.InputAmount, o.OutputAmount, result)
+	if err != nil {
+		return err
+	}
+	o.tokens = tokens
+	return nil
+}
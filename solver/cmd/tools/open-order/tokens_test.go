@@ -0,0 +1,316 @@
+package openorder
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/evmtest"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+func TestExtractTokenFlags(t *testing.T) {
+	args, selection, err := ExtractTokenFlags([]string{"evm", "--input-token", "OrcaCoin", "starknet", "--output-token=0xabc", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evm", "starknet", "--json"}, args)
+	assert.Equal(t, TokenSelection{Input: "OrcaCoin", Output: "0xabc"}, selection)
+
+	_, _, err = ExtractTokenFlags([]string{"evm", "--input-token", "--json"})
+	assert.ErrorContains(t, err, "--input-token requires a token symbol or address")
+
+	args, selection, err = ExtractTokenFlags([]string{"evm", "base"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evm", "base"}, args)
+	assert.Equal(t, TokenSelection{}, selection)
+}
+
+func TestResolveToken(t *testing.T) {
+	t.Run("symbol from env", func(t *testing.T) {
+		t.Setenv("OPTIMISM_DOG_COIN_ADDRESS", testOptimismDogCoin)
+		token, err := resolveToken("Optimism", "DogCoin", OutputTokenFlag)
+		require.NoError(t, err)
+		assert.Equal(t, testOptimismDogCoin, token.Address)
+		assert.Equal(t, "OPTIMISM_DOG_COIN_ADDRESS", token.Source)
+	})
+
+	t.Run("raw addresses are validated per network type", func(t *testing.T) {
+		token, err := resolveToken("Starknet", testStarknetDogCoin, OutputTokenFlag)
+		require.NoError(t, err)
+		assert.Equal(t, testStarknetDogCoin, token.Address)
+
+		_, err = resolveToken("Base", testStarknetDogCoin, InputTokenFlag)
+		assert.ErrorContains(t, err, "invalid Base address")
+
+		_, err = resolveToken("Starknet", "0x0", OutputTokenFlag)
+		assert.ErrorContains(t, err, "--output-token must not be zero")
+	})
+
+	t.Run("symbols resolve through the deploy token set", func(t *testing.T) {
+		useTestTokenSpecs(t)
+		t.Setenv("OPTIMISM_ORCA_COIN_ADDRESS", testOptimismDogCoin)
+		token, err := resolveToken("Optimism", "orca", InputTokenFlag)
+		require.NoError(t, err)
+		assert.Equal(t, testOptimismDogCoin, token.Address)
+		assert.Equal(t, "OPTIMISM_ORCA_COIN_ADDRESS", token.Source)
+	})
+
+	t.Run("symbols outside the deploy token set are an error", func(t *testing.T) {
+		t.Setenv("OPTIMISM_ORCA_COIN_ADDRESS", testOptimismDogCoin)
+		_, err := resolveToken("Optimism", "OrcaCoin", InputTokenFlag)
+		assert.ErrorContains(t, err, "--input-token OrcaCoin is not in the deploy token set")
+		assert.ErrorContains(t, err, "deploy-tokens.json (DogCoin)")
+	})
+
+	t.Run("missing deployment on the destination is an error", func(t *testing.T) {
+		useTestTokenSpecs(t)
+		t.Setenv("ARBITRUM_ORCA_COIN_ADDRESS", "")
+		_, err := resolveToken("Arbitrum", "OrcaCoin", OutputTokenFlag)
+		assert.ErrorContains(t, err, `missing OrcaCoinAddress for network "Arbitrum" (set ARBITRUM_ORCA_COIN_ADDRESS`)
+	})
+
+	t.Run("zero addresses are an error", func(t *testing.T) {
+		t.Setenv("BASE_DOG_COIN_ADDRESS", "0x0000000000000000000000000000000000000000")
+		_, err := resolveToken("Base", "DogCoin", InputTokenFlag)
+		assert.ErrorContains(t, err, "BASE_DOG_COIN_ADDRESS must not be zero")
+	})
+}
+
+// useTestTokenSpecs points DEPLOY_TOKENS_FILE at a token set of DogCoin and OrcaCoin
+func useTestTokenSpecs(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "deploy-tokens.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "DogCoin", "symbol": "DOG", "decimals": 18},
+		{"name": "OrcaCoin", "symbol": "ORCA", "decimals": 18}
+	]`), 0o600))
+	t.Setenv("DEPLOY_TOKENS_FILE", path)
+}
+
+func TestDeployedTokenAddress(t *testing.T) {
+	dir := t.TempDir()
+	deployment := `{"networkName":"Starknet","tokens":[{"name":"DogCoin","symbol":"DOG","address":"` + testStarknetDogCoin + `"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "starknet-mock-erc20-deployment.json"), []byte(deployment), 0o600))
+
+	for _, token := range []string{"DogCoin", "dog"} {
+		address, file, ok := deployedTokenAddress(dir, "Starknet", token)
+		require.True(t, ok, token)
+		assert.Equal(t, testStarknetDogCoin, address)
+		assert.Equal(t, filepath.Join(dir, "starknet-mock-erc20-deployment.json"), file)
+	}
+
+	_, _, ok := deployedTokenAddress(dir, "Ztarknet", "DogCoin")
+	assert.False(t, ok)
+}
+
+func TestResolveTokenRejectsCorruptDeploymentState(t *testing.T) {
+	useTestTokenSpecs(t)
+	stateDir := t.TempDir()
+	t.Setenv(solverdir.StateDirEnv, stateDir)
+	dir := deploystate.Dir()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	writeDeployment := func(network, address string) string {
+		path := filepath.Join(dir, strings.ToLower(network)+"-mock-erc20-deployment.json")
+		deployment := `{"networkName":"` + network + `","tokens":[{"name":"DogCoin","symbol":"DOG","address":"` + address + `"}]}`
+		require.NoError(t, os.WriteFile(path, []byte(deployment), 0o600))
+		return path
+	}
+	t.Setenv("STARKNET_DOG_COIN_ADDRESS", "")
+	t.Setenv("BASE_DOG_COIN_ADDRESS", "")
+
+	// A felt past the Stark prime would wrap to another address if it were reduced
+	path := writeDeployment("Starknet", testFeltOutOfRange)
+	_, err := resolveToken("Starknet", "DogCoin", OutputTokenFlag)
+	assert.ErrorContains(t, err, path+" is not a valid felt")
+
+	// A 32-byte word with its top bits set is not an EVM address, and is not truncated into one
+	path = writeDeployment("Base", "0xf00000000000000000000000"+testEthereumDogCoin[2:])
+	_, err = resolveToken("Base", "DogCoin", OutputTokenFlag)
+	assert.ErrorContains(t, err, "invalid Base address")
+	assert.ErrorContains(t, err, path)
+
+	writeDeployment("Base", "0x0000000000000000000000000000000000000000")
+	_, err = resolveToken("Base", "DogCoin", OutputTokenFlag)
+	assert.ErrorContains(t, err, "must not be zero")
+
+	// The felt conversion checks the field on its own, for tokens built without resolveToken
+	_, err = orderToken{Network: "Starknet", Address: testFeltOutOfRange, Source: path}.felt()
+	assert.ErrorContains(t, err, "is not a valid felt")
+}
+
+func TestMissingTokenError(t *testing.T) {
+	dir := t.TempDir()
+	deployment := `{"networkName":"Base","tokens":[{"name":"DogCoin","symbol":"DOG","address":"` + testEthereumDogCoin + `"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base-mock-erc20-deployment.json"), []byte(deployment), 0o600))
+
+	tests := []struct {
+		name     string
+		network  string
+		token    string
+		flag     string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "network missing from the deployment state",
+			network:  "Optimism",
+			token:    "DogCoin",
+			flag:     InputTokenFlag,
+			contains: []string{"has no deployment state for Optimism", "run `solver tools deploy-forge-mock-erc20` to deploy it"},
+			excludes: []string{ForceFlag},
+		},
+		{
+			name:     "token missing from the network's deployment",
+			network:  "Base",
+			token:    "OrcaCoin",
+			flag:     OutputTokenFlag,
+			contains: []string{`missing OrcaCoinAddress for network "Base" (set BASE_ORCA_COIN_ADDRESS, add it to ` + dir, "deploy-forge-mock-erc20", ForceFlag},
+			excludes: []string{"has no deployment state"},
+		},
+		{
+			name:     "starknet points at the Cairo deploy step",
+			network:  "Starknet",
+			token:    "DogCoin",
+			flag:     OutputTokenFlag,
+			contains: []string{"run `solver tools deploy-sn-mock-erc20` to deploy it"},
+		},
+		{
+			name:     "ztarknet has no setup step",
+			network:  "Ztarknet",
+			token:    "DogCoin",
+			flag:     InputTokenFlag,
+			contains: []string{"has no deployment state for Ztarknet"},
+			excludes: []string{"solver tools"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := missingTokenError(dir, tt.network, tt.token, tt.flag)
+			for _, want := range tt.contains {
+				assert.ErrorContains(t, err, want)
+			}
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, err.Error(), unwanted)
+			}
+		})
+	}
+}
+
+func TestKnownTokens(t *testing.T) {
+	dir := t.TempDir()
+	deployment := `{"networkName":"Starknet","tokens":[` +
+		`{"name":"DogCoin","symbol":"DOG","address":"` + testStarknetDogCoin + `"},` +
+		`{"name":"OrcaCoin","symbol":"ORCA","address":"0x0123"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "starknet-mock-erc20-deployment.json"), []byte(deployment), 0o600))
+	t.Setenv("STARKNET_DOG_COIN_ADDRESS", strings.ToUpper(testStarknetDogCoin))
+	t.Setenv("STARKNET_ORCA_COIN_ADDRESS", "")
+
+	tokens := knownTokens(dir, "Starknet")
+	require.Len(t, tokens, 2, "the env and deployment DogCoin are the same token")
+	assert.Equal(t, KnownToken{Symbol: "DogCoin", Address: strings.ToUpper(testStarknetDogCoin), Source: "STARKNET_DOG_COIN_ADDRESS"}, tokens[0])
+	assert.Equal(t, "OrcaCoin", tokens[1].Symbol)
+	assert.Equal(t, "0x0123", tokens[1].Address)
+
+	t.Setenv("ZTARKNET_DOG_COIN_ADDRESS", "")
+	t.Setenv("ZTARKNET_ORCA_COIN_ADDRESS", "")
+	assert.Empty(t, knownTokens(dir, "Ztarknet"))
+}
+
+func TestScaleTokenAmount(t *testing.T) {
+	amount := CreateTokenAmount(1001, tokenDecimals)
+	assert.Equal(t, CreateTokenAmount(1001, 6), scaleTokenAmount(amount, 6))
+	assert.Equal(t, amount, scaleTokenAmount(amount, tokenDecimals))
+	assert.Equal(t, CreateTokenAmount(1001, 24), scaleTokenAmount(amount, 24))
+}
+
+func TestResolveOrderTokens(t *testing.T) {
+	t.Setenv("ETHEREUM_DOG_COIN_ADDRESS", testEthereumDogCoin)
+	t.Setenv("STARKNET_DOG_COIN_ADDRESS", testStarknetDogCoin)
+	saved := tokenDecimalsReader
+	tokenDecimalsReader = func(_ context.Context, networkName, _ string) (int, error) {
+		if networkName == "Ethereum" {
+			return 6, nil
+		}
+		return tokenDecimals, nil
+	}
+	t.Cleanup(func() { tokenDecimalsReader = saved })
+
+	order := &OrderConfig{
+		OriginChain:      "Ethereum",
+		DestinationChain: "Starknet",
+		InputToken:       DefaultOrderToken,
+		OutputToken:      DefaultOrderToken,
+		InputAmount:      CreateTokenAmount(1001, tokenDecimals),
+		OutputAmount:     CreateTokenAmount(1000, tokenDecimals),
+	}
+	result := newOrderResult(order.OriginChain, order.DestinationChain, order.InputAmount, order.OutputAmount)
+	require.NoError(t, order.resolveTokens(context.Background(), result))
+
+	assert.Equal(t, testEthereumDogCoin, order.tokens.Input.Address)
+	assert.Equal(t, testStarknetDogCoin, order.tokens.Output.Address)
+	assert.Equal(t, big.NewInt(1001_000_000), order.InputAmount)
+	assert.Equal(t, CreateTokenAmount(1000, tokenDecimals), order.OutputAmount)
+	assert.Equal(t, "1001000000", result.InputAmount)
+	assert.Equal(t, testStarknetDogCoin, result.OutputToken)
+}
+
+func TestMixedDecimalsOrder(t *testing.T) {
+	// DogCoin has 18 decimals on Ethereum but was deployed with 6 on Base; decimals() is read from the chain
+	chain := evmtest.NewChain(t)
+	erc20, err := abi.JSON(strings.NewReader(ethutil.ERC20ABI))
+	require.NoError(t, err)
+	deployToken := func(decimals uint8) common.Address {
+		answer, err := evmtest.Answer(erc20.Methods["decimals"], decimals)
+		require.NoError(t, err)
+		return chain.Deploy(t, evmtest.Stub(answer))
+	}
+	t.Setenv("ETHEREUM_DOG_COIN_ADDRESS", deployToken(18).Hex())
+	t.Setenv("BASE_DOG_COIN_ADDRESS", deployToken(6).Hex())
+	saved := tokenDecimalsReader
+	tokenDecimalsReader = func(ctx context.Context, _, address string) (int, error) {
+		decimals, err := ethutil.ERC20Decimals(ctx, chain.Client, common.HexToAddress(address))
+		return int(decimals), err
+	}
+	t.Cleanup(func() { tokenDecimalsReader = saved })
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	t.Cleanup(func() { SetJSONOutput(false) })
+
+	require.NoError(t, InitRandomness(98, true))
+	inputAmount, outputAmount := randomOrderAmounts()
+	order := &OrderConfig{
+		OriginChain:      "Ethereum",
+		DestinationChain: "Base",
+		InputToken:       DefaultOrderToken,
+		OutputToken:      DefaultOrderToken,
+		InputAmount:      inputAmount,
+		OutputAmount:     outputAmount,
+	}
+	result := newOrderResult(order.OriginChain, order.DestinationChain, order.InputAmount, order.OutputAmount)
+	require.NoError(t, order.resolveTokens(context.Background(), result))
+
+	// Each side is scaled to its own decimals: the output is 10^12 times fewer base units for the same tokens
+	assert.Equal(t, inputAmount, order.InputAmount)
+	assert.Equal(t, new(big.Int).Quo(outputAmount, pow10(12)), order.OutputAmount)
+	assert.Equal(t, order.OutputAmount.String(), result.OutputAmount)
+	// and the solver's margin survives, compared in tokens
+	assert.Negative(t, new(big.Int).Mul(order.OutputAmount, pow10(12)).Cmp(order.InputAmount))
+	require.NoError(t, checkSpread(order.InputAmount, 18, order.OutputAmount, 6, false))
+
+	printOrderSummary(order.OriginChain, order.DestinationChain, order.tokens, order.InputAmount, order.OutputAmount)
+	human := formatExactAmount(order.OutputAmount, 6)
+	assert.Equal(t, formatExactAmount(outputAmount, tokenDecimals), human)
+	assert.Contains(t, buf.String(), "Output Amount: "+human+" DogCoin ("+order.OutputAmount.String()+")")
+	assert.Contains(t, buf.String(), "Input Amount: "+formatExactAmount(inputAmount, tokenDecimals)+" DogCoin ("+inputAmount.String()+")")
+}
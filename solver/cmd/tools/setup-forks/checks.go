@@ -0,0 +1,305 @@
+package setupforks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	verifyrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/verify-routers"
+	deployforgemockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/deploy-forge-mock-erc20"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const starknetNetwork = "Starknet"
+
+// starknetProvider connects to the Starknet fork
+func starknetProvider() (*rpc.Provider, error) {
+	networkConfig, err := config.GetNetworkConfig(starknetNetwork)
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewProvider(networkConfig.RPCURL)
+}
+
+// classDeclared reports whether the class recorded in the declaration file is declared on the fork, which a
+// restarted fork may have lost
+func classDeclared(ctx context.Context, declarationFile string) (bool, error) {
+	declaration, err := deploystate.ReadDeclaration(deploystate.Path(declarationFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	classHash, err := utils.HexToFelt(declaration.ClassHash)
+	if err != nil {
+		return false, fmt.Errorf("invalid class hash in %s: %w", declarationFile, err)
+	}
+
+	provider, err := starknetProvider()
+	if err != nil {
+		return false, err
+	}
+	if _, err := provider.Class(ctx, rpc.WithBlockTag("latest"), classHash); err != nil {
+		var rpcErr *rpc.RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrClassHashNotFound.Code {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get class %s: %w", classHash, err)
+	}
+	fmt.Printf("📋 Class %s is declared\n", classHash)
+	return true, nil
+}
+
+// recordedHyperlane reads the recorded Starknet Hyperlane7683 deployment; nil when there is none
+func recordedHyperlane() (*deploystate.NetworkState, error) {
+	state, err := deploystate.ReadState(deploystate.Dir())
+	if err != nil {
+		return nil, err
+	}
+	network := state.Network(starknetNetwork)
+	if network == nil || network.HyperlaneAddress == "" {
+		return nil, nil
+	}
+	return network, nil
+}
+
+// hyperlaneDeployed reports whether the recorded Hyperlane7683 is live on the fork
+func hyperlaneDeployed(ctx context.Context) (bool, error) {
+	deployment, err := recordedHyperlane()
+	if deployment == nil || err != nil {
+		return false, err
+	}
+	address, err := utils.HexToFelt(deployment.HyperlaneAddress)
+	if err != nil {
+		return false, fmt.Errorf("invalid Hyperlane7683 address %s: %w", deployment.HyperlaneAddress, err)
+	}
+	var classHash *felt.Felt
+	if deploy := deployment.HyperlaneDeploy; deploy != nil && deploy.ClassHash != "" {
+		if classHash, err = utils.HexToFelt(deploy.ClassHash); err != nil {
+			return false, fmt.Errorf("invalid Hyperlane7683 class hash %s: %w", deploy.ClassHash, err)
+		}
+	}
+
+	provider, err := starknetProvider()
+	if err != nil {
+		return false, err
+	}
+	deployed, err := starknetutil.DeployedAt(ctx, provider, address, classHash, starknetutil.Hyperlane7683ProbeView)
+	if deployed {
+		fmt.Printf("📋 Hyperlane7683 is live at %s\n", address)
+	}
+	return deployed, err
+}
+
+// adoptHyperlane points this run at the recorded Hyperlane7683, which .env may not name yet
+func adoptHyperlane() error {
+	deployment, err := recordedHyperlane()
+	if err != nil {
+		return err
+	}
+	if deployment == nil {
+		return fmt.Errorf("no Hyperlane7683 address recorded in %s", deploystate.StatePath(deploystate.Dir()))
+	}
+	if err := os.Setenv("STARKNET_HYPERLANE_ADDRESS", deployment.HyperlaneAddress); err != nil {
+		return err
+	}
+	config.ReloadNetworks()
+	return nil
+}
+
+// recordedTokens returns the address recorded for every token of the spec on networkName; ok is false when one
+// is missing
+func recordedTokens(networkName string) (addresses map[string]string, ok bool, err error) {
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		return nil, false, err
+	}
+	state, err := deploystate.ReadState(deploystate.Dir())
+	if err != nil {
+		return nil, false, err
+	}
+
+	network := state.Network(networkName)
+	addresses = make(map[string]string, len(specs))
+	for _, spec := range specs {
+		token, ok := network.Token(spec.Name)
+		if !ok || token.Address == "" {
+			return addresses, false, nil
+		}
+		addresses[spec.Name] = token.Address
+	}
+	return addresses, true, nil
+}
+
+// starknetTokensDeployed reports whether every token recorded on Starknet is live on the fork
+func starknetTokensDeployed(ctx context.Context) (bool, error) {
+	addresses, ok, err := recordedTokens(starknetNetwork)
+	if !ok || err != nil {
+		return false, err
+	}
+	provider, err := starknetProvider()
+	if err != nil {
+		return false, err
+	}
+	for name, hex := range addresses {
+		address, err := utils.HexToFelt(hex)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s address %s: %w", name, hex, err)
+		}
+		deployed, err := starknetutil.DeployedAt(ctx, provider, address, nil, starknetutil.ERC20ProbeView)
+		if !deployed || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// evmNetworkNames returns the config names of the networks the EVM tokens are deployed to
+func evmNetworkNames() []string {
+	names := make([]string, 0, len(deployforgemockerc20.Networks))
+	for _, network := range deployforgemockerc20.Networks {
+		names = append(names, network.Network)
+	}
+	return names
+}
+
+// evmTokensDeployed reports whether every token recorded on each EVM fork has code there
+func evmTokensDeployed(ctx context.Context) (bool, error) {
+	for _, networkName := range evmNetworkNames() {
+		addresses, ok, err := recordedTokens(networkName)
+		if !ok || err != nil {
+			return false, err
+		}
+		live, err := withEVMClient(ctx, networkName, func(client *ethclient.Client) (bool, error) {
+			for name, address := range addresses {
+				code, err := client.CodeAt(ctx, common.HexToAddress(address), nil)
+				if err != nil {
+					return false, fmt.Errorf("failed to get the %s code at %s: %w", name, address, err)
+				}
+				if len(code) == 0 {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		if !live || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// evmUsersFunded reports whether every user holds some of every token on each EVM fork. Funding mints on every
+// run, so a rerun is skipped as soon as the users have tokens to spend
+func evmUsersFunded(ctx context.Context) (bool, error) {
+	recipients := envutil.GetEVMRecipients()
+	for _, networkName := range evmNetworkNames() {
+		addresses, ok, err := recordedTokens(networkName)
+		if !ok || err != nil {
+			return false, err
+		}
+		funded, err := withEVMClient(ctx, networkName, func(client *ethclient.Client) (bool, error) {
+			for name, address := range addresses {
+				for _, recipient := range recipients {
+					balance, err := ethutil.ERC20BalanceAt(ctx, client, common.HexToAddress(address), recipient.Address, nil)
+					if err != nil {
+						return false, fmt.Errorf("failed to read %s's %s balance: %w", recipient.Name, name, err)
+					}
+					if balance.Sign() == 0 {
+						return false, nil
+					}
+				}
+			}
+			return true, nil
+		})
+		if !funded || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// evmAllowancesSet reports whether Alice gave Hyperlane7683 an unlimited allowance on every recorded EVM token
+func evmAllowancesSet(ctx context.Context) (bool, error) {
+	alice := common.HexToAddress(envutil.GetAlicePublicKey())
+	for _, networkName := range evmNetworkNames() {
+		addresses, ok, err := recordedTokens(networkName)
+		if !ok || err != nil {
+			return false, err
+		}
+		networkConfig, err := config.GetNetworkConfig(networkName)
+		if err != nil {
+			return false, err
+		}
+		hyperlane := common.HexToAddress(networkConfig.HyperlaneAddress)
+		set, err := withEVMClient(ctx, networkName, func(client *ethclient.Client) (bool, error) {
+			for name, address := range addresses {
+				allowance, err := ethutil.ERC20AllowanceAt(ctx, client, common.HexToAddress(address), alice, hyperlane, nil)
+				if err != nil {
+					return false, fmt.Errorf("failed to read Alice's %s allowance: %w", name, err)
+				}
+				if allowance.Cmp(abi.MaxUint256) != 0 {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		if !set || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// withEVMClient calls fn with a client of the network's fork
+func withEVMClient(ctx context.Context, networkName string, fn func(*ethclient.Client) (bool, error)) (bool, error) {
+	networkConfig, err := config.GetNetworkConfig(networkName)
+	if err != nil {
+		return false, err
+	}
+	client, err := ethclient.DialContext(ctx, networkConfig.RPCURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to %s: %w", networkName, err)
+	}
+	defer client.Close()
+	return fn(client)
+}
+
+// adoptTokens points this run at the tokens recorded on networks, which .env may not name yet
+func adoptTokens(networks []string) error {
+	for _, networkName := range networks {
+		addresses, ok, err := recordedTokens(networkName)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s tokens missing from %s", networkName, deploystate.StatePath(deploystate.Dir()))
+		}
+		for name, address := range addresses {
+			if err := os.Setenv(tokenspec.EnvName(networkName, name), address); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// routersRegistered reports whether every route on the forks already matches config
+func routersRegistered(ctx context.Context) (bool, error) {
+	return verifyrouters.Check(ctx, nil, io.Discard) == nil, nil
+}
@@ -0,0 +1,331 @@
+package setupforks
+
+// Setup forks tool: bootstraps the local forks in-process. deploy declares and deploys the Starknet contracts,
+// deploys the EVM tokens, funds the users, sets Alice's allowances and registers the routers in both
+// directions, skipping every step whose output is already in the deployment state and live on the forks.
+// declare only declares the Starknet classes, and verify runs the doctor and verify-routers checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	declaresnhyperlane7683 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/declare-sn-hyperlane7683"
+	declaresnmockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/declare-sn-mock-erc20"
+	deploysnhyperlane7683 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/deploy-sn-hyperlane7683"
+	deploysnmockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/deploy-sn-mock-erc20"
+	registerevmrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/register-evm-routers"
+	registersnrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/register-sn-routers"
+	setupstarknetcontracts "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/setup-starknet-contracts"
+	verifyrouters "github.com/NethermindEth/oif-starknet/solver/cmd/tools/additional-helpers/verify-routers"
+	deployforgemockerc20 "github.com/NethermindEth/oif-starknet/solver/cmd/tools/deploy-forge-mock-erc20"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/tools/doctor"
+	fundaccounts "github.com/NethermindEth/oif-starknet/solver/cmd/tools/fund-accounts"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	// forceFlag reruns every step, even those whose output is already in place
+	forceFlag = "--force"
+	// defaultEVMSalt is the CREATE2 salt of the EVM tokens when EVM_DEPLOY_SALT is not set, so they keep their
+	// address across fork restarts
+	defaultEVMSalt = "0x6f6966"
+)
+
+// Usage lists the setup-forks commands
+const Usage = `Usage: solver tools setup-forks <command> [options]
+Commands:
+  deploy [--write-env] [--force]  Declare, deploy, fund, approve and register routers on the forks
+  declare                         Declare the Hyperlane7683 and MockERC20 classes on the Starknet fork
+  verify                          Run the doctor and verify-routers checks`
+
+// options is the parsed deploy command line
+type options struct {
+	WriteEnv bool
+	Force    bool
+}
+
+// Run runs `setup-forks <deploy|declare|verify> [options]`
+func Run(ctx context.Context, args []string) error {
+	if len(args) < 1 {
+		fmt.Println(Usage)
+		return errors.New("missing command")
+	}
+	if err := solverdir.LoadEnv(); err != nil {
+		fmt.Printf("⚠️  %v, using environment variables\n", err)
+	}
+	config.InitializeNetworks()
+
+	switch strings.ToLower(args[0]) {
+	case "deploy":
+		opts, err := parseDeployArgs(args[1:])
+		if err != nil {
+			return err
+		}
+		return runSteps(ctx, os.Stdout, deploySteps(opts), opts.Force)
+	case "declare":
+		if len(args) > 1 {
+			return fmt.Errorf("unexpected argument: %s", args[1])
+		}
+		return runSteps(ctx, os.Stdout, declareSteps(), false)
+	case "verify":
+		if len(args) > 1 {
+			return fmt.Errorf("unexpected argument: %s", args[1])
+		}
+		return verify(ctx)
+	default:
+		fmt.Println(Usage)
+		return fmt.Errorf("unknown setup command: %s", args[0])
+	}
+}
+
+// parseDeployArgs parses `[--write-env] [--force]`
+func parseDeployArgs(args []string) (options, error) {
+	var opts options
+	for _, arg := range args {
+		switch arg {
+		case envutil.WriteEnvFlag:
+			opts.WriteEnv = true
+		case forceFlag:
+			opts.Force = true
+		default:
+			return options{}, fmt.Errorf("unexpected argument: %s (usage: setup-forks deploy [%s] [%s])", arg, envutil.WriteEnvFlag, forceFlag)
+		}
+	}
+	return opts, nil
+}
+
+// step is one stage of the bootstrap
+type step struct {
+	Name string
+	// Done reports whether the step's output is already in place; nil when the step is idempotent itself
+	Done func(ctx context.Context) (bool, error)
+	Run  func(ctx context.Context) error
+	// After runs once the step is done or skipped, to make its output visible to the next steps
+	After func() error
+}
+
+// stepStatus is how a step ended
+type stepStatus string
+
+const (
+	stepRan     stepStatus = "done"
+	stepSkipped stepStatus = "skipped"
+	stepFailed  stepStatus = "failed"
+	stepNotRun  stepStatus = "not run"
+)
+
+// stepResult is the outcome of one step, for the summary
+type stepResult struct {
+	Name    string
+	Status  stepStatus
+	Elapsed time.Duration
+	Err     error
+}
+
+// runSteps runs steps in order until one fails, skipping those already done unless force is set, and prints a
+// summary of every step
+func runSteps(ctx context.Context, w io.Writer, steps []step, force bool) error {
+	results := make([]stepResult, len(steps))
+	for i, s := range steps {
+		results[i] = stepResult{Name: s.Name, Status: stepNotRun}
+	}
+	defer printSummary(w, results)
+
+	for i, s := range steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n━━━ [%d/%d] %s ━━━\n", i+1, len(steps), s.Name)
+		start := time.Now()
+		result, err := runStep(ctx, w, s, force)
+		result.Elapsed = time.Since(start)
+		results[i] = result
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// runStep runs one step, or skips it when its output is already in place
+func runStep(ctx context.Context, w io.Writer, s step, force bool) (stepResult, error) {
+	result := stepResult{Name: s.Name, Status: stepRan}
+	if s.Done != nil && !force {
+		done, err := s.Done(ctx)
+		if err != nil {
+			// A failed check is not fatal: running the step again is always safe
+			fmt.Fprintf(w, "⚠️  Could not check whether this is already done: %v\n", err)
+		}
+		if done {
+			fmt.Fprintln(w, "⏭️  Already done, skipping")
+			result.Status = stepSkipped
+		}
+	}
+	if result.Status == stepRan {
+		if err := s.Run(ctx); err != nil {
+			result.Status, result.Err = stepFailed, err
+			return result, err
+		}
+	}
+	if s.After != nil {
+		if err := s.After(); err != nil {
+			result.Status, result.Err = stepFailed, err
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// printSummary prints one line per step
+func printSummary(w io.Writer, results []stepResult) {
+	counts := make(map[stepStatus]int)
+	fmt.Fprintln(w, "\n📋 Fork setup summary:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range results {
+		counts[r.Status]++
+		detail := ""
+		switch r.Status {
+		case stepRan:
+			detail = r.Elapsed.Round(time.Millisecond).String()
+		case stepFailed:
+			detail = r.Err.Error()
+		}
+		fmt.Fprintf(tw, "   %s %s\t%s\t%s\n", statusIcon(r.Status), r.Name, r.Status, detail)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n🔧 %d done, %d skipped, %d failed, %d not run\n",
+		counts[stepRan], counts[stepSkipped], counts[stepFailed], counts[stepNotRun])
+}
+
+func statusIcon(status stepStatus) string {
+	switch status {
+	case stepRan:
+		return "✅"
+	case stepSkipped:
+		return "⏭️ "
+	case stepFailed:
+		return "❌"
+	default:
+		return "⏸️ "
+	}
+}
+
+// declareSteps declares the Starknet classes
+func declareSteps() []step {
+	return []step{
+		{
+			Name: "declare Hyperlane7683 class",
+			Done: func(ctx context.Context) (bool, error) {
+				return classDeclared(ctx, declaresnhyperlane7683.DeclarationFile)
+			},
+			Run: func(ctx context.Context) error { return declaresnhyperlane7683.Run(ctx, nil) },
+		},
+		{
+			Name: "declare MockERC20 class",
+			Done: func(ctx context.Context) (bool, error) { return classDeclared(ctx, declaresnmockerc20.DeclarationFile) },
+			Run:  func(ctx context.Context) error { return declaresnmockerc20.Run(ctx, nil) },
+		},
+	}
+}
+
+// deploySteps is the whole bootstrap, in dependency order
+func deploySteps(opts options) []step {
+	var writeEnv []string
+	if opts.WriteEnv {
+		writeEnv = []string{envutil.WriteEnvFlag}
+	}
+
+	steps := declareSteps()
+	steps = append(steps,
+		step{
+			Name:  "deploy Hyperlane7683 on Starknet",
+			Done:  hyperlaneDeployed,
+			Run:   func(ctx context.Context) error { return deploysnhyperlane7683.Run(ctx, writeEnv) },
+			After: adoptHyperlane,
+		},
+		step{
+			Name:  "deploy Starknet tokens",
+			Done:  starknetTokensDeployed,
+			Run:   func(ctx context.Context) error { return deploysnmockerc20.Run(ctx, writeEnv) },
+			After: func() error { return adoptTokens([]string{starknetNetwork}) },
+		},
+		step{
+			Name: "deploy EVM tokens",
+			Done: evmTokensDeployed,
+			Run: func(ctx context.Context) error {
+				args := append([]string{}, writeEnv...)
+				if os.Getenv("EVM_DEPLOY_SALT") == "" {
+					args = append(args, "--salt", defaultEVMSalt)
+				}
+				return deployforgemockerc20.Run(ctx, args)
+			},
+			After: func() error { return adoptTokens(evmNetworkNames()) },
+		},
+		step{
+			Name: "fund EVM users",
+			Done: evmUsersFunded,
+			Run: func(ctx context.Context) error {
+				var failed []string
+				for _, network := range fundaccounts.EVMNetworks {
+					if err := fundaccounts.Run(ctx, []string{network}); err != nil {
+						failed = append(failed, network)
+					}
+				}
+				if len(failed) > 0 {
+					return fmt.Errorf("funding failed on %s", strings.Join(failed, ", "))
+				}
+				return nil
+			},
+		},
+		step{
+			// Alice signs EIP-2612 permits and the deployer submits them; tokens without permit() are approved
+			Name: "set EVM allowances",
+			Done: evmAllowancesSet,
+			Run: func(ctx context.Context) error {
+				var failed []string
+				for _, network := range fundaccounts.EVMNetworks {
+					if err := fundaccounts.SetEVMAllowances(ctx, network); err != nil {
+						failed = append(failed, network)
+					}
+				}
+				if len(failed) > 0 {
+					return fmt.Errorf("setting allowances failed on %s", strings.Join(failed, ", "))
+				}
+				return nil
+			},
+		},
+		step{
+			// Funding and approving on Starknet skips whatever is already in place
+			Name: "fund Starknet users and set allowances",
+			Run:  func(ctx context.Context) error { return setupstarknetcontracts.Run(ctx, nil) },
+		},
+		step{
+			Name: "register routers and gas configs",
+			Done: routersRegistered,
+			Run: func(ctx context.Context) error {
+				if err := registerevmrouters.Run(ctx, []string{"--fork"}); err != nil {
+					return err
+				}
+				return registersnrouters.Run(ctx, nil)
+			},
+		},
+	)
+	return steps
+}
+
+// verify runs the doctor, then the route check even when the doctor failed
+func verify(ctx context.Context) error {
+	fmt.Println("🩺 Running the doctor...")
+	doctorErr := doctor.Run(ctx, nil)
+	fmt.Println("\n🔍 Verifying the routers...")
+	routesErr := verifyrouters.Run(ctx, nil)
+	return errors.Join(doctorErr, routesErr)
+}
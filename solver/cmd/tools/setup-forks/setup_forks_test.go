@@ -0,0 +1,154 @@
+package setupforks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// fakeStep records whether it ran and reports done as its Done
+func fakeStep(name string, done bool, err error, ran *[]string) step {
+	return step{
+		Name: name,
+		Done: func(context.Context) (bool, error) { return done, nil },
+		Run: func(context.Context) error {
+			*ran = append(*ran, name)
+			return err
+		},
+	}
+}
+
+func TestRunSteps(t *testing.T) {
+	t.Run("skips the steps already done", func(t *testing.T) {
+		var ran, after []string
+		steps := []step{
+			fakeStep("declare", true, nil, &ran),
+			fakeStep("deploy", false, nil, &ran),
+			{Name: "setup", Run: func(context.Context) error { ran = append(ran, "setup"); return nil }},
+		}
+		steps[0].After = func() error { after = append(after, "declare"); return nil }
+
+		var out bytes.Buffer
+		require.NoError(t, runSteps(context.Background(), &out, steps, false))
+		assert.Equal(t, []string{"deploy", "setup"}, ran, "a step without a check always runs")
+		assert.Equal(t, []string{"declare"}, after, "After runs for skipped steps too")
+		assert.Contains(t, out.String(), "[1/3] declare")
+		assert.Contains(t, out.String(), "2 done, 1 skipped, 0 failed, 0 not run")
+	})
+
+	t.Run("--force reruns everything", func(t *testing.T) {
+		var ran []string
+		steps := []step{fakeStep("declare", true, nil, &ran), fakeStep("deploy", true, nil, &ran)}
+		require.NoError(t, runSteps(context.Background(), &bytes.Buffer{}, steps, true))
+		assert.Equal(t, []string{"declare", "deploy"}, ran)
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		var ran []string
+		steps := []step{
+			fakeStep("declare", false, nil, &ran),
+			fakeStep("deploy", false, errors.New("no class hash"), &ran),
+			fakeStep("fund", false, nil, &ran),
+		}
+		var out bytes.Buffer
+		err := runSteps(context.Background(), &out, steps, false)
+		assert.EqualError(t, err, "deploy: no class hash")
+		assert.Equal(t, []string{"declare", "deploy"}, ran)
+		assert.Contains(t, out.String(), "no class hash")
+		assert.Contains(t, out.String(), "1 done, 0 skipped, 1 failed, 1 not run")
+	})
+
+	t.Run("a failed check runs the step", func(t *testing.T) {
+		var ran []string
+		s := fakeStep("deploy", false, nil, &ran)
+		s.Done = func(context.Context) (bool, error) { return false, errors.New("connection refused") }
+		var out bytes.Buffer
+		require.NoError(t, runSteps(context.Background(), &out, []step{s}, false))
+		assert.Equal(t, []string{"deploy"}, ran)
+		assert.Contains(t, out.String(), "Could not check whether this is already done: connection refused")
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var ran []string
+		err := runSteps(ctx, &bytes.Buffer{}, []step{fakeStep("declare", false, nil, &ran)}, false)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, ran)
+	})
+}
+
+func TestParseDeployArgs(t *testing.T) {
+	opts, err := parseDeployArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, options{}, opts)
+
+	opts, err = parseDeployArgs([]string{"--write-env", "--force"})
+	require.NoError(t, err)
+	assert.Equal(t, options{WriteEnv: true, Force: true}, opts)
+
+	_, err = parseDeployArgs([]string{"--salt"})
+	assert.ErrorContains(t, err, "unexpected argument: --salt")
+}
+
+func TestRecordedTokens(t *testing.T) {
+	t.Setenv(solverdir.StateDirEnv, t.TempDir())
+	specs := filepath.Join(t.TempDir(), "tokens.json")
+	require.NoError(t, os.WriteFile(specs, []byte(`[
+		{"name": "DogCoin", "symbol": "DOG", "decimals": 18},
+		{"name": "USDC", "symbol": "USDC", "decimals": 6}
+	]`), 0o600))
+	t.Setenv("DEPLOY_TOKENS_FILE", specs)
+
+	_, ok, err := recordedTokens("Base")
+	require.NoError(t, err)
+	assert.False(t, ok, "nothing recorded yet")
+
+	_, err = tokenspec.RecordDeployment("Base", []tokenspec.DeployedToken{{Name: "DogCoin", Address: "0x01"}})
+	require.NoError(t, err)
+	_, ok, err = recordedTokens("Base")
+	require.NoError(t, err)
+	assert.False(t, ok, "USDC is missing")
+
+	_, err = tokenspec.RecordDeployment("Base", []tokenspec.DeployedToken{{Name: "USDC", Address: "0x02"}})
+	require.NoError(t, err)
+	addresses, ok, err := recordedTokens("Base")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"DogCoin": "0x01", "USDC": "0x02"}, addresses)
+
+	t.Setenv("BASE_DOG_COIN_ADDRESS", "")
+	t.Setenv("BASE_USDC_ADDRESS", "")
+	require.NoError(t, adoptTokens([]string{"Base"}))
+	assert.Equal(t, "0x01", os.Getenv("BASE_DOG_COIN_ADDRESS"))
+	assert.Equal(t, "0x02", os.Getenv("BASE_USDC_ADDRESS"))
+}
+
+func TestAdoptHyperlane(t *testing.T) {
+	// Registered first so it runs once the environment is restored
+	t.Cleanup(func() { config.ReloadNetworks() })
+	t.Setenv(solverdir.StateDirEnv, t.TempDir())
+	t.Setenv("STARKNET_HYPERLANE_ADDRESS", "")
+	config.InitializeNetworks()
+
+	assert.ErrorContains(t, adoptHyperlane(), "no Hyperlane7683 address recorded")
+
+	const address = "0x2d6b0b3a2fb5f0e0c3b1d7bc8a3f0e2ff0ed3c5e7b3a9f19c4a7e3d1b5c6a71"
+	require.NoError(t, deploystate.UpdateState(deploystate.Dir(), func(state *deploystate.State) error {
+		state.EnsureNetwork("Starknet").SetHyperlane(address, nil)
+		return nil
+	}))
+	require.NoError(t, adoptHyperlane())
+	assert.Equal(t, address, config.Networks["Starknet"].HyperlaneAddress, "the networks already built are rebuilt")
+}
@@ -0,0 +1,176 @@
+package typeddatahash
+
+// Typed-data hash tool: hashes SNIP-12 typed-data JSON (see pkg/snip12) for an account and prints the type, struct
+// and message hashes in hex and decimal as JSON, for comparison with the Cairo implementation. With --expect it
+// checks them against a file of expected hashes and fails on any difference, so the Permit2 typed-data
+// definitions in typedData/ can be checked as a regression test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/snip12"
+)
+
+const (
+	// AccountFlag is the account the message hash is computed for
+	AccountFlag = "--account"
+	// ExpectFlag checks the hashes against an expectations file
+	ExpectFlag = "--expect"
+	// stdinName is the file argument that reads typed data from stdin
+	stdinName = "-"
+)
+
+const usage = "usage: typed-data-hash [--account <address>] [--expect <expected.json>] [<typed-data.json>... | -]"
+
+// ErrMismatch is returned when a computed hash differs from the expected one
+var ErrMismatch = errors.New("computed hashes do not match the expected ones")
+
+// Expectations are the hashes the typed-data files should have for Account, keyed by file name
+type Expectations struct {
+	Account string                     `json:"account"`
+	Hashes  map[string]snip12.Expected `json:"hashes"`
+}
+
+// Result is the hashes of one typed-data file
+type Result struct {
+	File string `json:"file"`
+	snip12.Hashes
+	Mismatches []snip12.Mismatch `json:"mismatches,omitempty"`
+}
+
+// options are the parsed arguments
+type options struct {
+	account string
+	expect  string
+	files   []string
+}
+
+// RunTypedDataHash runs `tools typed-data-hash` and exits non-zero on failure or mismatch
+func RunTypedDataHash(args []string) {
+	if err := run(os.Stdin, os.Stdout, args); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(stdin io.Reader, w io.Writer, args []string) error {
+	opts, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	var expectations *Expectations
+	if opts.expect != "" {
+		if expectations, err = loadExpectations(opts.expect); err != nil {
+			return err
+		}
+		switch {
+		case opts.account == "":
+			opts.account = expectations.Account
+		case expectations.Account != "" && !strings.EqualFold(opts.account, expectations.Account):
+			return fmt.Errorf("%s %s differs from the account %s the expected hashes were computed for", AccountFlag, opts.account, expectations.Account)
+		}
+	}
+	if opts.account == "" {
+		return fmt.Errorf("%s is required (%s)", AccountFlag, usage)
+	}
+
+	results := make([]Result, 0, len(opts.files))
+	mismatched := false
+	for _, file := range opts.files {
+		result, err := hashFile(stdin, file, opts.account)
+		if err != nil {
+			return err
+		}
+		if expectations != nil {
+			want, ok := expectations.Hashes[filepath.Base(file)]
+			if !ok {
+				return fmt.Errorf("%s has no expected hashes for %s", opts.expect, filepath.Base(file))
+			}
+			result.Mismatches = snip12.Check(result.Hashes, want)
+			mismatched = mismatched || len(result.Mismatches) > 0
+		}
+		results = append(results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(results); err != nil {
+		return err
+	}
+	if mismatched {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// parseArgs parses the flags; without files the typed data is read from stdin
+func parseArgs(args []string) (options, error) {
+	var opts options
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		flag, value, inline := strings.Cut(arg, "=")
+		var target *string
+		switch flag {
+		case AccountFlag:
+			target = &opts.account
+		case ExpectFlag:
+			target = &opts.expect
+		default:
+			if strings.HasPrefix(arg, "--") {
+				return options{}, fmt.Errorf("unexpected argument: %s (%s)", arg, usage)
+			}
+			opts.files = append(opts.files, arg)
+			continue
+		}
+		if !inline {
+			if i+1 >= len(args) {
+				return options{}, fmt.Errorf("%s requires a value", flag)
+			}
+			i++
+			value = args[i]
+		}
+		*target = value
+	}
+	if len(opts.files) == 0 {
+		opts.files = []string{stdinName}
+	}
+	return opts, nil
+}
+
+// hashFile hashes the typed data in file, or in stdin for "-"
+func hashFile(stdin io.Reader, file, account string) (Result, error) {
+	var data []byte
+	var err error
+	if file == stdinName {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	hashes, err := snip12.HashJSON(data, account)
+	if err != nil {
+		return Result{}, fmt.Errorf("%s: %w", file, err)
+	}
+	return Result{File: file, Hashes: hashes}, nil
+}
+
+func loadExpectations(path string) (*Expectations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected hashes: %w", err)
+	}
+	var expectations Expectations
+	if err := json.Unmarshal(data, &expectations); err != nil {
+		return nil, fmt.Errorf("invalid expected hashes in %s: %w", path, err)
+	}
+	return &expectations, nil
+}
@@ -0,0 +1,121 @@
+package typeddatahash
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/snip12"
+)
+
+const typedDataDir = "../../../../typedData"
+
+// TestExpectedHashes is the regression test of the Permit2 typed data against expected_hashes.json
+func TestExpectedHashes(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(typedDataDir, "Permit*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	var out bytes.Buffer
+	args := append([]string{ExpectFlag, filepath.Join(typedDataDir, "expected_hashes.json")}, files...)
+	require.NoError(t, run(strings.NewReader(""), &out, args))
+
+	var results []Result
+	require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+	require.Len(t, results, len(files))
+	for _, result := range results {
+		assert.Empty(t, result.Mismatches, result.File)
+		assert.NotEmpty(t, result.MessageHash.Decimal, result.File)
+	}
+}
+
+func TestRun(t *testing.T) {
+	single, err := os.ReadFile(filepath.Join(typedDataDir, "PermitSingle.json"))
+	require.NoError(t, err)
+	account := "0x064b48806902a367c8598f4f95c305e8c1a1acba5f082d294a43793113115691"
+
+	writeExpect := func(t *testing.T, expectations Expectations) string {
+		data, err := json.Marshal(expectations)
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "expected.json")
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		return path
+	}
+
+	t.Run("stdin", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, run(bytes.NewReader(single), &out, []string{AccountFlag + "=" + account}))
+		var results []Result
+		require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+		require.Len(t, results, 1)
+		assert.Equal(t, "-", results[0].File)
+		assert.Equal(t, "Permit Single", results[0].PrimaryType)
+		assert.Equal(t, "0x717f305560f6d52bc17b2d96b714b09e5cbdc878ebcff2940caeb2d312d87eb", results[0].MessageHash.Hex)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		expect := writeExpect(t, Expectations{Account: account, Hashes: map[string]snip12.Expected{
+			"-": {TypeHash: "0x3ba9155c2accbec95e96bd8b3a44001b999bcab5f60ac9190d52971e5e326d5", MessageHash: "0x1"},
+		}})
+		var out bytes.Buffer
+		err := run(bytes.NewReader(single), &out, []string{ExpectFlag, expect})
+		assert.ErrorIs(t, err, ErrMismatch)
+		var results []Result
+		require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+		require.Len(t, results[0].Mismatches, 1)
+		assert.Equal(t, "messageHash", results[0].Mismatches[0].Field)
+	})
+
+	tests := []struct {
+		name    string
+		args    func(t *testing.T) []string
+		wantErr string
+	}{
+		{
+			name:    "missing account",
+			args:    func(t *testing.T) []string { return nil },
+			wantErr: "--account is required",
+		},
+		{
+			name:    "flag without value",
+			args:    func(t *testing.T) []string { return []string{AccountFlag} },
+			wantErr: "--account requires a value",
+		},
+		{
+			name:    "unknown flag",
+			args:    func(t *testing.T) []string { return []string{"--verbose"} },
+			wantErr: "unexpected argument: --verbose",
+		},
+		{
+			name: "account differs from expectations",
+			args: func(t *testing.T) []string {
+				return []string{AccountFlag, "0x1", ExpectFlag, writeExpect(t, Expectations{Account: account})}
+			},
+			wantErr: "--account 0x1 differs",
+		},
+		{
+			name: "no expectation for the file",
+			args: func(t *testing.T) []string {
+				return []string{ExpectFlag, writeExpect(t, Expectations{Account: account})}
+			},
+			wantErr: "has no expected hashes for -",
+		},
+		{
+			name:    "missing file",
+			args:    func(t *testing.T) []string { return []string{AccountFlag, account, "missing.json"} },
+			wantErr: "failed to read missing.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(bytes.NewReader(single), &bytes.Buffer{}, tt.args(t))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+//go:build e2e
+
+package e2e
+
+// End-to-end test of the order lifecycle on the local forks: Alice opens an order in each direction between an
+// EVM network and Starknet, the Solver fills it and settles it, and every balance the order moves is checked to
+// the wei. It drives the open-order, fill-order and settle-orders tools as libraries, so no solver process may be
+// running against the same forks (it would fill the orders first).
+//
+// The forks must be running (make start-networks) with the contracts deployed and Alice and the Solver funded and
+// approved (make fund-accounts-local register-starknet-on-evm-local, or the setup tools), as for the integration
+// tests. The forks have no Hyperlane relayer, so by default the test stops at the settle transaction and checks the
+// input is still escrowed on the origin; set E2E_SETTLE_TIMEOUT (e.g. 2m) when a relayer is running to also wait
+// for the origin to release the input to the Solver.
+//
+//	make test-e2e-local
+//
+// Order IDs, transaction hashes and balances are logged when a test fails, and written to E2E_ARTIFACTS_DIR if set.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+
+	fillorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/fill-order"
+	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	e2eStepTimeout    = 2 * time.Minute
+	e2eDefaultNetwork = "Ethereum"
+)
+
+// e2eAmounts are the fixed order amounts in 18-decimal units; the spread is the Solver's margin
+var (
+	e2eInputAmount  = openorder.CreateTokenAmount(101, 18)
+	e2eOutputAmount = openorder.CreateTokenAmount(100, 18)
+)
+
+// e2eArtifacts is what a failed run leaves behind to debug it
+type e2eArtifacts struct {
+	Direction    string            `json:"direction"`
+	OrderID      string            `json:"orderId,omitempty"`
+	OpenTxHash   string            `json:"openTxHash,omitempty"`
+	FillTxHash   string            `json:"fillTxHash,omitempty"`
+	SettleTxHash string            `json:"settleTxHash,omitempty"`
+	OriginStatus string            `json:"originStatus,omitempty"`
+	Balances     map[string]string `json:"balances,omitempty"`
+}
+
+// e2eHolder is one account whose token balance an order moves
+type e2eHolder struct {
+	Label   string
+	Network string
+	Token   string
+	Owner   string
+}
+
+func TestE2EOrderLifecycle(t *testing.T) {
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err, "failed to load config")
+	evmNetwork := envutil.GetEnvWithDefault("E2E_EVM_NETWORK", e2eDefaultNetwork)
+	for _, name := range []string{evmNetwork, openorder.StarknetNetworkName} {
+		require.NoError(t, e2eCheckReachable(cfg, name), "start the forks with `make start-networks` and run the setup first")
+	}
+
+	var settleTimeout time.Duration
+	if value := os.Getenv("E2E_SETTLE_TIMEOUT"); value != "" {
+		settleTimeout, err = time.ParseDuration(value)
+		require.NoError(t, err, "invalid E2E_SETTLE_TIMEOUT")
+	}
+	openorder.SetAutoApprove(true)
+	defer openorder.CloseClients()
+
+	t.Run("EVMToStarknet", func(t *testing.T) {
+		e2eRunOrder(t, evmNetwork, openorder.StarknetNetworkName, settleTimeout)
+	})
+	t.Run("StarknetToEVM", func(t *testing.T) {
+		e2eRunOrder(t, openorder.StarknetNetworkName, evmNetwork, settleTimeout)
+	})
+}
+
+// e2eRunOrder opens, fills and settles one order from origin to destination and checks the balances it moves
+func e2eRunOrder(t *testing.T, origin, destination string, settleTimeout time.Duration) {
+	artifacts := &e2eArtifacts{Direction: origin + " → " + destination}
+	t.Cleanup(func() {
+		if t.Failed() {
+			e2eReportArtifacts(t, artifacts)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*e2eStepTimeout+settleTimeout)
+	defer cancel()
+
+	// The holders are snapshotted before the order resolves its tokens; the order is checked to use the same ones
+	inputToken, err := e2eTokenAddress(origin)
+	require.NoError(t, err)
+	outputToken, err := e2eTokenAddress(destination)
+	require.NoError(t, err)
+	holders := []e2eHolder{
+		{Label: "alice@origin", Network: origin, Token: inputToken, Owner: e2eAlice(origin)},
+		{Label: "escrow@origin", Network: origin, Token: inputToken, Owner: config.Networks[origin].HyperlaneAddress},
+		{Label: "solver@origin", Network: origin, Token: inputToken, Owner: e2eSolver(origin)},
+		{Label: "alice@destination", Network: destination, Token: outputToken, Owner: e2eAlice(destination)},
+		{Label: "solver@destination", Network: destination, Token: outputToken, Owner: e2eSolver(destination)},
+	}
+	before := e2eSnapshot(ctx, t, holders)
+
+	opened, err := openorder.OpenOrder(ctx, origin, destination, e2eInputAmount, e2eOutputAmount)
+	if opened != nil {
+		artifacts.OrderID, artifacts.OpenTxHash = opened.OrderID, opened.TxHash
+	}
+	require.NoError(t, err, "open")
+	require.True(t, strings.EqualFold(opened.InputToken, inputToken), "the order used input token %s, expected %s", opened.InputToken, inputToken)
+	require.True(t, strings.EqualFold(opened.OutputToken, outputToken), "the order used output token %s, expected %s", opened.OutputToken, outputToken)
+	inputAmount, ok := new(big.Int).SetString(opened.InputAmount, 10)
+	require.True(t, ok, "invalid input amount %q", opened.InputAmount)
+	outputAmount, ok := new(big.Int).SetString(opened.OutputAmount, 10)
+	require.True(t, ok, "invalid output amount %q", opened.OutputAmount)
+	orderID := common.HexToHash(opened.OrderID)
+
+	artifacts.FillTxHash, err = fillorder.FillOrder(ctx, origin, orderID)
+	require.NoError(t, err, "fill")
+
+	results, err := fillorder.SettleOrders(ctx, origin, []common.Hash{orderID}, settleTimeout)
+	require.NoError(t, err, "settle")
+	require.Len(t, results, 1)
+	artifacts.SettleTxHash, artifacts.OriginStatus = results[0].TxHash, results[0].Status
+	require.NoError(t, results[0].Err, "settle")
+	// Both address forms read back as a left-padded word, so compare them as one
+	require.Equal(t, common.HexToHash(e2eSolver(origin)), common.HexToHash(results[0].Receiver),
+		"the Settle event pays %s, expected the solver on %s", results[0].Receiver, origin)
+
+	after := e2eSnapshot(ctx, t, holders)
+	artifacts.Balances = make(map[string]string, len(holders))
+	for _, h := range holders {
+		artifacts.Balances[h.Label] = fmt.Sprintf("%s → %s", before[h.Label], after[h.Label])
+	}
+
+	want := map[string]*big.Int{
+		"alice@origin":       new(big.Int).Neg(inputAmount),
+		"escrow@origin":      inputAmount,
+		"solver@origin":      new(big.Int),
+		"alice@destination":  outputAmount,
+		"solver@destination": new(big.Int).Neg(outputAmount),
+	}
+	if settleTimeout > 0 {
+		// The relayed settle message released the escrowed input to the Solver
+		want["escrow@origin"], want["solver@origin"] = new(big.Int), inputAmount
+	}
+	for _, h := range holders {
+		got := new(big.Int).Sub(after[h.Label], before[h.Label])
+		require.Zero(t, want[h.Label].Cmp(got), "%s (%s on %s): balance changed by %s, expected %s", h.Label, h.Owner, h.Network, got, want[h.Label])
+	}
+}
+
+// e2eCheckReachable fails fast with a hint when a fork is not running
+func e2eCheckReachable(cfg *config.Config, name string) error {
+	network, ok := cfg.Networks[name]
+	if !ok {
+		return fmt.Errorf("network %s is not configured", name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if config.IsStarknetNetwork(name) {
+		provider, err := rpc.NewProvider(network.RPCURL)
+		if err != nil {
+			return fmt.Errorf("%s at %s: %w", name, network.RPCURL, err)
+		}
+		if _, err := provider.BlockNumber(ctx); err != nil {
+			return fmt.Errorf("%s at %s is not reachable: %w", name, network.RPCURL, err)
+		}
+		return nil
+	}
+	client, err := ethclient.DialContext(ctx, network.RPCURL)
+	if err != nil {
+		return fmt.Errorf("%s at %s: %w", name, network.RPCURL, err)
+	}
+	defer client.Close()
+	if _, err := client.BlockNumber(ctx); err != nil {
+		return fmt.Errorf("%s at %s is not reachable: %w", name, network.RPCURL, err)
+	}
+	return nil
+}
+
+// e2eTokenAddress returns the address of the default order token on network
+func e2eTokenAddress(network string) (string, error) {
+	for _, token := range openorder.KnownTokens(network) {
+		if token.Symbol == openorder.DefaultOrderToken {
+			return token.Address, nil
+		}
+	}
+	return "", fmt.Errorf("no %s address on %s; run the setup first", openorder.DefaultOrderToken, network)
+}
+
+func e2eAlice(network string) string {
+	if config.IsStarknetNetwork(network) {
+		return envutil.GetStarknetAliceAddress()
+	}
+	return envutil.GetAlicePublicKey()
+}
+
+func e2eSolver(network string) string {
+	if config.IsStarknetNetwork(network) {
+		return envutil.GetStarknetSolverAddress()
+	}
+	return envutil.GetSolverPublicKey()
+}
+
+// e2eSnapshot reads the token balance of every holder
+func e2eSnapshot(ctx context.Context, t *testing.T, holders []e2eHolder) map[string]*big.Int {
+	t.Helper()
+	balances := make(map[string]*big.Int, len(holders))
+	for _, h := range holders {
+		balance, err := e2eBalance(ctx, h)
+		require.NoError(t, err, "failed to read %s balance", h.Label)
+		balances[h.Label] = balance
+	}
+	return balances
+}
+
+func e2eBalance(ctx context.Context, h e2eHolder) (*big.Int, error) {
+	rpcURL := config.Networks[h.Network].RPCURL
+	if config.IsStarknetNetwork(h.Network) {
+		provider, err := rpc.NewProvider(rpcURL)
+		if err != nil {
+			return nil, err
+		}
+		return starknetutil.ERC20Balance(ctx, provider, h.Token, h.Owner)
+	}
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return ethutil.ERC20BalanceAt(ctx, client, common.HexToAddress(h.Token), common.HexToAddress(h.Owner), nil)
+}
+
+// e2eReportArtifacts logs the artifacts of a failed order and writes them to E2E_ARTIFACTS_DIR
+func e2eReportArtifacts(t *testing.T, artifacts *e2eArtifacts) {
+	data, err := json.MarshalIndent(artifacts, "", "  ")
+	if err != nil {
+		t.Logf("failed to marshal artifacts: %v", err)
+		return
+	}
+	t.Logf("artifacts:\n%s", data)
+
+	dir := os.Getenv("E2E_ARTIFACTS_DIR")
+	if dir == "" {
+		return
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Logf("failed to create %s: %v", dir, err)
+		return
+	}
+	path := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Logf("failed to write %s: %v", path, err)
+		return
+	}
+	t.Logf("artifacts written to %s", path)
+}
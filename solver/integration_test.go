@@ -45,6 +45,10 @@ const (
 	// Order creation constants
 	OrderCreationTimeout   = 60 * time.Second // Max time to wait for order creation
 	OrderConfirmationDelay = 2 * time.Second  // Delay between order creation and confirmation check
+
+	// Balance verification constants
+	BalancePollTimeout  = 30 * time.Second       // Max time to wait for the origin balance to reflect the order
+	BalancePollInterval = 250 * time.Millisecond // How often to re-read the balance while waiting
 )
 
 // TestOrderLifecycleIntegration tests the complete order lifecycle
@@ -316,15 +320,25 @@ func testOrderCreationWithBalanceVerification(t *testing.T, solverPath string, c
 	t.Log("⏳ Step 4: Waiting for transaction to be fully processed...")
 
 	// Use proper transaction waiting instead of hardcoded delays
-	if err := waitForOpenTransaction(t, orderInfo); err != nil {
+	openBlock, err := waitForOpenTransaction(t, orderInfo)
+	if err != nil {
 		t.Logf("⚠️  Could not wait for transaction: %v", err)
 		t.Logf("   This is expected if the command failed or networks aren't running")
 		return
 	}
 
+	// Poll until the origin balance reflects the order instead of sleeping a fixed amount
+	aliceAfter, err := waitForAliceBalanceChange(orderInfo, beforeBalances.AliceBalances[orderInfo.OriginChain], openBlock)
+	if err != nil {
+		t.Logf("⚠️  Origin balance did not change: %v", err)
+	}
+
 	// Step 5: Get all network balances AFTER order creation
 	t.Log("📊 Step 5: Getting all network balances AFTER order creation...")
 	afterBalances := getAllNetworkBalances()
+	if aliceAfter != nil {
+		afterBalances.AliceBalances[orderInfo.OriginChain] = aliceAfter
+	}
 
 	// Log all after balances
 	t.Log("📋 After balances:")
@@ -359,7 +373,8 @@ type OrderInfo struct {
 }
 
 // waitForEVMTransaction waits for an EVM transaction to be confirmed with enhanced timeout and error handling
-func waitForEVMTransaction(t *testing.T, client *ethclient.Client, txHash common.Hash, timeout time.Duration) error {
+// and returns the block number it was mined in
+func waitForEVMTransaction(t *testing.T, client *ethclient.Client, txHash common.Hash, timeout time.Duration) (*big.Int, error) {
 	ctx, cancel := context.WithTimeout(t.Context(), timeout)
 	defer cancel()
 
@@ -369,12 +384,12 @@ func waitForEVMTransaction(t *testing.T, client *ethclient.Client, txHash common
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for EVM transaction: %w", ctx.Err())
+			return nil, fmt.Errorf("timeout waiting for EVM transaction: %w", ctx.Err())
 		default:
 			receipt, err := client.TransactionReceipt(ctx, txHash)
 			if err == nil && receipt != nil {
 				t.Logf("✅ EVM transaction confirmed: %s (gas used: %d)", txHash.Hex(), receipt.GasUsed)
-				return nil
+				return receipt.BlockNumber, nil
 			}
 			time.Sleep(OrderConfirmationDelay)
 		}
@@ -414,31 +429,32 @@ func waitForStarknetTransaction(t *testing.T, provider *rpc.Provider, txHash str
 }
 
 // waitForOpenTransaction waits for the `open` transaction to be confirmed using the appropriate method
-// based on the origin chain (EVM vs Starknet)
-func waitForOpenTransaction(t *testing.T, orderInfo *OrderInfo) error {
+// based on the origin chain (EVM vs Starknet). For EVM origins it returns the receipt block number
+// so balance reads can be pinned to it; for Starknet it returns nil
+func waitForOpenTransaction(t *testing.T, orderInfo *OrderInfo) (*big.Int, error) {
 	if orderInfo.TransactionHash == "" {
-		return fmt.Errorf("no transaction hash available for waiting")
+		return nil, fmt.Errorf("no transaction hash available for waiting")
 	}
 
 	// Get network configuration for the origin chain
 	networkConfig, err := config.GetNetworkConfig(orderInfo.OriginChain)
 	if err != nil {
-		return fmt.Errorf("failed to get network config for %s: %w", orderInfo.OriginChain, err)
+		return nil, fmt.Errorf("failed to get network config for %s: %w", orderInfo.OriginChain, err)
 	}
 
 	if orderInfo.OriginChain == "Starknet" {
 		// Use Starknet RPC
 		provider, err := rpc.NewProvider(networkConfig.RPCURL)
 		if err != nil {
-			return fmt.Errorf("failed to create Starknet provider: %w", err)
+			return nil, fmt.Errorf("failed to create Starknet provider: %w", err)
 		}
 
-		return waitForStarknetTransaction(t, provider, orderInfo.TransactionHash, OrderCreationTimeout)
+		return nil, waitForStarknetTransaction(t, provider, orderInfo.TransactionHash, OrderCreationTimeout)
 	} else {
 		// Use EVM RPC
 		client, err := ethclient.Dial(networkConfig.RPCURL)
 		if err != nil {
-			return fmt.Errorf("failed to create EVM client: %w", err)
+			return nil, fmt.Errorf("failed to create EVM client: %w", err)
 		}
 		defer client.Close()
 
@@ -449,6 +465,40 @@ func waitForOpenTransaction(t *testing.T, orderInfo *OrderInfo) error {
 	}
 }
 
+// waitForAliceBalanceChange polls Alice's origin chain DogCoin balance until it differs from before.
+// On EVM the reads are pinned to the open() receipt block so the comparison uses consistent state
+func waitForAliceBalanceChange(orderInfo *OrderInfo, before *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	networkConfig, err := config.GetNetworkConfig(orderInfo.OriginChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network config: %w", err)
+	}
+
+	aliceAddress := getAliceAddress(orderInfo.OriginChain)
+	tokenAddress, err := getDogCoinAddress(orderInfo.OriginChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DogCoin address: %w", err)
+	}
+
+	if orderInfo.OriginChain == "Starknet" {
+		provider, err := rpc.NewProvider(networkConfig.RPCURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Starknet provider: %w", err)
+		}
+
+		return starknetutil.WaitForERC20BalanceChange(context.Background(), provider, tokenAddress, aliceAddress, before,
+			starknetutil.BalancePollOptions{Timeout: BalancePollTimeout, PollInterval: BalancePollInterval})
+	}
+
+	client, err := ethclient.Dial(networkConfig.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EVM client: %w", err)
+	}
+	defer client.Close()
+
+	return ethutil.WaitForERC20BalanceChange(context.Background(), client, common.HexToAddress(tokenAddress), common.HexToAddress(aliceAddress), before,
+		ethutil.BalancePollOptions{Timeout: BalancePollTimeout, PollInterval: BalancePollInterval, BlockNumber: blockNumber})
+}
+
 // getAllNetworkBalances gets Alice's DogCoin balance and Hyperlane contract balance for all networks
 func getAllNetworkBalances() *NetworkBalances {
 	balances := &NetworkBalances{
@@ -503,7 +553,7 @@ func getAliceDogCoinBalance(networkName string) (*big.Int, error) {
 			return nil, fmt.Errorf("failed to create Starknet provider: %w", err)
 		}
 
-		return starknetutil.ERC20Balance(provider, tokenAddress, aliceAddress)
+		return starknetutil.ERC20Balance(context.Background(), provider, tokenAddress, aliceAddress)
 	} else {
 		// Use EVM RPC
 		client, err := ethclient.Dial(networkConfig.RPCURL)
@@ -542,7 +592,7 @@ func getHyperlaneDogCoinBalance(networkName string) (*big.Int, error) {
 			return nil, fmt.Errorf("STARKNET_HYPERLANE_ADDRESS not set")
 		}
 
-		return starknetutil.ERC20Balance(provider, tokenAddress, hyperlaneAddress)
+		return starknetutil.ERC20Balance(context.Background(), provider, tokenAddress, hyperlaneAddress)
 	} else {
 		// Use EVM RPC
 		client, err := ethclient.Dial(networkConfig.RPCURL)
@@ -909,15 +959,25 @@ func testOrderCreationOnly(t *testing.T, solverPath string, orderCommand []strin
 	t.Log("⏳ Step 4: Waiting for transaction to be fully processed...")
 
 	// Use proper transaction waiting instead of hardcoded delays
-	if err := waitForOpenTransaction(t, orderInfo); err != nil {
+	openBlock, err := waitForOpenTransaction(t, orderInfo)
+	if err != nil {
 		t.Logf("⚠️  Could not wait for transaction: %v", err)
 		t.Logf("   This is expected if the command failed or networks aren't running")
 		return
 	}
 
+	// Poll until the origin balance reflects the order instead of sleeping a fixed amount
+	aliceAfter, err := waitForAliceBalanceChange(orderInfo, beforeOrderBalances.AliceBalances[orderInfo.OriginChain], openBlock)
+	if err != nil {
+		t.Logf("⚠️  Origin balance did not change: %v", err)
+	}
+
 	// Step 5: Get all network balances AFTER order creation
 	t.Log("📊 Step 5: Getting all network balances AFTER order creation...")
 	afterOrderBalances := getAllNetworkBalances()
+	if aliceAfter != nil {
+		afterOrderBalances.AliceBalances[orderInfo.OriginChain] = aliceAfter
+	}
 
 	for network, beforeBalance := range beforeOrderBalances.HyperlaneBalances {
 		afterBalance := afterOrderBalances.HyperlaneBalances[network]
@@ -1060,7 +1120,7 @@ func getSolverDogCoinBalance(networkName string) (*big.Int, error) {
 			return nil, fmt.Errorf("failed to create Starknet provider: %w", err)
 		}
 
-		return starknetutil.ERC20Balance(provider, tokenAddress, solverAddress)
+		return starknetutil.ERC20Balance(context.Background(), provider, tokenAddress, solverAddress)
 	} else {
 		// Use EVM RPC
 		client, err := ethclient.Dial(networkConfig.RPCURL)
@@ -1311,7 +1371,7 @@ func testCompleteOrderLifecycleMultiOrder(t *testing.T, solverPath string) {
 		// Wait for this order's transaction to be confirmed before creating the next one
 		if orderInfo.TransactionHash != "" {
 			t.Logf("⏳ Waiting for order %d transaction confirmation...", i+1)
-			if err := waitForOpenTransaction(t, orderInfo); err != nil {
+			if _, err := waitForOpenTransaction(t, orderInfo); err != nil {
 				t.Logf("⚠️  Could not wait for order %d transaction: %v", i+1, err)
 				t.Logf("   Continuing with next order...")
 			} else {
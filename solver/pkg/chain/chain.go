@@ -0,0 +1,149 @@
+// Package chain gives each network family one implementation of its address rules.
+//
+// OrderData, routers and filler data carry addresses as bytes32 words, and each family writes its addresses
+// differently: EVM addresses are left-padded 20-byte values, Starknet and Ztarknet addresses are felts. Tools ask
+// a Network for the conversion instead of switching on the network type, so a new chain family is one more
+// implementation here.
+//
+// Address errors read as a predicate of the address ("must not be zero", "is not an EVM address (0x12)") and wrap
+// the sentinels below, for callers to prefix with what the address is:
+//
+//	word, err := chain.Of("Base").AddressToBytes32(address)
+//	if err != nil {
+//		return fmt.Errorf("BASE_HYPERLANE_ADDRESS %w", err)
+//	}
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// nativeTokenDecimals are the decimals of ETH and of STRK, the native tokens of both families
+const nativeTokenDecimals = 18
+
+var (
+	// ErrAddressNotSet is returned for an empty address
+	ErrAddressNotSet = errors.New("not set")
+	// ErrZeroAddress is returned for the zero address, which no order or router may use
+	ErrZeroAddress = errors.New("must not be zero")
+	// ErrNotEVMAddress is returned by EVM networks for anything but a 20-byte hex address
+	ErrNotEVMAddress = errors.New("is not an EVM address")
+	// ErrNotFelt is returned by Starknet networks for anything but a hex value below the field prime
+	ErrNotFelt = errors.New("is not a valid felt")
+)
+
+// Network is a configured network and the address rules of its family
+type Network interface {
+	// Name is the configured network name
+	Name() string
+	// Type is the configured network type
+	Type() config.NetworkType
+	// AddressToBytes32 validates address as a non-zero address of the network and returns its bytes32 word. A
+	// value that is not an address of the network is rejected rather than truncated or reduced into one
+	AddressToBytes32(address string) ([32]byte, error)
+	// Bytes32ToNativeAddress reads a word back as a hex address of the network
+	Bytes32ToNativeAddress(word [32]byte) (string, error)
+	// NativeTokenDecimals are the decimals of the token the network's fees are paid in
+	NativeTokenDecimals() uint8
+}
+
+// New returns the Network of cfg's family
+func New(cfg config.NetworkConfig) Network {
+	if cfg.Type.IsCairo() {
+		return StarknetNetwork{cfg: cfg}
+	}
+	return EVMNetwork{cfg: cfg}
+}
+
+// Of returns the configured network named name (case-insensitive). Names that are not configured, such as
+// CUSTOM_DOMAIN_<NAME> networks, are EVM networks, as in config.NetworkTypeOf
+func Of(name string) Network {
+	cfg, err := config.GetNetworkConfig(name)
+	if err != nil {
+		cfg = config.NetworkConfig{Name: name, Type: config.NetworkTypeOf(name)}
+	}
+	return New(cfg)
+}
+
+// EVMNetwork is a network running the Solidity contracts
+type EVMNetwork struct {
+	cfg config.NetworkConfig
+}
+
+// Name is the configured network name
+func (n EVMNetwork) Name() string { return n.cfg.Name }
+
+// Type is config.NetworkTypeEVM
+func (n EVMNetwork) Type() config.NetworkType { return config.NetworkTypeEVM }
+
+// NativeTokenDecimals are the decimals of ETH
+func (n EVMNetwork) NativeTokenDecimals() uint8 { return nativeTokenDecimals }
+
+// AddressToBytes32 left-pads a 20-byte hex address to a word; a 32-byte word is not an EVM address
+func (n EVMNetwork) AddressToBytes32(address string) ([32]byte, error) {
+	if address == "" {
+		return [32]byte{}, ErrAddressNotSet
+	}
+	if !common.IsHexAddress(address) {
+		return [32]byte{}, fmt.Errorf("%w (%s)", ErrNotEVMAddress, address)
+	}
+	evmAddress := common.HexToAddress(address)
+	if evmAddress == (common.Address{}) {
+		return [32]byte{}, ErrZeroAddress
+	}
+	return starknetutil.EVMAddressToBytes32(evmAddress), nil
+}
+
+// Bytes32ToNativeAddress reads a left-padded word as a checksummed address
+func (n EVMNetwork) Bytes32ToNativeAddress(word [32]byte) (string, error) {
+	address, err := starknetutil.Bytes32ToEVMAddress(word)
+	if err != nil {
+		return "", err
+	}
+	return address.Hex(), nil
+}
+
+// StarknetNetwork is a network running the Cairo contracts: Starknet or Ztarknet
+type StarknetNetwork struct {
+	cfg config.NetworkConfig
+}
+
+// Name is the configured network name
+func (n StarknetNetwork) Name() string { return n.cfg.Name }
+
+// Type is config.NetworkTypeStarknet or config.NetworkTypeZtarknet
+func (n StarknetNetwork) Type() config.NetworkType { return n.cfg.Type }
+
+// NativeTokenDecimals are the decimals of STRK
+func (n StarknetNetwork) NativeTokenDecimals() uint8 { return nativeTokenDecimals }
+
+// AddressToBytes32 writes a felt as its big-endian word
+func (n StarknetNetwork) AddressToBytes32(address string) ([32]byte, error) {
+	if address == "" {
+		return [32]byte{}, ErrAddressNotSet
+	}
+	f, err := utils.HexToFelt(address)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("%w (%s): %w", ErrNotFelt, address, err)
+	}
+	if f.IsZero() {
+		return [32]byte{}, ErrZeroAddress
+	}
+	return starknetutil.FeltToBytes32(f), nil
+}
+
+// Bytes32ToNativeAddress reads a word as a felt; a word not below the field prime is an error
+func (n StarknetNetwork) Bytes32ToNativeAddress(word [32]byte) (string, error) {
+	f, err := starknetutil.Bytes32ToFelt(word)
+	if err != nil {
+		return "", err
+	}
+	return f.String(), nil
+}
@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+const (
+	testEVMAddress      = "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"
+	testStarknetAddress = "0x4c8d1a8ff5dc2e6b5b1c1d8a3e7d6e2b0f0a1b2c3d4e5f60718293a4b5c6d7"
+	// feltOutOfRange is the field prime, which would wrap to zero if it were reduced
+	feltOutOfRange = "0x0800000000000011000000000000000000000000000000000000000000000001"
+)
+
+func TestNew(t *testing.T) {
+	assert.IsType(t, EVMNetwork{}, New(config.NetworkConfig{Name: "Base", Type: config.NetworkTypeEVM}))
+	assert.IsType(t, EVMNetwork{}, New(config.NetworkConfig{Name: "Custom"}), "an untyped network is EVM")
+
+	starknet := New(config.NetworkConfig{Name: "Starknet", Type: config.NetworkTypeStarknet})
+	assert.IsType(t, StarknetNetwork{}, starknet)
+	assert.Equal(t, "Starknet", starknet.Name())
+
+	ztarknet := New(config.NetworkConfig{Name: "Ztarknet", Type: config.NetworkTypeZtarknet})
+	assert.IsType(t, StarknetNetwork{}, ztarknet)
+	assert.Equal(t, config.NetworkTypeZtarknet, ztarknet.Type())
+	assert.Equal(t, uint8(18), ztarknet.NativeTokenDecimals())
+}
+
+func TestOf(t *testing.T) {
+	assert.Equal(t, config.NetworkTypeStarknet, Of("Starknet").Type())
+	assert.Equal(t, config.NetworkTypeEVM, Of("Base").Type())
+
+	custom := Of("Custom")
+	assert.Equal(t, config.NetworkTypeEVM, custom.Type(), "networks that are not configured are EVM")
+	assert.Equal(t, "Custom", custom.Name())
+}
+
+func TestEVMNetworkConversions(t *testing.T) {
+	n := New(config.NetworkConfig{Name: "Base", Type: config.NetworkTypeEVM})
+	assert.Equal(t, uint8(18), n.NativeTokenDecimals())
+
+	word, err := n.AddressToBytes32(testEVMAddress)
+	require.NoError(t, err)
+	assert.Equal(t, "000000000000000000000000f614c6bf94b022e16bef7dbecf7614ffd2b201d3", hex.EncodeToString(word[:]))
+
+	address, err := n.Bytes32ToNativeAddress(word)
+	require.NoError(t, err)
+	assert.Equal(t, testEVMAddress, address, "addresses read back checksummed")
+
+	_, err = n.Bytes32ToNativeAddress([32]byte{1})
+	assert.ErrorContains(t, err, "is not an EVM address")
+
+	for _, tc := range []struct {
+		address string
+		err     error
+	}{
+		{"", ErrAddressNotSet},
+		{"0x0000000000000000000000000000000000000000", ErrZeroAddress},
+		{testStarknetAddress, ErrNotEVMAddress},
+		{"0xzz14c6bF94b022E16BEF7dBecF7614FFD2b201d3", ErrNotEVMAddress},
+	} {
+		_, err := n.AddressToBytes32(tc.address)
+		assert.ErrorIs(t, err, tc.err, tc.address)
+	}
+
+	_, err = n.AddressToBytes32(testStarknetAddress)
+	assert.EqualError(t, err, "is not an EVM address ("+testStarknetAddress+")")
+}
+
+func TestStarknetNetworkConversions(t *testing.T) {
+	n := New(config.NetworkConfig{Name: "Starknet", Type: config.NetworkTypeStarknet})
+
+	word, err := n.AddressToBytes32(testStarknetAddress)
+	require.NoError(t, err)
+	assert.Equal(t, testStarknetAddress[2:], hex.EncodeToString(word[1:]))
+	assert.Equal(t, byte(0), word[0])
+
+	address, err := n.Bytes32ToNativeAddress(word)
+	require.NoError(t, err)
+	assert.Equal(t, testStarknetAddress, address)
+
+	// EVM-sized values are felts too, written right-aligned
+	word, err = n.AddressToBytes32(testEVMAddress)
+	require.NoError(t, err)
+	assert.Equal(t, "000000000000000000000000f614c6bf94b022e16bef7dbecf7614ffd2b201d3", hex.EncodeToString(word[:]))
+
+	var overflow [32]byte
+	overflow[0] = 0xff
+	_, err = n.Bytes32ToNativeAddress(overflow)
+	assert.ErrorContains(t, err, "exceeds the felt field")
+
+	for _, tc := range []struct {
+		address string
+		err     error
+	}{
+		{"", ErrAddressNotSet},
+		{"0x0", ErrZeroAddress},
+		{feltOutOfRange, ErrNotFelt},
+		{"0xzz", ErrNotFelt},
+	} {
+		_, err := n.AddressToBytes32(tc.address)
+		assert.ErrorIs(t, err, tc.err, tc.address)
+	}
+}
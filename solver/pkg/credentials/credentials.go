@@ -0,0 +1,163 @@
+// Package credentials loads the private keys the tools sign with.
+//
+// An account's keys are named by an env prefix, e.g. ALICE, LOCAL_ALICE or STARKNET_DEPLOYER, and
+// <prefix>_KEY_SOURCE picks where they come from:
+//
+//	(unset) or env     <prefix>_PRIVATE_KEY (and <prefix>_PUBLIC_KEY for Starknet keys), as in .env today
+//	keystore:<path>    an encrypted keystore file, unlocked with the password in <prefix>_KEY_PASSWORD_FILE
+//
+// EVM keystores are go-ethereum's (geth account new, cast wallet import); Starknet keystores use the format
+// in starknet_keystore.go and are created with the create-sn-keystore tool.
+package credentials
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+)
+
+const (
+	sourceEnv      = "env"
+	sourceKeystore = "keystore"
+)
+
+// ErrMissingKey is returned when the env source has no key configured, so tools can fall back or explain
+var ErrMissingKey = errors.New("private key not configured")
+
+// Provider supplies an account's keys
+type Provider interface {
+	// EVMKey returns the account's EVM private key
+	EVMKey() (*ecdsa.PrivateKey, error)
+	// StarknetKey returns the account's Starknet key pair
+	StarknetKey() (StarknetKeyPair, error)
+	// String names where the keys come from, for messages
+	String() string
+}
+
+// StarknetKeyPair is a Starknet account's signing key and the public key it is registered under
+type StarknetKeyPair struct {
+	PrivateKey *big.Int
+	PublicKey  *felt.Felt
+}
+
+// Keystore returns a starknet.go keystore holding the pair under PublicKey.String(), which is the public key
+// to create the account with
+func (k StarknetKeyPair) Keystore() *account.MemKeystore {
+	ks := account.NewMemKeystore()
+	ks.Put(k.PublicKey.String(), k.PrivateKey)
+	return ks
+}
+
+// Verify checks PublicKey is the one PrivateKey derives. The env source reads both separately, so they can drift
+func (k StarknetKeyPair) Verify() error {
+	derived, err := NewStarknetKeyPair(k.PrivateKey)
+	if err != nil {
+		return err
+	}
+	if k.PublicKey == nil || !derived.PublicKey.Equal(k.PublicKey) {
+		return fmt.Errorf("public key %v is not the private key's (%s)", k.PublicKey, derived.PublicKey)
+	}
+	return nil
+}
+
+// For returns the provider <prefix>_KEY_SOURCE selects
+func For(prefix string) (Provider, error) {
+	sourceEnvName := prefix + "_KEY_SOURCE"
+	source := strings.TrimSpace(os.Getenv(sourceEnvName))
+	kind, path, _ := strings.Cut(source, ":")
+	switch kind {
+	case "", sourceEnv:
+		if path != "" {
+			return nil, fmt.Errorf("invalid %s %q: the env source takes no path", sourceEnvName, source)
+		}
+		return envProvider{prefix: prefix}, nil
+	case sourceKeystore:
+		if path == "" {
+			return nil, fmt.Errorf("invalid %s %q: expected keystore:<path>", sourceEnvName, source)
+		}
+		return keystoreProvider{path: path, passwordEnv: prefix + "_KEY_PASSWORD_FILE"}, nil
+	default:
+		return nil, fmt.Errorf("invalid %s %q: expected env or keystore:<path>", sourceEnvName, source)
+	}
+}
+
+// LoadEVMKey loads the EVM key of prefix from its configured source
+func LoadEVMKey(prefix string) (*ecdsa.PrivateKey, error) {
+	p, err := For(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return p.EVMKey()
+}
+
+// LoadStarknetKey loads the Starknet key pair of prefix from its configured source
+func LoadStarknetKey(prefix string) (StarknetKeyPair, error) {
+	p, err := For(prefix)
+	if err != nil {
+		return StarknetKeyPair{}, err
+	}
+	return p.StarknetKey()
+}
+
+// envProvider reads raw keys from <prefix>_PRIVATE_KEY and <prefix>_PUBLIC_KEY
+type envProvider struct {
+	prefix string
+}
+
+func (p envProvider) String() string { return p.prefix + "_PRIVATE_KEY" }
+
+// lookup returns the value of the env var prefix_suffix. Unset values and example.env placeholders ("your ...
+// private key") are ErrMissingKey
+func (p envProvider) lookup(suffix string) (string, error) {
+	name := p.prefix + "_" + suffix
+	value := os.Getenv(name)
+	if value == "" || strings.Contains(value, " ") {
+		return "", fmt.Errorf("%w: %s is not set", ErrMissingKey, name)
+	}
+	return value, nil
+}
+
+func (p envProvider) EVMKey() (*ecdsa.PrivateKey, error) {
+	value, err := p.lookup("PRIVATE_KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := ethutil.ParsePrivateKey(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", p, err)
+	}
+	return key, nil
+}
+
+func (p envProvider) StarknetKey() (StarknetKeyPair, error) {
+	privateKey, err := p.lookup("PRIVATE_KEY")
+	if err != nil {
+		return StarknetKeyPair{}, err
+	}
+	publicKey, err := p.lookup("PUBLIC_KEY")
+	if err != nil {
+		return StarknetKeyPair{}, err
+	}
+
+	priv, ok := new(big.Int).SetString(privateKey, 0)
+	if !ok {
+		return StarknetKeyPair{}, fmt.Errorf("invalid %s: expected an integer", p)
+	}
+	if err := checkStarknetPrivateKey(priv); err != nil {
+		return StarknetKeyPair{}, fmt.Errorf("invalid %s: %w", p, err)
+	}
+	pub, err := utils.HexToFelt(publicKey)
+	if err != nil {
+		return StarknetKeyPair{}, fmt.Errorf("invalid %s_PUBLIC_KEY: %w", p.prefix, err)
+	}
+	return StarknetKeyPair{PrivateKey: priv, PublicKey: pub}, nil
+}
@@ -0,0 +1,172 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Anvil's account 1, sealed in testdata/evm_keystore.json with vectorPassword
+const (
+	testEVMKey     = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	testEVMAddress = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+)
+
+func mustFelt(t *testing.T, hexStr string) *felt.Felt {
+	t.Helper()
+	f, err := utils.HexToFelt(hexStr)
+	require.NoError(t, err)
+	return f
+}
+
+// setKeyEnv sets the TEST_ALICE_* variables, clearing any not given
+func setKeyEnv(t *testing.T, vars map[string]string) {
+	t.Helper()
+	for _, suffix := range []string{"KEY_SOURCE", "KEY_PASSWORD_FILE", "PRIVATE_KEY", "PUBLIC_KEY"} {
+		t.Setenv("TEST_ALICE_"+suffix, vars[suffix])
+	}
+}
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    string
+		wantErr string
+	}{
+		{source: "", want: "TEST_ALICE_PRIVATE_KEY"},
+		{source: "env", want: "TEST_ALICE_PRIVATE_KEY"},
+		{source: "keystore:/keys/alice.json", want: "keystore /keys/alice.json"},
+		{source: "keystore:", wantErr: `invalid TEST_ALICE_KEY_SOURCE "keystore:": expected keystore:<path>`},
+		{source: "env:/keys/alice.json", wantErr: `invalid TEST_ALICE_KEY_SOURCE "env:/keys/alice.json": the env source takes no path`},
+		{source: "keyring", wantErr: `invalid TEST_ALICE_KEY_SOURCE "keyring": expected env or keystore:<path>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			setKeyEnv(t, map[string]string{"KEY_SOURCE": tt.source})
+			p, err := For("TEST_ALICE")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p.String())
+		})
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Run("evm", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"PRIVATE_KEY": testEVMKey})
+		key, err := LoadEVMKey("TEST_ALICE")
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToAddress(testEVMAddress), crypto.PubkeyToAddress(key.PublicKey))
+	})
+
+	t.Run("starknet", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"KEY_SOURCE": "env", "PRIVATE_KEY": vectorPrivateKey, "PUBLIC_KEY": vectorPublicKey})
+		key, err := LoadStarknetKey("TEST_ALICE")
+		require.NoError(t, err)
+		assert.Equal(t, vectorPublicKey, key.PublicKey.String())
+		assert.Equal(t, vectorPrivateKey, "0x"+key.PrivateKey.Text(16))
+
+		_, err = key.Keystore().Get(vectorPublicKey)
+		assert.NoError(t, err, "the keystore is keyed by the public key's string")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"PRIVATE_KEY": "your order opener private key", "PUBLIC_KEY": vectorPublicKey})
+		_, err := LoadEVMKey("TEST_ALICE")
+		assert.ErrorIs(t, err, ErrMissingKey, "example.env placeholders count as missing")
+		assert.ErrorContains(t, err, "TEST_ALICE_PRIVATE_KEY is not set")
+
+		setKeyEnv(t, map[string]string{"PRIVATE_KEY": vectorPrivateKey})
+		_, err = LoadStarknetKey("TEST_ALICE")
+		assert.ErrorIs(t, err, ErrMissingKey)
+		assert.ErrorContains(t, err, "TEST_ALICE_PUBLIC_KEY is not set")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"PRIVATE_KEY": "0x1234", "PUBLIC_KEY": "0xzz"})
+		_, err := LoadEVMKey("TEST_ALICE")
+		assert.ErrorContains(t, err, "invalid TEST_ALICE_PRIVATE_KEY")
+		assert.NotErrorIs(t, err, ErrMissingKey)
+
+		_, err = LoadStarknetKey("TEST_ALICE")
+		assert.ErrorContains(t, err, "invalid TEST_ALICE_PUBLIC_KEY")
+	})
+}
+
+func TestKeystoreProvider(t *testing.T) {
+	passwordFile, err := filepath.Abs("testdata/password.txt")
+	require.NoError(t, err)
+
+	t.Run("evm", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"KEY_SOURCE": "keystore:testdata/evm_keystore.json", "KEY_PASSWORD_FILE": passwordFile})
+		key, err := LoadEVMKey("TEST_ALICE")
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToAddress(testEVMAddress), crypto.PubkeyToAddress(key.PublicKey))
+	})
+
+	t.Run("starknet", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"KEY_SOURCE": "keystore:testdata/starknet_keystore.json", "KEY_PASSWORD_FILE": passwordFile})
+		key, err := LoadStarknetKey("TEST_ALICE")
+		require.NoError(t, err)
+		assert.Equal(t, vectorPublicKey, key.PublicKey.String())
+	})
+
+	t.Run("wrong_kind", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"KEY_SOURCE": "keystore:testdata/evm_keystore.json", "KEY_PASSWORD_FILE": passwordFile})
+		_, err := LoadStarknetKey("TEST_ALICE")
+		assert.ErrorContains(t, err, "failed to decrypt Starknet keystore testdata/evm_keystore.json: unsupported Starknet keystore version 3")
+	})
+
+	t.Run("wrong_password", func(t *testing.T) {
+		wrong := filepath.Join(t.TempDir(), "password")
+		require.NoError(t, os.WriteFile(wrong, []byte("nope\n"), 0o600))
+		setKeyEnv(t, map[string]string{"KEY_SOURCE": "keystore:testdata/starknet_keystore.json", "KEY_PASSWORD_FILE": wrong})
+		_, err := LoadStarknetKey("TEST_ALICE")
+		assert.ErrorIs(t, err, keystore.ErrDecrypt)
+	})
+
+	t.Run("no_password_file", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"KEY_SOURCE": "keystore:testdata/evm_keystore.json"})
+		_, err := LoadEVMKey("TEST_ALICE")
+		assert.EqualError(t, err, "TEST_ALICE_KEY_PASSWORD_FILE is required to unlock keystore testdata/evm_keystore.json")
+		assert.NotErrorIs(t, err, ErrMissingKey, "a configured keystore is not a missing key")
+	})
+
+	t.Run("no_keystore", func(t *testing.T) {
+		setKeyEnv(t, map[string]string{"KEY_SOURCE": "keystore:testdata/missing.json", "KEY_PASSWORD_FILE": passwordFile})
+		_, err := LoadEVMKey("TEST_ALICE")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestReadPasswordFile(t *testing.T) {
+	dir := t.TempDir()
+	for content, want := range map[string]string{
+		"secret":              "secret",
+		"secret\n":            "secret",
+		"secret\r\n":          "secret",
+		"with spaces \nline2": "with spaces ",
+		"":                    "",
+	} {
+		path := filepath.Join(dir, "password")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		got, err := ReadPasswordFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "%q", content)
+	}
+
+	_, err := ReadPasswordFile(filepath.Join(dir, "missing"))
+	assert.ErrorContains(t, err, "failed to read password file")
+}
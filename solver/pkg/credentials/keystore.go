@@ -0,0 +1,70 @@
+package credentials
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// keystoreProvider decrypts a keystore file with the password read from the file named by passwordEnv
+type keystoreProvider struct {
+	path        string
+	passwordEnv string
+}
+
+func (p keystoreProvider) String() string { return "keystore " + p.path }
+
+// read returns the keystore JSON and its password
+func (p keystoreProvider) read() ([]byte, string, error) {
+	passwordFile := os.Getenv(p.passwordEnv)
+	if passwordFile == "" {
+		return nil, "", fmt.Errorf("%s is required to unlock %s", p.passwordEnv, p)
+	}
+	password, err := ReadPasswordFile(passwordFile)
+	if err != nil {
+		return nil, "", err
+	}
+	keyJSON, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read keystore: %w", err)
+	}
+	return keyJSON, password, nil
+}
+
+func (p keystoreProvider) EVMKey() (*ecdsa.PrivateKey, error) {
+	keyJSON, password, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt EVM %s: %w", p, err)
+	}
+	return key.PrivateKey, nil
+}
+
+func (p keystoreProvider) StarknetKey() (StarknetKeyPair, error) {
+	keyJSON, password, err := p.read()
+	if err != nil {
+		return StarknetKeyPair{}, err
+	}
+	key, err := DecryptStarknetKey(keyJSON, password)
+	if err != nil {
+		return StarknetKeyPair{}, fmt.Errorf("failed to decrypt Starknet %s: %w", p, err)
+	}
+	return key, nil
+}
+
+// ReadPasswordFile reads a keystore password. Like geth's --password, only the first line is used, so a trailing
+// newline is not part of the password
+func ReadPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	password, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSuffix(password, "\r"), nil
+}
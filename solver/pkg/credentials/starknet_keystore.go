@@ -0,0 +1,187 @@
+package credentials
+
+// Starknet keystore
+// A JSON file in the spirit of the Web3 Secret Storage format go-ethereum uses, with the KDF and cipher fixed:
+//
+//	{"version": 1, "crypto": {"kdf": "scrypt", "kdfparams": {"n", "r", "p", "dklen", "salt"},
+//	 "cipher": "aes-256-gcm", "nonce": <hex>, "ciphertext": <hex>}}
+//
+// The scrypt-derived 32-byte key seals privateKey || publicKey (two big-endian 32-byte words) with AES-256-GCM.
+// The GCM tag makes a wrong password or an edited file fail to decrypt instead of yielding another key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	starknetKeystoreVersion = 1
+	starknetKeystoreKDF     = "scrypt"
+	starknetKeystoreCipher  = "aes-256-gcm"
+
+	scryptR     = 8
+	scryptDKLen = 32
+	saltLength  = 32
+	wordLength  = 32
+)
+
+// starkCurveOrder bounds Starknet private keys to [1, order)
+var starkCurveOrder, _ = new(big.Int).SetString("800000000000010ffffffffffffffffb781126dcae7b2321e66a241adc64d2f", 16)
+
+// starknetKeystoreAAD binds the ciphertext to the format version
+var starknetKeystoreAAD = []byte("oif-starknet keystore v1")
+
+type starknetKeystoreJSON struct {
+	Version int                    `json:"version"`
+	Crypto  starknetKeystoreCrypto `json:"crypto"`
+}
+
+type starknetKeystoreCrypto struct {
+	KDF        string       `json:"kdf"`
+	KDFParams  scryptParams `json:"kdfparams"`
+	Cipher     string       `json:"cipher"`
+	Nonce      string       `json:"nonce"`
+	Ciphertext string       `json:"ciphertext"`
+}
+
+type scryptParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// NewStarknetKeyPair returns the pair of privateKey, deriving its public key
+func NewStarknetKeyPair(privateKey *big.Int) (StarknetKeyPair, error) {
+	if err := checkStarknetPrivateKey(privateKey); err != nil {
+		return StarknetKeyPair{}, err
+	}
+	x, _ := curve.PrivateKeyToPoint(privateKey)
+	return StarknetKeyPair{PrivateKey: new(big.Int).Set(privateKey), PublicKey: new(felt.Felt).SetBigInt(x)}, nil
+}
+
+// checkStarknetPrivateKey checks a private key is in [1, curve order)
+func checkStarknetPrivateKey(privateKey *big.Int) error {
+	if privateKey == nil || privateKey.Sign() <= 0 || privateKey.Cmp(starkCurveOrder) >= 0 {
+		return fmt.Errorf("private key is not in the STARK curve's scalar range")
+	}
+	return nil
+}
+
+// EncryptStarknetKey encrypts a key pair into a keystore. scryptN and scryptP are go-ethereum's, e.g.
+// keystore.StandardScryptN and keystore.StandardScryptP. The public key must be the private key's
+func EncryptStarknetKey(key StarknetKeyPair, password string, scryptN, scryptP int) ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return encryptStarknetKey(key, password, scryptParams{N: scryptN, R: scryptR, P: scryptP, DKLen: scryptDKLen}, salt, nonce)
+}
+
+// encryptStarknetKey is EncryptStarknetKey with a fixed salt and nonce
+func encryptStarknetKey(key StarknetKeyPair, password string, params scryptParams, salt, nonce []byte) ([]byte, error) {
+	if err := key.Verify(); err != nil {
+		return nil, err
+	}
+
+	params.Salt = hex.EncodeToString(salt)
+	aead, err := newStarknetKeystoreAEAD(password, params)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, 2*wordLength)
+	key.PrivateKey.FillBytes(plaintext[:wordLength])
+	publicKey := key.PublicKey.Bytes()
+	copy(plaintext[wordLength:], publicKey[:])
+
+	return json.MarshalIndent(starknetKeystoreJSON{
+		Version: starknetKeystoreVersion,
+		Crypto: starknetKeystoreCrypto{
+			KDF:        starknetKeystoreKDF,
+			KDFParams:  params,
+			Cipher:     starknetKeystoreCipher,
+			Nonce:      hex.EncodeToString(nonce),
+			Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, starknetKeystoreAAD)),
+		},
+	}, "", "  ")
+}
+
+// DecryptStarknetKey decrypts a keystore made by EncryptStarknetKey. A wrong password is keystore.ErrDecrypt
+func DecryptStarknetKey(keyJSON []byte, password string) (StarknetKeyPair, error) {
+	var ks starknetKeystoreJSON
+	if err := json.Unmarshal(keyJSON, &ks); err != nil {
+		return StarknetKeyPair{}, fmt.Errorf("invalid Starknet keystore: %w", err)
+	}
+	if ks.Version != starknetKeystoreVersion {
+		return StarknetKeyPair{}, fmt.Errorf("unsupported Starknet keystore version %d", ks.Version)
+	}
+	if ks.Crypto.KDF != starknetKeystoreKDF || ks.Crypto.Cipher != starknetKeystoreCipher {
+		return StarknetKeyPair{}, fmt.Errorf("unsupported Starknet keystore kdf %q or cipher %q", ks.Crypto.KDF, ks.Crypto.Cipher)
+	}
+
+	aead, err := newStarknetKeystoreAEAD(password, ks.Crypto.KDFParams)
+	if err != nil {
+		return StarknetKeyPair{}, err
+	}
+	nonce, err := hex.DecodeString(ks.Crypto.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return StarknetKeyPair{}, fmt.Errorf("invalid Starknet keystore nonce %q", ks.Crypto.Nonce)
+	}
+	ciphertext, err := hex.DecodeString(ks.Crypto.Ciphertext)
+	if err != nil {
+		return StarknetKeyPair{}, fmt.Errorf("invalid Starknet keystore ciphertext: %w", err)
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, starknetKeystoreAAD)
+	if err != nil {
+		return StarknetKeyPair{}, keystore.ErrDecrypt
+	}
+	if len(plaintext) != 2*wordLength {
+		return StarknetKeyPair{}, fmt.Errorf("starknet keystore holds %d bytes, expected %d", len(plaintext), 2*wordLength)
+	}
+
+	privateKey := new(big.Int).SetBytes(plaintext[:wordLength])
+	if err := checkStarknetPrivateKey(privateKey); err != nil {
+		return StarknetKeyPair{}, err
+	}
+	publicKey := new(felt.Felt)
+	if err := publicKey.SetBytesCanonical(plaintext[wordLength:]); err != nil {
+		return StarknetKeyPair{}, fmt.Errorf("starknet keystore public key exceeds the felt field")
+	}
+	return StarknetKeyPair{PrivateKey: privateKey, PublicKey: publicKey}, nil
+}
+
+// newStarknetKeystoreAEAD derives the AES-256-GCM cipher of a keystore from its password
+func newStarknetKeystoreAEAD(password string, params scryptParams) (cipher.AEAD, error) {
+	if params.DKLen != scryptDKLen {
+		return nil, fmt.Errorf("unsupported scrypt dklen %d, expected %d", params.DKLen, scryptDKLen)
+	}
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("invalid scrypt salt %q", params.Salt)
+	}
+	derived, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scrypt parameters: %w", err)
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
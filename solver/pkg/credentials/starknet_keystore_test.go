@@ -0,0 +1,152 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The fixed vector in testdata/starknet_keystore.json: devnet Alice's key (example.env) sealed with
+// "testpassword", scrypt n=4096 r=8 p=6, the salt and nonce below
+const (
+	vectorPassword   = "testpassword"
+	vectorPrivateKey = "0x1c9053c053edf324aec366a34c6901b1095b07af69495bffec7d7fe21effb1b"
+	vectorPublicKey  = "0x4c339f18b9d1b95b64a6d378abd1480b2e0d5d5bd33cd0828cbce4d65c27284"
+	vectorSalt       = "6f69662d737461726b6e65742074657374207661637465722073616c7420303031"
+	vectorNonce      = "000102030405060708090a0b"
+	// vectorDerivedKey is scrypt(vectorPassword, vectorSalt) computed independently (Python's hashlib.scrypt)
+	vectorDerivedKey = "79c300d6849226443a252be572d537ae80b5a252b09dd16adc9e3aa4a1a3546d"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+	return data
+}
+
+func vectorKeyPair(t *testing.T) StarknetKeyPair {
+	t.Helper()
+	priv, ok := new(big.Int).SetString(vectorPrivateKey, 0)
+	require.True(t, ok)
+	return StarknetKeyPair{PrivateKey: priv, PublicKey: mustFelt(t, vectorPublicKey)}
+}
+
+func mustBytes(t *testing.T, hexStr string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(hexStr)
+	require.NoError(t, err)
+	return b
+}
+
+func TestDecryptStarknetKeyVector(t *testing.T) {
+	key, err := DecryptStarknetKey(readTestdata(t, "starknet_keystore.json"), vectorPassword)
+	require.NoError(t, err)
+	want := vectorKeyPair(t)
+	assert.Equal(t, 0, want.PrivateKey.Cmp(key.PrivateKey))
+	assert.True(t, want.PublicKey.Equal(key.PublicKey))
+}
+
+func TestEncryptStarknetKeyVector(t *testing.T) {
+	params := scryptParams{N: 1 << 12, R: scryptR, P: 6, DKLen: scryptDKLen}
+	out, err := encryptStarknetKey(vectorKeyPair(t), vectorPassword, params, mustBytes(t, vectorSalt), mustBytes(t, vectorNonce))
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(readTestdata(t, "starknet_keystore.json"))), string(out))
+}
+
+// TestStarknetKeystoreVectorLayout opens the vector with the independently derived key, pinning the AAD and
+// the privateKey || publicKey plaintext
+func TestStarknetKeystoreVectorLayout(t *testing.T) {
+	block, err := aes.NewCipher(mustBytes(t, vectorDerivedKey))
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	ciphertext := mustBytes(t, "0f14ccb9266113c4ed6dac0cbd8a9774444283e4e9abf490cdc827581d3d12ebb1e6be6de232c7515754767a8fae9f6ca7a5b43fdc6f6145f9c520a72d7af869bb4bc60f53413bb9719be5c9d7d3ab2a")
+	plaintext, err := aead.Open(nil, mustBytes(t, vectorNonce), ciphertext, []byte("oif-starknet keystore v1"))
+	require.NoError(t, err)
+	assert.Equal(t, "0"+strings.TrimPrefix(vectorPrivateKey, "0x")+"0"+strings.TrimPrefix(vectorPublicKey, "0x"), hex.EncodeToString(plaintext))
+}
+
+func TestStarknetKeystoreRoundTrip(t *testing.T) {
+	priv, ok := new(big.Int).SetString("0xc5b2fcab997346f3ea1c00b002ecf6f382c5f9c9659a3894eb783c5320f912", 0)
+	require.True(t, ok)
+	key, err := NewStarknetKeyPair(priv)
+	require.NoError(t, err)
+
+	out, err := EncryptStarknetKey(key, "pw", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	back, err := DecryptStarknetKey(out, "pw")
+	require.NoError(t, err)
+	assert.Equal(t, 0, priv.Cmp(back.PrivateKey))
+	assert.True(t, key.PublicKey.Equal(back.PublicKey))
+
+	again, err := EncryptStarknetKey(key, "pw", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	assert.NotEqual(t, out, again, "every keystore gets a fresh salt and nonce")
+}
+
+func TestDecryptStarknetKeyErrors(t *testing.T) {
+	vector := string(readTestdata(t, "starknet_keystore.json"))
+
+	_, err := DecryptStarknetKey([]byte(vector), "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	tampered := strings.Replace(vector, `"ciphertext": "0f`, `"ciphertext": "1f`, 1)
+	_, err = DecryptStarknetKey([]byte(tampered), vectorPassword)
+	assert.ErrorIs(t, err, keystore.ErrDecrypt, "the tag covers the ciphertext")
+
+	tests := []struct {
+		name    string
+		keyJSON string
+		wantErr string
+	}{
+		{name: "not_json", keyJSON: "{", wantErr: "invalid Starknet keystore"},
+		{name: "geth_keystore", keyJSON: string(readTestdata(t, "evm_keystore.json")), wantErr: "unsupported Starknet keystore version 3"},
+		{name: "other_cipher", keyJSON: strings.Replace(vector, "aes-256-gcm", "aes-128-ctr", 1), wantErr: `cipher "aes-128-ctr"`},
+		{name: "short_dklen", keyJSON: strings.Replace(vector, `"dklen": 32`, `"dklen": 16`, 1), wantErr: "unsupported scrypt dklen 16"},
+		{name: "bad_n", keyJSON: strings.Replace(vector, `"n": 4096`, `"n": 4095`, 1), wantErr: "invalid scrypt parameters"},
+		{name: "bad_nonce", keyJSON: strings.Replace(vector, vectorNonce, "0001", 1), wantErr: "invalid Starknet keystore nonce"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecryptStarknetKey([]byte(tt.keyJSON), vectorPassword)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEncryptStarknetKeyChecksPair(t *testing.T) {
+	key := vectorKeyPair(t)
+	key.PublicKey = mustFelt(t, "0x1234")
+	_, err := EncryptStarknetKey(key, "pw", keystore.LightScryptN, keystore.LightScryptP)
+	assert.ErrorContains(t, err, "is not the private key's")
+
+	_, err = NewStarknetKeyPair(new(big.Int).Set(starkCurveOrder))
+	assert.ErrorContains(t, err, "scalar range")
+	_, err = NewStarknetKeyPair(big.NewInt(0))
+	assert.ErrorContains(t, err, "scalar range")
+}
+
+// TestNewStarknetKeyPairDevnetAccounts checks the derivation against example.env's devnet pairs
+func TestNewStarknetKeyPairDevnetAccounts(t *testing.T) {
+	for privateKey, publicKey := range map[string]string{
+		vectorPrivateKey: vectorPublicKey,
+		"0x1800000000300000180000000000030000000000003006001800006600":     "0x2b191c2f3ecf685a91af7cf72a43e7b90e2e41220175de5c4f7498981b10053",
+		"0xc5b2fcab997346f3ea1c00b002ecf6f382c5f9c9659a3894eb783c5320f912": "0x33246ce85ebdc292e6a5c5b4dd51fab2757be34b8ffda847ca6925edf31cb67",
+	} {
+		priv, ok := new(big.Int).SetString(privateKey, 0)
+		require.True(t, ok)
+		key, err := NewStarknetKeyPair(priv)
+		require.NoError(t, err)
+		assert.Equal(t, publicKey, key.PublicKey.String())
+	}
+}
@@ -0,0 +1,199 @@
+// Package deploystate reads and writes the JSON files the deploy tools keep in state/deployment, found through
+// solverdir so it does not depend on the working directory.
+//
+// Every write marshals to a temp file in the target's directory, fsyncs it and renames it over the
+// target, so readers never see a truncated file. Writers also hold an exclusive lock on <file>.lock
+// for the whole write (or read-modify-write with Update), so tools run in parallel by the Makefile
+// cannot interleave their updates.
+//
+// What was deployed where lives in one typed file, deployment-state.json (see State); the other files are
+// per-tool records such as declarations and cost reports.
+//
+// Usage:
+//
+//	state, err := deploystate.ReadState(deploystate.Dir())
+//	err := deploystate.UpdateState(deploystate.Dir(), func(state *deploystate.State) error {
+//		state.EnsureNetwork("Starknet").SetHyperlane(address, deploy)
+//		return nil
+//	})
+//	err := deploystate.Update(path, func(ids *[]identity.Identity) error { ...; return nil })
+package deploystate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+const (
+	dirPerms  = 0o700
+	filePerms = 0o600
+)
+
+// Dir returns the absolute directory the deploy tools keep their state in, deployment/ in the state directory
+func Dir() string {
+	return solverdir.StatePath("deployment")
+}
+
+// Path returns the absolute path of the state file name
+func Path(name string) string {
+	return filepath.Join(Dir(), name)
+}
+
+// CorruptFileError reports a state file that exists but does not parse
+type CorruptFileError struct {
+	Path string
+	Err  error
+}
+
+// Error names the file and how to recover it
+func (e *CorruptFileError) Error() string {
+	return fmt.Sprintf("deployment state %s is corrupted (%v); restore it from a backup or delete it and rerun the deploy step that writes it", e.Path, e.Err)
+}
+
+// Unwrap returns the parse error
+func (e *CorruptFileError) Unwrap() error {
+	return e.Err
+}
+
+// ReadJSON reads path into v. A missing file returns an error wrapping os.ErrNotExist,
+// an unparsable one a *CorruptFileError
+func ReadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read deployment state %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &CorruptFileError{Path: path, Err: err}
+	}
+	return nil
+}
+
+// WriteJSON atomically replaces path with v as indented JSON while holding the file's lock
+func WriteJSON(path string, v any) error {
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeLocked(path, v)
+}
+
+// Lock takes the lock WriteJSON and Update hold on path, for tools that rewrite other shared files (such as
+// .env) with their own writer. The returned function releases it
+func Lock(path string) (unlock func(), err error) {
+	return lock(path)
+}
+
+// Update reads path into a T (the zero value when the file does not exist yet), applies fn and writes the
+// result back, all under the file's lock. A corrupted file is reported instead of being overwritten
+func Update[T any](path string, fn func(*T) error) error {
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var state T
+	if err := ReadJSON(path, &state); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := fn(&state); err != nil {
+		return err
+	}
+	return writeLocked(path, &state)
+}
+
+// Declaration is the <contract>-declaration.json written by the declare tools and read by the deploy tools
+type Declaration struct {
+	ClassHash       string `json:"classHash"`
+	DeclarationTime string `json:"declarationTime"`
+	NetworkName     string `json:"networkName"`
+	TransactionHash string `json:"transactionHash,omitempty"` // empty when the class was already declared
+}
+
+// WriteDeclaration records a declared class in path, stamping the declaration time
+func WriteDeclaration(path, networkName, classHash, txHash string) error {
+	if classHash == "" {
+		return fmt.Errorf("refusing to write %s without a class hash", path)
+	}
+	return WriteJSON(path, Declaration{
+		ClassHash:       classHash,
+		DeclarationTime: time.Now().Format(time.RFC3339),
+		NetworkName:     networkName,
+		TransactionHash: txHash,
+	})
+}
+
+// ReadDeclaration reads a declaration written by WriteDeclaration and checks it names a class hash
+func ReadDeclaration(path string) (Declaration, error) {
+	var declaration Declaration
+	if err := ReadJSON(path, &declaration); err != nil {
+		return Declaration{}, err
+	}
+	if declaration.ClassHash == "" {
+		return Declaration{}, fmt.Errorf("class hash not found in declaration file %s", path)
+	}
+	return declaration, nil
+}
+
+// lock creates the state directory and takes the exclusive lock of path
+func lock(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirPerms); err != nil {
+		return nil, fmt.Errorf("failed to create deployment state directory: %w", err)
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, filePerms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file for %s: %w", path, err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// writeLocked writes v to path through WriteFileAtomic
+func writeLocked(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal deployment state: %w", err)
+	}
+	return WriteFileAtomic(path, data, filePerms)
+}
+
+// WriteFileAtomic replaces path with data through a synced temp file in the same directory and a rename, so
+// readers see either the old or the new file and never a partial one
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %w", path, err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { tmp.Close(); os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temp file for %s: %w", path, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to chmod temp file for %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temp file for %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file for %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
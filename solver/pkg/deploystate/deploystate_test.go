@@ -0,0 +1,144 @@
+package deploystate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndReadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "starknet-hyperlane7683-deployment.json")
+	require.NoError(t, WriteJSON(path, map[string]string{"deployedAddress": "0x7683"}))
+
+	var state map[string]string
+	require.NoError(t, ReadJSON(path, &state))
+	assert.Equal(t, "0x7683", state["deployedAddress"])
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(filePerms), info.Mode().Perm())
+
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("OLD=1\n"), 0o600))
+
+	require.NoError(t, WriteFileAtomic(path, []byte("NEW=1\n"), 0o644))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "NEW=1\n", string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+
+	err = WriteFileAtomic(filepath.Join(t.TempDir(), "missing", ".env"), nil, 0o600)
+	assert.ErrorContains(t, err, "failed to create temp file")
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	var state map[string]string
+	err := ReadJSON(filepath.Join(dir, "missing.json"), &state)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(dir, "truncated.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"deployedAddress": "0x76`), filePerms))
+	err = ReadJSON(path, &state)
+	var corrupt *CorruptFileError
+	require.ErrorAs(t, err, &corrupt)
+	assert.Equal(t, path, corrupt.Path)
+	assert.ErrorContains(t, err, path)
+	assert.ErrorContains(t, err, "rerun the deploy step")
+}
+
+func TestUpdateRefusesCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{`), filePerms))
+
+	err := Update(path, func(state *map[string]string) error { return nil })
+	var corrupt *CorruptFileError
+	require.ErrorAs(t, err, &corrupt)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{", string(data), "a corrupted file is left for the user to inspect")
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hyperlane-addresses.json")
+	const writers = 16
+	const updates = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*updates)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for u := 0; u < updates; u++ {
+				errs <- Update(path, func(state *map[string]string) error {
+					if *state == nil {
+						*state = map[string]string{}
+					}
+					(*state)[fmt.Sprintf("network-%d-%d", w, u)] = fmt.Sprintf("0x%x", w*updates+u)
+					return nil
+				})
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var state map[string]string
+	require.NoError(t, ReadJSON(path, &state))
+	assert.Len(t, state, writers*updates, "no update was lost to a concurrent writer")
+}
+
+func TestDeclarationRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "starknet-hyperlane7683-declaration.json")
+
+	t.Run("fresh declare records the transaction", func(t *testing.T) {
+		require.NoError(t, WriteDeclaration(path, "Starknet", "0x5eb8", "0x23c8"))
+		declaration, err := ReadDeclaration(path)
+		require.NoError(t, err)
+		assert.Equal(t, "0x5eb8", declaration.ClassHash)
+		assert.Equal(t, "0x23c8", declaration.TransactionHash)
+		assert.Equal(t, "Starknet", declaration.NetworkName)
+		assert.NotEmpty(t, declaration.DeclarationTime)
+	})
+
+	t.Run("already declared has no transaction", func(t *testing.T) {
+		require.NoError(t, WriteDeclaration(path, "Starknet", "0x5eb8", ""))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "transactionHash")
+
+		declaration, err := ReadDeclaration(path)
+		require.NoError(t, err)
+		assert.Equal(t, "0x5eb8", declaration.ClassHash)
+	})
+
+	t.Run("a class hash is required", func(t *testing.T) {
+		assert.Error(t, WriteDeclaration(path, "Starknet", "", "0x23c8"))
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"networkName":"Starknet"}`), filePerms))
+		_, err := ReadDeclaration(path)
+		assert.ErrorContains(t, err, "class hash not found")
+	})
+}
@@ -0,0 +1,26 @@
+//go:build !unix
+
+package deploystate
+
+import (
+	"os"
+	"sync"
+)
+
+// fileLocks serializes writers within the process where flock is unavailable
+var fileLocks sync.Map
+
+// lockFile locks f's path for this process only
+func lockFile(f *os.File) error {
+	mu, _ := fileLocks.LoadOrStore(f.Name(), &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return nil
+}
+
+// unlockFile releases the lock taken by lockFile
+func unlockFile(f *os.File) error {
+	if mu, ok := fileLocks.Load(f.Name()); ok {
+		mu.(*sync.Mutex).Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package deploystate
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive flock, released by the kernel if the process dies
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+// unlockFile releases the flock
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,277 @@
+package deploystate
+
+// Unified deployment state
+// deployment-state.json records, per network, the Hyperlane7683 and mock ERC20 deployments and whether its routers
+// are enrolled, under a schemaVersion so later layouts can be migrated. Trees set up before it keep their state in
+// the legacy per-tool files (starknet-hyperlane7683-deployment.json, <network>-mock-erc20-deployment.json); those
+// are read in its place until the first UpdateState writes it, and are ignored from then on
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StateFile is the unified deployment state every deploy and setup tool reads and writes
+const StateFile = "deployment-state.json"
+
+// SchemaVersion is the layout of StateFile this build writes
+const SchemaVersion = 1
+
+const (
+	legacyHyperlaneFile   = "starknet-hyperlane7683-deployment.json"
+	legacyTokenFileSuffix = "-mock-erc20-deployment.json"
+	legacyHyperlaneOwner  = "Starknet"
+)
+
+// State is the deployment state of every network
+type State struct {
+	SchemaVersion int                      `json:"schemaVersion"`
+	Networks      map[string]*NetworkState `json:"networks"`
+
+	// tokenSources holds the file each network's tokens were read from, keyed by lower-case network name
+	tokenSources map[string]string
+}
+
+// NetworkState is what the deploy tools recorded for one network
+type NetworkState struct {
+	HyperlaneAddress string `json:"hyperlaneAddress,omitempty"`
+	// HyperlaneDeploy is set when a deploy tool deployed HyperlaneAddress, rather than it being a known deployment
+	HyperlaneDeploy          *ContractDeploy            `json:"hyperlaneDeploy,omitempty"`
+	Tokens                   map[string]TokenDeployment `json:"tokens,omitempty"` // keyed by token name
+	TokensDeploymentTime     string                     `json:"tokensDeploymentTime,omitempty"`
+	RouterEnrollmentComplete bool                       `json:"routerEnrollmentComplete,omitempty"`
+}
+
+// ContractDeploy is how a contract was deployed
+type ContractDeploy struct {
+	ClassHash       string `json:"classHash,omitempty"`
+	TransactionHash string `json:"transactionHash,omitempty"` // empty when the deploy was only predicted
+	Salt            string `json:"salt,omitempty"`
+	DeploymentTime  string `json:"deploymentTime"`
+}
+
+// TokenDeployment is a deployed mock ERC20
+type TokenDeployment struct {
+	Name      string `json:"name"`
+	Symbol    string `json:"symbol"`
+	Decimals  uint8  `json:"decimals"`
+	Address   string `json:"address"`
+	ClassHash string `json:"classHash,omitempty"` // Cairo networks only
+	Salt      string `json:"salt,omitempty"`      // CREATE2 salt of EVM tokens deployed with one
+}
+
+// StatePath returns the path of the unified state in dir
+func StatePath(dir string) string {
+	return filepath.Join(dir, StateFile)
+}
+
+// ReadState reads the deployment state in dir, from the legacy files when StateFile does not exist yet. A
+// missing state is empty, not an error
+func ReadState(dir string) (*State, error) {
+	path := StatePath(dir)
+	var state State
+	err := ReadJSON(path, &state)
+	if errors.Is(err, os.ErrNotExist) {
+		return migrateLegacy(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case state.SchemaVersion == 0:
+		return nil, &CorruptFileError{Path: path, Err: errors.New("no schemaVersion")}
+	case state.SchemaVersion > SchemaVersion:
+		return nil, fmt.Errorf("deployment state %s has schema version %d, newer than the %d this solver reads; update the solver",
+			path, state.SchemaVersion, SchemaVersion)
+	}
+	if state.Networks == nil {
+		state.Networks = map[string]*NetworkState{}
+	}
+	state.tokenSources = map[string]string{}
+	for name := range state.Networks {
+		state.tokenSources[strings.ToLower(name)] = path
+	}
+	return &state, nil
+}
+
+// UpdateState reads the deployment state in dir, applies fn and writes it back as StateFile, all under its
+// lock. The first update upgrades the legacy files into StateFile
+func UpdateState(dir string, fn func(*State) error) error {
+	path := StatePath(dir)
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := ReadState(dir)
+	if err != nil {
+		return err
+	}
+	if err := fn(state); err != nil {
+		return err
+	}
+	state.SchemaVersion = SchemaVersion
+	return writeLocked(path, state)
+}
+
+// Network returns the state recorded for name, matched case-insensitively; nil when there is none
+func (s *State) Network(name string) *NetworkState {
+	if network, ok := s.Networks[name]; ok {
+		return network
+	}
+	for key, network := range s.Networks {
+		if strings.EqualFold(key, name) {
+			return network
+		}
+	}
+	return nil
+}
+
+// EnsureNetwork returns the state recorded for name, adding an empty one when there is none
+func (s *State) EnsureNetwork(name string) *NetworkState {
+	if network := s.Network(name); network != nil {
+		return network
+	}
+	if s.Networks == nil {
+		s.Networks = map[string]*NetworkState{}
+	}
+	network := &NetworkState{}
+	s.Networks[name] = network
+	return network
+}
+
+// TokenSource returns the file the tokens of network name were read from, for messages; empty when none were
+func (s *State) TokenSource(name string) string {
+	return s.tokenSources[strings.ToLower(name)]
+}
+
+// SetHyperlane records a Hyperlane7683 deployment. A new address needs its routers enrolled again
+func (n *NetworkState) SetHyperlane(address string, deploy *ContractDeploy) {
+	if !strings.EqualFold(n.HyperlaneAddress, address) {
+		n.RouterEnrollmentComplete = false
+	}
+	n.HyperlaneAddress = address
+	n.HyperlaneDeploy = deploy
+}
+
+// MarkRoutersEnrolled records that the routers of the Hyperlane7683 at address are enrolled
+func (n *NetworkState) MarkRoutersEnrolled(address string) {
+	if !strings.EqualFold(n.HyperlaneAddress, address) {
+		n.SetHyperlane(address, nil)
+	}
+	n.RouterEnrollmentComplete = true
+}
+
+// RecordRouterEnrollment marks the routers of networkName's Hyperlane7683 at address enrolled in the deployment
+// state
+func RecordRouterEnrollment(networkName, address string) error {
+	return UpdateState(Dir(), func(state *State) error {
+		state.EnsureNetwork(networkName).MarkRoutersEnrolled(address)
+		return nil
+	})
+}
+
+// SetToken records token under its name, replacing a token of the same name in any case
+func (n *NetworkState) SetToken(token TokenDeployment) {
+	if n.Tokens == nil {
+		n.Tokens = map[string]TokenDeployment{}
+	}
+	for name := range n.Tokens {
+		if strings.EqualFold(name, token.Name) {
+			delete(n.Tokens, name)
+		}
+	}
+	n.Tokens[token.Name] = token
+	n.TokensDeploymentTime = time.Now().Format(time.RFC3339)
+}
+
+// Token returns the token recorded under name, matched case-insensitively
+func (n *NetworkState) Token(name string) (TokenDeployment, bool) {
+	if n == nil {
+		return TokenDeployment{}, false
+	}
+	for key, token := range n.Tokens {
+		if strings.EqualFold(key, name) {
+			return token, true
+		}
+	}
+	return TokenDeployment{}, false
+}
+
+// SortedTokens returns the recorded tokens in name order
+func (n *NetworkState) SortedTokens() []TokenDeployment {
+	if n == nil {
+		return nil
+	}
+	tokens := make([]TokenDeployment, 0, len(n.Tokens))
+	for _, token := range n.Tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Name < tokens[j].Name })
+	return tokens
+}
+
+// legacyHyperlane is starknet-hyperlane7683-deployment.json
+type legacyHyperlane struct {
+	ClassHash       string `json:"classHash"`
+	DeployedAddress string `json:"deployedAddress"`
+	TransactionHash string `json:"transactionHash"`
+	Salt            string `json:"salt"`
+	DeploymentTime  string `json:"deploymentTime"`
+}
+
+// legacyTokens is <network>-mock-erc20-deployment.json
+type legacyTokens struct {
+	NetworkName    string            `json:"networkName"`
+	DeploymentTime string            `json:"deploymentTime"`
+	Tokens         []TokenDeployment `json:"tokens"`
+}
+
+// migrateLegacy builds the state recorded in the legacy files of dir
+func migrateLegacy(dir string) (*State, error) {
+	state := &State{SchemaVersion: SchemaVersion, Networks: map[string]*NetworkState{}, tokenSources: map[string]string{}}
+
+	hyperlanePath := filepath.Join(dir, legacyHyperlaneFile)
+	var hyperlane legacyHyperlane
+	err := ReadJSON(hyperlanePath, &hyperlane)
+	switch {
+	case err == nil && hyperlane.DeployedAddress != "":
+		state.EnsureNetwork(legacyHyperlaneOwner).SetHyperlane(hyperlane.DeployedAddress, &ContractDeploy{
+			ClassHash:       hyperlane.ClassHash,
+			TransactionHash: hyperlane.TransactionHash,
+			Salt:            hyperlane.Salt,
+			DeploymentTime:  hyperlane.DeploymentTime,
+		})
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	// Glob returns the files in name order, so a network recorded twice keeps its last file's tokens
+	files, err := filepath.Glob(filepath.Join(dir, "*"+legacyTokenFileSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment state in %s: %w", dir, err)
+	}
+	for _, path := range files {
+		var deployment legacyTokens
+		if err := ReadJSON(path, &deployment); err != nil {
+			return nil, err
+		}
+		name := deployment.NetworkName
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(path), legacyTokenFileSuffix)
+		}
+		network := state.EnsureNetwork(name)
+		for _, token := range deployment.Tokens {
+			network.SetToken(token)
+		}
+		network.TokensDeploymentTime = deployment.DeploymentTime
+		state.tokenSources[strings.ToLower(name)] = path
+	}
+	return state, nil
+}
@@ -0,0 +1,115 @@
+package deploystate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyDir copies the legacy state files in testdata/legacy into a temp dir
+func legacyDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files, err := filepath.Glob(filepath.Join("testdata", "legacy", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.Base(file)), data, filePerms))
+	}
+	return dir
+}
+
+func TestReadStateMigratesLegacyFiles(t *testing.T) {
+	dir := legacyDir(t)
+
+	state, err := ReadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, state.SchemaVersion)
+	assert.Len(t, state.Networks, 2)
+
+	starknet := state.Network("starknet")
+	require.NotNil(t, starknet)
+	assert.Equal(t, "0x2d6b0b3a2fb5f0e0c3b1d7bc8a3f0e2ff0ed3c5e7b3a9f19c4a7e3d1b5c6a71", starknet.HyperlaneAddress)
+	require.NotNil(t, starknet.HyperlaneDeploy)
+	assert.Equal(t, "0x7683", starknet.HyperlaneDeploy.Salt)
+	assert.Equal(t, "0x4b1e9f0c2d7a3e5b8c6f1a0d9e2b4c7a5f3e1d8b6c0a9f2e4d7b1c3a5e8f0d2", starknet.HyperlaneDeploy.TransactionHash)
+	assert.False(t, starknet.RouterEnrollmentComplete)
+	dog, ok := starknet.Token("dogcoin")
+	require.True(t, ok)
+	assert.Equal(t, "0x1f0a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f", dog.ClassHash)
+	assert.Equal(t, filepath.Join(dir, "starknet-mock-erc20-deployment.json"), state.TokenSource("Starknet"))
+
+	base := state.Network("Base")
+	require.NotNil(t, base)
+	assert.Empty(t, base.HyperlaneAddress)
+	assert.Equal(t, "2025-09-30T14:04:03Z", base.TokensDeploymentTime)
+	tokens := base.SortedTokens()
+	require.Len(t, tokens, 2)
+	assert.Equal(t, TokenDeployment{Name: "USDC", Symbol: "USDC", Decimals: 6, Address: "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"}, tokens[1])
+
+	_, err = os.Stat(StatePath(dir))
+	assert.ErrorIs(t, err, os.ErrNotExist, "reads do not upgrade the legacy files")
+
+	empty, err := ReadState(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, empty.Networks)
+	assert.Nil(t, empty.Network("Base").SortedTokens())
+}
+
+func TestUpdateStateUpgradesLegacyFiles(t *testing.T) {
+	dir := legacyDir(t)
+
+	require.NoError(t, UpdateState(dir, func(state *State) error {
+		state.EnsureNetwork("Base").SetToken(TokenDeployment{Name: "dogcoin", Symbol: "DOG", Decimals: 18, Address: "0xd07"})
+		return nil
+	}))
+
+	var written map[string]any
+	require.NoError(t, ReadJSON(StatePath(dir), &written))
+	assert.EqualValues(t, SchemaVersion, written["schemaVersion"])
+
+	// The legacy files are left alone but no longer read
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base-mock-erc20-deployment.json"), []byte(`{"networkName":"Base","tokens":[]}`), filePerms))
+	state, err := ReadState(dir)
+	require.NoError(t, err)
+	base := state.Network("Base")
+	require.Len(t, base.Tokens, 2, "a token of the same name in another case is replaced")
+	dog, ok := base.Token("DogCoin")
+	require.True(t, ok)
+	assert.Equal(t, "0xd07", dog.Address)
+	assert.Equal(t, StatePath(dir), state.TokenSource("Base"))
+	assert.NotEmpty(t, state.Network("Starknet").HyperlaneAddress, "untouched networks are carried over")
+}
+
+func TestReadStateSchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(StatePath(dir), []byte(`{"networks":{}}`), filePerms))
+	_, err := ReadState(dir)
+	var corrupt *CorruptFileError
+	require.ErrorAs(t, err, &corrupt)
+	assert.ErrorContains(t, err, "no schemaVersion")
+
+	require.NoError(t, os.WriteFile(StatePath(dir), []byte(`{"schemaVersion":2,"networks":{}}`), filePerms))
+	_, err = ReadState(dir)
+	assert.ErrorContains(t, err, "has schema version 2, newer than the 1 this solver reads")
+	assert.Error(t, UpdateState(dir, func(*State) error { return nil }), "a newer state is not overwritten")
+}
+
+func TestRouterEnrollment(t *testing.T) {
+	var network NetworkState
+	network.MarkRoutersEnrolled("0x7683")
+	assert.Equal(t, "0x7683", network.HyperlaneAddress)
+	assert.True(t, network.RouterEnrollmentComplete)
+
+	network.SetHyperlane("0X7683", &ContractDeploy{TransactionHash: "0x01"})
+	assert.True(t, network.RouterEnrollmentComplete, "the same Hyperlane7683 stays enrolled")
+
+	network.SetHyperlane("0x7684", &ContractDeploy{TransactionHash: "0x02"})
+	assert.False(t, network.RouterEnrollmentComplete, "a new Hyperlane7683 needs its routers enrolled")
+}
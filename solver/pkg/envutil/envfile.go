@@ -0,0 +1,207 @@
+package envutil
+
+// .env file editing
+// UpdateEnvFile rewrites only the values of the keys it is given: every other byte of the file, including
+// comments, blank lines, `export` prefixes, inline comments after a value and CRLF line endings, is kept.
+// ReadEnvFile parses the same syntax, so a value written by one is read back unchanged by the other
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+)
+
+const (
+	// WriteEnvFlag asks a deploy tool to persist the addresses it deployed to .env
+	WriteEnvFlag = "--write-env"
+
+	// envFilePerms is used when UpdateEnvFile creates the file
+	envFilePerms = 0o600
+)
+
+// envLine is a parsed KEY=value assignment; line[start:end] is the raw value as written
+type envLine struct {
+	key        string
+	value      string
+	quote      byte // '"', '\'' or 0 for unquoted
+	start, end int
+}
+
+// parseEnvLine parses one line without its line ending. ok is false for blank lines, comments and
+// anything that is not an assignment
+func parseEnvLine(line string) (envLine, bool) {
+	i := len(line) - len(strings.TrimLeft(line, " \t"))
+	if rest, found := strings.CutPrefix(line[i:], "export "); found {
+		i = len(line) - len(strings.TrimLeft(rest, " \t"))
+	}
+	eq := strings.IndexByte(line[i:], '=')
+	if eq <= 0 || strings.HasPrefix(line[i:], "#") {
+		return envLine{}, false
+	}
+	key := strings.TrimSpace(line[i : i+eq])
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return envLine{}, false
+	}
+
+	start := i + eq + 1
+	for start < len(line) && (line[start] == ' ' || line[start] == '\t') {
+		start++
+	}
+	parsed := envLine{key: key, start: start, end: start}
+	if start == len(line) || (line[start] == '#' && start > i+eq+1) {
+		return parsed, true
+	}
+
+	switch q := line[start]; q {
+	case '"', '\'':
+		var b strings.Builder
+		for j := start + 1; j < len(line); j++ {
+			c := line[j]
+			if c == q {
+				parsed.value, parsed.quote, parsed.end = b.String(), q, j+1
+				return parsed, true
+			}
+			if q == '"' && c == '\\' && j+1 < len(line) {
+				j++
+				switch line[j] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(line[j])
+				}
+				continue
+			}
+			b.WriteByte(c)
+		}
+		// An unterminated quote is taken literally up to the end of the line
+		parsed.value, parsed.end = line[start:], len(line)
+		return parsed, true
+	default:
+		// An unquoted value ends at an inline comment: a '#' preceded by whitespace
+		end := len(line)
+		for j := start; j < len(line); j++ {
+			if line[j] == '#' && j > start && (line[j-1] == ' ' || line[j-1] == '\t') {
+				end = j
+				break
+			}
+		}
+		raw := strings.TrimRight(line[start:end], " \t")
+		parsed.value, parsed.end = raw, start+len(raw)
+		return parsed, true
+	}
+}
+
+// formatEnvValue renders value for a .env line, keeping quote when the old value was quoted and
+// quoting when the value would otherwise be misread
+func formatEnvValue(value string, quote byte) string {
+	if quote == '\'' && !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+	if quote == 0 && value != "" && !strings.ContainsAny(value, " \t#\"'\\\n\r") {
+		return value
+	}
+	if quote == 0 && value == "" {
+		return ""
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+	return `"` + r.Replace(value) + `"`
+}
+
+// splitLineEnding separates a line from its "\n" or "\r\n" ending
+func splitLineEnding(line string) (body, eol string) {
+	if body, found := strings.CutSuffix(line, "\r\n"); found {
+		return body, "\r\n"
+	}
+	if body, found := strings.CutSuffix(line, "\n"); found {
+		return body, "\n"
+	}
+	return line, ""
+}
+
+// ReadEnvFile parses the KEY=value assignments of a .env file. When a key is assigned more than once
+// the last assignment wins, as with godotenv
+func ReadEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file %s: %w", path, err)
+	}
+	values := make(map[string]string)
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		body, _ := splitLineEnding(line)
+		if parsed, ok := parseEnvLine(body); ok {
+			values[parsed.key] = parsed.value
+		}
+	}
+	return values, nil
+}
+
+// UpdateEnvFile sets each key of updates in the .env file at path, creating the file if it does not exist.
+// Existing assignments keep their position, prefix, quoting style and inline comment; new keys are appended
+// in sorted order. The file is replaced atomically, under the same <path>.lock as the deployment state, so
+// tools updating .env in parallel do not drop each other's keys
+func UpdateEnvFile(path string, updates map[string]string) error {
+	unlock, err := deploystate.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	perm := os.FileMode(envFilePerms)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read env file %s: %w", path, err)
+	default:
+		if info, statErr := os.Stat(path); statErr == nil {
+			perm = info.Mode().Perm()
+		}
+	}
+
+	content := string(data)
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+
+	var out strings.Builder
+	seen := make(map[string]bool, len(updates))
+	for _, line := range strings.SplitAfter(content, "\n") {
+		body, eol := splitLineEnding(line)
+		parsed, ok := parseEnvLine(body)
+		value, update := updates[parsed.key]
+		if !ok || !update {
+			out.WriteString(line)
+			continue
+		}
+		seen[parsed.key] = true
+		out.WriteString(body[:parsed.start])
+		out.WriteString(formatEnvValue(value, parsed.quote))
+		out.WriteString(body[parsed.end:])
+		out.WriteString(eol)
+	}
+
+	missing := make([]string, 0, len(updates))
+	for key := range updates {
+		if !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 && out.Len() > 0 && !strings.HasSuffix(out.String(), "\n") {
+		out.WriteString(newline)
+	}
+	for _, key := range missing {
+		out.WriteString(key + "=" + formatEnvValue(updates[key], 0) + newline)
+	}
+
+	return deploystate.WriteFileAtomic(path, []byte(out.String()), perm)
+}
@@ -0,0 +1,150 @@
+package envutil
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEnvFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		key   string
+		value string
+	}{
+		{"plain", "KEY=value", "KEY", "value"},
+		{"export prefix", "export KEY=value", "KEY", "value"},
+		{"inline comment", "KEY=value # deployed by forge", "KEY", "value"},
+		{"hash without space is part of the value", "KEY=abc#def", "KEY", "abc#def"},
+		{"double quoted", `KEY="a value # not a comment"`, "KEY", "a value # not a comment"},
+		{"double quoted escapes", `KEY="say \"hi\"\n"`, "KEY", "say \"hi\"\n"},
+		{"single quoted is literal", `KEY='a\nb'`, "KEY", `a\nb`},
+		{"equals in value", "RPC_URL=https://rpc.example/?key=abc", "RPC_URL", "https://rpc.example/?key=abc"},
+		{"empty", "KEY=", "KEY", ""},
+		{"empty with comment", "KEY= # unset", "KEY", ""},
+		{"spaces around", "  KEY = value  ", "KEY", "value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			require.NoError(t, os.WriteFile(path, []byte("# header\n\n"+tt.line+"\n"), 0o600))
+			values, err := ReadEnvFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{tt.key: tt.value}, values)
+		})
+	}
+}
+
+func TestUpdateEnvFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  string
+		updates map[string]string
+		after   string
+	}{
+		{
+			name:    "untouched lines are kept byte for byte",
+			before:  "# Networks\nexport RPC=http://a  # local\n\nKEY=old\nOTHER = 'x'\n",
+			updates: map[string]string{"KEY": "0x1"},
+			after:   "# Networks\nexport RPC=http://a  # local\n\nKEY=0x1\nOTHER = 'x'\n",
+		},
+		{
+			name:    "inline comment and export prefix survive",
+			before:  "export KEY=old # set by deploy\n",
+			updates: map[string]string{"KEY": "0x2"},
+			after:   "export KEY=0x2 # set by deploy\n",
+		},
+		{
+			name:    "quoting style is kept",
+			before:  "A=\"old\"\nB='old'\n",
+			updates: map[string]string{"A": "new value", "B": "0x3"},
+			after:   "A=\"new value\"\nB='0x3'\n",
+		},
+		{
+			name:    "values that need quoting get quoted",
+			before:  "KEY=old\n",
+			updates: map[string]string{"KEY": `a "b" # c`},
+			after:   "KEY=\"a \\\"b\\\" # c\"\n",
+		},
+		{
+			name:    "CRLF endings are kept and used for new keys",
+			before:  "KEY=old\r\nOTHER=1\r\n",
+			updates: map[string]string{"KEY": "new", "NEW": "2"},
+			after:   "KEY=new\r\nOTHER=1\r\nNEW=2\r\n",
+		},
+		{
+			name:    "missing keys are appended after a file without a final newline",
+			before:  "A=1",
+			updates: map[string]string{"C": "3", "B": "2"},
+			after:   "A=1\nB=2\nC=3\n",
+		},
+		{
+			name:    "every assignment of a key is updated",
+			before:  "KEY=1\n# KEY=commented\nKEY=2\n",
+			updates: map[string]string{"KEY": "3"},
+			after:   "KEY=3\n# KEY=commented\nKEY=3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			require.NoError(t, os.WriteFile(path, []byte(tt.before), 0o640))
+			require.NoError(t, UpdateEnvFile(path, tt.updates))
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.after, string(data))
+
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o640), info.Mode().Perm(), "the file mode is kept")
+
+			values, err := ReadEnvFile(path)
+			require.NoError(t, err)
+			for key, value := range tt.updates {
+				assert.Equal(t, value, values[key], "%s reads back unchanged", key)
+			}
+		})
+	}
+}
+
+func TestUpdateEnvFileCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, UpdateEnvFile(path, map[string]string{"STARKNET_HYPERLANE_ADDRESS": "0x7683"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "STARKNET_HYPERLANE_ADDRESS=0x7683\n", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(envFilePerms), info.Mode().Perm())
+}
+
+func TestUpdateEnvFileConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("IS_DEVNET=false\n"), 0o600))
+
+	networks := []string{"ETHEREUM", "OPTIMISM", "ARBITRUM", "BASE"}
+	var wg sync.WaitGroup
+	for _, network := range networks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, UpdateEnvFile(path, map[string]string{network + "_DOG_COIN_ADDRESS": "0x" + network}))
+		}()
+	}
+	wg.Wait()
+
+	values, err := ReadEnvFile(path)
+	require.NoError(t, err)
+	assert.Len(t, values, len(networks)+1, "no writer drops another's key")
+	for _, network := range networks {
+		assert.Equal(t, "0x"+network, values[network+"_DOG_COIN_ADDRESS"])
+	}
+}
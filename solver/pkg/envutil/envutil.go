@@ -3,30 +3,35 @@ package envutil
 import (
 	"fmt"
 	"os"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 const (
 	trueValue = "true"
+
+	// Anvil's default accounts 1 and 3, used for Alice and the solver when no address is configured
+	defaultAlicePublicKey  = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	defaultSolverPublicKey = "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"
 )
 
 // GetConditionalEnv gets an environment variable based on IS_DEVNET flag
 // If IS_DEVNET=true, uses LOCAL_* version, otherwise uses regular version
 func GetConditionalEnv(key, defaultValue string) string {
-	isDevnet := os.Getenv("IS_DEVNET")
-
-	var targetKey string
-	if isDevnet == trueValue {
-		targetKey = "LOCAL_" + key
-	} else {
-		targetKey = key
-	}
-
-	if value := os.Getenv(targetKey); value != "" {
+	if value := os.Getenv(ConditionalKey(key)); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
+// ConditionalKey returns the variable GetConditionalEnv reads for key: LOCAL_<key> if IS_DEVNET=true, key otherwise
+func ConditionalKey(key string) string {
+	if os.Getenv("IS_DEVNET") == trueValue {
+		return "LOCAL_" + key
+	}
+	return key
+}
+
 // GetConditionalAccountEnv gets account-related environment variables based on IS_DEVNET flag
 // This is a convenience function for account keys and addresses
 func GetConditionalAccountEnv(key string) string {
@@ -179,7 +184,7 @@ func GetStarknetRPCURL() string {
 
 // GetAlicePublicKey returns Alice's EVM public key based on IS_DEVNET flag
 func GetAlicePublicKey() string {
-	return GetConditionalEnv("ALICE_PUB_KEY", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	return GetConditionalEnv("ALICE_PUB_KEY", defaultAlicePublicKey)
 }
 
 // GetAlicePrivateKey returns Alice's EVM private key based on IS_DEVNET flag
@@ -197,6 +202,25 @@ func GetSolverPrivateKey() string {
 	return GetConditionalAccountEnv("SOLVER_PRIVATE_KEY")
 }
 
+// Recipient is a named EVM account funded by the tools
+type Recipient struct {
+	Name    string
+	Address common.Address
+}
+
+// GetEVMRecipients returns the EVM accounts to fund (Alice and the solver) based on IS_DEVNET flag. The solver
+// address is the one the solver checks its balances with, falling back to anvil's account 3 when unset
+func GetEVMRecipients() []Recipient {
+	solver := GetSolverPublicKey()
+	if solver == "" {
+		solver = defaultSolverPublicKey
+	}
+	return []Recipient{
+		{Name: "Alice", Address: common.HexToAddress(GetAlicePublicKey())},
+		{Name: "Solver", Address: common.HexToAddress(solver)},
+	}
+}
+
 // Ztarknet helper functions (testnet-only, no LOCAL_ variants)
 
 // GetZtarknetAliceAddress returns Alice's Ztarknet address (testnet-only)
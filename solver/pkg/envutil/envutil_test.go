@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,6 +54,14 @@ func TestGetConditionalEnv(t *testing.T) {
 	})
 }
 
+func TestConditionalKey(t *testing.T) {
+	t.Setenv("IS_DEVNET", "true")
+	assert.Equal(t, "LOCAL_SOLVER_PRIVATE_KEY", ConditionalKey("SOLVER_PRIVATE_KEY"))
+
+	t.Setenv("IS_DEVNET", "false")
+	assert.Equal(t, "SOLVER_PRIVATE_KEY", ConditionalKey("SOLVER_PRIVATE_KEY"))
+}
+
 func TestGetConditionalUint64(t *testing.T) {
 	t.Run("Get conditional uint64 with IS_DEVNET=true", func(t *testing.T) {
 		t.Setenv("IS_DEVNET", "true")
@@ -385,6 +394,30 @@ func TestGetAlicePublicKey(t *testing.T) {
 	})
 }
 
+func TestGetEVMRecipients(t *testing.T) {
+	t.Run("Uses LOCAL addresses when forking", func(t *testing.T) {
+		t.Setenv("IS_DEVNET", "true")
+		t.Setenv("LOCAL_ALICE_PUB_KEY", "0x00000000000000000000000000000000000000a1")
+		t.Setenv("LOCAL_SOLVER_PUB_KEY", "0x00000000000000000000000000000000000000b2")
+
+		assert.Equal(t, []Recipient{
+			{Name: "Alice", Address: common.HexToAddress("0xa1")},
+			{Name: "Solver", Address: common.HexToAddress("0xb2")},
+		}, GetEVMRecipients())
+	})
+
+	t.Run("Falls back to anvil accounts when not set", func(t *testing.T) {
+		t.Setenv("IS_DEVNET", "false")
+		t.Setenv("ALICE_PUB_KEY", "")
+		t.Setenv("SOLVER_PUB_KEY", "")
+
+		assert.Equal(t, []Recipient{
+			{Name: "Alice", Address: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")},
+			{Name: "Solver", Address: common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")},
+		}, GetEVMRecipients())
+	})
+}
+
 func TestGetAlicePrivateKey(t *testing.T) {
 	t.Run("Uses LOCAL version when forking", func(t *testing.T) {
 		t.Setenv("IS_DEVNET", "true")
@@ -0,0 +1,221 @@
+package erc2612
+
+// Module: EIP-2612 permit helpers
+// - Reads name(), nonces(owner) and DOMAIN_SEPARATOR() from the token
+// - Builds the Permit typed data and signs it off-chain with go-ethereum's apitypes
+// - Packs permit(owner,spender,value,deadline,v,r,s) for whichever account submits it
+// A token whose permit reads revert, or whose domain is not the OpenZeppelin ERC20Permit one, is reported as
+// ErrUnsupported so callers can fall back to approve()
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const (
+	// domainVersion is the EIP-712 version OpenZeppelin's ERC20Permit signs with
+	domainVersion = "1"
+
+	// permitABI is the part of an EIP-2612 token the helpers call
+	permitABI = `[
+		{"inputs":[],"name":"name","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"},
+		{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"nonces","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+		{"inputs":[],"name":"DOMAIN_SEPARATOR","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},
+		{"inputs":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"uint256","name":"deadline","type":"uint256"},{"internalType":"uint8","name":"v","type":"uint8"},{"internalType":"bytes32","name":"r","type":"bytes32"},{"internalType":"bytes32","name":"s","type":"bytes32"}],"name":"permit","outputs":[],"stateMutability":"nonpayable","type":"function"}
+	]`
+)
+
+// ErrUnsupported is returned for tokens that do not implement EIP-2612
+var ErrUnsupported = errors.New("token does not implement EIP-2612 permit")
+
+var parsedABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(permitABI))
+	if err != nil {
+		panic(fmt.Sprintf("invalid EIP-2612 ABI: %v", err))
+	}
+	return parsed
+}()
+
+// Domain is a token's EIP-712 domain
+type Domain struct {
+	Name    string
+	ChainID *big.Int
+	Token   common.Address
+}
+
+// Permit is the EIP-2612 Permit message
+type Permit struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// Signature is a permit signature split the way permit() takes it
+type Signature struct {
+	V uint8
+	R [32]byte
+	S [32]byte
+}
+
+// ReadDomain reads the token's name and checks its DOMAIN_SEPARATOR() against the one the helpers sign for
+func ReadDomain(ctx context.Context, caller ethereum.ContractCaller, chainID *big.Int, token common.Address) (Domain, error) {
+	var name string
+	if err := call(ctx, caller, token, "name", &name); err != nil {
+		return Domain{}, err
+	}
+	var onchain [32]byte
+	if err := call(ctx, caller, token, "DOMAIN_SEPARATOR", &onchain); err != nil {
+		return Domain{}, err
+	}
+	domain := Domain{Name: name, ChainID: chainID, Token: token}
+	computed, err := DomainSeparator(domain)
+	if err != nil {
+		return Domain{}, err
+	}
+	if computed != onchain {
+		return Domain{}, fmt.Errorf("%w: DOMAIN_SEPARATOR %s is not the one of name %q version %s", ErrUnsupported,
+			common.Hash(onchain).Hex(), name, domainVersion)
+	}
+	return domain, nil
+}
+
+// Nonce reads nonces(owner), the nonce owner's next permit must carry
+func Nonce(ctx context.Context, caller ethereum.ContractCaller, token, owner common.Address) (*big.Int, error) {
+	var nonce *big.Int
+	if err := call(ctx, caller, token, "nonces", &nonce, owner); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// TypedData returns the EIP-712 typed data of permit under domain
+func TypedData(domain Domain, permit Permit) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain: apitypes.TypedDataDomain{
+			Name:              domain.Name,
+			Version:           domainVersion,
+			ChainId:           (*math.HexOrDecimal256)(domain.ChainID),
+			VerifyingContract: domain.Token.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"owner":    permit.Owner.Hex(),
+			"spender":  permit.Spender.Hex(),
+			"value":    (*math.HexOrDecimal256)(permit.Value),
+			"nonce":    (*math.HexOrDecimal256)(permit.Nonce),
+			"deadline": (*math.HexOrDecimal256)(permit.Deadline),
+		},
+	}
+}
+
+// DomainSeparator hashes domain the way OpenZeppelin's EIP712 does
+func DomainSeparator(domain Domain) (common.Hash, error) {
+	typedData := TypedData(domain, Permit{})
+	separator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash the EIP-712 domain: %w", err)
+	}
+	return common.BytesToHash(separator), nil
+}
+
+// Sign signs permit under domain with key, which must be the owner's
+func Sign(domain Domain, permit Permit, key *ecdsa.PrivateKey) (Signature, error) {
+	if signer := crypto.PubkeyToAddress(key.PublicKey); signer != permit.Owner {
+		return Signature{}, fmt.Errorf("key of %s cannot sign a permit for %s", signer.Hex(), permit.Owner.Hex())
+	}
+	digest, _, err := apitypes.TypedDataAndHash(TypedData(domain, permit))
+	if err != nil {
+		return Signature{}, fmt.Errorf("failed to hash the permit: %w", err)
+	}
+	sig, err := crypto.Sign(digest, key)
+	if err != nil {
+		return Signature{}, fmt.Errorf("failed to sign the permit: %w", err)
+	}
+	var out Signature
+	copy(out.R[:], sig[:32])
+	copy(out.S[:], sig[32:64])
+	out.V = sig[64] + 27
+	return out, nil
+}
+
+// Pack encodes the permit() call submitting permit with sig
+func Pack(permit Permit, sig Signature) ([]byte, error) {
+	data, err := parsedABI.Pack("permit", permit.Owner, permit.Spender, permit.Value, permit.Deadline, sig.V, sig.R, sig.S)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack permit: %w", err)
+	}
+	return data, nil
+}
+
+// SignedCall reads what a permit from the owner of key needs, signs one letting spender take value until
+// deadline and returns the permit() calldata. It returns ErrUnsupported when the token has no permit
+func SignedCall(ctx context.Context, caller ethereum.ContractCaller, chainID *big.Int, token, spender common.Address,
+	value, deadline *big.Int, key *ecdsa.PrivateKey) ([]byte, error) {
+	domain, err := ReadDomain(ctx, caller, chainID, token)
+	if err != nil {
+		return nil, err
+	}
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := Nonce(ctx, caller, token, owner)
+	if err != nil {
+		return nil, err
+	}
+	permit := Permit{Owner: owner, Spender: spender, Value: value, Nonce: nonce, Deadline: deadline}
+	sig, err := Sign(domain, permit, key)
+	if err != nil {
+		return nil, err
+	}
+	return Pack(permit, sig)
+}
+
+// call runs a view of the token into out. A revert or an empty result means the view does not exist
+func call(ctx context.Context, caller ethereum.ContractCaller, token common.Address, method string, out interface{}, args ...interface{}) error {
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return fmt.Errorf("%w: %s() failed: %v", ErrUnsupported, method, err)
+		}
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	if len(result) == 0 {
+		return fmt.Errorf("%w: %s() returned nothing", ErrUnsupported, method)
+	}
+	if err := parsedABI.UnpackIntoInterface(out, method, result); err != nil {
+		return fmt.Errorf("%w: failed to decode %s(): %v", ErrUnsupported, method, err)
+	}
+	return nil
+}
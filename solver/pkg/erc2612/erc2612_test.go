@@ -0,0 +1,137 @@
+package erc2612
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/evmtest"
+)
+
+var (
+	testToken   = common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	testSpender = common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
+	testChainID = big.NewInt(31337)
+	testDomain  = Domain{Name: "DogCoin", ChainID: testChainID, Token: testToken}
+)
+
+// referenceSeparator is OpenZeppelin's EIP712 domain separator, hashed by hand
+func referenceSeparator(d Domain) common.Hash {
+	return crypto.Keccak256Hash(
+		crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte(d.Name)),
+		crypto.Keccak256([]byte("1")),
+		common.LeftPadBytes(d.ChainID.Bytes(), common.HashLength),
+		common.LeftPadBytes(d.Token.Bytes(), common.HashLength),
+	)
+}
+
+// fakeToken answers the permit views by selector; a missing selector reverts
+type fakeToken map[string][]byte
+
+func (f fakeToken) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	for name, method := range parsedABI.Methods {
+		if string(msg.Data[:4]) == string(method.ID) {
+			if out, ok := f[name]; ok {
+				return out, nil
+			}
+		}
+	}
+	return nil, evmtest.RevertError{}
+}
+
+func permitToken(t *testing.T, d Domain, nonce int64) fakeToken {
+	t.Helper()
+	name, err := parsedABI.Methods["name"].Outputs.Pack(d.Name)
+	require.NoError(t, err)
+	nonces, err := parsedABI.Methods["nonces"].Outputs.Pack(big.NewInt(nonce))
+	require.NoError(t, err)
+	return fakeToken{"name": name, "nonces": nonces, "DOMAIN_SEPARATOR": referenceSeparator(d).Bytes()}
+}
+
+func TestDomainSeparator(t *testing.T) {
+	got, err := DomainSeparator(testDomain)
+	require.NoError(t, err)
+	assert.Equal(t, referenceSeparator(testDomain), got)
+}
+
+func TestSign(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	permit := Permit{
+		Owner:    crypto.PubkeyToAddress(key.PublicKey),
+		Spender:  testSpender,
+		Value:    new(big.Int).Lsh(big.NewInt(1), 255),
+		Nonce:    big.NewInt(3),
+		Deadline: big.NewInt(1_900_000_000),
+	}
+
+	sig, err := Sign(testDomain, permit, key)
+	require.NoError(t, err)
+	assert.Contains(t, []uint8{27, 28}, sig.V)
+
+	digest, _, err := apitypes.TypedDataAndHash(TypedData(testDomain, permit))
+	require.NoError(t, err)
+	raw := append(append(sig.R[:], sig.S[:]...), sig.V-27)
+	pub, err := crypto.SigToPub(digest, raw)
+	require.NoError(t, err)
+	assert.Equal(t, permit.Owner, crypto.PubkeyToAddress(*pub))
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = Sign(testDomain, permit, other)
+	assert.ErrorContains(t, err, "cannot sign a permit")
+}
+
+func TestSignedCall(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	deadline := big.NewInt(1_900_000_000)
+
+	t.Run("permit token", func(t *testing.T) {
+		data, err := SignedCall(context.Background(), permitToken(t, testDomain, 7), testChainID, testToken, testSpender, big.NewInt(1001), deadline, key)
+		require.NoError(t, err)
+		args, err := parsedABI.Methods["permit"].Inputs.Unpack(data[4:])
+		require.NoError(t, err)
+		assert.Equal(t, owner, args[0])
+		assert.Equal(t, testSpender, args[1])
+		assert.Equal(t, big.NewInt(1001), args[2])
+		assert.Equal(t, deadline, args[3])
+	})
+
+	t.Run("plain ERC20", func(t *testing.T) {
+		token := permitToken(t, testDomain, 0)
+		delete(token, "DOMAIN_SEPARATOR")
+		_, err := SignedCall(context.Background(), token, testChainID, testToken, testSpender, big.NewInt(1), deadline, key)
+		assert.ErrorIs(t, err, ErrUnsupported)
+	})
+
+	t.Run("foreign domain", func(t *testing.T) {
+		token := permitToken(t, testDomain, 0)
+		token["DOMAIN_SEPARATOR"] = referenceSeparator(Domain{Name: "DogCoin", ChainID: big.NewInt(1), Token: testToken}).Bytes()
+		_, err := SignedCall(context.Background(), token, testChainID, testToken, testSpender, big.NewInt(1), deadline, key)
+		assert.ErrorIs(t, err, ErrUnsupported)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		down := errors.New("connection refused")
+		_, err := ReadDomain(context.Background(), failingCaller{down}, testChainID, testToken)
+		assert.ErrorIs(t, err, down)
+		assert.NotErrorIs(t, err, ErrUnsupported)
+	})
+}
+
+type failingCaller struct{ err error }
+
+func (f failingCaller) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return nil, f.err
+}
@@ -0,0 +1,190 @@
+package etherscan
+
+// Module: Etherscan contract verification
+// - Submits standard-json-input sources to the Etherscan v2 API (verifysourcecode), one endpoint for every chain
+// - Polls checkverifystatus until the verification passes or fails
+// - Builds the standard-json-input from a Forge artifact's metadata and the sources on disk (see standard_json.go)
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultURL is the Etherscan v2 API, which serves every chain it indexes by chainid
+	DefaultURL = "https://api.etherscan.io/v2/api"
+	// DefaultPollInterval is how often a pending verification is checked
+	DefaultPollInterval = 5 * time.Second
+
+	// standardJSONFormat is the codeformat of standard-json-input submissions
+	standardJSONFormat = "solidity-standard-json-input"
+)
+
+// ErrAlreadyVerified is returned by Submit when the contract's source is already verified
+var ErrAlreadyVerified = errors.New("contract source code already verified")
+
+// Client talks to an Etherscan-compatible API
+type Client struct {
+	// URL is the API endpoint; empty is DefaultURL
+	URL    string
+	APIKey string
+	// HTTP is the client requests are sent with; nil is http.DefaultClient
+	HTTP *http.Client
+	// PollInterval is how often Wait checks a pending verification; zero is DefaultPollInterval
+	PollInterval time.Duration
+}
+
+// VerifyRequest is a contract to verify from standard-json-input sources
+type VerifyRequest struct {
+	ChainID string
+	Address common.Address
+	// ContractName is the fully qualified name, such as src/MockERC20.sol:MockERC20
+	ContractName string
+	// CompilerVersion is the solc long version, such as v0.8.25+commit.b61c2a91
+	CompilerVersion string
+	StandardJSON    []byte
+	// ConstructorArgs are the ABI-encoded constructor arguments appended to the creation code
+	ConstructorArgs []byte
+}
+
+// Status is the state of a verification
+type Status struct {
+	Pending  bool
+	Verified bool
+	// Message is the API's result, such as "Pass - Verified" or "Fail - Unable to verify"
+	Message string
+}
+
+// response is the envelope of every API answer; result is the GUID, the status or an error message
+type response struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+// Submit submits req for verification and returns the GUID to check its status with
+func (c *Client) Submit(ctx context.Context, req VerifyRequest) (string, error) {
+	form := url.Values{
+		"module":                {"contract"},
+		"action":                {"verifysourcecode"},
+		"apikey":                {c.APIKey},
+		"chainid":               {req.ChainID},
+		"codeformat":            {standardJSONFormat},
+		"sourceCode":            {string(req.StandardJSON)},
+		"contractaddress":       {req.Address.Hex()},
+		"contractname":          {req.ContractName},
+		"compilerversion":       {req.CompilerVersion},
+		"constructorArguements": {hex.EncodeToString(req.ConstructorArgs)}, // sic, the API's spelling
+	}
+	query := url.Values{"chainid": {req.ChainID}}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(query), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("verifysourcecode failed: %w", err)
+	}
+	if resp.Status != "1" {
+		if strings.Contains(strings.ToLower(resp.Result), "already verified") {
+			return "", ErrAlreadyVerified
+		}
+		return "", fmt.Errorf("verifysourcecode rejected %s: %s", req.Address.Hex(), resp.Result)
+	}
+	return resp.Result, nil
+}
+
+// Status checks the verification guid on chainID
+func (c *Client) Status(ctx context.Context, chainID, guid string) (Status, error) {
+	query := url.Values{
+		"chainid": {chainID},
+		"module":  {"contract"},
+		"action":  {"checkverifystatus"},
+		"guid":    {guid},
+		"apikey":  {c.APIKey},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint(query), nil)
+	if err != nil {
+		return Status{}, err
+	}
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return Status{}, fmt.Errorf("checkverifystatus failed: %w", err)
+	}
+	return parseStatus(resp.Result), nil
+}
+
+// Wait polls the verification guid until it is no longer pending
+func (c *Client) Wait(ctx context.Context, chainID, guid string) (Status, error) {
+	interval := c.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status, err := c.Status(ctx, chainID, guid)
+		if err != nil || !status.Pending {
+			return status, err
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseStatus reads a checkverifystatus result
+func parseStatus(result string) Status {
+	lower := strings.ToLower(result)
+	return Status{
+		Pending:  strings.Contains(lower, "pending"),
+		Verified: strings.HasPrefix(lower, "pass") || strings.Contains(lower, "already verified"),
+		Message:  result,
+	}
+}
+
+func (c *Client) endpoint(query url.Values) string {
+	base := c.URL
+	if base == "" {
+		base = DefaultURL
+	}
+	return base + "?" + query.Encode()
+}
+
+func (c *Client) do(req *http.Request) (response, error) {
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return response{}, err
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return response{}, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return response{}, fmt.Errorf("HTTP %d: %s", httpResp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var resp response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return response{}, fmt.Errorf("invalid response %q: %w", body, err)
+	}
+	return resp, nil
+}
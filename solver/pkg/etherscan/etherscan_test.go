@@ -0,0 +1,126 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRequest = VerifyRequest{
+	ChainID:         "84532",
+	Address:         common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3"),
+	ContractName:    "src/MockERC20.sol:MockERC20",
+	CompilerVersion: "v0.8.25+commit.b61c2a91",
+	StandardJSON:    []byte(`{"language":"Solidity"}`),
+	ConstructorArgs: []byte{0x01, 0x02},
+}
+
+// mockAPI serves verifysourcecode with submit and checkverifystatus with the statuses in turn, repeating the last
+func mockAPI(t *testing.T, submit response, statuses ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var checks atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, testRequest.ChainID, r.URL.Query().Get("chainid"))
+		assert.Equal(t, "key", r.Form.Get("apikey"))
+		var resp response
+		switch r.Form.Get("action") {
+		case "verifysourcecode":
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, standardJSONFormat, r.PostForm.Get("codeformat"))
+			assert.Equal(t, string(testRequest.StandardJSON), r.PostForm.Get("sourceCode"))
+			assert.Equal(t, testRequest.Address.Hex(), r.PostForm.Get("contractaddress"))
+			assert.Equal(t, testRequest.ContractName, r.PostForm.Get("contractname"))
+			assert.Equal(t, testRequest.CompilerVersion, r.PostForm.Get("compilerversion"))
+			assert.Equal(t, "0102", r.PostForm.Get("constructorArguements"))
+			resp = submit
+		case "checkverifystatus":
+			assert.Equal(t, "guid-1", r.Form.Get("guid"))
+			n := int(checks.Add(1)) - 1
+			resp = response{Status: "1", Message: "OK", Result: statuses[min(n, len(statuses)-1)]}
+		default:
+			http.Error(w, "unknown action", http.StatusBadRequest)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(server.Close)
+	return server, &checks
+}
+
+func TestSubmitAndWait(t *testing.T) {
+	server, checks := mockAPI(t, response{Status: "1", Message: "OK", Result: "guid-1"},
+		"Pending in queue", "Pending in queue", "Pass - Verified")
+	client := Client{URL: server.URL, APIKey: "key", PollInterval: time.Millisecond}
+
+	guid, err := client.Submit(context.Background(), testRequest)
+	require.NoError(t, err)
+	assert.Equal(t, "guid-1", guid)
+
+	status, err := client.Wait(context.Background(), testRequest.ChainID, guid)
+	require.NoError(t, err)
+	assert.Equal(t, Status{Verified: true, Message: "Pass - Verified"}, status)
+	assert.Equal(t, int32(3), checks.Load())
+}
+
+func TestWaitFail(t *testing.T) {
+	server, _ := mockAPI(t, response{}, "Fail - Unable to verify")
+	client := Client{URL: server.URL, APIKey: "key", PollInterval: time.Millisecond}
+
+	status, err := client.Wait(context.Background(), testRequest.ChainID, "guid-1")
+	require.NoError(t, err)
+	assert.False(t, status.Pending)
+	assert.False(t, status.Verified)
+	assert.Equal(t, "Fail - Unable to verify", status.Message)
+}
+
+func TestWaitCanceled(t *testing.T) {
+	server, _ := mockAPI(t, response{}, "Pending in queue")
+	client := Client{URL: server.URL, APIKey: "key", PollInterval: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	status, err := client.Wait(ctx, testRequest.ChainID, "guid-1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, status.Pending)
+}
+
+func TestSubmitErrors(t *testing.T) {
+	server, _ := mockAPI(t, response{Status: "0", Message: "NOTOK", Result: "Contract source code already verified"})
+	client := Client{URL: server.URL, APIKey: "key"}
+	_, err := client.Submit(context.Background(), testRequest)
+	require.ErrorIs(t, err, ErrAlreadyVerified)
+
+	server, _ = mockAPI(t, response{Status: "0", Message: "NOTOK", Result: "Invalid API Key"})
+	client.URL = server.URL
+	_, err = client.Submit(context.Background(), testRequest)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid API Key")
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer failing.Close()
+	client.URL = failing.URL
+	_, err = client.Submit(context.Background(), testRequest)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 429: rate limited")
+	_, err = client.Status(context.Background(), testRequest.ChainID, "guid-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checkverifystatus failed")
+}
+
+func TestParseStatus(t *testing.T) {
+	assert.Equal(t, Status{Pending: true, Message: "Pending in queue"}, parseStatus("Pending in queue"))
+	assert.Equal(t, Status{Verified: true, Message: "Pass - Verified"}, parseStatus("Pass - Verified"))
+	assert.Equal(t, Status{Verified: true, Message: "Already Verified"}, parseStatus("Already Verified"))
+	assert.Equal(t, Status{Message: "Fail - Unable to verify"}, parseStatus("Fail - Unable to verify"))
+}
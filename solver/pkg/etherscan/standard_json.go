@@ -0,0 +1,107 @@
+package etherscan
+
+// Standard-json-input from Forge artifacts
+// A Forge artifact's metadata names the compilation target, the compiler and its settings, and every source file
+// with its keccak256, which is all verification needs besides the sources themselves. They are read from the
+// Foundry project and checked against the hashes, so a source edited since the build is caught before Etherscan
+// compiles something else than what was deployed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Source is a contract's verification input
+type Source struct {
+	// ContractName is the fully qualified name, such as src/MockERC20.sol:MockERC20
+	ContractName string
+	// CompilerVersion is the solc long version with a v prefix, as the API expects it
+	CompilerVersion string
+	StandardJSON    []byte
+}
+
+// artifactMetadata is the part of a Forge artifact's solc metadata verification uses
+type artifactMetadata struct {
+	Metadata struct {
+		Language string `json:"language"`
+		Compiler struct {
+			Version string `json:"version"`
+		} `json:"compiler"`
+		Settings map[string]json.RawMessage `json:"settings"`
+		Sources  map[string]struct {
+			Keccak256 common.Hash `json:"keccak256"`
+		} `json:"sources"`
+	} `json:"metadata"`
+}
+
+type standardSource struct {
+	Content string `json:"content"`
+}
+
+type standardJSON struct {
+	Language string                     `json:"language"`
+	Sources  map[string]standardSource  `json:"sources"`
+	Settings map[string]json.RawMessage `json:"settings"`
+}
+
+// SourceFromArtifact builds the verification input of the contract in the Forge artifact at artifactPath, reading
+// its sources from projectDir, the Foundry project root they are named relative to
+func SourceFromArtifact(projectDir, artifactPath string) (Source, error) {
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return Source{}, fmt.Errorf("failed to read the artifact (run forge build in %s): %w", projectDir, err)
+	}
+	var artifact artifactMetadata
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return Source{}, fmt.Errorf("artifact %s is not a Forge artifact: %w", artifactPath, err)
+	}
+	meta := artifact.Metadata
+	if meta.Compiler.Version == "" || len(meta.Sources) == 0 {
+		return Source{}, fmt.Errorf("artifact %s has no metadata", artifactPath)
+	}
+
+	var target map[string]string
+	if err := json.Unmarshal(meta.Settings["compilationTarget"], &target); err != nil || len(target) != 1 {
+		return Source{}, fmt.Errorf("artifact %s has no single compilation target", artifactPath)
+	}
+	var contractName string
+	for file, name := range target {
+		contractName = file + ":" + name
+	}
+
+	input := standardJSON{Language: meta.Language, Sources: make(map[string]standardSource, len(meta.Sources)), Settings: map[string]json.RawMessage{}}
+	// compilationTarget is metadata only; solc rejects it in standard-json settings
+	for key, value := range meta.Settings {
+		if key != "compilationTarget" {
+			input.Settings[key] = value
+		}
+	}
+
+	names := make([]string, 0, len(meta.Sources))
+	for name := range meta.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(name)))
+		if err != nil {
+			return Source{}, fmt.Errorf("failed to read source %s: %w", name, err)
+		}
+		if crypto.Keccak256Hash(content) != meta.Sources[name].Keccak256 {
+			return Source{}, fmt.Errorf("source %s changed since the artifact was built (run forge build in %s)", name, projectDir)
+		}
+		input.Sources[name] = standardSource{Content: string(content)}
+	}
+
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return Source{}, err
+	}
+	return Source{ContractName: contractName, CompilerVersion: "v" + meta.Compiler.Version, StandardJSON: encoded}, nil
+}
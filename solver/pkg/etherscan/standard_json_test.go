@@ -0,0 +1,88 @@
+package etherscan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProject writes a Foundry project with two sources and a Forge artifact for src/Token.sol, and returns the
+// project and artifact paths
+func writeProject(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	sources := map[string]string{
+		"src/Token.sol":         "contract Token is Base {}",
+		"lib/base/src/Base.sol": "contract Base {}",
+	}
+	metaSources := map[string]any{}
+	for name, content := range sources {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		metaSources[name] = map[string]any{"keccak256": crypto.Keccak256Hash([]byte(content)).Hex()}
+	}
+	artifact := map[string]any{
+		"bytecode": map[string]any{"object": "0x6080"},
+		"metadata": map[string]any{
+			"language": "Solidity",
+			"compiler": map[string]any{"version": "0.8.25+commit.b61c2a91"},
+			"settings": map[string]any{
+				"compilationTarget": map[string]string{"src/Token.sol": "Token"},
+				"optimizer":         map[string]any{"enabled": true, "runs": 10000},
+				"evmVersion":        "shanghai",
+			},
+			"sources": metaSources,
+		},
+	}
+	data, err := json.Marshal(artifact)
+	require.NoError(t, err)
+	artifactPath := filepath.Join(dir, "out", "Token.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(artifactPath), 0o755))
+	require.NoError(t, os.WriteFile(artifactPath, data, 0o600))
+	return dir, artifactPath
+}
+
+func TestSourceFromArtifact(t *testing.T) {
+	dir, artifactPath := writeProject(t)
+	source, err := SourceFromArtifact(dir, artifactPath)
+	require.NoError(t, err)
+	assert.Equal(t, "src/Token.sol:Token", source.ContractName)
+	assert.Equal(t, "v0.8.25+commit.b61c2a91", source.CompilerVersion)
+
+	var input struct {
+		Language string                       `json:"language"`
+		Sources  map[string]map[string]string `json:"sources"`
+		Settings map[string]json.RawMessage   `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(source.StandardJSON, &input))
+	assert.Equal(t, "Solidity", input.Language)
+	assert.Equal(t, "contract Token is Base {}", input.Sources["src/Token.sol"]["content"])
+	assert.Equal(t, "contract Base {}", input.Sources["lib/base/src/Base.sol"]["content"])
+	assert.NotContains(t, input.Settings, "compilationTarget")
+	assert.JSONEq(t, `{"enabled":true,"runs":10000}`, string(input.Settings["optimizer"]))
+	assert.JSONEq(t, `"shanghai"`, string(input.Settings["evmVersion"]))
+}
+
+func TestSourceFromArtifactErrors(t *testing.T) {
+	dir, artifactPath := writeProject(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "Token.sol"), []byte("contract Token {}"), 0o600))
+	_, err := SourceFromArtifact(dir, artifactPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "src/Token.sol changed since the artifact was built")
+
+	_, err = SourceFromArtifact(dir, filepath.Join(dir, "out", "Missing.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run forge build")
+
+	noMetadata := filepath.Join(dir, "out", "NoMetadata.json")
+	require.NoError(t, os.WriteFile(noMetadata, []byte(`{"bytecode":{"object":"0x6080"}}`), 0o600))
+	_, err = SourceFromArtifact(dir, noMetadata)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no metadata")
+}
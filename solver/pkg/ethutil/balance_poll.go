@@ -0,0 +1,71 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Defaults for WaitForERC20BalanceChange
+const (
+	DefaultBalancePollTimeout  = 30 * time.Second
+	DefaultBalancePollInterval = 250 * time.Millisecond
+)
+
+// BalancePollOptions controls how WaitForERC20BalanceChange polls; zero values use the defaults
+type BalancePollOptions struct {
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// BlockNumber pins every read to a block (e.g. the receipt of the transaction that moved the tokens)
+	// so the comparison is against consistent state; nil reads the latest block
+	BlockNumber *big.Int
+}
+
+func (o BalancePollOptions) withDefaults() BalancePollOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultBalancePollTimeout
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultBalancePollInterval
+	}
+	return o
+}
+
+// WaitForERC20BalanceChange re-reads an ERC20 balance until it differs from previous or the timeout expires.
+// Read errors are retried, since a lagging node may not serve the pinned block yet
+func WaitForERC20BalanceChange(
+	ctx context.Context,
+	caller ethereum.ContractCaller,
+	tokenAddress, ownerAddress common.Address,
+	previous *big.Int,
+	opts BalancePollOptions,
+) (*big.Int, error) {
+	opts = opts.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		balance, err := ERC20BalanceAt(ctx, caller, tokenAddress, ownerAddress, opts.BlockNumber)
+		if err == nil && balance.Cmp(previous) != 0 {
+			return balance, nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("balance of %s did not change from %s within %s: %w", ownerAddress.Hex(), previous, opts.Timeout, lastErr)
+			}
+			return balance, fmt.Errorf("balance of %s did not change from %s within %s", ownerAddress.Hex(), previous, opts.Timeout)
+		case <-ticker.C:
+		}
+	}
+}
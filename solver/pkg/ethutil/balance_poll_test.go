@@ -0,0 +1,154 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// laggingBalanceCaller answers balanceOf with the old balance for the first staleCalls reads
+type laggingBalanceCaller struct {
+	oldBalance, newBalance *big.Int
+	staleCalls             int
+	err                    error
+	calls                  int
+	blocks                 []*big.Int
+}
+
+func (c *laggingBalanceCaller) CallContract(_ context.Context, _ ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	c.blocks = append(c.blocks, blockNumber)
+	if c.err != nil {
+		return nil, c.err
+	}
+	balance := c.newBalance
+	if c.calls <= c.staleCalls {
+		balance = c.oldBalance
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsedABI.Methods["balanceOf"].Outputs.Pack(balance)
+}
+
+func TestWaitForERC20BalanceChange(t *testing.T) {
+	token := common.HexToAddress("0x1")
+	owner := common.HexToAddress("0x2")
+
+	t.Run("returns new balance after stale reads", func(t *testing.T) {
+		caller := &laggingBalanceCaller{oldBalance: big.NewInt(1000), newBalance: big.NewInt(900), staleCalls: 3}
+		opts := BalancePollOptions{Timeout: time.Second, PollInterval: time.Millisecond, BlockNumber: big.NewInt(42)}
+
+		balance, err := WaitForERC20BalanceChange(context.Background(), caller, token, owner, big.NewInt(1000), opts)
+		require.NoError(t, err)
+		assert.Equal(t, int64(900), balance.Int64())
+		assert.Equal(t, 4, caller.calls)
+		for _, block := range caller.blocks {
+			assert.Equal(t, big.NewInt(42), block, "reads must be pinned to the receipt block")
+		}
+	})
+
+	t.Run("reads latest when no block is pinned", func(t *testing.T) {
+		caller := &laggingBalanceCaller{oldBalance: big.NewInt(1000), newBalance: big.NewInt(1100)}
+		opts := BalancePollOptions{Timeout: time.Second, PollInterval: time.Millisecond}
+
+		balance, err := WaitForERC20BalanceChange(context.Background(), caller, token, owner, big.NewInt(1000), opts)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1100), balance.Int64())
+		require.Len(t, caller.blocks, 1)
+		assert.Nil(t, caller.blocks[0])
+	})
+
+	t.Run("times out when balance never changes", func(t *testing.T) {
+		caller := &laggingBalanceCaller{oldBalance: big.NewInt(1000), newBalance: big.NewInt(900), staleCalls: 1 << 30}
+		opts := BalancePollOptions{Timeout: 20 * time.Millisecond, PollInterval: time.Millisecond}
+
+		balance, err := WaitForERC20BalanceChange(context.Background(), caller, token, owner, big.NewInt(1000), opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not change")
+		assert.Equal(t, int64(1000), balance.Int64())
+	})
+
+	t.Run("read errors are retried and reported on timeout", func(t *testing.T) {
+		caller := &laggingBalanceCaller{err: errors.New("header not found")}
+		opts := BalancePollOptions{Timeout: 20 * time.Millisecond, PollInterval: time.Millisecond}
+
+		_, err := WaitForERC20BalanceChange(context.Background(), caller, token, owner, big.NewInt(1000), opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "header not found")
+		assert.Greater(t, caller.calls, 1)
+	})
+
+	t.Run("zero options use defaults", func(t *testing.T) {
+		opts := BalancePollOptions{}.withDefaults()
+		assert.Equal(t, DefaultBalancePollTimeout, opts.Timeout)
+		assert.Equal(t, DefaultBalancePollInterval, opts.PollInterval)
+	})
+}
+
+// decimalsCaller answers decimals() with a fixed value, or with no data when the token has no code
+type decimalsCaller struct {
+	decimals uint8
+	empty    bool
+}
+
+func (c *decimalsCaller) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if c.empty {
+		return nil, nil
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsedABI.Methods["decimals"].Outputs.Pack(c.decimals)
+}
+
+func TestERC20Decimals(t *testing.T) {
+	decimals, err := ERC20Decimals(context.Background(), &decimalsCaller{decimals: 6}, common.HexToAddress("0x1"))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), decimals)
+
+	_, err = ERC20Decimals(context.Background(), &decimalsCaller{empty: true}, common.HexToAddress("0x1"))
+	assert.ErrorContains(t, err, "contract may not exist")
+}
+
+// allowanceCaller answers allowance(owner, spender) with a fixed value and records the call
+type allowanceCaller struct {
+	allowance *big.Int
+	msg       ethereum.CallMsg
+}
+
+func (c *allowanceCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.msg = msg
+	if c.allowance == nil {
+		return nil, nil
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsedABI.Methods["allowance"].Outputs.Pack(c.allowance)
+}
+
+func TestERC20AllowanceAt(t *testing.T) {
+	token, owner, spender := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	caller := &allowanceCaller{allowance: big.NewInt(42)}
+
+	allowance, err := ERC20AllowanceAt(context.Background(), caller, token, owner, spender, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), allowance)
+	assert.Equal(t, &token, caller.msg.To)
+
+	_, err = ERC20AllowanceAt(context.Background(), &allowanceCaller{}, token, owner, spender, nil)
+	assert.ErrorContains(t, err, "contract may not exist")
+}
@@ -0,0 +1,78 @@
+package ethutil
+
+// CREATE2 deployment
+// bind.DeployContract addresses a contract by the deployer's nonce, so every fork restart moves it. Deploying
+// through the deterministic deployment proxy (pre-deployed by anvil and on most public chains) addresses it by
+// salt and init code instead: Create2Address predicts the address, and DeployCreate2 only sends the deploy when
+// nothing is there yet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DeterministicDeploymentProxy is the canonical CREATE2 factory: called with salt ++ initCode, it deploys initCode
+// with CREATE2 and returns the address
+var DeterministicDeploymentProxy = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+// ErrNoCreate2Factory is returned by DeployCreate2 on chains without DeterministicDeploymentProxy
+var ErrNoCreate2Factory = errors.New("deterministic deployment proxy not deployed")
+
+// Create2Backend is the part of an RPC client DeployCreate2 needs; *ethclient.Client implements it
+type Create2Backend interface {
+	TxBackend
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// ParseSalt parses a CREATE2 salt given as a decimal or 0x-prefixed number of at most 32 bytes
+func ParseSalt(value string) ([32]byte, error) {
+	var salt [32]byte
+	n, ok := new(big.Int).SetString(value, 0)
+	if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+		return salt, fmt.Errorf("invalid salt %q: expected a number of at most 32 bytes", value)
+	}
+	n.FillBytes(salt[:])
+	return salt, nil
+}
+
+// Create2Address returns the address DeterministicDeploymentProxy deploys initCode to with salt
+func Create2Address(salt [32]byte, initCode []byte) common.Address {
+	return crypto.CreateAddress2(DeterministicDeploymentProxy, salt, crypto.Keccak256(initCode))
+}
+
+// DeployCreate2 deploys initCode with salt through DeterministicDeploymentProxy and returns its address and the
+// deploy transaction, which is nil when code is already at the address. The caller waits for the transaction
+func DeployCreate2(ctx context.Context, backend Create2Backend, auth *bind.TransactOpts, salt [32]byte, initCode []byte) (common.Address, *gethtypes.Transaction, error) {
+	address := Create2Address(salt, initCode)
+	code, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return address, nil, fmt.Errorf("failed to get code at %s: %w", address, err)
+	}
+	if len(code) > 0 {
+		return address, nil, nil
+	}
+
+	factoryCode, err := backend.CodeAt(ctx, DeterministicDeploymentProxy, nil)
+	if err != nil {
+		return address, nil, fmt.Errorf("failed to get code at %s: %w", DeterministicDeploymentProxy, err)
+	}
+	if len(factoryCode) == 0 {
+		return address, nil, fmt.Errorf("%w at %s", ErrNoCreate2Factory, DeterministicDeploymentProxy)
+	}
+
+	data := make([]byte, 0, len(salt)+len(initCode))
+	data = append(data, salt[:]...)
+	data = append(data, initCode...)
+	tx, err := SendTx(ctx, backend, auth, DeterministicDeploymentProxy, nil, data)
+	if err != nil {
+		return address, nil, fmt.Errorf("failed to send the CREATE2 deploy of %s: %w", address, err)
+	}
+	return address, tx, nil
+}
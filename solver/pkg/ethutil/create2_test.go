@@ -0,0 +1,134 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCreate2Chain runs calls to DeterministicDeploymentProxy like the proxy does, deploying salt ++ initCode
+// at its CREATE2 address. A restart clears deployed contracts but keeps the proxy, like a new anvil fork
+type fakeCreate2Chain struct {
+	fakeTxBackend
+	code    map[common.Address][]byte
+	codeErr error
+}
+
+func newFakeCreate2Chain() *fakeCreate2Chain {
+	chain := &fakeCreate2Chain{fakeTxBackend: fakeTxBackend{baseFee: big.NewInt(1), tipCap: big.NewInt(1), estimate: 100_000}}
+	chain.restart()
+	return chain
+}
+
+func (c *fakeCreate2Chain) restart() {
+	c.code = map[common.Address][]byte{DeterministicDeploymentProxy: {0x7f}}
+	c.nonce = 0
+}
+
+func (c *fakeCreate2Chain) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	return c.code[account], c.codeErr
+}
+
+func (c *fakeCreate2Chain) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	if err := c.fakeTxBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	c.nonce++
+	if tx.To() == nil || *tx.To() != DeterministicDeploymentProxy || len(c.code[DeterministicDeploymentProxy]) == 0 {
+		return nil
+	}
+	var salt [32]byte
+	copy(salt[:], tx.Data()[:32])
+	initCode := tx.Data()[32:]
+	c.code[crypto.CreateAddress2(DeterministicDeploymentProxy, salt, crypto.Keccak256(initCode))] = initCode
+	return nil
+}
+
+func TestParseSalt(t *testing.T) {
+	salt, err := ParseSalt("42")
+	require.NoError(t, err)
+	assert.Equal(t, byte(42), salt[31])
+	assert.Equal(t, common.BigToHash(big.NewInt(42)), common.Hash(salt))
+
+	hexSalt, err := ParseSalt("0x2a")
+	require.NoError(t, err)
+	assert.Equal(t, salt, hexSalt)
+
+	recorded, err := ParseSalt(common.Hash(salt).Hex())
+	require.NoError(t, err)
+	assert.Equal(t, salt, recorded, "a recorded salt parses back")
+
+	for _, invalid := range []string{"", "salty", "-1", "0x1" + string(bytes.Repeat([]byte("0"), 64))} {
+		_, err := ParseSalt(invalid)
+		assert.ErrorContains(t, err, "invalid salt", invalid)
+	}
+}
+
+func TestDeployCreate2(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := NewTransactor(big.NewInt(31337), key)
+	require.NoError(t, err)
+	salt, err := ParseSalt("0x6f6966")
+	require.NoError(t, err)
+	initCode := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+
+	t.Run("stable_across_redeploys", func(t *testing.T) {
+		chain := newFakeCreate2Chain()
+		predicted := Create2Address(salt, initCode)
+
+		address, tx, err := DeployCreate2(context.Background(), chain, auth, salt, initCode)
+		require.NoError(t, err)
+		require.NotNil(t, tx)
+		assert.Equal(t, predicted, address)
+		assert.Equal(t, DeterministicDeploymentProxy, *tx.To())
+		assert.Equal(t, append(salt[:], initCode...), tx.Data())
+		assert.Equal(t, initCode, chain.code[predicted])
+
+		address, tx, err = DeployCreate2(context.Background(), chain, auth, salt, initCode)
+		require.NoError(t, err)
+		assert.Nil(t, tx, "already deployed: nothing is sent")
+		assert.Equal(t, predicted, address)
+		assert.Len(t, chain.sent, 1)
+
+		// Another deployer nonce, as after other transactions or a fork restart, gives the same address
+		chain.restart()
+		chain.nonce = 9
+		address, tx, err = DeployCreate2(context.Background(), chain, auth, salt, initCode)
+		require.NoError(t, err)
+		require.NotNil(t, tx)
+		assert.Equal(t, predicted, address)
+	})
+
+	t.Run("salt_and_init_code_change_the_address", func(t *testing.T) {
+		otherSalt, err := ParseSalt("0x6f6967")
+		require.NoError(t, err)
+		address := Create2Address(salt, initCode)
+		assert.NotEqual(t, address, Create2Address(otherSalt, initCode))
+		assert.NotEqual(t, address, Create2Address(salt, append(initCode, 0x00)))
+	})
+
+	t.Run("no_factory", func(t *testing.T) {
+		chain := newFakeCreate2Chain()
+		delete(chain.code, DeterministicDeploymentProxy)
+		_, _, err := DeployCreate2(context.Background(), chain, auth, salt, initCode)
+		assert.ErrorIs(t, err, ErrNoCreate2Factory)
+		assert.Empty(t, chain.sent)
+	})
+
+	t.Run("code_lookup_fails", func(t *testing.T) {
+		chain := newFakeCreate2Chain()
+		chain.codeErr = errors.New("connection refused")
+		_, _, err := DeployCreate2(context.Background(), chain, auth, salt, initCode)
+		assert.ErrorContains(t, err, "failed to get code at")
+		assert.Empty(t, chain.sent)
+	})
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/ethereum/go-ethereum"
@@ -16,12 +15,7 @@ import (
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-)
-
-const (
-	// Gas limit constants
-	transferGasLimit = 100000
-	approveGasLimit  = 200000
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // ERC20ABI contains the minimal ABI for ERC20 operations
@@ -62,6 +56,13 @@ var ERC20ABI = `[
 		"outputs": [{"internalType": "bool", "name": "", "type": "bool"}],
 		"stateMutability": "nonpayable",
 		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [{"internalType": "uint8", "name": "", "type": "uint8"}],
+		"stateMutability": "view",
+		"type": "function"
 	}
 ]`
 
@@ -97,8 +98,19 @@ func GetBlockNumber(rpcURL string) (uint64, error) {
 	return client.BlockNumber(context.Background())
 }
 
+// PendingBlock is the block number ERC20BalanceAt and ERC20AllowanceAt read the pending state at, including the
+// transactions still in the mempool
+func PendingBlock() *big.Int {
+	return big.NewInt(int64(rpc.PendingBlockNumber))
+}
+
 // ERC20Balance gets the ERC20 token balance for a given address
 func ERC20Balance(client *ethclient.Client, tokenAddress, ownerAddress common.Address) (*big.Int, error) {
+	return ERC20BalanceAt(context.Background(), client, tokenAddress, ownerAddress, nil)
+}
+
+// ERC20BalanceAt gets the ERC20 token balance at a specific block (nil for latest, PendingBlock() for pending)
+func ERC20BalanceAt(ctx context.Context, caller ethereum.ContractCaller, tokenAddress, ownerAddress common.Address, blockNumber *big.Int) (*big.Int, error) {
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
@@ -123,7 +135,7 @@ func ERC20Balance(client *ethclient.Client, tokenAddress, ownerAddress common.Ad
 		BlobHashes:      nil,
 		AuthorizationList: nil,
 	}
-	result, err := client.CallContract(context.Background(), msg, nil)
+	result, err := caller.CallContract(ctx, msg, blockNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call balanceOf: %w", err)
 	}
@@ -141,8 +153,40 @@ func ERC20Balance(client *ethclient.Client, tokenAddress, ownerAddress common.Ad
 	return balance, nil
 }
 
+// ERC20Decimals reads decimals() from an ERC20 token
+func ERC20Decimals(ctx context.Context, caller ethereum.ContractCaller, tokenAddress common.Address) (uint8, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+
+	data, err := parsedABI.Pack("decimals")
+	if err != nil {
+		return 0, fmt.Errorf("failed to pack decimals call: %w", err)
+	}
+
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &tokenAddress, Data: data}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call decimals: %w", err)
+	}
+	if len(result) == 0 {
+		return 0, fmt.Errorf("empty result from decimals call - contract may not exist at address %s", tokenAddress.Hex())
+	}
+
+	var decimals uint8
+	if err := parsedABI.UnpackIntoInterface(&decimals, "decimals", result); err != nil {
+		return 0, fmt.Errorf("failed to unpack decimals result: %w", err)
+	}
+	return decimals, nil
+}
+
 // ERC20Allowance gets the ERC20 token allowance for a given owner and spender
 func ERC20Allowance(client *ethclient.Client, tokenAddress, ownerAddress, spenderAddress common.Address) (*big.Int, error) {
+	return ERC20AllowanceAt(context.Background(), client, tokenAddress, ownerAddress, spenderAddress, nil)
+}
+
+// ERC20AllowanceAt gets the ERC20 token allowance at a specific block (nil for latest, PendingBlock() for pending)
+func ERC20AllowanceAt(ctx context.Context, caller ethereum.ContractCaller, tokenAddress, ownerAddress, spenderAddress common.Address, blockNumber *big.Int) (*big.Int, error) {
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
@@ -153,24 +197,13 @@ func ERC20Allowance(client *ethclient.Client, tokenAddress, ownerAddress, spende
 		return nil, fmt.Errorf("failed to pack allowance call: %w", err)
 	}
 
-	msg := ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &tokenAddress,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            data,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
-		AuthorizationList: nil,
-	}
-	result, err := client.CallContract(context.Background(), msg, nil)
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &tokenAddress, Data: data}, blockNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call allowance: %w", err)
 	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty result from allowance call - contract may not exist at address %s", tokenAddress.Hex())
+	}
 
 	var allowance *big.Int
 	if err := parsedABI.UnpackIntoInterface(&allowance, "allowance", result); err != nil {
@@ -180,14 +213,14 @@ func ERC20Allowance(client *ethclient.Client, tokenAddress, ownerAddress, spende
 	return allowance, nil
 }
 
-// createERC20Transaction creates a generic ERC20 transaction
+// createERC20Transaction sends an ERC20 call through SendTx, which estimates its gas and fees
 func createERC20Transaction(
+	ctx context.Context,
 	client *ethclient.Client,
 	auth *bind.TransactOpts,
 	tokenAddress common.Address,
 	method string,
 	args []interface{},
-	gasLimit uint64,
 ) (*gethtypes.Transaction, error) {
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
 	if err != nil {
@@ -199,71 +232,33 @@ func createERC20Transaction(
 		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
 	}
 
-	// Get current nonce
-	nonce, err := client.PendingNonceAt(context.Background(), auth.From)
+	tx, err := SendTx(ctx, client, auth, tokenAddress, nil, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return nil, fmt.Errorf("%s: %w", method, err)
 	}
-
-	// Get current gas price
-	gasPrice, err := client.SuggestGasPrice(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
-	}
-
-	// Create transaction
-	tx := gethtypes.NewTransaction(
-		nonce,
-		tokenAddress,
-		big.NewInt(0), // No ETH value
-		gasLimit,      // Gas limit
-		gasPrice,
-		data,
-	)
-
-	// Sign transaction
-	signedTx, err := auth.Signer(auth.From, tx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign %s transaction: %w", method, err)
-	}
-
-	// Send transaction
-	err = client.SendTransaction(context.Background(), signedTx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send %s transaction: %w", method, err)
-	}
-
-	return signedTx, nil
+	return tx, nil
 }
 
 // ERC20Transfer creates a transfer transaction for ERC20 tokens
 func ERC20Transfer(
+	ctx context.Context,
 	client *ethclient.Client,
 	auth *bind.TransactOpts,
 	tokenAddress, recipientAddress common.Address,
 	amount *big.Int,
 ) (*gethtypes.Transaction, error) {
-	return createERC20Transaction(client, auth, tokenAddress, "transfer", []interface{}{recipientAddress, amount}, transferGasLimit)
+	return createERC20Transaction(ctx, client, auth, tokenAddress, "transfer", []interface{}{recipientAddress, amount})
 }
 
 // ERC20Approve creates an approve transaction for ERC20 tokens
 func ERC20Approve(
+	ctx context.Context,
 	client *ethclient.Client,
 	auth *bind.TransactOpts,
 	tokenAddress, spenderAddress common.Address,
 	amount *big.Int,
 ) (*gethtypes.Transaction, error) {
-	return createERC20Transaction(client, auth, tokenAddress, "approve", []interface{}{spenderAddress, amount}, approveGasLimit)
-}
-
-// WaitForTransaction waits for a transaction to be mined and returns the receipt
-func WaitForTransaction(client *ethclient.Client, tx *gethtypes.Transaction) (*gethtypes.Receipt, error) {
-	receipt, err := bind.WaitMined(context.Background(), client, tx)
-
-	// NOTE: Sometimes the Base network has unexpected latency on the useable state changes, this is a patch to overcome that.
-	time.Sleep(2 * time.Second)
-
-	return receipt, err
+	return createERC20Transaction(ctx, client, auth, tokenAddress, "approve", []interface{}{spenderAddress, amount})
 }
 
 // FormatTokenAmount formats a token amount from wei to tokens with specified decimals
@@ -0,0 +1,204 @@
+package ethutil
+
+// Revert decoding for failed Hyperlane7683 calls
+// Custom errors come from the generated Hyperlane7683 binding (Base7683 + BasicSwap7683 + Hyperlane7683)
+// plus the Permit2 errors that surface through openFor; Error(string) and Panic(uint256) are handled by abi.UnpackRevert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// permit2ErrorsABI lists the Permit2 custom errors that can bubble up from openFor.
+// Permit2's InvalidNonce() shares its selector with Base7683's and decodes to the same name
+var permit2ErrorsABI = `[
+	{"type": "error", "name": "SignatureExpired", "inputs": [{"name": "signatureDeadline", "type": "uint256"}]},
+	{"type": "error", "name": "InvalidAmount", "inputs": [{"name": "maxAmount", "type": "uint256"}]},
+	{"type": "error", "name": "LengthMismatch", "inputs": []},
+	{"type": "error", "name": "InvalidSigner", "inputs": []},
+	{"type": "error", "name": "InvalidSignatureLength", "inputs": []},
+	{"type": "error", "name": "InvalidSignature", "inputs": []},
+	{"type": "error", "name": "InvalidContractSignature", "inputs": []}
+]`
+
+// errorStringSelector is the selector of Error(string), used by require/revert with a message
+var errorStringSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+// RevertError is a decoded EVM revert
+type RevertError struct {
+	Name   string        // custom error name, "Error" for require/revert strings, "Panic" for panics; empty if unknown
+	Args   []interface{} // decoded custom error arguments
+	Reason string        // reason string for Error(string) and Panic(uint256)
+	Data   []byte        // raw revert data
+}
+
+// Error renders the revert as Name(arg, ...) or the revert reason string
+func (e *RevertError) Error() string {
+	switch {
+	case e.Name == "Error" || e.Name == "Panic":
+		return "execution reverted: " + e.Reason
+	case e.Name == "":
+		return "execution reverted with unknown data " + hexutil.Encode(e.Data)
+	}
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = formatRevertArg(arg)
+	}
+	return fmt.Sprintf("execution reverted: %s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+// revertErrors maps selectors to the custom errors we know how to decode
+var revertErrors = loadRevertErrors()
+
+func loadRevertErrors() map[[4]byte]abi.Error {
+	table := make(map[[4]byte]abi.Error)
+	add := func(parsed *abi.ABI) {
+		for _, e := range parsed.Errors {
+			var selector [4]byte
+			copy(selector[:], e.ID[:4])
+			table[selector] = e
+		}
+	}
+
+	if parsed, err := contracts.Hyperlane7683MetaData.GetAbi(); err == nil {
+		add(parsed)
+	}
+	if parsed, err := abi.JSON(strings.NewReader(permit2ErrorsABI)); err == nil {
+		add(&parsed)
+	}
+	return table
+}
+
+// DecodeRevert decodes revert data into a RevertError.
+// Unknown selectors still return a RevertError carrying the raw data
+func DecodeRevert(data []byte) (*RevertError, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("revert data too short: %d bytes", len(data))
+	}
+
+	revert := &RevertError{Data: data}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		revert.Reason = reason
+		if bytes.Equal(data[:4], errorStringSelector) {
+			revert.Name = "Error"
+		} else {
+			revert.Name = "Panic"
+		}
+		return revert, nil
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	customErr, ok := revertErrors[selector]
+	if !ok {
+		return revert, nil
+	}
+
+	args, err := customErr.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s arguments: %w", customErr.Name, err)
+	}
+	revert.Name = customErr.Name
+	revert.Args = args
+	return revert, nil
+}
+
+// RevertData extracts the revert payload from a JSON-RPC error (eth_call / eth_estimateGas)
+func RevertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil || len(data) == 0 {
+		return nil, false
+	}
+	return data, true
+}
+
+// DecodeRevertError decodes the revert carried by a JSON-RPC error, or returns nil if there is none
+func DecodeRevertError(err error) *RevertError {
+	data, ok := RevertData(err)
+	if !ok {
+		return nil
+	}
+	revert, decodeErr := DecodeRevert(data)
+	if decodeErr != nil {
+		return nil
+	}
+	return revert
+}
+
+// SimulateAndDecodeRevert replays msg with eth_call at blockNumber and decodes why it reverts.
+// It returns (nil, nil) if the call succeeds and the RPC error itself if it carries no revert data
+func SimulateAndDecodeRevert(ctx context.Context, client ethereum.ContractCaller, msg ethereum.CallMsg, blockNumber *big.Int) (*RevertError, error) {
+	_, err := client.CallContract(ctx, msg, blockNumber)
+	if err == nil {
+		return nil, nil
+	}
+	if revert := DecodeRevertError(err); revert != nil {
+		return revert, nil
+	}
+	return nil, fmt.Errorf("simulation failed without revert data: %w", err)
+}
+
+// RevertReason swaps a JSON-RPC error for its decoded revert (e.g. from eth_estimateGas) when it carries revert data
+func RevertReason(err error) error {
+	if revert := DecodeRevertError(err); revert != nil {
+		return revert
+	}
+	return err
+}
+
+// RevertedTxError replays a reverted transaction with the exact calldata and sender it was sent with
+// and decodes the revert reason. The replay runs against the state of the block the transaction
+// was mined in, so preceding transactions in that block (e.g. an approval) are taken into account
+func RevertedTxError(ctx context.Context, client ethereum.ContractCaller, label string, tx *gethtypes.Transaction, from common.Address, receipt *gethtypes.Receipt) error {
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	revert, err := SimulateAndDecodeRevert(ctx, client, msg, receipt.BlockNumber)
+	switch {
+	case err != nil:
+		return fmt.Errorf("%s transaction %s reverted (could not decode reason: %v)", label, tx.Hash().Hex(), err)
+	case revert == nil:
+		return fmt.Errorf("%s transaction %s reverted (replay succeeded, reason unavailable)", label, tx.Hash().Hex())
+	default:
+		return fmt.Errorf("%s transaction %s reverted: %w", label, tx.Hash().Hex(), revert)
+	}
+}
+
+// formatRevertArg renders decoded error arguments the way they appear in Solidity
+func formatRevertArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case [32]byte:
+		return common.Hash(v).Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case common.Address:
+		return v.Hex()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,224 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Revert payloads as returned by eth_call against Hyperlane7683 / Permit2 / OZ ERC20
+const (
+	invalidOriginDomainRevert = "0x56632acd000000000000000000000000000000000000000000000000000000000165cc12"
+	invalidOrderTypeRevert    = "0x8703f772faa1f595e2db7bf53e1a4bc9834eef6b86d3cd66ec9c8b3588c09253d0affc51"
+	orderOpenExpiredRevert    = "0x2bfdf69b"
+	gaslessSettlerRevert      = "0x257cabd4"
+	signatureExpiredRevert    = "0xcd21db4f000000000000000000000000000000000000000000000000000000006553f100"
+	allowanceRevert           = "0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000001d45524332303a20696e73756666696369656e7420616c6c6f77616e6365000000"
+)
+
+// rpcDataError mimics the JSON-RPC error returned by geth for reverted calls
+type rpcDataError struct {
+	data interface{}
+}
+
+func (e rpcDataError) Error() string          { return "execution reverted" }
+func (e rpcDataError) ErrorData() interface{} { return e.data }
+
+type revertingCaller struct {
+	err         error
+	blockNumber *big.Int
+	msg         ethereum.CallMsg
+}
+
+func (c *revertingCaller) CallContract(_ context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.msg = msg
+	c.blockNumber = blockNumber
+	return nil, c.err
+}
+
+func TestDecodeRevert(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		wantName string
+		wantArgs []interface{}
+		wantText string
+	}{
+		{
+			name:     "InvalidOriginDomain with uint32 argument",
+			payload:  invalidOriginDomainRevert,
+			wantName: "InvalidOriginDomain",
+			wantArgs: []interface{}{uint32(23448594)},
+			wantText: "execution reverted: InvalidOriginDomain(23448594)",
+		},
+		{
+			name:     "InvalidOrderType with bytes32 argument",
+			payload:  invalidOrderTypeRevert,
+			wantName: "InvalidOrderType",
+			wantArgs: []interface{}{common.HexToHash("0xfaa1f595e2db7bf53e1a4bc9834eef6b86d3cd66ec9c8b3588c09253d0affc51")},
+			wantText: "execution reverted: InvalidOrderType(0xfaa1f595e2db7bf53e1a4bc9834eef6b86d3cd66ec9c8b3588c09253d0affc51)",
+		},
+		{
+			name:     "OrderOpenExpired without arguments",
+			payload:  orderOpenExpiredRevert,
+			wantName: "OrderOpenExpired",
+			wantArgs: []interface{}{},
+			wantText: "execution reverted: OrderOpenExpired()",
+		},
+		{
+			name:     "InvalidGaslessOrderSettler without arguments",
+			payload:  gaslessSettlerRevert,
+			wantName: "InvalidGaslessOrderSettler",
+			wantArgs: []interface{}{},
+			wantText: "execution reverted: InvalidGaslessOrderSettler()",
+		},
+		{
+			name:     "Permit2 SignatureExpired",
+			payload:  signatureExpiredRevert,
+			wantName: "SignatureExpired",
+			wantArgs: []interface{}{big.NewInt(1700000000)},
+			wantText: "execution reverted: SignatureExpired(1700000000)",
+		},
+		{
+			name:     "Error(string) from ERC20",
+			payload:  allowanceRevert,
+			wantName: "Error",
+			wantText: "execution reverted: ERC20: insufficient allowance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revert, err := DecodeRevert(hexutil.MustDecode(tt.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, revert.Name)
+			if tt.wantArgs != nil {
+				require.Len(t, revert.Args, len(tt.wantArgs))
+				for i, want := range tt.wantArgs {
+					if hash, ok := want.(common.Hash); ok {
+						assert.Equal(t, [32]byte(hash), revert.Args[i])
+						continue
+					}
+					assert.Equal(t, want, revert.Args[i])
+				}
+			}
+			assert.Equal(t, tt.wantText, revert.Error())
+		})
+	}
+
+	t.Run("unknown selector keeps raw data", func(t *testing.T) {
+		revert, err := DecodeRevert(hexutil.MustDecode("0xdeadbeef"))
+		require.NoError(t, err)
+		assert.Empty(t, revert.Name)
+		assert.Equal(t, "execution reverted with unknown data 0xdeadbeef", revert.Error())
+	})
+
+	t.Run("short data is rejected", func(t *testing.T) {
+		_, err := DecodeRevert([]byte{0x01, 0x02})
+		assert.Error(t, err)
+	})
+
+	t.Run("truncated arguments are rejected", func(t *testing.T) {
+		_, err := DecodeRevert(hexutil.MustDecode(invalidOriginDomainRevert)[:10])
+		assert.Error(t, err)
+	})
+}
+
+func TestRevertData(t *testing.T) {
+	t.Run("extracts data from wrapped rpc error", func(t *testing.T) {
+		err := fmt.Errorf("failed to open order: %w", rpcDataError{data: orderOpenExpiredRevert})
+		data, ok := RevertData(err)
+		require.True(t, ok)
+		assert.Equal(t, hexutil.MustDecode(orderOpenExpiredRevert), data)
+
+		revert := DecodeRevertError(err)
+		require.NotNil(t, revert)
+		assert.Equal(t, "OrderOpenExpired", revert.Name)
+	})
+
+	t.Run("plain errors carry no data", func(t *testing.T) {
+		_, ok := RevertData(errors.New("execution reverted: data: 0x2bfdf69b"))
+		assert.False(t, ok)
+		assert.Nil(t, DecodeRevertError(errors.New("connection refused")))
+	})
+
+	t.Run("non-hex error data is ignored", func(t *testing.T) {
+		_, ok := RevertData(rpcDataError{data: map[string]interface{}{"foo": "bar"}})
+		assert.False(t, ok)
+		_, ok = RevertData(rpcDataError{data: "not hex"})
+		assert.False(t, ok)
+	})
+}
+
+func TestSimulateAndDecodeRevert(t *testing.T) {
+	msg := ethereum.CallMsg{
+		From: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		To:   &common.Address{0x01},
+		Data: []byte{0xaa, 0xbb},
+	}
+	block := big.NewInt(42)
+
+	t.Run("decodes revert from replayed call", func(t *testing.T) {
+		caller := &revertingCaller{err: rpcDataError{data: invalidOriginDomainRevert}}
+		revert, err := SimulateAndDecodeRevert(context.Background(), caller, msg, block)
+		require.NoError(t, err)
+		require.NotNil(t, revert)
+		assert.Equal(t, "InvalidOriginDomain", revert.Name)
+		assert.Equal(t, msg, caller.msg)
+		assert.Equal(t, block, caller.blockNumber)
+	})
+
+	t.Run("successful call has no revert", func(t *testing.T) {
+		revert, err := SimulateAndDecodeRevert(context.Background(), &revertingCaller{}, msg, block)
+		require.NoError(t, err)
+		assert.Nil(t, revert)
+	})
+
+	t.Run("rpc failure without data is returned", func(t *testing.T) {
+		caller := &revertingCaller{err: errors.New("connection refused")}
+		revert, err := SimulateAndDecodeRevert(context.Background(), caller, msg, block)
+		assert.Nil(t, revert)
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestRevertReason(t *testing.T) {
+	revert := RevertReason(rpcDataError{data: orderOpenExpiredRevert})
+	var decoded *RevertError
+	require.ErrorAs(t, revert, &decoded)
+	assert.Equal(t, "OrderOpenExpired", decoded.Name)
+
+	plain := errors.New("insufficient funds for gas")
+	assert.Equal(t, plain, RevertReason(plain), "errors without revert data are kept")
+}
+
+func TestRevertedTxError(t *testing.T) {
+	from := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	to := common.Address{0x01}
+	tx := gethtypes.NewTx(&gethtypes.LegacyTx{Nonce: 1, To: &to, Gas: 21000, Value: big.NewInt(5), Data: []byte{0xaa}})
+	receipt := &gethtypes.Receipt{BlockNumber: big.NewInt(42)}
+
+	caller := &revertingCaller{err: rpcDataError{data: invalidOriginDomainRevert}}
+	err := RevertedTxError(context.Background(), caller, "fill", tx, from, receipt)
+	var decoded *RevertError
+	require.ErrorAs(t, err, &decoded)
+	assert.Equal(t, "InvalidOriginDomain", decoded.Name)
+	assert.ErrorContains(t, err, "fill transaction "+tx.Hash().Hex()+" reverted: ")
+	assert.Equal(t, ethereum.CallMsg{From: from, To: &to, Gas: 21000, Value: big.NewInt(5), Data: []byte{0xaa}}, caller.msg)
+	assert.Equal(t, receipt.BlockNumber, caller.blockNumber, "replayed in the block it was mined in")
+
+	err = RevertedTxError(context.Background(), &revertingCaller{}, "fill", tx, from, receipt)
+	assert.ErrorContains(t, err, "replay succeeded, reason unavailable")
+
+	err = RevertedTxError(context.Background(), &revertingCaller{err: errors.New("connection refused")}, "fill", tx, from, receipt)
+	assert.ErrorContains(t, err, "could not decode reason")
+}
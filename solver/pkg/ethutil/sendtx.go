@@ -0,0 +1,152 @@
+package ethutil
+
+// Transaction sending
+// SendTx replaces hardcoded gas limits and legacy gas prices: the limit is estimated with eth_estimateGas and
+// scaled by GAS_LIMIT_MULTIPLIER, and the transaction is an EIP-1559 dynamic-fee transaction when the head block
+// has a base fee (tip from eth_maxPriorityFeePerGas, fee cap 2*baseFee+tip), a legacy one otherwise.
+// Fields already set on the TransactOpts (Nonce, GasLimit, GasPrice, GasFeeCap, GasTipCap) win over the
+// estimated ones; an explicit GasPrice keeps the transaction legacy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultGasLimitMultiplier pads estimated gas limits when GAS_LIMIT_MULTIPLIER is unset
+const DefaultGasLimitMultiplier = 1.2
+
+// TxBackend is the part of an RPC client SendTx needs; *ethclient.Client implements it
+type TxBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error
+}
+
+// GasLimitMultiplier returns GAS_LIMIT_MULTIPLIER, or DefaultGasLimitMultiplier when it is unset, invalid or below 1
+func GasLimitMultiplier() float64 {
+	if value := os.Getenv("GAS_LIMIT_MULTIPLIER"); value != "" {
+		if m, err := strconv.ParseFloat(value, 64); err == nil && m >= 1 {
+			return m
+		}
+	}
+	return DefaultGasLimitMultiplier
+}
+
+// SendTx prices, signs and sends a call of data to `to` carrying value (nil for none) from auth.From
+func SendTx(ctx context.Context, backend TxBackend, auth *bind.TransactOpts, to common.Address, value *big.Int, data []byte) (*gethtypes.Transaction, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	nonce, err := txNonce(ctx, backend, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head block: %w", err)
+	}
+	dynamic := head.BaseFee != nil && auth.GasPrice == nil
+
+	msg := ethereum.CallMsg{From: auth.From, To: &to, Value: value, Data: data}
+	var gasPrice, feeCap, tipCap *big.Int
+	if dynamic {
+		if tipCap, feeCap, err = dynamicFees(ctx, backend, auth, head.BaseFee); err != nil {
+			return nil, err
+		}
+		msg.GasFeeCap, msg.GasTipCap = feeCap, tipCap
+	} else {
+		if gasPrice = auth.GasPrice; gasPrice == nil {
+			if gasPrice, err = backend.SuggestGasPrice(ctx); err != nil {
+				return nil, fmt.Errorf("failed to get gas price: %w", err)
+			}
+		}
+		msg.GasPrice = gasPrice
+	}
+
+	gasLimit := auth.GasLimit
+	if gasLimit == 0 {
+		estimated, err := backend.EstimateGas(ctx, msg)
+		if err != nil {
+			if revert := DecodeRevertError(err); revert != nil {
+				return nil, fmt.Errorf("failed to estimate gas: %w", revert)
+			}
+			return nil, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+		gasLimit = uint64(float64(estimated) * GasLimitMultiplier())
+	}
+
+	var tx *gethtypes.Transaction
+	if dynamic {
+		tx = gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+			Nonce:     nonce,
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       gasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+	} else {
+		tx = gethtypes.NewTx(&gethtypes.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasPrice,
+			Gas:      gasLimit,
+			To:       &to,
+			Value:    value,
+			Data:     data,
+		})
+	}
+
+	signedTx, err := auth.Signer(auth.From, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := backend.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// txNonce returns auth.Nonce or the sender's pending nonce
+func txNonce(ctx context.Context, backend TxBackend, auth *bind.TransactOpts) (uint64, error) {
+	if auth.Nonce != nil {
+		return auth.Nonce.Uint64(), nil
+	}
+	nonce, err := backend.PendingNonceAt(ctx, auth.From)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// dynamicFees returns the tip and fee cap for a dynamic-fee transaction: the suggested tip (or auth.GasTipCap)
+// and 2*baseFee+tip (or auth.GasFeeCap), which survives the base fee doubling before inclusion
+func dynamicFees(ctx context.Context, backend TxBackend, auth *bind.TransactOpts, baseFee *big.Int) (tipCap, feeCap *big.Int, err error) {
+	tipCap = auth.GasTipCap
+	if tipCap == nil {
+		if tipCap, err = backend.SuggestGasTipCap(ctx); err != nil {
+			return nil, nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+		}
+	}
+	feeCap = auth.GasFeeCap
+	if feeCap == nil {
+		feeCap = new(big.Int).Add(tipCap, new(big.Int).Mul(baseFee, big.NewInt(2)))
+	}
+	if feeCap.Cmp(tipCap) < 0 {
+		return nil, nil, fmt.Errorf("gas fee cap %s is below the tip cap %s", feeCap, tipCap)
+	}
+	return tipCap, feeCap, nil
+}
@@ -0,0 +1,150 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTxBackend is a chain with a fixed head, prices and gas estimate that records what SendTx sends.
+// A nil baseFee models a pre-London chain
+type fakeTxBackend struct {
+	baseFee     *big.Int
+	gasPrice    *big.Int
+	tipCap      *big.Int
+	estimate    uint64
+	estimateErr error
+	nonce       uint64
+
+	estimated []ethereum.CallMsg
+	sent      []*gethtypes.Transaction
+}
+
+func (b *fakeTxBackend) HeaderByNumber(context.Context, *big.Int) (*gethtypes.Header, error) {
+	return &gethtypes.Header{Number: big.NewInt(1), BaseFee: b.baseFee}, nil
+}
+
+func (b *fakeTxBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return b.nonce, nil
+}
+
+func (b *fakeTxBackend) SuggestGasPrice(context.Context) (*big.Int, error) { return b.gasPrice, nil }
+
+func (b *fakeTxBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	if b.baseFee == nil {
+		return nil, errors.New("method eth_maxPriorityFeePerGas not supported")
+	}
+	return b.tipCap, nil
+}
+
+func (b *fakeTxBackend) EstimateGas(_ context.Context, msg ethereum.CallMsg) (uint64, error) {
+	b.estimated = append(b.estimated, msg)
+	return b.estimate, b.estimateErr
+}
+
+func (b *fakeTxBackend) SendTransaction(_ context.Context, tx *gethtypes.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func TestSendTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(31337)
+	to := common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
+	data := []byte{0x09, 0x5e, 0xa7, 0xb3}
+
+	newAuth := func(t *testing.T) *bind.TransactOpts {
+		auth, err := NewTransactor(chainID, key)
+		require.NoError(t, err)
+		return auth
+	}
+
+	t.Run("dynamic_fee_on_london_chain", func(t *testing.T) {
+		t.Setenv("GAS_LIMIT_MULTIPLIER", "")
+		backend := &fakeTxBackend{baseFee: big.NewInt(10_000), tipCap: big.NewInt(1_000), estimate: 50_000, nonce: 7}
+		auth := newAuth(t)
+
+		tx, err := SendTx(context.Background(), backend, auth, to, big.NewInt(5), data)
+		require.NoError(t, err)
+		require.Len(t, backend.sent, 1)
+
+		assert.Equal(t, uint8(gethtypes.DynamicFeeTxType), tx.Type())
+		assert.Equal(t, big.NewInt(1_000), tx.GasTipCap())
+		assert.Equal(t, big.NewInt(21_000), tx.GasFeeCap(), "fee cap is 2*baseFee+tip")
+		assert.Equal(t, uint64(60_000), tx.Gas(), "estimate padded by the default multiplier")
+		assert.Equal(t, uint64(7), tx.Nonce())
+		assert.Equal(t, big.NewInt(5), tx.Value())
+		assert.Equal(t, chainID, tx.ChainId())
+
+		from, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(chainID), tx)
+		require.NoError(t, err)
+		assert.Equal(t, auth.From, from)
+
+		require.Len(t, backend.estimated, 1)
+		assert.Equal(t, big.NewInt(21_000), backend.estimated[0].GasFeeCap, "the estimate is made with the same fees")
+		assert.Nil(t, backend.estimated[0].GasPrice)
+	})
+
+	t.Run("legacy_without_base_fee", func(t *testing.T) {
+		t.Setenv("GAS_LIMIT_MULTIPLIER", "1.5")
+		backend := &fakeTxBackend{gasPrice: big.NewInt(3_000), estimate: 40_000}
+
+		tx, err := SendTx(context.Background(), backend, newAuth(t), to, nil, data)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint8(gethtypes.LegacyTxType), tx.Type())
+		assert.Equal(t, big.NewInt(3_000), tx.GasPrice())
+		assert.Equal(t, uint64(60_000), tx.Gas())
+		assert.Equal(t, 0, tx.Value().Sign())
+		assert.Equal(t, big.NewInt(3_000), backend.estimated[0].GasPrice)
+	})
+
+	t.Run("explicit_gas_price_stays_legacy", func(t *testing.T) {
+		backend := &fakeTxBackend{baseFee: big.NewInt(10_000), tipCap: big.NewInt(1_000), estimate: 40_000}
+		auth := newAuth(t)
+		auth.GasPrice = big.NewInt(99_000)
+
+		tx, err := SendTx(context.Background(), backend, auth, to, nil, data)
+		require.NoError(t, err)
+		assert.Equal(t, uint8(gethtypes.LegacyTxType), tx.Type())
+		assert.Equal(t, big.NewInt(99_000), tx.GasPrice())
+	})
+
+	t.Run("explicit_limit_and_nonce_skip_lookups", func(t *testing.T) {
+		backend := &fakeTxBackend{baseFee: big.NewInt(10_000), tipCap: big.NewInt(1_000), nonce: 1}
+		auth := newAuth(t)
+		auth.GasLimit = 123_456
+		auth.Nonce = big.NewInt(42)
+
+		tx, err := SendTx(context.Background(), backend, auth, to, nil, data)
+		require.NoError(t, err)
+		assert.Empty(t, backend.estimated)
+		assert.Equal(t, uint64(123_456), tx.Gas())
+		assert.Equal(t, uint64(42), tx.Nonce())
+	})
+
+	t.Run("estimate_failure_is_not_sent", func(t *testing.T) {
+		backend := &fakeTxBackend{baseFee: big.NewInt(10_000), tipCap: big.NewInt(1_000), estimateErr: errors.New("execution reverted")}
+
+		_, err := SendTx(context.Background(), backend, newAuth(t), to, nil, data)
+		assert.ErrorContains(t, err, "failed to estimate gas")
+		assert.Empty(t, backend.sent)
+	})
+}
+
+func TestGasLimitMultiplier(t *testing.T) {
+	for value, want := range map[string]float64{"": DefaultGasLimitMultiplier, "1.5": 1.5, "0.5": DefaultGasLimitMultiplier, "abc": DefaultGasLimitMultiplier} {
+		t.Setenv("GAS_LIMIT_MULTIPLIER", value)
+		assert.Equal(t, want, GasLimitMultiplier(), value)
+	}
+}
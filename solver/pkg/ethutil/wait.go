@@ -0,0 +1,139 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+// Defaults for WaitForTransaction
+const (
+	DefaultTxWaitTimeout  = 5 * time.Minute
+	DefaultTxPollInterval = time.Second
+	// DefaultTxSettleDelay is slept after the receipt arrives: Base sometimes serves the state changes of a
+	// mined transaction with some latency, and callers read balances right after waiting
+	DefaultTxSettleDelay = 2 * time.Second
+	// droppedPolls is how many consecutive polls the node must not know a pending transaction before it is
+	// reported dropped, so a node that is slow to index a fresh transaction is not mistaken for a drop
+	droppedPolls = 3
+)
+
+var (
+	// ErrTxReplaced means another transaction with the same nonce was mined instead; retry with a higher fee
+	ErrTxReplaced = errors.New("transaction replaced")
+	// ErrTxDropped means the node no longer knows the transaction and its nonce is unused; resend it
+	ErrTxDropped = errors.New("transaction dropped")
+)
+
+// TxWaitBackend is the part of an RPC client WaitForTransaction needs; *ethclient.Client implements it
+type TxWaitBackend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*gethtypes.Transaction, bool, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+type waitOptions struct {
+	timeout           time.Duration
+	pollInterval      time.Duration
+	settleDelay       time.Duration
+	detectReplacement bool
+}
+
+// WaitOption configures WaitForTransaction
+type WaitOption func(*waitOptions)
+
+// WithWaitTimeout bounds the whole wait (default DefaultTxWaitTimeout)
+func WithWaitTimeout(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.timeout = d }
+}
+
+// WithPollInterval sets how often the receipt is polled (default DefaultTxPollInterval)
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.pollInterval = d }
+}
+
+// WithSettleDelay sets the pause after the receipt arrives (default DefaultTxSettleDelay)
+func WithSettleDelay(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.settleDelay = d }
+}
+
+// WithReplacementDetection makes WaitForTransaction return ErrTxReplaced once the sender's mined nonce passes
+// the transaction's without it landing, and ErrTxDropped once the node forgets a transaction whose nonce is unused
+func WithReplacementDetection() WaitOption {
+	return func(o *waitOptions) { o.detectReplacement = true }
+}
+
+// WaitForTransaction polls until tx is mined and returns its receipt, recording its cost into ctx's txcost
+// ledger. It gives up when ctx is done or the timeout expires; receipt lookup errors other than not-found are
+// retried until then
+func WaitForTransaction(ctx context.Context, client TxWaitBackend, tx *gethtypes.Transaction, opts ...WaitOption) (*gethtypes.Receipt, error) {
+	o := waitOptions{timeout: DefaultTxWaitTimeout, pollInterval: DefaultTxPollInterval, settleDelay: DefaultTxSettleDelay}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var from common.Address
+	if o.detectReplacement {
+		sender, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recover sender of %s: %w", tx.Hash().Hex(), err)
+		}
+		from = sender
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(o.pollInterval)
+	defer ticker.Stop()
+
+	missing := 0
+	for {
+		// The mined nonce is read before the receipt: if it already covers tx and the receipt is still
+		// missing afterwards, a different transaction used the nonce
+		var minedNonce uint64
+		var nonceErr error
+		if o.detectReplacement {
+			minedNonce, nonceErr = client.NonceAt(ctx, from, nil)
+		}
+
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		if err == nil && receipt != nil {
+			if o.settleDelay > 0 {
+				select {
+				case <-time.After(o.settleDelay):
+				case <-ctx.Done():
+				}
+			}
+			txcost.RecordEVM(ctx, tx, receipt)
+			return receipt, nil
+		}
+
+		if o.detectReplacement && nonceErr == nil && errors.Is(err, ethereum.NotFound) {
+			if minedNonce > tx.Nonce() {
+				return nil, fmt.Errorf("%w: nonce %d of %s was used by another transaction", ErrTxReplaced, tx.Nonce(), tx.Hash().Hex())
+			}
+			if _, _, err := client.TransactionByHash(ctx, tx.Hash()); errors.Is(err, ethereum.NotFound) {
+				if missing++; missing >= droppedPolls {
+					return nil, fmt.Errorf("%w: %s is no longer known to the node", ErrTxDropped, tx.Hash().Hex())
+				}
+			} else {
+				missing = 0
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up waiting for %s: %w", tx.Hash().Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
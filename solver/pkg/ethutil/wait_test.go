@@ -0,0 +1,121 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mempoolBackend serves a transaction that is mined after minedAfter receipt polls (never when 0). Once
+// nonceAfter polls have passed, the sender's mined nonce is nonce; known reports whether the node still
+// has the transaction
+type mempoolBackend struct {
+	minedAfter int
+	nonceAfter int
+	nonce      uint64
+	known      bool
+	receiptErr error
+
+	polls int
+}
+
+func (b *mempoolBackend) TransactionReceipt(context.Context, common.Hash) (*gethtypes.Receipt, error) {
+	b.polls++
+	if b.receiptErr != nil {
+		return nil, b.receiptErr
+	}
+	if b.minedAfter > 0 && b.polls >= b.minedAfter {
+		return &gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful, BlockNumber: big.NewInt(int64(b.polls))}, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (b *mempoolBackend) TransactionByHash(context.Context, common.Hash) (*gethtypes.Transaction, bool, error) {
+	if !b.known {
+		return nil, false, ethereum.NotFound
+	}
+	return nil, true, nil
+}
+
+func (b *mempoolBackend) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	if b.polls >= b.nonceAfter {
+		return b.nonce, nil
+	}
+	return 0, nil
+}
+
+func signedTestTx(t *testing.T, nonce uint64) *gethtypes.Transaction {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(31337)
+	tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainID), &gethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestWaitForTransaction(t *testing.T) {
+	fast := []WaitOption{WithPollInterval(time.Millisecond), WithSettleDelay(0), WithWaitTimeout(time.Second)}
+
+	t.Run("returns receipt once mined", func(t *testing.T) {
+		backend := &mempoolBackend{minedAfter: 3, known: true}
+		receipt, err := WaitForTransaction(context.Background(), backend, signedTestTx(t, 5), fast...)
+		require.NoError(t, err)
+		assert.Equal(t, gethtypes.ReceiptStatusSuccessful, receipt.Status)
+		assert.Equal(t, 3, backend.polls)
+	})
+
+	t.Run("replaced transaction", func(t *testing.T) {
+		// Another transaction with nonce 5 lands on the second poll, so the sender's nonce moves to 6
+		backend := &mempoolBackend{nonceAfter: 1, nonce: 6}
+		opts := append(fast, WithReplacementDetection())
+		_, err := WaitForTransaction(context.Background(), backend, signedTestTx(t, 5), opts...)
+		assert.ErrorIs(t, err, ErrTxReplaced)
+	})
+
+	t.Run("dropped transaction", func(t *testing.T) {
+		backend := &mempoolBackend{known: false, nonce: 5}
+		opts := append(fast, WithReplacementDetection())
+		_, err := WaitForTransaction(context.Background(), backend, signedTestTx(t, 5), opts...)
+		assert.ErrorIs(t, err, ErrTxDropped)
+		assert.Equal(t, droppedPolls, backend.polls)
+	})
+
+	t.Run("pending transaction is neither", func(t *testing.T) {
+		backend := &mempoolBackend{known: true, nonce: 5}
+		opts := append(fast, WithReplacementDetection(), WithWaitTimeout(20*time.Millisecond))
+		_, err := WaitForTransaction(context.Background(), backend, signedTestTx(t, 5), opts...)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, err, ErrTxDropped)
+	})
+
+	t.Run("without detection a dropped transaction times out", func(t *testing.T) {
+		backend := &mempoolBackend{known: false, nonce: 6}
+		opts := append(fast, WithWaitTimeout(20*time.Millisecond))
+		_, err := WaitForTransaction(context.Background(), backend, signedTestTx(t, 5), opts...)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("cancelled context stops the wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		backend := &mempoolBackend{receiptErr: errors.New("connection refused")}
+		_, err := WaitForTransaction(ctx, backend, signedTestTx(t, 5), fast...)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
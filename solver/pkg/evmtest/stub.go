@@ -0,0 +1,112 @@
+package evmtest
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EVM opcodes the stub is assembled from
+const (
+	opCallDataLoad = 0x35
+	opCodeCopy     = 0x39
+	opSHR          = 0x1c
+	opEQ           = 0x14
+	opJumpI        = 0x57
+	opJumpDest     = 0x5b
+	opPush1        = 0x60
+	opPush2        = 0x61
+	opPush4        = 0x63
+	opPush32       = 0x7f
+	opDup1         = 0x80
+	opLog0         = 0xa0
+	opReturn       = 0xf3
+	opRevert       = 0xfd
+)
+
+// Call is how a stub answers one function: it emits Logs in order, then returns Return
+type Call struct {
+	Selector [4]byte
+	Return   []byte
+	Logs     []Log
+}
+
+// Log is an event a stub emits
+type Log struct {
+	Topics []common.Hash
+	Data   []byte
+}
+
+// Answer is a Call for method that returns its outputs packed with the method's ABI
+func Answer(method abi.Method, outputs ...interface{}) (Call, error) {
+	data, err := method.Outputs.Pack(outputs...)
+	if err != nil {
+		return Call{}, fmt.Errorf("failed to pack %s outputs: %w", method.Name, err)
+	}
+	var selector [4]byte
+	copy(selector[:], method.ID)
+	return Call{Selector: selector, Return: data}, nil
+}
+
+// Stub returns the init code of a contract that answers every call in calls and reverts on any other selector.
+// It accepts value, and ignores the call's arguments
+func Stub(calls ...Call) []byte {
+	const dispatchEntry = 11 // DUP1 PUSH4 selector EQ PUSH2 dest JUMPI
+	const copyToMemory = 9   // PUSH2 size PUSH2 offset PUSH1 0 CODECOPY
+	header := []byte{opPush1, 0x00, opCallDataLoad, opPush1, 0xe0, opSHR}
+	fallback := []byte{opPush1, 0x00, opDup1, opRevert}
+
+	// Lay the code out first: the bodies, then the data they copy from
+	bodySize := func(call Call) int {
+		size := 1 + copyToMemory + 6 // JUMPDEST, then copy and PUSH2 size PUSH1 0 RETURN
+		for _, log := range call.Logs {
+			size += copyToMemory + 33*len(log.Topics) + 6 // copy, PUSH32 topics, PUSH2 size PUSH1 0 LOGn
+		}
+		return size
+	}
+	codeSize := len(header) + dispatchEntry*len(calls) + len(fallback)
+	bodies := make([]int, len(calls))
+	for i, call := range calls {
+		bodies[i] = codeSize
+		codeSize += bodySize(call)
+	}
+
+	code := append([]byte{}, header...)
+	for i, call := range calls {
+		code = append(code, opDup1, opPush4)
+		code = append(code, call.Selector[:]...)
+		code = append(code, opEQ, opPush2, byte(bodies[i]>>8), byte(bodies[i]), opJumpI)
+	}
+	code = append(code, fallback...)
+
+	var data []byte
+	copyData := func(chunk []byte) {
+		offset := codeSize + len(data)
+		data = append(data, chunk...)
+		code = append(code, opPush2, byte(len(chunk)>>8), byte(len(chunk)), opPush2, byte(offset>>8), byte(offset),
+			opPush1, 0x00, opCodeCopy)
+	}
+	for _, call := range calls {
+		code = append(code, opJumpDest)
+		for _, log := range call.Logs {
+			copyData(log.Data)
+			for i := len(log.Topics) - 1; i >= 0; i-- {
+				code = append(code, opPush32)
+				code = append(code, log.Topics[i].Bytes()...)
+			}
+			code = append(code, opPush2, byte(len(log.Data)>>8), byte(len(log.Data)), opPush1, 0x00, byte(opLog0+len(log.Topics)))
+		}
+		copyData(call.Return)
+		code = append(code, opPush2, byte(len(call.Return)>>8), byte(len(call.Return)), opPush1, 0x00, opReturn)
+	}
+	runtime := append(code, data...)
+
+	// Init code: copy the runtime code that follows it into memory and return it
+	const initSize = 15
+	initCode := []byte{
+		opPush2, byte(len(runtime) >> 8), byte(len(runtime)), opPush2, 0x00, initSize, opPush1, 0x00, opCodeCopy,
+		opPush2, byte(len(runtime) >> 8), byte(len(runtime)), opPush1, 0x00, opReturn,
+	}
+	return append(initCode, runtime...)
+}
@@ -0,0 +1,68 @@
+package evmtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStub(t *testing.T) {
+	chain := NewChain(t)
+	topic := common.HexToHash("0x01")
+	indexed := common.HexToHash("0xabcd")
+	stub := chain.Deploy(t, Stub(
+		Call{Selector: [4]byte{0x11, 0x22, 0x33, 0x44}, Return: common.LeftPadBytes([]byte{42}, 32)},
+		Call{
+			Selector: [4]byte{0xde, 0xad, 0xbe, 0xef},
+			Logs: []Log{
+				{Topics: []common.Hash{topic, indexed}, Data: []byte("first")},
+				{Data: []byte("second")},
+			},
+		},
+	))
+
+	tests := []struct {
+		name     string
+		calldata []byte
+		want     []byte
+		reverts  bool
+	}{
+		{"answers its selector", []byte{0x11, 0x22, 0x33, 0x44, 0x00}, common.LeftPadBytes([]byte{42}, 32), false},
+		{"returns nothing", []byte{0xde, 0xad, 0xbe, 0xef}, []byte{}, false},
+		{"reverts on other selectors", []byte{0x11, 0x22, 0x33, 0x45}, nil, true},
+		{"reverts without a selector", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chain.Client.CallContract(context.Background(), ethereum.CallMsg{To: &stub, Data: tt.calldata}, nil)
+			if tt.reverts {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Sending value to the logging selector mines both logs, in order
+	opts := chain.Transactor(t)
+	nonce, err := chain.Client.PendingNonceAt(context.Background(), chain.From)
+	require.NoError(t, err)
+	gasPrice, err := chain.Client.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	tx, err := opts.Signer(chain.From, types.NewTransaction(nonce, stub, big.NewInt(7), 100000, gasPrice, []byte{0xde, 0xad, 0xbe, 0xef}))
+	require.NoError(t, err)
+	require.NoError(t, chain.Client.SendTransaction(context.Background(), tx))
+	receipt := chain.Mine(t, tx)
+	require.Len(t, receipt.Logs, 2)
+	assert.Equal(t, []common.Hash{topic, indexed}, receipt.Logs[0].Topics)
+	assert.Equal(t, []byte("first"), receipt.Logs[0].Data)
+	assert.Empty(t, receipt.Logs[1].Topics)
+	assert.Equal(t, []byte("second"), receipt.Logs[1].Data)
+}
@@ -0,0 +1,87 @@
+// Package fillerdata builds and reads the fillerData a filler passes to fill().
+//
+// The destination settler stores fillerData with the fill and sends it back to the origin in the settle message,
+// where it is decoded as the bytes32 receiver of the escrowed input. Its layout therefore follows the origin
+// chain: an EVM origin reads a 20-byte address left-padded to 32 bytes, a Cairo origin (Starknet or Ztarknet) a
+// ContractAddress felt as a big-endian word. A zero receiver would release the input to nobody, so both builders
+// refuse it.
+package fillerdata
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// Size is the length of fillerData: one bytes32 receiver
+const Size = 32
+
+// ErrZeroReceiver is returned for a zero receiver address
+var ErrZeroReceiver = errors.New("fillerData receiver must not be zero")
+
+// BuildFillerDataEVM encodes an EVM receiver as its address left-padded to bytes32
+func BuildFillerDataEVM(receiver common.Address) ([]byte, error) {
+	if receiver == (common.Address{}) {
+		return nil, ErrZeroReceiver
+	}
+	word := starknetutil.EVMAddressToBytes32(receiver)
+	return word[:], nil
+}
+
+// BuildFillerDataStarknet encodes a Starknet or Ztarknet receiver as its ContractAddress felt, big-endian
+func BuildFillerDataStarknet(receiver *felt.Felt) ([]byte, error) {
+	if receiver == nil || receiver.IsZero() {
+		return nil, ErrZeroReceiver
+	}
+	word := starknetutil.FeltToBytes32(receiver)
+	return word[:], nil
+}
+
+// ParseStarknetAddress parses a 0x Starknet address, refusing values that are not below the field prime rather
+// than reducing them to another address
+func ParseStarknetAddress(address string) (*felt.Felt, error) {
+	digits, ok := strings.CutPrefix(strings.ToLower(address), "0x")
+	value, valid := new(big.Int).SetString(digits, 16)
+	if !ok || !valid || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid Starknet address %q: expected a 0x felt", address)
+	}
+	if value.BitLen() > 8*Size {
+		return nil, fmt.Errorf("address %s exceeds the felt field", address)
+	}
+	var word [Size]byte
+	value.FillBytes(word[:])
+	return starknetutil.Bytes32ToFelt(word)
+}
+
+// ReceiverEVM reads the EVM receiver back from fillerData
+func ReceiverEVM(data []byte) (common.Address, error) {
+	word, err := toWord(data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return starknetutil.Bytes32ToEVMAddress(word)
+}
+
+// ReceiverStarknet reads the Starknet or Ztarknet receiver back from fillerData
+func ReceiverStarknet(data []byte) (*felt.Felt, error) {
+	word, err := toWord(data)
+	if err != nil {
+		return nil, err
+	}
+	return starknetutil.Bytes32ToFelt(word)
+}
+
+func toWord(data []byte) ([Size]byte, error) {
+	var word [Size]byte
+	if len(data) != Size {
+		return word, fmt.Errorf("fillerData is %d bytes, expected %d", len(data), Size)
+	}
+	copy(word[:], data)
+	return word, nil
+}
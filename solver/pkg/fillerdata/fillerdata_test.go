@@ -0,0 +1,86 @@
+package fillerdata
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testEVMSolver      = "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"
+	testStarknetSolver = "0x2af9427c5a277474c079a1283c880ee8a6f0f8fbf73ce969c08d88befec1bba"
+)
+
+func TestBuildFillerDataEVM(t *testing.T) {
+	data, err := BuildFillerDataEVM(common.HexToAddress(testEVMSolver))
+	require.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000003c44cdddb6a900fa2b585dd299e03d12fa4293bc", hex.EncodeToString(data))
+
+	receiver, err := ReceiverEVM(data)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(testEVMSolver), receiver)
+
+	_, err = BuildFillerDataEVM(common.Address{})
+	assert.ErrorIs(t, err, ErrZeroReceiver)
+}
+
+func TestBuildFillerDataStarknet(t *testing.T) {
+	solver, err := ParseStarknetAddress(testStarknetSolver)
+	require.NoError(t, err)
+	data, err := BuildFillerDataStarknet(solver)
+	require.NoError(t, err)
+	assert.Equal(t, "02af9427c5a277474c079a1283c880ee8a6f0f8fbf73ce969c08d88befec1bba", hex.EncodeToString(data))
+
+	receiver, err := ReceiverStarknet(data)
+	require.NoError(t, err)
+	assert.True(t, solver.Equal(receiver))
+
+	// A felt word read as an EVM address has non-zero padding
+	_, err = ReceiverEVM(data)
+	assert.Error(t, err)
+
+	_, err = BuildFillerDataStarknet(new(felt.Felt))
+	assert.ErrorIs(t, err, ErrZeroReceiver)
+	_, err = BuildFillerDataStarknet(nil)
+	assert.ErrorIs(t, err, ErrZeroReceiver)
+}
+
+func TestParseStarknetAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		err     string
+	}{
+		{name: "address", address: testStarknetSolver, want: testStarknetSolver},
+		{name: "upper-case prefix", address: "0X1", want: "0x1"},
+		{name: "largest felt", address: "0x800000000000011000000000000000000000000000000000000000000000000", want: "0x800000000000011000000000000000000000000000000000000000000000000"},
+		{name: "field prime", address: "0x800000000000011000000000000000000000000000000000000000000000001", err: "exceeds the felt field"},
+		{name: "wider than a word", address: "0x1" + "00000000000000000000000000000000000000000000000000000000000000000", err: "exceeds the felt field"},
+		{name: "no prefix", address: "1234", err: "expected a 0x felt"},
+		{name: "not hex", address: "0xzz", err: "expected a 0x felt"},
+		{name: "negative", address: "0x-1", err: "expected a 0x felt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStarknetAddress(tt.address)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestReceiverLength(t *testing.T) {
+	_, err := ReceiverEVM(make([]byte, 20))
+	assert.ErrorContains(t, err, "fillerData is 20 bytes, expected 32")
+	_, err = ReceiverStarknet(nil)
+	assert.ErrorContains(t, err, "fillerData is 0 bytes")
+}
@@ -0,0 +1,277 @@
+// Package identity maps the logical users the tools act for (Alice, Solver, ...) to their address on each
+// chain type.
+//
+// The registry starts from the Alice and Solver accounts configured in .env (honouring IS_DEVNET) and is
+// extended by state/identities.json (IDENTITIES_FILE overrides the path), whose entries add users or replace
+// the .env ones of the same name:
+//
+//	[{"name": "Bob", "evmAddress": "0x...", "starknetAddress": "0x...", "starknetPublicKey": "0x..."}]
+//
+// Every address is checked when the registry is loaded, and no two users may share a name or an address on
+// the same chain type.
+package identity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+// DefaultName is the registry file in the state directory
+const DefaultName = "identities.json"
+
+// Chain is the type of chain an address lives on
+type Chain string
+
+const (
+	EVM      Chain = "EVM"
+	Starknet Chain = "Starknet"
+	Ztarknet Chain = "Ztarknet"
+)
+
+// Identity is one user's accounts. Empty fields mean the user has no account on that chain type
+type Identity struct {
+	Name              string `json:"name"`
+	EVMAddress        string `json:"evmAddress,omitempty"`
+	StarknetAddress   string `json:"starknetAddress,omitempty"`
+	StarknetPublicKey string `json:"starknetPublicKey,omitempty"`
+	ZtarknetAddress   string `json:"ztarknetAddress,omitempty"`
+	ZtarknetPublicKey string `json:"ztarknetPublicKey,omitempty"`
+}
+
+// AddressOn returns the user's address on chain, or an error when they have none there
+func (id Identity) AddressOn(chain Chain) (string, error) {
+	var address string
+	switch chain {
+	case EVM:
+		address = id.EVMAddress
+	case Starknet:
+		address = id.StarknetAddress
+	case Ztarknet:
+		address = id.ZtarknetAddress
+	default:
+		return "", fmt.Errorf("unknown chain type %q", chain)
+	}
+	if address == "" {
+		return "", fmt.Errorf("user %s has no %s address", id.Name, chain)
+	}
+	return address, nil
+}
+
+// PublicKeyOn returns the user's registered account public key on a Cairo chain, empty when none is registered
+func (id Identity) PublicKeyOn(chain Chain) (string, error) {
+	switch chain {
+	case Starknet:
+		return id.StarknetPublicKey, nil
+	case Ztarknet:
+		return id.ZtarknetPublicKey, nil
+	default:
+		return "", fmt.Errorf("%s accounts have no registered public key", chain)
+	}
+}
+
+// validate checks the name is set and every configured address and public key parses
+func (id Identity) validate() error {
+	if strings.TrimSpace(id.Name) == "" {
+		return fmt.Errorf("identity without a name")
+	}
+	if id.EVMAddress != "" && !common.IsHexAddress(id.EVMAddress) {
+		return fmt.Errorf("user %s: invalid EVM address %q", id.Name, id.EVMAddress)
+	}
+	for _, f := range []struct{ field, value string }{
+		{"Starknet address", id.StarknetAddress},
+		{"Starknet public key", id.StarknetPublicKey},
+		{"Ztarknet address", id.ZtarknetAddress},
+		{"Ztarknet public key", id.ZtarknetPublicKey},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if _, err := utils.HexToFelt(f.value); err != nil {
+			return fmt.Errorf("user %s: invalid %s %q: %w", id.Name, f.field, f.value, err)
+		}
+	}
+	return nil
+}
+
+// Registry is a validated set of identities
+type Registry struct {
+	identities []Identity // sorted by name
+}
+
+// New validates identities and returns their registry
+func New(identities []Identity) (*Registry, error) {
+	r := &Registry{identities: append([]Identity(nil), identities...)}
+	sort.Slice(r.identities, func(i, j int) bool {
+		return strings.ToLower(r.identities[i].Name) < strings.ToLower(r.identities[j].Name)
+	})
+
+	names := make(map[string]bool, len(identities))
+	owners := make(map[Chain]map[string]string)
+	for _, id := range r.identities {
+		if err := id.validate(); err != nil {
+			return nil, err
+		}
+		name := strings.ToLower(id.Name)
+		if names[name] {
+			return nil, fmt.Errorf("duplicate user %s", id.Name)
+		}
+		names[name] = true
+
+		for _, chain := range []Chain{EVM, Starknet, Ztarknet} {
+			address, err := id.AddressOn(chain)
+			if err != nil {
+				continue
+			}
+			key := canonicalAddress(chain, address)
+			if owners[chain] == nil {
+				owners[chain] = make(map[string]string)
+			}
+			if owner, taken := owners[chain][key]; taken {
+				return nil, fmt.Errorf("users %s and %s share the %s address %s", owner, id.Name, chain, address)
+			}
+			owners[chain][key] = id.Name
+		}
+	}
+	return r, nil
+}
+
+// canonicalAddress normalizes a validated address so differently written copies compare equal
+func canonicalAddress(chain Chain, address string) string {
+	if chain == EVM {
+		return common.HexToAddress(address).Hex()
+	}
+	f, _ := utils.HexToFelt(address)
+	return f.String()
+}
+
+// Identities returns the registered identities sorted by name
+func (r *Registry) Identities() []Identity {
+	return append([]Identity(nil), r.identities...)
+}
+
+// Lookup returns the identity named name (case-insensitive), or an error listing the known users
+func (r *Registry) Lookup(name string) (Identity, error) {
+	names := make([]string, len(r.identities))
+	for i, id := range r.identities {
+		if strings.EqualFold(id.Name, name) {
+			return id, nil
+		}
+		names[i] = id.Name
+	}
+	return Identity{}, fmt.Errorf("unknown user %q (known: %s); register it with `solver tools identities add`", name, strings.Join(names, ", "))
+}
+
+// FromEnv returns the Alice and Solver identities configured in .env. Unset values and example.env
+// placeholders ("your ... public key") are left empty
+func FromEnv() []Identity {
+	return []Identity{
+		{
+			Name:              "Alice",
+			EVMAddress:        envValue(envutil.GetAlicePublicKey()),
+			StarknetAddress:   envValue(envutil.GetStarknetAliceAddress()),
+			StarknetPublicKey: envValue(envutil.GetStarknetAlicePublicKey()),
+			ZtarknetAddress:   envValue(envutil.GetZtarknetAliceAddress()),
+			ZtarknetPublicKey: envValue(envutil.GetZtarknetAlicePublicKey()),
+		},
+		{
+			Name:              "Solver",
+			EVMAddress:        envValue(envutil.GetSolverPublicKey()),
+			StarknetAddress:   envValue(envutil.GetStarknetSolverAddress()),
+			StarknetPublicKey: envValue(envutil.GetStarknetSolverPublicKey()),
+			ZtarknetAddress:   envValue(envutil.GetZtarknetSolverAddress()),
+			ZtarknetPublicKey: envValue(envutil.GetZtarknetSolverPublicKey()),
+		},
+	}
+}
+
+// envValue drops example.env placeholders
+func envValue(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, " ") {
+		return ""
+	}
+	return value
+}
+
+// Path returns the registry file: IDENTITIES_FILE, or DefaultName in the state directory
+func Path() string {
+	return envutil.GetEnvWithDefault("IDENTITIES_FILE", solverdir.StatePath(DefaultName))
+}
+
+// Load returns the registry of the .env identities extended by the file at path. A missing file is not an
+// error: the registry is then the .env identities alone
+func Load(path string) (*Registry, error) {
+	stored, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := New(merge(FromEnv(), stored))
+	if err != nil {
+		return nil, fmt.Errorf("invalid identities (.env and %s): %w", path, err)
+	}
+	return r, nil
+}
+
+// ReadFile returns the identities stored at path, none when the file does not exist
+func ReadFile(path string) ([]Identity, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identities: %w", err)
+	}
+	var stored []Identity
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("identities file %s is not a JSON list of identities: %w", path, err)
+	}
+	return stored, nil
+}
+
+// Add stores id at path after checking the resulting registry is valid. A stored user of the same name is an
+// error; .env users may be overridden
+func Add(path string, id Identity) error {
+	return deploystate.Update(path, func(stored *[]Identity) error {
+		for _, existing := range *stored {
+			if strings.EqualFold(existing.Name, id.Name) {
+				return fmt.Errorf("user %s is already registered in %s", existing.Name, path)
+			}
+		}
+		next := append(append([]Identity(nil), *stored...), id)
+		if _, err := New(merge(FromEnv(), next)); err != nil {
+			return err
+		}
+		*stored = next
+		return nil
+	})
+}
+
+// merge returns base with every identity of overrides replacing the one of the same name, or appended
+func merge(base, overrides []Identity) []Identity {
+	merged := append([]Identity(nil), base...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range merged {
+			if strings.EqualFold(merged[i].Name, override.Name) {
+				merged[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
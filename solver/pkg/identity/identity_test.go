@@ -0,0 +1,159 @@
+package identity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	aliceEVM      = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	aliceStarknet = "0x13d9ee239f33fea4f8785b9e3870ade909e20a9599ae7cd62c1c292b73af1b7"
+	solverEVM     = "0x90F79bf6EB2c4f870365E785982E1f101E93b906"
+	bobEVM        = "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"
+	bobStarknet   = "0x4c339f18b9d1b95b64a6d378abd1480b2e0d5d5bd33cd0828cbce4d65c27284"
+)
+
+// setEnvUsers configures Alice and the solver in .env as live (IS_DEVNET=false) accounts
+func setEnvUsers(t *testing.T) {
+	t.Helper()
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("ALICE_PUB_KEY", aliceEVM)
+	t.Setenv("SOLVER_PUB_KEY", solverEVM)
+	t.Setenv("STARKNET_ALICE_ADDRESS", aliceStarknet)
+	t.Setenv("STARKNET_ALICE_PUBLIC_KEY", "your starknet public key")
+	t.Setenv("STARKNET_SOLVER_ADDRESS", "")
+	t.Setenv("STARKNET_SOLVER_PUBLIC_KEY", "")
+	for _, name := range []string{"ZTARKNET_ALICE_ADDRESS", "ZTARKNET_ALICE_PUBLIC_KEY", "ZTARKNET_SOLVER_ADDRESS", "ZTARKNET_SOLVER_PUBLIC_KEY"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		identities []Identity
+		wantErr    string
+	}{
+		{
+			name:       "valid",
+			identities: []Identity{{Name: "Alice", EVMAddress: aliceEVM, StarknetAddress: aliceStarknet}, {Name: "Bob", EVMAddress: bobEVM}},
+		},
+		{
+			name:       "no_name",
+			identities: []Identity{{EVMAddress: aliceEVM}},
+			wantErr:    "identity without a name",
+		},
+		{
+			name:       "duplicate_name",
+			identities: []Identity{{Name: "Alice"}, {Name: "alice"}},
+			wantErr:    "duplicate user alice",
+		},
+		{
+			name:       "invalid_evm",
+			identities: []Identity{{Name: "Bob", EVMAddress: "0x1234"}},
+			wantErr:    `user Bob: invalid EVM address "0x1234"`,
+		},
+		{
+			name:       "invalid_felt",
+			identities: []Identity{{Name: "Bob", StarknetPublicKey: "0xzz"}},
+			wantErr:    `user Bob: invalid Starknet public key "0xzz"`,
+		},
+		{
+			name:       "shared_evm_address",
+			identities: []Identity{{Name: "Alice", EVMAddress: aliceEVM}, {Name: "Bob", EVMAddress: "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"}},
+			wantErr:    "users Alice and Bob share the EVM address",
+		},
+		{
+			name:       "shared_felt_address",
+			identities: []Identity{{Name: "Alice", StarknetAddress: "0x0abc"}, {Name: "Bob", StarknetAddress: "0xabc"}},
+			wantErr:    "users Alice and Bob share the Starknet address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.identities)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	r, err := New([]Identity{{Name: "Bob", EVMAddress: bobEVM}, {Name: "Alice", EVMAddress: aliceEVM, StarknetAddress: aliceStarknet}})
+	require.NoError(t, err)
+
+	alice, err := r.Lookup("alice")
+	require.NoError(t, err)
+	address, err := alice.AddressOn(Starknet)
+	require.NoError(t, err)
+	assert.Equal(t, aliceStarknet, address)
+
+	_, err = alice.AddressOn(Ztarknet)
+	assert.EqualError(t, err, "user Alice has no Ztarknet address")
+
+	_, err = r.Lookup("Mallory")
+	assert.ErrorContains(t, err, `unknown user "Mallory" (known: Alice, Bob)`)
+}
+
+func TestLoad(t *testing.T) {
+	setEnvUsers(t)
+	dir := t.TempDir()
+
+	t.Run("env_only", func(t *testing.T) {
+		r, err := Load(filepath.Join(dir, "missing.json"))
+		require.NoError(t, err)
+		require.Len(t, r.Identities(), 2)
+		alice, err := r.Lookup("Alice")
+		require.NoError(t, err)
+		assert.Equal(t, Identity{Name: "Alice", EVMAddress: aliceEVM, StarknetAddress: aliceStarknet}, alice, "placeholders are dropped")
+	})
+
+	t.Run("file_extends_and_overrides", func(t *testing.T) {
+		path := filepath.Join(dir, "identities.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[
+			{"name": "Bob", "evmAddress": "`+bobEVM+`", "starknetAddress": "`+bobStarknet+`"},
+			{"name": "solver", "evmAddress": "`+solverEVM+`"}
+		]`), 0o600))
+
+		r, err := Load(path)
+		require.NoError(t, err)
+		names := []string{}
+		for _, id := range r.Identities() {
+			names = append(names, id.Name)
+		}
+		assert.Equal(t, []string{"Alice", "Bob", "solver"}, names)
+	})
+
+	t.Run("invalid_file", func(t *testing.T) {
+		path := filepath.Join(dir, "clash.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[{"name": "Bob", "starknetAddress": "`+aliceStarknet+`"}]`), 0o600))
+		_, err := Load(path)
+		assert.ErrorContains(t, err, "users Alice and Bob share the Starknet address")
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"Bob": {}}`), 0o600))
+		_, err = Load(path)
+		assert.ErrorContains(t, err, "is not a JSON list of identities")
+	})
+}
+
+func TestAdd(t *testing.T) {
+	setEnvUsers(t)
+	path := filepath.Join(t.TempDir(), "identities.json")
+
+	require.NoError(t, Add(path, Identity{Name: "Bob", EVMAddress: bobEVM}))
+	assert.ErrorContains(t, Add(path, Identity{Name: "bob", StarknetAddress: bobStarknet}), "user Bob is already registered")
+	assert.ErrorContains(t, Add(path, Identity{Name: "Carol", EVMAddress: aliceEVM}), "users Alice and Carol share the EVM address")
+	assert.ErrorContains(t, Add(path, Identity{Name: "Carol", EVMAddress: "not an address"}), "invalid EVM address")
+
+	stored, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Identity{{Name: "Bob", EVMAddress: bobEVM}}, stored, "rejected users are not written")
+}
@@ -0,0 +1,109 @@
+package multicall3
+
+// Module: Multicall3 call aggregation
+// - Packs aggregate3(Call3[]) and unpacks its Result[], one result per call in order
+// - Simulates an aggregation through eth_call, so each call's success and return data are known before sending
+// - Checks whether Multicall3 is deployed, at its canonical address on public chains and anvil alike
+// Calls made through Multicall3 have it as msg.sender: only calls that do not depend on the caller (such as
+// signed openFor orders) can be aggregated on a user's behalf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Address is where Multicall3 is deployed on every chain that has it
+var Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// ErrNotDeployed is returned when a chain has no code at the Multicall3 address
+var ErrNotDeployed = errors.New("multicall3 is not deployed")
+
+// aggregate3ABI is the minimal ABI for Multicall3.aggregate3
+const aggregate3ABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// Call3 is one call of an aggregation. A failing call with AllowFailure unset reverts the whole aggregation
+type Call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Result is the outcome of one call: its return data, or its revert data when it failed
+type Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+func aggregate3() (abi.Method, error) {
+	parsed, err := abi.JSON(strings.NewReader(aggregate3ABI))
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("failed to parse Multicall3 ABI: %w", err)
+	}
+	return parsed.Methods["aggregate3"], nil
+}
+
+// Pack ABI-encodes the aggregate3(calls) call
+func Pack(calls []Call3) ([]byte, error) {
+	method, err := aggregate3()
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Pack(calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack aggregate3: %w", err)
+	}
+	return append(append([]byte{}, method.ID...), args...), nil
+}
+
+// Unpack decodes what aggregate3 returned
+func Unpack(data []byte) ([]Result, error) {
+	method, err := aggregate3()
+	if err != nil {
+		return nil, err
+	}
+	out, err := method.Outputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3 result: %w", err)
+	}
+	results := *abi.ConvertType(out[0], new([]Result)).(*[]Result)
+	return results, nil
+}
+
+// Simulate runs aggregate3(calls) at address through eth_call as from, sending value. It returns one result per call
+func Simulate(ctx context.Context, caller ethereum.ContractCaller, address, from common.Address, value *big.Int, calls []Call3) ([]Result, error) {
+	data, err := Pack(calls)
+	if err != nil {
+		return nil, err
+	}
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{From: from, To: &address, Value: value, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate3 call failed: %w", err)
+	}
+	results, err := Unpack(out)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(results), len(calls))
+	}
+	return results, nil
+}
+
+// CheckDeployed returns ErrNotDeployed when there is no code at address
+func CheckDeployed(ctx context.Context, reader ethereum.ChainStateReader, address common.Address) error {
+	code, err := reader.CodeAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read code at %s: %w", address.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w at %s", ErrNotDeployed, address.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package multicall3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/evmtest"
+)
+
+var testCalls = []Call3{
+	{Target: common.HexToAddress("0x01"), AllowFailure: true, CallData: []byte{0x12, 0x34}},
+	{Target: common.HexToAddress("0x02"), AllowFailure: false, CallData: []byte{}},
+}
+
+func TestPackUnpack(t *testing.T) {
+	data, err := Pack(testCalls)
+	require.NoError(t, err)
+	assert.Equal(t, "0x82ad56cb", hexutil.Encode(data[:4]), "aggregate3((address,bool,bytes)[]) selector")
+
+	method, err := aggregate3()
+	require.NoError(t, err)
+	args, err := method.Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	assert.Equal(t, testCalls, *abi.ConvertType(args[0], new([]Call3)).(*[]Call3))
+
+	want := []Result{{Success: true, ReturnData: []byte{0xab}}, {Success: false, ReturnData: []byte{}}}
+	packed, err := method.Outputs.Pack(want)
+	require.NoError(t, err)
+	got, err := Unpack(packed)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestSimulate(t *testing.T) {
+	chain := evmtest.NewChain(t)
+	method, err := aggregate3()
+	require.NoError(t, err)
+	results := []Result{{Success: true, ReturnData: []byte{0xab}}, {Success: false, ReturnData: []byte("reverted")}}
+	answer, err := evmtest.Answer(method, results)
+	require.NoError(t, err)
+	multicall := chain.Deploy(t, evmtest.Stub(answer))
+	ctx := context.Background()
+
+	got, err := Simulate(ctx, chain.Client, multicall, chain.From, nil, testCalls)
+	require.NoError(t, err)
+	assert.Equal(t, results, got)
+
+	// Every call must get exactly one result
+	_, err = Simulate(ctx, chain.Client, multicall, chain.From, nil, testCalls[:1])
+	assert.ErrorContains(t, err, "aggregate3 returned 2 results for 1 calls")
+
+	_, err = Simulate(ctx, chain.Client, common.HexToAddress("0x0bad"), chain.From, nil, testCalls)
+	assert.Error(t, err)
+}
+
+func TestCheckDeployed(t *testing.T) {
+	chain := evmtest.NewChain(t)
+	multicall := chain.Deploy(t, evmtest.Stub())
+	ctx := context.Background()
+
+	require.NoError(t, CheckDeployed(ctx, chain.Client, multicall))
+	err := CheckDeployed(ctx, chain.Client, Address)
+	assert.ErrorIs(t, err, ErrNotDeployed)
+	assert.ErrorContains(t, err, Address.Hex())
+}
@@ -0,0 +1,64 @@
+// Package orderdeadline types the deadlines of an ERC-7683 order.
+//
+// Hyperlane7683 stores deadlines as uint32 unix seconds on every chain. The EVM and Cairo order builders carry
+// them as a Deadline and convert through Uint32 when encoding, so a value that does not fit (a date past 2106
+// or a unix-millis timestamp passed by mistake) is an error instead of a silently different deadline on the wire.
+package orderdeadline
+
+import (
+	"fmt"
+	"math"
+)
+
+// Max is the latest deadline the contracts can store
+const Max = math.MaxUint32
+
+// millisThreshold is where a deadline stops looking like unix seconds (year 33658) and starts looking like millis
+const millisThreshold = 1_000_000_000_000
+
+// Deadline is an order deadline in unix seconds
+type Deadline uint64
+
+// OverflowError reports a deadline that does not fit the on-chain uint32
+type OverflowError struct {
+	Deadline Deadline
+}
+
+func (e *OverflowError) Error() string {
+	msg := fmt.Sprintf("deadline %d does not fit in uint32 (max %d)", uint64(e.Deadline), uint64(Max))
+	if e.Deadline >= millisThreshold {
+		msg += "; it looks like unix milliseconds"
+	}
+	return msg
+}
+
+// Uint32 returns the deadline as encoded on chain, or an *OverflowError when it does not fit
+func (d Deadline) Uint32() (uint32, error) {
+	if d > Max {
+		return 0, &OverflowError{Deadline: d}
+	}
+	return uint32(d), nil
+}
+
+// Deadlines are the open and fill deadlines of an order
+type Deadlines struct {
+	Open Deadline
+	Fill Deadline
+}
+
+// Validate checks Fill > Open > now and that both fit on chain
+func (d Deadlines) Validate(now uint64) error {
+	if _, err := d.Open.Uint32(); err != nil {
+		return fmt.Errorf("invalid open deadline: %w", err)
+	}
+	if _, err := d.Fill.Uint32(); err != nil {
+		return fmt.Errorf("invalid fill deadline: %w", err)
+	}
+	if uint64(d.Open) <= now {
+		return fmt.Errorf("open deadline %d is not after the current time %d", d.Open, now)
+	}
+	if d.Fill <= d.Open {
+		return fmt.Errorf("fill deadline %d is not after the open deadline %d", d.Fill, d.Open)
+	}
+	return nil
+}
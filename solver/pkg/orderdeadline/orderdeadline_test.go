@@ -0,0 +1,67 @@
+package orderdeadline
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlineUint32(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadline Deadline
+		want     uint32
+		wantErr  string
+	}{
+		{name: "zero", deadline: 0, want: 0},
+		{name: "past_2038", deadline: math.MaxInt32 + 1, want: math.MaxInt32 + 1},
+		{name: "max", deadline: Max, want: math.MaxUint32},
+		{name: "max_plus_one", deadline: Max + 1, wantErr: "deadline 4294967296 does not fit in uint32 (max 4294967295)"},
+		{name: "millis", deadline: 1_700_000_000_000, wantErr: "deadline 1700000000000 does not fit in uint32 (max 4294967295); it looks like unix milliseconds"},
+		{name: "max_uint64", deadline: math.MaxUint64, wantErr: "it looks like unix milliseconds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.deadline.Uint32()
+			if tt.wantErr != "" {
+				var overflow *OverflowError
+				require.ErrorAs(t, err, &overflow)
+				assert.Equal(t, tt.deadline, overflow.Deadline)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDeadlinesValidate(t *testing.T) {
+	const now = 1_700_000_000
+	tests := []struct {
+		name      string
+		deadlines Deadlines
+		wantErr   string
+	}{
+		{name: "valid", deadlines: Deadlines{Open: now + 1, Fill: now + 2}},
+		{name: "valid_at_max", deadlines: Deadlines{Open: now + 1, Fill: Max}},
+		{name: "open_not_after_now", deadlines: Deadlines{Open: now, Fill: now + 2}, wantErr: "open deadline 1700000000 is not after the current time 1700000000"},
+		{name: "fill_not_after_open", deadlines: Deadlines{Open: now + 2, Fill: now + 2}, wantErr: "fill deadline 1700000002 is not after the open deadline 1700000002"},
+		{name: "fill_overflow", deadlines: Deadlines{Open: now + 1, Fill: Max + 1}, wantErr: "invalid fill deadline: deadline 4294967296 does not fit in uint32"},
+		{name: "open_millis", deadlines: Deadlines{Open: now * 1000, Fill: now*1000 + 1}, wantErr: "invalid open deadline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.deadlines.Validate(now)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package orderquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// resolvedOrderType is the ResolvedCrossChainOrder type Base7683 hashes into RESOLVED_CROSS_CHAIN_ORDER_TYPEHASH
+const resolvedOrderType = "ResolvedCrossChainOrder(address user, uint64 originChainId, uint32 openDeadline, uint32 fillDeadline, Output[] maxSpent, Output[] minReceived, FillInstruction[] fillInstructions)Output(bytes32 token, uint256 amount, bytes32 recipient, uint64 chainId)FillInstruction(uint64 destinationChainId, bytes32 destinationSettler, bytes originData)"
+
+// Witness type string framing around the ResolvedCrossChainOrder type, as in Base7683.witnessTypeString
+const (
+	witnessTypePrefix = "ResolvedCrossChainOrder witness)"
+	witnessTypeSuffix = "TokenPermissions(address token,uint256 amount)"
+)
+
+// ContractConstants are the status words and type hash a Hyperlane7683 contract exposes as views
+type ContractConstants struct {
+	Unknown  [32]byte
+	Opened   [32]byte
+	Filled   [32]byte
+	Settled  [32]byte
+	Refunded [32]byte
+	// ResolvedOrderTypeHash is RESOLVED_CROSS_CHAIN_ORDER_TYPEHASH
+	ResolvedOrderTypeHash [32]byte
+}
+
+// KnownConstants returns the constants of the Base7683 version this tree was written against, used offline
+func KnownConstants() ContractConstants {
+	var c ContractConstants
+	copy(c.Opened[:], "OPENED")
+	copy(c.Filled[:], "FILLED")
+	copy(c.Settled[:], "SETTLED")
+	copy(c.Refunded[:], "REFUNDED")
+	c.ResolvedOrderTypeHash = crypto.Keccak256Hash([]byte(resolvedOrderType))
+	return c
+}
+
+// Status maps an orderStatus word to its Status using the contract's own status values
+func (c ContractConstants) Status(word [32]byte) (Status, error) {
+	switch word {
+	case c.Unknown:
+		return StatusUnknown, nil
+	case c.Opened:
+		return StatusOpened, nil
+	case c.Filled:
+		return StatusFilled, nil
+	case c.Settled:
+		return StatusSettled, nil
+	case c.Refunded:
+		return StatusRefunded, nil
+	}
+	return StatusUnknown, fmt.Errorf("unrecognized order status %s", common.Hash(word).Hex())
+}
+
+// CheckWitnessTypeString checks a witnessTypeString wraps the ResolvedCrossChainOrder type the contract hashes, so a
+// Permit2 witness signed with it is the one the contract verifies
+func (c ContractConstants) CheckWitnessTypeString(witnessTypeString string) error {
+	orderType := strings.TrimSuffix(strings.TrimPrefix(witnessTypeString, witnessTypePrefix), witnessTypeSuffix)
+	if len(orderType) == len(witnessTypeString) || crypto.Keccak256Hash([]byte(orderType)) != c.ResolvedOrderTypeHash {
+		return fmt.Errorf("witness type string does not describe RESOLVED_CROSS_CHAIN_ORDER_TYPEHASH %s",
+			common.Hash(c.ResolvedOrderTypeHash).Hex())
+	}
+	return nil
+}
+
+var (
+	// offlineConstants makes LoadConstants answer KnownConstants without RPC
+	offlineConstants bool
+
+	constantsMu    sync.Mutex
+	constantsCache = map[string]ContractConstants{}
+)
+
+// SetOfflineConstants makes LoadConstants return KnownConstants instead of reading the contract, for tests
+// without RPC
+func SetOfflineConstants(enabled bool) {
+	constantsMu.Lock()
+	defer constantsMu.Unlock()
+	offlineConstants = enabled
+}
+
+// LoadConstants returns the constants of the Hyperlane7683 at contract on network, reading them through backend the
+// first time and from the cache afterwards
+func LoadConstants(ctx context.Context, network string, contract common.Address, backend bind.ContractCaller) (ContractConstants, error) {
+	constantsMu.Lock()
+	defer constantsMu.Unlock()
+	if offlineConstants {
+		return KnownConstants(), nil
+	}
+	key := strings.ToLower(network) + "/" + contract.Hex()
+	if c, ok := constantsCache[key]; ok {
+		return c, nil
+	}
+	c, err := ReadConstants(ctx, contract, backend)
+	if err != nil {
+		return ContractConstants{}, fmt.Errorf("failed to read Hyperlane7683 constants on %s: %w", network, err)
+	}
+	constantsCache[key] = c
+	return c, nil
+}
+
+// ReadConstants reads the constants of the Hyperlane7683 at contract, uncached
+func ReadConstants(ctx context.Context, contract common.Address, backend bind.ContractCaller) (ContractConstants, error) {
+	caller, err := contracts.NewHyperlane7683Caller(contract, backend)
+	if err != nil {
+		return ContractConstants{}, fmt.Errorf("failed to bind Hyperlane7683: %w", err)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	var c ContractConstants
+	for _, read := range []struct {
+		name string
+		view func(*bind.CallOpts) ([32]byte, error)
+		out  *[32]byte
+	}{
+		{"UNKNOWN", caller.UNKNOWN, &c.Unknown},
+		{"OPENED", caller.OPENED, &c.Opened},
+		{"FILLED", caller.FILLED, &c.Filled},
+		{"SETTLED", caller.SETTLED, &c.Settled},
+		{"REFUNDED", caller.REFUNDED, &c.Refunded},
+		{"RESOLVED_CROSS_CHAIN_ORDER_TYPEHASH", caller.RESOLVEDCROSSCHAINORDERTYPEHASH, &c.ResolvedOrderTypeHash},
+	} {
+		value, err := read.view(opts)
+		if err != nil {
+			return ContractConstants{}, fmt.Errorf("%s call failed: %w", read.name, err)
+		}
+		*read.out = value
+	}
+	return c, nil
+}
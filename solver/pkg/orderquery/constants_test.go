@@ -0,0 +1,113 @@
+package orderquery
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// Base7683.witnessTypeString as deployed
+const testWitnessTypeString = "ResolvedCrossChainOrder witness)" + resolvedOrderType + "TokenPermissions(address token,uint256 amount)"
+
+var testSettler = common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
+
+// abiBackend stands in for a deployed Hyperlane7683: calls are decoded and answered with the generated binding's
+// ABI, so the binding under test runs unchanged
+type abiBackend struct {
+	abi    *abi.ABI
+	values map[string][32]byte
+	calls  int
+}
+
+func newABIBackend(t *testing.T, c ContractConstants) *abiBackend {
+	t.Helper()
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+	return &abiBackend{abi: parsed, values: map[string][32]byte{
+		"UNKNOWN":                             c.Unknown,
+		"OPENED":                              c.Opened,
+		"FILLED":                              c.Filled,
+		"SETTLED":                             c.Settled,
+		"REFUNDED":                            c.Refunded,
+		"RESOLVED_CROSS_CHAIN_ORDER_TYPEHASH": c.ResolvedOrderTypeHash,
+	}}
+}
+
+func (b *abiBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x60, 0x80}, nil
+}
+
+func (b *abiBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	b.calls++
+	method, err := b.abi.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(b.values[method.RawName])
+}
+
+func TestKnownConstantsMatchStatusNames(t *testing.T) {
+	known := KnownConstants()
+	for _, word := range [][32]byte{known.Unknown, known.Opened, known.Filled, known.Settled, known.Refunded} {
+		want, err := DecodeStatus(word[:])
+		require.NoError(t, err)
+		got, err := known.Status(word)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestReadConstants(t *testing.T) {
+	t.Run("deployed contract", func(t *testing.T) {
+		got, err := ReadConstants(context.Background(), testSettler, newABIBackend(t, KnownConstants()))
+		require.NoError(t, err)
+		assert.Equal(t, KnownConstants(), got)
+	})
+
+	t.Run("statuses follow the contract", func(t *testing.T) {
+		upgraded := KnownConstants()
+		copy(upgraded.Filled[:], "FILLED_V2")
+		got, err := ReadConstants(context.Background(), testSettler, newABIBackend(t, upgraded))
+		require.NoError(t, err)
+
+		status, err := got.Status(upgraded.Filled)
+		require.NoError(t, err)
+		assert.Equal(t, StatusFilled, status)
+		_, err = got.Status(KnownConstants().Filled)
+		assert.ErrorContains(t, err, "unrecognized order status")
+	})
+}
+
+func TestLoadConstants(t *testing.T) {
+	ctx := context.Background()
+	backend := newABIBackend(t, KnownConstants())
+	first, err := LoadConstants(ctx, "Base", testSettler, backend)
+	require.NoError(t, err)
+	calls := backend.calls
+	second, err := LoadConstants(ctx, "base", testSettler, backend)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, calls, backend.calls, "second load must come from the cache")
+
+	SetOfflineConstants(true)
+	t.Cleanup(func() { SetOfflineConstants(false) })
+	offline, err := LoadConstants(ctx, "Optimism", testSettler, nil)
+	require.NoError(t, err)
+	assert.Equal(t, KnownConstants(), offline)
+}
+
+func TestCheckWitnessTypeString(t *testing.T) {
+	known := KnownConstants()
+	require.NoError(t, known.CheckWitnessTypeString(testWitnessTypeString))
+
+	assert.Error(t, known.CheckWitnessTypeString("ResolvedCrossChainOrder witness)ResolvedCrossChainOrder(address user)TokenPermissions(address token,uint256 amount)"))
+	assert.Error(t, known.CheckWitnessTypeString(resolvedOrderType), "missing witness framing")
+}
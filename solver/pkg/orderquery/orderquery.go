@@ -0,0 +1,281 @@
+// Package orderquery reads Hyperlane7683 orders back from chain: the status Base7683 keeps for every order and
+// the order an origin stores in openOrders, on EVM and Cairo networks alike.
+//
+// EVM contracts are read through the generated bindings; Cairo contracts through their order_status and
+// open_orders views, whose Bytes answer holds the same abi.encode(orderDataType, orderData) as on EVM
+package orderquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+const (
+	wordSize = 32
+	// openOrderHeaderSize covers abi.encode(orderDataType, orderData) up to the orderData bytes: orderDataType, the
+	// offset of orderData and its length
+	openOrderHeaderSize = 3 * wordSize
+	// orderDataHeadSize is the size of the OrderData head: the leading offset word plus 12 field words
+	orderDataHeadSize = 13 * wordSize
+)
+
+// ErrOrderNotFound is returned by GetOpenOrder when the contract holds no open order with the ID: it was never
+// opened there, or that network is not the order's origin
+var ErrOrderNotFound = errors.New("order not found")
+
+// Status is the lifecycle status Base7683 stores for an order
+type Status int
+
+// Order statuses, in lifecycle order. Base7683 stores them as the bytes32 of their name on EVM and the short
+// string felt on Cairo; a never seen order reads as zero, StatusUnknown
+const (
+	StatusUnknown Status = iota
+	StatusOpened
+	StatusFilled
+	StatusSettled
+	StatusRefunded
+)
+
+var statusNames = [...]string{"UNKNOWN", "OPENED", "FILLED", "SETTLED", "REFUNDED"}
+
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+	return statusNames[s]
+}
+
+// DecodeStatus reads a status word: an EVM bytes32, left aligned, or a Cairo short string felt, right aligned
+func DecodeStatus(word []byte) (Status, error) {
+	name := strings.Trim(string(word), "\x00")
+	if name == "" {
+		return StatusUnknown, nil
+	}
+	for s, known := range statusNames {
+		if name == known {
+			return Status(s), nil
+		}
+	}
+	return StatusUnknown, fmt.Errorf("unrecognized order status %q", name)
+}
+
+// OrderData is an order as its origin stores it in openOrders
+type OrderData struct {
+	OrderDataType      [32]byte
+	Sender             [32]byte
+	Recipient          [32]byte
+	InputToken         [32]byte
+	OutputToken        [32]byte
+	AmountIn           *big.Int
+	AmountOut          *big.Int
+	SenderNonce        *big.Int
+	OriginDomain       uint32
+	DestinationDomain  uint32
+	DestinationSettler [32]byte
+	FillDeadline       uint64
+	Data               []byte
+	// Encoded is the ABI-encoded OrderData, the originData a filler passes to fill
+	Encoded []byte
+}
+
+// DecodeOpenOrder decodes the abi.encode(orderDataType, orderData) stored in openOrders. EVM pads the data to a
+// whole word while Cairo stores it unpadded, so only the declared length is read
+func DecodeOpenOrder(raw []byte) (*OrderData, error) {
+	encoded, err := OpenOrderData(raw)
+	if err != nil {
+		return nil, err
+	}
+	order, err := ParseOrderData(encoded)
+	if err != nil {
+		return nil, err
+	}
+	copy(order.OrderDataType[:], raw[:wordSize])
+	return order, nil
+}
+
+// OpenOrderData returns the ABI-encoded OrderData of an openOrders value
+func OpenOrderData(raw []byte) ([]byte, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w (openOrders is empty)", ErrOrderNotFound)
+	}
+	if len(raw) < openOrderHeaderSize {
+		return nil, fmt.Errorf("open order too short: %d bytes", len(raw))
+	}
+
+	length := new(big.Int).SetBytes(raw[2*wordSize : openOrderHeaderSize])
+	if !length.IsUint64() || length.Uint64() > uint64(len(raw)-openOrderHeaderSize) {
+		return nil, fmt.Errorf("open order declares %s bytes of order data but has %d", length, len(raw)-openOrderHeaderSize)
+	}
+	return raw[openOrderHeaderSize : openOrderHeaderSize+int(length.Uint64())], nil
+}
+
+// ParseOrderData reads the fields of ABI-encoded OrderData. Data is left empty when the dynamic field does not
+// fit in data
+func ParseOrderData(data []byte) (*OrderData, error) {
+	if len(data) < orderDataHeadSize {
+		return nil, fmt.Errorf("order data too short: %d bytes", len(data))
+	}
+
+	// Field i of the OrderData head sits after the leading offset word
+	word := func(i int) []byte {
+		start := wordSize + i*wordSize
+		return data[start : start+wordSize]
+	}
+	order := &OrderData{
+		AmountIn:          new(big.Int).SetBytes(word(4)),
+		AmountOut:         new(big.Int).SetBytes(word(5)),
+		SenderNonce:       new(big.Int).SetBytes(word(6)),
+		OriginDomain:      uint32(new(big.Int).SetBytes(word(7)).Uint64()),
+		DestinationDomain: uint32(new(big.Int).SetBytes(word(8)).Uint64()),
+		FillDeadline:      new(big.Int).SetBytes(word(10)).Uint64(),
+		Encoded:           data,
+	}
+	copy(order.Sender[:], word(0))
+	copy(order.Recipient[:], word(1))
+	copy(order.InputToken[:], word(2))
+	copy(order.OutputToken[:], word(3))
+	copy(order.DestinationSettler[:], word(9))
+
+	// data is at its offset from the start of the tuple, as its length followed by the bytes
+	offset := new(big.Int).SetBytes(word(11))
+	if offset.IsUint64() && offset.Uint64() <= uint64(len(data)-2*wordSize) {
+		start := wordSize + int(offset.Uint64())
+		length := new(big.Int).SetBytes(data[start : start+wordSize])
+		if length.IsUint64() && length.Uint64() <= uint64(len(data)-start-wordSize) {
+			order.Data = data[start+wordSize : start+wordSize+int(length.Uint64())]
+		}
+	}
+	return order, nil
+}
+
+// Network locates a Hyperlane7683 contract: an origin's, whose openOrders holds the orders opened there, or a
+// destination settler's
+type Network struct {
+	Name   string
+	RPCURL string
+	// Contract is the Hyperlane7683 address, 0x EVM address or Cairo felt
+	Contract string
+	// Cairo is set for Starknet and Ztarknet, whose contracts are read through their Cairo views
+	Cairo bool
+}
+
+// GetOrderStatus reads orderStatus(orderId) from network's contract. EVM status words are matched against the
+// contract's own status constants
+func GetOrderStatus(ctx context.Context, network Network, orderID common.Hash) (Status, error) {
+	if network.Cairo {
+		resp, err := callCairo(ctx, network, "order_status", orderID)
+		if err != nil {
+			return StatusUnknown, err
+		}
+		if len(resp) == 0 {
+			return StatusUnknown, fmt.Errorf("order_status returned no data")
+		}
+		felt := resp[0].Bytes()
+		return DecodeStatus(felt[:])
+	}
+
+	contract, client, err := bindEVM(ctx, network)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	defer client.Close()
+	status, err := contract.OrderStatus(&bind.CallOpts{Context: ctx}, orderID)
+	if err != nil {
+		return StatusUnknown, fmt.Errorf("orderStatus call failed on %s: %w", network.Name, err)
+	}
+	constants, err := LoadConstants(ctx, network.Name, common.HexToAddress(network.Contract), client)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	return constants.Status(status)
+}
+
+// ReadOpenOrder returns the raw openOrders(orderId) value of network's contract, empty when it holds no such order
+func ReadOpenOrder(ctx context.Context, network Network, orderID common.Hash) ([]byte, error) {
+	if network.Cairo {
+		resp, err := callCairo(ctx, network, "open_orders", orderID)
+		if err != nil {
+			return nil, err
+		}
+		return starknetutil.FromCairoBytes(resp)
+	}
+
+	contract, client, err := bindEVM(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	raw, err := contract.OpenOrders(&bind.CallOpts{Context: ctx}, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("openOrders call failed on %s: %w", network.Name, err)
+	}
+	return raw, nil
+}
+
+// GetOpenOrder reads and decodes openOrders(orderId) from network's contract, which must be the order's origin
+func GetOpenOrder(ctx context.Context, network Network, orderID common.Hash) (OrderData, error) {
+	raw, err := ReadOpenOrder(ctx, network, orderID)
+	if err != nil {
+		return OrderData{}, err
+	}
+	order, err := DecodeOpenOrder(raw)
+	if err != nil {
+		return OrderData{}, fmt.Errorf("order %s on %s: %w", orderID.Hex(), network.Name, err)
+	}
+	return *order, nil
+}
+
+// bindEVM connects to network and binds its Hyperlane7683 contract; the caller closes the returned client
+func bindEVM(ctx context.Context, network Network) (*contracts.Hyperlane7683, *ethclient.Client, error) {
+	if !common.IsHexAddress(network.Contract) {
+		return nil, nil, fmt.Errorf("invalid %s Hyperlane7683 address %q", network.Name, network.Contract)
+	}
+	client, err := ethclient.DialContext(ctx, network.RPCURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	contract, err := contracts.NewHyperlane7683(common.HexToAddress(network.Contract), client)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("failed to bind Hyperlane7683 on %s: %w", network.Name, err)
+	}
+	return contract, client, nil
+}
+
+// callCairo calls a view of network's contract that takes the order ID as its only (u256) argument
+func callCairo(ctx context.Context, network Network, function string, orderID common.Hash) ([]*felt.Felt, error) {
+	contract, err := utils.HexToFelt(network.Contract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s Hyperlane7683 address %q: %w", network.Name, network.Contract, err)
+	}
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert order ID: %w", err)
+	}
+	resp, err := provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           []*felt.Felt{orderIDLow, orderIDHigh},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed on %s: %w", function, network.Name, err)
+	}
+	return resp, nil
+}
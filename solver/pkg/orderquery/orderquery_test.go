@@ -0,0 +1,113 @@
+package orderquery
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery/orderquerytest"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+func TestOpenOrderData(t *testing.T) {
+	orderData := orderquerytest.OrderData(t)
+
+	t.Run("EVM abi.encode pads the data", func(t *testing.T) {
+		raw := orderquerytest.EVMOpenOrder(t, orderData)
+		require.Zero(t, len(raw)%wordSize)
+
+		decoded, err := OpenOrderData(raw)
+		require.NoError(t, err)
+		assert.Equal(t, orderData, decoded)
+	})
+
+	t.Run("Starknet open_orders keeps the data unpadded", func(t *testing.T) {
+		raw := orderquerytest.EVMOpenOrder(t, orderData)[:openOrderHeaderSize+len(orderData)]
+		decoded, err := starknetutil.FromCairoBytes(starknetutil.ToCairoBytes(raw))
+		require.NoError(t, err)
+
+		decoded, err = OpenOrderData(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, orderData, decoded)
+	})
+
+	t.Run("empty means the order was never opened", func(t *testing.T) {
+		_, err := OpenOrderData(nil)
+		assert.ErrorIs(t, err, ErrOrderNotFound)
+	})
+
+	t.Run("length beyond the payload is rejected", func(t *testing.T) {
+		raw := orderquerytest.EVMOpenOrder(t, orderData)[:openOrderHeaderSize+10]
+		_, err := OpenOrderData(raw)
+		assert.ErrorContains(t, err, "declares")
+	})
+}
+
+func TestParseOrderData(t *testing.T) {
+	order, err := ParseOrderData(orderquerytest.OrderData(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, common.BigToHash(big.NewInt(1)), common.Hash(order.Sender))
+	assert.Equal(t, common.BigToHash(big.NewInt(2)), common.Hash(order.Recipient))
+	assert.Equal(t, common.BigToHash(big.NewInt(3)), common.Hash(order.InputToken))
+	assert.Equal(t, common.BigToHash(big.NewInt(4)), common.Hash(order.OutputToken))
+	assert.Equal(t, int64(1000), order.AmountIn.Int64())
+	assert.Equal(t, int64(990), order.AmountOut.Int64())
+	assert.Equal(t, int64(7), order.SenderNonce.Int64())
+	assert.Equal(t, uint32(10), order.OriginDomain)
+	assert.Equal(t, uint32(8453), order.DestinationDomain)
+	assert.Equal(t, common.HexToHash("0x0123456789abcdef"), common.Hash(order.DestinationSettler))
+	assert.Equal(t, uint64(1700000000), order.FillDeadline)
+	assert.Equal(t, []byte{0xaa, 0xbb}, order.Data)
+
+	_, err = ParseOrderData(make([]byte, orderDataHeadSize-1))
+	assert.ErrorContains(t, err, "too short")
+}
+
+func TestDecodeOpenOrder(t *testing.T) {
+	orderData := orderquerytest.OrderData(t)
+
+	order, err := DecodeOpenOrder(orderquerytest.EVMOpenOrder(t, orderData))
+	require.NoError(t, err)
+	assert.Equal(t, [32]byte{0x01}, order.OrderDataType)
+	assert.Equal(t, orderData, order.Encoded)
+	assert.Equal(t, uint32(8453), order.DestinationDomain)
+
+	_, err = DecodeOpenOrder(nil)
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+}
+
+func TestDecodeStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		word    []byte
+		want    Status
+		wantErr string
+	}{
+		{name: "EVM bytes32", word: common.RightPadBytes([]byte("FILLED"), wordSize), want: StatusFilled},
+		{name: "Cairo short string", word: common.LeftPadBytes([]byte("SETTLED"), wordSize), want: StatusSettled},
+		{name: "refunded", word: []byte("REFUNDED"), want: StatusRefunded},
+		{name: "never seen", word: make([]byte, wordSize), want: StatusUnknown},
+		{name: "unrecognized", word: []byte("CANCELLED"), wantErr: `unrecognized order status "CANCELLED"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeStatus(tt.word)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	assert.Equal(t, "OPENED", StatusOpened.String())
+	assert.Equal(t, "UNKNOWN", StatusUnknown.String())
+	assert.Equal(t, "Status(9)", Status(9).String())
+}
@@ -0,0 +1,50 @@
+// Package orderquerytest builds the order fixtures the orderquery tests and the tools reading orders share
+package orderquerytest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetorder"
+)
+
+// OrderData is an encoded OrderData from origin domain 10 to 8453 with the fields numbered 1 to 7 in order and
+// data 0xaabb
+func OrderData(t testing.TB) []byte {
+	t.Helper()
+	settler, err := utils.HexToFelt("0x0123456789abcdef")
+	require.NoError(t, err)
+	encoded, err := starknetorder.EncodeOrderData(&starknetorder.OrderData{
+		Sender:             utils.Uint64ToFelt(1),
+		Recipient:          utils.Uint64ToFelt(2),
+		InputToken:         utils.Uint64ToFelt(3),
+		OutputToken:        utils.Uint64ToFelt(4),
+		AmountIn:           big.NewInt(1000),
+		AmountOut:          big.NewInt(990),
+		SenderNonce:        utils.Uint64ToFelt(7),
+		OriginDomain:       10,
+		DestinationDomain:  8453,
+		DestinationSettler: settler,
+		FillDeadline:       1700000000,
+		Data:               []byte{0xaa, 0xbb},
+	})
+	require.NoError(t, err)
+	return encoded
+}
+
+// EVMOpenOrder encodes an open order the way Base7683 stores it: abi.encode(orderDataType, orderData)
+func EVMOpenOrder(t testing.TB, orderData []byte) []byte {
+	t.Helper()
+	bytes32Type, err := abi.NewType("bytes32", "", nil)
+	require.NoError(t, err)
+	bytesType, err := abi.NewType("bytes", "", nil)
+	require.NoError(t, err)
+
+	raw, err := abi.Arguments{{Type: bytes32Type}, {Type: bytesType}}.Pack([32]byte{0x01}, orderData)
+	require.NoError(t, err)
+	return raw
+}
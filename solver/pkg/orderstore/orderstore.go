@@ -0,0 +1,244 @@
+// Package orderstore keeps a local record of the orders opened by the development tools.
+//
+// Each order is stored as its own JSON file (<dir>/<orderId>.json) and every write goes through a
+// temp file and an atomic rename, so concurrent writers (e.g. open-order batch) never share a file
+// and readers never see a partial one.
+//
+// Usage:
+//
+//	store := orderstore.New(orderstore.DefaultDir())
+//	if err := store.Save(&orderstore.Order{OrderID: "0x...", Status: orderstore.StatusOpened}); err != nil { ... }
+//	order, err := store.Get("0x...")
+package orderstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+const (
+	dirPerms  = 0755
+	filePerms = 0644
+)
+
+// Order statuses as stored by Hyperlane7683
+const (
+	StatusOpened   = "OPENED"
+	StatusFilled   = "FILLED"
+	StatusSettled  = "SETTLED"
+	StatusRefunded = "REFUNDED"
+)
+
+// ErrNotFound is returned when the store has no record of an order
+var ErrNotFound = errors.New("order not found in store")
+
+// Order is the stored record of an opened order
+type Order struct {
+	OrderID          string `json:"orderId"`
+	OriginChain      string `json:"originChain"`
+	DestinationChain string `json:"destinationChain"`
+	TxHash           string `json:"txHash"`
+	User             string `json:"user,omitempty"`
+	Gasless          bool   `json:"gasless,omitempty"`
+	OpenDeadline     uint64 `json:"openDeadline,omitempty"`
+	FillDeadline     uint64 `json:"fillDeadline"`
+	OrderDataType    string `json:"orderDataType,omitempty"`
+	OrderData        string `json:"orderData,omitempty"`
+	// TxOrders lists every order opened by TxHash when it opened several
+	TxOrders  []string `json:"txOrders,omitempty"`
+	Status    string   `json:"status"`
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt"`
+}
+
+// Store reads and writes order records under a directory
+type Store struct {
+	dir string
+}
+
+// process-local lock so read-modify-write updates of the same order do not interleave
+var storeMu sync.Mutex
+
+// New returns a store rooted at dir; the directory is created on the first write
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns ORDER_STORE_DIR, or orders/ in the state directory
+func DefaultDir() string {
+	if dir := os.Getenv("ORDER_STORE_DIR"); dir != "" {
+		return dir
+	}
+	return solverdir.StatePath("orders")
+}
+
+// Dir returns the directory the store reads and writes
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes an order record, replacing any previous record of the same order
+func (s *Store) Save(order *Order) error {
+	if _, err := normalizeID(order.OrderID); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if order.CreatedAt == "" {
+		order.CreatedAt = now
+	}
+	order.UpdatedAt = now
+
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	return s.writeLocked(order)
+}
+
+// Get returns the record of an order; IDs match case-insensitively
+func (s *Store) Get(orderID string) (*Order, error) {
+	path, err := s.path(orderID)
+	if err != nil {
+		return nil, err
+	}
+	return readOrder(path)
+}
+
+// UpdateStatus records a new status for a stored order
+func (s *Store) UpdateStatus(orderID, status string) error {
+	path, err := s.path(orderID)
+	if err != nil {
+		return err
+	}
+
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	order, err := readOrder(path)
+	if err != nil {
+		return err
+	}
+	if order.Status == status {
+		return nil
+	}
+	order.Status = status
+	order.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return s.writeLocked(order)
+}
+
+// List returns every stored order, oldest first. Files that cannot be parsed are skipped
+// so one bad record does not hide the others
+func (s *Store) List() ([]*Order, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order store %s: %w", s.dir, err)
+	}
+
+	var orders []*Order
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		order, err := readOrder(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].CreatedAt != orders[j].CreatedAt {
+			return orders[i].CreatedAt < orders[j].CreatedAt
+		}
+		return orders[i].OrderID < orders[j].OrderID
+	})
+	return orders, nil
+}
+
+// writeLocked writes the record atomically while holding storeMu
+func (s *Store) writeLocked(order *Order) error {
+	path, err := s.path(order.OrderID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, dirPerms); err != nil {
+		return fmt.Errorf("failed to create order store directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(order, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal order: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "order-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp order file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { tmp.Close(); os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temp order file: %w", err)
+	}
+	if err := tmp.Chmod(filePerms); err != nil {
+		return fmt.Errorf("failed to chmod temp order file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temp order file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp order file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace order file: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of an order
+func (s *Store) path(orderID string) (string, error) {
+	id, err := normalizeID(orderID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+// readOrder reads one order file
+func readOrder(path string) (*Order, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order file: %w", err)
+	}
+	var order Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse order file %s: %w", path, err)
+	}
+	return &order, nil
+}
+
+// normalizeID lower-cases a 0x-prefixed hex order ID so it can name a file
+func normalizeID(orderID string) (string, error) {
+	id := strings.ToLower(orderID)
+	if !strings.HasPrefix(id, "0x") || len(id) == 2 {
+		return "", fmt.Errorf("invalid order ID %q: expected 0x-prefixed hex", orderID)
+	}
+	for _, c := range id[2:] {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return "", fmt.Errorf("invalid order ID %q: expected 0x-prefixed hex", orderID)
+		}
+	}
+	return id, nil
+}
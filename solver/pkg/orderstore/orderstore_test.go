@@ -0,0 +1,125 @@
+package orderstore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+const testOrderID = "0x9f4a7c1b2d3e4f5061728394a5b6c7d8e9f00112233445566778899aabbccdd"
+
+func TestSaveAndGet(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "orders"))
+
+	order := &Order{
+		OrderID:          testOrderID,
+		OriginChain:      "Base",
+		DestinationChain: "Starknet",
+		TxHash:           "0xaa",
+		FillDeadline:     1700000000,
+		OrderData:        "0x01",
+		Status:           StatusOpened,
+	}
+	require.NoError(t, store.Save(order))
+	assert.NotEmpty(t, order.CreatedAt)
+	assert.Equal(t, order.CreatedAt, order.UpdatedAt)
+
+	got, err := store.Get("0x9F4A7C1B2D3E4F5061728394A5B6C7D8E9F00112233445566778899AABBCCDD")
+	require.NoError(t, err)
+	assert.Equal(t, order, got)
+
+	_, err = store.Get("0x01")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestInvalidOrderIDs(t *testing.T) {
+	store := New(t.TempDir())
+	for _, id := range []string{"", "0x", "1234", "0x12zz", "0x../../etc/passwd"} {
+		assert.Error(t, store.Save(&Order{OrderID: id}), id)
+		_, err := store.Get(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	store := New(t.TempDir())
+	require.NoError(t, store.Save(&Order{OrderID: testOrderID, Status: StatusOpened, CreatedAt: "2024-01-01T00:00:00Z"}))
+
+	require.NoError(t, store.UpdateStatus(testOrderID, StatusFilled))
+	got, err := store.Get(testOrderID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFilled, got.Status)
+	assert.Equal(t, "2024-01-01T00:00:00Z", got.CreatedAt, "updates keep the creation time")
+
+	assert.ErrorIs(t, store.UpdateStatus("0x01", StatusFilled), ErrNotFound)
+}
+
+func TestList(t *testing.T) {
+	t.Run("missing directory is an empty store", func(t *testing.T) {
+		orders, err := New(filepath.Join(t.TempDir(), "missing")).List()
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+	})
+
+	t.Run("oldest first, unreadable files skipped", func(t *testing.T) {
+		dir := t.TempDir()
+		store := New(dir)
+		require.NoError(t, store.Save(&Order{OrderID: "0x02", CreatedAt: "2024-01-02T00:00:00Z"}))
+		require.NoError(t, store.Save(&Order{OrderID: "0x01", CreatedAt: "2024-01-03T00:00:00Z"}))
+		require.NoError(t, store.Save(&Order{OrderID: "0x03", CreatedAt: "2024-01-01T00:00:00Z"}))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0x04.json"), []byte("{"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hi"), 0o600))
+
+		orders, err := store.List()
+		require.NoError(t, err)
+		require.Len(t, orders, 3)
+		assert.Equal(t, "0x03", orders[0].OrderID)
+		assert.Equal(t, "0x02", orders[1].OrderID)
+		assert.Equal(t, "0x01", orders[2].OrderID)
+	})
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir)
+
+	const writers = 32
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("0x%02x", i)
+			assert.NoError(t, store.Save(&Order{OrderID: id, Status: StatusOpened}))
+			assert.NoError(t, store.UpdateStatus(id, StatusFilled))
+		}(i)
+	}
+	wg.Wait()
+
+	orders, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, orders, writers)
+	for _, order := range orders {
+		assert.Equal(t, StatusFilled, order.Status)
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers, "temp files are cleaned up")
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("ORDER_STORE_DIR", "")
+	t.Setenv(solverdir.StateDirEnv, "/srv/oif/state")
+	assert.Equal(t, "/srv/oif/state/orders", DefaultDir())
+
+	t.Setenv("ORDER_STORE_DIR", "/tmp/orders")
+	assert.Equal(t, "/tmp/orders", DefaultDir())
+}
@@ -0,0 +1,149 @@
+package permit2
+
+// Module: Permit2 signature helpers
+// - Reproduces Permit2's EIP-712 hashing for PermitBatchWitnessTransferFrom
+// - Reads the domain separator from the deployed Permit2 contract
+// - Signs digests in the 65-byte r||s||v form Permit2 expects
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// tokenPermissionsType is the EIP-712 type of a single permitted token
+	tokenPermissionsType = "TokenPermissions(address token,uint256 amount)"
+
+	// permitBatchWitnessTypeStub is completed by the caller's witness type string
+	permitBatchWitnessTypeStub = "PermitBatchWitnessTransferFrom(TokenPermissions[] permitted,address spender,uint256 nonce,uint256 deadline,"
+
+	// domainType is the EIP-712 domain used by Permit2 (no version field)
+	domainType = "EIP712Domain(string name,uint256 chainId,address verifyingContract)"
+
+	// domainName is the EIP-712 domain name of Permit2
+	domainName = "Permit2"
+
+	// signatureLength is the length of an r||s||v signature
+	signatureLength = 65
+
+	// domainSeparatorABI is the minimal ABI for Permit2.DOMAIN_SEPARATOR()
+	domainSeparatorABI = `[{"inputs":[],"name":"DOMAIN_SEPARATOR","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"}]`
+)
+
+// TokenPermissions mirrors ISignatureTransfer.TokenPermissions
+type TokenPermissions struct {
+	Token  common.Address
+	Amount *big.Int
+}
+
+// PermitBatchTransferFrom mirrors ISignatureTransfer.PermitBatchTransferFrom
+type PermitBatchTransferFrom struct {
+	Permitted []TokenPermissions
+	Nonce     *big.Int
+	Deadline  *big.Int
+}
+
+// TokenPermissionsTypeHash returns keccak256 of the TokenPermissions type
+func TokenPermissionsTypeHash() common.Hash {
+	return crypto.Keccak256Hash([]byte(tokenPermissionsType))
+}
+
+// BatchWitnessTypeHash returns the PermitBatchWitnessTransferFrom type hash for a witness type string
+func BatchWitnessTypeHash(witnessTypeString string) common.Hash {
+	return crypto.Keccak256Hash([]byte(permitBatchWitnessTypeStub + witnessTypeString))
+}
+
+// HashBatchWitnessTransferFrom computes the struct hash Permit2 verifies in permitWitnessTransferFrom.
+// spender is the contract calling Permit2 (msg.sender), e.g. the Hyperlane7683 settler
+func HashBatchWitnessTransferFrom(permit PermitBatchTransferFrom, spender common.Address, witness common.Hash, witnessTypeString string) common.Hash {
+	tokenTypeHash := TokenPermissionsTypeHash()
+
+	permissionHashes := make([]byte, 0, len(permit.Permitted)*common.HashLength)
+	for _, p := range permit.Permitted {
+		h := crypto.Keccak256(tokenTypeHash.Bytes(), addressWord(p.Token), uintWord(p.Amount))
+		permissionHashes = append(permissionHashes, h...)
+	}
+
+	return crypto.Keccak256Hash(
+		BatchWitnessTypeHash(witnessTypeString).Bytes(),
+		crypto.Keccak256(permissionHashes),
+		addressWord(spender),
+		uintWord(permit.Nonce),
+		uintWord(permit.Deadline),
+		witness.Bytes(),
+	)
+}
+
+// ComputeDomainSeparator computes the Permit2 domain separator for a chain and deployment
+func ComputeDomainSeparator(chainID *big.Int, permit2Address common.Address) common.Hash {
+	return crypto.Keccak256Hash(
+		crypto.Keccak256([]byte(domainType)),
+		crypto.Keccak256([]byte(domainName)),
+		uintWord(chainID),
+		addressWord(permit2Address),
+	)
+}
+
+// DomainSeparator reads DOMAIN_SEPARATOR() from a deployed Permit2 contract
+func DomainSeparator(ctx context.Context, caller ethereum.ContractCaller, permit2Address common.Address) (common.Hash, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(domainSeparatorABI))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to parse Permit2 ABI: %w", err)
+	}
+
+	data, err := parsedABI.Pack("DOMAIN_SEPARATOR")
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to pack DOMAIN_SEPARATOR call: %w", err)
+	}
+
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &permit2Address, Data: data}, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to call DOMAIN_SEPARATOR: %w", err)
+	}
+
+	var separator [32]byte
+	if err := parsedABI.UnpackIntoInterface(&separator, "DOMAIN_SEPARATOR", result); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to unpack DOMAIN_SEPARATOR: %w", err)
+	}
+	return separator, nil
+}
+
+// TypedDataDigest returns the EIP-712 digest keccak256("\x19\x01" || domainSeparator || structHash)
+func TypedDataDigest(domainSeparator, structHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator.Bytes(), structHash.Bytes())
+}
+
+// Sign signs an EIP-712 digest and returns r||s||v with v in {27, 28}
+func Sign(digest common.Hash, key *ecdsa.PrivateKey) ([]byte, error) {
+	sig, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign digest: %w", err)
+	}
+	if len(sig) != signatureLength {
+		return nil, fmt.Errorf("unexpected signature length %d", len(sig))
+	}
+	sig[signatureLength-1] += 27
+	return sig, nil
+}
+
+// addressWord returns an address left padded to a 32-byte word
+func addressWord(a common.Address) []byte {
+	return common.LeftPadBytes(a.Bytes(), common.HashLength)
+}
+
+// uintWord returns an unsigned integer as a 32-byte word (nil encodes as zero)
+func uintWord(n *big.Int) []byte {
+	word := make([]byte, common.HashLength)
+	if n != nil {
+		n.FillBytes(word)
+	}
+	return word
+}
@@ -0,0 +1,166 @@
+package permit2
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWitnessTypeString is an EIP-712 compliant witness so the digest can be cross-checked with apitypes
+const testWitnessTypeString = "Witness witness)TokenPermissions(address token,uint256 amount)Witness(uint256 value)"
+
+var (
+	testPermit2 = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	testSpender = common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
+	testToken1  = common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	testToken2  = common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")
+	testChainID = big.NewInt(31337)
+	testPermit  = PermitBatchTransferFrom{
+		Permitted: []TokenPermissions{
+			{Token: testToken1, Amount: big.NewInt(1001)},
+			{Token: testToken2, Amount: new(big.Int).Lsh(big.NewInt(1), 200)},
+		},
+		Nonce:    big.NewInt(42),
+		Deadline: big.NewInt(1_900_000_000),
+	}
+	testWitnessValue = big.NewInt(7)
+)
+
+// testWitnessHash is hashStruct(Witness{value})
+func testWitnessHash() common.Hash {
+	return crypto.Keccak256Hash(
+		crypto.Keccak256([]byte("Witness(uint256 value)")),
+		common.LeftPadBytes(testWitnessValue.Bytes(), common.HashLength),
+	)
+}
+
+// referenceTypedData describes the same permit with go-ethereum's EIP-712 implementation
+func referenceTypedData() apitypes.TypedData {
+	permitted := make([]interface{}, 0, len(testPermit.Permitted))
+	for _, p := range testPermit.Permitted {
+		permitted = append(permitted, map[string]interface{}{
+			"token":  p.Token.Hex(),
+			"amount": (*math.HexOrDecimal256)(p.Amount),
+		})
+	}
+
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"PermitBatchWitnessTransferFrom": {
+				{Name: "permitted", Type: "TokenPermissions[]"},
+				{Name: "spender", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+				{Name: "witness", Type: "Witness"},
+			},
+			"TokenPermissions": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint256"},
+			},
+			"Witness": {
+				{Name: "value", Type: "uint256"},
+			},
+		},
+		PrimaryType: "PermitBatchWitnessTransferFrom",
+		Domain: apitypes.TypedDataDomain{
+			Name:              domainName,
+			ChainId:           (*math.HexOrDecimal256)(testChainID),
+			VerifyingContract: testPermit2.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"permitted": permitted,
+			"spender":   testSpender.Hex(),
+			"nonce":     (*math.HexOrDecimal256)(testPermit.Nonce),
+			"deadline":  (*math.HexOrDecimal256)(testPermit.Deadline),
+			"witness":   map[string]interface{}{"value": (*math.HexOrDecimal256)(testWitnessValue)},
+		},
+	}
+}
+
+func TestBatchWitnessTypeHash(t *testing.T) {
+	typedData := referenceTypedData()
+	expected := typedData.TypeHash(typedData.PrimaryType)
+
+	assert.Equal(t, common.BytesToHash(expected), BatchWitnessTypeHash(testWitnessTypeString))
+}
+
+func TestHashBatchWitnessTransferFrom(t *testing.T) {
+	typedData := referenceTypedData()
+	expected, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	require.NoError(t, err)
+
+	got := HashBatchWitnessTransferFrom(testPermit, testSpender, testWitnessHash(), testWitnessTypeString)
+	assert.Equal(t, common.BytesToHash(expected), got)
+}
+
+func TestComputeDomainSeparator(t *testing.T) {
+	typedData := referenceTypedData()
+	expected, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	require.NoError(t, err)
+
+	assert.Equal(t, common.BytesToHash(expected), ComputeDomainSeparator(testChainID, testPermit2))
+}
+
+func TestTypedDataDigest(t *testing.T) {
+	expected, _, err := apitypes.TypedDataAndHash(referenceTypedData())
+	require.NoError(t, err)
+
+	structHash := HashBatchWitnessTransferFrom(testPermit, testSpender, testWitnessHash(), testWitnessTypeString)
+	digest := TypedDataDigest(ComputeDomainSeparator(testChainID, testPermit2), structHash)
+	assert.Equal(t, common.BytesToHash(expected), digest)
+}
+
+func TestSign(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	digest := crypto.Keccak256Hash([]byte("permit2"))
+
+	sig, err := Sign(digest, key)
+	require.NoError(t, err)
+	require.Len(t, sig, signatureLength)
+	assert.Contains(t, []byte{27, 28}, sig[signatureLength-1])
+
+	// Recover with the raw recovery id to confirm the signer
+	raw := append([]byte{}, sig...)
+	raw[signatureLength-1] -= 27
+	pub, err := crypto.SigToPub(digest.Bytes(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*pub))
+}
+
+// stubCaller returns a fixed result for any contract call
+type stubCaller struct {
+	result []byte
+	msg    ethereum.CallMsg
+}
+
+func (s *stubCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	s.msg = msg
+	return s.result, nil
+}
+
+func TestDomainSeparator(t *testing.T) {
+	expected := ComputeDomainSeparator(testChainID, testPermit2)
+	caller := &stubCaller{result: expected.Bytes()}
+
+	got, err := DomainSeparator(context.Background(), caller, testPermit2)
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+	require.NotNil(t, caller.msg.To)
+	assert.Equal(t, testPermit2, *caller.msg.To)
+	assert.Equal(t, hexutil.MustDecode("0x3644e515"), caller.msg.Data)
+}
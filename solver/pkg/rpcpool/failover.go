@@ -0,0 +1,321 @@
+package rpcpool
+
+// RPC failover
+// A network may list several RPC endpoints (<NETWORK>_RPC_URLS). Its client then sends every request through a
+// failover transport: the first endpoint answering eth_chainId / starknet_chainId is picked when the client is
+// created, and after FailoverAfter connection-level failures in a row (refused or reset connections, timeouts,
+// 429 and 5xx answers) the transport health-checks the others and moves to the next healthy one. Requests that
+// could not be sent at all are retried once on the new endpoint; anything else is returned to the caller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultHealthCheckTimeout bounds each endpoint health check without Options.HealthCheckTimeout
+	DefaultHealthCheckTimeout = 3 * time.Second
+	// DefaultFailoverAfter is the number of failures in a row that moves a network to its next endpoint
+	DefaultFailoverAfter = 3
+
+	evmChainIDMethod      = "eth_chainId"
+	starknetChainIDMethod = "starknet_chainId"
+)
+
+// ErrNoHealthyEndpoint is returned when none of a network's RPC endpoints answers its health check
+var ErrNoHealthyEndpoint = errors.New("no healthy RPC endpoint")
+
+// EVMFailover returns the client of networkName sending each request to the first healthy of urls, failing over
+// to the next one mid-run. With a single URL it is EVM, without a health check
+func (p *Pool) EVMFailover(ctx context.Context, networkName string, urls []string) (*ethclient.Client, error) {
+	if len(urls) == 1 {
+		return p.EVM(ctx, networkName, urls[0])
+	}
+	auth := authorization(os.Getenv(AuthEnv(networkName)))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, ErrClosed
+	}
+	k := key(strings.Join(urls, ","), auth)
+	if c, ok := p.evm[k]; ok {
+		return c, nil
+	}
+
+	f, err := p.newFailover(ctx, networkName, urls, auth, evmChainIDMethod)
+	if err != nil {
+		return nil, err
+	}
+	options := []gethrpc.ClientOption{gethrpc.WithHTTPClient(&http.Client{Transport: f, Timeout: p.http.Timeout})}
+	if auth != "" {
+		options = append(options, gethrpc.WithHeader("Authorization", auth))
+	}
+	rpcClient, err := gethrpc.DialOptions(ctx, f.endpoints[f.active].String(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", redact(f.endpoints[f.active]), err)
+	}
+	c := ethclient.NewClient(rpcClient)
+	p.evm[k] = c
+	return c, nil
+}
+
+// StarknetFailover returns the provider of networkName sending each request to the first healthy of urls, failing
+// over to the next one mid-run. With a single URL it is Starknet, without a health check
+func (p *Pool) StarknetFailover(ctx context.Context, networkName string, urls []string) (*rpc.Provider, error) {
+	if len(urls) == 1 {
+		return p.Starknet(networkName, urls[0])
+	}
+	auth := authorization(os.Getenv(AuthEnv(networkName)))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, ErrClosed
+	}
+	k := key(strings.Join(urls, ","), auth)
+	if provider, ok := p.starknet[k]; ok {
+		return provider, nil
+	}
+
+	f, err := p.newFailover(ctx, networkName, urls, auth, starknetChainIDMethod)
+	if err != nil {
+		return nil, err
+	}
+	options := []client.ClientOption{client.WithHTTPClient(&http.Client{Transport: f, Timeout: p.http.Timeout})}
+	if auth != "" {
+		options = append(options, client.WithHeader("Authorization", auth))
+	}
+	provider, err := rpc.NewProvider(f.endpoints[f.active].String(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", redact(f.endpoints[f.active]), err)
+	}
+	p.starknet[k] = provider
+	return provider, nil
+}
+
+// failover routes the requests of one network to its active endpoint
+type failover struct {
+	networkName string
+	endpoints   []*url.URL
+	auth        string
+	method      string
+	next        http.RoundTripper
+	timeout     time.Duration
+	after       int
+	logger      logrus.FieldLogger
+
+	mu       sync.Mutex
+	active   int
+	failures int
+	checking bool // a failing request is health-checking the other endpoints
+}
+
+// newFailover parses urls and picks the first healthy one as the active endpoint, logging the choice
+func (p *Pool) newFailover(ctx context.Context, networkName string, urls []string, auth, method string) (*failover, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no RPC URL configured for %s", networkName)
+	}
+	f := &failover{
+		networkName: networkName,
+		auth:        auth,
+		method:      method,
+		next:        p.http.Transport,
+		timeout:     p.opts.healthCheckTimeout(),
+		after:       p.opts.failoverAfter(),
+		logger:      p.opts.logger(),
+	}
+	for _, raw := range urls {
+		endpoint, err := url.Parse(raw)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid %s RPC URL %q", networkName, raw)
+		}
+		f.endpoints = append(f.endpoints, endpoint)
+	}
+
+	var failures []string
+	for i, endpoint := range f.endpoints {
+		if err := f.check(ctx, endpoint); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", redact(endpoint), err))
+			continue
+		}
+		f.active = i
+		f.logger.Infof("🔌 %s RPC: %s (%d of %d endpoints)", networkName, redact(endpoint), i+1, len(f.endpoints))
+		return f, nil
+	}
+	return nil, fmt.Errorf("%w for %s: %s", ErrNoHealthyEndpoint, networkName, strings.Join(failures, "; "))
+}
+
+// check sends the chain ID request to endpoint and expects a result
+func (f *failover) check(ctx context.Context, endpoint *url.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":[]}`, f.method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.auth != "" {
+		req.Header.Set("Authorization", f.auth)
+	}
+	resp, err := f.next.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	var answer struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&answer); err != nil {
+		return fmt.Errorf("invalid %s answer: %w", f.method, err)
+	}
+	if answer.Error != nil {
+		return fmt.Errorf("%s failed: %s", f.method, answer.Error.Message)
+	}
+	if len(answer.Result) == 0 || string(answer.Result) == "null" {
+		return fmt.Errorf("%s returned no chain ID", f.method)
+	}
+	return nil
+}
+
+func (f *failover) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	active := f.active
+	f.mu.Unlock()
+
+	resp, err := f.next.RoundTrip(f.route(req, active))
+	if !unavailable(resp, err) {
+		f.mu.Lock()
+		if f.active == active {
+			f.failures = 0
+		}
+		f.mu.Unlock()
+		return resp, err
+	}
+
+	switched := f.fail(req.Context(), active)
+	if switched < 0 || !notSent(err) || req.GetBody == nil {
+		return resp, err
+	}
+	body, bodyErr := req.GetBody()
+	if bodyErr != nil {
+		return resp, err
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return f.next.RoundTrip(f.route(retry, switched))
+}
+
+// route returns req addressed to endpoint i
+func (f *failover) route(req *http.Request, i int) *http.Request {
+	out := req.Clone(req.Context())
+	endpoint := *f.endpoints[i]
+	out.URL = &endpoint
+	out.Host = ""
+	return out
+}
+
+// fail records a failure of endpoint active and returns the endpoint switched to, or -1 when the network stays.
+// The health checks run without the lock, so the network's other requests are not held up while the failing
+// endpoint is being replaced
+func (f *failover) fail(ctx context.Context, active int) int {
+	f.mu.Lock()
+	switch {
+	case f.active != active:
+		// Another request already moved on
+		switched := f.active
+		f.mu.Unlock()
+		return switched
+	case f.checking:
+		// Another request is already looking for a healthy endpoint
+		f.mu.Unlock()
+		return -1
+	}
+	f.failures++
+	if f.failures < f.after {
+		f.mu.Unlock()
+		return -1
+	}
+	f.failures = 0
+	f.checking = true
+	f.mu.Unlock()
+
+	healthy := -1
+	for step := 1; step < len(f.endpoints); step++ {
+		i := (active + step) % len(f.endpoints)
+		if err := f.check(ctx, f.endpoints[i]); err != nil {
+			f.logger.Debugf("%s RPC %s is unhealthy: %v", f.networkName, redact(f.endpoints[i]), err)
+			continue
+		}
+		healthy = i
+		break
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checking = false
+	if f.active != active {
+		return f.active
+	}
+	if healthy < 0 {
+		return -1
+	}
+	f.active = healthy
+	f.logger.Warnf("⚠️  %s RPC %s failed %d times in a row, switched to %s", f.networkName, redact(f.endpoints[active]), f.after, redact(f.endpoints[healthy]))
+	return healthy
+}
+
+// unavailable reports whether a round trip failed at the connection level or was turned away by the endpoint
+func unavailable(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// notSent reports whether err happened before the request reached the endpoint, so it is safe to send again
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return err != nil && errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// redact returns the scheme and host of endpoint, leaving out API keys carried in the path or query
+func redact(endpoint *url.URL) string {
+	return endpoint.Scheme + "://" + endpoint.Host
+}
+
+// RedactURL is redact for a raw endpoint, for showing a configured RPC URL without its API key
+func RedactURL(raw string) string {
+	endpoint, err := url.Parse(raw)
+	if err != nil || endpoint.Host == "" {
+		return "(unparsable URL)"
+	}
+	return redact(endpoint)
+}
@@ -0,0 +1,225 @@
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// endpoint is an RPC server answering every method with chainID, counting the requests it served
+type endpoint struct {
+	*httptest.Server
+	served  atomic.Int32
+	status  atomic.Int32
+	methods chan string
+}
+
+func newEndpoint(t *testing.T, chainID string) *endpoint {
+	e := &endpoint{methods: make(chan string, 64)}
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		select {
+		case e.methods <- req.Method:
+		default:
+		}
+		if status := e.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+		e.served.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": chainID})
+	}))
+	t.Cleanup(e.Close)
+	return e
+}
+
+func testLogger() (*logrus.Logger, *bytes.Buffer) {
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&out)
+	logger.SetLevel(logrus.DebugLevel)
+	return logger, &out
+}
+
+func TestEVMFailoverPicksFirstHealthy(t *testing.T) {
+	down := newEndpoint(t, "0x1")
+	down.Close()
+	busy := newEndpoint(t, "0x1")
+	busy.status.Store(http.StatusTooManyRequests)
+	healthy := newEndpoint(t, "0x2")
+	logger, out := testLogger()
+	pool := New(Options{Logger: logger})
+	defer pool.Close()
+
+	urls := []string{down.URL, busy.URL, healthy.URL + "/v2/secret-key"}
+	c, err := pool.EVMFailover(context.Background(), "Base", urls)
+	require.NoError(t, err)
+	chainID, err := c.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), chainID.Int64())
+
+	again, err := pool.EVMFailover(context.Background(), "Base", urls)
+	require.NoError(t, err)
+	assert.Same(t, c, again)
+	assert.Equal(t, 1, strings.Count(out.String(), "Base RPC: "+healthy.URL+" (3 of 3 endpoints)"), "the choice is logged once")
+	assert.NotContains(t, out.String(), "secret-key")
+}
+
+func TestEVMFailoverSwitchesMidRun(t *testing.T) {
+	primary := newEndpoint(t, "0x1")
+	fallback := newEndpoint(t, "0x1")
+	logger, out := testLogger()
+	pool := New(Options{Logger: logger, FailoverAfter: 2})
+	defer pool.Close()
+
+	c, err := pool.EVMFailover(context.Background(), "Base", []string{primary.URL, fallback.URL})
+	require.NoError(t, err)
+	_, err = c.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, fallback.served.Load())
+
+	// The primary starts refusing connections: the first failure is returned, the second one moves to the
+	// fallback and is retried there
+	primary.Close()
+	_, err = c.ChainID(context.Background())
+	require.Error(t, err)
+	_, err = c.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "switched to "+fallback.URL)
+
+	served := fallback.served.Load()
+	_, err = c.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, served+1, fallback.served.Load(), "later requests stay on the fallback")
+}
+
+func TestEVMFailoverRateLimited(t *testing.T) {
+	primary := newEndpoint(t, "0x1")
+	fallback := newEndpoint(t, "0x1")
+	logger, _ := testLogger()
+	pool := New(Options{Logger: logger, FailoverAfter: 1})
+	defer pool.Close()
+
+	c, err := pool.EVMFailover(context.Background(), "Base", []string{primary.URL, fallback.URL})
+	require.NoError(t, err)
+
+	primary.status.Store(http.StatusTooManyRequests)
+	_, err = c.ChainID(context.Background())
+	require.Error(t, err, "an answered request is not sent again")
+	_, err = c.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, fallback.served.Load())
+}
+
+func TestFailoverHealthCheckDoesNotBlockRequests(t *testing.T) {
+	primary := newEndpoint(t, "0x1")
+	checking, release := make(chan struct{}), make(chan struct{})
+	var checked atomic.Bool
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if checked.CompareAndSwap(false, true) {
+			close(checking)
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+	logger, out := testLogger()
+	pool := New(Options{Logger: logger, FailoverAfter: 1, HealthCheckTimeout: 10 * time.Second})
+	defer pool.Close()
+
+	c, err := pool.EVMFailover(context.Background(), "Base", []string{primary.URL, slow.URL})
+	require.NoError(t, err)
+
+	// The primary turns a request away, which starts a health check of the fallback that does not answer yet
+	primary.status.Store(http.StatusServiceUnavailable)
+	failed := make(chan error, 1)
+	go func() {
+		_, err := c.ChainID(context.Background())
+		failed <- err
+	}()
+	<-checking
+
+	// The primary recovers, and a request sent meanwhile is not held up by the health check
+	primary.status.Store(0)
+	served := make(chan error, 1)
+	go func() {
+		_, err := c.ChainID(context.Background())
+		served <- err
+	}()
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("a request waited for the health check of another endpoint")
+	}
+
+	close(release)
+	require.Error(t, <-failed, "the turned away request is returned")
+	assert.Contains(t, out.String(), "switched to "+slow.URL)
+}
+
+func TestFailoverNoHealthyEndpoint(t *testing.T) {
+	down := newEndpoint(t, "0x1")
+	down.Close()
+	broken := newEndpoint(t, "0x1")
+	broken.status.Store(http.StatusBadGateway)
+	pool := New(Options{})
+	defer pool.Close()
+
+	_, err := pool.EVMFailover(context.Background(), "Base", []string{down.URL, broken.URL})
+	require.ErrorIs(t, err, ErrNoHealthyEndpoint)
+	assert.ErrorContains(t, err, "HTTP 502")
+
+	_, err = pool.EVMFailover(context.Background(), "Base", []string{"localhost:8545", broken.URL})
+	assert.ErrorContains(t, err, "invalid Base RPC URL")
+}
+
+func TestStarknetFailoverHealthCheck(t *testing.T) {
+	down := newEndpoint(t, "0x534e5f5345504f4c4941")
+	down.Close()
+	healthy := newEndpoint(t, "0x534e5f5345504f4c4941")
+	logger, out := testLogger()
+	pool := New(Options{Logger: logger})
+	defer pool.Close()
+
+	_, err := pool.StarknetFailover(context.Background(), "Starknet", []string{down.URL, healthy.URL})
+	require.NoError(t, err)
+	assert.Equal(t, "starknet_chainId", <-healthy.methods)
+	assert.Contains(t, out.String(), "Starknet RPC: "+healthy.URL)
+}
+
+func TestFailoverSingleURL(t *testing.T) {
+	server := newEndpoint(t, "0x1")
+	pool := New(Options{})
+	defer pool.Close()
+
+	c, err := pool.EVMFailover(context.Background(), "Base", []string{server.URL})
+	require.NoError(t, err)
+	direct, err := pool.EVM(context.Background(), "Base", server.URL)
+	require.NoError(t, err)
+	assert.Same(t, direct, c, "a single URL is used as is")
+	assert.Empty(t, server.methods, "without a health check")
+}
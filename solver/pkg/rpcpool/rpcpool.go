@@ -0,0 +1,265 @@
+package rpcpool
+
+// Module: RPC client pool for the tools
+// - Hands out one ethclient / Starknet provider per RPC URL for the whole tool run
+// - All clients share one http.Client with timeouts, so connections are reused across operations
+// - <NETWORK>_RPC_AUTH adds an Authorization header for protected RPCs: user:password is sent as basic auth,
+//   anything with a scheme ("Bearer <token>") is sent as is
+// - RPC_RATE_LIMIT caps the requests per second sent to each RPC host, for providers that answer 429 to
+//   parallel deploys; RPC_TIMEOUT overrides the per-request timeout
+// - EVMFailover / StarknetFailover spread a network over several RPC endpoints (see failover.go)
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultTimeout bounds each HTTP request without RPC_TIMEOUT
+	DefaultTimeout = 30 * time.Second
+
+	// TimeoutEnv overrides DefaultTimeout, e.g. RPC_TIMEOUT=60s
+	TimeoutEnv = "RPC_TIMEOUT"
+	// RateLimitEnv caps the requests per second sent to each RPC host, e.g. RPC_RATE_LIMIT=10
+	RateLimitEnv = "RPC_RATE_LIMIT"
+)
+
+// ErrClosed is returned for clients requested after Close
+var ErrClosed = errors.New("rpc pool is closed")
+
+// Options configures the HTTP client shared by a Pool
+type Options struct {
+	// Timeout bounds each HTTP request; 0 uses DefaultTimeout
+	Timeout time.Duration
+	// RequestsPerSecond caps the requests sent to each RPC host; 0 means no limit
+	RequestsPerSecond float64
+	// HealthCheckTimeout bounds each RPC endpoint health check of EVMFailover and StarknetFailover; 0 uses
+	// DefaultHealthCheckTimeout
+	HealthCheckTimeout time.Duration
+	// FailoverAfter is the number of failures in a row that moves a network to its next endpoint; 0 uses
+	// DefaultFailoverAfter
+	FailoverAfter int
+	// Logger receives the endpoint choices; nil uses the standard logger
+	Logger logrus.FieldLogger
+}
+
+func (o Options) healthCheckTimeout() time.Duration {
+	if o.HealthCheckTimeout <= 0 {
+		return DefaultHealthCheckTimeout
+	}
+	return o.HealthCheckTimeout
+}
+
+func (o Options) failoverAfter() int {
+	if o.FailoverAfter <= 0 {
+		return DefaultFailoverAfter
+	}
+	return o.FailoverAfter
+}
+
+func (o Options) logger() logrus.FieldLogger {
+	if o.Logger == nil {
+		return logrus.StandardLogger()
+	}
+	return o.Logger
+}
+
+// OptionsFromEnv reads RPC_TIMEOUT and RPC_RATE_LIMIT, ignoring invalid values
+func OptionsFromEnv() Options {
+	var opts Options
+	if value := os.Getenv(TimeoutEnv); value != "" {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+			opts.Timeout = timeout
+		}
+	}
+	if value := os.Getenv(RateLimitEnv); value != "" {
+		if rps, err := strconv.ParseFloat(value, 64); err == nil && rps > 0 {
+			opts.RequestsPerSecond = rps
+		}
+	}
+	return opts
+}
+
+// AuthEnv is the env var holding the credentials of networkName's RPC, e.g. BASE_RPC_AUTH
+func AuthEnv(networkName string) string {
+	return strings.ToUpper(networkName) + "_RPC_AUTH"
+}
+
+// authorization turns an <NETWORK>_RPC_AUTH value into an Authorization header value
+func authorization(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.Contains(value, " ") || !strings.Contains(value, ":") {
+		return value
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+// Pool caches one client per RPC URL and credentials. It is safe for concurrent use
+type Pool struct {
+	mu        sync.Mutex
+	opts      Options
+	http      *http.Client
+	transport *http.Transport
+	evm       map[string]*ethclient.Client
+	starknet  map[string]*rpc.Provider
+	closed    bool
+}
+
+// New creates a pool whose clients share one HTTP client configured by opts
+func New(opts Options) *Pool {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: timeout,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   16,
+		ForceAttemptHTTP2:     true,
+	}
+	var roundTripper http.RoundTripper = transport
+	if opts.RequestsPerSecond > 0 {
+		roundTripper = newRateLimiter(transport, opts.RequestsPerSecond)
+	}
+	return &Pool{
+		opts:      opts,
+		http:      &http.Client{Transport: roundTripper, Timeout: timeout},
+		transport: transport,
+		evm:       make(map[string]*ethclient.Client),
+		starknet:  make(map[string]*rpc.Provider),
+	}
+}
+
+// key identifies a client: the same URL with other credentials gets its own client
+func key(url, auth string) string {
+	return url + "\x00" + auth
+}
+
+// EVM returns the client of networkName's RPC at url, dialing it on first use. The pool owns the client:
+// callers must not close it
+func (p *Pool) EVM(ctx context.Context, networkName, url string) (*ethclient.Client, error) {
+	auth := authorization(os.Getenv(AuthEnv(networkName)))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, ErrClosed
+	}
+	if c, ok := p.evm[key(url, auth)]; ok {
+		return c, nil
+	}
+
+	options := []gethrpc.ClientOption{gethrpc.WithHTTPClient(p.http)}
+	if auth != "" {
+		options = append(options, gethrpc.WithHeader("Authorization", auth))
+	}
+	rpcClient, err := gethrpc.DialOptions(ctx, url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+	c := ethclient.NewClient(rpcClient)
+	p.evm[key(url, auth)] = c
+	return c, nil
+}
+
+// Starknet returns the provider of networkName's RPC at url, creating it on first use
+func (p *Pool) Starknet(networkName, url string) (*rpc.Provider, error) {
+	auth := authorization(os.Getenv(AuthEnv(networkName)))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, ErrClosed
+	}
+	if provider, ok := p.starknet[key(url, auth)]; ok {
+		return provider, nil
+	}
+
+	options := []client.ClientOption{client.WithHTTPClient(p.http)}
+	if auth != "" {
+		options = append(options, client.WithHeader("Authorization", auth))
+	}
+	provider, err := rpc.NewProvider(url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+	p.starknet[key(url, auth)] = provider
+	return provider, nil
+}
+
+// Close closes every client and the idle connections of the shared HTTP client. Later requests fail with ErrClosed
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for _, c := range p.evm {
+		c.Close()
+	}
+	// HTTP Starknet providers hold nothing but the shared HTTP client's connections
+	p.evm, p.starknet = nil, nil
+	p.transport.CloseIdleConnections()
+}
+
+// rateLimiter spaces the requests to each host at least 1/rps apart
+type rateLimiter struct {
+	next     http.RoundTripper
+	interval time.Duration
+
+	mu    sync.Mutex
+	slots map[string]time.Time
+}
+
+func newRateLimiter(next http.RoundTripper, rps float64) *rateLimiter {
+	return &rateLimiter{next: next, interval: time.Duration(float64(time.Second) / rps), slots: make(map[string]time.Time)}
+}
+
+func (l *rateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := l.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+	return l.next.RoundTrip(req)
+}
+
+// wait reserves the next free slot of host and sleeps until it
+func (l *rateLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.slots[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.slots[host] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,124 @@
+package rpcpool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainIDServer answers every JSON-RPC request with chain ID 1 and records the Authorization headers
+func chainIDServer(t *testing.T) (*httptest.Server, func() []string) {
+	var (
+		mu      sync.Mutex
+		headers []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		headers = append(headers, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": "0x1"})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), headers...)
+	}
+}
+
+func TestPoolReusesClients(t *testing.T) {
+	server, _ := chainIDServer(t)
+	pool := New(Options{})
+	defer pool.Close()
+
+	first, err := pool.EVM(context.Background(), "Base", server.URL)
+	require.NoError(t, err)
+	second, err := pool.EVM(context.Background(), "Base", server.URL)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	chainID, err := first.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), chainID.Int64())
+}
+
+func TestPoolSendsAuth(t *testing.T) {
+	server, headers := chainIDServer(t)
+	t.Setenv("BASE_RPC_AUTH", "alice:secret")
+	t.Setenv("OPTIMISM_RPC_AUTH", "Bearer token")
+	pool := New(Options{})
+	defer pool.Close()
+
+	base, err := pool.EVM(context.Background(), "Base", server.URL)
+	require.NoError(t, err)
+	_, err = base.ChainID(context.Background())
+	require.NoError(t, err)
+
+	optimism, err := pool.EVM(context.Background(), "Optimism", server.URL)
+	require.NoError(t, err)
+	assert.NotSame(t, base, optimism, "other credentials get their own client")
+	_, err = optimism.ChainID(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Basic YWxpY2U6c2VjcmV0", "Bearer token"}, headers())
+}
+
+func TestPoolClose(t *testing.T) {
+	server, _ := chainIDServer(t)
+	pool := New(Options{})
+	_, err := pool.EVM(context.Background(), "Base", server.URL)
+	require.NoError(t, err)
+
+	pool.Close()
+	pool.Close()
+	_, err = pool.EVM(context.Background(), "Base", server.URL)
+	assert.ErrorIs(t, err, ErrClosed)
+	_, err = pool.Starknet("Starknet", server.URL)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestRateLimiter(t *testing.T) {
+	server, headers := chainIDServer(t)
+	pool := New(Options{RequestsPerSecond: 20})
+	defer pool.Close()
+	c, err := pool.EVM(context.Background(), "Base", server.URL)
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := c.ChainID(context.Background())
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond, "5 requests at 20/s take at least 4 intervals")
+	assert.Len(t, headers(), 5)
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Setenv(TimeoutEnv, "5s")
+	t.Setenv(RateLimitEnv, "2.5")
+	assert.Equal(t, Options{Timeout: 5 * time.Second, RequestsPerSecond: 2.5}, OptionsFromEnv())
+
+	t.Setenv(TimeoutEnv, "soon")
+	t.Setenv(RateLimitEnv, "-1")
+	assert.Equal(t, Options{}, OptionsFromEnv())
+}
+
+func TestAuthorization(t *testing.T) {
+	assert.Equal(t, "", authorization(""))
+	assert.Equal(t, "Basic dTpw", authorization("u:p"))
+	assert.Equal(t, "Bearer abc", authorization("Bearer abc"))
+	assert.Equal(t, "abc", authorization("abc"))
+	assert.Equal(t, "BASE_SEPOLIA_RPC_AUTH", AuthEnv("Base_Sepolia"))
+}
@@ -0,0 +1,109 @@
+package snip12
+
+// Module: SNIP-12 (Starknet typed data) hashing
+// - Hashes typed-data JSON with starknet.go's typedata package, as the Cairo contracts and starknet.js do
+// - Reports the primary type's type hash, the message's struct hash and the message hash an account signs
+// - Renders each hash in hex and decimal, and compares hashes against expected values in either form
+
+import (
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/typedata"
+)
+
+// Hash is a felt in the two forms it is usually compared in: hex (starknet.js, explorers) and decimal (Cairo tests)
+type Hash struct {
+	Hex     string `json:"hex"`
+	Decimal string `json:"decimal"`
+}
+
+// NewHash renders f
+func NewHash(f *felt.Felt) Hash {
+	return Hash{Hex: f.String(), Decimal: f.Text(10)}
+}
+
+// Hashes are the hashes of a typed-data message
+type Hashes struct {
+	PrimaryType string `json:"primaryType"`
+	// TypeHash is the hash of the primary type's encoded definition
+	TypeHash Hash `json:"typeHash"`
+	// StructHash is the hash of the message as the primary type
+	StructHash Hash `json:"structHash"`
+	// MessageHash is what Account signs: the message prefixed with the domain and the account
+	MessageHash Hash   `json:"messageHash"`
+	Account     string `json:"account"`
+}
+
+// Parse decodes typed-data JSON
+func Parse(data []byte) (*typedata.TypedData, error) {
+	var td typedata.TypedData
+	if err := td.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	return &td, nil
+}
+
+// Compute returns the hashes of td's message, signed by account
+func Compute(td *typedata.TypedData, account string) (Hashes, error) {
+	typeHash, err := td.GetTypeHash(td.PrimaryType)
+	if err != nil {
+		return Hashes{}, fmt.Errorf("failed to hash type %q: %w", td.PrimaryType, err)
+	}
+	structHash, err := td.GetStructHash(td.PrimaryType)
+	if err != nil {
+		return Hashes{}, fmt.Errorf("failed to hash %q message: %w", td.PrimaryType, err)
+	}
+	messageHash, err := td.GetMessageHash(account)
+	if err != nil {
+		return Hashes{}, fmt.Errorf("failed to hash message for account %s: %w", account, err)
+	}
+	return Hashes{
+		PrimaryType: td.PrimaryType,
+		TypeHash:    NewHash(typeHash),
+		StructHash:  NewHash(structHash),
+		MessageHash: NewHash(messageHash),
+		Account:     account,
+	}, nil
+}
+
+// HashJSON parses typed-data JSON and computes its hashes for account
+func HashJSON(data []byte, account string) (Hashes, error) {
+	td, err := Parse(data)
+	if err != nil {
+		return Hashes{}, err
+	}
+	return Compute(td, account)
+}
+
+// Expected are the hashes a message should have, each as hex or decimal. Empty fields are not checked
+type Expected struct {
+	TypeHash    string `json:"typeHash,omitempty"`
+	StructHash  string `json:"structHash,omitempty"`
+	MessageHash string `json:"messageHash,omitempty"`
+}
+
+// Mismatch is a hash that differs from the expected one
+type Mismatch struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Computed string `json:"computed"`
+}
+
+// Check compares got with want; an expected value that is not a felt is reported as a mismatch
+func Check(got Hashes, want Expected) []Mismatch {
+	var mismatches []Mismatch
+	check := func(field, expected string, computed Hash) {
+		if expected == "" {
+			return
+		}
+		f, err := new(felt.Felt).SetString(expected)
+		if err != nil || f.String() != computed.Hex {
+			mismatches = append(mismatches, Mismatch{Field: field, Expected: expected, Computed: computed.Hex})
+		}
+	}
+	check("typeHash", want.TypeHash, got.TypeHash)
+	check("structHash", want.StructHash, got.StructHash)
+	check("messageHash", want.MessageHash, got.MessageHash)
+	return mismatches
+}
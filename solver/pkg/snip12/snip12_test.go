@@ -0,0 +1,83 @@
+package snip12
+
+import (
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAccount is the first predeployed starknet-devnet account
+const testAccount = "0x064b48806902a367c8598f4f95c305e8c1a1acba5f082d294a43793113115691"
+
+// permitSingleType is the SNIP-12 encoding of testdata/permit_single.json's primary type: the type, then the
+// types it references in alphabetical order
+const permitSingleType = `"Permit Single"("Details":"Permit Details","Spender":"ContractAddress","Sig Deadline":"u256")` +
+	`"Permit Details"("Token":"ContractAddress","Amount":"u256","Expiration":"timestamp","Nonce":"timestamp")` +
+	`"u256"("low":"u128","high":"u128")`
+
+func loadPermitSingle(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/permit_single.json")
+	require.NoError(t, err)
+	return data
+}
+
+func TestHashJSON(t *testing.T) {
+	hashes, err := HashJSON(loadPermitSingle(t), testAccount)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Permit Single", hashes.PrimaryType)
+	assert.Equal(t, testAccount, hashes.Account)
+	// The type hash is the starknet_keccak of the encoded type
+	assert.Equal(t, utils.GetSelectorFromNameFelt(permitSingleType).String(), hashes.TypeHash.Hex)
+	assert.Equal(t, Hash{
+		Hex:     "0x3ba9155c2accbec95e96bd8b3a44001b999bcab5f60ac9190d52971e5e326d5",
+		Decimal: "1686575165115998963135753293397327380848933614734921516341761255114482591445",
+	}, hashes.TypeHash)
+	assert.Equal(t, "0x783c0cea579b3e30ef47b9ff5d0fd18b47a52cdfc951b9fc9f232c9c1523d78", hashes.StructHash.Hex)
+	assert.Equal(t, "0x717f305560f6d52bc17b2d96b714b09e5cbdc878ebcff2940caeb2d312d87eb", hashes.MessageHash.Hex)
+
+	// The message hash is bound to the signing account
+	other, err := HashJSON(loadPermitSingle(t), "0x1")
+	require.NoError(t, err)
+	assert.Equal(t, hashes.StructHash, other.StructHash)
+	assert.NotEqual(t, hashes.MessageHash, other.MessageHash)
+}
+
+func TestHashJSONErrors(t *testing.T) {
+	_, err := HashJSON([]byte("{"), testAccount)
+	assert.ErrorContains(t, err, "invalid typed data")
+
+	_, err = HashJSON(loadPermitSingle(t), "not an address")
+	assert.ErrorContains(t, err, "failed to hash message for account not an address")
+}
+
+func TestCheck(t *testing.T) {
+	hashes, err := HashJSON(loadPermitSingle(t), testAccount)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		want   Expected
+		fields []string
+	}{
+		{"hex", Expected{TypeHash: hashes.TypeHash.Hex, StructHash: hashes.StructHash.Hex, MessageHash: hashes.MessageHash.Hex}, nil},
+		{"decimal", Expected{TypeHash: hashes.TypeHash.Decimal, StructHash: hashes.StructHash.Decimal}, nil},
+		{"leading zeros", Expected{TypeHash: "0x03ba9155c2accbec95e96bd8b3a44001b999bcab5f60ac9190d52971e5e326d5"}, nil},
+		{"nothing expected", Expected{}, nil},
+		{"wrong struct hash", Expected{TypeHash: hashes.TypeHash.Hex, StructHash: "0x1"}, []string{"structHash"}},
+		{"not a felt", Expected{MessageHash: "hash"}, []string{"messageHash"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, m := range Check(hashes, tt.want) {
+				fields = append(fields, m.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
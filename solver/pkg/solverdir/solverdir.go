@@ -0,0 +1,183 @@
+// Package solverdir locates the solver directory, which holds .env and state/, so the solver and its tools can
+// be run from any working directory.
+//
+// The state directory is the first of --state-dir, OIF_STATE_DIR and <solver>/state; the .env file the first of
+// --config, OIF_CONFIG and <solver>/.env. The solver directory is found by walking up from the working directory,
+// then from the executable, to the first directory holding example.env, or a solver/ subdirectory holding it so
+// runs from the repository root work. When neither walk finds it, the working directory is used
+package solverdir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	// StateDirFlag overrides the state directory for this run
+	StateDirFlag = "--state-dir"
+	// ConfigFlag overrides the .env file for this run
+	ConfigFlag = "--config"
+	// StateDirEnv overrides the state directory
+	StateDirEnv = "OIF_STATE_DIR"
+	// ConfigEnv overrides the .env file
+	ConfigEnv = "OIF_CONFIG"
+
+	// Marker is the file identifying the solver directory
+	Marker = "example.env"
+)
+
+// ExtractFlags removes --state-dir and --config (with a separate or =value) from args and exports their values
+// as OIF_STATE_DIR and OIF_CONFIG, so every loader of the process picks them up
+func ExtractFlags(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var flag, env string
+		switch {
+		case arg == StateDirFlag || strings.HasPrefix(arg, StateDirFlag+"="):
+			flag, env = StateDirFlag, StateDirEnv
+		case arg == ConfigFlag || strings.HasPrefix(arg, ConfigFlag+"="):
+			flag, env = ConfigFlag, ConfigEnv
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+
+		value, ok := strings.CutPrefix(arg, flag+"=")
+		if !ok {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a path", flag)
+			}
+			i++
+			value = args[i]
+		}
+		if value == "" {
+			return nil, fmt.Errorf("%s requires a path", flag)
+		}
+		abs, err := filepath.Abs(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", flag, value, err)
+		}
+		if err := os.Setenv(env, abs); err != nil {
+			return nil, err
+		}
+	}
+	return rest, nil
+}
+
+// ParseOSArgs removes the flags ExtractFlags handles from os.Args, exiting on an invalid one. Tools call it first
+// in main, before loading .env or parsing their own arguments
+func ParseOSArgs() {
+	args, err := ExtractFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+	os.Args = append(os.Args[:1], args...)
+}
+
+// Root returns the absolute solver directory, and whether it was found by its marker rather than defaulted to
+// the working directory
+func Root() (string, bool) {
+	starts := searchStarts()
+	for _, start := range starts {
+		if root, ok := findRoot(start); ok {
+			return root, true
+		}
+	}
+	return starts[0], false
+}
+
+// searchStarts returns the absolute directories Root walks up from: the working directory, then the executable's
+func searchStarts() []string {
+	cwd, err := filepath.Abs(".")
+	if err != nil {
+		cwd = "."
+	}
+	starts := []string{cwd}
+	if exe, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		starts = append(starts, filepath.Dir(exe))
+	}
+	return starts
+}
+
+// findRoot walks up from dir to the solver directory
+func findRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, candidate := range []string{dir, filepath.Join(dir, "solver")} {
+			if info, err := os.Stat(filepath.Join(candidate, Marker)); err == nil && !info.IsDir() {
+				return candidate, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Path returns the absolute path of elem, relative to the solver directory
+func Path(elem ...string) string {
+	root, _ := Root()
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+// StateDir returns the absolute state directory
+func StateDir() string {
+	if dir := strings.TrimSpace(os.Getenv(StateDirEnv)); dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			return abs
+		}
+		return dir
+	}
+	return Path("state")
+}
+
+// StatePath returns the absolute path of elem within the state directory
+func StatePath(elem ...string) string {
+	return filepath.Join(append([]string{StateDir()}, elem...)...)
+}
+
+// EnvFile returns the absolute path of the .env file
+func EnvFile() string {
+	if file := strings.TrimSpace(os.Getenv(ConfigEnv)); file != "" {
+		if abs, err := filepath.Abs(file); err == nil {
+			return abs
+		}
+		return file
+	}
+	return Path(".env")
+}
+
+// LoadEnv loads EnvFile without overriding variables that are already set. A missing file is reported with the
+// path tried and, when the solver directory was not found, where it was looked for
+func LoadEnv() error {
+	file := EnvFile()
+	err := godotenv.Load(file)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to load %s: %w", file, err)
+	}
+	if os.Getenv(ConfigEnv) == "" {
+		if _, found := Root(); !found {
+			return fmt.Errorf("no .env file at %s: no %s found in or above %s (set %s or %s)",
+				file, Marker, strings.Join(searchStarts(), " or "), ConfigFlag, ConfigEnv)
+		}
+	}
+	return fmt.Errorf("no .env file at %s", file)
+}
@@ -0,0 +1,78 @@
+package solverdir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractFlags(t *testing.T) {
+	t.Setenv(StateDirEnv, "")
+	t.Setenv(ConfigEnv, "")
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	args, err := ExtractFlags([]string{"evm", "--state-dir", "state-b", "--json", "--config=b.env"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evm", "--json"}, args)
+	assert.Equal(t, filepath.Join(dir, "state-b"), os.Getenv(StateDirEnv), "relative paths are made absolute")
+	assert.Equal(t, filepath.Join(dir, "b.env"), os.Getenv(ConfigEnv))
+
+	for _, invalid := range [][]string{{"--state-dir"}, {"--config="}, {"evm", "--config"}} {
+		_, err := ExtractFlags(invalid)
+		assert.ErrorContains(t, err, "requires a path", invalid)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	t.Setenv(StateDirEnv, "")
+	t.Setenv(ConfigEnv, "")
+	repo := t.TempDir()
+	solver := filepath.Join(repo, "solver")
+	nested := filepath.Join(solver, "cmd", "tools")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(solver, Marker), nil, 0o600))
+
+	for name, cwd := range map[string]string{"solver": solver, "nested": nested, "repository_root": repo} {
+		t.Run(name, func(t *testing.T) {
+			t.Chdir(cwd)
+			root, found := Root()
+			assert.True(t, found)
+			assert.Equal(t, solver, root)
+			assert.Equal(t, filepath.Join(solver, "state", "deployment"), StatePath("deployment"))
+			assert.Equal(t, filepath.Join(solver, ".env"), EnvFile())
+		})
+	}
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Chdir(repo)
+		t.Setenv(StateDirEnv, "/srv/oif/state")
+		t.Setenv(ConfigEnv, "/srv/oif/solver.env")
+		assert.Equal(t, "/srv/oif/state/orders", StatePath("orders"))
+		assert.Equal(t, "/srv/oif/solver.env", EnvFile())
+	})
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv(StateDirEnv, "")
+	t.Setenv(ConfigEnv, "")
+	solver := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(solver, Marker), nil, 0o600))
+	t.Chdir(solver)
+
+	err := LoadEnv()
+	assert.ErrorContains(t, err, "no .env file at "+filepath.Join(solver, ".env"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(solver, ".env"), []byte("OIF_SOLVERDIR_TEST=loaded\n"), 0o600))
+	t.Setenv("OIF_SOLVERDIR_TEST", "")
+	require.NoError(t, os.Unsetenv("OIF_SOLVERDIR_TEST"))
+	require.NoError(t, LoadEnv())
+	assert.Equal(t, "loaded", os.Getenv("OIF_SOLVERDIR_TEST"))
+
+	config := filepath.Join(t.TempDir(), "missing.env")
+	t.Setenv(ConfigEnv, config)
+	assert.ErrorContains(t, LoadEnv(), "no .env file at "+config)
+}
@@ -0,0 +1,195 @@
+package starknetorder
+
+// Decoding of the Hyperlane7683 Open event emitted by open()/open_for()
+// Layout follows the Cairo serde of Open { #[key] order_id, resolved_order }
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// Output mirrors the Cairo Output struct
+type Output struct {
+	Token     *felt.Felt
+	Amount    *big.Int
+	Recipient *felt.Felt
+	ChainID   uint32
+}
+
+// FillInstruction mirrors the Cairo FillInstruction struct
+type FillInstruction struct {
+	DestinationChainID uint32
+	DestinationSettler *felt.Felt
+	OriginData         []byte
+}
+
+// ResolvedOrder mirrors the Cairo ResolvedCrossChainOrder struct
+type ResolvedOrder struct {
+	User             *felt.Felt
+	OriginChainID    uint32
+	OpenDeadline     uint64
+	FillDeadline     uint64
+	OrderID          common.Hash
+	MaxSpent         []Output
+	MinReceived      []Output
+	FillInstructions []FillInstruction
+}
+
+// OpenEvent is a decoded Open event
+type OpenEvent struct {
+	OrderID       common.Hash
+	ResolvedOrder ResolvedOrder
+}
+
+// ParseOpenEventFromReceipt finds and decodes the Open event emitted by hyperlaneAddress in a receipt
+func ParseOpenEventFromReceipt(receipt *rpc.TransactionReceipt, hyperlaneAddress *felt.Felt) (*OpenEvent, error) {
+	for _, event := range receipt.Events {
+		if event.FromAddress == nil || !event.FromAddress.Equal(hyperlaneAddress) {
+			continue
+		}
+		if len(event.Keys) == 0 || !event.Keys[0].Equal(OpenEventSelector) {
+			continue
+		}
+		return ParseOpenEvent(event.Keys, event.Data)
+	}
+	return nil, fmt.Errorf("no Open event from %s in receipt %s", hyperlaneAddress.String(), receipt.Hash.String())
+}
+
+// ParseOpenEvent decodes the keys and data of an Open event
+func ParseOpenEvent(keys, data []*felt.Felt) (*OpenEvent, error) {
+	// keys: [selector, order_id.low, order_id.high]
+	if len(keys) < 3 {
+		return nil, fmt.Errorf("open event has %d keys, expected 3", len(keys))
+	}
+	if !keys[0].Equal(OpenEventSelector) {
+		return nil, fmt.Errorf("event selector %s is not Open", keys[0].String())
+	}
+
+	ro, err := decodeResolvedOrder(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Open event data: %w", err)
+	}
+
+	orderID := common.BigToHash(starknetutil.FromU256(keys[1], keys[2]))
+	if orderID != ro.OrderID {
+		return nil, fmt.Errorf("open event key order ID %s differs from resolved order ID %s", orderID.Hex(), ro.OrderID.Hex())
+	}
+
+	return &OpenEvent{OrderID: orderID, ResolvedOrder: ro}, nil
+}
+
+// decodeResolvedOrder decodes the Cairo serde of a ResolvedCrossChainOrder, which must fill data exactly
+func decodeResolvedOrder(data []*felt.Felt) (ResolvedOrder, error) {
+	d := &feltReader{data: data}
+	ro := ResolvedOrder{
+		User:          d.felt(),
+		OriginChainID: d.u32(),
+		OpenDeadline:  d.u64(),
+		FillDeadline:  d.u64(),
+		OrderID:       common.BigToHash(d.u256()),
+	}
+
+	ro.MaxSpent = d.outputs()
+	ro.MinReceived = d.outputs()
+
+	n := d.length()
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		ro.FillInstructions = append(ro.FillInstructions, FillInstruction{
+			DestinationChainID: d.u32(),
+			DestinationSettler: d.felt(),
+			OriginData:         d.bytes(),
+		})
+	}
+
+	if d.err != nil {
+		return ResolvedOrder{}, d.err
+	}
+	if d.idx != len(data) {
+		return ResolvedOrder{}, fmt.Errorf("%d trailing felts", len(data)-d.idx)
+	}
+	return ro, nil
+}
+
+// feltReader sequentially reads Cairo-serialized values, remembering the first error
+type feltReader struct {
+	data []*felt.Felt
+	idx  int
+	err  error
+}
+
+func (r *feltReader) felt() *felt.Felt {
+	if r.err != nil {
+		return new(felt.Felt)
+	}
+	if r.idx >= len(r.data) {
+		r.err = fmt.Errorf("unexpected end of data at felt %d", r.idx)
+		return new(felt.Felt)
+	}
+	f := r.data[r.idx]
+	r.idx++
+	return f
+}
+
+func (r *feltReader) u32() uint32 {
+	return uint32(r.felt().Uint64())
+}
+
+func (r *feltReader) u64() uint64 {
+	return r.felt().Uint64()
+}
+
+func (r *feltReader) u256() *big.Int {
+	low := r.felt()
+	high := r.felt()
+	return starknetutil.FromU256(low, high)
+}
+
+// length reads an array length, bounding it by the remaining data to avoid huge allocations
+func (r *feltReader) length() uint64 {
+	n := r.felt().Uint64()
+	if r.err == nil && n > uint64(len(r.data)-r.idx) {
+		r.err = fmt.Errorf("array length %d exceeds remaining data at felt %d", n, r.idx-1)
+		return 0
+	}
+	return n
+}
+
+func (r *feltReader) outputs() []Output {
+	n := r.length()
+	outs := make([]Output, 0, n)
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		outs = append(outs, Output{
+			Token:     r.felt(),
+			Amount:    r.u256(),
+			Recipient: r.felt(),
+			ChainID:   r.u32(),
+		})
+	}
+	return outs
+}
+
+// bytes reads a Cairo Bytes value (size, words_len, u128 words) back into raw bytes
+func (r *feltReader) bytes() []byte {
+	size := r.felt().Uint64()
+	words := r.length()
+	if r.err == nil && size > words*starknetutil.Bytes16Length {
+		r.err = fmt.Errorf("bytes size %d exceeds %d words", size, words)
+		return nil
+	}
+
+	raw := make([]byte, 0, words*starknetutil.Bytes16Length)
+	for i := uint64(0); i < words && r.err == nil; i++ {
+		b := r.felt().Bytes()
+		raw = append(raw, b[starknetutil.Bytes32Length-starknetutil.Bytes16Length:]...)
+	}
+	if r.err != nil {
+		return nil
+	}
+	return raw[:size]
+}
@@ -0,0 +1,96 @@
+package starknetorder
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadReceiptFixture loads a captured open() receipt whose Open event was emitted for testOrderData
+func loadReceiptFixture(t *testing.T) *rpc.TransactionReceiptWithBlockInfo {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/open_receipt.json")
+	require.NoError(t, err)
+
+	var receipt rpc.TransactionReceiptWithBlockInfo
+	require.NoError(t, json.Unmarshal(raw, &receipt))
+	return &receipt
+}
+
+func TestParseOpenEventFromReceipt(t *testing.T) {
+	receipt := loadReceiptFixture(t)
+	hyperlane := mustFelt(t, "0x1234")
+	o := testOrderData(t)
+
+	event, err := ParseOpenEventFromReceipt(&receipt.TransactionReceipt, hyperlane)
+	require.NoError(t, err)
+
+	expectedID := common.HexToHash("0x462e3b3af743e3af16aba6ca8e8c9bec16ad54a8cc63a094623ba4a6d5393816")
+	assert.Equal(t, expectedID, event.OrderID)
+	assert.Equal(t, ComputeOrderID(mustEncode(t, &o)), event.OrderID)
+
+	ro := event.ResolvedOrder
+	assert.True(t, o.Sender.Equal(ro.User))
+	assert.Equal(t, o.OriginDomain, ro.OriginChainID)
+	assert.Equal(t, uint64(math.MaxUint64), ro.OpenDeadline)
+	assert.Equal(t, uint64(o.FillDeadline), ro.FillDeadline)
+	assert.Equal(t, expectedID, ro.OrderID)
+
+	require.Len(t, ro.MaxSpent, 1)
+	assert.True(t, o.OutputToken.Equal(ro.MaxSpent[0].Token))
+	assert.Equal(t, 0, o.AmountOut.Cmp(ro.MaxSpent[0].Amount))
+	assert.True(t, o.DestinationSettler.Equal(ro.MaxSpent[0].Recipient))
+	assert.Equal(t, o.DestinationDomain, ro.MaxSpent[0].ChainID)
+
+	require.Len(t, ro.MinReceived, 1)
+	assert.True(t, o.InputToken.Equal(ro.MinReceived[0].Token))
+	assert.Equal(t, 0, o.AmountIn.Cmp(ro.MinReceived[0].Amount))
+	assert.True(t, ro.MinReceived[0].Recipient.IsZero())
+	assert.Equal(t, o.OriginDomain, ro.MinReceived[0].ChainID)
+
+	require.Len(t, ro.FillInstructions, 1)
+	assert.Equal(t, o.DestinationDomain, ro.FillInstructions[0].DestinationChainID)
+	assert.True(t, o.DestinationSettler.Equal(ro.FillInstructions[0].DestinationSettler))
+	assert.Equal(t, mustEncode(t, &o), ro.FillInstructions[0].OriginData)
+}
+
+func TestParseOpenEventFromReceiptWrongContract(t *testing.T) {
+	receipt := loadReceiptFixture(t)
+
+	_, err := ParseOpenEventFromReceipt(&receipt.TransactionReceipt, mustFelt(t, "0x9999"))
+	assert.Error(t, err)
+}
+
+func TestParseOpenEventMalformed(t *testing.T) {
+	receipt := loadReceiptFixture(t)
+	open := receipt.Events[1]
+
+	t.Run("truncated_data", func(t *testing.T) {
+		_, err := ParseOpenEvent(open.Keys, open.Data[:len(open.Data)-3])
+		assert.Error(t, err)
+	})
+
+	t.Run("trailing_data", func(t *testing.T) {
+		data := append(append([]*felt.Felt{}, open.Data...), new(felt.Felt))
+		_, err := ParseOpenEvent(open.Keys, data)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing_keys", func(t *testing.T) {
+		_, err := ParseOpenEvent(open.Keys[:1], open.Data)
+		assert.Error(t, err)
+	})
+
+	t.Run("key_mismatch", func(t *testing.T) {
+		keys := []*felt.Felt{open.Keys[0], new(felt.Felt), open.Keys[2]}
+		_, err := ParseOpenEvent(keys, open.Data)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,151 @@
+package starknetorder
+
+// Order data type preflight
+// Neither Hyperlane7683 exposes OrderEncoder's type hash, but both check it first thing in resolve(): a resolve
+// that fails with InvalidOrderType means the hash is wrong, and one that succeeds or fails for any other reason
+// means the contract accepts it. When the hash about to be sent is rejected, the other known hashes are probed
+// so the error can say which one the deployed contract expects
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// invalidOrderTypeMessage starts the panic raised by the Cairo _resolved_order on an unknown type hash
+const invalidOrderTypeMessage = "Invalid order type"
+
+// OrderDataTypeCandidate is an order data type hash the tools know about, with where it comes from
+type OrderDataTypeCandidate struct {
+	Source string
+	Hash   *big.Int
+}
+
+// OrderDataTypeProbe reports whether a contract accepts an order data type hash
+type OrderDataTypeProbe func(ctx context.Context, orderDataType *big.Int) (bool, error)
+
+// OrderDataTypeMismatchError reports an order data type hash the contract rejects
+type OrderDataTypeMismatchError struct {
+	Contract string
+	Sent     OrderDataTypeCandidate
+	// Accepted is the known hash the contract does accept; nil when it rejects all of them
+	Accepted *OrderDataTypeCandidate
+}
+
+// Error renders the mismatch as a diff between the hash being sent and the one the contract accepts
+func (e *OrderDataTypeMismatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "order data type mismatch on %s:\n", e.Contract)
+	fmt.Fprintf(&b, "  - sending:  %s (%s), rejected with InvalidOrderType\n", formatTypeHash(e.Sent.Hash), e.Sent.Source)
+	if e.Accepted != nil {
+		fmt.Fprintf(&b, "  + contract: %s (%s)", formatTypeHash(e.Accepted.Hash), e.Accepted.Source)
+	} else {
+		b.WriteString("  + contract: none of the known type hashes; the deployed OrderEncoder has changed")
+	}
+	return b.String()
+}
+
+// verifiedTypes caches definitive preflight results per contract and type hash, so batch runs probe once
+var verifiedTypes sync.Map
+
+// KnownOrderDataTypes returns the type hashes the tools can send: ORDER_DATA_TYPE_HASH when set, and the default
+func KnownOrderDataTypes() []OrderDataTypeCandidate {
+	var candidates []OrderDataTypeCandidate
+	if hashHex := envutil.GetEnvWithDefault("ORDER_DATA_TYPE_HASH", ""); hashHex != "" {
+		if hash, ok := new(big.Int).SetString(hashHex, 0); ok {
+			candidates = append(candidates, OrderDataTypeCandidate{Source: "ORDER_DATA_TYPE_HASH", Hash: hash})
+		}
+	}
+	defaultHash, _ := new(big.Int).SetString(DefaultOrderDataTypeHash, 0)
+	return append(candidates, OrderDataTypeCandidate{Source: "built-in default", Hash: defaultHash})
+}
+
+// CheckOrderDataType probes the hash about to be sent. If the contract rejects it, the other candidates are probed
+// to report which one it expects. contract identifies the contract in errors and in the cache (e.g. "Base 0x...")
+func CheckOrderDataType(
+	ctx context.Context,
+	contract string,
+	sent OrderDataTypeCandidate,
+	candidates []OrderDataTypeCandidate,
+	probe OrderDataTypeProbe,
+) error {
+	key := contract + "/" + formatTypeHash(sent.Hash)
+	if cached, ok := verifiedTypes.Load(key); ok {
+		if cached == nil {
+			return nil
+		}
+		return cached.(error)
+	}
+
+	accepted, err := probe(ctx, sent.Hash)
+	if err != nil {
+		// Transient failures are not cached so the next order retries the preflight
+		return fmt.Errorf("order data type preflight on %s failed: %w", contract, err)
+	}
+	if accepted {
+		verifiedTypes.Store(key, nil)
+		return nil
+	}
+
+	mismatch := &OrderDataTypeMismatchError{Contract: contract, Sent: sent}
+	for _, candidate := range candidates {
+		if candidate.Hash.Cmp(sent.Hash) == 0 {
+			continue
+		}
+		if ok, err := probe(ctx, candidate.Hash); err == nil && ok {
+			mismatch.Accepted = &candidate
+			break
+		}
+	}
+	verifiedTypes.Store(key, error(mismatch))
+	return mismatch
+}
+
+// StarknetOrderDataTypeProbe calls resolve(order) on a Cairo Hyperlane7683 with the given type hash
+func StarknetOrderDataTypeProbe(caller starknetutil.ContractCaller, hyperlaneAddress *felt.Felt, o *OrderData) OrderDataTypeProbe {
+	return func(ctx context.Context, orderDataType *big.Int) (bool, error) {
+		// resolve takes the same OnchainCrossChainOrder as open
+		openCall, err := BuildOpenCall(hyperlaneAddress, orderDataType, o)
+		if err != nil {
+			return false, err
+		}
+		_, err = caller.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    hyperlaneAddress,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("resolve"),
+			Calldata:           openCall.CallData,
+		}, rpc.WithBlockTag("latest"))
+		if err == nil {
+			return true, nil
+		}
+
+		var rpcErr *rpc.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != rpc.ErrContractError.Code {
+			return false, err
+		}
+		return !isInvalidOrderTypePanic(err.Error()), nil
+	}
+}
+
+// isInvalidOrderTypePanic reports whether a contract error carries the INVALID_ORDER_TYPE panic.
+// Nodes render the panic either as text or as the hex of its ByteArray words
+func isInvalidOrderTypePanic(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, strings.ToLower(invalidOrderTypeMessage)) ||
+		strings.Contains(message, hex.EncodeToString([]byte(invalidOrderTypeMessage)))
+}
+
+// formatTypeHash renders a type hash as a 0x-prefixed 32-byte word
+func formatTypeHash(hash *big.Int) string {
+	return "0x" + hex.EncodeToString(bigWord(hash))
+}
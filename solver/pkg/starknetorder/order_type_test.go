@@ -0,0 +1,139 @@
+package starknetorder
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProbe accepts only the given hash and counts calls per hash
+type fakeProbe struct {
+	accept *big.Int
+	err    error
+	calls  map[string]int
+}
+
+func (f *fakeProbe) probe(_ context.Context, hash *big.Int) (bool, error) {
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[hash.String()]++
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.accept != nil && f.accept.Cmp(hash) == 0, nil
+}
+
+func TestCheckOrderDataType(t *testing.T) {
+	good := OrderDataTypeCandidate{Source: "built-in default", Hash: big.NewInt(0x08d7)}
+	stale := OrderDataTypeCandidate{Source: "ORDER_DATA_TYPE_HASH", Hash: big.NewInt(0x1234)}
+	candidates := []OrderDataTypeCandidate{stale, good}
+
+	t.Run("accepted hashes are cached per contract", func(t *testing.T) {
+		f := &fakeProbe{accept: good.Hash}
+		require.NoError(t, CheckOrderDataType(context.Background(), "accepted", good, candidates, f.probe))
+		require.NoError(t, CheckOrderDataType(context.Background(), "accepted", good, candidates, f.probe))
+		assert.Equal(t, 1, f.calls[good.Hash.String()])
+	})
+
+	t.Run("mismatch names the hash the contract accepts", func(t *testing.T) {
+		f := &fakeProbe{accept: good.Hash}
+		err := CheckOrderDataType(context.Background(), "Base 0xabc", stale, candidates, f.probe)
+
+		var mismatch *OrderDataTypeMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		require.NotNil(t, mismatch.Accepted)
+		assert.Equal(t, good.Hash, mismatch.Accepted.Hash)
+		assert.Contains(t, err.Error(), "order data type mismatch on Base 0xabc")
+		assert.Contains(t, err.Error(), "- sending:  0x0000000000000000000000000000000000000000000000000000000000001234 (ORDER_DATA_TYPE_HASH)")
+		assert.Contains(t, err.Error(), "+ contract: 0x00000000000000000000000000000000000000000000000000000000000008d7 (built-in default)")
+
+		// The mismatch is cached too
+		require.Error(t, CheckOrderDataType(context.Background(), "Base 0xabc", stale, candidates, f.probe))
+		assert.Equal(t, 1, f.calls[stale.Hash.String()])
+	})
+
+	t.Run("no known hash is accepted", func(t *testing.T) {
+		f := &fakeProbe{}
+		err := CheckOrderDataType(context.Background(), "changed", good, candidates, f.probe)
+		assert.ErrorContains(t, err, "the deployed OrderEncoder has changed")
+	})
+
+	t.Run("probe failures are retried", func(t *testing.T) {
+		f := &fakeProbe{err: errors.New("connection refused")}
+		err := CheckOrderDataType(context.Background(), "flaky", good, candidates, f.probe)
+		assert.ErrorContains(t, err, "connection refused")
+
+		f.err = nil
+		f.accept = good.Hash
+		require.NoError(t, CheckOrderDataType(context.Background(), "flaky", good, candidates, f.probe))
+	})
+}
+
+func TestKnownOrderDataTypes(t *testing.T) {
+	t.Setenv("ORDER_DATA_TYPE_HASH", "")
+	known := KnownOrderDataTypes()
+	require.Len(t, known, 1)
+	assert.Equal(t, "built-in default", known[0].Source)
+
+	t.Setenv("ORDER_DATA_TYPE_HASH", "0x1234")
+	known = KnownOrderDataTypes()
+	require.Len(t, known, 2)
+	assert.Equal(t, "ORDER_DATA_TYPE_HASH", known[0].Source)
+	assert.Equal(t, int64(0x1234), known[0].Hash.Int64())
+}
+
+// fakeCaller answers resolve calls with a fixed error
+type fakeCaller struct {
+	err  error
+	call rpc.FunctionCall
+}
+
+func (f *fakeCaller) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	f.call = call
+	return nil, f.err
+}
+
+func TestStarknetOrderDataTypeProbe(t *testing.T) {
+	hyperlane := utils.Uint64ToFelt(0x7683)
+	order := &OrderData{AmountIn: big.NewInt(1), AmountOut: big.NewInt(1), FillDeadline: 99}
+	contractError := func(message string) error {
+		return &rpc.RPCError{Code: rpc.ErrContractError.Code, Message: rpc.ErrContractError.Message, Data: rpc.StringErrData(message)}
+	}
+
+	for name, tc := range map[string]struct {
+		err      error
+		accepted bool
+		fails    bool
+	}{
+		"resolve succeeds":  {err: nil, accepted: true},
+		"text panic":        {err: contractError("Invalid order type: 4660"), accepted: false},
+		"hex panic":         {err: contractError("0x" + hex.EncodeToString([]byte("Invalid order type: 46"))), accepted: false},
+		"later check fails": {err: contractError("Invalid origin domain: 7"), accepted: true},
+		"transport error":   {err: errors.New("dial tcp: connection refused"), fails: true},
+		"other RPC error":   {err: &rpc.RPCError{Code: 24, Message: "Block not found"}, fails: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			caller := &fakeCaller{err: tc.err}
+			accepted, err := StarknetOrderDataTypeProbe(caller, hyperlane, order)(context.Background(), big.NewInt(0x1234))
+			if tc.fails {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.accepted, accepted)
+
+			assert.Equal(t, utils.GetSelectorFromNameFelt("resolve"), caller.call.EntryPointSelector)
+			assert.Equal(t, uint64(99), caller.call.Calldata[0].Uint64(), "fill deadline")
+			assert.Equal(t, uint64(0x1234), caller.call.Calldata[1].Uint64(), "type hash low")
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package starknetorder
+
+// Permit2 witness signatures for open_for
+// open_for pulls the order's inputs through Permit2's permit_witness_batch_transfer_from, with the resolved order's
+// struct hash as the witness, so the user signs a batch permit for the min_received tokens, spent by the
+// Hyperlane7683 contract, with the order's nonce and open deadline. The hashes below follow base7683.cairo and
+// Permit2's snip12_utils exactly, including where they depart from what SNIP-12 typed data would give:
+// - the witness is hashed with RESOLVED_CROSS_CHAIN_ORDER_TYPE_HASH, whose type string lists the field as "Fill
+//   Instruction" and its dependencies in another order than WITNESS_TYPE_STRING
+// - min_received hashes are appended to the max_spent array, leaving min_received an empty array
+// - Bytes is hashed as its size and u128 words, without a type hash
+// so a wallet hashing the typed data from TypedData would sign another message than the one Permit2 checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typedata"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+const (
+	// permit2DomainName and permit2DomainVersion are Permit2's SNIP12Metadata
+	permit2DomainName    = "Permit2"
+	permit2DomainVersion = "v1"
+
+	// permitWitnessBatchType is the typed-data primary type of the signed permit
+	permitWitnessBatchType = "Permit Witness Batch Transfer From"
+
+	// u256Type and the other type strings are the encoded types hashed by the Cairo contracts
+	u256Type                 = `"u256"("low":"u128","high":"u128")`
+	tokenPermissionsType     = `"Token Permissions"("Token":"ContractAddress","Amount":"u256")` + u256Type
+	outputType               = `"Output"("Token":"ContractAddress","Amount":"u256","Recipient":"ContractAddress","Chain ID":"u128")` + u256Type
+	fillInstructionType      = `"Fill Instruction"("Destination Chain ID":"u128","Destination Settler":"ContractAddress","Origin Data":"Bytes")"Bytes"("Size":"u128","Data":"u128*")`
+	resolvedCrossChainOrder  = `"Resolved Cross Chain Order"("User":"ContractAddress","Origin Chain ID":"u128","Open Deadline":"timestamp","Fill Deadline":"timestamp","Order ID":"u256","Max Spent":"Output*","Min Received":"Output*","Fill Instruction":"Fill Instruction*")"Bytes"("Size":"u128","Data":"u128*")"Fill Instruction"("Destination Chain ID":"u128","Destination Settler":"ContractAddress","Origin Data":"Bytes")"Output"("Token":"ContractAddress","Amount":"u256","Recipient":"ContractAddress","Chain ID":"u128")` + u256Type
+	permitWitnessBatchPrefix = `"Permit Witness Batch Transfer From"("Permitted":"Token Permissions*","Spender":"ContractAddress","Nonce":"felt","Deadline":"u256",`
+
+	// WitnessTypeString is base7683.cairo's WITNESS_TYPE_STRING, which completes the permit's type string
+	WitnessTypeString = `"Witness":"Resolved Cross Chain Order")"Bytes"("Size":"u128","Data":"u128*")"Fill Instruction"("Destination Chain ID":"u128","Destination Settler":"ContractAddress","Origin Data":"Bytes")"Resolved Cross Chain Order"("User":"ContractAddress","Origin Chain ID":"u128","Open Deadline":"timestamp","Fill Deadline":"timestamp","Order ID":"u256","Max Spent":"Output*","Min Received":"Output*","Fill Instructions":"Fill Instruction*")"Output"("Token":"ContractAddress","Amount":"u256","Recipient":"ContractAddress","Chain ID":"u128")"Token Permissions"("Token":"ContractAddress","Amount":"u256")` + u256Type
+)
+
+var (
+	u256TypeHash                = curve.StarknetKeccak([]byte(u256Type))
+	tokenPermissionsTypeHash    = curve.StarknetKeccak([]byte(tokenPermissionsType))
+	outputTypeHash              = curve.StarknetKeccak([]byte(outputType))
+	fillInstructionTypeHash     = curve.StarknetKeccak([]byte(fillInstructionType))
+	resolvedCrossChainOrderHash = curve.StarknetKeccak([]byte(resolvedCrossChainOrder))
+	permitWitnessBatchTypeHash  = curve.StarknetKeccak([]byte(permitWitnessBatchPrefix + WitnessTypeString))
+	starknetDomainTypeHash      = curve.StarknetKeccak([]byte(`"StarknetDomain"("name":"shortstring","version":"shortstring","chainId":"shortstring","revision":"shortstring")`))
+	starknetMessagePrefix       = new(felt.Felt).SetBytes([]byte("StarkNet Message"))
+)
+
+// PermitWitness is the Permit2 witness batch transfer a user signs so a solver can open_for their order
+type PermitWitness struct {
+	// ChainID is the Starknet chain ID short string of the domain, such as SN_SEPOLIA
+	ChainID string
+	// Settler is the Hyperlane7683 contract: the order's origin_settler and the permit's spender
+	Settler *felt.Felt
+	// Nonce is the gasless order's nonce, which is also the Permit2 nonce
+	Nonce *felt.Felt
+	// Order is the order as open_for resolves it; its user signs and its open deadline is the permit's
+	Order ResolvedOrder
+}
+
+// NewPermitWitness resolves o as open_for would for o.Sender and returns the permit they sign to open it on
+// settler. The order's sender nonce is the gasless nonce, as the contract uses it for both
+func NewPermitWitness(o *OrderData, chainID string, settler *felt.Felt, openDeadline uint64) (*PermitWitness, error) {
+	if o.Sender == nil || o.SenderNonce == nil {
+		return nil, fmt.Errorf("a gasless order needs a sender and a sender nonce")
+	}
+	resolved, err := ResolveGasless(o, openDeadline)
+	if err != nil {
+		return nil, err
+	}
+	return &PermitWitness{ChainID: chainID, Settler: settler, Nonce: o.SenderNonce, Order: resolved}, nil
+}
+
+// BuildPermitWitnessTypedData returns the typed data of the permit o.Sender signs to open o on settler
+func BuildPermitWitnessTypedData(o *OrderData, chainID string, settler *felt.Felt, openDeadline uint64) (*typedata.TypedData, error) {
+	permit, err := NewPermitWitness(o, chainID, settler, openDeadline)
+	if err != nil {
+		return nil, err
+	}
+	return permit.TypedData()
+}
+
+// ResolveGasless returns the resolved order base7683's resolve_for gives o: o.Sender is the user and the fill
+// instruction carries o encoded with its fill deadline
+func ResolveGasless(o *OrderData, openDeadline uint64) (ResolvedOrder, error) {
+	encoded, err := EncodeOrderData(o)
+	if err != nil {
+		return ResolvedOrder{}, err
+	}
+	return ResolvedOrder{
+		User:          o.Sender,
+		OriginChainID: o.OriginDomain,
+		OpenDeadline:  openDeadline,
+		FillDeadline:  uint64(o.FillDeadline),
+		OrderID:       ComputeOrderID(encoded),
+		MaxSpent: []Output{{
+			Token: o.OutputToken, Amount: o.AmountOut, Recipient: o.DestinationSettler, ChainID: o.DestinationDomain,
+		}},
+		MinReceived: []Output{{
+			Token: o.InputToken, Amount: o.AmountIn, Recipient: new(felt.Felt), ChainID: o.OriginDomain,
+		}},
+		FillInstructions: []FillInstruction{{
+			DestinationChainID: o.DestinationDomain, DestinationSettler: o.DestinationSettler, OriginData: encoded,
+		}},
+	}, nil
+}
+
+// WitnessHash is the resolved order's struct hash, as witness_hash returns it
+func (p *PermitWitness) WitnessHash() *felt.Felt {
+	ro := p.Order
+	var spent []*felt.Felt
+	for _, out := range ro.MaxSpent {
+		spent = append(spent, outputHash(out))
+	}
+	// base7683.cairo appends the min_received hashes to the max_spent array
+	for _, out := range ro.MinReceived {
+		spent = append(spent, outputHash(out))
+	}
+	fills := make([]*felt.Felt, 0, len(ro.FillInstructions))
+	for _, fill := range ro.FillInstructions {
+		fills = append(fills, curve.PoseidonArray(
+			fillInstructionTypeHash,
+			utils.Uint64ToFelt(uint64(fill.DestinationChainID)),
+			fill.DestinationSettler,
+			bytesHash(fill.OriginData),
+		))
+	}
+	return curve.PoseidonArray(
+		resolvedCrossChainOrderHash,
+		ro.User,
+		utils.Uint64ToFelt(uint64(ro.OriginChainID)),
+		utils.Uint64ToFelt(ro.OpenDeadline),
+		utils.Uint64ToFelt(ro.FillDeadline),
+		u256Hash(new(big.Int).SetBytes(ro.OrderID.Bytes())),
+		curve.PoseidonArray(spent...),
+		curve.PoseidonArray(),
+		curve.PoseidonArray(fills...),
+	)
+}
+
+// StructHash is the hash of the permit with its witness, as Permit2 computes it
+func (p *PermitWitness) StructHash() *felt.Felt {
+	permitted := make([]*felt.Felt, 0, len(p.Order.MinReceived))
+	for _, in := range p.Order.MinReceived {
+		permitted = append(permitted, curve.PoseidonArray(tokenPermissionsTypeHash, in.Token, u256Hash(in.Amount)))
+	}
+	return curve.PoseidonArray(
+		permitWitnessBatchTypeHash,
+		curve.PoseidonArray(permitted...),
+		p.Settler,
+		p.Nonce,
+		u256Hash(new(big.Int).SetUint64(p.Order.OpenDeadline)),
+		p.WitnessHash(),
+	)
+}
+
+// DomainHash is the struct hash of Permit2's StarknetDomain on ChainID
+func (p *PermitWitness) DomainHash() *felt.Felt {
+	return curve.PoseidonArray(
+		starknetDomainTypeHash,
+		shortString(permit2DomainName),
+		shortString(permit2DomainVersion),
+		shortString(p.ChainID),
+		new(felt.Felt).SetUint64(1),
+	)
+}
+
+// MessageHash is the hash the order's user signs
+func (p *PermitWitness) MessageHash() *felt.Felt {
+	return curve.PoseidonArray(starknetMessagePrefix, p.DomainHash(), p.Order.User, p.StructHash())
+}
+
+// Sign signs the message hash with the user's Stark key and returns the [r, s] signature open_for takes
+func (p *PermitWitness) Sign(privateKey *felt.Felt) ([]*felt.Felt, error) {
+	r, s, err := curve.SignFelts(p.MessageHash(), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign permit witness: %w", err)
+	}
+	return []*felt.Felt{r, s}, nil
+}
+
+// TypedData renders the permit as SNIP-12 typed data, with the witness types of WitnessTypeString, to show what is
+// signed. Its hashes are not the ones Permit2 checks (see the top of this file): sign MessageHash instead
+func (p *PermitWitness) TypedData() (*typedata.TypedData, error) {
+	ro := p.Order
+	outputs := func(outs []Output) []any {
+		list := make([]any, 0, len(outs))
+		for _, out := range outs {
+			list = append(list, map[string]any{
+				"Token":     out.Token.String(),
+				"Amount":    u256Message(out.Amount),
+				"Recipient": out.Recipient.String(),
+				"Chain ID":  fmt.Sprint(out.ChainID),
+			})
+		}
+		return list
+	}
+	permitted := make([]any, 0, len(ro.MinReceived))
+	for _, in := range ro.MinReceived {
+		permitted = append(permitted, map[string]any{"Token": in.Token.String(), "Amount": u256Message(in.Amount)})
+	}
+	fills := make([]any, 0, len(ro.FillInstructions))
+	for _, fill := range ro.FillInstructions {
+		words := starknetutil.BytesToU128Felts(fill.OriginData)
+		data := make([]any, 0, len(words))
+		for _, word := range words {
+			data = append(data, word.String())
+		}
+		fills = append(fills, map[string]any{
+			"Destination Chain ID": fmt.Sprint(fill.DestinationChainID),
+			"Destination Settler":  fill.DestinationSettler.String(),
+			"Origin Data":          map[string]any{"Size": fmt.Sprint(len(fill.OriginData)), "Data": data},
+		})
+	}
+	message := map[string]any{
+		"Permitted": permitted,
+		"Spender":   p.Settler.String(),
+		"Nonce":     p.Nonce.String(),
+		"Deadline":  u256Message(new(big.Int).SetUint64(ro.OpenDeadline)),
+		"Witness": map[string]any{
+			"User":              ro.User.String(),
+			"Origin Chain ID":   fmt.Sprint(ro.OriginChainID),
+			"Open Deadline":     fmt.Sprint(ro.OpenDeadline),
+			"Fill Deadline":     fmt.Sprint(ro.FillDeadline),
+			"Order ID":          u256Message(new(big.Int).SetBytes(ro.OrderID.Bytes())),
+			"Max Spent":         outputs(ro.MaxSpent),
+			"Min Received":      outputs(ro.MinReceived),
+			"Fill Instructions": fills,
+		},
+	}
+
+	types := []typedata.TypeDefinition{
+		{Name: "StarknetDomain", Parameters: []typedata.TypeParameter{
+			{Name: "name", Type: "shortstring"},
+			{Name: "version", Type: "shortstring"},
+			{Name: "chainId", Type: "shortstring"},
+			{Name: "revision", Type: "shortstring"},
+		}},
+		{Name: permitWitnessBatchType, Parameters: []typedata.TypeParameter{
+			{Name: "Permitted", Type: "Token Permissions*"},
+			{Name: "Spender", Type: "ContractAddress"},
+			{Name: "Nonce", Type: "felt"},
+			{Name: "Deadline", Type: "u256"},
+			{Name: "Witness", Type: "Resolved Cross Chain Order"},
+		}},
+		{Name: "Token Permissions", Parameters: []typedata.TypeParameter{
+			{Name: "Token", Type: "ContractAddress"},
+			{Name: "Amount", Type: "u256"},
+		}},
+		{Name: "Resolved Cross Chain Order", Parameters: []typedata.TypeParameter{
+			{Name: "User", Type: "ContractAddress"},
+			{Name: "Origin Chain ID", Type: "u128"},
+			{Name: "Open Deadline", Type: "timestamp"},
+			{Name: "Fill Deadline", Type: "timestamp"},
+			{Name: "Order ID", Type: "u256"},
+			{Name: "Max Spent", Type: "Output*"},
+			{Name: "Min Received", Type: "Output*"},
+			{Name: "Fill Instructions", Type: "Fill Instruction*"},
+		}},
+		{Name: "Output", Parameters: []typedata.TypeParameter{
+			{Name: "Token", Type: "ContractAddress"},
+			{Name: "Amount", Type: "u256"},
+			{Name: "Recipient", Type: "ContractAddress"},
+			{Name: "Chain ID", Type: "u128"},
+		}},
+		{Name: "Fill Instruction", Parameters: []typedata.TypeParameter{
+			{Name: "Destination Chain ID", Type: "u128"},
+			{Name: "Destination Settler", Type: "ContractAddress"},
+			{Name: "Origin Data", Type: "Bytes"},
+		}},
+		{Name: "Bytes", Parameters: []typedata.TypeParameter{
+			{Name: "Size", Type: "u128"},
+			{Name: "Data", Type: "u128*"},
+		}},
+	}
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	domain := typedata.Domain{
+		Name:     permit2DomainName,
+		Version:  permit2DomainVersion,
+		ChainID:  p.ChainID,
+		Revision: 1,
+	}
+	td, err := typedata.NewTypedData(types, permitWitnessBatchType, domain, messageJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build permit witness typed data: %w", err)
+	}
+	return td, nil
+}
+
+// BuildOpenForCall builds the open_for(GaslessCrossChainOrder, signature, origin_filler_data) invoke call with
+// which a solver opens o for its sender on hyperlaneAddress, with an empty origin filler data
+func BuildOpenForCall(hyperlaneAddress *felt.Felt, orderDataType *big.Int, o *OrderData, openDeadline uint64, signature []*felt.Felt) (rpc.InvokeFunctionCall, error) {
+	orderData, err := EncodeOrderDataCalldata(o)
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, err
+	}
+	typeLow, typeHigh := starknetutil.ToU256(orderDataType)
+
+	calldata := []*felt.Felt{
+		hyperlaneAddress,
+		o.Sender,
+		o.SenderNonce,
+		utils.Uint64ToFelt(uint64(o.OriginDomain)),
+		utils.Uint64ToFelt(openDeadline),
+		utils.Uint64ToFelt(uint64(o.FillDeadline)),
+		typeLow, typeHigh,
+	}
+	calldata = append(calldata, orderData...)
+	calldata = append(calldata, utils.Uint64ToFelt(uint64(len(signature))))
+	calldata = append(calldata, signature...)
+	calldata = append(calldata, starknetutil.ToCairoBytes(nil)...)
+
+	return rpc.InvokeFunctionCall{
+		ContractAddress: hyperlaneAddress,
+		FunctionName:    "open_for",
+		CallData:        calldata,
+	}, nil
+}
+
+// outputHash is the Cairo OutputStructHash
+func outputHash(out Output) *felt.Felt {
+	return curve.PoseidonArray(
+		outputTypeHash, out.Token, u256Hash(out.Amount), out.Recipient, utils.Uint64ToFelt(uint64(out.ChainID)),
+	)
+}
+
+// u256Hash is the struct hash of a u256
+func u256Hash(n *big.Int) *felt.Felt {
+	low, high := starknetutil.ToU256(n)
+	return curve.PoseidonArray(u256TypeHash, low, high)
+}
+
+// bytesHash is the Cairo BytesStructHash: the size and the u128 words
+func bytesHash(b []byte) *felt.Felt {
+	return curve.PoseidonArray(append([]*felt.Felt{utils.Uint64ToFelt(uint64(len(b)))}, starknetutil.BytesToU128Felts(b)...)...)
+}
+
+// u256Message renders a u256 as a typed-data message value
+func u256Message(n *big.Int) map[string]any {
+	low, high := starknetutil.ToU256(n)
+	return map[string]any{"low": low.String(), "high": high.String()}
+}
+
+// shortString encodes a Cairo short string
+func shortString(s string) *felt.Felt {
+	return new(felt.Felt).SetBytes([]byte(s))
+}
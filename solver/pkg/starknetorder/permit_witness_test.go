@@ -0,0 +1,153 @@
+package starknetorder
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/typedata"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSettler is the Hyperlane7683 the test permits are signed for
+const testSettler = "0x2f5aa3f4a2b4e8f3d6c1b2a3e4f5061728394a5b6c7d8e9f0a1b2c3d4e5f607"
+
+func testPermitWitness(t *testing.T) *PermitWitness {
+	t.Helper()
+	o := testOrderData(t)
+	permit, err := NewPermitWitness(&o, "SN_SEPOLIA", mustFelt(t, testSettler), 1699990000)
+	require.NoError(t, err)
+	return permit
+}
+
+func TestPermitWitnessTypeHashes(t *testing.T) {
+	// FULL_WITNESS_BATCH_TYPE_HASH of the Cairo tests, which sign open_for permits with it
+	full := `"Permit Witness Batch Transfer From"("Permitted":"Token Permissions*","Spender":"ContractAddress","Nonce":"felt","Deadline":"u256","Witness":"Resolved Cross Chain Order")"Bytes"("Size":"u128","Data":"u128*")"Fill Instruction"("Destination Chain ID":"u128","Destination Settler":"ContractAddress","Origin Data":"Bytes")"Resolved Cross Chain Order"("User":"ContractAddress","Origin Chain ID":"u128","Open Deadline":"timestamp","Fill Deadline":"timestamp","Order ID":"u256","Max Spent":"Output*","Min Received":"Output*","Fill Instructions":"Fill Instruction*")"Output"("Token":"ContractAddress","Amount":"u256","Recipient":"ContractAddress","Chain ID":"u128")"Token Permissions"("Token":"ContractAddress","Amount":"u256")"u256"("low":"u128","high":"u128")`
+	assert.Equal(t, curve.StarknetKeccak([]byte(full)), permitWitnessBatchTypeHash)
+
+	// Permit2's own types hash the same as typed data does
+	td := loadTypedData(t, "PermitBatchTransferFrom.json")
+	tokenPermissions, err := td.GetTypeHash("Token Permissions")
+	require.NoError(t, err)
+	assert.Equal(t, tokenPermissions, tokenPermissionsTypeHash)
+	u256, err := td.GetTypeHash("u256")
+	require.NoError(t, err)
+	assert.Equal(t, u256, u256TypeHash)
+}
+
+// TestPermitWitnessMessageFraming checks the domain and message hashing against typed data, on a Permit2 message
+// of the same domain
+func TestPermitWitnessMessageFraming(t *testing.T) {
+	td := loadTypedData(t, "PermitBatchTransferFrom.json")
+	account := "0x064b48806902a367c8598f4f95c305e8c1a1acba5f082d294a43793113115691"
+	structHash, err := td.GetStructHash(td.PrimaryType)
+	require.NoError(t, err)
+	want, err := td.GetMessageHash(account)
+	require.NoError(t, err)
+
+	permit := &PermitWitness{ChainID: td.Domain.ChainID}
+	got := curve.PoseidonArray(starknetMessagePrefix, permit.DomainHash(), mustFelt(t, account), structHash)
+	assert.Equal(t, want, got)
+}
+
+// TestPermitWitnessGolden pins the hashes of testOrderData's permit, so a change to what users sign is deliberate.
+// They were computed by this package: to check them against the contract, witness_hash(resolve_for(order)) on a
+// Hyperlane7683 with local domain 23448591 must return the witness hash
+func TestPermitWitnessGolden(t *testing.T) {
+	permit := testPermitWitness(t)
+	assert.Equal(t, "0x101c42c777df9e589d5895d53b045e297ec28372b90da436a125e478704911b", permit.WitnessHash().String(), "witness")
+	assert.Equal(t, "0x2ac280e4de7b8398e4dec46f92cec4ae356765b16b08b273ccfada0de7238d3", permit.StructHash().String(), "struct")
+	assert.Equal(t, "0x303ee3c8df5f11f5482d8130ee0e0d068ad7b6ff5b8b518d798b1f73c1b8959", permit.MessageHash().String(), "message")
+}
+
+func TestPermitWitnessResolve(t *testing.T) {
+	o := testOrderData(t)
+	permit := testPermitWitness(t)
+	ro := permit.Order
+
+	assert.True(t, o.Sender.Equal(ro.User))
+	assert.Equal(t, uint64(1699990000), ro.OpenDeadline)
+	assert.Equal(t, uint64(o.FillDeadline), ro.FillDeadline)
+	assert.Equal(t, ComputeOrderID(mustEncode(t, &o)), ro.OrderID)
+	require.Len(t, ro.MinReceived, 1)
+	assert.True(t, o.InputToken.Equal(ro.MinReceived[0].Token))
+	assert.True(t, ro.MinReceived[0].Recipient.IsZero())
+	require.Len(t, ro.FillInstructions, 1)
+	assert.Equal(t, mustEncode(t, &o), ro.FillInstructions[0].OriginData)
+	assert.True(t, o.SenderNonce.Equal(permit.Nonce))
+
+	// The witness covers every field a solver could tamper with
+	tampered := *permit
+	tampered.Order.MaxSpent = []Output{{Token: o.OutputToken, Amount: big.NewInt(1), Recipient: o.DestinationSettler, ChainID: o.DestinationDomain}}
+	assert.NotEqual(t, permit.WitnessHash(), tampered.WitnessHash())
+
+	o.SenderNonce = nil
+	_, err := NewPermitWitness(&o, "SN_SEPOLIA", mustFelt(t, testSettler), 1699990000)
+	assert.Error(t, err)
+}
+
+func TestPermitWitnessSign(t *testing.T) {
+	permit := testPermitWitness(t)
+	privateKey := utils.Uint64ToFelt(0x1234567890abcdef)
+	signature, err := permit.Sign(privateKey)
+	require.NoError(t, err)
+	require.Len(t, signature, 2)
+
+	publicKey, _ := curve.PrivateKeyToPoint(utils.FeltToBigInt(privateKey))
+	ok, err := curve.VerifyFelts(permit.MessageHash(), signature[0], signature[1], utils.BigIntToFelt(publicKey))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestPermitWitnessTypedData(t *testing.T) {
+	o := testOrderData(t)
+	td, err := BuildPermitWitnessTypedData(&o, "SN_SEPOLIA", mustFelt(t, testSettler), 1699990000)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Permit Witness Batch Transfer From", td.PrimaryType)
+	assert.Equal(t, "Permit2", td.Domain.Name)
+	assert.Equal(t, "SN_SEPOLIA", td.Domain.ChainID)
+	assert.Equal(t, testSettler, td.Message["Spender"])
+	witness, ok := td.Message["Witness"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, o.Sender.String(), witness["User"])
+	assert.Len(t, witness["Fill Instructions"], 1)
+}
+
+func TestBuildOpenForCall(t *testing.T) {
+	o := testOrderData(t)
+	hyperlane := mustFelt(t, testSettler)
+	signature := []*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(2)}
+	call, err := BuildOpenForCall(hyperlane, big.NewInt(7), &o, 1699990000, signature)
+	require.NoError(t, err)
+
+	assert.Equal(t, "open_for", call.FunctionName)
+	orderData, err := EncodeOrderDataCalldata(&o)
+	require.NoError(t, err)
+	cd := call.CallData
+	require.Len(t, cd, 8+len(orderData)+3+2)
+	assert.True(t, hyperlane.Equal(cd[0]), "origin_settler")
+	assert.True(t, o.Sender.Equal(cd[1]), "user")
+	assert.True(t, o.SenderNonce.Equal(cd[2]), "nonce")
+	assert.Equal(t, uint64(o.OriginDomain), cd[3].Uint64(), "origin_chain_id")
+	assert.Equal(t, uint64(1699990000), cd[4].Uint64(), "open_deadline")
+	assert.Equal(t, uint64(o.FillDeadline), cd[5].Uint64(), "fill_deadline")
+	assert.Equal(t, uint64(7), cd[6].Uint64(), "order_data_type low")
+	assert.Equal(t, orderData, cd[8:8+len(orderData)])
+	rest := cd[8+len(orderData):]
+	assert.Equal(t, []*felt.Felt{utils.Uint64ToFelt(2), signature[0], signature[1]}, rest[:3], "signature")
+	assert.True(t, rest[3].IsZero() && rest[4].IsZero(), "empty origin_filler_data")
+}
+
+func loadTypedData(t *testing.T, name string) *typedata.TypedData {
+	t.Helper()
+	data, err := os.ReadFile("../../../typedData/" + name)
+	require.NoError(t, err)
+	var td typedata.TypedData
+	require.NoError(t, td.UnmarshalJSON(data))
+	return &td
+}
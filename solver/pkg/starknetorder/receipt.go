@@ -0,0 +1,84 @@
+package starknetorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+// ReceiptFetcher reads transaction receipts (rpc.Provider satisfies it)
+type ReceiptFetcher interface {
+	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error)
+}
+
+// TransactionRevertedError reports a transaction that was included but reverted
+type TransactionRevertedError struct {
+	Label  string
+	Hash   *felt.Felt
+	Reason string
+}
+
+// Error renders the revert with the reason from the receipt
+func (e *TransactionRevertedError) Error() string {
+	return fmt.Sprintf("%s transaction %s reverted: %s", e.Label, e.Hash.String(), e.Reason)
+}
+
+// WaitForAcceptedReceipt polls until the transaction is accepted on L2 (or L1) and checks it succeeded.
+// Unknown hashes and pre-confirmed receipts keep the poll going, since their execution status is not final yet.
+// A reverted transaction returns its receipt together with a *TransactionRevertedError. The fee is recorded into
+// ctx's txcost ledger, under label unless ctx names the operation
+func WaitForAcceptedReceipt(
+	ctx context.Context,
+	fetcher ReceiptFetcher,
+	label string,
+	hash *felt.Felt,
+	interval time.Duration,
+) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for %s transaction %s: %w", label, hash.String(), ctx.Err())
+		case <-ticker.C:
+		}
+
+		receipt, err := fetcher.TransactionReceipt(ctx, hash)
+		if err != nil {
+			var rpcErr *rpc.RPCError
+			if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrHashNotFound.Code {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s receipt %s: %w", label, hash.String(), err)
+		}
+		if !isFinalReceipt(receipt.FinalityStatus) {
+			continue
+		}
+		// A revert is charged too
+		txcost.RecordStarknet(txcost.WithDefaultOperation(ctx, label), &receipt.TransactionReceipt)
+		if receipt.ExecutionStatus == rpc.TxnExecutionStatusREVERTED {
+			return receipt, &TransactionRevertedError{Label: label, Hash: hash, Reason: receipt.RevertReason}
+		}
+		return receipt, nil
+	}
+}
+
+// waitForAcceptedReceipt runs WaitForAcceptedReceipt with retries on transient RPC failures. Reverts and timeouts
+// are final
+func waitForAcceptedReceipt(ctx context.Context, p starknetutil.RetryPolicy, fetcher ReceiptFetcher, label string, hash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	return starknetutil.Retry(ctx, p, label+" receipt wait", func(ctx context.Context) (*rpc.TransactionReceiptWithBlockInfo, error) {
+		return WaitForAcceptedReceipt(ctx, fetcher, label, hash, receiptPollInterval)
+	})
+}
+
+// isFinalReceipt reports whether a receipt's execution status can no longer change
+func isFinalReceipt(status rpc.TxnFinalityStatus) bool {
+	return status == rpc.TxnFinalityStatusAcceptedOnL2 || status == rpc.TxnFinalityStatusAcceptedOnL1
+}
@@ -0,0 +1,93 @@
+package starknetorder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedFetcher answers receipt polls in order, repeating the last answer
+type scriptedFetcher struct {
+	receipts []*rpc.TransactionReceiptWithBlockInfo
+	errs     []error
+	calls    int
+}
+
+func (f *scriptedFetcher) TransactionReceipt(_ context.Context, _ *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	i := f.calls
+	if i >= len(f.receipts) {
+		i = len(f.receipts) - 1
+	}
+	f.calls++
+	return f.receipts[i], f.errs[i]
+}
+
+func receiptWith(finality rpc.TxnFinalityStatus, execution rpc.TxnExecutionStatus, reason string) *rpc.TransactionReceiptWithBlockInfo {
+	return &rpc.TransactionReceiptWithBlockInfo{TransactionReceipt: rpc.TransactionReceipt{
+		FinalityStatus:  finality,
+		ExecutionStatus: execution,
+		RevertReason:    reason,
+	}}
+}
+
+func TestWaitForAcceptedReceipt(t *testing.T) {
+	hash := utils.Uint64ToFelt(0xabc)
+	notFound := &rpc.RPCError{Code: rpc.ErrHashNotFound.Code, Message: rpc.ErrHashNotFound.Message}
+
+	t.Run("polls past unknown and pre-confirmed receipts", func(t *testing.T) {
+		fetcher := &scriptedFetcher{
+			receipts: []*rpc.TransactionReceiptWithBlockInfo{
+				nil,
+				receiptWith(rpc.TxnFinalityStatusPreConfirmed, rpc.TxnExecutionStatusSUCCEEDED, ""),
+				receiptWith(rpc.TxnFinalityStatusAcceptedOnL2, rpc.TxnExecutionStatusSUCCEEDED, ""),
+			},
+			errs: []error{notFound, nil, nil},
+		}
+		receipt, err := WaitForAcceptedReceipt(context.Background(), fetcher, "open", hash, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, rpc.TxnFinalityStatusAcceptedOnL2, receipt.FinalityStatus)
+		assert.Equal(t, 3, fetcher.calls)
+	})
+
+	t.Run("reverted receipt carries the revert reason", func(t *testing.T) {
+		reason := "Error in the called contract: 0x496e76616c6964206f726465722074797065 ('Invalid order type')"
+		fetcher := &scriptedFetcher{
+			receipts: []*rpc.TransactionReceiptWithBlockInfo{receiptWith(rpc.TxnFinalityStatusAcceptedOnL2, rpc.TxnExecutionStatusREVERTED, reason)},
+			errs:     []error{nil},
+		}
+		receipt, err := WaitForAcceptedReceipt(context.Background(), fetcher, "open", hash, time.Millisecond)
+		require.NotNil(t, receipt)
+
+		var reverted *TransactionRevertedError
+		require.ErrorAs(t, err, &reverted)
+		assert.Equal(t, reason, reverted.Reason)
+		assert.Equal(t, "open transaction "+hash.String()+" reverted: "+reason, err.Error())
+	})
+
+	t.Run("other RPC errors are returned", func(t *testing.T) {
+		fetcher := &scriptedFetcher{
+			receipts: []*rpc.TransactionReceiptWithBlockInfo{nil},
+			errs:     []error{errors.New("connection refused")},
+		}
+		_, err := WaitForAcceptedReceipt(context.Background(), fetcher, "approve", hash, time.Millisecond)
+		assert.ErrorContains(t, err, "failed to read approve receipt")
+	})
+
+	t.Run("gives up when the context ends", func(t *testing.T) {
+		fetcher := &scriptedFetcher{
+			receipts: []*rpc.TransactionReceiptWithBlockInfo{receiptWith(rpc.TxnFinalityStatusPreConfirmed, rpc.TxnExecutionStatusSUCCEEDED, "")},
+			errs:     []error{nil},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := WaitForAcceptedReceipt(ctx, fetcher, "open", hash, time.Millisecond)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
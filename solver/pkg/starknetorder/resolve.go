@@ -0,0 +1,192 @@
+package starknetorder
+
+// Resolved order check
+// Before open() is sent, resolve() shows what the contract makes of the encoded order: the outputs the filler
+// spends and receives, where it fills and the order ID. The tools compare that with the order they meant to
+// build, in 32-byte words both contracts share, so an encoding mistake aborts before any funds are locked
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// Resolution is a resolved order reduced to what the tools check, as EVM and Cairo contracts both resolve it
+type Resolution struct {
+	OrderID          common.Hash
+	MaxSpent         []ResolvedOutput
+	MinReceived      []ResolvedOutput
+	FillInstructions []ResolvedFill
+}
+
+// ResolvedOutput is an Output of a resolved order
+type ResolvedOutput struct {
+	Token   common.Hash
+	Amount  *big.Int
+	ChainID uint64
+}
+
+// ResolvedFill is a FillInstruction of a resolved order
+type ResolvedFill struct {
+	DestinationChainID uint64
+	DestinationSettler common.Hash
+}
+
+// Intent is the order the tools meant to open
+type Intent struct {
+	OrderID            common.Hash
+	InputToken         common.Hash
+	AmountIn           *big.Int
+	OriginDomain       uint32
+	OutputToken        common.Hash
+	AmountOut          *big.Int
+	DestinationDomain  uint32
+	DestinationSettler common.Hash
+}
+
+// ResolutionDiff is a value resolve() returned that differs from the intended one
+type ResolutionDiff struct {
+	Field    string
+	Intended string
+	Resolved string
+}
+
+// ResolutionMismatchError reports a resolved order that is not the order the tools meant to open
+type ResolutionMismatchError struct {
+	Contract string
+	Diffs    []ResolutionDiff
+}
+
+// Error renders the mismatch as a diff between the intended and the resolved values
+func (e *ResolutionMismatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "resolve() on %s does not match the order built:", e.Contract)
+	for _, d := range e.Diffs {
+		fmt.Fprintf(&b, "\n  - intended %s: %s\n  + resolved %s: %s", d.Field, d.Intended, d.Field, d.Resolved)
+	}
+	return b.String()
+}
+
+// CheckResolution compares r with intent. Every difference is listed in a *ResolutionMismatchError; contract
+// identifies the contract in it
+func CheckResolution(contract string, intent Intent, r Resolution) error {
+	var diffs []ResolutionDiff
+	check := func(field, intended, resolved string) {
+		if intended != resolved {
+			diffs = append(diffs, ResolutionDiff{Field: field, Intended: intended, Resolved: resolved})
+		}
+	}
+
+	check("orderId", intent.OrderID.Hex(), r.OrderID.Hex())
+	// BasicSwap7683 resolves one output each way and one fill instruction
+	check("maxSpent", "1", fmt.Sprint(len(r.MaxSpent)))
+	if len(r.MaxSpent) > 0 {
+		out := r.MaxSpent[0]
+		check("maxSpent[0].token", intent.OutputToken.Hex(), out.Token.Hex())
+		check("maxSpent[0].amount", amountString(intent.AmountOut), amountString(out.Amount))
+		check("maxSpent[0].chainId", fmt.Sprint(intent.DestinationDomain), fmt.Sprint(out.ChainID))
+	}
+	check("minReceived", "1", fmt.Sprint(len(r.MinReceived)))
+	if len(r.MinReceived) > 0 {
+		out := r.MinReceived[0]
+		check("minReceived[0].token", intent.InputToken.Hex(), out.Token.Hex())
+		check("minReceived[0].amount", amountString(intent.AmountIn), amountString(out.Amount))
+		check("minReceived[0].chainId", fmt.Sprint(intent.OriginDomain), fmt.Sprint(out.ChainID))
+	}
+	check("fillInstructions", "1", fmt.Sprint(len(r.FillInstructions)))
+	if len(r.FillInstructions) > 0 {
+		fill := r.FillInstructions[0]
+		check("fillInstructions[0].destinationChainId", fmt.Sprint(intent.DestinationDomain), fmt.Sprint(fill.DestinationChainID))
+		check("fillInstructions[0].destinationSettler", intent.DestinationSettler.Hex(), fill.DestinationSettler.Hex())
+	}
+
+	if len(diffs) > 0 {
+		return &ResolutionMismatchError{Contract: contract, Diffs: diffs}
+	}
+	return nil
+}
+
+// amountString renders an amount for a diff, nil as zero
+func amountString(n *big.Int) string {
+	if n == nil {
+		return "0"
+	}
+	return n.String()
+}
+
+// Resolve calls resolve(order) on a Cairo Hyperlane7683. A view has no caller, so the contract resolves the order
+// for the zero sender and the order ID is the one of o with a zero Sender
+func Resolve(ctx context.Context, caller starknetutil.ContractCaller, hyperlaneAddress *felt.Felt, orderDataType *big.Int, o *OrderData) (ResolvedOrder, error) {
+	// resolve takes the same OnchainCrossChainOrder as open
+	openCall, err := BuildOpenCall(hyperlaneAddress, orderDataType, o)
+	if err != nil {
+		return ResolvedOrder{}, err
+	}
+	resp, err := caller.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    hyperlaneAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("resolve"),
+		Calldata:           openCall.CallData,
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return ResolvedOrder{}, fmt.Errorf("resolve() failed: %w", err)
+	}
+	ro, err := decodeResolvedOrder(resp)
+	if err != nil {
+		return ResolvedOrder{}, fmt.Errorf("failed to decode resolve() result: %w", err)
+	}
+	return ro, nil
+}
+
+// ViewIntent returns what o is meant to resolve to when resolved through a view, with a zero sender
+func ViewIntent(o *OrderData) (Intent, error) {
+	viewed := *o
+	viewed.Sender = new(felt.Felt)
+	encoded, err := EncodeOrderData(&viewed)
+	if err != nil {
+		return Intent{}, err
+	}
+	return Intent{
+		OrderID:            ComputeOrderID(encoded),
+		InputToken:         feltWord(o.InputToken),
+		AmountIn:           o.AmountIn,
+		OriginDomain:       o.OriginDomain,
+		OutputToken:        feltWord(o.OutputToken),
+		AmountOut:          o.AmountOut,
+		DestinationDomain:  o.DestinationDomain,
+		DestinationSettler: feltWord(o.DestinationSettler),
+	}, nil
+}
+
+// Resolution reduces a Cairo resolved order to the values CheckResolution compares
+func (ro ResolvedOrder) Resolution() Resolution {
+	r := Resolution{OrderID: ro.OrderID}
+	for _, out := range ro.MaxSpent {
+		r.MaxSpent = append(r.MaxSpent, ResolvedOutput{Token: feltWord(out.Token), Amount: out.Amount, ChainID: uint64(out.ChainID)})
+	}
+	for _, out := range ro.MinReceived {
+		r.MinReceived = append(r.MinReceived, ResolvedOutput{Token: feltWord(out.Token), Amount: out.Amount, ChainID: uint64(out.ChainID)})
+	}
+	for _, fill := range ro.FillInstructions {
+		r.FillInstructions = append(r.FillInstructions, ResolvedFill{
+			DestinationChainID: uint64(fill.DestinationChainID),
+			DestinationSettler: feltWord(fill.DestinationSettler),
+		})
+	}
+	return r
+}
+
+// feltWord returns a felt as a 32-byte word (nil as zero)
+func feltWord(f *felt.Felt) common.Hash {
+	if f == nil {
+		return common.Hash{}
+	}
+	return common.Hash(f.Bytes())
+}
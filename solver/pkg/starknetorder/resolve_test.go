@@ -0,0 +1,124 @@
+package starknetorder
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolvingCaller answers resolve with a fixed serialized ResolvedCrossChainOrder
+type resolvingCaller struct {
+	resolved []*felt.Felt
+	call     rpc.FunctionCall
+}
+
+func (c *resolvingCaller) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	c.call = call
+	return c.resolved, nil
+}
+
+// fixtureResolved is the resolved order of the captured Open event, serialized as resolve() returns it
+func fixtureResolved(t *testing.T) []*felt.Felt {
+	t.Helper()
+	receipt := loadReceiptFixture(t)
+	for _, event := range receipt.Events {
+		if len(event.Keys) > 0 && event.Keys[0].Equal(OpenEventSelector) {
+			return event.Data
+		}
+	}
+	t.Fatal("fixture has no Open event")
+	return nil
+}
+
+// fixtureIntent is what the fixture order was meant to resolve to, sender included
+func fixtureIntent(t *testing.T, o *OrderData) Intent {
+	t.Helper()
+	intent, err := ViewIntent(o)
+	require.NoError(t, err)
+	intent.OrderID = ComputeOrderID(mustEncode(t, o))
+	return intent
+}
+
+func TestResolve(t *testing.T) {
+	o := testOrderData(t)
+	caller := &resolvingCaller{resolved: fixtureResolved(t)}
+	hyperlane := mustFelt(t, "0x1234")
+
+	ro, err := Resolve(context.Background(), caller, hyperlane, big.NewInt(0x1234), &o)
+	require.NoError(t, err)
+	assert.Equal(t, utils.GetSelectorFromNameFelt("resolve"), caller.call.EntryPointSelector)
+	assert.True(t, hyperlane.Equal(caller.call.ContractAddress))
+	require.NoError(t, CheckResolution("Hyperlane7683 0x1234", fixtureIntent(t, &o), ro.Resolution()))
+
+	caller.resolved = caller.resolved[:len(caller.resolved)-1]
+	_, err = Resolve(context.Background(), caller, hyperlane, big.NewInt(0x1234), &o)
+	assert.ErrorContains(t, err, "failed to decode resolve() result")
+}
+
+func TestViewIntent(t *testing.T) {
+	o := testOrderData(t)
+	intent, err := ViewIntent(&o)
+	require.NoError(t, err)
+
+	viewed := o
+	viewed.Sender = new(felt.Felt)
+	assert.Equal(t, ComputeOrderID(mustEncode(t, &viewed)), intent.OrderID)
+	assert.NotEqual(t, ComputeOrderID(mustEncode(t, &o)), intent.OrderID)
+	assert.Equal(t, o.DestinationDomain, intent.DestinationDomain)
+	assert.True(t, o.Sender.Equal(testOrderData(t).Sender), "o is left untouched")
+}
+
+func TestCheckResolution(t *testing.T) {
+	o := testOrderData(t)
+	intent := fixtureIntent(t, &o)
+	resolved, err := decodeResolvedOrder(fixtureResolved(t))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		mutate func(r *Resolution)
+		fields []string
+	}{
+		{name: "matching", mutate: func(*Resolution) {}},
+		{
+			name:   "output amount",
+			mutate: func(r *Resolution) { r.MaxSpent[0].Amount = big.NewInt(1) },
+			fields: []string{"maxSpent[0].amount"},
+		},
+		{
+			name:   "destination domain",
+			mutate: func(r *Resolution) { r.FillInstructions[0].DestinationChainID = 1 },
+			fields: []string{"fillInstructions[0].destinationChainId"},
+		},
+		{
+			name:   "missing fill instruction",
+			mutate: func(r *Resolution) { r.FillInstructions = nil },
+			fields: []string{"fillInstructions"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolved.Resolution()
+			tt.mutate(&r)
+			err := CheckResolution("Hyperlane7683 0x1234", intent, r)
+			if len(tt.fields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var mismatch *ResolutionMismatchError
+			require.ErrorAs(t, err, &mismatch)
+			var fields []string
+			for _, d := range mismatch.Diffs {
+				fields = append(fields, d.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+			assert.ErrorContains(t, err, "  - intended "+tt.fields[0])
+		})
+	}
+}
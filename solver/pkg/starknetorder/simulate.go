@@ -0,0 +1,134 @@
+package starknetorder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OpenSimulator is the provider surface SimulateOpen needs (rpc.Provider satisfies it)
+type OpenSimulator interface {
+	Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+	SimulateTransactions(ctx context.Context, blockID rpc.BlockID, txns []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error)
+}
+
+// SimulationResult is the outcome of SimulateOpen
+type SimulationResult struct {
+	OrderID       common.Hash
+	EncodedOrder  []byte
+	OrderDataType *big.Int
+	FillDeadline  uint64
+	// Calldata is the open() calldata; InvokeCalldata is the account __execute__ calldata wrapping it
+	Calldata       []*felt.Felt
+	InvokeCalldata []*felt.Felt
+	// Event is the Open event the transaction would emit; nil when it reverts
+	Event *OpenEvent
+	// RevertReason is set when the simulated transaction reverts
+	RevertReason string
+	// L2Gas is the L2 gas the simulated transaction consumed
+	L2Gas uint64
+}
+
+// SimulateOpen runs open() from sender through starknet_simulateTransactions without signing or sending
+// anything: validation and fee charging are skipped, so no key is needed. A revert is not an error, it is
+// reported in RevertReason. Allowance is not checked or approved
+func SimulateOpen(ctx context.Context, sim OpenSimulator, sender *felt.Felt, params OrderParams) (SimulationResult, error) {
+	var result SimulationResult
+
+	if params.HyperlaneAddress == nil {
+		return result, fmt.Errorf("hyperlane address is required")
+	}
+	if params.Order.AmountIn == nil || params.Order.AmountOut == nil {
+		return result, fmt.Errorf("order amounts are required")
+	}
+	orderDataType, _, err := params.orderDataType()
+	if err != nil {
+		return result, err
+	}
+
+	order := params.Order
+	order.Sender = sender
+	openCall, err := BuildOpenCall(params.HyperlaneAddress, orderDataType, &order)
+	if err != nil {
+		return result, err
+	}
+
+	result.EncodedOrder, err = EncodeOrderData(&order)
+	if err != nil {
+		return result, err
+	}
+	result.OrderID = ComputeOrderID(result.EncodedOrder)
+	result.OrderDataType = orderDataType
+	result.FillDeadline = uint64(order.FillDeadline)
+	result.Calldata = openCall.CallData
+	result.InvokeCalldata = account.FmtCallDataCairo2(utils.InvokeFuncCallsToFunctionCalls([]rpc.InvokeFunctionCall{openCall}))
+
+	nonce, err := sim.Nonce(ctx, rpc.WithBlockTag("latest"), sender)
+	if err != nil {
+		return result, fmt.Errorf("failed to read nonce of %s: %w", sender.String(), err)
+	}
+
+	// Zero resource bounds are fine: SKIP_FEE_CHARGE leaves them unchecked
+	txn := utils.BuildInvokeTxn(sender, nonce, result.InvokeCalldata, &rpc.ResourceBoundsMapping{
+		L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}, &utils.TxnOptions{UseQueryBit: true})
+
+	simulated, err := sim.SimulateTransactions(ctx, rpc.WithBlockTag("latest"), []rpc.BroadcastTxn{txn},
+		[]rpc.SimulationFlag{rpc.SkipValidate, rpc.SkipFeeCharge})
+	if err != nil {
+		return result, fmt.Errorf("starknet_simulateTransactions failed: %w", err)
+	}
+	if len(simulated) != 1 {
+		return result, fmt.Errorf("starknet_simulateTransactions returned %d traces, expected 1", len(simulated))
+	}
+	trace, ok := simulated[0].TxnTrace.(rpc.InvokeTxnTrace)
+	if !ok {
+		return result, fmt.Errorf("unexpected simulation trace %T", simulated[0].TxnTrace)
+	}
+	result.L2Gas = uint64(trace.ExecutionResources.L2Gas)
+
+	if trace.ExecuteInvocation.RevertReason != "" || trace.ExecuteInvocation.FnInvocation == nil {
+		result.RevertReason = trace.ExecuteInvocation.RevertReason
+		return result, nil
+	}
+
+	event, err := findOpenEvent(trace.ExecuteInvocation.FnInvocation, params.HyperlaneAddress)
+	if err != nil {
+		return result, err
+	}
+	if event == nil {
+		return result, fmt.Errorf("simulated open() emitted no Open event from %s", params.HyperlaneAddress.String())
+	}
+	if event.OrderID != result.OrderID {
+		return result, fmt.Errorf("order ID mismatch: computed %s, simulation emitted %s", result.OrderID.Hex(), event.OrderID.Hex())
+	}
+	result.Event = event
+	return result, nil
+}
+
+// findOpenEvent walks an invocation tree for the Open event emitted by hyperlaneAddress
+func findOpenEvent(invocation *rpc.FnInvocation, hyperlaneAddress *felt.Felt) (*OpenEvent, error) {
+	if invocation.ContractAddress != nil && invocation.ContractAddress.Equal(hyperlaneAddress) {
+		for _, event := range invocation.InvocationEvents {
+			if event.EventContent == nil || len(event.Keys) == 0 || !event.Keys[0].Equal(OpenEventSelector) {
+				continue
+			}
+			return ParseOpenEvent(event.Keys, event.Data)
+		}
+	}
+	for i := range invocation.NestedCalls {
+		event, err := findOpenEvent(&invocation.NestedCalls[i], hyperlaneAddress)
+		if event != nil || err != nil {
+			return event, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,98 @@
+package starknetorder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSimulator answers starknet_simulateTransactions with a fixed execute invocation and records the request
+type fakeSimulator struct {
+	execute rpc.ExecInvocation
+
+	txns  []rpc.BroadcastTxn
+	flags []rpc.SimulationFlag
+}
+
+func (f *fakeSimulator) Nonce(context.Context, rpc.BlockID, *felt.Felt) (*felt.Felt, error) {
+	return new(felt.Felt).SetUint64(3), nil
+}
+
+func (f *fakeSimulator) SimulateTransactions(_ context.Context, _ rpc.BlockID, txns []rpc.BroadcastTxn, flags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
+	f.txns, f.flags = txns, flags
+	trace := rpc.InvokeTxnTrace{ExecuteInvocation: f.execute}
+	trace.ExecutionResources.L2Gas = 1_500_000
+	return []rpc.SimulatedTransaction{{TxnTrace: trace}}, nil
+}
+
+// accountInvocation nests a call on the hyperlane contract (emitting the fixture events) under the account
+func accountInvocation(t *testing.T, hyperlane *felt.Felt) *rpc.FnInvocation {
+	receipt := loadReceiptFixture(t)
+	var events []rpc.OrderedEvent
+	for i, event := range receipt.Events {
+		content := event.EventContent
+		events = append(events, rpc.OrderedEvent{Order: i, EventContent: &content})
+	}
+	return &rpc.FnInvocation{
+		FunctionCall: rpc.FunctionCall{ContractAddress: mustFelt(t, "0xacc")},
+		NestedCalls: []rpc.FnInvocation{{
+			FunctionCall:     rpc.FunctionCall{ContractAddress: hyperlane},
+			InvocationEvents: events,
+		}},
+	}
+}
+
+func TestSimulateOpen(t *testing.T) {
+	hyperlane := mustFelt(t, "0x1234")
+	order := testOrderData(t)
+	params := OrderParams{HyperlaneAddress: hyperlane, Order: order}
+
+	t.Run("emits_open_event", func(t *testing.T) {
+		sim := &fakeSimulator{execute: rpc.ExecInvocation{FnInvocation: accountInvocation(t, hyperlane)}}
+
+		result, err := SimulateOpen(context.Background(), sim, order.Sender, params)
+		require.NoError(t, err)
+
+		assert.Equal(t, []rpc.SimulationFlag{rpc.SkipValidate, rpc.SkipFeeCharge}, sim.flags)
+		require.Len(t, sim.txns, 1)
+		txn, ok := sim.txns[0].(*rpc.InvokeTxnV3)
+		require.True(t, ok, "got %T", sim.txns[0])
+		assert.True(t, txn.SenderAddress.Equal(order.Sender))
+		assert.Equal(t, result.InvokeCalldata, txn.Calldata)
+
+		assert.Equal(t, ComputeOrderID(mustEncode(t, &order)), result.OrderID)
+		assert.Equal(t, mustEncode(t, &order), result.EncodedOrder)
+		require.NotNil(t, result.Event)
+		assert.Equal(t, result.OrderID, result.Event.OrderID)
+		assert.Empty(t, result.RevertReason)
+		assert.Equal(t, uint64(1_500_000), result.L2Gas)
+	})
+
+	t.Run("revert_is_reported", func(t *testing.T) {
+		sim := &fakeSimulator{execute: rpc.ExecInvocation{RevertReason: "ERC20: insufficient allowance"}}
+
+		result, err := SimulateOpen(context.Background(), sim, order.Sender, params)
+		require.NoError(t, err)
+		assert.Equal(t, "ERC20: insufficient allowance", result.RevertReason)
+		assert.Nil(t, result.Event)
+		assert.Equal(t, ComputeOrderID(mustEncode(t, &order)), result.OrderID)
+	})
+
+	t.Run("event_from_other_contract", func(t *testing.T) {
+		sim := &fakeSimulator{execute: rpc.ExecInvocation{FnInvocation: accountInvocation(t, mustFelt(t, "0x9999"))}}
+
+		_, err := SimulateOpen(context.Background(), sim, order.Sender, params)
+		assert.ErrorContains(t, err, "no Open event")
+	})
+
+	t.Run("sender_changes_order_id", func(t *testing.T) {
+		sim := &fakeSimulator{execute: rpc.ExecInvocation{FnInvocation: accountInvocation(t, hyperlane)}}
+
+		_, err := SimulateOpen(context.Background(), sim, mustFelt(t, "0x5"), params)
+		assert.ErrorContains(t, err, "order ID mismatch")
+	})
+}
@@ -0,0 +1,417 @@
+package starknetorder
+
+// Module: Starknet order opening library
+// - Builds and ABI-encodes Hyperlane7683 OrderData from the Solidity struct schema, laid out like the Cairo OrderEncoder
+// - Wraps the encoding into Cairo Bytes calldata for open()
+// - Opens orders through a caller-supplied account and returns the result instead of exiting
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+const (
+	// DataOffset is the offset of the dynamic data field in the encoded OrderData (12 * 32 bytes)
+	DataOffset = 384
+
+	// DefaultOrderDataTypeHash is the ORDER_DATA_TYPE_HASH hardcoded in the Cairo and Solidity contracts
+	DefaultOrderDataTypeHash = "0x08d75650babf4de09c9273d48ef647876057ed91d4323f8a2e3ebc2cd8a63b5e"
+
+	// wordSize is the size of an ABI word in bytes
+	wordSize = 32
+
+	// receiptPollInterval is how often transaction receipts are polled
+	receiptPollInterval = time.Second
+)
+
+// OrderData mirrors the Cairo OrderData struct
+type OrderData struct {
+	Sender             *felt.Felt
+	Recipient          *felt.Felt
+	InputToken         *felt.Felt
+	OutputToken        *felt.Felt
+	AmountIn           *big.Int
+	AmountOut          *big.Int
+	SenderNonce        *felt.Felt
+	OriginDomain       uint32
+	DestinationDomain  uint32
+	DestinationSettler *felt.Felt
+	FillDeadline       orderdeadline.Deadline
+	Data               []byte
+}
+
+// OrderParams describes an order to open on a Hyperlane7683 contract
+type OrderParams struct {
+	// HyperlaneAddress is the Hyperlane7683 contract on the origin chain
+	HyperlaneAddress *felt.Felt
+	// OrderDataType overrides the order data type hash; nil uses ORDER_DATA_TYPE_HASH
+	OrderDataType *big.Int
+	// Order is the order payload. Sender is always replaced by the signing account,
+	// just like the contract does when it resolves the order
+	Order OrderData
+	// AutoApprove sends approve() for AmountIn when the allowance is short; otherwise OpenOrder fails before
+	// sending anything
+	AutoApprove bool
+	// Retry retries the reads, sends and receipt waits of OpenOrder on transient RPC failures; the zero value
+	// makes a single attempt. A repeated open() reuses the sender nonce, so the contract rejects a duplicate
+	Retry starknetutil.RetryPolicy
+}
+
+// OrderResult is the outcome of a successful OpenOrder call
+type OrderResult struct {
+	TransactionHash *felt.Felt
+	// ApprovalTxHash is set when an ERC20 approval had to be sent before opening
+	ApprovalTxHash *felt.Felt
+	OrderID        common.Hash
+	// EncodedOrder is the ABI-encoded OrderData the order ID is derived from
+	EncodedOrder []byte
+	// OrderDataType and FillDeadline complete the OnchainCrossChainOrder that was opened
+	OrderDataType *big.Int
+	FillDeadline  uint64
+	Calldata      []*felt.Felt
+	// Event is the Open event decoded from the transaction receipt
+	Event *OpenEvent
+	// GasUsed is the L2 gas consumed by the open transaction
+	GasUsed uint64
+}
+
+// OpenEventSelector is the selector of the Hyperlane7683 Open event
+var OpenEventSelector = utils.GetSelectorFromNameFelt("Open")
+
+// OrderDataTypeHash returns the order data type hash, honouring the ORDER_DATA_TYPE_HASH override
+func OrderDataTypeHash() (*big.Int, error) {
+	hashHex := envutil.GetEnvWithDefault("ORDER_DATA_TYPE_HASH", DefaultOrderDataTypeHash)
+	bi, ok := new(big.Int).SetString(hashHex, 0)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse ORDER_DATA_TYPE_HASH %q", hashHex)
+	}
+	return bi, nil
+}
+
+// orderDataArgs is abi.encode(OrderData) as a single dynamic tuple, the schema of the Solidity OrderData struct.
+// Packing through it yields the leading offset word and the head/tail layout the Cairo OrderEncoder mirrors
+var orderDataArgs = newOrderDataArgs()
+
+func newOrderDataArgs() abi.Arguments {
+	tuple, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "sender", Type: "bytes32"},
+		{Name: "recipient", Type: "bytes32"},
+		{Name: "inputToken", Type: "bytes32"},
+		{Name: "outputToken", Type: "bytes32"},
+		{Name: "amountIn", Type: "uint256"},
+		{Name: "amountOut", Type: "uint256"},
+		{Name: "senderNonce", Type: "uint256"},
+		{Name: "originDomain", Type: "uint32"},
+		{Name: "destinationDomain", Type: "uint32"},
+		{Name: "destinationSettler", Type: "bytes32"},
+		{Name: "fillDeadline", Type: "uint32"},
+		{Name: "data", Type: "bytes"},
+	})
+	if err != nil {
+		// The schema is a constant, so this only fails if it is edited into something invalid
+		panic(fmt.Sprintf("invalid OrderData ABI schema: %v", err))
+	}
+	return abi.Arguments{{Type: tuple}}
+}
+
+// abiOrderData is OrderData in the Go types orderDataArgs packs; fields match the tuple components by name
+type abiOrderData struct {
+	Sender             [32]byte
+	Recipient          [32]byte
+	InputToken         [32]byte
+	OutputToken        [32]byte
+	AmountIn           *big.Int
+	AmountOut          *big.Int
+	SenderNonce        *big.Int
+	OriginDomain       uint32
+	DestinationDomain  uint32
+	DestinationSettler [32]byte
+	FillDeadline       uint32
+	Data               []byte
+}
+
+// EncodeOrderData ABI-encodes an OrderData the same way the Cairo OrderEncoder::encode does. The fill deadline
+// is a uint32 in the encoding, so one that does not fit is an error rather than a different deadline
+func EncodeOrderData(o *OrderData) ([]byte, error) {
+	fillDeadline, err := o.FillDeadline.Uint32()
+	if err != nil {
+		return nil, fmt.Errorf("invalid fill deadline: %w", err)
+	}
+	amountIn, err := uint256Value("amount in", o.AmountIn)
+	if err != nil {
+		return nil, err
+	}
+	amountOut, err := uint256Value("amount out", o.AmountOut)
+	if err != nil {
+		return nil, err
+	}
+
+	senderNonce := starknetutil.FeltToBytes32(o.SenderNonce)
+	data := o.Data
+	if data == nil {
+		data = []byte{}
+	}
+	packed, err := orderDataArgs.Pack(abiOrderData{
+		Sender:             starknetutil.FeltToBytes32(o.Sender),
+		Recipient:          starknetutil.FeltToBytes32(o.Recipient),
+		InputToken:         starknetutil.FeltToBytes32(o.InputToken),
+		OutputToken:        starknetutil.FeltToBytes32(o.OutputToken),
+		AmountIn:           amountIn,
+		AmountOut:          amountOut,
+		SenderNonce:        new(big.Int).SetBytes(senderNonce[:]),
+		OriginDomain:       o.OriginDomain,
+		DestinationDomain:  o.DestinationDomain,
+		DestinationSettler: starknetutil.FeltToBytes32(o.DestinationSettler),
+		FillDeadline:       fillDeadline,
+		Data:               data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ABI-pack OrderData: %w", err)
+	}
+
+	// Solidity pads the data tail to a whole word; the Cairo encoder appends the data bytes as they are, and the
+	// order ID is the hash of the Cairo encoding
+	padding := (wordSize - len(data)%wordSize) % wordSize
+	return packed[:len(packed)-padding], nil
+}
+
+// ComputeOrderID derives the order ID from ABI-encoded OrderData, matching OrderEncoder::id
+// (keccak256 of the encoding, read as a big-endian u256)
+func ComputeOrderID(encodedOrder []byte) common.Hash {
+	return crypto.Keccak256Hash(encodedOrder)
+}
+
+// EncodeOrderDataCalldata encodes an OrderData as Cairo Bytes calldata (size, words_len, u128 words)
+func EncodeOrderDataCalldata(o *OrderData) ([]*felt.Felt, error) {
+	raw, err := EncodeOrderData(o)
+	if err != nil {
+		return nil, err
+	}
+	return starknetutil.ToCairoBytes(raw), nil
+}
+
+// BuildOpenCall builds the open(OnchainCrossChainOrder) invoke call for a Hyperlane7683 contract
+func BuildOpenCall(hyperlaneAddress *felt.Felt, orderDataType *big.Int, o *OrderData) (rpc.InvokeFunctionCall, error) {
+	orderData, err := EncodeOrderDataCalldata(o)
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, err
+	}
+	typeLow, typeHigh := starknetutil.ToU256(orderDataType)
+
+	// open(fill_deadline: u64, order_data_type: u256, order_data: Bytes); the u64 carries the same checked deadline
+	calldata := []*felt.Felt{utils.Uint64ToFelt(uint64(o.FillDeadline)), typeLow, typeHigh}
+	calldata = append(calldata, orderData...)
+
+	return rpc.InvokeFunctionCall{
+		ContractAddress: hyperlaneAddress,
+		FunctionName:    "open",
+		CallData:        calldata,
+	}, nil
+}
+
+// QuoteGasPayment calls quote_gas_payment(destination_domain) on a Hyperlane7683 contract. The quote is the
+// Hyperlane gas of a message to that domain; open() takes no fee, so nothing is attached to the open invoke
+func QuoteGasPayment(ctx context.Context, caller starknetutil.ContractCaller, hyperlaneAddress *felt.Felt, destinationDomain uint32) (*big.Int, error) {
+	resp, err := caller.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    hyperlaneAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("quote_gas_payment"),
+		Calldata:           []*felt.Felt{utils.Uint64ToFelt(uint64(destinationDomain))},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("quote_gas_payment(%d) failed: %w", destinationDomain, err)
+	}
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("quote_gas_payment returned %d felts, expected 2", len(resp))
+	}
+	return starknetutil.FromU256(resp[0], resp[1]), nil
+}
+
+// OpenOrder checks balance and allowance, approves the Hyperlane7683 contract if needed (and allowed by
+// AutoApprove) and opens the order. An approve still in the pre_confirmed block counts
+func OpenOrder(ctx context.Context, client *rpc.Provider, acct *account.Account, params OrderParams) (OrderResult, error) {
+	var result OrderResult
+
+	if params.HyperlaneAddress == nil {
+		return result, fmt.Errorf("hyperlane address is required")
+	}
+	if params.Order.AmountIn == nil || params.Order.AmountOut == nil {
+		return result, fmt.Errorf("order amounts are required")
+	}
+
+	orderDataType, typeSource, err := params.orderDataType()
+	if err != nil {
+		return result, err
+	}
+
+	// The contract replaces the sender with the caller before hashing the order
+	order := params.Order
+	order.Sender = acct.Address
+	encoded, err := EncodeOrderData(&order)
+	if err != nil {
+		return result, err
+	}
+
+	// Fail before approving anything if the contract would reject the order with InvalidOrderType
+	sent := OrderDataTypeCandidate{Source: typeSource, Hash: orderDataType}
+	probe := StarknetOrderDataTypeProbe(client, params.HyperlaneAddress, &order)
+	if err := CheckOrderDataType(ctx, "Hyperlane7683 "+params.HyperlaneAddress.String(), sent, KnownOrderDataTypes(), probe); err != nil {
+		return result, err
+	}
+
+	owner := acct.Address.String()
+	token := order.InputToken.String()
+	spender := params.HyperlaneAddress.String()
+
+	caller := starknetutil.RetryingCaller(client, params.Retry)
+	balance, allowance, err := readInputFunds(ctx, caller, params.logger(), token, owner, spender, order.AmountIn)
+	if err != nil {
+		return result, err
+	}
+	if balance.Cmp(order.AmountIn) < 0 {
+		return result, fmt.Errorf("insufficient balance of %s: need %s, have %s (short %s)",
+			token, order.AmountIn.String(), balance.String(), new(big.Int).Sub(order.AmountIn, balance).String())
+	}
+	if allowance.Cmp(order.AmountIn) < 0 && !params.AutoApprove {
+		return result, fmt.Errorf("insufficient allowance of %s for %s: need %s, have %s (short %s)",
+			token, spender, order.AmountIn.String(), allowance.String(), new(big.Int).Sub(order.AmountIn, allowance).String())
+	}
+	if allowance.Cmp(order.AmountIn) < 0 {
+		approveCall, err := starknetutil.ERC20Approve(token, spender, order.AmountIn)
+		if err != nil {
+			return result, fmt.Errorf("failed to build approve call: %w", err)
+		}
+		approveTx, err := starknetutil.BuildAndSendInvokeTxnWithRetry(ctx, params.Retry, acct, []rpc.InvokeFunctionCall{*approveCall}, nil)
+		if err != nil {
+			return result, fmt.Errorf("failed to send approve transaction: %w", err)
+		}
+		if _, err := waitForAcceptedReceipt(ctx, params.Retry, acct.Provider, "approve", approveTx.Hash); err != nil {
+			return result, err
+		}
+		result.ApprovalTxHash = approveTx.Hash
+	}
+
+	openCall, err := BuildOpenCall(params.HyperlaneAddress, orderDataType, &order)
+	if err != nil {
+		return result, err
+	}
+	tx, err := starknetutil.BuildAndSendInvokeTxnWithRetry(ctx, params.Retry, acct, []rpc.InvokeFunctionCall{openCall}, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to send open transaction: %w", err)
+	}
+	result.TransactionHash = tx.Hash
+	result.EncodedOrder = encoded
+	result.OrderDataType = orderDataType
+	result.FillDeadline = uint64(order.FillDeadline)
+	result.OrderID = ComputeOrderID(result.EncodedOrder)
+	result.Calldata = openCall.CallData
+
+	// A reverted open is reported with the receipt's revert reason instead of a missing Open event
+	receipt, err := waitForAcceptedReceipt(ctx, params.Retry, acct.Provider, "open", tx.Hash)
+	if receipt != nil {
+		result.GasUsed = uint64(receipt.ExecutionResources.L2Gas)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	event, err := ParseOpenEventFromReceipt(&receipt.TransactionReceipt, params.HyperlaneAddress)
+	if err != nil {
+		return result, err
+	}
+	result.Event = event
+
+	// The emitted ID must match ours, otherwise the encoding has drifted from the contract
+	if event.OrderID != result.OrderID {
+		return result, fmt.Errorf("order ID mismatch: computed %s, contract emitted %s", result.OrderID.Hex(), event.OrderID.Hex())
+	}
+
+	return result, nil
+}
+
+// readInputFunds reads owner's balance of the input token and its allowance for spender, each from the pending
+// state when only that covers need, where an approve sent just before may already be. It logs the state each
+// value was read at
+func readInputFunds(ctx context.Context, caller starknetutil.ContractCaller, log logrus.FieldLogger, token, owner, spender string, need *big.Int) (balance, allowance *big.Int, err error) {
+	funds, err := starknetutil.ReadFunds(ctx, starknetutil.ERC20BalanceReader(caller, token, owner), need)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read input token balance: %w", err)
+	}
+	logFunds(log, "balance", funds)
+	balance = funds.Value
+
+	funds, err = starknetutil.ReadFunds(ctx, starknetutil.ERC20AllowanceReader(caller, token, owner, spender), need)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read input token allowance: %w", err)
+	}
+	logFunds(log, "allowance", funds)
+	return balance, funds.Value, nil
+}
+
+// logFunds logs the state an input token balance or allowance was read at; a pending read is worth an info line,
+// since the order then relies on a transaction that is not in a block yet
+func logFunds(log logrus.FieldLogger, what string, funds starknetutil.Funds) {
+	if funds.PendingErr != nil {
+		log.Warnf("Could not read the pending input token %s, using the latest block: %v", what, funds.PendingErr)
+	}
+	entry := log.WithField("state", funds.State)
+	if funds.State == starknetutil.FundsPending {
+		entry.Infof("Input token %s %s covers the order at the pending state (%s at the latest block)", what, funds.Value, funds.Latest)
+		return
+	}
+	entry.Debugf("Input token %s %s read at the latest block", what, funds.Value)
+}
+
+// logger is where OpenOrder logs, the retry policy's logger
+func (p OrderParams) logger() logrus.FieldLogger {
+	if p.Retry.Logger == nil {
+		return logrus.StandardLogger()
+	}
+	return p.Retry.Logger
+}
+
+// orderDataType returns the type hash the order is opened with and where it came from
+func (p OrderParams) orderDataType() (*big.Int, string, error) {
+	if p.OrderDataType != nil {
+		return p.OrderDataType, "OrderParams.OrderDataType", nil
+	}
+	hash, err := OrderDataTypeHash()
+	if err != nil {
+		return nil, "", err
+	}
+	return hash, KnownOrderDataTypes()[0].Source, nil
+}
+
+// uint256Value checks n fits a uint256 (nil encodes as zero)
+func uint256Value(name string, n *big.Int) (*big.Int, error) {
+	if n == nil {
+		return new(big.Int), nil
+	}
+	if n.Sign() < 0 || n.BitLen() > 8*wordSize {
+		return nil, fmt.Errorf("%s %s does not fit in uint256", name, n.String())
+	}
+	return n, nil
+}
+
+// bigWord returns a big.Int as a 32-byte big-endian word (nil encodes as zero)
+func bigWord(n *big.Int) []byte {
+	word := make([]byte, wordSize)
+	if n != nil {
+		n.FillBytes(word)
+	}
+	return word
+}
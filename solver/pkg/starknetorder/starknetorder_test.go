@@ -0,0 +1,515 @@
+package starknetorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderdeadline"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+func mustFelt(t *testing.T, hex string) *felt.Felt {
+	t.Helper()
+	f, err := utils.HexToFelt(hex)
+	require.NoError(t, err)
+	return f
+}
+
+func testOrderData(t *testing.T) OrderData {
+	return OrderData{
+		Sender:             mustFelt(t, "0x13d9ee239f33fea4f8785b9e3870ade909e20a9599ae7cd62c1c292b73af1b7"),
+		Recipient:          mustFelt(t, "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"),
+		InputToken:         mustFelt(t, "0x4c8d1a8ff5dc2e6b5b1c1d8a3e7d6e2b0f0a1b2c3d4e5f60718293a4b5c6d7e"),
+		OutputToken:        mustFelt(t, "0x5fbdb2315678afecb367f032d93f642f64180aa3"),
+		AmountIn:           big.NewInt(1000),
+		AmountOut:          big.NewInt(999),
+		SenderNonce:        utils.Uint64ToFelt(42),
+		OriginDomain:       23448591,
+		DestinationDomain:  11155111,
+		DestinationSettler: mustFelt(t, "0xf614c6bf94b022e16bef7dbecf7614ffd2b201d3"),
+		FillDeadline:       1700000000,
+		Data:               []byte{},
+	}
+}
+
+// mustEncode encodes o, failing the test on error
+func mustEncode(t *testing.T, o *OrderData) []byte {
+	t.Helper()
+	raw, err := EncodeOrderData(o)
+	require.NoError(t, err)
+	return raw
+}
+
+// word returns the i-th 32-byte word of an encoding
+func word(raw []byte, i int) []byte {
+	return raw[i*wordSize : (i+1)*wordSize]
+}
+
+func TestEncodeOrderDataLayout(t *testing.T) {
+	o := testOrderData(t)
+	raw := mustEncode(t, &o)
+
+	// 13 head words (including the leading offset) + 1 length word, no data
+	require.Len(t, raw, 14*wordSize)
+
+	assert.Equal(t, uint64(32), new(big.Int).SetBytes(word(raw, 0)).Uint64(), "leading offset")
+	senderBytes := o.Sender.Bytes()
+	assert.Equal(t, senderBytes[:], word(raw, 1), "sender")
+	assert.Equal(t, int64(1000), new(big.Int).SetBytes(word(raw, 5)).Int64(), "amount_in")
+	assert.Equal(t, int64(999), new(big.Int).SetBytes(word(raw, 6)).Int64(), "amount_out")
+	assert.Equal(t, int64(42), new(big.Int).SetBytes(word(raw, 7)).Int64(), "sender_nonce")
+	assert.Equal(t, int64(23448591), new(big.Int).SetBytes(word(raw, 8)).Int64(), "origin_domain")
+	assert.Equal(t, int64(11155111), new(big.Int).SetBytes(word(raw, 9)).Int64(), "destination_domain")
+	assert.Equal(t, int64(1700000000), new(big.Int).SetBytes(word(raw, 11)).Int64(), "fill_deadline")
+	assert.Equal(t, int64(DataOffset), new(big.Int).SetBytes(word(raw, 12)).Int64(), "data offset")
+	assert.Equal(t, int64(0), new(big.Int).SetBytes(word(raw, 13)).Int64(), "data length")
+
+	// EVM addresses are left padded into bytes32
+	assert.Equal(t, make([]byte, 12), word(raw, 2)[:12], "recipient padding")
+}
+
+func TestEncodeOrderDataWithData(t *testing.T) {
+	o := testOrderData(t)
+	o.Data = []byte{0xde, 0xad, 0xbe, 0xef}
+	raw := mustEncode(t, &o)
+
+	require.Len(t, raw, 14*wordSize+4)
+	assert.Equal(t, int64(4), new(big.Int).SetBytes(word(raw, 13)).Int64())
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, raw[14*wordSize:])
+}
+
+func TestEncodeOrderDataCalldata(t *testing.T) {
+	o := testOrderData(t)
+	raw := mustEncode(t, &o)
+	calldata, err := EncodeOrderDataCalldata(&o)
+	require.NoError(t, err)
+
+	// size, words_len, then one felt per 16-byte word
+	require.Len(t, calldata, 2+len(raw)/16)
+	assert.Equal(t, uint64(len(raw)), calldata[0].Uint64())
+	assert.Equal(t, uint64(len(raw)/16), calldata[1].Uint64())
+
+	for i := 0; i < len(raw)/16; i++ {
+		expected := utils.BigIntToFelt(new(big.Int).SetBytes(raw[i*16 : (i+1)*16]))
+		assert.True(t, expected.Equal(calldata[2+i]), "word %d", i)
+	}
+}
+
+func TestToCairoBytesPartialWord(t *testing.T) {
+	out := starknetutil.ToCairoBytes([]byte{0x01, 0x02})
+	require.Len(t, out, 3)
+	assert.Equal(t, uint64(2), out[0].Uint64())
+	assert.Equal(t, uint64(1), out[1].Uint64())
+
+	// Partial words are right padded with zeros
+	expected := new(big.Int).Lsh(big.NewInt(0x0102), 14*8)
+	assert.Equal(t, 0, utils.FeltToBigInt(out[2]).Cmp(expected))
+}
+
+func TestOrderDataTypeHash(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("ORDER_DATA_TYPE_HASH", "")
+		hash, err := OrderDataTypeHash()
+		require.NoError(t, err)
+		expected, _ := new(big.Int).SetString(DefaultOrderDataTypeHash, 0)
+		assert.Equal(t, 0, hash.Cmp(expected))
+	})
+
+	t.Run("override", func(t *testing.T) {
+		t.Setenv("ORDER_DATA_TYPE_HASH", "0x1234")
+		hash, err := OrderDataTypeHash()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0x1234), hash.Int64())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("ORDER_DATA_TYPE_HASH", "not-a-hash")
+		_, err := OrderDataTypeHash()
+		assert.Error(t, err)
+	})
+}
+
+func TestBuildOpenCall(t *testing.T) {
+	o := testOrderData(t)
+	hyperlane := mustFelt(t, "0x1234")
+	typeHash, _ := new(big.Int).SetString(DefaultOrderDataTypeHash, 0)
+
+	call, err := BuildOpenCall(hyperlane, typeHash, &o)
+	require.NoError(t, err)
+	assert.Equal(t, "open", call.FunctionName)
+	assert.True(t, hyperlane.Equal(call.ContractAddress))
+
+	low, high := starknetutil.ToU256(typeHash)
+	require.Greater(t, len(call.CallData), 3)
+	assert.Equal(t, uint64(o.FillDeadline), call.CallData[0].Uint64())
+	assert.True(t, low.Equal(call.CallData[1]))
+	assert.True(t, high.Equal(call.CallData[2]))
+	orderData, err := EncodeOrderDataCalldata(&o)
+	require.NoError(t, err)
+	assert.Equal(t, orderData, call.CallData[3:])
+}
+
+func TestEncodeOrderDataFillDeadlineBounds(t *testing.T) {
+	hyperlane := mustFelt(t, "0x1234")
+	typeHash, _ := new(big.Int).SetString(DefaultOrderDataTypeHash, 0)
+
+	t.Run("max", func(t *testing.T) {
+		o := testOrderData(t)
+		o.FillDeadline = orderdeadline.Max
+		raw := mustEncode(t, &o)
+		assert.Equal(t, abiEncodeOrderData(t, &o), raw)
+		assert.Equal(t, uint64(math.MaxUint32), new(big.Int).SetBytes(word(raw, 11)).Uint64())
+
+		call, err := BuildOpenCall(hyperlane, typeHash, &o)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(math.MaxUint32), call.CallData[0].Uint64())
+	})
+
+	for name, deadline := range map[string]orderdeadline.Deadline{
+		"max_plus_one": orderdeadline.Max + 1,
+		"unix_millis":  1_700_000_000_000,
+	} {
+		t.Run(name, func(t *testing.T) {
+			o := testOrderData(t)
+			o.FillDeadline = deadline
+
+			_, err := EncodeOrderData(&o)
+			var overflow *orderdeadline.OverflowError
+			require.ErrorAs(t, err, &overflow)
+			assert.Equal(t, deadline, overflow.Deadline)
+
+			_, err = EncodeOrderDataCalldata(&o)
+			assert.ErrorAs(t, err, &overflow)
+			_, err = BuildOpenCall(hyperlane, typeHash, &o)
+			assert.ErrorAs(t, err, &overflow)
+		})
+	}
+}
+
+// abiEncodeOrderData encodes the Solidity OrderData with go-ethereum as an independent reference
+func abiEncodeOrderData(t *testing.T, o *OrderData) []byte {
+	t.Helper()
+	orderDataType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "sender", Type: "bytes32"},
+		{Name: "recipient", Type: "bytes32"},
+		{Name: "inputToken", Type: "bytes32"},
+		{Name: "outputToken", Type: "bytes32"},
+		{Name: "amountIn", Type: "uint256"},
+		{Name: "amountOut", Type: "uint256"},
+		{Name: "senderNonce", Type: "uint256"},
+		{Name: "originDomain", Type: "uint32"},
+		{Name: "destinationDomain", Type: "uint32"},
+		{Name: "destinationSettler", Type: "bytes32"},
+		{Name: "fillDeadline", Type: "uint32"},
+		{Name: "data", Type: "bytes"},
+	})
+	require.NoError(t, err)
+
+	type solOrderData struct {
+		Sender             [32]byte
+		Recipient          [32]byte
+		InputToken         [32]byte
+		OutputToken        [32]byte
+		AmountIn           *big.Int
+		AmountOut          *big.Int
+		SenderNonce        *big.Int
+		OriginDomain       uint32
+		DestinationDomain  uint32
+		DestinationSettler [32]byte
+		FillDeadline       uint32
+		Data               []byte
+	}
+
+	packed, err := abi.Arguments{{Type: orderDataType}}.Pack(solOrderData{
+		Sender:             o.Sender.Bytes(),
+		Recipient:          o.Recipient.Bytes(),
+		InputToken:         o.InputToken.Bytes(),
+		OutputToken:        o.OutputToken.Bytes(),
+		AmountIn:           o.AmountIn,
+		AmountOut:          o.AmountOut,
+		SenderNonce:        utils.FeltToBigInt(o.SenderNonce),
+		OriginDomain:       o.OriginDomain,
+		DestinationDomain:  o.DestinationDomain,
+		DestinationSettler: o.DestinationSettler.Bytes(),
+		FillDeadline:       uint32(o.FillDeadline),
+		Data:               o.Data,
+	})
+	require.NoError(t, err)
+	return packed
+}
+
+func TestComputeOrderID(t *testing.T) {
+	o := testOrderData(t)
+	encoded := mustEncode(t, &o)
+
+	t.Run("matches_abi_encode", func(t *testing.T) {
+		// With empty data the Cairo encoding is byte-identical to Solidity's abi.encode
+		assert.Equal(t, abiEncodeOrderData(t, &o), encoded)
+		assert.Equal(t, crypto.Keccak256Hash(abiEncodeOrderData(t, &o)), ComputeOrderID(encoded))
+	})
+
+	t.Run("fixed_vector", func(t *testing.T) {
+		expected := common.HexToHash("0x462e3b3af743e3af16aba6ca8e8c9bec16ad54a8cc63a094623ba4a6d5393816")
+		assert.Equal(t, expected, ComputeOrderID(encoded))
+	})
+
+	t.Run("changes_with_nonce", func(t *testing.T) {
+		other := o
+		other.SenderNonce = utils.Uint64ToFelt(43)
+		assert.NotEqual(t, ComputeOrderID(encoded), ComputeOrderID(mustEncode(t, &other)))
+	})
+}
+
+// manualEncodeOrderData is the hand-built Cairo OrderEncoder layout EncodeOrderData used before it packed the
+// Solidity schema, kept as a reference for the fuzz test
+func manualEncodeOrderData(o *OrderData) []byte {
+	uintWord := func(v uint64) []byte { return bigWord(new(big.Int).SetUint64(v)) }
+	feltBytes := func(f *felt.Felt) []byte {
+		word := starknetutil.FeltToBytes32(f)
+		return word[:]
+	}
+
+	raw := make([]byte, 0, DataOffset+2*wordSize+len(o.Data))
+	raw = append(raw, uintWord(wordSize)...)
+	raw = append(raw, feltBytes(o.Sender)...)
+	raw = append(raw, feltBytes(o.Recipient)...)
+	raw = append(raw, feltBytes(o.InputToken)...)
+	raw = append(raw, feltBytes(o.OutputToken)...)
+	raw = append(raw, bigWord(o.AmountIn)...)
+	raw = append(raw, bigWord(o.AmountOut)...)
+	raw = append(raw, feltBytes(o.SenderNonce)...)
+	raw = append(raw, uintWord(uint64(o.OriginDomain))...)
+	raw = append(raw, uintWord(uint64(o.DestinationDomain))...)
+	raw = append(raw, feltBytes(o.DestinationSettler)...)
+	raw = append(raw, uintWord(uint64(o.FillDeadline))...)
+	raw = append(raw, uintWord(DataOffset)...)
+	raw = append(raw, uintWord(uint64(len(o.Data)))...)
+	return append(raw, o.Data...)
+}
+
+// fuzzFelt reduces arbitrary bytes to a felt
+func fuzzFelt(b []byte) *felt.Felt {
+	return new(felt.Felt).SetBytes(b)
+}
+
+// fuzzUint256 reads at most 32 bytes as a uint256
+func fuzzUint256(b []byte) *big.Int {
+	if len(b) > wordSize {
+		b = b[:wordSize]
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+func FuzzEncodeOrderDataMatchesManualLayout(f *testing.F) {
+	f.Add([]byte{0x01}, []byte{0x70, 0x99}, []byte{0x4c}, []byte{0x5f}, []byte{0x03, 0xe8}, []byte{0x03, 0xe7}, uint64(42), uint32(23448591), uint32(11155111), []byte{0xf6}, uint32(1700000000), []byte{})
+	f.Add(bytes.Repeat([]byte{0xff}, 32), bytes.Repeat([]byte{0xff}, 32), []byte{}, []byte{}, bytes.Repeat([]byte{0xff}, 32), []byte{}, uint64(math.MaxUint64), uint32(math.MaxUint32), uint32(0), []byte{}, uint32(math.MaxUint32), bytes.Repeat([]byte{0xab}, 65))
+
+	f.Fuzz(func(t *testing.T, sender, recipient, inputToken, outputToken, amountIn, amountOut []byte, nonce uint64, originDomain, destinationDomain uint32, settler []byte, fillDeadline uint32, data []byte) {
+		o := OrderData{
+			Sender:             fuzzFelt(sender),
+			Recipient:          fuzzFelt(recipient),
+			InputToken:         fuzzFelt(inputToken),
+			OutputToken:        fuzzFelt(outputToken),
+			AmountIn:           fuzzUint256(amountIn),
+			AmountOut:          fuzzUint256(amountOut),
+			SenderNonce:        utils.Uint64ToFelt(nonce),
+			OriginDomain:       originDomain,
+			DestinationDomain:  destinationDomain,
+			DestinationSettler: fuzzFelt(settler),
+			FillDeadline:       orderdeadline.Deadline(fillDeadline),
+			Data:               data,
+		}
+
+		encoded, err := EncodeOrderData(&o)
+		require.NoError(t, err)
+		assert.Equal(t, manualEncodeOrderData(&o), encoded)
+		if len(data) == 0 {
+			// Without a data tail there is no padding, so the encoding is Solidity's abi.encode byte for byte
+			assert.Equal(t, abiEncodeOrderData(t, &o), encoded)
+		}
+	})
+}
+
+// orderDataVector is a golden OrderData encoding in testdata/order_data_vectors.json
+type orderDataVector struct {
+	Name               string `json:"name"`
+	Sender             string `json:"sender"`
+	Recipient          string `json:"recipient"`
+	InputToken         string `json:"inputToken"`
+	OutputToken        string `json:"outputToken"`
+	AmountIn           string `json:"amountIn"`
+	AmountOut          string `json:"amountOut"`
+	SenderNonce        string `json:"senderNonce"`
+	OriginDomain       uint32 `json:"originDomain"`
+	DestinationDomain  uint32 `json:"destinationDomain"`
+	DestinationSettler string `json:"destinationSettler"`
+	FillDeadline       uint64 `json:"fillDeadline"`
+	Data               string `json:"data"`
+	Encoded            string `json:"encoded"`
+	OrderID            string `json:"orderId"`
+}
+
+func (v orderDataVector) orderData(t *testing.T) OrderData {
+	amountIn, ok := new(big.Int).SetString(v.AmountIn, 10)
+	require.True(t, ok, "amountIn %q", v.AmountIn)
+	amountOut, ok := new(big.Int).SetString(v.AmountOut, 10)
+	require.True(t, ok, "amountOut %q", v.AmountOut)
+	return OrderData{
+		Sender:             mustFelt(t, v.Sender),
+		Recipient:          mustFelt(t, v.Recipient),
+		InputToken:         mustFelt(t, v.InputToken),
+		OutputToken:        mustFelt(t, v.OutputToken),
+		AmountIn:           amountIn,
+		AmountOut:          amountOut,
+		SenderNonce:        mustFelt(t, v.SenderNonce),
+		OriginDomain:       v.OriginDomain,
+		DestinationDomain:  v.DestinationDomain,
+		DestinationSettler: mustFelt(t, v.DestinationSettler),
+		FillDeadline:       orderdeadline.Deadline(v.FillDeadline),
+		Data:               common.FromHex(v.Data),
+	}
+}
+
+func TestEncodeOrderDataGoldenVectors(t *testing.T) {
+	raw, err := os.ReadFile("testdata/order_data_vectors.json")
+	require.NoError(t, err)
+	var vectors []orderDataVector
+	require.NoError(t, json.Unmarshal(raw, &vectors))
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			o := v.orderData(t)
+			encoded := mustEncode(t, &o)
+			assert.Equal(t, v.Encoded, hexutil.Encode(encoded))
+			assert.Equal(t, common.HexToHash(v.OrderID), ComputeOrderID(encoded))
+		})
+	}
+}
+
+func TestEncodeOrderDataAmountOutOfRange(t *testing.T) {
+	o := testOrderData(t)
+	o.AmountIn = new(big.Int).Lsh(big.NewInt(1), 256)
+	_, err := EncodeOrderData(&o)
+	assert.EqualError(t, err, "amount in 115792089237316195423570985008687907853269984665640564039457584007913129639936 does not fit in uint256")
+
+	o = testOrderData(t)
+	o.AmountOut = big.NewInt(-1)
+	_, err = EncodeOrderData(&o)
+	assert.EqualError(t, err, "amount out -1 does not fit in uint256")
+}
+
+func TestOpenEventSelector(t *testing.T) {
+	// Must match the selector the solver's Starknet listener filters on
+	expected := mustFelt(t, "0x35D8BA7F4BF26B6E2E2060E5BD28107042BE35460FBD828C9D29A2D8AF14445")
+	assert.True(t, expected.Equal(OpenEventSelector))
+}
+
+// quoteCaller answers quote_gas_payment with a fixed u256
+type quoteCaller struct {
+	quote *big.Int
+	call  rpc.FunctionCall
+}
+
+func (q *quoteCaller) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	q.call = call
+	low, high := starknetutil.ToU256(q.quote)
+	return []*felt.Felt{low, high}, nil
+}
+
+func TestQuoteGasPayment(t *testing.T) {
+	hyperlane := utils.Uint64ToFelt(0x7683)
+	quote := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(42))
+	caller := &quoteCaller{quote: quote}
+
+	got, err := QuoteGasPayment(context.Background(), caller, hyperlane, 11155111)
+	require.NoError(t, err)
+	assert.Equal(t, quote, got)
+	assert.Equal(t, utils.GetSelectorFromNameFelt("quote_gas_payment"), caller.call.EntryPointSelector)
+	assert.Equal(t, []*felt.Felt{utils.Uint64ToFelt(11155111)}, caller.call.Calldata)
+}
+
+// fundsCaller answers balanceOf and allowance per block tag; a tag it has no value for is rejected like an
+// unknown block id
+type fundsCaller struct {
+	balances, allowances map[rpc.BlockTag]*big.Int
+}
+
+func (c *fundsCaller) Call(_ context.Context, call rpc.FunctionCall, block rpc.BlockID) ([]*felt.Felt, error) {
+	values := c.balances
+	if call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt("allowance")) {
+		values = c.allowances
+	}
+	value, ok := values[block.Tag]
+	if !ok {
+		return nil, errors.New("Invalid block id")
+	}
+	low, high := starknetutil.ToU256(value)
+	return []*felt.Felt{low, high}, nil
+}
+
+func TestReadInputFunds(t *testing.T) {
+	need := big.NewInt(1000)
+	latest, pending := rpc.BlockTagLatest, starknetutil.PendingBlock.Tag
+	token, owner, spender := "0xd06", "0xa11ce", "0x7683"
+
+	t.Run("pending approve covers the order", func(t *testing.T) {
+		log, hook := logtest.NewNullLogger()
+		log.SetLevel(logrus.DebugLevel)
+		caller := &fundsCaller{
+			balances:   map[rpc.BlockTag]*big.Int{latest: big.NewInt(5000)},
+			allowances: map[rpc.BlockTag]*big.Int{latest: big.NewInt(0), pending: need},
+		}
+
+		balance, allowance, err := readInputFunds(context.Background(), caller, log, token, owner, spender, need)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5000), balance)
+		assert.Equal(t, need, allowance, "the pending allowance is used when only it covers the order")
+
+		entries := hook.AllEntries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, starknetutil.FundsLatest, entries[0].Data["state"])
+		assert.Equal(t, starknetutil.FundsPending, entries[1].Data["state"])
+		assert.Equal(t, logrus.InfoLevel, entries[1].Level)
+		assert.Contains(t, entries[1].Message, "0 at the latest block")
+	})
+
+	t.Run("node without a pending block", func(t *testing.T) {
+		log, hook := logtest.NewNullLogger()
+		caller := &fundsCaller{
+			balances:   map[rpc.BlockTag]*big.Int{latest: big.NewInt(5000)},
+			allowances: map[rpc.BlockTag]*big.Int{latest: big.NewInt(10)},
+		}
+
+		_, allowance, err := readInputFunds(context.Background(), caller, log, token, owner, spender, need)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), allowance, "the latest allowance is kept")
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Contains(t, hook.LastEntry().Message, "Invalid block id")
+	})
+
+	t.Run("latest read fails", func(t *testing.T) {
+		log, _ := logtest.NewNullLogger()
+		_, _, err := readInputFunds(context.Background(), &fundsCaller{}, log, token, owner, spender, need)
+		assert.ErrorContains(t, err, "failed to read input token balance")
+	})
+}
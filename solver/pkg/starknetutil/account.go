@@ -0,0 +1,177 @@
+package starknetutil
+
+// Account factory
+// account.NewAccount needs the Cairo version of the account contract: a Cairo 0 account (the old devnet
+// predeployed accounts, Argent and Braavos proxies) signed as Cairo 2 fails with an invalid signature that looks
+// like a key problem. The version comes from <NETWORK>_<USER>_ACCOUNT_VERSION (LOCAL_ on devnet for Starknet) or
+// is detected from the class hash at the account address, defaulting to Cairo 2. Before signing, tools check the
+// account holds the configured public key: a mismatch otherwise surfaces as an opaque validation failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// knownAccountClasses maps the class hashes of common account contracts to their Cairo version
+var knownAccountClasses = map[string]account.CairoVersion{
+	// Cairo 0
+	"0x4d07e40e93398ed3c76981e72dd1fd22557a78ce36c0515f679e27f0bb5bc5f": account.CairoV0, // OpenZeppelin 0.5.0 (starknet-devnet predeployed)
+	"0x25ec026985a3bf9d0cc1fe17326b245dfdc3ff89b8fde106542a3ea56c5a918": account.CairoV0, // Argent X proxy
+	"0x33434ad846cdd5f23eb73ff09fe6fddd568284a0fb7d1be20ee482f044dabe2": account.CairoV0, // Argent X 0.2.3
+	"0x3131fa018d520a037686ce3efddeab8f28895662f019ca3ca18a626650f7d1e": account.CairoV0, // Braavos proxy
+	// Cairo 2
+	"0x61dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f": account.CairoV2, // OpenZeppelin 0.8.1 (starknet-devnet-rs predeployed)
+	"0x1a736d6ed154502257f02b1ccdf4d9d1089f80811cd6acad48e6b6a9d1f2003": account.CairoV2, // Argent X 0.3.0
+	"0x36078334509b514626504edc9fb252328d1a240e4e948bef8d0c08dff45927f": account.CairoV2, // Argent X 0.4.0
+}
+
+// ClassHashReader is the part of the Starknet RPC provider used to detect an account's Cairo version
+type ClassHashReader interface {
+	ClassHashAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+}
+
+// AccountVersionEnv returns the env var overriding the Cairo version of user's account on network, e.g.
+// STARKNET_ALICE_ACCOUNT_VERSION (LOCAL_STARKNET_ALICE_ACCOUNT_VERSION on devnet) or ZTARKNET_SOLVER_ACCOUNT_VERSION
+func AccountVersionEnv(network, user string) string {
+	key := strings.ToUpper(network) + "_" + strings.ToUpper(user) + "_ACCOUNT_VERSION"
+	if strings.EqualFold(network, "Starknet") {
+		return envutil.ConditionalKey(key)
+	}
+	return key
+}
+
+// ParseCairoVersion parses an account version setting: 0, v0 or cairo0 and 2, v2 or cairo2 (case-insensitive)
+func ParseCairoVersion(value string) (account.CairoVersion, error) {
+	switch strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "cairo"), "v") {
+	case "0":
+		return account.CairoV0, nil
+	case "1", "2":
+		return account.CairoV2, nil
+	default:
+		return 0, fmt.Errorf("invalid account version %q: expected 0 or 2", value)
+	}
+}
+
+// CairoVersionForClass returns the Cairo version of a known account class hash
+func CairoVersionForClass(classHash *felt.Felt) (account.CairoVersion, bool) {
+	version, ok := knownAccountClasses[classHash.String()]
+	return version, ok
+}
+
+// DetectCairoVersion returns the Cairo version of the account at address: the value of versionEnv when set,
+// otherwise the version of its class hash. Unknown classes are taken as Cairo 2
+func DetectCairoVersion(ctx context.Context, reader ClassHashReader, versionEnv string, address *felt.Felt) (account.CairoVersion, error) {
+	if value := os.Getenv(versionEnv); value != "" {
+		version, err := ParseCairoVersion(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", versionEnv, err)
+		}
+		return version, nil
+	}
+
+	classHash, err := reader.ClassHashAt(ctx, rpc.WithBlockTag("latest"), address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the class hash of account %s (set %s to skip detection): %w", address.String(), versionEnv, err)
+	}
+	if version, ok := CairoVersionForClass(classHash); ok {
+		return version, nil
+	}
+	return account.CairoV2, nil
+}
+
+// NewAccount creates the account at address with the Cairo version DetectCairoVersion finds for it
+func NewAccount(ctx context.Context, provider rpc.RPCProvider, versionEnv string, address *felt.Felt, publicKey string, ks account.Keystore) (*account.Account, error) {
+	version, err := DetectCairoVersion(ctx, provider, versionEnv, address)
+	if err != nil {
+		return nil, err
+	}
+	return account.NewAccount(provider, address, publicKey, ks, version)
+}
+
+// NewDeployerAccount creates the deploy tools' account on network: the deployer at address, signing with
+// publicKey's key in ks, with the Cairo version of its contract (see AccountVersionEnv for the override)
+func NewDeployerAccount(ctx context.Context, provider rpc.RPCProvider, network string, address *felt.Felt, publicKey string, ks account.Keystore) (*account.Account, error) {
+	acct, err := NewAccount(ctx, provider, AccountVersionEnv(network, "Deployer"), address, publicKey, ks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize account: %w", err)
+	}
+	return acct, nil
+}
+
+// publicKeyViews are the account views returning the signer's public key: get_public_key on OpenZeppelin Cairo 2
+// accounts, getPublicKey on Cairo 0 ones
+var publicKeyViews = []string{"get_public_key", "getPublicKey"}
+
+// ErrPublicKeyUnavailable is returned when an account exposes none of publicKeyViews, e.g. Argent and Braavos
+// accounts, whose signer cannot be checked this way
+var ErrPublicKeyUnavailable = errors.New("account exposes no public key view")
+
+// AccountKeyMismatchError reports an account contract that holds another public key than the one configured:
+// transactions signed with the configured key fail validation on the node
+type AccountKeyMismatchError struct {
+	Account    *felt.Felt
+	Configured *felt.Felt
+	OnChain    *felt.Felt
+}
+
+func (e *AccountKeyMismatchError) Error() string {
+	return fmt.Sprintf("account %s holds public key %s, but the configured public key is %s",
+		e.Account.String(), e.OnChain.String(), e.Configured.String())
+}
+
+// AccountPublicKey reads the public key the account at address holds, trying each of publicKeyViews
+func AccountPublicKey(ctx context.Context, caller ContractCaller, address *felt.Felt) (*felt.Felt, error) {
+	var lastErr error
+	for _, view := range publicKeyViews {
+		resp, err := caller.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    address,
+			EntryPointSelector: utils.GetSelectorFromNameFelt(view),
+			Calldata:           []*felt.Felt{},
+		}, rpc.WithBlockTag("latest"))
+		if err == nil && len(resp) > 0 {
+			return resp[0], nil
+		}
+		if err == nil {
+			err = fmt.Errorf("%s returned no data", view)
+		}
+		if !isMissingEntryPoint(err) {
+			return nil, fmt.Errorf("%s failed: %w", view, err)
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%w: %v", ErrPublicKeyUnavailable, lastErr)
+}
+
+// CheckAccountPublicKey checks the account at address holds publicKey. A different key is an
+// *AccountKeyMismatchError; an account without a public key view is ErrPublicKeyUnavailable
+func CheckAccountPublicKey(ctx context.Context, caller ContractCaller, address, publicKey *felt.Felt) error {
+	onChain, err := AccountPublicKey(ctx, caller, address)
+	if err != nil {
+		return err
+	}
+	if !onChain.Equal(publicKey) {
+		return &AccountKeyMismatchError{Account: address, Configured: publicKey, OnChain: onChain}
+	}
+	return nil
+}
+
+// isMissingEntryPoint reports whether a call failed because the contract has no such entry point
+func isMissingEntryPoint(err error) bool {
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrEntrypointNotFound.Code {
+		return true
+	}
+	// Nodes wrap the Cairo ENTRYPOINT_NOT_FOUND revert in a contract error
+	msg := strings.ToUpper(err.Error())
+	return strings.Contains(msg, "ENTRYPOINT_NOT_FOUND") || strings.Contains(msg, "ENTRY_POINT_NOT_FOUND")
+}
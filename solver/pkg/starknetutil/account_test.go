@@ -0,0 +1,176 @@
+package starknetutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClassHashReader returns a fixed class hash, or err
+type fakeClassHashReader struct {
+	classHash string
+	err       error
+	calls     int
+}
+
+func (f *fakeClassHashReader) ClassHashAt(context.Context, rpc.BlockID, *felt.Felt) (*felt.Felt, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return utils.HexToFelt(f.classHash)
+}
+
+func TestCairoVersionForClass(t *testing.T) {
+	tests := []struct {
+		classHash string
+		version   account.CairoVersion
+		known     bool
+	}{
+		{"0x4d07e40e93398ed3c76981e72dd1fd22557a78ce36c0515f679e27f0bb5bc5f", account.CairoV0, true},
+		{"0x025ec026985a3bf9d0cc1fe17326b245dfdc3ff89b8fde106542a3ea56c5a918", account.CairoV0, true}, // leading zero
+		{"0x061dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f", account.CairoV2, true},
+		{"0x36078334509b514626504edc9fb252328d1a240e4e948bef8d0c08dff45927f", account.CairoV2, true},
+		{"0x1234", 0, false},
+	}
+	for _, tt := range tests {
+		classHash, err := utils.HexToFelt(tt.classHash)
+		require.NoError(t, err)
+		version, known := CairoVersionForClass(classHash)
+		assert.Equal(t, tt.known, known, tt.classHash)
+		assert.Equal(t, tt.version, version, tt.classHash)
+	}
+}
+
+func TestDetectCairoVersion(t *testing.T) {
+	address := new(felt.Felt).SetUint64(0xa11ce)
+	ctx := context.Background()
+	const env = "STARKNET_TEST_ACCOUNT_VERSION"
+	t.Setenv(env, "")
+
+	reader := &fakeClassHashReader{classHash: "0x4d07e40e93398ed3c76981e72dd1fd22557a78ce36c0515f679e27f0bb5bc5f"}
+	version, err := DetectCairoVersion(ctx, reader, env, address)
+	require.NoError(t, err)
+	assert.Equal(t, account.CairoV0, version)
+
+	version, err = DetectCairoVersion(ctx, &fakeClassHashReader{classHash: "0x1234"}, env, address)
+	require.NoError(t, err)
+	assert.Equal(t, account.CairoV2, version, "unknown classes default to Cairo 2")
+
+	_, err = DetectCairoVersion(ctx, &fakeClassHashReader{err: errors.New("contract not found")}, env, address)
+	assert.ErrorContains(t, err, "set "+env+" to skip detection")
+
+	t.Setenv(env, "cairo2")
+	reader.calls = 0
+	version, err = DetectCairoVersion(ctx, reader, env, address)
+	require.NoError(t, err)
+	assert.Equal(t, account.CairoV2, version, "the env var overrides the class hash")
+	assert.Zero(t, reader.calls)
+
+	t.Setenv(env, "3")
+	_, err = DetectCairoVersion(ctx, reader, env, address)
+	assert.ErrorContains(t, err, env+": invalid account version")
+}
+
+func TestParseCairoVersion(t *testing.T) {
+	for _, value := range []string{"0", "v0", "Cairo0", " V0 "} {
+		version, err := ParseCairoVersion(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, account.CairoV0, version, value)
+	}
+	for _, value := range []string{"2", "v2", "cairo2", "1"} {
+		version, err := ParseCairoVersion(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, account.CairoV2, version, value)
+	}
+	_, err := ParseCairoVersion("latest")
+	assert.Error(t, err)
+}
+
+func TestAccountVersionEnv(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	assert.Equal(t, "STARKNET_ALICE_ACCOUNT_VERSION", AccountVersionEnv("Starknet", "Alice"))
+	assert.Equal(t, "ZTARKNET_SOLVER_ACCOUNT_VERSION", AccountVersionEnv("Ztarknet", "Solver"))
+
+	t.Setenv("IS_DEVNET", "true")
+	assert.Equal(t, "LOCAL_STARKNET_DEPLOYER_ACCOUNT_VERSION", AccountVersionEnv("Starknet", "Deployer"))
+	assert.Equal(t, "ZTARKNET_SOLVER_ACCOUNT_VERSION", AccountVersionEnv("Ztarknet", "Solver"), "Ztarknet has no LOCAL_ variants")
+}
+
+// fakeAccount answers the public key views it exposes with a fixed key
+type fakeAccount struct {
+	views     map[string]*felt.Felt
+	err       error
+	selectors []*felt.Felt
+}
+
+func (f *fakeAccount) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	f.selectors = append(f.selectors, call.EntryPointSelector)
+	if f.err != nil {
+		return nil, f.err
+	}
+	for view, key := range f.views {
+		if utils.GetSelectorFromNameFelt(view).Equal(call.EntryPointSelector) {
+			return []*felt.Felt{key}, nil
+		}
+	}
+	return nil, errors.New("Contract error: ENTRYPOINT_NOT_FOUND")
+}
+
+func TestCheckAccountPublicKey(t *testing.T) {
+	ctx := context.Background()
+	address := new(felt.Felt).SetUint64(0xa11ce)
+	configured := new(felt.Felt).SetUint64(0x1234)
+
+	tests := []struct {
+		name    string
+		account *fakeAccount
+		check   func(t *testing.T, err error)
+	}{
+		{
+			name:    "Cairo 2 account holds the key",
+			account: &fakeAccount{views: map[string]*felt.Felt{"get_public_key": configured}},
+			check:   func(t *testing.T, err error) { assert.NoError(t, err) },
+		},
+		{
+			name:    "Cairo 0 account holds the key",
+			account: &fakeAccount{views: map[string]*felt.Felt{"getPublicKey": configured}},
+			check:   func(t *testing.T, err error) { assert.NoError(t, err) },
+		},
+		{
+			name:    "account holds another key",
+			account: &fakeAccount{views: map[string]*felt.Felt{"get_public_key": new(felt.Felt).SetUint64(0x5678)}},
+			check: func(t *testing.T, err error) {
+				var mismatch *AccountKeyMismatchError
+				require.ErrorAs(t, err, &mismatch)
+				assert.Equal(t, "0x5678", mismatch.OnChain.String())
+				assert.ErrorContains(t, err, "account 0xa11ce holds public key 0x5678, but the configured public key is 0x1234")
+			},
+		},
+		{
+			name:    "account without a public key view",
+			account: &fakeAccount{},
+			check:   func(t *testing.T, err error) { assert.ErrorIs(t, err, ErrPublicKeyUnavailable) },
+		},
+		{
+			name:    "RPC failure",
+			account: &fakeAccount{err: errors.New("connection refused")},
+			check: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "get_public_key failed: connection refused")
+				assert.NotErrorIs(t, err, ErrPublicKeyUnavailable)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, CheckAccountPublicKey(ctx, tt.account, address, configured))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package starknetutil
+
+// 32-byte word conversions
+// OrderData, routers and filler data carry addresses of both chain families as bytes32: felts as their big-endian
+// encoding, EVM addresses left-padded with 12 zero bytes. These helpers are the single place words are built and
+// read back, and reading back fails instead of silently reducing or truncating a word that is not a valid value
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// evmAddressPadding is the number of leading zero bytes of an EVM address word
+const evmAddressPadding = Bytes32Length - common.AddressLength
+
+// FeltToBytes32 returns a felt as a big-endian 32-byte word (nil encodes as zero)
+func FeltToBytes32(f *felt.Felt) [32]byte {
+	if f == nil {
+		return [32]byte{}
+	}
+	return f.Bytes()
+}
+
+// Bytes32ToFelt reads a word as a felt; a word not below the field prime is an error rather than reduced
+func Bytes32ToFelt(word [32]byte) (*felt.Felt, error) {
+	f := new(felt.Felt)
+	if err := f.SetBytesCanonical(word[:]); err != nil {
+		return nil, fmt.Errorf("word 0x%x exceeds the felt field", word)
+	}
+	return f, nil
+}
+
+// EVMAddressToBytes32 left-pads an EVM address to a 32-byte word
+func EVMAddressToBytes32(address common.Address) [32]byte {
+	var word [32]byte
+	copy(word[evmAddressPadding:], address.Bytes())
+	return word
+}
+
+// Bytes32ToEVMAddress reads an EVM address from a word, which must have 12 leading zero bytes
+func Bytes32ToEVMAddress(word [32]byte) (common.Address, error) {
+	for _, b := range word[:evmAddressPadding] {
+		if b != 0 {
+			return common.Address{}, fmt.Errorf("word 0x%x is not an EVM address: its %d leading bytes are not zero", word, evmAddressPadding)
+		}
+	}
+	return common.BytesToAddress(word[evmAddressPadding:]), nil
+}
+
+// Bytes32ToU256Felts encodes a word as the low and high felts of a Cairo u256
+func Bytes32ToU256Felts(word [32]byte) []*felt.Felt {
+	return U256Calldata(new(big.Int).SetBytes(word[:]))
+}
+
+// U256FeltsToBytes32 is the inverse of Bytes32ToU256Felts; each half must fit a u128
+func U256FeltsToBytes32(low, high *felt.Felt) ([32]byte, error) {
+	var word [32]byte
+	if err := putU128(word[:Bytes16Length], "high", high); err != nil {
+		return [32]byte{}, err
+	}
+	if err := putU128(word[Bytes16Length:], "low", low); err != nil {
+		return [32]byte{}, err
+	}
+	return word, nil
+}
+
+// putU128 writes the u128 half of a u256 into 16 bytes of dst
+func putU128(dst []byte, name string, half *felt.Felt) error {
+	if half == nil {
+		return fmt.Errorf("u256 %s is missing", name)
+	}
+	b := half.Bytes()
+	if new(big.Int).SetBytes(b[:Bytes16Length]).Sign() != 0 {
+		return fmt.Errorf("u256 %s %s does not fit in u128", name, half.String())
+	}
+	copy(dst, b[Bytes16Length:])
+	return nil
+}
+
+// HexToBytes32 right-aligns a hex value (EVM address or felt, with or without 0x, odd length allowed) in a word
+func HexToBytes32(hexStr string) ([32]byte, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(hexStr, "0x"), "0X")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("invalid hex %q: %w", hexStr, err)
+	}
+	if len(b) > Bytes32Length {
+		return [32]byte{}, fmt.Errorf("hex %q is %d bytes, longer than 32", hexStr, len(b))
+	}
+	var word [32]byte
+	copy(word[Bytes32Length-len(b):], b)
+	return word, nil
+}
@@ -0,0 +1,166 @@
+package starknetutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxFeltHex is the field prime minus one, the largest felt
+const maxFeltHex = "0x800000000000011000000000000000000000000000000000000000000000000"
+
+func wordFromHex(t *testing.T, hexStr string) [32]byte {
+	t.Helper()
+	word, err := HexToBytes32(hexStr)
+	require.NoError(t, err)
+	return word
+}
+
+func TestFeltBytes32RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+	}{
+		{name: "zero", hex: "0x0"},
+		{name: "one", hex: "0x1"},
+		{name: "odd_length", hex: "0xabc"},
+		{name: "leading_zero_address", hex: "0x004c8d1a8ff5dc2e6b5b1c1d8a3e7d6e2b0f0a1b2c3d4e5f60718293a4b5c6d7"},
+		{name: "evm_sized", hex: "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"},
+		{name: "63_digits", hex: "0x4c8d1a8ff5dc2e6b5b1c1d8a3e7d6e2b0f0a1b2c3d4e5f60718293a4b5c6d7e"},
+		{name: "max_felt", hex: maxFeltHex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := utils.HexToFelt(tt.hex)
+			require.NoError(t, err)
+
+			word := FeltToBytes32(f)
+			assert.Equal(t, wordFromHex(t, tt.hex), word, "felt bytes and hex must right-align the same way")
+
+			back, err := Bytes32ToFelt(word)
+			require.NoError(t, err)
+			assert.True(t, f.Equal(back), "%s != %s", f, back)
+		})
+	}
+
+	assert.Equal(t, [32]byte{}, FeltToBytes32(nil))
+}
+
+func TestBytes32ToFeltOverflow(t *testing.T) {
+	prime := new(big.Int).Add(utils.FeltToBigInt(mustHexFelt(t, maxFeltHex)), big.NewInt(1))
+	var word [32]byte
+	prime.FillBytes(word[:])
+
+	_, err := Bytes32ToFelt(word)
+	assert.EqualError(t, err, "word 0x0800000000000011000000000000000000000000000000000000000000000001 exceeds the felt field")
+
+	all := [32]byte{}
+	for i := range all {
+		all[i] = 0xff
+	}
+	_, err = Bytes32ToFelt(all)
+	assert.ErrorContains(t, err, "exceeds the felt field")
+}
+
+func mustHexFelt(t *testing.T, hexStr string) *felt.Felt {
+	t.Helper()
+	f, err := utils.HexToFelt(hexStr)
+	require.NoError(t, err)
+	return f
+}
+
+func TestEVMAddressBytes32(t *testing.T) {
+	for _, address := range []common.Address{
+		{},
+		common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		common.HexToAddress("0x00000000000000000000000000000000000000ff"),
+		common.HexToAddress("0x0000000000000000000000000000000000000000"),
+		common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff"),
+	} {
+		word := EVMAddressToBytes32(address)
+		assert.Equal(t, make([]byte, 12), word[:12])
+		assert.Equal(t, common.LeftPadBytes(address.Bytes(), 32), word[:])
+
+		back, err := Bytes32ToEVMAddress(word)
+		require.NoError(t, err)
+		assert.Equal(t, address, back)
+	}
+
+	// A Starknet felt does not fit the 20 address bytes
+	_, err := Bytes32ToEVMAddress(wordFromHex(t, "0x4c8d1a8ff5dc2e6b5b1c1d8a3e7d6e2b0f0a1b2c3d4e5f60718293a4b5c6d7e"))
+	assert.ErrorContains(t, err, "is not an EVM address: its 12 leading bytes are not zero")
+
+	var word [32]byte
+	word[11] = 1
+	_, err = Bytes32ToEVMAddress(word)
+	assert.Error(t, err, "a single byte in the padding is rejected")
+}
+
+func TestU256FeltsBytes32RoundTrip(t *testing.T) {
+	for _, hexStr := range []string{
+		"0x0",
+		"0x1",
+		"0xffffffffffffffffffffffffffffffff",
+		"0x100000000000000000000000000000000",
+		"0x70997970c51812dc3a010c7d01b50e0d17dc79c8",
+		maxFeltHex,
+		"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	} {
+		word := wordFromHex(t, hexStr)
+		felts := Bytes32ToU256Felts(word)
+		require.Len(t, felts, 2)
+
+		back, err := U256FeltsToBytes32(felts[0], felts[1])
+		require.NoError(t, err, hexStr)
+		assert.Equal(t, word, back, hexStr)
+	}
+}
+
+func TestU256FeltsToBytes32Errors(t *testing.T) {
+	tooWide := utils.BigIntToFelt(new(big.Int).Lsh(big.NewInt(1), 128))
+
+	_, err := U256FeltsToBytes32(tooWide, utils.Uint64ToFelt(0))
+	assert.EqualError(t, err, "u256 low 0x100000000000000000000000000000000 does not fit in u128")
+
+	_, err = U256FeltsToBytes32(utils.Uint64ToFelt(0), tooWide)
+	assert.EqualError(t, err, "u256 high 0x100000000000000000000000000000000 does not fit in u128")
+
+	_, err = U256FeltsToBytes32(nil, utils.Uint64ToFelt(0))
+	assert.EqualError(t, err, "u256 low is missing")
+}
+
+func TestHexToBytes32(t *testing.T) {
+	tests := []struct {
+		name    string
+		hex     string
+		want    string
+		wantErr string
+	}{
+		{name: "evm_address", hex: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", want: "0x00000000000000000000000070997970c51812dc3a010c7d01b50e0d17dc79c8"},
+		{name: "odd_length", hex: "0xabc", want: "0x0000000000000000000000000000000000000000000000000000000000000abc"},
+		{name: "no_prefix", hex: "1", want: "0x0000000000000000000000000000000000000000000000000000000000000001"},
+		{name: "upper_prefix", hex: "0X0a", want: "0x000000000000000000000000000000000000000000000000000000000000000a"},
+		{name: "empty", hex: "0x", want: "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{name: "full_word", hex: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", want: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		{name: "too_long", hex: "0x01ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", wantErr: "is 33 bytes, longer than 32"},
+		{name: "not_hex", hex: "0xzz", wantErr: `invalid hex "0xzz"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			word, err := HexToBytes32(tt.hex)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, common.HexToHash(tt.want), common.Hash(word))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package starknetutil
+
+// Calldata builders
+// Cairo serializes an enum as its variant index followed by the variant's fields, so core::option::Option
+// (`enum Option<T> { Some: T, None }`) is [0, value...] for Some and [1] for None. The helpers below name that
+// encoding once instead of leaving 0/1 felts in every caller, and encode the Hyperlane router admin calls
+// (enroll_remote_router(s), set_destination_gas) on top of it
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Variant indexes of core::option::Option
+const (
+	optionSomeVariant = 0
+	optionNoneVariant = 1
+)
+
+// OptionSome encodes Some(value) from the already-serialized value
+func OptionSome(value ...*felt.Felt) []*felt.Felt {
+	return append([]*felt.Felt{utils.Uint64ToFelt(optionSomeVariant)}, value...)
+}
+
+// OptionNone encodes None
+func OptionNone() []*felt.Felt {
+	return []*felt.Felt{utils.Uint64ToFelt(optionNoneVariant)}
+}
+
+// U256Calldata encodes a u256 as its low and high felts
+func U256Calldata(value *big.Int) []*felt.Felt {
+	low, high := ToU256(value)
+	return []*felt.Felt{low, high}
+}
+
+// U32Calldata encodes a u32
+func U32Calldata(value uint32) []*felt.Felt {
+	return []*felt.Felt{utils.Uint64ToFelt(uint64(value))}
+}
+
+// ArrayCalldata encodes an Array<T>: its length followed by each element as serialized by encode
+func ArrayCalldata[T any](items []T, encode func(T) []*felt.Felt) []*felt.Felt {
+	out := []*felt.Felt{utils.Uint64ToFelt(uint64(len(items)))}
+	for _, item := range items {
+		out = append(out, encode(item)...)
+	}
+	return out
+}
+
+// GasRouterConfig is the Cairo `GasRouterConfig { destination: u32, gas: u256 }`
+type GasRouterConfig struct {
+	Destination uint32
+	Gas         *big.Int
+}
+
+// Calldata serializes the struct fields in declaration order
+func (c GasRouterConfig) Calldata() []*felt.Felt {
+	return append(U32Calldata(c.Destination), U256Calldata(c.Gas)...)
+}
+
+// SetDestinationGasCalldata encodes set_destination_gas(gas_configs: Option<Array<GasRouterConfig>>,
+// domain: Option<u32>, gas: Option<u256>) for a batch: Some(configs), None, None
+func SetDestinationGasCalldata(configs []GasRouterConfig) []*felt.Felt {
+	out := OptionSome(ArrayCalldata(configs, GasRouterConfig.Calldata)...)
+	out = append(out, OptionNone()...)
+	return append(out, OptionNone()...)
+}
+
+// SetSingleDestinationGasCalldata encodes set_destination_gas for one domain: None, Some(domain), Some(gas)
+func SetSingleDestinationGasCalldata(domain uint32, gas *big.Int) []*felt.Felt {
+	out := OptionNone()
+	out = append(out, OptionSome(U32Calldata(domain)...)...)
+	return append(out, OptionSome(U256Calldata(gas)...)...)
+}
+
+// EnrollRemoteRoutersCalldata encodes enroll_remote_routers(domains: Array<u32>, addresses: Array<u256>)
+func EnrollRemoteRoutersCalldata(domains []uint32, routers [][32]byte) ([]*felt.Felt, error) {
+	if len(domains) != len(routers) {
+		return nil, fmt.Errorf("enroll_remote_routers needs one router per domain, got %d domains and %d routers", len(domains), len(routers))
+	}
+	out := ArrayCalldata(domains, U32Calldata)
+	return append(out, ArrayCalldata(routers, Bytes32ToU256Felts)...), nil
+}
+
+// EnrollRemoteRouterCalldata encodes enroll_remote_router(domain: u32, router: u256)
+func EnrollRemoteRouterCalldata(domain uint32, router [32]byte) []*felt.Felt {
+	return append(U32Calldata(domain), Bytes32ToU256Felts(router)...)
+}
@@ -0,0 +1,73 @@
+package starknetutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hexFelts renders calldata as hex strings so fixtures read like a transaction's calldata
+func hexFelts(calldata []*felt.Felt) []string {
+	out := make([]string, len(calldata))
+	for i, f := range calldata {
+		out[i] = f.String()
+	}
+	return out
+}
+
+func TestOptionCalldata(t *testing.T) {
+	assert.Equal(t, []string{"0x0", "0x2a"}, hexFelts(OptionSome(U32Calldata(42)...)))
+	assert.Equal(t, []string{"0x1"}, hexFelts(OptionNone()))
+	assert.Equal(t, []string{"0x0"}, hexFelts(OptionSome()), "Some of a zero-sized value is just the variant")
+}
+
+func TestSetDestinationGasCalldata(t *testing.T) {
+	// The batch call register-sn-routers sends for Sepolia (domain 11155111) at 64000 and Starknet
+	// (domain 23448591) at 100000
+	calldata := SetDestinationGasCalldata([]GasRouterConfig{
+		{Destination: 11155111, Gas: big.NewInt(64000)},
+		{Destination: 23448591, Gas: big.NewInt(100000)},
+	})
+	assert.Equal(t, []string{
+		"0x0",                       // gas_configs: Some
+		"0x2",                       // array length
+		"0xaa36a7", "0xfa00", "0x0", // { destination, gas.low, gas.high }
+		"0x165cc0f", "0x186a0", "0x0",
+		"0x1", // domain: None
+		"0x1", // gas: None
+	}, hexFelts(calldata))
+
+	t.Run("single_domain", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"0x1",           // gas_configs: None
+			"0x0", "0x2105", // domain: Some(8453)
+			"0x0", "0xfa00", "0x0", // gas: Some(64000)
+		}, hexFelts(SetSingleDestinationGasCalldata(8453, big.NewInt(64000))))
+	})
+
+	t.Run("u256_above_128_bits", func(t *testing.T) {
+		gas := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(3), 128), big.NewInt(5))
+		assert.Equal(t, []string{"0x7", "0x5", "0x3"}, hexFelts(GasRouterConfig{Destination: 7, Gas: gas}.Calldata()))
+	})
+}
+
+func TestEnrollRemoteRoutersCalldata(t *testing.T) {
+	var evmRouter [32]byte
+	copy(evmRouter[12:], []byte{0xf6, 0x14, 0xc6, 0xbf, 0x94, 0xb0, 0x22, 0xe1, 0x6b, 0xef, 0x7d, 0xbe, 0xcf, 0x76, 0x14, 0xff, 0xd2, 0xb2, 0x01, 0xd3})
+
+	calldata, err := EnrollRemoteRoutersCalldata([]uint32{11155111}, [][32]byte{evmRouter})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"0x1", "0xaa36a7", // domains
+		"0x1", "0x94b022e16bef7dbecf7614ffd2b201d3", "0xf614c6bf", // routers as u256 { low, high }
+	}, hexFelts(calldata))
+
+	_, err = EnrollRemoteRoutersCalldata([]uint32{1, 2}, [][32]byte{evmRouter})
+	assert.ErrorContains(t, err, "one router per domain")
+
+	assert.Equal(t, []string{"0xaa36a7", "0x94b022e16bef7dbecf7614ffd2b201d3", "0xf614c6bf"},
+		hexFelts(EnrollRemoteRouterCalldata(11155111, evmRouter)))
+}
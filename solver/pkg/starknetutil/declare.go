@@ -0,0 +1,34 @@
+package starknetutil
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// alreadyDeclaredPattern matches the execution error a node returns when declaring an existing class
+var alreadyDeclaredPattern = regexp.MustCompile(`Class with hash (0x[0-9a-fA-F]+) is already declared`)
+
+// AlreadyDeclared reports whether a declare error means the class is already declared, and returns the
+// class hash named in the error when the node includes one (nil otherwise)
+func AlreadyDeclared(err error) (*felt.Felt, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if match := alreadyDeclaredPattern.FindStringSubmatch(err.Error()); match != nil {
+		if classHash, parseErr := utils.HexToFelt(match[1]); parseErr == nil {
+			return classHash, true
+		}
+		return nil, true
+	}
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrClassAlreadyDeclared.Code {
+		return nil, true
+	}
+	// Nodes that word the error differently, or flatten it into text, still say so
+	return nil, strings.Contains(err.Error(), "is already declared")
+}
@@ -0,0 +1,56 @@
+package starknetutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlreadyDeclared(t *testing.T) {
+	const classHash = "0x0224518978adb773cfd4862a894e9d333192fbd24bc83841dc7d4167c09b89c5"
+
+	for name, tc := range map[string]struct {
+		err       error
+		declared  bool
+		classHash string
+	}{
+		"execution error with hash": {
+			err:       fmt.Errorf("41 Transaction execution error: Class with hash %s is already declared.", classHash),
+			declared:  true,
+			classHash: classHash,
+		},
+		"wrapped execution error": {
+			err:       fmt.Errorf("declare: %w", errors.New("Class with hash "+classHash+" is already declared")),
+			declared:  true,
+			classHash: classHash,
+		},
+		"RPC error code without hash": {
+			err:      &rpc.RPCError{Code: rpc.ErrClassAlreadyDeclared.Code, Message: rpc.ErrClassAlreadyDeclared.Message},
+			declared: true,
+		},
+		"message without a hash": {
+			err:      errors.New("Transaction execution error: class 0x1234 is already declared"),
+			declared: true,
+		},
+		"other RPC error": {err: &rpc.RPCError{Code: 50, Message: "Invalid contract class"}},
+		"transport error": {err: errors.New("dial tcp: connection refused")},
+		"no error":        {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, declared := AlreadyDeclared(tc.err)
+			assert.Equal(t, tc.declared, declared)
+			if tc.classHash == "" {
+				assert.Nil(t, got)
+				return
+			}
+			want, err := utils.HexToFelt(tc.classHash)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
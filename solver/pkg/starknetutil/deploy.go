@@ -0,0 +1,194 @@
+package starknetutil
+
+// Deployment verification
+// A UDC deployment whose constructor fails can still come back ACCEPTED, leaving nothing at the precomputed
+// address. VerifyDeployment checks the expected class is live at the address and that a view answers there, so
+// the deploy tools and doctor do not report a contract that is not there
+//
+// With a fixed salt (--salt or STARKNET_DEPLOY_SALT) a UDC deploy lands on an address known in advance:
+// UDCAddress predicts it, and DeployedAt lets a rerun skip contracts that are already live there. Once the deploy
+// is accepted, UDCDeployedAddress reads the address the UDC actually reported from the receipt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// Views taking no arguments that only answer once the contract's constructor ran
+const (
+	ERC20ProbeView         = "name"
+	Hyperlane7683ProbeView = "get_local_domain"
+)
+
+// Deploy tool options
+const (
+	// DeploySaltEnv fixes the UDC salt when SaltFlag is not given
+	DeploySaltEnv = "STARKNET_DEPLOY_SALT"
+	// SaltFlag fixes the UDC salt, so the deployed address only depends on the class, calldata and deployer
+	SaltFlag = "--salt"
+	// PredictOnlyFlag prints the addresses a deploy would use without sending anything
+	PredictOnlyFlag = "--predict-only"
+)
+
+// DeployArgs are the options of the Starknet deploy tools
+type DeployArgs struct {
+	Salt        *felt.Felt // nil deploys with a random salt
+	PredictOnly bool
+	WriteEnv    bool
+}
+
+// ParseDeployArgs parses [--salt <felt>] [--predict-only] [--write-env]. The salt defaults to
+// STARKNET_DEPLOY_SALT, and predicting requires one
+func ParseDeployArgs(args []string) (DeployArgs, error) {
+	var opts DeployArgs
+	salt := strings.TrimSpace(os.Getenv(DeploySaltEnv))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == PredictOnlyFlag:
+			opts.PredictOnly = true
+		case arg == envutil.WriteEnvFlag:
+			opts.WriteEnv = true
+		case arg == SaltFlag || strings.HasPrefix(arg, SaltFlag+"="):
+			value, ok := strings.CutPrefix(arg, SaltFlag+"=")
+			if !ok {
+				if i+1 >= len(args) {
+					return DeployArgs{}, fmt.Errorf("%s requires a value", SaltFlag)
+				}
+				i++
+				value = args[i]
+			}
+			salt = strings.TrimSpace(value)
+		default:
+			return DeployArgs{}, fmt.Errorf("unexpected argument: %s (usage: [%s <felt>] [%s] [%s])",
+				arg, SaltFlag, PredictOnlyFlag, envutil.WriteEnvFlag)
+		}
+	}
+
+	if salt != "" {
+		parsed, err := new(felt.Felt).SetString(salt)
+		if err != nil {
+			return DeployArgs{}, fmt.Errorf("invalid salt %q: %w", salt, err)
+		}
+		opts.Salt = parsed
+	}
+	if opts.PredictOnly && opts.Salt == nil {
+		return DeployArgs{}, fmt.Errorf("%s needs a fixed salt: pass %s or set %s", PredictOnlyFlag, SaltFlag, DeploySaltEnv)
+	}
+	return opts, nil
+}
+
+// UDCOptions returns the UDC options deploying with salt, nil for a random one
+func UDCOptions(salt *felt.Felt) *utils.UDCOptions {
+	if salt == nil {
+		return nil
+	}
+	return &utils.UDCOptions{Salt: salt}
+}
+
+// UDCAddress returns the address DeployContractWithUDC deploys classHash to when deployer sends it with salt
+// and constructorCalldata
+func UDCAddress(classHash, salt *felt.Felt, constructorCalldata []*felt.Felt, deployer *felt.Felt) *felt.Felt {
+	return utils.PrecomputeAddressForUDC(classHash, salt, constructorCalldata, utils.UDCCairoV0, deployer)
+}
+
+// Selectors of the event the UDC emits for every deployment: ContractDeployed for the Cairo 0 UDC, Deployed for
+// newer ones. Both carry [address, deployer, unique, classHash, calldata_len, calldata..., salt] as data
+var udcDeployedSelectors = []*felt.Felt{
+	utils.GetSelectorFromNameFelt("ContractDeployed"),
+	utils.GetSelectorFromNameFelt("Deployed"),
+}
+
+// UDCDeployedAddress returns the address in the receipt's UDC deployment event, skipping deployments of another
+// class unless classHash is nil; ok is false when the receipt has no such event
+func UDCDeployedAddress(receipt *rpc.TransactionReceipt, classHash *felt.Felt) (address *felt.Felt, ok bool) {
+	for _, event := range receipt.Events {
+		if len(event.Keys) == 0 || len(event.Data) < 4 || !isUDCDeployed(event.Keys[0]) {
+			continue
+		}
+		if classHash != nil && !event.Data[3].Equal(classHash) {
+			continue
+		}
+		return event.Data[0], true
+	}
+	return nil, false
+}
+
+func isUDCDeployed(selector *felt.Felt) bool {
+	for _, s := range udcDeployedSelectors {
+		if selector.Equal(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrNoContract is returned by VerifyDeployment when nothing is deployed at the address
+var ErrNoContract = errors.New("no contract deployed")
+
+// DeploymentReader is the part of the Starknet RPC provider VerifyDeployment uses
+type DeploymentReader interface {
+	ClassHashReader
+	ContractCaller
+}
+
+// VerifyDeployment checks a contract is deployed at address, of classHash unless it is nil, and that calling
+// the view probe returns data
+func VerifyDeployment(ctx context.Context, reader DeploymentReader, address, classHash *felt.Felt, probe string) error {
+	deployed, err := reader.ClassHashAt(ctx, rpc.WithBlockTag("latest"), address)
+	if err != nil {
+		var rpcErr *rpc.RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrContractNotFound.Code {
+			return fmt.Errorf("%w at %s", ErrNoContract, address)
+		}
+		return fmt.Errorf("failed to get the class hash at %s: %w", address, err)
+	}
+	if classHash != nil && !deployed.Equal(classHash) {
+		return fmt.Errorf("class hash at %s is %s, expected %s", address, deployed, classHash)
+	}
+
+	resp, err := reader.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(probe),
+		Calldata:           []*felt.Felt{},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return fmt.Errorf("%s() at %s failed: %w", probe, address, err)
+	}
+	if len(resp) == 0 {
+		return fmt.Errorf("%s() at %s returned no data", probe, address)
+	}
+	return nil
+}
+
+// DeployedAt reports whether a working contract of classHash is already at address. An empty address is not an
+// error; anything else failing VerifyDeployment is, since deploying to that address again would fail
+func DeployedAt(ctx context.Context, reader DeploymentReader, address, classHash *felt.Felt, probe string) (bool, error) {
+	err := VerifyDeployment(ctx, reader, address, classHash, probe)
+	switch {
+	case errors.Is(err, ErrNoContract):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+// VerifyDeployTransaction runs VerifyDeployment for the contract deployed by txHash, pointing at the
+// transaction's trace when the check fails
+func VerifyDeployTransaction(ctx context.Context, reader DeploymentReader, txHash, address, classHash *felt.Felt, probe string) error {
+	if err := VerifyDeployment(ctx, reader, address, classHash, probe); err != nil {
+		return fmt.Errorf("deploy transaction %s did not leave a working contract: %w (inspect it with starknet_traceTransaction %s)",
+			txHash, err, txHash)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package starknetutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeployment answers ClassHashAt from fakeClassHashReader and views from results, keyed by selector
+type fakeDeployment struct {
+	fakeClassHashReader
+	results map[string][]*felt.Felt
+}
+
+func (f *fakeDeployment) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	resp, ok := f.results[call.EntryPointSelector.String()]
+	if !ok {
+		return nil, errors.New("Entry point not found in contract")
+	}
+	return resp, nil
+}
+
+func TestVerifyDeployment(t *testing.T) {
+	const classHash = "0x0224518978adb773cfd4862a894e9d333192fbd24bc83841dc7d4167c09b89c5"
+	address := new(felt.Felt).SetUint64(0xc0de)
+	txHash := new(felt.Felt).SetUint64(0x7a)
+	expected, err := utils.HexToFelt(classHash)
+	require.NoError(t, err)
+	nameSelector := utils.GetSelectorFromNameFelt(ERC20ProbeView).String()
+
+	t.Run("live", func(t *testing.T) {
+		reader := &fakeDeployment{
+			fakeClassHashReader: fakeClassHashReader{classHash: classHash},
+			results:             map[string][]*felt.Felt{nameSelector: {new(felt.Felt)}},
+		}
+		assert.NoError(t, VerifyDeployment(context.Background(), reader, address, expected, ERC20ProbeView))
+		assert.NoError(t, VerifyDeployment(context.Background(), reader, address, nil, ERC20ProbeView), "nil skips the class hash")
+	})
+
+	t.Run("no_contract", func(t *testing.T) {
+		reader := &fakeDeployment{fakeClassHashReader: fakeClassHashReader{err: rpc.ErrContractNotFound}}
+		err := VerifyDeployTransaction(context.Background(), reader, txHash, address, expected, ERC20ProbeView)
+		assert.ErrorIs(t, err, ErrNoContract)
+		assert.ErrorContains(t, err, "deploy transaction 0x7a did not leave a working contract")
+		assert.ErrorContains(t, err, "starknet_traceTransaction 0x7a")
+	})
+
+	t.Run("other_class", func(t *testing.T) {
+		reader := &fakeDeployment{fakeClassHashReader: fakeClassHashReader{classHash: "0x1234"}}
+		err := VerifyDeployment(context.Background(), reader, address, expected, ERC20ProbeView)
+		assert.ErrorContains(t, err, "class hash at 0xc0de is 0x1234, expected "+expected.String())
+	})
+
+	t.Run("view_fails", func(t *testing.T) {
+		reader := &fakeDeployment{fakeClassHashReader: fakeClassHashReader{classHash: classHash}}
+		err := VerifyDeployment(context.Background(), reader, address, expected, Hyperlane7683ProbeView)
+		assert.ErrorContains(t, err, "get_local_domain() at 0xc0de failed")
+	})
+
+	t.Run("view_returns_nothing", func(t *testing.T) {
+		reader := &fakeDeployment{
+			fakeClassHashReader: fakeClassHashReader{classHash: classHash},
+			results:             map[string][]*felt.Felt{nameSelector: {}},
+		}
+		err := VerifyDeployment(context.Background(), reader, address, expected, ERC20ProbeView)
+		assert.ErrorContains(t, err, "name() at 0xc0de returned no data")
+	})
+
+	t.Run("rpc_error", func(t *testing.T) {
+		reader := &fakeDeployment{fakeClassHashReader: fakeClassHashReader{err: transportErr}}
+		err := VerifyDeployment(context.Background(), reader, address, expected, ERC20ProbeView)
+		assert.ErrorContains(t, err, "failed to get the class hash at 0xc0de")
+		assert.NotErrorIs(t, err, ErrNoContract)
+	})
+}
+
+func TestParseDeployArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		wantSalt string
+		want     DeployArgs
+		wantErr  string
+	}{
+		{name: "random_salt", args: nil},
+		{name: "write_env", args: []string{"--write-env"}, want: DeployArgs{WriteEnv: true}},
+		{name: "salt", args: []string{"--salt", "0x2a"}, wantSalt: "0x2a"},
+		{name: "salt_equals", args: []string{"--salt=42"}, wantSalt: "0x2a"},
+		{name: "salt_env", env: "0x7", args: []string{"--predict-only"}, wantSalt: "0x7", want: DeployArgs{PredictOnly: true}},
+		{name: "flag_overrides_env", env: "0x7", args: []string{"--salt", "0x8"}, wantSalt: "0x8"},
+		{name: "salt_without_value", args: []string{"--salt"}, wantErr: "--salt requires a value"},
+		{name: "invalid_salt", args: []string{"--salt", "salty"}, wantErr: `invalid salt "salty"`},
+		{name: "predict_without_salt", args: []string{"--predict-only"}, wantErr: "--predict-only needs a fixed salt"},
+		{name: "unknown_flag", args: []string{"--force"}, wantErr: "unexpected argument: --force"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DeploySaltEnv, tt.env)
+			got, err := ParseDeployArgs(tt.args)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantSalt == "" {
+				assert.Nil(t, got.Salt)
+				assert.Nil(t, UDCOptions(got.Salt))
+			} else {
+				require.NotNil(t, got.Salt)
+				assert.Equal(t, tt.wantSalt, got.Salt.String())
+				assert.Equal(t, got.Salt, UDCOptions(got.Salt).Salt)
+			}
+			got.Salt = nil
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUDCAddress(t *testing.T) {
+	classHash := new(felt.Felt).SetUint64(0xc1a55)
+	deployer := new(felt.Felt).SetUint64(0xde9)
+	salt := new(felt.Felt).SetUint64(42)
+	calldata := []*felt.Felt{new(felt.Felt).SetUint64(1)}
+
+	address := UDCAddress(classHash, salt, calldata, deployer)
+	assert.Equal(t, address, UDCAddress(classHash, salt, calldata, deployer), "the same inputs give the same address")
+	assert.NotEqual(t, address, UDCAddress(classHash, salt, []*felt.Felt{new(felt.Felt).SetUint64(2)}, deployer))
+	assert.NotEqual(t, address, UDCAddress(classHash, new(felt.Felt).SetUint64(43), calldata, deployer))
+	assert.NotEqual(t, address, UDCAddress(classHash, salt, calldata, new(felt.Felt).SetUint64(0xdea)))
+}
+
+func TestDeployedAt(t *testing.T) {
+	const classHash = "0x0224518978adb773cfd4862a894e9d333192fbd24bc83841dc7d4167c09b89c5"
+	address := new(felt.Felt).SetUint64(0xc0de)
+	expected, err := utils.HexToFelt(classHash)
+	require.NoError(t, err)
+	nameSelector := utils.GetSelectorFromNameFelt(ERC20ProbeView).String()
+
+	live := &fakeDeployment{
+		fakeClassHashReader: fakeClassHashReader{classHash: classHash},
+		results:             map[string][]*felt.Felt{nameSelector: {new(felt.Felt)}},
+	}
+	deployed, err := DeployedAt(context.Background(), live, address, expected, ERC20ProbeView)
+	require.NoError(t, err)
+	assert.True(t, deployed)
+
+	empty := &fakeDeployment{fakeClassHashReader: fakeClassHashReader{err: rpc.ErrContractNotFound}}
+	deployed, err = DeployedAt(context.Background(), empty, address, expected, ERC20ProbeView)
+	require.NoError(t, err)
+	assert.False(t, deployed)
+
+	taken := &fakeDeployment{fakeClassHashReader: fakeClassHashReader{classHash: "0x1234"}}
+	_, err = DeployedAt(context.Background(), taken, address, expected, ERC20ProbeView)
+	assert.ErrorContains(t, err, "class hash at 0xc0de is 0x1234")
+}
+
+func TestUDCDeployedAddress(t *testing.T) {
+	// A MockERC20 deployed through the Cairo 0 UDC with salt 0x6f6966; the token emits OwnershipTransferred
+	// before the UDC's ContractDeployed, and the fee transfer comes last
+	raw, err := os.ReadFile(filepath.Join("testdata", "udc_deploy_receipt.json"))
+	require.NoError(t, err)
+	var receipt rpc.TransactionReceipt
+	require.NoError(t, json.Unmarshal(raw, &receipt))
+
+	classHash, err := utils.HexToFelt("0x2ca71a5c0b8f1c4ab9f8d0c2e37b1e0d6a4be3e1d4f0d26f6e9a0c1b6a7d5e3")
+	require.NoError(t, err)
+	deployer, err := utils.HexToFelt("0x64b48806902a367c8598f4f95c305e8c1a1acba5f082d294a43793113115691")
+	require.NoError(t, err)
+	want, err := utils.HexToFelt("0x7b792577ccd2496934194dcaec3822b79d820e53443636195aa78d231daab6f")
+	require.NoError(t, err)
+
+	address, ok := UDCDeployedAddress(&receipt, classHash)
+	require.True(t, ok)
+	assert.Equal(t, want, address)
+
+	address, ok = UDCDeployedAddress(&receipt, nil)
+	require.True(t, ok, "any class matches without a class hash")
+	assert.Equal(t, want, address)
+
+	// The salt, unique flag and calldata in the event give back the precomputed address
+	deployed := receipt.Events[1].Data
+	calldata := deployed[5 : len(deployed)-1]
+	assert.Equal(t, want, UDCAddress(classHash, deployed[len(deployed)-1], calldata, deployer))
+
+	_, ok = UDCDeployedAddress(&receipt, new(felt.Felt).SetUint64(0xc1a55))
+	assert.False(t, ok, "a deployment of another class")
+
+	withoutUDC := receipt
+	withoutUDC.Events = []rpc.Event{receipt.Events[0], receipt.Events[2]}
+	_, ok = UDCDeployedAddress(&withoutUDC, classHash)
+	assert.False(t, ok, "no UDC event")
+
+	// Newer UDCs name the event Deployed, with the same data
+	renamed := receipt
+	renamed.Events = append([]rpc.Event{}, receipt.Events...)
+	renamed.Events[1].Keys = []*felt.Felt{utils.GetSelectorFromNameFelt("Deployed")}
+	address, ok = UDCDeployedAddress(&renamed, classHash)
+	require.True(t, ok)
+	assert.Equal(t, want, address)
+}
@@ -0,0 +1,149 @@
+package starknetutil
+
+// Waiting for Starknet transactions to reach a finality level
+// account.WaitForTransactionReceipt returns the first receipt it finds, pre-confirmed or not, and keeps polling a
+// rejected transaction until its context ends. WaitForFinality polls the transaction status instead, so callers
+// choose how final a transaction must be and a rejection fails the wait straight away
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+// FinalityLevel is how final a transaction must be before WaitForFinality returns
+type FinalityLevel int
+
+const (
+	// FinalityReceived returns once the node accepted the transaction into its mempool, for fire-and-forget sends
+	// whose effects are checked some other way
+	FinalityReceived FinalityLevel = iota
+	// FinalityAcceptedOnL2 returns once the transaction is in an L2 block, for steps that later steps depend on
+	FinalityAcceptedOnL2
+	// FinalityAcceptedOnL1 returns once the transaction's block is proven on L1
+	FinalityAcceptedOnL1
+)
+
+// String returns the transaction status that reaches the level
+func (l FinalityLevel) String() string {
+	return string(l.status())
+}
+
+// status returns the lowest transaction status that reaches the level
+func (l FinalityLevel) status() rpc.TxnStatus {
+	switch l {
+	case FinalityAcceptedOnL2:
+		return rpc.TxnStatusAcceptedOnL2
+	case FinalityAcceptedOnL1:
+		return rpc.TxnStatusAcceptedOnL1
+	default:
+		return rpc.TxnStatusReceived
+	}
+}
+
+// txnStatusRejected is reported by nodes on RPC versions before 0.8 for transactions that failed validation
+const txnStatusRejected rpc.TxnStatus = "REJECTED"
+
+// statusRank orders the transaction statuses by finality
+var statusRank = map[rpc.TxnStatus]int{
+	rpc.TxnStatusReceived:     1,
+	rpc.TxnStatusCandidate:    2,
+	rpc.TxnStatusPreConfirmed: 3,
+	rpc.TxnStatusAcceptedOnL2: 4,
+	rpc.TxnStatusAcceptedOnL1: 5,
+}
+
+// reached reports whether a transaction with status is at least as final as the level
+func (l FinalityLevel) reached(status rpc.TxnStatus) bool {
+	return statusRank[status] >= statusRank[l.status()]
+}
+
+var (
+	// ErrTransactionRejected is returned for a transaction the sequencer rejected; it never executed
+	ErrTransactionRejected = errors.New("rejected")
+	// ErrTransactionReverted is returned with the receipt of a transaction that was included but reverted
+	ErrTransactionReverted = errors.New("reverted")
+)
+
+// FinalityWaiter reads transaction statuses and receipts (rpc.Provider and account.Account's Provider satisfy it)
+type FinalityWaiter interface {
+	TransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*rpc.TxnStatusResult, error)
+	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error)
+}
+
+// WaitForFinality polls the status of txHash every pollInterval until it reaches level and returns its receipt,
+// with the execution resources and fee, which is recorded into ctx's txcost ledger. Unknown hashes and transient
+// RPC failures keep the poll going; a rejected transaction returns ErrTransactionRejected and a reverted one its
+// receipt with ErrTransactionReverted. At FinalityReceived the receipt is nil while the transaction has not
+// executed yet. A positive timeout bounds the wait on top of ctx
+func WaitForFinality(
+	ctx context.Context,
+	waiter FinalityWaiter,
+	txHash *felt.Felt,
+	level FinalityLevel,
+	pollInterval, timeout time.Duration,
+) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var last rpc.TxnStatus
+	for {
+		status, err := waiter.TransactionStatus(ctx, txHash)
+		switch {
+		case err == nil:
+			last = status.FinalityStatus
+			if last == txnStatusRejected {
+				return nil, fmt.Errorf("transaction %s %w: %s", txHash.String(), ErrTransactionRejected, status.FailureReason)
+			}
+			if level.reached(last) {
+				return finalReceipt(ctx, waiter, txHash)
+			}
+		case ctx.Err() != nil:
+		case !isHashNotFound(err) && !IsTransient(err):
+			return nil, fmt.Errorf("failed to read the status of transaction %s: %w", txHash.String(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if last == "" {
+				last = "unknown"
+			}
+			return nil, fmt.Errorf("timed out waiting for transaction %s to reach %s (last status %s): %w", txHash.String(), level, last, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// finalReceipt reads the receipt of a transaction that reached its finality level, records its fee and checks it
+// did not revert
+func finalReceipt(ctx context.Context, waiter FinalityWaiter, txHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	receipt, err := waiter.TransactionReceipt(ctx, txHash)
+	if isHashNotFound(err) {
+		// Only received, not executed yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the receipt of transaction %s: %w", txHash.String(), err)
+	}
+	// A revert is charged too
+	txcost.RecordStarknet(ctx, &receipt.TransactionReceipt)
+	if receipt.ExecutionStatus == rpc.TxnExecutionStatusREVERTED {
+		return receipt, fmt.Errorf("transaction %s %w: %s", txHash.String(), ErrTransactionReverted, receipt.RevertReason)
+	}
+	return receipt, nil
+}
+
+// isHashNotFound reports whether err is the node not knowing a transaction hash (yet)
+func isHashNotFound(err error) bool {
+	var rpcErr *rpc.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrHashNotFound.Code
+}
@@ -0,0 +1,123 @@
+package starknetutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+// statusProvider reports the statuses in turn, repeating the last, and a receipt once the transaction executed;
+// nil statuses are unknown hashes and errs fail the status reads with the same index
+type statusProvider struct {
+	statuses []*rpc.TxnStatusResult
+	errs     map[int]error
+	receipt  *rpc.TransactionReceiptWithBlockInfo
+	polls    int
+}
+
+func (p *statusProvider) TransactionStatus(_ context.Context, _ *felt.Felt) (*rpc.TxnStatusResult, error) {
+	n := p.polls
+	p.polls++
+	if err := p.errs[n]; err != nil {
+		return nil, err
+	}
+	status := p.statuses[min(n, len(p.statuses)-1)]
+	if status == nil {
+		return nil, rpc.ErrHashNotFound
+	}
+	return status, nil
+}
+
+func (p *statusProvider) TransactionReceipt(_ context.Context, _ *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	if p.receipt == nil {
+		return nil, rpc.ErrHashNotFound
+	}
+	return p.receipt, nil
+}
+
+func status(finality rpc.TxnStatus) *rpc.TxnStatusResult {
+	return &rpc.TxnStatusResult{FinalityStatus: finality}
+}
+
+func testReceipt(execution rpc.TxnExecutionStatus) *rpc.TransactionReceiptWithBlockInfo {
+	receipt := &rpc.TransactionReceiptWithBlockInfo{}
+	receipt.ExecutionStatus = execution
+	receipt.FinalityStatus = rpc.TxnFinalityStatusAcceptedOnL2
+	receipt.ActualFee = rpc.FeePayment{Amount: new(felt.Felt).SetUint64(7), Unit: rpc.UnitFri}
+	receipt.ExecutionResources.L2Gas = 1234
+	return receipt
+}
+
+func TestWaitForFinality(t *testing.T) {
+	hash := new(felt.Felt).SetUint64(0xabc)
+
+	t.Run("accepted on L2", func(t *testing.T) {
+		p := &statusProvider{
+			statuses: []*rpc.TxnStatusResult{nil, status(rpc.TxnStatusReceived), nil, status(rpc.TxnStatusPreConfirmed), status(rpc.TxnStatusAcceptedOnL2)},
+			errs:     map[int]error{2: transportErr},
+			receipt:  testReceipt(rpc.TxnExecutionStatusSUCCEEDED),
+		}
+		ledger := &txcost.Ledger{}
+		receipt, err := WaitForFinality(txcost.WithLedger(context.Background(), ledger), p, hash, FinalityAcceptedOnL2, time.Millisecond, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1234), receipt.ExecutionResources.L2Gas)
+		assert.Equal(t, 5, p.polls, "unknown hash, received, transport error, pre-confirmed, accepted")
+		assert.Len(t, ledger.Entries(), 1)
+	})
+
+	t.Run("received", func(t *testing.T) {
+		p := &statusProvider{statuses: []*rpc.TxnStatusResult{status(rpc.TxnStatusReceived)}}
+		receipt, err := WaitForFinality(context.Background(), p, hash, FinalityReceived, time.Millisecond, 0)
+		require.NoError(t, err)
+		assert.Nil(t, receipt, "not executed yet")
+		assert.Equal(t, 1, p.polls)
+	})
+
+	t.Run("L1 waits past L2", func(t *testing.T) {
+		p := &statusProvider{
+			statuses: []*rpc.TxnStatusResult{status(rpc.TxnStatusAcceptedOnL2), status(rpc.TxnStatusAcceptedOnL1)},
+			receipt:  testReceipt(rpc.TxnExecutionStatusSUCCEEDED),
+		}
+		_, err := WaitForFinality(context.Background(), p, hash, FinalityAcceptedOnL1, time.Millisecond, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, p.polls)
+	})
+
+	t.Run("reverted", func(t *testing.T) {
+		p := &statusProvider{statuses: []*rpc.TxnStatusResult{status(rpc.TxnStatusAcceptedOnL2)}, receipt: testReceipt(rpc.TxnExecutionStatusREVERTED)}
+		p.receipt.RevertReason = "ERC20: insufficient balance"
+		receipt, err := WaitForFinality(context.Background(), p, hash, FinalityAcceptedOnL2, time.Millisecond, 0)
+		require.ErrorIs(t, err, ErrTransactionReverted)
+		assert.EqualError(t, err, "transaction 0xabc reverted: ERC20: insufficient balance")
+		assert.NotNil(t, receipt, "a reverted transaction still has its receipt")
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		p := &statusProvider{statuses: []*rpc.TxnStatusResult{{FinalityStatus: txnStatusRejected, FailureReason: "invalid nonce"}}}
+		_, err := WaitForFinality(context.Background(), p, hash, FinalityAcceptedOnL2, time.Millisecond, 0)
+		require.ErrorIs(t, err, ErrTransactionRejected)
+		assert.EqualError(t, err, "transaction 0xabc rejected: invalid nonce")
+		assert.Equal(t, 1, p.polls, "a rejection is final")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		p := &statusProvider{statuses: []*rpc.TxnStatusResult{status(rpc.TxnStatusPreConfirmed)}}
+		_, err := WaitForFinality(context.Background(), p, hash, FinalityAcceptedOnL2, time.Millisecond, 20*time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "to reach ACCEPTED_ON_L2 (last status PRE_CONFIRMED)")
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		p := &statusProvider{statuses: []*rpc.TxnStatusResult{nil}, errs: map[int]error{0: rpc.ErrInvalidTxnHash}}
+		_, err := WaitForFinality(context.Background(), p, hash, FinalityAcceptedOnL2, time.Millisecond, 0)
+		require.ErrorIs(t, err, rpc.ErrInvalidTxnHash)
+		assert.Equal(t, 1, p.polls)
+	})
+}
@@ -0,0 +1,78 @@
+package starknetutil
+
+// Reading balances and allowances that cover an amount
+// An approve or mint sent just before an order may not be in a block yet. ReadFunds reads at the latest block and
+// falls back to the pending state only when the latest value is short, and reports which of the two covered the
+// amount so callers can say their check relied on transactions that are not final
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// FundsState is the block state a balance or allowance was read at
+type FundsState string
+
+const (
+	FundsLatest  FundsState = "latest"
+	FundsPending FundsState = "pending"
+)
+
+// FundsReader reads a balance or allowance at the latest block, or at the pending state when pending is set
+type FundsReader func(ctx context.Context, pending bool) (*big.Int, error)
+
+// Funds is a balance or allowance read by ReadFunds
+type Funds struct {
+	// Value is the value at State: the pending one when only the pending state covers the amount, else the latest
+	Value *big.Int
+	State FundsState
+	// Latest is the value at the latest block
+	Latest *big.Int
+	// PendingErr is why the pending state could not be read when the latest value was short; Value is then the
+	// latest value
+	PendingErr error
+}
+
+// ReadFunds reads at the latest block and, when that is below need, at the pending state. A node that cannot
+// serve the pending state leaves the latest value and sets PendingErr; only a failed latest read is an error
+func ReadFunds(ctx context.Context, read FundsReader, need *big.Int) (Funds, error) {
+	latest, err := read(ctx, false)
+	if err != nil {
+		return Funds{}, err
+	}
+	funds := Funds{Value: latest, State: FundsLatest, Latest: latest}
+	if latest.Cmp(need) >= 0 {
+		return funds, nil
+	}
+	pending, err := read(ctx, true)
+	switch {
+	case err != nil:
+		funds.PendingErr = err
+	case pending.Cmp(need) >= 0:
+		funds.Value, funds.State = pending, FundsPending
+	}
+	return funds, nil
+}
+
+// ERC20BalanceReader reads owner's token balance
+func ERC20BalanceReader(caller ContractCaller, token, owner string) FundsReader {
+	return func(ctx context.Context, pending bool) (*big.Int, error) {
+		return ERC20BalanceAt(ctx, caller, token, owner, fundsBlock(pending))
+	}
+}
+
+// ERC20AllowanceReader reads the token allowance owner gave spender
+func ERC20AllowanceReader(caller ContractCaller, token, owner, spender string) FundsReader {
+	return func(ctx context.Context, pending bool) (*big.Int, error) {
+		return ERC20AllowanceAt(ctx, caller, token, owner, spender, fundsBlock(pending))
+	}
+}
+
+func fundsBlock(pending bool) rpc.BlockID {
+	if pending {
+		return PendingBlock
+	}
+	return rpc.WithBlockTag(rpc.BlockTagLatest)
+}
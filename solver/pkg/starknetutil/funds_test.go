@@ -0,0 +1,55 @@
+package starknetutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFunds(t *testing.T) {
+	need := big.NewInt(100)
+	read := func(latest, pending int64) FundsReader {
+		return func(_ context.Context, atPending bool) (*big.Int, error) {
+			if atPending {
+				return big.NewInt(pending), nil
+			}
+			return big.NewInt(latest), nil
+		}
+	}
+
+	funds, err := ReadFunds(context.Background(), read(100, 0), need)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), funds.Value)
+	assert.Equal(t, FundsLatest, funds.State, "latest is enough, pending is not read")
+
+	funds, err = ReadFunds(context.Background(), read(0, 150), need)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(150), funds.Value)
+	assert.Equal(t, FundsPending, funds.State)
+	assert.Equal(t, big.NewInt(0), funds.Latest)
+
+	funds, err = ReadFunds(context.Background(), read(10, 50), need)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), funds.Value, "a short pending value reports the latest one")
+	assert.Equal(t, FundsLatest, funds.State)
+
+	noPending := func(_ context.Context, pending bool) (*big.Int, error) {
+		if pending {
+			return nil, errors.New("Invalid block id")
+		}
+		return big.NewInt(10), nil
+	}
+	funds, err = ReadFunds(context.Background(), noPending, need)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), funds.Value)
+	assert.Equal(t, FundsLatest, funds.State)
+	assert.EqualError(t, funds.PendingErr, "Invalid block id")
+
+	failing := func(context.Context, bool) (*big.Int, error) { return nil, errors.New("connection refused") }
+	_, err = ReadFunds(context.Background(), failing, need)
+	assert.ErrorContains(t, err, "connection refused")
+}
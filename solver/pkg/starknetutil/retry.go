@@ -0,0 +1,238 @@
+package starknetutil
+
+// Retries for flaky Starknet RPCs
+// starknet-devnet-rs forks drop the odd request with a connection reset or a 429 while producing blocks. The
+// wrappers below retry such transient failures with exponential backoff and jitter; execution errors and
+// reverts are deterministic and returned on the first attempt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/client/rpcerr"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/sirupsen/logrus"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/txcost"
+)
+
+// RetryPolicy configures the retries of transient RPC failures. The zero value makes a single attempt
+type RetryPolicy struct {
+	// Attempts is the total number of attempts, including the first one
+	Attempts int
+	// Backoff is the delay before the first retry, doubled after each retry
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts; 0 means no cap
+	MaxBackoff time.Duration
+	// Logger receives the retried attempts at debug level; nil uses the standard logger
+	Logger logrus.FieldLogger
+}
+
+// DefaultRetryPolicy rides out a few seconds of devnet instability
+var DefaultRetryPolicy = RetryPolicy{Attempts: 4, Backoff: 500 * time.Millisecond, MaxBackoff: 5 * time.Second}
+
+// WithLogger returns a copy of p logging to logger
+func (p RetryPolicy) WithLogger(logger logrus.FieldLogger) RetryPolicy {
+	p.Logger = logger
+	return p
+}
+
+func (p RetryPolicy) logger() logrus.FieldLogger {
+	if p.Logger == nil {
+		return logrus.StandardLogger()
+	}
+	return p.Logger
+}
+
+// delay returns the wait before retry number retry (1-based): the exponential backoff plus up to 50% jitter
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + rand.N(delay/2+1)
+}
+
+// IsTransient reports whether err is worth retrying: network errors, HTTP 429 / 5xx and JSON-RPC internal
+// errors (-32603, which starknet.go also uses for transport failures). Contract and execution errors are not
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == rpcerr.InternalError || rpcErr.Code == 429
+	}
+	var rpcErrValue rpc.RPCError
+	if errors.As(err, &rpcErrValue) {
+		return rpcErrValue.Code == rpcerr.InternalError || rpcErrValue.Code == 429
+	}
+	var httpErr client.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	// Errors flattened to text by fmt.Errorf("%v")
+	msg := strings.ToLower(err.Error())
+	for _, transient := range []string{"connection reset", "connection refused", "too many requests", "429"} {
+		if strings.Contains(msg, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry calls fn until it succeeds, fails with a non-transient error, ctx is done or the policy runs out of attempts
+func Retry[T any](ctx context.Context, p RetryPolicy, desc string, fn func(ctx context.Context) (T, error)) (T, error) {
+	attempts := max(p.Attempts, 1)
+	for attempt := 1; ; attempt++ {
+		result, err := fn(ctx)
+		if err == nil {
+			if attempt > 1 {
+				p.logger().Debugf("%s succeeded on attempt %d/%d", desc, attempt, attempts)
+			}
+			return result, nil
+		}
+		if attempt >= attempts || !IsTransient(err) || ctx.Err() != nil {
+			if attempt > 1 {
+				return result, fmt.Errorf("%s failed after %d attempts: %w", desc, attempt, err)
+			}
+			return result, err
+		}
+
+		delay := p.delay(attempt)
+		p.logger().Debugf("%s failed (attempt %d/%d): %v, retrying in %v", desc, attempt, attempts, err, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result, fmt.Errorf("%s failed after %d attempts: %w", desc, attempt, err)
+		}
+	}
+}
+
+// retryingCaller retries the calls of a ContractCaller
+type retryingCaller struct {
+	caller ContractCaller
+	policy RetryPolicy
+}
+
+// RetryingCaller wraps caller so every Call is retried with p, e.g. for the ERC20 helpers
+func RetryingCaller(caller ContractCaller, p RetryPolicy) ContractCaller {
+	return retryingCaller{caller: caller, policy: p}
+}
+
+func (c retryingCaller) Call(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	return CallWithRetry(ctx, c.policy, c.caller, call, blockID)
+}
+
+// CallWithRetry runs caller.Call with retries
+func CallWithRetry(ctx context.Context, p RetryPolicy, caller ContractCaller, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	return Retry(ctx, p, "starknet_call", func(ctx context.Context) ([]*felt.Felt, error) {
+		return caller.Call(ctx, call, blockID)
+	})
+}
+
+// InvokeSender is the part of account.Account that sends invoke transactions
+type InvokeSender interface {
+	Nonce(ctx context.Context) (*felt.Felt, error)
+	BuildAndSendInvokeTxn(ctx context.Context, functionCalls []rpc.InvokeFunctionCall, opts *account.TxnOptions) (rpc.AddInvokeTransactionResponse, error)
+}
+
+// ErrInvokeMaybeSent is returned by BuildAndSendInvokeTxnWithRetry when an invoke whose response was lost may
+// have been accepted, so it is not sent again
+var ErrInvokeMaybeSent = errors.New("invoke may have been sent")
+
+// IsNotSent reports whether err shows that a request never reached the node, so sending it again cannot repeat
+// it: the connection could not be dialled or was refused, or the node turned the request away with a 429
+func IsNotSent(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == 429 {
+		return true
+	}
+	var httpErr client.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == 429 {
+		return true
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if (errors.As(err, &opErr) && opErr.Op == "dial") || errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	// starknet.go flattens transport errors into the message of an internal RPC error
+	msg := strings.ToLower(err.Error())
+	for _, notSent := range []string{"connection refused", "dial tcp", "no such host", "too many requests"} {
+		if strings.Contains(msg, notSent) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildAndSendInvokeTxnWithRetry sends the invoke with retries. Failures that provably never reached the node
+// are sent again. After any other transient failure the node may have accepted the invoke, so the account nonce
+// is read again first: if it moved, ErrInvokeMaybeSent is returned instead of sending twice; if not, the resend
+// reuses the nonce of the first send, and the node executes at most one of them
+func BuildAndSendInvokeTxnWithRetry(ctx context.Context, p RetryPolicy, sender InvokeSender, calls []rpc.InvokeFunctionCall, opts *account.TxnOptions) (rpc.AddInvokeTransactionResponse, error) {
+	// unsure is the account nonce before a send that may have reached the node
+	var unsure *felt.Felt
+	return Retry(ctx, p, "invoke", func(ctx context.Context) (rpc.AddInvokeTransactionResponse, error) {
+		nonce, err := sender.Nonce(ctx)
+		if err != nil {
+			return rpc.AddInvokeTransactionResponse{}, err
+		}
+		if unsure != nil && !nonce.Equal(unsure) {
+			return rpc.AddInvokeTransactionResponse{}, fmt.Errorf("%w: account nonce moved from %s to %s",
+				ErrInvokeMaybeSent, unsure, nonce)
+		}
+		resp, err := sender.BuildAndSendInvokeTxn(ctx, calls, opts)
+		if err != nil && unsure == nil && !IsNotSent(err) {
+			unsure = nonce
+		}
+		return resp, err
+	})
+}
+
+// ReceiptWaiter is the part of account.Account that waits for receipts
+type ReceiptWaiter interface {
+	WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceiptWithBlockInfo, error)
+}
+
+// WaitForTransactionReceiptWithRetry waits for the receipt of txHash, starting over after transient errors, and
+// records its fee into ctx's txcost ledger
+func WaitForTransactionReceiptWithRetry(ctx context.Context, p RetryPolicy, waiter ReceiptWaiter, txHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	receipt, err := Retry(ctx, p, "receipt wait for "+txHash.String(), func(ctx context.Context) (*rpc.TransactionReceiptWithBlockInfo, error) {
+		return waiter.WaitForTransactionReceipt(ctx, txHash, pollInterval)
+	})
+	if err == nil && receipt != nil {
+		txcost.RecordStarknet(ctx, &receipt.TransactionReceipt)
+	}
+	return receipt, err
+}
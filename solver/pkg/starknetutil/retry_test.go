@@ -0,0 +1,205 @@
+package starknetutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{Attempts: 4, Backoff: time.Millisecond}
+
+// transportErr is how starknet.go reports a dropped connection
+var transportErr = &rpc.RPCError{Code: -32603, Message: "Post \"http://localhost:5050\": read: connection reset by peer"}
+
+// flakyProvider fails the first failures requests of each kind with err, then succeeds. Its account nonce moves
+// with every invoke it accepts; acceptFailed makes it accept the invokes whose response it then fails
+type flakyProvider struct {
+	failures     int
+	err          error
+	calls        int
+	nonce        uint64
+	acceptFailed bool
+}
+
+func (f *flakyProvider) next() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyProvider) Call(context.Context, rpc.FunctionCall, rpc.BlockID) ([]*felt.Felt, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return []*felt.Felt{new(felt.Felt).SetUint64(18)}, nil
+}
+
+func (f *flakyProvider) Nonce(context.Context) (*felt.Felt, error) {
+	return new(felt.Felt).SetUint64(f.nonce), nil
+}
+
+func (f *flakyProvider) BuildAndSendInvokeTxn(context.Context, []rpc.InvokeFunctionCall, *account.TxnOptions) (rpc.AddInvokeTransactionResponse, error) {
+	if err := f.next(); err != nil {
+		if f.acceptFailed {
+			f.nonce++
+		}
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+	f.nonce++
+	return rpc.AddInvokeTransactionResponse{Hash: new(felt.Felt).SetUint64(0xabc)}, nil
+}
+
+func (f *flakyProvider) WaitForTransactionReceipt(context.Context, *felt.Felt, time.Duration) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &rpc.TransactionReceiptWithBlockInfo{}, nil
+}
+
+func TestCallWithRetry(t *testing.T) {
+	provider := &flakyProvider{failures: 2, err: transportErr}
+	resp, err := CallWithRetry(context.Background(), testRetryPolicy, provider, rpc.FunctionCall{}, rpc.WithBlockTag("latest"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(18), resp[0].Uint64())
+	assert.Equal(t, 3, provider.calls)
+
+	// The ERC20 helpers retry through RetryingCaller
+	provider = &flakyProvider{failures: 1, err: &rpc.RPCError{Code: 429, Message: "Too Many Requests"}}
+	decimals, err := ERC20Decimals(context.Background(), RetryingCaller(provider, testRetryPolicy), "0x1")
+	require.NoError(t, err)
+	assert.Equal(t, uint8(18), decimals)
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestBuildAndSendInvokeTxnWithRetry(t *testing.T) {
+	provider := &flakyProvider{failures: 3, err: transportErr}
+	resp, err := BuildAndSendInvokeTxnWithRetry(context.Background(), testRetryPolicy, provider, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0xabc), resp.Hash.Uint64())
+	assert.Equal(t, 4, provider.calls)
+
+	// Reverts are deterministic
+	revert := &rpc.RPCError{Code: 41, Message: "Transaction execution error"}
+	provider = &flakyProvider{failures: 1, err: revert}
+	_, err = BuildAndSendInvokeTxnWithRetry(context.Background(), testRetryPolicy, provider, nil, nil)
+	assert.ErrorIs(t, err, revert)
+	assert.Equal(t, 1, provider.calls)
+
+	// A response lost after the node accepted the invoke moves the nonce, so it is not sent again
+	provider = &flakyProvider{failures: 1, err: transportErr, acceptFailed: true}
+	_, err = BuildAndSendInvokeTxnWithRetry(context.Background(), testRetryPolicy, provider, nil, nil)
+	assert.ErrorIs(t, err, ErrInvokeMaybeSent)
+	assert.Equal(t, 1, provider.calls)
+	assert.Equal(t, uint64(1), provider.nonce, "sent once")
+
+	// Refused connections never reached the node
+	refused := errors.New(`Post "http://localhost:5050": dial tcp 127.0.0.1:5050: connect: connection refused`)
+	provider = &flakyProvider{failures: 2, err: refused}
+	_, err = BuildAndSendInvokeTxnWithRetry(context.Background(), testRetryPolicy, provider, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, provider.calls)
+}
+
+func TestIsNotSent(t *testing.T) {
+	tests := []struct {
+		err     error
+		notSent bool
+	}{
+		{nil, false},
+		{&rpc.RPCError{Code: 429, Message: "Too Many Requests"}, true},
+		{client.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, true},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{fmt.Errorf("send: %w", syscall.ECONNREFUSED), true},
+		{&rpc.RPCError{Code: -32603, Message: "Post \"http://localhost:5050\": dial tcp: lookup devnet: no such host"}, true},
+		{transportErr, false},
+		{client.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}, false},
+		{io.ErrUnexpectedEOF, false},
+		{&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.notSent, IsNotSent(tt.err), "%v", tt.err)
+	}
+}
+
+func TestWaitForTransactionReceiptWithRetry(t *testing.T) {
+	provider := &flakyProvider{failures: 10, err: transportErr}
+	_, err := WaitForTransactionReceiptWithRetry(context.Background(), testRetryPolicy, provider, new(felt.Felt).SetUint64(1), time.Millisecond)
+	assert.ErrorContains(t, err, "failed after 4 attempts")
+	assert.ErrorIs(t, err, transportErr)
+	assert.Equal(t, 4, provider.calls)
+
+	provider = &flakyProvider{failures: 1, err: transportErr}
+	receipt, err := WaitForTransactionReceiptWithRetry(context.Background(), testRetryPolicy, provider, new(felt.Felt).SetUint64(1), time.Millisecond)
+	require.NoError(t, err)
+	assert.NotNil(t, receipt)
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := Retry(ctx, RetryPolicy{Attempts: 5, Backoff: time.Hour}, "call", func(context.Context) (int, error) {
+		calls++
+		cancel()
+		return 0, transportErr
+	})
+	assert.ErrorIs(t, err, transportErr)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryZeroPolicy(t *testing.T) {
+	provider := &flakyProvider{failures: 1, err: transportErr}
+	_, err := CallWithRetry(context.Background(), RetryPolicy{}, provider, rpc.FunctionCall{}, rpc.WithBlockTag("latest"))
+	assert.Equal(t, transportErr, err, "a single attempt returns the error as is")
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for retry, base := range map[int]time.Duration{1: 100, 2: 200, 3: 300, 10: 300} {
+		delay := p.delay(retry)
+		assert.GreaterOrEqual(t, delay, base*time.Millisecond, "retry %d", retry)
+		assert.LessOrEqual(t, delay, base*time.Millisecond*3/2, "retry %d", retry)
+	}
+	assert.Zero(t, RetryPolicy{}.delay(1))
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{nil, false},
+		{transportErr, true},
+		{fmt.Errorf("failed to read balance: %w", transportErr), true},
+		{&rpc.RPCError{Code: 429, Message: "Too Many Requests"}, true},
+		{client.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, true},
+		{client.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}, true},
+		{client.HTTPError{StatusCode: 400, Status: "400 Bad Request"}, false},
+		{fmt.Errorf("dial: %w", syscall.ECONNRESET), true},
+		{io.ErrUnexpectedEOF, true},
+		{errors.New("read tcp: connection reset by peer"), true},
+		{&rpc.RPCError{Code: 40, Message: "Contract error"}, false},
+		{&rpc.RPCError{Code: 41, Message: "Transaction execution error"}, false},
+		{&rpc.RPCError{Code: 20, Message: "Contract not found"}, false},
+		{context.DeadlineExceeded, false},
+		{context.Canceled, false},
+		{errors.New("invalid token address"), false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.transient, IsTransient(tt.err), "%v", tt.err)
+	}
+}
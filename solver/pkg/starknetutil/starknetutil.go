@@ -8,10 +8,14 @@ package starknetutil
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/holiman/uint256"
@@ -48,8 +52,50 @@ var StarknetERC20ABI = map[string]string{
 	"approve":   "0x219209519083abdd73264e9d09587b6ac54c8e5965d30f081f327dc0d3ab5d2", // approve(spender: felt, amount: u256) -> ()
 }
 
+// ContractCaller is the read-only part of the Starknet RPC provider used by the ERC20 helpers
+type ContractCaller interface {
+	Call(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error)
+}
+
+// MaxU256 is the largest u256 value, used for unlimited allowances
+var MaxU256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ToU256 splits a big.Int into the low and high 128-bit felts of a Cairo u256
+func ToU256(value *big.Int) (low, high *felt.Felt) {
+	u := ToUint256(value)
+
+	// Create mask for lower 128 bits
+	lowerMask := uint256.NewInt(1)
+	lowerMask.Lsh(lowerMask, U128BitShift)
+	lowerMask.SubUint64(lowerMask, 1)
+
+	// Extract low and high parts
+	lowPart := new(uint256.Int)
+	lowPart.And(u, lowerMask)
+	highPart := new(uint256.Int)
+	highPart.Rsh(u, U128BitShift)
+
+	low = utils.BigIntToFelt(lowPart.ToBig())
+	high = utils.BigIntToFelt(highPart.ToBig())
+	return low, high
+}
+
+// FromU256 combines the low and high felts of a Cairo u256: (high << 128) | low
+func FromU256(low, high *felt.Felt) *big.Int {
+	return new(big.Int).Add(new(big.Int).Lsh(utils.FeltToBigInt(high), U128BitShift), utils.FeltToBigInt(low))
+}
+
+// PendingBlock reads the state including the transactions not yet in a block, which RPC 0.9 calls the
+// pre_confirmed block. Nodes on older specs reject it
+var PendingBlock = rpc.WithBlockTag(rpc.BlockTagPreConfirmed)
+
 // ERC20Balance gets the ERC20 token balance for a given address on Starknet
-func ERC20Balance(provider *rpc.Provider, tokenAddress, ownerAddress string) (*big.Int, error) {
+func ERC20Balance(ctx context.Context, provider ContractCaller, tokenAddress, ownerAddress string) (*big.Int, error) {
+	return ERC20BalanceAt(ctx, provider, tokenAddress, ownerAddress, rpc.WithBlockTag("latest"))
+}
+
+// ERC20BalanceAt gets the ERC20 token balance for a given address at block, such as PendingBlock
+func ERC20BalanceAt(ctx context.Context, provider ContractCaller, tokenAddress, ownerAddress string, block rpc.BlockID) (*big.Int, error) {
 	// Convert addresses to felt
 	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
 	if err != nil {
@@ -69,24 +115,78 @@ func ERC20Balance(provider *rpc.Provider, tokenAddress, ownerAddress string) (*b
 	}
 
 	// Call the contract to get balance
-	resp, err := provider.Call(context.Background(), balanceCall, rpc.WithBlockTag("latest"))
+	resp, err := provider.Call(ctx, balanceCall, block)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call balanceOf: %w", err)
 	}
 
-	if len(resp) == 0 {
-		return nil, fmt.Errorf("no response from balanceOf call")
+	// balanceOf returns a u256 as (low, high); reading only low truncates balances >= 2^128
+	if len(resp) < 2 {
+		return nil, fmt.Errorf("invalid balanceOf result length: expected 2 felts, got %d", len(resp))
 	}
 
-	// Convert felt response to big.Int
-	balanceFelt := resp[0]
-	balanceBigInt := utils.FeltToBigInt(balanceFelt)
+	return FromU256(resp[0], resp[1]), nil
+}
 
-	return balanceBigInt, nil
+// Defaults for WaitForERC20BalanceChange
+const (
+	DefaultBalancePollTimeout  = 30 * time.Second
+	DefaultBalancePollInterval = 500 * time.Millisecond
+)
+
+// BalancePollOptions controls how WaitForERC20BalanceChange polls; zero values use the defaults
+type BalancePollOptions struct {
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// WaitForERC20BalanceChange re-reads an ERC20 balance until it differs from previous or the timeout expires.
+// Read errors are retried until the timeout
+func WaitForERC20BalanceChange(
+	ctx context.Context,
+	provider ContractCaller,
+	tokenAddress, ownerAddress string,
+	previous *big.Int,
+	opts BalancePollOptions,
+) (*big.Int, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultBalancePollTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultBalancePollInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		balance, err := ERC20Balance(ctx, provider, tokenAddress, ownerAddress)
+		if err == nil && balance.Cmp(previous) != 0 {
+			return balance, nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("balance of %s did not change from %s within %s: %w", ownerAddress, previous, opts.Timeout, lastErr)
+			}
+			return balance, fmt.Errorf("balance of %s did not change from %s within %s", ownerAddress, previous, opts.Timeout)
+		case <-ticker.C:
+		}
+	}
 }
 
 // ERC20Allowance gets the ERC20 token allowance for a given owner and spender on Starknet
-func ERC20Allowance(provider *rpc.Provider, tokenAddress, ownerAddress, spenderAddress string) (*big.Int, error) {
+func ERC20Allowance(ctx context.Context, provider ContractCaller, tokenAddress, ownerAddress, spenderAddress string) (*big.Int, error) {
+	return ERC20AllowanceAt(ctx, provider, tokenAddress, ownerAddress, spenderAddress, rpc.WithBlockTag("latest"))
+}
+
+// ERC20AllowanceAt gets the ERC20 token allowance for a given owner and spender at block, such as PendingBlock
+func ERC20AllowanceAt(ctx context.Context, provider ContractCaller, tokenAddress, ownerAddress, spenderAddress string, block rpc.BlockID) (*big.Int, error) {
 	// Convert addresses to felt
 	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
 	if err != nil {
@@ -111,7 +211,7 @@ func ERC20Allowance(provider *rpc.Provider, tokenAddress, ownerAddress, spenderA
 	}
 
 	// Call the contract to get allowance
-	resp, err := provider.Call(context.Background(), allowanceCall, rpc.WithBlockTag("latest"))
+	resp, err := provider.Call(ctx, allowanceCall, block)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call allowance: %w", err)
 	}
@@ -120,20 +220,7 @@ func ERC20Allowance(provider *rpc.Provider, tokenAddress, ownerAddress, spenderA
 		return nil, fmt.Errorf("invalid allowance result length: expected 2 felts, got %d", len(resp))
 	}
 
-	// Convert two felts (low, high) back to u256
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-
-	// Use uint256 for better performance
-	lowU := ToUint256(low)
-	highU := ToUint256(high)
-
-	// Combine low and high into u256: (high << 128) | low
-	result := new(uint256.Int)
-	result.Lsh(highU, U128BitShift)
-	result.Or(result, lowU)
-
-	return result.ToBig(), nil
+	return FromU256(resp[0], resp[1]), nil
 }
 
 // ERC20Approve creates an approve transaction for ERC20 tokens on Starknet
@@ -149,35 +236,141 @@ func ERC20Approve(tokenAddress, spenderAddress string, amount *big.Int) (*rpc.In
 		return nil, fmt.Errorf("invalid spender address: %w", err)
 	}
 
-	// Convert amount to two felts (low, high) for u256 using uint256 for better performance
-	amountU := ToUint256(amount)
+	lowFelt, highFelt := ToU256(amount)
 
-	// Create mask for lower 128 bits
-	lowerMask := uint256.NewInt(1)
-	lowerMask.Lsh(lowerMask, U128BitShift)
-	lowerMask.SubUint64(lowerMask, 1)
+	// Build approve calldata: approve(spender: felt, amount: u256)
+	invoke := rpc.InvokeFunctionCall{
+		ContractAddress: tokenAddrFelt,
+		FunctionName:    "approve",
+		CallData:        []*felt.Felt{spenderAddrFelt, lowFelt, highFelt},
+	}
 
-	// Extract low and high parts
-	low128 := new(uint256.Int)
-	low128.And(amountU, lowerMask)
-	high128 := new(uint256.Int)
-	high128.Rsh(amountU, U128BitShift)
+	return &invoke, nil
+}
 
-	lowFelt := utils.BigIntToFelt(low128.ToBig())
-	highFelt := utils.BigIntToFelt(high128.ToBig())
+// ERC20Mint creates a mint transaction for mock ERC20 tokens on Starknet
+func ERC20Mint(tokenAddress, recipientAddress string, amount *big.Int) (*rpc.InvokeFunctionCall, error) {
+	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token address: %w", err)
+	}
 
-	// Build approve calldata: approve(spender: felt, amount: u256)
-	approveCalldata := []*felt.Felt{spenderAddrFelt, lowFelt, highFelt}
+	recipientAddrFelt, err := utils.HexToFelt(recipientAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	lowFelt, highFelt := ToU256(amount)
 
+	// Build mint calldata: mint(to: ContractAddress, amount: u256)
 	invoke := rpc.InvokeFunctionCall{
 		ContractAddress: tokenAddrFelt,
-		FunctionName:    "approve",
-		CallData:        approveCalldata,
+		FunctionName:    "mint",
+		CallData:        []*felt.Felt{recipientAddrFelt, lowFelt, highFelt},
 	}
 
 	return &invoke, nil
 }
 
+// ApproveMax sends an unlimited approve from accnt and returns the transaction hash without waiting
+func ApproveMax(ctx context.Context, accnt *account.Account, tokenAddress, spenderAddress string) (*felt.Felt, error) {
+	approveCall, err := ERC20Approve(tokenAddress, spenderAddress, MaxU256)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := accnt.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{*approveCall}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send approve transaction: %w", err)
+	}
+	return resp.Hash, nil
+}
+
+// Mint sends a mock ERC20 mint from accnt and returns the transaction hash without waiting
+func Mint(ctx context.Context, accnt *account.Account, tokenAddress, recipientAddress string, amount *big.Int) (*felt.Felt, error) {
+	mintCall, err := ERC20Mint(tokenAddress, recipientAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := accnt.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{*mintCall}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send mint transaction: %w", err)
+	}
+	return resp.Hash, nil
+}
+
+// ERC20Decimals reads decimals() from a Starknet ERC20 token
+func ERC20Decimals(ctx context.Context, provider ContractCaller, tokenAddress string) (uint8, error) {
+	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token address: %w", err)
+	}
+
+	decimalsCall := rpc.FunctionCall{
+		ContractAddress:    tokenAddrFelt,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("decimals"),
+		Calldata:           []*felt.Felt{},
+	}
+
+	resp, err := provider.Call(ctx, decimalsCall, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to call decimals: %w", err)
+	}
+
+	if len(resp) == 0 {
+		return 0, fmt.Errorf("no response from decimals call")
+	}
+
+	// decimals() returns a u8
+	decimals := utils.FeltToBigInt(resp[0])
+	if !decimals.IsUint64() || decimals.Uint64() > math.MaxUint8 {
+		return 0, fmt.Errorf("decimals %s out of range for u8", decimals.String())
+	}
+
+	return uint8(decimals.Uint64()), nil
+}
+
+// DecimalsCache caches decimals() per token address; use one cache per network
+type DecimalsCache struct {
+	mu       sync.Mutex
+	decimals map[string]uint8
+}
+
+// NewDecimalsCache creates an empty DecimalsCache
+func NewDecimalsCache() *DecimalsCache {
+	return &DecimalsCache{decimals: make(map[string]uint8)}
+}
+
+// Decimals returns the cached decimals of a token, reading them from the contract on first use
+func (c *DecimalsCache) Decimals(ctx context.Context, provider ContractCaller, tokenAddress string) (uint8, error) {
+	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token address: %w", err)
+	}
+	key := tokenAddrFelt.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if decimals, ok := c.decimals[key]; ok {
+		return decimals, nil
+	}
+
+	decimals, err := ERC20Decimals(ctx, provider, key)
+	if err != nil {
+		return 0, err
+	}
+	c.decimals[key] = decimals
+	return decimals, nil
+}
+
+// ScaleTokenAmount converts a whole-token amount into base units for the given decimals
+func ScaleTokenAmount(tokens *big.Int, decimals uint8) *big.Int {
+	multiplier := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Int).Mul(tokens, multiplier)
+}
+
 // FormatTokenAmount formats a token amount for display (converts from wei to tokens)
 // Uses the shared utility function from types package
 func FormatTokenAmount(amount *big.Int, decimals int) string {
@@ -185,25 +378,10 @@ func FormatTokenAmount(amount *big.Int, decimals int) string {
 }
 
 // ConvertBigIntToU256Felts converts a big.Int to two felts, one for the low 128 bits and one for the high 128 bits
+//
+// Deprecated: use ToU256.
 func ConvertBigIntToU256Felts(value *big.Int) (low, high *felt.Felt) {
-	// Convert to uint256 for better performance
-	u := ToUint256(value)
-
-	// Create mask for lower 128 bits
-	lowerMask := uint256.NewInt(1)
-	lowerMask.Lsh(lowerMask, U128BitShift)
-	lowerMask.SubUint64(lowerMask, 1)
-
-	// Extract low and high parts
-	lowPart := new(uint256.Int)
-	lowPart.And(u, lowerMask)
-	highPart := new(uint256.Int)
-	highPart.Rsh(u, U128BitShift)
-
-	// Convert back to felts
-	low = utils.BigIntToFelt(lowPart.ToBig())
-	high = utils.BigIntToFelt(highPart.ToBig())
-	return low, high
+	return ToU256(value)
 }
 
 // ConvertSolidityOrderIDForStarknet converts a Solidity-style orderID (bytes32) into the low and high felts of a Starknet u256 orderID
@@ -246,3 +424,69 @@ func BytesToU128Felts(b []byte) []*felt.Felt {
 	}
 	return words
 }
+
+// ToCairoBytes wraps raw bytes into Cairo Bytes calldata: size, words_len, then big-endian u128 words. The final
+// partial word is left-aligned in its 16 bytes and zero padded
+func ToCairoBytes(b []byte) []*felt.Felt {
+	words := BytesToU128Felts(b)
+	out := make([]*felt.Felt, 0, 2+len(words))
+	out = append(out, utils.Uint64ToFelt(uint64(len(b))), utils.Uint64ToFelt(uint64(len(words))))
+	return append(out, words...)
+}
+
+// FromCairoBytes decodes Cairo Bytes (size, words_len, u128 words) as returned by a view call back into raw bytes.
+// It is the inverse of ToCairoBytes: words_len must be exactly the words size needs, every word must fit a u128
+// and the padding of the final partial word must be zero
+func FromCairoBytes(felts []*felt.Felt) ([]byte, error) {
+	if len(felts) < 2 {
+		return nil, fmt.Errorf("cairo bytes too short: %d felts", len(felts))
+	}
+	size, err := cairoBytesLength("size", felts[0])
+	if err != nil {
+		return nil, err
+	}
+	wordsLen, err := cairoBytesLength("words_len", felts[1])
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(felts)-2) != wordsLen {
+		return nil, fmt.Errorf("cairo bytes declares %d words but has %d", wordsLen, len(felts)-2)
+	}
+	if size > wordsLen*Bytes16Length {
+		return nil, fmt.Errorf("cairo bytes size %d exceeds %d words", size, wordsLen)
+	}
+	if needed := (size + Bytes16Length - 1) / Bytes16Length; wordsLen != needed {
+		return nil, fmt.Errorf("cairo bytes has %d words but size %d needs %d", wordsLen, size, needed)
+	}
+
+	out := make([]byte, 0, wordsLen*Bytes16Length)
+	for i, word := range felts[2:] {
+		b := word.Bytes()
+		if !isZero(b[:Bytes32Length-Bytes16Length]) {
+			return nil, fmt.Errorf("cairo bytes word %d (%s) does not fit in u128", i, word.String())
+		}
+		out = append(out, b[Bytes32Length-Bytes16Length:]...)
+	}
+	if !isZero(out[size:]) {
+		return nil, fmt.Errorf("cairo bytes has non-zero padding past size %d in its last word", size)
+	}
+	return out[:size], nil
+}
+
+// cairoBytesLength reads the size or words_len felt of Cairo Bytes
+func cairoBytesLength(name string, f *felt.Felt) (uint64, error) {
+	n := f.BigInt(new(big.Int))
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("cairo bytes %s %s is not a valid length", name, f.String())
+	}
+	return n.Uint64(), nil
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
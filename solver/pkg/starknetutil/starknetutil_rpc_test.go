@@ -1,6 +1,7 @@
 package starknetutil
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"os"
@@ -75,7 +76,7 @@ func testStarknetERC20Functions(t *testing.T, provider *rpc.Provider, isDevnet b
 	}
 
 	t.Run("ERC20Balance", func(t *testing.T) {
-		balance, err := ERC20Balance(provider, tokenAddress, aliceAddress)
+		balance, err := ERC20Balance(context.Background(), provider, tokenAddress, aliceAddress)
 		require.NoError(t, err)
 		assert.True(t, balance.Cmp(big.NewInt(0)) >= 0, "Balance should be non-negative")
 	})
@@ -88,7 +89,7 @@ func testStarknetERC20Functions(t *testing.T, provider *rpc.Provider, isDevnet b
 			return
 		}
 
-		allowance, err := ERC20Allowance(provider, tokenAddress, aliceAddress, hyperlaneAddress)
+		allowance, err := ERC20Allowance(context.Background(), provider, tokenAddress, aliceAddress, hyperlaneAddress)
 		require.NoError(t, err)
 		assert.True(t, allowance.Cmp(big.NewInt(0)) >= 0, "Allowance should be non-negative")
 	})
@@ -1,10 +1,15 @@
 package starknetutil
 
 import (
+	"context"
+	"errors"
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
@@ -143,6 +148,58 @@ func TestBytesToU128Felts(t *testing.T) {
 	}
 }
 
+func TestCairoBytesRoundTrip(t *testing.T) {
+	sizes := []int{449}
+	for size := 0; size <= 100; size++ {
+		sizes = append(sizes, size)
+	}
+	for _, size := range sizes {
+		raw := make([]byte, size)
+		for i := range raw {
+			// 0xff-heavy bytes so a misplaced or sign-extended padding byte shows up
+			raw[i] = byte(0xff - i)
+		}
+
+		encoded := ToCairoBytes(raw)
+		require.Len(t, encoded, 2+(size+Bytes16Length-1)/Bytes16Length)
+		assert.Equal(t, uint64(size), encoded[0].Uint64())
+
+		decoded, err := FromCairoBytes(encoded)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, raw, decoded, "size %d", size)
+	}
+}
+
+func TestFromCairoBytesErrors(t *testing.T) {
+	_, err := FromCairoBytes([]*felt.Felt{utils.Uint64ToFelt(1)})
+	assert.ErrorContains(t, err, "too short")
+
+	// words_len does not match the number of words
+	_, err = FromCairoBytes([]*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(2), utils.Uint64ToFelt(0)})
+	assert.ErrorContains(t, err, "declares 2 words")
+
+	// size larger than the words can hold
+	_, err = FromCairoBytes([]*felt.Felt{utils.Uint64ToFelt(17), utils.Uint64ToFelt(1), utils.Uint64ToFelt(0)})
+	assert.ErrorContains(t, err, "exceeds")
+
+	// more words than size needs
+	_, err = FromCairoBytes([]*felt.Felt{utils.Uint64ToFelt(16), utils.Uint64ToFelt(2), utils.Uint64ToFelt(0), utils.Uint64ToFelt(0)})
+	assert.EqualError(t, err, "cairo bytes has 2 words but size 16 needs 1")
+
+	// a length that does not fit uint64
+	huge := utils.BigIntToFelt(new(big.Int).Lsh(big.NewInt(1), 64))
+	_, err = FromCairoBytes([]*felt.Felt{huge, utils.Uint64ToFelt(0)})
+	assert.ErrorContains(t, err, "cairo bytes size 0x10000000000000000 is not a valid length")
+
+	// a word wider than u128
+	_, err = FromCairoBytes([]*felt.Felt{utils.Uint64ToFelt(16), utils.Uint64ToFelt(1), utils.BigIntToFelt(new(big.Int).Lsh(big.NewInt(1), 128))})
+	assert.ErrorContains(t, err, "cairo bytes word 0 (0x100000000000000000000000000000000) does not fit in u128")
+
+	// a right-aligned partial word leaves its bytes in the padding
+	_, err = FromCairoBytes([]*felt.Felt{utils.Uint64ToFelt(2), utils.Uint64ToFelt(1), utils.Uint64ToFelt(0x0102)})
+	assert.EqualError(t, err, "cairo bytes has non-zero padding past size 2 in its last word")
+}
+
 func TestConvertSolidityOrderIDForStarknet(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -481,3 +538,226 @@ func TestUint256Operations(t *testing.T) {
 		assert.Equal(t, 0, small.Cmp(new(uint256.Int).Set(small)))
 	})
 }
+
+func TestToU256FromU256(t *testing.T) {
+	two128 := new(big.Int).Lsh(big.NewInt(1), 128)
+	maxU128 := new(big.Int).Sub(two128, big.NewInt(1))
+
+	tests := []struct {
+		name  string
+		value *big.Int
+		low   *big.Int
+		high  *big.Int
+	}{
+		{name: "zero", value: big.NewInt(0), low: big.NewInt(0), high: big.NewInt(0)},
+		{name: "one", value: big.NewInt(1), low: big.NewInt(1), high: big.NewInt(0)},
+		{name: "max_u128", value: maxU128, low: maxU128, high: big.NewInt(0)},
+		{name: "two_pow_128", value: two128, low: big.NewInt(0), high: big.NewInt(1)},
+		{name: "two_pow_128_plus_max_u128", value: new(big.Int).Add(two128, maxU128), low: maxU128, high: big.NewInt(1)},
+		// 2^252 and above do not fit in a single felt (P ≈ 2^251)
+		{name: "overflows_felt", value: new(big.Int).Lsh(big.NewInt(1), 252), low: big.NewInt(0), high: new(big.Int).Lsh(big.NewInt(1), 124)},
+		{name: "max_u256", value: MaxU256, low: maxU128, high: maxU128},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high := ToU256(tt.value)
+			assert.Equal(t, 0, tt.low.Cmp(utils.FeltToBigInt(low)), "low")
+			assert.Equal(t, 0, tt.high.Cmp(utils.FeltToBigInt(high)), "high")
+			assert.Equal(t, 0, tt.value.Cmp(FromU256(low, high)), "round trip")
+		})
+	}
+}
+
+// stubCaller returns a fixed response and records the last call
+type stubCaller struct {
+	resp []*felt.Felt
+	err  error
+	call rpc.FunctionCall
+}
+
+func (s *stubCaller) Call(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	s.call = call
+	return s.resp, s.err
+}
+
+func TestERC20AllowanceStub(t *testing.T) {
+	low, high := ToU256(MaxU256)
+	caller := &stubCaller{resp: []*felt.Felt{low, high}}
+
+	allowance, err := ERC20Allowance(context.Background(), caller, "0x1", "0x2", "0x3")
+	require.NoError(t, err)
+	assert.Equal(t, 0, MaxU256.Cmp(allowance))
+	assert.True(t, caller.call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt("allowance")))
+	require.Len(t, caller.call.Calldata, 2)
+
+	caller.resp = caller.resp[:1]
+	_, err = ERC20Allowance(context.Background(), caller, "0x1", "0x2", "0x3")
+	assert.Error(t, err)
+
+	caller.err = errors.New("rpc down")
+	_, err = ERC20Allowance(context.Background(), caller, "0x1", "0x2", "0x3")
+	assert.Error(t, err)
+}
+
+func TestERC20BalanceStub(t *testing.T) {
+	caller := &stubCaller{resp: []*felt.Felt{new(felt.Felt).SetUint64(1000), new(felt.Felt)}}
+
+	balance, err := ERC20Balance(context.Background(), caller, "0x1", "0x2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), balance.Int64())
+	assert.True(t, caller.call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt("balanceOf")))
+
+	_, err = ERC20Balance(context.Background(), caller, "invalid", "0x2")
+	assert.Error(t, err)
+}
+
+func TestERC20BalanceHighWord(t *testing.T) {
+	// 2^129 + 7: low = 7, high = 2; the high word must not be dropped
+	expected := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 129), big.NewInt(7))
+	low, high := ToU256(expected)
+	caller := &stubCaller{resp: []*felt.Felt{low, high}}
+
+	balance, err := ERC20Balance(context.Background(), caller, "0x1", "0x2")
+	require.NoError(t, err)
+	assert.Equal(t, 0, expected.Cmp(balance), "expected %s, got %s", expected, balance)
+
+	// A single-felt response is malformed for a u256 return value
+	caller.resp = []*felt.Felt{low}
+	_, err = ERC20Balance(context.Background(), caller, "0x1", "0x2")
+	assert.Error(t, err)
+}
+
+func TestERC20Mint(t *testing.T) {
+	amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(3), 128), big.NewInt(5))
+
+	call, err := ERC20Mint("0x1234", "0x5678", amount)
+	require.NoError(t, err)
+	assert.Equal(t, "mint", call.FunctionName)
+	assert.Equal(t, "0x1234", call.ContractAddress.String())
+	require.Len(t, call.CallData, 3)
+	assert.Equal(t, "0x5678", call.CallData[0].String())
+	assert.Equal(t, uint64(5), call.CallData[1].Uint64())
+	assert.Equal(t, uint64(3), call.CallData[2].Uint64())
+
+	_, err = ERC20Mint("invalid", "0x5678", amount)
+	assert.Error(t, err)
+	_, err = ERC20Mint("0x1234", "invalid", amount)
+	assert.Error(t, err)
+}
+
+// countingCaller returns a fixed response and counts calls
+type countingCaller struct {
+	resp  []*felt.Felt
+	calls int
+}
+
+func (c *countingCaller) Call(_ context.Context, _ rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	c.calls++
+	return c.resp, nil
+}
+
+func TestTokenDecimals(t *testing.T) {
+	tests := []struct {
+		name      string
+		decimals  uint8
+		raw       string // 1234.5678 tokens in base units
+		formatted string
+	}{
+		{name: "usdc_style_6", decimals: 6, raw: "1234567800", formatted: "1234.57 tokens"},
+		{name: "wbtc_style_8", decimals: 8, raw: "123456780000", formatted: "1234.57 tokens"},
+		{name: "standard_18", decimals: 18, raw: "1234567800000000000000", formatted: "1234.57 tokens"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := &stubCaller{resp: []*felt.Felt{new(felt.Felt).SetUint64(uint64(tt.decimals))}}
+			decimals, err := ERC20Decimals(context.Background(), caller, "0x1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.decimals, decimals)
+			assert.True(t, caller.call.EntryPointSelector.Equal(utils.GetSelectorFromNameFelt("decimals")))
+
+			raw, ok := new(big.Int).SetString(tt.raw, 10)
+			require.True(t, ok)
+			assert.Equal(t, tt.formatted, FormatTokenAmount(raw, int(decimals)))
+
+			// 100000 whole tokens scale to 100000 * 10^decimals base units
+			expected := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tt.decimals)), nil)
+			expected.Mul(expected, big.NewInt(100000))
+			assert.Equal(t, 0, expected.Cmp(ScaleTokenAmount(big.NewInt(100000), decimals)))
+		})
+	}
+}
+
+func TestERC20DecimalsErrors(t *testing.T) {
+	_, err := ERC20Decimals(context.Background(), &stubCaller{}, "0x1")
+	assert.Error(t, err, "empty response")
+
+	_, err = ERC20Decimals(context.Background(), &stubCaller{resp: []*felt.Felt{new(felt.Felt).SetUint64(256)}}, "0x1")
+	assert.Error(t, err, "out of u8 range")
+
+	_, err = ERC20Decimals(context.Background(), &stubCaller{}, "invalid")
+	assert.Error(t, err, "invalid address")
+}
+
+func TestDecimalsCache(t *testing.T) {
+	caller := &countingCaller{resp: []*felt.Felt{new(felt.Felt).SetUint64(6)}}
+	cache := NewDecimalsCache()
+
+	for _, addr := range []string{"0x1", "0x01", "0x0001"} {
+		decimals, err := cache.Decimals(context.Background(), caller, addr)
+		require.NoError(t, err)
+		assert.Equal(t, uint8(6), decimals)
+	}
+	assert.Equal(t, 1, caller.calls, "equivalent addresses share one cache entry")
+
+	_, err := cache.Decimals(context.Background(), caller, "0x2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, caller.calls)
+}
+
+// laggingCaller reports the old balance for the first staleCalls reads, then the new one
+type laggingCaller struct {
+	oldBalance, newBalance *big.Int
+	staleCalls             int
+	calls                  int
+}
+
+func (c *laggingCaller) Call(_ context.Context, _ rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+	c.calls++
+	balance := c.newBalance
+	if c.calls <= c.staleCalls {
+		balance = c.oldBalance
+	}
+	low, high := ToU256(balance)
+	return []*felt.Felt{low, high}, nil
+}
+
+func TestWaitForERC20BalanceChange(t *testing.T) {
+	opts := BalancePollOptions{Timeout: time.Second, PollInterval: time.Millisecond}
+
+	t.Run("returns new balance after stale reads", func(t *testing.T) {
+		caller := &laggingCaller{oldBalance: big.NewInt(1000), newBalance: big.NewInt(900), staleCalls: 3}
+		balance, err := WaitForERC20BalanceChange(context.Background(), caller, "0x1", "0x2", big.NewInt(1000), opts)
+		require.NoError(t, err)
+		assert.Equal(t, int64(900), balance.Int64())
+		assert.Equal(t, 4, caller.calls)
+	})
+
+	t.Run("times out when balance never changes", func(t *testing.T) {
+		caller := &laggingCaller{oldBalance: big.NewInt(1000), newBalance: big.NewInt(900), staleCalls: 1 << 30}
+		shortOpts := BalancePollOptions{Timeout: 20 * time.Millisecond, PollInterval: time.Millisecond}
+		balance, err := WaitForERC20BalanceChange(context.Background(), caller, "0x1", "0x2", big.NewInt(1000), shortOpts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not change")
+		assert.Equal(t, int64(1000), balance.Int64())
+	})
+
+	t.Run("read errors are retried and reported on timeout", func(t *testing.T) {
+		caller := &stubCaller{err: errors.New("rpc down")}
+		shortOpts := BalancePollOptions{Timeout: 20 * time.Millisecond, PollInterval: time.Millisecond}
+		_, err := WaitForERC20BalanceChange(context.Background(), caller, "0x1", "0x2", big.NewInt(1000), shortOpts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rpc down")
+	})
+}
@@ -0,0 +1,88 @@
+package tokenspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+// MockERC20Artifact is the Forge build output of solidity/src/MockERC20.sol, relative to the solver directory
+const MockERC20Artifact = "../solidity/out/MockERC20.sol/MockERC20.json"
+
+// mockERC20Constructor is MockERC20's constructor(string name_, string symbol_, uint8 decimals_)
+var mockERC20Constructor = mustConstructorArgs("string", "string", "uint8")
+
+func mustConstructorArgs(types ...string) abi.Arguments {
+	args := make(abi.Arguments, len(types))
+	for i, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return args
+}
+
+// LoadMockERC20Bytecode reads the MockERC20 creation bytecode from the Forge artifact at path
+func LoadMockERC20Bytecode(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the MockERC20 artifact (run forge build in solidity/): %w", err)
+	}
+	var artifact struct {
+		Bytecode struct {
+			Object string `json:"object"`
+		} `json:"bytecode"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("MockERC20 artifact %s is not a Forge artifact: %w", path, err)
+	}
+	object := strings.TrimPrefix(artifact.Bytecode.Object, "0x")
+	if object == "" {
+		return nil, fmt.Errorf("MockERC20 artifact %s has no bytecode", path)
+	}
+	return common.FromHex(object), nil
+}
+
+// ERC20InitCode returns the init code deploying spec: the MockERC20 creation bytecode followed by the encoded
+// constructor arguments
+func ERC20InitCode(bytecode []byte, spec TokenSpec) ([]byte, error) {
+	args, err := ERC20ConstructorArgs(spec)
+	if err != nil {
+		return nil, err
+	}
+	initCode := make([]byte, 0, len(bytecode)+len(args))
+	initCode = append(initCode, bytecode...)
+	return append(initCode, args...), nil
+}
+
+// ERC20ConstructorArgs returns spec's encoded MockERC20 constructor arguments, as appended to the creation code
+func ERC20ConstructorArgs(spec TokenSpec) ([]byte, error) {
+	args, err := mockERC20Constructor.Pack(spec.Name, spec.Symbol, spec.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the %s constructor arguments: %w", spec.Name, err)
+	}
+	return args, nil
+}
+
+// PredictERC20Address returns the address spec is deployed to with salt through the CREATE2 proxy, on every EVM
+// network. The initial supply is minted after the deploy, so it does not change the address
+func PredictERC20Address(spec TokenSpec, salt [32]byte) (common.Address, error) {
+	bytecode, err := LoadMockERC20Bytecode(solverdir.Path(MockERC20Artifact))
+	if err != nil {
+		return common.Address{}, err
+	}
+	initCode, err := ERC20InitCode(bytecode, spec)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return ethutil.Create2Address(salt, initCode), nil
+}
@@ -0,0 +1,61 @@
+package tokenspec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+)
+
+func TestERC20InitCode(t *testing.T) {
+	bytecode := []byte{0x60, 0x80, 0x60, 0x40}
+	dog := TokenSpec{Name: "DogCoin", Symbol: "DOG", Decimals: 18}
+
+	initCode, err := ERC20InitCode(bytecode, dog)
+	require.NoError(t, err)
+	assert.Equal(t, bytecode, initCode[:len(bytecode)])
+
+	args, err := mockERC20Constructor.Unpack(initCode[len(bytecode):])
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"DogCoin", "DOG", uint8(18)}, args)
+}
+
+func TestPredictERC20Address(t *testing.T) {
+	root := t.TempDir()
+	solver := filepath.Join(root, "solver")
+	require.NoError(t, os.MkdirAll(solver, 0o755))
+	t.Chdir(solver)
+	salt, err := ethutil.ParseSalt("1")
+	require.NoError(t, err)
+	dog := TokenSpec{Name: "DogCoin", Symbol: "DOG", Decimals: 18}
+
+	_, err = PredictERC20Address(dog, salt)
+	assert.ErrorContains(t, err, "run forge build")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(MockERC20Artifact), 0o755))
+	require.NoError(t, os.WriteFile(MockERC20Artifact, []byte(`{"bytecode": {"object": "0x60806040"}}`), 0o600))
+
+	address, err := PredictERC20Address(dog, salt)
+	require.NoError(t, err)
+	initCode, err := ERC20InitCode([]byte{0x60, 0x80, 0x60, 0x40}, dog)
+	require.NoError(t, err)
+	assert.Equal(t, ethutil.Create2Address(salt, initCode), address)
+
+	supplied := dog
+	supplied.InitialSupply = dog.Supply().SetUint64(1000)
+	same, err := PredictERC20Address(supplied, salt)
+	require.NoError(t, err)
+	assert.Equal(t, address, same, "the initial supply does not move the token")
+
+	orca, err := PredictERC20Address(TokenSpec{Name: "OrcaCoin", Symbol: "ORCA", Decimals: 18}, salt)
+	require.NoError(t, err)
+	assert.NotEqual(t, address, orca, "each token of a salt gets its own address")
+
+	require.NoError(t, os.WriteFile(MockERC20Artifact, []byte(`{"bytecode": {"object": "0x"}}`), 0o600))
+	_, err = PredictERC20Address(dog, salt)
+	assert.ErrorContains(t, err, "has no bytecode")
+}
@@ -0,0 +1,174 @@
+// Package tokenspec describes the mock ERC20 tokens the deploy tools create on every network, and where their
+// deployed addresses are recorded.
+//
+// The token set is read from deploy-tokens.json (DEPLOY_TOKENS_FILE overrides the path) and defaults to DogCoin:
+//
+//	[{"name": "DogCoin", "symbol": "DOG", "decimals": 18},
+//	 {"name": "OrcaCoin", "symbol": "ORCA", "decimals": 18, "initialSupply": 1000000000000000000000000}]
+//
+// A deployed token is found through <NETWORK>_<NAME>_ADDRESS in .env (DogCoin on Base is
+// BASE_DOG_COIN_ADDRESS) or, failing that, under its name in the network's deployment state (see deploystate.State)
+package tokenspec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+// DefaultPath is where the token set is read from, relative to the solver directory
+const DefaultPath = "deploy-tokens.json"
+
+// maxDecimals keeps 10^decimals within a uint256
+const maxDecimals = 77
+
+// TokenSpec is one mock ERC20 to deploy. InitialSupply (in base units) is minted to the deployer
+type TokenSpec struct {
+	Name          string   `json:"name"`
+	Symbol        string   `json:"symbol"`
+	Decimals      uint8    `json:"decimals"`
+	InitialSupply *big.Int `json:"initialSupply,omitempty"`
+}
+
+// Defaults is the token set without a deploy-tokens.json
+func Defaults() []TokenSpec {
+	return []TokenSpec{{Name: "DogCoin", Symbol: "DOG", Decimals: 18}}
+}
+
+// Supply returns InitialSupply, zero when unset
+func (s TokenSpec) Supply() *big.Int {
+	if s.InitialSupply == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(s.InitialSupply)
+}
+
+// Path returns the token set file: DEPLOY_TOKENS_FILE, or DefaultPath in the solver directory
+func Path() string {
+	return envutil.GetEnvWithDefault("DEPLOY_TOKENS_FILE", solverdir.Path(DefaultPath))
+}
+
+// Load reads the token set at path, or returns Defaults when the file does not exist
+func Load(path string) ([]TokenSpec, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Defaults(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token specs: %w", err)
+	}
+	var specs []TokenSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("token spec file %s is not a JSON list of tokens: %w", path, err)
+	}
+	if err := Validate(specs); err != nil {
+		return nil, fmt.Errorf("invalid token specs in %s: %w", path, err)
+	}
+	return specs, nil
+}
+
+// Validate checks every spec is complete and no two specs share a name or symbol
+func Validate(specs []TokenSpec) error {
+	if len(specs) == 0 {
+		return fmt.Errorf("no tokens defined")
+	}
+	names := make(map[string]bool, len(specs))
+	symbols := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		switch {
+		case strings.TrimSpace(spec.Name) == "":
+			return fmt.Errorf("token without a name")
+		case strings.TrimSpace(spec.Symbol) == "":
+			return fmt.Errorf("token %s has no symbol", spec.Name)
+		case spec.Decimals > maxDecimals:
+			return fmt.Errorf("token %s has %d decimals, at most %d are supported", spec.Name, spec.Decimals, maxDecimals)
+		case spec.InitialSupply != nil && spec.InitialSupply.Sign() < 0:
+			return fmt.Errorf("token %s has a negative initial supply", spec.Name)
+		case names[strings.ToLower(spec.Name)]:
+			return fmt.Errorf("duplicate token name %s", spec.Name)
+		case symbols[strings.ToLower(spec.Symbol)]:
+			return fmt.Errorf("duplicate token symbol %s", spec.Symbol)
+		}
+		names[strings.ToLower(spec.Name)] = true
+		symbols[strings.ToLower(spec.Symbol)] = true
+	}
+	return nil
+}
+
+// Find returns the spec whose name or symbol is token, ignoring case
+func Find(specs []TokenSpec, token string) (TokenSpec, bool) {
+	for _, spec := range specs {
+		if strings.EqualFold(spec.Name, token) || strings.EqualFold(spec.Symbol, token) {
+			return spec, true
+		}
+	}
+	return TokenSpec{}, false
+}
+
+// Names returns the names of specs in order
+func Names(specs []TokenSpec) []string {
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.Name
+	}
+	return names
+}
+
+// EnvName returns the .env variable holding a token's address on a network, e.g. BASE_DOG_COIN_ADDRESS
+func EnvName(networkName, token string) string {
+	var b strings.Builder
+	runes := []rune(token)
+	for i, r := range runes {
+		// Split CamelCase names into words: DogCoin -> DOG_COIN
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			b.WriteByte('_')
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return strings.ToUpper(networkName) + "_" + b.String() + "_ADDRESS"
+}
+
+// DeployedToken is a token recorded in a network's deployment state
+type DeployedToken = deploystate.TokenDeployment
+
+// RecordDeployment records tokens in networkName's deployment state, replacing tokens of the same name and
+// keeping the others, and returns the state file
+func RecordDeployment(networkName string, tokens []DeployedToken) (string, error) {
+	err := deploystate.UpdateState(deploystate.Dir(), func(state *deploystate.State) error {
+		network := state.EnsureNetwork(networkName)
+		for _, token := range tokens {
+			network.SetToken(token)
+		}
+		return nil
+	})
+	return deploystate.StatePath(deploystate.Dir()), err
+}
+
+// Address returns the address of token on networkName from .env or the deployment state, and where it was found
+func Address(networkName, token string) (address, source string, err error) {
+	envName := EnvName(networkName, token)
+	if address := strings.TrimSpace(os.Getenv(envName)); address != "" {
+		return address, envName, nil
+	}
+
+	state, err := deploystate.ReadState(deploystate.Dir())
+	if err != nil {
+		return "", "", err
+	}
+	if t, ok := state.Network(networkName).Token(token); ok && t.Address != "" {
+		return t.Address, state.TokenSource(networkName), nil
+	}
+	return "", "", fmt.Errorf("%s address not found: set %s or deploy it (recorded in %s)", token, envName, deploystate.StatePath(deploystate.Dir()))
+}
@@ -0,0 +1,141 @@
+package tokenspec
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/deploystate"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "BASE_DOG_COIN_ADDRESS", EnvName("Base", "DogCoin"))
+	assert.Equal(t, "STARKNET_ORCA_COIN_ADDRESS", EnvName("Starknet", "OrcaCoin"))
+	assert.Equal(t, "ETHEREUM_USDC_ADDRESS", EnvName("Ethereum", "USDC"))
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("defaults", func(t *testing.T) {
+		specs, err := Load(filepath.Join(dir, "missing.json"))
+		require.NoError(t, err)
+		assert.Equal(t, Defaults(), specs)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(dir, "tokens.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[
+			{"name": "DogCoin", "symbol": "DOG", "decimals": 18},
+			{"name": "USDC", "symbol": "USDC", "decimals": 6, "initialSupply": 1000000000000}
+		]`), 0o600))
+
+		specs, err := Load(path)
+		require.NoError(t, err)
+		require.Len(t, specs, 2)
+		assert.Equal(t, uint8(6), specs[1].Decimals)
+		assert.Equal(t, big.NewInt(1_000_000_000_000), specs[1].Supply())
+		assert.Zero(t, specs[0].Supply().Sign(), "unset supply is zero")
+	})
+
+	t.Run("invalid_file", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"DogCoin": {}}`), 0o600))
+		_, err := Load(path)
+		assert.ErrorContains(t, err, "is not a JSON list of tokens")
+
+		require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o600))
+		_, err = Load(path)
+		assert.ErrorContains(t, err, "no tokens defined")
+	})
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []TokenSpec
+		wantErr string
+	}{
+		{name: "valid", specs: []TokenSpec{{Name: "DogCoin", Symbol: "DOG", Decimals: 18}, {Name: "USDC", Symbol: "USDC", Decimals: 6}}},
+		{name: "no_name", specs: []TokenSpec{{Symbol: "DOG"}}, wantErr: "token without a name"},
+		{name: "no_symbol", specs: []TokenSpec{{Name: "DogCoin"}}, wantErr: "token DogCoin has no symbol"},
+		{name: "too_many_decimals", specs: []TokenSpec{{Name: "DogCoin", Symbol: "DOG", Decimals: 78}}, wantErr: "at most 77"},
+		{
+			name:    "negative_supply",
+			specs:   []TokenSpec{{Name: "DogCoin", Symbol: "DOG", InitialSupply: big.NewInt(-1)}},
+			wantErr: "negative initial supply",
+		},
+		{
+			name:    "duplicate_name",
+			specs:   []TokenSpec{{Name: "DogCoin", Symbol: "DOG"}, {Name: "dogcoin", Symbol: "DOG2"}},
+			wantErr: "duplicate token name dogcoin",
+		},
+		{
+			name:    "duplicate_symbol",
+			specs:   []TokenSpec{{Name: "DogCoin", Symbol: "DOG"}, {Name: "OtherDog", Symbol: "dog"}},
+			wantErr: "duplicate token symbol dog",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.specs)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	specs := []TokenSpec{{Name: "DogCoin", Symbol: "DOG"}, {Name: "OrcaCoin", Symbol: "ORCA"}}
+
+	for _, token := range []string{"OrcaCoin", "orcacoin", "ORCA", "orca"} {
+		spec, ok := Find(specs, token)
+		require.True(t, ok, token)
+		assert.Equal(t, "OrcaCoin", spec.Name)
+	}
+	_, ok := Find(specs, "USDC")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"DogCoin", "OrcaCoin"}, Names(specs))
+}
+
+func TestRecordDeploymentAndAddress(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("BASE_DOG_COIN_ADDRESS", "")
+	t.Setenv("BASE_USDC_ADDRESS", "")
+
+	_, _, err := Address("Base", "DogCoin")
+	assert.ErrorContains(t, err, "DogCoin address not found: set BASE_DOG_COIN_ADDRESS")
+
+	path, err := RecordDeployment("Base", []DeployedToken{
+		{Name: "DogCoin", Symbol: "DOG", Decimals: 18, Address: "0xd06"},
+		{Name: "USDC", Symbol: "USDC", Decimals: 6, Address: "0x05dc"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, deploystate.StatePath(deploystate.Dir()), path)
+
+	_, err = RecordDeployment("Base", []DeployedToken{{Name: "DogCoin", Symbol: "DOG", Decimals: 18, Address: "0xd07"}})
+	require.NoError(t, err)
+
+	address, source, err := Address("Base", "DogCoin")
+	require.NoError(t, err)
+	assert.Equal(t, "0xd07", address, "a redeploy replaces the recorded address")
+	assert.Equal(t, path, source)
+
+	address, _, err = Address("Base", "USDC")
+	require.NoError(t, err)
+	assert.Equal(t, "0x05dc", address, "other tokens are kept")
+
+	t.Setenv("BASE_USDC_ADDRESS", "0xe4v")
+	address, source, err = Address("Base", "USDC")
+	require.NoError(t, err)
+	assert.Equal(t, "0xe4v", address)
+	assert.Equal(t, "BASE_USDC_ADDRESS", source, ".env takes precedence")
+}
@@ -0,0 +1,95 @@
+package txcost
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report is the JSON form of a ledger
+type Report struct {
+	Tool         string             `json:"tool,omitempty"`
+	Transactions []Entry            `json:"transactions"`
+	Totals       []Total            `json:"totals"`
+	TokenTotals  map[string]string  `json:"tokenTotals"` // whole tokens
+	Prices       Prices             `json:"prices,omitempty"`
+	Values       map[string]float64 `json:"values,omitempty"` // TokenTotals converted with Prices
+}
+
+// Report builds the JSON form of the ledger, converting the totals with prices when given
+func (l *Ledger) Report(tool string, prices Prices) Report {
+	report := Report{
+		Tool:         tool,
+		Transactions: l.Entries(),
+		Totals:       l.Totals(),
+		TokenTotals:  make(map[string]string),
+	}
+	if report.Transactions == nil {
+		report.Transactions = []Entry{}
+	}
+	if report.Totals == nil {
+		report.Totals = []Total{}
+	}
+	if len(prices) > 0 {
+		report.Prices = prices
+		report.Values = make(map[string]float64)
+	}
+	for token, fee := range l.TokenTotals() {
+		report.TokenTotals[token] = strings.TrimSuffix(FormatFee(fee, token), " "+token)
+		if value, ok := prices.Value(token, fee); ok {
+			report.Values[token] = value
+		}
+	}
+	return report
+}
+
+// WriteTable prints one line per network and operation, then the total per token, converted with prices when
+// given. Nothing is printed when no transaction was recorded
+func (l *Ledger) WriteTable(w io.Writer, prices Prices) {
+	totals := l.Totals()
+	if len(totals) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\n💸 Transaction costs:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "   NETWORK\tOPERATION\tTXS\tGAS\tFEE\t")
+	for _, t := range totals {
+		fmt.Fprintf(tw, "   %s\t%s\t%d\t%d\t%s\t%s\n", t.Network, t.Operation, t.Transactions, t.GasUsed,
+			FormatFee(t.Fee, t.Token), formatValue(prices, t.Token, t.Fee))
+	}
+	tw.Flush()
+
+	sums := l.TokenTotals()
+	tokens := make([]string, 0, len(sums))
+	for token := range sums {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	parts := make([]string, 0, len(tokens))
+	var value float64
+	valued := false
+	for _, token := range tokens {
+		parts = append(parts, FormatFee(sums[token], token))
+		if v, ok := prices.Value(token, sums[token]); ok {
+			value += v
+			valued = true
+		}
+	}
+	line := "   Total: " + strings.Join(parts, ", ")
+	if valued {
+		line += fmt.Sprintf(" (≈ %.2f)", value)
+	}
+	fmt.Fprintln(w, line)
+}
+
+// formatValue renders the converted fee, empty without a price for token
+func formatValue(prices Prices, token string, fee *big.Int) string {
+	value, ok := prices.Value(token, fee)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("≈ %.4f", value)
+}
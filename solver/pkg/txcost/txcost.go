@@ -0,0 +1,268 @@
+// Package txcost records what the transactions of a tool run cost, per network and operation.
+//
+// A tool puts a Ledger in its context with WithLedger, and labels the transactions it sends with WithNetwork and
+// WithOperation. The shared wait helpers (ethutil.WaitForTransaction, starknetutil.WaitForFinality,
+// starknetutil.WaitForTransactionReceiptWithRetry and starknetorder's receipt wait) record every receipt they return
+// into the context's ledger; without a ledger recording does nothing. EVM fees are gas used × effective gas price,
+// which leaves out the L1 data fee OP-stack chains charge on top; Starknet fees are the receipt's actual_fee.
+//
+// Usage:
+//
+//	ledger := &txcost.Ledger{}
+//	ctx = txcost.WithLedger(ctx, ledger)
+//	ctx = txcost.WithOperation(txcost.WithNetwork(ctx, "Base"), "approve")
+//	receipt, err := ethutil.WaitForTransaction(ctx, client, tx)
+//	...
+//	ledger.WriteTable(os.Stdout, prices)
+package txcost
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// Native token symbols fees are paid in
+const (
+	ETH  = "ETH"
+	STRK = "STRK"
+)
+
+// nativeDecimals is the decimals of ETH and STRK alike
+const nativeDecimals = 18
+
+// DefaultOperation labels transactions sent without WithOperation
+const DefaultOperation = "transaction"
+
+// Entry is the cost of one transaction
+type Entry struct {
+	Network   string   `json:"network"`
+	Operation string   `json:"operation"`
+	TxHash    string   `json:"txHash"`
+	GasUsed   uint64   `json:"gasUsed"`
+	Fee       *big.Int `json:"fee"`   // in the smallest unit of Token
+	Token     string   `json:"token"` // ETH or STRK
+}
+
+// Total sums the entries of one operation on one network
+type Total struct {
+	Network      string   `json:"network"`
+	Operation    string   `json:"operation"`
+	Transactions int      `json:"transactions"`
+	GasUsed      uint64   `json:"gasUsed"`
+	Fee          *big.Int `json:"fee"`
+	Token        string   `json:"token"`
+}
+
+// Ledger accumulates entries; it is safe for concurrent use
+type Ledger struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Record adds an entry
+func (l *Ledger) Record(e Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, e)
+}
+
+// Entries returns the recorded entries in the order they were recorded
+func (l *Ledger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Entry(nil), l.entries...)
+}
+
+// Totals groups the entries by network, operation and token, keeping the order networks and operations were
+// first recorded in
+func (l *Ledger) Totals() []Total {
+	var totals []Total
+	index := make(map[[3]string]int)
+	for _, e := range l.Entries() {
+		key := [3]string{e.Network, e.Operation, e.Token}
+		i, ok := index[key]
+		if !ok {
+			i = len(totals)
+			index[key] = i
+			totals = append(totals, Total{Network: e.Network, Operation: e.Operation, Token: e.Token, Fee: new(big.Int)})
+		}
+		totals[i].Transactions++
+		totals[i].GasUsed += e.GasUsed
+		totals[i].Fee.Add(totals[i].Fee, e.Fee)
+	}
+	// Operations of a network stay together even when networks were interleaved
+	networkOrder := make(map[string]int)
+	for _, t := range totals {
+		if _, ok := networkOrder[t.Network]; !ok {
+			networkOrder[t.Network] = len(networkOrder)
+		}
+	}
+	sort.SliceStable(totals, func(i, j int) bool {
+		return networkOrder[totals[i].Network] < networkOrder[totals[j].Network]
+	})
+	return totals
+}
+
+// TokenTotals sums the fees per token
+func (l *Ledger) TokenTotals() map[string]*big.Int {
+	sums := make(map[string]*big.Int)
+	for _, e := range l.Entries() {
+		if sums[e.Token] == nil {
+			sums[e.Token] = new(big.Int)
+		}
+		sums[e.Token].Add(sums[e.Token], e.Fee)
+	}
+	return sums
+}
+
+type contextKey int
+
+const (
+	ledgerKey contextKey = iota
+	networkKey
+	operationKey
+)
+
+// WithLedger returns a context whose transactions are recorded into ledger
+func WithLedger(ctx context.Context, ledger *Ledger) context.Context {
+	return context.WithValue(ctx, ledgerKey, ledger)
+}
+
+// LedgerFrom returns the context's ledger, nil when there is none
+func LedgerFrom(ctx context.Context) *Ledger {
+	ledger, _ := ctx.Value(ledgerKey).(*Ledger)
+	return ledger
+}
+
+// WithNetwork labels the transactions of ctx with the network they are sent on
+func WithNetwork(ctx context.Context, network string) context.Context {
+	return context.WithValue(ctx, networkKey, network)
+}
+
+// WithOperation labels the transactions of ctx with what they do, such as "approve" or "deploy DogCoin"
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey, operation)
+}
+
+// WithDefaultOperation labels the transactions of ctx with operation unless ctx already names one
+func WithDefaultOperation(ctx context.Context, operation string) context.Context {
+	if named, _ := ctx.Value(operationKey).(string); named != "" {
+		return ctx
+	}
+	return WithOperation(ctx, operation)
+}
+
+// labels returns the context's network, or fallback, and operation
+func labels(ctx context.Context, fallbackNetwork string) (network, operation string) {
+	network, _ = ctx.Value(networkKey).(string)
+	if network == "" {
+		network = fallbackNetwork
+	}
+	operation, _ = ctx.Value(operationKey).(string)
+	if operation == "" {
+		operation = DefaultOperation
+	}
+	return network, operation
+}
+
+// RecordEVM records the cost of an EVM transaction from its receipt into the context's ledger. tx is only used
+// for its chain ID and gas price when the node leaves the effective gas price out of the receipt
+func RecordEVM(ctx context.Context, tx *gethtypes.Transaction, receipt *gethtypes.Receipt) {
+	ledger := LedgerFrom(ctx)
+	if ledger == nil || receipt == nil {
+		return
+	}
+	network, operation := labels(ctx, "chain "+tx.ChainId().String())
+	price := receipt.EffectiveGasPrice
+	if price == nil {
+		price = tx.GasPrice()
+	}
+	ledger.Record(Entry{
+		Network:   network,
+		Operation: operation,
+		TxHash:    receipt.TxHash.Hex(),
+		GasUsed:   receipt.GasUsed,
+		Fee:       new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), price),
+		Token:     ETH,
+	})
+}
+
+// RecordStarknet records the actual fee of a Starknet receipt into the context's ledger. The gas reported is
+// the L2 gas
+func RecordStarknet(ctx context.Context, receipt *rpc.TransactionReceipt) {
+	ledger := LedgerFrom(ctx)
+	if ledger == nil || receipt == nil {
+		return
+	}
+	network, operation := labels(ctx, "Starknet")
+	token := STRK
+	if receipt.ActualFee.Unit == rpc.UnitWei {
+		token = ETH
+	}
+	fee := new(big.Int)
+	if receipt.ActualFee.Amount != nil {
+		fee = utils.FeltToBigInt(receipt.ActualFee.Amount)
+	}
+	txHash := ""
+	if receipt.Hash != nil {
+		txHash = receipt.Hash.String()
+	}
+	ledger.Record(Entry{
+		Network:   network,
+		Operation: operation,
+		TxHash:    txHash,
+		GasUsed:   uint64(receipt.ExecutionResources.L2Gas),
+		Fee:       fee,
+		Token:     token,
+	})
+}
+
+// Prices converts native token amounts to another currency, keyed by token symbol
+type Prices map[string]float64
+
+// ParsePrices parses "ETH=2500,STRK=0.45"; an empty string gives no prices
+func ParsePrices(s string) (Prices, error) {
+	prices := make(Prices)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		token, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid price %q: expected TOKEN=price", pair)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || price < 0 {
+			return nil, fmt.Errorf("invalid price %q: expected a non-negative number", pair)
+		}
+		prices[strings.ToUpper(strings.TrimSpace(token))] = price
+	}
+	return prices, nil
+}
+
+// Value converts fee, in the smallest unit of token, with the token's price; ok is false without one
+func (p Prices) Value(token string, fee *big.Int) (value float64, ok bool) {
+	price, ok := p[token]
+	if !ok {
+		return 0, false
+	}
+	amount, _ := new(big.Float).SetInt(fee).Float64()
+	return amount / 1e18 * price, true
+}
+
+// FormatFee renders fee, in the smallest unit of token, in whole tokens
+func FormatFee(fee *big.Int, token string) string {
+	amount := new(big.Rat).SetFrac(fee, new(big.Int).Exp(big.NewInt(10), big.NewInt(nativeDecimals), nil))
+	text := strings.TrimRight(strings.TrimRight(amount.FloatString(nativeDecimals), "0"), ".")
+	return text + " " + token
+}
@@ -0,0 +1,137 @@
+package txcost
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+func TestRecordEVM(t *testing.T) {
+	tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{ChainID: big.NewInt(8453), GasFeeCap: gwei(3)})
+	receipt := &gethtypes.Receipt{TxHash: common.HexToHash("0x01"), GasUsed: 21000, EffectiveGasPrice: gwei(2)}
+
+	t.Run("without a ledger", func(t *testing.T) {
+		assert.NotPanics(t, func() { RecordEVM(context.Background(), tx, receipt) })
+	})
+
+	t.Run("labelled", func(t *testing.T) {
+		ledger := &Ledger{}
+		ctx := WithOperation(WithNetwork(WithLedger(context.Background(), ledger), "Base"), "approve")
+		RecordEVM(ctx, tx, receipt)
+		require.Len(t, ledger.Entries(), 1)
+		e := ledger.Entries()[0]
+		assert.Equal(t, "Base", e.Network)
+		assert.Equal(t, "approve", e.Operation)
+		assert.Equal(t, uint64(21000), e.GasUsed)
+		assert.Equal(t, new(big.Int).Mul(big.NewInt(21000), gwei(2)), e.Fee)
+		assert.Equal(t, ETH, e.Token)
+	})
+
+	t.Run("unlabelled falls back to the chain and the tx gas price", func(t *testing.T) {
+		ledger := &Ledger{}
+		RecordEVM(WithLedger(context.Background(), ledger), tx, &gethtypes.Receipt{GasUsed: 100})
+		e := ledger.Entries()[0]
+		assert.Equal(t, "chain 8453", e.Network)
+		assert.Equal(t, DefaultOperation, e.Operation)
+		assert.Equal(t, new(big.Int).Mul(big.NewInt(100), gwei(3)), e.Fee)
+	})
+}
+
+func TestRecordStarknet(t *testing.T) {
+	ledger := &Ledger{}
+	ctx := WithDefaultOperation(WithOperation(WithLedger(context.Background(), ledger), "fill"), "transaction")
+	RecordStarknet(ctx, &rpc.TransactionReceipt{
+		Hash:               new(felt.Felt).SetUint64(0xabc),
+		ActualFee:          rpc.FeePayment{Amount: new(felt.Felt).SetUint64(5e17), Unit: rpc.UnitFri},
+		ExecutionResources: rpc.ExecutionResources{L2Gas: 1200},
+	})
+	RecordStarknet(WithLedger(context.Background(), ledger), &rpc.TransactionReceipt{
+		ActualFee: rpc.FeePayment{Amount: new(felt.Felt).SetUint64(7), Unit: rpc.UnitWei},
+	})
+
+	entries := ledger.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, Entry{Network: "Starknet", Operation: "fill", TxHash: "0xabc", GasUsed: 1200, Fee: big.NewInt(5e17), Token: STRK}, entries[0],
+		"WithDefaultOperation keeps the named operation")
+	assert.Equal(t, ETH, entries[1].Token)
+	assert.Equal(t, DefaultOperation, entries[1].Operation)
+}
+
+func TestTotals(t *testing.T) {
+	ledger := &Ledger{}
+	ledger.Record(Entry{Network: "Base", Operation: "approve", GasUsed: 10, Fee: big.NewInt(100), Token: ETH})
+	ledger.Record(Entry{Network: "Starknet", Operation: "open order", GasUsed: 5, Fee: big.NewInt(7), Token: STRK})
+	ledger.Record(Entry{Network: "Base", Operation: "open order", GasUsed: 20, Fee: big.NewInt(200), Token: ETH})
+	ledger.Record(Entry{Network: "Base", Operation: "approve", GasUsed: 10, Fee: big.NewInt(100), Token: ETH})
+
+	assert.Equal(t, []Total{
+		{Network: "Base", Operation: "approve", Transactions: 2, GasUsed: 20, Fee: big.NewInt(200), Token: ETH},
+		{Network: "Base", Operation: "open order", Transactions: 1, GasUsed: 20, Fee: big.NewInt(200), Token: ETH},
+		{Network: "Starknet", Operation: "open order", Transactions: 1, GasUsed: 5, Fee: big.NewInt(7), Token: STRK},
+	}, ledger.Totals())
+	assert.Equal(t, map[string]*big.Int{ETH: big.NewInt(400), STRK: big.NewInt(7)}, ledger.TokenTotals())
+}
+
+func TestParsePrices(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Prices
+		wantErr string
+	}{
+		{name: "empty", input: "", want: Prices{}},
+		{name: "pairs", input: "eth=2500, STRK=0.45", want: Prices{ETH: 2500, STRK: 0.45}},
+		{name: "missing price", input: "ETH", wantErr: "expected TOKEN=price"},
+		{name: "negative", input: "ETH=-1", wantErr: "non-negative number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePrices(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatFee(t *testing.T) {
+	assert.Equal(t, "0.000042 ETH", FormatFee(gwei(42000), ETH))
+	assert.Equal(t, "1.5 STRK", FormatFee(big.NewInt(15e17), STRK))
+	assert.Equal(t, "0 ETH", FormatFee(new(big.Int), ETH))
+}
+
+func TestWriteTable(t *testing.T) {
+	var empty bytes.Buffer
+	(&Ledger{}).WriteTable(&empty, nil)
+	assert.Empty(t, empty.String())
+
+	ledger := &Ledger{}
+	ledger.Record(Entry{Network: "Base", Operation: "approve", GasUsed: 46000, Fee: big.NewInt(1e15), Token: ETH})
+	ledger.Record(Entry{Network: "Starknet", Operation: "fill", GasUsed: 900, Fee: big.NewInt(2e18), Token: STRK})
+
+	var out bytes.Buffer
+	ledger.WriteTable(&out, Prices{ETH: 2000})
+	assert.Contains(t, out.String(), "Transaction costs:")
+	assert.Contains(t, out.String(), "0.001 ETH")
+	assert.Contains(t, out.String(), "≈ 2.0000")
+	assert.Contains(t, out.String(), "Total: 0.001 ETH, 2 STRK (≈ 2.00)")
+
+	report := ledger.Report("fill-order", Prices{ETH: 2000})
+	assert.Equal(t, map[string]string{ETH: "0.001", STRK: "2"}, report.TokenTotals)
+	assert.Equal(t, map[string]float64{ETH: 2}, report.Values)
+}
@@ -0,0 +1,117 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
+)
+
+const (
+	dirPerms  = 0755
+	filePerms = 0644
+)
+
+// Cursor saves the last block a watcher scanned on each network
+type Cursor interface {
+	// Load returns the last scanned block of a network, ok is false when none was saved
+	Load(network string) (block uint64, ok bool, err error)
+	Save(network string, block uint64) error
+}
+
+// DefaultCursorPath returns WATCH_CURSOR_FILE, or watch-cursor.json in the state directory
+func DefaultCursorPath() string {
+	if path := os.Getenv("WATCH_CURSOR_FILE"); path != "" {
+		return path
+	}
+	return solverdir.StatePath("watch-cursor.json")
+}
+
+// FileCursor keeps the cursors of all networks in one JSON file, rewritten through a temp file and an atomic
+// rename so a crash never leaves a partial file
+type FileCursor struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCursor returns a cursor stored at path; the file is created on the first save
+func NewFileCursor(path string) *FileCursor {
+	return &FileCursor{path: path}
+}
+
+// Load returns the last scanned block of a network
+func (c *FileCursor) Load(network string) (uint64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	blocks, err := c.read()
+	if err != nil {
+		return 0, false, err
+	}
+	block, ok := blocks[network]
+	return block, ok, nil
+}
+
+// Save records block as the last scanned block of a network
+func (c *FileCursor) Save(network string, block uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	blocks, err := c.read()
+	if err != nil {
+		return err
+	}
+	blocks[network] = block
+	return c.write(blocks)
+}
+
+// read returns the saved blocks by network, empty when the file does not exist yet
+func (c *FileCursor) read() (map[string]uint64, error) {
+	blocks := make(map[string]uint64)
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return blocks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cursor file: %w", err)
+	}
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, fmt.Errorf("failed to parse cursor file %s: %w", c.path, err)
+	}
+	return blocks, nil
+}
+
+// write replaces the cursor file atomically
+func (c *FileCursor) write(blocks map[string]uint64) error {
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, dirPerms); err != nil {
+		return fmt.Errorf("failed to create cursor directory: %w", err)
+	}
+	data, err := json.MarshalIndent(blocks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "watch-cursor-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp cursor file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { tmp.Close(); os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temp cursor file: %w", err)
+	}
+	if err := tmp.Chmod(filePerms); err != nil {
+		return fmt.Errorf("failed to chmod temp cursor file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp cursor file: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		return fmt.Errorf("failed to replace cursor file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+func TestParseEVMLog(t *testing.T) {
+	kinds, err := evmEventTopics()
+	require.NoError(t, err)
+	require.Len(t, kinds, len(evmEvents))
+	filterer, err := contracts.NewHyperlane7683Filterer(common.Address{}, nil)
+	require.NoError(t, err)
+	src := &evmSource{network: Network{Name: "Base"}, filterer: filterer, kinds: kinds}
+
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+	orderA, orderB := common.HexToHash("0x0a"), common.HexToHash("0x0b")
+
+	t.Run("Settled", func(t *testing.T) {
+		event := parsed.Events["Settled"]
+		data, err := event.Inputs.Pack(orderA, common.HexToAddress("0x01"))
+		require.NoError(t, err)
+		orderIDs, kind, err := src.parse(types.Log{Topics: []common.Hash{event.ID}, Data: data})
+		require.NoError(t, err)
+		assert.Equal(t, KindSettled, kind)
+		assert.Equal(t, []common.Hash{orderA}, orderIDs)
+	})
+
+	t.Run("Settle reports every order", func(t *testing.T) {
+		event := parsed.Events["Settle"]
+		data, err := event.Inputs.Pack([][32]byte{orderA, orderB}, [][]byte{{0x01}, {0x02}})
+		require.NoError(t, err)
+		orderIDs, kind, err := src.parse(types.Log{Topics: []common.Hash{event.ID}, Data: data})
+		require.NoError(t, err)
+		assert.Equal(t, KindSettle, kind)
+		assert.Equal(t, []common.Hash{orderA, orderB}, orderIDs)
+	})
+
+	t.Run("unknown topic", func(t *testing.T) {
+		_, _, err := src.parse(types.Log{Topics: []common.Hash{common.HexToHash("0xdead")}})
+		assert.ErrorContains(t, err, "unexpected event topic")
+	})
+}
+
+func TestParseStarknetEvent(t *testing.T) {
+	orderID := common.HexToHash("0x00000000000000000000000000000001000000000000000000000000000000ff")
+	low, high, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID.Hex())
+	require.NoError(t, err)
+	second := common.HexToHash("0x02")
+	secondLow, secondHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(second.Hex())
+	require.NoError(t, err)
+	receiver := utils.Uint64ToFelt(0x1234)
+
+	emitted := func(name string, keys, data []*felt.Felt) rpc.EmittedEvent {
+		e := rpc.EmittedEvent{BlockNumber: 12, TransactionHash: utils.Uint64ToFelt(0xabc)}
+		e.Keys = append([]*felt.Felt{utils.GetSelectorFromNameFelt(name)}, keys...)
+		e.Data = data
+		return e
+	}
+
+	openSelector, err := utils.HexToFelt("0x35D8BA7F4BF26B6E2E2060E5BD28107042BE35460FBD828C9D29A2D8AF14445")
+	require.NoError(t, err)
+	assert.Equal(t, KindOpen, starknetEvents[*openSelector], "the selector the solver's Starknet listener uses")
+
+	tests := []struct {
+		name    string
+		event   rpc.EmittedEvent
+		want    []common.Hash
+		kind    Kind
+		wantErr string
+	}{
+		{name: "Open keys the order ID", event: emitted("Open", []*felt.Felt{low, high}, []*felt.Felt{utils.Uint64ToFelt(1)}), want: []common.Hash{orderID}, kind: KindOpen},
+		{name: "Filled", event: emitted("Filled", nil, []*felt.Felt{low, high, utils.Uint64ToFelt(0)}), want: []common.Hash{orderID}, kind: KindFilled},
+		{name: "Settled", event: emitted("Settled", nil, []*felt.Felt{low, high, receiver}), want: []common.Hash{orderID}, kind: KindSettled},
+		{name: "Refunded", event: emitted("Refunded", nil, []*felt.Felt{low, high, receiver}), want: []common.Hash{orderID}, kind: KindRefunded},
+		{name: "Settle lists its orders", event: emitted("Settle", nil, []*felt.Felt{utils.Uint64ToFelt(2), low, high, secondLow, secondHigh, utils.Uint64ToFelt(0)}), want: []common.Hash{orderID, second}, kind: KindSettle},
+		{name: "Refund lists its orders", event: emitted("Refund", nil, []*felt.Felt{utils.Uint64ToFelt(1), secondLow, secondHigh}), want: []common.Hash{second}, kind: KindRefund},
+		{name: "truncated Settle", event: emitted("Settle", nil, []*felt.Felt{utils.Uint64ToFelt(2), low, high}), wantErr: "declares 2 orders"},
+		{name: "Filled without data", event: emitted("Filled", nil, nil), wantErr: "Filled event without an order ID"},
+		{name: "unknown selector", event: emitted("Transfer", nil, nil), wantErr: "unexpected event selector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseStarknetEvent("Starknet", tt.event)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, events, len(tt.want))
+			for i, e := range events {
+				assert.Equal(t, tt.want[i], e.OrderID)
+				assert.Equal(t, tt.kind, e.Kind)
+				assert.Equal(t, "Starknet", e.Network)
+				assert.Equal(t, uint64(12), e.BlockNumber)
+				assert.Equal(t, "0xabc", e.TxHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+)
+
+// evmEvents maps the Hyperlane7683 ABI events to the kinds they report
+var evmEvents = map[string]Kind{
+	"Open":     KindOpen,
+	"Filled":   KindFilled,
+	"Settle":   KindSettle,
+	"Refund":   KindRefund,
+	"Settled":  KindSettled,
+	"Refunded": KindRefunded,
+}
+
+// evmSource reads Hyperlane7683 logs with eth_getLogs
+type evmSource struct {
+	network  Network
+	client   *ethclient.Client
+	contract common.Address
+	filterer *contracts.Hyperlane7683Filterer
+	// kinds maps the topic of every watched event to its kind
+	kinds map[common.Hash]Kind
+}
+
+func newEVMSource(ctx context.Context, network Network) (*evmSource, error) {
+	if !common.IsHexAddress(network.Contract) {
+		return nil, fmt.Errorf("invalid %s Hyperlane7683 address %q", network.Name, network.Contract)
+	}
+	kinds, err := evmEventTopics()
+	if err != nil {
+		return nil, err
+	}
+	client, err := ethclient.DialContext(ctx, network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	contract := common.HexToAddress(network.Contract)
+	filterer, err := contracts.NewHyperlane7683Filterer(contract, client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to bind Hyperlane7683 on %s: %w", network.Name, err)
+	}
+	return &evmSource{network: network, client: client, contract: contract, filterer: filterer, kinds: kinds}, nil
+}
+
+// evmEventTopics returns the topic of every watched event from the generated binding's ABI
+func evmEventTopics() (map[common.Hash]Kind, error) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Hyperlane7683 ABI: %w", err)
+	}
+	kinds := make(map[common.Hash]Kind, len(evmEvents))
+	for name, kind := range evmEvents {
+		event, ok := parsed.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("Hyperlane7683 ABI has no %s event", name)
+		}
+		kinds[event.ID] = kind
+	}
+	return kinds, nil
+}
+
+func (s *evmSource) head(ctx context.Context) (uint64, error) {
+	return s.client.BlockNumber(ctx)
+}
+
+func (s *evmSource) events(ctx context.Context, from, to uint64) ([]Event, error) {
+	topics := make([]common.Hash, 0, len(s.kinds))
+	for topic := range s.kinds {
+		topics = append(topics, topic)
+	}
+	logs, err := s.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{s.contract},
+		Topics:    [][]common.Hash{topics},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		orderIDs, kind, err := s.parse(log)
+		if err != nil {
+			return nil, err
+		}
+		for _, orderID := range orderIDs {
+			events = append(events, Event{
+				Kind:        kind,
+				Network:     s.network.Name,
+				OrderID:     orderID,
+				BlockNumber: log.BlockNumber,
+				TxHash:      log.TxHash.Hex(),
+			})
+		}
+	}
+	return events, nil
+}
+
+// parse decodes a log with the generated binding and returns the orders it reports
+func (s *evmSource) parse(log types.Log) ([]common.Hash, Kind, error) {
+	if len(log.Topics) == 0 {
+		return nil, 0, fmt.Errorf("log without topics in tx %s", log.TxHash.Hex())
+	}
+	kind, ok := s.kinds[log.Topics[0]]
+	if !ok {
+		return nil, 0, fmt.Errorf("unexpected event topic %s in tx %s", log.Topics[0].Hex(), log.TxHash.Hex())
+	}
+
+	var orderIDs [][32]byte
+	var err error
+	switch kind {
+	case KindOpen:
+		var e *contracts.Hyperlane7683Open
+		if e, err = s.filterer.ParseOpen(log); err == nil {
+			orderIDs = [][32]byte{e.OrderId}
+		}
+	case KindFilled:
+		var e *contracts.Hyperlane7683Filled
+		if e, err = s.filterer.ParseFilled(log); err == nil {
+			orderIDs = [][32]byte{e.OrderId}
+		}
+	case KindSettle:
+		var e *contracts.Hyperlane7683Settle
+		if e, err = s.filterer.ParseSettle(log); err == nil {
+			orderIDs = e.OrderIds
+		}
+	case KindRefund:
+		var e *contracts.Hyperlane7683Refund
+		if e, err = s.filterer.ParseRefund(log); err == nil {
+			orderIDs = e.OrderIds
+		}
+	case KindSettled:
+		var e *contracts.Hyperlane7683Settled
+		if e, err = s.filterer.ParseSettled(log); err == nil {
+			orderIDs = [][32]byte{e.OrderId}
+		}
+	case KindRefunded:
+		var e *contracts.Hyperlane7683Refunded
+		if e, err = s.filterer.ParseRefunded(log); err == nil {
+			orderIDs = [][32]byte{e.OrderId}
+		}
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode %s event in tx %s: %w", kind, log.TxHash.Hex(), err)
+	}
+
+	hashes := make([]common.Hash, len(orderIDs))
+	for i, id := range orderIDs {
+		hashes[i] = id
+	}
+	return hashes, kind, nil
+}
+
+func (s *evmSource) close() {
+	s.client.Close()
+}
@@ -0,0 +1,92 @@
+package watcher
+
+import (
+	"context"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// subscribeHeads subscribes to the new heads of network's websocket endpoint and returns a channel receiving a
+// value per new block. The channel is closed when the subscription fails or ends, and is nil without a WSURL or
+// when the subscription cannot be made, leaving the watcher to poll
+func subscribeHeads(ctx context.Context, network Network) <-chan struct{} {
+	if network.WSURL == "" {
+		return nil
+	}
+	if network.Cairo {
+		return subscribeStarknetHeads(ctx, network.WSURL)
+	}
+	return subscribeEVMHeads(ctx, network.WSURL)
+}
+
+func subscribeEVMHeads(ctx context.Context, wsURL string) <-chan struct{} {
+	client, err := ethclient.DialContext(ctx, wsURL)
+	if err != nil {
+		return nil
+	}
+	headers := make(chan *types.Header)
+	sub, err := client.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		client.Close()
+		return nil
+	}
+
+	wake := make(chan struct{}, 1)
+	go func() {
+		defer close(wake)
+		defer client.Close()
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Err():
+				return
+			case <-headers:
+				notify(wake)
+			}
+		}
+	}()
+	return wake
+}
+
+func subscribeStarknetHeads(ctx context.Context, wsURL string) <-chan struct{} {
+	provider, err := rpc.NewWebsocketProvider(wsURL)
+	if err != nil {
+		return nil
+	}
+	headers := make(chan *rpc.BlockHeader)
+	sub, err := provider.SubscribeNewHeads(ctx, headers, rpc.SubscriptionBlockID{})
+	if err != nil {
+		provider.Close()
+		return nil
+	}
+
+	wake := make(chan struct{}, 1)
+	go func() {
+		defer close(wake)
+		defer provider.Close()
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Err():
+				return
+			case <-headers:
+				notify(wake)
+			}
+		}
+	}()
+	return wake
+}
+
+// notify wakes the watcher without blocking; a wake-up already pending covers this head too
+func notify(wake chan<- struct{}) {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
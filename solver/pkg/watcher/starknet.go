@@ -0,0 +1,136 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+)
+
+// starknetEventChunkSize is the page size of starknet_getEvents
+const starknetEventChunkSize = 128
+
+// starknetEvents maps the selectors of the Cairo Hyperlane7683 events to the kinds they report. The components'
+// events are flattened into the contract's, so the first key is the event name's selector
+var starknetEvents = map[felt.Felt]Kind{
+	*utils.GetSelectorFromNameFelt("Open"):     KindOpen,
+	*utils.GetSelectorFromNameFelt("Filled"):   KindFilled,
+	*utils.GetSelectorFromNameFelt("Settle"):   KindSettle,
+	*utils.GetSelectorFromNameFelt("Refund"):   KindRefund,
+	*utils.GetSelectorFromNameFelt("Settled"):  KindSettled,
+	*utils.GetSelectorFromNameFelt("Refunded"): KindRefunded,
+}
+
+// starknetSource reads Hyperlane7683 events with starknet_getEvents
+type starknetSource struct {
+	network  Network
+	provider *rpc.Provider
+	contract *felt.Felt
+}
+
+func newStarknetSource(network Network) (*starknetSource, error) {
+	contract, err := utils.HexToFelt(network.Contract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s Hyperlane7683 address %q: %w", network.Name, network.Contract, err)
+	}
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", network.Name, err)
+	}
+	return &starknetSource{network: network, provider: provider, contract: contract}, nil
+}
+
+func (s *starknetSource) head(ctx context.Context) (uint64, error) {
+	return s.provider.BlockNumber(ctx)
+}
+
+func (s *starknetSource) events(ctx context.Context, from, to uint64) ([]Event, error) {
+	selectors := make([]*felt.Felt, 0, len(starknetEvents))
+	for selector := range starknetEvents {
+		selectors = append(selectors, &selector)
+	}
+	input := rpc.EventsInput{
+		EventFilter: rpc.EventFilter{
+			FromBlock: rpc.BlockID{Number: &from},
+			ToBlock:   rpc.BlockID{Number: &to},
+			Address:   s.contract,
+			Keys:      [][]*felt.Felt{selectors},
+		},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: starknetEventChunkSize},
+	}
+
+	var events []Event
+	for {
+		chunk, err := s.provider.Events(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, emitted := range chunk.Events {
+			parsed, err := parseStarknetEvent(s.network.Name, emitted)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, parsed...)
+		}
+		if chunk.ContinuationToken == "" {
+			return events, nil
+		}
+		input.ContinuationToken = chunk.ContinuationToken
+	}
+}
+
+// parseStarknetEvent reads the orders of an emitted event: Open keys its u256 order ID, Filled, Settled and
+// Refunded start their data with it, and Settle and Refund start theirs with an array of them
+func parseStarknetEvent(networkName string, emitted rpc.EmittedEvent) ([]Event, error) {
+	keys, data := emitted.Keys, emitted.Data
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("event without keys in tx %s", emitted.TransactionHash)
+	}
+	kind, ok := starknetEvents[*keys[0]]
+	if !ok {
+		return nil, fmt.Errorf("unexpected event selector %s in tx %s", keys[0], emitted.TransactionHash)
+	}
+
+	// words holds the (low, high) felt pairs of the reported order IDs
+	var words []*felt.Felt
+	switch kind {
+	case KindOpen:
+		if len(keys) < 3 {
+			return nil, fmt.Errorf("%s event without an order ID in tx %s", kind, emitted.TransactionHash)
+		}
+		words = keys[1:3]
+	case KindSettle, KindRefund:
+		if len(data) == 0 {
+			return nil, fmt.Errorf("%s event without data in tx %s", kind, emitted.TransactionHash)
+		}
+		count := data[0].Uint64()
+		if uint64(len(data)-1) < 2*count {
+			return nil, fmt.Errorf("%s event declares %d orders but has %d data felts in tx %s", kind, count, len(data), emitted.TransactionHash)
+		}
+		words = data[1 : 1+2*count]
+	default:
+		if len(data) < 2 {
+			return nil, fmt.Errorf("%s event without an order ID in tx %s", kind, emitted.TransactionHash)
+		}
+		words = data[:2]
+	}
+
+	events := make([]Event, 0, len(words)/2)
+	for i := 0; i < len(words); i += 2 {
+		events = append(events, Event{
+			Kind:        kind,
+			Network:     networkName,
+			OrderID:     common.BigToHash(starknetutil.FromU256(words[i], words[i+1])),
+			BlockNumber: emitted.BlockNumber,
+			TxHash:      emitted.TransactionHash.String(),
+		})
+	}
+	return events, nil
+}
+
+func (s *starknetSource) close() {}
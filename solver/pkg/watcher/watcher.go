@@ -0,0 +1,286 @@
+// Package watcher follows the lifecycle events Hyperlane7683 emits for orders: Open on the origin, Filled and the
+// Settle/Refund dispatch on the destination, then Settled or Refunded back on the origin.
+//
+// Events are read over HTTP in block ranges, eth_getLogs on EVM and starknet_getEvents on Cairo networks, and only
+// once a block has Confirmations blocks on top of it, so a reorg shallower than that never surfaces an event. With
+// a websocket endpoint the watcher subscribes to new heads and scans as soon as a block arrives; without one, or
+// when the subscription drops, it polls. The last scanned block can be saved to a Cursor so a restarted watcher
+// resumes where it stopped.
+//
+// Usage:
+//
+//	events, errs := watcher.Watch(ctx, network, watcher.Options{Cursor: watcher.NewFileCursor(watcher.DefaultCursorPath())})
+//	for e := range events { ... }
+//	event, err := watcher.WaitForOrderFilled(ctx, destination, orderID, 5*time.Minute)
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
+)
+
+const (
+	// DefaultPollInterval is the delay between scans of a network without PollInterval
+	DefaultPollInterval = 2 * time.Second
+	// DefaultMaxBlockRange bounds the blocks read per request for a network without MaxBlockRange
+	DefaultMaxBlockRange = 1000
+	// maxConsecutiveErrors is how many scans in a row may fail before Watch gives up
+	maxConsecutiveErrors = 5
+)
+
+// Kind is the lifecycle event an order went through
+type Kind int
+
+// Event kinds, in lifecycle order
+const (
+	// KindOpen is emitted on the origin when the order is opened
+	KindOpen Kind = iota
+	// KindFilled is emitted on the destination when a solver fills the order
+	KindFilled
+	// KindSettle is emitted on the destination when settle dispatches the order to the origin
+	KindSettle
+	// KindRefund is emitted on the destination when refund dispatches the expired order to the origin
+	KindRefund
+	// KindSettled is emitted on the origin when the settlement arrives and the inputs are released to the filler
+	KindSettled
+	// KindRefunded is emitted on the origin when the refund arrives and the inputs are returned to the user
+	KindRefunded
+)
+
+var kindNames = [...]string{"Open", "Filled", "Settle", "Refund", "Settled", "Refunded"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// Event is one lifecycle event of one order; a Settle or Refund of several orders yields one Event per order
+type Event struct {
+	Kind        Kind
+	Network     string
+	OrderID     common.Hash
+	BlockNumber uint64
+	TxHash      string
+}
+
+// Network locates the Hyperlane7683 contract to watch and how to read it
+type Network struct {
+	Name   string
+	RPCURL string
+	// WSURL is an optional websocket endpoint whose new heads trigger the scans; empty polls RPCURL
+	WSURL string
+	// Contract is the Hyperlane7683 address, 0x EVM address or Cairo felt
+	Contract string
+	// Cairo is set for Starknet and Ztarknet, whose events are read with starknet_getEvents
+	Cairo bool
+	// Confirmations is how many blocks must be built on a block before its events are reported
+	Confirmations uint64
+	// PollInterval is the delay between scans, 0 = DefaultPollInterval
+	PollInterval time.Duration
+	// MaxBlockRange bounds the blocks read per request, 0 = DefaultMaxBlockRange
+	MaxBlockRange uint64
+}
+
+// queryNetwork is the orderquery view of the same contract
+func (n Network) queryNetwork() orderquery.Network {
+	return orderquery.Network{Name: n.Name, RPCURL: n.RPCURL, Contract: n.Contract, Cairo: n.Cairo}
+}
+
+// Options controls where a watch starts and whether its progress is saved
+type Options struct {
+	// FromBlock is the first block to scan when Cursor has none saved; 0 starts after the current confirmed head
+	FromBlock uint64
+	// Cursor, when set, is read to resume and updated after every scanned range
+	Cursor Cursor
+}
+
+// source reads a network's chain head and the Hyperlane7683 events of a block range
+type source interface {
+	head(ctx context.Context) (uint64, error)
+	events(ctx context.Context, from, to uint64) ([]Event, error)
+	close()
+}
+
+// newSource connects to a network's RPC endpoint
+func newSource(ctx context.Context, network Network) (source, error) {
+	if network.Cairo {
+		return newStarknetSource(network)
+	}
+	return newEVMSource(ctx, network)
+}
+
+// Watch streams the lifecycle events of every order on network until ctx is done. The events channel is closed
+// when the watch ends; the error channel then yields the error that ended it, if any, and is closed too
+func Watch(ctx context.Context, network Network, opts Options) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(events)
+		src, err := newSource(ctx, network)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer src.close()
+		wake := subscribeHeads(ctx, network)
+		if err := run(ctx, network, opts, src, wake, events); err != nil && !errors.Is(err, context.Canceled) {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
+
+// WaitForOrderFilled blocks until orderID is filled on network, its destination, or timeout elapses. An order
+// already FILLED or SETTLED there when the wait starts is returned straight away with BlockNumber 0
+func WaitForOrderFilled(ctx context.Context, network Network, orderID common.Hash, timeout time.Duration) (Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	src, err := newSource(ctx, network)
+	if err != nil {
+		return Event{}, err
+	}
+	// The head is read before the status so a fill landing in between is still scanned
+	head, err := src.head(ctx)
+	src.close()
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to read the %s head: %w", network.Name, err)
+	}
+	status, err := orderquery.GetOrderStatus(ctx, network.queryNetwork(), orderID)
+	if err != nil {
+		return Event{}, err
+	}
+	if status == orderquery.StatusFilled || status == orderquery.StatusSettled {
+		return Event{Kind: KindFilled, Network: network.Name, OrderID: orderID}, nil
+	}
+
+	events, errs := Watch(ctx, network, Options{FromBlock: safeHead(head, network.Confirmations) + 1})
+	for e := range events {
+		if e.Kind == KindFilled && e.OrderID == orderID {
+			return e, nil
+		}
+	}
+	if err := <-errs; err != nil {
+		return Event{}, err
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return Event{}, fmt.Errorf("order %s not filled on %s within %s", orderID.Hex(), network.Name, timeout)
+	}
+	return Event{}, ctx.Err()
+}
+
+// run scans network from its start block to the confirmed head, then again on every wake-up or poll tick
+func run(ctx context.Context, network Network, opts Options, src source, wake <-chan struct{}, sink chan<- Event) error {
+	next, err := startBlock(ctx, network, opts, src)
+	if err != nil {
+		return err
+	}
+
+	interval := network.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		next, err = scan(ctx, network, opts.Cursor, src, next, sink)
+		switch {
+		case err == nil:
+			failures = 0
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			failures++
+			if failures >= maxConsecutiveErrors {
+				return fmt.Errorf("%s: %d scans failed in a row: %w", network.Name, failures, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case _, ok := <-wake:
+			if !ok {
+				// The subscription dropped: keep going on the poll ticker alone
+				wake = nil
+			}
+		}
+	}
+}
+
+// startBlock is the first block to scan: after the saved cursor, else FromBlock, else after the confirmed head
+func startBlock(ctx context.Context, network Network, opts Options, src source) (uint64, error) {
+	if opts.Cursor != nil {
+		block, ok, err := opts.Cursor.Load(network.Name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load the %s cursor: %w", network.Name, err)
+		}
+		if ok {
+			return block + 1, nil
+		}
+	}
+	if opts.FromBlock > 0 {
+		return opts.FromBlock, nil
+	}
+	head, err := src.head(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the %s head: %w", network.Name, err)
+	}
+	return safeHead(head, network.Confirmations) + 1, nil
+}
+
+// scan sends the events of [next, confirmed head] in MaxBlockRange chunks, saving the cursor after each, and
+// returns the next block to scan
+func scan(ctx context.Context, network Network, cursor Cursor, src source, next uint64, sink chan<- Event) (uint64, error) {
+	head, err := src.head(ctx)
+	if err != nil {
+		return next, fmt.Errorf("failed to read the %s head: %w", network.Name, err)
+	}
+	safe := safeHead(head, network.Confirmations)
+	maxRange := network.MaxBlockRange
+	if maxRange == 0 {
+		maxRange = DefaultMaxBlockRange
+	}
+
+	for next <= safe {
+		to := min(next+maxRange-1, safe)
+		events, err := src.events(ctx, next, to)
+		if err != nil {
+			return next, fmt.Errorf("failed to read %s events of blocks %d-%d: %w", network.Name, next, to, err)
+		}
+		for _, e := range events {
+			select {
+			case sink <- e:
+			case <-ctx.Done():
+				return next, ctx.Err()
+			}
+		}
+		if cursor != nil {
+			if err := cursor.Save(network.Name, to); err != nil {
+				return next, fmt.Errorf("failed to save the %s cursor: %w", network.Name, err)
+			}
+		}
+		next = to + 1
+	}
+	return next, nil
+}
+
+// safeHead is the newest block with confirmations blocks on top of it
+func safeHead(head, confirmations uint64) uint64 {
+	if head < confirmations {
+		return 0
+	}
+	return head - confirmations
+}
@@ -0,0 +1,184 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves events by block from a chain whose head is set by the test
+type fakeSource struct {
+	headBlock uint64
+	byBlock   map[uint64][]Event
+	ranges    [][2]uint64
+	failures  int
+}
+
+func (f *fakeSource) head(context.Context) (uint64, error) {
+	if f.failures > 0 {
+		f.failures--
+		return 0, errors.New("connection refused")
+	}
+	return f.headBlock, nil
+}
+
+func (f *fakeSource) events(_ context.Context, from, to uint64) ([]Event, error) {
+	f.ranges = append(f.ranges, [2]uint64{from, to})
+	var events []Event
+	for b := from; b <= to; b++ {
+		events = append(events, f.byBlock[b]...)
+	}
+	return events, nil
+}
+
+func (f *fakeSource) close() {}
+
+// memoryCursor is a Cursor kept in memory
+type memoryCursor map[string]uint64
+
+func (m memoryCursor) Load(network string) (uint64, bool, error) {
+	block, ok := m[network]
+	return block, ok, nil
+}
+
+func (m memoryCursor) Save(network string, block uint64) error {
+	m[network] = block
+	return nil
+}
+
+func filledAt(block uint64, orderID common.Hash) Event {
+	return Event{Kind: KindFilled, Network: "Base", OrderID: orderID, BlockNumber: block}
+}
+
+func TestScan(t *testing.T) {
+	network := Network{Name: "Base", Confirmations: 2, MaxBlockRange: 4}
+	orderID := common.HexToHash("0x01")
+
+	t.Run("stops at the confirmed head in MaxBlockRange chunks", func(t *testing.T) {
+		src := &fakeSource{headBlock: 20, byBlock: map[uint64][]Event{12: {filledAt(12, orderID)}, 19: {filledAt(19, orderID)}}}
+		cursor := memoryCursor{}
+		sink := make(chan Event, 10)
+
+		next, err := scan(context.Background(), network, cursor, src, 10, sink)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(19), next, "blocks 19 and 20 are not confirmed yet")
+		assert.Equal(t, [][2]uint64{{10, 13}, {14, 17}, {18, 18}}, src.ranges)
+		assert.Equal(t, uint64(18), cursor["Base"])
+		require.Len(t, sink, 1)
+		assert.Equal(t, uint64(12), (<-sink).BlockNumber)
+
+		src.headBlock = 21
+		next, err = scan(context.Background(), network, cursor, src, next, sink)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(20), next)
+		assert.Equal(t, uint64(19), (<-sink).BlockNumber, "reported once confirmed")
+	})
+
+	t.Run("nothing new", func(t *testing.T) {
+		src := &fakeSource{headBlock: 5}
+		next, err := scan(context.Background(), network, nil, src, 4, make(chan Event))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), next)
+		assert.Empty(t, src.ranges)
+	})
+}
+
+func TestStartBlock(t *testing.T) {
+	network := Network{Name: "Base", Confirmations: 3}
+	src := &fakeSource{headBlock: 100}
+
+	next, err := startBlock(context.Background(), network, Options{}, src)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(98), next, "after the confirmed head")
+
+	next, err = startBlock(context.Background(), network, Options{FromBlock: 50}, src)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(50), next)
+
+	next, err = startBlock(context.Background(), network, Options{FromBlock: 50, Cursor: memoryCursor{"Base": 70}}, src)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(71), next, "the saved cursor wins")
+}
+
+func TestRun(t *testing.T) {
+	orderID := common.HexToHash("0x01")
+	network := Network{Name: "Base", PollInterval: time.Millisecond}
+
+	t.Run("streams events until cancelled", func(t *testing.T) {
+		src := &fakeSource{headBlock: 10, byBlock: map[uint64][]Event{7: {filledAt(7, orderID)}}}
+		ctx, cancel := context.WithCancel(context.Background())
+		sink := make(chan Event)
+		done := make(chan error, 1)
+		go func() { done <- run(ctx, network, Options{FromBlock: 5}, src, nil, sink) }()
+
+		e := <-sink
+		assert.Equal(t, KindFilled, e.Kind)
+		assert.Equal(t, orderID, e.OrderID)
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	t.Run("gives up after repeated failures", func(t *testing.T) {
+		src := &fakeSource{headBlock: 10, failures: maxConsecutiveErrors}
+		err := run(context.Background(), network, Options{FromBlock: 5}, src, nil, make(chan Event))
+		assert.ErrorContains(t, err, "Base: 5 scans failed in a row: failed to read the Base head: connection refused")
+	})
+
+	t.Run("recovers from a transient failure", func(t *testing.T) {
+		src := &fakeSource{headBlock: 10, byBlock: map[uint64][]Event{9: {filledAt(9, orderID)}}, failures: 2}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sink := make(chan Event)
+		go func() { _ = run(ctx, network, Options{FromBlock: 5}, src, nil, sink) }()
+		assert.Equal(t, uint64(9), (<-sink).BlockNumber)
+	})
+
+	t.Run("a closed head subscription falls back to polling", func(t *testing.T) {
+		src := &fakeSource{headBlock: 10, byBlock: map[uint64][]Event{6: {filledAt(6, orderID)}}}
+		wake := make(chan struct{})
+		close(wake)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sink := make(chan Event)
+		go func() { _ = run(ctx, network, Options{FromBlock: 5}, src, wake, sink) }()
+		assert.Equal(t, uint64(6), (<-sink).BlockNumber)
+	})
+}
+
+func TestFileCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "watch-cursor.json")
+	cursor := NewFileCursor(path)
+
+	_, ok, err := cursor.Load("Base")
+	require.NoError(t, err)
+	assert.False(t, ok, "no file yet")
+
+	require.NoError(t, cursor.Save("Base", 42))
+	require.NoError(t, cursor.Save("Starknet", 7))
+
+	block, ok, err := NewFileCursor(path).Load("Base")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), block)
+	block, _, err = NewFileCursor(path).Load("Starknet")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), block)
+}
+
+func TestSafeHead(t *testing.T) {
+	assert.Equal(t, uint64(8), safeHead(10, 2))
+	assert.Equal(t, uint64(10), safeHead(10, 0))
+	assert.Equal(t, uint64(0), safeHead(1, 5))
+}
+
+func TestKindString(t *testing.T) {
+	assert.Equal(t, "Filled", KindFilled.String())
+	assert.Equal(t, "Refunded", KindRefunded.String())
+	assert.Equal(t, "Kind(9)", Kind(9).String())
+}
@@ -0,0 +1,213 @@
+package admin
+
+// Module: Solver admin API
+// - Lists the orders the solver is tracking and pauses or resumes fills per destination network
+// - Shows the solver's token inventory: balances, what in-flight fills reserved and the thresholds
+// - Shows the running configuration without keys, tokens or RPC URL paths
+// - Every request must carry ADMIN_TOKEN as a bearer token; Serve exposes it when ADMIN_ADDR is set
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/rpcpool"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// shutdownTimeout bounds how long in-flight requests may finish once the solver stops
+const shutdownTimeout = 5 * time.Second
+
+// orderIDPattern matches an order ID as the listeners record it, with or without 0x
+var orderIDPattern = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{1,64}$`)
+
+// Manager is what the admin API inspects and controls; *solvercore.SolverManager implements it
+type Manager interface {
+	Orders() []solvercore.TrackedOrder
+	Order(orderID string) (solvercore.TrackedOrder, bool)
+	PauseNetwork(name string) (string, error)
+	ResumeNetwork(name string) (string, int, error)
+	PausedNetworks() []string
+	Inventory() []inventory.Balance
+}
+
+// networkStatus is the body of the pause and resume endpoints
+type networkStatus struct {
+	Network  string `json:"network"`
+	Paused   bool   `json:"paused"`
+	Released int    `json:"released,omitempty"`
+}
+
+// configView is the body of /config
+type configView struct {
+	LogLevel       string                         `json:"logLevel"`
+	LogFormat      string                         `json:"logFormat"`
+	MaxRetries     int                            `json:"maxRetries"`
+	Solvers        map[string]config.SolverConfig `json:"solvers"`
+	MetricsAddr    string                         `json:"metricsAddr,omitempty"`
+	AdminAddr      string                         `json:"adminAddr"`
+	PausedNetworks []string                       `json:"pausedNetworks"`
+	Networks       map[string]networkView         `json:"networks"`
+}
+
+// networkView is a network in /config, with RPC endpoints reduced to scheme and host
+type networkView struct {
+	Type               config.NetworkType `json:"type"`
+	ChainID            uint64             `json:"chainId"`
+	HyperlaneDomain    uint64             `json:"hyperlaneDomain"`
+	HyperlaneAddress   string             `json:"hyperlaneAddress"`
+	RPCURLs            []string           `json:"rpcUrls"`
+	WSURL              string             `json:"wsUrl,omitempty"`
+	SolverStartBlock   int64              `json:"solverStartBlock"`
+	PollInterval       int                `json:"pollIntervalMs,omitempty"`
+	ConfirmationBlocks uint64             `json:"confirmationBlocks,omitempty"`
+	MaxBlockRange      uint64             `json:"maxBlockRange,omitempty"`
+}
+
+// Handler serves the admin API for manager, showing cfg on /config. Requests without token as their bearer
+// token are refused, so an empty token refuses everything
+func Handler(manager Manager, cfg *config.Config, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orders", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string][]solvercore.TrackedOrder{"orders": manager.Orders()})
+	})
+	mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !orderIDPattern.MatchString(id) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid order ID %q: expected a hex hash", id))
+			return
+		}
+		order, ok := manager.Order(common.HexToHash(id).Hex())
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Errorf("order %s is not tracked", id))
+			return
+		}
+		writeJSON(w, http.StatusOK, order)
+	})
+	mux.HandleFunc("POST /networks/{name}/pause", func(w http.ResponseWriter, r *http.Request) {
+		network, err := manager.PauseNetwork(r.PathValue("name"))
+		if err != nil {
+			writeNetworkError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, networkStatus{Network: network, Paused: true})
+	})
+	mux.HandleFunc("POST /networks/{name}/resume", func(w http.ResponseWriter, r *http.Request) {
+		network, released, err := manager.ResumeNetwork(r.PathValue("name"))
+		if err != nil {
+			writeNetworkError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, networkStatus{Network: network, Released: released})
+	})
+	mux.HandleFunc("GET /inventory", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string][]inventory.Balance{"inventory": manager.Inventory()})
+	})
+	mux.HandleFunc("GET /config", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, sanitizedConfig(cfg, manager.PausedNetworks()))
+	})
+	return requireToken(token, mux)
+}
+
+// requireToken refuses requests whose Authorization header is not "Bearer <token>"
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="solver-admin"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sanitizedConfig is cfg without anything secret: no keys, no admin token and no RPC URL paths or queries,
+// which is where providers put API keys
+func sanitizedConfig(cfg *config.Config, paused []string) configView {
+	view := configView{
+		LogLevel:       cfg.LogLevel,
+		LogFormat:      cfg.LogFormat,
+		MaxRetries:     cfg.MaxRetries,
+		Solvers:        cfg.Solvers,
+		MetricsAddr:    cfg.MetricsAddr,
+		AdminAddr:      cfg.AdminAddr,
+		PausedNetworks: paused,
+		Networks:       make(map[string]networkView, len(cfg.Networks)),
+	}
+	for name, network := range cfg.Networks {
+		rpcURLs := network.RPCURLs
+		if len(rpcURLs) == 0 && network.RPCURL != "" {
+			rpcURLs = []string{network.RPCURL}
+		}
+		redacted := make([]string, 0, len(rpcURLs))
+		for _, rpcURL := range rpcURLs {
+			redacted = append(redacted, rpcpool.RedactURL(rpcURL))
+		}
+		wsURL := ""
+		if network.WSURL != "" {
+			wsURL = rpcpool.RedactURL(network.WSURL)
+		}
+		view.Networks[name] = networkView{
+			Type:               network.Type,
+			ChainID:            network.ChainID,
+			HyperlaneDomain:    network.HyperlaneDomain,
+			HyperlaneAddress:   network.HyperlaneAddress,
+			RPCURLs:            redacted,
+			WSURL:              wsURL,
+			SolverStartBlock:   network.SolverStartBlock,
+			PollInterval:       network.PollInterval,
+			ConfirmationBlocks: network.ConfirmationBlocks,
+			MaxBlockRange:      network.MaxBlockRange,
+		}
+	}
+	return view
+}
+
+// writeNetworkError answers 404 for a network that is not configured, 500 otherwise
+func writeNetworkError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, config.ErrUnknownNetwork) {
+		status = http.StatusNotFound
+	}
+	writeError(w, status, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Serve serves handler on addr until ctx is done
+func Serve(ctx context.Context, addr string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("admin server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "s3cret-admin-token"
+
+const trackedID = "0x00000000000000000000000000000000000000000000000000000000000000ab"
+
+// fakeManager answers like a SolverManager that only knows Ethereum and Optimism
+type fakeManager struct {
+	orders []solvercore.TrackedOrder
+	paused map[string]bool
+}
+
+func newFakeManager() *fakeManager {
+	return &fakeManager{
+		orders: []solvercore.TrackedOrder{{OrderID: trackedID, Origin: "Ethereum", Destination: "Optimism", Status: solvercore.OrderStatusCompleted}},
+		paused: map[string]bool{},
+	}
+}
+
+func (f *fakeManager) Orders() []solvercore.TrackedOrder { return f.orders }
+
+func (f *fakeManager) Order(orderID string) (solvercore.TrackedOrder, bool) {
+	for _, order := range f.orders {
+		if order.OrderID == orderID {
+			return order, true
+		}
+	}
+	return solvercore.TrackedOrder{}, false
+}
+
+func (f *fakeManager) canonical(name string) (string, error) {
+	for _, known := range []string{"Ethereum", "Optimism"} {
+		if strings.EqualFold(name, known) {
+			return known, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", config.ErrUnknownNetwork, name)
+}
+
+func (f *fakeManager) PauseNetwork(name string) (string, error) {
+	network, err := f.canonical(name)
+	if err == nil {
+		f.paused[network] = true
+	}
+	return network, err
+}
+
+func (f *fakeManager) ResumeNetwork(name string) (string, int, error) {
+	network, err := f.canonical(name)
+	if err != nil {
+		return "", 0, err
+	}
+	delete(f.paused, network)
+	return network, 2, nil
+}
+
+func (f *fakeManager) PausedNetworks() []string {
+	var networks []string
+	for network := range f.paused {
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func (f *fakeManager) Inventory() []inventory.Balance {
+	return []inventory.Balance{{
+		Network: "Ethereum", Symbol: "DOG", Token: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		Balance: big.NewInt(1000), Reserved: big.NewInt(300), Available: big.NewInt(600), Reserve: big.NewInt(100),
+	}}
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		LogLevel:   "info",
+		AdminAddr:  "127.0.0.1:9465",
+		AdminToken: testToken,
+		Solvers:    map[string]config.SolverConfig{"hyperlane7683": {Enabled: true}},
+		Networks: map[string]config.NetworkConfig{
+			"Ethereum": {
+				Name:            "Ethereum",
+				Type:            config.NetworkTypeEVM,
+				RPCURL:          "https://eth-sepolia.g.alchemy.com/v2/alchemy-api-key",
+				RPCURLs:         []string{"https://eth-sepolia.g.alchemy.com/v2/alchemy-api-key", "https://backup.example/rpc?key=other-key"},
+				WSURL:           "wss://eth-sepolia.g.alchemy.com/v2/alchemy-api-key",
+				ChainID:         11155111,
+				HyperlaneDomain: 11155111,
+			},
+		},
+	}
+}
+
+func do(t *testing.T, handler http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerRequiresToken(t *testing.T) {
+	handler := Handler(newFakeManager(), testConfig(), testToken)
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"missing", ""},
+		{"wrong token", "Bearer nope"},
+		{"not bearer", "Basic " + testToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+		})
+	}
+
+	rec := do(t, Handler(newFakeManager(), testConfig(), ""), http.MethodGet, "/orders", "anything")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "an empty token refuses everything")
+}
+
+func TestHandlerOrders(t *testing.T) {
+	handler := Handler(newFakeManager(), testConfig(), testToken)
+
+	rec := do(t, handler, http.MethodGet, "/orders", testToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Orders []solvercore.TrackedOrder `json:"orders"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Orders, 1)
+	assert.Equal(t, trackedID, list.Orders[0].OrderID)
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"exact", trackedID, http.StatusOK},
+		{"short and upper case", "0xAB", http.StatusOK},
+		{"not tracked", "0x01", http.StatusNotFound},
+		{"not hex", "order-1", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, handler, http.MethodGet, "/orders/"+tt.id, testToken)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.status == http.StatusOK {
+				var order solvercore.TrackedOrder
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &order))
+				assert.Equal(t, solvercore.OrderStatusCompleted, order.Status)
+			}
+		})
+	}
+}
+
+func TestHandlerPauseResume(t *testing.T) {
+	manager := newFakeManager()
+	handler := Handler(manager, testConfig(), testToken)
+
+	rec := do(t, handler, http.MethodPost, "/networks/optimism/pause", testToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"network":"Optimism","paused":true}`, rec.Body.String())
+	assert.True(t, manager.paused["Optimism"])
+
+	rec = do(t, handler, http.MethodPost, "/networks/Optimism/resume", testToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"network":"Optimism","paused":false,"released":2}`, rec.Body.String())
+	assert.False(t, manager.paused["Optimism"])
+
+	rec = do(t, handler, http.MethodPost, "/networks/mars/pause", testToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"error"`)
+
+	rec = do(t, handler, http.MethodGet, "/networks/optimism/pause", testToken)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandlerInventory(t *testing.T) {
+	rec := do(t, Handler(newFakeManager(), testConfig(), testToken), http.MethodGet, "/inventory", testToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Inventory []inventory.Balance `json:"inventory"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Inventory, 1)
+	assert.Equal(t, "DOG", body.Inventory[0].Symbol)
+	assert.Equal(t, "300", body.Inventory[0].Reserved.String())
+	assert.Equal(t, "600", body.Inventory[0].Available.String())
+}
+
+func TestHandlerConfigIsSanitized(t *testing.T) {
+	manager := newFakeManager()
+	manager.paused["Optimism"] = true
+	handler := Handler(manager, testConfig(), testToken)
+
+	rec := do(t, handler, http.MethodGet, "/config", testToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.NotContains(t, body, testToken)
+	assert.NotContains(t, body, "alchemy-api-key")
+	assert.NotContains(t, body, "other-key")
+
+	var view configView
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &view))
+	assert.Equal(t, []string{"Optimism"}, view.PausedNetworks)
+	ethereum := view.Networks["Ethereum"]
+	assert.Equal(t, config.NetworkTypeEVM, ethereum.Type)
+	assert.Equal(t, []string{"https://eth-sepolia.g.alchemy.com", "https://backup.example"}, ethereum.RPCURLs)
+	assert.Equal(t, "wss://eth-sepolia.g.alchemy.com", ethereum.WSURL)
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
@@ -11,6 +12,10 @@ import (
 // Returns (settled, error) where settled=true means the order was fully settled
 type EventHandler func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error)
 
+// ErrOrderHeld is returned by an EventHandler that holds an order instead of processing it, as while its
+// destination is paused. The order is not handled yet, so listeners must hand it over again after a restart
+var ErrOrderHeld = errors.New("order held")
+
 // ShutdownFunc is a function that stops the listener
 type ShutdownFunc func()
 
@@ -34,6 +39,9 @@ type ListenerConfig struct {
 	PollInterval       int // milliseconds
 	ConfirmationBlocks uint64
 	MaxBlockRange      uint64
+	// FromBlock is the solver's --from-block for this network: when set, the listener starts there instead of
+	// resuming from its checkpoint
+	FromBlock *big.Int
 }
 
 // NewListenerConfig creates a new listener configuration
@@ -6,7 +6,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/joho/godotenv"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
 )
 
 // SolverConfig represents configuration for a single solver
@@ -20,6 +20,23 @@ type Config struct {
 	LogLevel   string                  `json:"logLevel"`
 	LogFormat  string                  `json:"logFormat"`
 	MaxRetries int                     `json:"maxRetries"`
+	// MetricsAddr is where the solver serves /metrics and /healthz (METRICS_ADDR); empty disables the server
+	MetricsAddr string `json:"metricsAddr"`
+	// AdminAddr is where the solver serves its admin API (ADMIN_ADDR); empty disables it. Requests must carry
+	// AdminToken (ADMIN_TOKEN) as a bearer token
+	AdminAddr  string `json:"adminAddr"`
+	AdminToken string `json:"-"`
+	// FromBlocks are the solver's --from-block overrides: where each named network's listener starts instead of
+	// resuming from its checkpoint
+	FromBlocks map[string]uint64 `json:"-"`
+	// FillPolicy is read from the POLICY_* variables by the solver (LoadFillPolicy)
+	FillPolicy FillPolicy `json:"-"`
+	// Inventory is read from the INVENTORY_* variables by the solver (LoadInventoryConfig)
+	Inventory InventoryConfig `json:"-"`
+	// DryRun evaluates orders without filling them or saving solver state (solver --dry-run)
+	DryRun bool `json:"dryRun"`
+	// Networks is built from the environment after .env is loaded; it is also published as the package's Networks
+	Networks map[string]NetworkConfig `json:"-"`
 }
 
 // Default solver configurations
@@ -32,9 +49,9 @@ var defaultSolvers = map[string]SolverConfig{
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	// Load .env file first
-	if err := godotenv.Load(); err != nil {
+	if err := solverdir.LoadEnv(); err != nil {
 		// Don't fail if .env doesn't exist, just log a warning
-		fmt.Printf("Warning: .env file not found: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
 
 	// Create config with defaults
@@ -65,6 +82,10 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	config.MetricsAddr = os.Getenv("METRICS_ADDR")
+	config.AdminAddr = os.Getenv("ADMIN_ADDR")
+	config.AdminToken = os.Getenv("ADMIN_TOKEN")
+
 	// Allow environment variable override for solver enable/disable
 	// Format: SOLVER_HYPERLANE7683_ENABLED=true/false
 	for solverName := range config.Solvers {
@@ -80,9 +101,25 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Rebuild networks now that .env is loaded, in case anything initialized them from the bare environment
+	config.Networks = ReloadNetworks()
+
 	return config, nil
 }
 
+// Network returns the configuration of networkName
+func (c *Config) Network(networkName string) (NetworkConfig, error) {
+	if network, exists := c.Networks[networkName]; exists {
+		return network, nil
+	}
+	return NetworkConfig{}, unknownNetworkError(networkName, c.Networks)
+}
+
+// NetworkNames returns the configured network names in alphabetical order
+func (c *Config) NetworkNames() []string {
+	return sortedNames(c.Networks)
+}
+
 // IsSolverEnabled checks if a solver is enabled
 func (c *Config) IsSolverEnabled(solverName string) bool {
 	solver, exists := c.Solvers[solverName]
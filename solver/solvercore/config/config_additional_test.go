@@ -170,6 +170,7 @@ func TestInitializeNetworks(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		// Verify that networks were initialized
@@ -208,6 +209,7 @@ func TestGetNetworkConfig(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		config, err := GetNetworkConfig("Base")
@@ -252,6 +254,7 @@ func TestGetRPCURL(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		url, err := GetRPCURL("Base")
@@ -293,6 +296,7 @@ func TestGetChainID(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		chainID, err := GetChainID("Base")
@@ -334,6 +338,7 @@ func TestGetHyperlaneAddress(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		address, err := GetHyperlaneAddress("Base")
@@ -375,6 +380,7 @@ func TestGetHyperlaneDomain(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		domain, err := GetHyperlaneDomain("Base")
@@ -416,6 +422,7 @@ func TestGetForkStartBlock(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		block, err := GetForkStartBlock("Base")
@@ -457,6 +464,7 @@ func TestGetSolverStartBlock(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		block, err := GetSolverStartBlock("Base")
@@ -498,6 +506,7 @@ func TestGetListenerConfig(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		pollInterval, confirmationBlocks, maxBlockRange, err := GetListenerConfig("Base")
@@ -543,6 +552,7 @@ func TestGetRPCURLByChainID(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		url, err := GetRPCURLByChainID(84532)
@@ -584,6 +594,7 @@ func TestGetHyperlaneAddressByChainID(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		address, err := GetHyperlaneAddressByChainID(84532)
@@ -625,6 +636,7 @@ func TestGetNetworkNames(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		names := GetNetworkNames()
@@ -660,6 +672,7 @@ func TestValidateNetworkName(t *testing.T) {
 			os.Unsetenv("LOCAL_BASE_MAX_BLOCK_RANGE")
 		}()
 
+		ResetNetworks()
 		InitializeNetworks()
 
 		valid := ValidateNetworkName("Base")
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Inventory environment variables. Tokens are <network>:<token> as in the fill policy, amounts in base units
+const (
+	// InventoryReserveEnv keeps a buffer of each token that fills never spend: <network>:<token>=<amount>,...
+	InventoryReserveEnv = "INVENTORY_RESERVE"
+	// InventoryLowThresholdEnv warns when a token's available inventory drops below it: <network>:<token>=<amount>,...
+	InventoryLowThresholdEnv = "INVENTORY_LOW_THRESHOLD"
+	// InventoryRefreshIntervalEnv is how often balances are read back from the chains, e.g. 30s (default 1m)
+	InventoryRefreshIntervalEnv = "INVENTORY_REFRESH_INTERVAL"
+	// InventoryAutoRebalanceEnv hands low tokens to the rebalance hook when true
+	InventoryAutoRebalanceEnv = "INVENTORY_AUTO_REBALANCE"
+)
+
+// DefaultInventoryRefreshInterval is used when INVENTORY_REFRESH_INTERVAL is unset
+const DefaultInventoryRefreshInterval = time.Minute
+
+// InventoryConfig sets how the solver accounts for its token balances. Tokens are keyed by both chain ID and
+// Hyperlane domain, like the fill policy's
+type InventoryConfig struct {
+	Reserve         map[TokenKey]*big.Int
+	LowThreshold    map[TokenKey]*big.Int
+	RefreshInterval time.Duration
+	AutoRebalance   bool
+}
+
+// LoadInventoryConfig reads the INVENTORY_* variables, resolving network names against networks
+func LoadInventoryConfig(networks map[string]NetworkConfig) (InventoryConfig, error) {
+	reserve, err := parseTokenAmounts(networks, InventoryReserveEnv)
+	if err != nil {
+		return InventoryConfig{}, err
+	}
+	lowThreshold, err := parseTokenAmounts(networks, InventoryLowThresholdEnv)
+	if err != nil {
+		return InventoryConfig{}, err
+	}
+	cfg := InventoryConfig{Reserve: reserve, LowThreshold: lowThreshold, RefreshInterval: DefaultInventoryRefreshInterval}
+
+	if value := strings.TrimSpace(os.Getenv(InventoryRefreshIntervalEnv)); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return InventoryConfig{}, fmt.Errorf("invalid %s %q: expected a positive duration such as 30s", InventoryRefreshIntervalEnv, value)
+		}
+		cfg.RefreshInterval = interval
+	}
+	if value := strings.TrimSpace(os.Getenv(InventoryAutoRebalanceEnv)); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return InventoryConfig{}, fmt.Errorf("invalid %s %q: expected true or false", InventoryAutoRebalanceEnv, value)
+		}
+		cfg.AutoRebalance = enabled
+	}
+	return cfg, nil
+}
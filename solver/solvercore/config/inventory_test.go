@@ -0,0 +1,59 @@
+package config
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadInventoryConfig(t *testing.T) {
+	networks := map[string]NetworkConfig{
+		"Base":     {Name: "Base", ChainID: 84532, HyperlaneDomain: 84532},
+		"Starknet": {Name: "Starknet", ChainID: 393402133025997798, HyperlaneDomain: 23448594291968334},
+	}
+	dog := NormalizeTokenAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := LoadInventoryConfig(networks)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Reserve)
+		assert.Empty(t, cfg.LowThreshold)
+		assert.Equal(t, DefaultInventoryRefreshInterval, cfg.RefreshInterval)
+		assert.False(t, cfg.AutoRebalance)
+	})
+
+	t.Run("every setting", func(t *testing.T) {
+		t.Setenv(InventoryReserveEnv, "base:0x5FbDB2315678afecb367f032d93F642f64180aa3=100")
+		t.Setenv(InventoryLowThresholdEnv, "starknet:0x5FbDB2315678afecb367f032d93F642f64180aa3=2500")
+		t.Setenv(InventoryRefreshIntervalEnv, "15s")
+		t.Setenv(InventoryAutoRebalanceEnv, "true")
+
+		cfg, err := LoadInventoryConfig(networks)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(100), cfg.Reserve[TokenKey{ChainID: 84532, Token: dog}])
+		assert.Equal(t, big.NewInt(2500), cfg.LowThreshold[TokenKey{ChainID: 23448594291968334, Token: dog}])
+		assert.Equal(t, 15*time.Second, cfg.RefreshInterval)
+		assert.True(t, cfg.AutoRebalance)
+	})
+
+	invalid := []struct {
+		env, value, wantErr string
+	}{
+		{InventoryReserveEnv, "base:0xabc", "expected <network>:<token>=<amount>"},
+		{InventoryLowThresholdEnv, "mars:0xabc=1", "network not found"},
+		{InventoryRefreshIntervalEnv, "0s", "positive duration"},
+		{InventoryRefreshIntervalEnv, "often", "positive duration"},
+		{InventoryAutoRebalanceEnv, "sometimes", "true or false"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			_, err := LoadInventoryConfig(networks)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
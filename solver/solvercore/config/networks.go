@@ -1,7 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 )
@@ -36,13 +44,49 @@ const (
 	StarknetDefaultPollIntervalMs = 2000
 	DefaultMaxBlockRange          = 10
 	StarknetDefaultMaxBlockRange  = 100
+
+	// Default destination gas set on the Starknet Hyperlane7683 per destination kind
+	DefaultEVMDestinationGas      = 64000
+	DefaultStarknetDestinationGas = 100000
+)
+
+// NetworkType is the Hyperlane7683 a network runs: the Solidity contract on EVM chains, the Cairo one on
+// Starknet and Ztarknet
+type NetworkType string
+
+const (
+	NetworkTypeEVM      NetworkType = "evm"
+	NetworkTypeStarknet NetworkType = "starknet"
+	// NetworkTypeZtarknet runs the Cairo contracts like Starknet, but reads ZTARKNET_* without LOCAL_ variants
+	NetworkTypeZtarknet NetworkType = "ztarknet"
 )
 
+// IsCairo reports whether networks of type t run the Cairo contracts
+func (t NetworkType) IsCairo() bool {
+	return t == NetworkTypeStarknet || t == NetworkTypeZtarknet
+}
+
+// Label is the network type as written in messages
+func (t NetworkType) Label() string {
+	switch t {
+	case NetworkTypeStarknet:
+		return "Starknet"
+	case NetworkTypeZtarknet:
+		return "Ztarknet"
+	default:
+		return "EVM"
+	}
+}
+
 // NetworkConfig represents a single network configuration
 type NetworkConfig struct {
 	Name             string
+	Type             NetworkType
 	RPCURL           string
+	RPCURLs          []string // RPC endpoints in order of preference (<NETWORK>_RPC_URLS); RPCURL is the first
+	WSURL            string   // Optional websocket endpoint for event subscriptions (<NETWORK>_WS_URL)
 	ChainID          uint64
+	StarknetChainID  string // Cairo node's starknet_chainId as a 0x felt, when <NETWORK>_CHAIN_ID gives it (see starknet_chain_id.go)
 	HyperlaneAddress string
 	HyperlaneDomain  uint64 // Changed to uint64 to match new_code
 	ForkStartBlock   uint64
@@ -51,6 +95,9 @@ type NetworkConfig struct {
 	PollInterval       int    // milliseconds, 0 = use default
 	ConfirmationBlocks uint64 // 0 = use default
 	MaxBlockRange      uint64 // 0 = use default
+	// Destination gas the Starknet-side routers set for messages to this network
+	// (EVM_DESTINATION_GAS / STARKNET_DESTINATION_GAS)
+	DestinationGas uint64
 }
 
 // GetConditionalAccountEnv gets account-related environment variables based on IS_DEVNET flag
@@ -61,24 +108,44 @@ func GetConditionalAccountEnv(key string) string {
 	return envutil.GetConditionalAccountEnv(key)
 }
 
-// networksInitialized tracks whether networks have been initialized from env vars
-var networksInitialized = false
+// ErrUnknownNetwork is returned for a network name or chain ID that is not configured
+var ErrUnknownNetwork = errors.New("network not found")
+
+var (
+	// networksMu serializes building Networks; a build replaces the map rather than updating it
+	networksMu sync.Mutex
+	// networksInitialized tracks whether networks have been initialized from env vars
+	networksInitialized = false
+)
 
-// InitializeNetworks must be called after loading .env file to ensure proper config
+// InitializeNetworks builds Networks from the environment on the first call and is a no-op afterwards, so any
+// tool or goroutine can call it. LoadConfig rebuilds Networks once .env is loaded, so the call order no longer matters
+// for callers going through LoadConfig
 func InitializeNetworks() {
-	if !networksInitialized {
-		initializeNetworks()
-	}
+	ensureInitialized()
+}
+
+// ReloadNetworks rebuilds Networks from the current environment and returns it
+func ReloadNetworks() map[string]NetworkConfig {
+	networksMu.Lock()
+	defer networksMu.Unlock()
+	initializeNetworks()
+	return Networks
 }
 
 // ResetNetworks resets the networks cache to allow re-initialization
 func ResetNetworks() {
+	networksMu.Lock()
+	defer networksMu.Unlock()
 	networksInitialized = false
 	Networks = nil
+	CustomDomains = nil
 }
 
 // ensureInitialized initializes networks if not already done (fallback for legacy usage)
 func ensureInitialized() {
+	networksMu.Lock()
+	defer networksMu.Unlock()
 	if !networksInitialized {
 		initializeNetworks()
 	}
@@ -87,11 +154,52 @@ func ensureInitialized() {
 // Networks contains all network configurations
 var Networks map[string]NetworkConfig
 
+// CustomDomainEnvPrefix prefixes the env vars setting Hyperlane domains, e.g. CUSTOM_DOMAIN_BASE=84532 overrides
+// Base's and CUSTOM_DOMAIN_MYCHAIN=12345 adds one for a network without a configuration
+const CustomDomainEnvPrefix = "CUSTOM_DOMAIN_"
+
+// CustomDomains holds the CUSTOM_DOMAIN_<NAME> domains of networks that are not configured, by upper-case name.
+// Values that are not numbers are kept as 0, so orders to them are refused
+var CustomDomains map[string]uint64
+
 // initializeNetworks initializes the network configurations from environment variables
 func initializeNetworks() {
-	Networks = map[string]NetworkConfig{
+	Networks = buildNetworks()
+	CustomDomains = applyCustomDomains(Networks, os.Environ())
+	networksInitialized = true
+}
+
+// applyCustomDomains sets the CUSTOM_DOMAIN_<NAME> domains found in environ on networks and returns those naming
+// no network
+func applyCustomDomains(networks map[string]NetworkConfig, environ []string) map[string]uint64 {
+	custom := make(map[string]uint64)
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		name, ok := strings.CutPrefix(key, CustomDomainEnvPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		domain, err := strconv.ParseUint(strings.TrimSpace(value), 0, 64)
+		if err != nil {
+			domain = 0
+		}
+		if networkName, err := canonicalName(networks, name); err == nil {
+			network := networks[networkName]
+			network.HyperlaneDomain = domain
+			networks[networkName] = network
+			continue
+		}
+		custom[strings.ToUpper(name)] = domain
+	}
+	return custom
+}
+
+// buildNetworks reads the network configurations from environment variables
+func buildNetworks() map[string]NetworkConfig {
+	networks := map[string]NetworkConfig{
 		"Ethereum": {
 			Name:               "Ethereum",
+			Type:               NetworkTypeEVM,
 			RPCURL:             envutil.GetConditionalEnv("ETHEREUM_RPC_URL", "http://localhost:8545"),
 			ChainID:            envutil.GetEnvUint64Any([]string{"ETHEREUM_CHAIN_ID", "SEPOLIA_CHAIN_ID"}, EthereumSepoliaChainID),
 			HyperlaneAddress:   envutil.GetEnvWithDefault("EVM_HYPERLANE_ADDRESS", "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"),
@@ -101,9 +209,11 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			DestinationGas:     envutil.GetEnvUint64("EVM_DESTINATION_GAS", DefaultEVMDestinationGas),
 		},
 		"Optimism": {
 			Name:               "Optimism",
+			Type:               NetworkTypeEVM,
 			RPCURL:             envutil.GetConditionalEnv("OPTIMISM_RPC_URL", "http://localhost:8546"),
 			ChainID:            envutil.GetEnvUint64("OPTIMISM_CHAIN_ID", OptimismSepoliaChainID),
 			HyperlaneAddress:   envutil.GetEnvWithDefault("EVM_HYPERLANE_ADDRESS", "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"),
@@ -113,9 +223,11 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			DestinationGas:     envutil.GetEnvUint64("EVM_DESTINATION_GAS", DefaultEVMDestinationGas),
 		},
 		"Arbitrum": {
 			Name:               "Arbitrum",
+			Type:               NetworkTypeEVM,
 			RPCURL:             envutil.GetConditionalEnv("ARBITRUM_RPC_URL", "http://localhost:8547"),
 			ChainID:            envutil.GetEnvUint64("ARBITRUM_CHAIN_ID", ArbitrumSepoliaChainID),
 			HyperlaneAddress:   envutil.GetEnvWithDefault("EVM_HYPERLANE_ADDRESS", "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"),
@@ -125,9 +237,11 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			DestinationGas:     envutil.GetEnvUint64("EVM_DESTINATION_GAS", DefaultEVMDestinationGas),
 		},
 		"Base": {
 			Name:               "Base",
+			Type:               NetworkTypeEVM,
 			RPCURL:             envutil.GetConditionalEnv("BASE_RPC_URL", "http://localhost:8548"),
 			ChainID:            envutil.GetEnvUint64("BASE_CHAIN_ID", BaseSepoliaChainID),
 			HyperlaneAddress:   envutil.GetEnvWithDefault("EVM_HYPERLANE_ADDRESS", "0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"),
@@ -137,9 +251,11 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			DestinationGas:     envutil.GetEnvUint64("EVM_DESTINATION_GAS", DefaultEVMDestinationGas),
 		},
 		"Starknet": {
 			Name:               "Starknet",
+			Type:               NetworkTypeStarknet,
 			RPCURL:             envutil.GetConditionalEnv("STARKNET_RPC_URL", "http://localhost:5050"),
 			ChainID:            envutil.GetEnvUint64("STARKNET_CHAIN_ID", StarknetSepoliaChainID),
 			HyperlaneAddress:   envutil.GetEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", ""),
@@ -150,21 +266,189 @@ func initializeNetworks() {
 			ConfirmationBlocks: envutil.GetEnvUint64("STARKNET_CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange: envutil.GetEnvUint64("STARKNET_MAX_BLOCK_RANGE",
 				envutil.GetEnvUint64("MAX_BLOCK_RANGE", StarknetDefaultMaxBlockRange)),
+			DestinationGas: envutil.GetEnvUint64("STARKNET_DESTINATION_GAS", DefaultStarknetDestinationGas),
 		},
 		"Ztarknet": {
 			Name:               "Ztarknet",
+			Type:               NetworkTypeZtarknet,
 			RPCURL:             envutil.GetEnvWithDefault("ZTARKNET_RPC_URL", "https://ztarknet-madara.d.karnot.xyz"),
 			ChainID:            envutil.GetEnvUint64("ZTARKNET_CHAIN_ID", ZtarknetTestnetChainID),
 			HyperlaneAddress:   envutil.GetEnvWithDefault("ZTARKNET_HYPERLANE_ADDRESS", ""),
-			HyperlaneDomain:    envutil.GetEnvUint64("ZTARKNET_DOMAIN_ID", ZtarknetTestnetChainID),
+			HyperlaneDomain:    envutil.GetEnvUint64Any([]string{"ZTARKNET_DOMAIN_ID", "ZTARKNET_HYPERLANE_DOMAIN"}, ZtarknetTestnetChainID),
 			ForkStartBlock:     envutil.GetEnvUint64("ZTARKNET_SOLVER_START_BLOCK", ZtarknetDefaultStartBlock),
 			SolverStartBlock:   int64(envutil.GetEnvUint64("ZTARKNET_SOLVER_START_BLOCK", ZtarknetDefaultStartBlock)),
 			PollInterval:       envutil.GetEnvInt("ZTARKNET_POLL_INTERVAL_MS", envutil.GetEnvInt("POLL_INTERVAL_MS", StarknetDefaultPollIntervalMs)),
 			ConfirmationBlocks: envutil.GetEnvUint64("ZTARKNET_CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("ZTARKNET_MAX_BLOCK_RANGE", envutil.GetEnvUint64("MAX_BLOCK_RANGE", StarknetDefaultMaxBlockRange)),
+			DestinationGas:     envutil.GetEnvUint64("STARKNET_DESTINATION_GAS", DefaultStarknetDestinationGas),
 		},
 	}
-	networksInitialized = true
+	for name, network := range networks {
+		list := envutil.GetConditionalEnv(RPCURLsEnv(name), "")
+		network.WSURL = envutil.GetConditionalEnv(WSURLEnv(name), "")
+		if network.Type == NetworkTypeZtarknet {
+			// Ztarknet has no local variant: its RPC URL is not switched by IS_DEVNET either
+			list = os.Getenv(RPCURLsEnv(name))
+			network.WSURL = os.Getenv(WSURLEnv(name))
+		}
+		network.RPCURLs = rpcEndpoints(name, list, network.RPCURL)
+		network.RPCURL = network.RPCURLs[0]
+		if network.Type.IsCairo() {
+			network.ChainID, network.StarknetChainID = cairoChainID(os.Getenv(strings.ToUpper(name)+"_CHAIN_ID"), network.ChainID)
+		}
+		networks[name] = network
+	}
+	return networks
+}
+
+// RPCURLsEnv is the env var listing networkName's RPC endpoints, comma-separated in order of preference, e.g.
+// ETHEREUM_RPC_URLS. Like the single RPC URL it is read with a LOCAL_ prefix when IS_DEVNET=true
+func RPCURLsEnv(networkName string) string {
+	return strings.ToUpper(networkName) + "_RPC_URLS"
+}
+
+// WSURLEnv is the env var giving networkName's websocket endpoint, e.g. BASE_WS_URL, read with a LOCAL_ prefix
+// when IS_DEVNET=true like the RPC URL
+func WSURLEnv(networkName string) string {
+	return strings.ToUpper(networkName) + "_WS_URL"
+}
+
+// RPCURLOverrideEnv is the env var --rpc-url sets for networkName; RPCURLOverrideAllEnv applies to every network
+func RPCURLOverrideEnv(networkName string) string {
+	return strings.ToUpper(networkName) + "_RPC_URL_OVERRIDE"
+}
+
+// RPCURLOverrideAllEnv is the env var a --rpc-url without a network name sets
+const RPCURLOverrideAllEnv = "RPC_URL_OVERRIDE"
+
+// RPCURLFlag overrides the RPC endpoints of a network for this run: --rpc-url <network>=<url>, or --rpc-url <url>
+// for every network of a single-network tool
+const RPCURLFlag = "--rpc-url"
+
+// ExtractRPCURLFlags removes the --rpc-url flags (with a separate or =value) from args and exports them as the
+// override env vars, so the networks built afterwards use them instead of their configured endpoints
+func ExtractRPCURLFlags(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		value, ok := strings.CutPrefix(args[i], RPCURLFlag+"=")
+		if !ok {
+			if args[i] != RPCURLFlag {
+				rest = append(rest, args[i])
+				continue
+			}
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires [<network>=]<url>", RPCURLFlag)
+			}
+			i++
+			value = args[i]
+		}
+
+		env := RPCURLOverrideAllEnv
+		rpcURL := value
+		if name, endpoint, found := strings.Cut(value, "="); found && !strings.Contains(name, "://") {
+			networkName, err := canonicalNetworkName(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", RPCURLFlag, value, err)
+			}
+			env, rpcURL = RPCURLOverrideEnv(networkName), endpoint
+		}
+		if parsed, err := url.Parse(rpcURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid %s %q: expected [<network>=]<url>", RPCURLFlag, value)
+		}
+		if err := os.Setenv(env, rpcURL); err != nil {
+			return nil, err
+		}
+	}
+	return rest, nil
+}
+
+// ParseRPCURLFlags removes the --rpc-url flags from os.Args, exiting on an invalid one. Tools call it first in
+// main, with solverdir.ParseOSArgs
+func ParseRPCURLFlags() {
+	args, err := ExtractRPCURLFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+	os.Args = append(os.Args[:1], args...)
+}
+
+// CanonicalNetworkName returns the configured network named name, case-insensitively
+func CanonicalNetworkName(name string) (string, error) {
+	ensureInitialized()
+	return canonicalName(Networks, name)
+}
+
+// DestinationWeightsEnv weights the random destination picks of the order tools by network, e.g.
+// "Base=5,Arbitrum=1". Networks it leaves out weigh 1, and a network weighing 0 is never picked
+const DestinationWeightsEnv = "DESTINATION_WEIGHTS"
+
+// DestinationWeights returns the DESTINATION_WEIGHTS weights keyed by configured network name, nil when unset
+func DestinationWeights() (map[string]int, error) {
+	return ParseDestinationWeights(os.Getenv(DestinationWeightsEnv))
+}
+
+// ParseDestinationWeights parses comma-separated <network>=<weight> entries into weights keyed by configured
+// network name; the names are case-insensitive and the weights non-negative integers
+func ParseDestinationWeights(value string) (map[string]int, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	weights := map[string]int{}
+	for _, entry := range strings.Split(value, ",") {
+		name, weightText, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid %s entry %q: expected <network>=<weight>", DestinationWeightsEnv, entry)
+		}
+		networkName, err := CanonicalNetworkName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", DestinationWeightsEnv, entry, err)
+		}
+		if _, dup := weights[networkName]; dup {
+			return nil, fmt.Errorf("%s weighs %s twice", DestinationWeightsEnv, networkName)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightText))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid %s weight %q for %s: expected a non-negative integer", DestinationWeightsEnv, weightText, networkName)
+		}
+		weights[networkName] = weight
+	}
+	return weights, nil
+}
+
+// canonicalNetworkName returns the configured network named name, case-insensitively
+func canonicalNetworkName(name string) (string, error) {
+	return canonicalName(buildNetworks(), name)
+}
+
+// canonicalName returns the network of networks named name, case-insensitively
+func canonicalName(networks map[string]NetworkConfig, name string) (string, error) {
+	for networkName := range networks {
+		if strings.EqualFold(networkName, name) {
+			return networkName, nil
+		}
+	}
+	return "", unknownNetworkError(name, networks)
+}
+
+// rpcEndpoints returns the RPC endpoints of networkName: a --rpc-url override alone, else the entries of list,
+// else primary
+func rpcEndpoints(networkName, list, primary string) []string {
+	for _, override := range []string{os.Getenv(RPCURLOverrideEnv(networkName)), os.Getenv(RPCURLOverrideAllEnv)} {
+		if override = strings.TrimSpace(override); override != "" {
+			return []string{override}
+		}
+	}
+	var urls []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			urls = append(urls, entry)
+		}
+	}
+	if len(urls) == 0 {
+		return []string{primary}
+	}
+	return urls
 }
 
 // GetNetworkConfig returns the configuration for a given network name
@@ -173,7 +457,26 @@ func GetNetworkConfig(networkName string) (NetworkConfig, error) {
 	if config, exists := Networks[networkName]; exists {
 		return config, nil
 	}
-	return NetworkConfig{}, fmt.Errorf("network not found: %s", networkName)
+	return NetworkConfig{}, unknownNetworkError(networkName, Networks)
+}
+
+// unknownNetworkError reports networkName as unknown, listing the configured networks
+func unknownNetworkError(networkName string, networks map[string]NetworkConfig) error {
+	known := make([]string, 0, len(networks))
+	for _, name := range sortedNames(networks) {
+		known = append(known, fmt.Sprintf("%s (domain %d)", name, networks[name].HyperlaneDomain))
+	}
+	return fmt.Errorf("%w: %s (known: %s)", ErrUnknownNetwork, networkName, strings.Join(known, ", "))
+}
+
+// sortedNames returns the names of networks in alphabetical order
+func sortedNames(networks map[string]NetworkConfig) []string {
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // GetRPCURL returns the RPC URL for a given network name
@@ -203,13 +506,51 @@ func GetHyperlaneAddress(networkName string) (string, error) {
 	return config.HyperlaneAddress, nil
 }
 
-// GetHyperlaneDomain returns the Hyperlane domain ID for a given network name
-func GetHyperlaneDomain(networkName string) (uint64, error) { // Changed to uint64 to match new_code
+// GetHyperlaneDomain returns the Hyperlane domain ID for a given network name: a configured network's, else a
+// domain added with CUSTOM_DOMAIN_<NAME>
+func GetHyperlaneDomain(networkName string) (uint64, error) {
 	config, err := GetNetworkConfig(networkName)
+	if err == nil {
+		return config.HyperlaneDomain, nil
+	}
+	networksMu.Lock()
+	domain, ok := CustomDomains[strings.ToUpper(networkName)]
+	networksMu.Unlock()
+	if ok {
+		return domain, nil
+	}
+	return 0, err
+}
+
+// DestinationDomain returns the Hyperlane domain of destination for an order opened on originDomain. Both must be
+// set and distinct: an order to domain 0, or back to its origin, is accepted on-chain but can never be filled
+func DestinationDomain(originDomain uint32, destination string) (uint32, error) {
+	domain, err := GetHyperlaneDomain(destination)
+	if err != nil {
+		return 0, err
+	}
+	if originDomain == 0 {
+		return 0, errors.New("origin Hyperlane domain is 0")
+	}
+	domain32, err := NetworkConfig{Name: destination, HyperlaneDomain: domain}.Domain()
 	if err != nil {
 		return 0, err
 	}
-	return config.HyperlaneDomain, nil
+	if domain32 == originDomain {
+		return 0, fmt.Errorf("%s has the origin's Hyperlane domain %d: origin and destination must differ", destination, domain)
+	}
+	return domain32, nil
+}
+
+// Domain returns the network's Hyperlane domain as the uint32 the contracts use, refusing 0 and wider values
+func (n NetworkConfig) Domain() (uint32, error) {
+	switch {
+	case n.HyperlaneDomain == 0:
+		return 0, fmt.Errorf("%s has Hyperlane domain 0 (set %s_DOMAIN_ID or %s%s)", n.Name, strings.ToUpper(n.Name), CustomDomainEnvPrefix, strings.ToUpper(n.Name))
+	case n.HyperlaneDomain > math.MaxUint32:
+		return 0, fmt.Errorf("%s Hyperlane domain %d does not fit in 32 bits", n.Name, n.HyperlaneDomain)
+	}
+	return uint32(n.HyperlaneDomain), nil
 }
 
 // GetForkStartBlock returns the fork start block for a given network name
@@ -239,24 +580,80 @@ func GetListenerConfig(networkName string) (int, uint64, uint64, error) {
 	return config.PollInterval, config.ConfirmationBlocks, config.MaxBlockRange, nil
 }
 
+// IsStarknetNetwork reports whether a network runs the Cairo contracts rather than the EVM ones
+func IsStarknetNetwork(networkName string) bool {
+	return NetworkTypeOf(networkName).IsCairo()
+}
+
+// NetworkTypeOf returns the type of the network named networkName (case-insensitive); names that are not
+// configured, such as CUSTOM_DOMAIN_<NAME> networks, are EVM
+func NetworkTypeOf(networkName string) NetworkType {
+	ensureInitialized()
+	if name, err := canonicalName(Networks, networkName); err == nil {
+		return Networks[name].Type
+	}
+	return NetworkTypeEVM
+}
+
+// GetNetworksByType returns the configured networks of type t in alphabetical order
+func GetNetworksByType(t NetworkType) []NetworkConfig {
+	ensureInitialized()
+	var networks []NetworkConfig
+	for _, name := range sortedNames(Networks) {
+		if Networks[name].Type == t {
+			networks = append(networks, Networks[name])
+		}
+	}
+	return networks
+}
+
+// GetCairoNetworks returns the configured Starknet and Ztarknet networks in alphabetical order
+func GetCairoNetworks() []NetworkConfig {
+	return append(GetNetworksByType(NetworkTypeStarknet), GetNetworksByType(NetworkTypeZtarknet)...)
+}
+
 // GetRPCURLByChainID returns the RPC URL for a given chain ID
 func GetRPCURLByChainID(chainID uint64) (string, error) {
+	ensureInitialized()
 	for _, network := range Networks {
 		if network.ChainID == chainID {
 			return network.RPCURL, nil
 		}
 	}
-	return "", fmt.Errorf("network not found for chain ID: %d", chainID)
+	return "", fmt.Errorf("%w for chain ID: %d", ErrUnknownNetwork, chainID)
 }
 
 // GetHyperlaneAddressByChainID returns the Hyperlane address for a given chain ID
 func GetHyperlaneAddressByChainID(chainID uint64) (string, error) {
+	ensureInitialized()
 	for _, network := range Networks {
 		if network.ChainID == chainID {
 			return network.HyperlaneAddress, nil
 		}
 	}
-	return "", fmt.Errorf("network not found for chain ID: %d", chainID)
+	return "", fmt.Errorf("%w for chain ID: %d", ErrUnknownNetwork, chainID)
+}
+
+// GetNetworkTypeByChainID returns the type of the network with a given chain ID
+func GetNetworkTypeByChainID(chainID uint64) (NetworkType, error) {
+	ensureInitialized()
+	for _, network := range Networks {
+		if network.ChainID == chainID {
+			return network.Type, nil
+		}
+	}
+	return "", fmt.Errorf("%w for chain ID: %d", ErrUnknownNetwork, chainID)
+}
+
+// GetNetworkNameByChainID returns the name of the network with a given chain ID
+func GetNetworkNameByChainID(chainID uint64) (string, error) {
+	ensureInitialized()
+	for _, network := range Networks {
+		if network.ChainID == chainID {
+			return network.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w for chain ID: %d", ErrUnknownNetwork, chainID)
 }
 
 // GetNetworkNames returns all available network names
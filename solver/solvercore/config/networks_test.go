@@ -2,10 +2,14 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConditionalEnvironment(t *testing.T) {
@@ -153,3 +157,313 @@ func TestUtilityFunctions(t *testing.T) {
 	// Note: parseUint64 is now internal to envutil package, so we test it indirectly
 	// through the public functions that use it
 }
+
+func TestDestinationGas(t *testing.T) {
+	t.Run("defaults per destination kind", func(t *testing.T) {
+		ResetNetworks()
+		InitializeNetworks()
+		defer ResetNetworks()
+
+		assert.Equal(t, uint64(DefaultEVMDestinationGas), Networks["Base"].DestinationGas)
+		assert.Equal(t, uint64(DefaultStarknetDestinationGas), Networks["Starknet"].DestinationGas)
+		assert.Equal(t, uint64(DefaultStarknetDestinationGas), Networks["Ztarknet"].DestinationGas)
+	})
+
+	t.Run("env overrides", func(t *testing.T) {
+		t.Setenv("EVM_DESTINATION_GAS", "70000")
+		t.Setenv("STARKNET_DESTINATION_GAS", "120000")
+		ResetNetworks()
+		InitializeNetworks()
+		defer ResetNetworks()
+
+		assert.Equal(t, uint64(70000), Networks["Ethereum"].DestinationGas)
+		assert.Equal(t, uint64(120000), Networks["Starknet"].DestinationGas)
+	})
+
+	t.Run("IsStarknetNetwork", func(t *testing.T) {
+		assert.True(t, IsStarknetNetwork("Starknet"))
+		assert.True(t, IsStarknetNetwork("Ztarknet"))
+		assert.False(t, IsStarknetNetwork("Base"))
+	})
+}
+
+func TestNetworkModes(t *testing.T) {
+	t.Setenv("LOCAL_BASE_RPC_URL", "http://localhost:9548")
+	t.Setenv("BASE_RPC_URL", "https://base-sepolia.example")
+	t.Setenv("BASE_SOLVER_START_BLOCK", "")
+	t.Setenv("LOCAL_BASE_SOLVER_START_BLOCK", "")
+	t.Cleanup(ResetNetworks)
+
+	tests := []struct {
+		name       string
+		devnet     string
+		rpcURL     string
+		startBlock uint64
+	}{
+		{name: "fork", devnet: "true", rpcURL: "http://localhost:9548", startBlock: BaseLocalStartBlock},
+		{name: "live", devnet: "false", rpcURL: "https://base-sepolia.example", startBlock: BaseDefaultStartBlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("IS_DEVNET", tt.devnet)
+			networks := ReloadNetworks()
+
+			assert.Equal(t, tt.rpcURL, networks["Base"].RPCURL)
+			assert.Equal(t, tt.startBlock, networks["Base"].ForkStartBlock)
+			rpcURL, err := GetRPCURL("Base")
+			require.NoError(t, err)
+			assert.Equal(t, tt.rpcURL, rpcURL, "the package accessors see the rebuilt networks")
+		})
+	}
+}
+
+func TestUnknownNetwork(t *testing.T) {
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+
+	_, err := GetNetworkConfig("Mars")
+	require.ErrorIs(t, err, ErrUnknownNetwork)
+	assert.ErrorContains(t, err, "network not found: Mars (known: Arbitrum (domain 421614), Base (domain 84532), Ethereum (domain 11155111)")
+
+	ResetNetworks()
+	_, err = GetRPCURLByChainID(1)
+	assert.ErrorIs(t, err, ErrUnknownNetwork)
+	_, err = GetHyperlaneAddressByChainID(1)
+	assert.ErrorIs(t, err, ErrUnknownNetwork)
+
+	ResetNetworks()
+	rpcURL, err := GetRPCURLByChainID(BaseSepoliaChainID)
+	require.NoError(t, err, "lookups by chain ID initialize networks")
+	assert.NotEmpty(t, rpcURL)
+
+	cfg := &Config{Networks: ReloadNetworks()}
+	_, err = cfg.Network("mars")
+	assert.ErrorIs(t, err, ErrUnknownNetwork)
+	network, err := cfg.Network("Base")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(BaseSepoliaChainID), network.ChainID)
+	assert.Equal(t, []string{"Arbitrum", "Base", "Ethereum", "Optimism", "Starknet", "Ztarknet"}, cfg.NetworkNames())
+}
+
+func TestInitializeNetworksIdempotent(t *testing.T) {
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			InitializeNetworks()
+			_, err := GetNetworkConfig("Ethereum")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	t.Setenv("OPTIMISM_CHAIN_ID", "10")
+	InitializeNetworks()
+	assert.Equal(t, uint64(OptimismSepoliaChainID), Networks["Optimism"].ChainID, "later calls keep the first build")
+
+	t.Setenv(solverdir.ConfigEnv, filepath.Join(t.TempDir(), "missing.env"))
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), cfg.Networks["Optimism"].ChainID, "LoadConfig rebuilds networks after loading .env")
+	assert.Equal(t, uint64(10), Networks["Optimism"].ChainID)
+}
+
+func TestRPCURLs(t *testing.T) {
+	t.Setenv("IS_DEVNET", "false")
+	t.Setenv("BASE_RPC_URL", "https://base-1.example")
+	t.Setenv(RPCURLOverrideAllEnv, "")
+	t.Setenv(RPCURLOverrideEnv("Base"), "")
+	t.Cleanup(ResetNetworks)
+
+	networks := ReloadNetworks()
+	assert.Equal(t, []string{"https://base-1.example"}, networks["Base"].RPCURLs, "without a list the single URL is used")
+
+	t.Setenv("BASE_RPC_URLS", " https://base-2.example, https://base-3.example ,")
+	networks = ReloadNetworks()
+	assert.Equal(t, []string{"https://base-2.example", "https://base-3.example"}, networks["Base"].RPCURLs)
+	assert.Equal(t, "https://base-2.example", networks["Base"].RPCURL)
+
+	t.Setenv("IS_DEVNET", "true")
+	t.Setenv("LOCAL_BASE_RPC_URL", "http://localhost:8548")
+	networks = ReloadNetworks()
+	assert.Equal(t, []string{"http://localhost:8548"}, networks["Base"].RPCURLs, "the live list is not used on devnet")
+
+	t.Setenv(RPCURLOverrideEnv("Base"), "http://localhost:9999")
+	networks = ReloadNetworks()
+	assert.Equal(t, []string{"http://localhost:9999"}, networks["Base"].RPCURLs, "--rpc-url bypasses the list")
+	assert.Equal(t, "http://localhost:8545", networks["Ethereum"].RPCURL)
+}
+
+func TestExtractRPCURLFlags(t *testing.T) {
+	t.Setenv(RPCURLOverrideAllEnv, "")
+	t.Setenv(RPCURLOverrideEnv("Base"), "")
+
+	args, err := ExtractRPCURLFlags([]string{"tools", "--rpc-url", "base=http://localhost:9548", "open-order", "--rpc-url=https://rpc.example/v2/key"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tools", "open-order"}, args)
+	assert.Equal(t, "http://localhost:9548", os.Getenv(RPCURLOverrideEnv("Base")))
+	assert.Equal(t, "https://rpc.example/v2/key", os.Getenv(RPCURLOverrideAllEnv))
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args: []string{"--rpc-url"}, wantErr: "--rpc-url requires [<network>=]<url>"},
+		{args: []string{"--rpc-url", "mars=http://localhost:1"}, wantErr: "network not found: mars"},
+		{args: []string{"--rpc-url=localhost:8545"}, wantErr: "expected [<network>=]<url>"},
+		{args: []string{"--rpc-url", "base="}, wantErr: "expected [<network>=]<url>"},
+	}
+	for _, tt := range tests {
+		_, err := ExtractRPCURLFlags(tt.args)
+		assert.ErrorContains(t, err, tt.wantErr, tt.args)
+	}
+}
+
+func TestCustomDomains(t *testing.T) {
+	t.Setenv("CUSTOM_DOMAIN_BASE", "4242")
+	t.Setenv("CUSTOM_DOMAIN_MYCHAIN", "12345")
+	t.Setenv("CUSTOM_DOMAIN_TYPO", "twelve")
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+
+	domain, err := GetHyperlaneDomain("Base")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4242), domain, "a configured network's domain is overridden")
+
+	domain, err = GetHyperlaneDomain("MyChain")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12345), domain, "an unconfigured network gets a domain")
+	assert.NotContains(t, Networks, "MYCHAIN", "without becoming a network")
+
+	_, err = GetHyperlaneDomain("Mars")
+	assert.ErrorIs(t, err, ErrUnknownNetwork)
+}
+
+func TestDestinationDomain(t *testing.T) {
+	t.Setenv("CUSTOM_DOMAIN_MYCHAIN", "12345")
+	t.Setenv("CUSTOM_DOMAIN_BIGCHAIN", "0x100000000")
+	t.Setenv("CUSTOM_DOMAIN_TYPO", "twelve")
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+
+	domain, err := DestinationDomain(EthereumSepoliaChainID, "MyChain")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(12345), domain)
+
+	tests := []struct {
+		origin      uint32
+		destination string
+		wantErr     string
+	}{
+		{origin: EthereumSepoliaChainID, destination: "Mars", wantErr: "network not found: Mars"},
+		{origin: 0, destination: "Base", wantErr: "origin Hyperlane domain is 0"},
+		{origin: EthereumSepoliaChainID, destination: "Typo", wantErr: "Typo has Hyperlane domain 0 (set TYPO_DOMAIN_ID or CUSTOM_DOMAIN_TYPO)"},
+		{origin: EthereumSepoliaChainID, destination: "BigChain", wantErr: "does not fit in 32 bits"},
+		{origin: BaseSepoliaChainID, destination: "Base", wantErr: "origin and destination must differ"},
+	}
+	for _, tt := range tests {
+		_, err := DestinationDomain(tt.origin, tt.destination)
+		assert.ErrorContains(t, err, tt.wantErr, tt.destination)
+	}
+}
+
+func TestNetworkTypes(t *testing.T) {
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+
+	tests := []struct {
+		name    string
+		network string
+		want    NetworkType
+	}{
+		{name: "evm", network: "Base", want: NetworkTypeEVM},
+		{name: "starknet", network: "Starknet", want: NetworkTypeStarknet},
+		{name: "ztarknet", network: "Ztarknet", want: NetworkTypeZtarknet},
+		{name: "case-insensitive", network: "ztarknet", want: NetworkTypeZtarknet},
+		{name: "unknown names are evm", network: "MyChain", want: NetworkTypeEVM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NetworkTypeOf(tt.network))
+		})
+	}
+
+	var names []string
+	for _, network := range GetNetworksByType(NetworkTypeEVM) {
+		names = append(names, network.Name)
+	}
+	assert.Equal(t, []string{"Arbitrum", "Base", "Ethereum", "Optimism"}, names)
+
+	cairo := GetCairoNetworks()
+	require.Len(t, cairo, 2)
+	assert.Equal(t, "Starknet", cairo[0].Name)
+	assert.Equal(t, "Ztarknet", cairo[1].Name)
+	assert.True(t, NetworkTypeZtarknet.IsCairo())
+	assert.False(t, NetworkTypeEVM.IsCairo())
+}
+
+func TestZtarknetOnlyConfiguration(t *testing.T) {
+	t.Setenv("IS_DEVNET", "true")
+	t.Setenv("ZTARKNET_RPC_URL", "https://ztarknet.example")
+	t.Setenv("LOCAL_ZTARKNET_RPC_URL", "http://localhost:1234")
+	t.Setenv("ZTARKNET_CHAIN_ID", "424242")
+	t.Setenv("ZTARKNET_HYPERLANE_DOMAIN", "777")
+	t.Setenv("ZTARKNET_HYPERLANE_ADDRESS", "0x07683")
+	t.Setenv("ZTARKNET_RPC_URLS", "")
+	// Unset rather than empty: an empty CUSTOM_DOMAIN_ZTARKNET sets domain 0
+	for _, key := range []string{"ZTARKNET_DOMAIN_ID", "CUSTOM_DOMAIN_ZTARKNET"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+
+	ztarknet := GetNetworksByType(NetworkTypeZtarknet)
+	require.Len(t, ztarknet, 1)
+	network := ztarknet[0]
+	assert.Equal(t, "https://ztarknet.example", network.RPCURL, "Ztarknet has no LOCAL_ variant")
+	assert.Equal(t, uint64(424242), network.ChainID)
+	assert.Equal(t, uint64(777), network.HyperlaneDomain)
+	assert.Equal(t, "0x07683", network.HyperlaneAddress)
+
+	networkType, err := GetNetworkTypeByChainID(424242)
+	require.NoError(t, err)
+	assert.Equal(t, NetworkTypeZtarknet, networkType)
+	address, err := GetHyperlaneAddressByChainID(424242)
+	require.NoError(t, err)
+	assert.Equal(t, "0x07683", address)
+
+	domain, err := DestinationDomain(uint32(Networks["Base"].HyperlaneDomain), "Ztarknet")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(777), domain)
+
+	_, err = GetNetworkTypeByChainID(1)
+	assert.ErrorIs(t, err, ErrUnknownNetwork)
+}
+
+func TestParseDestinationWeights(t *testing.T) {
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+
+	weights, err := ParseDestinationWeights(" base=5, Arbitrum=1,Ethereum=0 ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"Base": 5, "Arbitrum": 1, "Ethereum": 0}, weights)
+
+	weights, err = ParseDestinationWeights("")
+	require.NoError(t, err)
+	assert.Nil(t, weights)
+
+	for _, value := range []string{"Base", "Base=-1", "Base=x", "Nowhere=1", "Base=1,base=2"} {
+		_, err := ParseDestinationWeights(value)
+		assert.Error(t, err, value)
+	}
+
+	t.Setenv(DestinationWeightsEnv, "Starknet=2")
+	weights, err = DestinationWeights()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"Starknet": 2}, weights)
+}
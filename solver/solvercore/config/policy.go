@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Fill policy environment variables. Networks are named as in the rest of the configuration, case-insensitively,
+// and amounts are in the token's base units
+const (
+	// PolicyMinSpreadEnv is the least MinReceived - MaxSpent an order must leave the solver, e.g. 1000
+	PolicyMinSpreadEnv = "POLICY_MIN_SPREAD"
+	// PolicyMinSpreadPercentEnv is the least spread as a percentage of MaxSpent, e.g. 0.5
+	PolicyMinSpreadPercentEnv = "POLICY_MIN_SPREAD_PERCENT"
+	// PolicyTokenAllowlistEnv limits orders to these tokens: <network>:<token>,... (unset allows every token)
+	PolicyTokenAllowlistEnv = "POLICY_TOKEN_ALLOWLIST"
+	// PolicyTokenDenylistEnv refuses orders involving these tokens: <network>:<token>,...
+	PolicyTokenDenylistEnv = "POLICY_TOKEN_DENYLIST"
+	// PolicyMaxOrderSizeEnv caps what the solver spends on one order per token: <network>:<token>=<amount>,...
+	PolicyMaxOrderSizeEnv = "POLICY_MAX_ORDER_SIZE"
+	// PolicyDisabledOriginsEnv lists the networks whose orders are never filled: <network>,...
+	PolicyDisabledOriginsEnv = "POLICY_DISABLED_ORIGINS"
+)
+
+// TokenKey identifies a token on a network by the chain ID or Hyperlane domain orders carry, and its address as
+// NormalizeTokenAddress returns it
+type TokenKey struct {
+	ChainID uint64
+	Token   string
+}
+
+// FillPolicy decides which orders the solver is willing to fill. The zero value allows everything the other rules
+// allow. Networks are keyed by both chain ID and Hyperlane domain, since orders opened on Cairo chains carry domains
+type FillPolicy struct {
+	MinSpread        *big.Int // nil for no minimum
+	MinSpreadPercent *big.Rat // nil for no minimum
+	AllowedTokens    map[TokenKey]bool
+	DeniedTokens     map[TokenKey]bool
+	MaxOrderSize     map[TokenKey]*big.Int
+	DisabledOrigins  map[uint64]string // chain ID or domain -> network name
+}
+
+// LoadFillPolicy reads the POLICY_* variables, resolving network names against networks
+func LoadFillPolicy(networks map[string]NetworkConfig) (FillPolicy, error) {
+	policy := FillPolicy{
+		AllowedTokens:   make(map[TokenKey]bool),
+		DeniedTokens:    make(map[TokenKey]bool),
+		DisabledOrigins: make(map[uint64]string),
+	}
+
+	if value := strings.TrimSpace(os.Getenv(PolicyMinSpreadEnv)); value != "" {
+		spread, ok := new(big.Int).SetString(value, 10)
+		if !ok || spread.Sign() < 0 {
+			return FillPolicy{}, fmt.Errorf("invalid %s %q: expected a non-negative integer amount", PolicyMinSpreadEnv, value)
+		}
+		policy.MinSpread = spread
+	}
+	if value := strings.TrimSpace(os.Getenv(PolicyMinSpreadPercentEnv)); value != "" {
+		percent, ok := new(big.Rat).SetString(value)
+		if !ok || percent.Sign() < 0 {
+			return FillPolicy{}, fmt.Errorf("invalid %s %q: expected a non-negative percentage such as 0.5", PolicyMinSpreadPercentEnv, value)
+		}
+		policy.MinSpreadPercent = percent
+	}
+
+	for env, tokens := range map[string]map[TokenKey]bool{
+		PolicyTokenAllowlistEnv: policy.AllowedTokens,
+		PolicyTokenDenylistEnv:  policy.DeniedTokens,
+	} {
+		for _, entry := range splitList(os.Getenv(env)) {
+			keys, err := parseTokenKeys(networks, entry)
+			if err != nil {
+				return FillPolicy{}, fmt.Errorf("invalid %s entry %q: %w", env, entry, err)
+			}
+			for _, key := range keys {
+				tokens[key] = true
+			}
+		}
+	}
+
+	maxOrderSize, err := parseTokenAmounts(networks, PolicyMaxOrderSizeEnv)
+	if err != nil {
+		return FillPolicy{}, err
+	}
+	policy.MaxOrderSize = maxOrderSize
+
+	for _, name := range splitList(os.Getenv(PolicyDisabledOriginsEnv)) {
+		networkName, err := canonicalName(networks, name)
+		if err != nil {
+			return FillPolicy{}, fmt.Errorf("invalid %s entry %q: %w", PolicyDisabledOriginsEnv, name, err)
+		}
+		for _, id := range networkIDs(networks[networkName]) {
+			policy.DisabledOrigins[id] = networkName
+		}
+	}
+
+	return policy, nil
+}
+
+// NormalizeTokenAddress returns addr as lower-case hex without leading zeros, so a 20-byte EVM address, its
+// bytes32 form in an order and a Starknet felt compare equal. Addresses that are not hex are returned lower-cased
+func NormalizeTokenAddress(addr string) string {
+	hex := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(addr), "0x"), "0X")
+	value, ok := new(big.Int).SetString(hex, 16)
+	if !ok {
+		return strings.ToLower(strings.TrimSpace(addr))
+	}
+	return "0x" + value.Text(16)
+}
+
+// parseTokenKeys parses <network>:<token> into the keys of the token under each of the network's IDs
+func parseTokenKeys(networks map[string]NetworkConfig, entry string) ([]TokenKey, error) {
+	name, token, found := strings.Cut(entry, ":")
+	if !found || strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("expected <network>:<token>")
+	}
+	if _, ok := new(big.Int).SetString(strings.TrimPrefix(strings.TrimSpace(token), "0x"), 16); !ok {
+		return nil, fmt.Errorf("token %q is not a hex address", token)
+	}
+	networkName, err := canonicalName(networks, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	var keys []TokenKey
+	for _, id := range networkIDs(networks[networkName]) {
+		keys = append(keys, TokenKey{ChainID: id, Token: NormalizeTokenAddress(token)})
+	}
+	return keys, nil
+}
+
+// parseTokenAmounts reads env as <network>:<token>=<amount>,... into amounts keyed by each of the network's IDs
+func parseTokenAmounts(networks map[string]NetworkConfig, env string) (map[TokenKey]*big.Int, error) {
+	amounts := make(map[TokenKey]*big.Int)
+	for _, entry := range splitList(os.Getenv(env)) {
+		token, amountText, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid %s entry %q: expected <network>:<token>=<amount>", env, entry)
+		}
+		amount, ok := new(big.Int).SetString(strings.TrimSpace(amountText), 10)
+		if !ok || amount.Sign() < 0 {
+			return nil, fmt.Errorf("invalid %s entry %q: amount must be a non-negative integer", env, entry)
+		}
+		keys, err := parseTokenKeys(networks, token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", env, entry, err)
+		}
+		for _, key := range keys {
+			amounts[key] = amount
+		}
+	}
+	return amounts, nil
+}
+
+// networkIDs returns the IDs orders may name network by: its chain ID and, when different, its Hyperlane domain
+func networkIDs(network NetworkConfig) []uint64 {
+	if network.HyperlaneDomain == 0 || network.HyperlaneDomain == network.ChainID {
+		return []uint64{network.ChainID}
+	}
+	return []uint64{network.ChainID, network.HyperlaneDomain}
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFillPolicy(t *testing.T) {
+	networks := map[string]NetworkConfig{
+		"Base":     {Name: "Base", ChainID: 84532, HyperlaneDomain: 84532},
+		"Starknet": {Name: "Starknet", ChainID: 393402133025997798, HyperlaneDomain: 23448594291968334},
+	}
+	usdc := NormalizeTokenAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")
+	strk := NormalizeTokenAddress("0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d")
+
+	t.Run("unset allows everything", func(t *testing.T) {
+		policy, err := LoadFillPolicy(networks)
+		require.NoError(t, err)
+		assert.Nil(t, policy.MinSpread)
+		assert.Nil(t, policy.MinSpreadPercent)
+		assert.Empty(t, policy.AllowedTokens)
+		assert.Empty(t, policy.DisabledOrigins)
+	})
+
+	t.Run("every setting", func(t *testing.T) {
+		t.Setenv(PolicyMinSpreadEnv, "1000")
+		t.Setenv(PolicyMinSpreadPercentEnv, "0.5")
+		t.Setenv(PolicyTokenAllowlistEnv, "base:0x036CbD53842c5426634e7929541eC2318f3dCF7e, starknet:0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d")
+		t.Setenv(PolicyTokenDenylistEnv, "Base:0xdead")
+		t.Setenv(PolicyMaxOrderSizeEnv, "starknet:0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d=5000")
+		t.Setenv(PolicyDisabledOriginsEnv, "starknet")
+
+		policy, err := LoadFillPolicy(networks)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1000), policy.MinSpread)
+		assert.Equal(t, big.NewRat(1, 2), policy.MinSpreadPercent)
+		assert.True(t, policy.AllowedTokens[TokenKey{ChainID: 84532, Token: usdc}])
+		// Starknet tokens are keyed by chain ID and by Hyperlane domain
+		assert.True(t, policy.AllowedTokens[TokenKey{ChainID: 393402133025997798, Token: strk}])
+		assert.True(t, policy.AllowedTokens[TokenKey{ChainID: 23448594291968334, Token: strk}])
+		assert.True(t, policy.DeniedTokens[TokenKey{ChainID: 84532, Token: "0xdead"}])
+		assert.Equal(t, big.NewInt(5000), policy.MaxOrderSize[TokenKey{ChainID: 23448594291968334, Token: strk}])
+		assert.Equal(t, map[uint64]string{393402133025997798: "Starknet", 23448594291968334: "Starknet"}, policy.DisabledOrigins)
+	})
+
+	invalid := []struct {
+		env, value, wantErr string
+	}{
+		{PolicyMinSpreadEnv, "-1", "non-negative integer"},
+		{PolicyMinSpreadPercentEnv, "half", "percentage"},
+		{PolicyTokenAllowlistEnv, "0xabc", "expected <network>:<token>"},
+		{PolicyTokenDenylistEnv, "mars:0xabc", "network not found"},
+		{PolicyTokenDenylistEnv, "base:usdc", "not a hex address"},
+		{PolicyMaxOrderSizeEnv, "base:0xabc", "expected <network>:<token>=<amount>"},
+		{PolicyMaxOrderSizeEnv, "base:0xabc=lots", "non-negative integer"},
+		{PolicyDisabledOriginsEnv, "mars", "network not found"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			_, err := LoadFillPolicy(networks)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNormalizeTokenAddress(t *testing.T) {
+	assert.Equal(t, "0x36cbd53842c5426634e7929541ec2318f3dcf7e", NormalizeTokenAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e"))
+	assert.Equal(t, NormalizeTokenAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e"),
+		NormalizeTokenAddress("0x000000000000000000000000036cbd53842c5426634e7929541ec2318f3dcf7e"))
+	assert.Equal(t, "0x0", NormalizeTokenAddress("0x0"))
+	assert.Equal(t, "eth", NormalizeTokenAddress("ETH"))
+}
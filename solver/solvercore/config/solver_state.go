@@ -1,11 +1,12 @@
 // Package config manages solver state persistence across networks.
 //
 // SolverState tracks only the last processed blocks for solver listeners
-// across all networks (Ethereum, Optimism, Arbitrum, Base, Starknet, Ztarknet).
+// across all networks (Ethereum, Optimism, Arbitrum, Base, Starknet, Ztarknet),
+// and the orders handled in the blocks a restart rescans.
 // All contract addresses and network config come from .env files.
 //
 // Key Features:
-// - Minimal persistent storage of last indexed blocks only
+// - Minimal persistent storage of last indexed blocks and recently handled orders
 // - Thread-safe file operations with atomic writes
 // - Automatic fallback to .env start blocks if file doesn't exist
 // - Special handling: start block 0 → use current block
@@ -27,6 +28,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/solverdir"
 )
 
 const (
@@ -46,8 +49,18 @@ type SolverState struct {
 type SolverNetworkState struct {
 	LastIndexedBlock uint64 `json:"lastIndexedBlock"`
 	LastUpdated      string `json:"lastUpdated"`
+	// HandledOrders maps the orders handled near LastIndexedBlock to the block they were opened in, so the
+	// blocks a restart rescans do not hand them to the solver again
+	HandledOrders map[string]uint64 `json:"handledOrders,omitempty"`
+	// HeldOrders maps the orders held while their destination was paused to the block they were opened in. They
+	// are not handled yet, so a restart rescans from the earliest of them
+	HeldOrders map[string]uint64 `json:"heldOrders,omitempty"`
 }
 
+// handledOrderRetention is how many blocks behind LastIndexedBlock handled orders are remembered; far more than
+// any confirmation window a restart rescans
+const handledOrderRetention = 10000
+
 // getDefaultSolverState creates default solver state with start blocks from .env
 func getDefaultSolverState() SolverState {
 	// Ensure config is loaded before accessing Networks
@@ -99,6 +112,17 @@ func resolveSolverStartBlock(solverStartBlock int64) uint64 {
 // process-local lock to serialize state file access
 var solverStateMu sync.Mutex
 
+// solverStateReadOnly drops every write of the state file, for dry runs (guarded by solverStateMu)
+var solverStateReadOnly bool
+
+// SetSolverStateReadOnly stops (or resumes) saving solver state, so a dry run leaves checkpoints and handled orders
+// as the last real run left them
+func SetSolverStateReadOnly(readOnly bool) {
+	solverStateMu.Lock()
+	defer solverStateMu.Unlock()
+	solverStateReadOnly = readOnly
+}
+
 // GetSolverState loads the current solver state from file
 func GetSolverState() (*SolverState, error) {
 	solverStateMu.Lock()
@@ -130,6 +154,11 @@ func UpdateLastIndexedBlock(networkName string, newBlockNumber uint64) error {
 
 	network.LastIndexedBlock = newBlockNumber
 	network.LastUpdated = time.Now().Format(time.RFC3339)
+	for orderID, block := range network.HandledOrders {
+		if block+handledOrderRetention < newBlockNumber {
+			delete(network.HandledOrders, orderID)
+		}
+	}
 	state.Networks[networkName] = network
 
 	if err := saveSolverStateLocked(state); err != nil {
@@ -139,6 +168,86 @@ func UpdateLastIndexedBlock(networkName string, newBlockNumber uint64) error {
 	return nil
 }
 
+// IsOrderHandled reports whether orderID, opened on networkName, was already handled
+func IsOrderHandled(networkName, orderID string) (bool, error) {
+	solverStateMu.Lock()
+	defer solverStateMu.Unlock()
+
+	state, err := readSolverStateLocked()
+	if err != nil {
+		return false, fmt.Errorf("failed to get solver state: %w", err)
+	}
+	_, handled := state.Networks[networkName].HandledOrders[orderID]
+	return handled, nil
+}
+
+// MarkOrderHandled records that orderID, opened on networkName in block, was handled
+func MarkOrderHandled(networkName, orderID string, block uint64) error {
+	return updateNetworkState(networkName, func(network *SolverNetworkState) {
+		if network.HandledOrders == nil {
+			network.HandledOrders = make(map[string]uint64)
+		}
+		network.HandledOrders[orderID] = block
+		delete(network.HeldOrders, orderID)
+	})
+}
+
+// MarkOrderHeld records that orderID, opened on networkName in block, is held until its destination is resumed
+func MarkOrderHeld(networkName, orderID string, block uint64) error {
+	return updateNetworkState(networkName, func(network *SolverNetworkState) {
+		if network.HeldOrders == nil {
+			network.HeldOrders = make(map[string]uint64)
+		}
+		network.HeldOrders[orderID] = block
+	})
+}
+
+// updateNetworkState applies fn to the state of networkName and saves it
+func updateNetworkState(networkName string, fn func(*SolverNetworkState)) error {
+	solverStateMu.Lock()
+	defer solverStateMu.Unlock()
+
+	state, err := readSolverStateLocked()
+	if err != nil {
+		return fmt.Errorf("failed to get solver state: %w", err)
+	}
+
+	network, exists := state.Networks[networkName]
+	if !exists {
+		return fmt.Errorf("network %s not found in solver state", networkName)
+	}
+	fn(&network)
+	state.Networks[networkName] = network
+
+	if err := saveSolverStateLocked(state); err != nil {
+		return fmt.Errorf("failed to save solver state: %w", err)
+	}
+	return nil
+}
+
+// ResetLastIndexedBlock forgets where the solver got to on networkName, so its next run starts from the
+// configured start block again. It returns the block the network was reset to
+func ResetLastIndexedBlock(networkName string) (uint64, error) {
+	solverStateMu.Lock()
+	defer solverStateMu.Unlock()
+
+	state, err := readSolverStateLocked()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get solver state: %w", err)
+	}
+
+	network, exists := getDefaultSolverState().Networks[networkName]
+	if !exists {
+		return 0, fmt.Errorf("network %s not found in solver state", networkName)
+	}
+	state.Networks[networkName] = network
+
+	if err := saveSolverStateLocked(state); err != nil {
+		return 0, fmt.Errorf("failed to save solver state: %w", err)
+	}
+	return network.LastIndexedBlock, nil
+}
+
 // DisplaySolverState prints the current solver persistence state to stdout
 func DisplaySolverState() error {
 	state, err := GetSolverState()
@@ -234,6 +343,9 @@ func readSolverStateLocked() (*SolverState, error) {
 
 // saveSolverStateLocked writes the state atomically while holding solverStateMu
 func saveSolverStateLocked(state *SolverState) error {
+	if solverStateReadOnly {
+		return nil
+	}
 	stateFile := getSolverStateFilePath()
 	dir := filepath.Dir(stateFile)
 	if err := os.MkdirAll(dir, defaultDirPerms); err != nil {
@@ -267,17 +379,11 @@ func saveSolverStateLocked(state *SolverState) error {
 	return nil
 }
 
-// getSolverStateFilePath returns the path to the solver state file
+// getSolverStateFilePath returns the path to the solver state file: SOLVER_STATE_FILE, or
+// solver_state/solver-state.json in the state directory
 func getSolverStateFilePath() string {
 	if custom := os.Getenv("SOLVER_STATE_FILE"); custom != "" {
 		return custom
 	}
-	candidates := []string{"state/solver_state/solver-state.json", "solver-state.json"}
-	for _, p := range candidates {
-		dir := filepath.Dir(p)
-		if _, err := os.Stat(dir); err == nil {
-			return p
-		}
-	}
-	return "state/solver_state/solver-state.json"
+	return solverdir.StatePath("solver_state", "solver-state.json")
 }
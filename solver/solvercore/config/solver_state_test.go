@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -214,3 +215,68 @@ func TestSolverStateEdgeCases(t *testing.T) {
 		assert.Equal(t, uint64(50000), state.Networks["Arbitrum"].LastIndexedBlock)
 	})
 }
+
+// TestHandledOrders tests recording handled orders, their pruning and ResetLastIndexedBlock
+func TestHandledOrders(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+
+	handled, err := IsOrderHandled("Base", "0xa")
+	require.NoError(t, err)
+	assert.False(t, handled)
+
+	require.NoError(t, MarkOrderHandled("Base", "0xa", 1000))
+	require.NoError(t, MarkOrderHandled("Base", "0xb", 5000))
+	require.Error(t, MarkOrderHandled("Mars", "0xa", 1000))
+
+	handled, err = IsOrderHandled("Base", "0xa")
+	require.NoError(t, err)
+	assert.True(t, handled)
+	handled, err = IsOrderHandled("Optimism", "0xa")
+	require.NoError(t, err)
+	assert.False(t, handled, "orders are recorded per origin network")
+
+	// Checkpointing far enough past an order forgets it
+	require.NoError(t, UpdateLastIndexedBlock("Base", 1000+handledOrderRetention+1))
+	state, err := GetSolverState()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"0xb": 5000}, state.Networks["Base"].HandledOrders)
+
+	// Held orders are kept until they are handled, however far the checkpoint moves
+	require.NoError(t, MarkOrderHeld("Base", "0xc", 1000))
+	require.NoError(t, UpdateLastIndexedBlock("Base", 1000+handledOrderRetention+2))
+	handled, err = IsOrderHandled("Base", "0xc")
+	require.NoError(t, err)
+	assert.False(t, handled, "a held order is not handled")
+	require.NoError(t, MarkOrderHandled("Base", "0xc", 1000))
+	state, err = GetSolverState()
+	require.NoError(t, err)
+	assert.Empty(t, state.Networks["Base"].HeldOrders)
+	assert.Contains(t, state.Networks["Base"].HandledOrders, "0xc")
+
+	block, err := ResetLastIndexedBlock("Base")
+	require.NoError(t, err)
+	state, err = GetSolverState()
+	require.NoError(t, err)
+	assert.Equal(t, block, state.Networks["Base"].LastIndexedBlock)
+	assert.Empty(t, state.Networks["Base"].LastUpdated)
+	assert.Empty(t, state.Networks["Base"].HandledOrders)
+
+	_, err = ResetLastIndexedBlock("Mars")
+	assert.Error(t, err)
+}
+
+// TestSolverStateReadOnly tests that a dry run leaves the state file alone
+func TestSolverStateReadOnly(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	require.NoError(t, UpdateLastIndexedBlock("Base", 100))
+
+	SetSolverStateReadOnly(true)
+	require.NoError(t, UpdateLastIndexedBlock("Base", 200))
+	require.NoError(t, MarkOrderHandled("Base", "0xa", 150))
+	SetSolverStateReadOnly(false)
+
+	state, err := GetSolverState()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), state.Networks["Base"].LastIndexedBlock)
+	assert.Empty(t, state.Networks["Base"].HandledOrders)
+}
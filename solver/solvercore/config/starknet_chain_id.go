@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Starknet chain IDs
+// Starknet nodes identify their chain with the felt of a short string (SN_SEPOLIA is 0x534e5f5345504f4c4941), far
+// beyond uint64, so NetworkConfig.ChainID cannot hold it. STARKNET_CHAIN_ID and ZTARKNET_CHAIN_ID accept it as hex,
+// decimal or the short string itself and keep it in StarknetChainID, which VerifyStarknetChainID checks against the
+// node. A decimal value that fits in 64 bits keeps its former meaning: the numeric chain ID, with no node check
+
+// ErrChainIDMismatch is returned when a node reports another chain than the configured one
+var ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+// feltPrime is the Starknet field modulus, 2^251 + 17*2^192 + 1
+var feltPrime = func() *big.Int {
+	p := new(big.Int).Lsh(big.NewInt(1), 251)
+	p.Add(p, new(big.Int).Lsh(big.NewInt(17), 192))
+	return p.Add(p, big.NewInt(1))
+}()
+
+// ChainIDReader is the part of a Starknet provider VerifyStarknetChainID needs; *rpc.Provider implements it
+type ChainIDReader interface {
+	ChainID(ctx context.Context) (string, error)
+}
+
+// ParseStarknetChainID parses a Starknet chain ID given as 0x hex, decimal or a short string such as SN_SEPOLIA
+// and returns it as a 0x felt
+func ParseStarknetChainID(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	n := new(big.Int)
+	switch {
+	case value == "":
+		return "", errors.New("empty chain ID")
+	case strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X"):
+		if _, ok := n.SetString(value[2:], 16); !ok {
+			return "", fmt.Errorf("invalid chain ID %q: not a hex number", value)
+		}
+	case strings.Trim(value, "0123456789") == "":
+		n.SetString(value, 10)
+	default:
+		var err error
+		if n, err = shortStringFelt(value); err != nil {
+			return "", fmt.Errorf("invalid chain ID %q: %w", value, err)
+		}
+	}
+	if n.Cmp(feltPrime) >= 0 {
+		return "", fmt.Errorf("invalid chain ID %q: not a felt", value)
+	}
+	return "0x" + n.Text(16), nil
+}
+
+// shortStringFelt encodes a Cairo short string: at most 31 ASCII characters, big-endian
+func shortStringFelt(value string) (*big.Int, error) {
+	if len(value) > 31 {
+		return nil, errors.New("short strings hold at most 31 characters")
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x20 || value[i] > 0x7e {
+			return nil, errors.New("short strings hold printable ASCII only")
+		}
+	}
+	return new(big.Int).SetBytes([]byte(value)), nil
+}
+
+// StarknetChainIDName returns the short string of a 0x felt chain ID, or the felt itself when it is not one
+func StarknetChainIDName(chainID string) string {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(chainID, "0x"), 16)
+	if !ok || n.Sign() == 0 {
+		return chainID
+	}
+	text := n.Bytes()
+	if _, err := shortStringFelt(string(text)); err != nil {
+		return chainID
+	}
+	return string(text)
+}
+
+// cairoChainID reads a Cairo network's <NETWORK>_CHAIN_ID: the numeric chain ID when it is a decimal that fits in
+// 64 bits, the node's chain ID otherwise. Invalid values are kept as given so VerifyStarknetChainID reports them
+func cairoChainID(value string, numericDefault uint64) (uint64, string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return numericDefault, ""
+	}
+	if n, ok := new(big.Int).SetString(value, 10); ok && n.IsUint64() && strings.Trim(value, "0123456789") == "" {
+		return n.Uint64(), ""
+	}
+	if chainID, err := ParseStarknetChainID(value); err == nil {
+		return numericDefault, chainID
+	}
+	return numericDefault, value
+}
+
+// ChainIDLabel describes the network's chain ID for display: the Starknet chain ID when configured, e.g.
+// "SN_SEPOLIA (0x534e5f5345504f4c4941)", the numeric one otherwise
+func (n NetworkConfig) ChainIDLabel() string {
+	if n.StarknetChainID == "" {
+		return fmt.Sprintf("%d", n.ChainID)
+	}
+	if name := StarknetChainIDName(n.StarknetChainID); name != n.StarknetChainID {
+		return fmt.Sprintf("%s (%s)", name, n.StarknetChainID)
+	}
+	return n.StarknetChainID
+}
+
+// VerifyStarknetChainID checks the node behind reader is on network's configured Starknet chain, so tools pointed
+// at the wrong fork stop before sending anything. Networks without a Starknet chain ID pass unchecked
+func VerifyStarknetChainID(ctx context.Context, reader ChainIDReader, network NetworkConfig) error {
+	if network.StarknetChainID == "" {
+		return nil
+	}
+	reported, err := reader.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the %s chain ID: %w", network.Name, err)
+	}
+	return CheckStarknetChainID(network, reported)
+}
+
+// CheckStarknetChainID compares the chain ID a node reported, as a short string or 0x felt, with network's
+// configured Starknet chain ID
+func CheckStarknetChainID(network NetworkConfig, reported string) error {
+	if network.StarknetChainID == "" {
+		return nil
+	}
+	env := strings.ToUpper(network.Name) + "_CHAIN_ID"
+	expected, err := ParseStarknetChainID(network.StarknetChainID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+	actual, err := reportedChainID(reported)
+	if err != nil {
+		return fmt.Errorf("%s node reports chain ID %q: %w", network.Name, reported, err)
+	}
+	if actual != expected {
+		return fmt.Errorf("%w: %s node reports %s, %s is %s", ErrChainIDMismatch, network.Name,
+			StarknetChainIDName(actual), env, StarknetChainIDName(expected))
+	}
+	return nil
+}
+
+// reportedChainID converts a node's chain ID, which starknet.go decodes to its short string, back to a 0x felt
+func reportedChainID(reported string) (string, error) {
+	if strings.HasPrefix(reported, "0x") {
+		return ParseStarknetChainID(reported)
+	}
+	n, err := shortStringFelt(reported)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + n.Text(16), nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticChainID is a ChainIDReader answering with a fixed chain ID
+type staticChainID struct {
+	chainID string
+	err     error
+}
+
+func (s staticChainID) ChainID(context.Context) (string, error) { return s.chainID, s.err }
+
+const snSepolia = "0x534e5f5345504f4c4941"
+
+func TestParseStarknetChainID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "hex", value: snSepolia, want: snSepolia},
+		{name: "upper case hex", value: "0X534E5F5345504F4C4941", want: snSepolia},
+		{name: "decimal", value: "393402133025997798000961", want: snSepolia},
+		{name: "short string", value: " SN_SEPOLIA ", want: snSepolia},
+		{name: "empty", value: "", wantErr: "empty chain ID"},
+		{name: "invalid hex", value: "0xSN", wantErr: "not a hex number"},
+		{name: "above the felt prime", value: "0x" + "f" + "00000000000000000000000000000000000000000000000000000000000000", wantErr: "not a felt"},
+		{name: "too long short string", value: "A_SHORT_STRING_THAT_IS_FAR_TOO_LONG", wantErr: "at most 31 characters"},
+		{name: "non printable short string", value: "SN\tSEPOLIA", wantErr: "printable ASCII only"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStarknetChainID(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCairoChainID(t *testing.T) {
+	tests := []struct {
+		value        string
+		wantNumeric  uint64
+		wantStarknet string
+	}{
+		{value: "", wantNumeric: 23448591},
+		{value: "23448594", wantNumeric: 23448594},
+		{value: "393402133025997798000961", wantNumeric: 23448591, wantStarknet: snSepolia},
+		{value: snSepolia, wantNumeric: 23448591, wantStarknet: snSepolia},
+		{value: "SN_SEPOLIA", wantNumeric: 23448591, wantStarknet: snSepolia},
+		{value: "0xZZ", wantNumeric: 23448591, wantStarknet: "0xZZ"},
+	}
+	for _, tt := range tests {
+		numeric, starknet := cairoChainID(tt.value, 23448591)
+		assert.Equal(t, tt.wantNumeric, numeric, tt.value)
+		assert.Equal(t, tt.wantStarknet, starknet, tt.value)
+	}
+}
+
+func TestStarknetChainIDNetworks(t *testing.T) {
+	t.Setenv("STARKNET_CHAIN_ID", "SN_SEPOLIA")
+	t.Setenv("ZTARKNET_CHAIN_ID", "")
+	networks := buildNetworks()
+
+	assert.Equal(t, uint64(StarknetSepoliaChainID), networks["Starknet"].ChainID, "the numeric chain ID keeps its default")
+	assert.Equal(t, snSepolia, networks["Starknet"].StarknetChainID)
+	assert.Equal(t, "SN_SEPOLIA (0x534e5f5345504f4c4941)", networks["Starknet"].ChainIDLabel())
+	assert.Empty(t, networks["Ztarknet"].StarknetChainID)
+	assert.Equal(t, "10066329", networks["Ztarknet"].ChainIDLabel())
+}
+
+func TestStarknetChainIDName(t *testing.T) {
+	assert.Equal(t, "SN_SEPOLIA", StarknetChainIDName(snSepolia))
+	assert.Equal(t, "0x1", StarknetChainIDName("0x1"), "control characters are not a short string")
+	assert.Equal(t, "0x0", StarknetChainIDName("0x0"))
+}
+
+func TestVerifyStarknetChainID(t *testing.T) {
+	network := NetworkConfig{Name: "Starknet", StarknetChainID: snSepolia}
+
+	require.NoError(t, VerifyStarknetChainID(context.Background(), staticChainID{chainID: "SN_SEPOLIA"}, network))
+	require.NoError(t, VerifyStarknetChainID(context.Background(), staticChainID{chainID: snSepolia}, network))
+
+	err := VerifyStarknetChainID(context.Background(), staticChainID{chainID: "SN_MAIN"}, network)
+	require.ErrorIs(t, err, ErrChainIDMismatch)
+	assert.EqualError(t, err, "chain ID mismatch: Starknet node reports SN_MAIN, STARKNET_CHAIN_ID is SN_SEPOLIA")
+
+	err = VerifyStarknetChainID(context.Background(), staticChainID{err: errors.New("connection refused")}, network)
+	assert.ErrorContains(t, err, "failed to read the Starknet chain ID: connection refused")
+
+	invalid := NetworkConfig{Name: "Ztarknet", StarknetChainID: "0xZZ"}
+	err = VerifyStarknetChainID(context.Background(), staticChainID{chainID: "ZTARKNET"}, invalid)
+	assert.ErrorContains(t, err, "ZTARKNET_CHAIN_ID: invalid chain ID")
+
+	unset := NetworkConfig{Name: "Starknet", ChainID: StarknetSepoliaChainID}
+	assert.NoError(t, VerifyStarknetChainID(context.Background(), staticChainID{err: errors.New("not called")}, unset))
+}
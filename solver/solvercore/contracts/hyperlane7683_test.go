@@ -0,0 +1,193 @@
+package contracts
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/evmtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pinnedABI is the Hyperlane7683 ABI as the Solidity contract defines it (solidity/src/Hyperlane7683.sol, trimmed
+// to what the solver uses). The stub's answers are encoded from it, so a regenerated binding whose selectors, struct
+// fields or event layout drift from the contract fails here instead of reverting on a live chain
+func pinnedABI(t *testing.T) abi.ABI {
+	t.Helper()
+	data, err := os.ReadFile("testdata/Hyperlane7683.abi.json")
+	require.NoError(t, err)
+	parsed, err := abi.JSON(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	return parsed
+}
+
+func testResolvedOrder(user common.Address) ResolvedCrossChainOrder {
+	return ResolvedCrossChainOrder{
+		User:          user,
+		OriginChainId: big.NewInt(84532),
+		OpenDeadline:  1700000000,
+		FillDeadline:  1700003600,
+		OrderId:       common.HexToHash("0x5c0ffee0"),
+		MaxSpent: []Output{{
+			Token:     common.HexToHash("0x1111"),
+			Amount:    big.NewInt(990),
+			Recipient: common.HexToHash("0x2222"),
+			ChainId:   big.NewInt(11155420),
+		}},
+		MinReceived: []Output{{
+			Token:     common.HexToHash("0x3333"),
+			Amount:    big.NewInt(1000),
+			Recipient: common.HexToHash("0x0"),
+			ChainId:   big.NewInt(84532),
+		}},
+		FillInstructions: []FillInstruction{{
+			DestinationChainId: big.NewInt(11155420),
+			DestinationSettler: common.HexToHash("0x4444"),
+			OriginData:         []byte("order data"),
+		}},
+	}
+}
+
+// deployHyperlane7683Stub deploys a stub answering the solver's Hyperlane7683 calls and binds it
+func deployHyperlane7683Stub(t *testing.T, chain *evmtest.Chain, resolved ResolvedCrossChainOrder) *Hyperlane7683 {
+	t.Helper()
+	pinned := pinnedABI(t)
+	answer := func(method string, outputs ...interface{}) evmtest.Call {
+		call, err := evmtest.Answer(pinned.Methods[method], outputs...)
+		require.NoError(t, err)
+		return call
+	}
+	filled := bytes32("FILLED")
+
+	open := answer("open")
+	openData, err := pinned.Events["Open"].Inputs.NonIndexed().Pack(resolved)
+	require.NoError(t, err)
+	open.Logs = []evmtest.Log{{Topics: []common.Hash{pinned.Events["Open"].ID, resolved.OrderId}, Data: openData}}
+
+	address := chain.Deploy(t, evmtest.Stub(
+		open,
+		answer("resolve", resolved),
+		answer("orderStatus", filled),
+		answer("quoteGasPayment", big.NewInt(123456789)),
+		answer("isValidNonce", true),
+	))
+	hyperlane, err := NewHyperlane7683(address, chain.Client)
+	require.NoError(t, err)
+	return hyperlane
+}
+
+// bytes32 right-pads s to 32 bytes, as the contract's status constants are defined (FILLED = "FILLED")
+func bytes32(s string) [32]byte {
+	var b [32]byte
+	copy(b[:], s)
+	return b
+}
+
+func TestHyperlane7683BindingSelectors(t *testing.T) {
+	pinned := pinnedABI(t)
+	bound, err := Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+	for name, method := range pinned.Methods {
+		require.Contains(t, bound.Methods, name)
+		assert.Equal(t, method.ID, bound.Methods[name].ID, "selector of %s", method.Sig)
+	}
+	for name, event := range pinned.Events {
+		require.Contains(t, bound.Events, name)
+		assert.Equal(t, event.ID, bound.Events[name].ID, "topic of %s", event.Sig)
+	}
+}
+
+func TestHyperlane7683Calls(t *testing.T) {
+	chain := evmtest.NewChain(t)
+	resolved := testResolvedOrder(chain.From)
+	hyperlane := deployHyperlane7683Stub(t, chain, resolved)
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	t.Run("Resolve", func(t *testing.T) {
+		got, err := hyperlane.Resolve(opts, OnchainCrossChainOrder{FillDeadline: 1700003600, OrderDataType: bytes32("type"), OrderData: []byte{1}})
+		require.NoError(t, err)
+		assert.Equal(t, resolved, got)
+	})
+
+	t.Run("OrderStatus", func(t *testing.T) {
+		got, err := hyperlane.OrderStatus(opts, resolved.OrderId)
+		require.NoError(t, err)
+		assert.Equal(t, bytes32("FILLED"), got)
+	})
+
+	t.Run("QuoteGasPayment", func(t *testing.T) {
+		got, err := hyperlane.QuoteGasPayment(opts, 11155420)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(123456789), got)
+	})
+
+	t.Run("IsValidNonce", func(t *testing.T) {
+		got, err := hyperlane.IsValidNonce(opts, chain.From, big.NewInt(7))
+		require.NoError(t, err)
+		assert.True(t, got)
+	})
+
+	t.Run("unanswered calls revert", func(t *testing.T) {
+		_, err := hyperlane.LocalDomain(opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestHyperlane7683OpenAndFilterOpen(t *testing.T) {
+	chain := evmtest.NewChain(t)
+	resolved := testResolvedOrder(chain.From)
+	hyperlane := deployHyperlane7683Stub(t, chain, resolved)
+
+	order := OnchainCrossChainOrder{FillDeadline: resolved.FillDeadline, OrderDataType: bytes32("type"), OrderData: []byte("order data")}
+	opts := chain.Transactor(t)
+	opts.Value = big.NewInt(1000)
+	tx, err := hyperlane.Open(opts, order)
+	require.NoError(t, err)
+	receipt := chain.Mine(t, tx)
+
+	// The binding encodes open's argument as the contract declares it
+	want, err := pinnedABI(t).Pack("open", order)
+	require.NoError(t, err)
+	assert.Equal(t, want, tx.Data())
+	assert.Equal(t, big.NewInt(1000), tx.Value())
+
+	require.Len(t, receipt.Logs, 1)
+	parsed, err := hyperlane.ParseOpen(*receipt.Logs[0])
+	require.NoError(t, err)
+	assert.Equal(t, resolved.OrderId, parsed.OrderId)
+	assert.Equal(t, resolved, parsed.ResolvedOrder)
+
+	tests := []struct {
+		name     string
+		orderIDs [][32]byte
+		want     int
+	}{
+		{"every order", nil, 1},
+		{"by order ID", [][32]byte{resolved.OrderId}, 1},
+		{"another order ID", [][32]byte{common.HexToHash("0x01")}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := receipt.BlockNumber.Uint64()
+			iter, err := hyperlane.FilterOpen(&bind.FilterOpts{Start: 0, End: &end, Context: context.Background()}, tt.orderIDs)
+			require.NoError(t, err)
+			defer iter.Close()
+			var events []*Hyperlane7683Open
+			for iter.Next() {
+				events = append(events, iter.Event)
+			}
+			require.NoError(t, iter.Error())
+			require.Len(t, events, tt.want)
+			if tt.want > 0 {
+				assert.Equal(t, resolved, events[0].ResolvedOrder)
+				assert.Equal(t, tx.Hash(), events[0].Raw.TxHash)
+			}
+		})
+	}
+}
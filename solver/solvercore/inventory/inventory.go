@@ -0,0 +1,421 @@
+package inventory
+
+// Module: Solver token inventory
+// - Tracks the solver's balance of every configured token on every network, re-read from the chains periodically
+// - Fills reserve what they spend before sending, so concurrent fills never spend more than the balance less the
+//   reserve buffer; a sent fill's amount comes off the balance until the next read
+// - Warns when a token's available amount drops below its low threshold and, with auto-rebalance, hands a
+//   rebalance plan to the hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/tokenspec"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// readTimeout bounds one balance read
+const readTimeout = 10 * time.Second
+
+// ErrInsufficientInventory is returned by Reserve when a fill would spend more than is available
+var ErrInsufficientInventory = errors.New("insufficient inventory")
+
+// Token is a token the inventory tracks on one network
+type Token struct {
+	Network config.NetworkConfig
+	Symbol  string
+	Address string
+}
+
+// BalanceReader reads the solver's balance of token
+type BalanceReader func(ctx context.Context, token Token) (*big.Int, error)
+
+// Rebalancer is the auto-rebalance hook, called when a token drops below its low threshold
+type Rebalancer func(ctx context.Context, plan RebalancePlan)
+
+// Balance is what the inventory knows about one token. Balance and Available are nil until the first read
+type Balance struct {
+	Network      string    `json:"network"`
+	Symbol       string    `json:"symbol"`
+	Token        string    `json:"token"`
+	Balance      *big.Int  `json:"balance"`
+	Reserved     *big.Int  `json:"reserved"`
+	Available    *big.Int  `json:"available"`
+	Reserve      *big.Int  `json:"reserve,omitempty"`
+	LowThreshold *big.Int  `json:"lowThreshold,omitempty"`
+	Low          bool      `json:"low"`
+	UpdatedAt    time.Time `json:"updatedAt,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// RebalancePlan suggests moving Amount of a low token from Source, the network with the largest surplus of the
+// same symbol, e.g. by opening a reverse order. Source is nil when no network has any to spare
+type RebalancePlan struct {
+	Low    Balance
+	Source *Balance
+	Amount *big.Int
+}
+
+// entry is a tracked token; every field but token is guarded by Inventory.mu
+type entry struct {
+	token        Token
+	balance      *big.Int
+	reserved     *big.Int
+	reserve      *big.Int
+	lowThreshold *big.Int
+	updatedAt    time.Time
+	err          string
+	low          bool
+	// commits counts the fills taken off balance, so a read that raced one does not put the amount back
+	commits uint64
+}
+
+// Inventory is the solver's token ledger. A nil *Inventory tracks nothing and allows every fill
+type Inventory struct {
+	read          BalanceReader
+	interval      time.Duration
+	autoRebalance bool
+	now           func() time.Time
+
+	mu        sync.Mutex
+	entries   []*entry
+	byKey     map[config.TokenKey]*entry
+	rebalance Rebalancer
+}
+
+// New creates an inventory of tokens, read with read and configured by cfg
+func New(cfg config.InventoryConfig, tokens []Token, read BalanceReader) *Inventory {
+	inv := &Inventory{
+		read:          read,
+		interval:      cfg.RefreshInterval,
+		autoRebalance: cfg.AutoRebalance,
+		now:           time.Now,
+		byKey:         make(map[config.TokenKey]*entry),
+		rebalance:     LogRebalance,
+	}
+	if inv.interval <= 0 {
+		inv.interval = config.DefaultInventoryRefreshInterval
+	}
+	for _, token := range tokens {
+		e := &entry{token: token, reserved: new(big.Int)}
+		for _, key := range tokenKeys(token) {
+			if _, tracked := inv.byKey[key]; tracked {
+				continue
+			}
+			inv.byKey[key] = e
+			if e.reserve == nil {
+				e.reserve = cfg.Reserve[key]
+			}
+			if e.lowThreshold == nil {
+				e.lowThreshold = cfg.LowThreshold[key]
+			}
+		}
+		inv.entries = append(inv.entries, e)
+	}
+	sort.SliceStable(inv.entries, func(i, j int) bool {
+		if inv.entries[i].token.Network.Name != inv.entries[j].token.Network.Name {
+			return inv.entries[i].token.Network.Name < inv.entries[j].token.Network.Name
+		}
+		return inv.entries[i].token.Symbol < inv.entries[j].token.Symbol
+	})
+	return inv
+}
+
+// ConfiguredTokens lists the deploy token set (see tokenspec) on every network, skipping tokens whose address is
+// not found
+func ConfiguredTokens(networks map[string]config.NetworkConfig) []Token {
+	specs, err := tokenspec.Load(tokenspec.Path())
+	if err != nil {
+		specs = tokenspec.Defaults()
+	}
+	var tokens []Token
+	for _, network := range networks {
+		seen := make(map[string]bool)
+		for _, spec := range specs {
+			address, _, err := tokenspec.Address(network.Name, spec.Name)
+			if err != nil || seen[config.NormalizeTokenAddress(address)] {
+				continue
+			}
+			seen[config.NormalizeTokenAddress(address)] = true
+			tokens = append(tokens, Token{Network: network, Symbol: spec.Symbol, Address: address})
+		}
+	}
+	return tokens
+}
+
+// SetRebalancer replaces the auto-rebalance hook, LogRebalance by default. It is only called with auto-rebalance on
+func (inv *Inventory) SetRebalancer(rebalance Rebalancer) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.rebalance = rebalance
+}
+
+// Run refreshes the balances now and then every refresh interval until ctx is done
+func (inv *Inventory) Run(ctx context.Context) {
+	if inv == nil || len(inv.entries) == 0 {
+		return
+	}
+	ticker := time.NewTicker(inv.interval)
+	defer ticker.Stop()
+	for {
+		inv.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh reads every tracked token's balance. A failed read keeps the last balance and records the error
+func (inv *Inventory) Refresh(ctx context.Context) {
+	if inv == nil {
+		return
+	}
+	for _, e := range inv.entries {
+		inv.mu.Lock()
+		commits := e.commits
+		inv.mu.Unlock()
+
+		readCtx, cancel := context.WithTimeout(ctx, readTimeout)
+		balance, err := inv.read(readCtx, e.token)
+		cancel()
+
+		inv.mu.Lock()
+		if err != nil {
+			e.err = err.Error()
+			inv.mu.Unlock()
+			fmt.Printf("⚠️  Failed to read the solver's %s balance on %s: %v\n", e.token.Symbol, e.token.Network.Name, err)
+			continue
+		}
+		// A fill committed during the read may not be in it yet; keep the lower balance until the next read
+		if e.commits != commits && e.balance != nil && e.balance.Cmp(balance) < 0 {
+			balance = e.balance
+		}
+		e.balance, e.err, e.updatedAt = new(big.Int).Set(balance), "", inv.now()
+		plan := inv.updatedLocked(e)
+		rebalance := inv.rebalance
+		inv.mu.Unlock()
+		if plan != nil && rebalance != nil {
+			rebalance(ctx, *plan)
+		}
+	}
+}
+
+// Balances returns every tracked token, by network and symbol
+func (inv *Inventory) Balances() []Balance {
+	if inv == nil {
+		return []Balance{}
+	}
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	balances := make([]Balance, 0, len(inv.entries))
+	for _, e := range inv.entries {
+		balances = append(balances, e.snapshot())
+	}
+	return balances
+}
+
+// Reserve sets aside what an order's fill spends, all or nothing. It fails with ErrInsufficientInventory when a
+// token's available amount does not cover it; tokens that are not tracked or not read yet are not checked
+func (inv *Inventory) Reserve(orderID string, outputs []types.Output) (*Reservation, error) {
+	if inv == nil {
+		return nil, nil
+	}
+	needs := make(map[*entry]*big.Int)
+	var order []*entry
+	for _, output := range outputs {
+		e, tracked := inv.byKey[outputKey(output)]
+		if !tracked || output.Amount == nil || output.Amount.Sign() <= 0 {
+			continue
+		}
+		if needs[e] == nil {
+			needs[e] = new(big.Int)
+			order = append(order, e)
+		}
+		needs[e].Add(needs[e], output.Amount)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	for _, e := range order {
+		available := e.available()
+		if available != nil && available.Cmp(needs[e]) < 0 {
+			return nil, fmt.Errorf("%w: order %s needs %s %s on %s, %s available", ErrInsufficientInventory,
+				orderID, needs[e], e.token.Symbol, e.token.Network.Name, available)
+		}
+	}
+	for _, e := range order {
+		e.reserved.Add(e.reserved, needs[e])
+		metrics.Default.InventoryChanged(e.token.Network.Name, e.token.Symbol, e.balance, e.reserved)
+	}
+	return &Reservation{inventory: inv, holds: needs}, nil
+}
+
+// Reservation is what one fill has set aside. A nil *Reservation holds nothing
+type Reservation struct {
+	inventory *Inventory
+	holds     map[*entry]*big.Int
+	done      bool
+}
+
+// Release gives the reserved amounts back, for a fill that was not sent
+func (r *Reservation) Release() {
+	r.finish(context.Background(), false)
+}
+
+// Commit takes the reserved amounts off the balances, for a fill that was sent; the next read replaces them
+func (r *Reservation) Commit(ctx context.Context) {
+	r.finish(ctx, true)
+}
+
+func (r *Reservation) finish(ctx context.Context, spent bool) {
+	if r == nil {
+		return
+	}
+	inv := r.inventory
+	inv.mu.Lock()
+	if r.done {
+		inv.mu.Unlock()
+		return
+	}
+	r.done = true
+	var plans []RebalancePlan
+	for e, amount := range r.holds {
+		e.reserved.Sub(e.reserved, amount)
+		if spent {
+			e.commits++
+			if e.balance != nil {
+				e.balance.Sub(e.balance, amount)
+				if e.balance.Sign() < 0 {
+					e.balance.SetInt64(0)
+				}
+			}
+		}
+		if plan := inv.updatedLocked(e); plan != nil {
+			plans = append(plans, *plan)
+		}
+	}
+	rebalance := inv.rebalance
+	inv.mu.Unlock()
+	if rebalance != nil {
+		for _, plan := range plans {
+			rebalance(ctx, plan)
+		}
+	}
+}
+
+// updatedLocked publishes e's new amounts and warns when it drops below its low threshold. It returns the plan
+// to hand the rebalance hook when auto-rebalance is on and e just became low
+func (inv *Inventory) updatedLocked(e *entry) *RebalancePlan {
+	metrics.Default.InventoryChanged(e.token.Network.Name, e.token.Symbol, e.balance, e.reserved)
+	available := e.available()
+	low := e.lowThreshold != nil && available != nil && available.Cmp(e.lowThreshold) < 0
+	wasLow := e.low
+	e.low = low
+	if !low || wasLow {
+		return nil
+	}
+	fmt.Printf("⚠️  Low inventory: %s %s available on %s, below the %s threshold\n",
+		available, e.token.Symbol, e.token.Network.Name, e.lowThreshold)
+	if !inv.autoRebalance {
+		return nil
+	}
+	return inv.planLocked(e)
+}
+
+// planLocked picks the network with the largest surplus of e's symbol to rebalance from
+func (inv *Inventory) planLocked(e *entry) *RebalancePlan {
+	shortfall := new(big.Int).Sub(e.lowThreshold, e.available())
+	plan := &RebalancePlan{Low: e.snapshot(), Amount: shortfall}
+	var best *big.Int
+	for _, other := range inv.entries {
+		if other == e || !strings.EqualFold(other.token.Symbol, e.token.Symbol) || other.available() == nil {
+			continue
+		}
+		surplus := new(big.Int).Set(other.available())
+		if other.lowThreshold != nil {
+			surplus.Sub(surplus, other.lowThreshold)
+		}
+		if surplus.Sign() > 0 && (best == nil || surplus.Cmp(best) > 0) {
+			source := other.snapshot()
+			plan.Source, best = &source, surplus
+		}
+	}
+	if best != nil && best.Cmp(shortfall) < 0 {
+		plan.Amount = best
+	}
+	return plan
+}
+
+// LogRebalance is the default rebalance hook: it logs the plan for an operator to act on
+func LogRebalance(_ context.Context, plan RebalancePlan) {
+	if plan.Source == nil {
+		fmt.Printf("🔁 Rebalance %s on %s: no other network has %s to spare\n", plan.Low.Symbol, plan.Low.Network, plan.Low.Symbol)
+		return
+	}
+	fmt.Printf("🔁 Rebalance %s on %s: open an order from %s to %s for %s %s\n", plan.Low.Symbol, plan.Low.Network,
+		plan.Source.Network, plan.Low.Network, plan.Amount, plan.Low.Symbol)
+}
+
+// available is the balance less what is reserved and the reserve buffer, at least zero; nil until the first read
+func (e *entry) available() *big.Int {
+	if e.balance == nil {
+		return nil
+	}
+	available := new(big.Int).Sub(e.balance, e.reserved)
+	if e.reserve != nil {
+		available.Sub(available, e.reserve)
+	}
+	if available.Sign() < 0 {
+		available.SetInt64(0)
+	}
+	return available
+}
+
+func (e *entry) snapshot() Balance {
+	balance := Balance{
+		Network:      e.token.Network.Name,
+		Symbol:       e.token.Symbol,
+		Token:        e.token.Address,
+		Reserved:     new(big.Int).Set(e.reserved),
+		Available:    e.available(),
+		Reserve:      e.reserve,
+		LowThreshold: e.lowThreshold,
+		Low:          e.low,
+		UpdatedAt:    e.updatedAt,
+		Error:        e.err,
+	}
+	if e.balance != nil {
+		balance.Balance = new(big.Int).Set(e.balance)
+	}
+	return balance
+}
+
+// tokenKeys returns the keys orders may name token by: under the network's chain ID and its Hyperlane domain
+func tokenKeys(token Token) []config.TokenKey {
+	address := config.NormalizeTokenAddress(token.Address)
+	keys := []config.TokenKey{{ChainID: token.Network.ChainID, Token: address}}
+	if domain := token.Network.HyperlaneDomain; domain != 0 && domain != token.Network.ChainID {
+		keys = append(keys, config.TokenKey{ChainID: domain, Token: address})
+	}
+	return keys
+}
+
+// outputKey identifies the token an order output spends
+func outputKey(output types.Output) config.TokenKey {
+	key := config.TokenKey{Token: config.NormalizeTokenAddress(output.Token)}
+	if output.ChainID != nil && output.ChainID.IsUint64() {
+		key.ChainID = output.ChainID.Uint64()
+	}
+	return key
+}
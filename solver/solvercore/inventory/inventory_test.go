@@ -0,0 +1,268 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dogBase     = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	dogStarknet = "0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+	orcaBase    = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
+)
+
+var (
+	base     = config.NetworkConfig{Name: "Base", ChainID: 84532, HyperlaneDomain: 84532}
+	starknet = config.NetworkConfig{Name: "Starknet", ChainID: 393402133025997798, HyperlaneDomain: 23448594291968334}
+)
+
+// fakeChain serves balances from a map keyed by network and symbol
+type fakeChain struct {
+	mu       sync.Mutex
+	balances map[string]*big.Int
+	err      error
+	// during runs inside a read, after the balance is taken
+	during func()
+}
+
+func (c *fakeChain) read(_ context.Context, token Token) (*big.Int, error) {
+	c.mu.Lock()
+	balance, err, during := c.balances[token.Network.Name+"/"+token.Symbol], c.err, c.during
+	c.mu.Unlock()
+	if during != nil {
+		during()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if balance == nil {
+		return new(big.Int), nil
+	}
+	return new(big.Int).Set(balance), nil
+}
+
+func newTestInventory(t *testing.T, cfg config.InventoryConfig, balances map[string]int64) (*Inventory, *fakeChain) {
+	t.Helper()
+	chain := &fakeChain{balances: make(map[string]*big.Int)}
+	for key, balance := range balances {
+		chain.balances[key] = big.NewInt(balance)
+	}
+	inv := New(cfg, []Token{
+		{Network: base, Symbol: "DOG", Address: dogBase},
+		{Network: base, Symbol: "ORCA", Address: orcaBase},
+		{Network: starknet, Symbol: "DOG", Address: dogStarknet},
+	}, chain.read)
+	inv.Refresh(context.Background())
+	return inv, chain
+}
+
+func spend(chainID uint64, token string, amount int64) types.Output {
+	return types.Output{Token: token, Amount: big.NewInt(amount), ChainID: new(big.Int).SetUint64(chainID)}
+}
+
+func find(t *testing.T, inv *Inventory, network, symbol string) Balance {
+	t.Helper()
+	for _, balance := range inv.Balances() {
+		if balance.Network == network && balance.Symbol == symbol {
+			return balance
+		}
+	}
+	t.Fatalf("%s on %s is not tracked", symbol, network)
+	return Balance{}
+}
+
+func assertAmount(t *testing.T, want int64, got *big.Int) {
+	t.Helper()
+	if assert.NotNil(t, got) {
+		assert.Equal(t, big.NewInt(want).String(), got.String())
+	}
+}
+
+func TestReserveUnderConcurrentFills(t *testing.T) {
+	tests := []struct {
+		name        string
+		reserve     int64
+		wantReserve int
+	}{
+		{"no reserve buffer", 0, 50},
+		{"reserve buffer", 100, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.InventoryConfig{Reserve: map[config.TokenKey]*big.Int{
+				{ChainID: base.ChainID, Token: config.NormalizeTokenAddress(dogBase)}: big.NewInt(tt.reserve),
+			}}
+			inv, _ := newTestInventory(t, cfg, map[string]int64{"Base/DOG": 500})
+
+			var wg sync.WaitGroup
+			var reserved atomic.Int64
+			reservations := make(chan *Reservation, 100)
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					reservation, err := inv.Reserve(fmt.Sprintf("0x%x", i), []types.Output{spend(base.ChainID, dogBase, 10)})
+					if err != nil {
+						assert.ErrorIs(t, err, ErrInsufficientInventory)
+						return
+					}
+					reserved.Add(1)
+					reservations <- reservation
+				}(i)
+			}
+			wg.Wait()
+			close(reservations)
+
+			assert.Equal(t, int64(tt.wantReserve), reserved.Load())
+			dog := find(t, inv, "Base", "DOG")
+			assertAmount(t, int64(tt.wantReserve*10), dog.Reserved)
+			assertAmount(t, 0, dog.Available)
+
+			// Half the fills are sent, the others released, concurrently
+			i := 0
+			for reservation := range reservations {
+				wg.Add(1)
+				go func(reservation *Reservation, send bool) {
+					defer wg.Done()
+					if send {
+						reservation.Commit(context.Background())
+					} else {
+						reservation.Release()
+					}
+					reservation.Release() // finishing twice is a no-op
+				}(reservation, i%2 == 0)
+				i++
+			}
+			wg.Wait()
+
+			dog = find(t, inv, "Base", "DOG")
+			assertAmount(t, 0, dog.Reserved)
+			assertAmount(t, 500-int64(tt.wantReserve/2*10), dog.Balance)
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	t.Run("all or nothing across tokens", func(t *testing.T) {
+		inv, _ := newTestInventory(t, config.InventoryConfig{}, map[string]int64{"Base/DOG": 100, "Base/ORCA": 5})
+		_, err := inv.Reserve("0x1", []types.Output{spend(base.ChainID, dogBase, 50), spend(base.ChainID, orcaBase, 6)})
+		require.ErrorIs(t, err, ErrInsufficientInventory)
+		assert.Contains(t, err.Error(), "needs 6 ORCA on Base, 5 available")
+		assertAmount(t, 0, find(t, inv, "Base", "DOG").Reserved)
+	})
+
+	t.Run("outputs of one token add up", func(t *testing.T) {
+		inv, _ := newTestInventory(t, config.InventoryConfig{}, map[string]int64{"Base/DOG": 100})
+		_, err := inv.Reserve("0x1", []types.Output{spend(base.ChainID, dogBase, 60), spend(base.ChainID, dogBase, 60)})
+		assert.ErrorIs(t, err, ErrInsufficientInventory)
+	})
+
+	t.Run("orders naming the Hyperlane domain and a padded address", func(t *testing.T) {
+		inv, _ := newTestInventory(t, config.InventoryConfig{}, map[string]int64{"Starknet/DOG": 100})
+		padded := "0x0" + dogStarknet[2:]
+		reservation, err := inv.Reserve("0x1", []types.Output{spend(starknet.HyperlaneDomain, padded, 70)})
+		require.NoError(t, err)
+		assertAmount(t, 70, find(t, inv, "Starknet", "DOG").Reserved)
+		reservation.Release()
+		assertAmount(t, 0, find(t, inv, "Starknet", "DOG").Reserved)
+	})
+
+	t.Run("untracked and unread tokens are not checked", func(t *testing.T) {
+		chain := &fakeChain{err: errors.New("connection refused")}
+		inv := New(config.InventoryConfig{}, []Token{{Network: base, Symbol: "DOG", Address: dogBase}}, chain.read)
+		inv.Refresh(context.Background())
+		_, err := inv.Reserve("0x1", []types.Output{spend(base.ChainID, dogBase, 1e9), spend(1, "0xabc", 1e9)})
+		require.NoError(t, err)
+		dog := find(t, inv, "Base", "DOG")
+		assert.Nil(t, dog.Balance)
+		assert.Contains(t, dog.Error, "connection refused")
+	})
+
+	t.Run("nil inventory", func(t *testing.T) {
+		var inv *Inventory
+		reservation, err := inv.Reserve("0x1", []types.Output{spend(base.ChainID, dogBase, 1)})
+		require.NoError(t, err)
+		reservation.Commit(context.Background())
+		assert.Empty(t, inv.Balances())
+	})
+}
+
+func TestRefreshKeepsFillsCommittedDuringRead(t *testing.T) {
+	inv, chain := newTestInventory(t, config.InventoryConfig{}, map[string]int64{"Base/DOG": 100})
+	reservation, err := inv.Reserve("0x1", []types.Output{spend(base.ChainID, dogBase, 30)})
+	require.NoError(t, err)
+
+	// The fill lands after the chain was read: the read's 100 must not undo it
+	chain.during = func() { reservation.Commit(context.Background()) }
+	inv.Refresh(context.Background())
+	assertAmount(t, 70, find(t, inv, "Base", "DOG").Balance)
+
+	chain.during = nil
+	chain.balances["Base/DOG"] = big.NewInt(70)
+	inv.Refresh(context.Background())
+	assertAmount(t, 70, find(t, inv, "Base", "DOG").Balance)
+}
+
+func TestLowInventoryRebalance(t *testing.T) {
+	threshold := func(network config.NetworkConfig, token string, amount int64) (config.TokenKey, *big.Int) {
+		return config.TokenKey{ChainID: network.ChainID, Token: config.NormalizeTokenAddress(token)}, big.NewInt(amount)
+	}
+	lowThreshold := make(map[config.TokenKey]*big.Int)
+	key, amount := threshold(base, dogBase, 100)
+	lowThreshold[key] = amount
+	key, amount = threshold(starknet, dogStarknet, 200)
+	lowThreshold[key] = amount
+
+	tests := []struct {
+		name          string
+		autoRebalance bool
+		starknetDog   int64
+		wantPlans     int
+		wantSource    string
+		wantAmount    int64
+	}{
+		{"disabled", false, 1000, 0, "", 0},
+		{"source covers the shortfall", true, 1000, 1, "Starknet", 60},
+		{"source covers part of it", true, 230, 1, "Starknet", 30},
+		{"no source", true, 150, 1, "", 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.InventoryConfig{LowThreshold: lowThreshold, AutoRebalance: tt.autoRebalance}
+			inv, _ := newTestInventory(t, cfg, map[string]int64{"Base/DOG": 150, "Starknet/DOG": tt.starknetDog})
+			var plans []RebalancePlan
+			inv.SetRebalancer(func(_ context.Context, plan RebalancePlan) { plans = append(plans, plan) })
+
+			reservation, err := inv.Reserve("0x1", []types.Output{spend(base.ChainID, dogBase, 110)})
+			require.NoError(t, err)
+			reservation.Commit(context.Background())
+			assert.True(t, find(t, inv, "Base", "DOG").Low)
+
+			// Still low after the next read: the hook is only called when a token becomes low
+			inv.Refresh(context.Background())
+
+			require.Len(t, plans, tt.wantPlans)
+			if tt.wantPlans == 0 {
+				return
+			}
+			assert.Equal(t, "Base", plans[0].Low.Network)
+			assertAmount(t, tt.wantAmount, plans[0].Amount)
+			if tt.wantSource == "" {
+				assert.Nil(t, plans[0].Source)
+			} else {
+				require.NotNil(t, plans[0].Source)
+				assert.Equal(t, tt.wantSource, plans[0].Source.Network)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package solvercore
+
+// Module: Balance reads for the solver's inventory
+// - Reads the solver's ERC20 balances with the same helpers as the balance rule and the balances tool
+// - EVM networks use the manager's clients; Cairo networks get a provider each, created on first read
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// inventoryReader reads balances for the inventory; it caches one provider per Cairo network
+type inventoryReader struct {
+	manager *SolverManager
+
+	mu        sync.Mutex
+	providers map[string]*rpc.Provider
+}
+
+func newInventoryReader(manager *SolverManager) *inventoryReader {
+	return &inventoryReader{manager: manager, providers: make(map[string]*rpc.Provider)}
+}
+
+// read returns the solver's balance of token
+func (r *inventoryReader) read(ctx context.Context, token inventory.Token) (*big.Int, error) {
+	network := token.Network
+	if !network.Type.IsCairo() {
+		owner := envutil.GetSolverPublicKey()
+		if owner == "" {
+			return nil, fmt.Errorf("solver public key not set")
+		}
+		client, err := r.manager.GetEVMClient(network.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		return ethutil.ERC20BalanceAt(ctx, client, common.HexToAddress(token.Address), common.HexToAddress(owner), nil)
+	}
+
+	owner := envutil.GetStarknetSolverAddress()
+	if network.Type == config.NetworkTypeZtarknet {
+		owner = envutil.GetZtarknetSolverAddress()
+	}
+	if owner == "" {
+		return nil, fmt.Errorf("%s solver address not set", network.Name)
+	}
+	provider, err := r.provider(network)
+	if err != nil {
+		return nil, err
+	}
+	return starknetutil.ERC20Balance(ctx, provider, token.Address, owner)
+}
+
+func (r *inventoryReader) provider(network config.NetworkConfig) (*rpc.Provider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if provider, ok := r.providers[network.Name]; ok {
+		return provider, nil
+	}
+	provider, err := rpc.NewProvider(network.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s provider: %w", network.Name, err)
+	}
+	r.providers[network.Name] = provider
+	return provider, nil
+}
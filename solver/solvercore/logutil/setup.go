@@ -0,0 +1,95 @@
+package logutil
+
+// Logger setup shared by the solver and the cmd tools
+// Text output is the bare message (emoji progress lines read as before); LOG_FORMAT=json switches to one JSON
+// object per line and LOG_LEVEL picks the level, e.g. debug for calldata dumps or error to keep only failures
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+// Log formats accepted by Configure
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// CleanFormatter outputs only the message. A message that already ends in a newline (a converted
+// fmt.Printf format) is not given a second one
+type CleanFormatter struct{}
+
+// Format implements logrus.Formatter
+func (f *CleanFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return append([]byte(strings.TrimSuffix(entry.Message, "\n")), '\n'), nil
+}
+
+// jsonFormatter is logrus' JSON formatter with the message trimmed; blank spacer lines are dropped
+type jsonFormatter struct {
+	logrus.JSONFormatter
+}
+
+// Format implements logrus.Formatter
+func (f *jsonFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	entry.Message = strings.TrimSpace(entry.Message)
+	if entry.Message == "" {
+		return nil, nil
+	}
+	return f.JSONFormatter.Format(entry)
+}
+
+// Configure sets logger's level ("debug", "info", ...) and format (FormatText or FormatJSON); empty values
+// keep the info level and text format
+func Configure(logger *logrus.Logger, level, format string) error {
+	lvl := logrus.InfoLevel
+	if level != "" {
+		parsed, err := logrus.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q: %w", level, err)
+		}
+		lvl = parsed
+	}
+
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		logger.SetFormatter(&CleanFormatter{})
+	case FormatJSON:
+		logger.SetFormatter(&jsonFormatter{})
+	default:
+		return fmt.Errorf("invalid log format %q: expected %s or %s", format, FormatText, FormatJSON)
+	}
+	logger.SetLevel(lvl)
+	return nil
+}
+
+// ConfigureFromConfig applies cfg.LogLevel and cfg.LogFormat (LOG_LEVEL and LOG_FORMAT) to logger. An invalid
+// value is reported on stderr and the previous setting is kept
+func ConfigureFromConfig(logger *logrus.Logger, cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	if err := Configure(logger, cfg.LogLevel, cfg.LogFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// ConfigureFromEnv applies LOG_LEVEL and LOG_FORMAT to logger, for tools that load .env without
+// config.LoadConfig. Invalid values are handled as in ConfigureFromConfig
+func ConfigureFromEnv(logger *logrus.Logger) {
+	ConfigureFromConfig(logger, &config.Config{LogLevel: os.Getenv("LOG_LEVEL"), LogFormat: os.Getenv("LOG_FORMAT")})
+}
+
+// NewToolLogger returns a logger for the cmd tools: clean text on stdout at info level, matching their
+// former fmt.Printf output. Apply LOG_LEVEL/LOG_FORMAT with ConfigureFromConfig once the config is loaded
+func NewToolLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(os.Stdout)
+	logger.SetFormatter(&CleanFormatter{})
+	logger.SetLevel(logrus.InfoLevel)
+	return logger
+}
@@ -0,0 +1,91 @@
+package logutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+)
+
+func newBufferedToolLogger() (*logrus.Logger, *bytes.Buffer) {
+	logger := NewToolLogger()
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	return logger, &buf
+}
+
+func TestToolLoggerTextMatchesPrintf(t *testing.T) {
+	logger, buf := newBufferedToolLogger()
+
+	logger.Infof("\n🎉 Order execution completed!\n")
+	logger.Infof("   Input Amount: %d\n", 1000)
+	logger.Infoln("✅ Connected", "to", 3, "networks")
+	logger.Infoln()
+	logger.Debugf("calldata: 0x%x\n", []byte{0xde, 0xad})
+
+	assert.Equal(t, "\n🎉 Order execution completed!\n   Input Amount: 1000\n✅ Connected to 3 networks\n\n", buf.String())
+}
+
+func TestConfigure(t *testing.T) {
+	t.Run("debug_level", func(t *testing.T) {
+		logger, buf := newBufferedToolLogger()
+		require.NoError(t, Configure(logger, "debug", ""))
+
+		logger.Debugf("calldata: 0x%x\n", []byte{0xde, 0xad})
+		assert.Equal(t, "calldata: 0xdead\n", buf.String())
+	})
+
+	t.Run("error_level_quiets_progress", func(t *testing.T) {
+		logger, buf := newBufferedToolLogger()
+		require.NoError(t, Configure(logger, "error", "text"))
+
+		logger.Infof("   ⏳ Waiting for confirmation...\n")
+		logger.Errorf("❌ %v\n", "open reverted")
+		assert.Equal(t, "❌ open reverted\n", buf.String())
+	})
+
+	t.Run("json_format", func(t *testing.T) {
+		logger, buf := newBufferedToolLogger()
+		require.NoError(t, Configure(logger, "info", "JSON"))
+
+		logger.Infof("\n💰 Funding test users...\n")
+		logger.Infoln()
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 1, "blank spacer lines are dropped")
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(lines[0], &entry))
+		assert.Equal(t, "💰 Funding test users...", entry["msg"])
+		assert.Equal(t, "info", entry["level"])
+	})
+
+	t.Run("invalid_values", func(t *testing.T) {
+		logger := NewToolLogger()
+		assert.Error(t, Configure(logger, "loud", ""))
+		assert.Error(t, Configure(logger, "info", "xml"))
+		assert.Equal(t, logrus.InfoLevel, logger.GetLevel())
+	})
+
+	t.Run("from_config", func(t *testing.T) {
+		logger := NewToolLogger()
+		ConfigureFromConfig(logger, &config.Config{LogLevel: "warn", LogFormat: "text"})
+		assert.Equal(t, logrus.WarnLevel, logger.GetLevel())
+
+		ConfigureFromConfig(logger, &config.Config{LogLevel: "nope"})
+		assert.Equal(t, logrus.WarnLevel, logger.GetLevel(), "an invalid level keeps the previous one")
+	})
+
+	t.Run("from_env", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "debug")
+		t.Setenv("LOG_FORMAT", "json")
+		logger := NewToolLogger()
+		ConfigureFromEnv(logger)
+		assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+		assert.IsType(t, &jsonFormatter{}, logger.Formatter)
+	})
+}
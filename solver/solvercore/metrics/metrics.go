@@ -0,0 +1,187 @@
+package metrics
+
+// Module: Solver metrics
+// - Prometheus counters and histograms for the orders the solver sees, fills and settles
+// - Per-token inventory gauges fed by the inventory's refreshes and reservations
+// - Per-network RPC health fed by the listeners' polls, served as /healthz
+// - Default is what the solver records into; Serve exposes it when METRICS_ADDR is set
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Default is the metrics set the solver records into
+var Default = New()
+
+// Metrics holds the solver's collectors in their own registry, and the last RPC outcome of every network
+type Metrics struct {
+	registry *prometheus.Registry
+
+	ordersObserved *prometheus.CounterVec
+	ordersRejected *prometheus.CounterVec
+	fillsAttempted prometheus.Counter
+	fillsSucceeded prometheus.Counter
+	fillLatency    prometheus.Histogram
+	settleLatency  prometheus.Histogram
+	rpcErrors      *prometheus.CounterVec
+	balance        *prometheus.GaugeVec
+	reserved       *prometheus.GaugeVec
+
+	mu       sync.RWMutex
+	networks map[string]*NetworkHealth
+	now      func() time.Time
+}
+
+// NetworkHealth is the last RPC outcome of one network's listener
+type NetworkHealth struct {
+	Connected   bool      `json:"connected"`
+	LastSuccess time.Time `json:"lastSuccess,omitempty"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// latencyBuckets spans a fill or settle on a local fork (about a second) to one waiting on a slow testnet
+var latencyBuckets = []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300}
+
+// New creates a metrics set with a fresh registry, including the Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		ordersObserved: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_observed_total",
+			Help: "Open events the listeners handed to the solver, by origin and destination network",
+		}, []string{"origin", "destination"}),
+		ordersRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "orders_rejected_total",
+			Help: "Orders the solver declined to fill, by the rule that rejected them",
+		}, []string{"rule"}),
+		fillsAttempted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fills_attempted_total",
+			Help: "Orders that passed the rules and were sent to the destination's fill",
+		}),
+		fillsSucceeded: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fills_succeeded_total",
+			Help: "Fills that completed without error, including orders found already filled",
+		}),
+		fillLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fill_latency_seconds",
+			Help:    "Time from starting a fill to its confirmation on the destination",
+			Buckets: latencyBuckets,
+		}),
+		settleLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "settle_latency_seconds",
+			Help:    "Time from starting a settle to its confirmation on the destination",
+			Buckets: latencyBuckets,
+		}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rpc_errors_total",
+			Help: "Failed listener polls, by network",
+		}, []string{"network"}),
+		balance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "inventory_balance",
+			Help: "The solver's balance of a token in base units, as last read or reduced by fills since, by network and token",
+		}, []string{"network", "token"}),
+		reserved: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "inventory_reserved",
+			Help: "What in-flight fills have reserved of a token in base units, by network and token",
+		}, []string{"network", "token"}),
+		networks: make(map[string]*NetworkHealth),
+		now:      time.Now,
+	}
+	m.registry.MustRegister(
+		m.ordersObserved, m.ordersRejected, m.fillsAttempted, m.fillsSucceeded, m.fillLatency, m.settleLatency, m.rpcErrors,
+		m.balance, m.reserved,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Registry returns the registry the collectors are registered in
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// OrderObserved counts an order a listener handed to the solver
+func (m *Metrics) OrderObserved(origin, destination string) {
+	m.ordersObserved.WithLabelValues(origin, destination).Inc()
+}
+
+// OrderRejected counts an order the solver declined to fill because of rule
+func (m *Metrics) OrderRejected(rule string) {
+	m.ordersRejected.WithLabelValues(rule).Inc()
+}
+
+// FillAttempted counts a fill the solver starts
+func (m *Metrics) FillAttempted() {
+	m.fillsAttempted.Inc()
+}
+
+// FillSucceeded counts a completed fill and records how long it took
+func (m *Metrics) FillSucceeded(took time.Duration) {
+	m.fillsSucceeded.Inc()
+	m.fillLatency.Observe(took.Seconds())
+}
+
+// SettleSucceeded records how long a completed settle took
+func (m *Metrics) SettleSucceeded(took time.Duration) {
+	m.settleLatency.Observe(took.Seconds())
+}
+
+// InventoryChanged records a token's balance and reserved amount; a nil balance (not read yet) is left out
+func (m *Metrics) InventoryChanged(network, token string, balance, reserved *big.Int) {
+	if balance != nil {
+		value, _ := new(big.Float).SetInt(balance).Float64()
+		m.balance.WithLabelValues(network, token).Set(value)
+	}
+	value, _ := new(big.Float).SetInt(reserved).Float64()
+	m.reserved.WithLabelValues(network, token).Set(value)
+}
+
+// TrackNetwork lists a network in /healthz before its first poll, as not connected yet
+func (m *Metrics) TrackNetwork(network string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.networks[network]; !ok {
+		m.networks[network] = &NetworkHealth{}
+	}
+	m.rpcErrors.WithLabelValues(network)
+}
+
+// RPCResult records the outcome of a network's poll: a nil err marks it connected, anything else counts an RPC
+// error and marks it disconnected until the next successful poll
+func (m *Metrics) RPCResult(network string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	health, ok := m.networks[network]
+	if !ok {
+		health = &NetworkHealth{}
+		m.networks[network] = health
+	}
+	if err != nil {
+		m.rpcErrors.WithLabelValues(network).Inc()
+		health.Connected = false
+		health.LastError = err.Error()
+		return
+	}
+	health.Connected = true
+	health.LastSuccess = m.now()
+	health.LastError = ""
+}
+
+// Health returns a copy of every tracked network's health, and whether all of them are connected
+func (m *Metrics) Health() (map[string]NetworkHealth, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	networks := make(map[string]NetworkHealth, len(m.networks))
+	healthy := true
+	for name, health := range m.networks {
+		networks[name] = *health
+		healthy = healthy && health.Connected
+	}
+	return networks, healthy
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape fetches path from m's handler
+func scrape(t *testing.T, m *Metrics, path string) (int, string) {
+	t.Helper()
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestMetricsAfterOrders(t *testing.T) {
+	m := New()
+	m.OrderObserved("Base", "Starknet")
+	m.OrderObserved("Base", "Starknet")
+	m.OrderObserved("Starknet", "Ethereum")
+	m.OrderRejected("FillPolicy")
+	m.FillAttempted()
+	m.FillAttempted()
+	m.FillSucceeded(3 * time.Second)
+	m.SettleSucceeded(40 * time.Second)
+	m.RPCResult("Base", errors.New("connection refused"))
+	m.InventoryChanged("Base", "DogCoin", big.NewInt(5000), big.NewInt(1200))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.ordersObserved.WithLabelValues("Base", "Starknet")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ordersObserved.WithLabelValues("Starknet", "Ethereum")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.fillsAttempted))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.fillsSucceeded))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.rpcErrors.WithLabelValues("Base")))
+
+	status, body := scrape(t, m, "/metrics")
+	require.Equal(t, http.StatusOK, status)
+	for _, line := range []string{
+		`orders_observed_total{destination="Starknet",origin="Base"} 2`,
+		`orders_observed_total{destination="Ethereum",origin="Starknet"} 1`,
+		`orders_rejected_total{rule="FillPolicy"} 1`,
+		`fills_attempted_total 2`,
+		`fills_succeeded_total 1`,
+		`fill_latency_seconds_bucket{le="5"} 1`,
+		`fill_latency_seconds_bucket{le="2"} 0`,
+		`fill_latency_seconds_sum 3`,
+		`settle_latency_seconds_bucket{le="60"} 1`,
+		`settle_latency_seconds_count 1`,
+		`rpc_errors_total{network="Base"} 1`,
+		`inventory_balance{network="Base",token="DogCoin"} 5000`,
+		`inventory_reserved{network="Base",token="DogCoin"} 1200`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	m := New()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	m.TrackNetwork("Base")
+	m.TrackNetwork("Starknet")
+
+	// A network that has not been polled yet is not connected
+	status, body := scrape(t, m, "/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Contains(t, body, `"status":"degraded"`)
+
+	m.RPCResult("Base", nil)
+	m.RPCResult("Starknet", nil)
+	status, body = scrape(t, m, "/healthz")
+	require.Equal(t, http.StatusOK, status)
+	var report healthReport
+	require.NoError(t, json.Unmarshal([]byte(body), &report))
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, NetworkHealth{Connected: true, LastSuccess: now}, report.Networks["Base"])
+
+	m.RPCResult("Starknet", errors.New("503 Service Unavailable"))
+	status, body = scrape(t, m, "/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	require.NoError(t, json.Unmarshal([]byte(body), &report))
+	assert.Equal(t, NetworkHealth{Connected: false, LastSuccess: now, LastError: "503 Service Unavailable"}, report.Networks["Starknet"])
+	assert.True(t, report.Networks["Base"].Connected)
+
+	_, metricsBody := scrape(t, m, "/metrics")
+	assert.Contains(t, metricsBody, `rpc_errors_total{network="Base"} 0`)
+	assert.Contains(t, metricsBody, `rpc_errors_total{network="Starknet"} 1`)
+}
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"9464", ":9464", false},
+		{":9464", ":9464", false},
+		{"127.0.0.1:9464", "127.0.0.1:9464", false},
+		{" 0.0.0.0:9100 ", "0.0.0.0:9100", false},
+		{"localhost", "", true},
+		{"0.0.0.0:", "", true},
+		{"a:b:c", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ListenAddr(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
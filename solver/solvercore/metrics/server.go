@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// shutdownTimeout bounds how long in-flight scrapes may finish once the solver stops
+const shutdownTimeout = 5 * time.Second
+
+// healthReport is the body of /healthz
+type healthReport struct {
+	Status   string                   `json:"status"`
+	Networks map[string]NetworkHealth `json:"networks"`
+}
+
+// Handler serves /metrics in the Prometheus text format and /healthz as JSON: 200 when every tracked network's
+// last poll succeeded, 503 otherwise
+func (m *Metrics) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		networks, healthy := m.Health()
+		report := healthReport{Status: "ok", Networks: networks}
+		status := http.StatusOK
+		if !healthy {
+			report.Status, status = "degraded", http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+	return mux
+}
+
+// ListenAddr normalizes METRICS_ADDR or ADMIN_ADDR: a bare port (example.env suggests 9464 for metrics) listens on
+// every interface, which keeps it reachable from the other containers of a docker-compose project
+func ListenAddr(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if addr != "" && !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: expected [host]:port", addr)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return "", fmt.Errorf("invalid address %q: port must be 1-65535", addr)
+	}
+	return addr, nil
+}
+
+// Serve serves m's Handler on addr until ctx is done
+func Serve(ctx context.Context, addr string, m *Metrics) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: m.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+	return nil
+}
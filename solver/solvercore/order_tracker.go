@@ -0,0 +1,165 @@
+package solvercore
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// Module: Order tracking and network pausing for the Solver Manager
+// - Records every order the listeners hand over and where its processing stands
+// - Holds orders whose destination is paused, and hands them back when it is resumed
+// - Safe for concurrent use by the listeners and the admin API
+
+// OrderStatus is where the solver stands with a tracked order
+type OrderStatus string
+
+const (
+	OrderStatusProcessing OrderStatus = "processing" // rules, fill or settle in progress
+	OrderStatusPaused     OrderStatus = "paused"     // held until its destination is resumed
+	OrderStatusCompleted  OrderStatus = "completed"  // filled, and settled where policy settles
+	OrderStatusFailed     OrderStatus = "failed"     // rejected by the rules or failed to fill or settle
+)
+
+// maxTrackedOrders bounds the tracker; the oldest finished orders are dropped first
+const maxTrackedOrders = 1000
+
+// TrackedOrder is an order the solver has seen
+type TrackedOrder struct {
+	OrderID     string      `json:"orderId"`
+	Origin      string      `json:"origin"`
+	Destination string      `json:"destination"`
+	Status      OrderStatus `json:"status"`
+	Error       string      `json:"error,omitempty"`
+	ObservedAt  time.Time   `json:"observedAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+}
+
+// heldOrder is an order held until its destination is resumed, and where it was opened
+type heldOrder struct {
+	args   types.ParsedArgs
+	origin string
+	block  uint64
+}
+
+// orderTracker keeps the tracked orders and the held ones
+type orderTracker struct {
+	mu     sync.RWMutex
+	orders map[string]*TrackedOrder
+	held   map[string]heldOrder
+	now    func() time.Time
+}
+
+func newOrderTracker() *orderTracker {
+	return &orderTracker{
+		orders: make(map[string]*TrackedOrder),
+		held:   make(map[string]heldOrder),
+		now:    time.Now,
+	}
+}
+
+// observe records an order handed over by a listener, opened on origin in block, with status
+func (t *orderTracker) observe(args types.ParsedArgs, origin string, block uint64, destination string, status OrderStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	order, ok := t.orders[args.OrderID]
+	if !ok {
+		order = &TrackedOrder{OrderID: args.OrderID, ObservedAt: now}
+		t.orders[args.OrderID] = order
+	}
+	order.Origin, order.Destination = origin, destination
+	order.Status, order.Error, order.UpdatedAt = status, "", now
+	if status == OrderStatusPaused {
+		t.held[args.OrderID] = heldOrder{args: args, origin: origin, block: block}
+	}
+	t.evict()
+}
+
+// finish records the outcome of processing an order
+func (t *orderTracker) finish(orderID string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	order, ok := t.orders[orderID]
+	if !ok {
+		return
+	}
+	order.Status, order.Error, order.UpdatedAt = OrderStatusCompleted, "", t.now()
+	if err != nil {
+		order.Status, order.Error = OrderStatusFailed, err.Error()
+	}
+}
+
+// release hands back the held orders bound for destination, marking them processing again
+func (t *orderTracker) release(destination string) []heldOrder {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var released []heldOrder
+	for orderID, held := range t.held {
+		order := t.orders[orderID]
+		if order == nil || order.Destination != destination {
+			continue
+		}
+		order.Status, order.UpdatedAt = OrderStatusProcessing, t.now()
+		released = append(released, held)
+		delete(t.held, orderID)
+	}
+	sort.Slice(released, func(i, j int) bool {
+		a, b := t.orders[released[i].args.OrderID], t.orders[released[j].args.OrderID]
+		if !a.ObservedAt.Equal(b.ObservedAt) {
+			return a.ObservedAt.Before(b.ObservedAt)
+		}
+		return a.OrderID < b.OrderID
+	})
+	return released
+}
+
+// list returns a copy of every tracked order, oldest first
+func (t *orderTracker) list() []TrackedOrder {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	orders := make([]TrackedOrder, 0, len(t.orders))
+	for _, order := range t.orders {
+		orders = append(orders, *order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].ObservedAt.Equal(orders[j].ObservedAt) {
+			return orders[i].ObservedAt.Before(orders[j].ObservedAt)
+		}
+		return orders[i].OrderID < orders[j].OrderID
+	})
+	return orders
+}
+
+// get returns a copy of the tracked order orderID
+func (t *orderTracker) get(orderID string) (TrackedOrder, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	order, ok := t.orders[orderID]
+	if !ok {
+		return TrackedOrder{}, false
+	}
+	return *order, true
+}
+
+// evict drops the oldest finished orders beyond maxTrackedOrders; orders still in progress or held are kept
+func (t *orderTracker) evict() {
+	if len(t.orders) <= maxTrackedOrders {
+		return
+	}
+	finished := make([]*TrackedOrder, 0, len(t.orders))
+	for _, order := range t.orders {
+		if order.Status == OrderStatusCompleted || order.Status == OrderStatusFailed {
+			finished = append(finished, order)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].UpdatedAt.Before(finished[j].UpdatedAt) })
+	for _, order := range finished {
+		if len(t.orders) <= maxTrackedOrders {
+			return
+		}
+		delete(t.orders, order.OrderID)
+	}
+}
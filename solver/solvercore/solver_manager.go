@@ -2,14 +2,21 @@ package solvercore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/account"
@@ -42,6 +49,25 @@ type SolverManager struct {
 	activeShutdowns []func()
 	solverRegistry  SolverRegistry
 	allowBlockLists types.AllowBlockLists
+
+	// orders tracks what the listeners handed over; processOrder runs one through the solver once it is started
+	orders       *orderTracker
+	processOrder func(args types.ParsedArgs) (bool, error)
+	// paused holds the destination networks fills are paused on. pauseMu also orders a pause check against a
+	// resume, so no order is held after its network's held orders were released
+	pauseMu sync.RWMutex
+	paused  map[string]bool
+
+	// fromBlocks are the --from-block overrides; listeners are kept by network to checkpoint them on shutdown
+	fromBlocks map[string]uint64
+	listeners  map[string]base.Listener
+
+	// fillPolicy and dryRun are handed to the solvers
+	fillPolicy config.FillPolicy
+	dryRun     bool
+
+	// inventory tracks the solver's token balances and what in-flight fills reserved of them
+	inventory *inventory.Inventory
 }
 
 // NewSolverManager creates a new solver manager
@@ -54,7 +80,7 @@ func NewSolverManager(cfg *config.Config) *SolverManager {
 		},
 	}
 
-	return &SolverManager{
+	sm := &SolverManager{
 		evmClients:      make(map[uint64]*ethclient.Client),
 		starknetClient:  nil, // Will be initialized later
 		activeShutdowns: make([]func(), 0),
@@ -63,7 +89,15 @@ func NewSolverManager(cfg *config.Config) *SolverManager {
 			AllowList: []types.AllowBlockListItem{},
 			BlockList: []types.AllowBlockListItem{},
 		},
+		orders:     newOrderTracker(),
+		paused:     make(map[string]bool),
+		fromBlocks: cfg.FromBlocks,
+		listeners:  make(map[string]base.Listener),
+		fillPolicy: cfg.FillPolicy,
+		dryRun:     cfg.DryRun,
 	}
+	sm.inventory = inventory.New(cfg.Inventory, inventory.ConfiguredTokens(cfg.Networks), newInventoryReader(sm).read)
+	return sm
 }
 
 // SetAllowBlockLists configures the allow/block lists for the solver manager
@@ -87,10 +121,13 @@ func (sm *SolverManager) InitializeSolvers(ctx context.Context) error {
 	}
 
 	// Initialize Starknet client
-	if err := sm.initializeStarknetClients(); err != nil {
+	if err := sm.initializeStarknetClients(ctx); err != nil {
 		return fmt.Errorf("failed to initialize Starknet client: %w", err)
 	}
 
+	// Keep the inventory's balances current; it reads through the clients above
+	go sm.inventory.Run(ctx)
+
 	// Initialize individual solvers
 	for solverName, config := range sm.solverRegistry {
 		if !config.Enabled {
@@ -123,14 +160,11 @@ func (sm *SolverManager) initializeEVMClients() error {
 
 	evmCount := 0
 	for networkName, networkConfig := range config.Networks {
-		// Check if this is NOT a Starknet network (i.e., it's an EVM network)
-		// Explicitly check for both "starknet" and "ztarknet" to avoid any confusion
-		name := strings.ToLower(networkName)
-		if strings.Contains(name, "starknet") || strings.Contains(name, "ztarknet") {
+		if networkConfig.Type != config.NetworkTypeEVM {
 			continue
 		}
 
-		fmt.Printf("   🔄 Initializing %s client (Chain ID: %d)\n", networkName, networkConfig.ChainID)
+		fmt.Printf("   🔄 Initializing %s client (Chain ID: %s)\n", networkName, networkConfig.ChainIDLabel())
 
 		client, err := ethclient.Dial(networkConfig.RPCURL)
 		if err != nil {
@@ -147,17 +181,15 @@ func (sm *SolverManager) initializeEVMClients() error {
 }
 
 // initializeStarknetClients initializes Starknet RPC connection for the first Starknet network found
-func (sm *SolverManager) initializeStarknetClients() error {
+func (sm *SolverManager) initializeStarknetClients(ctx context.Context) error {
 	fmt.Printf("Initializing Cairo clients...\n")
 
 	for networkName, networkConfig := range config.Networks {
-		// Check if this is a Starknet network
-		name := strings.ToLower(networkName)
-		if !strings.Contains(name, "starknet") && !strings.Contains(name, "ztarknet") {
+		if !networkConfig.Type.IsCairo() {
 			continue
 		}
 
-		fmt.Printf("   🔄 Initializing %s client (Chain ID: %d)\n", networkName, networkConfig.ChainID)
+		fmt.Printf("   🔄 Initializing %s client (Chain ID: %s)\n", networkName, networkConfig.ChainIDLabel())
 
 		provider, err := rpc.NewProvider(networkConfig.RPCURL)
 		if err != nil {
@@ -165,6 +197,16 @@ func (sm *SolverManager) initializeStarknetClients() error {
 			fmt.Printf("⚠️  Failed to create Starknet provider for %s: %v\n", networkName, err)
 			continue
 		}
+		// A node on another chain is fatal; one that cannot be reached yet is left to the listeners' retries
+		verifyCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err = config.VerifyStarknetChainID(verifyCtx, provider, networkConfig)
+		cancel()
+		if errors.Is(err, config.ErrChainIDMismatch) {
+			return err
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Could not verify the %s chain ID: %v\n", networkName, err)
+		}
 
 		// Store the last initialized client as the default one
 		// This is a limitation of the current architecture which expects a single Starknet client
@@ -252,7 +294,7 @@ func (sm *SolverManager) GetStarknetSigner() (*account.Account, error) {
 	}
 	ks.Put(pub, privBI)
 
-	acct, err := account.NewAccount(sm.starknetClient, addrF, pub, ks, account.CairoV2)
+	acct, err := starknetutil.NewAccount(context.Background(), sm.starknetClient, starknetutil.AccountVersionEnv("Starknet", "Solver"), addrF, pub, ks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Starknet account: %w", err)
 	}
@@ -273,11 +315,18 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 		sm.allowBlockLists,   // Allow/block lists
 	)
 	hyperlane7683Solver.AddDefaultRules()
+	hyperlane7683Solver.SetFillPolicy(sm.fillPolicy)
+	hyperlane7683Solver.SetDryRun(sm.dryRun)
+	hyperlane7683Solver.SetInventory(sm.inventory)
 
-	// Event handler that processes intents
-	eventHandler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+	// Event handler that processes intents, unless their destination is paused
+	sm.processOrder = func(args types.ParsedArgs) (bool, error) {
 		return hyperlane7683Solver.ProcessIntent(ctx, &args)
 	}
+	eventHandler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+		metrics.Default.OrderObserved(originChainName, destinationName(&args))
+		return sm.handleOrder(args, originChainName, blockNumber)
+	}
 
 	// Start listeners for each intent source
 	fmt.Printf("   📡 Starting network listeners...\n")
@@ -293,7 +342,8 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 		var shutdown base.ShutdownFunc
 
 		// Create appropriate listener based on chain type
-		if source == "Starknet" {
+		switch networkConfig.Type {
+		case config.NetworkTypeStarknet:
 			hyperlaneAddr, err := getStarknetHyperlaneAddress(&networkConfig)
 			if err != nil {
 				return fmt.Errorf("failed to get Starknet Hyperlane address: %w", err)
@@ -309,16 +359,18 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 				networkConfig.ConfirmationBlocks,           // confirmation blocks from config
 				networkConfig.MaxBlockRange,                // max block range from config
 			)
+			listenerConfig.FromBlock = sm.fromBlock(source)
 
 			starknetListener, err := contracts.NewStarknetListener(listenerConfig, networkConfig.RPCURL)
 			if err != nil {
 				return fmt.Errorf("failed to create Starknet listener: %w", err)
 			}
+			sm.listeners[source] = starknetListener
 			shutdown, err = starknetListener.Start(ctx, eventHandler)
 			if err != nil {
 				return fmt.Errorf("failed to start Starknet listener for %s: %w", source, err)
 			}
-		} else if source == "Ztarknet" {
+		case config.NetworkTypeZtarknet:
 			hyperlaneAddr, err := getZtarknetHyperlaneAddress(&networkConfig)
 			if err != nil {
 				return fmt.Errorf("failed to get Ztarknet Hyperlane address: %w", err)
@@ -334,16 +386,18 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 				networkConfig.ConfirmationBlocks,           // confirmation blocks from config
 				networkConfig.MaxBlockRange,                // max block range from config
 			)
+			listenerConfig.FromBlock = sm.fromBlock(source)
 
 			ztarknetListener, err := contracts.NewZtarknetListener(listenerConfig, networkConfig.RPCURL)
 			if err != nil {
 				return fmt.Errorf("failed to create Ztarknet listener: %w", err)
 			}
+			sm.listeners[source] = ztarknetListener
 			shutdown, err = ztarknetListener.Start(ctx, eventHandler)
 			if err != nil {
 				return fmt.Errorf("failed to start Ztarknet listener for %s: %w", source, err)
 			}
-		} else {
+		default:
 			// Create EVM listener config with original solver start block
 			// The listener will handle negative value resolution
 			listenerConfig := base.NewListenerConfig(
@@ -354,11 +408,13 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 				networkConfig.ConfirmationBlocks,           // confirmation blocks from config
 				networkConfig.MaxBlockRange,                // max block range from config
 			)
+			listenerConfig.FromBlock = sm.fromBlock(source)
 
 			evmListener, err := contracts.NewEVMListener(listenerConfig, networkConfig.RPCURL)
 			if err != nil {
 				return fmt.Errorf("failed to create EVM listener: %w", err)
 			}
+			sm.listeners[source] = evmListener
 			shutdown, err = evmListener.Start(ctx, eventHandler)
 			if err != nil {
 				return fmt.Errorf("failed to start EVM listener for %s: %w", source, err)
@@ -366,6 +422,7 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 		}
 
 		sm.activeShutdowns = append(sm.activeShutdowns, shutdown)
+		metrics.Default.TrackNetwork(source)
 		listenerCount++
 		fmt.Printf("     Started listener for %s\n", source)
 	}
@@ -374,6 +431,114 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 	return nil
 }
 
+// handleOrder tracks an order opened on origin in block and processes it, or holds it while its destination is
+// paused, returning base.ErrOrderHeld
+func (sm *SolverManager) handleOrder(args types.ParsedArgs, origin string, block uint64) (bool, error) {
+	destination := destinationName(&args)
+	sm.pauseMu.RLock()
+	if sm.paused[destination] {
+		sm.orders.observe(args, origin, block, destination, OrderStatusPaused)
+		sm.pauseMu.RUnlock()
+		fmt.Printf("⏸️  Holding order %s: fills on %s are paused\n", args.OrderID, destination)
+		return false, base.ErrOrderHeld
+	}
+	sm.orders.observe(args, origin, block, destination, OrderStatusProcessing)
+	sm.pauseMu.RUnlock()
+	return sm.runOrder(args)
+}
+
+// runOrder processes a tracked order and records the outcome
+func (sm *SolverManager) runOrder(args types.ParsedArgs) (bool, error) {
+	settled, err := sm.processOrder(args)
+	sm.orders.finish(args.OrderID, err)
+	return settled, err
+}
+
+// Orders returns the orders the solver is tracking, oldest first
+func (sm *SolverManager) Orders() []TrackedOrder {
+	return sm.orders.list()
+}
+
+// Order returns the tracked order orderID
+func (sm *SolverManager) Order(orderID string) (TrackedOrder, bool) {
+	return sm.orders.get(orderID)
+}
+
+// PauseNetwork stops filling orders bound for the network named name; new ones are held until it is resumed.
+// It returns the configured name of the network
+func (sm *SolverManager) PauseNetwork(name string) (string, error) {
+	network, err := config.CanonicalNetworkName(name)
+	if err != nil {
+		return "", err
+	}
+	sm.pauseMu.Lock()
+	defer sm.pauseMu.Unlock()
+	sm.paused[network] = true
+	return network, nil
+}
+
+// ResumeNetwork fills orders bound for the network named name again, processing the orders held while it was
+// paused in the background and recording those that succeed as handled. It returns the configured name of the
+// network and how many orders were held
+func (sm *SolverManager) ResumeNetwork(name string) (string, int, error) {
+	network, err := config.CanonicalNetworkName(name)
+	if err != nil {
+		return "", 0, err
+	}
+	sm.pauseMu.Lock()
+	delete(sm.paused, network)
+	held := sm.orders.release(network)
+	sm.pauseMu.Unlock()
+
+	if len(held) > 0 {
+		go func() {
+			for _, order := range held {
+				if _, err := sm.runOrder(order.args); err != nil {
+					fmt.Printf("❌ Held order %s failed: %v\n", order.args.OrderID, err)
+					continue
+				}
+				if err := config.MarkOrderHandled(order.origin, order.args.OrderID, order.block); err != nil {
+					fmt.Printf("⚠️  Failed to record handled order %s: %v\n", order.args.OrderID, err)
+				}
+			}
+		}()
+	}
+	return network, len(held), nil
+}
+
+// PausedNetworks returns the networks fills are paused on, in alphabetical order
+func (sm *SolverManager) PausedNetworks() []string {
+	sm.pauseMu.RLock()
+	defer sm.pauseMu.RUnlock()
+	networks := make([]string, 0, len(sm.paused))
+	for network := range sm.paused {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	return networks
+}
+
+// Inventory returns the solver's balance, reservations and thresholds of every tracked token
+func (sm *SolverManager) Inventory() []inventory.Balance {
+	return sm.inventory.Balances()
+}
+
+// destinationName names the destination network of an order for metrics, "unknown" when it has none configured
+func destinationName(args *types.ParsedArgs) string {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return "unknown"
+	}
+	chainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID
+	if chainID == nil || !chainID.IsUint64() {
+		return "unknown"
+	}
+	name, err := config.GetNetworkNameByChainID(chainID.Uint64())
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
+
 // AddSolver dynamically adds a new solver to the registry
 func (sm *SolverManager) AddSolver(name string, config SolverConfig) {
 	sm.solverRegistry[name] = config
@@ -425,9 +590,38 @@ func (sm *SolverManager) Shutdown() {
 	}
 
 	sm.activeShutdowns = make([]func(), 0)
+	sm.flushCheckpoints()
 	fmt.Printf("✅ All solvers shut down successfully (%d listeners stopped)\n", listenerCount)
 }
 
+// fromBlock returns the --from-block override of network, nil when it has none
+func (sm *SolverManager) fromBlock(network string) *big.Int {
+	block, ok := sm.fromBlocks[network]
+	if !ok {
+		return nil
+	}
+	return new(big.Int).SetUint64(block)
+}
+
+// flushCheckpoints saves where each stopped listener got to. Listeners checkpoint every block range they finish,
+// so this only moves a checkpoint forward when the last save failed
+func (sm *SolverManager) flushCheckpoints() {
+	state, err := config.GetSolverState()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to read solver state to checkpoint listeners: %v\n", err)
+		return
+	}
+	for network, listener := range sm.listeners {
+		last := listener.GetLastProcessedBlock()
+		if last <= state.Networks[network].LastIndexedBlock {
+			continue
+		}
+		if err := config.UpdateLastIndexedBlock(network, last); err != nil {
+			fmt.Printf("⚠️  Failed to checkpoint %s at block %d: %v\n", network, last, err)
+		}
+	}
+}
+
 // GetSolverStatus returns the status of all solvers
 func (sm *SolverManager) GetSolverStatus() map[string]bool {
 	status := make(map[string]bool)
@@ -458,35 +652,3 @@ func getZtarknetHyperlaneAddress(_ *config.NetworkConfig) (string, error) {
 		return "", fmt.Errorf("no ZTARKNET_HYPERLANE_ADDRESS set in .env")
 	}
 }
-
-//// getStarknetHyperlaneFromDeploymentState loads Starknet Hyperlane address from deployment state
-// func getStarknetHyperlaneFromDeploymentState() string {
-//	paths := []string{
-//		"state/network_state/deployment-state.json",
-//		"../state/network_state/deployment-state.json",
-//		"../../state/network_state/deployment-state.json",
-//	}
-//	for _, path := range paths {
-//		data, err := os.ReadFile(path)
-//		if err != nil {
-//			continue
-//		}
-//		var deploymentState struct {
-//			Networks map[string]struct {
-//				ChainID          uint64 `json:"chainId"`
-//				HyperlaneAddress string `json:"hyperlaneAddress"`
-//				DogCoinAddress   string `json:"dogCoinAddress"`
-//			} `json:"networks"`
-//		}
-//		if err := json.Unmarshal(data, &deploymentState); err != nil {
-//			continue
-//		}
-//		if stark, ok := deploymentState.Networks["Starknet"]; ok && stark.HyperlaneAddress != "" {
-//			return stark.HyperlaneAddress
-//		}
-//		if starkLegacy, ok := deploymentState.Networks["Starknet"]; ok && starkLegacy.HyperlaneAddress != "" {
-//			return starkLegacy.HyperlaneAddress
-//		}
-//	}
-//	return ""
-//}
@@ -1,12 +1,22 @@
 package solvercore
 
 import (
+	"context"
+	"errors"
+	"maps"
+	"math/big"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewSolverManager(t *testing.T) {
@@ -283,3 +293,172 @@ func TestShutdown(t *testing.T) {
 	assert.Equal(t, 2, shutdownCount)
 	assert.Equal(t, 0, len(sm.activeShutdowns))
 }
+
+func TestPauseHoldsOrdersUntilResumed(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	require.NotEmpty(t, config.GetNetworkNames())
+	optimism := config.Networks["Optimism"].ChainID
+	order := func(id string) types.ParsedArgs {
+		return types.ParsedArgs{
+			OrderID: id,
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				FillInstructions: []types.FillInstruction{{DestinationChainID: new(big.Int).SetUint64(optimism)}},
+			},
+		}
+	}
+
+	sm := NewSolverManager(&config.Config{})
+	var mu sync.Mutex
+	var processed []string
+	sm.processOrder = func(args types.ParsedArgs) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		processed = append(processed, args.OrderID)
+		if args.OrderID == "0xbad" {
+			return false, errors.New("fill reverted")
+		}
+		return true, nil
+	}
+	processedOrders := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), processed...)
+	}
+
+	_, err := sm.PauseNetwork("mars")
+	require.ErrorIs(t, err, config.ErrUnknownNetwork)
+
+	network, err := sm.PauseNetwork("optimism")
+	require.NoError(t, err)
+	assert.Equal(t, "Optimism", network)
+	assert.Equal(t, []string{"Optimism"}, sm.PausedNetworks())
+
+	settled, err := sm.handleOrder(order("0x01"), "Ethereum", 100)
+	require.ErrorIs(t, err, base.ErrOrderHeld)
+	assert.False(t, settled)
+	assert.Empty(t, processedOrders(), "a paused destination holds the order")
+	held, ok := sm.Order("0x01")
+	require.True(t, ok)
+	assert.Equal(t, OrderStatusPaused, held.Status)
+	assert.Equal(t, "Optimism", held.Destination)
+
+	_, err = sm.handleOrder(order("0xbad"), "Base", 200)
+	require.ErrorIs(t, err, base.ErrOrderHeld)
+
+	network, released, err := sm.ResumeNetwork("Optimism")
+	require.NoError(t, err)
+	assert.Equal(t, "Optimism", network)
+	assert.Equal(t, 2, released)
+	assert.Empty(t, sm.PausedNetworks())
+	assert.Eventually(t, func() bool { return len(processedOrders()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"0x01", "0xbad"}, processedOrders(), "held orders run in the order they were observed")
+
+	assert.Eventually(t, func() bool {
+		failed, _ := sm.Order("0xbad")
+		return failed.Status == OrderStatusFailed
+	}, time.Second, 10*time.Millisecond)
+	failed, _ := sm.Order("0xbad")
+	assert.Equal(t, "fill reverted", failed.Error)
+	completed, _ := sm.Order("0x01")
+	assert.Equal(t, OrderStatusCompleted, completed.Status)
+	assert.Eventually(t, func() bool {
+		handled, _ := config.IsOrderHandled("Ethereum", "0x01")
+		return handled
+	}, time.Second, 10*time.Millisecond, "a held order is recorded once it has run")
+	handled, err := config.IsOrderHandled("Base", "0xbad")
+	require.NoError(t, err)
+	assert.False(t, handled, "a failed order is left for the next run")
+
+	settled, err = sm.handleOrder(order("0x02"), "Ethereum", 300)
+	require.NoError(t, err)
+	assert.True(t, settled, "orders are processed straight away once resumed")
+	assert.Len(t, sm.Orders(), 3)
+}
+
+// fixedBlock is a block provider whose chain head is always block
+type fixedBlock uint64
+
+func (b fixedBlock) BlockNumber(context.Context) (uint64, error) {
+	return uint64(b), nil
+}
+
+func TestHeldOrderIsFilledAfterRestart(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	require.NotEmpty(t, config.GetNetworkNames())
+	const origin = "Base"
+	optimism := config.Networks["Optimism"].ChainID
+
+	// Orders on the chain, by the block they were opened in
+	chain := map[uint64][]string{101: {"0xa"}, 110: {"0xb"}}
+	var mu sync.Mutex
+	filled := map[string]int{}
+	newManager := func() *SolverManager {
+		sm := NewSolverManager(&config.Config{})
+		sm.processOrder = func(args types.ParsedArgs) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			filled[args.OrderID]++
+			return true, nil
+		}
+		return sm
+	}
+	filledOrders := func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(filled)
+	}
+	scan := func(_ context.Context, from, to uint64, handler base.EventHandler) (uint64, error) {
+		for b := from; b <= to; b++ {
+			for _, id := range chain[b] {
+				args := types.ParsedArgs{
+					OrderID: id,
+					ResolvedOrder: types.ResolvedCrossChainOrder{
+						FillInstructions: []types.FillInstruction{{DestinationChainID: new(big.Int).SetUint64(optimism)}},
+					},
+				}
+				if _, err := handler(args, origin, b); err != nil {
+					return 0, err
+				}
+			}
+		}
+		return to, nil
+	}
+	listenerConfig := base.NewListenerConfig("0x0", origin, big.NewInt(100), 0, 2, 50)
+	run := func(sm *SolverManager, head uint64) {
+		common, err := contracts.ResolveCommonListenerConfig(context.Background(), listenerConfig, fixedBlock(head))
+		require.NoError(t, err)
+		lastProcessed := common.LastProcessedBlock
+		handler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+			return sm.handleOrder(args, originChainName, blockNumber)
+		}
+		require.NoError(t, contracts.ProcessCurrentBlockRangeCommon(context.Background(), handler, fixedBlock(head), listenerConfig, &lastProcessed, "EVM", scan))
+	}
+
+	// First run: Optimism is paused, so 0xa is held while the checkpoint moves on past it
+	sm := newManager()
+	_, err := sm.PauseNetwork("Optimism")
+	require.NoError(t, err)
+	run(sm, 105)
+	state, err := config.GetSolverState()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(103), state.Networks[origin].LastIndexedBlock)
+
+	// Restart, paused again: the rescan reaches back to 0xa and holds it in the new manager, which fills it on resume
+	sm = newManager()
+	_, err = sm.PauseNetwork("Optimism")
+	require.NoError(t, err)
+	run(sm, 112)
+	assert.Empty(t, filledOrders())
+	_, released, err := sm.ResumeNetwork("Optimism")
+	require.NoError(t, err)
+	assert.Equal(t, 2, released)
+	assert.Eventually(t, func() bool { return len(filledOrders()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		handled, _ := config.IsOrderHandled(origin, "0xb")
+		return handled
+	}, time.Second, 10*time.Millisecond)
+
+	// A further restart fills nothing again
+	run(newManager(), 112)
+	assert.Equal(t, map[string]int{"0xa": 1, "0xb": 1}, filledOrders())
+}
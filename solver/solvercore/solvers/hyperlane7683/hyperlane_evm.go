@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderquery"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
@@ -329,8 +330,7 @@ func (h *HyperlaneEVM) GetOrderStatus(ctx context.Context, args *types.ParsedArg
 	}
 
 	statusHash := common.BytesToHash(res[:32])
-	status := h.interpretStatusHash(ctx, statusHash)
-	return status, nil
+	return h.interpretStatusHash(ctx, destinationSettlerAddr, statusHash)
 }
 
 // getOriginDomainFromArgs extracts the origin domain using the config system
@@ -397,26 +397,18 @@ func (h *HyperlaneEVM) setupApprovals(ctx context.Context, args *types.ParsedArg
 	return nil
 }
 
-func (h *HyperlaneEVM) interpretStatusHash(_ context.Context, statusHash common.Hash) string {
-	if statusHash == (common.Hash{}) {
-		return orderStatusUnknown
-	}
-
-	// Try to read constants from contract for comparison
-	filledHash := common.HexToHash("0x46494c4c45440000000000000000000000000000000000000000000000000000")
-	if statusHash == filledHash {
-		return orderStatusFilled
+// interpretStatusHash names a status word with the status constants the settler itself exposes. A word that is
+// none of them is returned as hex
+func (h *HyperlaneEVM) interpretStatusHash(ctx context.Context, settler common.Address, statusHash common.Hash) (string, error) {
+	constants, err := orderquery.LoadConstants(ctx, fmt.Sprintf("chain %d", h.chainID), settler, h.client)
+	if err != nil {
+		return orderStatusUnknown, err
 	}
-	//	}
-	//}
-
-	// Check hardcoded SETTLED constant
-	settledHash := common.HexToHash("0x534554544c454400000000000000000000000000000000000000000000000000")
-	if statusHash == settledHash {
-		return "SETTLED"
+	status, err := constants.Status(statusHash)
+	if err != nil {
+		return statusHash.Hex(), nil
 	}
-
-	return statusHash.Hex()
+	return status.String(), nil
 }
 
 // ensureTokenApproval ensures the solver has approved an arbitrary ERC20 token for the Hyperlane contract
@@ -97,7 +97,7 @@ func NewHyperlaneStarknet(rpcURL string, chainID uint64) *HyperlaneStarknet {
 	}
 
 	ks.Put(pub, privBI)
-	acct, err := account.NewAccount(provider, addrF, pub, ks, account.CairoV2)
+	acct, err := starknetutil.NewAccount(context.Background(), provider, starknetutil.AccountVersionEnv(chainName, "Solver"), addrF, pub, ks)
 	if err != nil {
 		fmt.Printf("failed to create %s account: %v", chainName, err)
 		return nil
@@ -276,7 +276,7 @@ func (h *HyperlaneStarknet) Settle(ctx context.Context, args *types.ParsedArgs)
 	if err != nil {
 		return fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
 	}
-	gasLow, gasHigh := starknetutil.ConvertBigIntToU256Felts(gasPayment)
+	gasLow, gasHigh := starknetutil.ToU256(gasPayment)
 	calldata := []*felt.Felt{
 		utils.Uint64ToFelt(1),   // order ID array length
 		orderIDLow, orderIDHigh, // order ID (u256) low and high
@@ -436,14 +436,7 @@ func (h *HyperlaneStarknet) quoteGasPayment(ctx context.Context, originDomain ui
 	}
 
 	// Convert two felts (low, high) back to u256
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-
-	// Combine low and high into u256: (high << 128) | low
-	result := new(big.Int).Lsh(high, 128)
-	result.Or(result, low)
-
-	return result, nil
+	return starknetutil.FromU256(resp[0], resp[1]), nil
 }
 
 // EnsureETHApproval ensures the solver has approved the ETH address for settlement
@@ -479,10 +472,7 @@ func (h *HyperlaneStarknet) ensureETHApproval(ctx context.Context, amount *big.I
 	}
 
 	// Convert two felts (low, high) back to u256
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-	currentAllowance := new(big.Int).Lsh(high, 128)
-	currentAllowance.Or(currentAllowance, low)
+	currentAllowance := starknetutil.FromU256(resp[0], resp[1])
 
 	// If allowance is sufficient, no need to approve
 	if currentAllowance.Cmp(amount) >= 0 {
@@ -490,11 +480,7 @@ func (h *HyperlaneStarknet) ensureETHApproval(ctx context.Context, amount *big.I
 	}
 
 	// Need to approve - convert amount to two felts (low, high)
-	low128 := new(big.Int).And(amount, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
-	high128 := new(big.Int).Rsh(amount, 128)
-
-	lowFelt := utils.BigIntToFelt(low128)
-	highFelt := utils.BigIntToFelt(high128)
+	lowFelt, highFelt := starknetutil.ToU256(amount)
 
 	// Build approve calldata: approve(spender: felt, amount: u256)
 	approveCalldata := []*felt.Felt{hyperlaneAddress, lowFelt, highFelt}
@@ -542,9 +528,7 @@ func (h *HyperlaneStarknet) ensureTokenApproval(ctx context.Context, tokenHex st
 		return fmt.Errorf("starknet allowance response too short: %d", len(resp))
 	}
 
-	low := utils.FeltToBigInt(resp[0])
-	high := utils.FeltToBigInt(resp[1])
-	current := new(big.Int).Add(low, new(big.Int).Lsh(high, 128))
+	current := starknetutil.FromU256(resp[0], resp[1])
 	if current.Cmp(amount) >= 0 {
 		return nil
 	}
@@ -2,11 +2,15 @@ package hyperlane7683
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
 // BlockNumberProvider defines the interface for getting the current block number
@@ -78,6 +82,7 @@ func ProcessCurrentBlockRangeCommon(
 	processBlockRange func(context.Context, uint64, uint64, base.EventHandler) (uint64, error),
 ) error {
 	currentBlock, err := blockProvider.BlockNumber(ctx)
+	metrics.Default.RPCResult(listenerConfig.ChainName, err)
 	if err != nil {
 		return fmt.Errorf("failed to get current block number: %v", err)
 	}
@@ -100,6 +105,7 @@ func ProcessCurrentBlockRangeCommon(
 	// Respect MaxBlockRange by chunking large ranges
 	chunkSize := listenerConfig.MaxBlockRange
 	newLast := *lastProcessedBlock
+	handler = handleOnce(listenerConfig.ChainName, handler)
 
 	for start := fromBlock; start <= toBlock; start += chunkSize {
 		end := start + chunkSize - 1
@@ -112,6 +118,7 @@ func ProcessCurrentBlockRangeCommon(
 
 		chunkLast, err := processBlockRange(ctx, start, end, handler)
 		if err != nil {
+			metrics.Default.RPCResult(listenerConfig.ChainName, err)
 			return fmt.Errorf("failed to process blocks %d-%d: %v", start, end, err)
 		}
 
@@ -151,6 +158,7 @@ func (bl *BaseListener) CatchUpHistoricalBlocks(
 	fmt.Printf("%s Catching up on historical blocks...\n", p)
 
 	currentBlock, err := bl.blockProvider.BlockNumber(ctx)
+	metrics.Default.RPCResult(bl.config.ChainName, err)
 	if err != nil {
 		return fmt.Errorf("%sfailed to get current block number: %v", p, err)
 	}
@@ -164,20 +172,22 @@ func (bl *BaseListener) CatchUpHistoricalBlocks(
 	// Start from the last processed block + 1 (which should be the solver start block)
 	fromBlock := bl.lastProcessedBlock + 1
 	toBlock := safeBlock
-	if fromBlock >= toBlock {
+	if fromBlock > toBlock {
 		fmt.Printf("%s Already up to date, no historical blocks to process\n", p)
 		return nil
 	}
 
 	chunkSize := bl.config.MaxBlockRange
-	for start := fromBlock; start < toBlock; start += chunkSize {
-		end := start + chunkSize
+	handler = handleOnce(bl.config.ChainName, handler)
+	for start := fromBlock; start <= toBlock; start += chunkSize {
+		end := start + chunkSize - 1
 		if end > toBlock {
 			end = toBlock
 		}
 
 		newLast, err := processBlockRange(ctx, start, end, handler)
 		if err != nil {
+			metrics.Default.RPCResult(bl.config.ChainName, err)
 			return fmt.Errorf("%sfailed to process historical blocks %d-%d: %v", p, start, end, err)
 		}
 
@@ -191,6 +201,53 @@ func (bl *BaseListener) CatchUpHistoricalBlocks(
 	return nil
 }
 
+// CatchUpHistoricalBlocksFor runs CatchUpHistoricalBlocks for a listener and moves its lastProcessedBlock, guarded
+// by mu, up to where the backfill got to: polling carries on from there rather than scanning its blocks again
+func (bl *BaseListener) CatchUpHistoricalBlocksFor(
+	ctx context.Context,
+	handler base.EventHandler,
+	processBlockRange func(context.Context, uint64, uint64, base.EventHandler) (uint64, error),
+	mu sync.Locker,
+	lastProcessedBlock *uint64,
+) error {
+	err := bl.CatchUpHistoricalBlocks(ctx, handler, processBlockRange)
+	mu.Lock()
+	defer mu.Unlock()
+	if bl.lastProcessedBlock > *lastProcessedBlock {
+		*lastProcessedBlock = bl.lastProcessedBlock
+	}
+	return err
+}
+
+// handleOnce wraps handler so that orders already handled on chainName are skipped, as when a restart rescans the
+// blocks behind its checkpoint, and records the orders handler accepts. Orders handler holds are recorded as held
+// instead, so a restart hands them over again
+func handleOnce(chainName string, handler base.EventHandler) base.EventHandler {
+	if handler == nil {
+		return nil
+	}
+	return func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+		if handled, err := config.IsOrderHandled(chainName, args.OrderID); err == nil && handled {
+			fmt.Printf("%s Skipping order %s: already handled\n", logutil.Prefix(chainName), args.OrderID)
+			return false, nil
+		}
+		settled, err := handler(args, originChainName, blockNumber)
+		if errors.Is(err, base.ErrOrderHeld) {
+			if err := config.MarkOrderHeld(chainName, args.OrderID, blockNumber); err != nil {
+				fmt.Printf("%s⚠️  Failed to record held order %s: %v\n", logutil.Prefix(chainName), args.OrderID, err)
+			}
+			return false, nil
+		}
+		if err != nil {
+			return settled, err
+		}
+		if err := config.MarkOrderHandled(chainName, args.OrderID, blockNumber); err != nil {
+			fmt.Printf("%s⚠️  Failed to record handled order %s: %v\n", logutil.Prefix(chainName), args.OrderID, err)
+		}
+		return settled, nil
+	}
+}
+
 // CommonListenerConfig holds common configuration for both EVM and Starknet listeners
 type CommonListenerConfig struct {
 	ListenerConfig     *base.ListenerConfig
@@ -240,25 +297,56 @@ func ResolveCommonListenerConfig(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get solver state: %w", err)
 	}
-
-	var lastProcessedBlock uint64
-	if networkState, exists := state.Networks[listenerConfig.ChainName]; exists {
-		deploymentStateBlock := networkState.LastIndexedBlock
-		if deploymentStateBlock > resolvedStartBlock {
-			lastProcessedBlock = deploymentStateBlock
-			fmt.Printf("%s Using deployment state block %d (higher than config start block %d)\n",
-				logutil.Prefix(listenerConfig.ChainName), deploymentStateBlock, resolvedStartBlock)
-		} else {
-			lastProcessedBlock = resolvedStartBlock
-			fmt.Printf("%s Using config start block %d (deployment state block %d is lower)\n",
-				logutil.Prefix(listenerConfig.ChainName), resolvedStartBlock, deploymentStateBlock)
-		}
-	} else {
+	networkState, exists := state.Networks[listenerConfig.ChainName]
+	if !exists {
 		return nil, fmt.Errorf("network %s not found in solver state", listenerConfig.ChainName)
 	}
 
+	lastProcessedBlock := resumeBlock(listenerConfig, configStartBlock, resolvedStartBlock, networkState)
 	return &CommonListenerConfig{
 		ListenerConfig:     listenerConfig,
 		LastProcessedBlock: lastProcessedBlock,
 	}, nil
 }
+
+// resumeBlock picks the block a listener counts as already processed:
+//   - --from-block N: N-1, whatever the checkpoint says
+//   - a checkpoint: the checkpoint less the confirmation window, so blocks that may have been reorganized since
+//     are scanned again (handleOnce skips the orders already handled in them). A relative start block (0 or
+//     negative) never skips ahead of the checkpoint, which would miss the orders opened while the solver was down;
+//     a fixed one still does when it is further ahead. Orders still held for a paused destination pull it back to
+//     the block they were opened in
+//   - otherwise: the configured start block
+func resumeBlock(listenerConfig *base.ListenerConfig, configStartBlock int64, resolvedStartBlock uint64, networkState config.SolverNetworkState) uint64 {
+	p := logutil.Prefix(listenerConfig.ChainName)
+	if listenerConfig.FromBlock != nil {
+		fromBlock := listenerConfig.FromBlock.Uint64()
+		fmt.Printf("%s Starting from block %d (--from-block)\n", p, fromBlock)
+		if fromBlock == 0 {
+			return 0
+		}
+		return fromBlock - 1
+	}
+
+	checkpoint := networkState.LastIndexedBlock
+	recorded := networkState.LastUpdated != ""
+	if !recorded || (configStartBlock > 0 && checkpoint <= resolvedStartBlock) {
+		fmt.Printf("%s Using config start block %d (deployment state block %d is lower)\n", p, resolvedStartBlock, checkpoint)
+		return resolvedStartBlock
+	}
+
+	resume := uint64(0)
+	if checkpoint > listenerConfig.ConfirmationBlocks {
+		resume = checkpoint - listenerConfig.ConfirmationBlocks
+	}
+	if configStartBlock > 0 && resume < resolvedStartBlock {
+		resume = resolvedStartBlock
+	}
+	for _, block := range networkState.HeldOrders {
+		if block <= resume {
+			resume = max(block, 1) - 1
+		}
+	}
+	fmt.Printf("%s Resuming from checkpoint block %d (rescanning from block %d)\n", p, checkpoint, resume+1)
+	return resume
+}
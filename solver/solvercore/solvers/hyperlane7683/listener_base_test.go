@@ -0,0 +1,154 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// fakeBlockProvider returns a fixed block number or error
+type fakeBlockProvider struct {
+	block uint64
+	err   error
+}
+
+func (p *fakeBlockProvider) BlockNumber(context.Context) (uint64, error) {
+	return p.block, p.err
+}
+
+func TestProcessCurrentBlockRangeRecordsRPCHealth(t *testing.T) {
+	// A network name of its own keeps the shared metrics of other tests out of the way
+	const network = "MetricsTestChain"
+	listenerConfig := base.NewListenerConfig("0x0", network, nil, 0, 0, 0)
+	noRange := func(context.Context, uint64, uint64, base.EventHandler) (uint64, error) {
+		t.Fatal("no block range should be processed")
+		return 0, nil
+	}
+	lastProcessed := uint64(100)
+
+	provider := &fakeBlockProvider{err: errors.New("connection refused")}
+	err := ProcessCurrentBlockRangeCommon(context.Background(), nil, provider, listenerConfig, &lastProcessed, "EVM", noRange)
+	require.Error(t, err)
+	health, _ := metrics.Default.Health()
+	assert.Equal(t, metrics.NetworkHealth{LastError: "connection refused"}, health[network])
+
+	// Up to date: the poll succeeds without processing anything
+	provider = &fakeBlockProvider{block: 100}
+	require.NoError(t, ProcessCurrentBlockRangeCommon(context.Background(), nil, provider, listenerConfig, &lastProcessed, "EVM", noRange))
+	health, _ = metrics.Default.Health()
+	assert.True(t, health[network].Connected)
+	assert.Empty(t, health[network].LastError)
+}
+
+func TestRestartMidStreamHandlesEveryOrderOnce(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	const network = "Base"
+
+	// Orders on the chain, by the block they were opened in
+	chain := map[uint64][]string{101: {"0xa"}, 104: {"0xb"}, 105: {"0xc"}, 106: {"0xd"}, 108: {"0xe"}}
+	handled := map[string]int{}
+	handler := func(args types.ParsedArgs, _ string, _ uint64) (bool, error) {
+		handled[args.OrderID]++
+		return true, nil
+	}
+	// crashAfter makes the scan die right after handing over that order, before its block range is checkpointed
+	crashAfter := ""
+	scan := func(_ context.Context, from, to uint64, h base.EventHandler) (uint64, error) {
+		for b := from; b <= to; b++ {
+			for _, id := range chain[b] {
+				if _, err := h(types.ParsedArgs{OrderID: id}, network, b); err != nil {
+					return 0, err
+				}
+				if id == crashAfter {
+					return 0, errors.New("killed")
+				}
+			}
+		}
+		return to, nil
+	}
+
+	listenerConfig := base.NewListenerConfig("0x0", network, big.NewInt(100), 0, 2, 3)
+	start := func(head uint64) uint64 {
+		common, err := ResolveCommonListenerConfig(context.Background(), listenerConfig, &fakeBlockProvider{block: head})
+		require.NoError(t, err)
+		return common.LastProcessedBlock
+	}
+
+	// First run: catches up to 105 (head 107 less 2 confirmations), then dies while handling block 106
+	lastProcessed := start(107)
+	assert.Equal(t, uint64(100), lastProcessed)
+	require.NoError(t, ProcessCurrentBlockRangeCommon(context.Background(), handler, &fakeBlockProvider{block: 107}, listenerConfig, &lastProcessed, "EVM", scan))
+	crashAfter = "0xd"
+	require.Error(t, ProcessCurrentBlockRangeCommon(context.Background(), handler, &fakeBlockProvider{block: 110}, listenerConfig, &lastProcessed, "EVM", scan))
+
+	state, err := config.GetSolverState()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(105), state.Networks[network].LastIndexedBlock)
+
+	// Restart: resumes two blocks behind the checkpoint and skips what the first run already handled
+	crashAfter = ""
+	lastProcessed = start(110)
+	assert.Equal(t, uint64(103), lastProcessed)
+	require.NoError(t, ProcessCurrentBlockRangeCommon(context.Background(), handler, &fakeBlockProvider{block: 110}, listenerConfig, &lastProcessed, "EVM", scan))
+
+	assert.Equal(t, map[string]int{"0xa": 1, "0xb": 1, "0xc": 1, "0xd": 1, "0xe": 1}, handled)
+	assert.Equal(t, uint64(108), lastProcessed)
+}
+
+func TestResumeBlock(t *testing.T) {
+	recorded := func(block uint64) config.SolverNetworkState {
+		return config.SolverNetworkState{LastIndexedBlock: block, LastUpdated: "2026-01-01T00:00:00Z"}
+	}
+	tests := []struct {
+		name        string
+		fromBlock   *big.Int
+		configStart int64
+		resolved    uint64
+		state       config.SolverNetworkState
+		want        uint64
+	}{
+		{"no checkpoint uses the start block", nil, 100, 100, config.SolverNetworkState{LastIndexedBlock: 100}, 100},
+		{"checkpoint less confirmations", nil, 100, 100, recorded(500), 497},
+		{"rewind stops at a fixed start block", nil, 100, 100, recorded(101), 100},
+		{"fixed start block ahead of the checkpoint wins", nil, 900, 900, recorded(500), 900},
+		{"live start never skips the checkpoint", nil, 0, 900, recorded(500), 497},
+		{"relative start never skips the checkpoint", nil, -10, 890, recorded(500), 497},
+		{"from-block overrides the checkpoint", big.NewInt(42), 100, 100, recorded(500), 41},
+		{"from-block zero", big.NewInt(0), 100, 100, recorded(500), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listenerConfig := base.NewListenerConfig("0x0", "Base", big.NewInt(tt.configStart), 0, 3, 0)
+			listenerConfig.FromBlock = tt.fromBlock
+			assert.Equal(t, tt.want, resumeBlock(listenerConfig, tt.configStart, tt.resolved, tt.state))
+		})
+	}
+}
+
+func TestCatchUpHistoricalBlocksForAdvancesPolling(t *testing.T) {
+	t.Setenv("SOLVER_STATE_FILE", filepath.Join(t.TempDir(), "solver-state.json"))
+	listenerConfig := base.NewListenerConfig("0x0", "Base", big.NewInt(100), 0, 0, 5)
+	bl := NewBaseListener(*listenerConfig, &fakeBlockProvider{block: 112}, "EVM")
+	bl.SetLastProcessedBlock(100)
+	scan := func(_ context.Context, _, to uint64, _ base.EventHandler) (uint64, error) { return to, nil }
+
+	var mu sync.Mutex
+	polled := uint64(100)
+	require.NoError(t, bl.CatchUpHistoricalBlocksFor(context.Background(), nil, scan, &mu, &polled))
+	assert.Equal(t, uint64(112), polled, "polling carries on from where the backfill got to")
+
+	polled = 120
+	require.NoError(t, bl.CatchUpHistoricalBlocksFor(context.Background(), nil, scan, &mu, &polled))
+	assert.Equal(t, uint64(120), polled, "polling that got further is not moved back")
+}
@@ -108,7 +108,7 @@ func (l *evmListener) startEventLoop(ctx context.Context, handler base.EventHand
 }
 
 func (l *evmListener) catchUpHistoricalBlocks(ctx context.Context, handler base.EventHandler) error {
-	return l.baseListener.CatchUpHistoricalBlocks(ctx, handler, l.processBlockRange)
+	return l.baseListener.CatchUpHistoricalBlocksFor(ctx, handler, l.processBlockRange, &l.mu, &l.lastProcessedBlock)
 }
 
 func (l *evmListener) startPolling(ctx context.Context, handler base.EventHandler) {
@@ -20,6 +20,7 @@ import (
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
@@ -111,7 +112,7 @@ func (l *starknetListener) startEventLoop(ctx context.Context, handler base.Even
 }
 
 func (l *starknetListener) catchUpHistoricalBlocks(ctx context.Context, handler base.EventHandler) error {
-	return l.baseListener.CatchUpHistoricalBlocks(ctx, handler, l.processBlockRange)
+	return l.baseListener.CatchUpHistoricalBlocksFor(ctx, handler, l.processBlockRange, &l.mu, &l.lastProcessedBlock)
 }
 
 func (l *starknetListener) startPolling(ctx context.Context, handler base.EventHandler) {
@@ -292,9 +293,9 @@ func (d *feltDecoder) readU64() uint64 {
 }
 
 func (d *feltDecoder) readU256() *big.Int {
-	low := utils.FeltToBigInt(d.readFelt())
-	high := utils.FeltToBigInt(d.readFelt())
-	return new(big.Int).Add(low, new(big.Int).Lsh(high, 128))
+	low := d.readFelt()
+	high := d.readFelt()
+	return starknetutil.FromU256(low, high)
 }
 
 func (d *feltDecoder) readAddress() string {
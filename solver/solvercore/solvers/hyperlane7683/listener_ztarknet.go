@@ -104,7 +104,7 @@ func (l *ztarknetListener) startEventLoop(ctx context.Context, handler base.Even
 }
 
 func (l *ztarknetListener) catchUpHistoricalBlocks(ctx context.Context, handler base.EventHandler) error {
-	return l.baseListener.CatchUpHistoricalBlocks(ctx, handler, l.processBlockRange)
+	return l.baseListener.CatchUpHistoricalBlocksFor(ctx, handler, l.processBlockRange, &l.mu, &l.lastProcessedBlock)
 }
 
 func (l *ztarknetListener) startPolling(ctx context.Context, handler base.EventHandler) {
@@ -0,0 +1,100 @@
+package hyperlane7683
+
+// Module: Fill policy rule for the Hyperlane7683 solver
+// - Refuses orders from disabled origins, with denied or unlisted tokens, above a token's size cap, or whose
+//   spread (MinReceived - MaxSpent) is below the configured minimums
+// - evaluateFillPolicy is pure: everything it needs comes from the order and config.FillPolicy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// PolicyRule applies the operator's fill policy; it runs before the rules that need RPC calls
+type PolicyRule struct {
+	Policy config.FillPolicy
+}
+
+func (pr *PolicyRule) Name() string {
+	return "FillPolicy"
+}
+
+func (pr *PolicyRule) Evaluate(_ context.Context, args *types.ParsedArgs) RuleResult {
+	return evaluateFillPolicy(pr.Policy, &args.ResolvedOrder)
+}
+
+// evaluateFillPolicy decides whether policy allows filling order
+func evaluateFillPolicy(policy config.FillPolicy, order *types.ResolvedCrossChainOrder) RuleResult {
+	if order.OriginChainID != nil && order.OriginChainID.IsUint64() {
+		if network, disabled := policy.DisabledOrigins[order.OriginChainID.Uint64()]; disabled {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("orders from %s are disabled", network)}
+		}
+	}
+
+	outputs := append(append([]types.Output{}, order.MaxSpent...), order.MinReceived...)
+	for _, output := range outputs {
+		key := tokenKey(output)
+		if policy.DeniedTokens[key] {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("token %s on chain %d is denied", output.Token, key.ChainID)}
+		}
+		if len(policy.AllowedTokens) > 0 && !policy.AllowedTokens[key] {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("token %s on chain %d is not allowed", output.Token, key.ChainID)}
+		}
+	}
+
+	for _, output := range order.MaxSpent {
+		limit, capped := policy.MaxOrderSize[tokenKey(output)]
+		if capped && output.Amount != nil && output.Amount.Cmp(limit) > 0 {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("order spends %s of token %s, above the %s limit",
+				output.Amount, output.Token, limit)}
+		}
+	}
+
+	if policy.MinSpread == nil && policy.MinSpreadPercent == nil {
+		return RuleResult{Passed: true, Reason: "Fill policy passed"}
+	}
+	spent, received := sumAmounts(order.MaxSpent), sumAmounts(order.MinReceived)
+	spread := new(big.Int).Sub(received, spent)
+	if policy.MinSpread != nil && spread.Cmp(policy.MinSpread) < 0 {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("spread %s is below the minimum %s", spread, policy.MinSpread)}
+	}
+	if policy.MinSpreadPercent != nil && policy.MinSpreadPercent.Sign() > 0 {
+		// spread / spent * 100 >= percent, without dividing; an order that spends nothing only needs a positive spread
+		if spent.Sign() == 0 {
+			if spread.Sign() <= 0 {
+				return RuleResult{Passed: false, Reason: fmt.Sprintf("spread %s is below the minimum %s%%", spread, policy.MinSpreadPercent.FloatString(2))}
+			}
+		} else {
+			actual := new(big.Rat).SetFrac(new(big.Int).Mul(spread, big.NewInt(100)), spent)
+			if actual.Cmp(policy.MinSpreadPercent) < 0 {
+				return RuleResult{Passed: false, Reason: fmt.Sprintf("spread %s%% is below the minimum %s%%",
+					actual.FloatString(2), policy.MinSpreadPercent.FloatString(2))}
+			}
+		}
+	}
+	return RuleResult{Passed: true, Reason: fmt.Sprintf("Fill policy passed: spread %s", spread)}
+}
+
+// tokenKey identifies output's token for the policy's lookups
+func tokenKey(output types.Output) config.TokenKey {
+	key := config.TokenKey{Token: config.NormalizeTokenAddress(output.Token)}
+	if output.ChainID != nil && output.ChainID.IsUint64() {
+		key.ChainID = output.ChainID.Uint64()
+	}
+	return key
+}
+
+// sumAmounts adds up the amounts of outputs, as the profitability check does
+func sumAmounts(outputs []types.Output) *big.Int {
+	total := new(big.Int)
+	for _, output := range outputs {
+		if output.Amount != nil {
+			total.Add(total, output.Amount)
+		}
+	}
+	return total
+}
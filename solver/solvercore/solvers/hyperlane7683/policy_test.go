@@ -0,0 +1,63 @@
+package hyperlane7683
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+func TestEvaluateFillPolicy(t *testing.T) {
+	const (
+		baseChain     = 84532
+		starknetChain = 23448594291968334 // a Hyperlane domain, as Cairo orders carry
+		usdc          = "0x00000000000000000000000036cbd53842c5426634e7929541ec2318f3dcf7e"
+		strk          = "0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+	)
+	// order spends (MaxSpent) on Starknet and receives (MinReceived) on Base
+	order := func(spend, receive int64) *types.ResolvedCrossChainOrder {
+		return &types.ResolvedCrossChainOrder{
+			OriginChainID: big.NewInt(baseChain),
+			MaxSpent:      []types.Output{{Token: strk, Amount: big.NewInt(spend), ChainID: big.NewInt(starknetChain)}},
+			MinReceived:   []types.Output{{Token: usdc, Amount: big.NewInt(receive), ChainID: big.NewInt(baseChain)}},
+		}
+	}
+	usdcKey := config.TokenKey{ChainID: baseChain, Token: config.NormalizeTokenAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")}
+	strkKey := config.TokenKey{ChainID: starknetChain, Token: config.NormalizeTokenAddress(strk)}
+
+	tests := []struct {
+		name       string
+		policy     config.FillPolicy
+		order      *types.ResolvedCrossChainOrder
+		wantPassed bool
+		wantReason string
+	}{
+		{"empty policy allows anything", config.FillPolicy{}, order(100, 90), true, ""},
+		{"disabled origin", config.FillPolicy{DisabledOrigins: map[uint64]string{baseChain: "Base"}}, order(100, 110), false, "orders from Base are disabled"},
+		{"denied token on either side", config.FillPolicy{DeniedTokens: map[config.TokenKey]bool{usdcKey: true}}, order(100, 110), false, "is denied"},
+		{"denied elsewhere only", config.FillPolicy{DeniedTokens: map[config.TokenKey]bool{{ChainID: 1, Token: usdcKey.Token}: true}}, order(100, 110), true, ""},
+		{"allowlist with both tokens", config.FillPolicy{AllowedTokens: map[config.TokenKey]bool{usdcKey: true, strkKey: true}}, order(100, 110), true, ""},
+		{"allowlist missing one token", config.FillPolicy{AllowedTokens: map[config.TokenKey]bool{usdcKey: true}}, order(100, 110), false, "is not allowed"},
+		{"within max order size", config.FillPolicy{MaxOrderSize: map[config.TokenKey]*big.Int{strkKey: big.NewInt(100)}}, order(100, 110), true, ""},
+		{"above max order size", config.FillPolicy{MaxOrderSize: map[config.TokenKey]*big.Int{strkKey: big.NewInt(99)}}, order(100, 110), false, "above the 99 limit"},
+		{"max size only caps what the solver spends", config.FillPolicy{MaxOrderSize: map[config.TokenKey]*big.Int{usdcKey: big.NewInt(1)}}, order(100, 110), true, ""},
+		{"spread meets the minimum", config.FillPolicy{MinSpread: big.NewInt(10)}, order(100, 110), true, ""},
+		{"spread below the minimum", config.FillPolicy{MinSpread: big.NewInt(11)}, order(100, 110), false, "spread 10 is below the minimum 11"},
+		{"negative spread", config.FillPolicy{MinSpread: big.NewInt(0)}, order(100, 90), false, "spread -10"},
+		{"percentage met exactly", config.FillPolicy{MinSpreadPercent: big.NewRat(10, 1)}, order(100, 110), true, ""},
+		{"percentage below", config.FillPolicy{MinSpreadPercent: big.NewRat(21, 2)}, order(100, 110), false, "spread 10.00% is below the minimum 10.50%"},
+		{"percentage of an order spending nothing", config.FillPolicy{MinSpreadPercent: big.NewRat(1, 1)}, order(0, 1), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluateFillPolicy(tt.policy, tt.order)
+			assert.Equal(t, tt.wantPassed, result.Passed, result.Reason)
+			if tt.wantReason != "" {
+				assert.Contains(t, result.Reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/ethereum/go-ethereum/common"
@@ -56,6 +57,13 @@ func NewRulesEngine() *RulesEngine {
 	}
 }
 
+// NewRulesEngineWithPolicy creates a rules engine that applies policy before the default rules
+func NewRulesEngineWithPolicy(policy config.FillPolicy) *RulesEngine {
+	re := NewRulesEngine()
+	re.rules = append([]Rule{&PolicyRule{Policy: policy}}, re.rules...)
+	return re
+}
+
 // AddRule adds a custom rule to the engine
 func (re *RulesEngine) AddRule(rule Rule) {
 	re.rules = append(re.rules, rule)
@@ -71,6 +79,7 @@ func (re *RulesEngine) EvaluateAll(ctx context.Context, args *types.ParsedArgs)
 		result := rule.Evaluate(ctx, args)
 		if !result.Passed {
 			logutil.CrossChainOperation(fmt.Sprintf("Rule '%s' failed: %s", rule.Name(), result.Reason), originChainID, destChainID, args.OrderID)
+			metrics.Default.OrderRejected(rule.Name())
 			return result
 		}
 		logutil.CrossChainOperation(fmt.Sprintf("Rule '%s' passed", rule.Name()), originChainID, destChainID, args.OrderID)
@@ -108,19 +117,20 @@ func (br *BalanceRule) Evaluate(ctx context.Context, args *types.ParsedArgs) Rul
 	}
 }
 
-func (br *BalanceRule) checkStarknetBalance(_ context.Context, args *types.ParsedArgs) RuleResult {
+func (br *BalanceRule) checkStarknetBalance(ctx context.Context, args *types.ParsedArgs) RuleResult {
 	// Get destination chain ID to determine which network we're checking
 	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
 
 	// Determine if this is Ztarknet or Starknet
 	config.InitializeNetworks()
 	var networkName string
+	var networkType config.NetworkType
 	var solverAddrHex string
 	var rpcURL string
 
 	for name, network := range config.Networks {
 		if network.ChainID == destinationChainID {
-			networkName = name
+			networkName, networkType = name, network.Type
 			break
 		}
 	}
@@ -128,15 +138,15 @@ func (br *BalanceRule) checkStarknetBalance(_ context.Context, args *types.Parse
 	// If network not found in config, try to guess based on ID or fallback
 	if networkName == "" {
 		if destinationChainID == config.ZtarknetTestnetChainID {
-			networkName = "Ztarknet"
+			networkName, networkType = "Ztarknet", config.NetworkTypeZtarknet
 		} else if destinationChainID == config.StarknetSepoliaChainID {
-			networkName = "Starknet"
+			networkName, networkType = "Starknet", config.NetworkTypeStarknet
 		} else {
 			return RuleResult{Passed: false, Reason: fmt.Sprintf("Network config not found for chain ID %d", destinationChainID)}
 		}
 	}
 
-	if networkName == "Ztarknet" {
+	if networkType == config.NetworkTypeZtarknet {
 		// Use Ztarknet credentials
 		solverAddrHex = envutil.GetZtarknetSolverAddress()
 		if solverAddrHex == "" {
@@ -172,11 +182,11 @@ func (br *BalanceRule) checkStarknetBalance(_ context.Context, args *types.Parse
 
 		// Use starknetutil for balance check
 		// The token address should already be in the correct format (32-byte felt) from event decoding
-		balance, err := starknetutil.ERC20Balance(provider, maxSpent.Token, solverAddrHex)
+		balance, err := starknetutil.ERC20Balance(ctx, provider, maxSpent.Token, solverAddrHex)
 		if err != nil {
 			// HACK: Skip balance check failure for Ztarknet as requested to avoid blocking orders on RPC issues
 			// Check network name OR if the error message contains "Method not found" which is common with Madara/Ztarknet issues
-			isZtarknetError := networkName == "Ztarknet" || strings.Contains(err.Error(), "Method not found")
+			isZtarknetError := networkType == config.NetworkTypeZtarknet || strings.Contains(err.Error(), "Method not found")
 
 			if isZtarknetError {
 				fmt.Printf("   ⚠️  Warning: Balance check failed for Ztarknet: %v\n", err)
@@ -351,19 +361,8 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 
 // Helper function to determine if a chain ID is Starknet or Ztarknet (Cairo-based chains)
 func isStarknetChain(chainID uint64) bool {
-	config.InitializeNetworks()
-	for name, network := range config.Networks {
-		if network.ChainID == chainID {
-			// Check if network name contains "starknet" (case insensitive) - includes both Starknet and Ztarknet
-			// Check both the map key and the Name field
-			nameLower := strings.ToLower(name)
-			networkNameLower := strings.ToLower(network.Name)
-			if strings.Contains(nameLower, "starknet") || strings.Contains(networkNameLower, "starknet") {
-				return true
-			}
-		}
-	}
-	return false
+	networkType, err := config.GetNetworkTypeByChainID(chainID)
+	return err == nil && networkType.IsCairo()
 }
 
 // Helper function to get chain type (EVM or Starknet)
@@ -9,12 +9,13 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 
 	"github.com/NethermindEth/starknet.go/account"
@@ -39,6 +40,13 @@ type Hyperlane7683Solver struct {
 	// Allow/block lists for controlling which orders to process
 	allowBlockLists types.AllowBlockLists
 
+	// Fill policy applied before the default rules; in dry-run mode orders are evaluated but never filled
+	fillPolicy config.FillPolicy
+	dryRun     bool
+
+	// inventory reserves what each fill spends; nil skips the check
+	inventory *inventory.Inventory
+
 	// Metadata for this solver
 	metadata types.Hyperlane7683Metadata
 }
@@ -76,23 +84,44 @@ func (f *Hyperlane7683Solver) ProcessIntent(ctx context.Context, args *types.Par
 
 	// Check allow/block lists first
 	if !f.isAllowedIntent(args) {
+		metrics.Default.OrderRejected("AllowBlockLists")
 		logutil.LogOperationComplete(args, "Order processing", false)
 		return false, fmt.Errorf("order blocked by allow/block lists")
 	}
 
 	// Run validation rules before processing
-	rulesEngine := NewRulesEngine()
+	rulesEngine := NewRulesEngineWithPolicy(f.fillPolicy)
 	if result := rulesEngine.EvaluateAll(ctx, args); !result.Passed {
 		logutil.LogOperationComplete(args, "Order validation", false)
 		return false, fmt.Errorf("order validation failed: %s", result.Reason)
 	}
 
+	// Set aside what the fill spends, so concurrent fills cannot overdraw the solver's inventory
+	reservation, err := f.inventory.Reserve(args.OrderID, args.ResolvedOrder.MaxSpent)
+	if err != nil {
+		metrics.Default.OrderRejected("Inventory")
+		logutil.LogOperationComplete(args, "Order validation", false)
+		return false, fmt.Errorf("order validation failed: %w", err)
+	}
+
+	if f.dryRun {
+		reservation.Release()
+		logutil.CrossChainOperation("🧪 Dry run: would fill this order (no transaction sent)",
+			args.ResolvedOrder.OriginChainID.Uint64(), args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64(), args.OrderID)
+		return false, nil
+	}
+
 	// Fill method handles its own status checks efficiently (skip if already filled)
+	metrics.Default.FillAttempted()
+	fillStart := time.Now()
 	action, err := f.Fill(ctx, args)
 	if err != nil {
+		reservation.Release()
 		logutil.LogOperationComplete(args, "Fill execution", false)
 		return false, fmt.Errorf("fill execution failed: %w", err)
 	}
+	reservation.Commit(ctx)
+	metrics.Default.FillSucceeded(time.Since(fillStart))
 
 	// Check if order is already complete (filled + settled)
 	if action == OrderActionComplete {
@@ -124,19 +153,8 @@ func (f *Hyperlane7683Solver) ProcessIntent(ctx context.Context, args *types.Par
 			isOriginEVM := f.isEVMChain(originChainID)
 
 			// Check if destination is Ztarknet
-			isDestZtarknet := false
-			if destChainID.Uint64() == config.ZtarknetTestnetChainID {
-				isDestZtarknet = true
-			} else {
-				// Fallback: check config name
-				config.InitializeNetworks()
-				for name, net := range config.Networks {
-					if net.ChainID == destChainID.Uint64() && strings.Contains(strings.ToLower(name), "ztarknet") {
-						isDestZtarknet = true
-						break
-					}
-				}
-			}
+			destType, err := config.GetNetworkTypeByChainID(destChainID.Uint64())
+			isDestZtarknet := destChainID.Uint64() == config.ZtarknetTestnetChainID || (err == nil && destType == config.NetworkTypeZtarknet)
 
 			// Condition: Settle ONLY if (Origin is EVM) AND (Destination is NOT Ztarknet)
 			shouldSettle := isOriginEVM && !isDestZtarknet
@@ -152,10 +170,12 @@ func (f *Hyperlane7683Solver) ProcessIntent(ctx context.Context, args *types.Par
 		time.Sleep(2 * time.Second)
 
 		// Settle the order
+		settleStart := time.Now()
 		if err := f.SettleOrder(ctx, args); err != nil {
 			logutil.LogOperationComplete(args, "Order settlement", false)
 			return false, fmt.Errorf("order settlement failed: %w", err)
 		}
+		metrics.Default.SettleSucceeded(time.Since(settleStart))
 	}
 
 	// Only return true when settle completes successfully
@@ -342,6 +362,21 @@ func (f *Hyperlane7683Solver) getStarknetHandler(chainID *big.Int) (ChainHandler
 	return handler, nil
 }
 
+// SetFillPolicy sets the policy orders must pass before the default rules
+func (f *Hyperlane7683Solver) SetFillPolicy(policy config.FillPolicy) {
+	f.fillPolicy = policy
+}
+
+// SetDryRun makes the solver evaluate orders and log the ones it would fill, without sending transactions
+func (f *Hyperlane7683Solver) SetDryRun(dryRun bool) {
+	f.dryRun = dryRun
+}
+
+// SetInventory makes every fill reserve what it spends from inv first
+func (f *Hyperlane7683Solver) SetInventory(inv *inventory.Inventory) {
+	f.inventory = inv
+}
+
 // AddDefaultRules adds standard validation rules to the solver
 func (f *Hyperlane7683Solver) AddDefaultRules() {
 	// Default rules can be added here if needed in the future
@@ -358,15 +393,8 @@ func (f *Hyperlane7683Solver) isStarknetChain(chainID *big.Int) bool {
 		return true
 	}
 
-	// Find any network with "Starknet" in the name that matches this chain ID
-	for networkName, network := range config.Networks {
-		if network.ChainID == chainID.Uint64() {
-			// Check if network name contains "Starknet" or "Ztarknet" (case insensitive)
-			name := strings.ToLower(networkName)
-			return strings.Contains(name, "starknet") || strings.Contains(name, "ztarknet")
-		}
-	}
-	return false
+	networkType, err := config.GetNetworkTypeByChainID(chainID.Uint64())
+	return err == nil && networkType.IsCairo()
 }
 
 func (f *Hyperlane7683Solver) isEVMChain(chainID *big.Int) bool {
@@ -163,3 +163,41 @@ func FormatTokenAmount(amount *big.Int, decimals int) string {
 
 	return tokenAmount.Text('f', 2) + " tokens"
 }
+
+// maxUint256 is the largest amount a uint256 / u256 order field holds
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ParseTokenAmount parses a decimal token amount such as "1500.5" into base units of a token with the given
+// decimals. The conversion is exact: an amount with more fractional digits than decimals is rejected rather than
+// rounded (trailing zeros do not count), as are signs, exponents and amounts beyond uint256
+func ParseTokenAmount(s string, decimals uint8) (*big.Int, error) {
+	value := strings.TrimSpace(s)
+	whole, fraction, hasPoint := strings.Cut(value, ".")
+	if whole == "" || !isDigits(whole) || (hasPoint && (fraction == "" || !isDigits(fraction))) {
+		return nil, fmt.Errorf("invalid token amount %q: expected a decimal number such as 1500.5", s)
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("token amount %q has more than the token's %d decimals", s, decimals)
+	}
+
+	// Pad the fraction to decimals digits; the digits then read as the amount in base units
+	amount, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", int(decimals)-len(fraction)), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token amount %q", s)
+	}
+	if amount.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("token amount %q does not fit in a uint256 with %d decimals", s, decimals)
+	}
+	return amount, nil
+}
+
+// isDigits reports whether s is made of ASCII digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
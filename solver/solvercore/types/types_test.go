@@ -330,6 +330,58 @@ func TestFormatTokenAmount(t *testing.T) {
 	})
 }
 
+func TestParseTokenAmount(t *testing.T) {
+	parses := []struct {
+		value    string
+		decimals uint8
+		want     string
+	}{
+		{"1500.5", 18, "1500500000000000000000"},
+		{"1499", 18, "1499000000000000000000"},
+		{"0.000000000000000001", 18, "1"},
+		{"1.5", 6, "1500000"},
+		{"1.500000000", 6, "1500000"},
+		{" 42 ", 0, "42"},
+		{"0", 18, "0"},
+		{"007.25", 2, "725"},
+	}
+	for _, tc := range parses {
+		got, err := ParseTokenAmount(tc.value, tc.decimals)
+		require.NoError(t, err, tc.value)
+		assert.Equal(t, tc.want, got.String(), tc.value)
+	}
+
+	// 2^256 - 1 base units is the largest amount
+	maxAmount := "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+	got, err := ParseTokenAmount(maxAmount, 0)
+	require.NoError(t, err)
+	assert.Equal(t, maxAmount, got.String())
+
+	rejects := []struct {
+		value    string
+		decimals uint8
+		err      string
+	}{
+		{"0.0000000000000000001", 18, "more than the token's 18 decimals"},
+		{"1.0000001", 6, "more than the token's 6 decimals"},
+		{"1.5", 0, "more than the token's 0 decimals"},
+		{"", 18, "invalid token amount"},
+		{"-1", 18, "invalid token amount"},
+		{"+1", 18, "invalid token amount"},
+		{"1e18", 18, "invalid token amount"},
+		{".5", 18, "invalid token amount"},
+		{"5.", 18, "invalid token amount"},
+		{"1,000", 18, "invalid token amount"},
+		{"1.2.3", 18, "invalid token amount"},
+		{"0x10", 18, "invalid token amount"},
+		{maxAmount, 1, "does not fit in a uint256"},
+	}
+	for _, tc := range rejects {
+		_, err := ParseTokenAmount(tc.value, tc.decimals)
+		assert.ErrorContains(t, err, tc.err, tc.value)
+	}
+}
+
 func TestGetOrderIDBytes(t *testing.T) {
 	t.Run("Valid order ID", func(t *testing.T) {
 		args := ParsedArgs{